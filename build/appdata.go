@@ -68,6 +68,11 @@ func WalletPassword() string {
 	return os.Getenv(siaWalletPassword)
 }
 
+// TorControlPassword returns the siaTorControlPassword environment variable.
+func TorControlPassword() string {
+	return os.Getenv(siaTorControlPassword)
+}
+
 // ExchangeRate returns the siaExchangeRate environment variable.
 func ExchangeRate() string {
 	return os.Getenv(siaExchangeRate)
@@ -23,4 +23,8 @@ var (
 	// siaExchangeRate is the environment variable that can be set to
 	// show amounts (additionally) in a different currency
 	siaExchangeRate = "SIA_EXCHANGE_RATE"
+
+	// siaTorControlPassword is the environment variable that can be set to
+	// authenticate with the Tor control port using a password
+	siaTorControlPassword = "SIA_TOR_CONTROL_PASSWORD"
 )
@@ -8,6 +8,7 @@ import (
 
 	"gitlab.com/NebulousLabs/errors"
 	"go.sia.tech/siad/node/api"
+	"go.sia.tech/siad/types"
 )
 
 var (
@@ -17,6 +18,34 @@ var (
 		Long:  "Print the current state of consensus such as current block, block height, and target.",
 		Run:   wrap(consensuscmd),
 	}
+
+	consensusReorgCmd = &cobra.Command{
+		Use:   "reorg",
+		Short: "Print the reorg settings and recent reorgs",
+		Long: `Print the reorg settings, the most recent reorg since siad started and the
+reorg which was refused because it is deeper than the maximum reorg depth.`,
+		Run: wrap(consensusreorgcmd),
+	}
+
+	consensusReorgConfirmCmd = &cobra.Command{
+		Use:   "confirm [tip]",
+		Short: "Confirm a refused reorg",
+		Long: `Switch to the fork of a reorg which was refused because it is deeper than the
+maximum reorg depth. The tip must be the tip of the pending reorg as printed by
+'siac consensus reorg'.`,
+		Run: wrap(consensusreorgconfirmcmd),
+	}
+
+	consensusVerifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Check the consensus database for inconsistencies",
+		Long: `Walk the consensus database, re-validate the diffs of the current path against
+the block data and check the consensus state for inconsistencies. With
+--repair, invalid blocks which are not on the current path are removed and a
+stale inconsistency flag is cleared. No blocks are accepted while the database
+is verified, which can take a long time.`,
+		Run: wrap(consensusverifycmd),
+	}
 )
 
 // consensuscmd is the handler for the command `siac consensus`.
@@ -60,3 +89,69 @@ Progress (estimated): %.1f%%
 		fmt.Println("Genesis Timestamp:", time.Unix(int64(cg.GenesisTimestamp), 0))
 	}
 }
+
+// consensusreorgcmd is the handler for the command `siac consensus reorg`.
+// Prints the reorg settings and recent reorgs.
+func consensusreorgcmd() {
+	crg, err := httpClient.ConsensusReorgGet()
+	if err != nil {
+		die("Could not get reorg status:", err)
+	}
+	maxDepth := "disabled"
+	if crg.Settings.MaxDepth > 0 {
+		maxDepth = fmt.Sprint(crg.Settings.MaxDepth)
+	}
+	fmt.Printf(`Alert Depth:     %v
+Max Reorg Depth: %v
+`, crg.Settings.AlertDepth, maxDepth)
+	if crg.LastReorg == nil {
+		fmt.Println("Last Reorg:      none since startup")
+	} else {
+		fmt.Printf("Last Reorg:      %v blocks from height %v at %v\n", crg.LastReorg.Depth, crg.LastReorg.ForkHeight, crg.LastReorg.Time.Format(time.RFC822))
+	}
+	if crg.PendingReorg != nil {
+		fmt.Printf(`
+Pending Reorg:   %v blocks from height %v
+Tip:             %v
+Confirm the reorg with 'siac consensus reorg confirm %v'.
+`, crg.PendingReorg.Depth, crg.PendingReorg.ForkHeight, crg.PendingReorg.NewTip, crg.PendingReorg.NewTip)
+	}
+}
+
+// consensusreorgconfirmcmd is the handler for the command `siac consensus
+// reorg confirm [tip]`. Switches to the fork of a refused reorg.
+func consensusreorgconfirmcmd(tipStr string) {
+	var tip types.BlockID
+	if err := tip.LoadString(tipStr); err != nil {
+		die("Could not parse tip:", err)
+	}
+	if err := httpClient.ConsensusReorgConfirmPost(tip); err != nil {
+		die("Could not confirm reorg:", err)
+	}
+	fmt.Println("Reorg confirmed.")
+}
+
+// consensusverifycmd is the handler for the command `siac consensus verify`.
+// Checks the consensus database for inconsistencies.
+func consensusverifycmd() {
+	fmt.Println("Verifying the consensus database, this can take a while...")
+	cvp, err := httpClient.ConsensusVerifyPost(consensusVerifyRepair)
+	if err != nil {
+		die("Could not verify consensus database:", err)
+	}
+	fmt.Printf("Checked %v blocks up to height %v.\n", cvp.BlocksChecked, cvp.Height)
+	if len(cvp.Inconsistencies) == 0 {
+		fmt.Println("No inconsistencies found.")
+	} else {
+		fmt.Printf("Found %v inconsistencies:\n", len(cvp.Inconsistencies))
+		for _, inconsistency := range cvp.Inconsistencies {
+			fmt.Println("  " + inconsistency)
+		}
+	}
+	for _, repair := range cvp.Repairs {
+		fmt.Println("Repaired:", repair)
+	}
+	if cvp.NeedsResync {
+		fmt.Println("The consensus database can't be repaired in place. Stop siad and delete the consensus database to resync the blockchain.")
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/node/api"
+)
+
+const (
+	// doctorStorageFolderFullRatio is the fraction of a host's total storage
+	// capacity that can be used before doctor warns about running out of
+	// space.
+	doctorStorageFolderFullRatio = 0.95
+)
+
+var (
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose common problems with the Sia daemon",
+		Long: `Run a series of checks against the Sia daemon and print the problems that
+were found, ordered by priority, together with the steps to fix them. Checks
+for modules that aren't loaded are skipped. The command exits with a non-zero
+exit code if a critical problem or error was found.`,
+		Run: wrap(doctorcmd),
+	}
+)
+
+// doctorFinding is a problem found by a check of siac doctor.
+type doctorFinding struct {
+	check       string
+	severity    modules.AlertSeverity
+	problem     string
+	remediation string
+}
+
+// doctorFindings is a list of findings that sorts by priority.
+type doctorFindings []doctorFinding
+
+func (df doctorFindings) Len() int      { return len(df) }
+func (df doctorFindings) Swap(i, j int) { df[i], df[j] = df[j], df[i] }
+func (df doctorFindings) Less(i, j int) bool {
+	return df[i].severity > df[j].severity
+}
+
+// doctorcmd is the handler for the command `siac doctor`. It runs all checks
+// and prints the problems it found.
+func doctorcmd() {
+	dg, err := httpClient.DaemonSettingsGet()
+	if err != nil {
+		die("Could not get daemon settings:", err)
+	}
+
+	var findings doctorFindings
+	if dg.Modules.Consensus {
+		findings = append(findings, doctorCheckConsensus()...)
+	}
+	if dg.Modules.Gateway {
+		findings = append(findings, doctorCheckGateway()...)
+	}
+	if dg.Modules.Wallet {
+		findings = append(findings, doctorCheckWallet()...)
+	}
+	if dg.Modules.Renter {
+		findings = append(findings, doctorCheckRenter()...)
+	}
+	if dg.Modules.Host {
+		findings = append(findings, doctorCheckHost()...)
+	}
+	findings = append(findings, doctorCheckAlerts()...)
+
+	if len(findings) == 0 {
+		fmt.Println("No problems found.")
+		return
+	}
+	sort.Stable(findings)
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Found %v problems:\n", len(findings))
+	fmt.Fprintln(w, "  Priority\tCheck\tProblem\tRemediation")
+	for _, f := range findings {
+		fmt.Fprintf(w, "  %v\t%v\t%v\t%v\n", f.severity, f.check, f.problem, f.remediation)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+
+	if findings[0].severity >= modules.SeverityError {
+		os.Exit(exitCodeGeneral)
+	}
+}
+
+// doctorCheckConsensus checks that the consensus set is synced.
+func doctorCheckConsensus() (findings []doctorFinding) {
+	cg, err := httpClient.ConsensusGet()
+	if err != nil {
+		return []doctorFinding{doctorRequestFailed("consensus", err)}
+	}
+	if !cg.Synced {
+		findings = append(findings, doctorFinding{
+			check:       "consensus",
+			severity:    modules.SeverityCritical,
+			problem:     fmt.Sprintf("Consensus is not synced (height %v)", cg.Height),
+			remediation: "Wait for the daemon to finish syncing, 'siac consensus' shows the progress",
+		})
+	}
+	return findings
+}
+
+// doctorCheckGateway checks that the gateway is connected to peers and that
+// other peers can connect to it.
+func doctorCheckGateway() (findings []doctorFinding) {
+	gg, err := httpClient.GatewayGet()
+	if err != nil {
+		return []doctorFinding{doctorRequestFailed("gateway", err)}
+	}
+	if len(gg.Peers) == 0 {
+		return []doctorFinding{{
+			check:       "gateway",
+			severity:    modules.SeverityCritical,
+			problem:     "The gateway has no peers",
+			remediation: "Check your internet connection or add a peer with 'siac gateway connect'",
+		}}
+	}
+	var inbound int
+	for _, peer := range gg.Peers {
+		if peer.Inbound {
+			inbound++
+		}
+	}
+	if inbound == 0 {
+		findings = append(findings, doctorFinding{
+			check:       "gateway",
+			severity:    modules.SeverityWarning,
+			problem:     fmt.Sprintf("No inbound peers, %v might not be reachable", gg.NetAddress),
+			remediation: "Forward the gateway port in your router and firewall",
+		})
+	}
+	return findings
+}
+
+// doctorCheckWallet checks that the wallet is unlocked and funded.
+func doctorCheckWallet() (findings []doctorFinding) {
+	wg, err := httpClient.WalletGet()
+	if err != nil {
+		return []doctorFinding{doctorRequestFailed("wallet", err)}
+	}
+	if !wg.Encrypted {
+		return []doctorFinding{{
+			check:       "wallet",
+			severity:    modules.SeverityError,
+			problem:     "The wallet has not been created",
+			remediation: "Create a wallet with 'siac wallet init'",
+		}}
+	}
+	if !wg.Unlocked {
+		return []doctorFinding{{
+			check:       "wallet",
+			severity:    modules.SeverityError,
+			problem:     "The wallet is locked",
+			remediation: "Unlock the wallet with 'siac wallet unlock'",
+		}}
+	}
+	if wg.ConfirmedSiacoinBalance.IsZero() {
+		findings = append(findings, doctorFinding{
+			check:       "wallet",
+			severity:    modules.SeverityWarning,
+			problem:     "The wallet has no confirmed siacoins",
+			remediation: "Send siacoins to an address from 'siac wallet address'",
+		})
+	}
+	return findings
+}
+
+// doctorCheckRenter checks that the renter has an allowance and enough
+// contracts to upload.
+func doctorCheckRenter() (findings []doctorFinding) {
+	rg, err := httpClient.RenterGet()
+	if err != nil {
+		return []doctorFinding{doctorRequestFailed("renter", err)}
+	}
+	allowance := rg.Settings.Allowance
+	if allowance.Funds.IsZero() {
+		// Without an allowance there are no contracts to check.
+		return []doctorFinding{{
+			check:       "renter",
+			severity:    modules.SeverityWarning,
+			problem:     "No allowance is set",
+			remediation: "Set an allowance with 'siac renter setallowance' to form contracts",
+		}}
+	}
+
+	rc, err := httpClient.RenterContractsGet()
+	if err != nil {
+		return []doctorFinding{doctorRequestFailed("renter", err)}
+	}
+	var goodForUpload uint64
+	for _, c := range rc.ActiveContracts {
+		if c.GoodForUpload {
+			goodForUpload++
+		}
+	}
+	switch {
+	case goodForUpload == 0:
+		findings = append(findings, doctorFinding{
+			check:       "renter",
+			severity:    modules.SeverityError,
+			problem:     "There are no contracts that can be used for uploads",
+			remediation: "Make sure consensus is synced and the wallet is unlocked and funded, then wait for contracts to form",
+		})
+	case goodForUpload < allowance.Hosts:
+		findings = append(findings, doctorFinding{
+			check:       "renter",
+			severity:    modules.SeverityWarning,
+			problem:     fmt.Sprintf("Only %v of %v contracts can be used for uploads", goodForUpload, allowance.Hosts),
+			remediation: "Check 'siac renter contracts' and consider raising the allowance's max prices",
+		})
+	}
+	return findings
+}
+
+// doctorCheckHost checks that the host is reachable and has storage space
+// left.
+func doctorCheckHost() (findings []doctorFinding) {
+	hg, err := httpClient.HostGet()
+	if err != nil {
+		return []doctorFinding{doctorRequestFailed("host", err)}
+	}
+	if !hg.InternalSettings.AcceptingContracts {
+		// A host that doesn't accept contracts doesn't need to be reachable.
+		return nil
+	}
+	if hg.ConnectabilityStatus == modules.HostConnectabilityStatusNotConnectable {
+		findings = append(findings, doctorFinding{
+			check:       "host",
+			severity:    modules.SeverityError,
+			problem:     fmt.Sprintf("The host is not connectable at %v", hg.ExternalSettings.NetAddress),
+			remediation: "Forward the host ports in your router and firewall and check 'siac host -v'",
+		})
+	}
+
+	sg, err := httpClient.HostStorageGet()
+	if err != nil {
+		return append(findings, doctorRequestFailed("host", err))
+	}
+	if len(sg.Folders) == 0 {
+		return append(findings, doctorFinding{
+			check:       "host",
+			severity:    modules.SeverityError,
+			problem:     "The host has no storage folders",
+			remediation: "Add a storage folder with 'siac host folder add'",
+		})
+	}
+	var capacity, remaining uint64
+	for _, sf := range sg.Folders {
+		capacity += sf.Capacity
+		remaining += sf.CapacityRemaining
+		if sf.FailedReads+sf.FailedWrites > 0 {
+			findings = append(findings, doctorFinding{
+				check:       "host",
+				severity:    modules.SeverityError,
+				problem:     fmt.Sprintf("Storage folder %v has %v failed reads and %v failed writes", sf.Path, sf.FailedReads, sf.FailedWrites),
+				remediation: "Check the disk's health and free space",
+			})
+		}
+	}
+	if capacity > 0 && float64(capacity-remaining) >= doctorStorageFolderFullRatio*float64(capacity) {
+		findings = append(findings, doctorFinding{
+			check:       "host",
+			severity:    modules.SeverityWarning,
+			problem:     fmt.Sprintf("The host's storage is almost full (%v remaining)", modules.FilesizeUnits(remaining)),
+			remediation: "Add or resize storage folders with 'siac host folder'",
+		})
+	}
+	return findings
+}
+
+// doctorCheckAlerts turns the daemon's registered alerts into findings.
+func doctorCheckAlerts() (findings []doctorFinding) {
+	dag, err := httpClient.DaemonAlertsGet()
+	if err != nil {
+		return []doctorFinding{doctorRequestFailed("alerts", err)}
+	}
+	for _, a := range dag.Alerts {
+		if a.Severity < modules.SeverityWarning {
+			continue
+		}
+		findings = append(findings, doctorFinding{
+			check:       a.Module,
+			severity:    a.Severity,
+			problem:     a.Msg,
+			remediation: a.Cause,
+		})
+	}
+	return findings
+}
+
+// doctorRequestFailed returns the finding for a check that couldn't query the
+// daemon.
+func doctorRequestFailed(check string, err error) doctorFinding {
+	severity := modules.AlertSeverity(modules.SeverityError)
+	remediation := "Check the daemon's logs"
+	if errors.Contains(err, api.ErrAPICallNotRecognized) {
+		severity = modules.SeverityWarning
+		remediation = "Make sure siac and siad are the same version"
+	}
+	return doctorFinding{
+		check:       check,
+		severity:    severity,
+		problem:     fmt.Sprintf("Could not query the %v: %v", check, err),
+		remediation: remediation,
+	}
+}
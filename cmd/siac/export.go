@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/types"
 )
 
@@ -25,6 +26,26 @@ var (
 			"file. Intended for upload to `https://rankings.sia.tech/`.",
 		Run: wrap(renterexportcontracttxnscmd),
 	}
+
+	renterExportFileKeysCmd = &cobra.Command{
+		Use:   "file-keys [path] [destination]",
+		Short: "export the encryption keys and erasure coding layout of a file",
+		Long: "Export the encryption keys and the erasure coding layout of a file in JSON " +
+			"format to the specified destination. The export allows independent recovery " +
+			"tools to reconstruct the file from the raw sectors stored on its hosts. " +
+			"Anyone with access to the export and the sectors can decrypt the file.",
+		Run: wrap(renterexportfilekeyscmd),
+	}
+
+	renterExportSharedFileCmd = &cobra.Command{
+		Use:   "shared-file [path] [destination]",
+		Short: "export a file and its keys into an encrypted shared file",
+		Long: "Export a file and its keys into a shared file at the specified destination, " +
+			"encrypted with a password. Anyone with the shared file and the password can " +
+			"load it with 'siac renter loadsharedfile' and download the file without " +
+			"access to this renter's seed.",
+		Run: wrap(renterexportsharedfilecmd),
+	}
 )
 
 // renterexportcontracttxnscmd is the handler for the command `siac renter export contract-txns`.
@@ -49,3 +70,51 @@ func renterexportcontracttxnscmd(destination string) {
 	}
 	fmt.Println("Exported contract data to", destination)
 }
+
+// renterexportfilekeyscmd is the handler for the command `siac renter export
+// file-keys`. Exports the keys and layout of a file to JSON.
+func renterexportfilekeyscmd(path, destination string) {
+	siaPath, err := modules.NewSiaPath(path)
+	if err != nil {
+		die("Couldn't parse SiaPath:", err)
+	}
+	fk, err := httpClient.RenterFileKeysGet(siaPath, false)
+	if err != nil {
+		die("Could not export file keys:", err)
+	}
+	destination = abs(destination)
+	file, err := os.OpenFile(destination, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		die("Could not export to file:", err)
+	}
+	err = json.NewEncoder(file).Encode(fk)
+	if err != nil {
+		die("Could not export to file:", err)
+	}
+	if err := file.Close(); err != nil {
+		die("Could not export to file:", err)
+	}
+	fmt.Println("Exported file keys to", destination)
+}
+
+// renterexportsharedfilecmd is the handler for the command `siac renter export
+// shared-file`. Exports a file and its keys into an encrypted shared file.
+func renterexportsharedfilecmd(path, destination string) {
+	siaPath, err := modules.NewSiaPath(path)
+	if err != nil {
+		die("Couldn't parse SiaPath:", err)
+	}
+	password, err := passwordPrompt("Shared file password: ")
+	if err != nil {
+		die("Reading password failed:", err)
+	}
+	if err := confirmPassword(password); err != nil {
+		die(err)
+	}
+	destination = abs(destination)
+	err = httpClient.RenterShareFilePost(siaPath, destination, password, false)
+	if err != nil {
+		die("Could not export shared file:", err)
+	}
+	fmt.Println("Exported shared file to", destination)
+}
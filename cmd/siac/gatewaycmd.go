@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"text/tabwriter"
 	"time"
 
@@ -24,7 +25,8 @@ var (
 		Use:   "bandwidth",
 		Short: "returns the total upload and download bandwidth usage for the gateway",
 		Long: `returns the total upload and download bandwidth usage for the gateway
-and the duration of the bandwidth tracking.`,
+and the duration of the bandwidth tracking. The usage is also split by protocol
+for the last hour, the last day and since startup.`,
 		Run: wrap(gatewaybandwidthcmd),
 	}
 
@@ -35,6 +37,24 @@ and the duration of the bandwidth tracking.`,
 		Run:   wrap(gatewaycmd),
 	}
 
+	gatewayBanCmd = &cobra.Command{
+		Use:   "ban [addresses]",
+		Short: "Ban peers from the gateway",
+		Long: `Ban one or more peers from the gateway and disconnect them. Without a
+duration the peers are added to the persistent blocklist, otherwise they are
+banned temporarily.
+
+For example: siac gateway ban --duration 24h --reason spam 123.123.123.123`,
+		Run: gatewaybancmd,
+	}
+
+	gatewayBansCmd = &cobra.Command{
+		Use:   "bans",
+		Short: "View the banned peers and the peer scores",
+		Long:  "Display the banned peers and the scores of the peers the gateway connected to.",
+		Run:   wrap(gatewaybanscmd),
+	}
+
 	gatewayBlocklistCmd = &cobra.Command{
 		Use:   "blocklist",
 		Short: "View and manage the gateway's blocklisted peers",
@@ -81,6 +101,25 @@ For example: siac gateway blocklist set 123.123.123.123 111.222.111.222 mysiahos
 		Run: gatewayblocklistsetcmd,
 	}
 
+	gatewayConnectivityCmd = &cobra.Command{
+		Use:   "connectivity",
+		Short: "Check whether the node is reachable",
+		Long: `Probe whether the ports forwarded by the node are reachable from the
+outside by asking peers to connect to them, and display how the ports were
+mapped on the router.`,
+		Run: wrap(gatewayconnectivitycmd),
+	}
+
+	gatewayConnLimitsCmd = &cobra.Command{
+		Use:   "connlimits [per /16] [per /24] [per ASN]",
+		Short: "set the limits of inbound peers per subnet and ASN",
+		Long: `Set the maximum number of inbound peers from the same /16 subnet, the
+same /24 subnet and the same autonomous system. Set a limit to 0 to disable it.
+The limit per autonomous system looks up the ASN of peers with a public DNS
+service.`,
+		Run: wrap(gatewayconnlimitscmd),
+	}
+
 	gatewayConnectCmd = &cobra.Command{
 		Use:   "connect [address]",
 		Short: "Connect to a peer",
@@ -102,6 +141,15 @@ For example: siac gateway blocklist set 123.123.123.123 111.222.111.222 mysiahos
 		Run:   wrap(gatewaylistcmd),
 	}
 
+	gatewayUnbanCmd = &cobra.Command{
+		Use:   "unban [addresses]",
+		Short: "Unban peers",
+		Long: `Remove the bans of one or more peers and reset their scores.
+
+For example: siac gateway unban 123.123.123.123`,
+		Run: gatewayunbancmd,
+	}
+
 	gatewayRatelimitCmd = &cobra.Command{
 		Use:   "ratelimit [maxdownloadspeed] [maxuploadspeed]",
 		Short: "set maxdownloadspeed and maxuploadspeed",
@@ -124,6 +172,53 @@ func gatewayconnectcmd(addr string) {
 	fmt.Println("Added", addr, "to peer list.")
 }
 
+// gatewayconnlimitscmd is the handler for the command `siac gateway
+// connlimits`. Sets the limits of inbound peers per subnet and autonomous
+// system.
+func gatewayconnlimitscmd(per16Str, per24Str, perASNStr string) {
+	var limits modules.GatewayConnectionLimits
+	var err error
+	if limits.MaxInboundPerSubnet16, err = strconv.ParseUint(per16Str, 10, 64); err != nil {
+		die("Could not parse limit per /16 subnet:", err)
+	}
+	if limits.MaxInboundPerSubnet24, err = strconv.ParseUint(per24Str, 10, 64); err != nil {
+		die("Could not parse limit per /24 subnet:", err)
+	}
+	if limits.MaxInboundPerASN, err = strconv.ParseUint(perASNStr, 10, 64); err != nil {
+		die("Could not parse limit per ASN:", err)
+	}
+	if err := httpClient.GatewayConnectionLimitsPost(limits); err != nil {
+		die("Could not set connection limits:", err)
+	}
+	fmt.Println("Connection limits updated.")
+}
+
+// gatewayconnectivitycmd is the handler for the command `siac gateway
+// connectivity`. Probes and prints whether the node is reachable.
+func gatewayconnectivitycmd() {
+	gcg, err := httpClient.GatewayConnectivityGet(true)
+	if err != nil {
+		die("Could not probe connectivity:", err)
+	}
+	fmt.Println("Address:  ", gcg.NetAddress)
+	fmt.Println("Reachable:", yesNo(gcg.Reachable))
+	if gcg.ProbeError != "" {
+		fmt.Println("Probe failed:", gcg.ProbeError)
+	}
+	if len(gcg.PortMappings) == 0 {
+		return
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Port\tExternal Port\tMethod\tReachable\tError")
+	for _, pm := range gcg.PortMappings {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", pm.Port, pm.ExternalPort, pm.Method, yesNo(pm.Reachable), pm.Error)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+}
+
 // gatewaydisconnectcmd is the handler for the command `siac gateway remove [address]`.
 // Removes a peer from the peer list.
 func gatewaydisconnectcmd(addr string) {
@@ -156,6 +251,22 @@ func gatewaybandwidthcmd() {
 Upload:   %v 
 Duration: %v 
 `, modules.FilesizeUnits(bandwidth.Download), modules.FilesizeUnits(bandwidth.Upload), fmtDuration(time.Since(bandwidth.StartTime)))
+	if len(bandwidth.Protocols) == 0 {
+		return
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Protocol\tDownload\tUpload\tDownload (1h)\tUpload (1h)\tDownload (24h)\tUpload (24h)")
+	for _, p := range bandwidth.Protocols {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", p.Protocol,
+			modules.FilesizeUnits(p.Total.Download), modules.FilesizeUnits(p.Total.Upload),
+			modules.FilesizeUnits(p.LastHour.Download), modules.FilesizeUnits(p.LastHour.Upload),
+			modules.FilesizeUnits(p.LastDay.Download), modules.FilesizeUnits(p.LastDay.Upload))
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
 }
 
 // gatewaycmd is the handler for the command `siac gateway`.
@@ -169,6 +280,96 @@ func gatewaycmd() {
 	fmt.Println("Active peers:", len(info.Peers))
 	fmt.Println("Max download speed:", info.MaxDownloadSpeed)
 	fmt.Println("Max upload speed:", info.MaxUploadSpeed)
+	fmt.Println("Max inbound peers per /16 subnet:", connLimitString(info.MaxInboundPerSubnet16))
+	fmt.Println("Max inbound peers per /24 subnet:", connLimitString(info.MaxInboundPerSubnet24))
+	fmt.Println("Max inbound peers per ASN:", connLimitString(info.MaxInboundPerASN))
+}
+
+// connLimitString returns the string representation of a connection limit.
+func connLimitString(limit uint64) string {
+	if limit == 0 {
+		return "none"
+	}
+	return strconv.FormatUint(limit, 10)
+}
+
+// gatewaybancmd is the handler for the command `siac gateway ban`.
+// Bans one or more peers, either permanently or temporarily.
+func gatewaybancmd(cmd *cobra.Command, addresses []string) {
+	if len(addresses) == 0 {
+		fmt.Println("No addresses submitted to ban")
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	var duration time.Duration
+	if gatewayBanDuration != "" {
+		var err error
+		duration, err = time.ParseDuration(gatewayBanDuration)
+		if err != nil || duration < time.Second {
+			die("Could not parse duration, it must be at least one second:", gatewayBanDuration)
+		}
+	}
+	err := httpClient.GatewayBanPost(addresses, duration, gatewayBanReason)
+	if err != nil {
+		die("Could not ban the peers:", err)
+	}
+	if duration == 0 {
+		fmt.Println(addresses, "successfully banned permanently")
+		return
+	}
+	fmt.Println(addresses, "successfully banned for", duration)
+}
+
+// gatewaybanscmd is the handler for the command `siac gateway bans`.
+// Prints the banned peers and the peer scores.
+func gatewaybanscmd() {
+	gbg, err := httpClient.GatewayBansGet()
+	if err != nil {
+		die("Could not get gateway bans:", err)
+	}
+	fmt.Println(len(gbg.Bans), "banned peers")
+	if len(gbg.Bans) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Address\tExpires\tReason")
+		for _, ban := range gbg.Bans {
+			expires := "never"
+			if !ban.Permanent {
+				expires = ban.Expiry.Format(time.RFC822)
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\n", ban.Address, expires, ban.Reason)
+		}
+		if err := w.Flush(); err != nil {
+			die("failed to flush writer:", err)
+		}
+	}
+	if len(gbg.Scores) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("Peer scores:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Address\tScore\tInvalid Blocks\tStalls\tLatency")
+	for _, score := range gbg.Scores {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", score.Address, score.Score, score.InvalidBlocks, score.Stalls, score.Latency)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+}
+
+// gatewayunbancmd is the handler for the command `siac gateway unban`.
+// Removes the bans of one or more peers.
+func gatewayunbancmd(cmd *cobra.Command, addresses []string) {
+	if len(addresses) == 0 {
+		fmt.Println("No addresses submitted to unban")
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	err := httpClient.GatewayUnbanPost(addresses)
+	if err != nil {
+		die("Could not unban the peers:", err)
+	}
+	fmt.Println(addresses, "successfully unbanned")
 }
 
 // gatewayblocklistcmd is the handler for the command `siac gateway blocklist`
@@ -261,7 +462,7 @@ func gatewaylistcmd() {
 		fmt.Fprintf(w, "%v\t%v\t%v\n", peer.Version, yesNo(!peer.Inbound), peer.NetAddress)
 	}
 	if err := w.Flush(); err != nil {
-		die("failed to flush writer")
+		die("failed to flush writer:", err)
 	}
 }
 
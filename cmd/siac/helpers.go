@@ -156,3 +156,17 @@ func sanitizeTime(t time.Time, cond bool) string {
 	}
 	return fmt.Sprintf("%v", t.Format(time.RFC3339))
 }
+
+// sanitizeJobTimes is a small helper function that sanitizes the output for
+// the given recent job times in ms. If there are no job times, it will print
+// "-".
+func sanitizeJobTimes(jobTimes []uint64) string {
+	if len(jobTimes) == 0 {
+		return "-"
+	}
+	strs := make([]string, 0, len(jobTimes))
+	for _, jt := range jobTimes {
+		strs = append(strs, fmt.Sprint(jt))
+	}
+	return strings.Join(strs, ",")
+}
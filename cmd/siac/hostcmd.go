@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -67,8 +68,39 @@ Available settings:
      maxephemeralaccountbalance: currency
      maxephemeralaccountrisk:    currency
 	 
-     registrysize:       filesize
-     customregistrypath: string
+     registrysize:          filesize
+     customregistrypath:    string
+     maxregistryupdaterate: updates per second (0 for no limit)
+
+     accesslogging:   boolean
+     maintenancemode: boolean
+     diskhealthsmart: boolean (requires smartctl)
+
+     minrenterfunds:        currency
+     mincompletedcontracts: int
+     acceptancewindows:     comma separated HH:MM-HH:MM windows (UTC)
+
+     maxrenterbandwidth: bandwidth per renter, e.g. 10MB/s (0 for no limit)
+     maxrenterrpcrate:   RPCs per second per renter (0 for no limit)
+     maxrentersessions:  open sessions per renter (0 for no limit)
+
+     maxtrafficbandwidth:  bandwidth of all RPC traffic, e.g. 100MB/s (0 disables prioritization)
+     trafficpriorityburst: filesize
+
+     bandwidthperiod:         seconds (0 for the default period)
+     maxperiodupload:         filesize sent per period (0 for no cap)
+     maxperioddownload:       filesize received per period (0 for no cap)
+     maxrenterperiodupload:   filesize sent to a renter per period (0 for no cap)
+     maxrenterperioddownload: filesize received from a renter per period (0 for no cap)
+
+     dynamicpricing:                   boolean
+     dynamicminstorageprice:           currency / TB / Month
+     dynamicmaxstorageprice:           currency / TB / Month (0 disables adjustments)
+     dynamicmindownloadbandwidthprice: currency / TB
+     dynamicmaxdownloadbandwidthprice: currency / TB (0 disables adjustments)
+     dynamicminuploadbandwidthprice:   currency / TB
+     dynamicmaxuploadbandwidthprice:   currency / TB (0 disables adjustments)
+     dynamicreferencebandwidth:        bandwidth at the max prices, e.g. 100MB/s (0 uses maxtrafficbandwidth)
 
 Currency units can be specified, e.g. 10SC; run 'siac help wallet' for details.
 
@@ -76,7 +108,7 @@ Durations (maxduration and windowsize) must be specified in either blocks (b),
 hours (h), days (d), or weeks (w). A block is approximately 10 minutes, so one
 hour is six blocks, a day is 144 blocks, and a week is 1008 blocks.
 
-Timeouts (ephemeralaccountexpiry) must be specified in either seconds (s),
+Timeouts (ephemeralaccountexpiry and bandwidthperiod) must be specified in either seconds (s),
 hours (h), days (d), or weeks (w). One hour is 3600 seconds, a day is 86400
 seconds, and a week is 604800 seconds.
 
@@ -84,6 +116,11 @@ For a description of each parameter, see doc/API.md.
 
 To configure the host to accept new contracts, set acceptingcontracts to true:
 	siac host config acceptingcontracts true
+
+To drain the host before hardware maintenance, enable maintenancemode. The host
+stops forming and renewing contracts and accepting new data, but keeps serving
+downloads and submitting storage proofs:
+	siac host config maintenancemode true
 `,
 		Run: wrap(hostconfigcmd),
 	}
@@ -94,8 +131,9 @@ To configure the host to accept new contracts, set acceptingcontracts to true:
 		Long: `Show host contracts sorted by expiration height.
 
 Available output types:
-     value:  show financial information
-     status: show status information
+     value:   show financial information
+     status:  show status information
+     renters: show the contracts, stored data and bandwidth of each renter
 `,
 		Run: wrap(hostcontractcmd),
 	}
@@ -122,6 +160,16 @@ data; it will instead be distributed across the remaining storage folders unless
 		Run: wrap(hostfolderremovecmd),
 	}
 
+	hostFolderDrainCmd = &cobra.Command{
+		Use:   "drain [path]",
+		Short: "Drain a storage folder in the background",
+		Long: `Mark a storage folder for removal. Unlike 'remove', the command returns
+immediately. The folder's data is migrated to the remaining storage folders in
+the background and the folder is removed once it is empty. Use the --stop flag
+to stop draining a folder that hasn't been removed yet.`,
+		Run: wrap(hostfolderdraincmd),
+	}
+
 	hostFolderResizeCmd = &cobra.Command{
 		Use:   "resize [path] [size]",
 		Short: "Resize a storage folder",
@@ -131,6 +179,46 @@ other storage folders.`,
 		Run: wrap(hostfolderresizecmd),
 	}
 
+	hostRegistryCmd = &cobra.Command{
+		Use:   "registry",
+		Short: "Show the host's registry usage",
+		Long: `Show how many entries are stored in the host's registry and how many
+registry lookups and updates the host served. The size of the registry and its
+update rate are configured with the registrysize and maxregistryupdaterate
+settings.`,
+		Run: wrap(hostregistrycmd),
+	}
+
+	hostRevenueCmd = &cobra.Command{
+		Use:   "revenue",
+		Short: "Show the host's revenue per month",
+		Long: `Show the revenue, transaction fees, lost revenue and burned collateral of the
+host's resolved contracts per calendar month. Use --csv contracts or --csv
+periods to export the revenue per contract or per month as CSV for accounting
+purposes. CSV amounts are in hastings.`,
+		Run: wrap(hostrevenuecmd),
+	}
+
+	hostTrafficCmd = &cobra.Command{
+		Use:   "traffic",
+		Short: "Show the host's traffic prioritization",
+		Long: `Show how much traffic the host scheduled as latency-critical priority traffic
+and as bulk traffic, and how long each class had to wait for bandwidth.
+Prioritization is configured with the maxtrafficbandwidth and
+trafficpriorityburst settings.`,
+		Run: wrap(hosttrafficcmd),
+	}
+
+	hostScrubCmd = &cobra.Command{
+		Use:   "scrub",
+		Short: "Show the progress of the integrity scrubber",
+		Long: `Show the progress of the host's integrity scrubber, which periodically
+re-reads all sectors and quarantines sectors whose data is corrupt. Contracts
+covering quarantined sectors are listed by their proof window, since the host
+will fail to submit storage proofs for them and lose its collateral.`,
+		Run: wrap(hostscrubcmd),
+	}
+
 	hostSectorCmd = &cobra.Command{
 		Use:   "sector",
 		Short: "Add or delete a sector (add not supported)",
@@ -235,8 +323,40 @@ Host Internal Settings:
 	maxephemeralaccountbalance: %v
 	maxephemeralaccountrisk:    %v
 
-	registrysize:       %v
-	customregistrypath: %v
+	registrysize:          %v
+	customregistrypath:    %v
+	maxregistryupdaterate: %v
+
+	accesslogging:   %v
+	maintenancemode: %v
+	diskhealthsmart: %v
+
+	minrenterfunds:        %v
+	mincompletedcontracts: %v
+	acceptancewindows:     %v
+
+	maxrenterbandwidth:   %v
+	maxrenterrpcrate:     %v
+	maxrentersessions:    %v
+	renterlimitoverrides: %v
+
+	maxtrafficbandwidth:  %v
+	trafficpriorityburst: %v
+
+	bandwidthperiod:         %v
+	maxperiodupload:         %v
+	maxperioddownload:       %v
+	maxrenterperiodupload:   %v
+	maxrenterperioddownload: %v
+
+	dynamicpricing:                   %v
+	dynamicminstorageprice:           %v / TB / Month
+	dynamicmaxstorageprice:           %v / TB / Month
+	dynamicmindownloadbandwidthprice: %v / TB
+	dynamicmaxdownloadbandwidthprice: %v / TB
+	dynamicminuploadbandwidthprice:   %v / TB
+	dynamicmaxuploadbandwidthprice:   %v / TB
+	dynamicreferencebandwidth:        %v
 
 Host Financials:
 	Contract Count:               %v
@@ -291,6 +411,38 @@ RPC Stats:
 			currencyUnits(is.MaxEphemeralAccountRisk),
 			modules.FilesizeUnits(is.RegistrySize),
 			is.CustomRegistryPath,
+			is.MaxRegistryUpdateRate,
+
+			yesNo(is.AccessLogging),
+			yesNo(is.MaintenanceMode),
+			yesNo(is.DiskHealthSMART),
+
+			currencyUnits(is.ContractPolicy.MinRenterFunds),
+			is.ContractPolicy.MinCompletedContracts,
+			acceptanceWindowsString(is.ContractPolicy.AcceptanceWindows),
+
+			ratelimitUnits(is.RenterLimits.Default.MaxBandwidth),
+			is.RenterLimits.Default.MaxRPCRate,
+			is.RenterLimits.Default.MaxSessions,
+			len(is.RenterLimits.Overrides),
+
+			ratelimitUnits(is.Traffic.MaxBandwidth),
+			modules.FilesizeUnits(uint64(is.Traffic.PriorityBurst)),
+
+			is.BandwidthCaps.PeriodLength(),
+			modules.FilesizeUnits(is.BandwidthCaps.MaxUpload),
+			modules.FilesizeUnits(is.BandwidthCaps.MaxDownload),
+			modules.FilesizeUnits(is.RenterLimits.Default.MaxPeriodUpload),
+			modules.FilesizeUnits(is.RenterLimits.Default.MaxPeriodDownload),
+
+			yesNo(is.DynamicPricing.Enabled),
+			currencyUnits(is.DynamicPricing.MinStoragePrice.Mul(modules.BlockBytesPerMonthTerabyte)),
+			currencyUnits(is.DynamicPricing.MaxStoragePrice.Mul(modules.BlockBytesPerMonthTerabyte)),
+			currencyUnits(is.DynamicPricing.MinDownloadBandwidthPrice.Mul(modules.BytesPerTerabyte)),
+			currencyUnits(is.DynamicPricing.MaxDownloadBandwidthPrice.Mul(modules.BytesPerTerabyte)),
+			currencyUnits(is.DynamicPricing.MinUploadBandwidthPrice.Mul(modules.BytesPerTerabyte)),
+			currencyUnits(is.DynamicPricing.MaxUploadBandwidthPrice.Mul(modules.BytesPerTerabyte)),
+			ratelimitUnits(is.DynamicPricing.ReferenceBandwidth),
 
 			fm.ContractCount, currencyUnits(fm.ContractCompensation),
 			currencyUnits(fm.PotentialContractCompensation),
@@ -344,6 +496,14 @@ RPC Stats:
 		fmt.Println("\nWarning:\n	Your wallet is locked. You must unlock your wallet for the host to function properly.")
 	}
 
+	// print degraded disks
+	if len(hg.DiskHealthWarnings) > 0 {
+		fmt.Println("\nWarning:\n	Degraded disks detected. Consider draining the affected storage folders:")
+		for _, warning := range hg.DiskHealthWarnings {
+			fmt.Println("	" + warning)
+		}
+	}
+
 	fmt.Println("\nStorage Folders:")
 
 	// display storage folder info
@@ -359,7 +519,14 @@ RPC Stats:
 	for _, folder := range sg.Folders {
 		curSize := int64(folder.Capacity - folder.CapacityRemaining)
 		pctUsed := 100 * (float64(curSize) / float64(folder.Capacity))
-		fmt.Fprintf(w, "\t%s\t%s\t%.2f\t%s\n", modules.FilesizeUnits(uint64(curSize)), modules.FilesizeUnits(folder.Capacity), pctUsed, folder.Path)
+		path := folder.Path
+		if folder.Draining {
+			path += " (draining)"
+		}
+		if folder.Health.Degraded {
+			path += " (degraded)"
+		}
+		fmt.Fprintf(w, "\t%s\t%s\t%.2f\t%s\n", modules.FilesizeUnits(uint64(curSize)), modules.FilesizeUnits(folder.Capacity), pctUsed, path)
 	}
 	if err := w.Flush(); err != nil {
 		die("failed to flush writer")
@@ -372,14 +539,14 @@ func hostconfigcmd(param, value string) {
 	var err error
 	switch param {
 	// currency (convert to hastings)
-	case "collateralbudget", "maxcollateral", "minbaserpcprice", "mincontractprice", "minsectoraccessprice", "maxephemeralaccountbalance", "maxephemeralaccountrisk":
+	case "collateralbudget", "maxcollateral", "minbaserpcprice", "mincontractprice", "minsectoraccessprice", "maxephemeralaccountbalance", "maxephemeralaccountrisk", "minrenterfunds":
 		value, err = types.ParseCurrency(value)
 		if err != nil {
 			die("Could not parse "+param+":", err)
 		}
 
 	// currency/TB (convert to hastings/byte)
-	case "mindownloadbandwidthprice", "minuploadbandwidthprice":
+	case "mindownloadbandwidthprice", "minuploadbandwidthprice", "dynamicmindownloadbandwidthprice", "dynamicmaxdownloadbandwidthprice", "dynamicminuploadbandwidthprice", "dynamicmaxuploadbandwidthprice":
 		hastings, err := types.ParseCurrency(value)
 		if err != nil {
 			die("Could not parse "+param+":", err)
@@ -389,7 +556,7 @@ func hostconfigcmd(param, value string) {
 		value = c.String()
 
 	// currency/TB/month (convert to hastings/byte/block)
-	case "collateral", "minstorageprice":
+	case "collateral", "minstorageprice", "dynamicminstorageprice", "dynamicmaxstorageprice":
 		hastings, err := types.ParseCurrency(value)
 		if err != nil {
 			die("Could not parse "+param+":", err)
@@ -399,7 +566,7 @@ func hostconfigcmd(param, value string) {
 		value = c.String()
 
 	// bool (allow "yes" and "no")
-	case "acceptingcontracts":
+	case "acceptingcontracts", "accesslogging", "dynamicpricing", "maintenancemode", "diskhealthsmart":
 		switch strings.ToLower(value) {
 		case "yes":
 			value = "true"
@@ -415,21 +582,29 @@ func hostconfigcmd(param, value string) {
 		}
 
 	// filesize (convert to bytes)
-	case "registrysize":
+	case "registrysize", "trafficpriorityburst", "maxperiodupload", "maxperioddownload", "maxrenterperiodupload", "maxrenterperioddownload":
 		value, err = parseFilesize(value)
 		if err != nil {
 			die("Could not parse "+param+":", err)
 		}
 
+	// ratelimit (convert to bytes per second)
+	case "maxrenterbandwidth", "maxtrafficbandwidth", "dynamicreferencebandwidth":
+		bps, err := parseRatelimit(value)
+		if err != nil {
+			die("Could not parse "+param+":", err)
+		}
+		value = fmt.Sprint(bps)
+
 	// timeout (convert to seconds)
-	case "ephemeralaccountexpiry":
+	case "ephemeralaccountexpiry", "bandwidthperiod":
 		value, err = parseTimeout(value)
 		if err != nil {
 			die("Could not parse "+param+":", err)
 		}
 
 	// other valid settings
-	case "maxdownloadbatchsize", "maxrevisebatchsize", "netaddress", "customregistrypath":
+	case "maxdownloadbatchsize", "maxrevisebatchsize", "netaddress", "customregistrypath", "mincompletedcontracts", "acceptancewindows", "maxrenterrpcrate", "maxrentersessions", "maxregistryupdaterate":
 
 	// invalid settings
 	default:
@@ -475,6 +650,12 @@ func hostcontractcmd() {
 			fmt.Fprintf(w, "%s\t%s\t%d\t%t\t%t\t%t\t%t\t%t\n", so.ObligationId, strings.TrimPrefix(so.ObligationStatus, "obligation"), so.ExpirationHeight, so.OriginConfirmed,
 				so.RevisionConstructed, so.RevisionConfirmed, so.ProofConstructed, so.ProofConfirmed)
 		}
+	case "renters":
+		fmt.Fprintf(w, "Renter\tContracts\tStored Data\tPeriod Upload\tPeriod Download\n")
+		for _, rs := range cg.Renters {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", rs.PublicKey, rs.Contracts, modules.FilesizeUnits(rs.StoredData),
+				modules.FilesizeUnits(rs.Bandwidth.Upload), modules.FilesizeUnits(rs.Bandwidth.Download))
+		}
 	default:
 		die("\"" + hostContractOutputType + "\" is not a format")
 	}
@@ -483,6 +664,129 @@ func hostcontractcmd() {
 	}
 }
 
+// hosttrafficcmd is the handler for the command `siac host traffic`.
+func hosttrafficcmd() {
+	hts, err := httpClient.HostTrafficGet()
+	if err != nil {
+		die("Could not fetch host traffic stats:", err)
+	}
+	if hts.MaxBandwidth == 0 {
+		fmt.Println("Traffic prioritization is disabled.")
+	} else {
+		fmt.Printf(`Max Bandwidth:   %v
+Priority Burst:  %v
+Burst Available: %v
+`, ratelimitUnits(hts.MaxBandwidth), modules.FilesizeUnits(uint64(hts.PriorityBurst)), modules.FilesizeUnits(uint64(hts.BurstAvailable)))
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "Class\tTraffic\tDelayed\tAvg Delay\tMax Delay\tQueued\n")
+	for _, class := range []struct {
+		name  string
+		stats modules.HostTrafficClassStats
+	}{{"Priority", hts.Priority}, {"Bulk", hts.Bulk}} {
+		var avgDelay time.Duration
+		if class.stats.Delayed > 0 {
+			avgDelay = class.stats.TotalDelay / time.Duration(class.stats.Delayed)
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", class.name, modules.FilesizeUnits(class.stats.Bytes), class.stats.Delayed,
+			avgDelay.Round(time.Millisecond), class.stats.MaxDelay.Round(time.Millisecond), class.stats.Queued)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// hostregistrycmd is the handler for the command `siac host registry`.
+// Prints the usage of the host's registry.
+func hostregistrycmd() {
+	hrs, err := httpClient.HostRegistryGet()
+	if err != nil {
+		die("Could not fetch registry stats:", err)
+	}
+	fmt.Printf(`Entries:              %v / %v
+Reads:                %v (%v found)
+Updates:              %v
+Failed Updates:       %v
+Rate Limited Updates: %v
+`, hrs.Entries, hrs.MaxEntries, hrs.Reads, hrs.ReadsFound, hrs.Updates, hrs.FailedUpdates, hrs.RateLimitedUpdates)
+}
+
+// hostrevenuecmd is the handler for the command `siac host revenue`.
+// Prints the revenue of the host per calendar month or exports it as CSV.
+func hostrevenuecmd() {
+	if hostRevenueCSV != "" {
+		csv, err := httpClient.HostRevenueCSVGet(hostRevenueCSV)
+		if err != nil {
+			die("Could not export revenue:", err)
+		}
+		fmt.Print(string(csv))
+		return
+	}
+
+	hrr, err := httpClient.HostRevenueGet()
+	if err != nil {
+		die("Could not fetch revenue:", err)
+	}
+	if len(hrr.Periods) == 0 {
+		fmt.Println("No contracts have been resolved yet.")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "Period\tContracts\tRevenue\tTransaction Fees\tLost Revenue\tBurned Collateral\n")
+	for _, rp := range hrr.Periods {
+		revenue := rp.ContractCompensation.Add(rp.StorageRevenue).Add(rp.DownloadRevenue).Add(rp.UploadRevenue).Add(rp.AccountFunding)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", rp.Period, rp.Contracts, currencyUnits(revenue),
+			currencyUnits(rp.TransactionFees), currencyUnits(rp.LostRevenue), currencyUnits(rp.BurnedCollateral))
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// hostscrubcmd is the handler for the command `siac host scrub`.
+// Prints the progress of the integrity scrubber and the contracts at risk.
+func hostscrubcmd() {
+	hsr, err := httpClient.HostStorageScrubGet()
+	if err != nil {
+		die("Could not fetch scrub report:", err)
+	}
+	passStart, lastPassEnd := "never", "never"
+	if !hsr.PassStart.IsZero() {
+		passStart = hsr.PassStart.Format(time.RFC822)
+	}
+	if !hsr.LastPassEnd.IsZero() {
+		lastPassEnd = hsr.LastPassEnd.Format(time.RFC822)
+	}
+	fmt.Printf(`Pass Started:      %v
+Last Pass Ended:   %v
+Sectors Scrubbed:  %v
+Corrupt Sectors:   %v
+`, passStart, lastPassEnd, hsr.SectorsScrubbed, hsr.CorruptSectors)
+	if len(hsr.ContractsAtRisk) == 0 {
+		return
+	}
+
+	cg, err := httpClient.ConsensusGet()
+	if err != nil {
+		die("Could not fetch consensus:", err)
+	}
+	fmt.Println()
+	fmt.Println("Contracts at risk:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(w, "ID\tCorrupt Sectors\tProof Window\tBlocks Left\tCollateral\n")
+	for _, c := range hsr.ContractsAtRisk {
+		var blocksLeft types.BlockHeight
+		if c.ProofWindowStart > cg.Height {
+			blocksLeft = c.ProofWindowStart - cg.Height
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v-%v\t%v\t%v\n", c.ContractID, c.CorruptSectors, c.ProofWindowStart, c.ProofDeadline, blocksLeft, currencyUnits(c.LockedCollateral))
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
 // hostannouncecmd is the handler for the command `siac host announce`.
 // Announces yourself as a host to the network. Optionally takes an address to
 // announce as.
@@ -550,6 +854,19 @@ func hostfolderremovecmd(path string) {
 	fmt.Println("Removed folder", path)
 }
 
+// hostfolderdraincmd marks a folder of the host for removal.
+func hostfolderdraincmd(path string) {
+	err := httpClient.HostStorageFoldersDrainPost(abs(path), !hostFolderDrainStop)
+	if err != nil {
+		die("Could not drain folder:", err)
+	}
+	if hostFolderDrainStop {
+		fmt.Println("Stopped draining folder", path)
+		return
+	}
+	fmt.Println("Draining folder", path)
+}
+
 // hostfolderresizecmd resizes a folder in the host.
 func hostfolderresizecmd(path, newsize string) {
 	newsize, err := parseFilesize(newsize)
@@ -582,3 +899,16 @@ func hostsectordeletecmd(root string) {
 	}
 	fmt.Println("Deleted sector", root)
 }
+
+// acceptanceWindowsString returns a human readable representation of the
+// host's contract acceptance windows.
+func acceptanceWindowsString(windows []modules.HostAcceptanceWindow) string {
+	if len(windows) == 0 {
+		return "always"
+	}
+	strs := make([]string, 0, len(windows))
+	for _, w := range windows {
+		strs = append(strs, w.String())
+	}
+	return strings.Join(strs, ", ")
+}
@@ -65,7 +65,9 @@ func printScoreBreakdown(info *api.HostdbHostsGET) {
 	fmt.Fprintf(w, "\t\tCollateral:\t %.3f\n", info.ScoreBreakdown.CollateralAdjustment/1e96)
 	fmt.Fprintf(w, "\t\tDuration:\t %.3f\n", info.ScoreBreakdown.DurationAdjustment)
 	fmt.Fprintf(w, "\t\tInteraction:\t %.3f\n", info.ScoreBreakdown.InteractionAdjustment)
+	fmt.Fprintf(w, "\t\tLatency:\t %.3f\n", info.ScoreBreakdown.LatencyAdjustment)
 	fmt.Fprintf(w, "\t\tPrice:\t %.3f\n", info.ScoreBreakdown.PriceAdjustment*1e24)
+	fmt.Fprintf(w, "\t\tRegion:\t %.3f\n", info.ScoreBreakdown.RegionAdjustment)
 	fmt.Fprintf(w, "\t\tStorage:\t %.3f\n", info.ScoreBreakdown.StorageRemainingAdjustment)
 	fmt.Fprintf(w, "\t\tUptime:\t %.3f\n", info.ScoreBreakdown.UptimeAdjustment)
 	fmt.Fprintf(w, "\t\tVersion:\t %.3f\n", info.ScoreBreakdown.VersionAdjustment)
@@ -398,6 +400,8 @@ func hostdbviewcmd(pubkey string) {
 	fmt.Println("  NetAddress:               ", info.Entry.NetAddress)
 	fmt.Println("  Last IP Net Change:       ", info.Entry.LastIPNetChange)
 	fmt.Println("  Number of IP Net Changes: ", len(info.Entry.IPNets))
+	fmt.Println("  Latency:                  ", info.Entry.Latency)
+	fmt.Println("  Region:                   ", info.Entry.Region)
 
 	fmt.Println("\n  Host Settings:")
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -23,6 +23,9 @@ var (
 
 	// Module Specific Flags
 	//
+	// Consensus Flags
+	consensusVerifyRepair bool // Repair the inconsistencies which can be fixed in place
+
 	// Daemon Flags
 	daemonStackOutputFile  string // The file that the stack trace will be written to
 	daemonCPUProfile       bool   // Indicates that the CPU profile should be started
@@ -30,24 +33,40 @@ var (
 	daemonProfileDirectory string // The Directory where the profile logs are saved
 	daemonTraceProfile     bool   // Indicates that the Trace profile should be started
 
+	// Gateway Flags
+	gatewayBanDuration string // duration of a temporary ban
+	gatewayBanReason   string // reason of a ban
+
 	// Host Flags
 	hostContractOutputType string // output type for host contracts
 	hostFolderRemoveForce  bool   // force folder remove
+	hostFolderDrainStop    bool   // stop draining a folder
+	hostRevenueCSV         string // export the host's revenue as CSV
 
 	// Renter Flags
 	dataPieces                string // the number of data pieces a file should be uploaded with
 	parityPieces              string // the number of parity pieces a file should be uploaded with
 	renterAllContracts        bool   // Show all active and expired contracts
 	renterBubbleAll           bool   // Bubble the entire directory tree
+	renterDeleteAsync         bool   // Delete folders in the background and poll the progress.
+	renterDeleteDryRun        bool   // Only report what would be deleted.
+	renterDeleteRecursive     bool   // Delete all paths with a single batch request.
 	renterDeleteRoot          bool   // Delete path start from root instead of the UserFolder.
 	renterDownloadAsync       bool   // Downloads files asynchronously
 	renterDownloadRecursive   bool   // Downloads folders recursively.
 	renterDownloadRoot        bool   // Download path start from root instead of the UserFolder.
 	renterFuseMountAllowOther bool   // Mount fuse with 'AllowOther' set to true.
+	renterFuseMountReadOnly   bool   // Mount fuse with 'ReadOnly' set to true.
+	renterDirHealthRecursive  bool   // Show the health of all subfolders recursively.
+	renterEventsFollow        bool   // Keep waiting for new events.
 	renterListRecursive       bool   // List files of folder recursively.
 	renterListRoot            bool   // List path start from root instead of the UserFolder.
+	renterReencodeRoot        bool   // Re-encode files relative to root instead of the UserFolder.
 	renterRenameRoot          bool   // Rename files relative to root instead of the UserFolder.
 	renterShowHistory         bool   // Show download history in addition to download queue.
+	renterSpendingPeriod      uint64 // Start height of the period to show the spending of.
+	renterVerifyRoot          bool   // Verify files relative to root instead of the UserFolder.
+	renterVerifySample        uint64 // Number of random pieces to verify, 0 verifies all pieces.
 
 	// Renter Allowance Flags
 	allowanceFunds       string // amount of money to be used within a period
@@ -67,6 +86,10 @@ var (
 	allowanceMaxStoragePrice           string // max allowed price to store data on a host
 	allowanceMaxUploadBandwidthPrice   string // max allowed price to upload data to a host
 
+	allowanceMinSubnets       string // minimum number of subnets to spread contracts across
+	allowancePreferredLatency string // preferred maximum latency of hosts
+	allowancePreferredRegions string // comma separated list of preferred host regions
+
 	// Skykey Flags
 	skykeyID              string // ID used to identify a Skykey.
 	skykeyName            string // Name used to identify a Skykey.
@@ -84,6 +107,19 @@ var (
 	walletStartHeight    uint64 // Start height for transaction search.
 	walletEndHeight      uint64 // End height for transaction search.
 	walletTxnFeeIncluded bool   // include the fee in the balance being sent
+	walletTxnStrategy    string // strategy used to select the outputs funding a transaction
+	walletTxnLabel       string // label of the sent transaction
+	walletSendTo         string // address book entry receiving the coins
+	walletWatchUnused    bool   // don't rescan the blockchain for watched addresses
+	walletMultisigUnused bool   // don't rescan the blockchain for a new multisig address
+	walletColdChange     string // address receiving the change of a cold transaction
+	walletRescanGapLimit uint64 // number of addresses searched for beyond the last used address
+	walletDailyLimit     string // maximum amount of siacoins sent within a day
+	walletApproveAbove   string // amount above which sends need to be approved
+	walletWebhook        string // URL notified about sends waiting for approval
+	walletBIP39          bool   // encode seeds as BIP39 mnemonics
+	walletEventsSince    uint64 // ID of the last wallet event that was seen
+	walletBumpFee        string // fee per KB when bumping the fee of a transaction set
 	insecureInput        bool   // Insecure password/seed input. Disables the shoulder-surfing and Mac secure input feature.
 )
 
@@ -290,52 +326,76 @@ func initCmds() *cobra.Command {
 
 	// create command tree (alphabetized by root command)
 	root.AddCommand(consensusCmd)
+	consensusCmd.AddCommand(consensusReorgCmd, consensusVerifyCmd)
+	consensusReorgCmd.AddCommand(consensusReorgConfirmCmd)
+	consensusVerifyCmd.Flags().BoolVarP(&consensusVerifyRepair, "repair", "", false, "Repair the inconsistencies which can be fixed without resyncing")
+
+	root.AddCommand(doctorCmd)
 	root.AddCommand(jsonCmd)
 
 	root.AddCommand(gatewayCmd)
-	gatewayCmd.AddCommand(gatewayAddressCmd, gatewayBandwidthCmd, gatewayBlocklistCmd, gatewayConnectCmd, gatewayDisconnectCmd, gatewayListCmd, gatewayRatelimitCmd)
+	gatewayCmd.AddCommand(gatewayAddressCmd, gatewayBandwidthCmd, gatewayBanCmd, gatewayBansCmd, gatewayBlocklistCmd, gatewayConnLimitsCmd, gatewayConnectCmd, gatewayConnectivityCmd, gatewayDisconnectCmd, gatewayListCmd, gatewayRatelimitCmd, gatewayUnbanCmd)
+	gatewayBanCmd.Flags().StringVar(&gatewayBanDuration, "duration", "", "Ban the peers temporarily for the duration, e.g. 24h, instead of permanently")
+	gatewayBanCmd.Flags().StringVar(&gatewayBanReason, "reason", "", "Reason of the ban")
 	gatewayBlocklistCmd.AddCommand(gatewayBlocklistAppendCmd, gatewayBlocklistClearCmd, gatewayBlocklistRemoveCmd, gatewayBlocklistSetCmd)
 
 	root.AddCommand(hostCmd)
-	hostCmd.AddCommand(hostAnnounceCmd, hostConfigCmd, hostContractCmd, hostFolderCmd, hostSectorCmd)
-	hostFolderCmd.AddCommand(hostFolderAddCmd, hostFolderRemoveCmd, hostFolderResizeCmd)
+	hostCmd.AddCommand(hostAnnounceCmd, hostConfigCmd, hostContractCmd, hostFolderCmd, hostRegistryCmd, hostRevenueCmd, hostScrubCmd, hostSectorCmd, hostTrafficCmd)
+	hostFolderCmd.AddCommand(hostFolderAddCmd, hostFolderDrainCmd, hostFolderRemoveCmd, hostFolderResizeCmd)
 	hostSectorCmd.AddCommand(hostSectorDeleteCmd)
 	hostContractCmd.Flags().StringVarP(&hostContractOutputType, "type", "t", "value", "Select output type")
 	hostFolderRemoveCmd.Flags().BoolVarP(&hostFolderRemoveForce, "force", "f", false, "Force the removal of the folder and its data")
+	hostRevenueCmd.Flags().StringVar(&hostRevenueCSV, "csv", "", "Export the revenue as CSV, either 'contracts' or 'periods'")
+	hostFolderDrainCmd.Flags().BoolVar(&hostFolderDrainStop, "stop", false, "Stop draining the folder")
 
 	root.AddCommand(hostdbCmd)
 	hostdbCmd.AddCommand(hostdbFiltermodeCmd, hostdbSetFiltermodeCmd, hostdbViewCmd)
 	hostdbCmd.Flags().IntVarP(&hostdbNumHosts, "numhosts", "n", 0, "Number of hosts to display from the hostdb")
 
 	root.AddCommand(minerCmd)
-	minerCmd.AddCommand(minerStartCmd, minerStopCmd)
+	minerCmd.AddCommand(minerPayoutsCmd, minerStartCmd, minerStopCmd)
+	minerPayoutsCmd.AddCommand(minerPayoutsClearCmd, minerPayoutsSetCmd)
 
 	root.AddCommand(renterCmd)
 	renterCmd.AddCommand(renterAllowanceCmd, renterBubbleCmd, renterBackupCreateCmd, renterBackupListCmd, renterBackupLoadCmd,
+		renterBackupPruneCmd, renterBackupScheduleCmd,
 		renterCleanCmd, renterContractsCmd, renterContractsRecoveryScanProgressCmd, renterDownloadCancelCmd,
 		renterDownloadsCmd, renterExportCmd, renterFilesDeleteCmd, renterFilesDownloadCmd,
-		renterFilesListCmd, renterFilesRenameCmd, renterFilesUnstuckCmd, renterFilesUploadCmd,
-		renterFuseCmd, renterLostCmd, renterPricesCmd, renterRatelimitCmd, renterSetAllowanceCmd,
-		renterSetLocalPathCmd, renterTriggerContractRecoveryScanCmd, renterUploadsCmd, renterWorkersCmd,
-		renterHealthSummaryCmd)
+		renterFilesListCmd, renterFilesReencodeCmd, renterFilesRenameCmd, renterFilesUnstuckCmd, renterFilesUploadCmd,
+		renterFuseCmd, renterLoadSharedFileCmd, renterLostCmd, renterPricesCmd, renterRatelimitCmd, renterReadOnlyCmd, renterSetAllowanceCmd,
+		renterRestoreDrillsCmd, renterSetLocalPathCmd, renterTriggerContractRecoveryScanCmd, renterUploadsCmd, renterVerifyCmd, renterWorkersCmd,
+		renterHealthSummaryCmd, renterDirHealthCmd, renterPinCmd, renterPinnedCmd, renterUnpinCmd,
+		renterArchiveCmd, renterArchivedCmd, renterThawCmd, renterChunkCacheCmd, renterEventsCmd, renterSpendingCmd, renterHostListsCmd,
+		renterMigrationsCmd)
 	renterWorkersCmd.AddCommand(renterWorkersAccountsCmd, renterWorkersDownloadsCmd, renterWorkersPriceTableCmd, renterWorkersReadJobsCmd, renterWorkersHasSectorJobSCmd, renterWorkersUploadsCmd, renterWorkersReadRegistryCmd, renterWorkersUpdateRegistryCmd)
 
 	renterAllowanceCmd.AddCommand(renterAllowanceCancelCmd)
+	renterChunkCacheCmd.AddCommand(renterChunkCacheSetSizeCmd)
+	renterHostListsCmd.AddCommand(renterHostListsAllowCmd, renterHostListsDenyCmd, renterHostListsRemoveCmd)
 	renterBubbleCmd.Flags().BoolVarP(&renterBubbleAll, "all", "A", false, "Bubble the entire directory tree")
 	renterContractsCmd.AddCommand(renterContractsViewCmd)
 	renterFilesUploadCmd.AddCommand(renterFilesUploadPauseCmd, renterFilesUploadResumeCmd)
 
 	renterContractsCmd.Flags().BoolVarP(&renterAllContracts, "all", "A", false, "Show all expired contracts in addition to active contracts")
 	renterDownloadsCmd.Flags().BoolVarP(&renterShowHistory, "history", "H", false, "Show download history in addition to the download queue")
+	renterFilesDeleteCmd.Flags().BoolVar(&renterDeleteAsync, "async", false, "Delete folders in the background and report the progress")
+	renterFilesDeleteCmd.Flags().BoolVar(&renterDeleteDryRun, "dry-run", false, "Only report the number of files and bytes that would be deleted")
+	renterFilesDeleteCmd.Flags().BoolVarP(&renterDeleteRecursive, "recursive", "r", false, "Delete all paths with a single request, including folders and their contents, and list every deleted file")
 	renterFilesDeleteCmd.Flags().BoolVar(&renterDeleteRoot, "root", false, "Delete files and folders from root instead of from the user home directory")
 	renterFilesDownloadCmd.Flags().BoolVarP(&renterDownloadAsync, "async", "A", false, "Download file asynchronously")
 	renterFilesDownloadCmd.Flags().BoolVarP(&renterDownloadRecursive, "recursive", "R", false, "Download folder recursively")
 	renterFilesDownloadCmd.Flags().BoolVar(&renterDownloadRoot, "root", false, "Download files and folders from root instead of from the user home directory")
 	renterFilesListCmd.Flags().BoolVarP(&renterListRecursive, "recursive", "R", false, "Recursively list files and folders")
+	renterDirHealthCmd.Flags().BoolVarP(&renterDirHealthRecursive, "recursive", "R", false, "Show the health of all subfolders recursively")
+	renterEventsCmd.Flags().BoolVarP(&renterEventsFollow, "follow", "f", false, "Keep waiting for new events")
+	renterSpendingCmd.Flags().Uint64Var(&renterSpendingPeriod, "period", 0, "Start height of the period to show the spending of, defaults to the current period")
 	renterFilesListCmd.Flags().BoolVar(&renterListRoot, "root", false, "List files and folders from root instead of from the user home directory")
 	renterFilesUploadCmd.Flags().StringVar(&dataPieces, "data-pieces", "", "the number of data pieces a files should be uploaded with")
 	renterFilesUploadCmd.Flags().StringVar(&parityPieces, "parity-pieces", "", "the number of parity pieces a files should be uploaded with")
-	renterExportCmd.AddCommand(renterExportContractTxnsCmd)
+	renterExportCmd.AddCommand(renterExportContractTxnsCmd, renterExportFileKeysCmd, renterExportSharedFileCmd)
+	renterVerifyCmd.Flags().BoolVar(&renterVerifyRoot, "root", false, "Verify files relative to root instead of the user homedir")
+	renterVerifyCmd.Flags().Uint64Var(&renterVerifySample, "sample", 10, "The number of random pieces to verify, 0 verifies all pieces")
+	renterFilesReencodeCmd.Flags().BoolVar(&renterReencodeRoot, "root", false, "Re-encode files relative to root instead of the user homedir")
 	renterFilesRenameCmd.Flags().BoolVar(&renterRenameRoot, "root", false, "Rename files relative to root instead of the user homedir")
 
 	renterSetAllowanceCmd.Flags().StringVar(&allowanceFunds, "amount", "", "amount of money in allowance, specified in currency units")
@@ -352,9 +412,15 @@ func initCmds() *cobra.Command {
 	renterSetAllowanceCmd.Flags().StringVar(&allowanceMaxSectorAccessPrice, "max-sector-access-price", "", "the maximum price that the renter will pay to access a sector on a host")
 	renterSetAllowanceCmd.Flags().StringVar(&allowanceMaxStoragePrice, "max-storage-price", "", "the maximum price that the renter will pay to store data on a host")
 	renterSetAllowanceCmd.Flags().StringVar(&allowanceMaxUploadBandwidthPrice, "max-upload-bandwidth-price", "", "the maximum price that the renter will pay to upload data to a host")
+	renterSetAllowanceCmd.Flags().StringVar(&allowancePreferredLatency, "preferred-latency", "", "the latency above which hosts are scored lower, e.g. 100ms, 0 disables the preference")
+	renterSetAllowanceCmd.Flags().StringVar(&allowancePreferredRegions, "preferred-regions", "", "comma separated list of regions whose hosts are preferred, an empty list disables the preference")
+	renterSetAllowanceCmd.Flags().StringVar(&allowanceMinSubnets, "min-subnets", "", "the minimum number of distinct subnets the renter's contracts should be spread across")
 
 	renterFuseCmd.AddCommand(renterFuseMountCmd, renterFuseUnmountCmd)
+	renterBackupScheduleCmd.AddCommand(renterBackupScheduleDisableCmd, renterBackupScheduleEnableCmd, renterBackupScheduleRunCmd)
+	renterRestoreDrillsCmd.AddCommand(renterRestoreDrillsDisableCmd, renterRestoreDrillsEnableCmd, renterRestoreDrillsRunCmd)
 	renterFuseMountCmd.Flags().BoolVarP(&renterFuseMountAllowOther, "allow-other", "", false, "Allow users other than the user that mounted the fuse directory to access and use the fuse directory")
+	renterFuseMountCmd.Flags().BoolVarP(&renterFuseMountReadOnly, "read-only", "", false, "Mount the fuse directory in read-only mode")
 
 	// Daemon Commands
 	root.AddCommand(alertsCmd, globalRatelimitCmd, profileCmd, stackCmd, stopCmd, updateCmd, versionCmd)
@@ -374,21 +440,46 @@ func initCmds() *cobra.Command {
 	utilsVerifySeedCmd.Flags().StringVarP(&dictionaryLanguage, "language", "l", "english", "which dictionary you want to use")
 
 	root.AddCommand(walletCmd)
-	walletCmd.AddCommand(walletAddressCmd, walletAddressesCmd, walletBalanceCmd, walletBroadcastCmd, walletChangepasswordCmd,
-		walletInitCmd, walletInitSeedCmd, walletLoadCmd, walletLockCmd, walletSeedsCmd, walletSendCmd,
-		walletSignCmd, walletSweepCmd, walletTransactionsCmd, walletUnlockCmd)
+	walletCmd.AddCommand(walletAddressCmd, walletAddressBookCmd, walletAddressesCmd, walletBalanceCmd, walletBroadcastCmd, walletBumpFeeCmd, walletChangepasswordCmd,
+		walletColdCmd, walletCPFPCmd, walletEventsCmd, walletInitCmd, walletInitSeedCmd, walletLabelCmd, walletLimitsCmd, walletLoadCmd, walletLockCmd, walletMultisigCmd, walletPendingCmd, walletRescanCmd, walletSeedsCmd, walletSendCmd,
+		walletSignCmd, walletStuckCmd, walletSweepCmd, walletTransactionsCmd, walletUnlockCmd, walletUnspentCmd, walletWatchCmd)
+	walletAddressBookCmd.AddCommand(walletAddressBookAddCmd, walletAddressBookRemoveCmd)
 	walletInitCmd.Flags().BoolVarP(&initPassword, "password", "p", false, "Prompt for a custom password")
 	walletInitCmd.Flags().BoolVarP(&initForce, "force", "", false, "destroy the existing wallet and re-encrypt")
+	walletBumpFeeCmd.Flags().StringVar(&walletBumpFee, "fee", "", "Fee per KB of the replacement, defaults to the priority fee estimation")
+	walletCPFPCmd.Flags().StringVar(&walletBumpFee, "fee", "", "Fee per KB of the set including the child, defaults to the priority fee estimation")
+	walletEventsCmd.Flags().Uint64VarP(&walletEventsSince, "since", "", 0, "Only print the events after the event with this ID")
+	walletInitCmd.Flags().BoolVarP(&walletBIP39, "bip39", "", false, "Display the seed as a BIP39 mnemonic")
+	walletSeedsCmd.Flags().BoolVarP(&walletBIP39, "bip39", "", false, "Display the seeds as BIP39 mnemonics")
 	walletInitSeedCmd.Flags().BoolVarP(&initForce, "force", "", false, "destroy the existing wallet")
 	walletLoadCmd.AddCommand(walletLoad033xCmd, walletLoadSeedCmd, walletLoadSiagCmd)
 	walletSendCmd.AddCommand(walletSendSiacoinsCmd, walletSendSiafundsCmd)
 	walletSendSiacoinsCmd.Flags().BoolVarP(&walletTxnFeeIncluded, "fee-included", "", false, "Take the transaction fee out of the balance being submitted instead of the fee being additional")
+	walletSendSiacoinsCmd.Flags().StringVar(&walletTxnStrategy, "strategy", "", "Strategy used to select the outputs funding the transaction: minimizeinputs, minimizechange or consolidate")
+	walletSendSiacoinsCmd.Flags().StringVar(&walletTxnLabel, "label", "", "Label of the sent transaction")
+	walletSendSiacoinsCmd.Flags().StringVar(&walletSendTo, "to", "", "Name of the address book entry receiving the coins")
 	walletUnlockCmd.Flags().BoolVarP(&insecureInput, "insecure-input", "", false, "Disable shoulder-surf protection (echoing passwords and seeds)")
 	walletUnlockCmd.Flags().BoolVarP(&initPassword, "password", "p", false, "Display interactive password prompt even if SIA_WALLET_PASSWORD is set")
 	walletBroadcastCmd.Flags().BoolVarP(&walletRawTxn, "raw", "", false, "Decode transaction as base64 instead of JSON")
 	walletSignCmd.Flags().BoolVarP(&walletRawTxn, "raw", "", false, "Encode signed transaction as base64 instead of JSON")
 	walletTransactionsCmd.Flags().Uint64Var(&walletStartHeight, "startheight", 0, " Height of the block where transaction history should begin.")
 	walletTransactionsCmd.Flags().Uint64Var(&walletEndHeight, "endheight", math.MaxUint64, " Height of the block where transaction history should end.")
+	walletRescanCmd.Flags().Uint64Var(&walletRescanGapLimit, "gap-limit", 0, "Number of addresses searched for beyond the last address in use")
+	walletLimitsCmd.Flags().StringVar(&walletDailyLimit, "daily-limit", "", "Maximum amount of siacoins sent within 24 hours, 0 to disable")
+	walletLimitsCmd.Flags().StringVar(&walletApproveAbove, "approval-threshold", "", "Amount above which sends need to be approved, 0 to disable")
+	walletLimitsCmd.Flags().StringVar(&walletWebhook, "webhook", "", "URL notified about sends waiting for approval")
+	walletPendingCmd.AddCommand(walletPendingApproveCmd, walletPendingRejectCmd)
+	walletPendingApproveCmd.Flags().StringVar(&walletTxnLabel, "label", "", "Label of the sent transaction")
+	walletColdCmd.AddCommand(walletColdBroadcastCmd, walletColdKeysCmd, walletColdSendCmd, walletColdSignCmd)
+	walletColdSendCmd.Flags().StringVar(&walletColdChange, "change", "", "Address receiving the change")
+	walletColdSignCmd.Flags().BoolVarP(&walletRawTxn, "raw", "", false, "Encode signed transaction as base64 instead of JSON")
+	walletMultisigCmd.AddCommand(walletMultisigAddressCmd, walletMultisigMergeCmd, walletMultisigSendCmd, walletMultisigSignCmd)
+	walletMultisigCmd.PersistentFlags().BoolVarP(&walletRawTxn, "raw", "", false, "Encode transactions as base64 instead of JSON")
+	walletMultisigAddressCmd.Flags().BoolVar(&walletMultisigUnused, "unused", false, "Don't rescan the blockchain because the address has never been used")
+	walletUnspentCmd.AddCommand(walletUnspentAllowSpendCmd, walletUnspentDoNotSpendCmd)
+	walletWatchCmd.AddCommand(walletWatchAddCmd, walletWatchRemoveCmd)
+	walletWatchAddCmd.Flags().BoolVar(&walletWatchUnused, "unused", false, "Don't rescan the blockchain because the address has never been used")
+	walletWatchRemoveCmd.Flags().BoolVar(&walletWatchUnused, "unused", false, "Don't rescan the blockchain because the address has never been used")
 
 	return root
 }
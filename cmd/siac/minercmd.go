@@ -2,9 +2,12 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/node/api"
 )
 
@@ -16,6 +19,33 @@ var (
 		Run:   wrap(minercmd),
 	}
 
+	minerPayoutsCmd = &cobra.Command{
+		Use:   "payouts",
+		Short: "View the payout splits",
+		Long:  "View the addresses the block reward is split across. The part of the reward which isn't assigned to an address is paid to the wallet.",
+		Run:   wrap(minerpayoutscmd),
+	}
+
+	minerPayoutsClearCmd = &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all payout splits",
+		Long:  "Remove all payout splits, so that the whole block reward is paid to the wallet.",
+		Run:   wrap(minerpayoutsclearcmd),
+	}
+
+	minerPayoutsSetCmd = &cobra.Command{
+		Use:   "set [address:percentage]...",
+		Short: "Split the block reward across addresses",
+		Long: `Split the block reward across addresses, replacing the current payout splits.
+Every split assigns a percentage of the reward to an address, with a precision
+of 0.01%. The part of the reward which isn't assigned to an address is paid to
+the wallet.
+
+Example:
+  siac miner payouts set <operator-address>:2.5 <pool-address>:97.5`,
+		Run: minerpayoutssetcmd,
+	}
+
 	minerStartCmd = &cobra.Command{
 		Use:   "start",
 		Short: "Start cpu mining",
@@ -62,6 +92,79 @@ CPU Mining:   %s
 CPU Hashrate: %v KH/s
 Blocks Mined: %d (%d stale)
 `, miningStr, status.CPUHashrate/1000, status.BlocksMined, status.StaleBlocksMined)
+
+	stratum, err := httpClient.MinerStratumGet()
+	if err != nil {
+		die("Could not get stratum server status:", err)
+	}
+	if stratum.Address == "" {
+		return
+	}
+	fmt.Printf(`
+Stratum Server: %s
+Workers:        %d
+Shares:         %d accepted, %d rejected, %d stale
+Blocks Found:   %d
+`, stratum.Address, stratum.Workers, stratum.AcceptedShares, stratum.RejectedShares, stratum.StaleShares, stratum.BlocksFound)
+}
+
+// minerpayoutscmd is the handler for the command `siac miner payouts`.
+// Prints the payout splits.
+func minerpayoutscmd() {
+	mpg, err := httpClient.MinerPayoutsGet()
+	if err != nil {
+		die("Could not get payout splits:", err)
+	}
+	if len(mpg.Splits) == 0 {
+		fmt.Println("The whole block reward is paid to the wallet.")
+		return
+	}
+	var total float64
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Address\tPercentage")
+	for _, split := range mpg.Splits {
+		fmt.Fprintf(w, "%v\t%v%%\n", split.UnlockHash, split.Percentage)
+		total += split.Percentage
+	}
+	if total < 100 {
+		fmt.Fprintf(w, "Wallet\t%.2f%%\n", 100-total)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// minerpayoutsclearcmd is the handler for the command `siac miner payouts
+// clear`. Removes all payout splits.
+func minerpayoutsclearcmd() {
+	if err := httpClient.MinerPayoutsPost(nil); err != nil {
+		die("Could not clear payout splits:", err)
+	}
+	fmt.Println("The whole block reward is now paid to the wallet.")
+}
+
+// minerpayoutssetcmd is the handler for the command `siac miner payouts set`.
+// Replaces the payout splits.
+func minerpayoutssetcmd(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	splits := make([]modules.MinerPayoutSplit, len(args))
+	for i, arg := range args {
+		split, err := modules.ParseMinerPayoutSplit(arg)
+		if err != nil {
+			die(err)
+		}
+		splits[i] = split
+	}
+	if err := modules.ValidateMinerPayoutSplits(splits); err != nil {
+		die(err)
+	}
+	if err := httpClient.MinerPayoutsPost(splits); err != nil {
+		die("Could not set payout splits:", err)
+	}
+	fmt.Println("Payout splits have been updated.")
 }
 
 // minerstopcmd is the handler for the command `siac miner stop`.
@@ -16,6 +16,7 @@ import (
 	"gitlab.com/NebulousLabs/encoding"
 	"gitlab.com/NebulousLabs/errors"
 	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/types"
 )
 
@@ -352,6 +353,22 @@ func parseTxn(s string) (types.Transaction, error) {
 	return txn, nil
 }
 
+// parseColdTxn decodes a JSON encoded cold transaction, which may also be
+// stored in a file.
+func parseColdTxn(s string) (modules.ColdTransaction, error) {
+	ctBytes, err := ioutil.ReadFile(s)
+	if os.IsNotExist(err) {
+		ctBytes = []byte(s)
+	} else if err != nil {
+		return modules.ColdTransaction{}, errors.New("could not read cold transaction file: " + err.Error())
+	}
+	var ct modules.ColdTransaction
+	if err := json.Unmarshal(ctBytes, &ct); err != nil {
+		return modules.ColdTransaction{}, errors.New("could not decode JSON cold transaction: " + err.Error())
+	}
+	return ct, nil
+}
+
 // fmtDuration converts a time.Duration into a days,hours,minutes string
 func fmtDuration(dur time.Duration) string {
 	dur = dur.Round(time.Minute)
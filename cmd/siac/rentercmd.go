@@ -85,6 +85,47 @@ To bubble the root directory pass in '.' as the directory.`,
 		Run:   wrap(renterbackuplistcmd),
 	}
 
+	renterBackupPruneCmd = &cobra.Command{
+		Use:   "prunebackup [name]",
+		Short: "Prune a backup stored on hosts",
+		Long: `Remove the backup with the given name from the renter. Hosts drop the
+backup the next time their backup table is updated.`,
+		Run: wrap(renterbackupprunecmd),
+	}
+
+	renterBackupScheduleCmd = &cobra.Command{
+		Use:   "backupschedule",
+		Short: "View the renter's scheduled backups",
+		Long: `View the settings of the renter's scheduled backups and the scheduled
+backups stored on hosts. Scheduled backups are encrypted and uploaded
+periodically and can be restored with the 'restorebackup' command.`,
+		Run: wrap(renterbackupschedulecmd),
+	}
+
+	renterBackupScheduleDisableCmd = &cobra.Command{
+		Use:   "disable",
+		Short: "Disable scheduled backups",
+		Long:  "Stop the renter from creating backups periodically.",
+		Run:   wrap(renterbackupscheduledisablecmd),
+	}
+
+	renterBackupScheduleEnableCmd = &cobra.Command{
+		Use:   "enable [interval] [generations]",
+		Short: "Enable scheduled backups",
+		Long: `Make the renter create and upload a backup periodically. The optional
+interval specifies the time between two backups, e.g. '12h'. It defaults to 24
+hours. The optional generations specify the number of backups that are kept,
+older ones are pruned. It defaults to 7.`,
+		Run: renterbackupscheduleenablecmd,
+	}
+
+	renterBackupScheduleRunCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Create a scheduled backup now",
+		Long:  "Create and upload a scheduled backup right away.",
+		Run:   wrap(renterbackupscheduleruncmd),
+	}
+
 	renterCleanCmd = &cobra.Command{
 		Use:   "clean",
 		Short: "Cleans up lost files",
@@ -142,7 +183,7 @@ the command 'siac renter lost' to see the renter's lost files.`,
 		Use:     "delete [path]",
 		Aliases: []string{"rm"},
 		Short:   "Delete a file or folder",
-		Long:    "Delete a file or folder. Does not delete the file/folder on disk.  Multiple files may be deleted with space separation.",
+		Long:    "Delete a file or folder. Does not delete the file/folder on disk.  Multiple files may be deleted with space separation. Use --dry-run to see how many files and bytes would be deleted, --async to delete large folders in the background and --recursive to delete all paths with a single request.",
 		Run:     renterfilesdeletecmd,
 	}
 
@@ -160,6 +201,15 @@ the command 'siac renter lost' to see the renter's lost files.`,
 		Run:   renterfileslistcmd,
 	}
 
+	renterFilesReencodeCmd = &cobra.Command{
+		Use:   "reencode [path] [datapieces] [paritypieces]",
+		Short: "Change the erasure code of a file",
+		Long: `Convert a file to new erasure code parameters. The file is downloaded, re-encoded
+and uploaded again chunk by chunk. The previous version of the file is replaced once the
+re-encoded file is available on the network.`,
+		Run: wrap(renterfilesreencodecmd),
+	}
+
 	renterFilesRenameCmd = &cobra.Command{
 		Use:     "rename [path] [newpath]",
 		Aliases: []string{"mv"},
@@ -179,10 +229,9 @@ the command 'siac renter lost' to see the renter's lost files.`,
 		Use:   "mount [path] [siapath]",
 		Short: "Mount a Sia folder to your disk",
 		Long: `Mount a Sia folder to your disk. Applications will be able to see this folder
-as though it is a normal part of your filesystem.  Currently experimental, and
-read-only. When Sia is ready to support read-write fuse mounting, siac will be
-updated to mount in read-write mode as the default. If you must guarantee that
-read-only mode is used, you must use the API.`,
+as though it is a normal part of your filesystem.  Currently experimental.
+Files written to the mount are buffered on disk and uploaded when they are
+closed or synced. Use --read-only to prevent any changes to the Sia folder.`,
 		Run: wrap(renterfusemountcmd),
 	}
 
@@ -194,6 +243,14 @@ local path where the Sia folder is mounted.`,
 		Run: wrap(renterfuseunmountcmd),
 	}
 
+	renterLoadSharedFileCmd = &cobra.Command{
+		Use:   "loadsharedfile [source] [path]",
+		Short: "Load a shared file",
+		Long: `Load a shared file that was exported with 'siac renter export shared-file'
+into the renter at [path]. The file can be downloaded once it is loaded.`,
+		Run: wrap(renterloadsharedfilecmd),
+	}
+
 	renterSetLocalPathCmd = &cobra.Command{
 		Use:   "setlocalpath [siapath] [newlocalpath]",
 		Short: "Changes the local path of the file",
@@ -212,7 +269,8 @@ local path where the Sia folder is mounted.`,
 		Use:   "upload [source] [path]",
 		Short: "Upload a file or folder",
 		Long: `Upload a file or folder to [path] on the Sia network. The --data-pieces and --parity-pieces
-flags can be used to set a custom redundancy for the file.`,
+flags can be used to set a custom redundancy for the file. If [source] is '-', the
+data is read from stdin and uploaded as a stream without being stored on disk.`,
 		Run: wrap(renterfilesuploadcmd),
 	}
 
@@ -274,6 +332,37 @@ have a reasonable number (>30) of hosts in your hostdb.`,
 		Run: rentersetallowancecmd,
 	}
 
+	renterRestoreDrillsCmd = &cobra.Command{
+		Use:   "restoredrills",
+		Short: "View the renter's restore drills",
+		Long: `View the settings and the most recent results of the renter's restore drills.
+A restore drill downloads a random file and verifies its checksum to prove that
+it can still be restored from the network.`,
+		Run: wrap(renterrestoredrillscmd),
+	}
+
+	renterRestoreDrillsDisableCmd = &cobra.Command{
+		Use:   "disable",
+		Short: "Disable periodic restore drills",
+		Long:  "Stop the renter from running restore drills periodically.",
+		Run:   wrap(renterrestoredrillsdisablecmd),
+	}
+
+	renterRestoreDrillsEnableCmd = &cobra.Command{
+		Use:   "enable [interval]",
+		Short: "Enable periodic restore drills",
+		Long: `Make the renter run a restore drill periodically. The optional interval
+specifies the time between two drills, e.g. '12h'. It defaults to 24 hours.`,
+		Run: renterrestoredrillsenablecmd,
+	}
+
+	renterRestoreDrillsRunCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Run a restore drill now",
+		Long:  "Run a restore drill right away and wait for its result.",
+		Run:   wrap(renterrestoredrillsruncmd),
+	}
+
 	renterTriggerContractRecoveryScanCmd = &cobra.Command{
 		Use:   "triggerrecoveryscan",
 		Short: "Triggers a recovery scan.",
@@ -288,6 +377,15 @@ have a reasonable number (>30) of hosts in your hostdb.`,
 		Run:   wrap(renteruploadscmd),
 	}
 
+	renterVerifyCmd = &cobra.Command{
+		Use:   "verify [path]",
+		Short: "Verify the integrity of a file",
+		Long: `Download a random sample of a file's pieces from the hosts and verify them against
+the Merkle roots in the file's metadata. Use --sample 0 to verify all pieces of the
+file. Hosts that return bad data are penalized in the hostdb.`,
+		Run: wrap(renterverifycmd),
+	}
+
 	renterWorkersCmd = &cobra.Command{
 		Use:   "workers",
 		Short: "View the Renter's workers",
@@ -326,7 +424,7 @@ have a reasonable number (>30) of hosts in your hostdb.`,
 	renterWorkersReadJobsCmd = &cobra.Command{
 		Use:   "rj",
 		Short: "View the workers' read jobs",
-		Long:  "View detailed information of the workers' read jobs",
+		Long:  "View detailed information of the workers' read jobs and the low priority read jobs of downloads",
 		Run:   wrap(renterworkersrjcmd),
 	}
 
@@ -351,6 +449,154 @@ have a reasonable number (>30) of hosts in your hostdb.`,
 		Run:   wrap(renterworkersupdateregistrycmd),
 	}
 
+	renterDirHealthCmd = &cobra.Command{
+		Use:   "dirhealth [path]",
+		Short: "Display the health of a folder and its subfolders",
+		Long: `Display the aggregated health, redundancy and stuck chunks of a folder's subtree
+and the subtrees of its subfolders, worst health first. To query the root dir
+either '""', '/' or '.' can be supplied.`,
+		Run: renterdirhealthcmd,
+	}
+
+	renterArchiveCmd = &cobra.Command{
+		Use:   "archive [path]",
+		Short: "Move a file or folder to the archive tier",
+		Long: `Move a file or folder to the archive tier. Archived files are no longer repaired,
+which means that their data stays on the hosts that already store it and isn't
+migrated to new hosts when hosts churn. This is useful for rarely accessed
+backups. Use 'siac renter thaw' to actively maintain the files again.`,
+		Run: wrap(renterarchivecmd),
+	}
+
+	renterArchivedCmd = &cobra.Command{
+		Use:   "archived",
+		Short: "List the archived files and folders",
+		Long:  "List the files and folders of the archive tier which are not repaired.",
+		Run:   wrap(renterarchivedcmd),
+	}
+
+	renterThawCmd = &cobra.Command{
+		Use:   "thaw [path]",
+		Short: "Move an archived file or folder back to active maintenance",
+		Long: `Move an archived file or folder back to actively maintained status. The renter
+starts repairing the files right away.`,
+		Run: wrap(renterthawcmd),
+	}
+
+	renterChunkCacheCmd = &cobra.Command{
+		Use:   "chunkcache",
+		Short: "View the renter's chunk cache",
+		Long: `View the size and hit rate of the renter's on-disk cache of downloaded chunks.
+Streams and FUSE mounts read cached chunks from disk instead of downloading them
+from the hosts again.`,
+		Run: wrap(renterchunkcachecmd),
+	}
+
+	renterReadOnlyCmd = &cobra.Command{
+		Use:   "readonly [true/false]",
+		Short: "Enable or disable the renter's read-only mode",
+		Long: `Enable or disable the renter's read-only mode. In read-only mode, no contracts
+are formed or renewed and no files are uploaded or repaired, but files can still
+be downloaded and queried.`,
+		Run: wrap(renterreadonlycmd),
+	}
+
+	renterChunkCacheSetSizeCmd = &cobra.Command{
+		Use:   "setsize [size]",
+		Short: "Set the size limit of the chunk cache",
+		Long: `Set the size limit of the renter's on-disk cache of downloaded chunks, e.g.
+'10GB'. The least recently used chunks are evicted once the cache is full. A
+size of 0 disables the cache. While the cache is enabled, streams download full
+chunks so that they can be cached.`,
+		Run: wrap(renterchunkcachesetsizecmd),
+	}
+
+	renterEventsCmd = &cobra.Command{
+		Use:   "events",
+		Short: "View the renter's recent events",
+		Long: `View the renter's recent events such as completed uploads and downloads,
+repaired chunks, formed contracts and changes of file health. With --follow, new
+events are printed as they occur.`,
+		Run: wrap(rentereventscmd),
+	}
+
+	renterSpendingCmd = &cobra.Command{
+		Use:   "spending",
+		Short: "View the renter's spending by contract",
+		Long: `View the renter's spending within a period by category and contract. By
+default, the spending within the current period is shown. Use --period to view
+the spending within the period that started at a previous height.`,
+		Run: wrap(renterspendingcmd),
+	}
+
+	renterMigrationsCmd = &cobra.Command{
+		Use:   "migrations",
+		Short: "View the contractor's migration plan",
+		Long: `View the contracts whose hosts were marked for replacement in the current period,
+the reason for each replacement and the estimated cost of migrating the data to
+new hosts. Replacements of hosts with a low score are deferred while they don't
+fit within the churn budget, which is derived from the allowance's max period
+churn.`,
+		Run: wrap(rentermigrationscmd),
+	}
+
+	renterHostListsCmd = &cobra.Command{
+		Use:   "hostlists",
+		Short: "View the contractor's host allowlist and denylist",
+		Long: `View the hosts that the contractor is explicitly allowed or not allowed to form
+contracts with. If the allowlist isn't empty, contracts are only formed with
+hosts on the allowlist. Hosts on the denylist are never used.`,
+		Run: wrap(renterhostlistscmd),
+	}
+
+	renterHostListsAllowCmd = &cobra.Command{
+		Use:   "allow [host]",
+		Short: "Add a host to the allowlist",
+		Long: `Add a host to the contractor's allowlist. [host] is either a public key such as
+'ed25519:...', a net address, a hostname or IP, or a subnet such as
+'12.34.56.0/24'. Once the allowlist contains a host, contracts with hosts that
+aren't on the allowlist are no longer renewed.`,
+		Run: wrap(renterhostlistsallowcmd),
+	}
+
+	renterHostListsDenyCmd = &cobra.Command{
+		Use:   "deny [host]",
+		Short: "Add a host to the denylist",
+		Long: `Add a host to the contractor's denylist. [host] is either a public key such as
+'ed25519:...', a net address, a hostname or IP, or a subnet such as
+'12.34.56.0/24'. Contracts with hosts on the denylist are no longer renewed.`,
+		Run: wrap(renterhostlistsdenycmd),
+	}
+
+	renterHostListsRemoveCmd = &cobra.Command{
+		Use:   "remove [host]",
+		Short: "Remove a host from the allowlist and denylist",
+		Long:  "Remove a host from the contractor's allowlist and denylist.",
+		Run:   wrap(renterhostlistsremovecmd),
+	}
+
+	renterPinCmd = &cobra.Command{
+		Use:   "pin [path]",
+		Short: "Prioritize the repair of a folder",
+		Long: `Pin a folder to high repair priority. Files within pinned folders are
+repaired before any other files.`,
+		Run: wrap(renterpincmd),
+	}
+
+	renterPinnedCmd = &cobra.Command{
+		Use:   "pinned",
+		Short: "List the pinned folders",
+		Long:  "List the folders that are repaired before any other folders.",
+		Run:   wrap(renterpinnedcmd),
+	}
+
+	renterUnpinCmd = &cobra.Command{
+		Use:   "unpin [path]",
+		Short: "Stop prioritizing the repair of a folder",
+		Long:  "Unpin a folder that was pinned to high repair priority.",
+		Run:   wrap(renterunpincmd),
+	}
+
 	renterHealthSummaryCmd = &cobra.Command{
 		Use:   "health",
 		Short: "Display a health summary of uploaded files",
@@ -503,6 +749,417 @@ func renterhealthsummarycmd() {
 	renterFileHealthSummary(dirs)
 }
 
+// parseDirSiaPath parses the siapath of a folder relative to the user's home
+// folder. The home folder can be specified as '""', '/' or '.'.
+func parseDirSiaPath(path string) modules.SiaPath {
+	if path == "." || path == "" || path == "/" {
+		return modules.RootSiaPath()
+	}
+	siaPath, err := modules.NewSiaPath(path)
+	if err != nil {
+		die("could not parse siapath:", err)
+	}
+	return siaPath
+}
+
+// renterdirhealthcmd is the handler for the command `siac renter dirhealth`.
+func renterdirhealthcmd(cmd *cobra.Command, args []string) {
+	var path string
+	switch len(args) {
+	case 0:
+		path = "."
+	case 1:
+		path = args[0]
+	default:
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	rdh, err := httpClient.RenterDirsHealthGet(parseDirSiaPath(path), renterDirHealthRecursive, false)
+	if err != nil {
+		die("Could not get folder health:", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Folder\tHealth\tMin Redundancy\tFiles\tSize\tRepair Size\tStuck Chunks\tPinned")
+	for _, dh := range rdh.Directories {
+		siaPath := "/" + dh.SiaPath.String()
+		redundancyStr := fmt.Sprintf("%.2f", dh.AggregateMinRedundancy)
+		if dh.AggregateMinRedundancy == -1 {
+			redundancyStr = "-"
+		}
+		fmt.Fprintf(w, "%v\t%.2f%%\t%v\t%v\t%v\t%v\t%v\t%v\n", siaPath, modules.HealthPercentage(dh.AggregateHealth),
+			redundancyStr, dh.AggregateNumFiles, modules.FilesizeUnits(dh.AggregateSize),
+			modules.FilesizeUnits(dh.AggregateRepairSize), dh.AggregateNumStuckChunks, yesNo(dh.Pinned))
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+}
+
+// renterarchivecmd is the handler for the command `siac renter archive
+// [path]`.
+func renterarchivecmd(path string) {
+	err := httpClient.RenterArchivePost(parseDirSiaPath(path), false)
+	if err != nil {
+		die("Could not archive path:", err)
+	}
+	fmt.Printf("Archived %v. Its files will no longer be repaired.\n", path)
+}
+
+// renterarchivedcmd is the handler for the command `siac renter archived`.
+func renterarchivedcmd() {
+	rag, err := httpClient.RenterArchiveGet(false)
+	if err != nil {
+		die("Could not get archived paths:", err)
+	}
+	if len(rag.ArchivedPaths) == 0 {
+		fmt.Println("No files or folders are archived.")
+		return
+	}
+	for _, siaPath := range rag.ArchivedPaths {
+		fmt.Println("/" + siaPath.String())
+	}
+}
+
+// renterthawcmd is the handler for the command `siac renter thaw [path]`.
+func renterthawcmd(path string) {
+	err := httpClient.RenterThawPost(parseDirSiaPath(path), false)
+	if err != nil {
+		die("Could not thaw path:", err)
+	}
+	fmt.Printf("Thawed %v. Its files will be repaired again.\n", path)
+}
+
+// renterchunkcachecmd is the handler for the command `siac renter chunkcache`.
+func renterchunkcachecmd() {
+	ccs, err := httpClient.RenterChunkCacheGet()
+	if err != nil {
+		die("Could not get chunk cache stats:", err)
+	}
+	if ccs.MaxSize == 0 {
+		fmt.Println("The chunk cache is disabled. Use 'siac renter chunkcache setsize' to enable it.")
+		return
+	}
+	hitRate := 0.0
+	if ccs.Hits+ccs.Misses > 0 {
+		hitRate = 100 * float64(ccs.Hits) / float64(ccs.Hits+ccs.Misses)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Size:\t%v / %v\n", modules.FilesizeUnits(ccs.Size), modules.FilesizeUnits(ccs.MaxSize))
+	fmt.Fprintf(w, "Chunks:\t%v\n", ccs.NumChunks)
+	fmt.Fprintf(w, "Hits:\t%v\n", ccs.Hits)
+	fmt.Fprintf(w, "Misses:\t%v\n", ccs.Misses)
+	fmt.Fprintf(w, "Hit Rate:\t%.2f%%\n", hitRate)
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+}
+
+// renterreadonlycmd is the handler for the command `siac renter readonly`.
+// Enables or disables the renter's read-only mode.
+func renterreadonlycmd(readOnlyStr string) {
+	readOnly, err := strconv.ParseBool(readOnlyStr)
+	if err != nil {
+		die("Could not parse read-only mode:", err)
+	}
+	err = httpClient.RenterReadOnlyPost(readOnly)
+	if err != nil {
+		die("Could not set read-only mode:", err)
+	}
+	if readOnly {
+		fmt.Println("Enabled the read-only mode.")
+		return
+	}
+	fmt.Println("Disabled the read-only mode.")
+}
+
+// renterchunkcachesetsizecmd is the handler for the command `siac renter
+// chunkcache setsize [size]`.
+func renterchunkcachesetsizecmd(sizeStr string) {
+	sizeStr, err := parseFilesize(sizeStr)
+	if err != nil {
+		die("Could not parse size:", err)
+	}
+	size, err := strconv.ParseUint(sizeStr, 10, 64)
+	if err != nil {
+		die("Could not parse size:", err)
+	}
+	err = httpClient.RenterChunkCacheSizePost(size)
+	if err != nil {
+		die("Could not set chunk cache size:", err)
+	}
+	if size == 0 {
+		fmt.Println("Disabled the chunk cache.")
+		return
+	}
+	fmt.Println("Set the chunk cache size to", modules.FilesizeUnits(size))
+}
+
+// rentereventscmd is the handler for the command `siac renter events`.
+func rentereventscmd() {
+	// Print the recent events without waiting and then keep polling for new
+	// events if requested.
+	var since uint64
+	timeout := time.Duration(0)
+	for {
+		reg, err := httpClient.RenterEventsGet(since, timeout, false)
+		if err != nil {
+			die("Could not get renter events:", err)
+		}
+		if since == 0 && len(reg.Events) == 0 && !renterEventsFollow {
+			fmt.Println("No recent events.")
+		}
+		for _, e := range reg.Events {
+			fmt.Println(e.Time.Format("Jan 02 15:04:05"), formatRenterEvent(e))
+		}
+		if !renterEventsFollow {
+			return
+		}
+		since = reg.LastID
+		timeout = 5 * time.Minute
+	}
+}
+
+// renterspendingcmd is the handler for the command `siac renter spending`.
+func renterspendingcmd() {
+	var rs modules.RenterSpending
+	var err error
+	if renterSpendingPeriod == 0 {
+		rs, err = httpClient.RenterCurrentSpendingGet()
+	} else {
+		rs, err = httpClient.RenterSpendingGet(types.BlockHeight(renterSpendingPeriod))
+	}
+	if err != nil {
+		die("Could not get renter spending:", err)
+	}
+	fmt.Printf("Spending from height %v to %v: %v\n", rs.PeriodStart, rs.PeriodEnd, currencyUnits(rs.TotalSpending))
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  Fees:\t%v\n", currencyUnits(rs.Fees))
+	fmt.Fprintf(w, "  Storage:\t%v\n", currencyUnits(rs.StorageSpending))
+	fmt.Fprintf(w, "  Upload:\t%v\n", currencyUnits(rs.UploadSpending))
+	fmt.Fprintf(w, "  Download:\t%v\n", currencyUnits(rs.DownloadSpending))
+	fmt.Fprintf(w, "  Fund Account:\t%v\n", currencyUnits(rs.FundAccountSpending))
+	fmt.Fprintf(w, "  Maintenance:\t%v\n", currencyUnits(rs.MaintenanceSpending.Sum()))
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+	if len(rs.Contracts) == 0 {
+		return
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Contract ID\tHost\tTotal\tFees\tStorage\tUpload\tDownload\tFund Account\tMaintenance")
+	for _, c := range rs.Contracts {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", c.ContractID, c.HostPublicKey,
+			currencyUnits(c.TotalSpending), currencyUnits(c.Fees), currencyUnits(c.StorageSpending),
+			currencyUnits(c.UploadSpending), currencyUnits(c.DownloadSpending),
+			currencyUnits(c.FundAccountSpending), currencyUnits(c.MaintenanceSpending.Sum()))
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+}
+
+// rentermigrationscmd is the handler for the command `siac renter migrations`.
+func rentermigrationscmd() {
+	plan, err := httpClient.RenterContractorMigrationsGet()
+	if err != nil {
+		die("Could not get migration plan:", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Churn This Period:\t%v / %v\n", modules.FilesizeUnits(plan.AggregateCurrentPeriodChurn), modules.FilesizeUnits(plan.MaxPeriodChurn))
+	fmt.Fprintf(w, "Remaining Churn Budget:\t%v\n", churnBudgetUnits(plan.RemainingChurnBudget))
+	fmt.Fprintf(w, "Estimated Cost:\t%v\n", currencyUnits(plan.TotalEstimatedCost))
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+	if len(plan.Migrations) == 0 {
+		fmt.Println("\nNo migrations in the current period.")
+		return
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Host\tHeight\tSize\tEstimated Cost\tStatus\tReason")
+	for _, m := range plan.Migrations {
+		status := "churned"
+		if m.Deferred {
+			status = "deferred"
+		}
+		host := string(m.NetAddress)
+		if host == "" {
+			host = m.HostPublicKey.String()
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", host, m.BlockHeight, modules.FilesizeUnits(m.Size),
+			currencyUnits(m.EstimatedCost), status, m.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+}
+
+// churnBudgetUnits converts a churn budget, which may be negative, into a
+// human readable size.
+func churnBudgetUnits(budget int64) string {
+	if budget < 0 {
+		return "-" + modules.FilesizeUnits(uint64(-budget))
+	}
+	return modules.FilesizeUnits(uint64(budget))
+}
+
+// renterhostlistscmd is the handler for the command `siac renter hostlists`.
+func renterhostlistscmd() {
+	lists, err := httpClient.RenterContractorHostListsGet()
+	if err != nil {
+		die("Could not get host lists:", err)
+	}
+	printList := func(name string, hl modules.HostList) {
+		if len(hl.PublicKeys) == 0 && len(hl.NetAddresses) == 0 {
+			fmt.Printf("%v: empty\n", name)
+			return
+		}
+		fmt.Printf("%v:\n", name)
+		for _, pk := range hl.PublicKeys {
+			fmt.Println("  ", pk)
+		}
+		for _, addr := range hl.NetAddresses {
+			fmt.Println("  ", addr)
+		}
+	}
+	printList("Allowlist", lists.Allowlist)
+	printList("Denylist", lists.Denylist)
+}
+
+// renterhostlistsallowcmd is the handler for the command `siac renter
+// hostlists allow [host]`.
+func renterhostlistsallowcmd(host string) {
+	updateHostLists(func(lists *modules.ContractorHostLists) {
+		addToHostList(&lists.Allowlist, host)
+	})
+	fmt.Println("Added", host, "to the allowlist.")
+}
+
+// renterhostlistsdenycmd is the handler for the command `siac renter hostlists
+// deny [host]`.
+func renterhostlistsdenycmd(host string) {
+	updateHostLists(func(lists *modules.ContractorHostLists) {
+		addToHostList(&lists.Denylist, host)
+	})
+	fmt.Println("Added", host, "to the denylist.")
+}
+
+// renterhostlistsremovecmd is the handler for the command `siac renter
+// hostlists remove [host]`.
+func renterhostlistsremovecmd(host string) {
+	updateHostLists(func(lists *modules.ContractorHostLists) {
+		removeFromHostList(&lists.Allowlist, host)
+		removeFromHostList(&lists.Denylist, host)
+	})
+	fmt.Println("Removed", host, "from the host lists.")
+}
+
+// updateHostLists fetches the contractor's host lists, applies the update and
+// submits the result.
+func updateHostLists(update func(*modules.ContractorHostLists)) {
+	lists, err := httpClient.RenterContractorHostListsGet()
+	if err != nil {
+		die("Could not get host lists:", err)
+	}
+	update(&lists)
+	err = httpClient.RenterContractorHostListsPost(lists)
+	if err != nil {
+		die("Could not set host lists:", err)
+	}
+}
+
+// addToHostList adds a host, which is either a public key or a net address, to
+// a host list.
+func addToHostList(hl *modules.HostList, host string) {
+	removeFromHostList(hl, host)
+	if strings.HasPrefix(host, "ed25519:") {
+		var pk types.SiaPublicKey
+		if err := pk.LoadString(host); err != nil {
+			die("Could not parse public key:", err)
+		}
+		hl.PublicKeys = append(hl.PublicKeys, pk)
+		return
+	}
+	hl.NetAddresses = append(hl.NetAddresses, host)
+}
+
+// removeFromHostList removes a host, which is either a public key or a net
+// address, from a host list.
+func removeFromHostList(hl *modules.HostList, host string) {
+	pks := hl.PublicKeys[:0]
+	for _, pk := range hl.PublicKeys {
+		if pk.String() != host {
+			pks = append(pks, pk)
+		}
+	}
+	hl.PublicKeys = pks
+	addrs := hl.NetAddresses[:0]
+	for _, addr := range hl.NetAddresses {
+		if addr != host {
+			addrs = append(addrs, addr)
+		}
+	}
+	hl.NetAddresses = addrs
+}
+
+// formatRenterEvent returns a description of a renter event.
+func formatRenterEvent(e modules.RenterEvent) string {
+	switch e.Type {
+	case modules.RenterEventChunkRepaired:
+		return fmt.Sprintf("repaired chunk %v of /%v", e.ChunkIndex, e.SiaPath)
+	case modules.RenterEventContractFormed:
+		return fmt.Sprintf("formed contract %v with host %v", e.ContractID, e.HostPublicKey)
+	case modules.RenterEventDownloadComplete:
+		if e.Error != "" {
+			return fmt.Sprintf("download of /%v failed: %v", e.SiaPath, e.Error)
+		}
+		return fmt.Sprintf("downloaded /%v", e.SiaPath)
+	case modules.RenterEventFileHealthChanged:
+		return fmt.Sprintf("health of /%v changed from %.2f to %.2f", e.SiaPath, e.PreviousHealth, e.Health)
+	case modules.RenterEventUploadComplete:
+		return fmt.Sprintf("uploaded /%v", e.SiaPath)
+	default:
+		return string(e.Type)
+	}
+}
+
+// renterpincmd is the handler for the command `siac renter pin [path]`.
+func renterpincmd(path string) {
+	err := httpClient.RenterDirPinnedPost(parseDirSiaPath(path), true, false)
+	if err != nil {
+		die("Could not pin folder:", err)
+	}
+	fmt.Printf("Pinned %v. Its files will be repaired before any other files.\n", path)
+}
+
+// renterpinnedcmd is the handler for the command `siac renter pinned`.
+func renterpinnedcmd() {
+	rdp, err := httpClient.RenterDirsPinnedGet(false)
+	if err != nil {
+		die("Could not get pinned folders:", err)
+	}
+	if len(rdp.PinnedDirs) == 0 {
+		fmt.Println("No folders are pinned.")
+		return
+	}
+	for _, siaPath := range rdp.PinnedDirs {
+		fmt.Println("/" + siaPath.String())
+	}
+}
+
+// renterunpincmd is the handler for the command `siac renter unpin [path]`.
+func renterunpincmd(path string) {
+	err := httpClient.RenterDirPinnedPost(parseDirSiaPath(path), false, false)
+	if err != nil {
+		die("Could not unpin folder:", err)
+	}
+	fmt.Printf("Unpinned %v.\n", path)
+}
+
 // renteruploadscmd is the handler for the command `siac renter uploads`.
 // Lists files currently uploading.
 func renteruploadscmd() {
@@ -592,6 +1249,14 @@ func renterallowancecmd() {
 		fmt.Printf("Warning: ignoring exchange rate - %s\n", err)
 	}
 
+	preferredLatency, preferredRegions := "none", "none"
+	if allowance.PreferredLatency > 0 {
+		preferredLatency = allowance.PreferredLatency.String()
+	}
+	if len(allowance.PreferredRegions) > 0 {
+		preferredRegions = strings.Join(allowance.PreferredRegions, ", ")
+	}
+
 	fmt.Printf(`Allowance:
   Amount:               %v
   Period:               %v blocks
@@ -611,6 +1276,11 @@ Price Protections:
   MaxSectorAccessPrice:      %v per million accesses
   MaxStoragePrice:           %v per TB per Month
   MaxUploadBandwidthPrice:   %v per TB
+
+Host Selection:
+  Preferred Latency:    %v
+  Preferred Regions:    %v
+  Min Subnets:          %v
 `, currencyUnitsWithExchangeRate(allowance.Funds, rate), allowance.Period, allowance.RenewWindow,
 		allowance.Hosts,
 		modules.FilesizeUnits(allowance.ExpectedStorage),
@@ -622,7 +1292,8 @@ Price Protections:
 		currencyUnits(allowance.MaxDownloadBandwidthPrice.Mul(modules.BytesPerTerabyte)),
 		currencyUnits(allowance.MaxSectorAccessPrice.Mul64(1e6)),
 		currencyUnits(allowance.MaxStoragePrice.Mul(modules.BlockBytesPerMonthTerabyte)),
-		currencyUnits(allowance.MaxUploadBandwidthPrice.Mul(modules.BytesPerTerabyte)))
+		currencyUnits(allowance.MaxUploadBandwidthPrice.Mul(modules.BytesPerTerabyte)),
+		preferredLatency, preferredRegions, allowance.MinSubnets)
 
 	// Show detailed current Period spending metrics
 	renterallowancespending(rg)
@@ -661,7 +1332,7 @@ to be accessible after a short period of time.`)
 
 // rentersetallowancecmd is the handler for `siac renter setallowance`.
 // set the allowance or modify individual allowance fields.
-func rentersetallowancecmd(_ *cobra.Command, _ []string) {
+func rentersetallowancecmd(cmd *cobra.Command, _ []string) {
 	// Get the current period setting.
 	rg, err := httpClient.RenterGet()
 	if err != nil {
@@ -862,6 +1533,35 @@ func rentersetallowancecmd(_ *cobra.Command, _ []string) {
 		req = req.WithMaxUploadBandwidthPrice(price)
 		changedFields++
 	}
+	// parse preferredlatency
+	if allowancePreferredLatency != "" {
+		latency, err := time.ParseDuration(allowancePreferredLatency)
+		if err != nil {
+			die("Could not parse preferred latency:", err)
+		}
+		req = req.WithPreferredLatency(latency)
+		changedFields++
+	}
+	// parse preferredregions
+	if cmd.Flags().Changed("preferred-regions") {
+		var regions []string
+		for _, region := range strings.Split(allowancePreferredRegions, ",") {
+			if region = strings.TrimSpace(region); region != "" {
+				regions = append(regions, region)
+			}
+		}
+		req = req.WithPreferredRegions(regions)
+		changedFields++
+	}
+	// parse minsubnets
+	if allowanceMinSubnets != "" {
+		minSubnets, err := strconv.ParseUint(allowanceMinSubnets, 10, 64)
+		if err != nil {
+			die("Could not parse min subnets:", err)
+		}
+		req = req.WithMinSubnets(minSubnets)
+		changedFields++
+	}
 
 	// check if any fields were updated.
 	if changedFields == 0 {
@@ -1397,6 +2097,109 @@ func renterbackuplistcmd() {
 	}
 }
 
+// renterbackupprunecmd is the handler for the command `siac renter
+// prunebackup`.
+func renterbackupprunecmd(name string) {
+	err := httpClient.RenterBackupsPrunePost(name)
+	if err != nil {
+		die("Failed to prune backup", err)
+	}
+	fmt.Println("Backup pruned.")
+}
+
+// renterbackupschedulecmd is the handler for the command `siac renter
+// backupschedule`. It prints the settings of the renter's scheduled backups
+// and the scheduled backups stored on hosts.
+func renterbackupschedulecmd() {
+	bsg, err := httpClient.RenterBackupScheduleGet()
+	if err != nil {
+		die("Could not get backup schedule:", err)
+	}
+	status := "disabled"
+	if bsg.Enabled {
+		status = "enabled"
+	}
+	fmt.Printf("Scheduled Backups: %v\n", status)
+	fmt.Printf("  Interval:     %v\n", bsg.Interval)
+	fmt.Printf("  Generations:  %v\n", bsg.Generations)
+	fmt.Printf("  In Progress:  %v\n", yesNo(bsg.InProgress))
+	if bsg.LastBackup.IsZero() {
+		fmt.Println("  Last Backup:  never")
+	} else {
+		fmt.Printf("  Last Backup:  %v\n", bsg.LastBackup.Format(time.RFC822))
+	}
+	if bsg.LastError != "" {
+		fmt.Printf("  Last Error:   %v\n", bsg.LastError)
+	}
+	if len(bsg.Backups) == 0 {
+		return
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Name\tCreation Date\tUpload Progress")
+	for _, ub := range bsg.Backups {
+		date := time.Unix(int64(ub.CreationDate), 0)
+		fmt.Fprintf(w, "  %v\t%v\t%v\n", ub.Name, date.Format(time.ANSIC), ub.UploadProgress)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+}
+
+// renterbackupscheduledisablecmd is the handler for the command `siac renter
+// backupschedule disable`.
+func renterbackupscheduledisablecmd() {
+	bsg, err := httpClient.RenterBackupScheduleGet()
+	if err != nil {
+		die("Could not get backup schedule:", err)
+	}
+	err = httpClient.RenterBackupSchedulePost(false, bsg.Interval, bsg.Generations)
+	if err != nil {
+		die("Could not disable scheduled backups:", err)
+	}
+	fmt.Println("Scheduled backups disabled.")
+}
+
+// renterbackupscheduleenablecmd is the handler for the command `siac renter
+// backupschedule enable [interval] [generations]`.
+func renterbackupscheduleenablecmd(cmd *cobra.Command, args []string) {
+	var interval time.Duration
+	var generations uint64
+	var err error
+	switch len(args) {
+	case 2:
+		generations, err = strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			die("Could not parse generations:", err)
+		}
+		fallthrough
+	case 1:
+		interval, err = time.ParseDuration(args[0])
+		if err != nil {
+			die("Could not parse interval:", err)
+		}
+	case 0:
+	default:
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	err = httpClient.RenterBackupSchedulePost(true, interval, generations)
+	if err != nil {
+		die("Could not enable scheduled backups:", err)
+	}
+	fmt.Println("Scheduled backups enabled.")
+}
+
+// renterbackupscheduleruncmd is the handler for the command `siac renter
+// backupschedule run`.
+func renterbackupscheduleruncmd() {
+	ub, err := httpClient.RenterBackupScheduleRunPost()
+	if err != nil {
+		die("Could not create scheduled backup:", err)
+	}
+	fmt.Printf("Backup '%v' initiated. Monitor progress with the 'backupschedule' command.\n", ub.Name)
+}
+
 // rentercontractscmd is the handler for the command `siac renter contracts`.
 // It lists the Renter's contracts.
 func rentercontractscmd() {
@@ -1582,12 +2385,26 @@ func renterdownloadcancelcmd(cancelID modules.DownloadID) {
 // renterfilesdeletecmd is the handler for the command `siac renter delete [path]`.
 // Removes the specified path from the Sia network.
 func renterfilesdeletecmd(cmd *cobra.Command, paths []string) {
+	if renterDeleteAsync && renterDeleteDryRun {
+		die("--async and --dry-run can't be combined")
+	}
+	if renterDeleteRecursive && (renterDeleteAsync || renterDeleteDryRun) {
+		die("--recursive can't be combined with --async or --dry-run")
+	}
+	if renterDeleteRecursive {
+		renterfilesbatchdelete(paths)
+		return
+	}
 	for _, path := range paths {
 		// Parse SiaPath.
 		siaPath, err := modules.NewSiaPath(path)
 		if err != nil {
 			die("Couldn't parse SiaPath:", err)
 		}
+		if renterDeleteDryRun {
+			renterfilesdeletedryrun(path, siaPath)
+			continue
+		}
 
 		// Try to delete file.
 		//
@@ -1609,7 +2426,9 @@ func renterfilesdeletecmd(cmd *cobra.Command, paths []string) {
 		}
 		// Try to delete dir.
 		var errDir error
-		if renterDeleteRoot {
+		if renterDeleteAsync {
+			errDir = renterdirdeleteasync(path, siaPath)
+		} else if renterDeleteRoot {
 			errDir = httpClient.RenterDirDeleteRootPost(siaPath)
 		} else {
 			errDir = httpClient.RenterDirDeletePost(siaPath)
@@ -1627,6 +2446,92 @@ func renterfilesdeletecmd(cmd *cobra.Command, paths []string) {
 	return
 }
 
+// renterfilesbatchdelete deletes all paths with a single request and prints
+// the result for every deleted file.
+func renterfilesbatchdelete(paths []string) {
+	var siaPaths []modules.SiaPath
+	for _, path := range paths {
+		siaPath, err := modules.NewSiaPath(path)
+		if err != nil {
+			die("Couldn't parse SiaPath:", err)
+		}
+		siaPaths = append(siaPaths, siaPath)
+	}
+	rdp, err := httpClient.RenterDeletePost(siaPaths, true, renterDeleteRoot)
+	if err != nil {
+		die("Failed to delete files:", err)
+	}
+	var failed int
+	for _, res := range rdp.Results {
+		if res.Error != "" {
+			fmt.Printf("Failed to delete '%v': %v\n", res.SiaPath, res.Error)
+			failed++
+			continue
+		}
+		fmt.Printf("Deleted '%v'\n", res.SiaPath)
+	}
+	if failed > 0 {
+		die(fmt.Sprintf("%v of %v deletions failed", failed, len(rdp.Results)))
+	}
+}
+
+// renterfilesdeletedryrun prints what deleting the file or folder at the given
+// path would remove without deleting anything.
+func renterfilesdeletedryrun(path string, siaPath modules.SiaPath) {
+	var rf api.RenterFile
+	var err error
+	if renterDeleteRoot {
+		rf, err = httpClient.RenterFileRootGet(siaPath)
+	} else {
+		rf, err = httpClient.RenterFileGet(siaPath)
+	}
+	if err == nil {
+		fmt.Printf("Would delete file '%v' (%v)\n", path, modules.FilesizeUnits(rf.File.Filesize))
+		return
+	} else if !strings.Contains(err.Error(), filesystem.ErrNotExist.Error()) {
+		die(fmt.Sprintf("Failed to get file %v: %v", path, err))
+	}
+	dds, err := httpClient.RenterDirDeleteDryRunPost(siaPath, renterDeleteRoot)
+	if err != nil && strings.Contains(err.Error(), filesystem.ErrNotExist.Error()) {
+		die(fmt.Sprintf("Unknown path '%v'", path))
+	} else if err != nil {
+		die(fmt.Sprintf("Failed to get directory %v: %v", path, err))
+	}
+	fmt.Printf("Would delete directory '%v' containing %v files (%v)\n", path, dds.NumFiles, modules.FilesizeUnits(dds.TotalBytes))
+}
+
+// renterdirdeleteasync deletes a directory in the background and polls the
+// progress of the deletion until it is done.
+func renterdirdeleteasync(path string, siaPath modules.SiaPath) error {
+	dds, err := httpClient.RenterDirDeleteAsyncPost(siaPath, renterDeleteRoot)
+	if err != nil {
+		return err
+	}
+	for {
+		rdd, err := httpClient.RenterDirDeletionsGet(true)
+		if err != nil {
+			die("Couldn't query the deletion progress:", err)
+		}
+		for _, d := range rdd.Deletions {
+			if d.ID == dds.ID {
+				dds = d
+				break
+			}
+		}
+		fmt.Printf("\rDeleting '%v': %v of %v files (%v of %v)", path, dds.FilesDeleted, dds.NumFiles,
+			modules.FilesizeUnits(dds.BytesDeleted), modules.FilesizeUnits(dds.TotalBytes))
+		if dds.Completed {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	fmt.Println()
+	if dds.Error != "" {
+		die(fmt.Sprintf("Failed to delete directory %v: %v", path, dds.Error))
+	}
+	return nil
+}
+
 // renterfilesdownload is the handler for the command `siac renter download
 // [path] [destination]`. It determines whether a file or a folder is downloaded
 // and calls the corresponding sub-handler.
@@ -1662,6 +2567,86 @@ func renterfilesdownloadcmd(path, destination string) {
 
 // rentertriggercontractrecoveryrescancmd starts a new scan for recoverable
 // contracts on the blockchain.
+// renterrestoredrillscmd is the handler for the command `siac renter
+// restoredrills`. It prints the settings and results of the renter's restore
+// drills.
+func renterrestoredrillscmd() {
+	rds, err := httpClient.RenterRestoreDrillsGet()
+	if err != nil {
+		die("Could not get restore drills:", err)
+	}
+	status := "disabled"
+	if rds.Enabled {
+		status = "enabled"
+	}
+	fmt.Printf("Restore Drills: %v\n", status)
+	fmt.Printf("  Interval:     %v\n", rds.Interval)
+	fmt.Printf("  In Progress:  %v\n", yesNo(rds.InProgress))
+	if rds.LastDrill.IsZero() {
+		fmt.Println("  Last Drill:   never")
+		return
+	}
+	fmt.Printf("  Last Drill:   %v\n", rds.LastDrill.Format(time.RFC822))
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Time\tSiaPath\tSize\tDuration\tResult")
+	for i := len(rds.Results) - 1; i >= 0; i-- {
+		res := rds.Results[i]
+		fmt.Fprintf(w, "  %v\t%v\t%v\t%v\t%v\n", res.Timestamp.Format(time.RFC822), res.SiaPath, modules.FilesizeUnits(res.Filesize), res.Duration.Round(time.Second), restoreDrillResultString(res))
+	}
+	if err := w.Flush(); err != nil {
+		die("Failed to flush writer:", err)
+	}
+}
+
+// renterrestoredrillsdisablecmd is the handler for the command `siac renter
+// restoredrills disable`.
+func renterrestoredrillsdisablecmd() {
+	rds, err := httpClient.RenterRestoreDrillsGet()
+	if err != nil {
+		die("Could not get restore drills:", err)
+	}
+	err = httpClient.RenterRestoreDrillsPost(false, rds.Interval)
+	if err != nil {
+		die("Could not disable restore drills:", err)
+	}
+	fmt.Println("Restore drills disabled.")
+}
+
+// renterrestoredrillsenablecmd is the handler for the command `siac renter
+// restoredrills enable [interval]`.
+func renterrestoredrillsenablecmd(cmd *cobra.Command, args []string) {
+	var interval time.Duration
+	switch len(args) {
+	case 0:
+	case 1:
+		var err error
+		interval, err = time.ParseDuration(args[0])
+		if err != nil {
+			die("Could not parse interval:", err)
+		}
+	default:
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	err := httpClient.RenterRestoreDrillsPost(true, interval)
+	if err != nil {
+		die("Could not enable restore drills:", err)
+	}
+	fmt.Println("Restore drills enabled.")
+}
+
+// renterrestoredrillsruncmd is the handler for the command `siac renter
+// restoredrills run`.
+func renterrestoredrillsruncmd() {
+	fmt.Println("Running restore drill...")
+	res, err := httpClient.RenterRestoreDrillsRunPost()
+	if err != nil {
+		die("Could not run restore drill:", err)
+	}
+	fmt.Printf("Restored '%v' (%v) in %v: %v\n", res.SiaPath, modules.FilesizeUnits(res.Filesize), res.Duration.Round(time.Second), restoreDrillResultString(res))
+}
+
 func rentertriggercontractrecoveryrescancmd() {
 	crpg, err := httpClient.RenterContractRecoveryProgressGet()
 	if err != nil {
@@ -1836,6 +2821,85 @@ func renterfileslistcmd(cmd *cobra.Command, args []string) {
 	}
 }
 
+// renterverifycmd is the handler for the command `siac renter verify [path]`.
+// It verifies a sample of the file's pieces and lists the hosts that returned
+// bad data or were unavailable.
+func renterverifycmd(path string) {
+	siaPath, err := modules.NewSiaPath(path)
+	if err != nil {
+		die("Couldn't parse SiaPath:", err)
+	}
+	fv, err := httpClient.RenterVerifyPost(siaPath, renterVerifySample, renterVerifyRoot)
+	if err != nil {
+		die("Could not verify file:", err)
+	}
+	fmt.Printf("Verified %v pieces of '%v' in %v\n", fv.PiecesChecked, path, fv.Duration.Round(time.Millisecond))
+	fmt.Printf("  Good:        %v\n", fv.PiecesVerified)
+	fmt.Printf("  Bad:         %v\n", fv.PiecesBad)
+	fmt.Printf("  Unavailable: %v\n", fv.PiecesUnavailable)
+	if fv.PiecesBad == 0 && fv.PiecesUnavailable == 0 {
+		return
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Host\tChecked\tBad\tUnavailable\tError")
+	for _, hv := range fv.Hosts {
+		if hv.PiecesBad == 0 && hv.PiecesUnavailable == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", hv.HostPublicKey, hv.PiecesChecked, hv.PiecesBad, hv.PiecesUnavailable, hv.Error)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer:", err)
+	}
+	if fv.PiecesBad > 0 {
+		die("Some hosts returned bad data")
+	}
+}
+
+// renterfilesreencodecmd is the handler for the command `siac renter reencode
+// [path] [datapieces] [paritypieces]`. It starts re-encoding a file and polls
+// the progress until the re-encode is done.
+func renterfilesreencodecmd(path, dataPieces, parityPieces string) {
+	siaPath, err := modules.NewSiaPath(path)
+	if err != nil {
+		die("Couldn't parse SiaPath:", err)
+	}
+	numDataPieces, numParityPieces, err := api.ParseDataAndParityPieces(dataPieces, parityPieces)
+	if err != nil {
+		die("Couldn't parse erasure code parameters:", err)
+	}
+	frs, err := httpClient.RenterReencodePost(siaPath, uint64(numDataPieces), uint64(numParityPieces), renterReencodeRoot)
+	if err != nil {
+		die("Could not re-encode file:", err)
+	}
+	fmt.Printf("Re-encoding '%v' from %v-of-%v to %v-of-%v\n", path, frs.OldDataPieces, frs.OldDataPieces+frs.OldParityPieces,
+		frs.NewDataPieces, frs.NewDataPieces+frs.NewParityPieces)
+	for {
+		rfr, err := httpClient.RenterReencodesGet(true)
+		if err != nil {
+			die("Couldn't query the re-encode progress:", err)
+		}
+		for _, re := range rfr.Reencodes {
+			if re.ID == frs.ID {
+				frs = re
+				break
+			}
+		}
+		fmt.Printf("\r%v of %v chunks (%v of %v)", frs.ChunksReencoded, frs.NumChunks,
+			modules.FilesizeUnits(frs.BytesReencoded), modules.FilesizeUnits(frs.Filesize))
+		if frs.Completed {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	fmt.Println()
+	if frs.Error != "" {
+		die(fmt.Sprintf("Failed to re-encode file %v: %v", path, frs.Error))
+	}
+	fmt.Printf("Re-encoded %v\n", path)
+}
+
 // renterfilesrenamecmd is the handler for the command `siac renter rename [path] [newpath]`.
 // Renames a file on the Sia network.
 func renterfilesrenamecmd(path, newpath string) {
@@ -1893,10 +2957,6 @@ func renterfusecmd() {
 
 // renterfusemountcmd is the handler for the command `siac renter fuse mount [path] [siapath]`.
 func renterfusemountcmd(path, siaPathStr string) {
-	// TODO: Once read-write is supported on the backend, the 'true' flag can be
-	// set to 'false' - siac will support mounting read-write by default. Need
-	// to update the help string of the command to indicate that mounting will
-	// mount in read-write mode.
 	path = abs(path)
 	var siaPath modules.SiaPath
 	var err error
@@ -1909,7 +2969,7 @@ func renterfusemountcmd(path, siaPathStr string) {
 		}
 	}
 	opts := modules.MountOptions{
-		ReadOnly:   true,
+		ReadOnly:   renterFuseMountReadOnly,
 		AllowOther: renterFuseMountAllowOther,
 	}
 	err = httpClient.RenterFuseMount(path, siaPath, opts)
@@ -1946,6 +3006,25 @@ func rentersetlocalpathcmd(siapath, newlocalpath string) {
 	fmt.Printf("Updated %s localpath to %s\n", siapath, newlocalpath)
 }
 
+// renterloadsharedfilecmd is the handler for the command `siac renter
+// loadsharedfile`. Loads a shared file into the renter.
+func renterloadsharedfilecmd(source, path string) {
+	siaPath, err := modules.NewSiaPath(path)
+	if err != nil {
+		die("Couldn't parse SiaPath:", err)
+	}
+	password, err := passwordPrompt("Shared file password: ")
+	if err != nil {
+		die("Reading password failed:", err)
+	}
+	source = abs(source)
+	err = httpClient.RenterLoadSharedFilePost(source, siaPath, password, false)
+	if err != nil {
+		die("Could not load shared file:", err)
+	}
+	fmt.Printf("Loaded shared file %v to %v\n", source, siaPath)
+}
+
 // renterfilesunstuckcmd is the handler for the command `siac renter
 // unstuckall`. Sets all files to unstuck.
 func renterfilesunstuckcmd() {
@@ -2006,17 +3085,31 @@ func renterfilesunstuckcmd() {
 // If [source] is a directory, all files inside it will be uploaded and named
 // relative to [path].
 func renterfilesuploadcmd(source, path string) {
-	stat, err := os.Stat(source)
-	if err != nil {
-		die("Could not stat file or folder:", err)
-	}
-
 	// Check for and parse any redundancy settings
 	numDataPieces, numParityPieces, err := api.ParseDataAndParityPieces(dataPieces, parityPieces)
 	if err != nil {
 		die("Could not parse data and parity pieces:", err)
 	}
 
+	// Stream the data from stdin without staging it on disk.
+	if source == "-" {
+		siaPath, err := modules.NewSiaPath(path)
+		if err != nil {
+			die("Couldn't parse SiaPath:", err)
+		}
+		err = httpClient.RenterUploadStreamPost(os.Stdin, siaPath, uint64(numDataPieces), uint64(numParityPieces), false)
+		if err != nil {
+			die("Could not upload stream:", err)
+		}
+		fmt.Printf("Uploaded stdin as '%s'.\n", path)
+		return
+	}
+
+	stat, err := os.Stat(source)
+	if err != nil {
+		die("Could not stat file or folder:", err)
+	}
+
 	if stat.IsDir() {
 		// folder
 		var files []string
@@ -2391,7 +3484,8 @@ func renterworkersptcmd() {
 }
 
 // renterworkersrjcmd is the handler for the command `siac renter workers rj`.
-// It lists the status of the read job queue for every worker.
+// It lists the status of the read job queue and the low priority read job
+// queue used by downloads for every worker.
 func renterworkersrjcmd() {
 	rw, err := httpClient.RenterWorkersGet()
 	if err != nil {
@@ -2413,26 +3507,18 @@ func renterworkersrjcmd() {
 
 	// print header
 	hostInfo := "Host PubKey"
-	queueInfo := "\tJobs\tAvgJobTime64k (ms)\tAvgJobTime1m (ms)\tAvgJobTime4m (ms)\tConsecFail\tErrorAt\tError"
+	queueInfo := "\tJobs\tAvgJobTime64k (ms)\tAvgJobTime1m (ms)\tAvgJobTime4m (ms)\tRecentJobTimes (ms)\tConsecFail\tErrorAt\tError"
 	header := hostInfo + queueInfo
 	fmt.Fprintln(w, "\nWorker Read Jobs  \n\n"+header)
 
 	// print rows
 	for _, worker := range rw.Workers {
-		rjs := worker.ReadJobsStatus
-
-		// Host Info
-		fmt.Fprintf(w, "%v", worker.HostPubKey.String())
+		writeWorkerReadJobsInfo(w, worker.HostPubKey, worker.ReadJobsStatus)
+	}
 
-		// ReadJobs Info
-		fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
-			rjs.JobQueueSize,
-			rjs.AvgJobTime64k,
-			rjs.AvgJobTime1m,
-			rjs.AvgJobTime4m,
-			rjs.ConsecutiveFailures,
-			sanitizeTime(rjs.RecentErrTime, rjs.RecentErr != ""),
-			sanitizeErr(rjs.RecentErr))
+	fmt.Fprintln(w, "\nWorker Low Priority Read Jobs  \n\n"+header)
+	for _, worker := range rw.Workers {
+		writeWorkerReadJobsInfo(w, worker.HostPubKey, worker.LowPrioReadJobsStatus)
 	}
 }
 
@@ -2459,7 +3545,7 @@ func renterworkershsjcmd() {
 
 	// print header
 	hostInfo := "Host PubKey"
-	queueInfo := "\tJobs\tAvgJobTime (ms)\tConsecFail\tErrorAt\tError"
+	queueInfo := "\tJobs\tAvgJobTime (ms)\tRecentJobTimes (ms)\tConsecFail\tErrorAt\tError"
 	header := hostInfo + queueInfo
 	fmt.Fprintln(w, "\nWorker Has Sector Jobs  \n\n"+header)
 
@@ -2471,9 +3557,10 @@ func renterworkershsjcmd() {
 		fmt.Fprintf(w, "%v", worker.HostPubKey.String())
 
 		// HasSector Jobs Info
-		fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\n",
+		fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\t%v\n",
 			hsjs.JobQueueSize,
 			hsjs.AvgJobTime,
+			sanitizeJobTimes(hsjs.RecentJobTimes),
 			hsjs.ConsecutiveFailures,
 			sanitizeTime(hsjs.RecentErrTime, hsjs.RecentErr != ""),
 			sanitizeErr(hsjs.RecentErr))
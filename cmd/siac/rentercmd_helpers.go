@@ -677,7 +677,7 @@ func writeWorkerReadUpdateRegistryInfo(read bool, w *tabwriter.Writer, rw module
 
 	// print header
 	hostInfo := "Host PubKey"
-	info := "\tOn Cooldown\tCooldown Time\tLast Error\tLast Error Time\tQueue"
+	info := "\tOn Cooldown\tCooldown Time\tLast Error\tLast Error Time\tQueue\tRecent Job Times (ms)"
 	header := hostInfo + info
 	if read {
 		fmt.Fprintln(w, "\nWorker ReadRegistry Detail  \n\n"+header)
@@ -693,20 +693,53 @@ func writeWorkerReadUpdateRegistryInfo(read bool, w *tabwriter.Writer, rw module
 		// Qeue Info
 		if read {
 			status := worker.ReadRegistryJobsStatus
-			fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\n",
+			fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\t%v\n",
 				status.OnCooldown,
 				absDuration(time.Until(status.OnCooldownUntil)),
 				sanitizeErr(status.RecentErr),
 				status.RecentErrTime,
-				status.JobQueueSize)
+				status.JobQueueSize,
+				sanitizeJobTimes(status.RecentJobTimes))
 		} else {
 			status := worker.UpdateRegistryJobsStatus
-			fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\n",
+			fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\t%v\n",
 				status.OnCooldown,
 				absDuration(time.Until(status.OnCooldownUntil)),
 				sanitizeErr(status.RecentErr),
 				status.RecentErrTime,
-				status.JobQueueSize)
+				status.JobQueueSize,
+				sanitizeJobTimes(status.RecentJobTimes))
 		}
 	}
 }
+
+// writeWorkerReadJobsInfo is a helper function for writing the status of a
+// worker's read job queue to the tabwriter.
+func writeWorkerReadJobsInfo(w *tabwriter.Writer, hostPubKey types.SiaPublicKey, rjs modules.WorkerReadJobsStatus) {
+	// Host Info
+	fmt.Fprintf(w, "%v", hostPubKey.String())
+
+	// ReadJobs Info
+	fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+		rjs.JobQueueSize,
+		rjs.AvgJobTime64k,
+		rjs.AvgJobTime1m,
+		rjs.AvgJobTime4m,
+		sanitizeJobTimes(rjs.RecentJobTimes),
+		rjs.ConsecutiveFailures,
+		sanitizeTime(rjs.RecentErrTime, rjs.RecentErr != ""),
+		sanitizeErr(rjs.RecentErr))
+}
+
+// restoreDrillResultString returns a human readable summary of a restore drill
+// result.
+func restoreDrillResultString(res modules.RestoreDrillResult) string {
+	switch {
+	case !res.Success:
+		return "failed: " + res.Error
+	case res.Baseline:
+		return "restored, checksum recorded"
+	default:
+		return "restored and verified"
+	}
+}
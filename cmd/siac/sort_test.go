@@ -4,6 +4,7 @@ import (
 	"sort"
 	"testing"
 
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/node/api"
 	"go.sia.tech/siad/types"
 )
@@ -31,3 +32,25 @@ func TestSortByValue(t *testing.T) {
 		}
 	}
 }
+
+// TestSortDoctorFindings tests that doctorFindings sorts findings by severity
+// while keeping the order of the checks.
+func TestSortDoctorFindings(t *testing.T) {
+	findings := doctorFindings{
+		{check: "a", severity: modules.SeverityWarning},
+		{check: "b", severity: modules.SeverityCritical},
+		{check: "c", severity: modules.SeverityWarning},
+		{check: "d", severity: modules.SeverityError},
+		{check: "e", severity: modules.SeverityCritical},
+	}
+	sort.Stable(findings)
+
+	expected := "bedac"
+	var got string
+	for _, f := range findings {
+		got += f.check
+	}
+	if got != expected {
+		t.Fatalf("expected findings in order %v but got %v", expected, got)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
 	"os"
 	"strconv"
 	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -34,6 +36,29 @@ var (
 		Run:   wrap(walletaddresscmd),
 	}
 
+	walletAddressBookCmd = &cobra.Command{
+		Use:   "addressbook",
+		Short: "List the address book",
+		Long: `List the named addresses of the wallet's address book. The names can be used
+as the destination of 'siac wallet send'.`,
+		Run: wrap(walletaddressbookcmd),
+	}
+
+	walletAddressBookAddCmd = &cobra.Command{
+		Use:   "add [name] [address]",
+		Short: "Add a named address to the address book",
+		Long: `Add a named address to the address book or change the address of an existing
+name. Names can't contain whitespace.`,
+		Run: wrap(walletaddressbookaddcmd),
+	}
+
+	walletAddressBookRemoveCmd = &cobra.Command{
+		Use:   "remove [name]",
+		Short: "Remove a named address from the address book",
+		Long:  "Remove a named address from the address book.",
+		Run:   wrap(walletaddressbookremovecmd),
+	}
+
 	walletAddressesCmd = &cobra.Command{
 		Use:   "addresses",
 		Short: "List all addresses",
@@ -82,11 +107,76 @@ The smallest unit of siacoins is the hasting. One siacoin is 10^24 hastings. Oth
 		Run: wrap(walletbalancecmd),
 	}
 
+	walletColdCmd = &cobra.Command{
+		Use:   "cold",
+		Short: "Spend from a cold wallet",
+		Long: `Spend from the addresses of a seed which is kept on an air-gapped machine.
+siad only watches the addresses, so the seed never touches an online machine.
+A typical workflow is:
+
+  1. The public keys are derived offline with 'cold keys'.
+  2. The public keys are watched with 'wallet watch add'.
+  3. The unsigned transaction is created with 'cold send'.
+  4. The transaction is signed offline with 'cold sign'.
+  5. The signed transaction is broadcast with 'cold broadcast'.`,
+		// Run field is not set, as the cold command itself is not a valid
+		// command. A subcommand must be provided.
+	}
+
+	walletColdBroadcastCmd = &cobra.Command{
+		Use:   "broadcast [txn]",
+		Short: "Broadcast a signed cold transaction",
+		Long: `Broadcast a cold transaction which was signed offline. txn may be either JSON,
+base64, or a file containing either.`,
+		Run: wrap(walletcoldbroadcastcmd),
+	}
+
+	walletColdKeysCmd = &cobra.Command{
+		Use:   "keys [n]",
+		Short: "Derive the public keys of a seed",
+		Long: `Derive the public keys and addresses of the first n addresses of a seed. The
+seed is prompted for, so the command doesn't require siad and can be run on an
+air-gapped machine.`,
+		Run: wrap(walletcoldkeyscmd),
+	}
+
+	walletColdSendCmd = &cobra.Command{
+		Use:   "send [amount] [dest]",
+		Short: "Create an unsigned cold transaction",
+		Long: `Create an unsigned transaction which sends amount from the watched cold
+addresses to dest. The change is sent to the address set with --change or to
+the address of the largest spent output. The transaction is printed as JSON.
+Run 'wallet send --help' to see a list of available units.`,
+		Run: wrap(walletcoldsendcmd),
+	}
+
+	walletColdSignCmd = &cobra.Command{
+		Use:   "sign [txn]",
+		Short: "Sign a cold transaction with a seed",
+		Long: `Sign a cold transaction created by 'cold send' with the keys of a seed. txn
+may be either JSON or a file containing it. The seed is prompted for, so the
+command doesn't require siad and can be run on an air-gapped machine.`,
+		Run: wrap(walletcoldsigncmd),
+	}
+
+	walletEventsCmd = &cobra.Command{
+		Use:   "events",
+		Short: "Follow the wallet's balance changes and transactions",
+		Long: `Print the wallet's recent events and wait for new ones. Events are printed when
+the balance of the wallet changes and when transactions of the wallet enter the
+transaction pool, are confirmed, or are reverted. The --since flag skips the
+events up to and including the event with that ID.`,
+		Run: wrap(walleteventscmd),
+	}
+
 	walletInitCmd = &cobra.Command{
 		Use:   "init",
 		Short: "Initialize and encrypt a new wallet",
 		Long: `Generate a new wallet from a randomly generated seed, and encrypt it.
-By default the wallet encryption / unlock password is the same as the generated seed.`,
+By default the wallet encryption / unlock password is the same as the generated seed.
+
+The --bip39 flag displays the seed as a 24 word BIP39 mnemonic instead of a Sia
+seed phrase. Commands which take a seed accept both.`,
 		Run: wrap(walletinitcmd),
 	}
 
@@ -97,6 +187,19 @@ By default the wallet encryption / unlock password is the same as the generated
 		Run:   wrap(walletinitseedcmd),
 	}
 
+	walletLimitsCmd = &cobra.Command{
+		Use:   "limits",
+		Short: "View or set the wallet's spending limits",
+		Long: `View or set the wallet's spending limits.
+
+The --daily-limit flag sets the maximum amount of siacoins the wallet sends within
+24 hours. Sends which exceed the --approval-threshold are held until they are
+approved with 'siac wallet pending approve', and the --webhook URL is notified
+about them. Amounts can be specified in units, e.g. 1.23KS, and an amount of 0
+disables the limit. Pass an empty --webhook to remove it.`,
+		Run: walletlimitscmd,
+	}
+
 	walletLoad033xCmd = &cobra.Command{
 		Use:   "033x [filepath]",
 		Short: "Load a v0.3.3.x wallet",
@@ -126,6 +229,35 @@ By default the wallet encryption / unlock password is the same as the generated
 		Run:     wrap(walletloadsiagcmd),
 	}
 
+	walletBumpFeeCmd = &cobra.Command{
+		Use:   "bumpfee [txid]",
+		Short: "Replace an unconfirmed transaction with one paying a higher fee",
+		Long: `Replace the unconfirmed transaction set of one of the wallet's transactions with
+a set paying a higher fee, so that it confirms faster. The higher fee is paid
+from a change output of the set, so the replacements have new ids.`,
+		Example: "siac wallet bumpfee 1b6b...96ec --fee 30mS",
+		Run:     wrap(walletbumpfeecmd),
+	}
+
+	walletCPFPCmd = &cobra.Command{
+		Use:   "cpfp [txid]",
+		Short: "Add a child transaction paying a higher fee to a stuck transaction",
+		Long: `Add a child transaction to the unconfirmed transaction set of one of the
+wallet's transactions. The child spends a change output of the set and pays
+enough fees to raise the fee of the whole set, so that it confirms faster.
+Unlike bumpfee, the ids of the transactions in the set don't change.`,
+		Example: "siac wallet cpfp 1b6b...96ec --fee 30mS",
+		Run:     wrap(walletcpfpcmd),
+	}
+
+	walletLabelCmd = &cobra.Command{
+		Use:   "label [txid] [label]",
+		Short: "Label a transaction",
+		Long: `Set the label of a transaction, which is shown by 'siac wallet transactions'.
+An empty label removes the label.`,
+		Run: wrap(walletlabelcmd),
+	}
+
 	walletLockCmd = &cobra.Command{
 		Use:   "lock",
 		Short: "Lock the wallet",
@@ -133,11 +265,111 @@ By default the wallet encryption / unlock password is the same as the generated
 		Run:   wrap(walletlockcmd),
 	}
 
+	walletMultisigCmd = &cobra.Command{
+		Use:   "multisig",
+		Short: "Create and spend from multisig addresses",
+		Long: `Create multisig addresses and spend from them together with the other
+parties. A typical workflow is:
+
+  1. Every party creates the address with 'multisig address'.
+  2. One party creates the transaction with 'multisig send'.
+  3. The parties sign their copies with 'multisig sign'.
+  4. The copies are combined with 'multisig merge'.
+  5. The transaction is broadcast with 'wallet broadcast'.
+
+Transactions are printed as JSON, or as base64 if --raw is set.`,
+		// Run field is not set, as the multisig command itself is not a valid
+		// command. A subcommand must be provided.
+	}
+
+	walletMultisigAddressCmd = &cobra.Command{
+		Use:   "address [required] [publickeys]",
+		Short: "Create a multisig address",
+		Long: `Create an address which can be spent with the signatures of 'required' of
+the comma-separated public keys (e.g. ed25519:d0e1...,ed25519:a9e2...) and
+watch it. The public key of a wallet address is part of its unlock conditions,
+see the /wallet/unlockconditions/:addr API. Unless --unused is set, the wallet
+rescans the blockchain for transactions related to the address.`,
+		Run: wrap(walletmultisigaddresscmd),
+	}
+
+	walletMultisigMergeCmd = &cobra.Command{
+		Use:   "merge [txn] [txn]...",
+		Short: "Merge the signatures of a multisig transaction",
+		Long: `Merge the signatures of copies of a multisig transaction which were signed
+by different parties. Each txn may be either JSON, base64, or a file containing
+either. Merging doesn't require siad.`,
+		Run: walletmultisigmergecmd,
+	}
+
+	walletMultisigSendCmd = &cobra.Command{
+		Use:   "send [address] [amount] [dest]",
+		Short: "Create a transaction spending from a multisig address",
+		Long: `Create an unsigned transaction which sends amount from the multisig address
+to dest. The change is returned to the multisig address. Run 'wallet send
+--help' to see a list of available units.`,
+		Run: wrap(walletmultisigsendcmd),
+	}
+
+	walletMultisigSignCmd = &cobra.Command{
+		Use:   "sign [txn]",
+		Short: "Sign a multisig transaction",
+		Long: `Add the signatures of the wallet's keys to a multisig transaction. txn may be
+either JSON, base64, or a file containing either.`,
+		Run: wrap(walletmultisigsigncmd),
+	}
+
+	walletPendingCmd = &cobra.Command{
+		Use:   "pending",
+		Short: "List the sends waiting for approval",
+		Long:  "List the sends which exceeded the approval threshold of the wallet's spending limits.",
+		Run:   wrap(walletpendingcmd),
+	}
+
+	walletStuckCmd = &cobra.Command{
+		Use:   "stuck",
+		Short: "List the stuck transactions of the wallet",
+		Long: `List the transaction sets sent by the wallet which weren't confirmed within a
+few blocks, were evicted from the transaction pool or were double spent. The
+wallet rebroadcasts its transactions until they are confirmed. Stuck
+transactions can be sped up with bumpfee or cpfp.`,
+		Run: wrap(walletstuckcmd),
+	}
+
+	walletPendingApproveCmd = &cobra.Command{
+		Use:   "approve [id]",
+		Short: "Approve a pending send",
+		Long: `Approve a pending send and send its siacoins. The send still needs to be within
+the daily spending limit.`,
+		Run: wrap(walletpendingapprovecmd),
+	}
+
+	walletPendingRejectCmd = &cobra.Command{
+		Use:   "reject [id]",
+		Short: "Reject a pending send",
+		Long:  "Reject a pending send without sending its siacoins.",
+		Run:   wrap(walletpendingrejectcmd),
+	}
+
+	walletRescanCmd = &cobra.Command{
+		Use:   "rescan",
+		Short: "Rescan the blockchain for the wallet's transactions",
+		Long: `Rescan the blockchain for transactions of the wallet's addresses and report
+the progress until the rescan is done.
+
+The --gap-limit flag sets the number of addresses of the wallet's seed which are
+searched for beyond the last address in use. Use it to recover the balance of
+addresses which were generated elsewhere, e.g. by another wallet using the same
+seed. The gap limit is remembered by the wallet.`,
+		Run: wrap(walletrescancmd),
+	}
+
 	walletSeedsCmd = &cobra.Command{
 		Use:   "seeds",
 		Short: "View information about your seeds",
-		Long:  "View your primary and auxiliary wallet seeds.",
-		Run:   wrap(walletseedscmd),
+		Long: `View your primary and auxiliary wallet seeds. The --bip39 flag displays the
+seeds as 24 word BIP39 mnemonics instead of Sia seed phrases.`,
+		Run: wrap(walletseedscmd),
 	}
 
 	walletSendCmd = &cobra.Command{
@@ -151,12 +383,18 @@ By default the wallet encryption / unlock password is the same as the generated
 	walletSendSiacoinsCmd = &cobra.Command{
 		Use:   "siacoins [amount] [dest]",
 		Short: "Send siacoins to an address",
-		Long: `Send siacoins to an address. 'dest' must be a 76-byte hexadecimal address.
+		Long: `Send siacoins to an address. 'dest' must be a 76-byte hexadecimal address or
+the name of an address book entry, which can also be provided with --to.
 'amount' can be specified in units, e.g. 1.23KS. Run 'wallet --help' for a list of units.
 If no unit is supplied, hastings will be assumed.
 
-A dynamic transaction fee is applied depending on the size of the transaction and how busy the network is.`,
-		Run: wrap(walletsendsiacoinscmd),
+A dynamic transaction fee is applied depending on the size of the transaction and how busy the network is.
+
+The --strategy flag selects which outputs fund the transaction:
+  minimizeinputs - spend the largest outputs (default)
+  minimizechange - spend the outputs that exceed the amount by the least
+  consolidate    - spend the smallest outputs, including dust`,
+		Run: walletsendsiacoinscmd,
 	}
 
 	walletSendSiafundsCmd = &cobra.Command{
@@ -206,6 +444,55 @@ SIA_WALLET_PASSWORD environment variable is set, the unlock command will
 use it instead of displaying the typical interactive prompt.`,
 		Run: wrap(walletunlockcmd),
 	}
+
+	walletUnspentCmd = &cobra.Command{
+		Use:   "unspent",
+		Short: "List the wallet's unspent outputs",
+		Long: `List the unspent siacoin and siafund outputs tracked by the wallet. Outputs
+marked as do-not-spend are never used to fund a transaction.`,
+		Run: wrap(walletunspentcmd),
+	}
+
+	walletUnspentAllowSpendCmd = &cobra.Command{
+		Use:   "allowspend [id] [id]...",
+		Short: "Allow the wallet to spend outputs again",
+		Long:  "Remove the do-not-spend mark of one or more outputs.",
+		Run:   walletunspentallowspendcmd,
+	}
+
+	walletUnspentDoNotSpendCmd = &cobra.Command{
+		Use:   "donotspend [id] [id]...",
+		Short: "Prevent the wallet from spending outputs",
+		Long: `Mark one or more outputs as do-not-spend. The wallet won't use them to fund
+transactions until the mark is removed with 'siac wallet unspent allowspend'.`,
+		Run: walletunspentdonotspendcmd,
+	}
+
+	walletWatchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "View the balances of watch-only addresses",
+		Long: `View the balances of the addresses the wallet watches without holding their
+keys. Watch-only balances are not included in the wallet's balance.`,
+		Run: wrap(walletwatchcmd),
+	}
+
+	walletWatchAddCmd = &cobra.Command{
+		Use:   "add [address|publickey]",
+		Short: "Watch an address",
+		Long: `Watch an address or the standard address of a public key (e.g.
+ed25519:d0e1...) without holding its keys. Unless --unused is set, the wallet
+rescans the blockchain for transactions related to the address.`,
+		Run: wrap(walletwatchaddcmd),
+	}
+
+	walletWatchRemoveCmd = &cobra.Command{
+		Use:   "remove [address|publickey]",
+		Short: "Stop watching an address",
+		Long: `Stop watching an address or the standard address of a public key. Unless
+--unused is set, the wallet rescans the blockchain to rebuild its transaction
+history.`,
+		Run: wrap(walletwatchremovecmd),
+	}
 )
 
 const askPasswordText = "We need to encrypt the new data using the current wallet password, please provide: "
@@ -258,6 +545,46 @@ func walletaddresscmd() {
 	fmt.Printf("Created new address: %s\n", addr.Address)
 }
 
+// walletaddressbookcmd lists the address book.
+func walletaddressbookcmd() {
+	wabg, err := httpClient.WalletAddressBookGet()
+	if err != nil {
+		die("Could not get address book:", err)
+	}
+	if len(wabg.Entries) == 0 {
+		fmt.Println("The address book is empty.")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tAddress")
+	for _, entry := range wabg.Entries {
+		fmt.Fprintf(w, "%v\t%v\n", entry.Name, entry.Address)
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// walletaddressbookaddcmd adds a named address to the address book.
+func walletaddressbookaddcmd(name, addr string) {
+	var hash types.UnlockHash
+	if err := hash.LoadString(addr); err != nil {
+		die("Could not parse address:", err)
+	}
+	if err := httpClient.WalletAddressBookAddPost(name, hash); err != nil {
+		die("Could not add address book entry:", err)
+	}
+	fmt.Printf("Added %v to the address book\n", name)
+}
+
+// walletaddressbookremovecmd removes a named address from the address book.
+func walletaddressbookremovecmd(name string) {
+	if err := httpClient.WalletAddressBookRemovePost(name); err != nil {
+		die("Could not remove address book entry:", err)
+	}
+	fmt.Printf("Removed %v from the address book\n", name)
+}
+
 // walletaddressescmd fetches the list of addresses that the wallet knows.
 func walletaddressescmd() {
 	addrs, err := httpClient.WalletAddressesGet()
@@ -288,6 +615,17 @@ func walletchangepasswordcmd() {
 	fmt.Println("Password changed successfully.")
 }
 
+// walleteventscmd prints the wallet's events as they occur.
+func walleteventscmd() {
+	err := httpClient.WalletEventsSubscribe(walletEventsSince, func(e modules.WalletEvent) error {
+		fmt.Println(e.ID, e.Time.Format("Jan 02 15:04:05"), formatWalletEvent(e))
+		return nil
+	})
+	if err != nil {
+		die("Could not follow wallet events:", err)
+	}
+}
+
 // walletinitcmd encrypts the wallet with the given password
 func walletinitcmd() {
 	var password string
@@ -300,7 +638,11 @@ func walletinitcmd() {
 			die(err)
 		}
 	}
-	er, err := httpClient.WalletInitPost(password, initForce)
+	dictionary := mnemonics.English
+	if walletBIP39 {
+		dictionary = modules.BIP39Dictionary
+	}
+	er, err := httpClient.WalletInitDictionaryPost(password, dictionary, initForce)
 	if err != nil {
 		die("Error when encrypting wallet:", err)
 	}
@@ -381,6 +723,79 @@ func walletloadsiagcmd(keyfiles string) {
 	fmt.Println("Wallet loading successful.")
 }
 
+// walletlabelcmd sets the label of a transaction.
+func walletlabelcmd(txidStr, label string) {
+	var txid types.TransactionID
+	if err := txid.UnmarshalJSON([]byte(`"` + txidStr + `"`)); err != nil {
+		die("Could not parse transaction id:", err)
+	}
+	if err := httpClient.WalletTransactionLabelPost(txid, label); err != nil {
+		die("Could not label transaction:", err)
+	}
+	if label == "" {
+		fmt.Println("Removed the label of the transaction")
+	} else {
+		fmt.Println("Labeled the transaction")
+	}
+}
+
+// bumpFeePerByte returns the fee per byte of the --fee flag, or the priority
+// fee estimation if it isn't set.
+func bumpFeePerByte() types.Currency {
+	if walletBumpFee == "" {
+		est, err := httpClient.TransactionPoolFeeEstimateGet()
+		if err != nil {
+			die("Could not get fee estimation:", err)
+		}
+		return est.Priority
+	}
+	hastings, err := types.ParseCurrency(walletBumpFee)
+	if err != nil {
+		die("Could not parse fee:", err)
+	}
+	var fee types.Currency
+	if _, err := fmt.Sscan(hastings, &fee); err != nil {
+		die("Could not parse fee:", err)
+	}
+	return fee.Div64(1e3)
+}
+
+// walletbumpfeecmd replaces the transaction set of an unconfirmed transaction
+// with a set paying a higher fee.
+func walletbumpfeecmd(txidStr string) {
+	var txid types.TransactionID
+	if err := txid.UnmarshalJSON([]byte(`"` + txidStr + `"`)); err != nil {
+		die("Could not parse transaction id:", err)
+	}
+	fee := bumpFeePerByte()
+	wbfp, err := httpClient.WalletTransactionBumpFeePost(txid, fee)
+	if err != nil {
+		die("Could not bump fee:", err)
+	}
+	fmt.Printf("Replaced the transaction with a set paying %v/KB:\n", currencyUnits(fee.Mul64(1e3)))
+	for _, id := range wbfp.TransactionIDs {
+		fmt.Println(id)
+	}
+}
+
+// walletcpfpcmd adds a child transaction paying a higher fee to the
+// transaction set of an unconfirmed transaction.
+func walletcpfpcmd(txidStr string) {
+	var txid types.TransactionID
+	if err := txid.UnmarshalJSON([]byte(`"` + txidStr + `"`)); err != nil {
+		die("Could not parse transaction id:", err)
+	}
+	fee := bumpFeePerByte()
+	wbfp, err := httpClient.WalletTransactionCPFPPost(txid, fee)
+	if err != nil {
+		die("Could not add child transaction:", err)
+	}
+	fmt.Printf("Added a child transaction, the set now pays %v/KB:\n", currencyUnits(fee.Mul64(1e3)))
+	for _, id := range wbfp.TransactionIDs {
+		fmt.Println(id)
+	}
+}
+
 // walletlockcmd locks the wallet
 func walletlockcmd() {
 	err := httpClient.WalletLockPost()
@@ -391,7 +806,11 @@ func walletlockcmd() {
 
 // walletseedcmd returns the current seed {
 func walletseedscmd() {
-	seedInfo, err := httpClient.WalletSeedsGet()
+	dictionary := mnemonics.English
+	if walletBIP39 {
+		dictionary = modules.BIP39Dictionary
+	}
+	seedInfo, err := httpClient.WalletSeedsDictionaryGet(dictionary)
 	if err != nil {
 		die("Error retrieving the current seed:", err)
 	}
@@ -413,7 +832,15 @@ func walletseedscmd() {
 }
 
 // walletsendsiacoinscmd sends siacoins to a destination address.
-func walletsendsiacoinscmd(amount, dest string) {
+func walletsendsiacoinscmd(cmd *cobra.Command, args []string) {
+	if walletSendTo != "" {
+		args = append(args, walletSendTo)
+	}
+	if len(args) != 2 {
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	amount, dest := args[0], args[1]
 	hastings, err := types.ParseCurrency(amount)
 	if err != nil {
 		die("Could not parse amount:", err)
@@ -422,15 +849,47 @@ func walletsendsiacoinscmd(amount, dest string) {
 	if _, err := fmt.Sscan(hastings, &value); err != nil {
 		die("Failed to parse amount", err)
 	}
-	var hash types.UnlockHash
-	if _, err := fmt.Sscan(dest, &hash); err != nil {
-		die("Failed to parse destination address", err)
+	hash := resolveDestination(dest)
+	strategy, err := modules.ParseSelectionStrategy(walletTxnStrategy)
+	if err != nil {
+		die("Could not parse strategy:", err)
 	}
-	_, err = httpClient.WalletSiacoinsPost(value, hash, walletTxnFeeIncluded)
+	wsp, err := httpClient.WalletSiacoinsStrategyPost(value, hash, walletTxnFeeIncluded, strategy)
 	if err != nil {
 		die("Could not send siacoins:", err)
 	}
+	if wsp.PendingID != "" {
+		fmt.Printf("The send exceeds the approval threshold and is pending approval with id %v\n", wsp.PendingID)
+		fmt.Printf("Approve it with 'siac wallet pending approve %v'\n", wsp.PendingID)
+		return
+	}
 	fmt.Printf("Sent %s hastings to %s\n", hastings, dest)
+	if walletTxnLabel != "" && len(wsp.TransactionIDs) > 0 {
+		err = httpClient.WalletTransactionLabelPost(wsp.TransactionIDs[len(wsp.TransactionIDs)-1], walletTxnLabel)
+		if err != nil {
+			die("Could not label transaction:", err)
+		}
+	}
+}
+
+// resolveDestination parses a destination address or looks up the address of
+// an address book entry.
+func resolveDestination(dest string) types.UnlockHash {
+	var hash types.UnlockHash
+	if err := hash.LoadString(dest); err == nil {
+		return hash
+	}
+	wabg, err := httpClient.WalletAddressBookGet()
+	if err != nil {
+		die("Could not get address book:", err)
+	}
+	for _, entry := range wabg.Entries {
+		if entry.Name == dest {
+			return entry.Address
+		}
+	}
+	die("Destination is neither an address nor the name of an address book entry:", dest)
+	return types.UnlockHash{}
 }
 
 // walletsendsiafundscmd sends siafunds to a destination address.
@@ -580,14 +1039,7 @@ func walletsigncmd(cmd *cobra.Command, args []string) {
 // transactions without siad.
 func walletsigncmdoffline(txn *types.Transaction, toSign []crypto.Hash) {
 	fmt.Println("Enter your wallet seed to generate the signing key(s) now and sign without siad.")
-	seedString, err := passwordPrompt("Seed: ")
-	if err != nil {
-		die("Reading seed failed:", err)
-	}
-	seed, err := modules.StringToSeed(seedString, mnemonics.English)
-	if err != nil {
-		die("Invalid seed:", err)
-	}
+	seed := promptSeed()
 	// signing via seed may take a while, since we need to regenerate
 	// keys. If it takes longer than a second, print a message to assure
 	// the user that this is normal.
@@ -599,7 +1051,7 @@ func walletsigncmdoffline(txn *types.Transaction, toSign []crypto.Hash) {
 		case <-done:
 		}
 	}()
-	err = wallet.SignTransaction(txn, seed, toSign, 180e3)
+	err := wallet.SignTransaction(txn, seed, toSign, 180e3)
 	if err != nil {
 		die("Failed to sign transaction:", err)
 	}
@@ -658,10 +1110,14 @@ func wallettransactionscmd() {
 		fmt.Printf("%67v%15.2f SC", txn.TransactionID, incomingSiacoinsFloat-outgoingSiacoinsFloat)
 		// For siafunds, need to avoid having a negative types.Currency.
 		if incomingSiafunds.Cmp(outgoingSiafunds) >= 0 {
-			fmt.Printf("%14v SF\n", incomingSiafunds.Sub(outgoingSiafunds))
+			fmt.Printf("%14v SF", incomingSiafunds.Sub(outgoingSiafunds))
 		} else {
-			fmt.Printf("-%14v SF\n", outgoingSiafunds.Sub(incomingSiafunds))
+			fmt.Printf("-%14v SF", outgoingSiafunds.Sub(incomingSiafunds))
+		}
+		if label, exists := wtg.Labels[txn.TransactionID.String()]; exists {
+			fmt.Printf("  %v", label)
 		}
+		fmt.Println()
 	}
 }
 
@@ -689,3 +1145,553 @@ func walletunlockcmd() {
 		die("Could not unlock wallet:", err)
 	}
 }
+
+// parseWatchAddress parses an address or a public key for the watch commands.
+func parseWatchAddress(s string) (addrs []types.UnlockHash, pks []types.SiaPublicKey) {
+	var addr types.UnlockHash
+	if err := addr.LoadString(s); err == nil {
+		return []types.UnlockHash{addr}, nil
+	}
+	var pk types.SiaPublicKey
+	if err := pk.LoadString(s); err == nil {
+		return nil, []types.SiaPublicKey{pk}
+	}
+	die("Could not parse address or public key:", s)
+	return nil, nil
+}
+
+// walletunspentcmd lists the unspent outputs of the wallet.
+func walletunspentcmd() {
+	wug, err := httpClient.WalletUnspentGet()
+	if err != nil {
+		die("Could not get unspent outputs:", err)
+	}
+	if len(wug.Outputs) == 0 {
+		fmt.Println("The wallet has no unspent outputs.")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tValue\tAddress\tHeight\tFlags")
+	for _, o := range wug.Outputs {
+		value := currencyUnits(o.Value)
+		if o.FundType == types.SpecifierSiafundOutput {
+			value = fmt.Sprintf("%v SF", o.Value)
+		}
+		height := fmt.Sprint(o.ConfirmationHeight)
+		if o.ConfirmationHeight == types.BlockHeight(math.MaxUint64) {
+			height = "unconfirmed"
+		}
+		var flags []string
+		if o.IsWatchOnly {
+			flags = append(flags, "watch-only")
+		}
+		if o.DoNotSpend {
+			flags = append(flags, "do-not-spend")
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", o.ID, value, o.UnlockHash, height, strings.Join(flags, ","))
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// parseOutputIDs parses the output ids of the unspent subcommands.
+func parseOutputIDs(cmd *cobra.Command, args []string) []types.OutputID {
+	if len(args) == 0 {
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	ids := make([]types.OutputID, 0, len(args))
+	for _, arg := range args {
+		var id types.OutputID
+		if err := (*crypto.Hash)(&id).LoadString(arg); err != nil {
+			die("Could not parse output id:", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// walletunspentallowspendcmd removes the do-not-spend mark of outputs.
+func walletunspentallowspendcmd(cmd *cobra.Command, args []string) {
+	err := httpClient.WalletUnspentPost(parseOutputIDs(cmd, args), false)
+	if err != nil {
+		die("Could not unmark outputs:", err)
+	}
+	fmt.Println("The outputs can be spent again")
+}
+
+// walletunspentdonotspendcmd marks outputs as do-not-spend.
+func walletunspentdonotspendcmd(cmd *cobra.Command, args []string) {
+	err := httpClient.WalletUnspentPost(parseOutputIDs(cmd, args), true)
+	if err != nil {
+		die("Could not mark outputs:", err)
+	}
+	fmt.Println("The outputs are marked as do-not-spend")
+}
+
+// walletwatchcmd prints the balances of the watch-only addresses.
+func walletwatchcmd() {
+	wwbg, err := httpClient.WalletWatchBalanceGet()
+	if err != nil {
+		die("Could not get watch-only balances:", err)
+	}
+	if len(wwbg.Addresses) == 0 {
+		fmt.Println("No addresses are being watched.")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Address\tConfirmed\tUnconfirmed In\tUnconfirmed Out\tSiafunds\tTransactions")
+	for _, wb := range wwbg.Addresses {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v SF\t%v\n", wb.Address, currencyUnits(wb.ConfirmedSiacoinBalance),
+			currencyUnits(wb.UnconfirmedIncomingSiacoins), currencyUnits(wb.UnconfirmedOutgoingSiacoins), wb.SiafundBalance, wb.Transactions)
+	}
+	fmt.Fprintf(w, "Total\t%v\t%v\t%v\t%v SF\t\n", currencyUnits(wwbg.ConfirmedSiacoinBalance),
+		currencyUnits(wwbg.UnconfirmedIncomingSiacoins), currencyUnits(wwbg.UnconfirmedOutgoingSiacoins), wwbg.SiafundBalance)
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// walletwatchaddcmd adds an address or a public key to the watch set.
+func walletwatchaddcmd(s string) {
+	addrs, pks := parseWatchAddress(s)
+	var err error
+	if len(pks) > 0 {
+		err = httpClient.WalletWatchPublicKeysAddPost(pks, walletWatchUnused)
+	} else {
+		err = httpClient.WalletWatchAddPost(addrs, walletWatchUnused)
+	}
+	if err != nil {
+		die("Could not watch address:", err)
+	}
+	fmt.Println("Address is being watched")
+}
+
+// walletwatchremovecmd removes an address or a public key from the watch set.
+func walletwatchremovecmd(s string) {
+	addrs, pks := parseWatchAddress(s)
+	for _, pk := range pks {
+		uc := types.UnlockConditions{
+			PublicKeys:         []types.SiaPublicKey{pk},
+			SignaturesRequired: 1,
+		}
+		addrs = append(addrs, uc.UnlockHash())
+	}
+	err := httpClient.WalletWatchRemovePost(addrs, walletWatchUnused)
+	if err != nil {
+		die("Could not stop watching address:", err)
+	}
+	fmt.Println("Address is no longer being watched")
+}
+
+// printMultisigTxn prints a multisig transaction and the number of signatures
+// it still needs. The transaction is printed to stdout so that it can be
+// written to a file.
+func printMultisigTxn(txn types.Transaction) {
+	var err error
+	if walletRawTxn {
+		_, err = base64.NewEncoder(base64.StdEncoding, os.Stdout).Write(encoding.Marshal(txn))
+	} else {
+		err = json.NewEncoder(os.Stdout).Encode(txn)
+	}
+	if err != nil {
+		die("failed to encode txn", err)
+	}
+	fmt.Println()
+	if missing := modules.MissingSignatures(txn); missing > 0 {
+		fmt.Fprintf(os.Stderr, "The transaction needs %v more signatures\n", missing)
+	} else {
+		fmt.Fprintln(os.Stderr, "The transaction is fully signed and can be broadcast with 'siac wallet broadcast'")
+	}
+}
+
+// formatWalletEvent returns a one line description of a wallet event.
+func formatWalletEvent(e modules.WalletEvent) string {
+	if e.Type == modules.WalletEventBalance {
+		return fmt.Sprintf("balance changed: %v confirmed, %v incoming, %v outgoing", currencyUnits(e.ConfirmedSiacoinBalance),
+			currencyUnits(e.UnconfirmedIncomingSiacoins), currencyUnits(e.UnconfirmedOutgoingSiacoins))
+	}
+	if e.Transaction == nil {
+		return string(e.Type)
+	}
+	var incoming, outgoing types.Currency
+	for _, input := range e.Transaction.Inputs {
+		if input.FundType == types.SpecifierSiacoinInput && input.WalletAddress {
+			outgoing = outgoing.Add(input.Value)
+		}
+	}
+	for _, output := range e.Transaction.Outputs {
+		if (output.FundType == types.SpecifierSiacoinOutput || output.FundType == types.SpecifierMinerPayout) && output.WalletAddress {
+			incoming = incoming.Add(output.Value)
+		}
+	}
+	var status string
+	switch e.Type {
+	case modules.WalletEventTransactionConfirmed:
+		status = "confirmed"
+	case modules.WalletEventTransactionReverted:
+		status = "reverted"
+	case modules.WalletEventTransactionUnconfirmed:
+		status = "unconfirmed"
+	default:
+		return string(e.Type)
+	}
+	return fmt.Sprintf("%v transaction %v: %v received, %v sent", status, e.Transaction.TransactionID,
+		currencyUnits(incoming), currencyUnits(outgoing))
+}
+
+// promptSeed prompts the user for a seed, which may be a Sia seed phrase or a
+// BIP39 mnemonic.
+func promptSeed() modules.Seed {
+	seedString, err := passwordPrompt("Seed: ")
+	if err != nil {
+		die("Reading seed failed:", err)
+	}
+	dictionary := mnemonics.English
+	if modules.IsBIP39Phrase(seedString) {
+		dictionary = modules.BIP39Dictionary
+	}
+	seed, err := modules.StringToSeed(seedString, dictionary)
+	if err != nil {
+		die("Invalid seed:", err)
+	}
+	return seed
+}
+
+// walletcoldkeyscmd derives the public keys of the first addresses of a seed.
+func walletcoldkeyscmd(n string) {
+	num, err := strconv.ParseUint(n, 10, 32)
+	if err != nil || num == 0 {
+		die("Invalid number of keys:", n)
+	}
+	seed := promptSeed()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Public Key\tAddress")
+	for _, uc := range wallet.SeedUnlockConditions(seed, num) {
+		fmt.Fprintf(w, "%v\t%v\n", uc.PublicKeys[0], uc.UnlockHash())
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// walletcoldsendcmd creates an unsigned cold transaction.
+func walletcoldsendcmd(amount, dest string) {
+	var to, change types.UnlockHash
+	if err := to.LoadString(dest); err != nil {
+		die("Failed to parse destination address", err)
+	}
+	if walletColdChange != "" {
+		if err := change.LoadString(walletColdChange); err != nil {
+			die("Failed to parse change address", err)
+		}
+	}
+	hastings, err := types.ParseCurrency(amount)
+	if err != nil {
+		die("Could not parse amount:", err)
+	}
+	var value types.Currency
+	if _, err := fmt.Sscan(hastings, &value); err != nil {
+		die("Failed to parse amount", err)
+	}
+	wctp, err := httpClient.WalletColdTransactionPost([]types.SiacoinOutput{{Value: value, UnlockHash: to}}, change)
+	if err != nil {
+		die("Could not create cold transaction:", err)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(wctp.ColdTransaction); err != nil {
+		die("failed to encode cold transaction", err)
+	}
+	fmt.Fprintln(os.Stderr, "Sign the transaction offline with 'siac wallet cold sign'")
+}
+
+// walletcoldsigncmd signs a cold transaction with the keys of a seed.
+func walletcoldsigncmd(txnStr string) {
+	ct, err := parseColdTxn(txnStr)
+	if err != nil {
+		die("Could not decode cold transaction:", err)
+	}
+	// Show what is signed before asking for the seed.
+	var sent types.Currency
+	for _, sco := range ct.Transaction.SiacoinOutputs {
+		fmt.Fprintf(os.Stderr, "Output: %v to %v\n", currencyUnits(sco.Value), sco.UnlockHash)
+		sent = sent.Add(sco.Value)
+	}
+	if ct.Funds.Cmp(sent) >= 0 {
+		fmt.Fprintf(os.Stderr, "Fee:    %v\n", currencyUnits(ct.Funds.Sub(sent)))
+	}
+	seed := promptSeed()
+	txn, err := wallet.SignColdTransaction(ct, seed)
+	if err != nil {
+		die("Failed to sign cold transaction:", err)
+	}
+	if walletRawTxn {
+		_, err = base64.NewEncoder(base64.StdEncoding, os.Stdout).Write(encoding.Marshal(txn))
+	} else {
+		err = json.NewEncoder(os.Stdout).Encode(txn)
+	}
+	if err != nil {
+		die("failed to encode txn", err)
+	}
+	fmt.Println()
+	fmt.Fprintln(os.Stderr, "Broadcast the transaction with 'siac wallet cold broadcast'")
+}
+
+// walletcoldbroadcastcmd broadcasts a cold transaction which was signed
+// offline.
+func walletcoldbroadcastcmd(txnStr string) {
+	txn, err := parseTxn(txnStr)
+	if err != nil {
+		die("Could not decode transaction:", err)
+	}
+	if err := httpClient.WalletColdBroadcastPost(txn); err != nil {
+		die("Could not broadcast cold transaction:", err)
+	}
+	fmt.Println("Transaction has been broadcast successfully")
+}
+
+// parseAmount parses an amount of siacoins which may be specified in units.
+func parseAmount(amount string) (types.Currency, error) {
+	hastings, err := types.ParseCurrency(amount)
+	if err != nil {
+		return types.Currency{}, err
+	}
+	var value types.Currency
+	if _, err := fmt.Sscan(hastings, &value); err != nil {
+		return types.Currency{}, err
+	}
+	return value, nil
+}
+
+// walletlimitscmd displays or sets the wallet's spending limits.
+func walletlimitscmd(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	wlg, err := httpClient.WalletLimitsGet()
+	if err != nil {
+		die("Could not get spending limits:", err)
+	}
+	limits := wlg.WalletSpendingLimits
+	if !cmd.Flags().Changed("daily-limit") && !cmd.Flags().Changed("approval-threshold") && !cmd.Flags().Changed("webhook") {
+		limitStr := func(c types.Currency) string {
+			if c.IsZero() {
+				return "none"
+			}
+			return currencyUnits(c)
+		}
+		webhook := limits.ApprovalWebhook
+		if webhook == "" {
+			webhook = "none"
+		}
+		fmt.Printf(`Daily Limit:        %v
+Spent Today:        %v
+Approval Threshold: %v
+Approval Webhook:   %v
+`, limitStr(limits.DailyLimit), currencyUnits(wlg.Spent), limitStr(limits.ApprovalThreshold), webhook)
+		return
+	}
+
+	if cmd.Flags().Changed("daily-limit") {
+		limits.DailyLimit, err = parseAmount(walletDailyLimit)
+		if err != nil {
+			die("Could not parse daily limit:", err)
+		}
+	}
+	if cmd.Flags().Changed("approval-threshold") {
+		limits.ApprovalThreshold, err = parseAmount(walletApproveAbove)
+		if err != nil {
+			die("Could not parse approval threshold:", err)
+		}
+	}
+	if cmd.Flags().Changed("webhook") {
+		limits.ApprovalWebhook = walletWebhook
+	}
+	if err := httpClient.WalletLimitsPost(limits); err != nil {
+		die("Could not set spending limits:", err)
+	}
+	fmt.Println("Spending limits have been updated")
+}
+
+// walletpendingcmd lists the sends which are waiting for approval.
+func walletpendingcmd() {
+	wpg, err := httpClient.WalletPendingGet()
+	if err != nil {
+		die("Could not get pending sends:", err)
+	}
+	if len(wpg.Sends) == 0 {
+		fmt.Println("No sends are pending approval.")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCreated\tAmount\tDestinations")
+	for _, ps := range wpg.Sends {
+		var total types.Currency
+		var dests []string
+		for _, sco := range ps.Outputs {
+			total = total.Add(sco.Value)
+			dests = append(dests, sco.UnlockHash.String())
+		}
+		created := time.Unix(int64(ps.Created), 0).Format(time.RFC822)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", ps.ID, created, currencyUnits(total), strings.Join(dests, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// walletstuckcmd lists the stuck transactions of the wallet.
+func walletstuckcmd() {
+	wsg, err := httpClient.WalletStuckGet()
+	if err != nil {
+		die("Could not get stuck transactions:", err)
+	}
+	if len(wsg.Sets) == 0 {
+		fmt.Println("No transactions are stuck.")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tStatus\tHeight\tBroadcasts\tLast Broadcast\tFees")
+	for _, bts := range wsg.Sets {
+		var fees types.Currency
+		for _, txn := range bts.Transactions {
+			for _, fee := range txn.MinerFees {
+				fees = fees.Add(fee)
+			}
+		}
+		last := time.Unix(int64(bts.LastBroadcast), 0).Format(time.RFC822)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", bts.ID, bts.Status, bts.Height, bts.Broadcasts, last, currencyUnits(fees))
+	}
+	if err := w.Flush(); err != nil {
+		die("failed to flush writer")
+	}
+}
+
+// walletpendingapprovecmd approves a pending send.
+func walletpendingapprovecmd(id string) {
+	wsp, err := httpClient.WalletPendingApprovePost(id, walletTxnLabel)
+	if err != nil {
+		die("Could not approve pending send:", err)
+	}
+	fmt.Println("Pending send has been approved and sent")
+	for _, txid := range wsp.TransactionIDs {
+		fmt.Println(txid)
+	}
+}
+
+// walletpendingrejectcmd rejects a pending send.
+func walletpendingrejectcmd(id string) {
+	if err := httpClient.WalletPendingRejectPost(id); err != nil {
+		die("Could not reject pending send:", err)
+	}
+	fmt.Println("Pending send has been rejected")
+}
+
+// walletrescancmd rescans the blockchain and reports the progress of the
+// rescan.
+func walletrescancmd() {
+	if err := httpClient.WalletRescanPost(walletRescanGapLimit); err != nil {
+		die("Could not start rescan:", err)
+	}
+	var wrg api.WalletRescanGET
+	for {
+		var err error
+		wrg, err = httpClient.WalletRescanGet()
+		if err != nil {
+			die("Could not get rescan progress:", err)
+		}
+		fmt.Printf("\rScanned to height %v of %v", wrg.ScannedHeight, wrg.TargetHeight)
+		if !wrg.Rescanning {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	fmt.Println()
+	if wrg.Error != "" {
+		die("Rescan failed:", wrg.Error)
+	}
+	fmt.Printf("Rescan complete, searching %v addresses beyond the addresses in use\n", wrg.Lookahead)
+}
+
+// walletmultisigaddresscmd creates a multisig address.
+func walletmultisigaddresscmd(required, publicKeys string) {
+	n, err := strconv.ParseUint(required, 10, 64)
+	if err != nil {
+		die("Could not parse the number of required signatures:", err)
+	}
+	var pks []types.SiaPublicKey
+	for _, s := range strings.Split(publicKeys, ",") {
+		var pk types.SiaPublicKey
+		if err := pk.LoadString(strings.TrimSpace(s)); err != nil {
+			die("Could not parse public key:", s)
+		}
+		pks = append(pks, pk)
+	}
+	wmap, err := httpClient.WalletMultisigAddressPost(n, pks, walletMultisigUnused)
+	if err != nil {
+		die("Could not create multisig address:", err)
+	}
+	fmt.Printf("Created %v-of-%v multisig address %v\n", n, len(pks), wmap.Address)
+}
+
+// walletmultisigsendcmd creates an unsigned transaction spending from a
+// multisig address.
+func walletmultisigsendcmd(addr, amount, dest string) {
+	var from, to types.UnlockHash
+	if err := from.LoadString(addr); err != nil {
+		die("Failed to parse multisig address", err)
+	}
+	if err := to.LoadString(dest); err != nil {
+		die("Failed to parse destination address", err)
+	}
+	hastings, err := types.ParseCurrency(amount)
+	if err != nil {
+		die("Could not parse amount:", err)
+	}
+	var value types.Currency
+	if _, err := fmt.Sscan(hastings, &value); err != nil {
+		die("Failed to parse amount", err)
+	}
+	wmp, err := httpClient.WalletMultisigTransactionPost(from, []types.SiacoinOutput{{Value: value, UnlockHash: to}})
+	if err != nil {
+		die("Could not create multisig transaction:", err)
+	}
+	printMultisigTxn(wmp.Transaction)
+}
+
+// walletmultisigsigncmd adds the wallet's signatures to a multisig
+// transaction.
+func walletmultisigsigncmd(txnStr string) {
+	txn, err := parseTxn(txnStr)
+	if err != nil {
+		die("Could not decode transaction:", err)
+	}
+	wmp, err := httpClient.WalletMultisigSignPost(txn)
+	if err != nil {
+		die("Could not sign multisig transaction:", err)
+	}
+	printMultisigTxn(wmp.Transaction)
+}
+
+// walletmultisigmergecmd merges the signatures of copies of a multisig
+// transaction.
+func walletmultisigmergecmd(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		_ = cmd.UsageFunc()(cmd)
+		os.Exit(exitCodeUsage)
+	}
+	txns := make([]types.Transaction, 0, len(args))
+	for _, arg := range args {
+		txn, err := parseTxn(arg)
+		if err != nil {
+			die("Could not decode transaction:", err)
+		}
+		txns = append(txns, txn)
+	}
+	txn, err := modules.MergeTransactionSignatures(txns)
+	if err != nil {
+		die("Could not merge signatures:", err)
+	}
+	printMultisigTxn(txn)
+}
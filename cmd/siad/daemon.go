@@ -16,6 +16,7 @@ import (
 
 	"go.sia.tech/siad/build"
 	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/consensus"
 	"go.sia.tech/siad/node/api/server"
 	"go.sia.tech/siad/profile"
 )
@@ -52,6 +53,23 @@ func verifyAPISecurity(config Config) error {
 	return nil
 }
 
+// verifyBootstrapCheckpoint checks that a snapshot downloaded from the
+// bootstrap mirrors can be verified, either against the checkpoint provided by
+// the operator or a checkpoint embedded in the binary.
+func verifyBootstrapCheckpoint(config Config) error {
+	if config.Siad.BootstrapCheckpoint != "" {
+		_, err := consensus.ParseBootstrapCheckpoint(config.Siad.BootstrapCheckpoint)
+		return errors.AddContext(err, "unable to parse --bootstrap-checkpoint")
+	}
+	if config.Siad.BootstrapMirrors == "" {
+		return nil
+	}
+	if _, err := consensus.LatestBootstrapCheckpoint(); err != nil {
+		return errors.AddContext(err, "--bootstrap-mirrors requires --bootstrap-checkpoint")
+	}
+	return nil
+}
+
 // processNetAddr adds a ':' to a bare integer, so that it is a proper port
 // number.
 func processNetAddr(addr string) string {
@@ -117,7 +135,8 @@ func processConfig(config Config) (Config, error) {
 		config.Siad.Profile, err2 = profile.ProcessProfileFlags(config.Siad.Profile)
 	}
 	err3 := verifyAPISecurity(config)
-	err := build.JoinErrors([]error{err1, err2, err3}, ", and ")
+	err4 := verifyBootstrapCheckpoint(config)
+	err := build.JoinErrors([]error{err1, err2, err3, err4}, ", and ")
 	if err != nil {
 		return Config{}, err
 	}
@@ -210,6 +229,8 @@ func startDaemon(config Config) (err error) {
 	nodeParams := parseModules(config)
 	// set the wallet password from the environment variable
 	nodeParams.WalletPassword = build.WalletPassword()
+	// set the Tor control password from the environment variable
+	nodeParams.TorPassword = build.TorControlPassword()
 
 	// Start and run the server.
 	srv, err := server.New(config.Siad.APIaddr, config.Siad.RequiredUserAgent, config.APIPassword, nodeParams, loadStart)
@@ -244,6 +265,30 @@ func startDaemon(config Config) (err error) {
 	return nil
 }
 
+// verifyConsensus checks the consensus database for inconsistencies without
+// starting siad.
+func verifyConsensus(config Config) error {
+	fmt.Println("Verifying the consensus database, this can take a while...")
+	report, err := consensus.VerifyDatabase(filepath.Join(config.Siad.SiaDir, modules.ConsensusDir), config.Siad.RepairConsensus)
+	if err != nil {
+		return errors.AddContext(err, "failed to verify consensus database")
+	}
+	fmt.Printf("Checked %v blocks up to height %v.\n", report.BlocksChecked, report.Height)
+	for _, inconsistency := range report.Inconsistencies {
+		fmt.Println("Inconsistency:", inconsistency)
+	}
+	for _, repair := range report.Repairs {
+		fmt.Println("Repaired:", repair)
+	}
+	if report.NeedsResync {
+		return errors.New("the consensus database can't be repaired in place, delete it to resync the blockchain")
+	}
+	if len(report.Inconsistencies) == 0 {
+		fmt.Println("No inconsistencies found.")
+	}
+	return nil
+}
+
 // startDaemonCmd is a passthrough function for startDaemon.
 func startDaemonCmd(cmd *cobra.Command, _ []string) {
 	// Process the config variables after they are parsed by cobra.
@@ -252,6 +297,14 @@ func startDaemonCmd(cmd *cobra.Command, _ []string) {
 		die(errors.AddContext(err, "failed to parse input parameter"))
 	}
 
+	// Verify the consensus database instead of starting siad if requested.
+	if config.Siad.VerifyConsensus {
+		if err := verifyConsensus(config); err != nil {
+			die(err)
+		}
+		return
+	}
+
 	// Parse profile flags
 	profileCPU := strings.Contains(config.Siad.Profile, "c")
 	profileMem := strings.Contains(config.Siad.Profile, "m")
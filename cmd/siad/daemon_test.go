@@ -2,6 +2,8 @@ package main
 
 import (
 	"testing"
+
+	"go.sia.tech/siad/modules/consensus"
 )
 
 // TestUnitProcessNetAddr probes the 'processNetAddr' function.
@@ -207,3 +209,25 @@ func TestVerifyAPISecurity(t *testing.T) {
 		t.Error("public + securityOff with authentication was rejected:", err)
 	}
 }
+
+// TestVerifyBootstrapCheckpoint checks that --bootstrap-mirrors requires a
+// valid --bootstrap-checkpoint if the binary doesn't embed a checkpoint.
+func TestVerifyBootstrapCheckpoint(t *testing.T) {
+	var config Config
+	if err := verifyBootstrapCheckpoint(config); err != nil {
+		t.Error("config without bootstrapping was rejected:", err)
+	}
+	config.Siad.BootstrapMirrors = "https://example.com"
+	if err := verifyBootstrapCheckpoint(config); err == nil {
+		t.Error("mirrors without a checkpoint were accepted")
+	}
+	config.Siad.BootstrapCheckpoint = "300000:foo"
+	if err := verifyBootstrapCheckpoint(config); err == nil {
+		t.Error("invalid checkpoint was accepted")
+	}
+	cp := consensus.BootstrapCheckpoint{Height: 300000, Size: 1 << 30}
+	config.Siad.BootstrapCheckpoint = cp.String()
+	if err := verifyBootstrapCheckpoint(config); err != nil {
+		t.Error("valid checkpoint was rejected:", err)
+	}
+}
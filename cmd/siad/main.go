@@ -37,14 +37,25 @@ type Config struct {
 		HostAddr      string
 		SiaMuxTCPAddr string
 		SiaMuxWSAddr  string
+		StratumAddr   string
 		AllowAPIBind  bool
 
-		Modules           string
-		NoBootstrap       bool
-		UseUPNP           bool
-		RequiredUserAgent string
-		AuthenticateAPI   bool
-		TempPassword      bool
+		Modules             string
+		NoBootstrap         bool
+		BootstrapMirrors    string
+		BootstrapCheckpoint string
+		ReorgAlertDepth     uint64
+		MaxReorgDepth       uint64
+		TPoolMaxSize        uint64
+		VerifyConsensus     bool
+		RepairConsensus     bool
+		UseUPNP             bool
+		Proxy               string
+		ProxyOnion          bool
+		TorControl          string
+		RequiredUserAgent   string
+		AuthenticateAPI     bool
+		TempPassword        bool
 
 		Profile    string
 		ProfileDir string
@@ -195,11 +206,22 @@ func main() {
 	root.Flags().StringVarP(&globalConfig.Siad.APIaddr, "api-addr", "", defaultAPIAddr, "which host:port the API server listens on")
 	root.Flags().StringVarP(&globalConfig.Siad.SiaDir, "sia-directory", "d", "", "location of the sia directory")
 	root.Flags().BoolVarP(&globalConfig.Siad.NoBootstrap, "no-bootstrap", "", false, "disable bootstrapping on this run")
-	root.Flags().BoolVarP(&globalConfig.Siad.UseUPNP, "upnp", "", true, "use UPnP for port forwarding and external IP discovery")
+	root.Flags().StringVarP(&globalConfig.Siad.BootstrapMirrors, "bootstrap-mirrors", "", "", "comma-separated URLs of mirrors to download a verified consensus snapshot from on first start")
+	root.Flags().StringVarP(&globalConfig.Siad.BootstrapCheckpoint, "bootstrap-checkpoint", "", "", "height:blockid:hash:size of the snapshot to download from --bootstrap-mirrors, required if the binary doesn't embed a checkpoint")
+	root.Flags().Uint64VarP(&globalConfig.Siad.ReorgAlertDepth, "reorg-alert-depth", "", 0, "register an alert for reorgs of at least this many blocks (default 6)")
+	root.Flags().Uint64VarP(&globalConfig.Siad.MaxReorgDepth, "max-reorg-depth", "", 0, "refuse reorgs deeper than this many blocks until they are confirmed, 0 to disable")
+	root.Flags().Uint64VarP(&globalConfig.Siad.TPoolMaxSize, "tpool-max-size", "", 0, "maximum size of the transaction pool in bytes, low fee transactions are evicted beyond it (default 20 MB)")
+	root.Flags().BoolVarP(&globalConfig.Siad.VerifyConsensus, "verify-consensus", "", false, "check the consensus database for inconsistencies and exit")
+	root.Flags().BoolVarP(&globalConfig.Siad.RepairConsensus, "repair-consensus", "", false, "repair the inconsistencies found by --verify-consensus which can be fixed without resyncing")
+	root.Flags().BoolVarP(&globalConfig.Siad.UseUPNP, "upnp", "", true, "use UPnP, PCP and NAT-PMP for port forwarding and UPnP for external IP discovery")
+	root.Flags().StringVarP(&globalConfig.Siad.Proxy, "proxy", "", "", "host:port of a SOCKS5 proxy, e.g. Tor, for all outbound gateway connections")
+	root.Flags().BoolVarP(&globalConfig.Siad.ProxyOnion, "onion", "", false, "listen as a Tor onion service, requires --proxy")
+	root.Flags().StringVarP(&globalConfig.Siad.TorControl, "tor-control", "", "127.0.0.1:9051", "host:port of the Tor control port used by --onion")
 	root.Flags().StringVarP(&globalConfig.Siad.Profile, "profile", "", "", "enable profiling with flags 'cmt' for CPU, memory, trace")
 	root.Flags().StringVarP(&globalConfig.Siad.RPCaddr, "rpc-addr", "", defaultRPCAddr, "which port the gateway listens on")
 	root.Flags().StringVarP(&globalConfig.Siad.SiaMuxTCPAddr, "siamux-addr", "", defaultRHP3TCPAddr, "which port the SiaMux listens on")
 	root.Flags().StringVarP(&globalConfig.Siad.SiaMuxWSAddr, "siamux-addr-ws", "", defaultRHP3WSAddr, "which port the SiaMux websocket listens on")
+	root.Flags().StringVarP(&globalConfig.Siad.StratumAddr, "stratum-addr", "", "", "which port the stratum server of the miner listens on, disabled if empty")
 	root.Flags().StringVarP(&globalConfig.Siad.Modules, "modules", "M", "gctwrhfa", "enabled modules, see 'siad modules' for more info")
 	root.Flags().BoolVarP(&globalConfig.Siad.AuthenticateAPI, "authenticate-api", "", true, "enable API password protection")
 	root.Flags().BoolVarP(&globalConfig.Siad.TempPassword, "temp-password", "", false, "enter a temporary API password during startup")
@@ -3,7 +3,9 @@ package main
 import (
 	"strings"
 
+	"go.sia.tech/siad/modules/consensus"
 	"go.sia.tech/siad/node"
+	"go.sia.tech/siad/types"
 )
 
 // createNodeParams parses the provided config and creates the corresponding
@@ -43,11 +45,27 @@ func parseModules(config Config) node.NodeParams {
 	}
 	// Parse remaining fields.
 	params.Bootstrap = !config.Siad.NoBootstrap
+	for _, mirror := range strings.Split(config.Siad.BootstrapMirrors, ",") {
+		if mirror = strings.TrimSpace(mirror); mirror != "" {
+			params.BootstrapMirrors = append(params.BootstrapMirrors, mirror)
+		}
+	}
+	if config.Siad.BootstrapCheckpoint != "" {
+		// The checkpoint was validated by processConfig.
+		params.BootstrapCheckpoint, _ = consensus.ParseBootstrapCheckpoint(config.Siad.BootstrapCheckpoint)
+	}
+	params.ReorgAlertDepth = types.BlockHeight(config.Siad.ReorgAlertDepth)
+	params.MaxReorgDepth = types.BlockHeight(config.Siad.MaxReorgDepth)
+	params.TPoolMaxSize = config.Siad.TPoolMaxSize
 	params.UseUPNP = config.Siad.UseUPNP
+	params.Proxy = config.Siad.Proxy
+	params.ProxyOnion = config.Siad.ProxyOnion
+	params.TorControl = config.Siad.TorControl
 	params.HostAddress = config.Siad.HostAddr
 	params.RPCAddress = config.Siad.RPCaddr
 	params.SiaMuxTCPAddress = config.Siad.SiaMuxTCPAddr
 	params.SiaMuxWSAddress = config.Siad.SiaMuxWSAddr
+	params.StratumAddress = config.Siad.StratumAddr
 	params.Dir = config.Siad.SiaDir
 	return params
 }
@@ -54,6 +54,12 @@ type (
 		// ciphertext.
 		EncryptBytes([]byte) Ciphertext
 
+		// EncryptBytesInPlace encrypts the given plaintext and returns the
+		// ciphertext. It will reuse the memory of the plaintext if the
+		// cipher doesn't add any overhead which means that it's not safe to
+		// use the plaintext after calling EncryptBytesInPlace.
+		EncryptBytesInPlace([]byte) Ciphertext
+
 		// DecryptBytes decrypts the given ciphertext and returns the
 		// plaintext.
 		DecryptBytes(Ciphertext) ([]byte, error)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"gitlab.com/NebulousLabs/fastrand"
 	"golang.org/x/crypto/chacha20poly1305"
 )
 
@@ -19,6 +20,44 @@ func TestCipherTypeStringConversion(t *testing.T) {
 	}
 }
 
+// TestEncryptBytesInPlace checks that EncryptBytesInPlace produces the same
+// ciphertext as EncryptBytes and that it reuses the plaintext's memory for
+// ciphers without overhead.
+func TestEncryptBytesInPlace(t *testing.T) {
+	tests := []struct {
+		ct      CipherType
+		inPlace bool
+	}{
+		{TypePlain, true},
+		{TypeThreefish, true},
+		{TypeTwofish, false},
+		{TypeXChaCha20, true},
+	}
+	for _, test := range tests {
+		key := GenerateSiaKey(test.ct)
+		data := fastrand.Bytes(4096)
+		plaintext := append([]byte{}, data...)
+		expected := key.EncryptBytes(data)
+		ciphertext := key.EncryptBytesInPlace(plaintext)
+
+		// Twofish uses a random nonce which is why the ciphertexts only match
+		// for the other ciphers.
+		if test.ct != TypeTwofish && !bytes.Equal(ciphertext, expected) {
+			t.Fatalf("%v: ciphertexts don't match", test.ct)
+		}
+		if inPlace := &ciphertext[0] == &plaintext[0]; inPlace != test.inPlace {
+			t.Fatalf("%v: expected in-place to be %v but was %v", test.ct, test.inPlace, inPlace)
+		}
+		decrypted, err := key.DecryptBytes(ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, data) {
+			t.Fatalf("%v: decrypted data doesn't match plaintext", test.ct)
+		}
+	}
+}
+
 // BenchmarkChaCha20Poly1305 benchmarks the speed of ChaCha20-Poly1305 AEAD
 // encryption and decryption.
 func BenchmarkChaCha20Poly1305(b *testing.B) {
@@ -36,3 +36,8 @@ func (p plainTextCipherKey) Derive(_, _ uint64) CipherKey {
 func (plainTextCipherKey) EncryptBytes(piece []byte) Ciphertext {
 	return Ciphertext(piece)
 }
+
+// EncryptBytesInPlace is a no-op for the plainTextCipherKey.
+func (plainTextCipherKey) EncryptBytesInPlace(piece []byte) Ciphertext {
+	return Ciphertext(piece)
+}
@@ -133,6 +133,22 @@ func (key threefishKey) Derive(chunkIndex, pieceIndex uint64) CipherKey {
 // EncryptBytes encrypts arbitrary data using the ThreefishKey and using a
 // different tweak for every 64 byte block.
 func (key threefishKey) EncryptBytes(piece []byte) Ciphertext {
+	ciphertext := make([]byte, len(piece))
+	key.encryptBytes(ciphertext, piece)
+	return ciphertext
+}
+
+// EncryptBytesInPlace encrypts arbitrary data using the ThreefishKey. It
+// reuses the memory of piece which means that piece can't be used after
+// calling EncryptBytesInPlace.
+func (key threefishKey) EncryptBytesInPlace(piece []byte) Ciphertext {
+	key.encryptBytes(piece, piece)
+	return piece
+}
+
+// encryptBytes encrypts piece into dst one block at a time, using a different
+// tweak for every block. dst and piece may overlap entirely.
+func (key threefishKey) encryptBytes(dst, piece []byte) {
 	// Sanity check piece length.
 	if len(piece)%threefish.BlockSize != 0 {
 		panic("piece must be multiple of threefish.BlockSize")
@@ -141,13 +157,11 @@ func (key threefishKey) EncryptBytes(piece []byte) Ciphertext {
 	// Create the cipher.
 	cipher := key.newCipher()
 
-	// Create the initial tweak and ciphertext slice.
+	// Create the initial tweak.
 	tweak := make([]byte, threefish.TweakSize)
-	ciphertext := make([]byte, len(piece))
 
 	// Encrypt the piece one block at a time while incrementing the tweak.
 	buf := bytes.NewBuffer(piece)
-	dst := ciphertext
 	for block := buf.Next(threefish.BlockSize); len(block) > 0; block = buf.Next(threefish.BlockSize) {
 		// Encrypt the block.
 		cipher.Encrypt(dst, block)
@@ -162,7 +176,6 @@ func (key threefishKey) EncryptBytes(piece []byte) Ciphertext {
 		// Adjust the dst.
 		dst = dst[threefish.BlockSize:]
 	}
-	return ciphertext
 }
 
 // Key returns the threefish key.
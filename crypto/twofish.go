@@ -114,6 +114,13 @@ func (key twofishKey) EncryptBytes(piece []byte) Ciphertext {
 	return EncryptWithNonce(piece, aead)
 }
 
+// EncryptBytesInPlace encrypts arbitrary data using the TwofishKey. Since
+// Twofish-GCM adds a nonce and an authentication tag to the ciphertext, it
+// can't reuse the memory of piece and behaves like EncryptBytes.
+func (key twofishKey) EncryptBytesInPlace(piece []byte) Ciphertext {
+	return key.EncryptBytes(piece)
+}
+
 // Key returns the twofish key.
 func (key twofishKey) Key() []byte {
 	return key[:]
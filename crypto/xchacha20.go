@@ -118,3 +118,13 @@ func (cipher xChaCha20CipherKey) EncryptBytes(plaintext []byte) Ciphertext {
 	cipher.XORKeyStream(ciphertext, plaintext)
 	return ciphertext
 }
+
+// EncryptBytesInPlace encrypts arbitrary data using the XChaCha20 key. It
+// reuses the memory of plaintext which means that plaintext can't be used
+// after calling EncryptBytesInPlace.
+func (cipher xChaCha20CipherKey) EncryptBytesInPlace(plaintext []byte) Ciphertext {
+	defer cipher.SetCounter(0) // Reset the cipher key stream.
+
+	cipher.XORKeyStream(plaintext, plaintext)
+	return plaintext
+}
@@ -52,10 +52,54 @@ const (
 	// AlertIDHostDiskTrouble is the id of the alert that is registered when the
 	// host is encountering problems interacting with one or more of his disks
 	AlertIDHostDiskTrouble = "host-disk-trouble"
+	// AlertIDHostCorruptSectors is the id of the alert that is registered
+	// when the host's integrity scrubber quarantined corrupt sectors and
+	// unregistered once none are left
+	AlertIDHostCorruptSectors = "host-corrupt-sectors"
+	// AlertIDHostDiskDegraded is the id of the alert that is registered when
+	// the disk health probe raised warnings for one or more storage folders
+	// and unregistered once all storage folders are healthy again
+	AlertIDHostDiskDegraded = "host-disk-degraded"
 	// AlertIDHostInsufficientCollateral is the id of the alert that is
 	// registered if the host has insufficient collateral budget left to form or
 	// renew a contract
 	AlertIDHostInsufficientCollateral = "host-insufficient-collateral"
+	// AlertIDHostMissedStorageProof is the id of the alert that is registered
+	// if the host missed the proof window of a contract and unregistered once
+	// the host submits a storage proof again
+	AlertIDHostMissedStorageProof = "host-missed-storage-proof"
+	// AlertIDHostLowBalance is the id of the alert that is registered if the
+	// host's wallet balance can't cover the collateral of a new contract
+	AlertIDHostLowBalance = "host-low-balance"
+	// AlertIDHostStorageFolderWriteFailures is the id of the alert that is
+	// registered if writes to one or more storage folders failed since the
+	// host's last check
+	AlertIDHostStorageFolderWriteFailures = "host-storage-folder-write-failures"
+	// AlertIDHostClockSkew is the id of the alert that is registered if the
+	// timestamp of the latest block is too far ahead of the host's clock
+	AlertIDHostClockSkew = "host-clock-skew"
+	// AlertIDRenterRestoreDrillFailed is the id of the alert that is
+	// registered if a restore drill failed and unregistered once a drill
+	// succeeds again
+	AlertIDRenterRestoreDrillFailed = "restore-drill-failed"
+	// AlertIDRenterScheduledBackupFailed is the id of the alert that is
+	// registered if a scheduled backup failed and unregistered once a
+	// scheduled backup succeeds again
+	AlertIDRenterScheduledBackupFailed = "scheduled-backup-failed"
+	// AlertIDConsensusNetworkPartition is the id of the alert that is
+	// registered if the chain stalled while peers relay blocks the node
+	// doesn't have.
+	AlertIDConsensusNetworkPartition = "network-partition"
+	// AlertIDConsensusEclipse is the id of the alert that is registered if
+	// all remote peers of the node are in the same subnet.
+	AlertIDConsensusEclipse = "network-eclipse"
+	// AlertIDConsensusDeepReorg is the id of the alert that is registered if
+	// a reorg at least as deep as the configured alert depth occurred.
+	AlertIDConsensusDeepReorg = "deep-reorg"
+	// AlertIDConsensusReorgRefused is the id of the alert that is registered
+	// if a reorg deeper than the configured maximum depth was refused and
+	// awaits confirmation.
+	AlertIDConsensusReorgRefused = "reorg-refused"
 )
 
 // AlertIDSiafileLowRedundancy uses a Siafile's UID to create a unique AlertID
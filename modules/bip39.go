@@ -0,0 +1,126 @@
+package modules
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gitlab.com/NebulousLabs/entropy-mnemonics"
+
+	"go.sia.tech/siad/crypto"
+)
+
+// BIP39Dictionary is the dictionary ID of seeds which are encoded as BIP39
+// mnemonics using the English word list. A seed is encoded as a 24 word
+// mnemonic of its entropy. Seeds can also be decoded from 12 word mnemonics
+// by hashing their entropy, which is compatible with go.sia.tech/core.
+const BIP39Dictionary mnemonics.DictionaryID = "bip39"
+
+var (
+	// errBIP39Checksum is returned if the checksum of a BIP39 mnemonic
+	// doesn't match its entropy.
+	errBIP39Checksum = errors.New("seed failed checksum verification")
+
+	// errBIP39Length is returned if a BIP39 mnemonic doesn't consist of 12 or
+	// 24 words.
+	errBIP39Length = errors.New("seed is not valid: BIP39 mnemonics must be 12 or 24 words")
+
+	// bip39WordIndex maps the words of the BIP39 word list to their index.
+	bip39WordIndex = func() map[string]uint16 {
+		m := make(map[string]uint16, len(bip39EnglishWordList))
+		for i, word := range bip39EnglishWordList {
+			m[word] = uint16(i)
+		}
+		return m
+	}()
+)
+
+// bip39Bit returns the i-th bit of b, starting from the most significant bit of
+// the first byte.
+func bip39Bit(b []byte, i int) byte {
+	return (b[i/8] >> (7 - uint(i%8))) & 1
+}
+
+// bip39Encode encodes entropy as a BIP39 mnemonic. The size of the entropy must
+// be a multiple of 4 bytes.
+func bip39Encode(entropy []byte) string {
+	checksum := sha256.Sum256(entropy)
+	bits := append(append([]byte(nil), entropy...), checksum[:]...)
+	numWords := (len(entropy)*8 + len(entropy)/4) / 11
+	words := make([]string, numWords)
+	for i := range words {
+		var index uint16
+		for j := 0; j < 11; j++ {
+			index = index<<1 | uint16(bip39Bit(bits, i*11+j))
+		}
+		words[i] = bip39EnglishWordList[index]
+	}
+	return strings.Join(words, " ")
+}
+
+// bip39Decode decodes the entropy of a BIP39 mnemonic and verifies its
+// checksum.
+func bip39Decode(phrase string) ([]byte, error) {
+	words := strings.Fields(phrase)
+	if len(words) == 0 || len(words)%3 != 0 || len(words) > 24 {
+		return nil, errBIP39Length
+	}
+	bits := make([]byte, (len(words)*11+7)/8)
+	for i, word := range words {
+		index, exists := bip39WordIndex[word]
+		if !exists {
+			return nil, fmt.Errorf("seed is not valid: unrecognized word '%v'", word)
+		}
+		for j := 0; j < 11; j++ {
+			if (index>>(10-uint(j)))&1 == 1 {
+				pos := i*11 + j
+				bits[pos/8] |= 1 << (7 - uint(pos%8))
+			}
+		}
+	}
+
+	// Every 32 bits of entropy are followed by one bit of checksum.
+	checksumBits := len(words) * 11 / 33
+	entropy := append([]byte(nil), bits[:(len(words)*11-checksumBits)/8]...)
+	checksum := sha256.Sum256(entropy)
+	for i := 0; i < checksumBits; i++ {
+		if bip39Bit(bits, len(entropy)*8+i) != bip39Bit(checksum[:], i) {
+			return nil, errBIP39Checksum
+		}
+	}
+	return entropy, nil
+}
+
+// IsBIP39Phrase returns true if the phrase has the number of words of a BIP39
+// mnemonic which can be decoded into a seed. Sia seed phrases have 28 or 29
+// words, so the two can be told apart.
+func IsBIP39Phrase(phrase string) bool {
+	n := len(strings.Fields(phrase))
+	return n == 12 || n == 24
+}
+
+// SeedToBIP39 encodes a seed as a 24 word BIP39 mnemonic.
+func SeedToBIP39(seed Seed) string {
+	return bip39Encode(seed[:])
+}
+
+// BIP39ToSeed decodes a seed from a BIP39 mnemonic. The entropy of a 24 word
+// mnemonic is the seed, while the seed of a 12 word mnemonic is the hash of
+// its entropy.
+func BIP39ToSeed(phrase string) (Seed, error) {
+	entropy, err := bip39Decode(phrase)
+	if err != nil {
+		return Seed{}, err
+	}
+	var seed Seed
+	switch len(entropy) {
+	case crypto.EntropySize:
+		copy(seed[:], entropy)
+	case 16:
+		seed = Seed(crypto.HashBytes(entropy))
+	default:
+		return Seed{}, errBIP39Length
+	}
+	return seed, nil
+}
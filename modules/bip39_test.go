@@ -0,0 +1,98 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+)
+
+// TestBIP39Vectors checks the BIP39 encoding against the test vectors of the
+// specification.
+func TestBIP39Vectors(t *testing.T) {
+	t.Parallel()
+
+	vectors := []struct {
+		entropy string
+		phrase  string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		},
+		{
+			"8080808080808080808080808080808080808080808080808080808080808080",
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic bless",
+		},
+		{
+			"f585c11aec520db57dd353c69554b21a89b20fb0650966fa0a9d6f74fd989d8f",
+			"void come effort suffer camp survey warrior heavy shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold",
+		},
+	}
+	for _, v := range vectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+		if phrase := bip39Encode(entropy); phrase != v.phrase {
+			t.Fatalf("wrong phrase for %v: %v", v.entropy, phrase)
+		}
+		decoded, err := bip39Decode(v.phrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, entropy) {
+			t.Fatalf("wrong entropy for %v: %x", v.phrase, decoded)
+		}
+	}
+}
+
+// TestBIP39Seed tests converting seeds to and from BIP39 mnemonics.
+func TestBIP39Seed(t *testing.T) {
+	t.Parallel()
+
+	seed := Seed(crypto.HashBytes([]byte("foo")))
+	phrase, err := SeedToString(seed, BIP39Dictionary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strings.Fields(phrase)) != 24 || !IsBIP39Phrase(phrase) {
+		t.Fatal("expected 24 words but got", phrase)
+	}
+	decoded, err := StringToSeed(phrase, BIP39Dictionary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != seed {
+		t.Fatal("seed doesn't match after decoding")
+	}
+
+	// The seed of a 12 word mnemonic is the hash of its entropy.
+	decoded, err = BIP39ToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != Seed(crypto.HashBytes(make([]byte, 16))) {
+		t.Fatal("wrong seed for 12 word mnemonic")
+	}
+
+	// Invalid mnemonics are rejected.
+	invalid := []string{
+		"",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon foo",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+	}
+	for _, phrase := range invalid {
+		if _, err := BIP39ToSeed(phrase); err == nil {
+			t.Fatalf("phrase '%v' should be invalid", phrase)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package modules
 import (
 	"errors"
 	"io"
+	"time"
 
 	"gitlab.com/NebulousLabs/encoding"
 	"go.sia.tech/siad/crypto"
@@ -185,6 +186,78 @@ type (
 		Adjusted  types.Currency
 	}
 
+	// NetworkHealth summarizes the consensus set's view of the network. The
+	// chain is stalled if no new block was found for several times the block
+	// frequency. The node is likely on a network partition if the chain is
+	// stalled while peers relay blocks above its height or blocks it can't
+	// connect to its chain, and it is likely eclipsed if all of its remote
+	// peers are in the same subnet.
+	NetworkHealth struct {
+		Height        types.BlockHeight `json:"height"`
+		LastBlockTime types.Timestamp   `json:"lastblocktime"`
+		Synced        bool              `json:"synced"`
+		Stalled       bool              `json:"stalled"`
+
+		Peers       int                `json:"peers"`
+		Subnets     int                `json:"subnets"`
+		PeersAhead  int                `json:"peersahead"`
+		PeerReports []PeerHeightReport `json:"peerreports"`
+
+		Eclipsed    bool `json:"eclipsed"`
+		Partitioned bool `json:"partitioned"`
+	}
+
+	// PeerHeightReport is the height of the most recent block a peer relayed
+	// to the node. If the block can't be connected to the node's chain, the
+	// block is an orphan and its height is unknown.
+	PeerHeightReport struct {
+		NetAddress NetAddress        `json:"netaddress"`
+		Height     types.BlockHeight `json:"height"`
+		Orphan     bool              `json:"orphan"`
+		Time       time.Time         `json:"time"`
+	}
+
+	// Reorg describes a reorg of the blockchain. The blocks above ForkHeight
+	// were reverted and replaced by the blocks of a heavier fork.
+	Reorg struct {
+		Depth      types.BlockHeight `json:"depth"`
+		ForkHeight types.BlockHeight `json:"forkheight"`
+		OldTip     types.BlockID     `json:"oldtip"`
+		NewTip     types.BlockID     `json:"newtip"`
+		Time       time.Time         `json:"time"`
+	}
+
+	// ReorgSettings configure how the consensus set treats deep reorgs. An
+	// alert is registered for reorgs of at least AlertDepth blocks. Reorgs
+	// of more than MaxDepth blocks are refused until they are confirmed by
+	// the operator, unless MaxDepth is 0.
+	ReorgSettings struct {
+		AlertDepth types.BlockHeight `json:"alertdepth"`
+		MaxDepth   types.BlockHeight `json:"maxdepth"`
+	}
+
+	// ReorgStatus contains the reorg settings, the most recent reorg since
+	// startup and the refused reorg which awaits confirmation.
+	ReorgStatus struct {
+		Settings     ReorgSettings `json:"settings"`
+		LastReorg    *Reorg        `json:"lastreorg"`
+		PendingReorg *Reorg        `json:"pendingreorg"`
+	}
+
+	// ConsensusVerifyReport is the result of checking the consensus database
+	// for inconsistencies. Inconsistencies in the current path or the
+	// consensus state can't be repaired in place, the database needs to be
+	// rebuilt by resyncing the blockchain in that case.
+	ConsensusVerifyReport struct {
+		Height          types.BlockHeight `json:"height"`
+		BlocksChecked   uint64            `json:"blockschecked"`
+		Inconsistencies []string          `json:"inconsistencies"`
+		Repairs         []string          `json:"repairs"`
+		// NeedsResync is true if the current path or the consensus state is
+		// inconsistent.
+		NeedsResync bool `json:"needsresync"`
+	}
+
 	// A ConsensusSet accepts blocks and builds an understanding of network
 	// consensus.
 	ConsensusSet interface {
@@ -206,6 +279,11 @@ type (
 		// a bool to indicate whether that block exists.
 		BlockByID(types.BlockID) (types.Block, types.BlockHeight, bool)
 
+		// ConfirmReorg switches to a refused fork whose reorg is deeper than
+		// the maximum reorg depth. The id must be the tip of the pending
+		// reorg.
+		ConfirmReorg(types.BlockID) error
+
 		// ChildTarget returns the target required to extend the current heaviest
 		// fork. This function is typically used by miners looking to extend the
 		// heaviest fork.
@@ -236,12 +314,23 @@ type (
 		// current path, false otherwise.
 		InCurrentPath(types.BlockID) bool
 
+		// NetworkHealth returns the consensus set's view of the health of the
+		// network.
+		NetworkHealth() (NetworkHealth, error)
+
 		// MinimumValidChildTimestamp returns the earliest timestamp that is
 		// valid on the current longest fork according to the consensus set. This is
 		// a required piece of information for the miner, who could otherwise be at
 		// risk of mining invalid blocks.
 		MinimumValidChildTimestamp(types.BlockID) (types.Timestamp, bool)
 
+		// ReorgStatus returns the reorg settings, the most recent reorg and
+		// the reorg which awaits confirmation.
+		ReorgStatus() (ReorgStatus, error)
+
+		// SetReorgSettings sets the reorg alert depth and maximum depth.
+		SetReorgSettings(ReorgSettings) error
+
 		// StorageProofSegment returns the segment to be used in the storage proof for
 		// a given file contract.
 		StorageProofSegment(types.FileContractID) (uint64, error)
@@ -260,6 +349,11 @@ type (
 		// allowing for garbage collection and rescanning. If the subscriber is
 		// not found in the subscriber database, no action is taken.
 		Unsubscribe(ConsensusSetSubscriber)
+
+		// Verify walks the consensus database and checks it for
+		// inconsistencies. If repair is true, the inconsistencies which can be
+		// fixed in place are repaired.
+		Verify(repair bool) (ConsensusVerifyReport, error)
 	}
 )
 
@@ -162,6 +162,12 @@ func (cs *ConsensusSet) addBlockToTree(tx *bolt.Tx, b types.Block, parent *proce
 		return changeEntry{}, modules.ErrNonExtendingBlock
 	}
 
+	// Reorgs deeper than the maximum depth need to be confirmed by the
+	// operator. Until then the fork is treated like a non-extending fork.
+	if cs.refuseReorg(tx, currentNode, newNode) {
+		return changeEntry{}, modules.ErrNonExtendingBlock
+	}
+
 	// Fork the blockchain and put the new heaviest block at the tip of the
 	// chain.
 	var revertedBlocks, appliedBlocks []*processedBlock
@@ -223,23 +229,24 @@ func (cs *ConsensusSet) threadedSleepOnFutureBlock(b types.Block) {
 // consecutive calls to AcceptBlock with each successive call accepting the
 // child block of the previous call.
 func (cs *ConsensusSet) managedAcceptBlocks(blocks []types.Block) (blockchainExtended bool, err error) {
-	// Grab a lock on the consensus set.
-	cs.mu.Lock()
-	defer cs.mu.Unlock()
-
 	// Make sure that blocks are consecutive. Though this isn't a strict
 	// requirement, if blocks are not consecutive then it becomes a lot harder
 	// to maintain correcetness when adding multiple blocks in a single tx.
 	//
-	// This is the first time that IDs on the blocks have been computed.
-	blockIDs := make([]types.BlockID, 0, len(blocks))
-	for i := 0; i < len(blocks); i++ {
-		blockIDs = append(blockIDs, blocks[i].ID())
-		if i > 0 && blocks[i].ParentID != blockIDs[i-1] {
+	// This is the first time that IDs on the blocks have been computed. They
+	// are computed in parallel before grabbing the lock, because they don't
+	// depend on the consensus state.
+	blockIDs := parallelBlockIDs(blocks)
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].ParentID != blockIDs[i-1] {
 			return false, errNonLinearChain
 		}
 	}
 
+	// Grab a lock on the consensus set.
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
 	// Verify the headers for every block, throw out known blocks, and the
 	// invalid blocks (which includes the children of invalid blocks).
 	chainExtended := false
@@ -313,7 +320,8 @@ func (cs *ConsensusSet) managedAcceptBlocks(blocks []types.Block) (blockchainExt
 	if !chainExtended {
 		return false, modules.ErrNonExtendingBlock
 	}
-	// Send any changes to subscribers.
+	// Record any reorgs and send the changes to subscribers.
+	cs.recordReorgs(changes)
 	for i := 0; i < len(changes); i++ {
 		cs.updateSubscribers(changes[i])
 	}
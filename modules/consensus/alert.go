@@ -6,5 +6,5 @@ import (
 
 // Alerts implements the Alerter interface for the consensusset.
 func (c *ConsensusSet) Alerts() (crit, err, warn, info []modules.Alert) {
-	return
+	return c.staticAlerter.Alerts()
 }
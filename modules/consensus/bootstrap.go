@@ -0,0 +1,245 @@
+package consensus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/NebulousLabs/bolt"
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// snapshotTempSuffix is the suffix of the file a snapshot is downloaded
+	// to before it is verified and moved into place.
+	snapshotTempSuffix = "_bootstrap_temp"
+
+	// snapshotHeaderTimeout is the time a mirror has to respond to a snapshot
+	// request. The download itself isn't limited, because snapshots of the
+	// full consensus database are large.
+	snapshotHeaderTimeout = time.Minute
+)
+
+var (
+	// errNoBootstrapCheckpoint is returned if the binary doesn't contain a
+	// checkpoint to verify a snapshot against.
+	errNoBootstrapCheckpoint = errors.New("no bootstrap checkpoint available in this release")
+
+	// errNoBootstrapMirrors is returned if no mirrors were provided to
+	// download a snapshot from.
+	errNoBootstrapMirrors = errors.New("no bootstrap mirrors provided")
+
+	// errInvalidBootstrapCheckpoint is returned if a checkpoint can't be
+	// parsed.
+	errInvalidBootstrapCheckpoint = errors.New("checkpoint must have the format height:blockid:hash:size")
+
+	// bootstrapCheckpoints are the checkpoints which consensus snapshots are
+	// verified against when the operator doesn't provide one. A snapshot is
+	// only compared against the hash of its checkpoint, so it is trusted only
+	// as far as the source of the checkpoint is: the release for a built-in
+	// checkpoint and the operator for one passed to siad. Checkpoints are
+	// added when a release is cut, the most recent one is used.
+	bootstrapCheckpoints = build.Select(build.Var{
+		Standard: []BootstrapCheckpoint{},
+		Testnet:  []BootstrapCheckpoint{},
+		Dev:      []BootstrapCheckpoint{},
+		Testing:  []BootstrapCheckpoint{},
+	}).([]BootstrapCheckpoint)
+)
+
+// BootstrapCheckpoint describes a consensus database snapshot which was taken
+// at a known height of the blockchain.
+type BootstrapCheckpoint struct {
+	Height  types.BlockHeight
+	BlockID types.BlockID
+	Hash    crypto.Hash
+	Size    int64
+}
+
+// filename returns the name of the snapshot file on the mirrors.
+func (cp BootstrapCheckpoint) filename() string {
+	return fmt.Sprintf("consensus-%d.db", cp.Height)
+}
+
+// String returns the checkpoint in the format parsed by
+// ParseBootstrapCheckpoint.
+func (cp BootstrapCheckpoint) String() string {
+	return fmt.Sprintf("%d:%v:%v:%d", cp.Height, cp.BlockID, cp.Hash, cp.Size)
+}
+
+// ParseBootstrapCheckpoint parses a checkpoint of the format
+// height:blockid:hash:size, which lets the operator provide the snapshot to
+// bootstrap from.
+func ParseBootstrapCheckpoint(s string) (cp BootstrapCheckpoint, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return BootstrapCheckpoint{}, errInvalidBootstrapCheckpoint
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return BootstrapCheckpoint{}, errors.Compose(errInvalidBootstrapCheckpoint, err)
+	}
+	cp.Height = types.BlockHeight(height)
+	if err := cp.BlockID.LoadString(parts[1]); err != nil {
+		return BootstrapCheckpoint{}, errors.Compose(errInvalidBootstrapCheckpoint, err)
+	}
+	if err := cp.Hash.LoadString(parts[2]); err != nil {
+		return BootstrapCheckpoint{}, errors.Compose(errInvalidBootstrapCheckpoint, err)
+	}
+	cp.Size, err = strconv.ParseInt(parts[3], 10, 64)
+	if err != nil || cp.Size <= 0 {
+		return BootstrapCheckpoint{}, errors.Compose(errInvalidBootstrapCheckpoint, err)
+	}
+	return cp, nil
+}
+
+// LatestBootstrapCheckpoint returns the most recent checkpoint embedded in the
+// binary.
+func LatestBootstrapCheckpoint() (BootstrapCheckpoint, error) {
+	return latestBootstrapCheckpoint(bootstrapCheckpoints)
+}
+
+// latestBootstrapCheckpoint returns the checkpoint with the greatest height.
+func latestBootstrapCheckpoint(checkpoints []BootstrapCheckpoint) (BootstrapCheckpoint, error) {
+	if len(checkpoints) == 0 {
+		return BootstrapCheckpoint{}, errNoBootstrapCheckpoint
+	}
+	latest := checkpoints[0]
+	for _, cp := range checkpoints[1:] {
+		if cp.Height > latest.Height {
+			latest = cp
+		}
+	}
+	return latest, nil
+}
+
+// downloadSnapshot downloads the snapshot of a checkpoint from a mirror to
+// the provided file and verifies its size and hash.
+func downloadSnapshot(mirror string, cp BootstrapCheckpoint, filename string) (err error) {
+	client := http.Client{
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ResponseHeaderTimeout: snapshotHeaderTimeout,
+		},
+	}
+	resp, err := client.Get(strings.TrimSuffix(mirror, "/") + "/" + cp.filename())
+	if err != nil {
+		return errors.AddContext(err, "unable to request snapshot")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mirror responded with status %v", resp.Status)
+	}
+
+	f, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.AddContext(err, "unable to create snapshot file")
+	}
+	defer func() {
+		err = errors.Compose(err, f.Close())
+	}()
+
+	// Read at most one byte more than expected to detect snapshots which are
+	// too large.
+	h := crypto.NewHash()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(resp.Body, cp.Size+1))
+	if err != nil {
+		return errors.AddContext(err, "unable to download snapshot")
+	}
+	if n != cp.Size {
+		return fmt.Errorf("snapshot has size %v, expected %v", n, cp.Size)
+	}
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	if hash != cp.Hash {
+		return fmt.Errorf("snapshot has hash %v, expected %v", hash, cp.Hash)
+	}
+	return f.Sync()
+}
+
+// verifySnapshot checks that a downloaded snapshot is a consensus database
+// whose current block is the block of the checkpoint.
+func verifySnapshot(cp BootstrapCheckpoint, filename string) (err error) {
+	db, err := persist.OpenDatabase(dbMetadata, filename)
+	if err != nil {
+		return errors.AddContext(err, "unable to open snapshot")
+	}
+	defer func() {
+		err = errors.Compose(err, db.Close())
+	}()
+	return db.View(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{BlockHeight, BlockPath, BlockMap} {
+			if tx.Bucket(bucket) == nil {
+				return fmt.Errorf("snapshot is missing the %s bucket", bucket)
+			}
+		}
+		if height := blockHeight(tx); height != cp.Height {
+			return fmt.Errorf("snapshot is at height %v, expected %v", height, cp.Height)
+		}
+		id, err := getPath(tx, cp.Height)
+		if err != nil {
+			return errors.AddContext(err, "unable to get checkpoint block")
+		}
+		if id != cp.BlockID {
+			return fmt.Errorf("snapshot has block %v at height %v, expected %v", id, cp.Height, cp.BlockID)
+		}
+		return nil
+	})
+}
+
+// bootstrapFromSnapshot downloads the snapshot of a checkpoint from the first
+// mirror that serves a valid copy and moves it into place as the consensus
+// database.
+func bootstrapFromSnapshot(persistDir string, mirrors []string, cp BootstrapCheckpoint) error {
+	if len(mirrors) == 0 {
+		return errNoBootstrapMirrors
+	}
+	if err := os.MkdirAll(persistDir, 0700); err != nil {
+		return err
+	}
+	dbFilename := filepath.Join(persistDir, DatabaseFilename)
+	tempFilename := dbFilename + snapshotTempSuffix
+	defer os.Remove(tempFilename)
+
+	var errs error
+	for _, mirror := range mirrors {
+		err := downloadSnapshot(mirror, cp, tempFilename)
+		if err == nil {
+			err = verifySnapshot(cp, tempFilename)
+		}
+		if err != nil {
+			errs = errors.Compose(errs, errors.AddContext(err, mirror))
+			continue
+		}
+		return os.Rename(tempFilename, dbFilename)
+	}
+	return errors.AddContext(errs, "no mirror provided a valid snapshot")
+}
+
+// BootstrapFromSnapshot initializes the consensus database in persistDir from
+// a snapshot downloaded from one of the mirrors. The snapshot is verified
+// against the checkpoint, so that only the blocks after the checkpoint need to
+// be synced from peers. If a consensus database already exists, it is left
+// untouched and false is returned.
+func BootstrapFromSnapshot(persistDir string, mirrors []string, cp BootstrapCheckpoint) (bool, error) {
+	_, err := os.Stat(filepath.Join(persistDir, DatabaseFilename))
+	if err == nil {
+		return false, nil
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+	if err := bootstrapFromSnapshot(persistDir, mirrors, cp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
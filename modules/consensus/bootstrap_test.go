@@ -0,0 +1,162 @@
+package consensus
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/gateway"
+	"go.sia.tech/siad/types"
+)
+
+// TestBootstrapFromSnapshot tests that a consensus database can be bootstrapped
+// from a snapshot which matches a checkpoint and that invalid snapshots are
+// rejected.
+func TestBootstrapFromSnapshot(t *testing.T) {
+	t.Parallel()
+	testdir := build.TempDir(modules.ConsensusDir, t.Name())
+
+	// Create a consensus database to serve as the snapshot.
+	g, err := gateway.New("localhost:0", false, filepath.Join(testdir, modules.GatewayDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	snapshotDir := filepath.Join(testdir, "snapshot")
+	cs, errChan := New(g, false, snapshotDir)
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	genesisID := cs.CurrentBlock().ID()
+	if err := cs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := ioutil.ReadFile(filepath.Join(snapshotDir, DatabaseFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp := BootstrapCheckpoint{
+		Height:  0,
+		BlockID: genesisID,
+		Hash:    crypto.HashBytes(snapshot),
+		Size:    int64(len(snapshot)),
+	}
+
+	// Create a mirror serving the snapshot and one serving a corrupted copy.
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/"+cp.filename() {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write(snapshot)
+	}))
+	defer good.Close()
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		corrupted := append([]byte(nil), snapshot...)
+		corrupted[len(corrupted)/2]++
+		w.Write(corrupted)
+	}))
+	defer bad.Close()
+
+	// Without mirrors or with only invalid mirrors, bootstrapping fails and
+	// no database is created.
+	bootstrapDir := filepath.Join(testdir, "bootstrap")
+	if err := bootstrapFromSnapshot(bootstrapDir, nil, cp); err != errNoBootstrapMirrors {
+		t.Fatal("expected errNoBootstrapMirrors but got", err)
+	}
+	if err := bootstrapFromSnapshot(bootstrapDir, []string{bad.URL}, cp); err == nil {
+		t.Fatal("corrupted snapshot was accepted")
+	}
+	if _, err := os.Stat(filepath.Join(bootstrapDir, DatabaseFilename)); !os.IsNotExist(err) {
+		t.Fatal("database was created from a corrupted snapshot", err)
+	}
+
+	// A snapshot for a different block is rejected even if its hash matches.
+	wrongBlock := cp
+	wrongBlock.BlockID = types.BlockID{1}
+	if err := bootstrapFromSnapshot(bootstrapDir, []string{good.URL}, wrongBlock); err == nil {
+		t.Fatal("snapshot with the wrong block was accepted")
+	}
+
+	// The first valid mirror is used. The checkpoint is provided the way an
+	// operator passes it to siad.
+	opCP, err := ParseBootstrapCheckpoint(cp.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := BootstrapFromSnapshot(bootstrapDir, []string{bad.URL, good.URL}, opCP); !ok || err != nil {
+		t.Fatal("bootstrapping failed", ok, err)
+	}
+	if _, err := os.Stat(filepath.Join(bootstrapDir, DatabaseFilename+snapshotTempSuffix)); !os.IsNotExist(err) {
+		t.Fatal("temporary snapshot wasn't removed", err)
+	}
+	cs, errChan = New(g, false, bootstrapDir)
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	if cs.CurrentBlock().ID() != genesisID {
+		t.Fatal("bootstrapped consensus set has the wrong current block")
+	}
+
+	// An existing database is left untouched.
+	if ok, err := BootstrapFromSnapshot(bootstrapDir, []string{good.URL}, cp); ok || err != nil {
+		t.Fatal("existing database shouldn't be replaced", ok, err)
+	}
+}
+
+// TestLatestBootstrapCheckpoint is a unit test for latestBootstrapCheckpoint.
+func TestLatestBootstrapCheckpoint(t *testing.T) {
+	t.Parallel()
+	if _, err := latestBootstrapCheckpoint(nil); err != errNoBootstrapCheckpoint {
+		t.Fatal("expected errNoBootstrapCheckpoint but got", err)
+	}
+	cp, err := latestBootstrapCheckpoint([]BootstrapCheckpoint{{Height: 5}, {Height: 10}, {Height: 7}})
+	if err != nil || cp.Height != 10 {
+		t.Fatal("wrong checkpoint", cp, err)
+	}
+}
+
+// TestParseBootstrapCheckpoint probes the parsing of checkpoints provided by
+// the operator.
+func TestParseBootstrapCheckpoint(t *testing.T) {
+	t.Parallel()
+	cp := BootstrapCheckpoint{
+		Height:  300000,
+		BlockID: types.BlockID{1, 2, 3},
+		Hash:    crypto.Hash{4, 5, 6},
+		Size:    1 << 30,
+	}
+	parsed, err := ParseBootstrapCheckpoint(cp.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != cp {
+		t.Fatal("wrong checkpoint", parsed, cp)
+	}
+
+	invalid := []string{
+		"",
+		"300000",
+		fmt.Sprintf("%v:%v:%v", cp.Height, cp.BlockID, cp.Hash),
+		fmt.Sprintf("x:%v:%v:%v", cp.BlockID, cp.Hash, cp.Size),
+		fmt.Sprintf("%vx:%v:%v:%v", cp.Height, cp.BlockID, cp.Hash, cp.Size),
+		fmt.Sprintf("%v:foo:%v:%v", cp.Height, cp.Hash, cp.Size),
+		fmt.Sprintf("%v:%v:foo:%v", cp.Height, cp.BlockID, cp.Size),
+		fmt.Sprintf("%v:%v:%v:0", cp.Height, cp.BlockID, cp.Hash),
+	}
+	for _, s := range invalid {
+		if _, err := ParseBootstrapCheckpoint(s); !errors.Contains(err, errInvalidBootstrapCheckpoint) {
+			t.Errorf("%q: expected errInvalidBootstrapCheckpoint but got %v", s, err)
+		}
+	}
+}
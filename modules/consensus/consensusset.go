@@ -86,13 +86,22 @@ type ConsensusSet struct {
 	blockRuleHelper blockRuleHelper
 	blockValidator  blockValidator
 
+	// staticNetworkHealth tracks the headers relayed by peers to detect
+	// network partitions.
+	staticNetworkHealth *networkHealth
+
+	// staticReorgs tracks the reorgs of the consensus set and refuses reorgs
+	// deeper than the maximum depth.
+	staticReorgs *reorgTracker
+
 	// Utilities
-	db         *persist.BoltDatabase
-	staticDeps modules.Dependencies
-	log        *persist.Logger
-	mu         demotemutex.DemoteMutex
-	persistDir string
-	tg         threadgroup.ThreadGroup
+	staticAlerter *modules.GenericAlerter
+	db            *persist.BoltDatabase
+	staticDeps    modules.Dependencies
+	log           *persist.Logger
+	mu            demotemutex.DemoteMutex
+	persistDir    string
+	tg            threadgroup.ThreadGroup
 }
 
 // consensusSetBlockingStartup handles the blocking portion of NewCustomConsensusSet.
@@ -119,8 +128,12 @@ func consensusSetBlockingStartup(gateway modules.Gateway, persistDir string, dep
 		blockRuleHelper: stdBlockRuleHelper{},
 		blockValidator:  NewBlockValidator(),
 
-		staticDeps: deps,
-		persistDir: persistDir,
+		staticNetworkHealth: newNetworkHealth(),
+		staticReorgs:        newReorgTracker(),
+
+		staticAlerter: modules.NewAlerter("consensus"),
+		staticDeps:    deps,
+		persistDir:    persistDir,
 	}
 	// Create the diffs for the genesis transaction outputs
 	for _, transaction := range types.GenesisBlock.Transactions {
@@ -188,6 +201,9 @@ func consensusSetAsyncStartup(cs *ConsensusSet, bootstrap bool) error {
 	cs.mu.Lock()
 	cs.synced = true
 	cs.mu.Unlock()
+
+	// Start monitoring the health of the network.
+	go cs.threadedMonitorNetworkHealth()
 	return nil
 }
 
@@ -79,7 +79,7 @@ func consensusChecksum(tx *bolt.Tx) crypto.Hash {
 
 // checkSiacoinCount checks that the number of siacoins countable within the
 // consensus set equal the expected number of siacoins for the block height.
-func checkSiacoinCount(tx *bolt.Tx) {
+func checkSiacoinCount(tx *bolt.Tx) error {
 	// Iterate through all the buckets looking for the delayed siacoin output
 	// buckets, and check that they are for the correct heights.
 	var dscoSiacoins types.Currency
@@ -90,22 +90,18 @@ func checkSiacoinCount(tx *bolt.Tx) {
 		}
 
 		// Sum up the delayed outputs in this bucket.
-		err := b.ForEach(func(_, delayedOutput []byte) error {
+		return b.ForEach(func(_, delayedOutput []byte) error {
 			var sco types.SiacoinOutput
 			err := encoding.Unmarshal(delayedOutput, &sco)
 			if err != nil {
-				manageErr(tx, err)
+				return err
 			}
 			dscoSiacoins = dscoSiacoins.Add(sco.Value)
 			return nil
 		})
-		if err != nil {
-			return err
-		}
-		return nil
 	})
 	if err != nil {
-		manageErr(tx, err)
+		return err
 	}
 
 	// Add all of the siacoin outputs.
@@ -114,13 +110,13 @@ func checkSiacoinCount(tx *bolt.Tx) {
 		var sco types.SiacoinOutput
 		err := encoding.Unmarshal(scoBytes, &sco)
 		if err != nil {
-			manageErr(tx, err)
+			return err
 		}
 		scoSiacoins = scoSiacoins.Add(sco.Value)
 		return nil
 	})
 	if err != nil {
-		manageErr(tx, err)
+		return err
 	}
 
 	// Add all of the payouts from file contracts.
@@ -129,7 +125,7 @@ func checkSiacoinCount(tx *bolt.Tx) {
 		var fc types.FileContract
 		err := encoding.Unmarshal(fcBytes, &fc)
 		if err != nil {
-			manageErr(tx, err)
+			return err
 		}
 		var fcCoins types.Currency
 		for _, output := range fc.ValidProofOutputs {
@@ -139,7 +135,7 @@ func checkSiacoinCount(tx *bolt.Tx) {
 		return nil
 	})
 	if err != nil {
-		manageErr(tx, err)
+		return err
 	}
 
 	// Add all of the siafund claims.
@@ -148,7 +144,7 @@ func checkSiacoinCount(tx *bolt.Tx) {
 		var sfo types.SiafundOutput
 		err := encoding.Unmarshal(sfoBytes, &sfo)
 		if err != nil {
-			manageErr(tx, err)
+			return err
 		}
 
 		coinsPerFund := getSiafundPool(tx).Sub(sfo.ClaimStart)
@@ -157,7 +153,7 @@ func checkSiacoinCount(tx *bolt.Tx) {
 		return nil
 	})
 	if err != nil {
-		manageErr(tx, err)
+		return err
 	}
 
 	expectedSiacoins := types.CalculateNumSiacoins(blockHeight(tx))
@@ -169,34 +165,36 @@ func checkSiacoinCount(tx *bolt.Tx) {
 		} else {
 			diagnostics += fmt.Sprintf("total: %v\nexpected: %v\n expected is bigger: %v", totalSiacoins, expectedSiacoins, totalSiacoins.Sub(expectedSiacoins))
 		}
-		manageErr(tx, errors.New(diagnostics))
+		return errors.New(diagnostics)
 	}
+	return nil
 }
 
 // checkSiafundCount checks that the number of siafunds countable within the
 // consensus set equal the expected number of siafunds for the block height.
-func checkSiafundCount(tx *bolt.Tx) {
+func checkSiafundCount(tx *bolt.Tx) error {
 	var total types.Currency
 	err := tx.Bucket(SiafundOutputs).ForEach(func(_, siafundOutputBytes []byte) error {
 		var sfo types.SiafundOutput
 		err := encoding.Unmarshal(siafundOutputBytes, &sfo)
 		if err != nil {
-			manageErr(tx, err)
+			return err
 		}
 		total = total.Add(sfo.Value)
 		return nil
 	})
 	if err != nil {
-		manageErr(tx, err)
+		return err
 	}
 	if !total.Equals(types.SiafundCount) {
-		manageErr(tx, errors.New("wrong number of siafunds in the consensus set"))
+		return errors.New("wrong number of siafunds in the consensus set")
 	}
+	return nil
 }
 
 // checkDSCOs scans the sets of delayed siacoin outputs and checks for
 // consistency.
-func checkDSCOs(tx *bolt.Tx) {
+func checkDSCOs(tx *bolt.Tx) error {
 	// Create a map to track which delayed siacoin output maps exist, and
 	// another map to track which ids have appeared in the dsco set.
 	dscoTracker := make(map[types.BlockHeight]struct{})
@@ -215,7 +213,7 @@ func checkDSCOs(tx *bolt.Tx) {
 		var height types.BlockHeight
 		err := encoding.Unmarshal(name[len(prefixDSCO):], &height)
 		if err != nil {
-			manageErr(tx, err)
+			return err
 		}
 		_, exists := dscoTracker[height]
 		if exists {
@@ -238,7 +236,7 @@ func checkDSCOs(tx *bolt.Tx) {
 			var sco types.SiacoinOutput
 			err := encoding.Unmarshal(delayedOutput, &sco)
 			if err != nil {
-				manageErr(tx, err)
+				return err
 			}
 			total = total.Add(sco.Value)
 			return nil
@@ -256,7 +254,7 @@ func checkDSCOs(tx *bolt.Tx) {
 		return nil
 	})
 	if err != nil {
-		manageErr(tx, err)
+		return err
 	}
 
 	// Check that all of the correct heights are represented.
@@ -268,13 +266,14 @@ func checkDSCOs(tx *bolt.Tx) {
 		}
 		_, exists := dscoTracker[i]
 		if !exists {
-			manageErr(tx, errors.New("missing a dsco bucket"))
+			return errors.New("missing a dsco bucket")
 		}
 		expectedBuckets++
 	}
 	if len(dscoTracker) != expectedBuckets {
-		manageErr(tx, errors.New("too many dsco buckets"))
+		return errors.New("too many dsco buckets")
 	}
+	return nil
 }
 
 // checkRevertApply reverts the most recent block, checking to see that the
@@ -319,9 +318,11 @@ func (cs *ConsensusSet) checkConsistency(tx *bolt.Tx) {
 	}
 
 	cs.checkingConsistency = true
-	checkDSCOs(tx)
-	checkSiacoinCount(tx)
-	checkSiafundCount(tx)
+	for _, check := range []func(*bolt.Tx) error{checkDSCOs, checkSiacoinCount, checkSiafundCount} {
+		if err := check(tx); err != nil {
+			manageErr(tx, err)
+		}
+	}
 	if build.DEBUG {
 		cs.checkRevertApply(tx)
 	}
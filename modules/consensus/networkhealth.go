@@ -0,0 +1,242 @@
+package consensus
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/bolt"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// networkStallBlocks is the number of block frequencies without a new
+	// block after which the chain is considered to be stalled.
+	networkStallBlocks = 6
+
+	// minEclipsePeers is the minimum number of remote peers for the eclipse
+	// check. With fewer peers, sharing a subnet is not unusual.
+	minEclipsePeers = 3
+
+	// partitionChecksBeforeAlert is the number of consecutive health checks
+	// that need to find the node partitioned before an alert is registered.
+	// A node that is only behind catches up between two checks.
+	partitionChecksBeforeAlert = 2
+
+	// eclipseSubnetIPv4 and eclipseSubnetIPv6 are the prefix lengths of the
+	// subnets that peers are grouped by for the eclipse check.
+	eclipseSubnetIPv4 = 16
+	eclipseSubnetIPv6 = 32
+)
+
+const (
+	// AlertMSGNetworkPartition indicates that the node might be on a network
+	// partition.
+	AlertMSGNetworkPartition = "no new blocks were received while peers relay blocks the node doesn't have, the node might be on a network partition"
+
+	// AlertMSGEclipse indicates that the node might be eclipsed.
+	AlertMSGEclipse = "all remote peers are in the same subnet, the node might be eclipsed"
+)
+
+var (
+	// networkHealthCheckInterval is how often the consensus set checks the
+	// health of the network.
+	networkHealthCheckInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: 5 * time.Minute,
+		Testnet:  5 * time.Minute,
+		Testing:  5 * time.Second,
+	}).(time.Duration)
+)
+
+type (
+	// networkHealth tracks the most recent block headers the peers relayed to
+	// the consensus set.
+	networkHealth struct {
+		reports map[modules.NetAddress]peerHeaderReport
+
+		// partitionChecks is the number of consecutive health checks that
+		// found the node partitioned.
+		partitionChecks int
+
+		mu sync.Mutex
+	}
+
+	// peerHeaderReport is a block header that was relayed by a peer. The ids
+	// are resolved to a height when the health is checked, so that reports of
+	// orphans become known once the consensus set caught up.
+	peerHeaderReport struct {
+		id       types.BlockID
+		parentID types.BlockID
+		time     time.Time
+	}
+)
+
+// newNetworkHealth creates an empty networkHealth.
+func newNetworkHealth() *networkHealth {
+	return &networkHealth{
+		reports: make(map[modules.NetAddress]peerHeaderReport),
+	}
+}
+
+// callReportHeader records a header that was relayed by a peer.
+func (nh *networkHealth) callReportHeader(addr modules.NetAddress, h types.BlockHeader) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	nh.reports[addr] = peerHeaderReport{
+		id:       h.ID(),
+		parentID: h.ParentID,
+		time:     time.Now(),
+	}
+}
+
+// callReports returns the reports of the given peers and drops the reports
+// of peers that are no longer connected.
+func (nh *networkHealth) callReports(peers []modules.Peer) map[modules.NetAddress]peerHeaderReport {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	reports := make(map[modules.NetAddress]peerHeaderReport)
+	for _, p := range peers {
+		if r, exists := nh.reports[p.NetAddress]; exists {
+			reports[p.NetAddress] = r
+		}
+	}
+	nh.reports = reports
+	copied := make(map[modules.NetAddress]peerHeaderReport, len(reports))
+	for addr, r := range reports {
+		copied[addr] = r
+	}
+	return copied
+}
+
+// callUpdatePartitioned records the result of a partition check and returns
+// the number of consecutive checks that found the node partitioned.
+func (nh *networkHealth) callUpdatePartitioned(partitioned bool) int {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	if partitioned {
+		nh.partitionChecks++
+	} else {
+		nh.partitionChecks = 0
+	}
+	return nh.partitionChecks
+}
+
+// peerSubnet returns the subnet the peer is grouped by for the eclipse check.
+// Peers whose address isn't an IP are their own subnet.
+func peerSubnet(addr modules.NetAddress) string {
+	ip := net.ParseIP(addr.Host())
+	if ip == nil {
+		return addr.Host()
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(eclipseSubnetIPv4, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(eclipseSubnetIPv6, 128)).String()
+}
+
+// managedNetworkHealth computes the consensus set's view of the network.
+func (cs *ConsensusSet) managedNetworkHealth() modules.NetworkHealth {
+	peers := cs.gateway.Peers()
+	reports := cs.staticNetworkHealth.callReports(peers)
+
+	var health modules.NetworkHealth
+	cs.mu.RLock()
+	health.Synced = cs.synced
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		current := currentProcessedBlock(tx)
+		health.Height = current.Height
+		health.LastBlockTime = current.Block.Timestamp
+		for addr, r := range reports {
+			report := modules.PeerHeightReport{
+				NetAddress: addr,
+				Time:       r.time,
+			}
+			if pb, err := getBlockMap(tx, r.id); err == nil {
+				report.Height = pb.Height
+			} else if parent, err := getBlockMap(tx, r.parentID); err == nil {
+				report.Height = parent.Height + 1
+			} else {
+				report.Orphan = true
+			}
+			health.PeerReports = append(health.PeerReports, report)
+		}
+		return nil
+	})
+	cs.mu.RUnlock()
+	sort.Slice(health.PeerReports, func(i, j int) bool {
+		return health.PeerReports[i].NetAddress < health.PeerReports[j].NetAddress
+	})
+
+	// Check whether the chain is stalled and whether peers are ahead.
+	stallTime := time.Duration(networkStallBlocks*types.BlockFrequency) * time.Second
+	health.Stalled = time.Since(time.Unix(int64(health.LastBlockTime), 0)) > stallTime
+	for _, r := range health.PeerReports {
+		if r.Orphan || r.Height > health.Height {
+			health.PeersAhead++
+		}
+	}
+	health.Partitioned = health.Synced && health.Stalled && health.PeersAhead > 0
+
+	// Group the remote peers by subnet.
+	subnets := make(map[string]struct{})
+	for _, p := range peers {
+		if p.Local {
+			continue
+		}
+		health.Peers++
+		subnets[peerSubnet(p.NetAddress)] = struct{}{}
+	}
+	health.Subnets = len(subnets)
+	health.Eclipsed = health.Peers >= minEclipsePeers && health.Subnets == 1
+	return health
+}
+
+// managedUpdateNetworkHealthAlerts registers or unregisters the network
+// health alerts according to the health of the network.
+func (cs *ConsensusSet) managedUpdateNetworkHealthAlerts(health modules.NetworkHealth) {
+	if cs.staticNetworkHealth.callUpdatePartitioned(health.Partitioned) >= partitionChecksBeforeAlert {
+		cause := fmt.Sprintf("no block since %v, %v of %v peers relayed blocks above height %v", time.Unix(int64(health.LastBlockTime), 0).UTC(), health.PeersAhead, len(health.PeerReports), health.Height)
+		cs.staticAlerter.RegisterAlert(modules.AlertIDConsensusNetworkPartition, AlertMSGNetworkPartition, cause, modules.SeverityError)
+	} else if !health.Partitioned {
+		cs.staticAlerter.UnregisterAlert(modules.AlertIDConsensusNetworkPartition)
+	}
+	if health.Eclipsed {
+		cause := fmt.Sprintf("%v remote peers share a single subnet", health.Peers)
+		cs.staticAlerter.RegisterAlert(modules.AlertIDConsensusEclipse, AlertMSGEclipse, cause, modules.SeverityWarning)
+	} else {
+		cs.staticAlerter.UnregisterAlert(modules.AlertIDConsensusEclipse)
+	}
+}
+
+// threadedMonitorNetworkHealth periodically checks the health of the network
+// and updates the alerts of the consensus set.
+func (cs *ConsensusSet) threadedMonitorNetworkHealth() {
+	if err := cs.tg.Add(); err != nil {
+		return
+	}
+	defer cs.tg.Done()
+	for {
+		select {
+		case <-cs.tg.StopChan():
+			return
+		case <-time.After(networkHealthCheckInterval):
+		}
+		cs.managedUpdateNetworkHealthAlerts(cs.managedNetworkHealth())
+	}
+}
+
+// NetworkHealth returns the consensus set's view of the health of the
+// network.
+func (cs *ConsensusSet) NetworkHealth() (modules.NetworkHealth, error) {
+	if err := cs.tg.Add(); err != nil {
+		return modules.NetworkHealth{}, err
+	}
+	defer cs.tg.Done()
+	return cs.managedNetworkHealth(), nil
+}
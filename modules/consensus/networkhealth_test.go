@@ -0,0 +1,55 @@
+package consensus
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestPeerSubnet probes the grouping of peers by subnet for the eclipse
+// check.
+func TestPeerSubnet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b modules.NetAddress
+		same bool
+	}{
+		{"1.2.3.4:9981", "1.2.200.1:9981", true},
+		{"1.2.3.4:9981", "1.3.3.4:9981", false},
+		{"[2001:db8::1]:9981", "[2001:db8:ffff::1]:9981", true},
+		{"[2001:db8::1]:9981", "[2001:db9::1]:9981", false},
+		{"host.example.com:9981", "host.example.com:9982", true},
+		{"host.example.com:9981", "other.example.com:9981", false},
+	}
+	for _, test := range tests {
+		if same := peerSubnet(test.a) == peerSubnet(test.b); same != test.same {
+			t.Errorf("%v and %v: expected same subnet %v, got %v", test.a, test.b, test.same, same)
+		}
+	}
+}
+
+// TestNetworkHealthReports checks that the reports of disconnected peers are
+// dropped and that consecutive partition checks are counted.
+func TestNetworkHealthReports(t *testing.T) {
+	t.Parallel()
+
+	nh := newNetworkHealth()
+	nh.callReportHeader("1.2.3.4:9981", types.GenesisBlock.Header())
+	nh.callReportHeader("5.6.7.8:9981", types.GenesisBlock.Header())
+	reports := nh.callReports([]modules.Peer{{NetAddress: "1.2.3.4:9981"}})
+	if len(reports) != 1 || len(nh.reports) != 1 {
+		t.Fatal("report of disconnected peer wasn't dropped", len(reports), len(nh.reports))
+	}
+	if _, exists := reports["1.2.3.4:9981"]; !exists {
+		t.Fatal("report of connected peer is missing")
+	}
+
+	if nh.callUpdatePartitioned(true) != 1 || nh.callUpdatePartitioned(true) != 2 {
+		t.Fatal("partition checks weren't counted")
+	}
+	if nh.callUpdatePartitioned(false) != 0 {
+		t.Fatal("partition checks weren't reset")
+	}
+}
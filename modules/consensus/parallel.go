@@ -0,0 +1,66 @@
+package consensus
+
+import (
+	"runtime"
+	"sync"
+
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// validationWorkers is the number of goroutines which verify signatures
+	// and compute Merkle roots in parallel.
+	validationWorkers = runtime.NumCPU()
+)
+
+// parallelFor calls fn for every index in [0, n) using up to
+// validationWorkers goroutines. fn must only access the state belonging to
+// its index.
+func parallelFor(n int, fn func(i int)) {
+	workers := validationWorkers
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				fn(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// parallelBlockIDs computes the IDs of the blocks in parallel. Computing an ID
+// requires the Merkle root of the block's transactions, which is the most
+// expensive part of hashing a block.
+func parallelBlockIDs(blocks []types.Block) []types.BlockID {
+	ids := make([]types.BlockID, len(blocks))
+	parallelFor(len(blocks), func(i int) {
+		ids[i] = blocks[i].ID()
+	})
+	return ids
+}
+
+// standaloneValidTransactions runs the checks of a block's transactions which
+// don't depend on the consensus state, most notably the signature
+// verification, in parallel. The returned slice contains the result for each
+// transaction, so that the serialized stage which applies the transactions
+// can report the errors in the same order as if the transactions were
+// validated one after another.
+func standaloneValidTransactions(txns []types.Transaction, currentHeight types.BlockHeight) []error {
+	errs := make([]error, len(txns))
+	parallelFor(len(txns), func(i int) {
+		errs[i] = txns[i].StandaloneValid(currentHeight)
+	})
+	return errs
+}
@@ -0,0 +1,67 @@
+package consensus
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"go.sia.tech/siad/types"
+)
+
+// TestParallelFor checks that parallelFor calls the function exactly once for
+// every index.
+func TestParallelFor(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{0, 1, 2, 7, 100} {
+		calls := make([]int32, n)
+		parallelFor(n, func(i int) {
+			atomic.AddInt32(&calls[i], 1)
+		})
+		for i, c := range calls {
+			if c != 1 {
+				t.Fatalf("index %v of %v was called %v times", i, n, c)
+			}
+		}
+	}
+}
+
+// TestParallelBlockIDs checks that parallelBlockIDs returns the same IDs as
+// computing them one after another.
+func TestParallelBlockIDs(t *testing.T) {
+	t.Parallel()
+	blocks := make([]types.Block, 20)
+	for i := range blocks {
+		blocks[i].Timestamp = types.Timestamp(i)
+		blocks[i].Transactions = []types.Transaction{{ArbitraryData: [][]byte{{byte(i)}}}}
+	}
+	ids := parallelBlockIDs(blocks)
+	for i := range blocks {
+		if ids[i] != blocks[i].ID() {
+			t.Fatal("wrong id for block", i)
+		}
+	}
+}
+
+// TestStandaloneValidTransactions checks that standaloneValidTransactions
+// reports the result of StandaloneValid for each transaction.
+func TestStandaloneValidTransactions(t *testing.T) {
+	t.Parallel()
+	txns := make([]types.Transaction, 20)
+	for i := range txns {
+		// Every third transaction has an output with a zero value, which is
+		// invalid.
+		if i%3 == 0 {
+			txns[i].SiacoinOutputs = []types.SiacoinOutput{{}}
+		}
+	}
+	errs := standaloneValidTransactions(txns, 0)
+	if len(errs) != len(txns) {
+		t.Fatal("wrong number of results", len(errs))
+	}
+	for i, err := range errs {
+		if expected := txns[i].StandaloneValid(0); err != expected {
+			t.Fatalf("transaction %v: expected %v but got %v", i, expected, err)
+		} else if (i%3 == 0) != (err != nil) {
+			t.Fatalf("transaction %v has the wrong result %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package consensus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/bolt"
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// AlertMSGDeepReorg indicates that a deep reorg occurred.
+	AlertMSGDeepReorg = "a deep reorg occurred, recently confirmed transactions might have been reverted"
+
+	// AlertMSGReorgRefused indicates that a reorg was refused because it is
+	// deeper than the maximum reorg depth.
+	AlertMSGReorgRefused = "a reorg deeper than the maximum reorg depth was refused and needs to be confirmed"
+)
+
+var (
+	// defaultReorgAlertDepth is the default depth of reorgs which register an
+	// alert.
+	defaultReorgAlertDepth = build.Select(build.Var{
+		Dev:      types.BlockHeight(6),
+		Standard: types.BlockHeight(6),
+		Testnet:  types.BlockHeight(6),
+		Testing:  types.BlockHeight(3),
+	}).(types.BlockHeight)
+
+	// reorgAlertBlocks is the number of blocks that need to be added on top
+	// of a deep reorg before its alert is unregistered.
+	reorgAlertBlocks = build.Select(build.Var{
+		Dev:      types.BlockHeight(144),
+		Standard: types.BlockHeight(144),
+		Testnet:  types.BlockHeight(144),
+		Testing:  types.BlockHeight(3),
+	}).(types.BlockHeight)
+
+	// errNoPendingReorg is returned when confirming a reorg which isn't
+	// pending.
+	errNoPendingReorg = errors.New("no pending reorg with that tip")
+
+	// errPendingReorgNotHeavier is returned when confirming a reorg to a fork
+	// which is no longer heavier than the current chain.
+	errPendingReorgNotHeavier = errors.New("the fork of the pending reorg is no longer heavier than the current chain")
+)
+
+// reorgTracker tracks the reorgs of the consensus set and refuses the reorgs
+// which are deeper than the maximum depth.
+type reorgTracker struct {
+	settings modules.ReorgSettings
+	last     *modules.Reorg
+	pending  *modules.Reorg
+
+	// alertClearHeight is the height at which the deep reorg alert is
+	// unregistered.
+	alertClearHeight types.BlockHeight
+
+	mu sync.Mutex
+}
+
+// newReorgTracker creates a reorgTracker with the default settings.
+func newReorgTracker() *reorgTracker {
+	return &reorgTracker{
+		settings: modules.ReorgSettings{
+			AlertDepth: defaultReorgAlertDepth,
+		},
+	}
+}
+
+// callRefuse returns true if the reorg is deeper than the maximum depth, in
+// which case it becomes the pending reorg.
+func (rt *reorgTracker) callRefuse(r modules.Reorg) bool {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.settings.MaxDepth == 0 || r.Depth <= rt.settings.MaxDepth {
+		return false
+	}
+	rt.pending = &r
+	return true
+}
+
+// callRecord records a reorg that occurred and returns true if it is deep
+// enough to register an alert.
+func (rt *reorgTracker) callRecord(r modules.Reorg) bool {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.last = &r
+	if rt.pending != nil && rt.pending.NewTip == r.NewTip {
+		rt.pending = nil
+	}
+	if r.Depth < rt.settings.AlertDepth {
+		return false
+	}
+	rt.alertClearHeight = r.ForkHeight + r.Depth + reorgAlertBlocks
+	return true
+}
+
+// callClearAlert returns true if the deep reorg alert should be unregistered
+// at the given height.
+func (rt *reorgTracker) callClearAlert(height types.BlockHeight) bool {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.alertClearHeight == 0 || height < rt.alertClearHeight {
+		return false
+	}
+	rt.alertClearHeight = 0
+	return true
+}
+
+// callPending returns the pending reorg.
+func (rt *reorgTracker) callPending() *modules.Reorg {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.pending == nil {
+		return nil
+	}
+	pending := *rt.pending
+	return &pending
+}
+
+// callClearPending removes the pending reorg.
+func (rt *reorgTracker) callClearPending() {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.pending = nil
+}
+
+// callStatus returns the settings, the last reorg and the pending reorg.
+func (rt *reorgTracker) callStatus() modules.ReorgStatus {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	status := modules.ReorgStatus{Settings: rt.settings}
+	if rt.last != nil {
+		last := *rt.last
+		status.LastReorg = &last
+	}
+	if rt.pending != nil {
+		pending := *rt.pending
+		status.PendingReorg = &pending
+	}
+	return status
+}
+
+// callSetSettings sets the reorg settings.
+func (rt *reorgTracker) callSetSettings(settings modules.ReorgSettings) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.settings = settings
+}
+
+// refuseReorg checks whether switching to the fork of newNode is a reorg
+// deeper than the maximum depth. If so, the reorg becomes the pending reorg,
+// an alert is registered and true is returned.
+func (cs *ConsensusSet) refuseReorg(tx *bolt.Tx, currentNode, newNode *processedBlock) bool {
+	commonParent := backtrackToCurrentPath(tx, newNode)[0]
+	r := modules.Reorg{
+		Depth:      currentNode.Height - commonParent.Height,
+		ForkHeight: commonParent.Height,
+		OldTip:     currentNode.Block.ID(),
+		NewTip:     newNode.Block.ID(),
+		Time:       time.Now(),
+	}
+	if r.Depth == 0 || !cs.staticReorgs.callRefuse(r) {
+		return false
+	}
+	cause := fmt.Sprintf("reorg of %v blocks from height %v to fork %v awaits confirmation", r.Depth, r.ForkHeight, r.NewTip)
+	cs.log.Println("WARN: refused reorg:", cause)
+	cs.staticAlerter.RegisterAlert(modules.AlertIDConsensusReorgRefused, AlertMSGReorgRefused, cause, modules.SeverityCritical)
+	return true
+}
+
+// recordReorgs records the reorgs of the applied changes and updates
+// the deep reorg alert.
+func (cs *ConsensusSet) recordReorgs(changes []changeEntry) {
+	var height types.BlockHeight
+	err := cs.db.View(func(tx *bolt.Tx) error {
+		height = blockHeight(tx)
+		for _, ce := range changes {
+			if len(ce.RevertedBlocks) == 0 || len(ce.AppliedBlocks) == 0 {
+				continue
+			}
+			firstApplied, err := getBlockMap(tx, ce.AppliedBlocks[0])
+			if err != nil {
+				return err
+			}
+			r := modules.Reorg{
+				Depth:      types.BlockHeight(len(ce.RevertedBlocks)),
+				ForkHeight: firstApplied.Height - 1,
+				OldTip:     ce.RevertedBlocks[0],
+				NewTip:     ce.AppliedBlocks[len(ce.AppliedBlocks)-1],
+				Time:       time.Now(),
+			}
+			cs.log.Printf("Reorg of %v blocks from height %v, replaced %v with %v", r.Depth, r.ForkHeight, r.OldTip, r.NewTip)
+			if cs.staticReorgs.callRecord(r) {
+				cause := fmt.Sprintf("reorg of %v blocks from height %v replaced block %v with %v", r.Depth, r.ForkHeight, r.OldTip, r.NewTip)
+				cs.staticAlerter.RegisterAlert(modules.AlertIDConsensusDeepReorg, AlertMSGDeepReorg, cause, modules.SeverityWarning)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		cs.log.Println("ERROR: unable to record reorg:", err)
+	}
+	if cs.staticReorgs.callClearAlert(height) {
+		cs.staticAlerter.UnregisterAlert(modules.AlertIDConsensusDeepReorg)
+	}
+}
+
+// ConfirmReorg switches to the fork of the pending reorg, which was refused
+// because it is deeper than the maximum reorg depth.
+func (cs *ConsensusSet) ConfirmReorg(tip types.BlockID) error {
+	if err := cs.tg.Add(); err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	pending := cs.staticReorgs.callPending()
+	if pending == nil || pending.NewTip != tip {
+		return errNoPendingReorg
+	}
+	var ce changeEntry
+	err := cs.db.Update(func(tx *bolt.Tx) error {
+		pb, err := getBlockMap(tx, tip)
+		if err != nil {
+			return errors.AddContext(err, "unable to find the tip of the pending reorg")
+		}
+		if !pb.heavierThan(currentProcessedBlock(tx)) {
+			return errPendingReorgNotHeavier
+		}
+		revertedBlocks, appliedBlocks, err := cs.forkBlockchain(tx, pb)
+		if err != nil {
+			return err
+		}
+		for _, rn := range revertedBlocks {
+			ce.RevertedBlocks = append(ce.RevertedBlocks, rn.Block.ID())
+		}
+		for _, an := range appliedBlocks {
+			ce.AppliedBlocks = append(ce.AppliedBlocks, an.Block.ID())
+		}
+		return appendChangeLog(tx, ce)
+	})
+	if err != nil {
+		// The pending reorg can't be confirmed anymore.
+		cs.staticReorgs.callClearPending()
+		cs.staticAlerter.UnregisterAlert(modules.AlertIDConsensusReorgRefused)
+		return errors.AddContext(err, "unable to confirm reorg")
+	}
+	cs.log.Println("Confirmed pending reorg to", tip)
+	cs.staticAlerter.UnregisterAlert(modules.AlertIDConsensusReorgRefused)
+	cs.recordReorgs([]changeEntry{ce})
+	cs.updateSubscribers(ce)
+	return nil
+}
+
+// ReorgStatus returns the reorg settings, the most recent reorg since startup
+// and the reorg which awaits confirmation.
+func (cs *ConsensusSet) ReorgStatus() (modules.ReorgStatus, error) {
+	if err := cs.tg.Add(); err != nil {
+		return modules.ReorgStatus{}, err
+	}
+	defer cs.tg.Done()
+	return cs.staticReorgs.callStatus(), nil
+}
+
+// SetReorgSettings sets the reorg settings. An alert depth of 0 is replaced
+// by the default alert depth.
+func (cs *ConsensusSet) SetReorgSettings(settings modules.ReorgSettings) error {
+	if err := cs.tg.Add(); err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+	if settings.AlertDepth == 0 {
+		settings.AlertDepth = defaultReorgAlertDepth
+	}
+	cs.staticReorgs.callSetSettings(settings)
+	return nil
+}
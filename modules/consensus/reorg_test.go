@@ -0,0 +1,125 @@
+package consensus
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/gateway"
+	"go.sia.tech/siad/types"
+)
+
+// TestReorgTrackerRefuse checks that reorgs are only refused if a maximum
+// depth is set and the reorg is deeper than it.
+func TestReorgTrackerRefuse(t *testing.T) {
+	t.Parallel()
+	rt := newReorgTracker()
+	r := modules.Reorg{Depth: 10, NewTip: types.BlockID{1}}
+	if rt.callRefuse(r) {
+		t.Fatal("reorg was refused without a maximum depth")
+	}
+	if rt.callPending() != nil {
+		t.Fatal("reorg shouldn't be pending")
+	}
+
+	rt.callSetSettings(modules.ReorgSettings{AlertDepth: defaultReorgAlertDepth, MaxDepth: 10})
+	if rt.callRefuse(r) {
+		t.Fatal("reorg at the maximum depth was refused")
+	}
+	r.Depth = 11
+	if !rt.callRefuse(r) {
+		t.Fatal("reorg deeper than the maximum depth wasn't refused")
+	}
+	pending := rt.callPending()
+	if pending == nil || *pending != r {
+		t.Fatal("refused reorg isn't pending", pending)
+	}
+
+	// The status contains copies of the reorgs.
+	status := rt.callStatus()
+	status.PendingReorg.Depth = 0
+	if rt.callPending().Depth != r.Depth {
+		t.Fatal("status shares the pending reorg with the tracker")
+	}
+
+	rt.callClearPending()
+	if rt.callPending() != nil {
+		t.Fatal("pending reorg wasn't cleared")
+	}
+}
+
+// TestReorgTrackerRecord checks that recorded reorgs update the last reorg,
+// clear the matching pending reorg and trigger and clear the alert.
+func TestReorgTrackerRecord(t *testing.T) {
+	t.Parallel()
+	rt := newReorgTracker()
+	rt.callSetSettings(modules.ReorgSettings{AlertDepth: 3, MaxDepth: 5})
+
+	// A shallow reorg doesn't trigger an alert.
+	shallow := modules.Reorg{Depth: 2, ForkHeight: 10, NewTip: types.BlockID{1}}
+	if rt.callRecord(shallow) {
+		t.Fatal("shallow reorg triggered an alert")
+	}
+	if last := rt.callStatus().LastReorg; last == nil || *last != shallow {
+		t.Fatal("last reorg wasn't recorded", last)
+	}
+
+	// Recording a refused reorg after it was confirmed clears it.
+	deep := modules.Reorg{Depth: 6, ForkHeight: 20, NewTip: types.BlockID{2}}
+	if !rt.callRefuse(deep) {
+		t.Fatal("deep reorg wasn't refused")
+	}
+	if !rt.callRecord(deep) {
+		t.Fatal("deep reorg didn't trigger an alert")
+	}
+	if rt.callPending() != nil {
+		t.Fatal("confirmed reorg is still pending")
+	}
+
+	// The alert is cleared once enough blocks were added on top of the new
+	// tip.
+	clearHeight := deep.ForkHeight + deep.Depth + reorgAlertBlocks
+	if rt.callClearAlert(clearHeight - 1) {
+		t.Fatal("alert was cleared too early")
+	}
+	if !rt.callClearAlert(clearHeight) {
+		t.Fatal("alert wasn't cleared")
+	}
+	if rt.callClearAlert(clearHeight + 1) {
+		t.Fatal("alert was cleared twice")
+	}
+}
+
+// TestSetReorgSettings checks that an alert depth of 0 is replaced by the
+// default.
+func TestSetReorgSettings(t *testing.T) {
+	t.Parallel()
+	testdir := build.TempDir(modules.ConsensusDir, t.Name())
+	g, err := gateway.New("localhost:0", false, filepath.Join(testdir, modules.GatewayDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	cs, errChan := New(g, false, filepath.Join(testdir, modules.ConsensusDir))
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	if err := cs.SetReorgSettings(modules.ReorgSettings{MaxDepth: 4}); err != nil {
+		t.Fatal(err)
+	}
+	status, err := cs.ReorgStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Settings.AlertDepth != defaultReorgAlertDepth || status.Settings.MaxDepth != 4 {
+		t.Fatal("wrong settings", status.Settings)
+	}
+	if status.LastReorg != nil || status.PendingReorg != nil {
+		t.Fatal("unexpected reorgs", status)
+	}
+	if err := cs.ConfirmReorg(types.BlockID{1}); err != errNoPendingReorg {
+		t.Fatal("expected errNoPendingReorg but got", err)
+	}
+}
@@ -96,6 +96,19 @@ func isTimeoutErr(err error) bool {
 	return (err.Error() == "Read timeout" || err.Error() == "Write timeout")
 }
 
+// isInvalidBlockErr returns true if an error returned by managedAcceptBlocks
+// means that a peer relayed an invalid block. Blocks which are already known,
+// don't extend the longest fork, have an unknown parent or a timestamp
+// slightly in the future are also relayed by honest peers.
+func isInvalidBlockErr(err error) bool {
+	return err != nil &&
+		!errors.Contains(err, modules.ErrBlockKnown) &&
+		!errors.Contains(err, modules.ErrNonExtendingBlock) &&
+		!errors.Contains(err, errOrphan) &&
+		!errors.Contains(err, ErrFutureTimestamp) &&
+		!errors.Contains(err, threadgroup.ErrStopped)
+}
+
 // blockHistory returns up to 32 block ids, starting with recent blocks and
 // then proving exponentially increasingly less recent blocks. The genesis
 // block is always included as the last block. This block history can be used
@@ -165,6 +178,7 @@ func (cs *ConsensusSet) managedReceiveBlocks(conn modules.PeerConn) (returnErr e
 	defer func() {
 		if isTimeoutErr(returnErr) && stalled {
 			returnErr = errSendBlocksStalled
+			cs.gateway.ReportPeer(conn.RPCAddr(), modules.PeerOffenseStall)
 		}
 	}()
 
@@ -232,6 +246,9 @@ func (cs *ConsensusSet) managedReceiveBlocks(conn modules.PeerConn) (returnErr e
 		if extended {
 			chainExtended = true
 		}
+		if isInvalidBlockErr(acceptErr) {
+			cs.gateway.ReportPeer(conn.RPCAddr(), modules.PeerOffenseInvalidBlock)
+		}
 		// ErrNonExtendingBlock must be ignored until headers-first block
 		// sharing is implemented, block already in database should also be
 		// ignored.
@@ -432,6 +449,11 @@ func (cs *ConsensusSet) threadedRPCRelayHeader(conn modules.PeerConn) error {
 		return cs.validateHeader(boltTxWrapper{tx}, h)
 	})
 	cs.mu.RUnlock()
+	// Remember the header for the network health checks. Invalid headers are
+	// ignored.
+	if err == nil || errors.Contains(err, errOrphan) || errors.Contains(err, modules.ErrBlockKnown) {
+		cs.staticNetworkHealth.callReportHeader(conn.RPCAddr(), h)
+	}
 	// WARN: orphan multithreading logic (dangerous areas, see below)
 	//
 	// If the header is valid and extends the heaviest chain, fetch the
@@ -536,6 +558,9 @@ func (cs *ConsensusSet) managedReceiveBlock(id types.BlockID) modules.RPCFunc {
 		if chainExtended {
 			cs.managedBroadcastBlock(block)
 		}
+		if isInvalidBlockErr(err) {
+			cs.gateway.ReportPeer(conn.RPCAddr(), modules.PeerOffenseInvalidBlock)
+		}
 		if err != nil {
 			return err
 		}
@@ -334,10 +334,14 @@ func validTransaction(tx *bolt.Tx, t types.Transaction) error {
 	if err != nil {
 		return err
 	}
+	return validTransactionState(tx, t, currentHeight)
+}
 
-	// Check that each portion of the transaction is legal given the current
-	// consensus set.
-	err = validSiacoins(tx, t)
+// validTransactionState checks that each portion of the transaction is legal
+// given the current consensus set. The checks of StandaloneValid are not
+// performed.
+func validTransactionState(tx *bolt.Tx, t types.Transaction, currentHeight types.BlockHeight) error {
+	err := validSiacoins(tx, t)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,321 @@
+package consensus
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gitlab.com/NebulousLabs/bolt"
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// maxReportedInconsistencies is the maximum number of inconsistencies
+	// which are listed individually in a verification report.
+	maxReportedInconsistencies = 100
+)
+
+// verifier collects the inconsistencies found while verifying the consensus
+// database.
+type verifier struct {
+	report  modules.ConsensusVerifyReport
+	omitted int
+}
+
+// addInconsistency adds an inconsistency to the report. needsResync indicates
+// whether the inconsistency can only be fixed by rebuilding the database.
+func (v *verifier) addInconsistency(needsResync bool, format string, a ...interface{}) {
+	if needsResync {
+		v.report.NeedsResync = true
+	}
+	if len(v.report.Inconsistencies) >= maxReportedInconsistencies {
+		v.omitted++
+		return
+	}
+	v.report.Inconsistencies = append(v.report.Inconsistencies, fmt.Sprintf(format, a...))
+}
+
+// verifyBlockDiffs checks that the diffs of a processed block contain the
+// changes made by the block's transactions and miner payouts. The diffs also
+// contain the changes of the block's maintenance, which are not checked.
+func verifyBlockDiffs(pb *processedBlock) []string {
+	scoApplied := make(map[types.SiacoinOutputID]types.Currency)
+	scoReverted := make(map[types.SiacoinOutputID]struct{})
+	for _, diff := range pb.SiacoinOutputDiffs {
+		if diff.Direction == modules.DiffApply {
+			scoApplied[diff.ID] = diff.SiacoinOutput.Value
+		} else {
+			scoReverted[diff.ID] = struct{}{}
+		}
+	}
+	fcApplied := make(map[types.FileContractID]struct{})
+	fcReverted := make(map[types.FileContractID]struct{})
+	for _, diff := range pb.FileContractDiffs {
+		if diff.Direction == modules.DiffApply {
+			fcApplied[diff.ID] = struct{}{}
+		} else {
+			fcReverted[diff.ID] = struct{}{}
+		}
+	}
+	sfoApplied := make(map[types.SiafundOutputID]types.Currency)
+	sfoReverted := make(map[types.SiafundOutputID]struct{})
+	for _, diff := range pb.SiafundOutputDiffs {
+		if diff.Direction == modules.DiffApply {
+			sfoApplied[diff.ID] = diff.SiafundOutput.Value
+		} else {
+			sfoReverted[diff.ID] = struct{}{}
+		}
+	}
+	dscoApplied := make(map[types.SiacoinOutputID]types.Currency)
+	for _, diff := range pb.DelayedSiacoinOutputDiffs {
+		if diff.Direction == modules.DiffApply {
+			dscoApplied[diff.ID] = diff.SiacoinOutput.Value
+		}
+	}
+
+	var problems []string
+	missing := func(kind string, id interface{}) {
+		problems = append(problems, fmt.Sprintf("block %v at height %v has no diff for %v %v", pb.Block.ID(), pb.Height, kind, id))
+	}
+	for _, txn := range pb.Block.Transactions {
+		for _, sci := range txn.SiacoinInputs {
+			if _, exists := scoReverted[sci.ParentID]; !exists {
+				missing("spent siacoin output", sci.ParentID)
+			}
+		}
+		for i, sco := range txn.SiacoinOutputs {
+			id := txn.SiacoinOutputID(uint64(i))
+			if value, exists := scoApplied[id]; !exists || !value.Equals(sco.Value) {
+				missing("created siacoin output", id)
+			}
+		}
+		for i := range txn.FileContracts {
+			id := txn.FileContractID(uint64(i))
+			if _, exists := fcApplied[id]; !exists {
+				missing("created file contract", id)
+			}
+		}
+		for _, fcr := range txn.FileContractRevisions {
+			_, reverted := fcReverted[fcr.ParentID]
+			_, applied := fcApplied[fcr.ParentID]
+			if !reverted || !applied {
+				missing("revised file contract", fcr.ParentID)
+			}
+		}
+		for _, sp := range txn.StorageProofs {
+			if _, exists := fcReverted[sp.ParentID]; !exists {
+				missing("proven file contract", sp.ParentID)
+			}
+		}
+		for _, sfi := range txn.SiafundInputs {
+			if _, exists := sfoReverted[sfi.ParentID]; !exists {
+				missing("spent siafund output", sfi.ParentID)
+			}
+		}
+		for i, sfo := range txn.SiafundOutputs {
+			id := txn.SiafundOutputID(uint64(i))
+			if value, exists := sfoApplied[id]; !exists || !value.Equals(sfo.Value) {
+				missing("created siafund output", id)
+			}
+		}
+	}
+	for i, payout := range pb.Block.MinerPayouts {
+		id := pb.Block.MinerPayoutID(uint64(i))
+		if value, exists := dscoApplied[id]; !exists || !value.Equals(payout.Value) {
+			missing("miner payout", id)
+		}
+	}
+	return problems
+}
+
+// verifyConsensusDB walks the consensus database and checks that the blocks
+// of the current path are linked, that their diffs match the block data and
+// that the consensus state is consistent. If repair is true, invalid blocks
+// which are not part of the current path are removed and a stale
+// inconsistency flag is cleared. Repairing requires a writable transaction.
+func verifyConsensusDB(tx *bolt.Tx, repair bool) (modules.ConsensusVerifyReport, error) {
+	var v verifier
+	for _, bucket := range [][]byte{BlockHeight, BlockMap, BlockPath, Consistency, SiacoinOutputs, FileContracts, SiafundOutputs, SiafundPool} {
+		if tx.Bucket(bucket) == nil {
+			v.addInconsistency(true, "database is missing the %s bucket", bucket)
+		}
+	}
+	if v.report.NeedsResync {
+		return v.report, nil
+	}
+	var height types.BlockHeight
+	if err := encoding.Unmarshal(tx.Bucket(BlockHeight).Get(BlockHeight), &height); err != nil {
+		v.addInconsistency(true, "unable to decode the block height: %v", err)
+		return v.report, nil
+	}
+	v.report.Height = height
+
+	// Walk the current path.
+	blockMap := tx.Bucket(BlockMap)
+	blockPath := tx.Bucket(BlockPath)
+	onPath := make(map[types.BlockID]struct{})
+	var parentID types.BlockID
+	for h := types.BlockHeight(0); h <= height; h++ {
+		v.report.BlocksChecked++
+		var id types.BlockID
+		if err := encoding.Unmarshal(blockPath.Get(encoding.Marshal(h)), &id); err != nil {
+			v.addInconsistency(true, "block path has no valid entry at height %v", h)
+			parentID = types.BlockID{}
+			continue
+		}
+		onPath[id] = struct{}{}
+		pbBytes := blockMap.Get(id[:])
+		if pbBytes == nil {
+			v.addInconsistency(true, "block %v at height %v is missing from the block map", id, h)
+			parentID = id
+			continue
+		}
+		var pb processedBlock
+		if err := encoding.Unmarshal(pbBytes, &pb); err != nil {
+			v.addInconsistency(true, "unable to decode block %v at height %v: %v", id, h, err)
+			parentID = id
+			continue
+		}
+		if pb.Block.ID() != id {
+			v.addInconsistency(true, "block data at height %v doesn't match its id %v", h, id)
+		}
+		if pb.Height != h {
+			v.addInconsistency(true, "block %v is on the path at height %v but has height %v", id, h, pb.Height)
+		}
+		if h > 0 && pb.Block.ParentID != parentID {
+			v.addInconsistency(true, "block %v at height %v isn't a child of the previous block", id, h)
+		}
+		if !pb.DiffsGenerated {
+			v.addInconsistency(true, "block %v at height %v is on the path but has no diffs", id, h)
+		} else if h > 0 {
+			// The diffs of the genesis block are created when the database
+			// is initialized and don't contain its miner payouts.
+			for _, problem := range verifyBlockDiffs(&pb) {
+				v.addInconsistency(true, "%v", problem)
+			}
+		}
+		parentID = id
+	}
+	if blockPath.Get(encoding.Marshal(height+1)) != nil {
+		v.addInconsistency(true, "block path has entries above the current height %v", height)
+	}
+
+	// Check the blocks which are not on the current path. Invalid blocks can
+	// be removed, because they are downloaded again if needed.
+	var invalid [][]byte
+	err := blockMap.ForEach(func(k, pbBytes []byte) error {
+		var id types.BlockID
+		copy(id[:], k)
+		if _, exists := onPath[id]; exists {
+			return nil
+		}
+		var pb processedBlock
+		if err := encoding.Unmarshal(pbBytes, &pb); err != nil {
+			v.addInconsistency(false, "unable to decode block %v which is not on the current path: %v", id, err)
+		} else if pb.Block.ID() != id {
+			v.addInconsistency(false, "block data of %v which is not on the current path doesn't match its id", id)
+		} else {
+			return nil
+		}
+		invalid = append(invalid, append([]byte(nil), k...))
+		return nil
+	})
+	if err != nil {
+		return modules.ConsensusVerifyReport{}, err
+	}
+	if repair && len(invalid) > 0 {
+		for _, k := range invalid {
+			if err := blockMap.Delete(k); err != nil {
+				return modules.ConsensusVerifyReport{}, errors.AddContext(err, "unable to remove invalid block")
+			}
+		}
+		v.report.Repairs = append(v.report.Repairs, fmt.Sprintf("removed %v invalid blocks which were not on the current path", len(invalid)))
+	}
+
+	// Check the consensus state.
+	for _, check := range []func(*bolt.Tx) error{checkDSCOs, checkSiacoinCount, checkSiafundCount} {
+		if err := check(tx); err != nil {
+			v.addInconsistency(true, "consensus state is inconsistent: %v", err)
+		}
+	}
+
+	// The inconsistency flag is set when an inconsistency is detected at
+	// runtime. It can be cleared if the database is consistent now.
+	var inconsistent bool
+	if err := encoding.Unmarshal(tx.Bucket(Consistency).Get(Consistency), &inconsistent); err != nil || inconsistent {
+		v.addInconsistency(false, "database was flagged as inconsistent at runtime")
+		if repair && !v.report.NeedsResync {
+			if err := tx.Bucket(Consistency).Put(Consistency, encoding.Marshal(false)); err != nil {
+				return modules.ConsensusVerifyReport{}, errors.AddContext(err, "unable to clear the inconsistency flag")
+			}
+			v.report.Repairs = append(v.report.Repairs, "cleared the inconsistency flag")
+		}
+	}
+
+	if v.omitted > 0 {
+		v.report.Inconsistencies = append(v.report.Inconsistencies, fmt.Sprintf("%v more inconsistencies were omitted", v.omitted))
+	}
+	return v.report, nil
+}
+
+// VerifyDatabase checks the consensus database in persistDir for
+// inconsistencies. It must not be called while a consensus set is using the
+// database. If repair is true, the inconsistencies which can be fixed in place
+// are repaired.
+func VerifyDatabase(persistDir string, repair bool) (report modules.ConsensusVerifyReport, err error) {
+	filename := filepath.Join(persistDir, DatabaseFilename)
+	if _, err := os.Stat(filename); err != nil {
+		return modules.ConsensusVerifyReport{}, errors.AddContext(err, "unable to find consensus database")
+	}
+	db, err := persist.OpenDatabase(dbMetadata, filename)
+	if err != nil {
+		return modules.ConsensusVerifyReport{}, errors.AddContext(err, "unable to open consensus database")
+	}
+	defer func() {
+		err = errors.Compose(err, db.Close())
+	}()
+	verify := func(tx *bolt.Tx) (err error) {
+		report, err = verifyConsensusDB(tx, repair)
+		return err
+	}
+	if repair {
+		err = db.Update(verify)
+	} else {
+		err = db.View(verify)
+	}
+	return report, err
+}
+
+// Verify checks the consensus database for inconsistencies. If repair is
+// true, the inconsistencies which can be fixed in place are repaired. Blocks
+// can't be accepted while the database is verified.
+func (cs *ConsensusSet) Verify(repair bool) (report modules.ConsensusVerifyReport, err error) {
+	if err := cs.tg.Add(); err != nil {
+		return modules.ConsensusVerifyReport{}, err
+	}
+	defer cs.tg.Done()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	verify := func(tx *bolt.Tx) (err error) {
+		report, err = verifyConsensusDB(tx, repair)
+		return err
+	}
+	if repair {
+		err = cs.db.Update(verify)
+	} else {
+		err = cs.db.View(verify)
+	}
+	if err != nil {
+		return modules.ConsensusVerifyReport{}, err
+	}
+	if len(report.Inconsistencies) > 0 || len(report.Repairs) > 0 {
+		cs.log.Printf("Consensus verification found %v inconsistencies and made %v repairs", len(report.Inconsistencies), len(report.Repairs))
+	}
+	return report, nil
+}
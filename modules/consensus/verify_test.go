@@ -0,0 +1,130 @@
+package consensus
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/bolt"
+	"gitlab.com/NebulousLabs/encoding"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/gateway"
+	"go.sia.tech/siad/types"
+)
+
+// TestVerifyBlockDiffs is a unit test for verifyBlockDiffs.
+func TestVerifyBlockDiffs(t *testing.T) {
+	t.Parallel()
+	txn := types.Transaction{
+		SiacoinInputs:  []types.SiacoinInput{{ParentID: types.SiacoinOutputID{1}}},
+		SiacoinOutputs: []types.SiacoinOutput{{Value: types.NewCurrency64(5)}},
+	}
+	pb := &processedBlock{
+		Block: types.Block{
+			MinerPayouts: []types.SiacoinOutput{{Value: types.NewCurrency64(7)}},
+			Transactions: []types.Transaction{txn},
+		},
+		Height: 1,
+	}
+	if problems := verifyBlockDiffs(pb); len(problems) != 3 {
+		t.Fatal("expected 3 problems but got", problems)
+	}
+
+	pb.SiacoinOutputDiffs = []modules.SiacoinOutputDiff{
+		{Direction: modules.DiffRevert, ID: txn.SiacoinInputs[0].ParentID},
+		{Direction: modules.DiffApply, ID: txn.SiacoinOutputID(0), SiacoinOutput: txn.SiacoinOutputs[0]},
+	}
+	pb.DelayedSiacoinOutputDiffs = []modules.DelayedSiacoinOutputDiff{
+		{Direction: modules.DiffApply, ID: pb.Block.MinerPayoutID(0), SiacoinOutput: types.SiacoinOutput{Value: types.NewCurrency64(6)}},
+	}
+	if problems := verifyBlockDiffs(pb); len(problems) != 1 {
+		t.Fatal("expected the miner payout problem but got", problems)
+	}
+	pb.DelayedSiacoinOutputDiffs[0].SiacoinOutput = pb.Block.MinerPayouts[0]
+	if problems := verifyBlockDiffs(pb); len(problems) != 0 {
+		t.Fatal("expected no problems but got", problems)
+	}
+}
+
+// TestVerify tests that inconsistencies in the consensus database are
+// reported and repaired.
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	testdir := build.TempDir(modules.ConsensusDir, t.Name())
+	g, err := gateway.New("localhost:0", false, filepath.Join(testdir, modules.GatewayDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	csDir := filepath.Join(testdir, modules.ConsensusDir)
+	cs, errChan := New(g, false, csDir)
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh database is consistent.
+	report, err := cs.Verify(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Inconsistencies) != 0 || report.NeedsResync || report.BlocksChecked != 1 {
+		t.Fatal("unexpected report", report)
+	}
+
+	// Add an invalid block which is not on the current path and flag the
+	// database as inconsistent.
+	err = cs.db.Update(func(tx *bolt.Tx) error {
+		markInconsistency(tx)
+		return tx.Bucket(BlockMap).Put(make([]byte, 32), []byte("garbage"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err = cs.Verify(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Inconsistencies) != 2 || report.NeedsResync || len(report.Repairs) != 0 {
+		t.Fatal("unexpected report", report)
+	}
+
+	// Both inconsistencies can be repaired.
+	report, err = cs.Verify(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Inconsistencies) != 2 || len(report.Repairs) != 2 {
+		t.Fatal("unexpected report", report)
+	}
+	report, err = cs.Verify(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Inconsistencies) != 0 {
+		t.Fatal("inconsistencies weren't repaired", report)
+	}
+
+	// Removing the genesis block from the block path requires a resync.
+	err = cs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(BlockPath).Delete(encoding.Marshal(types.BlockHeight(0)))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	report, err = VerifyDatabase(csDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.NeedsResync || len(report.Repairs) != 0 {
+		t.Fatal("unexpected report", report)
+	}
+
+	// Verifying a database which doesn't exist fails.
+	if _, err := VerifyDatabase(filepath.Join(testdir, "missing"), false); err == nil {
+		t.Fatal("expected an error")
+	}
+}
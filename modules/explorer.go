@@ -10,7 +10,59 @@ const (
 	ExplorerDir = "explorer"
 )
 
+const (
+	// AddressEventSiacoinInput is the type of an event where a siacoin output
+	// of the address was spent.
+	AddressEventSiacoinInput AddressEventType = "siacoininput"
+
+	// AddressEventSiacoinOutput is the type of an event where a transaction
+	// created a siacoin output for the address.
+	AddressEventSiacoinOutput AddressEventType = "siacoinoutput"
+
+	// AddressEventSiacoinPayout is the type of an event where a delayed
+	// siacoin output of the address matured. Delayed outputs are created by
+	// miner payouts, file contract payouts and siafund claims.
+	AddressEventSiacoinPayout AddressEventType = "siacoinpayout"
+
+	// AddressEventSiafundInput is the type of an event where a siafund output
+	// of the address was spent.
+	AddressEventSiafundInput AddressEventType = "siafundinput"
+
+	// AddressEventSiafundOutput is the type of an event where a siafund output
+	// was created for the address.
+	AddressEventSiafundOutput AddressEventType = "siafundoutput"
+)
+
 type (
+	// AddressEventType describes how an address event changed the balance of
+	// the address.
+	AddressEventType string
+
+	// AddressEvent is an output of an address which was created or spent.
+	// The balances are the balances of the address after the event.
+	AddressEvent struct {
+		Height        types.BlockHeight   `json:"height"`
+		BlockID       types.BlockID       `json:"blockid"`
+		TransactionID types.TransactionID `json:"transactionid"`
+		Type          AddressEventType    `json:"type"`
+		OutputID      types.OutputID      `json:"outputid"`
+		Value         types.Currency      `json:"value"`
+
+		SiacoinBalance types.Currency `json:"siacoinbalance"`
+		SiafundBalance types.Currency `json:"siafundbalance"`
+	}
+
+	// AddressHistory is a page of the events of an address, ordered from the
+	// oldest to the newest event, together with the current balances of the
+	// address.
+	AddressHistory struct {
+		UnlockHash     types.UnlockHash `json:"unlockhash"`
+		SiacoinBalance types.Currency   `json:"siacoinbalance"`
+		SiafundBalance types.Currency   `json:"siafundbalance"`
+		TotalEvents    uint64           `json:"totalevents"`
+		Events         []AddressEvent   `json:"events"`
+	}
+
 	// BlockFacts returns a bunch of statistics about the consensus set as they
 	// were at a specific block.
 	BlockFacts struct {
@@ -73,6 +125,11 @@ type (
 		// provided unlock hash.
 		UnlockHash(types.UnlockHash) []types.TransactionID
 
+		// AddressHistory returns up to limit events of the provided unlock
+		// hash, starting with the event at offset. The bool indicates whether
+		// the unlock hash has any events.
+		AddressHistory(uh types.UnlockHash, offset, limit uint64) (AddressHistory, bool)
+
 		// SiacoinOutput will return the siacoin output associated with the
 		// input id.
 		SiacoinOutput(types.SiacoinOutputID) (types.SiacoinOutput, bool)
@@ -0,0 +1,197 @@
+package explorer
+
+import (
+	"encoding/binary"
+
+	"gitlab.com/NebulousLabs/bolt"
+
+	"gitlab.com/NebulousLabs/encoding"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// The address index stores the events of every address in a sub-bucket of
+// bucketAddressEvents. The events are keyed by their big-endian index within
+// the history of the address, so they are iterated from oldest to newest and
+// the newest event, which holds the current balances, is the last key. Blocks
+// are always reverted in the reverse order they were applied, which means the
+// events of a reverted block are always at the end of the history.
+
+// addressEventKey returns the key of the event with the given index.
+func addressEventKey(index uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, index)
+	return key
+}
+
+// blockAddressEvents returns the address events caused by applying a block
+// with the provided diffs, in the order of the diffs.
+func blockAddressEvents(block types.Block, height types.BlockHeight, diffs modules.ConsensusChangeDiffs) map[types.UnlockHash][]modules.AddressEvent {
+	// Map the outputs created and spent by the block to their transactions.
+	bid := block.ID()
+	scoTxns := make(map[types.SiacoinOutputID]types.TransactionID)
+	sfoTxns := make(map[types.SiafundOutputID]types.TransactionID)
+	for _, txn := range block.Transactions {
+		txid := txn.ID()
+		for _, sci := range txn.SiacoinInputs {
+			scoTxns[sci.ParentID] = txid
+		}
+		for i := range txn.SiacoinOutputs {
+			scoTxns[txn.SiacoinOutputID(uint64(i))] = txid
+		}
+		for _, sfi := range txn.SiafundInputs {
+			sfoTxns[sfi.ParentID] = txid
+		}
+		for i := range txn.SiafundOutputs {
+			sfoTxns[txn.SiafundOutputID(uint64(i))] = txid
+		}
+	}
+	// Delayed outputs which are removed from the delayed set mature in this
+	// block.
+	matured := make(map[types.SiacoinOutputID]struct{})
+	for _, dscod := range diffs.DelayedSiacoinOutputDiffs {
+		if dscod.Direction == modules.DiffRevert {
+			matured[dscod.ID] = struct{}{}
+		}
+	}
+
+	events := make(map[types.UnlockHash][]modules.AddressEvent)
+	for _, scod := range diffs.SiacoinOutputDiffs {
+		event := modules.AddressEvent{
+			Height:        height,
+			BlockID:       bid,
+			TransactionID: scoTxns[scod.ID],
+			Type:          modules.AddressEventSiacoinOutput,
+			OutputID:      types.OutputID(scod.ID),
+			Value:         scod.SiacoinOutput.Value,
+		}
+		if scod.Direction == modules.DiffRevert {
+			event.Type = modules.AddressEventSiacoinInput
+		} else if _, ok := matured[scod.ID]; ok {
+			event.Type = modules.AddressEventSiacoinPayout
+		}
+		uh := scod.SiacoinOutput.UnlockHash
+		events[uh] = append(events[uh], event)
+	}
+	for _, sfod := range diffs.SiafundOutputDiffs {
+		event := modules.AddressEvent{
+			Height:        height,
+			BlockID:       bid,
+			TransactionID: sfoTxns[sfod.ID],
+			Type:          modules.AddressEventSiafundOutput,
+			OutputID:      types.OutputID(sfod.ID),
+			Value:         sfod.SiafundOutput.Value,
+		}
+		if sfod.Direction == modules.DiffRevert {
+			event.Type = modules.AddressEventSiafundInput
+		}
+		uh := sfod.SiafundOutput.UnlockHash
+		events[uh] = append(events[uh], event)
+	}
+	return events
+}
+
+// dbAddAddressEvents adds the address events of an applied block to the
+// address index. Like the other db functions of update.go, it panics on
+// error.
+func dbAddAddressEvents(tx *bolt.Tx, block types.Block, height types.BlockHeight, diffs modules.ConsensusChangeDiffs) {
+	for uh, events := range blockAddressEvents(block, height, diffs) {
+		b, err := tx.Bucket(bucketAddressEvents).CreateBucketIfNotExists(encoding.Marshal(uh))
+		assertNil(err)
+
+		// Continue from the balances of the newest event.
+		var last modules.AddressEvent
+		var next uint64
+		if k, v := b.Cursor().Last(); k != nil {
+			assertNil(encoding.Unmarshal(v, &last))
+			next = binary.BigEndian.Uint64(k) + 1
+		}
+		scBalance, sfBalance := last.SiacoinBalance, last.SiafundBalance
+		for _, event := range events {
+			switch event.Type {
+			case modules.AddressEventSiacoinInput:
+				scBalance = scBalance.Sub(event.Value)
+			case modules.AddressEventSiacoinOutput, modules.AddressEventSiacoinPayout:
+				scBalance = scBalance.Add(event.Value)
+			case modules.AddressEventSiafundInput:
+				sfBalance = sfBalance.Sub(event.Value)
+			case modules.AddressEventSiafundOutput:
+				sfBalance = sfBalance.Add(event.Value)
+			}
+			event.SiacoinBalance, event.SiafundBalance = scBalance, sfBalance
+			assertNil(b.Put(addressEventKey(next), encoding.Marshal(event)))
+			next++
+		}
+	}
+}
+
+// dbRemoveAddressEvents removes the address events of a reverted block from
+// the address index.
+func dbRemoveAddressEvents(tx *bolt.Tx, bid types.BlockID, diffs modules.ConsensusChangeDiffs) {
+	addrs := make(map[types.UnlockHash]struct{})
+	for _, scod := range diffs.SiacoinOutputDiffs {
+		addrs[scod.SiacoinOutput.UnlockHash] = struct{}{}
+	}
+	for _, sfod := range diffs.SiafundOutputDiffs {
+		addrs[sfod.SiafundOutput.UnlockHash] = struct{}{}
+	}
+	for uh := range addrs {
+		b := tx.Bucket(bucketAddressEvents).Bucket(encoding.Marshal(uh))
+		if b == nil {
+			continue
+		}
+		for {
+			k, v := b.Cursor().Last()
+			if k == nil {
+				break
+			}
+			var event modules.AddressEvent
+			assertNil(encoding.Unmarshal(v, &event))
+			if event.BlockID != bid {
+				break
+			}
+			assertNil(b.Delete(k))
+		}
+		if bucketIsEmpty(b) {
+			assertNil(tx.Bucket(bucketAddressEvents).DeleteBucket(encoding.Marshal(uh)))
+		}
+	}
+}
+
+// AddressHistory returns up to limit events of the unlock hash, starting with
+// the event at offset, and the current balances of the unlock hash. The bool
+// indicates whether the unlock hash appears in the blockchain.
+func (e *Explorer) AddressHistory(uh types.UnlockHash, offset, limit uint64) (modules.AddressHistory, bool) {
+	history := modules.AddressHistory{UnlockHash: uh}
+	err := e.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketAddressEvents).Bucket(encoding.Marshal(uh))
+		if b == nil {
+			return errNotExist
+		}
+		c := b.Cursor()
+		k, v := c.Last()
+		if k == nil {
+			return errNotExist
+		}
+		var last modules.AddressEvent
+		if err := encoding.Unmarshal(v, &last); err != nil {
+			return err
+		}
+		history.SiacoinBalance = last.SiacoinBalance
+		history.SiafundBalance = last.SiafundBalance
+		history.TotalEvents = binary.BigEndian.Uint64(k) + 1
+
+		for k, v = c.Seek(addressEventKey(offset)); k != nil && uint64(len(history.Events)) < limit; k, v = c.Next() {
+			var event modules.AddressEvent
+			if err := encoding.Unmarshal(v, &event); err != nil {
+				return err
+			}
+			history.Events = append(history.Events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return modules.AddressHistory{UnlockHash: uh}, false
+	}
+	return history, true
+}
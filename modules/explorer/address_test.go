@@ -0,0 +1,166 @@
+package explorer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/bolt"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/consensus"
+	"go.sia.tech/siad/modules/gateway"
+	"go.sia.tech/siad/types"
+)
+
+// newAddressTestExplorer creates an explorer on top of a consensus set which
+// only contains the genesis block.
+func newAddressTestExplorer(t *testing.T) *Explorer {
+	testdir := build.TempDir(modules.ExplorerDir, t.Name())
+	g, err := gateway.New("localhost:0", false, filepath.Join(testdir, modules.GatewayDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, errChan := consensus.New(g, false, filepath.Join(testdir, modules.ConsensusDir))
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	e, err := New(cs, filepath.Join(testdir, modules.ExplorerDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		e.Close()
+		cs.Close()
+		g.Close()
+	})
+	return e
+}
+
+// TestAddressHistoryGenesis checks that the outputs of the genesis block are
+// added to the address index.
+func TestAddressHistoryGenesis(t *testing.T) {
+	t.Parallel()
+	e := newAddressTestExplorer(t)
+
+	sfo := types.GenesisSiafundAllocation[0]
+	history, exists := e.AddressHistory(sfo.UnlockHash, 0, 10)
+	if !exists {
+		t.Fatal("genesis siafund address isn't indexed")
+	}
+	if history.TotalEvents == 0 || len(history.Events) == 0 {
+		t.Fatal("no events", history)
+	}
+	event := history.Events[0]
+	if event.Height != 0 || event.BlockID != types.GenesisID || event.Type != modules.AddressEventSiafundOutput {
+		t.Fatal("wrong genesis event", event)
+	}
+	if history.SiafundBalance.IsZero() {
+		t.Fatal("genesis siafund address has no balance")
+	}
+
+	if _, exists := e.AddressHistory(types.UnlockHash{1}, 0, 10); exists {
+		t.Fatal("unknown address has a history")
+	}
+}
+
+// TestAddressEvents checks that applying and reverting blocks updates the
+// events and balances of the address index.
+func TestAddressEvents(t *testing.T) {
+	t.Parallel()
+	e := newAddressTestExplorer(t)
+	alice, bob := types.UnlockHash{1}, types.UnlockHash{2}
+
+	// The first block creates an output for alice.
+	txn1 := types.Transaction{
+		SiacoinOutputs: []types.SiacoinOutput{{Value: types.NewCurrency64(10), UnlockHash: alice}},
+	}
+	b1 := types.Block{Timestamp: 1, Transactions: []types.Transaction{txn1}}
+	diffs1 := modules.ConsensusChangeDiffs{
+		SiacoinOutputDiffs: []modules.SiacoinOutputDiff{
+			{Direction: modules.DiffApply, ID: txn1.SiacoinOutputID(0), SiacoinOutput: txn1.SiacoinOutputs[0]},
+		},
+	}
+
+	// The second block spends it to bob and alice, and a payout of alice
+	// matures.
+	txn2 := types.Transaction{
+		SiacoinInputs: []types.SiacoinInput{{ParentID: txn1.SiacoinOutputID(0)}},
+		SiacoinOutputs: []types.SiacoinOutput{
+			{Value: types.NewCurrency64(4), UnlockHash: alice},
+			{Value: types.NewCurrency64(6), UnlockHash: bob},
+		},
+	}
+	b2 := types.Block{ParentID: b1.ID(), Timestamp: 2, Transactions: []types.Transaction{txn2}}
+	payoutID := types.SiacoinOutputID{3}
+	payout := types.SiacoinOutput{Value: types.NewCurrency64(5), UnlockHash: alice}
+	diffs2 := modules.ConsensusChangeDiffs{
+		SiacoinOutputDiffs: []modules.SiacoinOutputDiff{
+			{Direction: modules.DiffRevert, ID: txn1.SiacoinOutputID(0), SiacoinOutput: txn1.SiacoinOutputs[0]},
+			{Direction: modules.DiffApply, ID: txn2.SiacoinOutputID(0), SiacoinOutput: txn2.SiacoinOutputs[0]},
+			{Direction: modules.DiffApply, ID: txn2.SiacoinOutputID(1), SiacoinOutput: txn2.SiacoinOutputs[1]},
+			{Direction: modules.DiffApply, ID: payoutID, SiacoinOutput: payout},
+		},
+		DelayedSiacoinOutputDiffs: []modules.DelayedSiacoinOutputDiff{
+			{Direction: modules.DiffRevert, ID: payoutID, SiacoinOutput: payout},
+		},
+	}
+	err := e.db.Update(func(tx *bolt.Tx) error {
+		dbAddAddressEvents(tx, b1, 1, diffs1)
+		dbAddAddressEvents(tx, b2, 2, diffs2)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, exists := e.AddressHistory(alice, 0, 10)
+	if !exists {
+		t.Fatal("alice has no history")
+	}
+	if history.TotalEvents != 4 || len(history.Events) != 4 || !history.SiacoinBalance.Equals64(9) {
+		t.Fatal("wrong history", history)
+	}
+	expected := []struct {
+		typ     modules.AddressEventType
+		txid    types.TransactionID
+		balance uint64
+	}{
+		{modules.AddressEventSiacoinOutput, txn1.ID(), 10},
+		{modules.AddressEventSiacoinInput, txn2.ID(), 0},
+		{modules.AddressEventSiacoinOutput, txn2.ID(), 4},
+		{modules.AddressEventSiacoinPayout, types.TransactionID{}, 9},
+	}
+	for i, event := range history.Events {
+		if event.Type != expected[i].typ || event.TransactionID != expected[i].txid || !event.SiacoinBalance.Equals64(expected[i].balance) {
+			t.Fatalf("wrong event %v: %+v", i, event)
+		}
+	}
+
+	// Check pagination.
+	page, _ := e.AddressHistory(alice, 1, 2)
+	if page.TotalEvents != 4 || len(page.Events) != 2 || page.Events[0].Type != modules.AddressEventSiacoinInput {
+		t.Fatal("wrong page", page)
+	}
+	page, _ = e.AddressHistory(alice, 10, 2)
+	if page.TotalEvents != 4 || len(page.Events) != 0 {
+		t.Fatal("wrong page", page)
+	}
+
+	// Reverting the second block restores the balance of alice and removes
+	// bob.
+	err = e.db.Update(func(tx *bolt.Tx) error {
+		dbRemoveAddressEvents(tx, b2.ID(), diffs2)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, _ = e.AddressHistory(alice, 0, 10)
+	if history.TotalEvents != 1 || !history.SiacoinBalance.Equals64(10) {
+		t.Fatal("wrong history after revert", history)
+	}
+	if _, exists := e.AddressHistory(bob, 0, 10); exists {
+		t.Fatal("bob still has a history")
+	}
+}
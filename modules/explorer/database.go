@@ -11,6 +11,7 @@ import (
 
 var (
 	// database buckets
+	bucketAddressEvents         = []byte("AddressEvents")
 	bucketBlockFacts            = []byte("BlockFacts")
 	bucketBlockIDs              = []byte("BlockIDs")
 	bucketBlocksDifficulty      = []byte("BlocksDifficulty")
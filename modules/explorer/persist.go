@@ -35,6 +35,7 @@ func (e *Explorer) initPersist() error {
 	// Initialize the database
 	err = e.db.Update(func(tx *bolt.Tx) error {
 		buckets := [][]byte{
+			bucketAddressEvents,
 			bucketBlockFacts,
 			bucketBlockIDs,
 			bucketBlocksDifficulty,
@@ -49,6 +50,20 @@ func (e *Explorer) initPersist() error {
 			bucketTransactionIDs,
 			bucketUnlockHashes,
 		}
+		// Databases created before the address index was added are rebuilt
+		// from the beginning of the blockchain, so that the index contains
+		// every block.
+		if tx.Bucket(bucketInternal) != nil && tx.Bucket(bucketAddressEvents) == nil {
+			for _, b := range buckets {
+				if tx.Bucket(b) == nil {
+					continue
+				}
+				if err := tx.DeleteBucket(b); err != nil {
+					return err
+				}
+			}
+		}
+
 		for _, b := range buckets {
 			_, err := tx.CreateBucketIfNotExists(b)
 			if err != nil {
@@ -27,10 +27,11 @@ func (e *Explorer) ProcessConsensusChange(cc modules.ConsensusChange) {
 		}()
 
 		// Update cumulative stats for reverted blocks.
-		for _, block := range cc.RevertedBlocks {
+		for i, block := range cc.RevertedBlocks {
 			bid := block.ID()
 			tbid := types.TransactionID(bid)
 
+			dbRemoveAddressEvents(tx, bid, cc.RevertedDiffs[i])
 			dbRemoveBlockID(tx, bid)
 			dbRemoveTransactionID(tx, tbid) // Miner payouts are a transaction
 
@@ -116,17 +117,19 @@ func (e *Explorer) ProcessConsensusChange(cc modules.ConsensusChange) {
 
 		blockheight := cc.InitialHeight()
 		// Update cumulative stats for applied blocks.
-		for _, block := range cc.AppliedBlocks {
+		for i, block := range cc.AppliedBlocks {
 			bid := block.ID()
 			tbid := types.TransactionID(bid)
 
 			// special handling for genesis block
 			if bid == types.GenesisID {
 				dbAddGenesisBlock(tx)
+				dbAddAddressEvents(tx, block, 0, cc.AppliedDiffs[i])
 				continue
 			}
 
 			blockheight++
+			dbAddAddressEvents(tx, block, blockheight, cc.AppliedDiffs[i])
 			dbAddBlockID(tx, bid, blockheight)
 			dbAddTransactionID(tx, tbid, blockheight) // Miner payouts are a transaction
 
@@ -111,7 +111,144 @@ var (
 	}).([]NetAddress)
 )
 
+const (
+	// PeerOffenseInvalidBlock is reported when a peer relays a block which
+	// is invalid.
+	PeerOffenseInvalidBlock PeerOffense = "invalidblock"
+
+	// PeerOffenseStall is reported when a peer doesn't send any blocks
+	// before a block download times out.
+	PeerOffenseStall PeerOffense = "stall"
+)
+
+const (
+	// PortMappingNone indicates that a port isn't mapped on the router.
+	PortMappingNone PortMappingMethod = "none"
+
+	// PortMappingUPnP indicates that a port was mapped using UPnP.
+	PortMappingUPnP PortMappingMethod = "upnp"
+
+	// PortMappingNATPMP indicates that a port was mapped using NAT-PMP.
+	PortMappingNATPMP PortMappingMethod = "natpmp"
+
+	// PortMappingPCP indicates that a port was mapped using PCP.
+	PortMappingPCP PortMappingMethod = "pcp"
+)
+
+const (
+	// BandwidthProtocolBlocks is the traffic of block and header relay and
+	// synchronization.
+	BandwidthProtocolBlocks BandwidthProtocol = "blocks"
+
+	// BandwidthProtocolTransactions is the traffic of transaction relay.
+	BandwidthProtocolTransactions BandwidthProtocol = "transactions"
+
+	// BandwidthProtocolPeers is the traffic of the gateway's own RPCs, which
+	// share nodes and discover and probe the node's address.
+	BandwidthProtocolPeers BandwidthProtocol = "peers"
+
+	// BandwidthProtocolRenterHost is the traffic of the renter-host protocols
+	// served by the host.
+	BandwidthProtocolRenterHost BandwidthProtocol = "renterhost"
+
+	// BandwidthProtocolOther is the traffic of all other RPCs.
+	BandwidthProtocolOther BandwidthProtocol = "other"
+)
+
+// BandwidthProtocols are the protocols whose bandwidth is tracked by the
+// gateway.
+var BandwidthProtocols = []BandwidthProtocol{
+	BandwidthProtocolBlocks,
+	BandwidthProtocolTransactions,
+	BandwidthProtocolPeers,
+	BandwidthProtocolRenterHost,
+	BandwidthProtocolOther,
+}
+
 type (
+	// ASNLookup returns the number of the autonomous system which announces
+	// the IP.
+	ASNLookup func(ip net.IP) (uint32, error)
+
+	// BandwidthProtocol is a group of RPCs whose bandwidth usage is tracked
+	// separately.
+	BandwidthProtocol string
+
+	// BandwidthUsage is the number of bytes sent and received.
+	BandwidthUsage struct {
+		Upload   uint64 `json:"upload"`
+		Download uint64 `json:"download"`
+	}
+
+	// ProtocolBandwidth is the bandwidth usage of a protocol since startup
+	// and within the last hour and day.
+	ProtocolBandwidth struct {
+		Protocol BandwidthProtocol `json:"protocol"`
+		Total    BandwidthUsage    `json:"total"`
+		LastHour BandwidthUsage    `json:"lasthour"`
+		LastDay  BandwidthUsage    `json:"lastday"`
+	}
+
+	// GatewayConnectionLimits limits the number of inbound peers within the
+	// same subnet or autonomous system, which makes it harder for an attacker
+	// to occupy all peer slots of the node. For IPv6 addresses, /32 and /48
+	// subnets are used instead of /16 and /24 subnets. A limit of zero
+	// disables it. Local peers are exempt from the limits.
+	GatewayConnectionLimits struct {
+		MaxInboundPerSubnet16 uint64 `json:"maxinboundpersubnet16"`
+		MaxInboundPerSubnet24 uint64 `json:"maxinboundpersubnet24"`
+		MaxInboundPerASN      uint64 `json:"maxinboundperasn"`
+	}
+
+	// PeerOffense is misbehavior of a peer which lowers its score.
+	PeerOffense string
+
+	// PortMappingMethod is the protocol used to map a port on the router.
+	PortMappingMethod string
+
+	// PortMapping describes a port which the gateway tried to forward and
+	// whether it was reachable from the outside during the last probe.
+	PortMapping struct {
+		Port         string            `json:"port"`
+		ExternalPort string            `json:"externalport"`
+		Method       PortMappingMethod `json:"method"`
+		Error        string            `json:"error"`
+		Reachable    bool              `json:"reachable"`
+	}
+
+	// GatewayConnectivity describes whether the node is reachable from the
+	// outside. Reachability is probed by asking peers to dial the forwarded
+	// ports of the node.
+	GatewayConnectivity struct {
+		NetAddress   NetAddress    `json:"netaddress"`
+		PortMappings []PortMapping `json:"portmappings"`
+		Reachable    bool          `json:"reachable"`
+		LastProbe    time.Time     `json:"lastprobe"`
+		ProbeError   string        `json:"probeerror"`
+	}
+
+	// PeerBan is an address the gateway refuses to connect to. Permanent bans
+	// are the persisted blocklist, temporary bans are created automatically
+	// for misbehaving peers or manually with a duration and are lost when the
+	// gateway is restarted.
+	PeerBan struct {
+		Address   string    `json:"address"`
+		Reason    string    `json:"reason"`
+		Permanent bool      `json:"permanent"`
+		Expiry    time.Time `json:"expiry"`
+	}
+
+	// PeerScore describes the behavior of the peers of an address. Peers
+	// lose points for every offense within a time window and for high
+	// latency, and are banned temporarily if their score drops too low.
+	PeerScore struct {
+		Address       string        `json:"address"`
+		Score         int64         `json:"score"`
+		InvalidBlocks uint64        `json:"invalidblocks"`
+		Stalls        uint64        `json:"stalls"`
+		Latency       time.Duration `json:"latency"`
+	}
+
 	// Peer contains all the info necessary to Broadcast to a peer.
 	Peer struct {
 		Inbound    bool       `json:"inbound"`
@@ -147,6 +284,14 @@ type (
 		// BandwidthCounters returns the Gateway's upload and download bandwidth
 		BandwidthCounters() (uint64, uint64, time.Time, error)
 
+		// ProtocolBandwidth returns the bandwidth usage of the gateway split
+		// by protocol.
+		ProtocolBandwidth() ([]ProtocolBandwidth, error)
+
+		// RecordBandwidth records traffic of other modules which doesn't go
+		// through the gateway, such as the renter-host protocols.
+		RecordBandwidth(protocol BandwidthProtocol, upload, download uint64)
+
 		// Connect establishes a persistent connection to a peer.
 		Connect(NetAddress) error
 
@@ -166,6 +311,14 @@ type (
 		// the mapping is established or until it is interrupted by a shutdown.
 		ForwardPort(port string) error
 
+		// Connectivity returns the port mappings of the gateway and the
+		// result of the last reachability probe.
+		Connectivity() (GatewayConnectivity, error)
+
+		// ProbeConnectivity asks peers to dial the forwarded ports of the
+		// gateway and returns the updated connectivity.
+		ProbeConnectivity() (GatewayConnectivity, error)
+
 		// DisconnectManual is a Disconnect wrapper for a user-initiated
 		// disconnect
 		DisconnectManual(NetAddress) error
@@ -183,6 +336,26 @@ type (
 		// SetBlocklist sets the blocklist of the gateway
 		SetBlocklist(addresses []string) error
 
+		// BanPeers bans addresses for the given duration and disconnects from
+		// them. A duration of 0 adds the addresses to the blocklist.
+		BanPeers(addresses []string, duration time.Duration, reason string) error
+
+		// Bans returns the addresses of the blocklist and the temporary bans
+		// of the gateway.
+		Bans() ([]PeerBan, error)
+
+		// UnbanPeers removes addresses from the blocklist and the temporary
+		// bans and resets their score.
+		UnbanPeers(addresses []string) error
+
+		// PeerScores returns the scores of the addresses which the gateway
+		// has scored.
+		PeerScores() ([]PeerScore, error)
+
+		// ReportPeer lowers the score of a peer which misbehaved. Peers whose
+		// score drops too low are banned temporarily.
+		ReportPeer(addr NetAddress, offense PeerOffense)
+
 		// Address returns the Gateway's address.
 		Address() NetAddress
 
@@ -194,6 +367,18 @@ type (
 		// supply the given RPC ID.
 		RegisterRPC(string, RPCFunc)
 
+		// ConnectionLimits returns the limits of inbound peers per subnet and
+		// autonomous system.
+		ConnectionLimits() GatewayConnectionLimits
+
+		// SetConnectionLimits changes the limits of inbound peers per subnet
+		// and autonomous system. Existing peers are not disconnected.
+		SetConnectionLimits(limits GatewayConnectionLimits) error
+
+		// SetASNLookup replaces the lookup of the autonomous system of a
+		// peer, which is used to enforce MaxInboundPerASN.
+		SetASNLookup(lookup ASNLookup)
+
 		// RateLimits returns the currently set bandwidth limits of the gateway.
 		RateLimits() (int64, int64)
 
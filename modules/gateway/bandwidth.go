@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"sync"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+const (
+	// bandwidthBucketDuration is the duration covered by a bucket of the
+	// bandwidth tracker, which is the resolution of the rolling windows.
+	bandwidthBucketDuration = time.Minute
+
+	// bandwidthBuckets is the number of buckets kept per protocol, which is
+	// the length of the longest rolling window.
+	bandwidthBuckets = 24 * 60
+)
+
+// rpcProtocols maps the RPCs of the gateway and the modules using it to their
+// protocol. RPCs which aren't listed count as BandwidthProtocolOther.
+var rpcProtocols = map[rpcID]modules.BandwidthProtocol{
+	handlerName("SendBlocks"):          modules.BandwidthProtocolBlocks,
+	handlerName("SendBlk"):             modules.BandwidthProtocolBlocks,
+	handlerName("RelayHeader"):         modules.BandwidthProtocolBlocks,
+	handlerName("RelayTransactionSet"): modules.BandwidthProtocolTransactions,
+	handlerName("ShareNodes"):          modules.BandwidthProtocolPeers,
+	handlerName("DiscoverIP"):          modules.BandwidthProtocolPeers,
+	handlerName("ProbePorts"):          modules.BandwidthProtocolPeers,
+}
+
+type (
+	// bandwidthBucket is the traffic within a single bucket duration.
+	bandwidthBucket struct {
+		index int64
+		modules.BandwidthUsage
+	}
+
+	// protocolBandwidth is the traffic of a single protocol. The buckets form
+	// a ring which covers the last bandwidthBuckets bucket durations.
+	protocolBandwidth struct {
+		total   modules.BandwidthUsage
+		buckets [bandwidthBuckets]bandwidthBucket
+	}
+
+	// bandwidthTracker tracks the traffic of the gateway split by protocol.
+	bandwidthTracker struct {
+		protocols map[modules.BandwidthProtocol]*protocolBandwidth
+		mu        sync.Mutex
+	}
+
+	// bandwidthConn is a connection whose traffic is recorded by the bandwidth
+	// tracker.
+	bandwidthConn struct {
+		modules.PeerConn
+		staticProtocol modules.BandwidthProtocol
+		staticTracker  *bandwidthTracker
+	}
+)
+
+// newBandwidthTracker creates a bandwidth tracker without traffic.
+func newBandwidthTracker() *bandwidthTracker {
+	bt := &bandwidthTracker{
+		protocols: make(map[modules.BandwidthProtocol]*protocolBandwidth),
+	}
+	for _, protocol := range modules.BandwidthProtocols {
+		bt.protocols[protocol] = new(protocolBandwidth)
+	}
+	return bt
+}
+
+// rpcProtocol returns the protocol of the RPC with the given ID.
+func rpcProtocol(id rpcID) modules.BandwidthProtocol {
+	if protocol, ok := rpcProtocols[id]; ok {
+		return protocol
+	}
+	return modules.BandwidthProtocolOther
+}
+
+// bucketIndex returns the index of the bucket which covers the time.
+func bucketIndex(t time.Time) int64 {
+	return t.UnixNano() / int64(bandwidthBucketDuration)
+}
+
+// window returns the traffic of the buckets within the last n bucket
+// durations, including the current one.
+func (pb *protocolBandwidth) window(current int64, n int64) (usage modules.BandwidthUsage) {
+	for _, b := range pb.buckets {
+		if b.index <= current && current-b.index < n {
+			usage.Upload += b.Upload
+			usage.Download += b.Download
+		}
+	}
+	return usage
+}
+
+// callRecord records traffic of the protocol. Unknown protocols count as
+// BandwidthProtocolOther.
+func (bt *bandwidthTracker) callRecord(protocol modules.BandwidthProtocol, upload, download uint64, now time.Time) {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	pb, ok := bt.protocols[protocol]
+	if !ok {
+		pb = bt.protocols[modules.BandwidthProtocolOther]
+	}
+	pb.total.Upload += upload
+	pb.total.Download += download
+
+	index := bucketIndex(now)
+	b := &pb.buckets[index%bandwidthBuckets]
+	if b.index != index {
+		*b = bandwidthBucket{index: index}
+	}
+	b.Upload += upload
+	b.Download += download
+}
+
+// callUsage returns the traffic of all protocols.
+func (bt *bandwidthTracker) callUsage(now time.Time) []modules.ProtocolBandwidth {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	current := bucketIndex(now)
+	usage := make([]modules.ProtocolBandwidth, 0, len(modules.BandwidthProtocols))
+	for _, protocol := range modules.BandwidthProtocols {
+		pb := bt.protocols[protocol]
+		usage = append(usage, modules.ProtocolBandwidth{
+			Protocol: protocol,
+			Total:    pb.total,
+			LastHour: pb.window(current, int64(time.Hour/bandwidthBucketDuration)),
+			LastDay:  pb.window(current, bandwidthBuckets),
+		})
+	}
+	return usage
+}
+
+// newConn wraps the connection to record its traffic as traffic of the
+// protocol.
+func (bt *bandwidthTracker) newConn(conn modules.PeerConn, protocol modules.BandwidthProtocol) *bandwidthConn {
+	return &bandwidthConn{
+		PeerConn:       conn,
+		staticProtocol: protocol,
+		staticTracker:  bt,
+	}
+}
+
+// Read reads from the connection and records the bytes that were read.
+func (c *bandwidthConn) Read(p []byte) (int, error) {
+	n, err := c.PeerConn.Read(p)
+	if n > 0 {
+		c.staticTracker.callRecord(c.staticProtocol, 0, uint64(n), time.Now())
+	}
+	return n, err
+}
+
+// Write writes to the connection and records the bytes that were written.
+func (c *bandwidthConn) Write(p []byte) (int, error) {
+	n, err := c.PeerConn.Write(p)
+	if n > 0 {
+		c.staticTracker.callRecord(c.staticProtocol, uint64(n), 0, time.Now())
+	}
+	return n, err
+}
+
+// ProtocolBandwidth returns the bandwidth usage of the gateway split by
+// protocol. Only the payload of RPCs is attributed to a protocol, the overhead
+// of the peer connections is only included in BandwidthCounters.
+func (g *Gateway) ProtocolBandwidth() ([]modules.ProtocolBandwidth, error) {
+	if err := g.threads.Add(); err != nil {
+		return nil, err
+	}
+	defer g.threads.Done()
+	return g.staticBandwidth.callUsage(time.Now()), nil
+}
+
+// RecordBandwidth records traffic of other modules which doesn't go through
+// the gateway.
+func (g *Gateway) RecordBandwidth(protocol modules.BandwidthProtocol, upload, download uint64) {
+	g.staticBandwidth.callRecord(protocol, upload, download, time.Now())
+}
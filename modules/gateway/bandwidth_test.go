@@ -0,0 +1,117 @@
+package gateway
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+)
+
+// TestBandwidthTrackerWindows checks that the bandwidth tracker sums up the
+// traffic of the rolling windows.
+func TestBandwidthTrackerWindows(t *testing.T) {
+	t.Parallel()
+	bt := newBandwidthTracker()
+	now := time.Now()
+	bt.callRecord(modules.BandwidthProtocolBlocks, 1, 2, now.Add(-25*time.Hour))
+	bt.callRecord(modules.BandwidthProtocolBlocks, 10, 20, now.Add(-2*time.Hour))
+	bt.callRecord(modules.BandwidthProtocolBlocks, 100, 200, now.Add(-time.Minute))
+	bt.callRecord(modules.BandwidthProtocolBlocks, 1000, 2000, now)
+	bt.callRecord("unknown", 5, 5, now)
+
+	usage := bt.callUsage(now)
+	if len(usage) != len(modules.BandwidthProtocols) {
+		t.Fatal("wrong number of protocols", len(usage))
+	}
+	for _, pb := range usage {
+		switch pb.Protocol {
+		case modules.BandwidthProtocolBlocks:
+			if pb.Total != (modules.BandwidthUsage{Upload: 1111, Download: 2222}) {
+				t.Error("wrong total", pb.Total)
+			}
+			if pb.LastDay != (modules.BandwidthUsage{Upload: 1110, Download: 2220}) {
+				t.Error("wrong last day", pb.LastDay)
+			}
+			if pb.LastHour != (modules.BandwidthUsage{Upload: 1100, Download: 2200}) {
+				t.Error("wrong last hour", pb.LastHour)
+			}
+		case modules.BandwidthProtocolOther:
+			if pb.Total != (modules.BandwidthUsage{Upload: 5, Download: 5}) || pb.LastHour != pb.Total {
+				t.Error("unknown protocols should count as other", pb)
+			}
+		default:
+			if pb.Total != (modules.BandwidthUsage{}) {
+				t.Error("unexpected traffic", pb)
+			}
+		}
+	}
+
+	// A bucket is reset when it's reused a day later.
+	bt.callRecord(modules.BandwidthProtocolBlocks, 1, 1, now.Add(24*time.Hour))
+	usage = bt.callUsage(now.Add(24 * time.Hour))
+	if usage[0].LastDay != (modules.BandwidthUsage{Upload: 1, Download: 1}) {
+		t.Fatal("bucket wasn't reset", usage[0].LastDay)
+	}
+}
+
+// TestProtocolBandwidth checks that the traffic of RPCs is attributed to their
+// protocol on both sides of the connection.
+func TestProtocolBandwidth(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	g1 := newNamedTestingGateway(t, "1")
+	defer g1.Close()
+	g2 := newNamedTestingGateway(t, "2")
+	defer g2.Close()
+
+	g2.RegisterRPC("RelayTransactionSet", func(conn modules.PeerConn) error {
+		_, err := conn.Write(make([]byte, 100))
+		return err
+	})
+	if err := connectToNode(g1, g2, false); err != nil {
+		t.Fatal(err)
+	}
+	err := g1.RPC(g2.Address(), "RelayTransactionSet", func(conn modules.PeerConn) error {
+		_, err := conn.Read(make([]byte, 100))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1.RecordBandwidth(modules.BandwidthProtocolRenterHost, 7, 8)
+
+	usage := func(g *Gateway, protocol modules.BandwidthProtocol) modules.BandwidthUsage {
+		protocols, err := g.ProtocolBandwidth()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pb := range protocols {
+			if pb.Protocol == protocol {
+				return pb.Total
+			}
+		}
+		t.Fatal("protocol not found", protocol)
+		return modules.BandwidthUsage{}
+	}
+	// The header of the RPC is 16 bytes.
+	if u := usage(g1, modules.BandwidthProtocolTransactions); u.Upload != 16 || u.Download != 100 {
+		t.Fatal("wrong caller traffic", u)
+	}
+	// The callee might not have recorded its response yet.
+	err = build.Retry(100, 10*time.Millisecond, func() error {
+		if u := usage(g2, modules.BandwidthProtocolTransactions); u.Upload != 100 || u.Download != 16 {
+			return fmt.Errorf("wrong callee traffic %v", u)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u := usage(g1, modules.BandwidthProtocolRenterHost); u.Upload != 7 || u.Download != 8 {
+		t.Fatal("recorded traffic is missing", u)
+	}
+}
@@ -34,6 +34,15 @@ func newLocalAddr(addr modules.NetAddress) *net.TCPAddr {
 // staticDial appropriately handles things like clean shutdown, fast shutdown,
 // and chooses the correct communication protocol.
 func (g *Gateway) staticDial(addr modules.NetAddress) (net.Conn, error) {
+	if g.staticProxy.Address != "" {
+		conn, err := g.staticDialProxy(addr)
+		if err != nil {
+			return nil, err
+		}
+		conn.SetDeadline(time.Now().Add(connStdDeadline))
+		return connmonitor.NewMonitoredConn(conn, g.m), nil
+	}
+
 	dialer := &net.Dialer{
 		Cancel:  g.threads.StopChan(),
 		Timeout: dialTimeout,
@@ -0,0 +1,267 @@
+package gateway
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/modules"
+)
+
+var (
+	// errNoProbePeers is returned by a probe if the gateway has no peers to
+	// ask.
+	errNoProbePeers = errors.New("no peers to probe the ports with")
+
+	// errProbeProxy is returned by a probe if the gateway uses a proxy, in
+	// which case peers would dial the proxy instead of the gateway.
+	errProbeProxy = errors.New("ports can't be probed through a proxy")
+)
+
+// connectivity tracks the ports forwarded by the gateway and whether they
+// were reachable during the last probe.
+type connectivity struct {
+	mappings  map[string]*modules.PortMapping
+	lastProbe time.Time
+	probeErr  error
+
+	mu sync.Mutex
+}
+
+// newConnectivity creates a connectivity without ports.
+func newConnectivity() *connectivity {
+	return &connectivity{
+		mappings: make(map[string]*modules.PortMapping),
+	}
+}
+
+// mapping returns the mapping of the port, creating it if necessary. The
+// caller must hold the lock.
+func (c *connectivity) mapping(port string) *modules.PortMapping {
+	pm, ok := c.mappings[port]
+	if !ok {
+		pm = &modules.PortMapping{
+			Port:   port,
+			Method: modules.PortMappingNone,
+		}
+		c.mappings[port] = pm
+	}
+	return pm
+}
+
+// callAddPort starts tracking a port.
+func (c *connectivity) callAddPort(port string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mapping(port)
+}
+
+// callSetMapping sets the mapping of a port.
+func (c *connectivity) callSetMapping(port string, method modules.PortMappingMethod, externalPort string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pm := c.mapping(port)
+	pm.Method = method
+	pm.ExternalPort = externalPort
+	pm.Error = ""
+	if err != nil {
+		pm.Error = err.Error()
+	}
+}
+
+// callPorts returns the tracked ports.
+func (c *connectivity) callPorts() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ports := make([]string, 0, len(c.mappings))
+	for port := range c.mappings {
+		ports = append(ports, port)
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+// callSetProbe records the result of a probe.
+func (c *connectivity) callSetProbe(reachable map[string]bool, err error, probeTime time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for port, pm := range c.mappings {
+		pm.Reachable = reachable[port]
+	}
+	c.lastProbe = probeTime
+	c.probeErr = err
+}
+
+// callStatus returns the connectivity of the gateway. The gateway is
+// reachable if all of its ports were reachable during the last probe.
+func (c *connectivity) callStatus(addr modules.NetAddress) modules.GatewayConnectivity {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := modules.GatewayConnectivity{
+		NetAddress:   addr,
+		PortMappings: make([]modules.PortMapping, 0, len(c.mappings)),
+		Reachable:    !c.lastProbe.IsZero() && c.probeErr == nil,
+		LastProbe:    c.lastProbe,
+	}
+	if c.probeErr != nil {
+		status.ProbeError = c.probeErr.Error()
+	}
+	for _, pm := range c.mappings {
+		status.PortMappings = append(status.PortMappings, *pm)
+		status.Reachable = status.Reachable && pm.Reachable
+	}
+	sort.Slice(status.PortMappings, func(i, j int) bool {
+		return status.PortMappings[i].Port < status.PortMappings[j].Port
+	})
+	return status
+}
+
+// rpcProbePorts is the handler for the ProbePorts RPC. It dials the requested
+// ports on the IP of the caller and returns whether they are reachable.
+func (g *Gateway) rpcProbePorts(conn modules.PeerConn) error {
+	conn.SetDeadline(time.Now().Add(connStdDeadline))
+	var ports []string
+	if err := encoding.ReadObject(conn, &ports, maxProbePorts*16); err != nil {
+		return errors.AddContext(err, "failed to read ports")
+	}
+	if len(ports) > maxProbePorts {
+		return errors.New("too many ports to probe")
+	}
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return errors.AddContext(err, "failed to split host from port")
+	}
+
+	reachable := make([]bool, len(ports))
+	var wg sync.WaitGroup
+	for i, port := range ports {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, port string) {
+			defer wg.Done()
+			c, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), probeTimeout)
+			if err == nil {
+				reachable[i] = true
+				c.Close()
+			}
+		}(i, port)
+	}
+	wg.Wait()
+	return encoding.WriteObject(conn, reachable)
+}
+
+// managedProbe asks random peers to dial the tracked ports in parallel. A
+// port is reachable if any of the peers reached it.
+func (g *Gateway) managedProbe() {
+	if g.staticProxy.Address != "" {
+		g.staticConnectivity.callSetProbe(nil, errProbeProxy, time.Now())
+		return
+	}
+	ports := g.staticConnectivity.callPorts()
+	peers := g.Peers()
+	if len(peers) == 0 {
+		g.staticConnectivity.callSetProbe(nil, errNoProbePeers, time.Now())
+		return
+	}
+	fastrand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+	if len(peers) > probePeers {
+		peers = peers[:probePeers]
+	}
+
+	var mu sync.Mutex
+	var answered int
+	var probeErr error
+	reachable := make(map[string]bool)
+	var wg sync.WaitGroup
+	for _, peer := range peers {
+		wg.Add(1)
+		go func(addr modules.NetAddress) {
+			defer wg.Done()
+			err := g.RPC(addr, "ProbePorts", func(conn modules.PeerConn) error {
+				if err := encoding.WriteObject(conn, ports); err != nil {
+					return err
+				}
+				var results []bool
+				if err := encoding.ReadObject(conn, &results, uint64(8+len(ports))); err != nil {
+					return err
+				}
+				if len(results) != len(ports) {
+					return errors.New("wrong number of results")
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for i, ok := range results {
+					reachable[ports[i]] = reachable[ports[i]] || ok
+				}
+				return nil
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				probeErr = errors.Compose(probeErr, errors.AddContext(err, string(addr)))
+				return
+			}
+			answered++
+		}(peer.NetAddress)
+	}
+	wg.Wait()
+
+	// The probe only fails if none of the peers answered, since older peers
+	// don't support the RPC.
+	if answered > 0 {
+		probeErr = nil
+	} else {
+		probeErr = errors.AddContext(probeErr, "no peer answered the probe")
+	}
+	g.staticConnectivity.callSetProbe(reachable, probeErr, time.Now())
+}
+
+// threadedProbeConnectivity probes the reachability of the forwarded ports
+// regularly.
+func (g *Gateway) threadedProbeConnectivity() {
+	if err := g.threads.Add(); err != nil {
+		return
+	}
+	defer g.threads.Done()
+
+	if !g.managedSleep(probeStartupDelay) {
+		return // shutdown interrupted sleep
+	}
+	for {
+		g.managedProbe()
+		if !g.managedSleep(probeInterval) {
+			return // shutdown interrupted sleep
+		}
+	}
+}
+
+// Connectivity returns the port mappings of the gateway and the result of
+// the last reachability probe.
+func (g *Gateway) Connectivity() (modules.GatewayConnectivity, error) {
+	if err := g.threads.Add(); err != nil {
+		return modules.GatewayConnectivity{}, err
+	}
+	defer g.threads.Done()
+	return g.staticConnectivity.callStatus(g.Address()), nil
+}
+
+// ProbeConnectivity asks peers to dial the forwarded ports of the gateway and
+// returns the updated connectivity.
+func (g *Gateway) ProbeConnectivity() (modules.GatewayConnectivity, error) {
+	if err := g.threads.Add(); err != nil {
+		return modules.GatewayConnectivity{}, err
+	}
+	defer g.threads.Done()
+	g.managedProbe()
+	return g.staticConnectivity.callStatus(g.Address()), nil
+}
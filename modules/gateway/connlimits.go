@@ -0,0 +1,216 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+)
+
+var (
+	// errSubnet16Limit is returned if a peer is rejected because of the limit
+	// of inbound peers per /16 subnet.
+	errSubnet16Limit = errors.New("too many inbound peers from the same /16 subnet")
+
+	// errSubnet24Limit is returned if a peer is rejected because of the limit
+	// of inbound peers per /24 subnet.
+	errSubnet24Limit = errors.New("too many inbound peers from the same /24 subnet")
+
+	// errASNLimit is returned if a peer is rejected because of the limit of
+	// inbound peers per autonomous system.
+	errASNLimit = errors.New("too many inbound peers from the same autonomous system")
+)
+
+type (
+	// asnCache caches the results of ASN lookups by /24 subnet, since smaller
+	// prefixes aren't routed between autonomous systems.
+	asnCache struct {
+		entries map[string]asnCacheEntry
+		lookup  modules.ASNLookup
+		mu      sync.Mutex
+	}
+
+	// asnCacheEntry is a cached ASN lookup.
+	asnCacheEntry struct {
+		asn    uint32
+		expiry time.Time
+	}
+)
+
+// newASNCache creates an empty cache which uses the default ASN lookup.
+func newASNCache() *asnCache {
+	return &asnCache{
+		entries: make(map[string]asnCacheEntry),
+		lookup:  lookupASN,
+	}
+}
+
+// callSetLookup replaces the lookup and clears the cache.
+func (c *asnCache) callSetLookup(lookup modules.ASNLookup) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]asnCacheEntry)
+	c.lookup = lookup
+}
+
+// callLookup returns the ASN of the IP, looking it up if it isn't cached. The
+// lock isn't held during the lookup.
+func (c *asnCache) callLookup(ip net.IP, now time.Time) (uint32, error) {
+	_, subnet := subnets(ip)
+	c.mu.Lock()
+	entry, ok := c.entries[subnet]
+	lookup := c.lookup
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiry) {
+		return entry.asn, nil
+	}
+
+	asn, err := lookup(ip)
+	if err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxASNCacheEntries {
+		c.entries = make(map[string]asnCacheEntry)
+	}
+	c.entries[subnet] = asnCacheEntry{
+		asn:    asn,
+		expiry: now.Add(asnCacheDuration),
+	}
+	return asn, nil
+}
+
+// lookupASN is the default ASN lookup. It queries the IP to ASN mapping of
+// Team Cymru over DNS.
+func lookupASN(ip net.IP) (uint32, error) {
+	var name string
+	if ip4 := ip.To4(); ip4 != nil {
+		name = fmt.Sprintf("%d.%d.%d.%d.origin.asn.cymru.com", ip4[3], ip4[2], ip4[1], ip4[0])
+	} else {
+		// IPv6 addresses are queried nibble by nibble in reverse order.
+		var nibbles []string
+		for i := len(ip) - 1; i >= 0; i-- {
+			nibbles = append(nibbles, strconv.FormatUint(uint64(ip[i]&0xf), 16), strconv.FormatUint(uint64(ip[i]>>4), 16))
+		}
+		name = strings.Join(nibbles, ".") + ".origin6.asn.cymru.com"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), asnLookupTimeout)
+	defer cancel()
+	records, err := net.DefaultResolver.LookupTXT(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	// The records look like "13335 | 1.1.1.0/24 | AU | apnic | 2011-08-11".
+	// Prefixes announced by multiple systems list all of them.
+	for _, record := range records {
+		fields := strings.Fields(strings.Split(record, "|")[0])
+		if len(fields) == 0 {
+			continue
+		}
+		asn, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			return 0, errors.AddContext(err, "invalid ASN record")
+		}
+		return uint32(asn), nil
+	}
+	return 0, errors.New("no ASN record found")
+}
+
+// subnets returns the /16 and /24 subnets of an IPv4 address or the /32 and
+// /48 subnets of an IPv6 address.
+func subnets(ip net.IP) (subnet16, subnet24 string) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(16, 32)).String() + "/16", ip4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+	}
+	return ip.Mask(net.CIDRMask(32, 128)).String() + "/32", ip.Mask(net.CIDRMask(48, 128)).String() + "/48"
+}
+
+// inboundLimitErr returns an error if accepting an inbound peer with the IP
+// and ASN would exceed one of the connection limits. An ASN of 0 is unknown.
+// The caller must hold the lock.
+func (g *Gateway) inboundLimitErr(ip net.IP, asn uint32) error {
+	limits := g.persist.ConnectionLimits
+	subnet16, subnet24 := subnets(ip)
+	var num16, num24, numASN uint64
+	for _, p := range g.peers {
+		if !p.Inbound || p.Local {
+			continue
+		}
+		if asn != 0 && p.asn == asn {
+			numASN++
+		}
+		peerIP := net.ParseIP(p.NetAddress.Host())
+		if peerIP == nil {
+			continue
+		}
+		peer16, peer24 := subnets(peerIP)
+		if peer16 == subnet16 {
+			num16++
+		}
+		if peer24 == subnet24 {
+			num24++
+		}
+	}
+	switch {
+	case limits.MaxInboundPerSubnet16 > 0 && num16 >= limits.MaxInboundPerSubnet16:
+		return errSubnet16Limit
+	case limits.MaxInboundPerSubnet24 > 0 && num24 >= limits.MaxInboundPerSubnet24:
+		return errSubnet24Limit
+	case limits.MaxInboundPerASN > 0 && asn != 0 && numASN >= limits.MaxInboundPerASN:
+		return errASNLimit
+	}
+	return nil
+}
+
+// managedLookupASN returns the ASN of the IP if the limit of inbound peers per
+// autonomous system is enabled. Otherwise, or if the lookup fails, the ASN is
+// unknown and 0 is returned.
+func (g *Gateway) managedLookupASN(ip net.IP) uint32 {
+	g.mu.RLock()
+	enabled := g.persist.ConnectionLimits.MaxInboundPerASN > 0
+	g.mu.RUnlock()
+	if !enabled {
+		return 0
+	}
+	asn, err := g.staticASNCache.callLookup(ip, time.Now())
+	if err != nil {
+		g.log.Debugf("WARN: failed to look up the ASN of %v: %v", ip, err)
+		return 0
+	}
+	return asn
+}
+
+// ConnectionLimits returns the limits of inbound peers per subnet and
+// autonomous system.
+func (g *Gateway) ConnectionLimits() modules.GatewayConnectionLimits {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.persist.ConnectionLimits
+}
+
+// SetConnectionLimits changes the limits of inbound peers per subnet and
+// autonomous system. Existing peers are not disconnected.
+func (g *Gateway) SetConnectionLimits(limits modules.GatewayConnectionLimits) error {
+	if err := g.threads.Add(); err != nil {
+		return err
+	}
+	defer g.threads.Done()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.persist.ConnectionLimits = limits
+	return g.saveSync()
+}
+
+// SetASNLookup replaces the lookup of the autonomous system of a peer, which
+// is used to enforce MaxInboundPerASN.
+func (g *Gateway) SetASNLookup(lookup modules.ASNLookup) {
+	g.staticASNCache.callSetLookup(lookup)
+}
@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestSubnets checks the subnets of IPv4 and IPv6 addresses.
+func TestSubnets(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ip       string
+		subnet16 string
+		subnet24 string
+	}{
+		{"1.2.3.4", "1.2.0.0/16", "1.2.3.0/24"},
+		{"::ffff:1.2.3.4", "1.2.0.0/16", "1.2.3.0/24"},
+		{"2001:db8:1:2::1", "2001:db8::/32", "2001:db8:1::/48"},
+	}
+	for _, test := range tests {
+		subnet16, subnet24 := subnets(net.ParseIP(test.ip))
+		if subnet16 != test.subnet16 || subnet24 != test.subnet24 {
+			t.Errorf("wrong subnets of %v: %v %v", test.ip, subnet16, subnet24)
+		}
+	}
+}
+
+// TestInboundLimitErr checks that inbound peers are limited per subnet and
+// autonomous system.
+func TestInboundLimitErr(t *testing.T) {
+	t.Parallel()
+	g := &Gateway{peers: make(map[modules.NetAddress]*peer)}
+	g.persist.ConnectionLimits = modules.GatewayConnectionLimits{
+		MaxInboundPerSubnet16: 3,
+		MaxInboundPerSubnet24: 2,
+		MaxInboundPerASN:      4,
+	}
+	addPeer := func(addr string, inbound bool, asn uint32) {
+		na := modules.NetAddress(addr)
+		g.peers[na] = &peer{
+			Peer: modules.Peer{
+				Inbound:    inbound,
+				Local:      na.IsLocal(),
+				NetAddress: na,
+			},
+			asn: asn,
+		}
+	}
+	check := func(ip string, asn uint32, expected error) {
+		t.Helper()
+		if err := g.inboundLimitErr(net.ParseIP(ip), asn); err != expected {
+			t.Fatalf("expected %v for %v, got %v", expected, ip, err)
+		}
+	}
+
+	// Outbound and local peers don't count.
+	addPeer("1.2.3.4:9981", false, 1)
+	addPeer("1.2.3.5:9981", false, 1)
+	addPeer("192.168.1.1:9981", true, 1)
+	addPeer("192.168.1.2:9981", true, 1)
+	check("1.2.3.6", 1, nil)
+	check("192.168.1.3", 1, nil)
+
+	// The /24 limit is reached first.
+	addPeer("1.2.3.4:9982", true, 1)
+	check("1.2.3.6", 1, nil)
+	addPeer("1.2.3.5:9982", true, 1)
+	check("1.2.3.6", 1, errSubnet24Limit)
+	check("1.2.4.1", 1, nil)
+
+	// Then the /16 limit.
+	addPeer("1.2.4.1:9981", true, 1)
+	check("1.2.5.1", 2, errSubnet16Limit)
+	check("1.3.0.1", 2, nil)
+
+	// And finally the ASN limit, which is ignored if the ASN is unknown.
+	addPeer("5.6.7.8:9981", true, 1)
+	check("9.9.9.9", 1, errASNLimit)
+	check("9.9.9.9", 0, nil)
+	check("9.9.9.9", 2, nil)
+
+	// Disabled limits are ignored.
+	g.persist.ConnectionLimits = modules.GatewayConnectionLimits{}
+	check("1.2.3.6", 1, nil)
+}
+
+// TestASNCache checks that ASN lookups are cached by /24 subnet.
+func TestASNCache(t *testing.T) {
+	t.Parallel()
+	c := newASNCache()
+	var lookups int
+	c.callSetLookup(func(ip net.IP) (uint32, error) {
+		lookups++
+		if ip.Equal(net.ParseIP("9.9.9.9")) {
+			return 0, errors.New("lookup failed")
+		}
+		return uint32(ip.To4()[0]), nil
+	})
+
+	now := time.Now()
+	for _, ip := range []string{"1.2.3.4", "1.2.3.5"} {
+		asn, err := c.callLookup(net.ParseIP(ip), now)
+		if err != nil || asn != 1 {
+			t.Fatal("wrong ASN", asn, err)
+		}
+	}
+	if lookups != 1 {
+		t.Fatal("lookup wasn't cached", lookups)
+	}
+	if asn, err := c.callLookup(net.ParseIP("2.2.3.4"), now); err != nil || asn != 2 || lookups != 2 {
+		t.Fatal("wrong ASN", asn, err, lookups)
+	}
+
+	// Failed lookups aren't cached.
+	for i := 0; i < 2; i++ {
+		if _, err := c.callLookup(net.ParseIP("9.9.9.9"), now); err == nil {
+			t.Fatal("lookup should fail")
+		}
+	}
+	if lookups != 4 {
+		t.Fatal("failed lookup was cached", lookups)
+	}
+
+	// Entries expire.
+	if _, err := c.callLookup(net.ParseIP("1.2.3.4"), now.Add(asnCacheDuration)); err != nil || lookups != 5 {
+		t.Fatal("entry didn't expire", err, lookups)
+	}
+}
+
+// TestConnectionLimitsPersist checks that the connection limits default to
+// defaultConnectionLimits and are persisted.
+func TestConnectionLimitsPersist(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	g := newTestingGateway(t)
+	if g.ConnectionLimits() != defaultConnectionLimits {
+		t.Fatal("wrong default limits", g.ConnectionLimits())
+	}
+	limits := modules.GatewayConnectionLimits{
+		MaxInboundPerSubnet16: 10,
+		MaxInboundPerSubnet24: 0,
+		MaxInboundPerASN:      5,
+	}
+	if err := g.SetConnectionLimits(limits); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Close(); err != nil {
+		t.Fatal(err)
+	}
+	g, err := New("localhost:0", false, g.persistDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	if g.ConnectionLimits() != limits {
+		t.Fatal("limits weren't persisted", g.ConnectionLimits())
+	}
+}
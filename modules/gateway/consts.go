@@ -40,6 +40,30 @@ const (
 	// codebase were made that weren't backwards compatible. This might include
 	// changes to the protocol or hardforks.
 	minimumAcceptablePeerVersion = "1.5.4"
+
+	// natpmpLifetime is the lifetime in seconds requested for NAT-PMP and
+	// PCP port mappings. The mappings are renewed after half of the lifetime
+	// granted by the router.
+	natpmpLifetime = 7200
+
+	// natpmpRetryLifetime is the lifetime in seconds which is assumed for a
+	// mapping which failed to renew, to retry after half of it.
+	natpmpRetryLifetime = 120
+
+	// maxProbePorts is the maximum number of ports a peer may ask to be
+	// probed in a single ProbePorts RPC.
+	maxProbePorts = 8
+
+	// asnCacheDuration is the duration for which the result of an ASN lookup
+	// is cached.
+	asnCacheDuration = 24 * time.Hour
+
+	// asnLookupTimeout is the timeout of a single ASN lookup.
+	asnLookupTimeout = 5 * time.Second
+
+	// maxASNCacheEntries is the number of cached ASN lookups at which the
+	// cache is cleared.
+	maxASNCacheEntries = 10000
 )
 
 var (
@@ -153,6 +177,17 @@ var (
 		Testing:  500 * time.Millisecond,
 	}).(time.Duration)
 
+	// defaultConnectionLimits are the limits of inbound peers per subnet and
+	// autonomous system of a new gateway. The limit per autonomous system is
+	// disabled by default since the default lookup queries a public DNS
+	// service.
+	defaultConnectionLimits = build.Select(build.Var{
+		Standard: modules.GatewayConnectionLimits{MaxInboundPerSubnet16: 16, MaxInboundPerSubnet24: 4},
+		Testnet:  modules.GatewayConnectionLimits{MaxInboundPerSubnet16: 16, MaxInboundPerSubnet24: 4},
+		Dev:      modules.GatewayConnectionLimits{MaxInboundPerSubnet16: 8, MaxInboundPerSubnet24: 4},
+		Testing:  modules.GatewayConnectionLimits{MaxInboundPerSubnet16: 4, MaxInboundPerSubnet24: 2},
+	}).(modules.GatewayConnectionLimits)
+
 	// fullyConnectedThreshold defines the number of peers that the gateway can
 	// have before it stops accepting inbound connections.
 	fullyConnectedThreshold = build.Select(build.Var{
@@ -215,6 +250,68 @@ var (
 )
 
 var (
+	// natpmpInitialTimeout is the time waited for the response to the first
+	// NAT-PMP or PCP request. It doubles with every retransmission.
+	natpmpInitialTimeout = build.Select(build.Var{
+		Standard: 250 * time.Millisecond,
+		Testnet:  250 * time.Millisecond,
+		Dev:      250 * time.Millisecond,
+		Testing:  50 * time.Millisecond,
+	}).(time.Duration)
+
+	// natpmpRetries is the number of times a NAT-PMP or PCP request is sent
+	// before giving up.
+	natpmpRetries = build.Select(build.Var{
+		Standard: 5,
+		Testnet:  5,
+		Dev:      4,
+		Testing:  3,
+	}).(int)
+
+	// natpmpDeleteTimeout is the time spent trying to delete the NAT-PMP and
+	// PCP mappings at shutdown.
+	natpmpDeleteTimeout = build.Select(build.Var{
+		Standard: 5 * time.Second,
+		Testnet:  5 * time.Second,
+		Dev:      3 * time.Second,
+		Testing:  time.Second,
+	}).(time.Duration)
+
+	// probeInterval is the interval at which the gateway probes whether its
+	// forwarded ports are reachable.
+	probeInterval = build.Select(build.Var{
+		Standard: time.Hour,
+		Testnet:  time.Hour,
+		Dev:      10 * time.Minute,
+		Testing:  10 * time.Second,
+	}).(time.Duration)
+
+	// probeStartupDelay is the time waited after startup before the first
+	// probe, to give the gateway time to connect to peers.
+	probeStartupDelay = build.Select(build.Var{
+		Standard: 5 * time.Minute,
+		Testnet:  5 * time.Minute,
+		Dev:      time.Minute,
+		Testing:  3 * time.Second,
+	}).(time.Duration)
+
+	// probePeers is the number of peers asked to dial the forwarded ports
+	// during a probe.
+	probePeers = build.Select(build.Var{
+		Standard: 3,
+		Testnet:  3,
+		Dev:      2,
+		Testing:  2,
+	}).(int)
+
+	// probeTimeout is the timeout of the dials performed for a probe.
+	probeTimeout = build.Select(build.Var{
+		Standard: 15 * time.Second,
+		Testnet:  15 * time.Second,
+		Dev:      10 * time.Second,
+		Testing:  time.Second,
+	}).(time.Duration)
+
 	// connStdDeadline defines the standard deadline that should be used for
 	// all temporary connections to the gateway.
 	connStdDeadline = build.Select(build.Var{
@@ -290,3 +387,50 @@ var (
 		Testing:  100 * time.Millisecond,
 	}).(time.Duration)
 )
+
+const (
+	// peerBanScore is the score at which a peer is banned temporarily.
+	peerBanScore = -100
+
+	// invalidBlockPenalty is the number of points a peer loses for relaying
+	// an invalid block.
+	invalidBlockPenalty = 50
+
+	// stallPenalty is the number of points a peer loses for stalling a block
+	// download.
+	stallPenalty = 10
+
+	// highLatencyPenalty is the number of points a peer loses while its
+	// latency is above highLatencyThreshold. It is not enough to get a peer
+	// banned on its own.
+	highLatencyPenalty = 10
+)
+
+var (
+	// highLatencyThreshold is the handshake latency above which a peer is
+	// penalized.
+	highLatencyThreshold = build.Select(build.Var{
+		Standard: 5 * time.Second,
+		Testnet:  5 * time.Second,
+		Dev:      5 * time.Second,
+		Testing:  time.Second,
+	}).(time.Duration)
+
+	// peerBanDuration is the duration of the temporary ban of a peer whose
+	// score dropped to peerBanScore.
+	peerBanDuration = build.Select(build.Var{
+		Standard: 24 * time.Hour,
+		Testnet:  24 * time.Hour,
+		Dev:      10 * time.Minute,
+		Testing:  3 * time.Second,
+	}).(time.Duration)
+
+	// peerScoreWindow is the time after which an offense no longer counts
+	// towards the score of a peer.
+	peerScoreWindow = build.Select(build.Var{
+		Standard: 24 * time.Hour,
+		Testnet:  24 * time.Hour,
+		Dev:      10 * time.Minute,
+		Testing:  3 * time.Second,
+	}).(time.Duration)
+)
@@ -96,6 +96,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -152,13 +153,18 @@ type Gateway struct {
 	peerTG    threadgroup.ThreadGroup
 
 	// Utilities.
-	log           *persist.Logger
-	mu            sync.RWMutex
-	persist       persistence
-	persistDir    string
-	threads       threadgroup.ThreadGroup
-	staticAlerter *modules.GenericAlerter
-	staticDeps    modules.Dependencies
+	log                *persist.Logger
+	mu                 sync.RWMutex
+	persist            persistence
+	persistDir         string
+	threads            threadgroup.ThreadGroup
+	staticAlerter      *modules.GenericAlerter
+	staticASNCache     *asnCache
+	staticBandwidth    *bandwidthTracker
+	staticConnectivity *connectivity
+	staticDeps         modules.Dependencies
+	staticPeerScores   *peerScores
+	staticProxy        ProxySettings
 
 	// Unique ID
 	staticID gatewayID
@@ -173,24 +179,7 @@ func (g *Gateway) addToBlocklist(addresses []string) error {
 	// Add addresses to the blocklist and disconnect from them
 	var err error
 	for _, addr := range addresses {
-		// Check Gateway peer map for address
-		for peerAddr, peer := range g.peers {
-			// If the address corresponds with a peer, close the peer session
-			// and remove the peer from the peer map
-			if peerAddr.Host() == addr {
-				err = errors.Compose(err, peer.sess.Close())
-				delete(g.peers, peerAddr)
-			}
-		}
-		// Check Gateway node map for address
-		for nodeAddr := range g.nodes {
-			// If the address corresponds with a node remove the node from the
-			// node map to prevent the node from being re-connected while
-			// looking for a replacement peer
-			if nodeAddr.Host() == addr {
-				delete(g.nodes, nodeAddr)
-			}
-		}
+		err = errors.Compose(err, g.disconnectHost(addr))
 
 		// Add address to the blocklist
 		g.blocklist[addr] = struct{}{}
@@ -198,6 +187,40 @@ func (g *Gateway) addToBlocklist(addresses []string) error {
 	return errors.Compose(err, g.saveSync())
 }
 
+// disconnectHost disconnects from all peers of a host and removes its nodes
+// from the node list.
+func (g *Gateway) disconnectHost(host string) error {
+	var err error
+	// Check Gateway peer map for address
+	for peerAddr, peer := range g.peers {
+		// If the address corresponds with a peer, close the peer session
+		// and remove the peer from the peer map
+		if peerAddr.Host() == host {
+			err = errors.Compose(err, peer.sess.Close())
+			delete(g.peers, peerAddr)
+		}
+	}
+	// Check Gateway node map for address
+	for nodeAddr := range g.nodes {
+		// If the address corresponds with a node remove the node from the
+		// node map to prevent the node from being re-connected while
+		// looking for a replacement peer
+		if nodeAddr.Host() == host {
+			delete(g.nodes, nodeAddr)
+		}
+	}
+	return err
+}
+
+// isBanned returns true if the host is on the blocklist or banned
+// temporarily.
+func (g *Gateway) isBanned(host string) bool {
+	if _, exists := g.blocklist[host]; exists {
+		return true
+	}
+	return g.staticPeerScores.callIsBanned(host, time.Now())
+}
+
 // managedSleep will sleep for the given period of time. If the full time
 // elapses, 'true' is returned. If the sleep is interrupted for shutdown,
 // 'false' is returned.
@@ -244,6 +267,57 @@ func (g *Gateway) AddToBlocklist(addresses []string) error {
 	return g.addToBlocklist(addresses)
 }
 
+// BanPeers bans addresses for the given duration and disconnects from them.
+// A duration of 0 adds the addresses to the blocklist.
+func (g *Gateway) BanPeers(addresses []string, duration time.Duration, reason string) error {
+	if err := g.threads.Add(); err != nil {
+		return err
+	}
+	defer g.threads.Done()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if duration == 0 {
+		for _, addr := range addresses {
+			if reason != "" {
+				g.persist.BlocklistReasons[addr] = reason
+			}
+		}
+		return g.addToBlocklist(addresses)
+	}
+	var err error
+	expiry := time.Now().Add(duration)
+	for _, addr := range addresses {
+		g.staticPeerScores.callBan(addr, reason, expiry)
+		err = errors.Compose(err, g.disconnectHost(addr))
+	}
+	return err
+}
+
+// Bans returns the addresses of the blocklist and the temporary bans of the
+// gateway.
+func (g *Gateway) Bans() ([]modules.PeerBan, error) {
+	if err := g.threads.Add(); err != nil {
+		return nil, err
+	}
+	defer g.threads.Done()
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var bans []modules.PeerBan
+	for addr := range g.blocklist {
+		bans = append(bans, modules.PeerBan{
+			Address:   addr,
+			Reason:    g.persist.BlocklistReasons[addr],
+			Permanent: true,
+		})
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Address < bans[j].Address
+	})
+	return append(bans, g.staticPeerScores.callBans(time.Now())...), nil
+}
+
 // BandwidthCounters returns the Gateway's upload and download bandwidth
 func (g *Gateway) BandwidthCounters() (uint64, uint64, time.Time, error) {
 	if err := g.threads.Add(); err != nil {
@@ -299,6 +373,16 @@ func (g *Gateway) ForwardPort(port string) error {
 	return g.managedForwardPort(port)
 }
 
+// PeerScores returns the scores of the addresses which the gateway has
+// scored.
+func (g *Gateway) PeerScores() ([]modules.PeerScore, error) {
+	if err := g.threads.Add(); err != nil {
+		return nil, err
+	}
+	defer g.threads.Done()
+	return g.staticPeerScores.callScores(time.Now()), nil
+}
+
 // RateLimits returns the currently set bandwidth limits of the gateway.
 func (g *Gateway) RateLimits() (int64, int64) {
 	g.mu.RLock()
@@ -306,6 +390,29 @@ func (g *Gateway) RateLimits() (int64, int64) {
 	return g.persist.MaxDownloadSpeed, g.persist.MaxUploadSpeed
 }
 
+// ReportPeer lowers the score of a peer which misbehaved. If the score drops
+// to peerBanScore, the peer is banned temporarily and the gateway disconnects
+// from it.
+func (g *Gateway) ReportPeer(addr modules.NetAddress, offense modules.PeerOffense) {
+	if err := g.threads.Add(); err != nil {
+		return
+	}
+	defer g.threads.Done()
+
+	host := addr.Host()
+	g.log.Debugf("INFO: peer %v reported for %v", addr, offense)
+	if !g.staticPeerScores.callReport(host, offense, time.Now()) {
+		return
+	}
+	g.log.Printf("WARN: banning %v for %v after %v", host, peerBanDuration, offense)
+	g.mu.Lock()
+	err := g.disconnectHost(host)
+	g.mu.Unlock()
+	if err != nil {
+		g.log.Println("WARN: failed to disconnect from banned peer:", err)
+	}
+}
+
 // RemoveFromBlocklist removes addresses from the Gateway's blocklist
 func (g *Gateway) RemoveFromBlocklist(addresses []string) error {
 	if err := g.threads.Add(); err != nil {
@@ -344,6 +451,23 @@ func (g *Gateway) SetBlocklist(addresses []string) error {
 	return g.addToBlocklist(addresses)
 }
 
+// UnbanPeers removes addresses from the blocklist and the temporary bans and
+// resets their score.
+func (g *Gateway) UnbanPeers(addresses []string) error {
+	if err := g.threads.Add(); err != nil {
+		return err
+	}
+	defer g.threads.Done()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, addr := range addresses {
+		delete(g.blocklist, addr)
+		g.staticPeerScores.callUnban(addr)
+	}
+	return g.saveSync()
+}
+
 // SetRateLimits changes the rate limits for the peer-connections of the
 // gateway.
 func (g *Gateway) SetRateLimits(downloadSpeed, uploadSpeed int64) error {
@@ -361,11 +485,14 @@ func (g *Gateway) SetRateLimits(downloadSpeed, uploadSpeed int64) error {
 
 // New returns an initialized Gateway.
 func New(addr string, bootstrap bool, persistDir string) (*Gateway, error) {
-	return NewCustomGateway(addr, bootstrap, true, persistDir, modules.ProdDependencies)
+	return NewCustomGateway(addr, bootstrap, true, ProxySettings{}, persistDir, modules.ProdDependencies)
 }
 
 // NewCustomGateway returns an initialized Gateway with custom dependencies.
-func NewCustomGateway(addr string, bootstrap bool, useUPNP bool, persistDir string, deps modules.Dependencies) (*Gateway, error) {
+func NewCustomGateway(addr string, bootstrap bool, useUPNP bool, proxy ProxySettings, persistDir string, deps modules.Dependencies) (*Gateway, error) {
+	if proxy.Onion && proxy.Address == "" {
+		return nil, errors.New("an onion service requires a proxy")
+	}
 	// Create the directory if it doesn't exist.
 	err := os.MkdirAll(persistDir, 0700)
 	if err != nil {
@@ -380,10 +507,19 @@ func NewCustomGateway(addr string, bootstrap bool, useUPNP bool, persistDir stri
 		nodes:     make(map[modules.NetAddress]*node),
 		peers:     make(map[modules.NetAddress]*peer),
 
-		persistDir:    persistDir,
-		staticAlerter: modules.NewAlerter("gateway"),
-		staticDeps:    deps,
-		staticUseUPNP: useUPNP,
+		persist: persistence{
+			BlocklistReasons: make(map[string]string),
+			ConnectionLimits: defaultConnectionLimits,
+		},
+		persistDir:         persistDir,
+		staticAlerter:      modules.NewAlerter("gateway"),
+		staticASNCache:     newASNCache(),
+		staticBandwidth:    newBandwidthTracker(),
+		staticConnectivity: newConnectivity(),
+		staticDeps:         deps,
+		staticPeerScores:   newPeerScores(),
+		staticProxy:        proxy,
+		staticUseUPNP:      useUPNP,
 	}
 
 	// Set Unique GatewayID
@@ -420,11 +556,13 @@ func NewCustomGateway(addr string, bootstrap bool, useUPNP bool, persistDir stri
 	// Register RPCs.
 	g.RegisterRPC("ShareNodes", g.shareNodes)
 	g.RegisterRPC("DiscoverIP", g.discoverPeerIP)
+	g.RegisterRPC("ProbePorts", g.rpcProbePorts)
 	g.RegisterConnectCall("ShareNodes", g.requestNodes)
 	// Establish the de-registration of the RPCs.
 	g.threads.OnStop(func() error {
 		g.UnregisterRPC("ShareNodes")
 		g.UnregisterRPC("DiscoverIP")
+		g.UnregisterRPC("ProbePorts")
 		g.UnregisterConnectCall("ShareNodes")
 		return nil
 	})
@@ -502,6 +640,17 @@ func NewCustomGateway(addr string, bootstrap bool, useUPNP bool, persistDir stri
 	// overwritten by threadedLearnHostname later on.
 	g.myAddr = modules.NetAddress(net.JoinHostPort(host, port))
 
+	// If the gateway listens as an onion service, the onion address is the
+	// address of the gateway.
+	if proxy.Onion {
+		onionAddr, err := g.managedStartOnionService()
+		if err != nil {
+			return nil, errors.AddContext(err, "unable to start onion service")
+		}
+		g.myAddr = onionAddr
+		g.log.Println("INFO: our onion address is", onionAddr)
+	}
+
 	// Spawn the peer connection listener.
 	go g.permanentListen(permanentListenClosedChan)
 
@@ -533,6 +682,10 @@ func NewCustomGateway(addr string, bootstrap bool, useUPNP bool, persistDir stri
 	go g.threadedForwardPort(g.port)
 	go g.threadedLearnHostname()
 
+	// Spawn thread to periodically probe whether the forwarded ports are
+	// reachable.
+	go g.threadedProbeConnectivity()
+
 	// Spawn thread to periodically check if the gateway is online.
 	go g.threadedOnlineCheck()
 
@@ -0,0 +1,333 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/modules"
+)
+
+// NAT-PMP (RFC 6886) and its successor PCP (RFC 6887) share the same port
+// and the result codes of NAT-PMP. A NAT-PMP router answers a PCP request
+// with an unsupported version error and vice versa.
+const (
+	natpmpPort = "5351"
+
+	natpmpVersion = 0
+	pcpVersion    = 2
+
+	natpmpOpMapTCP     = 2
+	natpmpResponseBit  = 128
+	natpmpResponseSize = 16
+
+	pcpOpMap        = 1
+	pcpProtocolTCP  = 6
+	pcpRequestSize  = 60
+	pcpResponseSize = 60
+
+	natpmpResultSuccess = 0
+)
+
+var (
+	// errNATPMPNoResponse is returned if the router doesn't respond to any of
+	// the retransmissions of a request.
+	errNATPMPNoResponse = errors.New("no response from router")
+
+	// errNATPMPUnsupportedVersion is returned if the router doesn't support
+	// the version of the protocol.
+	errNATPMPUnsupportedVersion = errors.New("router doesn't support the protocol version")
+)
+
+// natMapping is a port mapping created with NAT-PMP or PCP.
+type natMapping struct {
+	router       string
+	method       modules.PortMappingMethod
+	port         uint16
+	externalPort uint16
+
+	// nonce identifies the mapping when renewing or deleting it with PCP.
+	nonce [12]byte
+}
+
+// natpmpRoundTrip sends the request to the router and returns the response.
+// The request is retransmitted with a doubling timeout as recommended by RFC
+// 6886.
+func natpmpRoundTrip(ctx context.Context, conn net.Conn, req []byte) ([]byte, error) {
+	resp := make([]byte, 1100)
+	timeout := natpmpInitialTimeout
+	for i := 0; i < natpmpRetries; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, err
+		}
+		n, err := conn.Read(resp)
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			timeout *= 2
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return resp[:n], nil
+	}
+	return nil, errNATPMPNoResponse
+}
+
+// natpmpResultErr returns the error of a NAT-PMP or PCP result code.
+func natpmpResultErr(code uint16) error {
+	switch code {
+	case natpmpResultSuccess:
+		return nil
+	case 1:
+		return errNATPMPUnsupportedVersion
+	case 2:
+		return errors.New("router refused the request")
+	case 3:
+		return errors.New("router is not connected to the network")
+	case 4:
+		return errors.New("router is out of resources")
+	default:
+		return errors.New("router returned result code " + strconv.Itoa(int(code)))
+	}
+}
+
+// natpmpMapTCP maps a TCP port with NAT-PMP. A lifetime of 0 deletes the
+// mapping. It returns the lifetime granted by the router.
+func (m *natMapping) natpmpMapTCP(ctx context.Context, conn net.Conn, lifetime uint32) (uint32, error) {
+	req := make([]byte, 12)
+	req[0] = natpmpVersion
+	req[1] = natpmpOpMapTCP
+	binary.BigEndian.PutUint16(req[4:], m.port)
+	if lifetime > 0 {
+		binary.BigEndian.PutUint16(req[6:], m.externalPort)
+	}
+	binary.BigEndian.PutUint32(req[8:], lifetime)
+	resp, err := natpmpRoundTrip(ctx, conn, req)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp) < 4 || resp[1] != natpmpResponseBit|natpmpOpMapTCP {
+		return 0, errors.New("invalid NAT-PMP response")
+	}
+	if err := natpmpResultErr(binary.BigEndian.Uint16(resp[2:])); err != nil {
+		return 0, err
+	}
+	if len(resp) < natpmpResponseSize || binary.BigEndian.Uint16(resp[8:]) != m.port {
+		return 0, errors.New("invalid NAT-PMP response")
+	}
+	m.externalPort = binary.BigEndian.Uint16(resp[10:])
+	return binary.BigEndian.Uint32(resp[12:]), nil
+}
+
+// pcpMapTCP maps a TCP port with PCP. A lifetime of 0 deletes the mapping. It
+// returns the lifetime granted by the router.
+func (m *natMapping) pcpMapTCP(ctx context.Context, conn net.Conn, lifetime uint32) (uint32, error) {
+	clientIP := conn.LocalAddr().(*net.UDPAddr).IP.To16()
+	req := make([]byte, pcpRequestSize)
+	req[0] = pcpVersion
+	req[1] = pcpOpMap
+	binary.BigEndian.PutUint32(req[4:], lifetime)
+	copy(req[8:24], clientIP)
+	copy(req[24:36], m.nonce[:])
+	req[36] = pcpProtocolTCP
+	binary.BigEndian.PutUint16(req[40:], m.port)
+	binary.BigEndian.PutUint16(req[42:], m.externalPort)
+	// Any external IPv4 address.
+	copy(req[44:60], net.IPv4zero.To16())
+	resp, err := natpmpRoundTrip(ctx, conn, req)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp) < 4 {
+		return 0, errors.New("invalid PCP response")
+	}
+	if resp[0] != pcpVersion {
+		// NAT-PMP routers respond with their own version.
+		return 0, errNATPMPUnsupportedVersion
+	}
+	if resp[1] != natpmpResponseBit|pcpOpMap {
+		return 0, errors.New("invalid PCP response")
+	}
+	if err := natpmpResultErr(uint16(resp[3])); err != nil {
+		return 0, err
+	}
+	if len(resp) < pcpResponseSize || string(resp[24:36]) != string(m.nonce[:]) || binary.BigEndian.Uint16(resp[40:]) != m.port {
+		return 0, errors.New("invalid PCP response")
+	}
+	m.externalPort = binary.BigEndian.Uint16(resp[42:])
+	return binary.BigEndian.Uint32(resp[4:]), nil
+}
+
+// refresh creates, renews or, with a lifetime of 0, deletes the mapping. It
+// returns the lifetime granted by the router.
+func (m *natMapping) refresh(ctx context.Context, lifetime uint32) (uint32, error) {
+	conn, err := net.Dial("udp", m.router)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if m.method == modules.PortMappingPCP {
+		return m.pcpMapTCP(ctx, conn, lifetime)
+	}
+	return m.natpmpMapTCP(ctx, conn, lifetime)
+}
+
+// newNATMapping maps the port on the router, trying PCP first and falling
+// back to NAT-PMP.
+func newNATMapping(ctx context.Context, router string, port uint16) (*natMapping, uint32, error) {
+	m := &natMapping{
+		router:       router,
+		method:       modules.PortMappingPCP,
+		port:         port,
+		externalPort: port,
+	}
+	fastrand.Read(m.nonce[:])
+	lifetime, pcpErr := m.refresh(ctx, natpmpLifetime)
+	if pcpErr == nil {
+		return m, lifetime, nil
+	}
+	m.method = modules.PortMappingNATPMP
+	m.externalPort = port
+	lifetime, err := m.refresh(ctx, natpmpLifetime)
+	if err != nil {
+		return nil, 0, errors.Compose(errors.AddContext(pcpErr, "PCP failed"), errors.AddContext(err, "NAT-PMP failed"))
+	}
+	return m, lifetime, nil
+}
+
+// defaultGateway returns the default gateway of the machine by reading the
+// routing table. It is only supported on Linux.
+func defaultGateway() (net.IP, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// Iface Destination Gateway Flags ...
+		fields := strings.Fields(s.Text())
+		if len(fields) < 4 || fields[1] != "00000000" {
+			continue
+		}
+		b, err := hex.DecodeString(fields[2])
+		if err != nil || len(b) != 4 {
+			continue
+		}
+		// The address is in host byte order, which is little endian on all
+		// platforms Sia supports.
+		return net.IPv4(b[3], b[2], b[1], b[0]), nil
+	}
+	return nil, errors.New("no default gateway found")
+}
+
+// managedRouterCandidates returns the addresses which might belong to the
+// router: the router found by UPnP, the default gateway and the first address
+// of the local networks.
+func (g *Gateway) managedRouterCandidates() []string {
+	var ips []net.IP
+	g.mu.RLock()
+	routerURL := g.persist.RouterURL
+	g.mu.RUnlock()
+	if u, err := url.Parse(routerURL); err == nil && routerURL != "" {
+		if ip := net.ParseIP(u.Hostname()); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	if ip, err := defaultGateway(); err == nil {
+		ips = append(ips, ip)
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok || ipnet.IP.To4() == nil || ipnet.IP.IsLoopback() {
+				continue
+			}
+			ip := ipnet.IP.Mask(ipnet.Mask).To4()
+			if !modules.NetAddress(net.JoinHostPort(ip.String(), natpmpPort)).IsLocal() {
+				continue
+			}
+			ip[3]++
+			ips = append(ips, ip)
+		}
+	}
+
+	var routers []string
+	seen := make(map[string]struct{})
+	for _, ip := range ips {
+		router := net.JoinHostPort(ip.String(), natpmpPort)
+		if _, ok := seen[router]; ok {
+			continue
+		}
+		seen[router] = struct{}{}
+		routers = append(routers, router)
+	}
+	return routers
+}
+
+// managedForwardPortNATPMP maps the port on the first router which supports
+// PCP or NAT-PMP and keeps renewing the mapping until shutdown.
+func (g *Gateway) managedForwardPortNATPMP(port uint16) (*natMapping, error) {
+	routers := g.managedRouterCandidates()
+	if len(routers) == 0 {
+		return nil, errors.New("no router found")
+	}
+	var err error
+	for _, router := range routers {
+		m, lifetime, mapErr := newNATMapping(g.threads.StopCtx(), router, port)
+		if mapErr != nil {
+			err = errors.Compose(err, errors.AddContext(mapErr, router))
+			continue
+		}
+		go g.threadedRenewNATMapping(m, lifetime)
+		// Delete the mapping at shutdown.
+		g.threads.AfterStop(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), natpmpDeleteTimeout)
+			defer cancel()
+			if _, err := m.refresh(ctx, 0); err != nil {
+				g.log.Printf("WARN: could not delete %v mapping of port %v: %v", m.method, m.port, err)
+			}
+			return nil
+		})
+		return m, nil
+	}
+	return nil, err
+}
+
+// threadedRenewNATMapping renews the mapping when half of its lifetime has
+// passed.
+func (g *Gateway) threadedRenewNATMapping(m *natMapping, lifetime uint32) {
+	if err := g.threads.Add(); err != nil {
+		return
+	}
+	defer g.threads.Done()
+
+	port := strconv.Itoa(int(m.port))
+	for {
+		if !g.managedSleep(time.Duration(lifetime) * time.Second / 2) {
+			return // shutdown interrupted sleep
+		}
+		var err error
+		lifetime, err = m.refresh(g.threads.StopCtx(), natpmpLifetime)
+		if err != nil {
+			g.log.Printf("WARN: could not renew %v mapping of port %v: %v", m.method, port, err)
+			lifetime = natpmpRetryLifetime
+		}
+		g.staticConnectivity.callSetMapping(port, m.method, strconv.Itoa(int(m.externalPort)), err)
+	}
+}
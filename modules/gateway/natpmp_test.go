@@ -0,0 +1,161 @@
+package gateway
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"go.sia.tech/siad/modules"
+)
+
+// newTestNATRouter starts a fake router which answers NAT-PMP requests and,
+// if pcp is true, PCP requests. Requests of the unsupported protocol are
+// answered with an unsupported version error. The router maps every port to
+// the port + 1.
+func newTestNATRouter(t *testing.T, pcp bool) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 1100)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			req := buf[:n]
+			var resp []byte
+			switch {
+			case req[0] == pcpVersion && pcp && n == pcpRequestSize:
+				resp = make([]byte, pcpResponseSize)
+				resp[0] = pcpVersion
+				resp[1] = natpmpResponseBit | req[1]
+				copy(resp[4:8], req[4:8])
+				copy(resp[24:44], req[24:44])
+				binary.BigEndian.PutUint16(resp[42:], binary.BigEndian.Uint16(req[40:])+1)
+			case req[0] == pcpVersion:
+				// NAT-PMP routers answer with an unsupported version error.
+				resp = []byte{natpmpVersion, natpmpResponseBit | req[1], 0, 1, 0, 0, 0, 0}
+			case req[0] == natpmpVersion && pcp:
+				resp = make([]byte, 24)
+				resp[0] = pcpVersion
+				resp[1] = natpmpResponseBit | req[1]
+				resp[3] = 1
+			case req[0] == natpmpVersion && n == 12:
+				resp = make([]byte, natpmpResponseSize)
+				resp[1] = natpmpResponseBit | req[1]
+				copy(resp[8:10], req[4:6])
+				binary.BigEndian.PutUint16(resp[10:], binary.BigEndian.Uint16(req[4:])+1)
+				copy(resp[12:16], req[8:12])
+			default:
+				continue
+			}
+			if _, err := conn.WriteTo(resp, addr); err != nil {
+				return
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// TestNATMapping checks that ports are mapped with PCP if the router supports
+// it and with NAT-PMP otherwise.
+func TestNATMapping(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pcp    bool
+		method modules.PortMappingMethod
+	}{
+		{true, modules.PortMappingPCP},
+		{false, modules.PortMappingNATPMP},
+	}
+	for _, test := range tests {
+		router := newTestNATRouter(t, test.pcp)
+		m, lifetime, err := newNATMapping(context.Background(), router, 9981)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.method != test.method || m.externalPort != 9982 || lifetime != natpmpLifetime {
+			t.Fatalf("wrong mapping %+v with lifetime %v", m, lifetime)
+		}
+		// The mapping can be deleted.
+		if lifetime, err := m.refresh(context.Background(), 0); err != nil || lifetime != 0 {
+			t.Fatal("failed to delete mapping", lifetime, err)
+		}
+	}
+
+	// Mapping fails if the router doesn't respond.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, _, err := newNATMapping(context.Background(), conn.LocalAddr().String(), 9981); err == nil {
+		t.Fatal("mapping should fail without a router")
+	}
+}
+
+// TestConnectivityProbe checks that the gateway probes the reachability of
+// its ports through its peers.
+func TestConnectivityProbe(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	g1 := newNamedTestingGateway(t, "1")
+	defer g1.Close()
+	g2 := newNamedTestingGateway(t, "2")
+	defer g2.Close()
+
+	// Without peers the probe fails.
+	status, err := g1.ProbeConnectivity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Reachable || status.ProbeError == "" {
+		t.Fatal("probe without peers should fail", status)
+	}
+
+	// Forward a closed port in addition to the port of the gateway.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, closedPort, _ := net.SplitHostPort(l.Addr().String())
+	l.Close()
+	if err := g1.ForwardPort(g1.port); err != nil {
+		t.Fatal(err)
+	}
+	if err := g1.ForwardPort(closedPort); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := connectToNode(g1, g2, false); err != nil {
+		t.Fatal(err)
+	}
+	status, err = g1.ProbeConnectivity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ProbeError != "" || status.LastProbe.IsZero() || len(status.PortMappings) != 2 {
+		t.Fatal("wrong status", status)
+	}
+	for _, pm := range status.PortMappings {
+		if pm.Method != modules.PortMappingNone || pm.Reachable != (pm.Port == g1.port) {
+			t.Fatal("wrong port mapping", pm)
+		}
+	}
+	if status.Reachable {
+		t.Fatal("node is reachable although a port is closed")
+	}
+	connectivity, err := g1.Connectivity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if connectivity.LastProbe != status.LastProbe {
+		t.Fatal("Connectivity doesn't return the last probe")
+	}
+}
@@ -2,7 +2,6 @@ package gateway
 
 import (
 	"fmt"
-	"net"
 	"time"
 
 	"gitlab.com/NebulousLabs/errors"
@@ -34,7 +33,7 @@ func (g *Gateway) addNode(addr modules.NetAddress) error {
 		return errNodeExists
 	} else if addr.IsStdValid() != nil {
 		return errors.New("address is not valid: " + string(addr))
-	} else if net.ParseIP(addr.Host()) == nil {
+	} else if !g.staticDialableHost(addr.Host()) {
 		return errors.New("address must be an IP address: " + string(addr))
 	}
 	g.nodes[addr] = &node{
@@ -46,6 +46,10 @@ type peer struct {
 	m    *connmonitor.Monitor
 	rl   *ratelimit.RateLimit
 	sess streamSession
+
+	// asn is the autonomous system of an inbound peer. It's 0 if it's
+	// unknown.
+	asn uint32
 }
 
 // sessionHeader is sent after the initial version exchange. It prevents peers
@@ -162,13 +166,27 @@ func (g *Gateway) threadedAcceptConn(conn net.Conn) {
 	g.log.Debugf("INFO: %v wants to connect", addr)
 
 	g.mu.RLock()
-	_, exists := g.blocklist[addr.Host()]
+	banned := g.isBanned(addr.Host())
 	g.mu.RUnlock()
-	if exists {
-		g.log.Debugf("INFO: %v was rejected. (blocklisted)", addr)
+	if banned {
+		g.log.Debugf("INFO: %v was rejected. (banned)", addr)
 		conn.Close()
 		return
 	}
+	// Check the connection limits before the handshake. Local peers are
+	// exempt from the limits.
+	var asn uint32
+	if ip := net.ParseIP(addr.Host()); ip != nil && !addr.IsLocal() {
+		asn = g.managedLookupASN(ip)
+		g.mu.RLock()
+		err := g.inboundLimitErr(ip, asn)
+		g.mu.RUnlock()
+		if err != nil {
+			g.log.Debugf("INFO: %v was rejected. (%v)", addr, err)
+			conn.Close()
+			return
+		}
+	}
 	remoteVersion, err := acceptVersionHandshake(conn, ProtocolVersion)
 	if err != nil {
 		g.log.Debugf("INFO: %v wanted to connect but version handshake failed: %v", addr, err)
@@ -177,7 +195,7 @@ func (g *Gateway) threadedAcceptConn(conn net.Conn) {
 	}
 
 	if err = acceptableVersion(remoteVersion); err == nil {
-		err = g.managedAcceptConnPeer(conn, remoteVersion)
+		err = g.managedAcceptConnPeer(conn, remoteVersion, asn)
 	}
 	if err != nil {
 		g.log.Debugf("INFO: %v wanted to connect, but failed: %v", addr, err)
@@ -207,7 +225,7 @@ func acceptableSessionHeader(ourHeader, remoteHeader sessionHeader, remoteAddr s
 // managedAcceptConnPeer accepts connection requests from peers >= v1.3.1.
 // The requesting peer is added as a node and a peer. The peer is only added if
 // a nil error is returned.
-func (g *Gateway) managedAcceptConnPeer(conn net.Conn, remoteVersion string) error {
+func (g *Gateway) managedAcceptConnPeer(conn net.Conn, remoteVersion string, asn uint32) error {
 	g.log.Debugln("Attempting to Accept Connection from Peer; Sending sessionHeader with address", g.myAddr, g.myAddr.IsLocal())
 	// Perform header handshake.
 	g.mu.RLock()
@@ -235,6 +253,12 @@ func (g *Gateway) managedAcceptConnPeer(conn net.Conn, remoteVersion string) err
 	remoteIP := modules.NetAddress(conn.RemoteAddr().String()).Host()
 	remotePort := remoteHeader.NetAddress.Port()
 	remoteAddr := modules.NetAddress(net.JoinHostPort(remoteIP, remotePort))
+	// Connections to our onion service are forwarded by the local Tor daemon,
+	// which means the peer can only be identified by the onion address it
+	// announced.
+	if g.staticProxy.Onion && modules.NetAddress(conn.RemoteAddr().String()).IsLoopback() && isOnionHost(remoteHeader.NetAddress.Host()) {
+		remoteAddr = remoteHeader.NetAddress
+	}
 	g.log.Debugln("Making connection with remote peer", remoteAddr)
 
 	// Accept the peer.
@@ -252,8 +276,18 @@ func (g *Gateway) managedAcceptConnPeer(conn net.Conn, remoteVersion string) err
 		m:    g.m,
 		rl:   rl,
 		sess: newServerStream(conn, remoteVersion),
+		asn:  asn,
 	}
+	// Check the connection limits again since other peers might have been
+	// accepted during the handshake.
 	g.mu.Lock()
+	if ip := net.ParseIP(remoteAddr.Host()); ip != nil && !peer.Local {
+		if err := g.inboundLimitErr(ip, asn); err != nil {
+			g.mu.Unlock()
+			peer.sess.Close()
+			return err
+		}
+	}
 	g.acceptPeer(peer)
 	g.mu.Unlock()
 
@@ -450,19 +484,20 @@ func (g *Gateway) managedConnect(addr modules.NetAddress) error {
 		g.log.Debugln("Unable to connect to", addr, "error:", err)
 		return err
 	}
-	if net.ParseIP(addr.Host()) == nil {
+	if !g.staticDialableHost(addr.Host()) {
 		err := errors.New("address must be an IP address")
 		g.log.Debugln("Unable to connect to", addr, "error:", err)
 		return err
 	}
-	if _, exists := g.blocklist[addr.Host()]; exists {
-		err := errors.New("can't connect to blocklisted address")
-		g.log.Debugln("Unable to connect to", addr, "error:", err)
-		return err
-	}
 	g.mu.RLock()
+	banned := g.isBanned(addr.Host())
 	_, exists := g.peers[addr]
 	g.mu.RUnlock()
+	if banned {
+		err := errors.New("can't connect to banned address")
+		g.log.Debugln("Unable to connect to", addr, "error:", err)
+		return err
+	}
 	if exists {
 		g.log.Debugln("Unable to connect to", addr, "error:", errPeerExists)
 		return errPeerExists
@@ -476,13 +511,16 @@ func (g *Gateway) managedConnect(addr modules.NetAddress) error {
 	}
 	g.log.Debugln("Created conn; remote and local addr", conn.RemoteAddr(), conn.LocalAddr())
 
-	// Perform peer initialization.
+	// Perform peer initialization. The version handshake is a single round
+	// trip, which makes it a good measure of the latency of the peer.
+	handshakeStart := time.Now()
 	remoteVersion, err := connectVersionHandshake(conn, ProtocolVersion)
 	if err != nil {
 		conn.Close()
 		g.log.Debugln("Unable to connect to", addr, "error:", err)
 		return err
 	}
+	g.staticPeerScores.callReportLatency(addr.Host(), time.Since(handshakeStart))
 
 	if err = acceptableVersion(remoteVersion); err == nil {
 		err = g.managedConnectPeer(conn, remoteVersion, addr)
@@ -579,6 +617,7 @@ func (g *Gateway) ConnectManual(addr modules.NetAddress) error {
 		delete(g.blocklist, addr.Host())
 		err = g.saveSync()
 	}
+	g.staticPeerScores.callUnban(addr.Host())
 	g.mu.Unlock()
 	return build.ComposeErrors(err, g.Connect(addr))
 }
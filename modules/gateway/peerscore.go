@@ -0,0 +1,186 @@
+package gateway
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+type (
+	// peerScores scores the peers of the gateway by address and keeps track
+	// of the temporary bans. Scores are kept by host, like the blocklist, so
+	// that a misbehaving node can't reconnect from a different port.
+	peerScores struct {
+		scores map[string]*peerScore
+		bans   map[string]modules.PeerBan
+
+		mu sync.Mutex
+	}
+
+	// peerScore is the score of a single address.
+	peerScore struct {
+		invalidBlocks uint64
+		stalls        uint64
+		latency       time.Duration
+		offenses      []peerOffense
+	}
+
+	// peerOffense is an offense which counts towards the score of a peer
+	// until it is older than peerScoreWindow.
+	peerOffense struct {
+		penalty int64
+		time    time.Time
+	}
+)
+
+// newPeerScores creates an empty peerScores.
+func newPeerScores() *peerScores {
+	return &peerScores{
+		scores: make(map[string]*peerScore),
+		bans:   make(map[string]modules.PeerBan),
+	}
+}
+
+// score prunes the offenses which are older than peerScoreWindow and returns
+// the current score.
+func (ps *peerScore) score(now time.Time) int64 {
+	var score int64
+	offenses := ps.offenses[:0]
+	for _, o := range ps.offenses {
+		if now.Sub(o.time) > peerScoreWindow {
+			continue
+		}
+		offenses = append(offenses, o)
+		score -= o.penalty
+	}
+	ps.offenses = offenses
+	if ps.latency > highLatencyThreshold {
+		score -= highLatencyPenalty
+	}
+	return score
+}
+
+// scoreOf returns the score of the host, creating it if necessary. The
+// caller must hold the lock.
+func (s *peerScores) scoreOf(host string) *peerScore {
+	ps, ok := s.scores[host]
+	if !ok {
+		ps = new(peerScore)
+		s.scores[host] = ps
+	}
+	return ps
+}
+
+// callReport records an offense of a host. If the score of the host drops to
+// peerBanScore, the host is banned temporarily and true is returned.
+func (s *peerScores) callReport(host string, offense modules.PeerOffense, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ps := s.scoreOf(host)
+	var penalty int64
+	switch offense {
+	case modules.PeerOffenseInvalidBlock:
+		ps.invalidBlocks++
+		penalty = invalidBlockPenalty
+	case modules.PeerOffenseStall:
+		ps.stalls++
+		penalty = stallPenalty
+	}
+	ps.offenses = append(ps.offenses, peerOffense{penalty: penalty, time: now})
+
+	score := ps.score(now)
+	if score > peerBanScore {
+		return false
+	}
+	if ban, banned := s.bans[host]; banned && now.Before(ban.Expiry) {
+		return false
+	}
+	s.bans[host] = modules.PeerBan{
+		Address: host,
+		Reason:  fmt.Sprintf("score dropped to %v after %v", score, offense),
+		Expiry:  now.Add(peerBanDuration),
+	}
+	// The peer starts over once the ban expires.
+	ps.offenses = nil
+	return true
+}
+
+// callReportLatency records the latency of a host.
+func (s *peerScores) callReportLatency(host string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scoreOf(host).latency = latency
+}
+
+// callBan bans a host until expiry.
+func (s *peerScores) callBan(host, reason string, expiry time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bans[host] = modules.PeerBan{
+		Address: host,
+		Reason:  reason,
+		Expiry:  expiry,
+	}
+}
+
+// callUnban removes the temporary ban and the score of a host.
+func (s *peerScores) callUnban(host string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.bans, host)
+	delete(s.scores, host)
+}
+
+// callIsBanned returns true if the host is banned temporarily.
+func (s *peerScores) callIsBanned(host string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ban, banned := s.bans[host]
+	if banned && !now.Before(ban.Expiry) {
+		delete(s.bans, host)
+		return false
+	}
+	return banned
+}
+
+// callBans returns the temporary bans which haven't expired, sorted by
+// address.
+func (s *peerScores) callBans(now time.Time) []modules.PeerBan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bans := make([]modules.PeerBan, 0, len(s.bans))
+	for host, ban := range s.bans {
+		if !now.Before(ban.Expiry) {
+			delete(s.bans, host)
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Address < bans[j].Address
+	})
+	return bans
+}
+
+// callScores returns the scores of all hosts, sorted by address.
+func (s *peerScores) callScores(now time.Time) []modules.PeerScore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scores := make([]modules.PeerScore, 0, len(s.scores))
+	for host, ps := range s.scores {
+		scores = append(scores, modules.PeerScore{
+			Address:       host,
+			Score:         ps.score(now),
+			InvalidBlocks: ps.invalidBlocks,
+			Stalls:        ps.stalls,
+			Latency:       ps.latency,
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Address < scores[j].Address
+	})
+	return scores
+}
@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestPeerScoresReport checks that reporting offenses lowers the score of a
+// host until it is banned.
+func TestPeerScoresReport(t *testing.T) {
+	t.Parallel()
+	s := newPeerScores()
+	host := "1.2.3.4"
+	now := time.Now()
+
+	// Stalls alone take a while to get a host banned.
+	if s.callReport(host, modules.PeerOffenseStall, now) {
+		t.Fatal("host was banned after a single stall")
+	}
+	// Invalid blocks get it banned quickly.
+	if s.callReport(host, modules.PeerOffenseInvalidBlock, now) {
+		t.Fatal("host was banned too early")
+	}
+	if !s.callReport(host, modules.PeerOffenseInvalidBlock, now) {
+		t.Fatal("host wasn't banned")
+	}
+	if !s.callIsBanned(host, now) {
+		t.Fatal("host isn't banned")
+	}
+	if s.callIsBanned("5.6.7.8", now) {
+		t.Fatal("other host is banned")
+	}
+	scores := s.callScores(now)
+	if len(scores) != 1 || scores[0].InvalidBlocks != 2 || scores[0].Stalls != 1 || scores[0].Score != 0 {
+		t.Fatal("wrong scores", scores)
+	}
+
+	// Further offenses during the ban don't ban the host again.
+	s.callReport(host, modules.PeerOffenseInvalidBlock, now)
+	if s.callReport(host, modules.PeerOffenseInvalidBlock, now) {
+		t.Fatal("banned host was banned again")
+	}
+
+	// The ban expires.
+	expiry := now.Add(peerBanDuration)
+	if bans := s.callBans(expiry.Add(-time.Second)); len(bans) != 1 || bans[0].Address != host || bans[0].Permanent {
+		t.Fatal("wrong bans", bans)
+	}
+	if s.callIsBanned(host, expiry) {
+		t.Fatal("ban didn't expire")
+	}
+	if bans := s.callBans(expiry); len(bans) != 0 {
+		t.Fatal("expired ban is still listed", bans)
+	}
+}
+
+// TestPeerScoresWindow checks that offenses older than peerScoreWindow no
+// longer count towards the score and that high latency is penalized.
+func TestPeerScoresWindow(t *testing.T) {
+	t.Parallel()
+	s := newPeerScores()
+	host := "1.2.3.4"
+	now := time.Now()
+
+	s.callReport(host, modules.PeerOffenseInvalidBlock, now)
+	later := now.Add(peerScoreWindow + time.Second)
+	if s.callReport(host, modules.PeerOffenseInvalidBlock, later) {
+		t.Fatal("host was banned for an offense outside of the window")
+	}
+	if scores := s.callScores(later); scores[0].Score != -invalidBlockPenalty {
+		t.Fatal("wrong score", scores[0].Score)
+	}
+
+	s.callReportLatency(host, highLatencyThreshold+time.Millisecond)
+	if scores := s.callScores(later); scores[0].Score != -invalidBlockPenalty-highLatencyPenalty {
+		t.Fatal("high latency wasn't penalized", scores[0].Score)
+	}
+	s.callReportLatency(host, highLatencyThreshold)
+	if scores := s.callScores(later); scores[0].Score != -invalidBlockPenalty {
+		t.Fatal("low latency was penalized", scores[0].Score)
+	}
+}
+
+// TestPeerScoresUnban checks that unbanning a host removes its ban and resets
+// its score.
+func TestPeerScoresUnban(t *testing.T) {
+	t.Parallel()
+	s := newPeerScores()
+	host := "1.2.3.4"
+	now := time.Now()
+
+	s.callReport(host, modules.PeerOffenseStall, now)
+	s.callBan(host, "manual", now.Add(time.Hour))
+	bans := s.callBans(now)
+	if len(bans) != 1 || bans[0].Reason != "manual" {
+		t.Fatal("wrong bans", bans)
+	}
+	s.callUnban(host)
+	if s.callIsBanned(host, now) {
+		t.Fatal("host is still banned")
+	}
+	if scores := s.callScores(now); len(scores) != 0 {
+		t.Fatal("score wasn't reset", scores)
+	}
+}
+
+// TestGatewayBans checks that the gateway bans and unbans peers both
+// permanently and temporarily.
+func TestGatewayBans(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	g := newTestingGateway(t)
+	defer func() {
+		if err := g.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	if err := g.BanPeers([]string{"1.1.1.1"}, 0, "spam"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.BanPeers([]string{"2.2.2.2"}, time.Hour, "stalling"); err != nil {
+		t.Fatal(err)
+	}
+	bans, err := g.Bans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bans) != 2 {
+		t.Fatal("wrong number of bans", bans)
+	}
+	if bans[0].Address != "1.1.1.1" || !bans[0].Permanent || bans[0].Reason != "spam" {
+		t.Fatal("wrong permanent ban", bans[0])
+	}
+	if bans[1].Address != "2.2.2.2" || bans[1].Permanent || bans[1].Reason != "stalling" {
+		t.Fatal("wrong temporary ban", bans[1])
+	}
+	blocklist, err := g.Blocklist()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocklist) != 1 || blocklist[0] != "1.1.1.1" {
+		t.Fatal("permanent ban isn't on the blocklist", blocklist)
+	}
+	g.mu.RLock()
+	banned := g.isBanned("1.1.1.1") && g.isBanned("2.2.2.2") && !g.isBanned("3.3.3.3")
+	g.mu.RUnlock()
+	if !banned {
+		t.Fatal("isBanned doesn't match the bans")
+	}
+
+	if err := g.UnbanPeers([]string{"1.1.1.1", "2.2.2.2"}); err != nil {
+		t.Fatal(err)
+	}
+	bans, err = g.Bans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bans) != 0 {
+		t.Fatal("peers weren't unbanned", bans)
+	}
+}
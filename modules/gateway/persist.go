@@ -37,6 +37,18 @@ type (
 
 		// blocklisted IPs
 		Blocklist []string
+
+		// BlocklistReasons are the reasons given when addresses were added
+		// to the blocklist with BanPeers.
+		BlocklistReasons map[string]string
+
+		// OnionPrivateKey is the key of the onion service of the gateway in
+		// the format of the Tor control protocol.
+		OnionPrivateKey string
+
+		// ConnectionLimits are the limits of inbound peers per subnet and
+		// autonomous system.
+		ConnectionLimits modules.GatewayConnectionLimits
 	}
 )
 
@@ -94,6 +106,9 @@ func (g *Gateway) load() error {
 	for _, ip := range g.persist.Blocklist {
 		g.blocklist[ip] = struct{}{}
 	}
+	if g.persist.BlocklistReasons == nil {
+		g.persist.BlocklistReasons = make(map[string]string)
+	}
 	return nil
 }
 
@@ -104,6 +119,12 @@ func (g *Gateway) saveSync() error {
 	for ip := range g.blocklist {
 		g.persist.Blocklist = append(g.persist.Blocklist, ip)
 	}
+	// Forget the reasons of addresses which were removed from the blocklist.
+	for ip := range g.persist.BlocklistReasons {
+		if _, exists := g.blocklist[ip]; !exists {
+			delete(g.persist.BlocklistReasons, ip)
+		}
+	}
 	return persist.SaveJSON(persistMetadata, g.persist, filepath.Join(g.persistDir, persistFilename))
 }
 
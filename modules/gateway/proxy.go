@@ -0,0 +1,119 @@
+package gateway
+
+import (
+	"context"
+	"encoding/base32"
+	"net"
+	"strings"
+
+	"gitlab.com/NebulousLabs/errors"
+	"golang.org/x/net/proxy"
+
+	"go.sia.tech/siad/modules"
+)
+
+// onionSuffix is the suffix of the hostnames of onion services.
+const onionSuffix = ".onion"
+
+type (
+	// ProxySettings configure the gateway to make its outbound connections
+	// through a SOCKS5 proxy, e.g. Tor, and to listen as an onion service.
+	ProxySettings struct {
+		// Address is the address of the SOCKS5 proxy. If it is empty, the
+		// gateway connects to its peers directly.
+		Address string
+
+		// Onion registers an onion service which forwards to the gateway
+		// listener through the Tor control port at TorControl. The onion
+		// address becomes the address of the gateway. It requires a proxy.
+		Onion              bool
+		TorControl         string
+		TorControlPassword string
+	}
+
+	// contextDialer is the interface implemented by the dialers of the proxy
+	// package which support contexts.
+	contextDialer interface {
+		DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+	}
+)
+
+// isOnionHost returns true if the host is the hostname of a v3 onion service.
+func isOnionHost(host string) bool {
+	if !strings.HasSuffix(host, onionSuffix) {
+		return false
+	}
+	// v3 onion addresses are the base32 encoding of a 35 byte public key,
+	// checksum and version.
+	id := strings.TrimSuffix(host, onionSuffix)
+	b, err := base32.StdEncoding.DecodeString(strings.ToUpper(id))
+	return err == nil && len(b) == 35
+}
+
+// staticDialableHost returns true if the gateway is able to dial the host.
+// Onion services can only be reached through the proxy.
+func (g *Gateway) staticDialableHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	return g.staticProxy.Address != "" && isOnionHost(host)
+}
+
+// staticDialProxy dials the address through the SOCKS5 proxy. The hostname is
+// resolved by the proxy, which means that no DNS requests leak and that onion
+// services can be dialed.
+func (g *Gateway) staticDialProxy(addr modules.NetAddress) (net.Conn, error) {
+	dialer, err := proxy.SOCKS5("tcp", g.staticProxy.Address, nil, &net.Dialer{Timeout: dialTimeout})
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to create proxy dialer")
+	}
+	cd, ok := dialer.(contextDialer)
+	if !ok {
+		return nil, errors.New("proxy dialer doesn't support contexts")
+	}
+	ctx, cancel := context.WithTimeout(g.threads.StopCtx(), dialTimeout)
+	defer cancel()
+	conn, err := cd.DialContext(ctx, "tcp", string(addr))
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to dial through proxy")
+	}
+	return conn, nil
+}
+
+// managedStartOnionService registers the onion service of the gateway with
+// Tor and returns the onion address of the gateway. The onion service is
+// removed when the gateway shuts down.
+func (g *Gateway) managedStartOnionService() (modules.NetAddress, error) {
+	tc, err := dialTorControl(g.staticProxy.TorControl)
+	if err != nil {
+		return "", err
+	}
+	if err := tc.authenticate(g.staticProxy.TorControlPassword); err != nil {
+		return "", errors.Compose(err, tc.Close())
+	}
+
+	// Reuse the key of the previous onion service to keep the address of the
+	// gateway stable.
+	g.mu.RLock()
+	key := g.persist.OnionPrivateKey
+	port := g.port
+	g.mu.RUnlock()
+	target := net.JoinHostPort("127.0.0.1", port)
+	serviceID, newKey, err := tc.addOnion(key, port, target)
+	if err != nil {
+		return "", errors.Compose(err, tc.Close())
+	}
+	if key == "" {
+		g.mu.Lock()
+		g.persist.OnionPrivateKey = newKey
+		err = g.saveSync()
+		g.mu.Unlock()
+		if err != nil {
+			return "", errors.Compose(err, tc.Close())
+		}
+	}
+
+	// The onion service lives as long as the control connection.
+	g.threads.AfterStop(tc.Close)
+	return modules.NetAddress(net.JoinHostPort(serviceID+onionSuffix, port)), nil
+}
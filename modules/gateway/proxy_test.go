@@ -0,0 +1,263 @@
+package gateway
+
+import (
+	"bufio"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+)
+
+// testOnionHost returns the hostname of a v3 onion service.
+func testOnionHost() string {
+	return strings.ToLower(base32.StdEncoding.EncodeToString(make([]byte, 35))) + onionSuffix
+}
+
+// newTestSOCKS5Proxy starts a minimal SOCKS5 proxy which only supports
+// CONNECT without authentication. The returned counter is incremented for
+// every proxied connection.
+func newTestSOCKS5Proxy(t *testing.T) (string, *uint64) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	var proxied uint64
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				// Greeting.
+				buf := make([]byte, 262)
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+					return
+				}
+				if _, err := conn.Write([]byte{5, 0}); err != nil {
+					return
+				}
+				// Request, the proxy is only used with IPv4 addresses.
+				if _, err := io.ReadFull(conn, buf[:10]); err != nil || buf[3] != 1 {
+					return
+				}
+				ip := net.IP(buf[4:8])
+				port := binary.BigEndian.Uint16(buf[8:10])
+				target, err := net.Dial("tcp", net.JoinHostPort(ip.String(), strconv.Itoa(int(port))))
+				if err != nil {
+					conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer target.Close()
+				if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+					return
+				}
+				atomic.AddUint64(&proxied, 1)
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+	return l.Addr().String(), &proxied
+}
+
+// newTestTorControl starts a fake Tor control port which supports the given
+// authentication method and expects the given AUTHENTICATE command. The
+// commands it received are sent on the returned channel.
+func newTestTorControl(t *testing.T, authLine, expectedAuth string) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	commands := make(chan string, 10)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := textproto.NewReader(bufio.NewReader(conn))
+		for {
+			line, err := r.ReadLine()
+			if err != nil {
+				return
+			}
+			commands <- line
+			var reply string
+			switch {
+			case line == "PROTOCOLINFO 1":
+				reply = "250-PROTOCOLINFO 1\r\n250-" + authLine + "\r\n250-VERSION Tor=\"0.4.5.7\"\r\n250 OK\r\n"
+			case line == expectedAuth:
+				reply = "250 OK\r\n"
+			case strings.HasPrefix(line, "ADD_ONION "):
+				reply = "250-ServiceID=" + strings.TrimSuffix(testOnionHost(), onionSuffix) + "\r\n250-PrivateKey=ED25519-V3:key\r\n250 OK\r\n"
+			default:
+				reply = "515 Authentication failed\r\n"
+			}
+			if _, err := conn.Write([]byte(reply)); err != nil {
+				return
+			}
+		}
+	}()
+	return l.Addr().String(), commands
+}
+
+// TestIsOnionHost checks that only v3 onion hostnames are detected.
+func TestIsOnionHost(t *testing.T) {
+	t.Parallel()
+	onion := testOnionHost()
+	tests := []struct {
+		host  string
+		onion bool
+	}{
+		{onion, true},
+		{strings.ToUpper(onion[:56]) + onionSuffix, true},
+		{onion[1:], false},
+		{"expyuzz4wqqyqhjn.onion", false},
+		{"example.com", false},
+		{"1.2.3.4", false},
+	}
+	for _, test := range tests {
+		if isOnionHost(test.host) != test.onion {
+			t.Errorf("isOnionHost(%v) should be %v", test.host, test.onion)
+		}
+	}
+
+	// Onion hosts are only dialable through a proxy.
+	g := &Gateway{}
+	if g.staticDialableHost(onion) || !g.staticDialableHost("1.2.3.4") {
+		t.Fatal("wrong dialable hosts without proxy")
+	}
+	g.staticProxy.Address = "127.0.0.1:9050"
+	if !g.staticDialableHost(onion) || g.staticDialableHost("example.com") {
+		t.Fatal("wrong dialable hosts with proxy")
+	}
+}
+
+// TestTorControl checks that the Tor control client authenticates and adds
+// onion services.
+func TestTorControl(t *testing.T) {
+	t.Parallel()
+	dir := build.TempDir(modules.GatewayDir, t.Name())
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	cookie := []byte{1, 2, 3, 4}
+	cookieFile := filepath.Join(dir, "control_auth_cookie")
+	if err := ioutil.WriteFile(cookieFile, cookie, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		authLine     string
+		password     string
+		expectedAuth string
+	}{
+		{"null", "AUTH METHODS=NULL", "", "AUTHENTICATE"},
+		{"cookie", "AUTH METHODS=COOKIE,SAFECOOKIE COOKIEFILE=" + strconv.Quote(cookieFile), "", "AUTHENTICATE " + hex.EncodeToString(cookie)},
+		{"password", "AUTH METHODS=COOKIE,HASHEDPASSWORD COOKIEFILE=\"/nonexistent\"", "pass\"word", `AUTHENTICATE "pass\"word"`},
+	}
+	for _, test := range tests {
+		addr, commands := newTestTorControl(t, test.authLine, test.expectedAuth)
+		tc, err := dialTorControl(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.authenticate(test.password); err != nil {
+			t.Fatal(test.name, err)
+		}
+		serviceID, key, err := tc.addOnion("", "9981", "127.0.0.1:9981")
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+		if serviceID+onionSuffix != testOnionHost() || key != "ED25519-V3:key" {
+			t.Fatal(test.name, "wrong onion service", serviceID, key)
+		}
+		if err := tc.Close(); err != nil {
+			t.Fatal(err)
+		}
+		<-commands
+		<-commands
+		if cmd := <-commands; cmd != "ADD_ONION NEW:ED25519-V3 Port=9981,127.0.0.1:9981" {
+			t.Fatal(test.name, "wrong command", cmd)
+		}
+	}
+
+	// Unsupported authentication methods are rejected.
+	addr, _ := newTestTorControl(t, "AUTH METHODS=SAFECOOKIE", "")
+	tc, err := dialTorControl(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tc.Close()
+	if err := tc.authenticate(""); err == nil {
+		t.Fatal("authentication should fail")
+	}
+}
+
+// TestProxyConnect checks that a gateway with a proxy connects to its peers
+// through the proxy.
+func TestProxyConnect(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	proxyAddr, proxied := newTestSOCKS5Proxy(t)
+	g1, err := NewCustomGateway("localhost:0", false, false, ProxySettings{Address: proxyAddr}, build.TempDir("gateway", t.Name()+"1"), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g1.Close()
+	g2 := newNamedTestingGateway(t, "2")
+	defer g2.Close()
+
+	if err := connectToNode(g1, g2, false); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadUint64(proxied) == 0 {
+		t.Fatal("connection wasn't proxied")
+	}
+
+	// An onion service requires a proxy.
+	_, err = NewCustomGateway("localhost:0", false, false, ProxySettings{Onion: true}, build.TempDir("gateway", t.Name()+"3"), modules.ProdDependencies)
+	if err == nil {
+		t.Fatal("onion service without proxy should fail")
+	}
+
+	// The onion address becomes the address of the gateway and the key of
+	// the onion service is persisted.
+	controlAddr, _ := newTestTorControl(t, "AUTH METHODS=NULL", "AUTHENTICATE")
+	g3, err := NewCustomGateway("localhost:0", false, false, ProxySettings{Address: proxyAddr, Onion: true, TorControl: controlAddr}, build.TempDir("gateway", t.Name()+"3"), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g3.Close()
+	if g3.Address() != modules.NetAddress(net.JoinHostPort(testOnionHost(), g3.port)) {
+		t.Fatal("wrong onion address", g3.Address())
+	}
+	g3.mu.RLock()
+	key := g3.persist.OnionPrivateKey
+	g3.mu.RUnlock()
+	if key != "ED25519-V3:key" {
+		t.Fatal("onion key wasn't persisted", key)
+	}
+}
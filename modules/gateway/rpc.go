@@ -57,6 +57,7 @@ func (g *Gateway) managedRPC(addr modules.NetAddress, name string, fn modules.RP
 	defer func() {
 		err = errors.Compose(err, conn.Close())
 	}()
+	conn = g.staticBandwidth.newConn(conn, rpcProtocol(handlerName(name)))
 
 	// write header
 	conn.SetDeadline(time.Now().Add(rpcStdDeadline))
@@ -225,6 +226,12 @@ func (g *Gateway) threadedHandleConn(conn modules.PeerConn) {
 	}
 	g.log.Debugf("INFO: incoming conn %v requested RPC \"%v\"", conn.RPCAddr(), id)
 
+	// Record the header, which is the length prefixed ID, and the rest of the
+	// RPC as traffic of its protocol.
+	protocol := rpcProtocol(id)
+	g.staticBandwidth.callRecord(protocol, 0, uint64(8+len(id)), time.Now())
+	conn = g.staticBandwidth.newConn(conn, protocol)
+
 	// call fn
 	startRPCTime := time.Now()
 	err = fn(conn)
@@ -0,0 +1,151 @@
+package gateway
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+)
+
+// torControl is a minimal client of the Tor control protocol, which is used
+// to register the onion service of the gateway.
+type torControl struct {
+	conn    *textproto.Conn
+	netConn net.Conn
+}
+
+// dialTorControl connects to the Tor control port at addr.
+func dialTorControl(addr string) (*torControl, error) {
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to connect to the Tor control port")
+	}
+	return &torControl{
+		conn:    textproto.NewConn(conn),
+		netConn: conn,
+	}, nil
+}
+
+// command sends a command and returns the lines of the reply. An error is
+// returned if Tor doesn't reply with 250 OK.
+func (tc *torControl) command(cmd string) ([]string, error) {
+	if err := tc.netConn.SetDeadline(time.Now().Add(connStdDeadline)); err != nil {
+		return nil, err
+	}
+	defer tc.netConn.SetDeadline(time.Time{})
+	if err := tc.conn.PrintfLine("%s", cmd); err != nil {
+		return nil, err
+	}
+	_, msg, err := tc.conn.ReadResponse(250)
+	if err != nil {
+		// Only the name of the command is added to the error since the
+		// arguments may contain secrets.
+		return nil, errors.AddContext(err, "Tor rejected "+strings.Fields(cmd)[0])
+	}
+	return strings.Split(msg, "\n"), nil
+}
+
+// authenticate authenticates with the control port. Passwords are used if
+// provided, otherwise the cookie file is read.
+func (tc *torControl) authenticate(password string) error {
+	lines, err := tc.command("PROTOCOLINFO 1")
+	if err != nil {
+		return err
+	}
+	var methods []string
+	var cookieFile string
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "AUTH ") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if strings.HasPrefix(field, "METHODS=") {
+				methods = strings.Split(strings.TrimPrefix(field, "METHODS="), ",")
+			}
+		}
+		if i := strings.Index(line, "COOKIEFILE="); i >= 0 {
+			cookieFile, err = parseTorQuotedString(line[i+len("COOKIEFILE="):])
+			if err != nil {
+				return errors.AddContext(err, "unable to parse cookie file")
+			}
+		}
+	}
+	supports := func(method string) bool {
+		for _, m := range methods {
+			if m == method {
+				return true
+			}
+		}
+		return false
+	}
+
+	var auth string
+	switch {
+	case password != "" && supports("HASHEDPASSWORD"):
+		auth = "AUTHENTICATE " + strconv.Quote(password)
+	case supports("NULL"):
+		auth = "AUTHENTICATE"
+	case supports("COOKIE") && cookieFile != "":
+		cookie, err := ioutil.ReadFile(cookieFile)
+		if err != nil {
+			return errors.AddContext(err, "unable to read Tor cookie file")
+		}
+		auth = "AUTHENTICATE " + hex.EncodeToString(cookie)
+	default:
+		return fmt.Errorf("no supported Tor authentication method in %v", methods)
+	}
+	_, err = tc.command(auth)
+	return err
+}
+
+// addOnion registers an onion service which forwards the virtual port to the
+// target address and returns its service ID. If key is empty, a new key is
+// created and returned.
+func (tc *torControl) addOnion(key, port, target string) (serviceID, newKey string, err error) {
+	if key == "" {
+		key = "NEW:ED25519-V3"
+	}
+	lines, err := tc.command(fmt.Sprintf("ADD_ONION %s Port=%s,%s", key, port, target))
+	if err != nil {
+		return "", "", err
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, "ServiceID=") {
+			serviceID = strings.TrimPrefix(line, "ServiceID=")
+		} else if strings.HasPrefix(line, "PrivateKey=") {
+			newKey = strings.TrimPrefix(line, "PrivateKey=")
+		}
+	}
+	if serviceID == "" {
+		return "", "", errors.New("Tor didn't return a service ID")
+	}
+	return serviceID, newKey, nil
+}
+
+// Close closes the connection to the control port, which removes the onion
+// service.
+func (tc *torControl) Close() error {
+	return tc.conn.Close()
+}
+
+// parseTorQuotedString parses the quoted string at the beginning of s.
+func parseTorQuotedString(s string) (string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", errors.New("string isn't quoted")
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return strconv.Unquote(s[:i+1])
+		}
+	}
+	return "", errors.New("string isn't terminated")
+}
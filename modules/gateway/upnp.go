@@ -113,6 +113,11 @@ func (g *Gateway) threadedLearnHostname() {
 	if build.Release == "testing" {
 		return
 	}
+	// Behind a proxy, the discovered IP would either leak the IP of the
+	// gateway or be the IP of the proxy.
+	if g.staticProxy.Address != "" {
+		return
+	}
 
 	for {
 		host, err := g.managedLearnHostname(nil)
@@ -151,15 +156,20 @@ func (g *Gateway) threadedLearnHostname() {
 	}
 }
 
-// managedForwardPort adds a port mapping to the router.
+// managedForwardPort adds a port mapping to the router using UPnP, falling
+// back to PCP and NAT-PMP.
 func (g *Gateway) managedForwardPort(port string) error {
+	// Track the port so that its reachability is probed even if it can't be
+	// mapped.
+	g.staticConnectivity.callAddPort(port)
+
 	if build.Release == "testing" {
 		// Port forwarding functions are frequently unavailable during testing,
 		// and the long blocking can be highly disruptive. Under normal
 		// scenarios, return without complaint, and without running the
 		// port-forward logic.
 		return nil
-	} else if !g.staticUseUPNP {
+	} else if !g.staticUseUPNP || g.staticProxy.Address != "" {
 		// UPnP is disabled
 		return nil
 	}
@@ -171,6 +181,23 @@ func (g *Gateway) managedForwardPort(port string) error {
 		return err
 	}
 
+	err = g.managedForwardPortUPNP(port, portInt)
+	if err == nil {
+		g.staticConnectivity.callSetMapping(port, modules.PortMappingUPnP, port, nil)
+		return nil
+	}
+	m, natErr := g.managedForwardPortNATPMP(uint16(portInt))
+	if natErr != nil {
+		err = errors.Compose(err, fmt.Errorf("WARN: could not automatically forward port %s with PCP or NAT-PMP: %v", port, natErr))
+		g.staticConnectivity.callSetMapping(port, modules.PortMappingNone, "", err)
+		return err
+	}
+	g.staticConnectivity.callSetMapping(port, m.method, strconv.Itoa(int(m.externalPort)), nil)
+	return nil
+}
+
+// managedForwardPortUPNP adds a port mapping to the router using UPnP.
+func (g *Gateway) managedForwardPortUPNP(port string, portInt int) error {
 	// Create a context to stop UPnP discovery in case of a shutdown.
 	ctx, cancel := context.WithCancel(g.threads.StopCtx())
 	defer cancel()
@@ -243,6 +270,7 @@ func (g *Gateway) threadedForwardPort(port string) {
 
 	if err := g.managedForwardPort(port); err != nil {
 		g.log.Debugf("WARN: %v", err)
+		return
 	}
 	g.log.Println("INFO: successfully forwarded port", port)
 	return
@@ -1,6 +1,8 @@
 package modules
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"go.sia.tech/siad/build"
@@ -98,6 +100,34 @@ var (
 		Testing:  types.BlockHeight(5),   // 5 seconds.
 	}).(types.BlockHeight)
 
+	// DefaultHostRenterLimit is the default limit of the resources a single
+	// renter can use on a host. It is generous enough to not affect renters
+	// that behave but stops a single renter from hogging the host.
+	DefaultHostRenterLimit = build.Select(build.Var{
+		Dev:      HostRenterLimit{MaxRPCRate: 100, MaxSessions: 32},
+		Standard: HostRenterLimit{MaxRPCRate: 100, MaxSessions: 32},
+		Testnet:  HostRenterLimit{MaxRPCRate: 100, MaxSessions: 32},
+		Testing:  HostRenterLimit{},
+	}).(HostRenterLimit)
+
+	// DefaultHostBandwidthPeriod is the default length of the accounting
+	// period of the host's bandwidth caps.
+	DefaultHostBandwidthPeriod = build.Select(build.Var{
+		Dev:      time.Hour,
+		Standard: 30 * 24 * time.Hour,
+		Testnet:  30 * 24 * time.Hour,
+		Testing:  time.Minute,
+	}).(time.Duration)
+
+	// MinHostBandwidthPeriod is the shortest accounting period of the host's
+	// bandwidth caps.
+	MinHostBandwidthPeriod = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: time.Hour,
+		Testnet:  time.Hour,
+		Testing:  time.Second,
+	}).(time.Duration)
+
 	// DefaultBaseRPCPrice is the default price of talking to the host. It is
 	// roughly equal to the default bandwidth cost of exchanging a pair of
 	// 4096-byte messages.
@@ -290,6 +320,337 @@ type (
 
 		CustomRegistryPath string `json:"customregistrypath"`
 		RegistrySize       uint64 `json:"registrysize"`
+
+		// MaxRegistryUpdateRate is the number of registry updates per second
+		// the host accepts from all renters combined. Updates above the rate
+		// are rejected. A value of zero means no limit.
+		MaxRegistryUpdateRate float64 `json:"maxregistryupdaterate"`
+
+		AccessLogging bool `json:"accesslogging"`
+
+		// MaintenanceMode stops the host from forming and renewing contracts
+		// and from accepting new sector data while it keeps serving downloads
+		// and submitting storage proofs for its existing contracts.
+		MaintenanceMode bool `json:"maintenancemode"`
+
+		// DiskHealthSMART enables querying the SMART health of the storage
+		// folders' disks with smartctl during the periodic disk health
+		// probes.
+		DiskHealthSMART bool `json:"diskhealthsmart"`
+
+		BandwidthCaps  HostBandwidthCaps   `json:"bandwidthcaps"`
+		ContractPolicy HostContractPolicy  `json:"contractpolicy"`
+		DynamicPricing HostDynamicPricing  `json:"dynamicpricing"`
+		RenterLimits   HostRenterLimits    `json:"renterlimits"`
+		Traffic        HostTrafficSettings `json:"traffic"`
+	}
+
+	// HostDynamicPricing configures the host's pricing engine. If enabled,
+	// the host periodically adjusts its storage price between the bounds
+	// based on the utilization of its storage and its bandwidth prices
+	// between the bounds based on the recent demand. A price is only adjusted
+	// if its upper bound is set.
+	HostDynamicPricing struct {
+		Enabled bool `json:"enabled"`
+
+		MinStoragePrice           types.Currency `json:"minstorageprice"`
+		MaxStoragePrice           types.Currency `json:"maxstorageprice"`
+		MinDownloadBandwidthPrice types.Currency `json:"mindownloadbandwidthprice"`
+		MaxDownloadBandwidthPrice types.Currency `json:"maxdownloadbandwidthprice"`
+		MinUploadBandwidthPrice   types.Currency `json:"minuploadbandwidthprice"`
+		MaxUploadBandwidthPrice   types.Currency `json:"maxuploadbandwidthprice"`
+
+		// ReferenceBandwidth is the number of bytes per second of RPC traffic
+		// at which the bandwidth prices reach their upper bounds. A value of
+		// zero uses the MaxBandwidth of the HostTrafficSettings. If neither is
+		// set, the bandwidth prices aren't adjusted.
+		ReferenceBandwidth int64 `json:"referencebandwidth"`
+	}
+
+	// HostBandwidthCaps limit the RPC traffic of the host within an
+	// accounting period. Once a cap is reached, the host refuses new bulk
+	// RPCs like sector transfers until the next period starts. Caps of
+	// individual renters are part of the HostRenterLimit.
+	HostBandwidthCaps struct {
+		// Period is the length of an accounting period. A value of zero uses
+		// the DefaultHostBandwidthPeriod.
+		Period time.Duration `json:"period"`
+
+		// MaxUpload and MaxDownload are the number of bytes that the host can
+		// send and receive within a period. A value of zero means that the
+		// traffic isn't capped.
+		MaxUpload   uint64 `json:"maxupload"`
+		MaxDownload uint64 `json:"maxdownload"`
+	}
+
+	// HostBandwidthUsage is the number of bytes the host sent to and received
+	// from renters.
+	HostBandwidthUsage struct {
+		Upload   uint64 `json:"upload"`
+		Download uint64 `json:"download"`
+	}
+
+	// HostBandwidthPeriod contains the RPC traffic of the host within the
+	// current accounting period.
+	HostBandwidthPeriod struct {
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end"`
+
+		// HostBandwidthUsage is the traffic of all RPCs.
+		HostBandwidthUsage
+
+		// Renters maps the string representation of a renter's public key to
+		// the traffic of its sessions. Only RPC loop sessions in which the
+		// renter locked a contract can be attributed to a renter.
+		Renters map[string]HostBandwidthUsage `json:"renters"`
+	}
+
+	// HostContractRevenue contains the revenue, expenses and collateral of a
+	// single storage obligation for accounting purposes. The revenue of
+	// unresolved obligations is potential revenue, the revenue of failed
+	// obligations was lost. Rejected obligations are not included.
+	HostContractRevenue struct {
+		ContractID      types.FileContractID `json:"contractid"`
+		RenterPublicKey types.SiaPublicKey   `json:"renterpublickey"`
+		Status          string               `json:"status"`
+
+		// NegotiationTime is the timestamp of the block at the negotiation
+		// height. ResolutionTime is the timestamp of the block at the start of
+		// the proof window for succeeded obligations and at the proof deadline
+		// for failed obligations. It is zero for unresolved obligations.
+		NegotiationHeight types.BlockHeight `json:"negotiationheight"`
+		ProofDeadline     types.BlockHeight `json:"proofdeadline"`
+		NegotiationTime   time.Time         `json:"negotiationtime"`
+		ResolutionTime    time.Time         `json:"resolutiontime"`
+
+		ContractCompensation types.Currency `json:"contractcompensation"`
+		StorageRevenue       types.Currency `json:"storagerevenue"`
+		DownloadRevenue      types.Currency `json:"downloadrevenue"`
+		UploadRevenue        types.Currency `json:"uploadrevenue"`
+		AccountFunding       types.Currency `json:"accountfunding"`
+		TransactionFees      types.Currency `json:"transactionfees"`
+
+		// BurnedCollateral is the risked collateral the host lost because it
+		// missed the storage proof of a failed obligation.
+		LockedCollateral types.Currency `json:"lockedcollateral"`
+		RiskedCollateral types.Currency `json:"riskedcollateral"`
+		BurnedCollateral types.Currency `json:"burnedcollateral"`
+	}
+
+	// HostRevenuePeriod contains the totals of the storage obligations which
+	// were resolved within a calendar month.
+	HostRevenuePeriod struct {
+		// Period is the month in the format YYYY-MM (UTC).
+		Period    string `json:"period"`
+		Contracts uint64 `json:"contracts"`
+
+		// The revenue of succeeded obligations.
+		ContractCompensation types.Currency `json:"contractcompensation"`
+		StorageRevenue       types.Currency `json:"storagerevenue"`
+		DownloadRevenue      types.Currency `json:"downloadrevenue"`
+		UploadRevenue        types.Currency `json:"uploadrevenue"`
+		AccountFunding       types.Currency `json:"accountfunding"`
+
+		// The transaction fees of all resolved obligations and the revenue
+		// and collateral lost due to failed obligations.
+		TransactionFees  types.Currency `json:"transactionfees"`
+		LostRevenue      types.Currency `json:"lostrevenue"`
+		BurnedCollateral types.Currency `json:"burnedcollateral"`
+	}
+
+	// HostRevenueReport contains the revenue of the host's storage
+	// obligations per contract and per calendar month.
+	HostRevenueReport struct {
+		Contracts []HostContractRevenue `json:"contracts"`
+		Periods   []HostRevenuePeriod   `json:"periods"`
+	}
+
+	// HostRenterStats contains the resources the host provides to a single
+	// renter.
+	HostRenterStats struct {
+		PublicKey types.SiaPublicKey `json:"publickey"`
+
+		// Contracts is the number of unresolved contracts of the renter and
+		// StoredData the size of the data they protect.
+		Contracts  uint64 `json:"contracts"`
+		StoredData uint64 `json:"storeddata"`
+
+		// Bandwidth is the RPC traffic of the renter within the host's current
+		// accounting period.
+		Bandwidth HostBandwidthUsage `json:"bandwidth"`
+	}
+
+	// HostTrafficSettings configure how the host prioritizes its RPC traffic
+	// on a saturated link. Latency-critical traffic like contract formation,
+	// renewals and revisions is never queued behind bulk sector transfers.
+	HostTrafficSettings struct {
+		// MaxBandwidth is the number of bytes per second that all of the
+		// host's RPC traffic shares for reading and writing respectively. A
+		// value of zero disables traffic prioritization.
+		MaxBandwidth int64 `json:"maxbandwidth"`
+
+		// PriorityBurst is the number of bytes that latency-critical traffic
+		// can transfer in excess of MaxBandwidth. The burst refills with
+		// bandwidth that is left unused.
+		PriorityBurst int64 `json:"priorityburst"`
+	}
+
+	// HostRegistryStats contains the usage of the host's registry since the
+	// host was started.
+	HostRegistryStats struct {
+		// Entries is the number of entries in the registry, MaxEntries the
+		// number of entries the registry can hold at its configured size.
+		Entries    uint64 `json:"entries"`
+		MaxEntries uint64 `json:"maxentries"`
+
+		// Reads is the number of registry lookups, ReadsFound the number of
+		// lookups for an existing entry.
+		Reads      uint64 `json:"reads"`
+		ReadsFound uint64 `json:"readsfound"`
+
+		// Updates is the number of successful registry updates,
+		// FailedUpdates the number of updates that were rejected by the
+		// registry, e.g. because of an outdated revision number or a full
+		// registry, and RateLimitedUpdates the number of updates that were
+		// rejected because they exceeded the MaxRegistryUpdateRate.
+		Updates            uint64 `json:"updates"`
+		FailedUpdates      uint64 `json:"failedupdates"`
+		RateLimitedUpdates uint64 `json:"ratelimitedupdates"`
+	}
+
+	// HostTrafficStats contains the state of the host's traffic
+	// prioritization.
+	HostTrafficStats struct {
+		HostTrafficSettings
+
+		// BurstAvailable is the number of bytes that latency-critical traffic
+		// can currently transfer in excess of MaxBandwidth in each direction.
+		BurstAvailable int64 `json:"burstavailable"`
+
+		Priority HostTrafficClassStats `json:"priority"`
+		Bulk     HostTrafficClassStats `json:"bulk"`
+	}
+
+	// HostTrafficClassStats contains the queue statistics of a single traffic
+	// class.
+	HostTrafficClassStats struct {
+		// Bytes is the number of bytes of the class that passed the scheduler
+		// since the host was started.
+		Bytes uint64 `json:"bytes"`
+
+		// Delayed is the number of reads and writes that had to wait for
+		// bandwidth and TotalDelay the total time they waited.
+		Delayed    uint64        `json:"delayed"`
+		TotalDelay time.Duration `json:"totaldelay"`
+		MaxDelay   time.Duration `json:"maxdelay"`
+
+		// Queued is the number of reads and writes that are currently waiting
+		// for bandwidth.
+		Queued uint64 `json:"queued"`
+	}
+
+	// HostContractPolicy is a declarative set of rules that every incoming
+	// contract formation or renewal is evaluated against on top of the host's
+	// regular settings. The zero value accepts all contracts.
+	HostContractPolicy struct {
+		// MinRenterFunds is the minimum amount of money a renter needs to put
+		// into a contract for the host to accept it. It acts as a price floor
+		// for contracts that are too small to be worth the host's overhead.
+		MinRenterFunds types.Currency `json:"minrenterfunds"`
+
+		// MinCompletedContracts is the number of contracts a renter needs to
+		// have successfully completed with the host before the host accepts
+		// new contracts from it. Renewals are exempt.
+		MinCompletedContracts uint64 `json:"mincompletedcontracts"`
+
+		// AcceptanceWindows are the times of day in which the host accepts
+		// contracts. If empty, contracts are accepted at any time.
+		AcceptanceWindows []HostAcceptanceWindow `json:"acceptancewindows"`
+	}
+
+	// HostRenterLimits throttle individual renters to prevent a single renter
+	// from starving the host's other customers. Renters are identified by the
+	// key of the contract they lock, which means the limits apply to RPC loop
+	// sessions once a contract was locked.
+	HostRenterLimits struct {
+		// Default is the limit of renters without an override.
+		Default HostRenterLimit `json:"default"`
+
+		// Overrides maps the string representation of a renter's public key
+		// to the limit of that renter.
+		Overrides map[string]HostRenterLimit `json:"overrides"`
+	}
+
+	// HostRenterLimit limits the resources a single renter can use. A limit
+	// of zero means that the resource is not limited.
+	HostRenterLimit struct {
+		// MaxBandwidth is the number of bytes per second that all sessions of
+		// the renter share for reading and writing respectively.
+		MaxBandwidth int64 `json:"maxbandwidth"`
+
+		// MaxRPCRate is the number of RPCs per second that all sessions of
+		// the renter share. RPCs above the limit are delayed.
+		MaxRPCRate float64 `json:"maxrpcrate"`
+
+		// MaxSessions is the number of sessions the renter can have open at
+		// the same time. Sessions above the limit are closed.
+		MaxSessions uint64 `json:"maxsessions"`
+
+		// MaxPeriodUpload and MaxPeriodDownload are the number of bytes the
+		// host sends to and receives from the renter within an accounting
+		// period of the host's HostBandwidthCaps.
+		MaxPeriodUpload   uint64 `json:"maxperiodupload"`
+		MaxPeriodDownload uint64 `json:"maxperioddownload"`
+	}
+
+	// HostAcceptanceWindow is a time of day window, specified in minutes since
+	// midnight UTC. A window with an end before its start wraps around
+	// midnight.
+	HostAcceptanceWindow struct {
+		Start uint16 `json:"start"`
+		End   uint16 `json:"end"`
+	}
+
+	// HostContractDecision describes the outcome of evaluating an incoming
+	// contract formation or renewal request.
+	HostContractDecision struct {
+		Accepted  bool               `json:"accepted"`
+		Duration  types.BlockHeight  `json:"duration"`
+		Reason    string             `json:"reason"`
+		Renewal   bool               `json:"renewal"`
+		RenterKey types.SiaPublicKey `json:"renterkey"`
+		Timestamp time.Time          `json:"timestamp"`
+	}
+
+	// HostScrubReport contains the progress of the host's integrity scrubber
+	// and the contracts whose storage proofs will fail because they cover
+	// quarantined sectors.
+	HostScrubReport struct {
+		StorageScrubStatus
+		ContractsAtRisk []HostContractAtRisk `json:"contractsatrisk"`
+	}
+
+	// HostContractAtRisk is an unresolved contract which covers quarantined
+	// sectors. Unless the renter uploads the data again, the host will fail
+	// to submit a storage proof for the contract and lose its collateral.
+	HostContractAtRisk struct {
+		ContractID       types.FileContractID `json:"contractid"`
+		CorruptSectors   uint64               `json:"corruptsectors"`
+		ProofWindowStart types.BlockHeight    `json:"proofwindowstart"`
+		ProofDeadline    types.BlockHeight    `json:"proofdeadline"`
+		LockedCollateral types.Currency       `json:"lockedcollateral"`
+	}
+
+	// HostSectorAccess contains the sector reads and writes of a single
+	// contract within an hour. Bytes written are counted in full sectors
+	// since that is what the host writes to disk.
+	HostSectorAccess struct {
+		ContractID   types.FileContractID `json:"contractid"`
+		Hour         time.Time            `json:"hour"`
+		SectorReads  uint64               `json:"sectorreads"`
+		SectorWrites uint64               `json:"sectorwrites"`
+		BytesRead    uint64               `json:"bytesread"`
+		BytesWritten uint64               `json:"byteswritten"`
 	}
 
 	// HostNetworkMetrics reports the quantity of each type of RPC call that
@@ -316,6 +677,7 @@ type (
 		PotentialDownloadRevenue types.Currency       `json:"potentialdownloadrevenue"`
 		PotentialStorageRevenue  types.Currency       `json:"potentialstoragerevenue"`
 		PotentialUploadRevenue   types.Currency       `json:"potentialuploadrevenue"`
+		RenterPublicKey          types.SiaPublicKey   `json:"renterpublickey"`
 		RiskedCollateral         types.Currency       `json:"riskedcollateral"`
 		SectorRootsCount         uint64               `json:"sectorrootscount"`
 		TransactionFeesAdded     types.Currency       `json:"transactionfeesadded"`
@@ -398,6 +760,22 @@ type (
 		// that is, if it can connect to itself on the configured NetAddress.
 		ConnectabilityStatus() HostConnectabilityStatus
 
+		// ContractDecisions returns the most recent decisions the host made
+		// about incoming contract formation and renewal requests.
+		ContractDecisions() []HostContractDecision
+
+		// BandwidthPeriod returns the host's RPC traffic within the current
+		// accounting period of its bandwidth caps.
+		BandwidthPeriod() HostBandwidthPeriod
+
+		// RevenueReport returns the revenue of the host's storage obligations
+		// per contract and per calendar month for accounting purposes.
+		RevenueReport() HostRevenueReport
+
+		// RenterStats returns the resources the host provides to each renter,
+		// ordered by the size of the renters' stored data.
+		RenterStats() []HostRenterStats
+
 		// DeleteSector deletes a sector, meaning that the host will be
 		// unable to upload that sector and be unable to provide a storage
 		// proof on that sector. DeleteSector is for removing the data
@@ -406,6 +784,12 @@ type (
 		// requests to remove data.
 		DeleteSector(sectorRoot crypto.Hash) error
 
+		// DrainStorageFolder marks a storage folder for removal, or unmarks it
+		// if drain is false. The sectors of a draining storage folder are
+		// migrated to other storage folders in the background and the folder
+		// is removed once it is empty.
+		DrainStorageFolder(index uint16, drain bool) error
+
 		// ExternalSettings returns the settings of the host as seen by an
 		// untrusted node querying the host for settings.
 		ExternalSettings() HostExternalSettings
@@ -481,6 +865,10 @@ type (
 		// and the resize operation completed, meaning that data will be lost.
 		ResizeStorageFolder(index uint16, newSize uint64, force bool) error
 
+		// SectorAccessLog returns the hourly sector accesses of the host's
+		// contracts that were recorded while access logging was enabled.
+		SectorAccessLog() []HostSectorAccess
+
 		// SetInternalSettings sets the hosting parameters of the host.
 		SetInternalSettings(HostInternalSettings) error
 
@@ -496,6 +884,21 @@ type (
 		// host.
 		StorageFolders() []StorageFolderMetadata
 
+		// ScrubReport returns the progress of the host's integrity scrubber
+		// and the contracts whose storage proofs will fail because of
+		// quarantined sectors, ordered by their proof window.
+		ScrubReport() (HostScrubReport, error)
+
+		// TrafficStats returns the state of the host's traffic prioritization.
+		TrafficStats() HostTrafficStats
+
+		// RegistryStats returns the usage of the host's registry.
+		RegistryStats() HostRegistryStats
+
+		// ValidateInternalSettings checks the proposed internal settings for
+		// consistency and projected consequences without applying them.
+		ValidateInternalSettings(HostInternalSettings) (SettingsValidation, error)
+
 		// WorkingStatus returns the working state of the host, determined by if
 		// settings calls are increasing.
 		WorkingStatus() HostWorkingStatus
@@ -514,6 +917,157 @@ func (his HostInternalSettings) MaxSectorAccessPrice() types.Currency {
 	return his.MinDownloadBandwidthPrice.Mul64(MaxSectorAccessPriceVsBandwidth)
 }
 
+// minutesPerDay is the number of minutes in a day.
+const minutesPerDay = 24 * 60
+
+// Contains returns whether the time of day of t falls into the window.
+func (w HostAcceptanceWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	minute := uint16(t.Hour()*60 + t.Minute())
+	if w.Start <= w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// String returns the window in the HH:MM-HH:MM format.
+func (w HostAcceptanceWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// Validate checks that the window is well formed.
+func (w HostAcceptanceWindow) Validate() error {
+	if w.Start >= minutesPerDay || w.End >= minutesPerDay {
+		return fmt.Errorf("acceptance window %v is out of range", w)
+	}
+	if w.Start == w.End {
+		return fmt.Errorf("acceptance window %v is empty", w)
+	}
+	return nil
+}
+
+// ParseHostAcceptanceWindows parses a comma separated list of acceptance
+// windows in the HH:MM-HH:MM format. An empty string results in no windows.
+func ParseHostAcceptanceWindows(s string) ([]HostAcceptanceWindow, error) {
+	var windows []HostAcceptanceWindow
+	if strings.TrimSpace(s) == "" {
+		return windows, nil
+	}
+	for _, ws := range strings.Split(s, ",") {
+		var startH, startM, endH, endM uint16
+		_, err := fmt.Sscanf(strings.TrimSpace(ws), "%d:%d-%d:%d", &startH, &startM, &endH, &endM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse acceptance window '%v': %v", ws, err)
+		}
+		if startH > 23 || endH > 23 || startM > 59 || endM > 59 {
+			return nil, fmt.Errorf("acceptance window '%v' is out of range", ws)
+		}
+		w := HostAcceptanceWindow{
+			Start: startH*60 + startM,
+			End:   endH*60 + endM,
+		}
+		if err := w.Validate(); err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// Validate checks the policy for internal consistency.
+func (p HostContractPolicy) Validate() error {
+	for _, w := range p.AcceptanceWindows {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Limit returns the limit of the renter with the given key.
+func (l HostRenterLimits) Limit(renterKey types.SiaPublicKey) HostRenterLimit {
+	if limit, exists := l.Overrides[renterKey.String()]; exists {
+		return limit
+	}
+	return l.Default
+}
+
+// Validate checks the limits for internal consistency.
+func (l HostRenterLimits) Validate() error {
+	if err := l.Default.Validate(); err != nil {
+		return err
+	}
+	for key, limit := range l.Overrides {
+		var spk types.SiaPublicKey
+		if err := spk.LoadString(key); err != nil || spk.String() != key {
+			return fmt.Errorf("invalid renter key '%v'", key)
+		}
+		if err := limit.Validate(); err != nil {
+			return fmt.Errorf("invalid limit for renter %v: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// PeriodLength returns the length of an accounting period.
+func (c HostBandwidthCaps) PeriodLength() time.Duration {
+	if c.Period == 0 {
+		return DefaultHostBandwidthPeriod
+	}
+	return c.Period
+}
+
+// Validate checks the bandwidth caps for consistency.
+func (c HostBandwidthCaps) Validate() error {
+	if c.Period < 0 {
+		return fmt.Errorf("period %v is negative", c.Period)
+	}
+	if c.Period != 0 && c.Period < MinHostBandwidthPeriod {
+		return fmt.Errorf("period %v is shorter than the minimum of %v", c.Period, MinHostBandwidthPeriod)
+	}
+	return nil
+}
+
+// Validate checks that the lower bounds of the dynamic pricing don't exceed
+// the upper bounds.
+func (dp HostDynamicPricing) Validate() error {
+	if dp.MinStoragePrice.Cmp(dp.MaxStoragePrice) > 0 && !dp.MaxStoragePrice.IsZero() {
+		return fmt.Errorf("min storage price %v exceeds max storage price %v", dp.MinStoragePrice, dp.MaxStoragePrice)
+	}
+	if dp.MinDownloadBandwidthPrice.Cmp(dp.MaxDownloadBandwidthPrice) > 0 && !dp.MaxDownloadBandwidthPrice.IsZero() {
+		return fmt.Errorf("min download bandwidth price %v exceeds max download bandwidth price %v", dp.MinDownloadBandwidthPrice, dp.MaxDownloadBandwidthPrice)
+	}
+	if dp.MinUploadBandwidthPrice.Cmp(dp.MaxUploadBandwidthPrice) > 0 && !dp.MaxUploadBandwidthPrice.IsZero() {
+		return fmt.Errorf("min upload bandwidth price %v exceeds max upload bandwidth price %v", dp.MinUploadBandwidthPrice, dp.MaxUploadBandwidthPrice)
+	}
+	if dp.ReferenceBandwidth < 0 {
+		return fmt.Errorf("reference bandwidth %v is negative", dp.ReferenceBandwidth)
+	}
+	return nil
+}
+
+// Validate checks that none of the traffic settings is negative.
+func (ts HostTrafficSettings) Validate() error {
+	if ts.MaxBandwidth < 0 {
+		return fmt.Errorf("max bandwidth %v is negative", ts.MaxBandwidth)
+	}
+	if ts.PriorityBurst < 0 {
+		return fmt.Errorf("priority burst %v is negative", ts.PriorityBurst)
+	}
+	return nil
+}
+
+// Validate checks that none of the limits is negative.
+func (l HostRenterLimit) Validate() error {
+	if l.MaxBandwidth < 0 {
+		return fmt.Errorf("max bandwidth %v is negative", l.MaxBandwidth)
+	}
+	if l.MaxRPCRate < 0 {
+		return fmt.Errorf("max rpc rate %v is negative", l.MaxRPCRate)
+	}
+	return nil
+}
+
 // DefaultHostExternalSettings returns HostExternalSettings with certain default
 // fields set. NetAddress, RemainingStorage, TotalStorage, UnlockHash, RevisionNumber and SiaMuxPort are not set.
 func DefaultHostExternalSettings() HostExternalSettings {
@@ -0,0 +1,187 @@
+package host
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// accessLogFile is the name of the file the host persists its sector
+	// access log in.
+	accessLogFile = "accesslog.json"
+
+	// maxAccessLogEntries is the maximum number of hourly contract entries
+	// the access log keeps. Once it is exceeded, the oldest hours are
+	// dropped first.
+	maxAccessLogEntries = 100e3
+)
+
+var (
+	// accessLogMetadata is the metadata of the access log persist file.
+	accessLogMetadata = persist.Metadata{
+		Header:  "Host Access Log",
+		Version: "1.5.5",
+	}
+
+	// accessLogRetention is the amount of time an hourly entry is kept in
+	// the access log.
+	accessLogRetention = build.Select(build.Var{
+		Standard: 30 * 24 * time.Hour,
+		Testnet:  30 * 24 * time.Hour,
+		Dev:      24 * time.Hour,
+		Testing:  time.Hour,
+	}).(time.Duration)
+
+	// accessLogPruneFrequency is the frequency at which the access log is
+	// pruned and saved.
+	accessLogPruneFrequency = build.Select(build.Var{
+		Standard: time.Hour,
+		Testnet:  time.Hour,
+		Dev:      10 * time.Minute,
+		Testing:  30 * time.Second,
+	}).(time.Duration)
+)
+
+// accessLogKey identifies an entry of the access log.
+type accessLogKey struct {
+	contractID types.FileContractID
+	hour       int64
+}
+
+// accessLog aggregates the sector reads and writes of the host's contracts
+// per contract and hour.
+type accessLog struct {
+	entries map[accessLogKey]*modules.HostSectorAccess
+
+	staticPersistPath string
+	mu                sync.Mutex
+}
+
+// newAccessLog loads the access log from disk or initializes an empty one.
+func newAccessLog(persistDir string) (*accessLog, error) {
+	al := &accessLog{
+		entries:           make(map[accessLogKey]*modules.HostSectorAccess),
+		staticPersistPath: filepath.Join(persistDir, accessLogFile),
+	}
+	var entries []modules.HostSectorAccess
+	err := persist.LoadJSON(accessLogMetadata, &entries, al.staticPersistPath)
+	if os.IsNotExist(err) {
+		return al, nil
+	}
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to load access log")
+	}
+	for i := range entries {
+		al.entries[accessLogKey{entries[i].ContractID, entries[i].Hour.Unix()}] = &entries[i]
+	}
+	return al, nil
+}
+
+// callAdd adds the reads and writes of the access to the entry of its
+// contract and hour.
+func (al *accessLog) callAdd(access modules.HostSectorAccess) {
+	access.Hour = access.Hour.Truncate(time.Hour)
+	key := accessLogKey{access.ContractID, access.Hour.Unix()}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	entry, exists := al.entries[key]
+	if !exists {
+		al.entries[key] = &access
+		return
+	}
+	entry.SectorReads += access.SectorReads
+	entry.SectorWrites += access.SectorWrites
+	entry.BytesRead += access.BytesRead
+	entry.BytesWritten += access.BytesWritten
+}
+
+// callEntries returns all entries of the access log, most recent first.
+func (al *accessLog) callEntries() []modules.HostSectorAccess {
+	al.mu.Lock()
+	entries := make([]modules.HostSectorAccess, 0, len(al.entries))
+	for _, entry := range al.entries {
+		entries = append(entries, *entry)
+	}
+	al.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Hour.Equal(entries[j].Hour) {
+			return entries[i].Hour.After(entries[j].Hour)
+		}
+		return entries[i].ContractID.String() < entries[j].ContractID.String()
+	})
+	return entries
+}
+
+// callPrune drops the entries that are past their retention and the oldest
+// entries beyond maxAccessLogEntries.
+func (al *accessLog) callPrune(now time.Time) {
+	entries := al.callEntries()
+	cutoff := now.Add(-accessLogRetention)
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	for i, entry := range entries {
+		if i < maxAccessLogEntries && entry.Hour.After(cutoff) {
+			continue
+		}
+		delete(al.entries, accessLogKey{entry.ContractID, entry.Hour.Unix()})
+	}
+}
+
+// callSave persists the access log.
+func (al *accessLog) callSave() error {
+	return persist.SaveJSON(accessLogMetadata, al.callEntries(), al.staticPersistPath)
+}
+
+// managedLogSectorAccess records the access in the access log if access
+// logging is enabled.
+func (h *Host) managedLogSectorAccess(access modules.HostSectorAccess) {
+	h.mu.RLock()
+	enabled := h.settings.AccessLogging
+	h.mu.RUnlock()
+	if enabled {
+		h.staticAccessLog.callAdd(access)
+	}
+}
+
+// threadedPruneAccessLog periodically prunes and saves the access log.
+func (h *Host) threadedPruneAccessLog() {
+	for {
+		func() {
+			if err := h.tg.Add(); err != nil {
+				return
+			}
+			defer h.tg.Done()
+			h.staticAccessLog.callPrune(time.Now())
+			if err := h.staticAccessLog.callSave(); err != nil {
+				h.log.Println("Could not save access log:", err)
+			}
+		}()
+
+		// Block until next cycle.
+		select {
+		case <-h.tg.StopChan():
+			return
+		case <-time.After(accessLogPruneFrequency):
+			continue
+		}
+	}
+}
+
+// SectorAccessLog returns the hourly sector accesses of the host's contracts,
+// most recent first.
+func (h *Host) SectorAccessLog() []modules.HostSectorAccess {
+	return h.staticAccessLog.callEntries()
+}
@@ -0,0 +1,69 @@
+package host
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestAccessLog is a unit test for the aggregation, pruning and persistence
+// of the host's access log.
+func TestAccessLog(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir(modules.HostDir, t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	al, err := newAccessLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Accesses within the same hour and contract are aggregated.
+	fcid1 := types.FileContractID{1}
+	fcid2 := types.FileContractID{2}
+	now := time.Now().Truncate(time.Hour)
+	al.callAdd(modules.HostSectorAccess{ContractID: fcid1, Hour: now, SectorReads: 1, BytesRead: 10})
+	al.callAdd(modules.HostSectorAccess{ContractID: fcid1, Hour: now.Add(time.Minute), SectorWrites: 1, BytesWritten: modules.SectorSize})
+	al.callAdd(modules.HostSectorAccess{ContractID: fcid2, Hour: now, SectorReads: 2, BytesRead: 20})
+	al.callAdd(modules.HostSectorAccess{ContractID: fcid1, Hour: now.Add(-time.Hour), SectorReads: 3, BytesRead: 30})
+
+	entries := al.callEntries()
+	if len(entries) != 3 {
+		t.Fatal("expected 3 entries but got", len(entries))
+	}
+	e := entries[0]
+	if e.ContractID != fcid1 || !e.Hour.Equal(now) || e.SectorReads != 1 || e.SectorWrites != 1 || e.BytesRead != 10 || e.BytesWritten != modules.SectorSize {
+		t.Fatalf("wrong aggregated entry %+v", e)
+	}
+	if entries[1].ContractID != fcid2 || !entries[2].Hour.Equal(now.Add(-time.Hour)) {
+		t.Fatal("entries aren't sorted", entries)
+	}
+
+	// Save and reload the log.
+	if err := al.callSave(); err != nil {
+		t.Fatal(err)
+	}
+	al2, err := newAccessLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := al2.callEntries(); len(entries) != 3 || entries[0].BytesWritten != modules.SectorSize {
+		t.Fatal("log wasn't persisted", entries)
+	}
+
+	// Entries past their retention are pruned.
+	al2.callPrune(now.Add(accessLogRetention - time.Minute))
+	entries = al2.callEntries()
+	if len(entries) != 2 || !entries[1].Hour.Equal(now) {
+		t.Fatal("old entries weren't pruned", entries)
+	}
+}
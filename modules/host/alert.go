@@ -1,6 +1,13 @@
 package host
 
-import "go.sia.tech/siad/modules"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
 
 // Alerts implements the modules.Alerter interface for the host.
 func (h *Host) Alerts() (crit, err, warn, info []modules.Alert) {
@@ -23,3 +30,81 @@ func (h *Host) tryUnregisterInsufficientCollateralBudgetAlert() {
 		h.staticAlerter.UnregisterAlert(modules.AlertIDHostInsufficientCollateral)
 	}
 }
+
+// storageFolderWriteFailures returns the paths of the storage folders whose
+// failed writes increased since the last check and the failed writes of all
+// storage folders for the next check.
+func storageFolderWriteFailures(last map[string]uint64, sfs []modules.StorageFolderMetadata) ([]string, map[string]uint64) {
+	var failing []string
+	failedWrites := make(map[string]uint64, len(sfs))
+	for _, sf := range sfs {
+		if sf.FailedWrites > last[sf.Path] {
+			failing = append(failing, sf.Path)
+		}
+		failedWrites[sf.Path] = sf.FailedWrites
+	}
+	return failing, failedWrites
+}
+
+// managedCheckAlerts registers or unregisters the alerts for a low wallet
+// balance, storage folder write failures and clock skew. It returns the failed
+// writes of the storage folders for the next check.
+func (h *Host) managedCheckAlerts(lastFailedWrites map[string]uint64) map[string]uint64 {
+	// Check that the wallet can cover the collateral of a new contract.
+	h.mu.RLock()
+	accepting := h.settings.AcceptingContracts && !h.settings.MaintenanceMode
+	maxCollateral := h.settings.MaxCollateral
+	h.mu.RUnlock()
+	balance, _, _, err := h.wallet.ConfirmedBalance()
+	if err == nil && accepting && balance.Cmp(maxCollateral) < 0 {
+		cause := fmt.Sprintf("confirmed balance of %v is below the max collateral of %v", balance.HumanString(), maxCollateral.HumanString())
+		h.staticAlerter.RegisterAlert(modules.AlertIDHostLowBalance, AlertMSGHostLowBalance, cause, modules.SeverityWarning)
+	} else if err == nil {
+		h.staticAlerter.UnregisterAlert(modules.AlertIDHostLowBalance)
+	}
+
+	// Check whether writes to the storage folders failed since the last
+	// check.
+	failing, failedWrites := storageFolderWriteFailures(lastFailedWrites, h.StorageFolders())
+	if len(failing) > 0 {
+		cause := "failed writes to " + strings.Join(failing, ", ")
+		h.staticAlerter.RegisterAlert(modules.AlertIDHostStorageFolderWriteFailures, AlertMSGHostStorageFolderWriteFailures, cause, modules.SeverityError)
+	} else {
+		h.staticAlerter.UnregisterAlert(modules.AlertIDHostStorageFolderWriteFailures)
+	}
+
+	// Check whether the latest block is ahead of the host's clock. The
+	// timestamps of blocks are set by the miners, so a block from the future
+	// indicates that the host's clock is behind the network.
+	now := types.CurrentTimestamp()
+	if blockTime := h.cs.CurrentBlock().Timestamp; blockTime > now+maxClockSkew {
+		cause := fmt.Sprintf("the latest block is %v ahead of the host's clock", time.Duration(blockTime-now)*time.Second)
+		h.staticAlerter.RegisterAlert(modules.AlertIDHostClockSkew, AlertMSGHostClockSkew, cause, modules.SeverityWarning)
+	} else {
+		h.staticAlerter.UnregisterAlert(modules.AlertIDHostClockSkew)
+	}
+	return failedWrites
+}
+
+// threadedCheckAlerts periodically checks the conditions of the host's alerts
+// which aren't triggered by events.
+func (h *Host) threadedCheckAlerts() {
+	var failedWrites map[string]uint64
+	for {
+		func() {
+			if err := h.tg.Add(); err != nil {
+				return
+			}
+			defer h.tg.Done()
+			failedWrites = h.managedCheckAlerts(failedWrites)
+		}()
+
+		// Block until next cycle.
+		select {
+		case <-h.tg.StopChan():
+			return
+		case <-time.After(alertCheckInterval):
+			continue
+		}
+	}
+}
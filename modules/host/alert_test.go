@@ -0,0 +1,39 @@
+package host
+
+import (
+	"reflect"
+	"testing"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestStorageFolderWriteFailures is a unit test for the detection of storage
+// folders with new write failures.
+func TestStorageFolderWriteFailures(t *testing.T) {
+	t.Parallel()
+
+	sfs := []modules.StorageFolderMetadata{
+		{Path: "/a", FailedWrites: 0},
+		{Path: "/b", FailedWrites: 2},
+	}
+
+	// Failures before the first check are reported.
+	failing, failedWrites := storageFolderWriteFailures(nil, sfs)
+	if !reflect.DeepEqual(failing, []string{"/b"}) {
+		t.Fatal("wrong failing folders", failing)
+	}
+
+	// Without new failures no folder is reported.
+	failing, failedWrites = storageFolderWriteFailures(failedWrites, sfs)
+	if len(failing) != 0 {
+		t.Fatal("folders without new failures were reported", failing)
+	}
+
+	// New failures are reported, including those of new folders.
+	sfs[0].FailedWrites = 1
+	sfs = append(sfs, modules.StorageFolderMetadata{Path: "/c", FailedWrites: 1})
+	failing, _ = storageFolderWriteFailures(failedWrites, sfs)
+	if !reflect.DeepEqual(failing, []string{"/a", "/c"}) {
+		t.Fatal("wrong failing folders", failing)
+	}
+}
@@ -0,0 +1,210 @@
+package host
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/siamux"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// ErrBandwidthCapReached is returned if a renter starts a bulk RPC after
+	// the host reached its bandwidth cap for the current period.
+	ErrBandwidthCapReached = ErrorCommunication("host reached its bandwidth cap for the current period")
+
+	// ErrRenterBandwidthCapReached is returned if a renter starts a bulk RPC
+	// after it reached its bandwidth cap for the current period.
+	ErrRenterBandwidthCapReached = ErrorCommunication("renter reached its bandwidth cap with the host for the current period")
+)
+
+type (
+	// bandwidthMeter keeps track of the host's RPC traffic within the current
+	// accounting period. The host's total traffic is also reported to the
+	// gateway, which tracks the bandwidth usage of the node by protocol.
+	bandwidthMeter struct {
+		caps   modules.HostBandwidthCaps
+		period modules.HostBandwidthPeriod
+		mu     sync.Mutex
+
+		staticGateway modules.Gateway
+	}
+
+	// bandwidthMeterConn is a connection whose traffic is recorded by the
+	// host's bandwidth meter. If staticRenter is set, the traffic is
+	// attributed to that renter, otherwise it counts towards the host's
+	// total.
+	bandwidthMeterConn struct {
+		net.Conn
+		staticRenter string
+		staticMeter  *bandwidthMeter
+	}
+
+	// bandwidthMeterStream is a SiaMux stream whose traffic is recorded by
+	// the host's bandwidth meter.
+	bandwidthMeterStream struct {
+		siamux.Stream
+		staticConn *bandwidthMeterConn
+	}
+)
+
+// newBandwidthMeter creates a new bandwidth meter which reports the host's
+// traffic to the gateway, if one is provided.
+func newBandwidthMeter(g modules.Gateway) *bandwidthMeter {
+	return &bandwidthMeter{
+		staticGateway: g,
+	}
+}
+
+// advance starts a new period if the current one is over. Periods start at
+// multiples of the period length after the first period. The caller must hold
+// the lock.
+func (bm *bandwidthMeter) advance(now time.Time) {
+	length := bm.caps.PeriodLength()
+	if bm.period.Start.IsZero() || now.Before(bm.period.Start) {
+		bm.period = modules.HostBandwidthPeriod{Start: now}
+	} else if elapsed := now.Sub(bm.period.Start); elapsed >= length {
+		bm.period = modules.HostBandwidthPeriod{Start: bm.period.Start.Add(elapsed / length * length)}
+	}
+	bm.period.End = bm.period.Start.Add(length)
+	if bm.period.Renters == nil {
+		bm.period.Renters = make(map[string]modules.HostBandwidthUsage)
+	}
+}
+
+// callSetSettings updates the bandwidth caps of the meter.
+func (bm *bandwidthMeter) callSetSettings(caps modules.HostBandwidthCaps) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.caps = caps
+}
+
+// callLoad restores the period that was persisted by the host.
+func (bm *bandwidthMeter) callLoad(period modules.HostBandwidthPeriod) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.period = period
+}
+
+// callPeriod returns a copy of the current period.
+func (bm *bandwidthMeter) callPeriod(now time.Time) modules.HostBandwidthPeriod {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.advance(now)
+	period := bm.period
+	period.Renters = make(map[string]modules.HostBandwidthUsage, len(bm.period.Renters))
+	for key, usage := range bm.period.Renters {
+		period.Renters[key] = usage
+	}
+	return period
+}
+
+// callRecord records traffic of the renter with the given key. If the key is
+// empty, the traffic counts towards the host's total.
+func (bm *bandwidthMeter) callRecord(renter string, upload, download uint64, now time.Time) {
+	if renter == "" && bm.staticGateway != nil {
+		bm.staticGateway.RecordBandwidth(modules.BandwidthProtocolRenterHost, upload, download)
+	}
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.advance(now)
+	if renter == "" {
+		bm.period.Upload += upload
+		bm.period.Download += download
+		return
+	}
+	usage := bm.period.Renters[renter]
+	usage.Upload += upload
+	usage.Download += download
+	bm.period.Renters[renter] = usage
+}
+
+// callCheckCaps returns an error if the host or the renter with the given key
+// reached a bandwidth cap in the current period.
+func (bm *bandwidthMeter) callCheckCaps(renter string, limit modules.HostRenterLimit, now time.Time) error {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	bm.advance(now)
+	if reachedCap(bm.period.HostBandwidthUsage, bm.caps.MaxUpload, bm.caps.MaxDownload) {
+		return ErrBandwidthCapReached
+	}
+	if renter != "" && reachedCap(bm.period.Renters[renter], limit.MaxPeriodUpload, limit.MaxPeriodDownload) {
+		return ErrRenterBandwidthCapReached
+	}
+	return nil
+}
+
+// reachedCap returns whether the usage reached one of the caps. A cap of zero
+// is ignored.
+func reachedCap(usage modules.HostBandwidthUsage, maxUpload, maxDownload uint64) bool {
+	return (maxUpload > 0 && usage.Upload >= maxUpload) || (maxDownload > 0 && usage.Download >= maxDownload)
+}
+
+// newConn wraps the connection to record its traffic.
+func (bm *bandwidthMeter) newConn(conn net.Conn, renter string) *bandwidthMeterConn {
+	return &bandwidthMeterConn{
+		Conn:         conn,
+		staticRenter: renter,
+		staticMeter:  bm,
+	}
+}
+
+// newStream wraps the stream to record its traffic towards the host's total.
+func (bm *bandwidthMeter) newStream(stream siamux.Stream) *bandwidthMeterStream {
+	return &bandwidthMeterStream{
+		Stream:     stream,
+		staticConn: bm.newConn(stream, ""),
+	}
+}
+
+// Read reads from the connection and records the bytes that were read.
+func (c *bandwidthMeterConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.staticMeter.callRecord(c.staticRenter, 0, uint64(n), time.Now())
+	}
+	return n, err
+}
+
+// Write writes to the connection and records the bytes that were written.
+func (c *bandwidthMeterConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		c.staticMeter.callRecord(c.staticRenter, uint64(n), 0, time.Now())
+	}
+	return n, err
+}
+
+// Read reads from the stream.
+func (s *bandwidthMeterStream) Read(p []byte) (int, error) {
+	return s.staticConn.Read(p)
+}
+
+// Write writes to the stream.
+func (s *bandwidthMeterStream) Write(p []byte) (int, error) {
+	return s.staticConn.Write(p)
+}
+
+// managedCheckBandwidthCaps returns an error if the host or the renter with
+// the given key reached a bandwidth cap in the current period. The key can be
+// empty if the renter is unknown.
+func (h *Host) managedCheckBandwidthCaps(renterKey types.SiaPublicKey) error {
+	var renter string
+	var limit modules.HostRenterLimit
+	if len(renterKey.Key) != 0 {
+		renter = renterKey.String()
+		h.mu.RLock()
+		limit = h.settings.RenterLimits.Limit(renterKey)
+		h.mu.RUnlock()
+	}
+	return h.staticBandwidthMeter.callCheckCaps(renter, limit, time.Now())
+}
+
+// BandwidthPeriod returns the host's RPC traffic within the current accounting
+// period.
+func (h *Host) BandwidthPeriod() modules.HostBandwidthPeriod {
+	return h.staticBandwidthMeter.callPeriod(time.Now())
+}
@@ -0,0 +1,93 @@
+package host
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/modules"
+)
+
+// TestBandwidthMeter is a unit test for the accounting and the caps of the
+// host's bandwidth meter.
+func TestBandwidthMeter(t *testing.T) {
+	t.Parallel()
+
+	bm := newBandwidthMeter(nil)
+	bm.callSetSettings(modules.HostBandwidthCaps{
+		Period:      time.Hour,
+		MaxUpload:   1000,
+		MaxDownload: 2000,
+	})
+	limit := modules.HostRenterLimit{MaxPeriodUpload: 100}
+	start := time.Now()
+
+	// Record some traffic of the host and a renter.
+	bm.callRecord("", 500, 1000, start)
+	bm.callRecord("renter", 50, 0, start)
+	period := bm.callPeriod(start)
+	if !period.Start.Equal(start) || !period.End.Equal(start.Add(time.Hour)) {
+		t.Fatal("wrong period", period.Start, period.End)
+	}
+	if period.Upload != 500 || period.Download != 1000 {
+		t.Fatal("wrong host usage", period.HostBandwidthUsage)
+	}
+	if usage := period.Renters["renter"]; usage.Upload != 50 || usage.Download != 0 {
+		t.Fatal("wrong renter usage", usage)
+	}
+	if err := bm.callCheckCaps("renter", limit, start); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reach the renter's cap. Other renters are unaffected.
+	bm.callRecord("renter", 50, 0, start)
+	if err := bm.callCheckCaps("renter", limit, start); !errors.Contains(err, ErrRenterBandwidthCapReached) {
+		t.Fatal("expected ErrRenterBandwidthCapReached but got", err)
+	}
+	if err := bm.callCheckCaps("other", limit, start); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reach the host's cap.
+	bm.callRecord("", 0, 1000, start)
+	if err := bm.callCheckCaps("", modules.HostRenterLimit{}, start); !errors.Contains(err, ErrBandwidthCapReached) {
+		t.Fatal("expected ErrBandwidthCapReached but got", err)
+	}
+
+	// The next period starts at a multiple of the period length and resets
+	// the usage.
+	now := start.Add(5*time.Hour + time.Minute)
+	if err := bm.callCheckCaps("renter", limit, now); err != nil {
+		t.Fatal(err)
+	}
+	period = bm.callPeriod(now)
+	if !period.Start.Equal(start.Add(5 * time.Hour)) {
+		t.Fatal("wrong period start", period.Start)
+	}
+	if period.Upload != 0 || period.Download != 0 || len(period.Renters) != 0 {
+		t.Fatal("usage should have been reset", period)
+	}
+
+	// Traffic of metered connections is recorded.
+	c1, c2 := net.Pipe()
+	defer func() {
+		if err := errors.Compose(c1.Close(), c2.Close()); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	conn := bm.newConn(c1, "renter")
+	go func() {
+		_, _ = c2.Write(make([]byte, 10))
+		_, _ = c2.Read(make([]byte, 20))
+	}()
+	if _, err := conn.Read(make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write(make([]byte, 20)); err != nil {
+		t.Fatal(err)
+	}
+	if usage := bm.callPeriod(time.Now()).Renters["renter"]; usage.Upload != 20 || usage.Download != 10 {
+		t.Fatal("wrong usage of metered connection", usage)
+	}
+}
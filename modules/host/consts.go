@@ -12,6 +12,22 @@ const (
 	// AlertMSGHostInsufficientCollateral indicates that a host has insufficient
 	// collateral budget remaining
 	AlertMSGHostInsufficientCollateral = "host has insufficient collateral budget"
+
+	// AlertMSGHostMissedStorageProof indicates that the host missed the proof
+	// window of a contract and lost its collateral
+	AlertMSGHostMissedStorageProof = "host missed a storage proof"
+
+	// AlertMSGHostLowBalance indicates that the host's wallet balance is too
+	// low to cover the collateral of a new contract
+	AlertMSGHostLowBalance = "host wallet balance is too low for collateral"
+
+	// AlertMSGHostStorageFolderWriteFailures indicates that writes to one or
+	// more storage folders failed
+	AlertMSGHostStorageFolderWriteFailures = "host failed to write to storage folders"
+
+	// AlertMSGHostClockSkew indicates that the host's clock is behind the
+	// clocks of its peers
+	AlertMSGHostClockSkew = "host clock is behind the network"
 )
 
 const (
@@ -145,6 +161,34 @@ var (
 		Testing:  time.Second * 10,
 	}).(time.Duration)
 
+	// alertCheckInterval defines how frequently the host checks the
+	// conditions of its alerts which aren't triggered by events.
+	alertCheckInterval = build.Select(build.Var{
+		Standard: time.Minute * 10,
+		Testnet:  time.Minute * 10,
+		Dev:      time.Minute * 1,
+		Testing:  time.Second * 3,
+	}).(time.Duration)
+
+	// maxClockSkew is the number of seconds the timestamp of the latest block
+	// can be ahead of the host's clock before the host alerts the user. Blocks
+	// which are more than types.FutureThreshold ahead are rejected.
+	maxClockSkew = build.Select(build.Var{
+		Standard: types.Timestamp(15 * 60),
+		Testnet:  types.Timestamp(15 * 60),
+		Dev:      types.Timestamp(15 * 60),
+		Testing:  types.Timestamp(60),
+	}).(types.Timestamp)
+
+	// dynamicPricingInterval defines how frequently the host's pricing engine
+	// adjusts the host's prices.
+	dynamicPricingInterval = build.Select(build.Var{
+		Standard: time.Minute * 10,
+		Testnet:  time.Minute * 10,
+		Dev:      time.Minute * 1,
+		Testing:  time.Second * 3,
+	}).(time.Duration)
+
 	// workingStatusThreshold defines how many settings calls must occur over the
 	// workingStatusFrequency for the host to be considered working.
 	workingStatusThreshold = build.Select(build.Var{
@@ -12,6 +12,14 @@ const (
 	// AlertMSGHostDiskTrouble indicates that one or multiple of a host's disks
 	// are encountering problems
 	AlertMSGHostDiskTrouble = "disk problem detected"
+
+	// AlertMSGHostCorruptSectors indicates that the integrity scrubber found
+	// sectors whose data no longer matches their Merkle root
+	AlertMSGHostCorruptSectors = "corrupt sectors detected"
+
+	// AlertMSGHostDiskDegraded indicates that the disk health probe raised
+	// warnings for one or more storage folders
+	AlertMSGHostDiskDegraded = "degraded disk detected"
 )
 
 const (
@@ -65,6 +73,11 @@ const (
 	// sector counters on disk in AddSectorBatch and RemoveSectorBatch.
 	maxSectorBatchThreads = 100
 
+	// rebalanceThreshold is the difference in utilization between the most and
+	// the least utilized storage folder above which the rebalancer starts
+	// migrating sectors between them.
+	rebalanceThreshold = 0.1
+
 	// sectorMetadataDiskSize defines the number of bytes it takes to store the
 	// metadata of a single sector on disk.
 	sectorMetadataDiskSize = 14
@@ -148,4 +161,64 @@ var (
 		Testnet:  time.Second * 60 * 5,
 		Testing:  time.Second * 8,
 	}).(time.Duration)
+
+	// rebalanceInterval specifies the amount of time between two passes of the
+	// storage folder rebalancer.
+	rebalanceInterval = build.Select(build.Var{
+		Dev:      time.Minute * 5,
+		Standard: time.Hour,
+		Testnet:  time.Hour,
+		Testing:  time.Minute * 10,
+	}).(time.Duration)
+
+	// diskHealthInterval specifies the amount of time between two probes of
+	// the storage folders' disk health.
+	diskHealthInterval = build.Select(build.Var{
+		Dev:      time.Minute * 5,
+		Standard: time.Hour,
+		Testnet:  time.Hour,
+		Testing:  time.Minute * 10,
+	}).(time.Duration)
+
+	// diskHealthSamples is the number of free space samples that are used to
+	// compute the free space trend of a storage folder's filesystem.
+	diskHealthSamples = 24
+
+	// diskFullWarningPeriod is the amount of time within which a filesystem
+	// needs to be predicted to run out of free space for the disk health probe
+	// to raise a warning.
+	diskFullWarningPeriod = time.Hour * 24 * 7
+
+	// smartctlTimeout is the amount of time that querying the SMART health of
+	// a disk may take.
+	smartctlTimeout = time.Second * 30
+
+	// scrubInterval specifies the amount of time between two passes of the
+	// integrity scrubber over all sectors.
+	scrubInterval = build.Select(build.Var{
+		Dev:      time.Hour,
+		Standard: time.Hour * 24 * 7,
+		Testnet:  time.Hour * 24 * 7,
+		Testing:  time.Minute * 10,
+	}).(time.Duration)
+
+	// scrubSectorDelay specifies the amount of time that the integrity
+	// scrubber waits after verifying a sector. It throttles the scrubber so
+	// that it doesn't compete with renters for disk bandwidth.
+	scrubSectorDelay = build.Select(build.Var{
+		Dev:      time.Millisecond * 10,
+		Standard: time.Millisecond * 100,
+		Testnet:  time.Millisecond * 100,
+		Testing:  time.Millisecond,
+	}).(time.Duration)
+
+	// rebalanceMoveDelay specifies the amount of time that the rebalancer
+	// waits after migrating a sector. It throttles the rebalancer so that it
+	// doesn't compete with renters for disk bandwidth.
+	rebalanceMoveDelay = build.Select(build.Var{
+		Dev:      time.Millisecond * 10,
+		Standard: time.Millisecond * 50,
+		Testnet:  time.Millisecond * 50,
+		Testing:  time.Millisecond,
+	}).(time.Duration)
 )
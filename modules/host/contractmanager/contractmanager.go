@@ -114,10 +114,35 @@ type ContractManager struct {
 	sectorLocations map[sectorID]sectorLocation
 	storageFolders  map[uint16]*storageFolder
 
+	// corruptSectors contains the sectors which were quarantined by the
+	// integrity scrubber because their data no longer matches their Merkle
+	// root. It is protected by sectorMu.
+	corruptSectors map[sectorID]struct{}
+
 	// sectors are removed from the store in a rate-limited queue to work around
 	// lock contention on extra large contracts.
 	sectorRemoval *sectorRemovalMap
 
+	// rebalanceChan is used to wake up the storage folder rebalancer before
+	// its next scheduled pass, e.g. when a storage folder is marked for
+	// removal.
+	rebalanceChan chan struct{}
+
+	// rebalanceMu ensures that only one rebalancing pass runs at a time.
+	rebalanceMu sync.Mutex
+
+	// scrubStatus tracks the progress of the integrity scrubber and is
+	// protected by scrubMu.
+	scrubStatus modules.StorageScrubStatus
+	scrubMu     sync.Mutex
+
+	// diskHealth contains the results of the disk health probes of the
+	// storage folders and querySMART indicates whether the probes query the
+	// SMART health of the disks. Both are protected by diskHealthMu.
+	diskHealth   map[uint16]*folderHealth
+	querySMART   bool
+	diskHealthMu sync.Mutex
+
 	// Utilities.
 	dependencies  modules.Dependencies
 	staticAlerter *modules.GenericAlerter
@@ -138,9 +163,13 @@ func newContractManager(dependencies modules.Dependencies, persistDir string) (_
 	cm := &ContractManager{
 		storageFolders:  make(map[uint16]*storageFolder),
 		sectorLocations: make(map[sectorID]sectorLocation),
+		corruptSectors:  make(map[sectorID]struct{}),
+		diskHealth:      make(map[uint16]*folderHealth),
 
 		lockedSectors: make(map[sectorID]*sectorLock),
 
+		rebalanceChan: make(chan struct{}, 1),
+
 		dependencies: dependencies,
 		persistDir:   persistDir,
 
@@ -254,6 +283,19 @@ func newContractManager(dependencies modules.Dependencies, persistDir string) (_
 	// and adds them if they are discovered.
 	go cm.threadedFolderRecheck()
 
+	// Spin up the thread that migrates sectors between storage folders to
+	// even out their utilization and to drain storage folders that were marked
+	// for removal.
+	go cm.threadedRebalanceStorageFolders()
+
+	// Spin up the thread that periodically verifies the integrity of all
+	// sectors.
+	go cm.threadedScrubSectors()
+
+	// Spin up the thread that periodically probes the health of the storage
+	// folders' disks.
+	go cm.threadedProbeDiskHealth()
+
 	// the removal map is loaded last so that the WAL and metadata is loaded.
 	cm.sectorRemoval, err = newSectorRemovalMap(filepath.Join(persistDir, sectorRemovalQueueFile), cm)
 	if err != nil {
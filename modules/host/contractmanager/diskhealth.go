@@ -0,0 +1,292 @@
+package contractmanager
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+)
+
+const (
+	// The SMART statuses reported by the disk health probe.
+	smartStatusPassed  = "passed"
+	smartStatusFailed  = "failed"
+	smartStatusUnknown = "unknown"
+)
+
+var (
+	// errDiskHealthUnsupported is returned if a disk health probe isn't
+	// supported on the current platform.
+	errDiskHealthUnsupported = errors.New("disk health probe is not supported on this platform")
+)
+
+type (
+	// freeSpaceSample is a sample of the free space of a storage folder's
+	// filesystem.
+	freeSpaceSample struct {
+		timestamp time.Time
+		free      uint64
+	}
+
+	// folderHealth contains the state of the disk health probes of a storage
+	// folder.
+	folderHealth struct {
+		path             string
+		samples          []freeSpaceSample
+		lastFailedReads  uint64
+		lastFailedWrites uint64
+		health           modules.StorageFolderHealth
+	}
+
+	// folderProbe contains the information about a storage folder that is
+	// needed to probe its health.
+	folderProbe struct {
+		index             uint16
+		path              string
+		capacityRemaining uint64
+		failedReads       uint64
+		failedWrites      uint64
+		unavailable       bool
+	}
+)
+
+// counterIncrease returns the increase of a failure counter since the last
+// probe. The counters are reset by ResetStorageFolderHealth.
+func counterIncrease(current, last uint64) uint64 {
+	if current < last {
+		return current
+	}
+	return current - last
+}
+
+// freeSpaceTrend returns the change of the free space in bytes per day between
+// the oldest and the newest sample.
+func freeSpaceTrend(samples []freeSpaceSample) int64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	elapsed := last.timestamp.Sub(first.timestamp)
+	if elapsed <= 0 {
+		return 0
+	}
+	change := float64(last.free) - float64(first.free)
+	return int64(change / elapsed.Hours() * 24)
+}
+
+// diskHealthWarnings returns the warnings for a probed storage folder.
+func diskHealthWarnings(health modules.StorageFolderHealth, capacityRemaining uint64, unavailable bool) []string {
+	warnings := []string{}
+	if unavailable {
+		warnings = append(warnings, "storage folder is unavailable")
+	}
+	// The sector files are sparse, so the filesystem needs to be able to hold
+	// the remaining capacity of the storage folder.
+	if health.FilesystemSize > 0 && health.FilesystemFree < capacityRemaining {
+		warnings = append(warnings, fmt.Sprintf("filesystem has %v free but the storage folder can still grow by %v", modules.FilesizeUnits(health.FilesystemFree), modules.FilesizeUnits(capacityRemaining)))
+	}
+	if health.FreeSpaceTrend < 0 {
+		daysLeft := float64(health.FilesystemFree) / float64(-health.FreeSpaceTrend)
+		if daysLeft*24 < diskFullWarningPeriod.Hours() {
+			warnings = append(warnings, fmt.Sprintf("filesystem is predicted to be full in %.1f days", daysLeft))
+		}
+	}
+	if health.RecentFailedReads > 0 {
+		warnings = append(warnings, fmt.Sprintf("%v failed reads since the previous probe", health.RecentFailedReads))
+	}
+	if health.RecentFailedWrites > 0 {
+		warnings = append(warnings, fmt.Sprintf("%v failed writes since the previous probe", health.RecentFailedWrites))
+	}
+	if health.SMARTStatus == smartStatusFailed {
+		warnings = append(warnings, "SMART self-assessment failed")
+	}
+	return warnings
+}
+
+// update updates the health of the storage folder with the results of a probe.
+// The filesystem's size and free space are only used if usageKnown is true.
+func (fh *folderHealth) update(p folderProbe, now time.Time, size, free uint64, usageKnown bool, smartStatus string) {
+	// Start over if the index now belongs to a different storage folder.
+	if fh.path != p.path {
+		*fh = folderHealth{path: p.path}
+	}
+
+	health := modules.StorageFolderHealth{
+		LastProbe:          now,
+		RecentFailedReads:  counterIncrease(p.failedReads, fh.lastFailedReads),
+		RecentFailedWrites: counterIncrease(p.failedWrites, fh.lastFailedWrites),
+		SMARTStatus:        smartStatus,
+	}
+	fh.lastFailedReads, fh.lastFailedWrites = p.failedReads, p.failedWrites
+	if usageKnown {
+		fh.samples = append(fh.samples, freeSpaceSample{timestamp: now, free: free})
+		if len(fh.samples) > diskHealthSamples {
+			fh.samples = fh.samples[len(fh.samples)-diskHealthSamples:]
+		}
+		health.FilesystemSize = size
+		health.FilesystemFree = free
+		health.FreeSpaceTrend = freeSpaceTrend(fh.samples)
+	}
+	health.Warnings = diskHealthWarnings(health, p.capacityRemaining, p.unavailable)
+	health.Degraded = len(health.Warnings) > 0
+	fh.health = health
+}
+
+// parseSMARTStatus extracts the overall health from the output of 'smartctl
+// -H'. ATA and NVMe disks report the result of the self-assessment while SCSI
+// disks report a health status.
+func parseSMARTStatus(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.Contains(line, "self-assessment test result:") && !strings.Contains(line, "SMART Health Status:") {
+			continue
+		}
+		switch strings.TrimSpace(line[strings.LastIndex(line, ":")+1:]) {
+		case "PASSED", "OK":
+			return smartStatusPassed
+		default:
+			return smartStatusFailed
+		}
+	}
+	return smartStatusUnknown
+}
+
+// querySMART queries the SMART health of the disk that contains the provided
+// path with smartctl.
+func querySMART(path string) (string, error) {
+	device, err := filesystemDevice(path)
+	if err != nil {
+		return smartStatusUnknown, errors.AddContext(err, "unable to find the device of the storage folder")
+	}
+	smartctl, err := exec.LookPath("smartctl")
+	if err != nil {
+		return smartStatusUnknown, errors.AddContext(err, "unable to find smartctl")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), smartctlTimeout)
+	defer cancel()
+
+	// smartctl reports problems with the disk through its exit status, so
+	// the output is parsed even if the command returned an error.
+	out, err := exec.CommandContext(ctx, smartctl, "-H", device).Output()
+	status := parseSMARTStatus(string(out))
+	if status == smartStatusUnknown && err != nil {
+		return status, errors.AddContext(err, "smartctl failed")
+	}
+	return status, nil
+}
+
+// managedFolderHealth returns the health of the storage folder with the
+// provided index.
+func (cm *ContractManager) managedFolderHealth(index uint16) modules.StorageFolderHealth {
+	cm.diskHealthMu.Lock()
+	defer cm.diskHealthMu.Unlock()
+	fh, exists := cm.diskHealth[index]
+	if !exists {
+		return modules.StorageFolderHealth{Warnings: []string{}}
+	}
+	health := fh.health
+	health.Warnings = append([]string{}, fh.health.Warnings...)
+	return health
+}
+
+// managedProbeDiskHealth probes the health of the disks of all storage folders
+// and registers an alert if any of them are degraded.
+func (cm *ContractManager) managedProbeDiskHealth() {
+	cm.sectorMu.Lock()
+	probes := make([]folderProbe, 0, len(cm.storageFolders))
+	for _, sf := range cm.storageFolders {
+		probes = append(probes, folderProbe{
+			index:             sf.index,
+			path:              sf.path,
+			capacityRemaining: ((64 * uint64(len(sf.usage))) - sf.sectors) * modules.SectorSize,
+			failedReads:       atomic.LoadUint64(&sf.atomicFailedReads),
+			failedWrites:      atomic.LoadUint64(&sf.atomicFailedWrites),
+			unavailable:       atomic.LoadUint64(&sf.atomicUnavailable) == 1,
+		})
+	}
+	cm.sectorMu.Unlock()
+	cm.diskHealthMu.Lock()
+	smartEnabled := cm.querySMART
+	cm.diskHealthMu.Unlock()
+
+	now := time.Now()
+	probed := make(map[uint16]struct{})
+	var degraded []string
+	for _, p := range probes {
+		var smartStatus string
+		if smartEnabled && !p.unavailable {
+			var err error
+			smartStatus, err = querySMART(p.path)
+			if err != nil {
+				cm.log.Printf("WARN: unable to query the SMART health of storage folder %v: %v\n", p.path, err)
+			}
+		}
+		size, free, err := filesystemUsage(p.path)
+		if err != nil && !errors.Contains(err, errDiskHealthUnsupported) && !p.unavailable {
+			cm.log.Printf("WARN: unable to get the filesystem usage of storage folder %v: %v\n", p.path, err)
+		}
+
+		cm.diskHealthMu.Lock()
+		fh, exists := cm.diskHealth[p.index]
+		if !exists {
+			fh = &folderHealth{path: p.path}
+			cm.diskHealth[p.index] = fh
+		}
+		fh.update(p, now, size, free, err == nil, smartStatus)
+		if fh.health.Degraded {
+			degraded = append(degraded, fmt.Sprintf("%v: %v", p.path, strings.Join(fh.health.Warnings, ", ")))
+		}
+		cm.diskHealthMu.Unlock()
+		probed[p.index] = struct{}{}
+	}
+
+	// Forget the storage folders which were removed.
+	cm.diskHealthMu.Lock()
+	for index := range cm.diskHealth {
+		if _, exists := probed[index]; !exists {
+			delete(cm.diskHealth, index)
+		}
+	}
+	cm.diskHealthMu.Unlock()
+
+	if len(degraded) > 0 {
+		cm.staticAlerter.RegisterAlert(modules.AlertIDHostDiskDegraded, AlertMSGHostDiskDegraded, strings.Join(degraded, "; "), modules.SeverityWarning)
+	} else {
+		cm.staticAlerter.UnregisterAlert(modules.AlertIDHostDiskDegraded)
+	}
+}
+
+// threadedProbeDiskHealth periodically probes the health of the storage
+// folders' disks.
+func (cm *ContractManager) threadedProbeDiskHealth() {
+	// Don't spawn the loop if 'noDiskHealth' disruption is set.
+	if cm.dependencies.Disrupt("noDiskHealth") {
+		return
+	}
+	if err := cm.tg.Add(); err != nil {
+		return
+	}
+	defer cm.tg.Done()
+
+	for {
+		cm.managedProbeDiskHealth()
+		select {
+		case <-cm.tg.StopChan():
+			return
+		case <-time.After(diskHealthInterval):
+		}
+	}
+}
+
+// SetSMARTQuerying enables or disables querying the SMART health of the
+// storage folders' disks during the periodic health probes.
+func (cm *ContractManager) SetSMARTQuerying(enabled bool) {
+	cm.diskHealthMu.Lock()
+	defer cm.diskHealthMu.Unlock()
+	cm.querySMART = enabled
+}
@@ -0,0 +1,28 @@
+package contractmanager
+
+import (
+	"strings"
+	"syscall"
+
+	"gitlab.com/NebulousLabs/errors"
+)
+
+// filesystemDevice returns the device of the filesystem that contains the
+// provided path.
+func filesystemDevice(path string) (string, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return "", err
+	}
+	var device strings.Builder
+	for _, c := range stat.Mntfromname {
+		if c == 0 {
+			break
+		}
+		device.WriteByte(byte(c))
+	}
+	if !strings.HasPrefix(device.String(), "/dev/") {
+		return "", errors.New("storage folder is not on a block device")
+	}
+	return device.String(), nil
+}
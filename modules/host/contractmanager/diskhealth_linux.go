@@ -0,0 +1,38 @@
+package contractmanager
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/NebulousLabs/errors"
+)
+
+// filesystemDevice returns the device of the filesystem that contains the
+// provided path by finding the longest mount point that contains the path.
+func filesystemDevice(path string) (string, error) {
+	mounts, err := ioutil.ReadFile("/proc/self/mounts")
+	if err != nil {
+		return "", err
+	}
+	path = filepath.Clean(path)
+	var device, mountPoint string
+	for _, line := range strings.Split(string(mounts), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// Spaces in mount points are escaped.
+		mp := strings.Replace(fields[1], `\040`, " ", -1)
+		if !strings.HasPrefix(path, mp) || (len(path) > len(mp) && mp != "/" && path[len(mp)] != '/') {
+			continue
+		}
+		if len(mp) >= len(mountPoint) {
+			device, mountPoint = fields[0], mp
+		}
+	}
+	if !strings.HasPrefix(device, "/dev/") {
+		return "", errors.New("storage folder is not on a block device")
+	}
+	return device, nil
+}
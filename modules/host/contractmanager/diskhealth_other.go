@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package contractmanager
+
+// filesystemUsage returns the size and the free space of the filesystem that
+// contains the provided path. It isn't supported on the current platform.
+func filesystemUsage(path string) (size, free uint64, err error) {
+	return 0, 0, errDiskHealthUnsupported
+}
+
+// filesystemDevice returns the device of the filesystem that contains the
+// provided path. It isn't supported on the current platform.
+func filesystemDevice(path string) (string, error) {
+	return "", errDiskHealthUnsupported
+}
@@ -0,0 +1,148 @@
+package contractmanager
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestFolderHealthUpdate is a unit test for the evaluation of the disk health
+// probes.
+func TestFolderHealthUpdate(t *testing.T) {
+	t.Parallel()
+
+	fh := &folderHealth{path: "/a"}
+	p := folderProbe{index: 1, path: "/a", capacityRemaining: 1 << 20}
+	start := time.Now()
+
+	// A healthy filesystem.
+	fh.update(p, start, 1<<40, 1<<30, true, "")
+	if fh.health.Degraded || len(fh.health.Warnings) != 0 || fh.health.FreeSpaceTrend != 0 {
+		t.Fatal("healthy folder was degraded", fh.health)
+	}
+
+	// The filesystem loses 1/16 of its free space per day, which is reported
+	// as a trend but doesn't fill it up within the warning period.
+	fh.update(p, start.Add(24*time.Hour), 1<<40, 15<<26, true, "")
+	if fh.health.FreeSpaceTrend != -(1 << 26) {
+		t.Fatal("wrong trend", fh.health.FreeSpaceTrend)
+	}
+	if fh.health.Degraded {
+		t.Fatal("folder shouldn't be degraded", fh.health.Warnings)
+	}
+
+	// Losing most of the remaining free space within another day fills up
+	// the filesystem within the warning period.
+	fh.update(p, start.Add(48*time.Hour), 1<<40, 1<<28, true, "")
+	if !fh.health.Degraded || len(fh.health.Warnings) != 1 {
+		t.Fatal("filling filesystem wasn't reported", fh.health.Warnings)
+	}
+
+	// Failed writes and a failed SMART self-assessment are reported.
+	p.failedWrites = 2
+	fh.update(p, start.Add(49*time.Hour), 1<<40, 1<<28, true, smartStatusFailed)
+	if fh.health.RecentFailedWrites != 2 || len(fh.health.Warnings) != 3 {
+		t.Fatal("failures weren't reported", fh.health)
+	}
+	// The failures are only reported once.
+	fh.update(p, start.Add(50*time.Hour), 1<<40, 1<<28, true, smartStatusPassed)
+	if fh.health.RecentFailedWrites != 0 || len(fh.health.Warnings) != 1 {
+		t.Fatal("old failures were reported", fh.health)
+	}
+
+	// A filesystem with less free space than the storage folder's remaining
+	// capacity is reported.
+	p.capacityRemaining = 1 << 29
+	fh.update(p, start.Add(51*time.Hour), 1<<40, 1<<28, true, "")
+	if len(fh.health.Warnings) != 2 {
+		t.Fatal("full filesystem wasn't reported", fh.health.Warnings)
+	}
+
+	// A different storage folder with the same index starts over.
+	p = folderProbe{index: 1, path: "/b"}
+	fh.update(p, start.Add(52*time.Hour), 1<<40, 1<<30, true, "")
+	if fh.health.Degraded || len(fh.samples) != 1 {
+		t.Fatal("health of the previous folder was kept", fh.health)
+	}
+}
+
+// TestParseSMARTStatus is a unit test for parseSMARTStatus.
+func TestParseSMARTStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		out    string
+		status string
+	}{
+		{"=== START OF READ SMART DATA SECTION ===\nSMART overall-health self-assessment test result: PASSED\n", smartStatusPassed},
+		{"=== START OF READ SMART DATA SECTION ===\nSMART overall-health self-assessment test result: FAILED!\n", smartStatusFailed},
+		{"=== START OF READ SMART DATA SECTION ===\nSMART Health Status: OK\n", smartStatusPassed},
+		{"Smartctl open device: /dev/sda failed: Permission denied\n", smartStatusUnknown},
+	}
+	for _, test := range tests {
+		if status := parseSMARTStatus(test.out); status != test.status {
+			t.Errorf("expected %v but got %v for %q", test.status, status, test.out)
+		}
+	}
+}
+
+// TestProbeDiskHealth checks that the disk health probe reports the
+// filesystem usage and the failures of the storage folders.
+func TestProbeDiskHealth(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
+		t.Skip("filesystem usage is not supported on", runtime.GOOS)
+	}
+	t.Parallel()
+	cmt, err := newContractManagerTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cmt.panicClose()
+
+	storageFolderDir := filepath.Join(cmt.persistDir, "storageFolderOne")
+	err = os.MkdirAll(storageFolderDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmt.cm.AddStorageFolder(storageFolderDir, modules.SectorSize*storageFolderGranularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmt.cm.managedProbeDiskHealth()
+	sfs := cmt.cm.StorageFolders()
+	if len(sfs) != 1 {
+		t.Fatal("expected one storage folder")
+	}
+	health := sfs[0].Health
+	if health.LastProbe.IsZero() || health.FilesystemSize == 0 || health.FilesystemFree > health.FilesystemSize {
+		t.Fatal("wrong filesystem usage", health)
+	}
+
+	// Failed writes degrade the storage folder.
+	cmt.cm.sectorMu.Lock()
+	sf := cmt.cm.storageFolders[sfs[0].Index]
+	cmt.cm.sectorMu.Unlock()
+	atomic.AddUint64(&sf.atomicFailedWrites, 1)
+	cmt.cm.managedProbeDiskHealth()
+	health = cmt.cm.StorageFolders()[0].Health
+	if !health.Degraded || health.RecentFailedWrites != 1 {
+		t.Fatal("failed writes weren't reported", health)
+	}
+	_, _, warn, _ := cmt.cm.Alerts()
+	var alerted bool
+	for _, alert := range warn {
+		alerted = alerted || alert.Msg == AlertMSGHostDiskDegraded
+	}
+	if !alerted {
+		t.Fatal("degraded disk alert wasn't registered")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package contractmanager
+
+import "syscall"
+
+// filesystemUsage returns the size and the free space of the filesystem that
+// contains the provided path. The free space only includes the space that is
+// available to unprivileged users.
+func filesystemUsage(path string) (size, free uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return uint64(stat.Blocks) * uint64(stat.Bsize), uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
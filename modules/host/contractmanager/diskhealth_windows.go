@@ -0,0 +1,31 @@
+package contractmanager
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// procGetDiskFreeSpaceEx is the GetDiskFreeSpaceExW function of kernel32.dll.
+var procGetDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// filesystemUsage returns the size and the free space of the filesystem that
+// contains the provided path. The free space only includes the space that is
+// available to the user running siad.
+func filesystemUsage(path string) (size, free uint64, err error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var totalFree uint64
+	r, _, err := procGetDiskFreeSpaceEx.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&free)), uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&totalFree)))
+	if r == 0 {
+		return 0, 0, err
+	}
+	return size, free, nil
+}
+
+// filesystemDevice returns the device of the filesystem that contains the
+// provided path. Querying SMART isn't supported on Windows.
+func filesystemDevice(path string) (string, error) {
+	return "", errDiskHealthUnsupported
+}
@@ -1,6 +1,7 @@
 package contractmanager
 
 import (
+	"bytes"
 	"encoding/binary"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 
 	"go.sia.tech/siad/build"
 	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/persist"
 )
 
@@ -19,9 +21,10 @@ type (
 	// savedStorageFolder contains fields that are saved automatically to disk
 	// for each storage folder.
 	savedStorageFolder struct {
-		Index uint16
-		Path  string
-		Usage []uint64
+		Index    uint16
+		Path     string
+		Usage    []uint64
+		Draining bool `json:",omitempty"`
 	}
 
 	// savedSettings contains fields that are saved atomically to disk inside
@@ -29,19 +32,25 @@ type (
 	savedSettings struct {
 		SectorSalt     crypto.Hash
 		StorageFolders []savedStorageFolder
+		CorruptSectors []sectorID `json:",omitempty"`
 	}
 )
 
 // equals tests if all settings are equal between two savedSettings.
 func (s *savedSettings) equals(sb savedSettings) bool {
-	if s.SectorSalt != sb.SectorSalt || len(s.StorageFolders) != len(sb.StorageFolders) {
+	if s.SectorSalt != sb.SectorSalt || len(s.StorageFolders) != len(sb.StorageFolders) || len(s.CorruptSectors) != len(sb.CorruptSectors) {
 		return false
 	}
+	for i := range s.CorruptSectors {
+		if s.CorruptSectors[i] != sb.CorruptSectors[i] {
+			return false
+		}
+	}
 
 	for i, sf := range s.StorageFolders {
 		sfb := sb.StorageFolders[i]
 
-		if sf.Index != sfb.Index || sf.Path != sfb.Path || len(sf.Usage) != len(sfb.Usage) {
+		if sf.Index != sfb.Index || sf.Path != sfb.Path || sf.Draining != sfb.Draining || len(sf.Usage) != len(sfb.Usage) {
 			return false
 		}
 
@@ -58,9 +67,10 @@ func (s *savedSettings) equals(sb savedSettings) bool {
 // savedStorageFolder returns the persistent version of the storage folder.
 func (sf *storageFolder) savedStorageFolder() savedStorageFolder {
 	ssf := savedStorageFolder{
-		Index: sf.index,
-		Path:  sf.path,
-		Usage: make([]uint64, len(sf.usage)),
+		Index:    sf.index,
+		Path:     sf.path,
+		Usage:    make([]uint64, len(sf.usage)),
+		Draining: sf.draining,
 	}
 	copy(ssf.Usage, sf.usage)
 	return ssf
@@ -97,11 +107,20 @@ func (cm *ContractManager) loadSettings() error {
 
 	// Copy the saved settings into the contract manager.
 	cm.sectorSalt = ss.SectorSalt
+	cm.sectorMu.Lock()
+	for _, id := range ss.CorruptSectors {
+		cm.corruptSectors[id] = struct{}{}
+	}
+	cm.sectorMu.Unlock()
+	if len(ss.CorruptSectors) > 0 {
+		cm.staticAlerter.RegisterAlert(modules.AlertIDHostCorruptSectors, AlertMSGHostCorruptSectors, "", modules.SeverityError)
+	}
 	for i := range ss.StorageFolders {
 		sf := new(storageFolder)
 		sf.index = ss.StorageFolders[i].Index
 		sf.path = ss.StorageFolders[i].Path
 		sf.usage = ss.StorageFolders[i].Usage
+		sf.draining = ss.StorageFolders[i].Draining
 		sf.metadataFile, err = cm.dependencies.OpenFile(filepath.Join(ss.StorageFolders[i].Path, metadataFile), os.O_RDWR, 0700)
 		if err != nil {
 			// Mark the folder as unavailable and log an error.
@@ -189,6 +208,9 @@ func (cm *ContractManager) savedSettings() savedSettings {
 			sf.setUsage(sectorIndex)
 		}
 	}
+	for id := range cm.corruptSectors {
+		ss.CorruptSectors = append(ss.CorruptSectors, id)
+	}
 	cm.sectorMu.Unlock()
 
 	// canonicalize storage folder ordering; otherwise savedSettings.equals
@@ -196,5 +218,8 @@ func (cm *ContractManager) savedSettings() savedSettings {
 	sort.Slice(ss.StorageFolders, func(i, j int) bool {
 		return ss.StorageFolders[i].Index < ss.StorageFolders[j].Index
 	})
+	sort.Slice(ss.CorruptSectors, func(i, j int) bool {
+		return bytes.Compare(ss.CorruptSectors[i][:], ss.CorruptSectors[j][:]) < 0
+	})
 	return ss
 }
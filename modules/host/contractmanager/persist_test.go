@@ -158,6 +158,22 @@ func TestSavedSettingsEquals(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "diff draining",
+			a: savedSettings{
+				SectorSalt: crypto.Hash{1},
+				StorageFolders: []savedStorageFolder{
+					{Index: 1, Path: "/tmp/storage/01", Usage: []uint64{5, 1, 2, 3, 4}, Draining: true},
+				},
+			},
+			b: savedSettings{
+				SectorSalt: crypto.Hash{1},
+				StorageFolders: []savedStorageFolder{
+					{Index: 1, Path: "/tmp/storage/01", Usage: []uint64{5, 1, 2, 3, 4}},
+				},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,189 @@
+package contractmanager
+
+import (
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+)
+
+// managedScrubSector re-reads the sector with the given id at the provided
+// location and verifies that its data still matches its Merkle root. Corrupt
+// sectors are quarantined. The returned bool indicates whether the sector was
+// verified, which is not the case if it was moved, deleted or quarantined in
+// the meantime.
+func (cm *ContractManager) managedScrubSector(sf *storageFolder, id sectorID, index uint32) (bool, error) {
+	cm.wal.managedLockSector(id)
+	defer cm.wal.managedUnlockSector(id)
+
+	// Check that the sector is still at the same location.
+	cm.sectorMu.Lock()
+	sl, exists := cm.sectorLocations[id]
+	_, corrupt := cm.corruptSectors[id]
+	cm.sectorMu.Unlock()
+	if !exists || corrupt || sl.storageFolder != sf.index || sl.index != index {
+		return false, nil
+	}
+
+	data, err := readSector(sf.sectorFile, index)
+	if err != nil {
+		atomic.AddUint64(&sf.atomicFailedReads, 1)
+		return false, errors.AddContext(err, "unable to read sector")
+	}
+	atomic.AddUint64(&sf.atomicSuccessfulReads, 1)
+	if cm.managedSectorID(crypto.MerkleRoot(data)) == id {
+		return true, nil
+	}
+
+	cm.sectorMu.Lock()
+	cm.corruptSectors[id] = struct{}{}
+	cm.sectorMu.Unlock()
+	cm.log.Printf("WARN: quarantined corrupt sector at index %v of storage folder %v\n", index, sf.path)
+	cm.staticAlerter.RegisterAlert(modules.AlertIDHostCorruptSectors, AlertMSGHostCorruptSectors, "", modules.SeverityError)
+	return true, nil
+}
+
+// managedScrubStorageFolder verifies the sectors of a storage folder. The
+// scrubber stops early if the contract manager is shutting down.
+func (cm *ContractManager) managedScrubStorageFolder(sf *storageFolder) error {
+	// Read the sector lookup bytes into memory to figure out which sectors
+	// are stored in the folder.
+	cm.wal.mu.Lock()
+	cm.sectorMu.Lock()
+	usage := append([]uint64(nil), sf.usage...)
+	cm.sectorMu.Unlock()
+	cm.wal.mu.Unlock()
+	sectorLookupBytes, err := readFullMetadata(sf.metadataFile, len(usage)*storageFolderGranularity)
+	if err != nil {
+		atomic.AddUint64(&sf.atomicFailedReads, 1)
+		return errors.AddContext(err, "unable to read sector metadata")
+	}
+	atomic.AddUint64(&sf.atomicSuccessfulReads, 1)
+
+	for i, u := range usage {
+		for j := uint32(0); j < storageFolderGranularity; j++ {
+			if u&(1<<j) == 0 {
+				continue
+			}
+			if atomic.LoadUint64(&sf.atomicUnavailable) == 1 {
+				return nil
+			}
+			sectorIndex := uint32(i)*storageFolderGranularity + j
+			readHead := sectorIndex * sectorMetadataDiskSize
+			var id sectorID
+			copy(id[:], sectorLookupBytes[readHead:readHead+12])
+
+			scrubbed, err := cm.managedScrubSector(sf, id, sectorIndex)
+			if err != nil {
+				cm.log.Printf("WARN: unable to scrub sector at index %v of storage folder %v: %v\n", sectorIndex, sf.path, err)
+			}
+			if !scrubbed {
+				continue
+			}
+			cm.scrubMu.Lock()
+			cm.scrubStatus.SectorsScrubbed++
+			cm.scrubMu.Unlock()
+
+			select {
+			case <-cm.tg.StopChan():
+				return nil
+			case <-time.After(scrubSectorDelay):
+			}
+		}
+	}
+	return nil
+}
+
+// managedScrubSectors performs a single pass of the integrity scrubber over
+// all sectors. Afterwards, quarantined sectors which no longer exist are
+// forgotten and the corrupt sectors alert is unregistered if none are left.
+func (cm *ContractManager) managedScrubSectors() {
+	cm.sectorMu.Lock()
+	var sfs []*storageFolder
+	for _, sf := range cm.storageFolders {
+		sfs = append(sfs, sf)
+	}
+	cm.sectorMu.Unlock()
+
+	cm.scrubMu.Lock()
+	cm.scrubStatus.PassStart = time.Now()
+	cm.scrubStatus.SectorsScrubbed = 0
+	cm.scrubMu.Unlock()
+	for _, sf := range sfs {
+		if atomic.LoadUint64(&sf.atomicUnavailable) == 1 {
+			continue
+		}
+		err := cm.managedScrubStorageFolder(sf)
+		if err != nil {
+			cm.log.Printf("WARN: unable to scrub storage folder %v: %v\n", sf.path, err)
+		}
+		select {
+		case <-cm.tg.StopChan():
+			return
+		default:
+		}
+	}
+	cm.scrubMu.Lock()
+	cm.scrubStatus.LastPassEnd = time.Now()
+	cm.scrubMu.Unlock()
+
+	cm.sectorMu.Lock()
+	for id := range cm.corruptSectors {
+		if _, exists := cm.sectorLocations[id]; !exists {
+			delete(cm.corruptSectors, id)
+		}
+	}
+	corrupt := len(cm.corruptSectors)
+	cm.sectorMu.Unlock()
+	if corrupt > 0 {
+		cm.log.Printf("Scrubber pass finished with %v quarantined sectors\n", corrupt)
+	} else {
+		cm.staticAlerter.UnregisterAlert(modules.AlertIDHostCorruptSectors)
+	}
+}
+
+// threadedScrubSectors periodically verifies the integrity of the sectors
+// stored by the contract manager.
+func (cm *ContractManager) threadedScrubSectors() {
+	// Don't spawn the loop if 'noScrub' disruption is set.
+	if cm.dependencies.Disrupt("noScrub") {
+		return
+	}
+	if err := cm.tg.Add(); err != nil {
+		return
+	}
+	defer cm.tg.Done()
+
+	for {
+		select {
+		case <-cm.tg.StopChan():
+			return
+		case <-time.After(scrubInterval):
+		}
+		cm.managedScrubSectors()
+	}
+}
+
+// SectorCorrupt indicates whether the sector with the given root was
+// quarantined because its data failed the integrity check.
+func (cm *ContractManager) SectorCorrupt(root crypto.Hash) bool {
+	id := cm.managedSectorID(root)
+	cm.sectorMu.Lock()
+	defer cm.sectorMu.Unlock()
+	_, corrupt := cm.corruptSectors[id]
+	return corrupt
+}
+
+// ScrubStatus returns the progress of the integrity scrubber.
+func (cm *ContractManager) ScrubStatus() modules.StorageScrubStatus {
+	cm.scrubMu.Lock()
+	status := cm.scrubStatus
+	cm.scrubMu.Unlock()
+	cm.sectorMu.Lock()
+	status.CorruptSectors = uint64(len(cm.corruptSectors))
+	cm.sectorMu.Unlock()
+	return status
+}
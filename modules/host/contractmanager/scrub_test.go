@@ -0,0 +1,119 @@
+package contractmanager
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+)
+
+// TestScrubSectors checks that the integrity scrubber quarantines corrupt
+// sectors, that quarantined sectors are no longer served and that the
+// quarantine survives a restart.
+func TestScrubSectors(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cmt, err := newContractManagerTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cmt.panicClose()
+
+	// Add a storage folder and some sectors.
+	storageFolderDir := filepath.Join(cmt.persistDir, "storageFolderOne")
+	err = os.MkdirAll(storageFolderDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmt.cm.AddStorageFolder(storageFolderDir, modules.SectorSize*storageFolderGranularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roots []crypto.Hash
+	sectors := make(map[crypto.Hash][]byte)
+	for i := 0; i < 3; i++ {
+		root, data := randSector()
+		err = cmt.cm.AddSector(root, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+		sectors[root] = data
+	}
+
+	// Corrupt the data of the first sector on disk.
+	cmt.cm.sectorMu.Lock()
+	sl := cmt.cm.sectorLocations[cmt.cm.managedSectorID(roots[0])]
+	cmt.cm.sectorMu.Unlock()
+	f, err := os.OpenFile(filepath.Join(storageFolderDir, sectorFile), os.O_RDWR, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteAt([]byte("corrupt"), int64(uint64(sl.index)*modules.SectorSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scrub the sectors. Only the corrupt sector should be quarantined.
+	cmt.cm.managedScrubSectors()
+	status := cmt.cm.ScrubStatus()
+	if status.SectorsScrubbed != 3 || status.CorruptSectors != 1 || status.LastPassEnd.Before(status.PassStart) {
+		t.Fatal("wrong scrub status", status)
+	}
+	if !cmt.cm.SectorCorrupt(roots[0]) {
+		t.Fatal("corrupt sector wasn't quarantined")
+	}
+	if _, err := cmt.cm.ReadSector(roots[0]); err != ErrSectorCorrupt {
+		t.Fatal("expected ErrSectorCorrupt but got", err)
+	}
+	for _, root := range roots[1:] {
+		if cmt.cm.SectorCorrupt(root) {
+			t.Fatal("intact sector was quarantined")
+		}
+		data, err := cmt.cm.ReadSector(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, sectors[root]) {
+			t.Fatal("sector has the wrong data")
+		}
+	}
+
+	// The quarantine should survive a restart. It is persisted with the
+	// settings, which are written during one sync and committed during the
+	// next.
+	for i := 0; i < 2; i++ {
+		cmt.cm.wal.mu.Lock()
+		syncChan := cmt.cm.wal.syncChan
+		cmt.cm.wal.mu.Unlock()
+		<-syncChan
+	}
+	err = cmt.cm.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmt.cm, err = New(filepath.Join(cmt.persistDir, modules.ContractManagerDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmt.cm.SectorCorrupt(roots[0]) {
+		t.Fatal("quarantine wasn't persisted")
+	}
+
+	// Removing the corrupt sector lifts the quarantine.
+	err = cmt.cm.RemoveSector(roots[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmt.cm.SectorCorrupt(roots[0]) || cmt.cm.ScrubStatus().CorruptSectors != 0 {
+		t.Fatal("removed sector is still quarantined")
+	}
+}
@@ -15,6 +15,10 @@ var (
 	// ErrSectorNotFound is returned when a lookup for a sector fails.
 	ErrSectorNotFound = errors.New("could not find the desired sector")
 
+	// ErrSectorCorrupt is returned when a sector is read which was
+	// quarantined by the integrity scrubber.
+	ErrSectorCorrupt = errors.New("sector failed the integrity check and was quarantined")
+
 	// errDiskTrouble is returned when the host is supposed to have enough
 	// storage to hold a new sector but failures that are likely related to the
 	// disk have prevented the host from successfully adding the sector.
@@ -134,10 +138,14 @@ func (cm *ContractManager) ReadPartialSector(root crypto.Hash, offset, length ui
 	cm.sectorMu.Lock()
 	sl, exists1 := cm.sectorLocations[id]
 	sf, exists2 := cm.storageFolders[sl.storageFolder]
+	_, corrupt := cm.corruptSectors[id]
 	cm.sectorMu.Unlock()
 	if !exists1 {
 		return nil, ErrSectorNotFound
 	}
+	if corrupt {
+		return nil, ErrSectorCorrupt
+	}
 	if !exists2 {
 		cm.log.Critical("Unable to load storage folder despite having sector metadata")
 		return nil, ErrSectorNotFound
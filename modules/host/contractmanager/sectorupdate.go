@@ -268,6 +268,7 @@ func (wal *writeAheadLog) managedDeleteSector(id sectorID) error {
 
 		// Delete the sector and mark the usage as available.
 		delete(wal.cm.sectorLocations, id)
+		delete(wal.cm.corruptSectors, id)
 		sf.availableSectors[id] = location.index
 
 		// Block until the change has been committed.
@@ -334,6 +335,7 @@ func (wal *writeAheadLog) managedRemoveSector(id sectorID) error {
 		if location.count == 0 {
 			// Delete the sector and mark it as available.
 			delete(wal.cm.sectorLocations, id)
+			delete(wal.cm.corruptSectors, id)
 			sf.availableSectors[id] = location.index
 		} else {
 			// Reduce the sector usage.
@@ -422,6 +424,7 @@ func (wal *writeAheadLog) managedRemoveSectors(sectors map[sectorID]uint64) erro
 		if location.count == 0 {
 			// Delete the sector and mark it as available.
 			delete(wal.cm.sectorLocations, id)
+			delete(wal.cm.corruptSectors, id)
 			sf.availableSectors[id] = location.index
 		} else {
 			// Reduce the sector usage.
@@ -110,6 +110,12 @@ type storageFolder struct {
 	availableSectors map[sectorID]uint32
 	sectors          uint64
 
+	// draining indicates that the storage folder was marked for removal. No
+	// new sectors are placed into a draining storage folder and the
+	// rebalancer migrates its sectors to other storage folders before
+	// removing it. draining is protected by the contract manager's sectorMu.
+	draining bool
+
 	// An open file handle is kept so that writes can easily be made to the
 	// storage folder without needing to grab a new file handle. This also
 	// makes it easy to do delayed-syncing.
@@ -286,8 +292,8 @@ func (cm *ContractManager) availableStorageFolders() []*storageFolder {
 	cm.sectorMu.Lock()
 	defer cm.sectorMu.Unlock()
 	for _, sf := range cm.storageFolders {
-		// Skip unavailable storage folders.
-		if atomic.LoadUint64(&sf.atomicUnavailable) == 1 {
+		// Skip unavailable and draining storage folders.
+		if atomic.LoadUint64(&sf.atomicUnavailable) == 1 || sf.draining {
 			continue
 		}
 		sfs = append(sfs, sf)
@@ -454,6 +460,8 @@ func (cm *ContractManager) StorageFolders() []modules.StorageFolderMetadata {
 			CapacityRemaining: ((64 * uint64(len(sf.usage))) - sf.sectors) * modules.SectorSize,
 			Index:             sf.index,
 			Path:              sf.path,
+			Draining:          sf.draining,
+			Health:            cm.managedFolderHealth(sf.index),
 		}
 
 		// Set some of the values to extreme numbers if the storage folder is
@@ -0,0 +1,249 @@
+package contractmanager
+
+import (
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+)
+
+// utilization returns the fraction of the storage folder's sectors which are
+// in use. The caller must hold the contract manager's sectorMu.
+func (sf *storageFolder) utilization() float64 {
+	capacity := uint64(len(sf.usage)) * storageFolderGranularity
+	if capacity == 0 {
+		return 1
+	}
+	return float64(sf.sectors) / float64(capacity)
+}
+
+// rebalanceNeeded returns whether the rebalancer should migrate sectors from
+// the src storage folder to the dst storage folder. Sectors are always
+// migrated out of draining storage folders. Otherwise they are only migrated if
+// the difference in utilization exceeds the rebalanceThreshold. The caller must
+// hold the contract manager's sectorMu.
+func rebalanceNeeded(src, dst *storageFolder) bool {
+	if src == dst || dst.draining || src.sectors == 0 {
+		return false
+	}
+	if atomic.LoadUint64(&src.atomicUnavailable) == 1 || atomic.LoadUint64(&dst.atomicUnavailable) == 1 {
+		return false
+	}
+	if dst.sectors >= uint64(len(dst.usage))*storageFolderGranularity {
+		return false
+	}
+	if src.draining {
+		return true
+	}
+	return src.utilization()-dst.utilization() > rebalanceThreshold
+}
+
+// managedRebalanceTargets returns the storage folders that the rebalancer
+// should migrate sectors between next. Draining storage folders are emptied
+// first, otherwise sectors are migrated from the most utilized to the least
+// utilized storage folder. nil is returned if no sectors need to be migrated.
+func (cm *ContractManager) managedRebalanceTargets() (src, dst *storageFolder) {
+	cm.sectorMu.Lock()
+	defer cm.sectorMu.Unlock()
+	for _, sf := range cm.storageFolders {
+		if atomic.LoadUint64(&sf.atomicUnavailable) == 1 {
+			continue
+		}
+		if sf.draining {
+			if sf.sectors > 0 && (src == nil || !src.draining) {
+				src = sf
+			}
+			continue
+		}
+		if sf.sectors < uint64(len(sf.usage))*storageFolderGranularity && (dst == nil || sf.utilization() < dst.utilization()) {
+			dst = sf
+		}
+		if sf.sectors > 0 && (src == nil || (!src.draining && sf.utilization() > src.utilization())) {
+			src = sf
+		}
+	}
+	if src == nil || dst == nil || !rebalanceNeeded(src, dst) {
+		return nil, nil
+	}
+	return src, dst
+}
+
+// managedRebalanceStorageFolder migrates sectors from the src storage folder
+// to the dst storage folder until the folders are balanced or the src folder
+// is drained. Migrations are throttled by rebalanceMoveDelay. The rebalancer
+// backs off if a storage folder operation is performed on the src folder.
+func (cm *ContractManager) managedRebalanceStorageFolder(src, dst *storageFolder) (moved uint64, err error) {
+	// Read the sector lookup bytes into memory to figure out which sectors
+	// are stored in the src folder.
+	cm.wal.mu.Lock()
+	cm.sectorMu.Lock()
+	usage := append([]uint64(nil), src.usage...)
+	cm.sectorMu.Unlock()
+	cm.wal.mu.Unlock()
+	sectorLookupBytes, err := readFullMetadata(src.metadataFile, len(usage)*storageFolderGranularity)
+	if err != nil {
+		atomic.AddUint64(&src.atomicFailedReads, 1)
+		return 0, errors.AddContext(err, "unable to read sector metadata")
+	}
+	atomic.AddUint64(&src.atomicSuccessfulReads, 1)
+
+	for i, u := range usage {
+		for j := uint32(0); j < storageFolderGranularity; j++ {
+			if u&(1<<j) == 0 {
+				continue
+			}
+			sectorIndex := uint32(i)*storageFolderGranularity + j
+			readHead := sectorIndex * sectorMetadataDiskSize
+			var id sectorID
+			copy(id[:], sectorLookupBytes[readHead:readHead+12])
+
+			// Check that the folders still need to be balanced and that the
+			// sector wasn't moved or deleted in the meantime.
+			cm.sectorMu.Lock()
+			needed := rebalanceNeeded(src, dst)
+			sl, exists := cm.sectorLocations[id]
+			cm.sectorMu.Unlock()
+			if !needed {
+				return moved, nil
+			}
+			if !exists || sl.storageFolder != src.index || sl.index != sectorIndex {
+				continue
+			}
+
+			// Don't interfere with storage folder operations on the src
+			// folder.
+			if !src.mu.TryRLock() {
+				return moved, nil
+			}
+			err := cm.wal.managedMoveSectorTo(id, []*storageFolder{dst})
+			src.mu.RUnlock()
+			if err != nil && err.Error() == modules.V1420HostOutOfStorageErrString {
+				// The dst folder is busy or full.
+				return moved, nil
+			} else if errors.Contains(err, errDiskTrouble) {
+				cm.staticAlerter.RegisterAlert(modules.AlertIDHostDiskTrouble, AlertMSGHostDiskTrouble, "", modules.SeverityCritical)
+				return moved, err
+			} else if err != nil {
+				return moved, err
+			}
+			moved++
+
+			select {
+			case <-cm.tg.StopChan():
+				return moved, nil
+			case <-time.After(rebalanceMoveDelay):
+			}
+		}
+	}
+	return moved, nil
+}
+
+// managedRebalanceStorageFolders performs a single rebalancing pass over the
+// storage folders. Afterwards, draining storage folders which are empty are
+// removed.
+func (cm *ContractManager) managedRebalanceStorageFolders() error {
+	cm.rebalanceMu.Lock()
+	defer cm.rebalanceMu.Unlock()
+
+	var moved uint64
+	for {
+		select {
+		case <-cm.tg.StopChan():
+			return nil
+		default:
+		}
+		src, dst := cm.managedRebalanceTargets()
+		if src == nil {
+			break
+		}
+		n, err := cm.managedRebalanceStorageFolder(src, dst)
+		moved += n
+		if err != nil {
+			return errors.AddContext(err, "unable to migrate sectors from "+src.path)
+		}
+		if n == 0 {
+			// No progress was made, try again during the next pass.
+			break
+		}
+	}
+	if moved > 0 {
+		cm.log.Printf("Rebalancer migrated %v sectors between storage folders\n", moved)
+	}
+
+	// Remove the draining storage folders which are empty.
+	cm.sectorMu.Lock()
+	var drained []uint16
+	for index, sf := range cm.storageFolders {
+		if sf.draining && sf.sectors == 0 && atomic.LoadUint64(&sf.atomicUnavailable) == 0 {
+			drained = append(drained, index)
+		}
+	}
+	cm.sectorMu.Unlock()
+	var errs error
+	for _, index := range drained {
+		err := cm.RemoveStorageFolder(index, false)
+		if err != nil {
+			errs = errors.Compose(errs, errors.AddContext(err, "unable to remove drained storage folder"))
+		}
+	}
+	return errs
+}
+
+// threadedRebalanceStorageFolders periodically migrates sectors between the
+// storage folders of the contract manager to even out their utilization and
+// to drain the storage folders which were marked for removal.
+func (cm *ContractManager) threadedRebalanceStorageFolders() {
+	// Don't spawn the loop if 'noRebalance' disruption is set.
+	if cm.dependencies.Disrupt("noRebalance") {
+		return
+	}
+	if err := cm.tg.Add(); err != nil {
+		return
+	}
+	defer cm.tg.Done()
+
+	for {
+		select {
+		case <-cm.tg.StopChan():
+			return
+		case <-cm.rebalanceChan:
+		case <-time.After(rebalanceInterval):
+		}
+		if err := cm.managedRebalanceStorageFolders(); err != nil {
+			cm.log.Println("WARN: storage folder rebalancing failed:", err)
+		}
+	}
+}
+
+// DrainStorageFolder marks a storage folder for removal or unmarks it. No new
+// sectors are placed into a draining storage folder. Instead, its sectors are
+// migrated to the other storage folders in the background and the folder is
+// removed once it is empty.
+func (cm *ContractManager) DrainStorageFolder(index uint16, drain bool) error {
+	err := cm.tg.Add()
+	if err != nil {
+		return err
+	}
+	defer cm.tg.Done()
+
+	cm.sectorMu.Lock()
+	sf, exists := cm.storageFolders[index]
+	if exists {
+		sf.draining = drain
+	}
+	cm.sectorMu.Unlock()
+	if !exists {
+		return errStorageFolderNotFound
+	}
+
+	// Wake up the rebalancer.
+	if drain {
+		select {
+		case cm.rebalanceChan <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
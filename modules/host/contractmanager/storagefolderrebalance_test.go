@@ -0,0 +1,124 @@
+package contractmanager
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+)
+
+// TestRebalanceStorageFolders checks that the rebalancer evens out the
+// utilization of the storage folders and drains storage folders which are
+// marked for removal.
+func TestRebalanceStorageFolders(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	cmt, err := newContractManagerTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cmt.panicClose()
+
+	// Add a storage folder and fill it with some sectors.
+	storageFolderOne := filepath.Join(cmt.persistDir, "storageFolderOne")
+	err = os.MkdirAll(storageFolderOne, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmt.cm.AddStorageFolder(storageFolderOne, modules.SectorSize*storageFolderGranularity*2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sectors := make(map[crypto.Hash][]byte)
+	for i := 0; i < 40; i++ {
+		root, data := randSector()
+		err = cmt.cm.AddSector(root, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sectors[root] = data
+	}
+
+	// Add a second, empty storage folder and rebalance.
+	storageFolderTwo := filepath.Join(cmt.persistDir, "storageFolderTwo")
+	err = os.MkdirAll(storageFolderTwo, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmt.cm.AddStorageFolder(storageFolderTwo, modules.SectorSize*storageFolderGranularity*2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmt.cm.managedRebalanceStorageFolders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfs := cmt.cm.StorageFolders()
+	if len(sfs) != 2 {
+		t.Fatal("expected 2 storage folders but got", len(sfs))
+	}
+	used := func(sf modules.StorageFolderMetadata) uint64 {
+		return (sf.Capacity - sf.CapacityRemaining) / modules.SectorSize
+	}
+	if used(sfs[0])+used(sfs[1]) != 40 {
+		t.Fatal("sectors went missing", used(sfs[0]), used(sfs[1]))
+	}
+	diff := float64(used(sfs[0])) - float64(used(sfs[1]))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff/float64(storageFolderGranularity*2) > rebalanceThreshold {
+		t.Fatal("storage folders weren't balanced", used(sfs[0]), used(sfs[1]))
+	}
+	checkSectors := func() {
+		for root, data := range sectors {
+			readData, err := cmt.cm.ReadSector(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(readData, data) {
+				t.Fatal("sector has the wrong data after migration")
+			}
+		}
+	}
+	checkSectors()
+
+	// Drain the first storage folder.
+	var index uint16
+	for _, sf := range sfs {
+		if sf.Path == storageFolderOne {
+			index = sf.Index
+		}
+	}
+	err = cmt.cm.DrainStorageFolder(index, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		sfs := cmt.cm.StorageFolders()
+		if len(sfs) != 1 {
+			return errors.New("drained storage folder wasn't removed")
+		}
+		if sfs[0].Path != storageFolderTwo || used(sfs[0]) != 40 {
+			return errors.New("sectors weren't migrated to the remaining storage folder")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSectors()
+
+	// Draining an unknown storage folder should fail.
+	if err := cmt.cm.DrainStorageFolder(index, true); err != errStorageFolderNotFound {
+		t.Fatal("expected errStorageFolderNotFound but got", err)
+	}
+}
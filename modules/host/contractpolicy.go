@@ -0,0 +1,158 @@
+package host
+
+import (
+	"encoding/json"
+	"time"
+
+	"gitlab.com/NebulousLabs/bolt"
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// maxContractDecisions is the number of contract decisions the host keeps
+	// in memory to be queried through the API.
+	maxContractDecisions = 1000
+)
+
+var (
+	// ErrOutsideAcceptanceWindow is returned if a renter tries to form or
+	// renew a contract outside of the host's acceptance windows.
+	ErrOutsideAcceptanceWindow = ErrorCommunication("host is not accepting contracts at this time of day")
+
+	// ErrInsufficientPaymentHistory is returned if a renter tries to form a
+	// contract without having completed enough contracts with the host.
+	ErrInsufficientPaymentHistory = ErrorCommunication("renter has not completed enough contracts with the host")
+
+	// ErrLowRenterFunds is returned if a renter tries to form or renew a
+	// contract with less funds than the host's policy requires.
+	ErrLowRenterFunds = ErrorCommunication("renter proposed a file contract with insufficient renter funds")
+)
+
+// contractDecisions is a bounded, in-memory log of the host's most recent
+// contract decisions.
+type contractDecisions struct {
+	decisions []modules.HostContractDecision
+}
+
+// add adds a decision to the log, dropping the oldest one if the log is full.
+func (cd *contractDecisions) add(d modules.HostContractDecision) {
+	cd.decisions = append(cd.decisions, d)
+	if len(cd.decisions) > maxContractDecisions {
+		cd.decisions = cd.decisions[len(cd.decisions)-maxContractDecisions:]
+	}
+}
+
+// checkContractPolicy checks a proposed file contract against the parts of the
+// host's contract policy that don't require access to the host's database.
+func checkContractPolicy(policy modules.HostContractPolicy, fc types.FileContract, now time.Time) error {
+	// Check the renter funds against the floor.
+	if fc.ValidRenterPayout().Cmp(policy.MinRenterFunds) < 0 {
+		return ErrLowRenterFunds
+	}
+	// If there are acceptance windows, the current time needs to fall into at
+	// least one of them.
+	if len(policy.AcceptanceWindows) == 0 {
+		return nil
+	}
+	for _, w := range policy.AcceptanceWindows {
+		if w.Contains(now) {
+			return nil
+		}
+	}
+	return ErrOutsideAcceptanceWindow
+}
+
+// managedCheckContractPolicy checks a proposed file contract from the renter
+// with the given key against the host's contract policy.
+func (h *Host) managedCheckContractPolicy(policy modules.HostContractPolicy, renterPK types.SiaPublicKey, fc types.FileContract, renewal bool) error {
+	err := checkContractPolicy(policy, fc, time.Now())
+	if err != nil {
+		return err
+	}
+	// Renewals are exempt from the payment history requirement since the
+	// renter is an existing customer.
+	if renewal || policy.MinCompletedContracts == 0 {
+		return nil
+	}
+	completed, err := h.managedCompletedContracts(renterPK)
+	if err != nil {
+		return errors.AddContext(err, "failed to count completed contracts")
+	}
+	if completed < policy.MinCompletedContracts {
+		return ErrInsufficientPaymentHistory
+	}
+	return nil
+}
+
+// managedCompletedContracts returns the number of storage obligations with the
+// renter that were completed successfully. Contracts with the same renter are
+// identified by their unlock hash, which is derived from the renter's and the
+// host's keys.
+//
+// NOTE: this iterates over all storage obligations which is why it's only
+// called if the host's policy requires a payment history.
+func (h *Host) managedCompletedContracts(renterPK types.SiaPublicKey) (completed uint64, err error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	uh := types.UnlockConditions{
+		PublicKeys:         []types.SiaPublicKey{renterPK, h.publicKey},
+		SignaturesRequired: 2,
+	}.UnlockHash()
+	err = h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketStorageObligations).ForEach(func(_, soBytes []byte) error {
+			var so storageObligation
+			err := json.Unmarshal(soBytes, &so)
+			if err != nil {
+				return build.ExtendErr("unable to unmarshal storage obligation:", err)
+			}
+			if so.ObligationStatus != obligationSucceeded || len(so.OriginTransactionSet) == 0 {
+				return nil
+			}
+			originTxn := so.OriginTransactionSet[len(so.OriginTransactionSet)-1]
+			if len(originTxn.FileContracts) > 0 && originTxn.FileContracts[0].UnlockHash == uh {
+				completed++
+			}
+			return nil
+		})
+	})
+	return
+}
+
+// managedRecordContractDecision records the outcome of evaluating a contract
+// request in the decision log and the host's log.
+func (h *Host) managedRecordContractDecision(renterPK types.SiaPublicKey, fc types.FileContract, blockHeight types.BlockHeight, renewal bool, reason error) {
+	d := modules.HostContractDecision{
+		Accepted:  reason == nil,
+		Renewal:   renewal,
+		RenterKey: renterPK,
+		Timestamp: time.Now(),
+	}
+	if fc.WindowStart > blockHeight {
+		d.Duration = fc.WindowStart - blockHeight
+	}
+	if reason != nil {
+		d.Reason = reason.Error()
+	}
+	h.mu.Lock()
+	h.contractDecisions.add(d)
+	h.mu.Unlock()
+
+	if d.Accepted {
+		h.log.Debugf("Accepted contract request from renter %v (renewal: %v, duration: %v)", renterPK, renewal, d.Duration)
+	} else {
+		h.log.Printf("Rejected contract request from renter %v (renewal: %v, duration: %v): %v", renterPK, renewal, d.Duration, reason)
+	}
+}
+
+// ContractDecisions returns the most recent decisions the host made about
+// incoming contract requests.
+func (h *Host) ContractDecisions() []modules.HostContractDecision {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	decisions := make([]modules.HostContractDecision, len(h.contractDecisions.decisions))
+	copy(decisions, h.contractDecisions.decisions)
+	return decisions
+}
@@ -0,0 +1,71 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestCheckContractPolicy is a unit test for checkContractPolicy.
+func TestCheckContractPolicy(t *testing.T) {
+	t.Parallel()
+
+	// Create a contract with 10 SC of renter funds.
+	fc := types.FileContract{
+		ValidProofOutputs: []types.SiacoinOutput{
+			{Value: types.SiacoinPrecision.Mul64(10)},
+			{Value: types.ZeroCurrency},
+		},
+	}
+	noon := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// The zero policy accepts everything.
+	if err := checkContractPolicy(modules.HostContractPolicy{}, fc, noon); err != nil {
+		t.Fatal(err)
+	}
+
+	// Contracts with too little funds are rejected.
+	policy := modules.HostContractPolicy{
+		MinRenterFunds: types.SiacoinPrecision.Mul64(11),
+	}
+	if err := checkContractPolicy(policy, fc, noon); err != ErrLowRenterFunds {
+		t.Fatal("expected ErrLowRenterFunds but got", err)
+	}
+	policy.MinRenterFunds = types.SiacoinPrecision.Mul64(10)
+	if err := checkContractPolicy(policy, fc, noon); err != nil {
+		t.Fatal(err)
+	}
+
+	// Contracts outside of the acceptance windows are rejected.
+	policy.AcceptanceWindows = []modules.HostAcceptanceWindow{
+		{Start: 2 * 60, End: 6 * 60},
+		{Start: 22 * 60, End: 60},
+	}
+	if err := checkContractPolicy(policy, fc, noon); err != ErrOutsideAcceptanceWindow {
+		t.Fatal("expected ErrOutsideAcceptanceWindow but got", err)
+	}
+	if err := checkContractPolicy(policy, fc, noon.Add(-7*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkContractPolicy(policy, fc, noon.Add(12*time.Hour+30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestContractDecisionsLimit makes sure the decision log is bounded.
+func TestContractDecisionsLimit(t *testing.T) {
+	t.Parallel()
+
+	var cd contractDecisions
+	for i := 0; i < maxContractDecisions+10; i++ {
+		cd.add(modules.HostContractDecision{Duration: types.BlockHeight(i)})
+	}
+	if len(cd.decisions) != maxContractDecisions {
+		t.Fatalf("expected %v decisions but got %v", maxContractDecisions, len(cd.decisions))
+	}
+	if cd.decisions[0].Duration != 10 {
+		t.Fatal("oldest decisions weren't dropped", cd.decisions[0].Duration)
+	}
+}
@@ -0,0 +1,186 @@
+package host
+
+import (
+	"sync"
+	"time"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// dynamicPricingChangeDivisor determines which price changes are
+	// significant enough to be applied. Changes smaller than
+	// 1/dynamicPricingChangeDivisor of the current price are ignored to avoid
+	// bumping the host's settings for every small fluctuation in demand.
+	dynamicPricingChangeDivisor = 100
+
+	// dynamicPricingDemandSmoothing is the weight of the most recent sample
+	// in the moving average of the host's bandwidth demand.
+	dynamicPricingDemandSmoothing = 0.5
+)
+
+// pricingEngine keeps track of the recent bandwidth demand of the host which
+// is used to adjust its bandwidth prices.
+type pricingEngine struct {
+	// demand is the moving average of the host's RPC traffic in bytes per
+	// second.
+	demand    float64
+	hasDemand bool
+
+	// lastPeriodStart, lastTotal and lastSample describe the previous sample
+	// of the bandwidth meter's period.
+	lastPeriodStart time.Time
+	lastTotal       uint64
+	lastSample      time.Time
+
+	mu sync.Mutex
+}
+
+// newPricingEngine creates a new pricing engine.
+func newPricingEngine() *pricingEngine {
+	return &pricingEngine{}
+}
+
+// callSampleDemand updates the moving average of the bandwidth demand with the
+// traffic recorded in the period since the previous sample and returns the
+// average. false is returned until two samples were taken.
+func (pe *pricingEngine) callSampleDemand(period modules.HostBandwidthPeriod, now time.Time) (float64, bool) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	total := period.Upload + period.Download
+	if !pe.lastSample.IsZero() && now.After(pe.lastSample) {
+		traffic := total
+		if period.Start.Equal(pe.lastPeriodStart) && total >= pe.lastTotal {
+			traffic = total - pe.lastTotal
+		}
+		rate := float64(traffic) / now.Sub(pe.lastSample).Seconds()
+		if pe.hasDemand {
+			pe.demand = dynamicPricingDemandSmoothing*rate + (1-dynamicPricingDemandSmoothing)*pe.demand
+		} else {
+			pe.demand = rate
+			pe.hasDemand = true
+		}
+	}
+	pe.lastPeriodStart = period.Start
+	pe.lastTotal = total
+	pe.lastSample = now
+	return pe.demand, pe.hasDemand
+}
+
+// interpolatePrice returns the price at the fraction between min and max. The
+// fraction is clamped to [0, 1].
+func interpolatePrice(min, max types.Currency, fraction float64) types.Currency {
+	if fraction <= 0 || max.Cmp(min) <= 0 {
+		return min
+	}
+	if fraction >= 1 {
+		return max
+	}
+	return min.Add(max.Sub(min).MulFloat(fraction))
+}
+
+// significantPriceChange returns whether the change from the old to the new
+// price is large enough to be applied.
+func significantPriceChange(old, new types.Currency) bool {
+	var diff types.Currency
+	if new.Cmp(old) > 0 {
+		diff = new.Sub(old)
+	} else {
+		diff = old.Sub(new)
+	}
+	if diff.IsZero() {
+		return false
+	}
+	return diff.Mul64(dynamicPricingChangeDivisor).Cmp(old) >= 0
+}
+
+// dynamicPrices returns the settings with the prices adjusted according to
+// the host's dynamic pricing, as well as whether any of the prices changed
+// significantly. The storage price is adjusted based on the utilization of
+// the host's storage, the bandwidth prices based on the demand in bytes per
+// second relative to the reference bandwidth. The bandwidth prices aren't
+// adjusted if the demand is unknown.
+func dynamicPrices(settings modules.HostInternalSettings, utilization, demand float64, hasDemand bool) (modules.HostInternalSettings, bool) {
+	dp := settings.DynamicPricing
+	changed := false
+	adjust := func(price *types.Currency, min, max types.Currency, fraction float64) {
+		if max.IsZero() {
+			return
+		}
+		// Prices outside of the bounds are always corrected.
+		newPrice := interpolatePrice(min, max, fraction)
+		if significantPriceChange(*price, newPrice) || price.Cmp(min) < 0 || price.Cmp(max) > 0 {
+			*price = newPrice
+			changed = true
+		}
+	}
+	adjust(&settings.MinStoragePrice, dp.MinStoragePrice, dp.MaxStoragePrice, utilization)
+
+	reference := dp.ReferenceBandwidth
+	if reference == 0 {
+		reference = settings.Traffic.MaxBandwidth
+	}
+	if hasDemand && reference > 0 {
+		fraction := demand / float64(reference)
+		adjust(&settings.MinDownloadBandwidthPrice, dp.MinDownloadBandwidthPrice, dp.MaxDownloadBandwidthPrice, fraction)
+		adjust(&settings.MinUploadBandwidthPrice, dp.MinUploadBandwidthPrice, dp.MaxUploadBandwidthPrice, fraction)
+	}
+	return settings, changed
+}
+
+// managedUpdateDynamicPrices adjusts the host's prices if dynamic pricing is
+// enabled. Updated prices are advertised to renters with a new revision of
+// the host's settings and price table.
+func (h *Host) managedUpdateDynamicPrices() {
+	now := time.Now()
+	demand, hasDemand := h.staticPricingEngine.callSampleDemand(h.staticBandwidthMeter.callPeriod(now), now)
+	total, remaining := h.capacity()
+	utilization := 1.0
+	if total > 0 {
+		utilization = float64(total-remaining) / float64(total)
+	}
+
+	h.mu.Lock()
+	if !h.settings.DynamicPricing.Enabled {
+		h.mu.Unlock()
+		return
+	}
+	settings, changed := dynamicPrices(h.settings, utilization, demand, hasDemand)
+	if !changed {
+		h.mu.Unlock()
+		return
+	}
+	h.settings = settings
+	h.revisionNumber++
+	err := h.saveSync()
+	h.mu.Unlock()
+	if err != nil {
+		h.log.Println("Could not save dynamic prices:", err)
+	}
+	h.managedUpdatePriceTable()
+	h.log.Debugf("Dynamic pricing updated prices: storage %v, download %v, upload %v", settings.MinStoragePrice, settings.MinDownloadBandwidthPrice, settings.MinUploadBandwidthPrice)
+}
+
+// threadedDynamicPricing periodically adjusts the host's prices according to
+// its dynamic pricing settings.
+func (h *Host) threadedDynamicPricing() {
+	for {
+		func() {
+			if err := h.tg.Add(); err != nil {
+				return
+			}
+			defer h.tg.Done()
+			h.managedUpdateDynamicPrices()
+		}()
+
+		// Block until next cycle.
+		select {
+		case <-h.tg.StopChan():
+			return
+		case <-time.After(dynamicPricingInterval):
+			continue
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestDynamicPrices is a unit test for the price adjustments of the host's
+// pricing engine.
+func TestDynamicPrices(t *testing.T) {
+	t.Parallel()
+
+	var settings modules.HostInternalSettings
+	settings.MinStoragePrice = types.NewCurrency64(100)
+	settings.MinDownloadBandwidthPrice = types.NewCurrency64(1000)
+	settings.MinUploadBandwidthPrice = types.NewCurrency64(1000)
+	settings.DynamicPricing = modules.HostDynamicPricing{
+		Enabled:                   true,
+		MinStoragePrice:           types.NewCurrency64(100),
+		MaxStoragePrice:           types.NewCurrency64(300),
+		MinDownloadBandwidthPrice: types.NewCurrency64(1000),
+		MaxDownloadBandwidthPrice: types.NewCurrency64(2000),
+		ReferenceBandwidth:        100,
+	}
+
+	// The storage price follows the utilization, the download price follows
+	// the demand. The upload price has no upper bound and isn't adjusted.
+	updated, changed := dynamicPrices(settings, 0.5, 50, true)
+	if !changed {
+		t.Fatal("prices should have changed")
+	}
+	if !updated.MinStoragePrice.Equals64(200) {
+		t.Fatal("wrong storage price", updated.MinStoragePrice)
+	}
+	if !updated.MinDownloadBandwidthPrice.Equals64(1500) {
+		t.Fatal("wrong download price", updated.MinDownloadBandwidthPrice)
+	}
+	if !updated.MinUploadBandwidthPrice.Equals64(1000) {
+		t.Fatal("upload price shouldn't change", updated.MinUploadBandwidthPrice)
+	}
+
+	// Insignificant changes are ignored.
+	if _, changed := dynamicPrices(updated, 0.502, 50.5, true); changed {
+		t.Fatal("insignificant change was applied")
+	}
+
+	// The prices are clamped to the bounds and the bandwidth prices aren't
+	// adjusted without a known demand.
+	updated, _ = dynamicPrices(updated, 2, 0, false)
+	if !updated.MinStoragePrice.Equals64(300) {
+		t.Fatal("wrong storage price", updated.MinStoragePrice)
+	}
+	if !updated.MinDownloadBandwidthPrice.Equals64(1500) {
+		t.Fatal("download price shouldn't change", updated.MinDownloadBandwidthPrice)
+	}
+
+	// Without a reference bandwidth, the max traffic bandwidth is used.
+	settings.DynamicPricing.ReferenceBandwidth = 0
+	settings.Traffic.MaxBandwidth = 200
+	updated, _ = dynamicPrices(settings, 0, 200, true)
+	if !updated.MinDownloadBandwidthPrice.Equals64(2000) {
+		t.Fatal("wrong download price", updated.MinDownloadBandwidthPrice)
+	}
+
+	// Prices outside of the bounds are corrected even if the change is small.
+	settings.DynamicPricing.MinStoragePrice = types.NewCurrency64(10000)
+	settings.DynamicPricing.MaxStoragePrice = types.NewCurrency64(30000)
+	settings.MinStoragePrice = types.NewCurrency64(9999)
+	updated, changed = dynamicPrices(settings, 0, 0, false)
+	if !changed || !updated.MinStoragePrice.Equals64(10000) {
+		t.Fatal("price outside of the bounds wasn't corrected", updated.MinStoragePrice)
+	}
+}
+
+// TestPricingEngineDemand is a unit test for the demand tracking of the host's
+// pricing engine.
+func TestPricingEngineDemand(t *testing.T) {
+	t.Parallel()
+
+	pe := newPricingEngine()
+	start := time.Now()
+	period := modules.HostBandwidthPeriod{Start: start}
+
+	// The first sample doesn't provide a demand.
+	if _, ok := pe.callSampleDemand(period, start); ok {
+		t.Fatal("demand shouldn't be known after the first sample")
+	}
+
+	// 1000 bytes within 10 seconds.
+	period.Upload, period.Download = 600, 400
+	demand, ok := pe.callSampleDemand(period, start.Add(10*time.Second))
+	if !ok || demand != 100 {
+		t.Fatal("wrong demand", demand, ok)
+	}
+
+	// No traffic within 10 seconds halves the average.
+	demand, _ = pe.callSampleDemand(period, start.Add(20*time.Second))
+	if demand != 50 {
+		t.Fatal("wrong demand", demand)
+	}
+
+	// A new period resets the traffic counters.
+	period = modules.HostBandwidthPeriod{Start: start.Add(25 * time.Second)}
+	period.Download = 1500
+	demand, _ = pe.callSampleDemand(period, start.Add(30*time.Second))
+	if demand != 100 {
+		t.Fatal("wrong demand", demand)
+	}
+}
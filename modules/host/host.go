@@ -160,10 +160,16 @@ type Host struct {
 	modules.StorageManager
 
 	// Subsystems
+	staticAccessLog             *accessLog
 	staticAccountManager        *accountManager
+	staticBandwidthMeter        *bandwidthMeter
 	staticMDM                   *mdm.MDM
+	staticPricingEngine         *pricingEngine
 	staticRegistry              *registry.Registry
+	staticRegistryLimiter       *registryLimiter
 	staticRegistrySubscriptions *registrySubscriptions
+	staticRenterLimiter         *renterLimiter
+	staticTrafficScheduler      *trafficScheduler
 
 	// Host ACID fields - these fields need to be updated in serial, ACID
 	// transactions.
@@ -183,6 +189,10 @@ type Host struct {
 	workingStatus        modules.HostWorkingStatus
 	connectabilityStatus modules.HostConnectabilityStatus
 
+	// The most recent decisions the host made about incoming contract
+	// requests.
+	contractDecisions contractDecisions
+
 	// A map of storage obligations that are currently being modified. Locks on
 	// storage obligations can be long-running, and each storage obligation can
 	// be locked separately.
@@ -457,13 +467,20 @@ func newHost(dependencies modules.Dependencies, smDeps modules.Dependencies, cs
 				heap: make([]*hostRPCPriceTable, 0),
 			},
 		},
+		staticRegistryLimiter:       newRegistryLimiter(),
 		staticRegistrySubscriptions: newRegistrySubscriptions(),
+		staticRenterLimiter:         newRenterLimiter(),
+		staticBandwidthMeter:        newBandwidthMeter(g),
+		staticPricingEngine:         newPricingEngine(),
 		persistDir:                  persistDir,
 	}
 
 	// Create MDM.
 	h.staticMDM = mdm.New(h)
 
+	// Create the traffic scheduler.
+	h.staticTrafficScheduler = newTrafficScheduler(h.tg.StopChan())
+
 	// Call stop in the event of a partial startup.
 	defer func() {
 		if err != nil {
@@ -532,6 +549,18 @@ func newHost(dependencies modules.Dependencies, smDeps modules.Dependencies, cs
 		return nil, err
 	}
 
+	// Load the access log and save it before shutting down.
+	h.staticAccessLog, err = newAccessLog(h.persistDir)
+	if err != nil {
+		return nil, err
+	}
+	h.tg.AfterStop(func() {
+		err := h.staticAccessLog.callSave()
+		if err != nil {
+			h.log.Println("Could not save access log upon shutdown:", err)
+		}
+	})
+
 	// Subscribe to the consensus set.
 	err = h.initConsensusSubscription()
 	if err != nil {
@@ -563,6 +592,15 @@ func newHost(dependencies modules.Dependencies, smDeps modules.Dependencies, cs
 	// Ensure the expired RPC tables get pruned as to not leak memory
 	go h.threadedPruneExpiredPriceTables()
 
+	// Prune and save the access log periodically.
+	go h.threadedPruneAccessLog()
+
+	// Adjust the prices periodically if dynamic pricing is enabled.
+	go h.threadedDynamicPricing()
+
+	// Check the conditions of the host's alerts periodically.
+	go h.threadedCheckAlerts()
+
 	return h, nil
 }
 
@@ -687,6 +725,41 @@ func (h *Host) SetInternalSettings(settings modules.HostInternalSettings) error
 		}
 	}
 
+	// The contract policy needs to be valid.
+	err = settings.ContractPolicy.Validate()
+	if err != nil {
+		return errors.AddContext(err, "internal settings not updated, invalid contract policy")
+	}
+
+	// The renter limits need to be valid.
+	err = settings.RenterLimits.Validate()
+	if err != nil {
+		return errors.AddContext(err, "internal settings not updated, invalid renter limits")
+	}
+
+	// The traffic settings need to be valid.
+	err = settings.Traffic.Validate()
+	if err != nil {
+		return errors.AddContext(err, "internal settings not updated, invalid traffic settings")
+	}
+
+	// The bandwidth caps need to be valid.
+	err = settings.BandwidthCaps.Validate()
+	if err != nil {
+		return errors.AddContext(err, "internal settings not updated, invalid bandwidth caps")
+	}
+
+	// The registry update rate can't be negative.
+	if settings.MaxRegistryUpdateRate < 0 {
+		return fmt.Errorf("internal settings not updated, max registry update rate %v is negative", settings.MaxRegistryUpdateRate)
+	}
+
+	// The dynamic pricing bounds need to be valid.
+	err = settings.DynamicPricing.Validate()
+	if err != nil {
+		return errors.AddContext(err, "internal settings not updated, invalid dynamic pricing")
+	}
+
 	if settings.NetAddress != "" {
 		err := settings.NetAddress.IsValid()
 		if err != nil {
@@ -727,6 +800,9 @@ func (h *Host) SetInternalSettings(settings modules.HostInternalSettings) error
 
 	h.settings = settings
 	h.revisionNumber++
+	h.staticTrafficScheduler.callSetSettings(settings.Traffic)
+	h.staticBandwidthMeter.callSetSettings(settings.BandwidthCaps)
+	h.StorageManager.SetSMARTQuerying(settings.DiskHealthSMART)
 
 	// The locked storage collateral was altered, we potentially want to
 	// unregister the insufficient collateral budget alert
@@ -773,7 +849,9 @@ func (h *Host) RegistryGet(sid modules.RegistryEntryID) (types.SiaPublicKey, mod
 		return types.SiaPublicKey{}, modules.SignedRegistryValue{}, false
 	}
 	defer h.tg.Done()
-	return h.staticRegistry.Get(sid)
+	spk, srv, found := h.staticRegistry.Get(sid)
+	h.staticRegistryLimiter.callRecordRead(found)
+	return spk, srv, found
 }
 
 // RegistryUpdate updates a value in the registry.
@@ -795,8 +873,16 @@ func (h *Host) RegistryUpdate(rv modules.SignedRegistryValue, pubKey types.SiaPu
 	if h.dependencies.Disrupt("RegistryUpdateNoOp") {
 		return modules.SignedRegistryValue{}, nil
 	}
+	// Reject the update if the host exceeded its update rate.
+	h.mu.RLock()
+	rate := h.settings.MaxRegistryUpdateRate
+	h.mu.RUnlock()
+	if !h.staticRegistryLimiter.callReserveUpdate(rate, time.Now()) {
+		return modules.SignedRegistryValue{}, ErrRegistryRateLimited
+	}
 	// Update the registry.
 	existingSRV, err := h.staticRegistry.Update(rv, pubKey, expiry)
+	h.staticRegistryLimiter.callRecordUpdate(err)
 	if err != nil {
 		return existingSRV, errors.AddContext(err, "failed to update registry")
 	}
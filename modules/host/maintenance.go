@@ -0,0 +1,51 @@
+package host
+
+import (
+	"go.sia.tech/siad/modules"
+)
+
+var (
+	// ErrMaintenanceMode is returned if a renter tries to upload data to the
+	// host while it is in maintenance mode.
+	ErrMaintenanceMode = ErrorCommunication("host is in maintenance mode and doesn't accept new data")
+)
+
+// managedMaintenanceMode indicates whether the host is in maintenance mode.
+func (h *Host) managedMaintenanceMode() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.settings.MaintenanceMode
+}
+
+// programAddsSectors indicates whether the program uploads new sectors to the
+// host.
+func programAddsSectors(p modules.Program) bool {
+	for _, instruction := range p {
+		if instruction.Specifier == modules.SpecifierAppend {
+			return true
+		}
+	}
+	return false
+}
+
+// writeActionsAddData indicates whether the write actions upload new data to
+// the host.
+func writeActionsAddData(actions []modules.LoopWriteAction) bool {
+	for _, action := range actions {
+		if action.Type == modules.WriteActionAppend || action.Type == modules.WriteActionUpdate {
+			return true
+		}
+	}
+	return false
+}
+
+// revisionActionsAddData indicates whether the revision actions of the legacy
+// revision protocol upload new data to the host.
+func revisionActionsAddData(actions []modules.RevisionAction) bool {
+	for _, action := range actions {
+		if action.Type == modules.ActionInsert || action.Type == modules.ActionModify {
+			return true
+		}
+	}
+	return false
+}
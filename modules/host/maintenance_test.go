@@ -0,0 +1,45 @@
+package host
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestMaintenanceModeUploads is a unit test for the helpers which determine
+// whether a request uploads new data to the host in maintenance mode.
+func TestMaintenanceModeUploads(t *testing.T) {
+	t.Parallel()
+
+	// MDM programs.
+	readOnly := modules.Program{{Specifier: modules.SpecifierHasSector}, {Specifier: modules.SpecifierReadSector}}
+	if programAddsSectors(readOnly) {
+		t.Fatal("read-only program shouldn't add sectors")
+	}
+	appendProgram := modules.Program{{Specifier: modules.SpecifierHasSector}, {Specifier: modules.SpecifierAppend}}
+	if !programAddsSectors(appendProgram) {
+		t.Fatal("append program should add sectors")
+	}
+
+	// RPC loop write actions.
+	if writeActionsAddData([]modules.LoopWriteAction{{Type: modules.WriteActionTrim}, {Type: modules.WriteActionSwap}}) {
+		t.Fatal("trim and swap shouldn't add data")
+	}
+	if !writeActionsAddData([]modules.LoopWriteAction{{Type: modules.WriteActionTrim}, {Type: modules.WriteActionAppend}}) {
+		t.Fatal("append should add data")
+	}
+	if !writeActionsAddData([]modules.LoopWriteAction{{Type: modules.WriteActionUpdate}}) {
+		t.Fatal("update should add data")
+	}
+
+	// Legacy revision actions.
+	if revisionActionsAddData([]modules.RevisionAction{{Type: modules.ActionDelete}}) {
+		t.Fatal("delete shouldn't add data")
+	}
+	if !revisionActionsAddData([]modules.RevisionAction{{Type: modules.ActionDelete}, {Type: modules.ActionInsert}}) {
+		t.Fatal("insert should add data")
+	}
+	if !revisionActionsAddData([]modules.RevisionAction{{Type: modules.ActionModify}}) {
+		t.Fatal("modify should add data")
+	}
+}
@@ -96,6 +96,12 @@ func (h *Host) managedDownloadIteration(conn net.Conn, so *storageObligation) er
 				return extendErr("failed to load sector: ", ErrorInternal(err.Error()))
 			}
 			payload = append(payload, sectorData[request.Offset:request.Offset+request.Length])
+			h.managedLogSectorAccess(modules.HostSectorAccess{
+				ContractID:  so.id(),
+				Hour:        time.Now(),
+				SectorReads: 1,
+				BytesRead:   request.Length,
+			})
 		}
 		return nil
 	}()
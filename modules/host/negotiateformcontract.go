@@ -217,7 +217,7 @@ func (h *Host) managedRPCFormContract(conn net.Conn) error {
 
 // managedVerifyNewContract checks that an incoming file contract matches the host's
 // expectations for a valid contract.
-func (h *Host) managedVerifyNewContract(txnSet []types.Transaction, renterPK crypto.PublicKey, eSettings modules.HostExternalSettings) error {
+func (h *Host) managedVerifyNewContract(txnSet []types.Transaction, renterPK crypto.PublicKey, eSettings modules.HostExternalSettings) (err error) {
 	// Register the HostInsufficientCollateral alert if necessary.
 	var registerHostInsufficientCollateral bool
 	defer func() {
@@ -246,6 +246,11 @@ func (h *Host) managedVerifyNewContract(txnSet []types.Transaction, renterPK cry
 	h.mu.RUnlock()
 	fc := txnSet[len(txnSet)-1].FileContracts[0]
 
+	// Record the host's decision once the contract was evaluated.
+	defer func() {
+		h.managedRecordContractDecision(types.Ed25519PublicKey(renterPK), fc, blockHeight, false, err)
+	}()
+
 	// A new file contract should have a file size of zero.
 	if fc.FileSize != 0 {
 		return ErrBadFileSize
@@ -269,6 +274,11 @@ func (h *Host) managedVerifyNewContract(txnSet []types.Transaction, renterPK cry
 	if fc.WindowStart > blockHeight+eSettings.MaxDuration {
 		return ErrLongDuration
 	}
+	// The contract needs to satisfy the host's contract policy.
+	err = h.managedCheckContractPolicy(iSettings.ContractPolicy, types.Ed25519PublicKey(renterPK), fc, false)
+	if err != nil {
+		return err
+	}
 
 	// ValidProofOutputs should have 2 outputs (renter + host) and missed
 	// outputs should have 3 (renter + host + void)
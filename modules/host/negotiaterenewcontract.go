@@ -96,7 +96,7 @@ func (h *Host) managedAddRenewCollateral(hostCollateral types.Currency, so stora
 
 // managedVerifyRenewedContract checks that the contract renewal matches the
 // previous contract and makes all of the appropriate payments.
-func (h *Host) managedVerifyRenewedContract(so storageObligation, txnSet []types.Transaction, renterPK types.SiaPublicKey) (types.Currency, error) {
+func (h *Host) managedVerifyRenewedContract(so storageObligation, txnSet []types.Transaction, renterPK types.SiaPublicKey) (_ types.Currency, err error) {
 	// Register the HostInsufficientCollateral alert if necessary.
 	var registerHostInsufficientCollateral bool
 	defer func() {
@@ -127,6 +127,11 @@ func (h *Host) managedVerifyRenewedContract(so storageObligation, txnSet []types
 	h.mu.Unlock()
 	fc := txnSet[len(txnSet)-1].FileContracts[0]
 
+	// Record the host's decision once the contract was evaluated.
+	defer func() {
+		h.managedRecordContractDecision(renterPK, fc, blockHeight, true, err)
+	}()
+
 	// The file size and merkle root must match the file size and merkle root
 	// from the previous file contract.
 	if fc.FileSize != so.fileSize() {
@@ -149,6 +154,11 @@ func (h *Host) managedVerifyRenewedContract(so storageObligation, txnSet []types
 	if fc.WindowStart > blockHeight+externalSettings.MaxDuration {
 		return types.Currency{}, ErrLongDuration
 	}
+	// The contract needs to satisfy the host's contract policy.
+	err = h.managedCheckContractPolicy(internalSettings.ContractPolicy, renterPK, fc, true)
+	if err != nil {
+		return types.Currency{}, err
+	}
 
 	// ValidProofOutputs shoud have 2 outputs (renter + host) and missed
 	// outputs should have 3 (renter + host + void)
@@ -82,6 +82,10 @@ func (h *Host) managedRevisionIteration(conn net.Conn, so *storageObligation, fi
 	var sectorsRemoved []crypto.Hash
 	sectorsGained := make(map[crypto.Hash][]byte)
 	err = func() error {
+		// Don't accept new data in maintenance mode.
+		if revisionActionsAddData(modifications) && h.managedMaintenanceMode() {
+			return ErrMaintenanceMode
+		}
 		for _, modification := range modifications {
 			// Check that the index points to an existing sector root. If the type
 			// is ActionInsert, we permit inserting at the end.
@@ -43,8 +43,9 @@ func (h *Host) externalSettings(maxFeeEstimation types.Currency) modules.HostExt
 		contractPrice = h.settings.MinContractPrice
 	}
 
-	// If the host's wallet is locked report that it is not accepting contracts.
-	acceptingContracts := h.settings.AcceptingContracts
+	// If the host's wallet is locked or the host is in maintenance mode report
+	// that it is not accepting contracts.
+	acceptingContracts := h.settings.AcceptingContracts && !h.settings.MaintenanceMode
 	if unlocked, err := h.wallet.Unlocked(); err != nil || !unlocked {
 		acceptingContracts = false
 	}
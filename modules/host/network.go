@@ -315,6 +315,17 @@ func (h *Host) threadedHandleConn(conn net.Conn) {
 		}
 	}
 
+	setTrafficClass(conn, rpcTrafficClass(id))
+
+	// Refuse bulk RPCs once the host reached its bandwidth cap. RPC loop
+	// sessions check the caps for each of their RPCs.
+	if rpcTrafficClass(id) == trafficClassBulk {
+		if err := h.managedCheckBandwidthCaps(types.SiaPublicKey{}); err != nil {
+			h.log.Debugf("WARN: refused RPC \"%v\" of incoming conn %v: %v", id, conn.RemoteAddr(), err)
+			return
+		}
+	}
+
 	switch id {
 	// new RPCs: enter an infinite request/response loop
 	case modules.RPCLoopEnter:
@@ -386,6 +397,9 @@ func (h *Host) threadedHandleStream(stream siamux.Stream) {
 	}
 	defer h.tg.Done()
 
+	// Record the stream's traffic.
+	stream = h.staticBandwidthMeter.newStream(stream)
+
 	// set an initial duration that is generous, but finite. RPCs can extend
 	// this if desired
 	err = stream.SetDeadline(time.Now().Add(defaultConnectionDeadline))
@@ -406,6 +420,19 @@ func (h *Host) threadedHandleStream(stream siamux.Stream) {
 		return
 	}
 
+	// Refuse bulk RPCs once the host reached its bandwidth cap.
+	if rpcTrafficClass(rpcID) == trafficClassBulk {
+		if err := h.managedCheckBandwidthCaps(types.SiaPublicKey{}); err != nil {
+			if wErr := modules.RPCWriteError(stream, err); wErr != nil {
+				h.managedLogError(wErr)
+			}
+			return
+		}
+	}
+
+	// Schedule the stream's traffic according to the RPC.
+	stream = h.staticTrafficScheduler.newStream(stream, rpcTrafficClass(rpcID))
+
 	switch rpcID {
 	case modules.RPCAccountBalance:
 		err = h.managedRPCAccountBalance(stream)
@@ -448,6 +475,8 @@ func (h *Host) threadedListen(closeChan chan struct{}) {
 		}
 
 		conn = connmonitor.NewMonitoredConn(conn, h.staticMonitor)
+		conn = h.staticBandwidthMeter.newConn(conn, "")
+		conn = h.staticTrafficScheduler.newConn(conn)
 
 		go h.threadedHandleConn(conn)
 
@@ -97,6 +97,13 @@ func (h *Host) managedRPCLoopLock(s *rpcSession) error {
 		return err
 	}
 
+	// now that the renter is known, apply its limits to the session
+	err = h.managedOpenRenterSession(s, rev.UnlockConditions.PublicKeys[0])
+	if err != nil {
+		err = errors.Compose(err, s.writeError(err))
+		return err
+	}
+
 	// attempt to lock the storage obligation
 	lockErr := h.managedTryLockStorageObligation(req.ContractID, lockTimeout)
 	if lockErr == nil {
@@ -185,6 +192,12 @@ func (h *Host) managedRPCLoopWrite(s *rpcSession) error {
 		return err
 	}
 
+	// Don't accept new data in maintenance mode.
+	if writeActionsAddData(req.Actions) && h.managedMaintenanceMode() {
+		s.writeError(ErrMaintenanceMode)
+		return ErrMaintenanceMode
+	}
+
 	// Read some internal fields for later.
 	_, maxFee := h.tpool.FeeEstimation()
 	h.mu.Lock()
@@ -543,6 +556,12 @@ func (h *Host) managedRPCLoopRead(s *rpcSession) error {
 			return err
 		}
 		data := sectorData[sec.Offset : sec.Offset+sec.Length]
+		h.managedLogSectorAccess(modules.HostSectorAccess{
+			ContractID:  s.so.id(),
+			Hour:        time.Now(),
+			SectorReads: 1,
+			BytesRead:   uint64(sec.Length),
+		})
 
 		// Construct the Merkle proof, if requested.
 		var proof []crypto.Hash
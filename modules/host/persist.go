@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gitlab.com/NebulousLabs/bolt"
 	"gitlab.com/NebulousLabs/errors"
@@ -30,6 +31,9 @@ type persistence struct {
 	SecretKey        crypto.SecretKey             `json:"secretkey"`
 	Settings         modules.HostInternalSettings `json:"settings"`
 	UnlockHash       types.UnlockHash             `json:"unlockhash"`
+
+	// Bandwidth accounting.
+	BandwidthPeriod modules.HostBandwidthPeriod `json:"bandwidthperiod"`
 }
 
 // persistData returns the data in the Host that will be saved to disk.
@@ -48,6 +52,9 @@ func (h *Host) persistData() persistence {
 		SecretKey:        h.secretKey,
 		Settings:         h.settings,
 		UnlockHash:       h.unlockHash,
+
+		// Bandwidth accounting.
+		BandwidthPeriod: h.staticBandwidthMeter.callPeriod(time.Now()),
 	}
 }
 
@@ -75,6 +82,10 @@ func (h *Host) establishDefaults() error {
 		EphemeralAccountExpiry:     modules.DefaultEphemeralAccountExpiry,
 		MaxEphemeralAccountBalance: modules.DefaultMaxEphemeralAccountBalance,
 		MaxEphemeralAccountRisk:    defaultMaxEphemeralAccountRisk,
+
+		RenterLimits: modules.HostRenterLimits{
+			Default: modules.DefaultHostRenterLimit,
+		},
 	}
 
 	// Load the host's key pair, use the same keys as the SiaMux.
@@ -116,6 +127,10 @@ func (h *Host) loadPersistObject(p *persistence) {
 	h.revisionNumber = p.RevisionNumber
 	h.secretKey = p.SecretKey
 	h.settings = p.Settings
+	h.staticTrafficScheduler.callSetSettings(p.Settings.Traffic)
+	h.staticBandwidthMeter.callSetSettings(p.Settings.BandwidthCaps)
+	h.StorageManager.SetSMARTQuerying(p.Settings.DiskHealthSMART)
+	h.staticBandwidthMeter.callLoad(p.BandwidthPeriod)
 	if err := p.Settings.NetAddress.IsValid(); err != nil {
 		h.log.Printf("WARN: NetAddress '%v' loaded from persist is invalid: %v", p.Settings.NetAddress, err)
 		h.settings.NetAddress = ""
@@ -0,0 +1,96 @@
+package host
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+var (
+	// ErrRegistryRateLimited is returned if a renter tries to update the
+	// registry after the host exceeded its max registry update rate.
+	ErrRegistryRateLimited = ErrorCommunication("host exceeded its registry update rate, try again later")
+)
+
+// registryLimiter rate limits the updates of the host's registry and keeps
+// track of the registry's usage.
+type registryLimiter struct {
+	// updateTokens is the number of registry updates the host accepts before
+	// rejecting them. The tokens refill at the max registry update rate, up
+	// to a burst of one second.
+	updateTokens float64
+	lastUpdate   time.Time
+
+	stats modules.HostRegistryStats
+	mu    sync.Mutex
+}
+
+// newRegistryLimiter creates a new registryLimiter.
+func newRegistryLimiter() *registryLimiter {
+	return &registryLimiter{}
+}
+
+// callReserveUpdate reserves a registry update. It returns false if the update
+// exceeds the given rate and needs to be rejected.
+func (rl *registryLimiter) callReserveUpdate(rate float64, now time.Time) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rate == 0 {
+		return true
+	}
+
+	// Refill the tokens.
+	burst := math.Max(1, rate)
+	if rl.lastUpdate.IsZero() {
+		rl.updateTokens = burst
+	} else if now.After(rl.lastUpdate) {
+		rl.updateTokens = math.Min(burst, rl.updateTokens+now.Sub(rl.lastUpdate).Seconds()*rate)
+	}
+	rl.lastUpdate = now
+
+	// Take a token if there is one left.
+	if rl.updateTokens < 1 {
+		rl.stats.RateLimitedUpdates++
+		return false
+	}
+	rl.updateTokens--
+	return true
+}
+
+// callRecordRead records a registry lookup.
+func (rl *registryLimiter) callRecordRead(found bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.stats.Reads++
+	if found {
+		rl.stats.ReadsFound++
+	}
+}
+
+// callRecordUpdate records a registry update.
+func (rl *registryLimiter) callRecordUpdate(err error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if err != nil {
+		rl.stats.FailedUpdates++
+	} else {
+		rl.stats.Updates++
+	}
+}
+
+// callStats returns the recorded registry usage.
+func (rl *registryLimiter) callStats() modules.HostRegistryStats {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.stats
+}
+
+// RegistryStats returns the usage of the host's registry.
+func (h *Host) RegistryStats() modules.HostRegistryStats {
+	stats := h.staticRegistryLimiter.callStats()
+	stats.Entries = h.staticRegistry.Len()
+	stats.MaxEntries = h.staticRegistry.Cap()
+	return stats
+}
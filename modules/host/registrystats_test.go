@@ -0,0 +1,78 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+)
+
+// TestRegistryLimiter is a unit test for the rate limiting and the statistics
+// of the host's registry.
+func TestRegistryLimiter(t *testing.T) {
+	t.Parallel()
+
+	rl := newRegistryLimiter()
+	now := time.Now()
+
+	// Without a rate, updates are never rejected.
+	for i := 0; i < 100; i++ {
+		if !rl.callReserveUpdate(0, now) {
+			t.Fatal("update was rejected without a rate")
+		}
+	}
+
+	// With a rate of 2 updates per second, the burst allows for 2 updates.
+	rl = newRegistryLimiter()
+	if !rl.callReserveUpdate(2, now) || !rl.callReserveUpdate(2, now) {
+		t.Fatal("update within the burst was rejected")
+	}
+	if rl.callReserveUpdate(2, now) {
+		t.Fatal("update exceeding the burst was accepted")
+	}
+
+	// After half a second, one more update is allowed.
+	now = now.Add(500 * time.Millisecond)
+	if !rl.callReserveUpdate(2, now) {
+		t.Fatal("update was rejected after refill")
+	}
+	if rl.callReserveUpdate(2, now) {
+		t.Fatal("update exceeding the rate was accepted")
+	}
+
+	// The tokens don't refill beyond the burst.
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if !rl.callReserveUpdate(2, now) {
+			t.Fatal("update within the burst was rejected")
+		}
+	}
+	if rl.callReserveUpdate(2, now) {
+		t.Fatal("update exceeding the burst was accepted")
+	}
+
+	// Rates below 1 still allow for a single update.
+	rl = newRegistryLimiter()
+	if !rl.callReserveUpdate(0.5, now) {
+		t.Fatal("first update was rejected")
+	}
+	if rl.callReserveUpdate(0.5, now.Add(time.Second)) {
+		t.Fatal("update exceeding the rate was accepted")
+	}
+	if !rl.callReserveUpdate(0.5, now.Add(3*time.Second)) {
+		t.Fatal("update was rejected after refill")
+	}
+
+	// Check the recorded stats.
+	rl.callRecordRead(true)
+	rl.callRecordRead(false)
+	rl.callRecordUpdate(nil)
+	rl.callRecordUpdate(errors.New("failed"))
+	stats := rl.callStats()
+	if stats.Reads != 2 || stats.ReadsFound != 1 {
+		t.Fatal("wrong read stats", stats)
+	}
+	if stats.Updates != 1 || stats.FailedUpdates != 1 || stats.RateLimitedUpdates != 1 {
+		t.Fatal("wrong update stats", stats)
+	}
+}
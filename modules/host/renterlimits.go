@@ -0,0 +1,176 @@
+package host
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/ratelimit"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// ErrTooManyRenterSessions is returned if a renter tries to open more
+	// sessions with the host than its limit allows.
+	ErrTooManyRenterSessions = ErrorCommunication("renter has too many open sessions with the host")
+
+	// errRPCThrottleInterrupted is returned if the host shuts down while an
+	// RPC is being delayed.
+	errRPCThrottleInterrupted = errors.New("rpc throttling interrupted by host shutdown")
+)
+
+// renterLimiter keeps track of the resources used by the renters that have
+// open sessions with the host.
+type renterLimiter struct {
+	renters map[string]*renterUsage
+	mu      sync.Mutex
+}
+
+// renterUsage contains the resources a single renter is using.
+type renterUsage struct {
+	// rpcTokens is the number of RPCs the renter can perform before being
+	// delayed. The tokens refill at the renter's max RPC rate, up to a burst
+	// of one second. A negative number means that RPCs are already delayed.
+	rpcTokens float64
+	lastRPC   time.Time
+
+	sessions        uint64
+	staticBandwidth *ratelimit.RateLimit
+}
+
+// newRenterLimiter creates a new renterLimiter.
+func newRenterLimiter() *renterLimiter {
+	return &renterLimiter{
+		renters: make(map[string]*renterUsage),
+	}
+}
+
+// callOpenSession registers a new session of the renter with the given key. It
+// returns the ratelimit that all sessions of the renter share.
+func (rl *renterLimiter) callOpenSession(key string, limit modules.HostRenterLimit) (*ratelimit.RateLimit, error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	u, exists := rl.renters[key]
+	if !exists {
+		u = &renterUsage{
+			staticBandwidth: ratelimit.NewRateLimit(0, 0, 0),
+		}
+		rl.renters[key] = u
+	}
+	if limit.MaxSessions > 0 && u.sessions >= limit.MaxSessions {
+		return nil, ErrTooManyRenterSessions
+	}
+	u.sessions++
+
+	// Update the bandwidth limit in case it changed since the renter's last
+	// session was opened.
+	if limit.MaxBandwidth == 0 {
+		u.staticBandwidth.SetLimits(0, 0, 0)
+	} else {
+		u.staticBandwidth.SetLimits(limit.MaxBandwidth, limit.MaxBandwidth, 4*4096)
+	}
+	return u.staticBandwidth, nil
+}
+
+// callCloseSession unregisters a session of the renter with the given key.
+// Once the renter has no open sessions, its usage is forgotten.
+func (rl *renterLimiter) callCloseSession(key string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	u, exists := rl.renters[key]
+	if !exists || u.sessions == 0 {
+		build.Critical("closing session of renter without open sessions")
+		return
+	}
+	u.sessions--
+	if u.sessions == 0 {
+		delete(rl.renters, key)
+	}
+}
+
+// callReserveRPC reserves an RPC for the renter with the given key. It returns
+// how long the RPC needs to be delayed to not exceed the renter's RPC rate.
+func (rl *renterLimiter) callReserveRPC(key string, limit modules.HostRenterLimit, now time.Time) time.Duration {
+	if limit.MaxRPCRate == 0 {
+		return 0
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	u, exists := rl.renters[key]
+	if !exists {
+		return 0
+	}
+
+	// Refill the tokens.
+	burst := math.Max(1, limit.MaxRPCRate)
+	if u.lastRPC.IsZero() {
+		u.rpcTokens = burst
+	} else if now.After(u.lastRPC) {
+		u.rpcTokens = math.Min(burst, u.rpcTokens+now.Sub(u.lastRPC).Seconds()*limit.MaxRPCRate)
+	}
+	u.lastRPC = now
+
+	// Take a token. If there is none left, the RPC needs to wait until the
+	// token it took is refilled.
+	u.rpcTokens--
+	if u.rpcTokens >= 0 {
+		return 0
+	}
+	return time.Duration(-u.rpcTokens / limit.MaxRPCRate * float64(time.Second))
+}
+
+// managedOpenRenterSession applies the limits of the renter with the given key
+// to the session. Sessions are only counted towards the first renter they are
+// used by.
+func (h *Host) managedOpenRenterSession(s *rpcSession, renterKey types.SiaPublicKey) error {
+	if len(s.renterKey.Key) != 0 {
+		return nil
+	}
+	h.mu.RLock()
+	limit := h.settings.RenterLimits.Limit(renterKey)
+	h.mu.RUnlock()
+
+	bandwidth, err := h.staticRenterLimiter.callOpenSession(renterKey.String(), limit)
+	if err != nil {
+		return err
+	}
+	s.renterKey = renterKey
+	s.conn = h.staticBandwidthMeter.newConn(s.conn, renterKey.String())
+	s.conn = ratelimit.NewRLConn(s.conn, bandwidth, h.tg.StopChan())
+	return nil
+}
+
+// managedCloseRenterSession releases the session's slot of its renter.
+func (h *Host) managedCloseRenterSession(s *rpcSession) {
+	if len(s.renterKey.Key) == 0 {
+		return
+	}
+	h.staticRenterLimiter.callCloseSession(s.renterKey.String())
+	s.renterKey = types.SiaPublicKey{}
+}
+
+// managedThrottleRPC delays the next RPC of the session if its renter exceeded
+// its RPC rate.
+func (h *Host) managedThrottleRPC(s *rpcSession) error {
+	if len(s.renterKey.Key) == 0 {
+		return nil
+	}
+	h.mu.RLock()
+	limit := h.settings.RenterLimits.Limit(s.renterKey)
+	h.mu.RUnlock()
+
+	wait := h.staticRenterLimiter.callReserveRPC(s.renterKey.String(), limit, time.Now())
+	if wait == 0 {
+		return nil
+	}
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-h.tg.StopChan():
+		return errRPCThrottleInterrupted
+	}
+}
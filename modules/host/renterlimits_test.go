@@ -0,0 +1,109 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/modules"
+)
+
+// TestRenterLimiterSessions is a unit test for limiting the number of sessions
+// of a renter.
+func TestRenterLimiterSessions(t *testing.T) {
+	t.Parallel()
+
+	rl := newRenterLimiter()
+	limit := modules.HostRenterLimit{MaxBandwidth: 1000, MaxSessions: 2}
+
+	// Open the allowed number of sessions. They should share a ratelimit.
+	bw1, err := rl.callOpenSession("renter", limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bw2, err := rl.callOpenSession("renter", limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bw1 != bw2 {
+		t.Fatal("sessions of the same renter should share a ratelimit")
+	}
+	if read, write, _ := bw1.Limits(); read != 1000 || write != 1000 {
+		t.Fatal("wrong bandwidth limits", read, write)
+	}
+
+	// Another session should be rejected but other renters are unaffected.
+	_, err = rl.callOpenSession("renter", limit)
+	if !errors.Contains(err, ErrTooManyRenterSessions) {
+		t.Fatal("expected ErrTooManyRenterSessions", err)
+	}
+	if _, err := rl.callOpenSession("other", limit); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing a session frees up a slot.
+	rl.callCloseSession("renter")
+	if _, err := rl.callOpenSession("renter", limit); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once all sessions are closed, the renter is forgotten.
+	rl.callCloseSession("renter")
+	rl.callCloseSession("renter")
+	if _, exists := rl.renters["renter"]; exists {
+		t.Fatal("renter without sessions should be removed")
+	}
+
+	// A limit of zero means unlimited.
+	for i := 0; i < 10; i++ {
+		if _, err := rl.callOpenSession("unlimited", modules.HostRenterLimit{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestRenterLimiterRPCRate is a unit test for throttling the RPCs of a renter.
+func TestRenterLimiterRPCRate(t *testing.T) {
+	t.Parallel()
+
+	rl := newRenterLimiter()
+	limit := modules.HostRenterLimit{MaxRPCRate: 2}
+	if _, err := rl.callOpenSession("renter", limit); err != nil {
+		t.Fatal(err)
+	}
+
+	// The renter can burst up to the RPC rate.
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if wait := rl.callReserveRPC("renter", limit, now); wait != 0 {
+			t.Fatal("RPC shouldn't be delayed", i, wait)
+		}
+	}
+
+	// Further RPCs are delayed by half a second each.
+	if wait := rl.callReserveRPC("renter", limit, now); wait != 500*time.Millisecond {
+		t.Fatal("wrong delay", wait)
+	}
+	if wait := rl.callReserveRPC("renter", limit, now); wait != time.Second {
+		t.Fatal("wrong delay", wait)
+	}
+
+	// After waiting, the tokens are refilled but not beyond the burst.
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		if wait := rl.callReserveRPC("renter", limit, now); wait != 0 {
+			t.Fatal("RPC shouldn't be delayed", i, wait)
+		}
+	}
+	if wait := rl.callReserveRPC("renter", limit, now); wait == 0 {
+		t.Fatal("RPC should be delayed")
+	}
+
+	// Unknown renters and unlimited renters are never delayed.
+	if wait := rl.callReserveRPC("unknown", limit, now); wait != 0 {
+		t.Fatal("RPC shouldn't be delayed", wait)
+	}
+	if wait := rl.callReserveRPC("renter", modules.HostRenterLimit{}, now); wait != 0 {
+		t.Fatal("RPC shouldn't be delayed", wait)
+	}
+}
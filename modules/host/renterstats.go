@@ -0,0 +1,56 @@
+package host
+
+import (
+	"sort"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// renterStats aggregates the unresolved storage obligations and the traffic of
+// the current accounting period by renter. Renters which only have traffic in
+// the current period are included as well.
+func renterStats(sos []modules.StorageObligation, period modules.HostBandwidthPeriod) []modules.HostRenterStats {
+	renters := make(map[string]*modules.HostRenterStats)
+	renter := func(key types.SiaPublicKey) *modules.HostRenterStats {
+		rs, exists := renters[key.String()]
+		if !exists {
+			rs = &modules.HostRenterStats{PublicKey: key}
+			renters[key.String()] = rs
+		}
+		return rs
+	}
+	for _, so := range sos {
+		if len(so.RenterPublicKey.Key) == 0 || so.ObligationStatus != obligationUnresolved.String() {
+			continue
+		}
+		rs := renter(so.RenterPublicKey)
+		rs.Contracts++
+		rs.StoredData += so.DataSize
+	}
+	for keyStr, usage := range period.Renters {
+		var key types.SiaPublicKey
+		if err := key.LoadString(keyStr); err != nil {
+			continue
+		}
+		renter(key).Bandwidth = usage
+	}
+
+	stats := make([]modules.HostRenterStats, 0, len(renters))
+	for _, rs := range renters {
+		stats = append(stats, *rs)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].StoredData != stats[j].StoredData {
+			return stats[i].StoredData > stats[j].StoredData
+		}
+		return stats[i].PublicKey.String() < stats[j].PublicKey.String()
+	})
+	return stats
+}
+
+// RenterStats returns the resources the host provides to each renter, ordered
+// by the size of the renters' stored data.
+func (h *Host) RenterStats() []modules.HostRenterStats {
+	return renterStats(h.StorageObligations(), h.BandwidthPeriod())
+}
@@ -0,0 +1,53 @@
+package host
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestRenterStats is a unit test for the aggregation of the host's per-renter
+// statistics.
+func TestRenterStats(t *testing.T) {
+	t.Parallel()
+
+	_, pk1 := crypto.GenerateKeyPair()
+	_, pk2 := crypto.GenerateKeyPair()
+	_, pk3 := crypto.GenerateKeyPair()
+	renter1 := types.Ed25519PublicKey(pk1)
+	renter2 := types.Ed25519PublicKey(pk2)
+	renter3 := types.Ed25519PublicKey(pk3)
+
+	unresolved := obligationUnresolved.String()
+	sos := []modules.StorageObligation{
+		{RenterPublicKey: renter1, DataSize: 100, ObligationStatus: unresolved},
+		{RenterPublicKey: renter1, DataSize: 200, ObligationStatus: unresolved},
+		{RenterPublicKey: renter2, DataSize: 1000, ObligationStatus: unresolved},
+		// Resolved obligations and obligations without a renter are ignored.
+		{RenterPublicKey: renter2, DataSize: 1000, ObligationStatus: obligationSucceeded.String()},
+		{DataSize: 1000, ObligationStatus: unresolved},
+	}
+	period := modules.HostBandwidthPeriod{
+		Renters: map[string]modules.HostBandwidthUsage{
+			renter1.String(): {Upload: 10, Download: 20},
+			renter3.String(): {Upload: 30},
+			"invalid":        {Upload: 40},
+		},
+	}
+
+	stats := renterStats(sos, period)
+	if len(stats) != 3 {
+		t.Fatal("wrong number of renters", len(stats))
+	}
+	if !stats[0].PublicKey.Equals(renter2) || stats[0].Contracts != 1 || stats[0].StoredData != 1000 || stats[0].Bandwidth.Upload != 0 {
+		t.Fatal("wrong stats for renter 2", stats[0])
+	}
+	if !stats[1].PublicKey.Equals(renter1) || stats[1].Contracts != 2 || stats[1].StoredData != 300 || stats[1].Bandwidth.Upload != 10 || stats[1].Bandwidth.Download != 20 {
+		t.Fatal("wrong stats for renter 1", stats[1])
+	}
+	if !stats[2].PublicKey.Equals(renter3) || stats[2].Contracts != 0 || stats[2].StoredData != 0 || stats[2].Bandwidth.Upload != 30 {
+		t.Fatal("wrong stats for renter 3", stats[2])
+	}
+}
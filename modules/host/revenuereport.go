@@ -0,0 +1,126 @@
+package host
+
+import (
+	"sort"
+	"time"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// revenuePeriodFormat is the time format of the calendar months of the
+	// revenue report.
+	revenuePeriodFormat = "2006-01"
+)
+
+// contractRevenue converts a storage obligation into its revenue for the
+// revenue report. blockTime returns the timestamp of the block at the provided
+// height and false if the block is unknown.
+func contractRevenue(so modules.StorageObligation, blockTime func(types.BlockHeight) (time.Time, bool)) modules.HostContractRevenue {
+	cr := modules.HostContractRevenue{
+		ContractID:        so.ObligationId,
+		RenterPublicKey:   so.RenterPublicKey,
+		Status:            so.ObligationStatus,
+		NegotiationHeight: so.NegotiationHeight,
+		ProofDeadline:     so.ProofDeadLine,
+
+		ContractCompensation: so.ContractCost,
+		StorageRevenue:       so.PotentialStorageRevenue,
+		DownloadRevenue:      so.PotentialDownloadRevenue,
+		UploadRevenue:        so.PotentialUploadRevenue,
+		AccountFunding:       so.PotentialAccountFunding,
+		TransactionFees:      so.TransactionFeesAdded,
+
+		LockedCollateral: so.LockedCollateral,
+		RiskedCollateral: so.RiskedCollateral,
+	}
+	cr.NegotiationTime, _ = blockTime(so.NegotiationHeight)
+
+	// An obligation succeeds once the storage proof was confirmed within the
+	// proof window and fails once the proof deadline has passed.
+	switch so.ObligationStatus {
+	case obligationSucceeded.String():
+		cr.ResolutionTime, _ = blockTime(so.ExpirationHeight)
+	case obligationFailed.String():
+		cr.ResolutionTime, _ = blockTime(so.ProofDeadLine)
+		cr.BurnedCollateral = so.RiskedCollateral
+	}
+	return cr
+}
+
+// revenueReport creates the revenue report of the provided storage
+// obligations. The totals of the resolved obligations are aggregated by the
+// calendar month of their resolution.
+func revenueReport(sos []modules.StorageObligation, blockTime func(types.BlockHeight) (time.Time, bool)) modules.HostRevenueReport {
+	report := modules.HostRevenueReport{
+		Contracts: []modules.HostContractRevenue{},
+		Periods:   []modules.HostRevenuePeriod{},
+	}
+	periods := make(map[string]*modules.HostRevenuePeriod)
+	for _, so := range sos {
+		if so.ObligationStatus == obligationRejected.String() {
+			continue
+		}
+		cr := contractRevenue(so, blockTime)
+		report.Contracts = append(report.Contracts, cr)
+		if cr.ResolutionTime.IsZero() {
+			continue
+		}
+
+		period := cr.ResolutionTime.UTC().Format(revenuePeriodFormat)
+		rp, exists := periods[period]
+		if !exists {
+			rp = &modules.HostRevenuePeriod{Period: period}
+			periods[period] = rp
+		}
+		rp.Contracts++
+		rp.TransactionFees = rp.TransactionFees.Add(cr.TransactionFees)
+		revenue := cr.ContractCompensation.Add(cr.StorageRevenue).Add(cr.DownloadRevenue).Add(cr.UploadRevenue).Add(cr.AccountFunding)
+		if cr.Status == obligationFailed.String() {
+			rp.LostRevenue = rp.LostRevenue.Add(revenue)
+			rp.BurnedCollateral = rp.BurnedCollateral.Add(cr.BurnedCollateral)
+			continue
+		}
+		rp.ContractCompensation = rp.ContractCompensation.Add(cr.ContractCompensation)
+		rp.StorageRevenue = rp.StorageRevenue.Add(cr.StorageRevenue)
+		rp.DownloadRevenue = rp.DownloadRevenue.Add(cr.DownloadRevenue)
+		rp.UploadRevenue = rp.UploadRevenue.Add(cr.UploadRevenue)
+		rp.AccountFunding = rp.AccountFunding.Add(cr.AccountFunding)
+	}
+
+	sort.Slice(report.Contracts, func(i, j int) bool {
+		if report.Contracts[i].NegotiationHeight != report.Contracts[j].NegotiationHeight {
+			return report.Contracts[i].NegotiationHeight < report.Contracts[j].NegotiationHeight
+		}
+		return report.Contracts[i].ContractID.String() < report.Contracts[j].ContractID.String()
+	})
+	for _, rp := range periods {
+		report.Periods = append(report.Periods, *rp)
+	}
+	sort.Slice(report.Periods, func(i, j int) bool {
+		return report.Periods[i].Period < report.Periods[j].Period
+	})
+	return report
+}
+
+// RevenueReport returns the revenue of the host's storage obligations per
+// contract and per calendar month for accounting purposes.
+func (h *Host) RevenueReport() modules.HostRevenueReport {
+	// Many obligations share the same heights, so the block timestamps are
+	// cached while creating the report.
+	times := make(map[types.BlockHeight]time.Time)
+	blockTime := func(height types.BlockHeight) (time.Time, bool) {
+		if t, exists := times[height]; exists {
+			return t, true
+		}
+		b, exists := h.cs.BlockAtHeight(height)
+		if !exists {
+			return time.Time{}, false
+		}
+		t := time.Unix(int64(b.Timestamp), 0).UTC()
+		times[height] = t
+		return t, true
+	}
+	return revenueReport(h.StorageObligations(), blockTime)
+}
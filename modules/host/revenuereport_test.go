@@ -0,0 +1,86 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestRevenueReport is a unit test for the aggregation of the revenue report.
+func TestRevenueReport(t *testing.T) {
+	t.Parallel()
+
+	// Every block height is a day.
+	start := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	blockTime := func(height types.BlockHeight) (time.Time, bool) {
+		if height > 100 {
+			return time.Time{}, false
+		}
+		return start.Add(time.Duration(height) * 24 * time.Hour), true
+	}
+	so := func(id byte, status storageObligationStatus, negotiation, expiration types.BlockHeight) modules.StorageObligation {
+		return modules.StorageObligation{
+			ObligationId:            types.FileContractID{id},
+			ObligationStatus:        status.String(),
+			ContractCost:            types.SiacoinPrecision,
+			PotentialStorageRevenue: types.SiacoinPrecision.Mul64(2),
+			PotentialUploadRevenue:  types.SiacoinPrecision.Mul64(3),
+			TransactionFeesAdded:    types.SiacoinPrecision.Mul64(4),
+			RiskedCollateral:        types.SiacoinPrecision.Mul64(5),
+			NegotiationHeight:       negotiation,
+			ExpirationHeight:        expiration,
+			ProofDeadLine:           expiration + 10,
+		}
+	}
+	sos := []modules.StorageObligation{
+		so(1, obligationSucceeded, 5, 20),  // resolved in January
+		so(2, obligationFailed, 1, 25),     // resolved in February
+		so(3, obligationSucceeded, 10, 40), // resolved in February
+		so(4, obligationRejected, 2, 30),
+		so(5, obligationUnresolved, 3, 90),
+	}
+	report := revenueReport(sos, blockTime)
+
+	// The rejected obligation is skipped and the contracts are ordered by
+	// their negotiation height.
+	if len(report.Contracts) != 4 {
+		t.Fatal("wrong number of contracts", len(report.Contracts))
+	}
+	for i, id := range []byte{2, 5, 1, 3} {
+		if report.Contracts[i].ContractID != (types.FileContractID{id}) {
+			t.Fatal("wrong order of contracts", i, report.Contracts[i].ContractID)
+		}
+	}
+	if !report.Contracts[0].BurnedCollateral.Equals(types.SiacoinPrecision.Mul64(5)) {
+		t.Fatal("collateral of the failed contract wasn't burned", report.Contracts[0].BurnedCollateral)
+	}
+	if !report.Contracts[1].ResolutionTime.IsZero() || !report.Contracts[1].BurnedCollateral.IsZero() {
+		t.Fatal("unresolved contract was resolved", report.Contracts[1])
+	}
+	if !report.Contracts[2].NegotiationTime.Equal(start.Add(5*24*time.Hour)) || !report.Contracts[2].ResolutionTime.Equal(start.Add(20*24*time.Hour)) {
+		t.Fatal("wrong times", report.Contracts[2].NegotiationTime, report.Contracts[2].ResolutionTime)
+	}
+
+	// The resolved contracts are aggregated by month.
+	if len(report.Periods) != 2 {
+		t.Fatal("wrong number of periods", report.Periods)
+	}
+	jan, feb := report.Periods[0], report.Periods[1]
+	if jan.Period != "2021-01" || jan.Contracts != 1 || !jan.StorageRevenue.Equals(types.SiacoinPrecision.Mul64(2)) || !jan.LostRevenue.IsZero() {
+		t.Fatal("wrong january", jan)
+	}
+	if feb.Period != "2021-02" || feb.Contracts != 2 {
+		t.Fatal("wrong february", feb)
+	}
+	if !feb.ContractCompensation.Equals(types.SiacoinPrecision) || !feb.UploadRevenue.Equals(types.SiacoinPrecision.Mul64(3)) {
+		t.Fatal("wrong revenue", feb)
+	}
+	if !feb.LostRevenue.Equals(types.SiacoinPrecision.Mul64(6)) || !feb.BurnedCollateral.Equals(types.SiacoinPrecision.Mul64(5)) {
+		t.Fatal("wrong losses", feb)
+	}
+	if !feb.TransactionFees.Equals(types.SiacoinPrecision.Mul64(8)) {
+		t.Fatal("wrong transaction fees", feb.TransactionFees)
+	}
+}
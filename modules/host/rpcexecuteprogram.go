@@ -81,6 +81,11 @@ func (h *Host) managedRPCExecuteProgram(stream siamux.Stream) error {
 	fcid, instructions, dataLength := epr.FileContractID, epr.Program, epr.ProgramDataLength
 	program := modules.Program(instructions)
 
+	// Don't accept new data in maintenance mode.
+	if programAddsSectors(program) && h.managedMaintenanceMode() {
+		return ErrMaintenanceMode
+	}
+
 	// If the program isn't readonly we need to acquire a lock on the storage
 	// obligation.
 	readonly := program.ReadOnly()
@@ -195,6 +200,14 @@ func (h *Host) managedRPCExecuteProgram(stream siamux.Stream) error {
 
 		instructionSpecifier := program[numOutputs-1].Specifier
 		readInstruction := instructionSpecifier == modules.SpecifierReadOffset || instructionSpecifier == modules.SpecifierReadSector
+		if readInstruction && output.Error == nil && fcid != (types.FileContractID{}) {
+			h.managedLogSectorAccess(modules.HostSectorAccess{
+				ContractID:  fcid,
+				Hour:        time.Now(),
+				SectorReads: 1,
+				BytesRead:   uint64(len(output.Output)),
+			})
+		}
 		updateRegistryInstruction := instructionSpecifier == modules.SpecifierUpdateRegistry
 		if (readInstruction || updateRegistryInstruction) && h.dependencies.Disrupt("CorruptMDMOutput") {
 			// Replace output with same amount of random data.
@@ -22,6 +22,10 @@ type rpcSession struct {
 	aead      cipher.AEAD
 	so        storageObligation
 	challenge [16]byte
+
+	// renterKey is the key of the renter whose limits apply to the session.
+	// It is set once the renter locks a contract.
+	renterKey types.SiaPublicKey
 }
 
 // extendDeadline extends the read/write deadline on the underlying connection
@@ -111,12 +115,14 @@ func (h *Host) managedRPCLoop(conn net.Conn) error {
 		return err
 	}
 
-	// ensure we unlock any locked contracts when protocol ends
+	// ensure we unlock any locked contracts when protocol ends and release
+	// the session's slot of the renter
 	defer func() {
 		if len(s.so.OriginTransactionSet) != 0 {
 			h.managedUnlockStorageObligation(s.so.id())
 			s.so = storageObligation{}
 		}
+		h.managedCloseRenterSession(s)
 	}()
 
 	// enter RPC loop
@@ -131,8 +137,8 @@ func (h *Host) managedRPCLoop(conn net.Conn) error {
 		modules.RPCLoopSectorRoots:        h.managedRPCLoopSectorRoots,
 	}
 	for {
-		conn.SetDeadline(time.Now().Add(rpcRequestInterval))
-		id, err := modules.ReadRPCID(conn, aead)
+		s.conn.SetDeadline(time.Now().Add(rpcRequestInterval))
+		id, err := modules.ReadRPCID(s.conn, aead)
 		if err != nil {
 			h.log.Debugf("WARN: could not read RPC ID: %v", err)
 			err = errors.Compose(err, s.writeError(err)) // try to write, even though this is probably due to a faulty connection
@@ -140,6 +146,15 @@ func (h *Host) managedRPCLoop(conn net.Conn) error {
 		} else if id == modules.RPCLoopExit {
 			return nil
 		}
+		if err := h.managedThrottleRPC(s); err != nil {
+			return err
+		}
+		if rpcTrafficClass(id) == trafficClassBulk {
+			if err := h.managedCheckBandwidthCaps(s.renterKey); err != nil {
+				return errors.Compose(err, s.writeError(err))
+			}
+		}
+		setTrafficClass(conn, rpcTrafficClass(id))
 		if rpcFn, ok := rpcs[id]; !ok {
 			return errors.New("invalid or unknown RPC ID: " + id.String())
 		} else if err := rpcFn(s); err != nil {
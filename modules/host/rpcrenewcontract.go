@@ -57,7 +57,7 @@ func (h *Host) managedRPCRenewContract(stream siamux.Stream) error {
 	hsk := h.secretKey
 	contractPrice := pt.ContractPrice
 	is := h.settings // internal settings
-	ac := is.AcceptingContracts
+	ac := is.AcceptingContracts && !is.MaintenanceMode
 	lockedCollateral := h.financialMetrics.LockedStorageCollateral
 	unlockHash := h.unlockHash
 	h.mu.RUnlock()
@@ -131,6 +131,10 @@ func (h *Host) managedRPCRenewContract(stream siamux.Stream) error {
 	// have the size set anymore which we need for collateral and base price
 	// calculations.
 	hostCollateral, err := verifyRenewedContract(so, newContract, currentRevision, bh, is, unlockHash, pt, rpk, hpk, lockedCollateral)
+	if err == nil {
+		err = h.managedCheckContractPolicy(is.ContractPolicy, rpk, newContract, true)
+	}
+	h.managedRecordContractDecision(rpk, newContract, bh, true, err)
 	if errors.Contains(err, errCollateralBudgetExceeded) {
 		h.staticAlerter.RegisterAlert(modules.AlertIDHostInsufficientCollateral, AlertMSGHostInsufficientCollateral, "", modules.SeverityWarning)
 	} else {
@@ -0,0 +1,80 @@
+package host
+
+import (
+	"encoding/json"
+	"sort"
+
+	"gitlab.com/NebulousLabs/bolt"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+)
+
+// managedContractsAtRisk returns the unresolved storage obligations which
+// require a storage proof and cover sectors that were quarantined by the
+// storage manager's integrity scrubber, ordered by their proof window.
+//
+// NOTE: this iterates over all storage obligations which is why it's only
+// called if the storage manager quarantined sectors.
+func (h *Host) managedContractsAtRisk() ([]modules.HostContractAtRisk, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	contracts := []modules.HostContractAtRisk{}
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketStorageObligations).ForEach(func(_, soBytes []byte) error {
+			var so storageObligation
+			err := json.Unmarshal(soBytes, &so)
+			if err != nil {
+				return build.ExtendErr("unable to unmarshal storage obligation:", err)
+			}
+			if so.ObligationStatus != obligationUnresolved || so.ProofConfirmed || !so.requiresProof() {
+				return nil
+			}
+			var corrupt uint64
+			for _, root := range so.SectorRoots {
+				if h.StorageManager.SectorCorrupt(root) {
+					corrupt++
+				}
+			}
+			if corrupt == 0 {
+				return nil
+			}
+			contracts = append(contracts, modules.HostContractAtRisk{
+				ContractID:       so.id(),
+				CorruptSectors:   corrupt,
+				ProofWindowStart: so.expiration(),
+				ProofDeadline:    so.proofDeadline(),
+				LockedCollateral: so.LockedCollateral,
+			})
+			return nil
+		})
+	})
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].ProofWindowStart < contracts[j].ProofWindowStart
+	})
+	return contracts, err
+}
+
+// ScrubReport returns the progress of the host's integrity scrubber and the
+// contracts whose storage proofs will fail because of quarantined sectors,
+// ordered by their proof window.
+func (h *Host) ScrubReport() (modules.HostScrubReport, error) {
+	if err := h.tg.Add(); err != nil {
+		return modules.HostScrubReport{}, err
+	}
+	defer h.tg.Done()
+
+	report := modules.HostScrubReport{
+		StorageScrubStatus: h.StorageManager.ScrubStatus(),
+		ContractsAtRisk:    []modules.HostContractAtRisk{},
+	}
+	if report.CorruptSectors == 0 {
+		return report, nil
+	}
+	contracts, err := h.managedContractsAtRisk()
+	if err != nil {
+		return modules.HostScrubReport{}, err
+	}
+	report.ContractsAtRisk = contracts
+	return report, nil
+}
@@ -0,0 +1,88 @@
+package host
+
+import (
+	"go.sia.tech/siad/modules"
+)
+
+// ValidateInternalSettings checks proposed internal settings for consistency
+// and projected consequences without applying them.
+func (h *Host) ValidateInternalSettings(settings modules.HostInternalSettings) (modules.SettingsValidation, error) {
+	if err := h.tg.Add(); err != nil {
+		return modules.SettingsValidation{}, err
+	}
+	defer h.tg.Done()
+	sv := modules.SettingsValidation{Issues: []modules.SettingsIssue{}}
+
+	// Check the settings that SetInternalSettings rejects.
+	if err := settings.ContractPolicy.Validate(); err != nil {
+		sv.AddError("contractpolicy", "%v", err)
+	}
+	if err := settings.RenterLimits.Validate(); err != nil {
+		sv.AddError("renterlimits", "%v", err)
+	}
+	if err := settings.BandwidthCaps.Validate(); err != nil {
+		sv.AddError("bandwidthcaps", "%v", err)
+	}
+	if err := settings.DynamicPricing.Validate(); err != nil {
+		sv.AddError("dynamicpricing", "%v", err)
+	}
+	if settings.MaxRegistryUpdateRate < 0 {
+		sv.AddError("maxregistryupdaterate", "can't be negative")
+	}
+	if settings.NetAddress != "" {
+		if err := settings.NetAddress.IsValid(); err != nil {
+			sv.AddError("netaddress", "%v", err)
+		}
+	}
+
+	// Check the prices for consistency.
+	if settings.MinBaseRPCPrice.Cmp(settings.MaxBaseRPCPrice()) > 0 {
+		sv.AddError("minbaserpcprice", "can't be more than %v times the download bandwidth price", modules.MaxBaseRPCPriceVsBandwidth)
+	}
+	if settings.MinSectorAccessPrice.Cmp(settings.MaxSectorAccessPrice()) > 0 {
+		sv.AddError("minsectoraccessprice", "can't be more than %v times the download bandwidth price", modules.MaxSectorAccessPriceVsBandwidth)
+	}
+	if dp := settings.DynamicPricing; dp.Enabled && !dp.MaxDownloadBandwidthPrice.IsZero() {
+		if settings.MinBaseRPCPrice.Cmp(dp.MinDownloadBandwidthPrice.Mul64(modules.MaxBaseRPCPriceVsBandwidth)) > 0 {
+			sv.AddError("dynamicpricing", "min base rpc price can't be more than %v times the lower bound of the download bandwidth price", modules.MaxBaseRPCPriceVsBandwidth)
+		}
+		if settings.MinSectorAccessPrice.Cmp(dp.MinDownloadBandwidthPrice.Mul64(modules.MaxSectorAccessPriceVsBandwidth)) > 0 {
+			sv.AddError("dynamicpricing", "min sector access price can't be more than %v times the lower bound of the download bandwidth price", modules.MaxSectorAccessPriceVsBandwidth)
+		}
+	}
+	if dp := settings.DynamicPricing; dp.Enabled && dp.ReferenceBandwidth == 0 && settings.Traffic.MaxBandwidth == 0 && (!dp.MaxDownloadBandwidthPrice.IsZero() || !dp.MaxUploadBandwidthPrice.IsZero()) {
+		sv.AddWarning("dynamicpricing", "bandwidth prices aren't adjusted without a reference bandwidth or max traffic bandwidth")
+	}
+	if bw := settings.RenterLimits.Default.MaxBandwidth; bw > 0 && bw < modules.SettingsMinRecommendedBandwidth {
+		sv.AddWarning("maxrenterbandwidth", "limits below %v per second may cause renters' transfers to time out", modules.FilesizeUnits(modules.SettingsMinRecommendedBandwidth))
+	}
+	if settings.MaintenanceMode {
+		sv.AddWarning("maintenancemode", "the host doesn't form or renew contracts and doesn't accept new data until maintenance mode is disabled")
+	}
+	if !settings.AcceptingContracts {
+		return sv, nil
+	}
+
+	// Check that renters can form contracts with the host.
+	h.mu.RLock()
+	netAddress := h.autoAddress
+	h.mu.RUnlock()
+	if settings.NetAddress == "" && netAddress == "" {
+		sv.AddWarning("netaddress", "no net address is set and none could be discovered, renters won't be able to reach the host")
+	}
+	if defaultDuration := modules.DefaultAllowance.Period + modules.DefaultAllowance.RenewWindow; settings.MaxDuration < defaultDuration {
+		sv.AddWarning("maxduration", "renters using the default allowance form contracts of %v blocks and will reject the host", defaultDuration)
+	}
+
+	// Check that the host can put up the collateral it offers.
+	if settings.Collateral.Cmp(settings.MinStoragePrice) < 0 {
+		sv.AddWarning("collateral", "collateral is lower than the storage price, renters prefer hosts with more collateral")
+	}
+	if settings.CollateralBudget.Cmp(settings.MaxCollateral) < 0 {
+		sv.AddWarning("collateralbudget", "collateral budget of %v can't cover the max collateral of %v of a single contract", settings.CollateralBudget.HumanString(), settings.MaxCollateral.HumanString())
+	}
+	if balance, _, _, err := h.wallet.ConfirmedBalance(); err == nil && balance.Cmp(settings.CollateralBudget) < 0 {
+		sv.AddWarning("collateralbudget", "the wallet only holds %v of the collateral budget of %v", balance.HumanString(), settings.CollateralBudget.HumanString())
+	}
+	return sv, nil
+}
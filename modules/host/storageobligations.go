@@ -33,6 +33,7 @@ package host
 import (
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"strconv"
 	"time"
@@ -344,6 +345,7 @@ func (so *storageObligation) StorageObligation() modules.StorageObligation {
 		PotentialDownloadRevenue: so.PotentialDownloadRevenue,
 		PotentialStorageRevenue:  so.PotentialStorageRevenue,
 		PotentialUploadRevenue:   so.PotentialUploadRevenue,
+		RenterPublicKey:          so.renterKey(),
 		RiskedCollateral:         so.RiskedCollateral,
 		SectorRootsCount:         uint64(len(so.SectorRoots)),
 		TransactionFeesAdded:     so.TransactionFeesAdded,
@@ -482,6 +484,17 @@ func (so storageObligation) revisionNumber() uint64 {
 	return so.OriginTransactionSet[len(so.OriginTransactionSet)-1].FileContracts[0].RevisionNumber
 }
 
+// renterKey returns the public key of the renter of the storage obligation.
+// The key is taken from the most recent revision, which is empty if the
+// obligation has no revision.
+func (so storageObligation) renterKey() types.SiaPublicKey {
+	rev, err := so.recentRevision()
+	if err != nil || len(rev.UnlockConditions.PublicKeys) == 0 {
+		return types.SiaPublicKey{}
+	}
+	return rev.UnlockConditions.PublicKeys[0]
+}
+
 // requiresProof is a helper to determine whether the storage obligation
 // requires a proof.
 func (so storageObligation) requiresProof() bool {
@@ -940,6 +953,16 @@ func (h *Host) managedModifyStorageObligation(so storageObligation, sectorsRemov
 
 	// Update the financial information for the storage obligation
 	h.updateFinancialMetricsUpdateSO(oldSO, so)
+
+	// Log the written sectors.
+	if h.settings.AccessLogging && len(sectorsGained) > 0 {
+		h.staticAccessLog.callAdd(modules.HostSectorAccess{
+			ContractID:   soid,
+			Hour:         time.Now(),
+			SectorWrites: uint64(len(sectorsGained)),
+			BytesWritten: uint64(len(sectorsGained)) * modules.SectorSize,
+		})
+	}
 	return nil
 }
 
@@ -1041,8 +1064,17 @@ func (h *Host) removeStorageObligation(so storageObligation, sos storageObligati
 		// The locked storage collateral was altered, we potentially want to
 		// unregister the insufficient collateral budget alert
 		h.tryUnregisterInsufficientCollateralBudgetAlert()
+
+		// A submitted storage proof resolves a missed storage proof alert.
+		if so.requiresProof() {
+			h.staticAlerter.UnregisterAlert(modules.AlertIDHostMissedStorageProof)
+		}
 	}
 	if sos == obligationFailed {
+		if so.requiresProof() {
+			cause := fmt.Sprintf("missed the proof window of contract %v which ended at height %v", so.id(), so.proofDeadline())
+			h.staticAlerter.RegisterAlert(modules.AlertIDHostMissedStorageProof, AlertMSGHostMissedStorageProof, cause, modules.SeverityError)
+		}
 		// Remove the obligation statistics as potential risk and income.
 		h.log.Printf("Missed storage proof. Revenue would have been %v.\n", so.ContractCost.Add(so.PotentialStorageRevenue).Add(so.PotentialDownloadRevenue).Add(so.PotentialUploadRevenue).Add(so.PotentialAccountFunding))
 		h.financialMetrics.PotentialAccountFunding = h.financialMetrics.PotentialAccountFunding.Sub(so.PotentialAccountFunding)
@@ -1275,6 +1307,7 @@ func (h *Host) threadedHandleActionItem(soid types.FileContractID) {
 			h.log.Printf("contract %s action: failed to build revision txn: Error signing transaction: %s", soid, err)
 			builder.Drop()
 		}
+		h.staticTrafficScheduler.callHoldBulk(time.Now().Add(storageProofTrafficHold))
 		err = h.tpool.AcceptTransactionSet(feeAddedRevisionTransactionSet)
 		if err != nil {
 			h.log.Printf("contract %s action: failed to build revision txn: Error submitting transaction to transaction pool: %s", soid, err)
@@ -1374,6 +1407,7 @@ func (h *Host) threadedHandleActionItem(soid types.FileContractID) {
 			builder.Drop()
 			return
 		}
+		h.staticTrafficScheduler.callHoldBulk(time.Now().Add(storageProofTrafficHold))
 		err = h.tpool.AcceptTransactionSet(storageProofSet)
 		if err != nil {
 			h.log.Printf("contract %s action: failed to build storage proof trransaction: Host unable to submit storage proof transaction to transaction pool: %s", soid, err)
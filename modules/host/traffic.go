@@ -0,0 +1,360 @@
+package host
+
+import (
+	"math"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/siamux"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// trafficClass is the class of traffic that a connection of the host is
+// currently used for.
+type trafficClass uint32
+
+const (
+	// trafficClassBulk is used for sector transfers and other traffic that
+	// isn't latency-critical.
+	trafficClassBulk trafficClass = iota
+
+	// trafficClassPriority is used for latency-critical traffic like contract
+	// formation, renewals and revisions.
+	trafficClassPriority
+)
+
+const (
+	// trafficChunkSize is the maximum number of bytes that a single read or
+	// write reserves at once. Larger writes are split up so that priority
+	// traffic can cut in between the chunks of a sector transfer.
+	trafficChunkSize = 1 << 14
+
+	// trafficYieldInterval is how long bulk traffic waits before checking
+	// again whether priority traffic is still queued.
+	trafficYieldInterval = 5 * time.Millisecond
+)
+
+var (
+	// storageProofTrafficHold is how long bulk traffic is held back after the
+	// host submitted a storage proof or a revision to the transaction pool.
+	// The transaction is broadcast by the gateway, which shares the link with
+	// the host's RPCs.
+	storageProofTrafficHold = build.Select(build.Var{
+		Standard: 10 * time.Second,
+		Testnet:  10 * time.Second,
+		Dev:      5 * time.Second,
+		Testing:  100 * time.Millisecond,
+	}).(time.Duration)
+
+	// errTrafficInterrupted is returned if the host shuts down while a read
+	// or write is waiting for bandwidth.
+	errTrafficInterrupted = errors.New("traffic scheduling interrupted by host shutdown")
+)
+
+type (
+	// trafficScheduler schedules the RPC traffic of the host. Each direction
+	// is limited to the configured bandwidth. Priority traffic is always
+	// served before bulk traffic and can exceed the limit by a burst that
+	// refills with unused bandwidth.
+	trafficScheduler struct {
+		settings      modules.HostTrafficSettings
+		read          trafficBucket
+		write         trafficBucket
+		bulkHeldUntil time.Time
+		stats         [2]modules.HostTrafficClassStats
+
+		staticStopChan <-chan struct{}
+		mu             sync.Mutex
+	}
+
+	// trafficBucket is the token bucket of a single direction.
+	trafficBucket struct {
+		// tokens is the number of bytes that can be transferred before
+		// traffic needs to wait. It refills at the bandwidth limit up to one
+		// second worth of traffic. A negative number means that traffic is
+		// already delayed.
+		tokens     float64
+		burst      float64
+		lastRefill time.Time
+
+		// priorityQueued is the number of priority reads or writes that are
+		// waiting for bandwidth. Bulk traffic yields while it is not zero.
+		priorityQueued uint64
+	}
+
+	// trafficConn is a connection whose traffic is scheduled by the host's
+	// traffic scheduler according to its current traffic class.
+	trafficConn struct {
+		net.Conn
+		atomicClass uint32
+
+		staticScheduler *trafficScheduler
+	}
+
+	// trafficStream is a SiaMux stream whose traffic is scheduled by the
+	// host's traffic scheduler.
+	trafficStream struct {
+		siamux.Stream
+		staticConn *trafficConn
+	}
+)
+
+// newTrafficScheduler creates a new traffic scheduler. Waiting traffic is
+// interrupted when the stop channel is closed.
+func newTrafficScheduler(stopChan <-chan struct{}) *trafficScheduler {
+	return &trafficScheduler{
+		staticStopChan: stopChan,
+	}
+}
+
+// rpcTrafficClass returns the traffic class of the RPC with the given id.
+func rpcTrafficClass(id types.Specifier) trafficClass {
+	switch id {
+	case modules.RPCDownload, modules.RPCReviseContract, modules.RPCLoopRead,
+		modules.RPCLoopWrite, modules.RPCLoopSectorRoots, modules.RPCExecuteProgram,
+		modules.RPCRegistrySubscription:
+		return trafficClassBulk
+	}
+	return trafficClassPriority
+}
+
+// setTrafficClass sets the traffic class of the connection if its traffic is
+// scheduled.
+func setTrafficClass(conn net.Conn, class trafficClass) {
+	if tc, ok := conn.(*trafficConn); ok {
+		atomic.StoreUint32(&tc.atomicClass, uint32(class))
+	}
+}
+
+// newConn wraps the connection to schedule its traffic. The connection starts
+// out as priority traffic.
+func (ts *trafficScheduler) newConn(conn net.Conn) *trafficConn {
+	return &trafficConn{
+		Conn:            conn,
+		atomicClass:     uint32(trafficClassPriority),
+		staticScheduler: ts,
+	}
+}
+
+// newStream wraps the stream to schedule its traffic using the given class.
+func (ts *trafficScheduler) newStream(stream siamux.Stream, class trafficClass) *trafficStream {
+	tc := ts.newConn(stream)
+	tc.atomicClass = uint32(class)
+	return &trafficStream{
+		Stream:     stream,
+		staticConn: tc,
+	}
+}
+
+// refill refills the bucket with the bandwidth that accumulated since the
+// last refill. Bandwidth that doesn't fit into the bucket refills the burst.
+func (ts *trafficScheduler) refill(b *trafficBucket, now time.Time) {
+	limit := float64(ts.settings.MaxBandwidth)
+	maxBurst := float64(ts.settings.PriorityBurst)
+	if b.lastRefill.IsZero() {
+		b.tokens = limit
+		b.burst = maxBurst
+	} else if now.After(b.lastRefill) {
+		b.tokens += now.Sub(b.lastRefill).Seconds() * limit
+		if b.tokens > limit {
+			b.burst = math.Min(maxBurst, b.burst+b.tokens-limit)
+			b.tokens = limit
+		}
+	}
+	b.lastRefill = now
+}
+
+// callReserve tries to reserve n bytes of the bucket for traffic of the given
+// class. It returns how long the traffic needs to wait before trying again or
+// 0 if the bytes were reserved.
+func (ts *trafficScheduler) callReserve(b *trafficBucket, class trafficClass, n int, now time.Time) time.Duration {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.settings.MaxBandwidth == 0 {
+		ts.stats[class].Bytes += uint64(n)
+		return 0
+	}
+	ts.refill(b, now)
+
+	// Bulk traffic yields to priority traffic.
+	if class == trafficClassBulk {
+		if now.Before(ts.bulkHeldUntil) {
+			return ts.bulkHeldUntil.Sub(now)
+		}
+		if b.priorityQueued > 0 {
+			return trafficYieldInterval
+		}
+	}
+
+	// Take the bytes from the bucket. Once the bucket is empty, priority
+	// traffic can dip into the burst.
+	if b.tokens > 0 {
+		b.tokens -= float64(n)
+	} else if class == trafficClassPriority && b.burst >= float64(n) {
+		b.burst -= float64(n)
+	} else {
+		wait := time.Duration(-b.tokens / float64(ts.settings.MaxBandwidth) * float64(time.Second))
+		if wait < time.Millisecond {
+			wait = time.Millisecond
+		}
+		return wait
+	}
+	ts.stats[class].Bytes += uint64(n)
+	return 0
+}
+
+// callEnqueue marks traffic of the given class as waiting for bandwidth.
+func (ts *trafficScheduler) callEnqueue(b *trafficBucket, class trafficClass) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.stats[class].Queued++
+	if class == trafficClassPriority {
+		b.priorityQueued++
+	}
+}
+
+// callDequeue marks traffic of the given class as no longer waiting and
+// records how long it waited.
+func (ts *trafficScheduler) callDequeue(b *trafficBucket, class trafficClass, delay time.Duration) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	stats := &ts.stats[class]
+	stats.Queued--
+	stats.Delayed++
+	stats.TotalDelay += delay
+	if delay > stats.MaxDelay {
+		stats.MaxDelay = delay
+	}
+	if class == trafficClassPriority {
+		b.priorityQueued--
+	}
+}
+
+// managedWait blocks until n bytes of the bucket are reserved for traffic of
+// the given class.
+func (ts *trafficScheduler) managedWait(b *trafficBucket, class trafficClass, n int) error {
+	var queued bool
+	var start time.Time
+	for {
+		now := time.Now()
+		wait := ts.callReserve(b, class, n, now)
+		if wait == 0 {
+			break
+		}
+		if !queued {
+			queued = true
+			start = now
+			ts.callEnqueue(b, class)
+		}
+		select {
+		case <-time.After(wait):
+		case <-ts.staticStopChan:
+			ts.callDequeue(b, class, time.Since(start))
+			return errTrafficInterrupted
+		}
+	}
+	if queued {
+		ts.callDequeue(b, class, time.Since(start))
+	}
+	return nil
+}
+
+// callHoldBulk holds back all bulk traffic until the given time.
+func (ts *trafficScheduler) callHoldBulk(until time.Time) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if until.After(ts.bulkHeldUntil) {
+		ts.bulkHeldUntil = until
+	}
+}
+
+// callSetSettings updates the settings of the scheduler. The buckets start out
+// full with the new settings.
+func (ts *trafficScheduler) callSetSettings(settings modules.HostTrafficSettings) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.settings == settings {
+		return
+	}
+	ts.settings = settings
+	ts.read.lastRefill = time.Time{}
+	ts.write.lastRefill = time.Time{}
+}
+
+// callStats returns the state of the scheduler.
+func (ts *trafficScheduler) callStats() modules.HostTrafficStats {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	now := time.Now()
+	ts.refill(&ts.read, now)
+	ts.refill(&ts.write, now)
+	return modules.HostTrafficStats{
+		HostTrafficSettings: ts.settings,
+		BurstAvailable:      int64(math.Min(ts.read.burst, ts.write.burst)),
+		Priority:            ts.stats[trafficClassPriority],
+		Bulk:                ts.stats[trafficClassBulk],
+	}
+}
+
+// class returns the current traffic class of the connection.
+func (tc *trafficConn) class() trafficClass {
+	return trafficClass(atomic.LoadUint32(&tc.atomicClass))
+}
+
+// Read reads from the connection and waits for the bandwidth of the bytes
+// that were read.
+func (tc *trafficConn) Read(p []byte) (int, error) {
+	if len(p) > trafficChunkSize {
+		p = p[:trafficChunkSize]
+	}
+	n, err := tc.Conn.Read(p)
+	if n > 0 {
+		if waitErr := tc.staticScheduler.managedWait(&tc.staticScheduler.read, tc.class(), n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// Write writes to the connection in chunks, waiting for the bandwidth of each
+// chunk before writing it.
+func (tc *trafficConn) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > trafficChunkSize {
+			chunk = chunk[:trafficChunkSize]
+		}
+		if err := tc.staticScheduler.managedWait(&tc.staticScheduler.write, tc.class(), len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := tc.Conn.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Read reads from the stream.
+func (ts *trafficStream) Read(p []byte) (int, error) {
+	return ts.staticConn.Read(p)
+}
+
+// Write writes to the stream.
+func (ts *trafficStream) Write(p []byte) (int, error) {
+	return ts.staticConn.Write(p)
+}
+
+// TrafficStats returns the state of the host's traffic prioritization.
+func (h *Host) TrafficStats() modules.HostTrafficStats {
+	return h.staticTrafficScheduler.callStats()
+}
@@ -0,0 +1,109 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestTrafficSchedulerReserve is a unit test for reserving bandwidth with the
+// traffic scheduler.
+func TestTrafficSchedulerReserve(t *testing.T) {
+	t.Parallel()
+
+	ts := newTrafficScheduler(make(chan struct{}))
+	b := &ts.write
+	now := time.Now()
+
+	// Without a limit, traffic never waits.
+	if wait := ts.callReserve(b, trafficClassBulk, 1e9, now); wait != 0 {
+		t.Fatal("unlimited traffic shouldn't wait", wait)
+	}
+
+	// Set a limit of 1000 bytes per second with a burst of 500 bytes. The
+	// bucket starts out full.
+	ts.callSetSettings(modules.HostTrafficSettings{MaxBandwidth: 1000, PriorityBurst: 500})
+	if wait := ts.callReserve(b, trafficClassBulk, 1500, now); wait != 0 {
+		t.Fatal("full bucket should allow traffic", wait)
+	}
+
+	// The bucket is in debt now. Bulk traffic needs to wait for the debt to
+	// be paid off.
+	wait := ts.callReserve(b, trafficClassBulk, 100, now)
+	if wait != 500*time.Millisecond {
+		t.Fatal("wrong wait", wait)
+	}
+
+	// Priority traffic can use the burst.
+	if wait := ts.callReserve(b, trafficClassPriority, 400, now); wait != 0 {
+		t.Fatal("priority traffic should use the burst", wait)
+	}
+	if wait := ts.callReserve(b, trafficClassPriority, 400, now); wait == 0 {
+		t.Fatal("burst should be used up")
+	}
+
+	// While priority traffic is queued, bulk traffic yields even if the
+	// bucket was refilled.
+	later := now.Add(time.Second)
+	ts.callEnqueue(b, trafficClassPriority)
+	if wait := ts.callReserve(b, trafficClassBulk, 100, later); wait != trafficYieldInterval {
+		t.Fatal("bulk traffic should yield", wait)
+	}
+	ts.callDequeue(b, trafficClassPriority, time.Second)
+	if wait := ts.callReserve(b, trafficClassBulk, 100, later); wait != 0 {
+		t.Fatal("bulk traffic shouldn't wait", wait)
+	}
+
+	// Bulk traffic is held back after a storage proof.
+	ts.callHoldBulk(later.Add(time.Second))
+	if wait := ts.callReserve(b, trafficClassBulk, 1, later); wait != time.Second {
+		t.Fatal("bulk traffic should be held", wait)
+	}
+	if wait := ts.callReserve(b, trafficClassPriority, 1, later); wait != 0 {
+		t.Fatal("priority traffic shouldn't be held", wait)
+	}
+
+	// Check the stats.
+	stats := ts.callStats()
+	if stats.Bulk.Bytes != 1e9+1500+100 || stats.Priority.Bytes != 401 {
+		t.Fatal("wrong bytes", stats.Bulk.Bytes, stats.Priority.Bytes)
+	}
+	if stats.Priority.Delayed != 1 || stats.Priority.MaxDelay != time.Second || stats.Priority.Queued != 0 {
+		t.Fatal("wrong priority stats", stats.Priority)
+	}
+}
+
+// TestTrafficSchedulerBurstRefill checks that the burst is only refilled with
+// unused bandwidth.
+func TestTrafficSchedulerBurstRefill(t *testing.T) {
+	t.Parallel()
+
+	ts := newTrafficScheduler(make(chan struct{}))
+	ts.callSetSettings(modules.HostTrafficSettings{MaxBandwidth: 1000, PriorityBurst: 500})
+	b := &ts.read
+	now := time.Now()
+
+	// Use up the bucket and the burst.
+	if wait := ts.callReserve(b, trafficClassPriority, 1000, now); wait != 0 {
+		t.Fatal(wait)
+	}
+	if wait := ts.callReserve(b, trafficClassPriority, 500, now); wait != 0 {
+		t.Fatal(wait)
+	}
+	if b.tokens != 0 || b.burst != 0 {
+		t.Fatal("bucket should be empty", b.tokens, b.burst)
+	}
+
+	// After a second the bucket is full again but the burst is still empty.
+	ts.refill(b, now.Add(time.Second))
+	if b.tokens != 1000 || b.burst != 0 {
+		t.Fatal("wrong refill", b.tokens, b.burst)
+	}
+
+	// Unused bandwidth refills the burst up to its maximum.
+	ts.refill(b, now.Add(2*time.Second))
+	if b.tokens != 1000 || b.burst != 500 {
+		t.Fatal("wrong refill", b.tokens, b.burst)
+	}
+}
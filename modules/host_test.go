@@ -2,6 +2,9 @@ package modules
 
 import (
 	"testing"
+	"time"
+
+	"go.sia.tech/siad/types"
 )
 
 // TestUnitMaxFileContractSetLenSanity checks that a sensible value for
@@ -19,3 +22,92 @@ func TestUnitMaxFileContractSetLenSanity(t *testing.T) {
 		t.Fatal("MaxfileContractSetLen does not have a sensible value - should be smaller than the TransactionSetSizeLimit")
 	}
 }
+
+// TestParseHostAcceptanceWindows tests parsing acceptance windows and checking
+// whether they contain a certain time of day.
+func TestParseHostAcceptanceWindows(t *testing.T) {
+	t.Parallel()
+
+	// Empty string results in no windows.
+	windows, err := ParseHostAcceptanceWindows("")
+	if err != nil || len(windows) != 0 {
+		t.Fatal("unexpected result", windows, err)
+	}
+
+	// Parse valid windows.
+	windows, err = ParseHostAcceptanceWindows("02:00-06:30, 22:15-01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 2 {
+		t.Fatal("wrong number of windows", len(windows))
+	}
+	if windows[0].String() != "02:00-06:30" || windows[1].String() != "22:15-01:00" {
+		t.Fatal("wrong windows", windows)
+	}
+
+	// Check Contains.
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		offset   time.Duration
+		window   int
+		contains bool
+	}{
+		{2 * time.Hour, 0, true},
+		{6*time.Hour + 29*time.Minute, 0, true},
+		{6*time.Hour + 30*time.Minute, 0, false},
+		{time.Hour, 0, false},
+		{23 * time.Hour, 1, true},
+		{30 * time.Minute, 1, true},
+		{time.Hour, 1, false},
+		{12 * time.Hour, 1, false},
+	}
+	for _, test := range tests {
+		if windows[test.window].Contains(day.Add(test.offset)) != test.contains {
+			t.Errorf("window %v, offset %v: expected %v", windows[test.window], test.offset, test.contains)
+		}
+	}
+
+	// Invalid windows.
+	for _, s := range []string{"garbage", "24:00-01:00", "01:60-02:00", "01:00-01:00"} {
+		if _, err := ParseHostAcceptanceWindows(s); err == nil {
+			t.Errorf("expected '%v' to fail", s)
+		}
+	}
+}
+
+// TestHostRenterLimits tests looking up and validating renter limits.
+func TestHostRenterLimits(t *testing.T) {
+	t.Parallel()
+
+	renter := types.Ed25519PublicKey([32]byte{1})
+	other := types.Ed25519PublicKey([32]byte{2})
+	limits := HostRenterLimits{
+		Default: HostRenterLimit{MaxSessions: 10},
+		Overrides: map[string]HostRenterLimit{
+			renter.String(): {MaxSessions: 1},
+		},
+	}
+	if err := limits.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if limit := limits.Limit(renter); limit.MaxSessions != 1 {
+		t.Fatal("override wasn't used", limit)
+	}
+	if limit := limits.Limit(other); limit.MaxSessions != 10 {
+		t.Fatal("default wasn't used", limit)
+	}
+
+	// Invalid limits.
+	invalid := []HostRenterLimits{
+		{Default: HostRenterLimit{MaxBandwidth: -1}},
+		{Default: HostRenterLimit{MaxRPCRate: -1}},
+		{Overrides: map[string]HostRenterLimit{"garbage": {}}},
+		{Overrides: map[string]HostRenterLimit{renter.String(): {MaxRPCRate: -1}}},
+	}
+	for _, l := range invalid {
+		if err := l.Validate(); err == nil {
+			t.Errorf("expected %v to be invalid", l)
+		}
+	}
+}
@@ -1,7 +1,13 @@
 package modules
 
 import (
+	"fmt"
 	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"gitlab.com/NebulousLabs/errors"
 
 	"go.sia.tech/siad/types"
 )
@@ -10,8 +16,100 @@ const (
 	// MinerDir is the name of the directory that is used to store the miner's
 	// persistent data.
 	MinerDir = "miner"
+
+	// MaxMinerPayoutSplits is the maximum number of addresses the block
+	// reward can be split across.
+	MaxMinerPayoutSplits = 16
 )
 
+// MinerPayoutSplit assigns a percentage of the block reward to an address.
+// Percentages have a precision of 0.01%.
+type MinerPayoutSplit struct {
+	UnlockHash types.UnlockHash `json:"unlockhash"`
+	Percentage float64          `json:"percentage"`
+}
+
+// ParseMinerPayoutSplit parses a payout split in the format
+// "address:percentage", e.g. "<address>:2.5".
+func ParseMinerPayoutSplit(s string) (MinerPayoutSplit, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return MinerPayoutSplit{}, fmt.Errorf("payout split '%v' should have the format address:percentage", s)
+	}
+	var split MinerPayoutSplit
+	if err := split.UnlockHash.LoadString(strings.TrimSpace(parts[0])); err != nil {
+		return MinerPayoutSplit{}, errors.AddContext(err, fmt.Sprintf("unable to parse address of payout split '%v'", s))
+	}
+	percentage, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return MinerPayoutSplit{}, errors.AddContext(err, fmt.Sprintf("unable to parse percentage of payout split '%v'", s))
+	}
+	split.Percentage = percentage
+	return split, nil
+}
+
+// String returns the payout split in the format "address:percentage".
+func (split MinerPayoutSplit) String() string {
+	return split.UnlockHash.String() + ":" + strconv.FormatFloat(split.Percentage, 'f', -1, 64)
+}
+
+// BasisPoints returns the percentage of the payout split in hundredths of a
+// percent.
+func (split MinerPayoutSplit) BasisPoints() uint64 {
+	if split.Percentage <= 0 || math.IsNaN(split.Percentage) {
+		return 0
+	}
+	return uint64(math.Round(math.Min(split.Percentage, 100) * 100))
+}
+
+// ValidateMinerPayoutSplits checks that every split has an address and a
+// percentage of at least 0.01%, that no address is used twice and that the
+// percentages don't add up to more than 100%.
+func ValidateMinerPayoutSplits(splits []MinerPayoutSplit) error {
+	if len(splits) > MaxMinerPayoutSplits {
+		return fmt.Errorf("the block reward can't be split across more than %v addresses", MaxMinerPayoutSplits)
+	}
+	seen := make(map[types.UnlockHash]struct{})
+	var total uint64
+	for _, split := range splits {
+		if split.UnlockHash == (types.UnlockHash{}) {
+			return errors.New("payout split is missing an address")
+		}
+		if _, exists := seen[split.UnlockHash]; exists {
+			return fmt.Errorf("address %v is used by more than one payout split", split.UnlockHash)
+		}
+		seen[split.UnlockHash] = struct{}{}
+		if split.Percentage > 100 || split.BasisPoints() == 0 {
+			return fmt.Errorf("percentage of payout split %v has to be between 0.01 and 100", split)
+		}
+		total += split.BasisPoints()
+	}
+	if total > 10000 {
+		return errors.New("percentages of the payout splits add up to more than 100")
+	}
+	return nil
+}
+
+// StratumStats contains statistics about the stratum server of the miner.
+type StratumStats struct {
+	// Address is the address the stratum server is listening on. It is empty
+	// if the server isn't running.
+	Address string `json:"address"`
+
+	// Workers is the number of connections which are subscribed to work.
+	Workers int `json:"workers"`
+
+	// AcceptedShares, RejectedShares and StaleShares count the shares which
+	// were submitted since the server was started. Stale shares are shares
+	// for jobs which are no longer known.
+	AcceptedShares uint64 `json:"acceptedshares"`
+	RejectedShares uint64 `json:"rejectedshares"`
+	StaleShares    uint64 `json:"staleshares"`
+
+	// BlocksFound is the number of shares which solved a block.
+	BlocksFound uint64 `json:"blocksfound"`
+}
+
 // BlockManager contains functions that can interface with external miners,
 // providing and receiving blocks that have experienced nonce grinding.
 type BlockManager interface {
@@ -30,6 +128,14 @@ type BlockManager interface {
 	// BlocksMined returns the number of blocks and stale blocks that have been
 	// mined using this miner.
 	BlocksMined() (goodBlocks, staleBlocks int)
+
+	// PayoutSplits returns the addresses the block reward is split across.
+	PayoutSplits() []MinerPayoutSplit
+
+	// SetPayoutSplits sets the addresses the block reward is split across.
+	// The part of the reward which isn't assigned to an address is paid to
+	// the wallet.
+	SetPayoutSplits([]MinerPayoutSplit) error
 }
 
 // CPUMiner provides access to a single-threaded cpu miner.
@@ -76,9 +182,20 @@ type TestMiner interface {
 	Miner
 }
 
+// StratumServer provides work to external mining software using the stratum
+// protocol.
+type StratumServer interface {
+	// StartStratum starts a stratum server listening on the given address.
+	StartStratum(addr string) error
+
+	// StratumStats returns statistics about the stratum server.
+	StratumStats() StratumStats
+}
+
 // The Miner interface provides access to mining features.
 type Miner interface {
 	BlockManager
 	CPUMiner
+	StratumServer
 	io.Closer
 }
@@ -15,11 +15,11 @@ var (
 	errLateHeader = errors.New("header is old, block could not be recovered")
 )
 
-// blockForWork returns a block that is ready for nonce grinding, including
-// correct miner payouts and a random transaction to prevent collisions and
-// overlapping work with other blocks being mined in parallel or for different
-// forks (during testing).
-func (m *Miner) blockForWork() types.Block {
+// blockTemplate returns a copy of the unsolved block with an updated timestamp
+// and correct miner payouts, which are split according to the payout splits.
+// The returned block shares its transactions with the unsolved block, so they
+// need to be copied before they are modified.
+func (m *Miner) blockTemplate() types.Block {
 	b := m.persist.UnsolvedBlock
 
 	// Update the timestamp.
@@ -32,10 +32,16 @@ func (m *Miner) blockForWork() types.Block {
 	if err != nil {
 		m.log.Println(err)
 	}
-	b.MinerPayouts = []types.SiacoinOutput{{
-		Value:      b.CalculateSubsidy(m.persist.Height + 1),
-		UnlockHash: m.persist.Address,
-	}}
+	b.MinerPayouts = m.minerPayouts(b.CalculateSubsidy(m.persist.Height + 1))
+	return b
+}
+
+// blockForWork returns a block that is ready for nonce grinding, including
+// correct miner payouts and a random transaction to prevent collisions and
+// overlapping work with other blocks being mined in parallel or for different
+// forks (during testing).
+func (m *Miner) blockForWork() types.Block {
+	b := m.blockTemplate()
 
 	// Add an arb-data txn to the block to create a unique merkle root.
 	randBytes := fastrand.Bytes(types.SpecifierLen)
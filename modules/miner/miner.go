@@ -104,6 +104,9 @@ type Miner struct {
 	mining   bool  // indicates if the miner is actually running
 	hashRate int64 // indicates hashes per second
 
+	// stratum is the stratum server, it is nil if the server isn't running.
+	stratum *stratumServer
+
 	// Utils
 	log        *persist.Logger
 	mu         sync.RWMutex
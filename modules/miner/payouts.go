@@ -0,0 +1,75 @@
+package miner
+
+import (
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// minerPayouts splits the subsidy of a block across the payout splits. The
+// part of the subsidy which isn't assigned to a split is paid to the miner's
+// address. If the splits cover the whole subsidy, the rounding remainder is
+// added to the last split instead. Payouts which would be zero are skipped,
+// because consensus doesn't allow them.
+func (m *Miner) minerPayouts(subsidy types.Currency) []types.SiacoinOutput {
+	var payouts []types.SiacoinOutput
+	var total uint64
+	remaining := subsidy
+	for _, split := range m.persist.PayoutSplits {
+		total += split.BasisPoints()
+		value := subsidy.Mul64(split.BasisPoints()).Div64(10000)
+		if value.IsZero() {
+			continue
+		}
+		payouts = append(payouts, types.SiacoinOutput{
+			Value:      value,
+			UnlockHash: split.UnlockHash,
+		})
+		remaining = remaining.Sub(value)
+	}
+	if remaining.IsZero() {
+		return payouts
+	}
+	if total == 10000 && len(payouts) > 0 {
+		last := &payouts[len(payouts)-1]
+		last.Value = last.Value.Add(remaining)
+		return payouts
+	}
+	return append(payouts, types.SiacoinOutput{
+		Value:      remaining,
+		UnlockHash: m.persist.Address,
+	})
+}
+
+// PayoutSplits returns the addresses the block reward is split across.
+func (m *Miner) PayoutSplits() []modules.MinerPayoutSplit {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]modules.MinerPayoutSplit(nil), m.persist.PayoutSplits...)
+}
+
+// SetPayoutSplits sets the addresses the block reward is split across. The
+// part of the reward which isn't assigned to an address is paid to the wallet.
+// New work uses the new splits right away.
+func (m *Miner) SetPayoutSplits(splits []modules.MinerPayoutSplit) error {
+	if err := m.tg.Add(); err != nil {
+		return err
+	}
+	defer m.tg.Done()
+	if err := modules.ValidateMinerPayoutSplits(splits); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.persist.PayoutSplits = append([]modules.MinerPayoutSplit(nil), splits...)
+	if err := m.saveSync(); err != nil {
+		return err
+	}
+	if m.sourceBlock != nil {
+		m.newSourceBlock()
+	}
+	if m.stratum != nil {
+		m.stratum.signalRefresh()
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package miner
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestMinerPayouts checks that the subsidy is split across the payout splits
+// and the miner's address without losing any siacoins.
+func TestMinerPayouts(t *testing.T) {
+	t.Parallel()
+	m := &Miner{}
+	m.persist.Address = types.UnlockHash{1}
+	operator, pool := types.UnlockHash{2}, types.UnlockHash{3}
+	subsidy := types.SiacoinPrecision.Mul64(300e3).Add64(7)
+
+	checkPayouts := func(payouts []types.SiacoinOutput, expected ...types.UnlockHash) {
+		t.Helper()
+		if len(payouts) != len(expected) {
+			t.Fatal("wrong number of payouts", payouts)
+		}
+		var total types.Currency
+		for i, payout := range payouts {
+			if payout.UnlockHash != expected[i] || payout.Value.IsZero() {
+				t.Fatal("wrong payout", i, payout)
+			}
+			total = total.Add(payout.Value)
+		}
+		if !total.Equals(subsidy) {
+			t.Fatal("payouts don't add up to the subsidy", total)
+		}
+	}
+
+	// Without splits, the miner's address receives everything.
+	checkPayouts(m.minerPayouts(subsidy), m.persist.Address)
+
+	// The rest of a partial split is paid to the miner's address.
+	m.persist.PayoutSplits = []modules.MinerPayoutSplit{{UnlockHash: operator, Percentage: 2.5}}
+	payouts := m.minerPayouts(subsidy)
+	checkPayouts(payouts, operator, m.persist.Address)
+	if !payouts[0].Value.Equals(subsidy.Mul64(250).Div64(10000)) {
+		t.Fatal("wrong operator payout", payouts[0].Value)
+	}
+
+	// If the splits cover the subsidy, the last split receives the rounding
+	// remainder.
+	m.persist.PayoutSplits = append(m.persist.PayoutSplits, modules.MinerPayoutSplit{UnlockHash: pool, Percentage: 97.5})
+	checkPayouts(m.minerPayouts(subsidy), operator, pool)
+
+	// Payouts which would be zero are skipped.
+	subsidy = types.NewCurrency64(10)
+	checkPayouts(m.minerPayouts(subsidy), pool)
+}
+
+// TestPayoutSplitsPersist checks that the payout splits are persisted in the
+// miner settings and that invalid splits aren't loaded.
+func TestPayoutSplitsPersist(t *testing.T) {
+	t.Parallel()
+	m := &Miner{persistDir: build.TempDir(modules.MinerDir, t.Name())}
+	if err := m.initPersist(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := m.tg.Stop(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	splits := []modules.MinerPayoutSplit{{UnlockHash: types.UnlockHash{2}, Percentage: 2.5}}
+	if err := m.SetPayoutSplits(splits); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetPayoutSplits([]modules.MinerPayoutSplit{{UnlockHash: types.UnlockHash{2}, Percentage: 101}}); err == nil {
+		t.Fatal("invalid splits shouldn't be accepted")
+	}
+
+	m.persist.PayoutSplits = nil
+	if err := m.load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.PayoutSplits(); len(got) != 1 || got[0] != splits[0] {
+		t.Fatal("splits weren't persisted", got)
+	}
+
+	// Invalid splits in the settings file are rejected.
+	m.persist.PayoutSplits = append(m.persist.PayoutSplits, splits[0])
+	if err := m.saveSync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.load(); err == nil {
+		t.Fatal("invalid splits shouldn't be loaded")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"gitlab.com/NebulousLabs/errors"
+
 	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/persist"
 	"go.sia.tech/siad/types"
@@ -32,6 +34,7 @@ type (
 		Address       types.UnlockHash
 		BlocksFound   []types.BlockID
 		UnsolvedBlock types.Block
+		PayoutSplits  []modules.MinerPayoutSplit
 	}
 )
 
@@ -78,7 +81,11 @@ func (m *Miner) initPersist() error {
 
 // load loads the miner persistence from disk.
 func (m *Miner) load() error {
-	return persist.LoadJSON(settingsMetadata, &m.persist, filepath.Join(m.persistDir, settingsFile))
+	err := persist.LoadJSON(settingsMetadata, &m.persist, filepath.Join(m.persistDir, settingsFile))
+	if err != nil {
+		return err
+	}
+	return errors.AddContext(modules.ValidateMinerPayoutSplits(m.persist.PayoutSplits), "invalid payout splits")
 }
 
 // saveSync saves the miner persistence to disk, and then syncs to disk.
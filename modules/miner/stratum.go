@@ -0,0 +1,758 @@
+package miner
+
+// stratum.go implements a stratum server, which external mining software can
+// use to receive work and submit shares without polling the /miner/header
+// endpoint of the API. The server speaks the line-delimited JSON-RPC dialect
+// used by Sia mining pools: the block is sent as a template whose last
+// transaction contains the extranonces in its arbitrary data, together with
+// the merkle branch which proves that transaction. The mining software builds
+// the header from the template and rolls the extranonce, the timestamp and
+// the nonce.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"math/big"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// stratumExtranonce1Size is the size of the extranonce which the server
+	// assigns to every connection.
+	stratumExtranonce1Size = 4
+
+	// stratumExtranonce2Size is the size of the extranonce which is rolled by
+	// the mining software.
+	stratumExtranonce2Size = 4
+
+	// stratumJobMemory is the number of jobs with the same parent which are
+	// remembered, so that shares for recent jobs are still accepted.
+	stratumJobMemory = 16
+
+	// stratumMaxMessageSize is the maximum size of a message sent by the
+	// mining software.
+	stratumMaxMessageSize = 1 << 14
+)
+
+// Error codes of the stratum protocol.
+const (
+	stratumErrOther          = 20
+	stratumErrJobNotFound    = 21
+	stratumErrDuplicateShare = 22
+	stratumErrLowDifficulty  = 23
+	stratumErrUnauthorized   = 24
+	stratumErrNotSubscribed  = 25
+)
+
+var (
+	// stratumDifficulty is the share difficulty which is assigned to new
+	// connections. The mining software can suggest a different difficulty.
+	stratumDifficulty = build.Select(build.Var{
+		Standard: float64(1024),
+		Testnet:  float64(1024),
+		Dev:      float64(1),
+		Testing:  float64(1),
+	}).(float64)
+
+	// stratumIdleTimeout is the amount of time after which a connection which
+	// hasn't sent any messages is closed.
+	stratumIdleTimeout = build.Select(build.Var{
+		Standard: 10 * time.Minute,
+		Testnet:  10 * time.Minute,
+		Dev:      5 * time.Minute,
+		Testing:  30 * time.Second,
+	}).(time.Duration)
+
+	// stratumDiff1Target is the share target of difficulty 1.
+	stratumDiff1Target = types.Target{0, 0, 0, 0, 0xff, 0xff}
+
+	errStratumDuplicateShare = errors.New("duplicate share")
+	errStratumInvalidNonce   = errors.New("share does not meet nonce requirements")
+	errStratumInvalidParams  = errors.New("invalid parameters")
+	errStratumInvalidTime    = errors.New("share timestamp is out of range")
+	errStratumJobNotFound    = errors.New("job not found")
+	errStratumLowDifficulty  = errors.New("low difficulty share")
+	errStratumNotSubscribed  = errors.New("not subscribed")
+	errStratumRunning        = errors.New("stratum server is already running")
+	errStratumUnauthorized   = errors.New("unauthorized worker")
+	errStratumUnknownMethod  = errors.New("unknown method")
+)
+
+type (
+	// stratumJob is a block template which is sent to the mining software.
+	// The last transaction of the block contains the extranonces at the end
+	// of its arbitrary data, coinb1 and coinb2 are the encoding of that
+	// transaction before and after the extranonces.
+	stratumJob struct {
+		id     string
+		block  types.Block
+		height types.BlockHeight
+		target types.Target
+		coinb1 []byte
+		coinb2 []byte
+		branch []crypto.Hash
+
+		// shares contains the ids of the submitted shares to detect
+		// duplicates. It is protected by the mutex of the server.
+		shares map[string]struct{}
+	}
+
+	// stratumConn is a connection of the mining software.
+	stratumConn struct {
+		conn        net.Conn
+		extranonce1 [stratumExtranonce1Size]byte
+
+		// mu protects the fields below and the writes to the connection.
+		mu         sync.Mutex
+		enc        *json.Encoder
+		difficulty float64
+		subscribed bool
+		worker     string
+	}
+
+	// stratumServer hands out jobs to the connections of the mining software
+	// and submits the shares which solve a block.
+	stratumServer struct {
+		m        *Miner
+		listener net.Listener
+		refresh  chan struct{}
+
+		mu          sync.Mutex
+		conns       map[*stratumConn]struct{}
+		extranonce1 uint32
+		job         *stratumJob
+		jobs        map[string]*stratumJob
+		jobOrder    []string
+		jobCounter  uint64
+
+		accepted uint64
+		rejected uint64
+		stale    uint64
+		blocks   uint64
+	}
+
+	// stratumRequest is a request sent by the mining software.
+	stratumRequest struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+
+	// stratumResponse is the response to a request.
+	stratumResponse struct {
+		ID     json.RawMessage `json:"id"`
+		Result interface{}     `json:"result"`
+		Error  interface{}     `json:"error"`
+	}
+
+	// stratumNotification is a message sent by the server without a request.
+	stratumNotification struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params []interface{}   `json:"params"`
+	}
+)
+
+// stratumErrorCode returns the error code of the stratum protocol which
+// corresponds to the error.
+func stratumErrorCode(err error) int {
+	switch {
+	case errors.Contains(err, errStratumJobNotFound):
+		return stratumErrJobNotFound
+	case errors.Contains(err, errStratumDuplicateShare):
+		return stratumErrDuplicateShare
+	case errors.Contains(err, errStratumLowDifficulty):
+		return stratumErrLowDifficulty
+	case errors.Contains(err, errStratumUnauthorized):
+		return stratumErrUnauthorized
+	case errors.Contains(err, errStratumNotSubscribed):
+		return stratumErrNotSubscribed
+	default:
+		return stratumErrOther
+	}
+}
+
+// stratumMerkleRoot returns the merkle root of a block, given the encoding of
+// its last transaction and the merkle branch of that transaction. All
+// siblings of the last leaf are on its left.
+func stratumMerkleRoot(coinbase []byte, branch []crypto.Hash) crypto.Hash {
+	root := crypto.HashBytes(append([]byte{0x00}, coinbase...))
+	for _, h := range branch {
+		buf := make([]byte, 0, 1+2*crypto.HashSize)
+		buf = append(buf, 0x01)
+		buf = append(buf, h[:]...)
+		buf = append(buf, root[:]...)
+		root = crypto.HashBytes(buf)
+	}
+	return root
+}
+
+// stratumShareTarget returns the share target for the given difficulty.
+func stratumShareTarget(difficulty float64) types.Target {
+	return types.RatToTarget(new(big.Rat).Quo(stratumDiff1Target.Rat(), new(big.Rat).SetFloat64(difficulty)))
+}
+
+// newStratumJob creates a job from a block whose last transaction has room for
+// the extranonces at the end of its arbitrary data.
+func newStratumJob(id string, b types.Block, height types.BlockHeight, target types.Target) *stratumJob {
+	// The arbitrary data is the last field of the transaction, only the
+	// signatures follow it.
+	last := b.Transactions[len(b.Transactions)-1]
+	enc := encoding.Marshal(last)
+	split := len(enc) - len(encoding.Marshal(last.TransactionSignatures)) - stratumExtranonce1Size - stratumExtranonce2Size
+
+	// Build the merkle proof of the last transaction. The first element of the
+	// proof is the hash of the transaction itself.
+	tree := crypto.NewTree()
+	if err := tree.SetIndex(uint64(len(b.MinerPayouts) + len(b.Transactions) - 1)); err != nil {
+		build.Critical(err)
+	}
+	var buf bytes.Buffer
+	e := encoding.NewEncoder(&buf)
+	for _, payout := range b.MinerPayouts {
+		payout.MarshalSia(e)
+		tree.Push(buf.Bytes())
+		buf.Reset()
+	}
+	for _, txn := range b.Transactions {
+		txn.MarshalSia(e)
+		tree.Push(buf.Bytes())
+		buf.Reset()
+	}
+	_, _, proof, _, _ := tree.Prove()
+	branch := make([]crypto.Hash, len(proof)-1)
+	for i := range branch {
+		branch[i] = crypto.Hash(proof[i+1])
+	}
+
+	return &stratumJob{
+		id:     id,
+		block:  b,
+		height: height,
+		target: target,
+		coinb1: enc[:split],
+		coinb2: enc[split+stratumExtranonce1Size+stratumExtranonce2Size:],
+		branch: branch,
+		shares: make(map[string]struct{}),
+	}
+}
+
+// header returns the header of the job's block for the given extranonce,
+// timestamp and nonce.
+func (job *stratumJob) header(extranonce []byte, ts types.Timestamp, nonce types.BlockNonce) types.BlockHeader {
+	coinbase := make([]byte, 0, len(job.coinb1)+len(extranonce)+len(job.coinb2))
+	coinbase = append(coinbase, job.coinb1...)
+	coinbase = append(coinbase, extranonce...)
+	coinbase = append(coinbase, job.coinb2...)
+	return types.BlockHeader{
+		ParentID:      job.block.ParentID,
+		Nonce:         nonce,
+		Timestamp:     ts,
+		MerkleRoot:    stratumMerkleRoot(coinbase, job.branch),
+		PrevMainBlock: job.block.PrevMainBlock(),
+	}
+}
+
+// solvedBlock returns the job's block for the given extranonce, timestamp and
+// nonce.
+func (job *stratumJob) solvedBlock(extranonce []byte, ts types.Timestamp, nonce types.BlockNonce) types.Block {
+	b := job.block
+	b.Nonce = nonce
+	b.Timestamp = ts
+
+	// The transactions are shared with the job, so they need to be copied.
+	txns := make([]types.Transaction, len(b.Transactions))
+	copy(txns, b.Transactions)
+	last := &txns[len(txns)-1]
+	arbData := append([]byte(nil), last.ArbitraryData[0]...)
+	copy(arbData[len(arbData)-len(extranonce):], extranonce)
+	last.ArbitraryData = [][]byte{arbData}
+	b.Transactions = txns
+	return b
+}
+
+// params returns the parameters of the mining.notify message for the job.
+func (job *stratumJob) params(clean bool) []interface{} {
+	branch := make([]string, len(job.branch))
+	for i, h := range job.branch {
+		branch[i] = hex.EncodeToString(h[:])
+	}
+	var ntime [8]byte
+	binary.LittleEndian.PutUint64(ntime[:], uint64(job.block.Timestamp))
+	prevMainBlock := job.block.PrevMainBlock()
+	return []interface{}{
+		job.id,
+		hex.EncodeToString(job.block.ParentID[:]),
+		hex.EncodeToString(job.coinb1),
+		hex.EncodeToString(job.coinb2),
+		branch,
+		"",
+		hex.EncodeToString(job.target[:]),
+		hex.EncodeToString(ntime[:]),
+		clean,
+		hex.EncodeToString(prevMainBlock[:]),
+	}
+}
+
+// write sends a message to the mining software.
+func (sc *stratumConn) write(msg interface{}) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if err := sc.conn.SetWriteDeadline(time.Now().Add(stratumIdleTimeout)); err != nil {
+		return err
+	}
+	return sc.enc.Encode(msg)
+}
+
+// notify sends a notification to the mining software.
+func (sc *stratumConn) notify(method string, params ...interface{}) error {
+	return sc.write(stratumNotification{
+		Method: method,
+		Params: params,
+	})
+}
+
+// newStratumServer returns a stratum server which accepts connections from the
+// listener.
+func newStratumServer(m *Miner, l net.Listener) *stratumServer {
+	return &stratumServer{
+		m:           m,
+		listener:    l,
+		refresh:     make(chan struct{}, 1),
+		conns:       make(map[*stratumConn]struct{}),
+		extranonce1: uint32(fastrand.Uint64n(math.MaxUint32)),
+		jobs:        make(map[string]*stratumJob),
+	}
+}
+
+// signalRefresh signals the server to send a new job to the mining software.
+func (s *stratumServer) signalRefresh() {
+	select {
+	case s.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// managedClose closes the listener and all connections.
+func (s *stratumServer) managedClose() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sc := range s.conns {
+		err = errors.Compose(err, sc.conn.Close())
+	}
+	return err
+}
+
+// managedStats returns statistics about the server.
+func (s *stratumServer) managedStats() modules.StratumStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := modules.StratumStats{
+		Address:        s.listener.Addr().String(),
+		AcceptedShares: s.accepted,
+		RejectedShares: s.rejected,
+		StaleShares:    s.stale,
+		BlocksFound:    s.blocks,
+	}
+	for sc := range s.conns {
+		sc.mu.Lock()
+		if sc.subscribed {
+			stats.Workers++
+		}
+		sc.mu.Unlock()
+	}
+	return stats
+}
+
+// addJob adds a job to the server and makes it the current job. If the job
+// has a new parent, the previous jobs are forgotten. The caller must hold the
+// lock.
+func (s *stratumServer) addJob(job *stratumJob) (clean bool) {
+	clean = s.job == nil || s.job.block.ParentID != job.block.ParentID
+	if clean {
+		s.jobs = make(map[string]*stratumJob)
+		s.jobOrder = s.jobOrder[:0]
+	}
+	if len(s.jobOrder) == stratumJobMemory {
+		delete(s.jobs, s.jobOrder[0])
+		s.jobOrder = s.jobOrder[1:]
+	}
+	s.jobs[job.id] = job
+	s.jobOrder = append(s.jobOrder, job.id)
+	s.job = job
+	return clean
+}
+
+// managedRefreshJob creates a new job from the miner's unsolved block and
+// sends it to the mining software.
+func (s *stratumServer) managedRefreshJob() error {
+	m := s.m
+	m.mu.Lock()
+	unlocked, err := m.wallet.Unlocked()
+	if err != nil || !unlocked {
+		m.mu.Unlock()
+		return errors.Compose(err, modules.ErrLockedWallet)
+	}
+	if err := m.checkAddress(); err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	b := m.blockTemplate()
+	height := m.persist.Height + 1
+	target := m.persist.Target
+	m.mu.Unlock()
+
+	// Add a transaction with room for the extranonces. The random bytes
+	// prevent overlapping work between jobs.
+	arbData := append(modules.PrefixNonSia[:], fastrand.Bytes(8)...)
+	arbData = append(arbData, make([]byte, stratumExtranonce1Size+stratumExtranonce2Size)...)
+	txns := make([]types.Transaction, len(b.Transactions), len(b.Transactions)+1)
+	copy(txns, b.Transactions)
+	b.Transactions = append(txns, types.Transaction{
+		ArbitraryData: [][]byte{arbData},
+	})
+
+	s.mu.Lock()
+	s.jobCounter++
+	job := newStratumJob(strconv.FormatUint(s.jobCounter, 16), b, height, target)
+	clean := s.addJob(job)
+	var conns []*stratumConn
+	for sc := range s.conns {
+		conns = append(conns, sc)
+	}
+	s.mu.Unlock()
+
+	params := job.params(clean)
+	for _, sc := range conns {
+		sc.mu.Lock()
+		subscribed := sc.subscribed
+		sc.mu.Unlock()
+		if !subscribed {
+			continue
+		}
+		if err := sc.notify("mining.notify", params...); err != nil {
+			m.log.Debugln("Closing stratum connection after failed notification:", err)
+			sc.conn.Close()
+		}
+	}
+	return nil
+}
+
+// managedSubmitShare checks a share submitted by the mining software and
+// submits the block if the share solves it.
+func (s *stratumServer) managedSubmitShare(sc *stratumConn, jobID string, extranonce2 []byte, ts types.Timestamp, nonce types.BlockNonce) error {
+	if len(extranonce2) != stratumExtranonce2Size {
+		return errStratumInvalidParams
+	}
+	sc.mu.Lock()
+	extranonce := append(sc.extranonce1[:], extranonce2...)
+	shareTarget := stratumShareTarget(sc.difficulty)
+	worker := sc.worker
+	sc.mu.Unlock()
+
+	s.mu.Lock()
+	job, exists := s.jobs[jobID]
+	if !exists {
+		s.stale++
+	}
+	s.mu.Unlock()
+	if !exists {
+		return errStratumJobNotFound
+	}
+
+	// Check the share.
+	id := job.header(extranonce, ts, nonce).ID()
+	err := func() error {
+		if ts < job.block.Timestamp || ts > types.CurrentTimestamp()+types.FutureThreshold {
+			return errStratumInvalidTime
+		}
+		if job.height >= types.ASICHardforkHeight && binary.LittleEndian.Uint64(nonce[:])%types.ASICHardforkFactor != 0 {
+			return errStratumInvalidNonce
+		}
+		// Shares which solve a block are always accepted.
+		if shareTarget.Cmp(job.target) < 0 {
+			shareTarget = job.target
+		}
+		if bytes.Compare(shareTarget[:], id[:]) < 0 {
+			return errStratumLowDifficulty
+		}
+		return nil
+	}()
+	s.mu.Lock()
+	if _, exists := job.shares[string(id[:])]; exists && err == nil {
+		err = errStratumDuplicateShare
+	}
+	if err != nil {
+		s.rejected++
+	} else {
+		job.shares[string(id[:])] = struct{}{}
+		s.accepted++
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Submit the block if the share solves it.
+	if bytes.Compare(job.target[:], id[:]) < 0 {
+		return nil
+	}
+	if err := s.m.managedSubmitBlock(job.solvedBlock(extranonce, ts, nonce)); err != nil {
+		s.m.log.Println("ERROR: block found by stratum worker was not accepted:", err)
+		return nil
+	}
+	s.mu.Lock()
+	s.blocks++
+	s.mu.Unlock()
+	s.m.log.Println("Block", id, "found by stratum worker", worker)
+	return nil
+}
+
+// managedHandleRequest handles a single request of the mining software and
+// returns the result.
+func (s *stratumServer) managedHandleRequest(sc *stratumConn, req stratumRequest) (interface{}, error) {
+	switch req.Method {
+	case "mining.subscribe":
+		sc.mu.Lock()
+		sc.subscribed = true
+		sc.mu.Unlock()
+		subID := hex.EncodeToString(sc.extranonce1[:])
+		return []interface{}{
+			[][]string{{"mining.set_difficulty", subID}, {"mining.notify", subID}},
+			subID,
+			stratumExtranonce2Size,
+		}, nil
+
+	case "mining.authorize":
+		var params []string
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params) == 0 {
+			return nil, errStratumInvalidParams
+		}
+		sc.mu.Lock()
+		sc.worker = params[0]
+		sc.mu.Unlock()
+		return true, nil
+
+	case "mining.suggest_difficulty":
+		var params []float64
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params) == 0 {
+			return nil, errStratumInvalidParams
+		}
+		if params[0] <= 0 || math.IsInf(params[0], 0) || math.IsNaN(params[0]) {
+			return nil, errStratumInvalidParams
+		}
+		sc.mu.Lock()
+		sc.difficulty = params[0]
+		sc.mu.Unlock()
+		return true, nil
+
+	case "mining.submit":
+		sc.mu.Lock()
+		subscribed, worker := sc.subscribed, sc.worker
+		sc.mu.Unlock()
+		if !subscribed {
+			return nil, errStratumNotSubscribed
+		}
+		if worker == "" {
+			return nil, errStratumUnauthorized
+		}
+		var params []string
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params) < 5 {
+			return nil, errStratumInvalidParams
+		}
+		extranonce2, err1 := hex.DecodeString(params[2])
+		ntime, err2 := hex.DecodeString(params[3])
+		nonceBytes, err3 := hex.DecodeString(params[4])
+		if err := errors.Compose(err1, err2, err3); err != nil || len(ntime) != 8 || len(nonceBytes) != 8 {
+			return nil, errStratumInvalidParams
+		}
+		var nonce types.BlockNonce
+		copy(nonce[:], nonceBytes)
+		ts := types.Timestamp(binary.LittleEndian.Uint64(ntime))
+		if err := s.managedSubmitShare(sc, params[1], extranonce2, ts, nonce); err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	default:
+		return nil, errStratumUnknownMethod
+	}
+}
+
+// managedSendWork sends the difficulty and the current job to the mining
+// software.
+func (s *stratumServer) managedSendWork(sc *stratumConn) error {
+	sc.mu.Lock()
+	difficulty := sc.difficulty
+	sc.mu.Unlock()
+	if err := sc.notify("mining.set_difficulty", difficulty); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	job := s.job
+	s.mu.Unlock()
+	if job == nil {
+		return nil
+	}
+	return sc.notify("mining.notify", job.params(true)...)
+}
+
+// threadedHandleConn handles the requests of a connection until it is closed.
+func (s *stratumServer) threadedHandleConn(conn net.Conn) {
+	if err := s.m.tg.Add(); err != nil {
+		conn.Close()
+		return
+	}
+	defer s.m.tg.Done()
+
+	sc := &stratumConn{
+		conn:       conn,
+		enc:        json.NewEncoder(conn),
+		difficulty: stratumDifficulty,
+	}
+	s.mu.Lock()
+	s.extranonce1++
+	binary.BigEndian.PutUint32(sc.extranonce1[:], s.extranonce1)
+	s.conns[sc] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, sc)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 1024), stratumMaxMessageSize)
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(stratumIdleTimeout)); err != nil {
+			return
+		}
+		if !scanner.Scan() {
+			s.m.log.Debugln("Closing stratum connection:", scanner.Err())
+			return
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req stratumRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.m.log.Debugln("Closing stratum connection after malformed request:", err)
+			return
+		}
+
+		result, err := s.managedHandleRequest(sc, req)
+		resp := stratumResponse{ID: req.ID, Result: result}
+		if err != nil {
+			resp.Result = nil
+			resp.Error = []interface{}{stratumErrorCode(err), err.Error(), nil}
+		}
+		if err := sc.write(resp); err != nil {
+			return
+		}
+		if err == nil && (req.Method == "mining.subscribe" || req.Method == "mining.suggest_difficulty") {
+			if err := s.managedSendWork(sc); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// threadedListen accepts connections until the listener is closed.
+func (s *stratumServer) threadedListen() {
+	if err := s.m.tg.Add(); err != nil {
+		return
+	}
+	defer s.m.tg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			s.m.log.Debugln("Closing stratum listener:", err)
+			return
+		}
+		go s.threadedHandleConn(conn)
+	}
+}
+
+// threadedRefreshJobs creates a new job whenever the miner has a new parent
+// block and at least every MaxSourceBlockAge, so that new transactions are
+// included in the work.
+func (s *stratumServer) threadedRefreshJobs() {
+	if err := s.m.tg.Add(); err != nil {
+		return
+	}
+	defer s.m.tg.Done()
+
+	for {
+		if err := s.managedRefreshJob(); err != nil {
+			s.m.log.Debugln("Unable to create stratum job:", err)
+		}
+		select {
+		case <-s.m.tg.StopChan():
+			return
+		case <-s.refresh:
+		case <-time.After(MaxSourceBlockAge):
+		}
+	}
+}
+
+// StartStratum starts a stratum server listening on the given address, which
+// external mining software can use to receive work and submit shares.
+func (m *Miner) StartStratum(addr string) error {
+	if err := m.tg.Add(); err != nil {
+		return err
+	}
+	defer m.tg.Done()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stratum != nil {
+		return errStratumRunning
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.AddContext(err, "unable to start stratum server")
+	}
+	m.stratum = newStratumServer(m, l)
+	m.tg.OnStop(m.stratum.managedClose)
+
+	go m.stratum.threadedListen()
+	go m.stratum.threadedRefreshJobs()
+	m.log.Println("Stratum server listening on", l.Addr())
+	return nil
+}
+
+// StratumStats returns statistics about the stratum server.
+func (m *Miner) StratumStats() modules.StratumStats {
+	if err := m.tg.Add(); err != nil {
+		return modules.StratumStats{}
+	}
+	defer m.tg.Done()
+
+	m.mu.RLock()
+	s := m.stratum
+	m.mu.RUnlock()
+	if s == nil {
+		return modules.StratumStats{}
+	}
+	return s.managedStats()
+}
@@ -0,0 +1,215 @@
+package miner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+// newTestStratumJob returns a job for a block with the given number of
+// transactions, which never solves the block.
+func newTestStratumJob(id string, parent types.BlockID, numTxns int) *stratumJob {
+	b := types.Block{
+		ParentID:  parent,
+		Timestamp: types.CurrentTimestamp(),
+		MinerPayouts: []types.SiacoinOutput{{
+			Value:      types.CalculateCoinbase(1),
+			UnlockHash: types.UnlockHash{1},
+		}},
+	}
+	for i := 0; i < numTxns; i++ {
+		b.Transactions = append(b.Transactions, types.Transaction{
+			ArbitraryData: [][]byte{fastrand.Bytes(fastrand.Intn(100))},
+		})
+	}
+	arbData := append(modules.PrefixNonSia[:], make([]byte, 8+stratumExtranonce1Size+stratumExtranonce2Size)...)
+	b.Transactions = append(b.Transactions, types.Transaction{
+		ArbitraryData: [][]byte{arbData},
+	})
+	return newStratumJob(id, b, 1, types.Target{})
+}
+
+// newTestStratumServer returns a stratum server whose miner has no
+// dependencies. The server doesn't accept connections from its listener.
+func newTestStratumServer(t *testing.T) *stratumServer {
+	log, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		l.Close()
+	})
+	return newStratumServer(&Miner{log: log}, l)
+}
+
+// TestStratumJob checks that the header built from a job by the mining
+// software matches the header of the solved block.
+func TestStratumJob(t *testing.T) {
+	t.Parallel()
+	for numTxns := 0; numTxns < 10; numTxns++ {
+		job := newTestStratumJob("1", types.BlockID{1}, numTxns)
+		extranonce := fastrand.Bytes(stratumExtranonce1Size + stratumExtranonce2Size)
+		var nonce types.BlockNonce
+		fastrand.Read(nonce[:])
+		ts := job.block.Timestamp + 1
+
+		b := job.solvedBlock(extranonce, ts, nonce)
+		if job.header(extranonce, ts, nonce) != b.Header() {
+			t.Fatalf("header doesn't match block with %v transactions", numTxns)
+		}
+		// The job's block must not be modified.
+		if job.block.Nonce == nonce || job.block.ID() == b.ID() {
+			t.Fatal("job's block was modified")
+		}
+	}
+}
+
+// TestStratumSubmitShare probes the checks of submitted shares.
+func TestStratumSubmitShare(t *testing.T) {
+	t.Parallel()
+	s := newTestStratumServer(t)
+	job := newTestStratumJob("1", types.BlockID{1}, 2)
+	if !s.addJob(job) {
+		t.Fatal("first job should be clean")
+	}
+	sc := &stratumConn{difficulty: 1e-12}
+	extranonce2 := []byte{1, 2, 3, 4}
+	ts := job.block.Timestamp
+
+	if err := s.managedSubmitShare(sc, "1", extranonce2, ts, types.BlockNonce{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.managedSubmitShare(sc, "1", extranonce2, ts, types.BlockNonce{}); err != errStratumDuplicateShare {
+		t.Fatal("expected errStratumDuplicateShare but got", err)
+	}
+	if err := s.managedSubmitShare(sc, "2", extranonce2, ts, types.BlockNonce{}); err != errStratumJobNotFound {
+		t.Fatal("expected errStratumJobNotFound but got", err)
+	}
+	if err := s.managedSubmitShare(sc, "1", extranonce2, ts-1, types.BlockNonce{}); err != errStratumInvalidTime {
+		t.Fatal("expected errStratumInvalidTime but got", err)
+	}
+	if err := s.managedSubmitShare(sc, "1", extranonce2[:2], ts, types.BlockNonce{}); err != errStratumInvalidParams {
+		t.Fatal("expected errStratumInvalidParams but got", err)
+	}
+	sc.difficulty = 1e30
+	if err := s.managedSubmitShare(sc, "1", extranonce2, ts, types.BlockNonce{1}); err != errStratumLowDifficulty {
+		t.Fatal("expected errStratumLowDifficulty but got", err)
+	}
+
+	stats := s.managedStats()
+	if stats.AcceptedShares != 1 || stats.RejectedShares != 3 || stats.StaleShares != 1 || stats.BlocksFound != 0 {
+		t.Fatalf("wrong stats %+v", stats)
+	}
+
+	// Jobs with the same parent are kept, a new parent forgets them.
+	if s.addJob(newTestStratumJob("2", types.BlockID{1}, 2)) {
+		t.Fatal("job with the same parent shouldn't be clean")
+	}
+	if _, exists := s.jobs["1"]; !exists {
+		t.Fatal("job with the same parent was forgotten")
+	}
+	if !s.addJob(newTestStratumJob("3", types.BlockID{2}, 2)) {
+		t.Fatal("job with a new parent should be clean")
+	}
+	if _, exists := s.jobs["1"]; exists || len(s.jobs) != 1 {
+		t.Fatal("jobs of the old parent weren't forgotten")
+	}
+}
+
+// TestStratumProtocol checks the messages exchanged with the mining software.
+func TestStratumProtocol(t *testing.T) {
+	t.Parallel()
+	s := newTestStratumServer(t)
+	job := newTestStratumJob("1", types.BlockID{1}, 2)
+	s.addJob(job)
+
+	server, client := net.Pipe()
+	defer client.Close()
+	go s.threadedHandleConn(server)
+	enc := json.NewEncoder(client)
+	scanner := bufio.NewScanner(client)
+	read := func() map[string]interface{} {
+		if !scanner.Scan() {
+			t.Fatal("connection closed:", scanner.Err())
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+	request := func(id int, method string, params ...interface{}) map[string]interface{} {
+		if err := enc.Encode(map[string]interface{}{"id": id, "method": method, "params": params}); err != nil {
+			t.Fatal(err)
+		}
+		resp := read()
+		if resp["id"] != float64(id) {
+			t.Fatal("wrong response id", resp)
+		}
+		return resp
+	}
+
+	// Shares can't be submitted before subscribing.
+	resp := request(1, "mining.submit", "worker", "1", "00000000", "0000000000000000", "0000000000000000")
+	if errParams, ok := resp["error"].([]interface{}); !ok || errParams[0] != float64(stratumErrNotSubscribed) {
+		t.Fatal("expected not subscribed error", resp)
+	}
+
+	// Subscribing returns the extranonce and sends the difficulty and job.
+	resp = request(2, "mining.subscribe")
+	result := resp["result"].([]interface{})
+	extranonce1, err := hex.DecodeString(result[1].(string))
+	if err != nil || len(extranonce1) != stratumExtranonce1Size || result[2] != float64(stratumExtranonce2Size) {
+		t.Fatal("wrong subscription", resp)
+	}
+	if msg := read(); msg["method"] != "mining.set_difficulty" || msg["params"].([]interface{})[0] != stratumDifficulty {
+		t.Fatal("expected difficulty", msg)
+	}
+	msg := read()
+	params := msg["params"].([]interface{})
+	if msg["method"] != "mining.notify" || params[0] != job.id || params[1] != hex.EncodeToString(job.block.ParentID[:]) || params[8] != true {
+		t.Fatal("expected job", msg)
+	}
+
+	// Lower the difficulty so that every share is accepted.
+	if resp := request(3, "mining.suggest_difficulty", 1e-12); resp["result"] != true {
+		t.Fatal("difficulty wasn't accepted", resp)
+	}
+	if msg := read(); msg["method"] != "mining.set_difficulty" {
+		t.Fatal("expected difficulty", msg)
+	}
+	if msg := read(); msg["method"] != "mining.notify" {
+		t.Fatal("expected job", msg)
+	}
+
+	// Shares can't be submitted before authorizing.
+	var ntime [8]byte
+	binary.LittleEndian.PutUint64(ntime[:], uint64(job.block.Timestamp))
+	resp = request(4, "mining.submit", "worker", "1", "01020304", hex.EncodeToString(ntime[:]), "0000000000000000")
+	if errParams, ok := resp["error"].([]interface{}); !ok || errParams[0] != float64(stratumErrUnauthorized) {
+		t.Fatal("expected unauthorized error", resp)
+	}
+	if resp := request(5, "mining.authorize", "worker", "x"); resp["result"] != true {
+		t.Fatal("worker wasn't authorized", resp)
+	}
+	if resp := request(6, "mining.submit", "worker", "1", "01020304", hex.EncodeToString(ntime[:]), "0000000000000000"); resp["result"] != true {
+		t.Fatal("share wasn't accepted", resp)
+	}
+	if stats := s.managedStats(); stats.Workers != 1 || stats.AcceptedShares != 1 {
+		t.Fatalf("wrong stats %+v", stats)
+	}
+}
@@ -266,6 +266,9 @@ func (m *Miner) ProcessConsensusChange(cc modules.ConsensusChange) {
 	// the stale rate as low as possible.
 	if cc.Synced {
 		m.newSourceBlock()
+		if m.stratum != nil {
+			m.stratum.signalRefresh()
+		}
 	}
 	m.persist.RecentChange = cc.ID
 }
@@ -0,0 +1,60 @@
+package modules
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/types"
+)
+
+// TestMinerPayoutSplits tests parsing and validating payout splits.
+func TestMinerPayoutSplits(t *testing.T) {
+	addr := types.UnlockHash{1}
+	split, err := ParseMinerPayoutSplit(addr.String() + ":2.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if split.UnlockHash != addr || split.Percentage != 2.5 || split.BasisPoints() != 250 {
+		t.Fatal("wrong split", split)
+	}
+	if parsed, err := ParseMinerPayoutSplit(split.String()); err != nil || parsed != split {
+		t.Fatal("split doesn't round trip", parsed, err)
+	}
+	for _, s := range []string{"", addr.String(), addr.String() + ":", "abc:2.5", addr.String() + ":2:5"} {
+		if _, err := ParseMinerPayoutSplit(s); err == nil {
+			t.Fatalf("parsing '%v' should fail", s)
+		}
+	}
+
+	// Check the validation.
+	other := types.UnlockHash{2}
+	valid := [][]MinerPayoutSplit{
+		nil,
+		{{UnlockHash: addr, Percentage: 100}},
+		{{UnlockHash: addr, Percentage: 0.01}, {UnlockHash: other, Percentage: 99.99}},
+	}
+	for _, splits := range valid {
+		if err := ValidateMinerPayoutSplits(splits); err != nil {
+			t.Fatal(splits, err)
+		}
+	}
+	invalid := [][]MinerPayoutSplit{
+		{{Percentage: 10}},
+		{{UnlockHash: addr, Percentage: 0}},
+		{{UnlockHash: addr, Percentage: 0.001}},
+		{{UnlockHash: addr, Percentage: 100.5}},
+		{{UnlockHash: addr, Percentage: 10}, {UnlockHash: addr, Percentage: 10}},
+		{{UnlockHash: addr, Percentage: 60}, {UnlockHash: other, Percentage: 40.01}},
+	}
+	for _, splits := range invalid {
+		if err := ValidateMinerPayoutSplits(splits); err == nil {
+			t.Fatal("splits should be invalid", splits)
+		}
+	}
+	many := make([]MinerPayoutSplit, MaxMinerPayoutSplits+1)
+	for i := range many {
+		many[i] = MinerPayoutSplit{UnlockHash: types.UnlockHash{byte(i + 1)}, Percentage: 1}
+	}
+	if err := ValidateMinerPayoutSplits(many); err == nil {
+		t.Fatal("too many splits should be invalid")
+	}
+}
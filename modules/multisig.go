@@ -0,0 +1,115 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// ErrMultisigMismatch is returned when merging the signatures of
+	// transactions which don't spend and create the same outputs.
+	ErrMultisigMismatch = errors.New("transactions differ in more than their signatures")
+
+	// ErrNoTransactions is returned when merging the signatures of an empty
+	// set of transactions.
+	ErrNoTransactions = errors.New("no transactions provided")
+)
+
+// MultisigUnlockConditions returns the unlock conditions of an address which
+// can be spent with the signatures of 'required' of the provided public keys.
+func MultisigUnlockConditions(required uint64, pks []types.SiaPublicKey) (types.UnlockConditions, error) {
+	if len(pks) < 2 {
+		return types.UnlockConditions{}, errors.New("a multisig address needs at least two public keys")
+	}
+	if required == 0 || required > uint64(len(pks)) {
+		return types.UnlockConditions{}, fmt.Errorf("the number of required signatures must be between 1 and %v", len(pks))
+	}
+	seen := make(map[string]struct{})
+	for _, pk := range pks {
+		if pk.Algorithm != types.SignatureEd25519 || len(pk.Key) != crypto.PublicKeySize {
+			return types.UnlockConditions{}, fmt.Errorf("public key %v is not an ed25519 key", pk)
+		}
+		if _, exists := seen[pk.String()]; exists {
+			return types.UnlockConditions{}, fmt.Errorf("public key %v is used more than once", pk)
+		}
+		seen[pk.String()] = struct{}{}
+	}
+	return types.UnlockConditions{
+		PublicKeys:         append([]types.SiaPublicKey(nil), pks...),
+		SignaturesRequired: required,
+	}, nil
+}
+
+// inputUnlockConditions returns the unlock conditions of the siacoin and
+// siafund inputs of a transaction by their parent ids.
+func inputUnlockConditions(txn types.Transaction) map[crypto.Hash]types.UnlockConditions {
+	ucs := make(map[crypto.Hash]types.UnlockConditions)
+	for _, sci := range txn.SiacoinInputs {
+		ucs[crypto.Hash(sci.ParentID)] = sci.UnlockConditions
+	}
+	for _, sfi := range txn.SiafundInputs {
+		ucs[crypto.Hash(sfi.ParentID)] = sfi.UnlockConditions
+	}
+	return ucs
+}
+
+// MissingSignatures returns the number of signatures the siacoin and siafund
+// inputs of the transaction still need before it can be broadcast.
+func MissingSignatures(txn types.Transaction) uint64 {
+	signatures := make(map[crypto.Hash]uint64)
+	for _, sig := range txn.TransactionSignatures {
+		signatures[sig.ParentID]++
+	}
+	var missing uint64
+	for id, uc := range inputUnlockConditions(txn) {
+		if signatures[id] < uc.SignaturesRequired {
+			missing += uc.SignaturesRequired - signatures[id]
+		}
+	}
+	return missing
+}
+
+// MergeTransactionSignatures merges the signatures of copies of the same
+// transaction which were signed by different parties. Every public key signs
+// an input at most once and signatures exceeding the number of required
+// signatures are dropped, since they would invalidate the transaction.
+func MergeTransactionSignatures(txns []types.Transaction) (types.Transaction, error) {
+	if len(txns) == 0 {
+		return types.Transaction{}, ErrNoTransactions
+	}
+	// The transaction id doesn't cover the signatures.
+	id := txns[0].ID()
+	for _, txn := range txns[1:] {
+		if txn.ID() != id {
+			return types.Transaction{}, ErrMultisigMismatch
+		}
+	}
+
+	type sigKey struct {
+		parentID crypto.Hash
+		index    uint64
+	}
+	ucs := inputUnlockConditions(txns[0])
+	used := make(map[sigKey]struct{})
+	signatures := make(map[crypto.Hash]uint64)
+	merged := txns[0]
+	merged.TransactionSignatures = nil
+	for _, txn := range txns {
+		for _, sig := range txn.TransactionSignatures {
+			key := sigKey{sig.ParentID, sig.PublicKeyIndex}
+			if _, exists := used[key]; exists {
+				continue
+			}
+			if uc, exists := ucs[sig.ParentID]; exists && signatures[sig.ParentID] >= uc.SignaturesRequired {
+				continue
+			}
+			used[key] = struct{}{}
+			signatures[sig.ParentID]++
+			merged.TransactionSignatures = append(merged.TransactionSignatures, sig)
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,90 @@
+package modules
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+// TestMultisigUnlockConditions is a unit test for MultisigUnlockConditions.
+func TestMultisigUnlockConditions(t *testing.T) {
+	t.Parallel()
+
+	var pks []types.SiaPublicKey
+	for i := 0; i < 3; i++ {
+		_, pk := crypto.GenerateKeyPair()
+		pks = append(pks, types.Ed25519PublicKey(pk))
+	}
+	uc, err := MultisigUnlockConditions(2, pks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc.SignaturesRequired != 2 || len(uc.PublicKeys) != 3 {
+		t.Fatal("wrong unlock conditions", uc)
+	}
+
+	// invalid conditions
+	tests := []struct {
+		required uint64
+		pks      []types.SiaPublicKey
+	}{
+		{1, pks[:1]},
+		{0, pks},
+		{4, pks},
+		{2, []types.SiaPublicKey{pks[0], pks[0]}},
+		{1, []types.SiaPublicKey{pks[0], {Algorithm: types.SignatureEntropy, Key: make([]byte, crypto.PublicKeySize)}}},
+	}
+	for i, test := range tests {
+		if _, err := MultisigUnlockConditions(test.required, test.pks); err == nil {
+			t.Errorf("%v: invalid conditions were accepted", i)
+		}
+	}
+}
+
+// TestMergeTransactionSignatures is a unit test for
+// MergeTransactionSignatures and MissingSignatures.
+func TestMergeTransactionSignatures(t *testing.T) {
+	t.Parallel()
+
+	uc := types.UnlockConditions{
+		PublicKeys:         make([]types.SiaPublicKey, 3),
+		SignaturesRequired: 2,
+	}
+	txn := types.Transaction{
+		SiacoinInputs:  []types.SiacoinInput{{ParentID: types.SiacoinOutputID{1}, UnlockConditions: uc}},
+		SiacoinOutputs: []types.SiacoinOutput{{Value: types.SiacoinPrecision}},
+	}
+	if MissingSignatures(txn) != 2 {
+		t.Fatal("wrong number of missing signatures", MissingSignatures(txn))
+	}
+	sig := func(index uint64) types.TransactionSignature {
+		return types.TransactionSignature{ParentID: crypto.Hash{1}, PublicKeyIndex: index, Signature: []byte{byte(index)}}
+	}
+
+	// Each party signs its own copy.
+	a, b, c := txn, txn, txn
+	a.TransactionSignatures = []types.TransactionSignature{sig(0)}
+	b.TransactionSignatures = []types.TransactionSignature{sig(0), sig(1)}
+	c.TransactionSignatures = []types.TransactionSignature{sig(2)}
+	merged, err := MergeTransactionSignatures([]types.Transaction{a, b, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The duplicate and the extra signature are dropped.
+	if len(merged.TransactionSignatures) != 2 || MissingSignatures(merged) != 0 {
+		t.Fatal("wrong signatures", merged.TransactionSignatures)
+	}
+	if merged.TransactionSignatures[0].PublicKeyIndex != 0 || merged.TransactionSignatures[1].PublicKeyIndex != 1 {
+		t.Fatal("wrong signatures", merged.TransactionSignatures)
+	}
+
+	// Transactions with different outputs can't be merged.
+	c.SiacoinOutputs = []types.SiacoinOutput{{Value: types.SiacoinPrecision.Mul64(2)}}
+	if _, err := MergeTransactionSignatures([]types.Transaction{a, c}); err != ErrMultisigMismatch {
+		t.Fatal("expected mismatch error", err)
+	}
+	if _, err := MergeTransactionSignatures(nil); err != ErrNoTransactions {
+		t.Fatal("expected error for empty set", err)
+	}
+}
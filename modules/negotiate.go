@@ -3,10 +3,12 @@ package modules
 import (
 	"bytes"
 	"crypto/cipher"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"gitlab.com/NebulousLabs/errors"
@@ -647,16 +649,48 @@ func (e *RPCError) Error() string {
 // would be smaller than RPCMinLen, it is padded with random data.
 const RPCMinLen = 4096
 
+// rpcBufferPool is a pool of buffers used for encoding and encrypting RPC
+// messages.
+var rpcBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
 // WriteRPCMessage writes an encrypted RPC message.
+//
+// The message is encoded into a pooled buffer which has room for the length
+// prefix and the nonce in front of the payload. That way the payload can be
+// encrypted in place and the whole message is sent with a single write,
+// instead of copying large payloads such as sectors multiple times.
 func WriteRPCMessage(w io.Writer, aead cipher.AEAD, obj interface{}) error {
-	payload := encoding.Marshal(obj)
+	buf := rpcBufferPool.Get().(*bytes.Buffer)
+	defer rpcBufferPool.Put(buf)
+	buf.Reset()
+
+	// reserve space for the length prefix and the nonce.
+	headerLen := 8 + aead.NonceSize()
+	buf.Write(make([]byte, headerLen))
+	if err := encoding.NewEncoder(buf).Encode(obj); err != nil {
+		return errors.AddContext(err, "failed to encode rpc message")
+	}
 	// pad the payload to RPCMinLen bytes to prevent eavesdroppers from
 	// identifying RPCs by their size.
 	minLen := RPCMinLen - aead.Overhead() - aead.NonceSize()
-	if len(payload) < minLen {
-		payload = append(payload, fastrand.Bytes(minLen-len(payload))...)
-	}
-	return encoding.WritePrefixedBytes(w, crypto.EncryptWithNonce(payload, aead))
+	if payloadLen := buf.Len() - headerLen; payloadLen < minLen {
+		buf.Write(fastrand.Bytes(minLen - payloadLen))
+	}
+	// make sure the ciphertext fits into the buffer to avoid a reallocation
+	// when sealing the payload.
+	buf.Grow(aead.Overhead())
+	msg := buf.Bytes()
+	nonce, payload := msg[8:headerLen], msg[headerLen:]
+	fastrand.Read(nonce)
+	ciphertext := aead.Seal(payload[:0], nonce, payload, nil)
+	msg = msg[:headerLen+len(ciphertext)]
+	binary.LittleEndian.PutUint64(msg[:8], uint64(aead.NonceSize()+len(ciphertext)))
+	_, err := w.Write(msg)
+	return err
 }
 
 // ReadRPCMessage reads an encrypted RPC message.
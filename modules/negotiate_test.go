@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"testing"
 
+	"gitlab.com/NebulousLabs/encoding"
 	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/types"
+	"golang.org/x/crypto/chacha20poly1305"
 )
 
 // TestAnnouncementHandling checks that CreateAnnouncement and
@@ -159,3 +162,42 @@ func TestRenterPayoutsPreTax(t *testing.T) {
 		t.Fatal("Negative currency returned for host collateral", hostCollateral)
 	}
 }
+
+// TestWriteRPCMessage checks that messages written with WriteRPCMessage are
+// padded, prefixed and encrypted as expected and can be read again.
+func TestWriteRPCMessage(t *testing.T) {
+	aead, err := chacha20poly1305.NewX(fastrand.Bytes(chacha20poly1305.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range []int{0, 100, RPCMinLen, int(SectorSize)} {
+		data := fastrand.Bytes(size)
+
+		// Write the message twice to make sure reused buffers don't affect
+		// the output.
+		var buf bytes.Buffer
+		for i := 0; i < 2; i++ {
+			if err := WriteRPCMessage(&buf, aead, data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		msgLen := uint64(buf.Len() / 2)
+		for i := 0; i < 2; i++ {
+			// Check the length prefix and the padding.
+			prefix := encoding.DecUint64(buf.Bytes()[:8])
+			if prefix != msgLen-8 {
+				t.Fatal("wrong length prefix", prefix, msgLen-8)
+			}
+			if prefix < RPCMinLen {
+				t.Fatal("message wasn't padded", prefix)
+			}
+			var read []byte
+			if err := ReadRPCMessage(&buf, aead, &read, uint64(size)+RPCMinLen); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(read, data) {
+				t.Fatal("read data doesn't match written data")
+			}
+		}
+	}
+}
@@ -147,6 +147,42 @@ const (
 	HostDBActiveWhitelist
 )
 
+// The types of jobs performed by the renter's workers.
+const (
+	WorkerJobDownloadSnapshot WorkerJobType = "downloadsnapshot"
+	WorkerJobHasSector        WorkerJobType = "hassector"
+	WorkerJobLowPrioRead      WorkerJobType = "lowprioread"
+	WorkerJobRead             WorkerJobType = "read"
+	WorkerJobReadRegistry     WorkerJobType = "readregistry"
+	WorkerJobRenew            WorkerJobType = "renew"
+	WorkerJobUpdateRegistry   WorkerJobType = "updateregistry"
+	WorkerJobUploadSnapshot   WorkerJobType = "uploadsnapshot"
+)
+
+// The classes of errors worker jobs fail with.
+const (
+	// WorkerJobErrorTimeout is the code of jobs which timed out while
+	// communicating with the host.
+	WorkerJobErrorTimeout WorkerJobErrorCode = "timeout"
+
+	// WorkerJobErrorInsufficientBalance is the code of jobs which the host
+	// rejected because the worker's ephemeral account didn't have a
+	// sufficient balance.
+	WorkerJobErrorInsufficientBalance WorkerJobErrorCode = "insufficientbalance"
+
+	// WorkerJobErrorRevisionMismatch is the code of jobs which failed because
+	// the renter and the host disagree about the latest contract revision.
+	WorkerJobErrorRevisionMismatch WorkerJobErrorCode = "revisionmismatch"
+
+	// WorkerJobErrorInvalidResponse is the code of jobs which failed because
+	// the host's response failed verification.
+	WorkerJobErrorInvalidResponse WorkerJobErrorCode = "invalidresponse"
+
+	// WorkerJobErrorOther is the code of jobs which failed with any other
+	// error, including errors returned by the host.
+	WorkerJobErrorOther WorkerJobErrorCode = "other"
+)
+
 // Filesystem related consts.
 const (
 	// DefaultDirPerm defines the default permissions used for a new dir if no
@@ -280,6 +316,19 @@ type Allowance struct {
 	// period.
 	MaxPeriodChurn uint64 `json:"maxperiodchurn"`
 
+	// The following fields let the user express preferences about the
+	// locality of the hosts. They are taken into account when scoring hosts
+	// and forming new contracts, but hosts which don't satisfy them are not
+	// excluded.
+	//
+	// PreferredLatency is the round-trip latency up to which hosts are not
+	// penalized. PreferredRegions are the GeoIP regions which hosts are
+	// preferred from. MinSubnets is the number of distinct /16 subnets (/32
+	// for IPv6) that the contractor tries to spread the contracts across.
+	PreferredLatency time.Duration `json:"preferredlatency"`
+	PreferredRegions []string      `json:"preferredregions"`
+	MinSubnets       uint64        `json:"minsubnets"`
+
 	// The following fields provide price gouging protection for the user. By
 	// setting a particular maximum price for each mechanism that a host can use
 	// to charge users, the workers know to avoid hosts that go outside of the
@@ -380,6 +429,31 @@ type DirectoryInfo struct {
 	UID                 uint64      `json:"uid"`
 }
 
+// DirectoryHealth contains the aggregated health and stuck statistics of a
+// siadir's subtree.
+type DirectoryHealth struct {
+	SiaPath SiaPath `json:"siapath"`
+
+	AggregateHealth              float64   `json:"aggregatehealth"`
+	AggregateLastHealthCheckTime time.Time `json:"aggregatelasthealthchecktime"`
+	AggregateMaxHealthPercentage float64   `json:"aggregatemaxhealthpercentage"`
+	AggregateMinRedundancy       float64   `json:"aggregateminredundancy"`
+	AggregateNumFiles            uint64    `json:"aggregatenumfiles"`
+	AggregateNumStuckChunks      uint64    `json:"aggregatenumstuckchunks"`
+	AggregateRepairSize          uint64    `json:"aggregaterepairsize"`
+	AggregateSize                uint64    `json:"aggregatesize"`
+	AggregateStuckHealth         float64   `json:"aggregatestuckhealth"`
+	AggregateStuckSize           uint64    `json:"aggregatestucksize"`
+
+	// NeedsRepair indicates whether any file in the subtree needs to be
+	// repaired.
+	NeedsRepair bool `json:"needsrepair"`
+
+	// Pinned indicates whether the directory is pinned or within a pinned
+	// directory, which means it is repaired before unpinned directories.
+	Pinned bool `json:"pinned"`
+}
+
 // Name implements os.FileInfo.
 func (d DirectoryInfo) Name() string { return d.SiaPath.Name() }
 
@@ -414,6 +488,306 @@ type DownloadInfo struct {
 	StartTime            time.Time `json:"starttime"`            // The time when the download was started.
 	StartTimeUnix        int64     `json:"starttimeunix"`        // The time when the download was started in unix format.
 	TotalDataTransferred uint64    `json:"totaldatatransferred"` // Total amount of data transferred, including negotiation, etc.
+
+	CostWeight    float64        `json:"costweight"`    // How much host prices were weighed against host speed, see RenterDownloadParameters.
+	EstimatedCost types.Currency `json:"estimatedcost"` // The estimated cost of fetching the pieces from the preferred hosts. Only set if CostWeight is not 0.
+	MaxSpeed      uint64         `json:"maxspeed"`      // The per-download bandwidth cap in bytes per second, 0 if the download isn't capped.
+
+	RequestClass    DownloadRequestClass `json:"requestclass"`    // The class of the download which determines its overdrive policy.
+	OverdrivePieces uint64               `json:"overdrivepieces"` // The number of pieces fetched beyond the minimum required to recover the chunks.
+}
+
+// DownloadRequestClass identifies a class of downloads that share an overdrive
+// policy.
+type DownloadRequestClass string
+
+const (
+	// DownloadClassDownload is the class of downloads started through
+	// /renter/download.
+	DownloadClassDownload = DownloadRequestClass("download")
+
+	// DownloadClassStream is the class of downloads performed by streams, e.g.
+	// through /renter/stream or FUSE.
+	DownloadClassStream = DownloadRequestClass("stream")
+)
+
+// DownloadRequestClasses are the download classes with a configurable
+// overdrive policy.
+var DownloadRequestClasses = []DownloadRequestClass{DownloadClassDownload, DownloadClassStream}
+
+// DownloadOverdrivePolicy controls how many pieces beyond the minimum a
+// download fetches for each chunk to keep slow hosts from becoming a
+// bottleneck. Fetching more pieces lowers the tail latency of chunks at the
+// cost of bandwidth.
+type DownloadOverdrivePolicy struct {
+	// BaseWorkers is the number of extra pieces that are fetched as soon as
+	// the chunk is handed to the workers.
+	BaseWorkers int `json:"baseworkers"`
+
+	// HedgeDelay is how long to wait for a chunk to complete before fetching
+	// another extra piece. A delay of 0 disables hedging.
+	HedgeDelay time.Duration `json:"hedgedelay"`
+
+	// MaxExtraPieces is the maximum number of extra pieces, including the
+	// BaseWorkers, that are fetched for a chunk.
+	MaxExtraPieces int `json:"maxextrapieces"`
+}
+
+// DownloadOverdriveStatus contains the overdrive policy of a download class
+// and the latencies that were measured for the recent chunks of the class.
+type DownloadOverdriveStatus struct {
+	Policy DownloadOverdrivePolicy `json:"policy"`
+
+	Chunks          uint64        `json:"chunks"`          // The number of recent chunks the stats are based on.
+	OverdrivePieces uint64        `json:"overdrivepieces"` // The number of pieces the recent chunks fetched beyond the minimum.
+	LatencyP50      time.Duration `json:"latencyp50"`      // The median time it took to fetch a chunk.
+	LatencyP99      time.Duration `json:"latencyp99"`      // The 99th percentile of the time it took to fetch a chunk.
+}
+
+// AccountFundingPolicy controls how the renter funds its ephemeral account on
+// a host. Zero fields of a host's policy fall back to the renter-wide policy
+// and zero fields of the renter-wide policy fall back to the defaults.
+type AccountFundingPolicy struct {
+	// BalanceTarget is the balance an account is refilled to. It defaults to
+	// 1 SC.
+	BalanceTarget types.Currency `json:"balancetarget"`
+
+	// RefillThreshold is the balance below which an account is refilled. It
+	// defaults to half the BalanceTarget.
+	RefillThreshold types.Currency `json:"refillthreshold"`
+
+	// MaxDriftTolerance is the amount by which the balance reported by a host
+	// may fall short of the renter's version of the balance. If the shortfall
+	// is larger, the renter assumes the host reset the account, accepts the
+	// host's balance and records the difference as lost. It defaults to a
+	// tenth of the BalanceTarget.
+	MaxDriftTolerance types.Currency `json:"maxdrifttolerance"`
+}
+
+// HostAccountFunding contains the funding policy of the renter's ephemeral
+// account on a host and the money that was lost to the host resetting the
+// account.
+type HostAccountFunding struct {
+	HostPubKey types.SiaPublicKey `json:"hostpubkey"`
+
+	// Policy is the policy that was set for the host. EffectivePolicy is the
+	// policy the renter uses after falling back to the renter-wide policy and
+	// the defaults.
+	Policy          AccountFundingPolicy `json:"policy"`
+	EffectivePolicy AccountFundingPolicy `json:"effectivepolicy"`
+
+	BalanceLost types.Currency `json:"balancelost"`
+}
+
+// AccountFundingReport contains the renter-wide account funding policy and the
+// funding details of the renter's ephemeral accounts.
+type AccountFundingReport struct {
+	// Policy is the renter-wide policy with the defaults applied.
+	Policy AccountFundingPolicy `json:"policy"`
+
+	Accounts         []HostAccountFunding `json:"accounts"`
+	TotalBalanceLost types.Currency       `json:"totalbalancelost"`
+}
+
+// BackupScheduleSettings control the renter's scheduled backups. Generations
+// is the number of uploaded scheduled backups the renter keeps, older ones are
+// pruned.
+type BackupScheduleSettings struct {
+	Enabled     bool          `json:"enabled"`
+	Interval    time.Duration `json:"interval"`
+	Generations uint64        `json:"generations"`
+}
+
+// BackupScheduleStatus contains the settings of the renter's scheduled backups
+// and the scheduled backups the renter knows about, ordered from newest to
+// oldest.
+type BackupScheduleStatus struct {
+	BackupScheduleSettings
+	InProgress bool             `json:"inprogress"`
+	LastBackup time.Time        `json:"lastbackup"`
+	LastError  string           `json:"lasterror"` // Will be the empty string unless the last scheduled backup failed.
+	Backups    []UploadedBackup `json:"backups"`
+}
+
+// RestoreDrillSettings control the renter's periodic restore drills.
+type RestoreDrillSettings struct {
+	Enabled  bool          `json:"enabled"`
+	Interval time.Duration `json:"interval"`
+}
+
+// RestoreDrillResult describes the outcome of a single restore drill. A drill
+// downloads a random file and verifies it against a reference checksum. If no
+// reference was available, the drill records the checksum of the downloaded
+// data as the reference for future drills and Baseline is set.
+type RestoreDrillResult struct {
+	Baseline  bool          `json:"baseline"`  // Whether the drill established the reference checksum.
+	Checksum  crypto.Hash   `json:"checksum"`  // The checksum of the downloaded data.
+	Duration  time.Duration `json:"duration"`  // How long the download and verification took.
+	Error     string        `json:"error"`     // Will be the empty string unless the drill failed.
+	Filesize  uint64        `json:"filesize"`  // The size of the restored file.
+	SiaPath   SiaPath       `json:"siapath"`   // The siapath of the restored file.
+	Success   bool          `json:"success"`   // Whether the file was restored and verified.
+	Timestamp time.Time     `json:"timestamp"` // The time when the drill was started.
+}
+
+// RestoreDrillStatus contains the settings of the renter's restore drills and
+// the results of the most recent drills, ordered from oldest to newest.
+type RestoreDrillStatus struct {
+	RestoreDrillSettings
+	InProgress bool                 `json:"inprogress"`
+	LastDrill  time.Time            `json:"lastdrill"`
+	Results    []RestoreDrillResult `json:"results"`
+}
+
+// DirDeletionID uniquely identifies a background directory deletion.
+type DirDeletionID string
+
+// DirDeletionStatus describes a recursive deletion of a directory. NumFiles and
+// TotalBytes are the number of files and their total size that were found
+// within the directory when the deletion started. For dry runs nothing is
+// deleted and the status is returned without being tracked by the renter.
+type DirDeletionStatus struct {
+	ID           DirDeletionID `json:"id"`
+	SiaPath      SiaPath       `json:"siapath"`
+	DryRun       bool          `json:"dryrun"`
+	Completed    bool          `json:"completed"`
+	Error        string        `json:"error"` // Will be the empty string unless the deletion failed.
+	NumFiles     uint64        `json:"numfiles"`
+	TotalBytes   uint64        `json:"totalbytes"`
+	FilesDeleted uint64        `json:"filesdeleted"`
+	BytesDeleted uint64        `json:"bytesdeleted"`
+	StartTime    time.Time     `json:"starttime"`
+	EndTime      time.Time     `json:"endtime"`
+}
+
+// FileReencodeID uniquely identifies a background re-encode of a file.
+type FileReencodeID string
+
+// FileReencodeStatus describes the conversion of a file from its current
+// erasure code to new parameters. The file is downloaded, re-encoded and
+// uploaded again chunk by chunk. NumChunks and ChunksReencoded refer to the
+// chunks of the re-encoded file.
+type FileReencodeStatus struct {
+	ID              FileReencodeID `json:"id"`
+	SiaPath         SiaPath        `json:"siapath"`
+	OldDataPieces   int            `json:"olddatapieces"`
+	OldParityPieces int            `json:"oldparitypieces"`
+	NewDataPieces   int            `json:"newdatapieces"`
+	NewParityPieces int            `json:"newparitypieces"`
+	Completed       bool           `json:"completed"`
+	Error           string         `json:"error"` // Will be the empty string unless the re-encode failed.
+	Filesize        uint64         `json:"filesize"`
+	BytesReencoded  uint64         `json:"bytesreencoded"`
+	NumChunks       uint64         `json:"numchunks"`
+	ChunksReencoded uint64         `json:"chunksreencoded"`
+	StartTime       time.Time      `json:"starttime"`
+	EndTime         time.Time      `json:"endtime"`
+}
+
+// FileDefragStatus describes the progress of the renter's background siafile
+// defragmentation, which removes dead pieces and unused hosts from the
+// metadata of the renter's files. The counters refer to the current pass if
+// one is active and to the last pass otherwise.
+type FileDefragStatus struct {
+	Active            bool      `json:"active"`
+	NumFiles          uint64    `json:"numfiles"`
+	FilesChecked      uint64    `json:"fileschecked"`
+	FilesDefragmented uint64    `json:"filesdefragmented"`
+	PiecesRemoved     uint64    `json:"piecesremoved"`
+	HostsPruned       uint64    `json:"hostspruned"`
+	BytesFreed        uint64    `json:"bytesfreed"`
+	StartTime         time.Time `json:"starttime"`
+	EndTime           time.Time `json:"endtime"`
+}
+
+// FileDeletionResult is the outcome of deleting a single file as part of a
+// batch deletion.
+type FileDeletionResult struct {
+	SiaPath SiaPath `json:"siapath"`
+	Error   string  `json:"error"` // Will be the empty string unless the deletion failed.
+}
+
+// RenterEventType is the type of an event in the renter's event stream.
+type RenterEventType string
+
+const (
+	// RenterEventChunkRepaired is emitted when a chunk that already had
+	// uploaded pieces was repaired to full redundancy.
+	RenterEventChunkRepaired RenterEventType = "chunkrepaired"
+
+	// RenterEventContractFormed is emitted when the renter forms or renews a
+	// contract.
+	RenterEventContractFormed RenterEventType = "contractformed"
+
+	// RenterEventDownloadComplete is emitted when a download finishes,
+	// successfully or not. Streams don't emit events.
+	RenterEventDownloadComplete RenterEventType = "downloadcomplete"
+
+	// RenterEventFileHealthChanged is emitted when the health of a fully
+	// uploaded file changes.
+	RenterEventFileHealthChanged RenterEventType = "filehealthchanged"
+
+	// RenterEventUploadComplete is emitted when all pieces of a file were
+	// uploaded.
+	RenterEventUploadComplete RenterEventType = "uploadcomplete"
+)
+
+// RenterEvent is an event in the renter's event stream. Events are numbered
+// in the order they occur, starting at 1 every time the renter starts. Only
+// the fields related to the event's type are set.
+type RenterEvent struct {
+	ID   uint64          `json:"id"`
+	Type RenterEventType `json:"type"`
+	Time time.Time       `json:"time"`
+
+	// File events.
+	SiaPath        SiaPath `json:"siapath"`
+	ChunkIndex     uint64  `json:"chunkindex"`
+	Health         float64 `json:"health"`
+	PreviousHealth float64 `json:"previoushealth"`
+
+	// Download events.
+	DownloadID DownloadID `json:"downloadid"`
+	Error      string     `json:"error"`
+
+	// Contract events.
+	ContractID    types.FileContractID `json:"contractid"`
+	HostPublicKey types.SiaPublicKey   `json:"hostpublickey"`
+}
+
+// ChunkCacheStats contains statistics about the renter's on-disk cache of
+// downloaded chunks. Hits and misses are counted per chunk since startup.
+type ChunkCacheStats struct {
+	Size      uint64 `json:"size"`
+	MaxSize   uint64 `json:"maxsize"`
+	NumChunks uint64 `json:"numchunks"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+}
+
+// FileVerification is the result of verifying the pieces of a file. The
+// pieces are downloaded from the hosts and their Merkle roots are compared to
+// the ones in the file's metadata.
+type FileVerification struct {
+	SiaPath           SiaPath            `json:"siapath"`
+	PiecesChecked     uint64             `json:"pieceschecked"`
+	PiecesVerified    uint64             `json:"piecesverified"`
+	PiecesBad         uint64             `json:"piecesbad"`
+	PiecesUnavailable uint64             `json:"piecesunavailable"`
+	Hosts             []HostVerification `json:"hosts"`
+	Duration          time.Duration      `json:"duration"`
+}
+
+// HostVerification contains the outcome of verifying the pieces of a file
+// that are stored on a single host. Bad pieces were downloaded but didn't match
+// their Merkle root while unavailable pieces couldn't be downloaded at all.
+type HostVerification struct {
+	HostPublicKey     types.SiaPublicKey `json:"hostpublickey"`
+	PiecesChecked     uint64             `json:"pieceschecked"`
+	PiecesVerified    uint64             `json:"piecesverified"`
+	PiecesBad         uint64             `json:"piecesbad"`
+	PiecesUnavailable uint64             `json:"piecesunavailable"`
+	Error             string             `json:"error"` // The last error of a bad or unavailable piece.
 }
 
 // FileUploadParams contains the information used by the Renter to upload a
@@ -436,6 +810,27 @@ type FileUploadParams struct {
 	CipherKey crypto.CipherKey
 }
 
+// UploadSessionID uniquely identifies an upload session.
+type UploadSessionID string
+
+// UploadSession is a resumable streaming upload. The data of the session can
+// be uploaded with multiple requests. Every chunk that becomes available on
+// the network is checkpointed, so that an interrupted upload can resume from
+// the last completed chunk, even after the renter restarts.
+type UploadSession struct {
+	ID         UploadSessionID `json:"id"`
+	SiaPath    SiaPath         `json:"siapath"`
+	ChunkSize  uint64          `json:"chunksize"`
+	CreateTime time.Time       `json:"createtime"`
+
+	// CompletedChunks is the number of chunks from the start of the file that
+	// are available on the network. Offset is the offset at which the next
+	// request needs to continue the upload.
+	CompletedChunks uint64    `json:"completedchunks"`
+	Offset          uint64    `json:"offset"`
+	LastCheckpoint  time.Time `json:"lastcheckpoint"`
+}
+
 // FileInfo provides information about a file.
 type FileInfo struct {
 	AccessTime       time.Time         `json:"accesstime"`
@@ -462,6 +857,7 @@ type FileInfo struct {
 	Stuck            bool              `json:"stuck"`
 	StuckBytes       uint64            `json:"stuckbytes"`
 	StuckHealth      float64           `json:"stuckhealth"`
+	Tags             map[string]string `json:"tags,omitempty"`
 	UID              uint64            `json:"uid"`
 	UploadedBytes    uint64            `json:"uploadedbytes"`
 	UploadProgress   float64           `json:"uploadprogress"`
@@ -485,6 +881,66 @@ func (f FileInfo) IsDir() bool { return false }
 // Sys implements os.FileInfo.
 func (f FileInfo) Sys() interface{} { return nil }
 
+// FileKeysVersion is the version of the FileKeys export format. It is
+// increased whenever a change to the format would break existing recovery
+// tools.
+const FileKeysVersion = 1
+
+type (
+	// FileKeys contains the encryption keys and the erasure coding layout of a
+	// siafile. It allows tools that are independent of the renter to recover
+	// the file from the raw sectors stored on its hosts.
+	//
+	// Every chunk of ChunkSize bytes is erasure coded into pieces which are
+	// stored as whole sectors on the hosts. Pieces are encrypted with their
+	// key using the cipher of the file before being uploaded. To recover a
+	// chunk, any DataPieces of its pieces are decrypted and decoded with the
+	// erasure code. The last chunk is padded, only FileSize bytes of the
+	// recovered data belong to the file.
+	FileKeys struct {
+		Version     int             `json:"version"`
+		SiaPath     SiaPath         `json:"siapath"`
+		FileSize    uint64          `json:"filesize"`
+		ChunkSize   uint64          `json:"chunksize"`
+		PieceSize   uint64          `json:"piecesize"`
+		CipherType  string          `json:"ciphertype"`
+		MasterKey   []byte          `json:"masterkey"`
+		ErasureCode FileKeysErasure `json:"erasurecode"`
+		Chunks      []FileKeysChunk `json:"chunks"`
+	}
+
+	// FileKeysErasure describes the erasure code of a siafile. SegmentSize is
+	// the number of bytes of a piece that can be decoded independently, 0 if
+	// pieces can only be decoded as a whole.
+	FileKeysErasure struct {
+		Type         string `json:"type"`
+		DataPieces   int    `json:"datapieces"`
+		ParityPieces int    `json:"paritypieces"`
+		SegmentSize  uint64 `json:"segmentsize"`
+	}
+
+	// FileKeysChunk contains the pieces of a single chunk of a siafile.
+	FileKeysChunk struct {
+		Index  uint64          `json:"index"`
+		Pieces []FileKeysPiece `json:"pieces"`
+	}
+
+	// FileKeysPiece contains the key a piece is encrypted with and the
+	// sectors on the hosts that store it. The key is derived from the master
+	// key of the file and the indices of the chunk and piece.
+	FileKeysPiece struct {
+		Index   uint64           `json:"index"`
+		Key     []byte           `json:"key"`
+		Sectors []FileKeysSector `json:"sectors"`
+	}
+
+	// FileKeysSector is a sector that stores a piece of a siafile.
+	FileKeysSector struct {
+		HostPublicKey types.SiaPublicKey `json:"hostpublickey"`
+		MerkleRoot    crypto.Hash        `json:"merkleroot"`
+	}
+)
+
 // A HostDBEntry represents one host entry in the Renter's host DB. It
 // aggregates the host's external settings and metrics with its public key.
 type HostDBEntry struct {
@@ -512,6 +968,12 @@ type HostDBEntry struct {
 	IPNets          []string  `json:"ipnets"`
 	LastIPNetChange time.Time `json:"lastipnetchange"`
 
+	// Latency is the moving average of the round-trip latency measured while
+	// scanning the host. Region is the GeoIP region of the host, it is only
+	// set if the hostdb has a GeoIP database.
+	Latency time.Duration `json:"latency"`
+	Region  string        `json:"region"`
+
 	// The public key of the host, stored separately to minimize risk of certain
 	// MitM based vulnerabilities.
 	PublicKey types.SiaPublicKey `json:"publickey"`
@@ -545,7 +1007,9 @@ type HostScoreBreakdown struct {
 	CollateralAdjustment       float64 `json:"collateraladjustment"`
 	DurationAdjustment         float64 `json:"durationadjustment"`
 	InteractionAdjustment      float64 `json:"interactionadjustment"`
+	LatencyAdjustment          float64 `json:"latencyadjustment"`
 	PriceAdjustment            float64 `json:"pricesmultiplier,siamismatch"`
+	RegionAdjustment           float64 `json:"regionadjustment"`
 	StorageRemainingAdjustment float64 `json:"storageremainingadjustment"`
 	UptimeAdjustment           float64 `json:"uptimeadjustment"`
 	VersionAdjustment          float64 `json:"versionadjustment"`
@@ -614,11 +1078,15 @@ type RenterPriceEstimation struct {
 
 // RenterSettings control the behavior of the Renter.
 type RenterSettings struct {
-	Allowance        Allowance     `json:"allowance"`
-	IPViolationCheck bool          `json:"ipviolationcheck"`
-	MaxUploadSpeed   int64         `json:"maxuploadspeed"`
-	MaxDownloadSpeed int64         `json:"maxdownloadspeed"`
-	UploadsStatus    UploadsStatus `json:"uploadsstatus"`
+	AccountFunding   AccountFundingPolicy `json:"accountfunding"`
+	Allowance        Allowance            `json:"allowance"`
+	ChunkCacheSize   uint64               `json:"chunkcachesize"`
+	IPViolationCheck bool                 `json:"ipviolationcheck"`
+	MaxUploadSpeed   int64                `json:"maxuploadspeed"`
+	MaxDownloadSpeed int64                `json:"maxdownloadspeed"`
+	ReadOnly         bool                 `json:"readonly"`
+	RepairSchedule   RepairSchedule       `json:"repairschedule"`
+	UploadsStatus    UploadsStatus        `json:"uploadsstatus"`
 }
 
 // UploadsStatus contains information about the Renter's Uploads
@@ -830,6 +1298,98 @@ type ContractorSpending struct {
 	PreviousSpending types.Currency `json:"previousspending"`
 }
 
+// SpendingCategories contains the money spent on one or more contracts by
+// category.
+type SpendingCategories struct {
+	Fees                types.Currency      `json:"fees"`
+	DownloadSpending    types.Currency      `json:"downloadspending"`
+	FundAccountSpending types.Currency      `json:"fundaccountspending"`
+	MaintenanceSpending MaintenanceSpending `json:"maintenancespending"`
+	StorageSpending     types.Currency      `json:"storagespending"`
+	UploadSpending      types.Currency      `json:"uploadspending"`
+}
+
+// ContractSpendingSnapshot is the cumulative spending of a contract at a
+// block height.
+type ContractSpendingSnapshot struct {
+	BlockHeight types.BlockHeight `json:"blockheight"`
+	SpendingCategories
+}
+
+// ContractSpending is the spending of a contract within a range of blocks.
+type ContractSpending struct {
+	ContractID    types.FileContractID `json:"contractid"`
+	HostPublicKey types.SiaPublicKey   `json:"hostpublickey"`
+	StartHeight   types.BlockHeight    `json:"startheight"`
+	EndHeight     types.BlockHeight    `json:"endheight"`
+	TotalSpending types.Currency       `json:"totalspending"`
+	SpendingCategories
+}
+
+// RenterSpending is the renter's spending within a range of blocks, in total
+// and by contract.
+type RenterSpending struct {
+	PeriodStart   types.BlockHeight  `json:"periodstart"`
+	PeriodEnd     types.BlockHeight  `json:"periodend"`
+	TotalSpending types.Currency     `json:"totalspending"`
+	Contracts     []ContractSpending `json:"contracts"`
+	SpendingCategories
+}
+
+// NewSpendingCategories returns the cumulative spending of a contract by
+// category.
+func NewSpendingCategories(c RenterContract) SpendingCategories {
+	return SpendingCategories{
+		Fees:                c.ContractFee.Add(c.TxnFee).Add(c.SiafundFee),
+		DownloadSpending:    c.DownloadSpending,
+		FundAccountSpending: c.FundAccountSpending,
+		MaintenanceSpending: c.MaintenanceSpending,
+		StorageSpending:     c.StorageSpending,
+		UploadSpending:      c.UploadSpending,
+	}
+}
+
+// Add returns the sum of the spending of both objects.
+func (x SpendingCategories) Add(y SpendingCategories) SpendingCategories {
+	return SpendingCategories{
+		Fees:                x.Fees.Add(y.Fees),
+		DownloadSpending:    x.DownloadSpending.Add(y.DownloadSpending),
+		FundAccountSpending: x.FundAccountSpending.Add(y.FundAccountSpending),
+		MaintenanceSpending: x.MaintenanceSpending.Add(y.MaintenanceSpending),
+		StorageSpending:     x.StorageSpending.Add(y.StorageSpending),
+		UploadSpending:      x.UploadSpending.Add(y.UploadSpending),
+	}
+}
+
+// Sub returns the spending of x minus the spending of y. Categories in which
+// y spent more than x are 0.
+func (x SpendingCategories) Sub(y SpendingCategories) SpendingCategories {
+	sub := func(a, b types.Currency) types.Currency {
+		if a.Cmp(b) <= 0 {
+			return types.ZeroCurrency
+		}
+		return a.Sub(b)
+	}
+	return SpendingCategories{
+		Fees:                sub(x.Fees, y.Fees),
+		DownloadSpending:    sub(x.DownloadSpending, y.DownloadSpending),
+		FundAccountSpending: sub(x.FundAccountSpending, y.FundAccountSpending),
+		MaintenanceSpending: MaintenanceSpending{
+			AccountBalanceCost:   sub(x.MaintenanceSpending.AccountBalanceCost, y.MaintenanceSpending.AccountBalanceCost),
+			FundAccountCost:      sub(x.MaintenanceSpending.FundAccountCost, y.MaintenanceSpending.FundAccountCost),
+			UpdatePriceTableCost: sub(x.MaintenanceSpending.UpdatePriceTableCost, y.MaintenanceSpending.UpdatePriceTableCost),
+		},
+		StorageSpending: sub(x.StorageSpending, y.StorageSpending),
+		UploadSpending:  sub(x.UploadSpending, y.UploadSpending),
+	}
+}
+
+// Total returns the sum of all categories.
+func (x SpendingCategories) Total() types.Currency {
+	return x.Fees.Add(x.DownloadSpending).Add(x.FundAccountSpending).Add(x.MaintenanceSpending.Sum()).
+		Add(x.StorageSpending).Add(x.UploadSpending)
+}
+
 // SpendingBreakdown provides a breakdown of a few fields in the Contractor
 // Spending
 func (cs ContractorSpending) SpendingBreakdown() (totalSpent, unspentAllocated, unspentUnallocated types.Currency) {
@@ -856,6 +1416,68 @@ type ContractorChurnStatus struct {
 	MaxPeriodChurn uint64 `json:"maxperiodchurn"`
 }
 
+// ContractorMigration is a contract whose data the contractor migrates to other
+// hosts because the contract's host was marked for replacement.
+type ContractorMigration struct {
+	ContractID    types.FileContractID `json:"contractid"`
+	HostPublicKey types.SiaPublicKey   `json:"hostpublickey"`
+	NetAddress    NetAddress           `json:"netaddress"`
+
+	// BlockHeight is the height at which the host was marked for
+	// replacement.
+	BlockHeight types.BlockHeight `json:"blockheight"`
+
+	// Size is the amount of data stored in the contract.
+	Size uint64 `json:"size"`
+
+	// EstimatedCost is the estimated cost of uploading the data to a new host
+	// and storing it there until the end of the current contracts.
+	EstimatedCost types.Currency `json:"estimatedcost"`
+
+	// Reason is the reason why the host was marked for replacement.
+	Reason string `json:"reason"`
+
+	// Deferred indicates that the migration is planned but was deferred
+	// because it doesn't fit within the churn budget.
+	Deferred bool `json:"deferred"`
+}
+
+// ContractorMigrationPlan contains the contractor's migrations in the current
+// period and the migrations which are planned but deferred because of the churn
+// budget.
+type ContractorMigrationPlan struct {
+	ContractorChurnStatus
+
+	// RemainingChurnBudget is the amount of data that can currently be
+	// churned. It is replenished with every block and may be negative.
+	RemainingChurnBudget int64 `json:"remainingchurnbudget"`
+
+	// RemainingPeriodChurn is the amount of data that can still be churned in
+	// the current period. It may be negative.
+	RemainingPeriodChurn int64 `json:"remainingperiodchurn"`
+
+	Migrations         []ContractorMigration `json:"migrations"`
+	TotalEstimatedCost types.Currency        `json:"totalestimatedcost"`
+}
+
+// HostList is a list of hosts identified by their public keys or net
+// addresses. A net address is either a host:port combination, a hostname or IP
+// which matches all ports, or a subnet in CIDR notation such as
+// 12.34.56.0/24.
+type HostList struct {
+	PublicKeys   []types.SiaPublicKey `json:"publickeys"`
+	NetAddresses []string             `json:"netaddresses"`
+}
+
+// ContractorHostLists contains the hosts that the contractor is explicitly
+// allowed or not allowed to form contracts with. If the allowlist is not
+// empty, the contractor only forms contracts with hosts on the allowlist. Hosts
+// on the denylist are never used, even if they are on the allowlist.
+type ContractorHostLists struct {
+	Allowlist HostList `json:"allowlist"`
+	Denylist  HostList `json:"denylist"`
+}
+
 // UploadedBackup contains metadata about an uploaded backup.
 type UploadedBackup struct {
 	Name           string
@@ -916,6 +1538,10 @@ type (
 		// Read Jobs Information
 		ReadJobsStatus WorkerReadJobsStatus `json:"readjobsstatus"`
 
+		// Low Priority Read Job Information, these are the read jobs of
+		// downloads
+		LowPrioReadJobsStatus WorkerReadJobsStatus `json:"lowprioreadjobsstatus"`
+
 		// HasSector Job Information
 		HasSectorJobsStatus WorkerHasSectorJobsStatus `json:"hassectorjobsstatus"`
 
@@ -934,6 +1560,7 @@ type (
 		OnCooldownUntil     time.Time `json:"oncooldownuntil"`
 		RecentErr           string    `json:"recenterr"`
 		RecentErrTime       time.Time `json:"recenterrtime"`
+		RecentJobTimes      []uint64  `json:"recentjobtimes"` // in ms, oldest first
 	}
 
 	// WorkerAccountStatus contains detailed information about the account
@@ -941,6 +1568,10 @@ type (
 		AvailableBalance types.Currency `json:"availablebalance"`
 		NegativeBalance  types.Currency `json:"negativebalance"`
 
+		// BalanceLost is the money that was lost to the host resetting the
+		// account.
+		BalanceLost types.Currency `json:"balancelost"`
+
 		RecentErr         string    `json:"recenterr"`
 		RecentErrTime     time.Time `json:"recenterrtime"`
 		RecentSuccessTime time.Time `json:"recentsuccesstime"`
@@ -970,6 +1601,8 @@ type (
 
 		RecentErr     string    `json:"recenterr"`
 		RecentErrTime time.Time `json:"recenterrtime"`
+
+		RecentJobTimes []uint64 `json:"recentjobtimes"` // in ms, oldest first
 	}
 
 	// WorkerHasSectorJobsStatus contains detailed information about the has
@@ -983,8 +1616,44 @@ type (
 
 		RecentErr     string    `json:"recenterr"`
 		RecentErrTime time.Time `json:"recenterrtime"`
+
+		RecentJobTimes []uint64 `json:"recentjobtimes"` // in ms, oldest first
+	}
+
+	// WorkerJobErrorCode classifies the error a worker job failed with.
+	WorkerJobErrorCode string
+
+	// WorkerJobFailure describes a failed worker job.
+	WorkerJobFailure struct {
+		HostPubKey types.SiaPublicKey `json:"hostpubkey"`
+		JobType    WorkerJobType      `json:"jobtype"`
+		Error      string             `json:"error"`
+		ErrorCode  WorkerJobErrorCode `json:"errorcode"`
+		Time       time.Time          `json:"time"`
+
+		// ConsecutiveFailures is the number of jobs of the same type that
+		// failed in a row on the worker, including this one.
+		ConsecutiveFailures uint64 `json:"consecutivefailures"`
+
+		// CooldownUntil is the time until which the worker's queue of the job
+		// type was put on cooldown due to the failure.
+		CooldownUntil time.Time `json:"cooldownuntil"`
+	}
+
+	// WorkerJobFailureFilter limits the failures returned from the worker job
+	// history. Zero values don't filter.
+	WorkerJobFailureFilter struct {
+		HostPubKey types.SiaPublicKey
+		JobType    WorkerJobType
+		ErrorCode  WorkerJobErrorCode
+		Since      time.Time
+		Until      time.Time
+		Limit      int
 	}
 
+	// WorkerJobType identifies the type of a worker job.
+	WorkerJobType string
+
 	// WorkerReadRegistryJobStatus contains detailed information about the read
 	// registry jobs.
 	WorkerReadRegistryJobStatus struct {
@@ -1003,6 +1672,10 @@ type (
 type Renter interface {
 	Alerter
 
+	// AccountFunding returns the renter-wide account funding policy and the
+	// funding details of the renter's ephemeral accounts.
+	AccountFunding() (AccountFundingReport, error)
+
 	// ActiveHosts provides the list of hosts that the renter is selecting,
 	// sorted by preference.
 	ActiveHosts() ([]HostDBEntry, error)
@@ -1046,6 +1719,19 @@ type Renter interface {
 	// ContractorChurnStatus returns contract churn stats for the current period.
 	ContractorChurnStatus() ContractorChurnStatus
 
+	// ContractorMigrationPlan returns the contractor's migrations in the
+	// current period, including the ones that are deferred by the churn
+	// budget.
+	ContractorMigrationPlan() ContractorMigrationPlan
+
+	// ContractorHostLists returns the contractor's host allowlist and
+	// denylist.
+	ContractorHostLists() ContractorHostLists
+
+	// SetContractorHostLists replaces the contractor's host allowlist and
+	// denylist.
+	SetContractorHostLists(ContractorHostLists) error
+
 	// ContractUtility provides the contract utility for a given host key.
 	ContractUtility(pk types.SiaPublicKey) (ContractUtility, bool)
 
@@ -1070,6 +1756,14 @@ type Renter interface {
 	// billing period.
 	PeriodSpending() (ContractorSpending, error)
 
+	// Spending returns the renter's spending between the start and end
+	// heights by contract, based on the recorded spending history.
+	Spending(start, end types.BlockHeight) (RenterSpending, error)
+
+	// SpendingHistory returns the recorded cumulative spending of the
+	// renter's contracts, oldest snapshot first.
+	SpendingHistory() map[types.FileContractID][]ContractSpendingSnapshot
+
 	// RecoverableContracts returns the contracts that the contractor deems
 	// recoverable. That means they are not expired yet and also not part of the
 	// active contracts. Usually this should return an empty slice unless the host
@@ -1083,9 +1777,24 @@ type Renter interface {
 	// RefreshedContract checks if the contract was previously refreshed
 	RefreshedContract(fcid types.FileContractID) bool
 
+	// RestoreDrillStatus returns the settings and recent results of the
+	// renter's restore drills.
+	RestoreDrillStatus() (RestoreDrillStatus, error)
+
+	// RunRestoreDrill downloads a random file and verifies its contents,
+	// blocking until the drill is complete.
+	RunRestoreDrill() (RestoreDrillResult, error)
+
+	// SetRestoreDrillSettings updates the settings of the renter's restore
+	// drills.
+	SetRestoreDrillSettings(settings RestoreDrillSettings) error
+
 	// SetFileStuck sets the 'stuck' status of a file.
 	SetFileStuck(siaPath SiaPath, stuck bool) error
 
+	// SetFileTags replaces the tags of a file.
+	SetFileTags(siaPath SiaPath, tags map[string]string) error
+
 	// UploadBackup uploads a backup to hosts, such that it can be retrieved
 	// using only the seed.
 	UploadBackup(src string, name string) error
@@ -1100,9 +1809,29 @@ type Renter interface {
 	// BackupsOnHost returns the backups stored on the specified host.
 	BackupsOnHost(hostKey types.SiaPublicKey) ([]UploadedBackup, error)
 
+	// PruneBackup removes an uploaded backup from the renter. Hosts drop the
+	// backup the next time their snapshot table is updated.
+	PruneBackup(name string) error
+
+	// BackupScheduleStatus returns the settings of the renter's scheduled
+	// backups and the scheduled backups the renter knows about.
+	BackupScheduleStatus() (BackupScheduleStatus, error)
+
+	// RunScheduledBackup creates and uploads a scheduled backup right away and
+	// prunes scheduled backups beyond the configured generations.
+	RunScheduledBackup() (UploadedBackup, error)
+
+	// SetBackupScheduleSettings updates the settings of the renter's
+	// scheduled backups.
+	SetBackupScheduleSettings(settings BackupScheduleSettings) error
+
 	// DeleteFile deletes a file entry from the renter.
 	DeleteFile(siaPath SiaPath) error
 
+	// DeleteFiles deletes multiple files at once and returns the result of
+	// every deletion. Directories are only deleted if recursive is set.
+	DeleteFiles(siaPaths []SiaPath, recursive bool) ([]FileDeletionResult, error)
+
 	// Download creates a download according to the parameters passed, including
 	// downloads of `offset` and `length` type. It returns a method to
 	// start the download.
@@ -1115,6 +1844,20 @@ type Renter interface {
 	// download is finished.
 	DownloadAsync(params RenterDownloadParameters, onComplete func(error) error) (uid DownloadID, start func() error, cancel func(), err error)
 
+	// DownloadBatch downloads the files and the contents of the directories
+	// at the provided siapaths and writes them to w as a tar archive. The
+	// archive entries are named relative to root.
+	DownloadBatch(siaPaths []SiaPath, root SiaPath, disableLocalFetch bool, w io.Writer) error
+
+	// ChunkCacheStats returns statistics about the renter's on-disk cache of
+	// downloaded chunks.
+	ChunkCacheStats() ChunkCacheStats
+
+	// Events returns the events of the renter's event stream that occurred
+	// after the event with the provided ID. If there are no such events, the
+	// call blocks until an event occurs or the context is done.
+	Events(ctx context.Context, since uint64) ([]RenterEvent, error)
+
 	// ClearDownloadHistory clears the download history of the renter
 	// inclusive for before and after times.
 	ClearDownloadHistory(after, before time.Time) error
@@ -1125,9 +1868,25 @@ type Renter interface {
 	// DownloadHistory lists all the files that have been scheduled for download.
 	DownloadHistory() []DownloadInfo
 
+	// DownloadOverdrive returns the overdrive policy and the recently measured
+	// chunk latencies of every download class.
+	DownloadOverdrive() (map[DownloadRequestClass]DownloadOverdriveStatus, error)
+
 	// File returns information on specific file queried by user
 	File(siaPath SiaPath) (FileInfo, error)
 
+	// FileKeys returns the encryption keys and the erasure coding layout of
+	// a file.
+	FileKeys(siaPath SiaPath) (FileKeys, error)
+
+	// ShareFile writes the siafile at siaPath to dst, encrypted with the
+	// password, so that it can be loaded by another renter.
+	ShareFile(siaPath SiaPath, dst, password string) error
+
+	// LoadSharedFile loads a file that was shared with ShareFile into the
+	// renter at siaPath.
+	LoadSharedFile(src string, siaPath SiaPath, password string) error
+
 	// FileList returns information on all of the files stored by the renter at the
 	// specified folder. The 'cached' argument specifies whether cached values
 	// should be returned or not.
@@ -1136,6 +1895,18 @@ type Renter interface {
 	// FileHosts returns a list of hosts that are storing the file data.
 	FileHosts(SiaPath) ([]HostDBEntry, error)
 
+	// FileReencodes returns the status of the renter's recent file
+	// re-encodes.
+	FileReencodes() []FileReencodeStatus
+
+	// FileDefragStatus returns the progress of the renter's background
+	// siafile defragmentation.
+	FileDefragStatus() FileDefragStatus
+
+	// RepairScheduleStatus returns whether the renter's repairs may currently
+	// run according to its repair schedule.
+	RepairScheduleStatus() RepairScheduleStatus
+
 	// Filter returns the renter's hostdb's filterMode and filteredHosts
 	Filter() (FilterMode, map[string]types.SiaPublicKey, []string, error)
 
@@ -1153,6 +1924,10 @@ type Renter interface {
 	// storage and data operations.
 	PriceEstimation(allowance Allowance) (RenterPriceEstimation, Allowance, error)
 
+	// ReencodeFile starts converting a file to the provided erasure code in
+	// the background. The progress can be polled using FileReencodes.
+	ReencodeFile(siaPath SiaPath, ec ErasureCoder) (FileReencodeStatus, error)
+
 	// RenameFile changes the path of a file.
 	RenameFile(siaPath, newSiaPath SiaPath) error
 
@@ -1179,6 +1954,18 @@ type Renter interface {
 	// SetSettings sets the Renter's settings.
 	SetSettings(RenterSettings) error
 
+	// ValidateSettings checks the proposed settings for consistency and
+	// projected consequences without applying them.
+	ValidateSettings(RenterSettings) (SettingsValidation, error)
+
+	// SetDownloadOverdrivePolicy sets the overdrive policy of a download
+	// class.
+	SetDownloadOverdrivePolicy(class DownloadRequestClass, policy DownloadOverdrivePolicy) error
+
+	// SetHostAccountFundingPolicy sets the account funding policy of a host.
+	// A zero policy removes the host's policy.
+	SetHostAccountFundingPolicy(hostKey types.SiaPublicKey, policy AccountFundingPolicy) error
+
 	// SetFileTrackingPath sets the on-disk location of an uploaded file to a
 	// new value. Useful if files need to be moved on disk.
 	SetFileTrackingPath(siaPath SiaPath, newPath string) error
@@ -1199,6 +1986,10 @@ type Renter interface {
 	// resource.
 	Streamer(siapath SiaPath, disableLocalFetch bool) (string, Streamer, error)
 
+	// StreamerRange creates a Streamer for the length bytes of a file
+	// starting at offset. Only the chunks covering the range are downloaded.
+	StreamerRange(siapath SiaPath, offset, length uint64, disableLocalFetch bool) (string, Streamer, error)
+
 	// Upload uploads a file using the input parameters.
 	Upload(FileUploadParams) error
 
@@ -1206,18 +1997,82 @@ type Renter interface {
 	// reached and upload the data to the Sia network.
 	UploadStreamFromReader(up FileUploadParams, reader io.Reader) error
 
+	// NewUploadSession creates an empty siafile and starts a resumable upload
+	// session for it.
+	NewUploadSession(up FileUploadParams) (UploadSession, error)
+
+	// UploadSession returns the upload session with the given id.
+	UploadSession(id UploadSessionID) (UploadSession, error)
+
+	// UploadSessions returns all unfinished upload sessions.
+	UploadSessions() ([]UploadSession, error)
+
+	// UploadSessionData uploads the data of the reader to the session,
+	// starting at the given offset which needs to match the session's
+	// offset. Unless final is set, the data needs to end at a chunk boundary.
+	// A final upload finishes the session.
+	UploadSessionData(id UploadSessionID, offset uint64, reader io.Reader, final bool) (UploadSession, error)
+
+	// AbortUploadSession removes an upload session. The data that was already
+	// uploaded is kept.
+	AbortUploadSession(id UploadSessionID) error
+
 	// CreateDir creates a directory for the renter
 	CreateDir(siaPath SiaPath, mode os.FileMode) error
 
 	// DeleteDir deletes a directory from the renter
 	DeleteDir(siaPath SiaPath) error
 
+	// DeleteDirAsync starts deleting a directory and all of its contents in
+	// the background. The progress can be polled using DirDeletions.
+	DeleteDirAsync(siaPath SiaPath) (DirDeletionStatus, error)
+
+	// DeleteDirDryRun returns the number of files and bytes that deleting a
+	// directory would remove without deleting anything.
+	DeleteDirDryRun(siaPath SiaPath) (DirDeletionStatus, error)
+
+	// DirDeletions returns the status of the renter's recent background
+	// directory deletions.
+	DirDeletions() []DirDeletionStatus
+
 	// DirList lists the directories in a siadir
 	DirList(siaPath SiaPath) ([]DirectoryInfo, error)
 
+	// DirsHealth returns the aggregated health of the subtrees of a siadir
+	// and its subdirectories, worst health first. If recursive is false, only
+	// the direct subdirectories are included.
+	DirsHealth(siaPath SiaPath, recursive bool) ([]DirectoryHealth, error)
+
+	// PinnedDirs returns the directories whose subtrees are repaired before
+	// any other directories.
+	PinnedDirs() []SiaPath
+
+	// SetDirPinned pins or unpins a directory. The subtrees of pinned
+	// directories are repaired before any other directories.
+	SetDirPinned(siaPath SiaPath, pinned bool) error
+
+	// ArchivedPaths returns the files and directories of the archive tier.
+	ArchivedPaths() []SiaPath
+
+	// ArchivePath moves a file or directory to the archive tier. Archived
+	// files are not repaired.
+	ArchivePath(siaPath SiaPath) error
+
+	// ThawPath moves an archived file or directory back to actively
+	// maintained status.
+	ThawPath(siaPath SiaPath) error
+
+	// VerifyFile downloads a random sample of a file's pieces, or all of
+	// them if sample is 0, and verifies them against the file's metadata.
+	VerifyFile(siaPath SiaPath, sample uint64) (FileVerification, error)
+
 	// WorkerPoolStatus returns the current status of the Renter's worker pool
 	WorkerPoolStatus() (WorkerPoolStatus, error)
 
+	// WorkerJobHistory returns the failed worker jobs matching the filter,
+	// newest first.
+	WorkerJobHistory(filter WorkerJobFailureFilter) []WorkerJobFailure
+
 	// BubbleMetadata calculates the updated values of a directory's metadata and
 	// updates the siadir metadata on disk then calls callThreadedBubbleMetadata
 	// on the parent directory so that it is only blocking for the current
@@ -1254,6 +2109,18 @@ type RenterDownloadParameters struct {
 	SiaPath          SiaPath
 	Destination      string
 	DisableDiskFetch bool
+
+	// CostWeight determines how much the price of a host is weighed against
+	// its speed when choosing the hosts to download pieces from. It ranges
+	// from 0 to 1. 0 ignores prices and prefers the fastest hosts while 1
+	// prefers the cheapest hosts that still meet the latency target of the
+	// download.
+	CostWeight float64
+
+	// MaxSpeed caps the rate in bytes per second at which the download
+	// fetches data from hosts, in addition to the renter's global download
+	// speed limit. 0 means the download isn't capped.
+	MaxSpeed uint64
 }
 
 // HealthPercentage returns the health in a more human understandable format out
@@ -0,0 +1,159 @@
+package renter
+
+import (
+	"sort"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// defaultAccountBalanceTarget is the balance target of the renter's
+	// ephemeral accounts if no account funding policy sets a different one.
+	//
+	// TODO: check that the balance target makes sense in function of the
+	// amount of MDM programs it can run with that amount of money
+	defaultAccountBalanceTarget = types.SiacoinPrecision
+
+	// errRefillThresholdTooHigh is returned if an account funding policy has a
+	// refill threshold above its balance target.
+	errRefillThresholdTooHigh = errors.New("refill threshold can't be larger than the balance target")
+)
+
+// accountFundingPolicyWithDefaults returns the policy with its zero fields
+// replaced by the defaults. The refill threshold defaults to half the balance
+// target and the drift tolerance to a tenth of it.
+func accountFundingPolicyWithDefaults(policy modules.AccountFundingPolicy, defaultTarget types.Currency) modules.AccountFundingPolicy {
+	if policy.BalanceTarget.IsZero() {
+		policy.BalanceTarget = defaultTarget
+	}
+	if policy.RefillThreshold.IsZero() {
+		policy.RefillThreshold = policy.BalanceTarget.Div64(2)
+	}
+	if policy.RefillThreshold.Cmp(policy.BalanceTarget) > 0 {
+		policy.RefillThreshold = policy.BalanceTarget
+	}
+	if policy.MaxDriftTolerance.IsZero() {
+		policy.MaxDriftTolerance = policy.BalanceTarget.Div64(10)
+	}
+	return policy
+}
+
+// mergeAccountFundingPolicies returns the policy with its zero fields replaced
+// by the fields of the fallback policy.
+func mergeAccountFundingPolicies(policy, fallback modules.AccountFundingPolicy) modules.AccountFundingPolicy {
+	if policy.BalanceTarget.IsZero() {
+		policy.BalanceTarget = fallback.BalanceTarget
+	}
+	if policy.RefillThreshold.IsZero() {
+		policy.RefillThreshold = fallback.RefillThreshold
+	}
+	if policy.MaxDriftTolerance.IsZero() {
+		policy.MaxDriftTolerance = fallback.MaxDriftTolerance
+	}
+	return policy
+}
+
+// validateAccountFundingPolicy checks that the refill threshold of a policy
+// doesn't exceed its balance target if both are set.
+func validateAccountFundingPolicy(policy modules.AccountFundingPolicy) error {
+	if !policy.BalanceTarget.IsZero() && policy.RefillThreshold.Cmp(policy.BalanceTarget) > 0 {
+		return errRefillThresholdTooHigh
+	}
+	return nil
+}
+
+// managedHostAccountFundingPolicy returns the account funding policy of a host
+// merged with the renter-wide policy. The defaults are not applied.
+func (r *Renter) managedHostAccountFundingPolicy(hostKey types.SiaPublicKey) modules.AccountFundingPolicy {
+	id := r.mu.RLock()
+	defer r.mu.RUnlock(id)
+	return mergeAccountFundingPolicies(r.persist.HostAccountFundingPolicies[hostKey.String()], r.persist.AccountFundingPolicy)
+}
+
+// staticAccountFundingPolicy returns the funding policy of the worker's
+// account. The policy is taken from the worker's cache and therefore applies
+// to the worker after its next cache update.
+func (w *worker) staticAccountFundingPolicy() modules.AccountFundingPolicy {
+	var policy modules.AccountFundingPolicy
+	if cache := w.staticCache(); cache != nil {
+		policy = cache.staticAccountFundingPolicy
+	}
+	return accountFundingPolicyWithDefaults(policy, w.staticBalanceTarget)
+}
+
+// AccountFunding returns the renter-wide account funding policy and the
+// funding details of the renter's ephemeral accounts.
+func (r *Renter) AccountFunding() (modules.AccountFundingReport, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.AccountFundingReport{}, err
+	}
+	defer r.tg.Done()
+
+	// Collect the lost balances of the accounts.
+	am := r.staticAccountManager
+	am.mu.Lock()
+	accounts := make([]*account, 0, len(am.accounts))
+	for _, acc := range am.accounts {
+		accounts = append(accounts, acc)
+	}
+	am.mu.Unlock()
+
+	id := r.mu.RLock()
+	renterPolicy := r.persist.AccountFundingPolicy
+	hostPolicies := make(map[string]modules.AccountFundingPolicy, len(r.persist.HostAccountFundingPolicies))
+	for hostKey, policy := range r.persist.HostAccountFundingPolicies {
+		hostPolicies[hostKey] = policy
+	}
+	r.mu.RUnlock(id)
+
+	report := modules.AccountFundingReport{
+		Policy:   accountFundingPolicyWithDefaults(renterPolicy, defaultAccountBalanceTarget),
+		Accounts: make([]modules.HostAccountFunding, 0, len(accounts)),
+	}
+	for _, acc := range accounts {
+		acc.mu.Lock()
+		lost := acc.balanceLost
+		acc.mu.Unlock()
+
+		hostPolicy := hostPolicies[acc.staticHostKey.String()]
+		report.Accounts = append(report.Accounts, modules.HostAccountFunding{
+			HostPubKey:      acc.staticHostKey,
+			Policy:          hostPolicy,
+			EffectivePolicy: accountFundingPolicyWithDefaults(mergeAccountFundingPolicies(hostPolicy, renterPolicy), defaultAccountBalanceTarget),
+			BalanceLost:     lost,
+		})
+		report.TotalBalanceLost = report.TotalBalanceLost.Add(lost)
+	}
+	sort.Slice(report.Accounts, func(i, j int) bool {
+		return report.Accounts[i].HostPubKey.String() < report.Accounts[j].HostPubKey.String()
+	})
+	return report, nil
+}
+
+// SetHostAccountFundingPolicy sets the account funding policy of a host. A
+// zero policy removes the host's policy. The policy applies to the host's
+// worker after its next cache update.
+func (r *Renter) SetHostAccountFundingPolicy(hostKey types.SiaPublicKey, policy modules.AccountFundingPolicy) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	if err := validateAccountFundingPolicy(policy); err != nil {
+		return err
+	}
+
+	id := r.mu.Lock()
+	defer r.mu.Unlock(id)
+	if policy.BalanceTarget.IsZero() && policy.RefillThreshold.IsZero() && policy.MaxDriftTolerance.IsZero() {
+		delete(r.persist.HostAccountFundingPolicies, hostKey.String())
+		return r.saveSync()
+	}
+	if r.persist.HostAccountFundingPolicies == nil {
+		r.persist.HostAccountFundingPolicies = make(map[string]modules.AccountFundingPolicy)
+	}
+	r.persist.HostAccountFundingPolicies[hostKey.String()] = policy
+	return r.saveSync()
+}
@@ -0,0 +1,56 @@
+package renter
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestAccountFundingPolicyDefaults tests merging account funding policies and
+// applying the defaults to them.
+func TestAccountFundingPolicyDefaults(t *testing.T) {
+	t.Parallel()
+
+	sc := types.SiacoinPrecision
+	equals := func(p modules.AccountFundingPolicy, target, threshold, tolerance types.Currency) bool {
+		return p.BalanceTarget.Equals(target) && p.RefillThreshold.Equals(threshold) && p.MaxDriftTolerance.Equals(tolerance)
+	}
+
+	// An empty policy gets the defaults.
+	p := accountFundingPolicyWithDefaults(modules.AccountFundingPolicy{}, sc)
+	if !equals(p, sc, sc.Div64(2), sc.Div64(10)) {
+		t.Fatal("wrong defaults", p)
+	}
+
+	// The defaults of the threshold and tolerance depend on the target.
+	p = accountFundingPolicyWithDefaults(modules.AccountFundingPolicy{BalanceTarget: sc.Mul64(4)}, sc)
+	if !equals(p, sc.Mul64(4), sc.Mul64(2), sc.Mul64(4).Div64(10)) {
+		t.Fatal("wrong defaults for custom target", p)
+	}
+
+	// The threshold is capped at the target.
+	p = accountFundingPolicyWithDefaults(modules.AccountFundingPolicy{RefillThreshold: sc.Mul64(2)}, sc)
+	if !p.RefillThreshold.Equals(sc) {
+		t.Fatal("threshold should be capped", p)
+	}
+
+	// A host's policy falls back to the renter-wide one.
+	renterPolicy := modules.AccountFundingPolicy{BalanceTarget: sc.Mul64(2), MaxDriftTolerance: sc}
+	hostPolicy := modules.AccountFundingPolicy{BalanceTarget: sc.Mul64(3), RefillThreshold: sc}
+	p = mergeAccountFundingPolicies(hostPolicy, renterPolicy)
+	if !equals(p, sc.Mul64(3), sc, sc) {
+		t.Fatal("wrong merged policy", p)
+	}
+
+	// Check the validation.
+	if err := validateAccountFundingPolicy(hostPolicy); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateAccountFundingPolicy(modules.AccountFundingPolicy{BalanceTarget: sc, RefillThreshold: sc.Mul64(2)}); err != errRefillThresholdTooHigh {
+		t.Fatal("expected errRefillThresholdTooHigh but got", err)
+	}
+	if err := validateAccountFundingPolicy(modules.AccountFundingPolicy{RefillThreshold: sc.Mul64(2)}); err != nil {
+		t.Fatal("threshold without target should be valid", err)
+	}
+}
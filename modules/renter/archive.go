@@ -0,0 +1,204 @@
+package renter
+
+import (
+	"sort"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+)
+
+var (
+	// errFileArchived is returned if the renter tries to repair an archived
+	// file.
+	errFileArchived = errors.New("the file is archived")
+
+	// errNotArchived is returned if a siapath that isn't archived is thawed.
+	errNotArchived = errors.New("siapath is not archived")
+)
+
+// archivedPaths contains the files and directories of the archive tier. The
+// renter doesn't repair archived files, which means that their data stays on
+// the hosts it was uploaded to instead of being migrated when hosts churn.
+type archivedPaths struct {
+	paths map[modules.SiaPath]struct{}
+	mu    sync.Mutex
+}
+
+// newArchivedPaths creates a new, empty archivedPaths object.
+func newArchivedPaths() *archivedPaths {
+	return &archivedPaths{
+		paths: make(map[modules.SiaPath]struct{}),
+	}
+}
+
+// callArchivedAncestor returns the archived siapath that contains the provided
+// siapath, which is either the siapath itself or one of its ancestors.
+func (ap *archivedPaths) callArchivedAncestor(siaPath modules.SiaPath) (modules.SiaPath, bool) {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	for archived := range ap.paths {
+		if archived.Contains(siaPath) {
+			return archived, true
+		}
+	}
+	return modules.SiaPath{}, false
+}
+
+// callIsArchived returns whether the file or directory is archived or within
+// an archived directory.
+func (ap *archivedPaths) callIsArchived(siaPath modules.SiaPath) bool {
+	_, archived := ap.callArchivedAncestor(siaPath)
+	return archived
+}
+
+// callPaths returns the archived siapaths sorted by siapath.
+func (ap *archivedPaths) callPaths() []modules.SiaPath {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	paths := make([]modules.SiaPath, 0, len(ap.paths))
+	for siaPath := range ap.paths {
+		paths = append(paths, siaPath)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i].String() < paths[j].String()
+	})
+	return paths
+}
+
+// callSetPaths replaces the archived siapaths.
+func (ap *archivedPaths) callSetPaths(siaPaths []modules.SiaPath) {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	ap.paths = make(map[modules.SiaPath]struct{}, len(siaPaths))
+	for _, siaPath := range siaPaths {
+		ap.paths[siaPath] = struct{}{}
+	}
+}
+
+// ArchivedPaths returns the files and directories of the archive tier.
+func (r *Renter) ArchivedPaths() []modules.SiaPath {
+	return r.staticArchivedPaths.callPaths()
+}
+
+// ArchivePath moves a file or directory to the archive tier. Archived files
+// are no longer repaired, so their data is neither migrated to new hosts nor
+// re-uploaded when hosts churn. The renter only pays for the storage on the
+// hosts that already store the data.
+func (r *Renter) ArchivePath(siaPath modules.SiaPath) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+
+	// Only existing files and directories can be archived.
+	isFile, err := r.staticFileSystem.FileExists(siaPath)
+	if err != nil {
+		return err
+	}
+	if !isFile {
+		isDir, err := r.staticFileSystem.DirExists(siaPath)
+		if err != nil {
+			return err
+		} else if !isDir {
+			return filesystem.ErrNotExist
+		}
+	}
+	if r.staticArchivedPaths.callIsArchived(siaPath) {
+		return nil
+	}
+	return r.managedSetArchivedPaths(append(r.staticArchivedPaths.callPaths(), siaPath))
+}
+
+// ThawPath moves a file or directory from the archive tier back to actively
+// maintained status. The repair loop is woken up to repair the thawed files.
+func (r *Renter) ThawPath(siaPath modules.SiaPath) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+
+	paths := r.staticArchivedPaths.callPaths()
+	var remaining []modules.SiaPath
+	for _, path := range paths {
+		if !path.Equals(siaPath) {
+			remaining = append(remaining, path)
+		}
+	}
+	if len(remaining) == len(paths) {
+		// Files within archived directories can only be thawed together with
+		// the directory.
+		if archived, isArchived := r.staticArchivedPaths.callArchivedAncestor(siaPath); isArchived {
+			return errors.AddContext(errNotArchived, "siapath is within the archived directory "+archived.String())
+		}
+		return errNotArchived
+	}
+	err := r.managedSetArchivedPaths(remaining)
+	if err != nil {
+		return err
+	}
+
+	// Update the health of the thawed files and wake up the repair loop.
+	dirSiaPath := siaPath
+	if isDir, err := r.staticFileSystem.DirExists(siaPath); err == nil && !isDir {
+		dirSiaPath, err = siaPath.Dir()
+		if err != nil {
+			return err
+		}
+	}
+	_ = r.staticBubbleScheduler.callQueueBubble(dirSiaPath)
+	select {
+	case r.uploadHeap.repairNeeded <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// managedSetArchivedPaths updates and persists the archived siapaths.
+func (r *Renter) managedSetArchivedPaths(siaPaths []modules.SiaPath) error {
+	r.staticArchivedPaths.callSetPaths(siaPaths)
+	id := r.mu.Lock()
+	defer r.mu.Unlock(id)
+	r.persist.ArchivedPaths = r.staticArchivedPaths.callPaths()
+	return r.saveSync()
+}
+
+// managedRenameArchivedPaths moves the archived siapaths within a renamed file
+// or directory to their new location.
+func (r *Renter) managedRenameArchivedPaths(oldPath, newPath modules.SiaPath) error {
+	paths := r.staticArchivedPaths.callPaths()
+	var renamed bool
+	for i, path := range paths {
+		if !oldPath.Contains(path) {
+			continue
+		}
+		rebased, err := path.Rebase(oldPath, newPath)
+		if err != nil {
+			return err
+		}
+		paths[i] = rebased
+		renamed = true
+	}
+	if !renamed {
+		return nil
+	}
+	return r.managedSetArchivedPaths(paths)
+}
+
+// managedThawDeletedPaths removes the archived siapaths within a deleted file
+// or directory.
+func (r *Renter) managedThawDeletedPaths(siaPath modules.SiaPath) error {
+	paths := r.staticArchivedPaths.callPaths()
+	var remaining []modules.SiaPath
+	for _, path := range paths {
+		if !siaPath.Contains(path) {
+			remaining = append(remaining, path)
+		}
+	}
+	if len(remaining) == len(paths) {
+		return nil
+	}
+	return r.managedSetArchivedPaths(remaining)
+}
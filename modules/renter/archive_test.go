@@ -0,0 +1,60 @@
+package renter
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestArchivedPaths is a unit test for the archivedPaths object.
+func TestArchivedPaths(t *testing.T) {
+	t.Parallel()
+
+	newSiaPath := func(path string) modules.SiaPath {
+		siaPath, err := modules.NewSiaPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return siaPath
+	}
+	ap := newArchivedPaths()
+	if ap.callIsArchived(newSiaPath("dir/file")) {
+		t.Fatal("nothing should be archived")
+	}
+
+	// Archive a directory and a file.
+	ap.callSetPaths([]modules.SiaPath{newSiaPath("dir"), newSiaPath("other/file")})
+	paths := ap.callPaths()
+	if len(paths) != 2 || paths[0].String() != "dir" || paths[1].String() != "other/file" {
+		t.Fatal("wrong archived paths", paths)
+	}
+
+	// Files and subdirectories within archived directories are archived.
+	tests := []struct {
+		path     string
+		archived bool
+	}{
+		{"dir", true},
+		{"dir/file", true},
+		{"dir/sub/file", true},
+		{"dir2/file", false},
+		{"other", false},
+		{"other/file", true},
+		{"other/file2", false},
+	}
+	for _, test := range tests {
+		if ap.callIsArchived(newSiaPath(test.path)) != test.archived {
+			t.Errorf("%v: expected archived to be %v", test.path, test.archived)
+		}
+	}
+	ancestor, archived := ap.callArchivedAncestor(newSiaPath("dir/sub/file"))
+	if !archived || ancestor.String() != "dir" {
+		t.Fatal("wrong archived ancestor", ancestor, archived)
+	}
+
+	// Replacing the paths thaws the previous ones.
+	ap.callSetPaths(nil)
+	if ap.callIsArchived(newSiaPath("dir/file")) || len(ap.callPaths()) != 0 {
+		t.Fatal("paths should be thawed")
+	}
+}
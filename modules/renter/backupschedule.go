@@ -0,0 +1,427 @@
+package renter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+	"go.sia.tech/siad/persist"
+)
+
+const (
+	// backupScheduleFilename is the name of the file the renter persists the
+	// backup schedule in.
+	backupScheduleFilename = "backupschedule.json"
+
+	// defaultBackupGenerations is the number of scheduled backups the renter
+	// keeps if the user doesn't specify a number.
+	defaultBackupGenerations = 7
+
+	// scheduledBackupPrefix is the prefix of the names of scheduled backups.
+	// It tells them apart from backups the user created and is followed by the
+	// unix timestamp of the backup's creation.
+	scheduledBackupPrefix = "scheduled-"
+)
+
+var (
+	// backupScheduleMetadata is the metadata of the backup schedule persist
+	// file.
+	backupScheduleMetadata = persist.Metadata{
+		Header:  "Renter Backup Schedule",
+		Version: "1.5.5",
+	}
+
+	// defaultBackupScheduleInterval is the interval between scheduled backups
+	// if the user doesn't specify one.
+	defaultBackupScheduleInterval = build.Select(build.Var{
+		Dev:      time.Minute * 10,
+		Standard: time.Hour * 24,
+		Testnet:  time.Hour * 24,
+		Testing:  time.Second * 5,
+	}).(time.Duration)
+
+	// minBackupScheduleInterval is the minimum interval between scheduled
+	// backups. Every backup is uploaded with high redundancy and replicated to
+	// all hosts, so backing up too often wastes money.
+	minBackupScheduleInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: time.Hour,
+		Testnet:  time.Hour,
+		Testing:  time.Second,
+	}).(time.Duration)
+
+	// backupScheduleCheckInterval is how often the backup schedule loop checks
+	// whether a backup is due.
+	backupScheduleCheckInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: time.Minute * 10,
+		Testnet:  time.Minute * 10,
+		Testing:  time.Second,
+	}).(time.Duration)
+)
+
+var (
+	// errBackupNotFound is returned if the user tries to prune a backup the
+	// renter doesn't know about.
+	errBackupNotFound = errors.New("no record of a backup with that name")
+
+	// errScheduledBackupInProgress is returned if a scheduled backup is
+	// started while another one is still running.
+	errScheduledBackupInProgress = errors.New("a scheduled backup is already in progress")
+
+	// errBackupScheduleIntervalTooLow is returned if the user tries to set an
+	// interval below minBackupScheduleInterval.
+	errBackupScheduleIntervalTooLow = errors.New("backup schedule interval is too low")
+)
+
+type (
+	// backupSchedule keeps track of the renter's scheduled backups.
+	backupSchedule struct {
+		inProgress bool
+		persist    backupSchedulePersist
+
+		staticPersistPath string
+		mu                sync.Mutex
+	}
+
+	// backupSchedulePersist contains the persisted backup schedule data.
+	backupSchedulePersist struct {
+		Settings   modules.BackupScheduleSettings
+		LastBackup time.Time
+		LastError  string
+	}
+)
+
+// newBackupSchedule loads the backup schedule from disk or initializes it with
+// default settings.
+func newBackupSchedule(persistDir string) (*backupSchedule, error) {
+	bs := &backupSchedule{
+		persist: backupSchedulePersist{
+			Settings: modules.BackupScheduleSettings{
+				Interval:    defaultBackupScheduleInterval,
+				Generations: defaultBackupGenerations,
+			},
+		},
+		staticPersistPath: filepath.Join(persistDir, backupScheduleFilename),
+	}
+	err := persist.LoadJSON(backupScheduleMetadata, &bs.persist, bs.staticPersistPath)
+	if os.IsNotExist(err) {
+		return bs, nil
+	}
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to load backup schedule")
+	}
+	return bs, nil
+}
+
+// due returns whether a scheduled backup should be created at the given time.
+func (bs *backupSchedule) due(now time.Time) bool {
+	if !bs.persist.Settings.Enabled || bs.inProgress {
+		return false
+	}
+	return now.Sub(bs.persist.LastBackup) >= bs.persist.Settings.Interval
+}
+
+// save persists the backup schedule.
+func (bs *backupSchedule) save() error {
+	return persist.SaveJSON(backupScheduleMetadata, bs.persist, bs.staticPersistPath)
+}
+
+// scheduledBackupName returns the name of a scheduled backup created at the
+// given time.
+func scheduledBackupName(t time.Time) string {
+	return fmt.Sprintf("%v%v", scheduledBackupPrefix, t.Unix())
+}
+
+// isScheduledBackup returns whether the backup was created by the backup
+// schedule.
+func isScheduledBackup(ub modules.UploadedBackup) bool {
+	return strings.HasPrefix(ub.Name, scheduledBackupPrefix)
+}
+
+// scheduledBackups returns the scheduled backups among the provided backups,
+// ordered from newest to oldest.
+func scheduledBackups(backups []modules.UploadedBackup) []modules.UploadedBackup {
+	var scheduled []modules.UploadedBackup
+	for _, ub := range backups {
+		if isScheduledBackup(ub) {
+			scheduled = append(scheduled, ub)
+		}
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return scheduled[i].CreationDate > scheduled[j].CreationDate
+	})
+	return scheduled
+}
+
+// prunableBackups returns the scheduled backups that are older than the
+// newest 'generations' fully uploaded scheduled backups. Backups that are
+// still uploading don't count as a generation, that way a failing upload never
+// causes the last good backups to be pruned.
+func prunableBackups(backups []modules.UploadedBackup, generations uint64) []modules.UploadedBackup {
+	var kept uint64
+	var prunable []modules.UploadedBackup
+	for _, ub := range scheduledBackups(backups) {
+		if kept >= generations {
+			prunable = append(prunable, ub)
+			continue
+		}
+		if ub.UploadProgress == 100 {
+			kept++
+		}
+	}
+	return prunable
+}
+
+// managedPrunedBackups returns the set of UIDs of pruned backups.
+func (r *Renter) managedPrunedBackups() map[[16]byte]struct{} {
+	id := r.mu.RLock()
+	defer r.mu.RUnlock(id)
+	pruned := make(map[[16]byte]struct{}, len(r.persist.PrunedBackups))
+	for _, uid := range r.persist.PrunedBackups {
+		pruned[uid] = struct{}{}
+	}
+	return pruned
+}
+
+// managedPruneBackup removes the backup with the given name from the renter's
+// uploaded backups and remembers its UID so that it is not synchronized with
+// hosts again.
+func (r *Renter) managedPruneBackup(name string) error {
+	id := r.mu.Lock()
+	index := -1
+	for i, ub := range r.persist.UploadedBackups {
+		if ub.Name == name {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		r.mu.Unlock(id)
+		return errBackupNotFound
+	}
+	uid := r.persist.UploadedBackups[index].UID
+	r.persist.UploadedBackups = append(r.persist.UploadedBackups[:index], r.persist.UploadedBackups[index+1:]...)
+	r.persist.PrunedBackups = append(r.persist.PrunedBackups, uid)
+	err := r.saveSync()
+	r.mu.Unlock(id)
+	if err != nil {
+		return errors.AddContext(err, "failed to save pruned backup")
+	}
+
+	// Delete the backup's siafile in case it is still being uploaded.
+	sp, err := modules.BackupFolder.Join(name)
+	if err != nil {
+		return err
+	}
+	err = r.staticFileSystem.DeleteFile(sp)
+	if err != nil && !errors.Contains(err, filesystem.ErrNotExist) {
+		return errors.AddContext(err, "failed to delete backup siafile")
+	}
+	return nil
+}
+
+// managedPruneScheduledBackups prunes the scheduled backups beyond the given
+// number of generations.
+func (r *Renter) managedPruneScheduledBackups(generations uint64) error {
+	id := r.mu.RLock()
+	backups := append([]modules.UploadedBackup(nil), r.persist.UploadedBackups...)
+	r.mu.RUnlock(id)
+
+	var errs []error
+	for _, ub := range prunableBackups(backups, generations) {
+		if err := r.managedPruneBackup(ub.Name); err != nil {
+			errs = append(errs, errors.AddContext(err, fmt.Sprintf("failed to prune backup '%v'", ub.Name)))
+			continue
+		}
+		r.log.Printf("Pruned scheduled backup '%v'", ub.Name)
+	}
+	return errors.Compose(errs...)
+}
+
+// managedCreateScheduledBackup creates an encrypted backup of the renter and
+// uploads it to hosts.
+func (r *Renter) managedCreateScheduledBackup() (modules.UploadedBackup, error) {
+	// Get the wallet seed.
+	ws, _, err := r.w.PrimarySeed()
+	if err != nil {
+		return modules.UploadedBackup{}, errors.AddContext(err, "failed to get wallet's primary seed")
+	}
+	// Derive the renter seed and wipe the memory once we are done using it.
+	rs := modules.DeriveRenterSeed(ws)
+	defer fastrand.Read(rs[:])
+	// Derive the secret and wipe it afterwards.
+	secret := crypto.HashAll(rs, modules.BackupKeySpecifier)
+	defer fastrand.Read(secret[:])
+
+	// Write the backup to the persist dir and delete it after uploading.
+	name := scheduledBackupName(time.Now())
+	backupPath := filepath.Join(r.persistDir, fmt.Sprintf("%v-%v.bak", name, persist.RandomSuffix()))
+	defer func() {
+		if err := os.RemoveAll(backupPath); err != nil {
+			r.log.Println("WARN: failed to remove scheduled backup file:", err)
+		}
+	}()
+	if err := r.managedCreateBackup(backupPath, secret[:32]); err != nil {
+		return modules.UploadedBackup{}, errors.AddContext(err, "failed to create backup")
+	}
+	if err := r.managedUploadBackup(backupPath, name); err != nil {
+		return modules.UploadedBackup{}, errors.AddContext(err, "failed to upload backup")
+	}
+
+	id := r.mu.RLock()
+	defer r.mu.RUnlock(id)
+	for _, ub := range r.persist.UploadedBackups {
+		if ub.Name == name {
+			return ub, nil
+		}
+	}
+	return modules.UploadedBackup{}, errBackupNotFound
+}
+
+// BackupScheduleStatus returns the settings of the renter's scheduled backups
+// and the scheduled backups the renter knows about.
+func (r *Renter) BackupScheduleStatus() (modules.BackupScheduleStatus, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.BackupScheduleStatus{}, err
+	}
+	defer r.tg.Done()
+	id := r.mu.RLock()
+	backups := scheduledBackups(r.persist.UploadedBackups)
+	r.mu.RUnlock(id)
+
+	bs := r.staticBackupSchedule
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return modules.BackupScheduleStatus{
+		BackupScheduleSettings: bs.persist.Settings,
+		InProgress:             bs.inProgress,
+		LastBackup:             bs.persist.LastBackup,
+		LastError:              bs.persist.LastError,
+		Backups:                backups,
+	}, nil
+}
+
+// PruneBackup removes an uploaded backup from the renter. Hosts drop the
+// backup the next time their snapshot table is updated.
+func (r *Renter) PruneBackup(name string) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	return r.managedPruneBackup(name)
+}
+
+// RunScheduledBackup creates and uploads a scheduled backup right away and
+// prunes scheduled backups beyond the configured generations. It returns once
+// the backup is queued for upload, the upload itself continues in the
+// background.
+func (r *Renter) RunScheduledBackup() (modules.UploadedBackup, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.UploadedBackup{}, err
+	}
+	defer r.tg.Done()
+	bs := r.staticBackupSchedule
+	bs.mu.Lock()
+	if bs.inProgress {
+		bs.mu.Unlock()
+		return modules.UploadedBackup{}, errScheduledBackupInProgress
+	}
+	bs.inProgress = true
+	generations := bs.persist.Settings.Generations
+	bs.mu.Unlock()
+	defer func() {
+		bs.mu.Lock()
+		bs.inProgress = false
+		bs.mu.Unlock()
+	}()
+
+	ub, err := r.managedCreateScheduledBackup()
+	if err == nil {
+		err = r.managedPruneScheduledBackups(generations)
+	}
+
+	// Report the result.
+	bs.mu.Lock()
+	bs.persist.LastBackup = time.Now()
+	bs.persist.LastError = ""
+	if err != nil {
+		bs.persist.LastError = err.Error()
+	}
+	saveErr := bs.save()
+	bs.mu.Unlock()
+	if err != nil {
+		r.staticAlerter.RegisterAlert(modules.AlertIDRenterScheduledBackupFailed, AlertMSGScheduledBackupFailed,
+			err.Error(), modules.SeverityError)
+		r.log.Println("Scheduled backup failed:", err)
+		return modules.UploadedBackup{}, errors.Compose(err, saveErr)
+	}
+	r.staticAlerter.UnregisterAlert(modules.AlertIDRenterScheduledBackupFailed)
+	r.log.Printf("Created scheduled backup '%v'", ub.Name)
+	return ub, saveErr
+}
+
+// SetBackupScheduleSettings updates the settings of the renter's scheduled
+// backups.
+func (r *Renter) SetBackupScheduleSettings(settings modules.BackupScheduleSettings) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	if settings.Interval == 0 {
+		settings.Interval = defaultBackupScheduleInterval
+	}
+	if settings.Interval < minBackupScheduleInterval {
+		return errors.AddContext(errBackupScheduleIntervalTooLow, "interval must be at least "+minBackupScheduleInterval.String())
+	}
+	if settings.Generations == 0 {
+		settings.Generations = defaultBackupGenerations
+	}
+	bs := r.staticBackupSchedule
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.persist.Settings = settings
+	return bs.save()
+}
+
+// threadedBackupScheduleLoop periodically creates scheduled backups if they
+// are enabled.
+func (r *Renter) threadedBackupScheduleLoop() {
+	if err := r.tg.Add(); err != nil {
+		return
+	}
+	defer r.tg.Done()
+	for {
+		select {
+		case <-r.tg.StopChan():
+			return
+		case <-time.After(backupScheduleCheckInterval):
+		}
+		// Backups are encrypted with a key derived from the wallet seed.
+		if unlocked, _ := r.w.Unlocked(); !unlocked {
+			continue
+		}
+		bs := r.staticBackupSchedule
+		bs.mu.Lock()
+		due := bs.due(time.Now())
+		bs.mu.Unlock()
+		if !due {
+			continue
+		}
+		_, err := r.RunScheduledBackup()
+		if err != nil {
+			r.log.Println("WARN: failed to run scheduled backup:", err)
+		}
+	}
+}
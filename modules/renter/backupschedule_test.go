@@ -0,0 +1,115 @@
+package renter
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestBackupSchedulePersist is a unit test for loading and saving the backup
+// schedule.
+func TestBackupSchedulePersist(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir("renter", t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh schedule should be disabled and use the defaults.
+	bs, err := newBackupSchedule(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := bs.persist.Settings
+	if settings.Enabled || settings.Interval != defaultBackupScheduleInterval || settings.Generations != defaultBackupGenerations {
+		t.Fatal("wrong default settings", settings)
+	}
+
+	// Update the settings and the last backup.
+	bs.persist.Settings = modules.BackupScheduleSettings{Enabled: true, Interval: time.Hour, Generations: 3}
+	bs.persist.LastBackup = time.Now()
+	bs.persist.LastError = "error"
+	if err := bs.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload and compare.
+	bs2, err := newBackupSchedule(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bs2.persist.Settings != bs.persist.Settings {
+		t.Fatal("settings don't match", bs2.persist.Settings, bs.persist.Settings)
+	}
+	if !bs2.persist.LastBackup.Equal(bs.persist.LastBackup) || bs2.persist.LastError != bs.persist.LastError {
+		t.Fatal("last backup doesn't match", bs2.persist, bs.persist)
+	}
+}
+
+// TestBackupScheduleDue is a unit test for deciding when the next scheduled
+// backup is due.
+func TestBackupScheduleDue(t *testing.T) {
+	t.Parallel()
+
+	bs := &backupSchedule{}
+	now := time.Now()
+	if bs.due(now) {
+		t.Fatal("disabled schedule shouldn't be due")
+	}
+	bs.persist.Settings = modules.BackupScheduleSettings{Enabled: true, Interval: time.Hour}
+	if !bs.due(now) {
+		t.Fatal("backup should be due if none was created yet")
+	}
+	bs.persist.LastBackup = now
+	if bs.due(now.Add(time.Minute)) || !bs.due(now.Add(time.Hour)) {
+		t.Fatal("backup should be due after the interval")
+	}
+	bs.inProgress = true
+	if bs.due(now.Add(time.Hour)) {
+		t.Fatal("backup shouldn't be due while one is in progress")
+	}
+}
+
+// TestPrunableBackups checks which scheduled backups are pruned.
+func TestPrunableBackups(t *testing.T) {
+	t.Parallel()
+
+	backup := func(name string, created types.Timestamp, progress float64) modules.UploadedBackup {
+		return modules.UploadedBackup{Name: name, CreationDate: created, UploadProgress: progress}
+	}
+	backups := []modules.UploadedBackup{
+		backup(scheduledBackupName(time.Unix(1, 0)), 1, 100),
+		backup("manual", 2, 100),
+		backup(scheduledBackupName(time.Unix(3, 0)), 3, 100),
+		backup(scheduledBackupName(time.Unix(5, 0)), 5, 50),
+		backup(scheduledBackupName(time.Unix(4, 0)), 4, 100),
+	}
+
+	// The scheduled backups are ordered from newest to oldest.
+	scheduled := scheduledBackups(backups)
+	if len(scheduled) != 4 || scheduled[0].CreationDate != 5 || scheduled[3].CreationDate != 1 {
+		t.Fatal("wrong scheduled backups", scheduled)
+	}
+
+	// Keeping 2 generations prunes the oldest one. The backup that is still
+	// uploading doesn't count as a generation and the manual one is never
+	// pruned.
+	prunable := prunableBackups(backups, 2)
+	if len(prunable) != 1 || prunable[0].CreationDate != 1 {
+		t.Fatal("wrong prunable backups", prunable)
+	}
+	if prunable := prunableBackups(backups, 1); len(prunable) != 2 {
+		t.Fatal("wrong prunable backups", prunable)
+	}
+	if prunable := prunableBackups(backups, 3); len(prunable) != 0 {
+		t.Fatal("nothing should be pruned", prunable)
+	}
+}
@@ -0,0 +1,290 @@
+package renter
+
+// chunkcache implements an on-disk LRU cache for the decoded chunks that are
+// downloaded by streamers. Repeated reads of the same region of a file, which
+// are common when files are accessed through FUSE or streamed by media
+// players, are served from disk instead of paying the hosts for the same
+// sectors again.
+//
+// Every chunk is stored in its own file within the cache directory. The file
+// name contains the UID of the siafile and the index of the chunk. Siafiles
+// never change the data of an uploaded chunk, so cached chunks don't need to
+// be invalidated. Chunks of deleted files are eventually evicted.
+
+import (
+	"container/list"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem/siafile"
+)
+
+const (
+	// chunkCacheDir is the name of the directory within the renter's persist
+	// directory that contains the cached chunks.
+	chunkCacheDir = "chunkcache"
+
+	// chunkCacheTmpSuffix is the suffix of chunks which are being written to
+	// the cache.
+	chunkCacheTmpSuffix = ".tmp"
+)
+
+type (
+	// chunkCacheKey identifies a chunk of a siafile.
+	chunkCacheKey struct {
+		uid        siafile.SiafileUID
+		chunkIndex uint64
+	}
+
+	// chunkCacheEntry is an element of the chunk cache's LRU list.
+	chunkCacheEntry struct {
+		key  chunkCacheKey
+		size uint64
+	}
+
+	// chunkCache is an on-disk LRU cache of decoded chunks. The front of the
+	// list is the most recently used chunk.
+	chunkCache struct {
+		entries map[chunkCacheKey]*list.Element
+		lru     *list.List
+		size    uint64
+		maxSize uint64
+
+		hits   uint64
+		misses uint64
+
+		staticDir string
+		mu        sync.Mutex
+	}
+)
+
+// newChunkCache loads the chunk cache from the provided directory. Chunks that
+// are found on disk are added to the cache in the order of their modification
+// time.
+func newChunkCache(dir string, maxSize uint64) (*chunkCache, error) {
+	cc := &chunkCache{
+		entries:   make(map[chunkCacheKey]*list.Element),
+		lru:       list.New(),
+		maxSize:   maxSize,
+		staticDir: dir,
+	}
+	err := os.MkdirAll(dir, modules.DefaultDirPerm)
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to create chunk cache dir")
+	}
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to read chunk cache dir")
+	}
+	sort.Slice(fis, func(i, j int) bool {
+		return fis[i].ModTime().Before(fis[j].ModTime())
+	})
+	for _, fi := range fis {
+		key, err := parseChunkCacheFilename(fi.Name())
+		if err != nil || fi.IsDir() {
+			// Remove leftovers of interrupted writes and unknown files.
+			err = os.RemoveAll(filepath.Join(dir, fi.Name()))
+			if err != nil {
+				return nil, errors.AddContext(err, "failed to remove invalid chunk cache file")
+			}
+			continue
+		}
+		cc.entries[key] = cc.lru.PushFront(&chunkCacheEntry{key: key, size: uint64(fi.Size())})
+		cc.size += uint64(fi.Size())
+	}
+	// The limit might have been lowered since the last time the cache was
+	// used.
+	return cc, cc.evict()
+}
+
+// chunkCacheFilename returns the name of the file that stores a chunk.
+func chunkCacheFilename(key chunkCacheKey) string {
+	return fmt.Sprintf("%s_%d", hex.EncodeToString([]byte(key.uid)), key.chunkIndex)
+}
+
+// parseChunkCacheFilename parses the key of a chunk from the name of the file
+// that stores it.
+func parseChunkCacheFilename(name string) (chunkCacheKey, error) {
+	parts := strings.Split(name, "_")
+	if len(parts) != 2 {
+		return chunkCacheKey{}, fmt.Errorf("invalid chunk cache filename '%v'", name)
+	}
+	uid, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return chunkCacheKey{}, errors.AddContext(err, "invalid uid in chunk cache filename")
+	}
+	chunkIndex, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return chunkCacheKey{}, errors.AddContext(err, "invalid chunk index in chunk cache filename")
+	}
+	return chunkCacheKey{uid: siafile.SiafileUID(uid), chunkIndex: chunkIndex}, nil
+}
+
+// evict removes the least recently used chunks until the cache is within its
+// size limit.
+func (cc *chunkCache) evict() error {
+	var err error
+	for cc.size > cc.maxSize {
+		entry := cc.lru.Remove(cc.lru.Back()).(*chunkCacheEntry)
+		delete(cc.entries, entry.key)
+		cc.size -= entry.size
+		err = errors.Compose(err, os.Remove(filepath.Join(cc.staticDir, chunkCacheFilename(entry.key))))
+	}
+	return err
+}
+
+// callAdd adds a chunk to the cache. Chunks that are larger than the cache are
+// ignored.
+func (cc *chunkCache) callAdd(key chunkCacheKey, data []byte) error {
+	cc.mu.Lock()
+	_, exists := cc.entries[key]
+	enabled := cc.maxSize >= uint64(len(data))
+	cc.mu.Unlock()
+	if exists || !enabled {
+		return nil
+	}
+
+	// Write the chunk to a temporary file first to avoid blocking the cache
+	// while writing to disk.
+	path := filepath.Join(cc.staticDir, chunkCacheFilename(key))
+	tmpPath := fmt.Sprintf("%s_%x%s", path, fastrand.Bytes(4), chunkCacheTmpSuffix)
+	err := ioutil.WriteFile(tmpPath, data, modules.DefaultFilePerm)
+	if err != nil {
+		return errors.Compose(err, os.Remove(tmpPath))
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if _, exists := cc.entries[key]; exists || cc.maxSize < uint64(len(data)) {
+		return os.Remove(tmpPath)
+	}
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return errors.Compose(err, os.Remove(tmpPath))
+	}
+	cc.entries[key] = cc.lru.PushFront(&chunkCacheEntry{key: key, size: uint64(len(data))})
+	cc.size += uint64(len(data))
+	return cc.evict()
+}
+
+// callRead returns the cached data of a chunk. The returned bool indicates
+// whether the chunk was found in the cache.
+func (cc *chunkCache) callRead(key chunkCacheKey) ([]byte, bool) {
+	cc.mu.Lock()
+	elem, exists := cc.entries[key]
+	if !exists {
+		cc.misses++
+		cc.mu.Unlock()
+		return nil, false
+	}
+	cc.lru.MoveToFront(elem)
+	size := elem.Value.(*chunkCacheEntry).size
+	cc.mu.Unlock()
+
+	// The chunk might be evicted before it is read, which counts as a miss.
+	data, err := ioutil.ReadFile(filepath.Join(cc.staticDir, chunkCacheFilename(key)))
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if err != nil || uint64(len(data)) != size {
+		cc.misses++
+		return nil, false
+	}
+	cc.hits++
+	return data, true
+}
+
+// callEnabled returns whether the cache can store any chunks.
+func (cc *chunkCache) callEnabled() bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.maxSize > 0
+}
+
+// callSetMaxSize updates the size limit of the cache. Chunks are evicted until
+// the cache is within the new limit.
+func (cc *chunkCache) callSetMaxSize(maxSize uint64) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.maxSize = maxSize
+	return cc.evict()
+}
+
+// callStats returns statistics about the cache.
+func (cc *chunkCache) callStats() modules.ChunkCacheStats {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return modules.ChunkCacheStats{
+		Size:      cc.size,
+		MaxSize:   cc.maxSize,
+		NumChunks: uint64(len(cc.entries)),
+		Hits:      cc.hits,
+		Misses:    cc.misses,
+	}
+}
+
+// managedReadCachedRange returns the data of the provided range of a file if
+// all of the chunks that overlap with the range are cached.
+func (r *Renter) managedReadCachedRange(file *siafile.Snapshot, offset, length uint64) ([]byte, bool) {
+	if length == 0 || !r.staticChunkCache.callEnabled() {
+		return nil, false
+	}
+	chunkSize := file.ChunkSize()
+	firstChunk := offset / chunkSize
+	lastChunk := (offset + length - 1) / chunkSize
+	data := make([]byte, 0, (lastChunk-firstChunk+1)*chunkSize)
+	for chunkIndex := firstChunk; chunkIndex <= lastChunk; chunkIndex++ {
+		chunk, cached := r.staticChunkCache.callRead(chunkCacheKey{uid: file.UID(), chunkIndex: chunkIndex})
+		if !cached {
+			return nil, false
+		}
+		data = append(data, chunk...)
+	}
+	start := offset - firstChunk*chunkSize
+	if start+length > uint64(len(data)) {
+		return nil, false
+	}
+	return data[start : start+length], true
+}
+
+// managedCacheRange adds the chunks of a downloaded range of a file to the
+// chunk cache. Only chunks that are fully contained within the range are
+// cached.
+func (r *Renter) managedCacheRange(file *siafile.Snapshot, offset uint64, data []byte) {
+	chunkSize := file.ChunkSize()
+	chunkIndex := offset / chunkSize
+	if offset%chunkSize != 0 {
+		chunkIndex++
+	}
+	for ; chunkIndex*chunkSize < offset+uint64(len(data)); chunkIndex++ {
+		start := chunkIndex*chunkSize - offset
+		end := start + chunkSize
+		if chunkIndex*chunkSize+chunkSize > file.Size() {
+			// The last chunk of a file is shorter than the chunk size.
+			end = file.Size() - offset
+		}
+		if end > uint64(len(data)) {
+			return
+		}
+		err := r.staticChunkCache.callAdd(chunkCacheKey{uid: file.UID(), chunkIndex: chunkIndex}, data[start:end])
+		if err != nil {
+			r.log.Println("WARN: failed to add chunk to chunk cache:", err)
+			return
+		}
+	}
+}
+
+// ChunkCacheStats returns statistics about the renter's chunk cache.
+func (r *Renter) ChunkCacheStats() modules.ChunkCacheStats {
+	return r.staticChunkCache.callStats()
+}
@@ -0,0 +1,130 @@
+package renter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/build"
+)
+
+// TestChunkCache is a unit test for the chunkCache.
+func TestChunkCache(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir("renter", t.Name())
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	cc, err := newChunkCache(dir, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := func(chunkIndex uint64) chunkCacheKey {
+		return chunkCacheKey{uid: "uid", chunkIndex: chunkIndex}
+	}
+
+	// Add 3 chunks which fill the cache.
+	chunks := [][]byte{fastrand.Bytes(10), fastrand.Bytes(10), fastrand.Bytes(10)}
+	for i, chunk := range chunks {
+		if err := cc.callAdd(key(uint64(i)), chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, chunk := range chunks {
+		data, cached := cc.callRead(key(uint64(i)))
+		if !cached || !bytes.Equal(data, chunk) {
+			t.Fatal("chunk wasn't cached correctly", i)
+		}
+	}
+	if _, cached := cc.callRead(key(3)); cached {
+		t.Fatal("chunk shouldn't be cached")
+	}
+
+	// Reading chunk 0 makes chunk 1 the least recently used chunk, which is
+	// evicted when another chunk is added.
+	if _, cached := cc.callRead(key(0)); !cached {
+		t.Fatal("chunk should be cached")
+	}
+	if err := cc.callAdd(key(3), fastrand.Bytes(10)); err != nil {
+		t.Fatal(err)
+	}
+	if _, cached := cc.callRead(key(1)); cached {
+		t.Fatal("least recently used chunk wasn't evicted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, chunkCacheFilename(key(1)))); !os.IsNotExist(err) {
+		t.Fatal("evicted chunk wasn't removed from disk", err)
+	}
+	stats := cc.callStats()
+	if stats.Size != 30 || stats.NumChunks != 3 || stats.Hits != 4 || stats.Misses != 2 {
+		t.Fatal("wrong stats", stats)
+	}
+
+	// Chunks that are larger than the cache are ignored.
+	if err := cc.callAdd(key(4), fastrand.Bytes(31)); err != nil {
+		t.Fatal(err)
+	}
+	if _, cached := cc.callRead(key(4)); cached {
+		t.Fatal("chunk larger than the cache shouldn't be cached")
+	}
+
+	// Reloading the cache keeps the chunks and removes unknown files. A
+	// smaller limit evicts the least recently written chunk.
+	if err := ioutil.WriteFile(filepath.Join(dir, "unknown"+chunkCacheTmpSuffix), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	cc, err = newChunkCache(dir, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := cc.callStats(); stats.Size != 20 || stats.NumChunks != 2 {
+		t.Fatal("wrong stats after reload", stats)
+	}
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fis) != 2 {
+		t.Fatal("wrong number of files in cache dir", len(fis))
+	}
+
+	// Disabling the cache removes all chunks.
+	if err := cc.callSetMaxSize(0); err != nil {
+		t.Fatal(err)
+	}
+	if cc.callEnabled() || cc.callStats().NumChunks != 0 {
+		t.Fatal("cache should be empty and disabled")
+	}
+	if err := cc.callAdd(key(0), chunks[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, cached := cc.callRead(key(0)); cached {
+		t.Fatal("disabled cache shouldn't cache chunks")
+	}
+}
+
+// TestChunkCacheFilename probes chunkCacheFilename and
+// parseChunkCacheFilename.
+func TestChunkCacheFilename(t *testing.T) {
+	t.Parallel()
+
+	key := chunkCacheKey{uid: "0123abcd", chunkIndex: 42}
+	parsed, err := parseChunkCacheFilename(chunkCacheFilename(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != key {
+		t.Fatal("wrong key", parsed)
+	}
+	for _, name := range []string{"", "abc", "zz_1", "00_x", "00_1_2", "00_1.tmp"} {
+		if _, err := parseChunkCacheFilename(name); err == nil {
+			t.Errorf("'%v' should be invalid", name)
+		}
+	}
+}
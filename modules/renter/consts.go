@@ -21,6 +21,12 @@ const (
 	// AlertSiafileLowRedundancyThreshold is the health threshold at which we start
 	// registering the LowRedundancy alert for a Siafile.
 	AlertSiafileLowRedundancyThreshold = 0.75
+	// AlertMSGRestoreDrillFailed indicates that a restore drill failed to
+	// download or verify a file.
+	AlertMSGRestoreDrillFailed = "A restore drill failed to download or verify the SiaFile mentioned in the 'Cause'"
+	// AlertMSGScheduledBackupFailed indicates that a scheduled backup failed
+	// to be created or uploaded.
+	AlertMSGScheduledBackupFailed = "A scheduled backup failed to be created or uploaded"
 )
 
 // AlertCauseSiafileLowRedundancy creates a customized "cause" for a siafile
@@ -29,6 +35,12 @@ func AlertCauseSiafileLowRedundancy(siaPath modules.SiaPath, health, redundancy
 	return fmt.Sprintf("Siafile '%v' has a health of %v and redundancy of %v", siaPath.String(), health, redundancy)
 }
 
+// AlertCauseRestoreDrillFailed creates a customized "cause" for a failed
+// restore drill of a siafile with a certain path.
+func AlertCauseRestoreDrillFailed(siaPath modules.SiaPath, reason string) string {
+	return fmt.Sprintf("Restore drill of siafile '%v' failed: %v", siaPath.String(), reason)
+}
+
 // Default redundancy parameters.
 var (
 	// syncCheckInterval is how often the repair heap checks the consensus code
@@ -30,14 +30,23 @@ type churnLimiter struct {
 	// churned in the current period.
 	aggregateCurrentPeriodChurn uint64
 
+	// churnedContracts are the migrations of the contracts churned in the
+	// current period.
+	churnedContracts []modules.ContractorMigration
+
+	// deferredContracts are the migrations that the churnLimiter avoided
+	// during the most recent round of contract maintenance.
+	deferredContracts []modules.ContractorMigration
+
 	mu         sync.Mutex
 	contractor *Contractor
 }
 
 // churnLimiterPersist is the persisted state of a churnLimiter.
 type churnLimiterPersist struct {
-	AggregateCurrentPeriodChurn uint64 `json:"aggregatecurrentperiodchurn"`
-	RemainingChurnBudget        int    `json:"remainingchurnbudget"`
+	AggregateCurrentPeriodChurn uint64                        `json:"aggregatecurrentperiodchurn"`
+	RemainingChurnBudget        int                           `json:"remainingchurnbudget"`
+	ChurnedContracts            []modules.ContractorMigration `json:"churnedcontracts"`
 }
 
 // managedMaxPeriodChurn returns the MaxPeriodChurn of the churnLimiter.
@@ -50,7 +59,11 @@ func (cl *churnLimiter) managedMaxPeriodChurn() uint64 {
 func (cl *churnLimiter) callPersistData() churnLimiterPersist {
 	cl.mu.Lock()
 	defer cl.mu.Unlock()
-	return churnLimiterPersist{cl.aggregateCurrentPeriodChurn, cl.remainingChurnBudget}
+	return churnLimiterPersist{
+		AggregateCurrentPeriodChurn: cl.aggregateCurrentPeriodChurn,
+		RemainingChurnBudget:        cl.remainingChurnBudget,
+		ChurnedContracts:            append([]modules.ContractorMigration{}, cl.churnedContracts...),
+	}
 }
 
 // newChurnLimiterFromPersist creates a new churnLimiter using persisted state.
@@ -59,6 +72,7 @@ func newChurnLimiterFromPersist(contractor *Contractor, persistData churnLimiter
 		contractor:                  contractor,
 		aggregateCurrentPeriodChurn: persistData.AggregateCurrentPeriodChurn,
 		remainingChurnBudget:        persistData.RemainingChurnBudget,
+		churnedContracts:            persistData.ChurnedContracts,
 	}
 }
 
@@ -83,12 +97,14 @@ func (cl *churnLimiter) callResetAggregateChurn() {
 	cl.mu.Lock()
 	cl.contractor.log.Println("Aggregate Churn for last period: ", cl.aggregateCurrentPeriodChurn)
 	cl.aggregateCurrentPeriodChurn = 0
+	cl.churnedContracts = nil
 	cl.mu.Unlock()
 }
 
 // callNotifyChurnedContract adds the size of this contract's files to the aggregate
-// churn in this period. Must be called when contracts are marked !GFR.
-func (cl *churnLimiter) callNotifyChurnedContract(contract modules.RenterContract) {
+// churn in this period and records the contract's migration. Must be called
+// when contracts are marked !GFR.
+func (cl *churnLimiter) callNotifyChurnedContract(contract modules.RenterContract, migration modules.ContractorMigration) {
 	size := contract.Transaction.FileContractRevisions[0].NewFileSize
 	if size == 0 {
 		return
@@ -98,6 +114,13 @@ func (cl *churnLimiter) callNotifyChurnedContract(contract modules.RenterContrac
 	cl.mu.Lock()
 	defer cl.mu.Unlock()
 
+	cl.churnedContracts = append(cl.churnedContracts, migration)
+	for i, m := range cl.deferredContracts {
+		if m.ContractID == contract.ID {
+			cl.deferredContracts = append(cl.deferredContracts[:i:i], cl.deferredContracts[i+1:]...)
+			break
+		}
+	}
 	cl.aggregateCurrentPeriodChurn += size
 	cl.remainingChurnBudget -= int(size)
 	cl.contractor.log.Debugf("Increasing aggregate churn by %d to %d (MaxPeriodChurn: %d)", size, cl.aggregateCurrentPeriodChurn, maxPeriodChurn)
@@ -143,6 +166,13 @@ func (cl *churnLimiter) managedProcessSuggestedUpdates(queue []contractScoreAndU
 		return queue[i].score.Cmp(queue[j].score) < 0
 	})
 
+	var deferred []modules.ContractorMigration
+	defer func() {
+		cl.mu.Lock()
+		cl.deferredContracts = deferred
+		cl.mu.Unlock()
+	}()
+
 	var queuedContract contractScoreAndUtil
 	for len(queue) > 0 {
 		queuedContract, queue = queue[0], queue[1:]
@@ -157,6 +187,11 @@ func (cl *churnLimiter) managedProcessSuggestedUpdates(queue []contractScoreAndU
 			currentBudget, periodBudget := cl.managedChurnBudget()
 			cl.contractor.log.Debugf("Remaining Churn Budget: %d. Remaining Period Budget: %d", currentBudget, periodBudget)
 			queuedContract.util.GoodForRenew = true
+
+			migration := cl.contractor.managedNewMigration(queuedContract.contract, queuedContract.util)
+			migration.Reason = churnReasonLowScore
+			migration.Deferred = true
+			deferred = append(deferred, migration)
 		}
 
 		if churningThisContract {
@@ -1,9 +1,12 @@
 package contractor
 
 import (
+	"io/ioutil"
+	"math"
 	"testing"
 
 	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
 	"go.sia.tech/siad/types"
 )
 
@@ -81,3 +84,73 @@ func TestCanChurnContract(t *testing.T) {
 		t.Fatal("Expected not to be able to churn contract")
 	}
 }
+
+// TestMigrationPlan probes the churn reasons, the migration cost estimates and
+// the tracking of churned and deferred migrations.
+func TestMigrationPlan(t *testing.T) {
+	t.Parallel()
+
+	// Check the churn reasons.
+	contract := contractWithSize(100)
+	host := modules.HostDBEntry{ScanHistory: modules.HostDBScans{{Success: true}}}
+	offline := modules.HostDBEntry{ScanHistory: modules.HostDBScans{{Success: false}}}
+	maxRevision := contractWithSize(100)
+	maxRevision.Transaction.FileContractRevisions[0].NewRevisionNumber = math.MaxUint64
+	tests := []struct {
+		contract modules.RenterContract
+		u        modules.ContractUtility
+		host     modules.HostDBEntry
+		exists   bool
+		reason   string
+	}{
+		{contract, modules.ContractUtility{BadContract: true}, host, true, churnReasonBadContract},
+		{maxRevision, modules.ContractUtility{Locked: true}, host, true, churnReasonMaxRevision},
+		{contract, modules.ContractUtility{Locked: true}, host, true, churnReasonCanceled},
+		{contract, modules.ContractUtility{}, host, false, churnReasonHostMissing},
+		{contract, modules.ContractUtility{}, modules.HostDBEntry{Filtered: true}, true, churnReasonFiltered},
+		{contract, modules.ContractUtility{}, offline, true, churnReasonOffline},
+		{contract, modules.ContractUtility{}, host, true, churnReasonLowScore},
+	}
+	for _, test := range tests {
+		if reason := churnReason(test.contract, test.u, test.host, test.exists); reason != test.reason {
+			t.Errorf("expected reason '%v' but got '%v'", test.reason, reason)
+		}
+	}
+
+	// The cost estimate uses the average prices of the hosts.
+	var h1, h2 modules.HostDBEntry
+	h1.UploadBandwidthPrice, h1.StoragePrice = types.NewCurrency64(1), types.NewCurrency64(2)
+	h2.UploadBandwidthPrice, h2.StoragePrice = types.NewCurrency64(3), types.NewCurrency64(4)
+	if cost := estimateMigrationCost(10, []modules.HostDBEntry{h1, h2}, 5); !cost.Equals64(10*2 + 10*3*5) {
+		t.Fatal("wrong cost estimate", cost)
+	}
+	if cost := estimateMigrationCost(10, nil, 5); !cost.IsZero() {
+		t.Fatal("cost should be zero without hosts", cost)
+	}
+
+	// Churned migrations are listed before deferred ones. Deferred migrations
+	// which are churned are no longer deferred.
+	logger, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl := newChurnLimiter(&Contractor{allowance: modules.DefaultAllowance, log: logger})
+	cl.deferredContracts = []modules.ContractorMigration{
+		{ContractID: types.FileContractID{1}, Deferred: true},
+		{ContractID: types.FileContractID{2}, Deferred: true},
+	}
+	churned := contractWithSize(100)
+	churned.ID = types.FileContractID{2}
+	cl.callNotifyChurnedContract(churned, modules.ContractorMigration{ContractID: types.FileContractID{2}})
+	cl.callNotifyChurnedContract(contractWithSize(0), modules.ContractorMigration{ContractID: types.FileContractID{3}})
+	migrations := cl.callMigrationPlan()
+	if len(migrations) != 2 || migrations[0].ContractID != (types.FileContractID{2}) || migrations[0].Deferred || migrations[1].ContractID != (types.FileContractID{1}) {
+		t.Fatal("wrong migrations", migrations)
+	}
+
+	// A new period resets the churned migrations.
+	cl.callResetAggregateChurn()
+	if migrations := cl.callMigrationPlan(); len(migrations) != 1 || !migrations[0].Deferred {
+		t.Fatal("churned migrations weren't reset", migrations)
+	}
+}
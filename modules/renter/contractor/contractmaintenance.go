@@ -348,6 +348,9 @@ func (c *Contractor) managedFindMinAllowedHostScores() (types.Currency, types.Cu
 // managedNewContract negotiates an initial file contract with the specified
 // host, saves it, and returns it.
 func (c *Contractor) managedNewContract(host modules.HostDBEntry, contractFunding types.Currency, endHeight types.BlockHeight) (_ types.Currency, _ modules.RenterContract, err error) {
+	if c.ReadOnly() {
+		return types.ZeroCurrency, modules.RenterContract{}, ErrReadOnly
+	}
 	// reject hosts that are too expensive
 	if host.StoragePrice.Cmp(maxStoragePrice) > 0 {
 		return types.ZeroCurrency, modules.RenterContract{}, errTooExpensive
@@ -594,6 +597,9 @@ func checkFormContractGouging(allowance modules.Allowance, hostSettings modules.
 // It returns the new contract. This is a blocking call that performs network
 // I/O.
 func (c *Contractor) managedRenew(id types.FileContractID, hpk types.SiaPublicKey, contractFunding types.Currency, newEndHeight types.BlockHeight, hostSettings modules.HostExternalSettings) (_ modules.RenterContract, err error) {
+	if c.ReadOnly() {
+		return modules.RenterContract{}, ErrReadOnly
+	}
 	// Fetch the host associated with this contract.
 	host, ok, err := c.hdb.Host(hpk)
 	if err != nil {
@@ -982,7 +988,7 @@ func (c *Contractor) callUpdateUtility(safeContract *proto.SafeContract, newUtil
 
 	// If the contract is going from GFR to !GFR, notify the churn limiter.
 	if !renewed && contract.Utility.GoodForRenew && !newUtility.GoodForRenew {
-		c.staticChurnLimiter.callNotifyChurnedContract(contract)
+		c.staticChurnLimiter.callNotifyChurnedContract(contract, c.managedNewMigration(contract, newUtility))
 	}
 
 	return safeContract.UpdateUtility(newUtility)
@@ -1064,6 +1070,12 @@ func (c *Contractor) threadedContractMaintenance() {
 		return
 	}
 
+	// No contracts are formed or renewed in read-only mode.
+	if c.ReadOnly() {
+		c.log.Debugln("Exiting contract maintenance because the renter is in read-only mode.")
+		return
+	}
+
 	// The rest of this function needs to know a few of the stateful variables
 	// from the contractor, build those up under a lock so that the rest of the
 	// function can execute without lock contention.
@@ -1385,6 +1397,25 @@ func (c *Contractor) threadedContractMaintenance() {
 	}
 	c.log.Debugln("trying to form contracts with hosts, pulled this many hosts from hostdb:", len(hosts))
 
+	// Try hosts in new subnets first if the renter's contracts don't span the
+	// allowance's minimum number of subnets yet.
+	c.mu.RLock()
+	minSubnets := c.allowance.MinSubnets
+	c.mu.RUnlock()
+	if minSubnets > 0 {
+		var contracted []modules.HostDBEntry
+		for _, contract := range allContracts {
+			if !contract.Utility.GoodForUpload {
+				continue
+			}
+			host, exists, err := c.hdb.Host(contract.HostPublicKey)
+			if err == nil && exists {
+				contracted = append(contracted, host)
+			}
+		}
+		hosts = prioritizeSubnets(hosts, contracted, minSubnets)
+	}
+
 	// Calculate the anticipated transaction fee.
 	_, maxFee := c.tpool.FeeEstimation()
 	txnFee := maxFee.Mul64(modules.EstimatedFileContractTransactionSetSize)
@@ -1465,7 +1496,9 @@ func (c *Contractor) threadedContractMaintenance() {
 			c.log.Println("Collateral Adjustment: ", sb.CollateralAdjustment)
 			c.log.Println("Duration Adjustment:   ", sb.DurationAdjustment)
 			c.log.Println("Interaction Adjustment:", sb.InteractionAdjustment)
+			c.log.Println("Latency Adjustment:    ", sb.LatencyAdjustment)
 			c.log.Println("Price Adjustment:      ", sb.PriceAdjustment)
+			c.log.Println("Region Adjustment:     ", sb.RegionAdjustment)
 			c.log.Println("Storage Adjustment:    ", sb.StorageRemainingAdjustment)
 			c.log.Println("Uptime Adjustment:     ", sb.UptimeAdjustment)
 			c.log.Println("Version Adjustment:    ", sb.VersionAdjustment)
@@ -77,6 +77,10 @@ type Contractor struct {
 	currentPeriod types.BlockHeight
 	lastChange    modules.ConsensusChangeID
 
+	// readOnly indicates whether contract maintenance is disabled because
+	// the renter is in read-only mode.
+	readOnly bool
+
 	// recentRecoveryChange is the first ConsensusChange that was missed while
 	// trying to find recoverable contracts. This is where we need to start
 	// rescanning the blockchain for recoverable contracts the next time the wallet
@@ -106,6 +110,7 @@ type Contractor struct {
 	renewedTo            map[types.FileContractID]types.FileContractID
 
 	staticChurnLimiter *churnLimiter
+	staticHostLists    *hostLists
 	staticWatchdog     *watchdog
 }
 
@@ -468,16 +473,19 @@ func New(cs modules.ConsensusSet, wallet modules.Wallet, tpool modules.Transacti
 
 // contractorBlockingStartup handles the blocking portion of NewCustomContractor.
 func contractorBlockingStartup(cs modules.ConsensusSet, w modules.Wallet, tp modules.TransactionPool, hdb modules.HostDB, persistDir string, contractSet *proto.ContractSet, l *persist.Logger, deps modules.Dependencies) (*Contractor, error) {
-	// Create the Contractor object.
+	// Create the Contractor object. The hostdb is wrapped to apply the
+	// contractor's host lists.
+	hostLists := newHostLists(deps.Resolver())
 	c := &Contractor{
-		staticAlerter: modules.NewAlerter("contractor"),
-		cs:            cs,
-		staticDeps:    deps,
-		hdb:           hdb,
-		log:           l,
-		persistDir:    persistDir,
-		tpool:         tp,
-		wallet:        w,
+		staticAlerter:   modules.NewAlerter("contractor"),
+		cs:              cs,
+		staticDeps:      deps,
+		hdb:             &hostListsDB{HostDB: hdb, staticHostLists: hostLists},
+		log:             l,
+		persistDir:      persistDir,
+		staticHostLists: hostLists,
+		tpool:           tp,
+		wallet:          w,
 
 		interruptMaintenance: make(chan struct{}),
 		synced:               make(chan struct{}),
@@ -498,6 +506,12 @@ func contractorBlockingStartup(cs modules.ConsensusSet, w modules.Wallet, tp mod
 	c.staticChurnLimiter = newChurnLimiter(c)
 	c.staticWatchdog = newWatchdog(c)
 
+	// Log the refcounters which had to be resized when the contracts were
+	// loaded.
+	for _, r := range contractSet.RefCounterRepairs() {
+		c.log.Printf("WARN: refcounter of contract %v had %v counts but the contract has %v sectors, resized the refcounter", r.ID, r.OldSectors, r.NewSectors)
+	}
+
 	// Close the contract set and logger upon shutdown.
 	err := c.tg.AfterStop(func() error {
 		if err := c.staticContracts.Close(); err != nil {
@@ -685,6 +699,9 @@ func newPayByContractRequest(rev types.FileContractRevision, sig crypto.Signatur
 // RenewContract takes an established connection to a host and renews the
 // contract with that host.
 func (c *Contractor) RenewContract(conn net.Conn, fcid types.FileContractID, params modules.ContractParams, txnBuilder modules.TransactionBuilder, tpool modules.TransactionPool, hdb modules.HostDB, pt *modules.RPCPriceTable) (modules.RenterContract, []types.Transaction, error) {
+	if c.ReadOnly() {
+		return modules.RenterContract{}, nil, ErrReadOnly
+	}
 	newContract, txnSet, err := c.staticContracts.RenewContract(conn, fcid, params, txnBuilder, tpool, hdb, pt)
 	if err != nil {
 		return modules.RenterContract{}, nil, errors.AddContext(err, "RenewContract: failed to renew contract")
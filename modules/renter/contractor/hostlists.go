@@ -0,0 +1,244 @@
+package contractor
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// errEmptyHostListAddress is returned if a host list contains an empty
+	// net address.
+	errEmptyHostListAddress = errors.New("host list contains an empty net address")
+)
+
+type (
+	// hostMatcher matches hosts against a modules.HostList.
+	hostMatcher struct {
+		publicKeys   map[string]struct{}
+		netAddresses map[string]struct{}
+		subnets      []*net.IPNet
+	}
+
+	// hostLists contains the contractor's host allowlist and denylist.
+	hostLists struct {
+		lists modules.ContractorHostLists
+		allow hostMatcher
+		deny  hostMatcher
+		mu    sync.Mutex
+
+		// staticResolver resolves the hostnames of hosts to match them
+		// against the subnets of the lists.
+		staticResolver modules.Resolver
+	}
+
+	// hostListsDB wraps the contractor's hostdb to apply the contractor's
+	// host lists. Hosts that are not allowed are reported as filtered and are
+	// never returned as random hosts, which excludes them from new contracts
+	// and marks their existing contracts as having no utility.
+	hostListsDB struct {
+		modules.HostDB
+		staticHostLists *hostLists
+	}
+)
+
+// newHostMatcher creates a hostMatcher for a host list.
+func newHostMatcher(hl modules.HostList) (hostMatcher, error) {
+	hm := hostMatcher{
+		publicKeys:   make(map[string]struct{}),
+		netAddresses: make(map[string]struct{}),
+	}
+	for _, pk := range hl.PublicKeys {
+		hm.publicKeys[pk.String()] = struct{}{}
+	}
+	for _, addr := range hl.NetAddresses {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			return hostMatcher{}, errEmptyHostListAddress
+		}
+		if strings.Contains(addr, "/") {
+			_, subnet, err := net.ParseCIDR(addr)
+			if err != nil {
+				return hostMatcher{}, errors.AddContext(err, "invalid subnet in host list")
+			}
+			hm.subnets = append(hm.subnets, subnet)
+			continue
+		}
+		hm.netAddresses[addr] = struct{}{}
+	}
+	return hm, nil
+}
+
+// empty returns whether the matcher doesn't match any hosts.
+func (hm hostMatcher) empty() bool {
+	return len(hm.publicKeys) == 0 && len(hm.netAddresses) == 0 && len(hm.subnets) == 0
+}
+
+// matches returns whether the host is on the list. Hosts which are addressed
+// by a hostname are matched against the subnets of the list using the IP
+// addresses the hostname resolves to, like the hostdb does when it filters
+// hosts by their subnets.
+func (hm hostMatcher) matches(host modules.HostDBEntry, resolver modules.Resolver) bool {
+	if _, exists := hm.publicKeys[host.PublicKey.String()]; exists {
+		return true
+	}
+	if _, exists := hm.netAddresses[string(host.NetAddress)]; exists {
+		return true
+	}
+	hostname := host.NetAddress.Host()
+	if _, exists := hm.netAddresses[hostname]; exists {
+		return true
+	}
+	if len(hm.subnets) == 0 {
+		return false
+	}
+	ips := []net.IP{net.ParseIP(hostname)}
+	if ips[0] == nil {
+		var err error
+		ips, err = resolver.LookupIP(hostname)
+		if err != nil {
+			return false
+		}
+	}
+	for _, subnet := range hm.subnets {
+		for _, ip := range ips {
+			if subnet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newHostLists creates empty host lists which allow all hosts.
+func newHostLists(resolver modules.Resolver) *hostLists {
+	hl := &hostLists{staticResolver: resolver}
+	_ = hl.callSet(modules.ContractorHostLists{})
+	return hl
+}
+
+// callAllowed returns whether the contractor may use the host.
+func (hl *hostLists) callAllowed(host modules.HostDBEntry) bool {
+	// The matchers are replaced but never modified, so hostnames can be
+	// resolved without holding the lock.
+	hl.mu.Lock()
+	allow, deny := hl.allow, hl.deny
+	hl.mu.Unlock()
+	if deny.matches(host, hl.staticResolver) {
+		return false
+	}
+	return allow.empty() || allow.matches(host, hl.staticResolver)
+}
+
+// callActive returns whether the host lists restrict the hosts the contractor
+// may use.
+func (hl *hostLists) callActive() bool {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+	return !hl.allow.empty() || !hl.deny.empty()
+}
+
+// callLists returns a copy of the host lists.
+func (hl *hostLists) callLists() modules.ContractorHostLists {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+	copyList := func(l modules.HostList) modules.HostList {
+		return modules.HostList{
+			PublicKeys:   append([]types.SiaPublicKey{}, l.PublicKeys...),
+			NetAddresses: append([]string{}, l.NetAddresses...),
+		}
+	}
+	return modules.ContractorHostLists{
+		Allowlist: copyList(hl.lists.Allowlist),
+		Denylist:  copyList(hl.lists.Denylist),
+	}
+}
+
+// callSet replaces the host lists.
+func (hl *hostLists) callSet(lists modules.ContractorHostLists) error {
+	allow, err := newHostMatcher(lists.Allowlist)
+	if err != nil {
+		return errors.AddContext(err, "invalid allowlist")
+	}
+	deny, err := newHostMatcher(lists.Denylist)
+	if err != nil {
+		return errors.AddContext(err, "invalid denylist")
+	}
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+	hl.lists = lists
+	hl.allow = allow
+	hl.deny = deny
+	return nil
+}
+
+// Host returns the host with the provided public key. Hosts that are not
+// allowed by the host lists are reported as filtered.
+func (hdb *hostListsDB) Host(pk types.SiaPublicKey) (modules.HostDBEntry, bool, error) {
+	host, exists, err := hdb.HostDB.Host(pk)
+	if exists && !hdb.staticHostLists.callAllowed(host) {
+		host.Filtered = true
+	}
+	return host, exists, err
+}
+
+// RandomHosts returns random hosts from the hostdb which are allowed by the
+// host lists.
+func (hdb *hostListsDB) RandomHosts(n int, blacklist, addressBlacklist []types.SiaPublicKey) ([]modules.HostDBEntry, error) {
+	if !hdb.staticHostLists.callActive() {
+		return hdb.HostDB.RandomHosts(n, blacklist, addressBlacklist)
+	}
+	hosts, err := hdb.HostDB.ActiveHosts()
+	if err != nil {
+		return nil, err
+	}
+	blacklist = append([]types.SiaPublicKey{}, blacklist...)
+	for _, host := range hosts {
+		if !hdb.staticHostLists.callAllowed(host) {
+			blacklist = append(blacklist, host.PublicKey)
+		}
+	}
+	return hdb.HostDB.RandomHosts(n, blacklist, addressBlacklist)
+}
+
+// HostLists returns the contractor's host allowlist and denylist.
+func (c *Contractor) HostLists() modules.ContractorHostLists {
+	return c.staticHostLists.callLists()
+}
+
+// SetHostLists replaces the contractor's host allowlist and denylist. Contracts
+// with hosts that are no longer allowed lose their utility during the next
+// round of contract maintenance, which is started right away.
+func (c *Contractor) SetHostLists(lists modules.ContractorHostLists) error {
+	if err := c.tg.Add(); err != nil {
+		return err
+	}
+	defer c.tg.Done()
+	err := c.staticHostLists.callSet(lists)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	err = c.save()
+	c.mu.Unlock()
+	if err != nil {
+		return errors.AddContext(err, "failed to save host lists")
+	}
+
+	// Interrupt any existing maintenance and launch a new round of
+	// maintenance.
+	if err := c.tg.Add(); err != nil {
+		return err
+	}
+	go func() {
+		defer c.tg.Done()
+		c.callInterruptContractMaintenance()
+		c.threadedContractMaintenance()
+	}()
+	return nil
+}
@@ -0,0 +1,140 @@
+package contractor
+
+import (
+	"net"
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// testHostListsResolver resolves the hostnames of the host list tests.
+type testHostListsResolver map[string][]net.IP
+
+// LookupIP implements the modules.Resolver interface.
+func (r testHostListsResolver) LookupIP(host string) ([]net.IP, error) {
+	ips, exists := r[host]
+	if !exists {
+		return nil, errors.New("no such host")
+	}
+	return ips, nil
+}
+
+// TestHostLists probes the matching of hosts against the contractor's host
+// lists.
+func TestHostLists(t *testing.T) {
+	t.Parallel()
+
+	host := func(key byte, addr modules.NetAddress) modules.HostDBEntry {
+		var entry modules.HostDBEntry
+		entry.PublicKey = types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{key}}
+		entry.NetAddress = addr
+		return entry
+	}
+	h1 := host(1, "12.34.56.1:9982")
+	h2 := host(2, "12.34.57.1:9982")
+	h3 := host(3, "host.example.com:9982")
+	h4 := host(4, "host.example.com:9992")
+
+	// Empty lists allow all hosts.
+	hl := newHostLists(testHostListsResolver{})
+	if hl.callActive() {
+		t.Fatal("empty lists shouldn't be active")
+	}
+	for _, h := range []modules.HostDBEntry{h1, h2, h3, h4} {
+		if !hl.callAllowed(h) {
+			t.Fatal("host should be allowed", h.NetAddress)
+		}
+	}
+
+	// An allowlist only allows the hosts on the list. The denylist takes
+	// precedence.
+	err := hl.callSet(modules.ContractorHostLists{
+		Allowlist: modules.HostList{
+			PublicKeys:   []types.SiaPublicKey{h2.PublicKey},
+			NetAddresses: []string{"12.34.56.0/24", "host.example.com"},
+		},
+		Denylist: modules.HostList{
+			NetAddresses: []string{"host.example.com:9992"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hl.callActive() {
+		t.Fatal("lists should be active")
+	}
+	for _, h := range []modules.HostDBEntry{h1, h2, h3} {
+		if !hl.callAllowed(h) {
+			t.Fatal("host should be allowed", h.NetAddress)
+		}
+	}
+	if hl.callAllowed(h4) || hl.callAllowed(host(5, "12.34.57.2:9982")) {
+		t.Fatal("host shouldn't be allowed")
+	}
+
+	// Invalid lists are rejected and don't change the current lists.
+	for _, addr := range []string{"", " ", "12.34.56.0/33", "foo/24"} {
+		err = hl.callSet(modules.ContractorHostLists{
+			Denylist: modules.HostList{NetAddresses: []string{addr}},
+		})
+		if err == nil {
+			t.Fatalf("'%v' should be invalid", addr)
+		}
+	}
+	if lists := hl.callLists(); len(lists.Allowlist.NetAddresses) != 2 || len(lists.Denylist.NetAddresses) != 1 {
+		t.Fatal("lists changed", lists)
+	}
+}
+
+// TestHostListsHostnameSubnets checks that hosts which are addressed by a
+// hostname are matched against the subnets of the host lists.
+func TestHostListsHostnameSubnets(t *testing.T) {
+	t.Parallel()
+
+	host := func(key byte, addr modules.NetAddress) modules.HostDBEntry {
+		var entry modules.HostDBEntry
+		entry.PublicKey = types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{key}}
+		entry.NetAddress = addr
+		return entry
+	}
+	h1 := host(1, "denied.example.com:9982")
+	h2 := host(2, "allowed.example.com:9982")
+	h3 := host(3, "unknown.example.com:9982")
+	hl := newHostLists(testHostListsResolver{
+		"denied.example.com":  {net.ParseIP("12.34.56.1"), net.ParseIP("98.76.54.1")},
+		"allowed.example.com": {net.ParseIP("98.76.54.2")},
+	})
+
+	// A denied subnet denies a host if any of its addresses are within it.
+	err := hl.callSet(modules.ContractorHostLists{
+		Denylist: modules.HostList{NetAddresses: []string{"12.34.56.0/24"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hl.callAllowed(h1) {
+		t.Fatal("host within denied subnet shouldn't be allowed")
+	}
+	if !hl.callAllowed(h2) || !hl.callAllowed(h3) {
+		t.Fatal("hosts outside of the denied subnet should be allowed")
+	}
+
+	// An allowed subnet only allows hosts which resolve to an address within
+	// it.
+	err = hl.callSet(modules.ContractorHostLists{
+		Allowlist: modules.HostList{NetAddresses: []string{"98.76.54.0/24"}},
+		Denylist:  modules.HostList{NetAddresses: []string{"12.34.56.0/24"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hl.callAllowed(h2) {
+		t.Fatal("host within allowed subnet should be allowed")
+	}
+	if hl.callAllowed(h1) || hl.callAllowed(h3) {
+		t.Fatal("denied and unresolvable hosts shouldn't be allowed")
+	}
+}
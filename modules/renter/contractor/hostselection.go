@@ -0,0 +1,78 @@
+package contractor
+
+import (
+	"net"
+
+	"go.sia.tech/siad/modules"
+)
+
+const (
+	// spreadIPv4FilterRange is the prefix length of the IPv4 subnets used to
+	// spread contracts across the allowance's minimum number of subnets.
+	spreadIPv4FilterRange = 16
+
+	// spreadIPv6FilterRange is the prefix length of the IPv6 subnets used to
+	// spread contracts across the allowance's minimum number of subnets.
+	spreadIPv6FilterRange = 32
+)
+
+// spreadSubnets returns the subnets of a host which are used to spread the
+// renter's contracts. The subnets are wider than the ones used by the hostdb's
+// address filter to make sure that contracts end up with different providers.
+func spreadSubnets(host modules.HostDBEntry) []string {
+	var subnets []string
+	for _, ipNet := range host.IPNets {
+		ip, _, err := net.ParseCIDR(ipNet)
+		if err != nil {
+			continue
+		}
+		mask := net.CIDRMask(spreadIPv4FilterRange, 32)
+		if ip.To4() == nil {
+			mask = net.CIDRMask(spreadIPv6FilterRange, 128)
+		}
+		subnet := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+		subnets = append(subnets, subnet.String())
+	}
+	return subnets
+}
+
+// prioritizeSubnets reorders the candidate hosts for new contracts so that
+// hosts in subnets which the renter doesn't have contracts in yet are tried
+// first, until the renter's contracts span at least minSubnets subnets. The
+// order of the remaining hosts is preserved.
+func prioritizeSubnets(candidates, contracted []modules.HostDBEntry, minSubnets uint64) []modules.HostDBEntry {
+	if minSubnets == 0 {
+		return candidates
+	}
+	seen := make(map[string]struct{})
+	for _, host := range contracted {
+		for _, subnet := range spreadSubnets(host) {
+			seen[subnet] = struct{}{}
+		}
+	}
+
+	var prioritized, rest []modules.HostDBEntry
+	for _, host := range candidates {
+		if uint64(len(seen)) >= minSubnets {
+			rest = append(rest, host)
+			continue
+		}
+		subnets := spreadSubnets(host)
+		isNew := len(subnets) > 0
+		for _, subnet := range subnets {
+			if _, exists := seen[subnet]; exists {
+				isNew = false
+				break
+			}
+		}
+		if !isNew {
+			rest = append(rest, host)
+			continue
+		}
+		for _, subnet := range subnets {
+			seen[subnet] = struct{}{}
+		}
+		prioritized = append(prioritized, host)
+	}
+	return append(prioritized, rest...)
+}
@@ -0,0 +1,51 @@
+package contractor
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestPrioritizeSubnets probes the ordering of hosts to spread contracts across
+// subnets.
+func TestPrioritizeSubnets(t *testing.T) {
+	t.Parallel()
+
+	host := func(addr modules.NetAddress, ipNets ...string) modules.HostDBEntry {
+		var entry modules.HostDBEntry
+		entry.NetAddress = addr
+		entry.IPNets = ipNets
+		return entry
+	}
+	contracted := []modules.HostDBEntry{host("a", "1.2.3.0/24")}
+	candidates := []modules.HostDBEntry{
+		host("b", "1.2.4.0/24"),
+		host("c"),
+		host("d", "5.6.7.0/24"),
+		host("e", "5.6.8.0/24"),
+		host("f", "9.9.9.0/24", "2001:db8:1::/54"),
+	}
+	order := func(hosts []modules.HostDBEntry) (s string) {
+		for _, h := range hosts {
+			s += string(h.NetAddress)
+		}
+		return
+	}
+
+	// Without a minimum the order is unchanged.
+	if o := order(prioritizeSubnets(candidates, contracted, 0)); o != "bcdef" {
+		t.Fatal("wrong order", o)
+	}
+	// Hosts in new /16 subnets come first until the minimum is reached.
+	if o := order(prioritizeSubnets(candidates, contracted, 2)); o != "dbcef" {
+		t.Fatal("wrong order", o)
+	}
+	if o := order(prioritizeSubnets(candidates, contracted, 10)); o != "dfbce" {
+		t.Fatal("wrong order", o)
+	}
+	// Subnets are /16 for IPv4 and /32 for IPv6.
+	subnets := spreadSubnets(candidates[4])
+	if len(subnets) != 2 || subnets[0] != "9.9.0.0/16" || subnets[1] != "2001:db8::/32" {
+		t.Fatal("wrong subnets", subnets)
+	}
+}
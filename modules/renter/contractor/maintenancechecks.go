@@ -77,7 +77,9 @@ func (c *Contractor) managedCheckHostScore(contract modules.RenterContract, sb m
 			c.log.Println("Collateral Adjustment: ", sb.CollateralAdjustment)
 			c.log.Println("Duration Adjustment:   ", sb.DurationAdjustment)
 			c.log.Println("Interaction Adjustment:", sb.InteractionAdjustment)
+			c.log.Println("Latency Adjustment:    ", sb.LatencyAdjustment)
 			c.log.Println("Price Adjustment:      ", sb.PriceAdjustment)
+			c.log.Println("Region Adjustment:     ", sb.RegionAdjustment)
 			c.log.Println("Storage Adjustment:    ", sb.StorageRemainingAdjustment)
 			c.log.Println("Uptime Adjustment:     ", sb.UptimeAdjustment)
 			c.log.Println("Version Adjustment:    ", sb.VersionAdjustment)
@@ -107,7 +109,9 @@ func (c *Contractor) managedCheckHostScore(contract modules.RenterContract, sb m
 			c.log.Println("Collateral Adjustment: ", sb.CollateralAdjustment)
 			c.log.Println("Duration Adjustment:   ", sb.DurationAdjustment)
 			c.log.Println("Interaction Adjustment:", sb.InteractionAdjustment)
+			c.log.Println("Latency Adjustment:    ", sb.LatencyAdjustment)
 			c.log.Println("Price Adjustment:      ", sb.PriceAdjustment)
+			c.log.Println("Region Adjustment:     ", sb.RegionAdjustment)
 			c.log.Println("Storage Adjustment:    ", sb.StorageRemainingAdjustment)
 			c.log.Println("Uptime Adjustment:     ", sb.UptimeAdjustment)
 			c.log.Println("Version Adjustment:    ", sb.VersionAdjustment)
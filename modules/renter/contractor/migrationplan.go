@@ -0,0 +1,125 @@
+package contractor
+
+import (
+	"math"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// The reasons for which a host is marked for replacement.
+const (
+	churnReasonBadContract = "contract was marked as bad"
+	churnReasonCanceled    = "contract was canceled"
+	churnReasonHostMissing = "host is not in the hostdb"
+	churnReasonFiltered    = "host is filtered"
+	churnReasonLowScore    = "host score is too low"
+	churnReasonMaxRevision = "contract reached its maximum revision number"
+	churnReasonOffline     = "host is offline"
+)
+
+// churnReason returns the reason why a contract's host is marked for
+// replacement, given the contract's new utility and its host. The checks
+// mirror the order of the utility checks of the contract maintenance.
+func churnReason(contract modules.RenterContract, u modules.ContractUtility, host modules.HostDBEntry, exists bool) string {
+	switch {
+	case u.BadContract:
+		return churnReasonBadContract
+	case u.Locked && contract.Transaction.FileContractRevisions[0].NewRevisionNumber == math.MaxUint64:
+		return churnReasonMaxRevision
+	case u.Locked:
+		return churnReasonCanceled
+	case !exists:
+		return churnReasonHostMissing
+	case host.Filtered:
+		return churnReasonFiltered
+	case isOffline(host):
+		return churnReasonOffline
+	default:
+		return churnReasonLowScore
+	}
+}
+
+// estimateMigrationCost estimates the cost of uploading size bytes to a new
+// host and storing them for the provided duration. The average prices of the
+// provided hosts are used as the prices of the new host.
+func estimateMigrationCost(size uint64, hosts []modules.HostDBEntry, duration types.BlockHeight) types.Currency {
+	if len(hosts) == 0 {
+		return types.ZeroCurrency
+	}
+	var uploadPrice, storagePrice types.Currency
+	for _, host := range hosts {
+		uploadPrice = uploadPrice.Add(host.UploadBandwidthPrice)
+		storagePrice = storagePrice.Add(host.StoragePrice)
+	}
+	uploadPrice = uploadPrice.Div64(uint64(len(hosts)))
+	storagePrice = storagePrice.Div64(uint64(len(hosts)))
+	return uploadPrice.Mul64(size).Add(storagePrice.Mul64(size).Mul64(uint64(duration)))
+}
+
+// managedNewMigration creates the migration of a contract whose host was marked
+// for replacement. The hosts of the contracts that are good for upload are
+// used to estimate the prices of the new host.
+func (c *Contractor) managedNewMigration(contract modules.RenterContract, u modules.ContractUtility) modules.ContractorMigration {
+	host, exists, err := c.hdb.Host(contract.HostPublicKey)
+	exists = exists && err == nil
+
+	var hosts []modules.HostDBEntry
+	for _, rc := range c.staticContracts.ViewAll() {
+		if !rc.Utility.GoodForUpload || rc.ID == contract.ID {
+			continue
+		}
+		h, ok, err := c.hdb.Host(rc.HostPublicKey)
+		if ok && err == nil {
+			hosts = append(hosts, h)
+		}
+	}
+	if len(hosts) == 0 && exists {
+		hosts = append(hosts, host)
+	}
+
+	c.mu.RLock()
+	blockHeight := c.blockHeight
+	var duration types.BlockHeight
+	if endHeight := c.contractEndHeight(); endHeight > blockHeight {
+		duration = endHeight - blockHeight
+	}
+	c.mu.RUnlock()
+
+	size := contract.Transaction.FileContractRevisions[0].NewFileSize
+	return modules.ContractorMigration{
+		ContractID:    contract.ID,
+		HostPublicKey: contract.HostPublicKey,
+		NetAddress:    host.NetAddress,
+		BlockHeight:   blockHeight,
+		Size:          size,
+		EstimatedCost: estimateMigrationCost(size, hosts, duration),
+		Reason:        churnReason(contract, u, host, exists),
+	}
+}
+
+// callMigrationPlan returns the migrations of the current period, followed by
+// the deferred migrations.
+func (cl *churnLimiter) callMigrationPlan() []modules.ContractorMigration {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	migrations := append([]modules.ContractorMigration{}, cl.churnedContracts...)
+	return append(migrations, cl.deferredContracts...)
+}
+
+// MigrationPlan returns the contracts that were churned in the current period
+// and the contracts that the churn limiter plans to churn once the churn
+// budget allows it, together with the estimated cost of migrating their data.
+func (c *Contractor) MigrationPlan() modules.ContractorMigrationPlan {
+	remainingBudget, remainingPeriodBudget := c.staticChurnLimiter.managedChurnBudget()
+	plan := modules.ContractorMigrationPlan{
+		ContractorChurnStatus: c.ChurnStatus(),
+		RemainingChurnBudget:  int64(remainingBudget),
+		RemainingPeriodChurn:  int64(remainingPeriodBudget),
+		Migrations:            c.staticChurnLimiter.callMigrationPlan(),
+	}
+	for _, m := range plan.Migrations {
+		plan.TotalEstimatedCost = plan.TotalEstimatedCost.Add(m.EstimatedCost)
+	}
+	return plan
+}
@@ -37,11 +37,13 @@ type contractorPersist struct {
 	RecoverableContracts []modules.RecoverableContract   `json:"recoverablecontracts"`
 	RenewedFrom          map[string]types.FileContractID `json:"renewedfrom"`
 	RenewedTo            map[string]types.FileContractID `json:"renewedto"`
+	ReadOnly             bool                            `json:"readonly"`
 	Synced               bool                            `json:"synced"`
 
 	// Subsystem persistence:
-	ChurnLimiter churnLimiterPersist `json:"churnlimiter"`
-	WatchdogData watchdogPersist     `json:"watchdogdata"`
+	ChurnLimiter churnLimiterPersist         `json:"churnlimiter"`
+	HostLists    modules.ContractorHostLists `json:"hostlists"`
+	WatchdogData watchdogPersist             `json:"watchdogdata"`
 }
 
 // persistData returns the data in the Contractor that will be saved to disk.
@@ -61,6 +63,7 @@ func (c *Contractor) persistData() contractorPersist {
 		RenewedFrom:          make(map[string]types.FileContractID),
 		RenewedTo:            make(map[string]types.FileContractID),
 		DoubleSpentContracts: make(map[string]types.BlockHeight),
+		ReadOnly:             c.readOnly,
 		Synced:               synced,
 	}
 	for k, v := range c.renewedFrom {
@@ -79,6 +82,7 @@ func (c *Contractor) persistData() contractorPersist {
 		data.RecoverableContracts = append(data.RecoverableContracts, contract)
 	}
 	data.ChurnLimiter = c.staticChurnLimiter.callPersistData()
+	data.HostLists = c.staticHostLists.callLists()
 	data.WatchdogData = c.staticWatchdog.callPersistData()
 	return data
 }
@@ -117,6 +121,7 @@ func (c *Contractor) load() error {
 	c.blockHeight = data.BlockHeight
 	c.currentPeriod = data.CurrentPeriod
 	c.lastChange = data.LastChange
+	c.readOnly = data.ReadOnly
 	c.synced = make(chan struct{})
 	if data.Synced {
 		close(c.synced)
@@ -149,6 +154,10 @@ func (c *Contractor) load() error {
 	}
 
 	c.staticChurnLimiter = newChurnLimiterFromPersist(c, data.ChurnLimiter)
+	err = c.staticHostLists.callSet(data.HostLists)
+	if err != nil {
+		return errors.AddContext(err, "failed to load host lists")
+	}
 
 	c.staticWatchdog, err = newWatchdogFromPersist(c, data.WatchdogData)
 	if err != nil {
@@ -93,15 +93,27 @@ func TestSaveLoad(t *testing.T) {
 	c.staticChurnLimiter = newChurnLimiter(c)
 	c.staticChurnLimiter.aggregateCurrentPeriodChurn = 123456
 	c.staticChurnLimiter.remainingChurnBudget = -789
+	c.staticChurnLimiter.churnedContracts = []modules.ContractorMigration{{ContractID: types.FileContractID{1}, Reason: churnReasonOffline}}
+
+	c.staticHostLists = newHostLists(modules.ProdDependencies.Resolver())
+	err := c.staticHostLists.callSet(modules.ContractorHostLists{
+		Denylist: modules.HostList{NetAddresses: []string{"12.34.56.0/24"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.readOnly = true
 
 	// save, clear, and reload
-	err := c.save()
+	err = c.save()
 	if err != nil {
 		t.Fatal(err)
 	}
 	c.oldContracts = make(map[types.FileContractID]modules.RenterContract)
 	c.renewedFrom = make(map[types.FileContractID]types.FileContractID)
 	c.renewedTo = make(map[types.FileContractID]types.FileContractID)
+	c.staticHostLists = newHostLists(modules.ProdDependencies.Resolver())
+	c.readOnly = false
 	err = c.load()
 	if err != nil {
 		t.Fatal(err)
@@ -120,6 +132,12 @@ func TestSaveLoad(t *testing.T) {
 	if c.renewedTo[types.FileContractID{1}] != id {
 		t.Fatal("renewedTo not restored properly:", c.renewedTo)
 	}
+	if !c.staticHostLists.callActive() {
+		t.Fatal("host lists not restored properly:", c.staticHostLists.callLists())
+	}
+	if !c.readOnly {
+		t.Fatal("read-only mode not restored properly")
+	}
 	select {
 	case <-c.synced:
 	default:
@@ -302,6 +320,9 @@ func TestSaveLoad(t *testing.T) {
 	if periodBudget != expectedPeriodBudget {
 		t.Fatal("Expected remainingChurnBudget", periodBudget)
 	}
+	if migrations := c.staticChurnLimiter.callMigrationPlan(); len(migrations) != 1 || migrations[0].Reason != churnReasonOffline {
+		t.Fatal("churned contracts not restored properly", migrations)
+	}
 }
 
 // TestConvertPersist tests that contracts previously stored in the
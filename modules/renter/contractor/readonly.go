@@ -0,0 +1,53 @@
+package contractor
+
+import (
+	"gitlab.com/NebulousLabs/errors"
+)
+
+var (
+	// ErrReadOnly is returned if a contract is formed or renewed while the
+	// contractor is in read-only mode.
+	ErrReadOnly = errors.New("contracts can't be formed or renewed in read-only mode")
+)
+
+// ReadOnly returns whether the contractor is in read-only mode. In read-only
+// mode, contract maintenance doesn't form or renew any contracts.
+func (c *Contractor) ReadOnly() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.readOnly
+}
+
+// SetReadOnly enables or disables the read-only mode of the contractor. Any
+// ongoing contract maintenance is interrupted when the mode is enabled and a new
+// round of maintenance is started when it is disabled.
+func (c *Contractor) SetReadOnly(readOnly bool) error {
+	if err := c.tg.Add(); err != nil {
+		return err
+	}
+	defer c.tg.Done()
+	c.mu.Lock()
+	if c.readOnly == readOnly {
+		c.mu.Unlock()
+		return nil
+	}
+	c.readOnly = readOnly
+	err := c.save()
+	c.mu.Unlock()
+	if err != nil {
+		return errors.AddContext(err, "failed to save read-only mode")
+	}
+
+	if readOnly {
+		c.callInterruptContractMaintenance()
+		return nil
+	}
+	if err := c.tg.Add(); err != nil {
+		return err
+	}
+	go func() {
+		defer c.tg.Done()
+		c.threadedContractMaintenance()
+	}()
+	return nil
+}
@@ -0,0 +1,60 @@
+package contractor
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// readOnlyTestHostDB is a hostdb which doesn't know any hosts.
+type readOnlyTestHostDB struct {
+	modules.HostDB
+}
+
+// Host implements the modules.HostDB interface.
+func (readOnlyTestHostDB) Host(types.SiaPublicKey) (modules.HostDBEntry, bool, error) {
+	return modules.HostDBEntry{}, false, errors.New("unknown host")
+}
+
+// TestReadOnlyContracts checks that contracts are neither formed nor renewed
+// while the contractor is in read-only mode.
+func TestReadOnlyContracts(t *testing.T) {
+	t.Parallel()
+
+	c := &Contractor{
+		hdb:      readOnlyTestHostDB{},
+		readOnly: true,
+	}
+	form := func() error {
+		_, _, err := c.managedNewContract(modules.HostDBEntry{}, types.SiacoinPrecision, 100)
+		return err
+	}
+	renew := func() error {
+		_, err := c.managedRenew(types.FileContractID{}, types.SiaPublicKey{}, types.SiacoinPrecision, 100, modules.HostExternalSettings{})
+		return err
+	}
+
+	// In read-only mode contracts are neither formed nor renewed.
+	if err := form(); !errors.Contains(err, ErrReadOnly) {
+		t.Fatalf("expected %v but got %v", ErrReadOnly, err)
+	}
+	if err := renew(); !errors.Contains(err, ErrReadOnly) {
+		t.Fatalf("expected %v but got %v", ErrReadOnly, err)
+	}
+	if _, _, err := c.RenewContract(nil, types.FileContractID{}, modules.ContractParams{}, nil, nil, nil, nil); !errors.Contains(err, ErrReadOnly) {
+		t.Fatalf("expected %v but got %v", ErrReadOnly, err)
+	}
+
+	// Otherwise the contractor tries to form and renew contracts. Without an
+	// allowance and hosts that fails.
+	c.readOnly = false
+	if err := form(); err == nil || errors.Contains(err, ErrReadOnly) {
+		t.Fatalf("expected an error other than %v but got %v", ErrReadOnly, err)
+	}
+	if err := renew(); err == nil || errors.Contains(err, ErrReadOnly) {
+		t.Fatalf("expected an error other than %v but got %v", ErrReadOnly, err)
+	}
+}
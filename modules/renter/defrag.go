@@ -0,0 +1,151 @@
+package renter
+
+import (
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem/siafile"
+)
+
+var (
+	// fileDefragInterval is the interval between two passes of the background
+	// siafile defragmentation.
+	fileDefragInterval = build.Select(build.Var{
+		Dev:      10 * time.Minute,
+		Standard: 24 * time.Hour,
+		Testnet:  24 * time.Hour,
+		Testing:  time.Minute,
+	}).(time.Duration)
+)
+
+var (
+	// errFileDefragInProgress is returned if a defragmentation pass is started
+	// while another one is still running.
+	errFileDefragInProgress = errors.New("a defragmentation pass is already in progress")
+)
+
+// fileDefrag keeps track of the progress of the renter's background siafile
+// defragmentation.
+type fileDefrag struct {
+	status modules.FileDefragStatus
+	mu     sync.Mutex
+}
+
+// callStart starts a new defragmentation pass over numFiles files.
+func (fd *fileDefrag) callStart(numFiles uint64) error {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	if fd.status.Active {
+		return errFileDefragInProgress
+	}
+	fd.status = modules.FileDefragStatus{
+		Active:    true,
+		NumFiles:  numFiles,
+		StartTime: time.Now(),
+	}
+	return nil
+}
+
+// callProgress records that a file was checked and the changes that were made
+// to it.
+func (fd *fileDefrag) callProgress(res siafile.DefragResult) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	fd.status.FilesChecked++
+	if res != (siafile.DefragResult{}) {
+		fd.status.FilesDefragmented++
+	}
+	fd.status.PiecesRemoved += res.PiecesRemoved
+	fd.status.HostsPruned += res.HostsPruned
+	fd.status.BytesFreed += res.BytesFreed
+}
+
+// callFinish marks the current defragmentation pass as done.
+func (fd *fileDefrag) callFinish() {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	fd.status.Active = false
+	fd.status.EndTime = time.Now()
+}
+
+// callStatus returns the progress of the current or last defragmentation
+// pass.
+func (fd *fileDefrag) callStatus() modules.FileDefragStatus {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	return fd.status
+}
+
+// managedDefragFiles runs a defragmentation pass over all of the renter's
+// files. Files which fail to be defragmented are skipped.
+func (r *Renter) managedDefragFiles() error {
+	var mu sync.Mutex
+	var siaPaths []modules.SiaPath
+	err := r.FileList(modules.RootSiaPath(), true, true, func(fi modules.FileInfo) {
+		mu.Lock()
+		siaPaths = append(siaPaths, fi.SiaPath)
+		mu.Unlock()
+	})
+	if err != nil {
+		return errors.AddContext(err, "failed to list files")
+	}
+	fd := r.staticFileDefrag
+	if err := fd.callStart(uint64(len(siaPaths))); err != nil {
+		return err
+	}
+	defer fd.callFinish()
+
+	for _, siaPath := range siaPaths {
+		select {
+		case <-r.tg.StopChan():
+			return errors.New("renter shut down before defragmentation finished")
+		default:
+		}
+		res, err := r.managedDefragFile(siaPath)
+		if err != nil {
+			r.log.Printf("WARN: failed to defragment %v: %v", siaPath, err)
+		}
+		fd.callProgress(res)
+	}
+	return nil
+}
+
+// managedDefragFile defragments a single file.
+func (r *Renter) managedDefragFile(siaPath modules.SiaPath) (_ siafile.DefragResult, err error) {
+	sf, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return siafile.DefragResult{}, err
+	}
+	defer func() {
+		err = errors.Compose(err, sf.Close())
+	}()
+	return sf.Defragment()
+}
+
+// threadedFileDefragLoop periodically defragments the renter's files.
+func (r *Renter) threadedFileDefragLoop() {
+	if err := r.tg.Add(); err != nil {
+		return
+	}
+	defer r.tg.Done()
+	for {
+		select {
+		case <-r.tg.StopChan():
+			return
+		case <-time.After(fileDefragInterval):
+		}
+		if err := r.managedDefragFiles(); err != nil {
+			r.log.Println("WARN: failed to defragment files:", err)
+		}
+	}
+}
+
+// FileDefragStatus returns the progress of the renter's background siafile
+// defragmentation.
+func (r *Renter) FileDefragStatus() modules.FileDefragStatus {
+	return r.staticFileDefrag.callStatus()
+}
@@ -0,0 +1,83 @@
+package renter
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+// TestFileDefrag tests running a defragmentation pass over the renter's
+// files.
+func TestFileDefrag(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	rt, err := newRenterTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Create two files and add the same piece twice to the first one.
+	sf, err := rt.renter.newRenterTestFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: fastrand.Bytes(crypto.PublicKeySize)}
+	var root crypto.Hash
+	fastrand.Read(root[:])
+	for i := 0; i < 2; i++ {
+		if err := sf.AddPiece(pk, 0, 0, root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sf2, err := rt.renter.newRenterTestFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := errors.Compose(sf.Close(), sf2.Close()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Run a pass and check the status.
+	if err := rt.renter.managedDefragFiles(); err != nil {
+		t.Fatal(err)
+	}
+	status := rt.renter.FileDefragStatus()
+	if status.Active || status.EndTime.Before(status.StartTime) {
+		t.Fatal("pass should be done", status)
+	}
+	if status.NumFiles != 2 || status.FilesChecked != 2 || status.FilesDefragmented != 1 {
+		t.Fatal("wrong number of files", status)
+	}
+	if status.PiecesRemoved != 1 || status.HostsPruned != 0 {
+		t.Fatal("wrong number of removed pieces", status)
+	}
+
+	// A second pass shouldn't remove anything.
+	if err := rt.renter.managedDefragFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if status = rt.renter.FileDefragStatus(); status.FilesDefragmented != 0 || status.PiecesRemoved != 0 {
+		t.Fatal("second pass shouldn't change any files", status)
+	}
+
+	// Only one pass can run at a time.
+	fd := rt.renter.staticFileDefrag
+	if err := fd.callStart(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.renter.managedDefragFiles(); !errors.Contains(err, errFileDefragInProgress) {
+		t.Fatal("expected errFileDefragInProgress but got", err)
+	}
+}
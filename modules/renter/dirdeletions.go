@@ -0,0 +1,241 @@
+package renter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+)
+
+const (
+	// maxDirDeletions is the number of completed directory deletions the
+	// renter keeps track of.
+	maxDirDeletions = 100
+)
+
+var (
+	// errDirDeletionInProgress is returned if a directory is deleted while
+	// a background deletion of the same directory is still running.
+	errDirDeletionInProgress = errors.New("the directory is already being deleted")
+
+	// errDirDeletionInterrupted is returned if the renter shuts down before a
+	// background deletion is done.
+	errDirDeletionInterrupted = errors.New("renter shut down before the directory was deleted")
+)
+
+// dirDeletions keeps track of the renter's background directory deletions.
+type dirDeletions struct {
+	deletions map[modules.DirDeletionID]*modules.DirDeletionStatus
+	mu        sync.Mutex
+}
+
+// newDirDeletions creates a new dirDeletions object.
+func newDirDeletions() *dirDeletions {
+	return &dirDeletions{
+		deletions: make(map[modules.DirDeletionID]*modules.DirDeletionStatus),
+	}
+}
+
+// callAdd starts tracking a new deletion. Completed deletions are pruned if the
+// renter tracks more than maxDirDeletions of them.
+func (dd *dirDeletions) callAdd(status modules.DirDeletionStatus) error {
+	dd.mu.Lock()
+	defer dd.mu.Unlock()
+	var completed []*modules.DirDeletionStatus
+	for _, d := range dd.deletions {
+		if !d.Completed && d.SiaPath.Equals(status.SiaPath) {
+			return errDirDeletionInProgress
+		}
+		if d.Completed {
+			completed = append(completed, d)
+		}
+	}
+	if len(completed) >= maxDirDeletions {
+		sort.Slice(completed, func(i, j int) bool {
+			return completed[i].EndTime.Before(completed[j].EndTime)
+		})
+		for _, d := range completed[:len(completed)-maxDirDeletions+1] {
+			delete(dd.deletions, d.ID)
+		}
+	}
+	dd.deletions[status.ID] = &status
+	return nil
+}
+
+// callFinish marks a deletion as completed.
+func (dd *dirDeletions) callFinish(id modules.DirDeletionID, err error) {
+	dd.mu.Lock()
+	defer dd.mu.Unlock()
+	d, exists := dd.deletions[id]
+	if !exists {
+		return
+	}
+	d.Completed = true
+	d.EndTime = time.Now()
+	if err != nil {
+		d.Error = err.Error()
+	}
+}
+
+// callProgress updates the progress of a deletion after a file was deleted.
+func (dd *dirDeletions) callProgress(id modules.DirDeletionID, filesize uint64) {
+	dd.mu.Lock()
+	defer dd.mu.Unlock()
+	d, exists := dd.deletions[id]
+	if !exists {
+		return
+	}
+	d.FilesDeleted++
+	d.BytesDeleted += filesize
+}
+
+// callStatus returns the status of all tracked deletions, ordered from newest
+// to oldest.
+func (dd *dirDeletions) callStatus() []modules.DirDeletionStatus {
+	dd.mu.Lock()
+	defer dd.mu.Unlock()
+	statuses := make([]modules.DirDeletionStatus, 0, len(dd.deletions))
+	for _, d := range dd.deletions {
+		statuses = append(statuses, *d)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].StartTime.After(statuses[j].StartTime)
+	})
+	return statuses
+}
+
+// DeleteDirAsync starts deleting a directory and all of its contents in the
+// background. Instead of removing the whole tree at once, the files are
+// deleted one by one which allows for tracking the progress and prevents huge
+// directories from blocking the caller.
+func (r *Renter) DeleteDirAsync(siaPath modules.SiaPath) (modules.DirDeletionStatus, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.DirDeletionStatus{}, err
+	}
+	defer r.tg.Done()
+	files, status, err := r.managedDirDeletionFiles(siaPath)
+	if err != nil {
+		return modules.DirDeletionStatus{}, err
+	}
+	status.ID = modules.DirDeletionID(hex.EncodeToString(fastrand.Bytes(16)))
+	status.StartTime = time.Now()
+	err = r.staticDirDeletions.callAdd(status)
+	if err != nil {
+		return modules.DirDeletionStatus{}, err
+	}
+	go r.threadedDeleteDir(status.ID, siaPath, files)
+	return status, nil
+}
+
+// DeleteDirDryRun returns the number of files and bytes that deleting a
+// directory would remove without deleting anything.
+func (r *Renter) DeleteDirDryRun(siaPath modules.SiaPath) (modules.DirDeletionStatus, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.DirDeletionStatus{}, err
+	}
+	defer r.tg.Done()
+	start := time.Now()
+	_, status, err := r.managedDirDeletionFiles(siaPath)
+	if err != nil {
+		return modules.DirDeletionStatus{}, err
+	}
+	status.DryRun = true
+	status.Completed = true
+	status.StartTime = start
+	status.EndTime = time.Now()
+	return status, nil
+}
+
+// DirDeletions returns the status of the renter's recent background directory
+// deletions.
+func (r *Renter) DirDeletions() []modules.DirDeletionStatus {
+	if err := r.tg.Add(); err != nil {
+		return nil
+	}
+	defer r.tg.Done()
+	return r.staticDirDeletions.callStatus()
+}
+
+// managedDirDeletionFiles returns the files within a directory and its sub
+// directories together with a status that contains their number and total
+// size.
+func (r *Renter) managedDirDeletionFiles(siaPath modules.SiaPath) ([]modules.FileInfo, modules.DirDeletionStatus, error) {
+	var mu sync.Mutex
+	var files []modules.FileInfo
+	flf := func(fi modules.FileInfo) {
+		mu.Lock()
+		files = append(files, fi)
+		mu.Unlock()
+	}
+	err := r.staticFileSystem.CachedList(siaPath, true, flf, func(modules.DirectoryInfo) {})
+	if err != nil {
+		return nil, modules.DirDeletionStatus{}, errors.AddContext(err, "failed to list directory")
+	}
+	status := modules.DirDeletionStatus{
+		SiaPath:  siaPath,
+		NumFiles: uint64(len(files)),
+	}
+	for _, fi := range files {
+		status.TotalBytes += fi.Filesize
+	}
+	return files, status, nil
+}
+
+// managedDeleteDirContents deletes the provided files one by one before
+// removing what is left of the directory.
+func (r *Renter) managedDeleteDirContents(id modules.DirDeletionID, siaPath modules.SiaPath, files []modules.FileInfo) error {
+	for _, fi := range files {
+		select {
+		case <-r.tg.StopChan():
+			return errDirDeletionInterrupted
+		default:
+		}
+		err := r.staticFileSystem.DeleteFile(fi.SiaPath)
+		if err != nil && !errors.Contains(err, filesystem.ErrNotExist) {
+			return errors.AddContext(err, fmt.Sprintf("failed to delete file %v", fi.SiaPath))
+		}
+		r.staticDirDeletions.callProgress(id, fi.Filesize)
+	}
+	// Remove the remaining directory tree. This also removes files that were
+	// added to the directory after the deletion started.
+	err := r.staticFileSystem.DeleteDir(siaPath)
+	if err != nil {
+		return errors.AddContext(err, "failed to delete directory")
+	}
+	if err := r.managedUnpinDeletedDirs(siaPath); err != nil {
+		r.log.Printf("Unable to unpin deleted directory %v: %v", siaPath, err)
+	}
+	if err := r.managedThawDeletedPaths(siaPath); err != nil {
+		r.log.Printf("Unable to thaw deleted directory %v: %v", siaPath, err)
+	}
+	// Bubble the parent once instead of once per deleted file.
+	parent, err := siaPath.Dir()
+	if err != nil {
+		r.log.Printf("Unable to fetch the parent of deleted directory %v: %v", siaPath, err)
+		return nil
+	}
+	_ = r.staticBubbleScheduler.callQueueBubble(parent)
+	return nil
+}
+
+// threadedDeleteDir deletes a directory in the background and records the
+// outcome of the deletion.
+func (r *Renter) threadedDeleteDir(id modules.DirDeletionID, siaPath modules.SiaPath, files []modules.FileInfo) {
+	if err := r.tg.Add(); err != nil {
+		r.staticDirDeletions.callFinish(id, errDirDeletionInterrupted)
+		return
+	}
+	defer r.tg.Done()
+	err := r.managedDeleteDirContents(id, siaPath, files)
+	if err != nil {
+		r.log.Printf("WARN: background deletion of %v failed: %v", siaPath, err)
+	}
+	r.staticDirDeletions.callFinish(id, err)
+}
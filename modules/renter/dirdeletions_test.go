@@ -0,0 +1,84 @@
+package renter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/modules"
+)
+
+// TestDirDeletions is a unit test for tracking background directory
+// deletions.
+func TestDirDeletions(t *testing.T) {
+	t.Parallel()
+
+	dd := newDirDeletions()
+	siaPath, err := modules.NewSiaPath("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	// Add a deletion and update its progress.
+	err = dd.callAdd(modules.DirDeletionStatus{ID: "1", SiaPath: siaPath, NumFiles: 2, TotalBytes: 30, StartTime: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dd.callProgress("1", 10)
+	dd.callProgress("1", 20)
+
+	// Deleting the same directory again should fail while the first deletion
+	// is in progress.
+	err = dd.callAdd(modules.DirDeletionStatus{ID: "2", SiaPath: siaPath})
+	if !errors.Contains(err, errDirDeletionInProgress) {
+		t.Fatal("expected errDirDeletionInProgress", err)
+	}
+
+	// Finish the deletion with an error and check the status.
+	dd.callFinish("1", errDirDeletionInterrupted)
+	statuses := dd.callStatus()
+	if len(statuses) != 1 {
+		t.Fatal("wrong number of deletions", len(statuses))
+	}
+	s := statuses[0]
+	if !s.Completed || s.EndTime.IsZero() || s.Error != errDirDeletionInterrupted.Error() {
+		t.Fatal("deletion wasn't finished correctly", s)
+	}
+	if s.FilesDeleted != 2 || s.BytesDeleted != 30 {
+		t.Fatal("wrong progress", s.FilesDeleted, s.BytesDeleted)
+	}
+
+	// Updating unknown deletions is a no-op.
+	dd.callProgress("unknown", 10)
+	dd.callFinish("unknown", nil)
+
+	// Once finished, the directory can be deleted again.
+	err = dd.callAdd(modules.DirDeletionStatus{ID: "2", SiaPath: siaPath, StartTime: now.Add(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses = dd.callStatus()
+	if len(statuses) != 2 || statuses[0].ID != "2" || statuses[1].ID != "1" {
+		t.Fatal("deletions should be ordered from newest to oldest", statuses)
+	}
+	dd.callFinish("2", nil)
+
+	// Add more completed deletions than are kept. The oldest ones should be
+	// pruned.
+	for i := 0; i < maxDirDeletions; i++ {
+		id := modules.DirDeletionID(fmt.Sprint("completed", i))
+		err = dd.callAdd(modules.DirDeletionStatus{ID: id, SiaPath: siaPath})
+		if err != nil {
+			t.Fatal(err)
+		}
+		dd.callFinish(id, nil)
+	}
+	if len(dd.deletions) != maxDirDeletions {
+		t.Fatal("wrong number of deletions", len(dd.deletions))
+	}
+	if _, exists := dd.deletions["1"]; exists {
+		t.Fatal("oldest deletion wasn't pruned")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"container/heap"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 
 	"gitlab.com/NebulousLabs/errors"
@@ -18,7 +19,8 @@ import (
 // repairDirectoryHeap
 type directory struct {
 	// Heap controlled fields
-	index int // The index of the item in the heap
+	index  int  // The index of the item in the heap
+	pinned bool // Whether the repair of the directory is prioritized
 
 	staticSiaPath modules.SiaPath
 
@@ -74,6 +76,10 @@ type directoryHeap struct {
 	// heap
 	heapDirectories map[modules.SiaPath]*directory
 
+	// pinnedDirs contains the directories whose subtrees are repaired before
+	// any other directories. Unlike the heap it is not cleared on reset.
+	pinnedDirs map[modules.SiaPath]struct{}
+
 	mu sync.Mutex
 }
 
@@ -89,7 +95,15 @@ func (rdh repairDirectoryHeap) Less(i, j int) bool {
 	iHealth, iRemote := rdh[i].managedHeapHealth()
 	jHealth, jRemote := rdh[j].managedHeapHealth()
 
-	// Prioritize based on Remote first
+	// Prioritize pinned directories that need to be repaired over all other
+	// directories
+	iPinned := rdh[i].pinned && modules.NeedsRepair(iHealth)
+	jPinned := rdh[j].pinned && modules.NeedsRepair(jHealth)
+	if iPinned != jPinned {
+		return iPinned
+	}
+
+	// Prioritize based on Remote next
 	if iRemote && !jRemote {
 		return true
 	}
@@ -184,10 +198,67 @@ func (dh *directoryHeap) managedPush(d *directory) {
 	}
 
 	// If the directory does not exist in the heap, add it to the heap.
+	d.mu.Lock()
+	explored := d.explored
+	d.mu.Unlock()
+	d.pinned = dh.isPinned(d.staticSiaPath, explored)
 	heap.Push(&dh.heap, d)
 	dh.heapDirectories[d.staticSiaPath] = d
 }
 
+// isPinned returns whether the repair of the directory should be prioritized.
+// That is the case for pinned directories and their subdirectories, as well as
+// for unexplored directories that contain a pinned directory. The latter makes
+// sure that the repair loop explores its way to the pinned directories first.
+func (dh *directoryHeap) isPinned(siaPath modules.SiaPath, explored bool) bool {
+	for pinnedDir := range dh.pinnedDirs {
+		if pinnedDir.Contains(siaPath) || (!explored && siaPath.Contains(pinnedDir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// managedIsPinned returns whether the directory is pinned or within a pinned
+// directory.
+func (dh *directoryHeap) managedIsPinned(siaPath modules.SiaPath) bool {
+	dh.mu.Lock()
+	defer dh.mu.Unlock()
+	return dh.isPinned(siaPath, true)
+}
+
+// managedPinnedDirs returns the pinned directories sorted by siapath.
+func (dh *directoryHeap) managedPinnedDirs() []modules.SiaPath {
+	dh.mu.Lock()
+	defer dh.mu.Unlock()
+	pinnedDirs := make([]modules.SiaPath, 0, len(dh.pinnedDirs))
+	for siaPath := range dh.pinnedDirs {
+		pinnedDirs = append(pinnedDirs, siaPath)
+	}
+	sort.Slice(pinnedDirs, func(i, j int) bool {
+		return pinnedDirs[i].String() < pinnedDirs[j].String()
+	})
+	return pinnedDirs
+}
+
+// managedSetPinnedDirs replaces the pinned directories and reprioritizes the
+// directories that are currently in the heap.
+func (dh *directoryHeap) managedSetPinnedDirs(siaPaths []modules.SiaPath) {
+	dh.mu.Lock()
+	defer dh.mu.Unlock()
+	dh.pinnedDirs = make(map[modules.SiaPath]struct{}, len(siaPaths))
+	for _, siaPath := range siaPaths {
+		dh.pinnedDirs[siaPath] = struct{}{}
+	}
+	for _, d := range dh.heap {
+		d.mu.Lock()
+		explored := d.explored
+		d.mu.Unlock()
+		d.pinned = dh.isPinned(d.staticSiaPath, explored)
+	}
+	heap.Init(&dh.heap)
+}
+
 // managedReset clears the directory heap by recreating the heap and
 // heapDirectories.
 func (dh *directoryHeap) managedReset() {
@@ -220,6 +291,7 @@ func (dh *directoryHeap) update(d *directory) bool {
 	if !heapDir.explored || !d.explored {
 		heapDir.explored = false
 	}
+	heapDir.pinned = dh.isPinned(heapDir.staticSiaPath, heapDir.explored)
 	heapDir.mu.Unlock()
 	dh.heapDirectories[d.staticSiaPath] = heapDir
 	heap.Fix(&dh.heap, heapDir.index)
@@ -548,3 +548,65 @@ func TestDirectoryHeapHealth(t *testing.T) {
 		t.Errorf("Expected heapHealth to be %v but was %v", d.health, heapHealth)
 	}
 }
+
+// TestDirectoryHeapPinned checks that pinned directories that need to be
+// repaired are popped before any other directories.
+func TestDirectoryHeapPinned(t *testing.T) {
+	t.Parallel()
+
+	dh := directoryHeap{
+		heapDirectories: make(map[modules.SiaPath]*directory),
+	}
+	siaPath := func(s string) modules.SiaPath {
+		sp, err := modules.NewSiaPath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sp
+	}
+	important := siaPath("important")
+	dh.managedSetPinnedDirs([]modules.SiaPath{important})
+
+	// The root contains the pinned directory. As long as it is unexplored it
+	// is pinned, once it is explored it isn't.
+	if !dh.isPinned(modules.RootSiaPath(), false) || dh.isPinned(modules.RootSiaPath(), true) {
+		t.Fatal("wrong pinned state for root")
+	}
+	if !dh.isPinned(siaPath("important/sub"), true) || dh.isPinned(siaPath("importantother"), false) {
+		t.Fatal("wrong pinned state for subdirectories")
+	}
+
+	// Push a directory with a bad health, a pinned subdirectory with a
+	// health that needs repair and a healthy pinned subdirectory.
+	push := func(sp modules.SiaPath, health float64) {
+		dh.managedPush(&directory{
+			aggregateHealth: health,
+			explored:        true,
+			health:          health,
+			staticSiaPath:   sp,
+		})
+	}
+	push(siaPath("other"), 2*modules.RepairThreshold)
+	push(siaPath("important/sub"), modules.RepairThreshold)
+	push(siaPath("important/healthy"), 0)
+
+	// The pinned directory that needs repair is popped first, the healthy
+	// pinned directory last.
+	for _, expected := range []string{"important/sub", "other", "important/healthy"} {
+		d := dh.managedPop()
+		if d == nil || d.staticSiaPath.String() != expected {
+			t.Fatalf("expected %v but got %v", expected, d)
+		}
+	}
+
+	// Unpinning the directory removes the priority.
+	push(siaPath("other"), 2*modules.RepairThreshold)
+	push(siaPath("important/sub"), modules.RepairThreshold)
+	dh.managedSetPinnedDirs(nil)
+	if d := dh.managedPop(); d.staticSiaPath.String() != "other" {
+		t.Fatal("unpinned directory shouldn't be prioritized", d.staticSiaPath)
+	}
+	if pinned := dh.managedPinnedDirs(); len(pinned) != 0 {
+		t.Fatal("no directories should be pinned", pinned)
+	}
+}
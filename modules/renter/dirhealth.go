@@ -0,0 +1,154 @@
+package renter
+
+import (
+	"sort"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+)
+
+// DirsHealth returns the aggregated health of the subtrees of a siadir and its
+// subdirectories, worst health first. If recursive is false, only the direct
+// subdirectories are included.
+func (r *Renter) DirsHealth(siaPath modules.SiaPath, recursive bool) ([]modules.DirectoryHealth, error) {
+	if err := r.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer r.tg.Done()
+
+	var mu sync.Mutex
+	var dhs []modules.DirectoryHealth
+	dlf := func(di modules.DirectoryInfo) {
+		dh := modules.DirectoryHealth{
+			SiaPath:                      di.SiaPath,
+			AggregateHealth:              di.AggregateHealth,
+			AggregateLastHealthCheckTime: di.AggregateLastHealthCheckTime,
+			AggregateMaxHealthPercentage: di.AggregateMaxHealthPercentage,
+			AggregateMinRedundancy:       di.AggregateMinRedundancy,
+			AggregateNumFiles:            di.AggregateNumFiles,
+			AggregateNumStuckChunks:      di.AggregateNumStuckChunks,
+			AggregateRepairSize:          di.AggregateRepairSize,
+			AggregateSize:                di.AggregateSize,
+			AggregateStuckHealth:         di.AggregateStuckHealth,
+			AggregateStuckSize:           di.AggregateStuckSize,
+			NeedsRepair:                  modules.NeedsRepair(di.AggregateHealth) || di.AggregateNumStuckChunks > 0,
+			Pinned:                       r.directoryHeap.managedIsPinned(di.SiaPath),
+		}
+		mu.Lock()
+		dhs = append(dhs, dh)
+		mu.Unlock()
+	}
+	err := r.staticFileSystem.CachedList(siaPath, recursive, func(modules.FileInfo) {}, dlf)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(dhs, func(i, j int) bool {
+		if dhs[i].AggregateHealth != dhs[j].AggregateHealth {
+			return dhs[i].AggregateHealth > dhs[j].AggregateHealth
+		}
+		return dhs[i].SiaPath.String() < dhs[j].SiaPath.String()
+	})
+	return dhs, nil
+}
+
+// PinnedDirs returns the directories whose subtrees are repaired before any
+// other directories.
+func (r *Renter) PinnedDirs() []modules.SiaPath {
+	return r.directoryHeap.managedPinnedDirs()
+}
+
+// SetDirPinned pins or unpins a directory. The subtrees of pinned directories
+// are repaired before any other directories.
+func (r *Renter) SetDirPinned(siaPath modules.SiaPath, pinned bool) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+
+	pinnedDirs := r.directoryHeap.managedPinnedDirs()
+	if !pinned {
+		var remaining []modules.SiaPath
+		for _, pinnedDir := range pinnedDirs {
+			if !pinnedDir.Equals(siaPath) {
+				remaining = append(remaining, pinnedDir)
+			}
+		}
+		if len(remaining) == len(pinnedDirs) {
+			return errors.New("directory is not pinned")
+		}
+		return r.managedSetPinnedDirs(remaining)
+	}
+
+	// Only existing directories can be pinned.
+	exists, err := r.staticFileSystem.DirExists(siaPath)
+	if err != nil {
+		return err
+	} else if !exists {
+		return filesystem.ErrNotExist
+	}
+	for _, pinnedDir := range pinnedDirs {
+		if pinnedDir.Equals(siaPath) {
+			return nil
+		}
+	}
+	err = r.managedSetPinnedDirs(append(pinnedDirs, siaPath))
+	if err != nil {
+		return err
+	}
+
+	// Wake up the repair loop to look at the pinned directory.
+	select {
+	case r.uploadHeap.repairNeeded <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// managedSetPinnedDirs updates and persists the pinned directories.
+func (r *Renter) managedSetPinnedDirs(pinnedDirs []modules.SiaPath) error {
+	r.directoryHeap.managedSetPinnedDirs(pinnedDirs)
+	id := r.mu.Lock()
+	defer r.mu.Unlock(id)
+	r.persist.PinnedDirs = r.directoryHeap.managedPinnedDirs()
+	return r.saveSync()
+}
+
+// managedRenamePinnedDirs moves the pins within a renamed directory to the
+// directory's new location.
+func (r *Renter) managedRenamePinnedDirs(oldPath, newPath modules.SiaPath) error {
+	pinnedDirs := r.directoryHeap.managedPinnedDirs()
+	var renamed bool
+	for i, pinnedDir := range pinnedDirs {
+		if !oldPath.Contains(pinnedDir) {
+			continue
+		}
+		rebased, err := pinnedDir.Rebase(oldPath, newPath)
+		if err != nil {
+			return err
+		}
+		pinnedDirs[i] = rebased
+		renamed = true
+	}
+	if !renamed {
+		return nil
+	}
+	return r.managedSetPinnedDirs(pinnedDirs)
+}
+
+// managedUnpinDeletedDirs removes the pins within a deleted directory.
+func (r *Renter) managedUnpinDeletedDirs(siaPath modules.SiaPath) error {
+	pinnedDirs := r.directoryHeap.managedPinnedDirs()
+	var remaining []modules.SiaPath
+	for _, pinnedDir := range pinnedDirs {
+		if !siaPath.Contains(pinnedDir) {
+			remaining = append(remaining, pinnedDir)
+		}
+	}
+	if len(remaining) == len(pinnedDirs) {
+		return nil
+	}
+	return r.managedSetPinnedDirs(remaining)
+}
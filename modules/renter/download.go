@@ -54,9 +54,24 @@ type (
 		staticParams downloadParams
 
 		// Retrieval settings for the file.
-		staticLatencyTarget time.Duration // In milliseconds. Lower latency results in lower total system throughput.
-		staticOverdrive     int           // How many extra pieces to download to prevent slow hosts from being a bottleneck.
-		staticPriority      uint64        // Downloads with higher priority will complete first.
+		staticLatencyTarget   time.Duration                   // In milliseconds. Lower latency results in lower total system throughput.
+		staticOverdrivePolicy modules.DownloadOverdrivePolicy // How many extra pieces to download to prevent slow hosts from being a bottleneck.
+		staticPriority        uint64                          // Downloads with higher priority will complete first.
+		staticRequestClass    modules.DownloadRequestClass    // The class of the download, empty for repair downloads.
+
+		// overdrivePieces is the number of pieces the completed chunks fetched
+		// beyond the minimum.
+		overdrivePieces uint64
+
+		// Host selection. If staticCostWeight is not 0, the download prefers
+		// hosts based on their prices and estimatedCost is the estimated cost
+		// of fetching the pieces from the preferred hosts.
+		staticCostWeight float64
+		estimatedCost    types.Currency
+
+		// staticRateLimit paces the download's chunks according to its max
+		// speed. It is nil if the download isn't limited.
+		staticRateLimit *downloadRateLimit
 
 		// Utilities.
 		r  *Renter    // The renter that was used to create the download.
@@ -68,15 +83,22 @@ type (
 		destination       downloadDestination // The place to write the downloaded data.
 		destinationType   string              // "file", "buffer", "http stream", etc.
 		destinationString string              // The string to report to the user for the destination.
+		costWeight        float64             // How much host prices are weighed against their speed when choosing hosts.
 		disableLocalFetch bool                // Whether or not the file can be fetched from disk if available.
 		file              *siafile.Snapshot   // The file to download.
 		latencyTarget     time.Duration       // Workers above this latency will be automatically put on standby initially.
 		length            uint64              // Length of download. Cannot be 0.
 		needsMemory       bool                // Whether new memory needs to be allocated to perform the download.
 		offset            uint64              // Offset within the file to start the download. Must be less than the total filesize.
-		overdrive         int                 // How many extra pieces to download to prevent slow hosts from being a bottleneck.
+		maxSpeed          uint64              // Bytes per second the download may fetch from hosts, 0 for no limit.
 		priority          uint64              // Files with a higher priority will be downloaded first.
 
+		// overdrivePolicy determines how many extra pieces are downloaded to
+		// prevent slow hosts from being a bottleneck. It is taken from the
+		// requestClass of the download. Repair downloads don't have a class.
+		overdrivePolicy modules.DownloadOverdrivePolicy
+		requestClass    modules.DownloadRequestClass
+
 		staticMemoryManager *memoryManager
 
 		// staticSpendingCategory specifies what field to update when we track
@@ -283,6 +305,9 @@ func (r *Renter) managedDownload(p modules.RenterDownloadParameters) (_ *downloa
 	if p.Offset < 0 || p.Offset+p.Length > entry.Size() {
 		return nil, fmt.Errorf("offset and length combination invalid, max byte is at index %d", entry.Size()-1)
 	}
+	if p.CostWeight < 0 || p.CostWeight > 1 {
+		return nil, errors.New("cost weight must be between 0 and 1")
+	}
 
 	// Instantiate the correct downloadWriter implementation.
 	var dw downloadDestination
@@ -321,17 +346,21 @@ func (r *Renter) managedDownload(p modules.RenterDownloadParameters) (_ *downloa
 	d, err := r.managedNewDownload(downloadParams{
 		destination:       dw,
 		destinationType:   destinationType,
+		costWeight:        p.CostWeight,
 		destinationString: p.Destination,
 		disableLocalFetch: p.DisableDiskFetch,
 		file:              snap,
 
 		latencyTarget: 25e3 * time.Millisecond, // TODO: high default until full latency support is added.
 		length:        p.Length,
+		maxSpeed:      p.MaxSpeed,
 		needsMemory:   true,
 		offset:        p.Offset,
-		overdrive:     3, // TODO: moderate default until full overdrive support is added.
 		priority:      5, // TODO: moderate default until full priority support is added.
 
+		overdrivePolicy: r.managedDownloadOverdrivePolicy(modules.DownloadClassDownload),
+		requestClass:    modules.DownloadClassDownload,
+
 		staticMemoryManager:    r.userDownloadMemoryManager, // user initiated download
 		staticSpendingCategory: categoryDownload,
 	})
@@ -360,6 +389,20 @@ func (r *Renter) managedDownload(p modules.RenterDownloadParameters) (_ *downloa
 		r.downloadHistoryMu.Lock()
 		r.downloadHistory[d.UID()] = d
 		r.downloadHistoryMu.Unlock()
+
+		// Report the download to the event stream once it's done.
+		d.OnComplete(func(err error) error {
+			e := modules.RenterEvent{
+				Type:       modules.RenterEventDownloadComplete,
+				SiaPath:    d.staticSiaPath,
+				DownloadID: d.UID(),
+			}
+			if err != nil {
+				e.Error = err.Error()
+			}
+			r.staticEvents.callAdd(e)
+			return nil
+		})
 	}
 
 	// Return the download object
@@ -396,9 +439,12 @@ func (r *Renter) managedNewDownload(params downloadParams) (*download, error) {
 		staticLatencyTarget:   params.latencyTarget,
 		staticLength:          params.length,
 		staticOffset:          params.offset,
-		staticOverdrive:       params.overdrive,
+		staticOverdrivePolicy: params.overdrivePolicy,
 		staticSiaPath:         params.file.SiaPath(),
 		staticPriority:        params.priority,
+		staticRequestClass:    params.requestClass,
+		staticCostWeight:      params.costWeight,
+		staticRateLimit:       newDownloadRateLimit(params.maxSpeed),
 
 		r:            r,
 		staticParams: params,
@@ -468,6 +514,14 @@ func (d *download) Start() error {
 		}
 	}
 
+	// If the download weighs host prices, estimate the cost and latency of
+	// fetching a full piece from every host.
+	var candidates map[string]downloadHostCandidate
+	if d.staticCostWeight > 0 {
+		_, pieceLength := sectorOffsetAndLength(0, params.file.ChunkSize(), params.file.ErasureCode())
+		candidates = d.r.managedDownloadHostCandidates(pieceLength)
+	}
+
 	// Queue the downloads for each chunk.
 	writeOffset := int64(0) // where to write a chunk within the download destination.
 	d.chunksRemaining += maxChunk - minChunk + 1
@@ -527,10 +581,15 @@ func (d *download) Start() error {
 		udc.staticWriteOffset = writeOffset
 		writeOffset += int64(udc.staticFetchLength)
 
-		// TODO: Currently all chunks are given overdrive. This should probably
-		// be changed once the hostdb knows how to measure host speed/latency
-		// and once we can assign overdrive dynamically.
-		udc.staticOverdrive = params.overdrive
+		// TODO: Currently all chunks are given the same overdrive policy.
+		// This should probably be changed once the hostdb knows how to
+		// measure host speed/latency.
+		udc.staticOverdrivePolicy = params.overdrivePolicy
+
+		// Pick the preferred hosts for the chunk.
+		if candidates != nil {
+			d.managedSetPreferredHosts(udc, candidates)
+		}
 
 		// Add this chunk to the chunk heap, and notify the download loop that
 		// there is work to do.
@@ -543,6 +602,35 @@ func (d *download) Start() error {
 	return nil
 }
 
+// managedSetPreferredHosts picks the hosts that fit the download's cost
+// weight best out of the hosts that store pieces of the chunk and adds the
+// expected cost of fetching the chunk from them to the download's estimated
+// cost.
+func (d *download) managedSetPreferredHosts(udc *unfinishedDownloadChunk, candidates map[string]downloadHostCandidate) {
+	var chunkCandidates []downloadHostCandidate
+	for hostKey := range udc.staticChunkMap {
+		if c, exists := candidates[hostKey]; exists {
+			chunkCandidates = append(chunkCandidates, c)
+		}
+	}
+	minPieces := udc.erasureCode.MinPieces()
+	preferred := preferredDownloadHosts(chunkCandidates, minPieces+udc.staticOverdrivePolicy.BaseWorkers, udc.staticLatencyTarget, d.staticCostWeight)
+
+	udc.staticPreferredHosts = make(map[string]struct{}, len(preferred))
+	udc.preferredPending = make(map[string]struct{}, len(preferred))
+	var cost types.Currency
+	for i, c := range preferred {
+		udc.staticPreferredHosts[c.staticHostKey] = struct{}{}
+		udc.preferredPending[c.staticHostKey] = struct{}{}
+		if i < minPieces {
+			cost = cost.Add(c.staticCost)
+		}
+	}
+	d.mu.Lock()
+	d.estimatedCost = d.estimatedCost.Add(cost)
+	d.mu.Unlock()
+}
+
 // DownloadByUID returns a single download from the history by it's UID.
 func (r *Renter) DownloadByUID(uid modules.DownloadID) (modules.DownloadInfo, bool) {
 	r.downloadHistoryMu.Lock()
@@ -566,6 +654,13 @@ func (r *Renter) DownloadByUID(uid modules.DownloadID) (modules.DownloadInfo, bo
 		StartTime:            d.staticStartTime,
 		StartTimeUnix:        d.staticStartTime.UnixNano(),
 		TotalDataTransferred: atomic.LoadUint64(&d.atomicTotalDataTransferred),
+
+		CostWeight:    d.staticCostWeight,
+		EstimatedCost: d.estimatedCost,
+		MaxSpeed:      d.staticRateLimit.callMaxSpeed(),
+
+		RequestClass:    d.staticRequestClass,
+		OverdrivePieces: d.overdrivePieces,
 	}, true
 }
 
@@ -610,6 +705,13 @@ func (r *Renter) DownloadHistory() []modules.DownloadInfo {
 			StartTime:            d.staticStartTime,
 			StartTimeUnix:        d.staticStartTime.UnixNano(),
 			TotalDataTransferred: atomic.LoadUint64(&d.atomicTotalDataTransferred),
+
+			CostWeight:    d.staticCostWeight,
+			EstimatedCost: d.estimatedCost,
+			MaxSpeed:      d.staticRateLimit.callMaxSpeed(),
+
+			RequestClass:    d.staticRequestClass,
+			OverdrivePieces: d.overdrivePieces,
 		}
 		// Release download lock before calling d.Err(), which will acquire the
 		// lock. The error needs to be checked separately because we need to
@@ -0,0 +1,163 @@
+package renter
+
+import (
+	"archive/tar"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+)
+
+const (
+	// batchDownloadLookahead is the number of files of a batch download for
+	// which streams are opened ahead of the file that is currently written to
+	// the archive. A stream starts fetching the first chunks of its file right
+	// away, which keeps the workers busy across files instead of downloading
+	// the files one after another.
+	batchDownloadLookahead = 4
+)
+
+var (
+	// errNoBatchDownloadPaths is returned if a batch download is started
+	// without any siapaths.
+	errNoBatchDownloadPaths = errors.New("no siapaths provided for the batch download")
+)
+
+// managedBatchDownloadFiles returns the files of a batch download in the order
+// in which they are written to the archive. Directories are replaced by the
+// files within them and their subdirectories. Files which are included
+// multiple times are only downloaded once.
+func (r *Renter) managedBatchDownloadFiles(siaPaths []modules.SiaPath) ([]modules.FileInfo, error) {
+	if len(siaPaths) == 0 {
+		return nil, errNoBatchDownloadPaths
+	}
+	var files []modules.FileInfo
+	seen := make(map[modules.SiaPath]struct{})
+	add := func(fi modules.FileInfo) {
+		if _, exists := seen[fi.SiaPath]; exists {
+			return
+		}
+		seen[fi.SiaPath] = struct{}{}
+		files = append(files, fi)
+	}
+	for _, siaPath := range siaPaths {
+		isFile, err := r.staticFileSystem.FileExists(siaPath)
+		if err != nil {
+			return nil, err
+		}
+		if isFile {
+			fi, err := r.staticFileSystem.CachedFileInfo(siaPath)
+			if err != nil {
+				return nil, errors.AddContext(err, "failed to get file info of "+siaPath.String())
+			}
+			add(fi)
+			continue
+		}
+		isDir, err := r.staticFileSystem.DirExists(siaPath)
+		if err != nil {
+			return nil, err
+		}
+		if !isDir {
+			return nil, errors.AddContext(filesystem.ErrNotExist, siaPath.String())
+		}
+		var mu sync.Mutex
+		var dirFiles []modules.FileInfo
+		err = r.FileList(siaPath, true, true, func(fi modules.FileInfo) {
+			mu.Lock()
+			dirFiles = append(dirFiles, fi)
+			mu.Unlock()
+		})
+		if err != nil {
+			return nil, errors.AddContext(err, "failed to list files of "+siaPath.String())
+		}
+		sort.Slice(dirFiles, func(i, j int) bool {
+			return dirFiles[i].SiaPath.String() < dirFiles[j].SiaPath.String()
+		})
+		for _, fi := range dirFiles {
+			add(fi)
+		}
+	}
+	return files, nil
+}
+
+// batchDownloadName returns the name of a file's entry in the archive of a
+// batch download. Files within root are named relative to root.
+func batchDownloadName(siaPath, root modules.SiaPath) string {
+	if root.IsRoot() || !root.Contains(siaPath) || root.Equals(siaPath) {
+		return siaPath.String()
+	}
+	return strings.TrimPrefix(siaPath.String(), root.String()+"/")
+}
+
+// DownloadBatch downloads the files and the contents of the directories at
+// the provided siapaths and writes them to w as a tar archive. The archive
+// entries are named after the siapaths of the files relative to root. Streams
+// for the next files are opened while a file is written to the archive, so
+// that the chunks of multiple files are downloaded at the same time. Nothing
+// is written to w if the siapaths can't be resolved.
+func (r *Renter) DownloadBatch(siaPaths []modules.SiaPath, root modules.SiaPath, disableLocalFetch bool, w io.Writer) (err error) {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+
+	files, err := r.managedBatchDownloadFiles(siaPaths)
+	if err != nil {
+		return err
+	}
+
+	// Close the streams which are still open when returning early.
+	streams := make([]modules.Streamer, len(files))
+	defer func() {
+		for _, s := range streams {
+			if s != nil {
+				err = errors.Compose(err, s.Close())
+			}
+		}
+	}()
+	openStream := func(i int) error {
+		if i >= len(files) {
+			return nil
+		}
+		_, s, err := r.Streamer(files[i].SiaPath, disableLocalFetch)
+		if err != nil {
+			return errors.AddContext(err, "failed to open stream for "+files[i].SiaPath.String())
+		}
+		streams[i] = s
+		return nil
+	}
+	for i := 0; i < batchDownloadLookahead; i++ {
+		if err := openStream(i); err != nil {
+			return err
+		}
+	}
+
+	tw := tar.NewWriter(w)
+	for i, fi := range files {
+		if err := openStream(i + batchDownloadLookahead); err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return errors.AddContext(err, "failed to create tar header")
+		}
+		hdr.Name = batchDownloadName(fi.SiaPath, root)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return errors.AddContext(err, "failed to write tar header")
+		}
+		if _, err := io.CopyN(tw, streams[i], hdr.Size); err != nil {
+			return errors.AddContext(err, "failed to download "+fi.SiaPath.String())
+		}
+		err = streams[i].Close()
+		streams[i] = nil
+		if err != nil {
+			return errors.AddContext(err, "failed to close stream")
+		}
+	}
+	return tw.Close()
+}
@@ -0,0 +1,37 @@
+package renter
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestBatchDownloadName tests naming the entries of a batch download's
+// archive.
+func TestBatchDownloadName(t *testing.T) {
+	t.Parallel()
+
+	sp := func(s string) modules.SiaPath {
+		siaPath, err := modules.NewSiaPath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return siaPath
+	}
+	tests := []struct {
+		siaPath modules.SiaPath
+		root    modules.SiaPath
+		name    string
+	}{
+		{sp("home/user/foo/bar"), modules.UserFolder, "foo/bar"},
+		{sp("home/user/foo/bar"), modules.RootSiaPath(), "home/user/foo/bar"},
+		{sp("home/username/bar"), modules.UserFolder, "home/username/bar"},
+		{sp("snapshots/bar"), modules.UserFolder, "snapshots/bar"},
+		{modules.UserFolder, modules.UserFolder, "home/user"},
+	}
+	for _, test := range tests {
+		if name := batchDownloadName(test.siaPath, test.root); name != test.name {
+			t.Errorf("expected name %v for %v relative to %v but got %v", test.name, test.siaPath, test.root, name)
+		}
+	}
+}
@@ -58,15 +58,25 @@ type unfinishedDownloadChunk struct {
 	staticLatencyTarget    time.Duration
 	staticNeedsMemory      bool // Set to true if memory was not pre-allocated for this chunk.
 	staticMemoryManager    *memoryManager
-	staticOverdrive        int
+	staticOverdrivePolicy  modules.DownloadOverdrivePolicy
 	staticPriority         uint64
 
+	// staticPreferredHosts are the hosts that should be used to fetch the
+	// pieces of the chunk if possible. If nil, all hosts are treated equally.
+	// preferredPending contains the preferred hosts whose workers haven't
+	// processed the chunk yet and is protected by the mutex.
+	staticPreferredHosts map[string]struct{}
+	preferredPending     map[string]struct{}
+
 	// Download chunk state - need mutex to access.
 	completedPieces   []bool    // Which pieces were downloaded successfully.
+	distributedTime   time.Time // When the chunk was handed to the workers, zero if it was fetched from disk.
 	failed            bool      // Indicates if the chunk has been marked as failed.
+	hedges            int       // Number of extra pieces the chunk's hedge delay allowed so far.
 	physicalChunkData [][]byte  // Used to recover the logical data.
 	pieceUsage        []bool    // Which pieces are being actively fetched.
 	piecesCompleted   int       // Number of pieces that have successfully completed.
+	piecesLaunched    int       // Number of pieces that workers started fetching.
 	piecesRegistered  int       // Number of pieces that workers are actively fetching.
 	recoveryComplete  bool      // Whether or not the recovery has completed and the chunk memory released.
 	workersRemaining  int       // Number of workers still able to fetch the chunk.
@@ -105,7 +115,7 @@ func (udc *unfinishedDownloadChunk) managedCleanUp() {
 	udc.mu.Lock()
 	if udc.workersRemaining+udc.piecesCompleted < udc.erasureCode.MinPieces() && !udc.failed {
 		str := fmt.Sprintf("workers remaining %v, pieces completed %v, min pieces %v", udc.workersRemaining, udc.piecesCompleted, udc.erasureCode.MinPieces())
-		udc.fail(errors.AddContext(errNotEnoughWorkers, str))
+		udc.fail(errors.AddContext(ErrNotEnoughWorkers, str))
 	}
 	// Return any excess memory.
 	udc.returnMemory()
@@ -118,7 +128,7 @@ func (udc *unfinishedDownloadChunk) managedCleanUp() {
 
 	// Check whether standby workers are required.
 	chunkComplete := udc.piecesCompleted >= udc.erasureCode.MinPieces()
-	desiredPiecesRegistered := udc.erasureCode.MinPieces() + udc.staticOverdrive - udc.piecesCompleted
+	desiredPiecesRegistered := udc.erasureCode.MinPieces() + udc.overdrive() - udc.piecesCompleted
 	standbyWorkersRequired := !chunkComplete && udc.piecesRegistered < desiredPiecesRegistered
 	if !standbyWorkersRequired {
 		udc.mu.Unlock()
@@ -148,11 +158,25 @@ func (udc *unfinishedDownloadChunk) managedFinalizeRecovery() {
 	udc.mu.Lock()
 	udc.physicalChunkData = nil
 	udc.recoveryComplete = true
+	var overdrivePieces uint64
+	if extra := udc.piecesLaunched - udc.erasureCode.MinPieces(); extra > 0 {
+		overdrivePieces = uint64(extra)
+	}
+	distributedTime := udc.distributedTime
 	udc.mu.Unlock()
 
+	// Track the chunk's latency and extra pieces for its download class.
+	// Chunks that were fetched from disk don't say anything about the
+	// overdrive policy.
+	class := udc.download.staticRequestClass
+	if class != "" && !distributedTime.IsZero() {
+		udc.download.r.staticOverdriveStats.callAddChunk(class, time.Since(distributedTime), overdrivePieces)
+	}
+
 	// Update the download and signal completion of this chunk.
 	udc.download.mu.Lock()
 	defer udc.download.mu.Unlock()
+	udc.download.overdrivePieces += overdrivePieces
 	udc.download.chunksRemaining--
 	if udc.download.chunksRemaining == 0 {
 		// Download is complete, send out a notification.
@@ -62,14 +62,14 @@ func (dch *downloadChunkHeap) Pop() interface{} {
 // before memory can be acquired.
 func (r *Renter) managedAcquireMemoryForDownloadChunk(udc *unfinishedDownloadChunk) bool {
 	// The amount of memory required is equal minimum number of pieces plus the
-	// overdrive amount.
+	// maximum overdrive amount.
 	//
 	// TODO: This allocation assumes that the erasure coding does not need extra
 	// memory to decode a bunch of pieces. Optimized erasure coding will not
 	// need extra memory to decode a bunch of pieces, though I do not believe
 	// our erasure coding has been optimized around this yet, so we may actually
 	// go over the memory limits when we decode pieces.
-	memoryRequired := uint64(udc.staticOverdrive+udc.erasureCode.MinPieces()) * udc.staticPieceSize
+	memoryRequired := uint64(udc.staticOverdrivePolicy.MaxExtraPieces+udc.erasureCode.MinPieces()) * udc.staticPieceSize
 	udc.memoryAllocated = memoryRequired
 	return udc.staticMemoryManager.Request(context.Background(), memoryRequired, memoryPriorityHigh)
 }
@@ -129,6 +129,13 @@ func (r *Renter) managedDistributeDownloadChunkToWorkers(udc *unfinishedDownload
 	r.staticWorkerPool.mu.RLock()
 	udc.mu.Lock()
 	udc.workersRemaining = len(r.staticWorkerPool.workers)
+	udc.distributedTime = time.Now()
+	// Preferred hosts without a worker will never process the chunk.
+	for hostKey := range udc.preferredPending {
+		if _, exists := r.staticWorkerPool.workers[hostKey]; !exists {
+			delete(udc.preferredPending, hostKey)
+		}
+	}
 	udc.mu.Unlock()
 	for _, worker := range r.staticWorkerPool.workers {
 		go worker.threadedPerformDownloadChunkJob(udc)
@@ -139,23 +146,51 @@ func (r *Renter) managedDistributeDownloadChunkToWorkers(udc *unfinishedDownload
 	// the chunk, so we must make sure that managedCleanUp is called at least
 	// once on the chunk.
 	udc.managedCleanUp()
+
+	// Fetch extra pieces if the chunk takes too long.
+	policy := udc.staticOverdrivePolicy
+	if policy.HedgeDelay > 0 && policy.MaxExtraPieces > policy.BaseWorkers {
+		go r.threadedHedgeDownloadChunk(udc)
+	}
 }
 
 // managedNextDownloadChunk will fetch the next chunk from the download heap. If
 // the download heap is empty, 'nil' will be returned.
-func (r *Renter) managedNextDownloadChunk() *unfinishedDownloadChunk {
+//
+// Chunks of downloads that are ahead of their rate limit are skipped and
+// remain in the heap. If no chunk is returned, the returned duration is how
+// long it takes until the first of the skipped chunks can be distributed, or 0
+// if no chunks were skipped.
+func (r *Renter) managedNextDownloadChunk() (*unfinishedDownloadChunk, time.Duration) {
 	r.downloadHeapMu.Lock()
 	defer r.downloadHeapMu.Unlock()
 
-	for {
-		if r.downloadHeap.Len() <= 0 {
-			return nil
+	var throttled []*unfinishedDownloadChunk
+	defer func() {
+		for _, udc := range throttled {
+			heap.Push(r.downloadHeap, udc)
 		}
+	}()
+
+	now := time.Now()
+	var wait time.Duration
+	for r.downloadHeap.Len() > 0 {
 		nextChunk := heap.Pop(r.downloadHeap).(*unfinishedDownloadChunk)
-		if !nextChunk.download.staticComplete() {
-			return nextChunk
+		if nextChunk.download.staticComplete() {
+			continue
+		}
+		rl := nextChunk.download.staticRateLimit
+		if w := rl.callWait(now); w > 0 {
+			throttled = append(throttled, nextChunk)
+			if wait == 0 || w < wait {
+				wait = w
+			}
+			continue
 		}
+		rl.callReserve(nextChunk.staticFetchBytes(), now)
+		return nextChunk, 0
 	}
+	return nil, wait
 }
 
 // managedTryFetchChunkFromDisk will try to fetch the chunk from disk if
@@ -248,6 +283,14 @@ func (r *Renter) managedTryFetchChunkFromDisk(chunk *unfinishedDownloadChunk) bo
 				localPath, fileName, err)
 			return false
 		}
+		// Return the pieces to the sector buffer pool once the chunk was
+		// recovered or serving it from disk failed. A buffer destination keeps
+		// the pieces it was handed, so they can't be reused in that case.
+		defer func() {
+			if _, keepsPieces := chunk.destination.(*downloadDestinationBuffer); !success || !keepsPieces {
+				staticSectorBufferPool.callPutPieces(pieces)
+			}
+		}()
 		shards, err := chunk.renterFile.ErasureCode().EncodeShards(pieces)
 		if err != nil {
 			r.log.Debugf("managedTryFetchChunkFromDisk failed to encode data pieces from %v for %v: %v",
@@ -278,6 +321,10 @@ func (r *Renter) threadedDownloadLoop() {
 	// Infinite loop to process downloads. Will return if r.tg.Stop() is called.
 LOOP:
 	for {
+		// throttled fires once a chunk that was skipped because of its
+		// download's rate limit can be distributed.
+		var throttled <-chan time.Time
+
 		// Wait until the renter is online.
 		if !r.managedBlockUntilOnline() {
 			// The renter shut down before the internet connection was restored.
@@ -302,9 +349,12 @@ LOOP:
 			}
 
 			// Get the next chunk.
-			nextChunk := r.managedNextDownloadChunk()
+			nextChunk, wait := r.managedNextDownloadChunk()
 			if nextChunk == nil {
 				// Break out of the inner loop and wait for more work.
+				if wait > 0 {
+					throttled = time.After(wait)
+				}
 				break
 			}
 
@@ -326,6 +376,7 @@ LOOP:
 		case <-r.tg.StopChan():
 			return
 		case <-r.newDownloads:
+		case <-throttled:
 		}
 	}
 }
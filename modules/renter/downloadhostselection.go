@@ -0,0 +1,85 @@
+package renter
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"go.sia.tech/siad/types"
+)
+
+// downloadHostCandidate contains the estimated cost and latency of fetching a
+// piece from a host.
+type downloadHostCandidate struct {
+	staticCost    types.Currency
+	staticHostKey string
+	staticLatency time.Duration
+}
+
+// managedDownloadHostCandidates returns a download host candidate for every
+// worker that isn't on cooldown, keyed by the host's public key.
+func (r *Renter) managedDownloadHostCandidates(length uint64) map[string]downloadHostCandidate {
+	candidates := make(map[string]downloadHostCandidate)
+	for _, w := range r.staticWorkerPool.callWorkers() {
+		jq := w.staticJobLowPrioReadQueue
+		if jq.callOnCooldown() {
+			continue
+		}
+		hostKey := w.staticHostPubKey.String()
+		candidates[hostKey] = downloadHostCandidate{
+			staticCost:    jq.callExpectedJobCost(length),
+			staticHostKey: hostKey,
+			staticLatency: jq.callExpectedJobTime(length),
+		}
+	}
+	return candidates
+}
+
+// preferredDownloadHosts sorts the candidates by how well they fit the cost
+// weight of a download and returns the best numHosts of them. Hosts that meet
+// the latency target always come before hosts that don't. Among them, the
+// hosts are ordered by a score that blends their normalized cost and latency
+// using costWeight.
+func preferredDownloadHosts(candidates []downloadHostCandidate, numHosts int, latencyTarget time.Duration, costWeight float64) []downloadHostCandidate {
+	if costWeight < 0 {
+		costWeight = 0
+	} else if costWeight > 1 {
+		costWeight = 1
+	}
+
+	// Find the maximum cost and latency to normalize the candidates.
+	var maxCost types.Currency
+	var maxLatency time.Duration
+	for _, c := range candidates {
+		if c.staticCost.Cmp(maxCost) > 0 {
+			maxCost = c.staticCost
+		}
+		if c.staticLatency > maxLatency {
+			maxLatency = c.staticLatency
+		}
+	}
+	score := func(c downloadHostCandidate) float64 {
+		var normCost, normLatency float64
+		if !maxCost.IsZero() {
+			normCost, _ = new(big.Rat).SetFrac(c.staticCost.Big(), maxCost.Big()).Float64()
+		}
+		if maxLatency > 0 {
+			normLatency = float64(c.staticLatency) / float64(maxLatency)
+		}
+		return costWeight*normCost + (1-costWeight)*normLatency
+	}
+
+	sorted := append([]downloadHostCandidate{}, candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iMeets := sorted[i].staticLatency <= latencyTarget
+		jMeets := sorted[j].staticLatency <= latencyTarget
+		if iMeets != jMeets {
+			return iMeets
+		}
+		return score(sorted[i]) < score(sorted[j])
+	})
+	if len(sorted) > numHosts {
+		sorted = sorted[:numHosts]
+	}
+	return sorted
+}
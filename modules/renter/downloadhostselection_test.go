@@ -0,0 +1,72 @@
+package renter
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/types"
+)
+
+// TestPreferredDownloadHosts is a unit test for preferredDownloadHosts.
+func TestPreferredDownloadHosts(t *testing.T) {
+	t.Parallel()
+
+	candidates := []downloadHostCandidate{
+		{staticHostKey: "fast", staticCost: types.NewCurrency64(100), staticLatency: time.Millisecond},
+		{staticHostKey: "medium", staticCost: types.NewCurrency64(50), staticLatency: 10 * time.Millisecond},
+		{staticHostKey: "cheap", staticCost: types.NewCurrency64(10), staticLatency: 100 * time.Millisecond},
+		{staticHostKey: "tooslow", staticCost: types.NewCurrency64(1), staticLatency: time.Second},
+	}
+	keys := func(hosts []downloadHostCandidate) []string {
+		var keys []string
+		for _, h := range hosts {
+			keys = append(keys, h.staticHostKey)
+		}
+		return keys
+	}
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+	latencyTarget := 500 * time.Millisecond
+
+	tests := []struct {
+		costWeight float64
+		numHosts   int
+		expected   []string
+	}{
+		// Ignoring prices prefers the fastest hosts.
+		{0, 2, []string{"fast", "medium"}},
+		// Only looking at prices prefers the cheapest hosts that meet the
+		// latency target.
+		{1, 2, []string{"cheap", "medium"}},
+		// Hosts that don't meet the latency target are still used last.
+		{1, 4, []string{"cheap", "medium", "fast", "tooslow"}},
+		// Out of bounds weights are clamped.
+		{-1, 1, []string{"fast"}},
+		{2, 1, []string{"cheap"}},
+	}
+	for _, test := range tests {
+		preferred := preferredDownloadHosts(candidates, test.numHosts, latencyTarget, test.costWeight)
+		if !equal(keys(preferred), test.expected) {
+			t.Errorf("costWeight %v numHosts %v: expected %v but got %v", test.costWeight, test.numHosts, test.expected, keys(preferred))
+		}
+	}
+
+	// The input shouldn't be modified.
+	if candidates[0].staticHostKey != "fast" || candidates[3].staticHostKey != "tooslow" {
+		t.Fatal("candidates were modified")
+	}
+
+	// No candidates results in no preferred hosts.
+	if preferred := preferredDownloadHosts(nil, 3, latencyTarget, 0.5); len(preferred) != 0 {
+		t.Fatal("expected no preferred hosts", preferred)
+	}
+}
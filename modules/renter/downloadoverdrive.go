@@ -0,0 +1,196 @@
+package renter
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+)
+
+const (
+	// maxOverdriveStatsChunks is the number of recent chunks per download
+	// class that the overdrive stats are computed from.
+	maxOverdriveStatsChunks = 1000
+)
+
+var (
+	// defaultDownloadOverdrivePolicies are the overdrive policies of the
+	// download classes that weren't changed by the user. Streams are latency
+	// sensitive and therefore fetch more extra pieces.
+	defaultDownloadOverdrivePolicies = map[modules.DownloadRequestClass]modules.DownloadOverdrivePolicy{
+		modules.DownloadClassDownload: {BaseWorkers: 3, MaxExtraPieces: 3},
+		modules.DownloadClassStream:   {BaseWorkers: 5, MaxExtraPieces: 5},
+	}
+
+	// errUnknownDownloadClass is returned if the overdrive policy of an
+	// unknown download class is requested or updated.
+	errUnknownDownloadClass = errors.New("unknown download class")
+)
+
+// downloadOverdriveStats tracks the latency and the number of extra pieces of
+// the recently completed chunks of every download class.
+type downloadOverdriveStats struct {
+	classes map[modules.DownloadRequestClass]*overdriveClassStats
+	mu      sync.Mutex
+}
+
+// overdriveClassStats contains the recently completed chunks of a single
+// download class. Both slices are used as ring buffers.
+type overdriveClassStats struct {
+	latencies       []time.Duration
+	overdrivePieces []uint64
+	next            int
+}
+
+// newDownloadOverdriveStats creates empty overdrive stats.
+func newDownloadOverdriveStats() *downloadOverdriveStats {
+	return &downloadOverdriveStats{
+		classes: make(map[modules.DownloadRequestClass]*overdriveClassStats),
+	}
+}
+
+// callAddChunk records a completed chunk of the given download class.
+func (dos *downloadOverdriveStats) callAddChunk(class modules.DownloadRequestClass, latency time.Duration, overdrivePieces uint64) {
+	dos.mu.Lock()
+	defer dos.mu.Unlock()
+	cs, exists := dos.classes[class]
+	if !exists {
+		cs = &overdriveClassStats{}
+		dos.classes[class] = cs
+	}
+	if len(cs.latencies) < maxOverdriveStatsChunks {
+		cs.latencies = append(cs.latencies, latency)
+		cs.overdrivePieces = append(cs.overdrivePieces, overdrivePieces)
+		return
+	}
+	cs.latencies[cs.next] = latency
+	cs.overdrivePieces[cs.next] = overdrivePieces
+	cs.next = (cs.next + 1) % maxOverdriveStatsChunks
+}
+
+// callStatus returns the stats of the given download class. The policy of the
+// returned status is not set.
+func (dos *downloadOverdriveStats) callStatus(class modules.DownloadRequestClass) (status modules.DownloadOverdriveStatus) {
+	dos.mu.Lock()
+	cs, exists := dos.classes[class]
+	if !exists {
+		dos.mu.Unlock()
+		return
+	}
+	latencies := append([]time.Duration{}, cs.latencies...)
+	for _, pieces := range cs.overdrivePieces {
+		status.OverdrivePieces += pieces
+	}
+	dos.mu.Unlock()
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	status.Chunks = uint64(len(latencies))
+	status.LatencyP50 = latencies[(len(latencies)-1)*50/100]
+	status.LatencyP99 = latencies[(len(latencies)-1)*99/100]
+	return
+}
+
+// managedDownloadOverdrivePolicy returns the overdrive policy of a download
+// class.
+func (r *Renter) managedDownloadOverdrivePolicy(class modules.DownloadRequestClass) modules.DownloadOverdrivePolicy {
+	id := r.mu.RLock()
+	defer r.mu.RUnlock(id)
+	if policy, exists := r.persist.DownloadOverdrivePolicies[class]; exists {
+		return policy
+	}
+	return defaultDownloadOverdrivePolicies[class]
+}
+
+// DownloadOverdrive returns the overdrive policy and the recently measured
+// chunk latencies of every download class.
+func (r *Renter) DownloadOverdrive() (map[modules.DownloadRequestClass]modules.DownloadOverdriveStatus, error) {
+	if err := r.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer r.tg.Done()
+	statuses := make(map[modules.DownloadRequestClass]modules.DownloadOverdriveStatus)
+	for _, class := range modules.DownloadRequestClasses {
+		status := r.staticOverdriveStats.callStatus(class)
+		status.Policy = r.managedDownloadOverdrivePolicy(class)
+		statuses[class] = status
+	}
+	return statuses, nil
+}
+
+// SetDownloadOverdrivePolicy sets the overdrive policy of a download class.
+// The policy applies to chunks that are queued after it was set.
+func (r *Renter) SetDownloadOverdrivePolicy(class modules.DownloadRequestClass, policy modules.DownloadOverdrivePolicy) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	if _, exists := defaultDownloadOverdrivePolicies[class]; !exists {
+		return errors.AddContext(errUnknownDownloadClass, string(class))
+	}
+	if policy.BaseWorkers < 0 || policy.HedgeDelay < 0 {
+		return errors.New("base workers and hedge delay can't be negative")
+	}
+	if policy.MaxExtraPieces < policy.BaseWorkers {
+		return errors.New("max extra pieces can't be smaller than the base workers")
+	}
+
+	id := r.mu.Lock()
+	defer r.mu.Unlock(id)
+	if r.persist.DownloadOverdrivePolicies == nil {
+		r.persist.DownloadOverdrivePolicies = make(map[modules.DownloadRequestClass]modules.DownloadOverdrivePolicy)
+	}
+	r.persist.DownloadOverdrivePolicies[class] = policy
+	return r.saveSync()
+}
+
+// overdrive returns the number of pieces beyond the minimum that should
+// currently be fetched for the chunk. It starts at the policy's base workers
+// and grows with every hedge up to the policy's max extra pieces.
+func (udc *unfinishedDownloadChunk) overdrive() int {
+	policy := udc.staticOverdrivePolicy
+	overdrive := policy.BaseWorkers + udc.hedges
+	if overdrive > policy.MaxExtraPieces {
+		return policy.MaxExtraPieces
+	}
+	return overdrive
+}
+
+// threadedHedgeDownloadChunk allows the chunk to fetch another extra piece
+// every time the hedge delay of its overdrive policy passes without the chunk
+// completing. Standby workers are woken up to fetch the extra pieces.
+func (r *Renter) threadedHedgeDownloadChunk(udc *unfinishedDownloadChunk) {
+	if err := r.tg.Add(); err != nil {
+		return
+	}
+	defer r.tg.Done()
+	policy := udc.staticOverdrivePolicy
+	for {
+		select {
+		case <-r.tg.StopChan():
+			return
+		case <-udc.download.completeChan:
+			return
+		case <-time.After(policy.HedgeDelay):
+		}
+
+		udc.mu.Lock()
+		done := udc.failed || udc.piecesCompleted >= udc.erasureCode.MinPieces()
+		if !done {
+			udc.hedges++
+		}
+		maxed := udc.overdrive() >= policy.MaxExtraPieces
+		udc.mu.Unlock()
+		if done {
+			return
+		}
+		udc.managedCleanUp()
+		if maxed {
+			return
+		}
+	}
+}
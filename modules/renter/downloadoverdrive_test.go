@@ -0,0 +1,65 @@
+package renter
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestDownloadOverdriveStats is a unit test for the latency percentiles and
+// bounds of the download overdrive stats.
+func TestDownloadOverdriveStats(t *testing.T) {
+	t.Parallel()
+
+	dos := newDownloadOverdriveStats()
+	if status := dos.callStatus(modules.DownloadClassDownload); status.Chunks != 0 {
+		t.Fatal("expected empty status", status)
+	}
+
+	// Add 100 chunks with latencies from 1ms to 100ms.
+	for i := 100; i > 0; i-- {
+		dos.callAddChunk(modules.DownloadClassDownload, time.Duration(i)*time.Millisecond, 1)
+	}
+	status := dos.callStatus(modules.DownloadClassDownload)
+	if status.Chunks != 100 || status.OverdrivePieces != 100 {
+		t.Fatal("wrong number of chunks or pieces", status.Chunks, status.OverdrivePieces)
+	}
+	if status.LatencyP50 != 50*time.Millisecond || status.LatencyP99 != 99*time.Millisecond {
+		t.Fatal("wrong percentiles", status.LatencyP50, status.LatencyP99)
+	}
+
+	// Other classes are unaffected.
+	if status := dos.callStatus(modules.DownloadClassStream); status.Chunks != 0 {
+		t.Fatal("expected empty status", status)
+	}
+
+	// Only the most recent chunks are kept.
+	for i := 0; i < maxOverdriveStatsChunks; i++ {
+		dos.callAddChunk(modules.DownloadClassDownload, time.Second, 0)
+	}
+	status = dos.callStatus(modules.DownloadClassDownload)
+	if status.Chunks != maxOverdriveStatsChunks || status.OverdrivePieces != 0 || status.LatencyP50 != time.Second {
+		t.Fatal("old chunks weren't dropped", status)
+	}
+}
+
+// TestUnfinishedDownloadChunkOverdrive checks that hedges increase the
+// overdrive of a chunk up to the maximum of its policy.
+func TestUnfinishedDownloadChunkOverdrive(t *testing.T) {
+	t.Parallel()
+
+	udc := &unfinishedDownloadChunk{
+		staticOverdrivePolicy: modules.DownloadOverdrivePolicy{
+			BaseWorkers:    1,
+			HedgeDelay:     time.Millisecond,
+			MaxExtraPieces: 3,
+		},
+	}
+	for hedges, expected := range []int{1, 2, 3, 3} {
+		udc.hedges = hedges
+		if od := udc.overdrive(); od != expected {
+			t.Fatalf("expected overdrive %v after %v hedges but got %v", expected, hedges, od)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package renter
+
+import (
+	"sync"
+	"time"
+)
+
+// downloadRateLimit paces the distribution of a download's chunks to the
+// workers so that the download fetches no more than its max speed from hosts
+// on average. Chunks of a download that is ahead of its rate limit stay in the
+// download heap and don't hold up the chunks of other downloads, which allows
+// a bulk download to be capped without slowing down interactive downloads.
+//
+// The global bandwidth limits of the renter still apply to capped downloads.
+type downloadRateLimit struct {
+	// next is the earliest time at which the next chunk of the download may
+	// be distributed.
+	next time.Time
+
+	staticMaxSpeed uint64 // in bytes per second
+	mu             sync.Mutex
+}
+
+// newDownloadRateLimit creates a rate limit for a download. A maxSpeed of 0
+// means the download is not limited, in which case nil is returned.
+func newDownloadRateLimit(maxSpeed uint64) *downloadRateLimit {
+	if maxSpeed == 0 {
+		return nil
+	}
+	return &downloadRateLimit{
+		staticMaxSpeed: maxSpeed,
+	}
+}
+
+// callMaxSpeed returns the max speed of the rate limit or 0 if the rate limit
+// is nil.
+func (drl *downloadRateLimit) callMaxSpeed() uint64 {
+	if drl == nil {
+		return 0
+	}
+	return drl.staticMaxSpeed
+}
+
+// callReserve consumes the time it takes to fetch n bytes at the max speed of
+// the rate limit. The reservation starts at 'now' if the download was idle and
+// right after the previous reservation otherwise.
+func (drl *downloadRateLimit) callReserve(n uint64, now time.Time) {
+	if drl == nil {
+		return
+	}
+	drl.mu.Lock()
+	defer drl.mu.Unlock()
+	if drl.next.Before(now) {
+		drl.next = now
+	}
+	drl.next = drl.next.Add(time.Duration(float64(n) / float64(drl.staticMaxSpeed) * float64(time.Second)))
+}
+
+// callWait returns how long the next chunk of the download has to wait before
+// it can be distributed. 0 is returned if the chunk can be distributed
+// immediately.
+func (drl *downloadRateLimit) callWait(now time.Time) time.Duration {
+	if drl == nil {
+		return 0
+	}
+	drl.mu.Lock()
+	defer drl.mu.Unlock()
+	if !drl.next.After(now) {
+		return 0
+	}
+	return drl.next.Sub(now)
+}
+
+// staticFetchBytes returns the number of bytes the chunk fetches from hosts to
+// recover its data, not counting overdrive pieces.
+func (udc *unfinishedDownloadChunk) staticFetchBytes() uint64 {
+	_, pieceLength := sectorOffsetAndLength(udc.staticFetchOffset, udc.staticFetchLength, udc.erasureCode)
+	return pieceLength * uint64(udc.erasureCode.MinPieces())
+}
@@ -0,0 +1,94 @@
+package renter
+
+import (
+	"container/heap"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestDownloadRateLimit is a unit test for the pacing of a download's chunks.
+func TestDownloadRateLimit(t *testing.T) {
+	t.Parallel()
+
+	// A nil rate limit never waits.
+	var drl *downloadRateLimit
+	now := time.Now()
+	drl.callReserve(1<<30, now)
+	if drl.callWait(now) != 0 || drl.callMaxSpeed() != 0 {
+		t.Fatal("nil rate limit shouldn't limit the download")
+	}
+	if newDownloadRateLimit(0) != nil {
+		t.Fatal("rate limit without max speed should be nil")
+	}
+
+	// Reservations are queued back to back.
+	drl = newDownloadRateLimit(1 << 20)
+	if drl.callWait(now) != 0 {
+		t.Fatal("idle download shouldn't wait")
+	}
+	drl.callReserve(1<<20, now)
+	drl.callReserve(1<<19, now)
+	if wait := drl.callWait(now); wait != 1500*time.Millisecond {
+		t.Fatal("wrong wait", wait)
+	}
+	if wait := drl.callWait(now.Add(2 * time.Second)); wait != 0 {
+		t.Fatal("download should be able to continue", wait)
+	}
+
+	// A download that was idle doesn't accumulate a burst.
+	later := now.Add(time.Minute)
+	drl.callReserve(1<<20, later)
+	if wait := drl.callWait(later); wait != time.Second {
+		t.Fatal("wrong wait after idling", wait)
+	}
+}
+
+// TestNextDownloadChunkRateLimit checks that chunks of downloads that are
+// ahead of their rate limit don't block the chunks of other downloads.
+func TestNextDownloadChunkRateLimit(t *testing.T) {
+	t.Parallel()
+
+	r := &Renter{downloadHeap: new(downloadChunkHeap)}
+	now := time.Now()
+	capped := &download{
+		completeChan:    make(chan struct{}),
+		staticStartTime: now,
+		staticRateLimit: newDownloadRateLimit(1 << 20),
+	}
+	uncapped := &download{
+		completeChan:    make(chan struct{}),
+		staticStartTime: now.Add(time.Second),
+	}
+	newChunk := func(d *download, index uint64) *unfinishedDownloadChunk {
+		return &unfinishedDownloadChunk{
+			download:          d,
+			erasureCode:       modules.NewRSSubCodeDefault(),
+			staticChunkIndex:  index,
+			staticFetchLength: modules.SectorSize,
+		}
+	}
+	for i := uint64(0); i < 3; i++ {
+		heap.Push(r.downloadHeap, newChunk(capped, i))
+	}
+	heap.Push(r.downloadHeap, newChunk(uncapped, 0))
+
+	// The first chunk of the capped download is distributed, the next one
+	// has to wait for the chunk to be fetched.
+	udc, _ := r.managedNextDownloadChunk()
+	if udc == nil || udc.download != capped || udc.staticChunkIndex != 0 {
+		t.Fatal("expected first chunk of capped download", udc)
+	}
+	udc, _ = r.managedNextDownloadChunk()
+	if udc == nil || udc.download != uncapped {
+		t.Fatal("expected chunk of uncapped download", udc)
+	}
+	udc, wait := r.managedNextDownloadChunk()
+	if udc != nil || wait <= 0 || wait > 2*time.Duration(modules.SectorSize)*time.Second/(1<<20) {
+		t.Fatal("expected capped download to wait", udc, wait)
+	}
+	if r.downloadHeap.Len() != 2 {
+		t.Fatal("throttled chunks weren't returned to the heap", r.downloadHeap.Len())
+	}
+}
@@ -2,6 +2,7 @@ package renter
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"sync"
 	"time"
@@ -29,6 +30,14 @@ type (
 		offset     int64
 		r          *Renter
 
+		// The streamer only serves the range of the file between
+		// staticRangeStart and staticRangeEnd. Offsets of the streamer are
+		// relative to the start of the range, the offsets used internally are
+		// relative to the start of the file. Chunks outside of the range are
+		// never fetched.
+		staticRangeStart int64
+		staticRangeEnd   int64
+
 		// The cache itself is a []byte that is managed by threadedFillCache. The
 		// 'cacheOffset' indicates the starting location of the cache within the
 		// file, and all of the data in the []byte will be the actual file data
@@ -82,16 +91,16 @@ func (s *streamer) managedFillCache() bool {
 	streamOffset := s.offset
 	cacheLen := int64(len(s.cache))
 	streamReadErr := s.readErr
-	fileSize := int64(s.staticFile.Size())
+	rangeEnd := s.staticRangeEnd
 	targetCacheSize := s.targetCacheSize
 	s.mu.Unlock()
 	// If there has been a read error in the stream, abort.
 	if streamReadErr != nil {
 		return false
 	}
-	// Check whether the cache has reached the end of the file and also the
+	// Check whether the cache has reached the end of the range and also the
 	// streamOffset is contained within the cache. If so, no updates are needed.
-	if cacheOffset <= streamOffset && cacheOffset+cacheLen == fileSize {
+	if cacheOffset <= streamOffset && cacheOffset+cacheLen == rangeEnd {
 		return false
 	}
 	// If partial downloads are supported and more than half of the target cache
@@ -160,10 +169,84 @@ func (s *streamer) managedFillCache() bool {
 	}
 
 	// Finally, check if the fetchOffset and fetchLen goes beyond the boundaries
-	// of the file. If so, the fetchLen will be truncated so that the cache only
-	// goes up to the end of the file.
-	if fetchOffset+fetchLen > fileSize {
-		fetchLen = fileSize - fetchOffset
+	// of the range. If so, the fetchLen will be truncated so that the cache
+	// only goes up to the end of the range.
+	if fetchOffset+fetchLen > rangeEnd {
+		fetchLen = rangeEnd - fetchOffset
+	}
+
+	// Serve the data from the renter's chunk cache if possible. Otherwise the
+	// data is downloaded and the fetched chunks are added to the chunk cache.
+	data, cached := s.r.managedReadCachedRange(s.staticFile, uint64(fetchOffset), uint64(fetchLen))
+	if !cached {
+		var ok bool
+		data, ok = s.managedDownloadRange(fetchOffset, fetchLen)
+		if !ok {
+			return false
+		}
+	}
+
+	// Update the cache.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Before updating the cache, check if the stream has caught up in the
+	// current cache. If the stream has caught up, the cache is not filling fast
+	// enough and the target cache size should be increased.
+	//
+	// streamOffsetInTail checks if the stream offset is in the final quarter of
+	// the cache. If it is, we consider the cache to be not filling fast enough,
+	// and we extend the size of the cache.
+	//
+	// A final check for cacheExists is performed, because if there currently is
+	// no cache at all, this must be the first fetch, and there is no reason to
+	// extend the cache size.
+	cacheLen = int64(len(s.cache))
+	streamOffsetInCache := s.cacheOffset <= s.offset && s.offset <= s.cacheOffset+cacheLen // NOTE: it's '<=' so that we also count being 1 byte beyond the cache
+	streamOffsetInTail := streamOffsetInCache && s.offset >= s.cacheOffset+(cacheLen/4)+(cacheLen/2)
+	targetCacheUnderLimit := s.targetCacheSize < maxStreamerCacheSize
+	cacheExists := cacheLen > 0
+	if cacheExists && partialDownloadsSupported && targetCacheUnderLimit && streamOffsetInTail {
+		if s.targetCacheSize*2 > maxStreamerCacheSize {
+			s.targetCacheSize = maxStreamerCacheSize
+		} else {
+			s.targetCacheSize *= 2
+		}
+	}
+
+	// Update the cache based on whether the entire cache needs to be replaced
+	// or whether only some of the cache is being replaced. The whole cache
+	// needs to be replaced in the even that partial downloads are not
+	// supported, and also in the event that the stream offset is complete
+	// outside the previous cache.
+	if !partialDownloadsSupported || streamOffset >= cacheOffset+cacheLen || streamOffset < cacheOffset {
+		s.cache = data
+		s.cacheOffset = fetchOffset
+	} else {
+		s.cache = s.cache[streamOffset-cacheOffset:]
+		s.cache = append(s.cache, data...)
+		s.cacheOffset = streamOffset
+	}
+
+	// Return true, indicating that this function should be called again,
+	// because there may be more cache that has been requested or used since the
+	// previous request.
+	return true
+}
+
+// managedDownloadRange downloads the provided range of the streamer's file. If
+// the renter's chunk cache is enabled, the download is extended to full chunks
+// which are added to the cache. Errors are recorded in the streamer's readErr.
+func (s *streamer) managedDownloadRange(fetchOffset, fetchLen int64) ([]byte, bool) {
+	downloadOffset, downloadLen := fetchOffset, fetchLen
+	if s.r.staticChunkCache.callEnabled() {
+		chunkSize := int64(s.staticFile.ChunkSize())
+		downloadOffset = fetchOffset / chunkSize * chunkSize
+		downloadEnd := (fetchOffset + fetchLen + chunkSize - 1) / chunkSize * chunkSize
+		if fileSize := int64(s.staticFile.Size()); downloadEnd > fileSize {
+			downloadEnd = fileSize
+		}
+		downloadLen = downloadEnd - downloadOffset
 	}
 
 	// Perform the actual download.
@@ -177,12 +260,14 @@ func (s *streamer) managedFillCache() bool {
 		file:              s.staticFile,
 
 		latencyTarget: 50 * time.Millisecond, // TODO: low default until full latency support is added.
-		length:        uint64(fetchLen),
+		length:        uint64(downloadLen),
 		needsMemory:   true,
-		offset:        uint64(fetchOffset),
-		overdrive:     5,    // TODO: high default until full overdrive support is added.
+		offset:        uint64(downloadOffset),
 		priority:      1000, // TODO: high default until full priority support is added.
 
+		overdrivePolicy: s.r.managedDownloadOverdrivePolicy(modules.DownloadClassStream),
+		requestClass:    modules.DownloadClassStream,
+
 		staticMemoryManager:    s.r.userDownloadMemoryManager, // user initiated download
 		staticSpendingCategory: categoryDownload,
 	})
@@ -193,7 +278,7 @@ func (s *streamer) managedFillCache() bool {
 		s.readErr = readErr
 		s.mu.Unlock()
 		s.r.log.Println("Error downloading for stream file:", readErr)
-		return false
+		return nil, false
 	}
 	// Register some cleanup for when the download is done.
 	d.OnComplete(func(_ error) error {
@@ -202,7 +287,7 @@ func (s *streamer) managedFillCache() bool {
 	})
 	// Start the download.
 	if err := d.Start(); err != nil {
-		return false
+		return nil, false
 	}
 	// Block until the download has completed.
 	select {
@@ -215,7 +300,7 @@ func (s *streamer) managedFillCache() bool {
 			s.readErr = readErr
 			s.mu.Unlock()
 			s.r.log.Println("Error during stream download:", readErr)
-			return false
+			return nil, false
 		}
 	case <-s.r.tg.StopChan():
 		stopErr := errors.New("download interrupted by shutdown")
@@ -224,55 +309,15 @@ func (s *streamer) managedFillCache() bool {
 		s.readErr = readErr
 		s.mu.Unlock()
 		s.r.log.Debugln(stopErr)
-		return false
+		return nil, false
 	}
 
-	// Update the cache.
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	// Before updating the cache, check if the stream has caught up in the
-	// current cache. If the stream has caught up, the cache is not filling fast
-	// enough and the target cache size should be increased.
-	//
-	// streamOffsetInTail checks if the stream offset is in the final quarter of
-	// the cache. If it is, we consider the cache to be not filling fast enough,
-	// and we extend the size of the cache.
-	//
-	// A final check for cacheExists is performed, because if there currently is
-	// no cache at all, this must be the first fetch, and there is no reason to
-	// extend the cache size.
-	cacheLen = int64(len(s.cache))
-	streamOffsetInCache := s.cacheOffset <= s.offset && s.offset <= s.cacheOffset+cacheLen // NOTE: it's '<=' so that we also count being 1 byte beyond the cache
-	streamOffsetInTail := streamOffsetInCache && s.offset >= s.cacheOffset+(cacheLen/4)+(cacheLen/2)
-	targetCacheUnderLimit := s.targetCacheSize < maxStreamerCacheSize
-	cacheExists := cacheLen > 0
-	if cacheExists && partialDownloadsSupported && targetCacheUnderLimit && streamOffsetInTail {
-		if s.targetCacheSize*2 > maxStreamerCacheSize {
-			s.targetCacheSize = maxStreamerCacheSize
-		} else {
-			s.targetCacheSize *= 2
-		}
-	}
-
-	// Update the cache based on whether the entire cache needs to be replaced
-	// or whether only some of the cache is being replaced. The whole cache
-	// needs to be replaced in the even that partial downloads are not
-	// supported, and also in the event that the stream offset is complete
-	// outside the previous cache.
-	if !partialDownloadsSupported || streamOffset >= cacheOffset+cacheLen || streamOffset < cacheOffset {
-		s.cache = buffer.Bytes()
-		s.cacheOffset = fetchOffset
-	} else {
-		s.cache = s.cache[streamOffset-cacheOffset:]
-		s.cache = append(s.cache, buffer.Bytes()...)
-		s.cacheOffset = streamOffset
+	data := buffer.Bytes()
+	if s.r.staticChunkCache.callEnabled() {
+		s.r.managedCacheRange(s.staticFile, uint64(downloadOffset), data)
 	}
-
-	// Return true, indicating that this function should be called again,
-	// because there may be more cache that has been requested or used since the
-	// previous request.
-	return true
+	start := fetchOffset - downloadOffset
+	return data[start : start+fetchLen : start+fetchLen], true
 }
 
 // threadedFillCache is a background thread that keeps the cache full as data is
@@ -337,9 +382,8 @@ func (s *streamer) Read(p []byte) (int, error) {
 		// error, we will drop the lock and spin up a thread to fill the cache,
 		// and then block until the cache has been updated.
 		s.mu.Lock()
-		// Get the file's size and check for EOF.
-		fileSize := int64(s.staticFile.Size())
-		if s.offset >= fileSize {
+		// Check for EOF.
+		if s.offset >= s.staticRangeEnd {
 			s.mu.Unlock()
 			return 0, io.EOF
 		}
@@ -421,10 +465,10 @@ func (s *streamer) Read(p []byte) (int, error) {
 }
 
 // Seek sets the offset for the next Read to offset, interpreted
-// according to whence: SeekStart means relative to the start of the file,
-// SeekCurrent means relative to the current offset, and SeekEnd means relative
-// to the end. Seek returns the new offset relative to the start of the file
-// and an error, if any.
+// according to whence: SeekStart means relative to the start of the streamed
+// range, SeekCurrent means relative to the current offset, and SeekEnd means
+// relative to the end of the range. Seek returns the new offset relative to
+// the start of the range and an error, if any.
 func (s *streamer) Seek(offset int64, whence int) (int64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -432,19 +476,19 @@ func (s *streamer) Seek(offset int64, whence int) (int64, error) {
 	var newOffset int64
 	switch whence {
 	case io.SeekStart:
-		newOffset = 0
+		newOffset = s.staticRangeStart
 	case io.SeekCurrent:
 		newOffset = s.offset
 	case io.SeekEnd:
-		newOffset = int64(s.staticFile.Size())
+		newOffset = s.staticRangeEnd
 	}
 	newOffset += offset
-	if newOffset < 0 {
-		return s.offset, errors.New("cannot seek to negative offset")
+	if newOffset < s.staticRangeStart {
+		return s.offset - s.staticRangeStart, errors.New("cannot seek to negative offset")
 	}
 	// If the Seek is a no-op, do not invalidate the cache.
 	if newOffset == s.offset {
-		return 0, nil
+		return newOffset - s.staticRangeStart, nil
 	}
 
 	// Reset the target cache size upon seek to be the default again. This is in
@@ -467,7 +511,7 @@ func (s *streamer) Seek(offset int64, whence int) (int64, error) {
 	default:
 	}
 
-	return newOffset, nil
+	return newOffset - s.staticRangeStart, nil
 }
 
 // Streamer creates a modules.Streamer that can be used to stream downloads from
@@ -496,6 +540,45 @@ func (r *Renter) Streamer(siaPath modules.SiaPath, disableLocalFetch bool) (_ st
 	return siaPath.String(), s, nil
 }
 
+// StreamerRange creates a modules.Streamer that only streams the length bytes
+// of the file starting at offset. Only the chunks covering the range are
+// downloaded. A length of 0 streams the file from offset to its end.
+func (r *Renter) StreamerRange(siaPath modules.SiaPath, offset, length uint64, disableLocalFetch bool) (_ string, _ modules.Streamer, err error) {
+	if err := r.tg.Add(); err != nil {
+		return "", nil, err
+	}
+	defer r.tg.Done()
+
+	// Lookup the file associated with the nickname.
+	node, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		err = errors.Compose(err, node.Close())
+	}()
+
+	// Validate the range.
+	size := node.Size()
+	if offset > size {
+		return "", nil, errors.New("offset cannot be greater than file size")
+	}
+	if length == 0 {
+		length = size - offset
+	}
+	if offset+length > size {
+		return "", nil, fmt.Errorf("offset and length combination invalid, max byte is at index %d", size-1)
+	}
+
+	// Create the streamer from a snapshot of the chunks within the range.
+	snap, err := node.SnapshotRange(siaPath, offset, length)
+	if err != nil {
+		return "", nil, err
+	}
+	s := r.managedRangeStreamer(snap, offset, length, disableLocalFetch)
+	return siaPath.String(), s, nil
+}
+
 // StreamerByNode will open a streamer for the renter, taking a FileNode as
 // input instead of a siapath. This is important for fuse, which has filenodes
 // that could be getting renamed before the streams are opened.
@@ -518,10 +601,20 @@ func (r *Renter) StreamerByNode(node *filesystem.FileNode, disableLocalFetch boo
 // managedStreamer creates a streamer from a siafile snapshot and starts filling
 // its cache.
 func (r *Renter) managedStreamer(snapshot *siafile.Snapshot, disableLocalFetch bool) modules.Streamer {
+	return r.managedRangeStreamer(snapshot, 0, snapshot.Size(), disableLocalFetch)
+}
+
+// managedRangeStreamer creates a streamer for a range of a siafile snapshot
+// and starts filling its cache from the start of the range.
+func (r *Renter) managedRangeStreamer(snapshot *siafile.Snapshot, offset, length uint64, disableLocalFetch bool) modules.Streamer {
 	s := &streamer{
 		staticFile: snapshot,
+		offset:     int64(offset),
 		r:          r,
 
+		staticRangeStart: int64(offset),
+		staticRangeEnd:   int64(offset + length),
+
 		activateCache:           make(chan struct{}),
 		cacheReady:              make(chan struct{}),
 		staticDisableLocalFetch: disableLocalFetch,
@@ -0,0 +1,37 @@
+package renter
+
+import (
+	"io"
+	"testing"
+)
+
+// TestStreamerRangeSeek checks that the offsets of a range streamer are
+// relative to the start of its range.
+func TestStreamerRangeSeek(t *testing.T) {
+	t.Parallel()
+
+	s := &streamer{
+		offset:           100,
+		activateCache:    make(chan struct{}),
+		staticRangeStart: 100,
+		staticRangeEnd:   300,
+	}
+	if off, err := s.Seek(0, io.SeekEnd); err != nil || off != 200 || s.offset != 300 {
+		t.Fatal("wrong offset after seeking to the end", off, s.offset, err)
+	}
+	if off, err := s.Seek(0, io.SeekEnd); err != nil || off != 200 {
+		t.Fatal("no-op seek returned wrong offset", off, err)
+	}
+	if _, err := s.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatal("expected EOF at the end of the range", err)
+	}
+	if off, err := s.Seek(10, io.SeekStart); err != nil || off != 10 || s.offset != 110 {
+		t.Fatal("wrong offset after seeking from the start", off, s.offset, err)
+	}
+	if off, err := s.Seek(-5, io.SeekCurrent); err != nil || off != 5 || s.offset != 105 {
+		t.Fatal("wrong offset after seeking from the current offset", off, s.offset, err)
+	}
+	if _, err := s.Seek(-1, io.SeekStart); err == nil {
+		t.Fatal("shouldn't be able to seek before the start of the range")
+	}
+}
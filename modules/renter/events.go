@@ -0,0 +1,178 @@
+package renter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/threadgroup"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem/siafile"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// maxRenterEvents is the number of events the renter keeps for clients
+	// of the event stream. Older events are dropped.
+	maxRenterEvents = 1000
+)
+
+type (
+	// renterEvent is an event of the event stream together with the UID of
+	// the file it relates to.
+	renterEvent struct {
+		event     modules.RenterEvent
+		staticUID siafile.SiafileUID
+	}
+
+	// renterEvents is the renter's event stream. Clients long-poll for
+	// events that occurred after the last event they have seen.
+	renterEvents struct {
+		events []renterEvent
+		nextID uint64
+
+		// knownContracts contains the contracts that were seen by the last
+		// update to detect newly formed contracts.
+		knownContracts map[types.FileContractID]struct{}
+
+		// newEvents is closed and replaced whenever an event is added.
+		newEvents chan struct{}
+
+		mu sync.Mutex
+	}
+)
+
+// newRenterEvents creates a new, empty event stream.
+func newRenterEvents() *renterEvents {
+	return &renterEvents{
+		nextID:    1,
+		newEvents: make(chan struct{}),
+	}
+}
+
+// add adds an event to the stream and wakes up the waiting clients.
+func (re *renterEvents) add(e modules.RenterEvent, uid siafile.SiafileUID) {
+	e.ID = re.nextID
+	e.Time = time.Now()
+	re.nextID++
+	re.events = append(re.events, renterEvent{event: e, staticUID: uid})
+	if len(re.events) > maxRenterEvents {
+		re.events = append([]renterEvent{}, re.events[len(re.events)-maxRenterEvents:]...)
+	}
+	close(re.newEvents)
+	re.newEvents = make(chan struct{})
+}
+
+// callAdd adds an event to the stream.
+func (re *renterEvents) callAdd(e modules.RenterEvent) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	re.add(e, "")
+}
+
+// callAddFileEvent adds an event related to a file to the stream. Some events
+// are only added once per file. Chunks that finish uploading concurrently
+// might otherwise report the completion of the same upload multiple times.
+func (re *renterEvents) callAddFileEvent(e modules.RenterEvent, uid siafile.SiafileUID) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	if e.Type == modules.RenterEventUploadComplete {
+		for _, existing := range re.events {
+			if existing.staticUID == uid && existing.event.Type == e.Type {
+				return
+			}
+		}
+	}
+	re.add(e, uid)
+}
+
+// callEvents returns the events that occurred after the event with the
+// provided ID. If the ID is from before the last restart, all events are
+// returned. The returned channel is closed when the next event is added.
+func (re *renterEvents) callEvents(since uint64) ([]modules.RenterEvent, <-chan struct{}) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	if since >= re.nextID {
+		since = 0
+	}
+	var events []modules.RenterEvent
+	for _, e := range re.events {
+		if e.event.ID > since {
+			events = append(events, e.event)
+		}
+	}
+	return events, re.newEvents
+}
+
+// callUpdateContracts adds an event for every contract that wasn't known
+// during the previous update. The first update only initializes the known
+// contracts.
+func (re *renterEvents) callUpdateContracts(contracts []modules.RenterContract) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	initialized := re.knownContracts != nil
+	known := make(map[types.FileContractID]struct{}, len(contracts))
+	for _, c := range contracts {
+		known[c.ID] = struct{}{}
+		if _, exists := re.knownContracts[c.ID]; exists || !initialized {
+			continue
+		}
+		re.add(modules.RenterEvent{
+			Type:          modules.RenterEventContractFormed,
+			ContractID:    c.ID,
+			HostPublicKey: c.HostPublicKey,
+		}, "")
+	}
+	re.knownContracts = known
+}
+
+// Events returns the events of the renter's event stream that occurred after
+// the event with the provided ID. If there are no such events yet, Events
+// blocks until the next event occurs or the context is done, in which case no
+// events are returned.
+func (r *Renter) Events(ctx context.Context, since uint64) ([]modules.RenterEvent, error) {
+	if err := r.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer r.tg.Done()
+	for {
+		events, newEvents := r.staticEvents.callEvents(since)
+		if len(events) > 0 {
+			return events, nil
+		}
+		select {
+		case <-newEvents:
+		case <-ctx.Done():
+			return nil, nil
+		case <-r.tg.StopChan():
+			return nil, threadgroup.ErrStopped
+		}
+	}
+}
+
+// managedEmitUploadChunkEvents adds the events of a chunk that finished
+// uploading to the event stream. Chunks that already had uploaded pieces are
+// repairs, all other chunks belong to the initial upload of the file.
+func (r *Renter) managedEmitUploadChunkEvents(uc *unfinishedUploadChunk, successful bool) {
+	siaPath := r.staticFileSystem.FileSiaPath(uc.fileEntry)
+	if uc.staticRepair {
+		if successful {
+			r.staticEvents.callAddFileEvent(modules.RenterEvent{
+				Type:       modules.RenterEventChunkRepaired,
+				SiaPath:    siaPath,
+				ChunkIndex: uc.staticIndex,
+			}, uc.fileEntry.UID())
+		}
+		return
+	}
+	md := uc.fileEntry.Metadata()
+	if md.CachedUploadProgress < 100 {
+		return
+	}
+	r.staticEvents.callAddFileEvent(modules.RenterEvent{
+		Type:    modules.RenterEventUploadComplete,
+		SiaPath: siaPath,
+		Health:  md.CachedHealth,
+	}, uc.fileEntry.UID())
+}
@@ -0,0 +1,107 @@
+package renter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestRenterEvents is a unit test for the renterEvents object.
+func TestRenterEvents(t *testing.T) {
+	t.Parallel()
+
+	re := newRenterEvents()
+	events, newEvents := re.callEvents(0)
+	if len(events) != 0 {
+		t.Fatal("stream should be empty")
+	}
+
+	// Adding an event wakes up waiting clients.
+	re.callAdd(modules.RenterEvent{Type: modules.RenterEventDownloadComplete})
+	select {
+	case <-newEvents:
+	default:
+		t.Fatal("waiting clients weren't notified")
+	}
+	events, _ = re.callEvents(0)
+	if len(events) != 1 || events[0].ID != 1 || events[0].Time.IsZero() {
+		t.Fatal("wrong events", events)
+	}
+
+	// An upload is only reported as complete once per file.
+	e := modules.RenterEvent{Type: modules.RenterEventUploadComplete}
+	re.callAddFileEvent(e, "uid1")
+	re.callAddFileEvent(e, "uid1")
+	re.callAddFileEvent(e, "uid2")
+	re.callAddFileEvent(modules.RenterEvent{Type: modules.RenterEventChunkRepaired}, "uid1")
+	re.callAddFileEvent(modules.RenterEvent{Type: modules.RenterEventChunkRepaired}, "uid1")
+	events, _ = re.callEvents(1)
+	if len(events) != 4 || events[0].ID != 2 || events[3].ID != 5 {
+		t.Fatal("wrong events", events)
+	}
+
+	// IDs from before a restart return all events.
+	events, _ = re.callEvents(100)
+	if len(events) != 5 {
+		t.Fatal("wrong number of events", len(events))
+	}
+
+	// The first contract update only initializes the known contracts.
+	contracts := []modules.RenterContract{{ID: types.FileContractID{1}}}
+	re.callUpdateContracts(contracts)
+	re.callUpdateContracts(contracts)
+	events, _ = re.callEvents(5)
+	if len(events) != 0 {
+		t.Fatal("known contracts shouldn't be reported", events)
+	}
+	contracts = append(contracts, modules.RenterContract{ID: types.FileContractID{2}})
+	re.callUpdateContracts(contracts)
+	events, _ = re.callEvents(5)
+	if len(events) != 1 || events[0].Type != modules.RenterEventContractFormed || events[0].ContractID != contracts[1].ID {
+		t.Fatal("new contract wasn't reported", events)
+	}
+
+	// Old events are dropped.
+	for i := 0; i < maxRenterEvents; i++ {
+		re.callAdd(modules.RenterEvent{Type: modules.RenterEventDownloadComplete})
+	}
+	events, _ = re.callEvents(0)
+	if len(events) != maxRenterEvents || events[0].ID != 7 {
+		t.Fatal("old events weren't dropped", len(events), events[0].ID)
+	}
+}
+
+// TestRenterEventsLongPoll probes the blocking behavior of Renter.Events.
+func TestRenterEventsLongPoll(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	r := &Renter{staticEvents: newRenterEvents()}
+
+	// Without events, the call returns once the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	events, err := r.Events(ctx, 0)
+	if err != nil || len(events) != 0 {
+		t.Fatal("expected no events", events, err)
+	}
+
+	// An event that is added while waiting is returned.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		r.staticEvents.callAdd(modules.RenterEvent{Type: modules.RenterEventDownloadComplete})
+	}()
+	events, err = r.Events(context.Background(), 0)
+	if err != nil || len(events) != 1 {
+		t.Fatal("expected one event", events, err)
+	}
+	events, err = r.Events(ctx, events[0].ID)
+	if err != nil || len(events) != 0 {
+		t.Fatal("expected no new events", events, err)
+	}
+}
@@ -0,0 +1,98 @@
+package renter
+
+import (
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem/siafile"
+)
+
+var (
+	// erasureCodeNames are the names of the erasure codes in the FileKeys
+	// export.
+	erasureCodeNames = map[modules.ErasureCoderType]string{
+		modules.ECReedSolomon:            "reedsolomon",
+		modules.ECReedSolomonSubShards64: "reedsolomon-subshards64",
+		modules.ECPassthrough:            "passthrough",
+	}
+
+	// errFileKeysPartialChunks is returned when exporting the keys of a file
+	// whose last chunk is combined with the chunks of other files.
+	errFileKeysPartialChunks = errors.New("exporting the keys of files with partial chunks is not supported")
+)
+
+// FileKeys returns the encryption keys and the erasure coding layout of a
+// file.
+func (r *Renter) FileKeys(siaPath modules.SiaPath) (_ modules.FileKeys, err error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.FileKeys{}, err
+	}
+	defer r.tg.Done()
+
+	node, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return modules.FileKeys{}, err
+	}
+	defer func() {
+		err = errors.Compose(err, node.Close())
+	}()
+	snap, err := node.Snapshot(siaPath)
+	if err != nil {
+		return modules.FileKeys{}, err
+	}
+	return exportFileKeys(snap)
+}
+
+// exportFileKeys creates the FileKeys export of a siafile snapshot.
+func exportFileKeys(snap *siafile.Snapshot) (modules.FileKeys, error) {
+	if len(snap.PartialChunks()) > 0 {
+		return modules.FileKeys{}, errFileKeysPartialChunks
+	}
+	ec := snap.ErasureCode()
+	ecName, known := erasureCodeNames[ec.Type()]
+	if !known {
+		return modules.FileKeys{}, errors.New("unknown erasure code type")
+	}
+	segmentSize, _ := ec.SupportsPartialEncoding()
+	mk := snap.MasterKey()
+
+	fk := modules.FileKeys{
+		Version:    modules.FileKeysVersion,
+		SiaPath:    snap.SiaPath(),
+		FileSize:   snap.Size(),
+		ChunkSize:  snap.ChunkSize(),
+		PieceSize:  snap.PieceSize(),
+		CipherType: mk.Type().String(),
+		MasterKey:  mk.Key(),
+		ErasureCode: modules.FileKeysErasure{
+			Type:         ecName,
+			DataPieces:   ec.MinPieces(),
+			ParityPieces: ec.NumPieces() - ec.MinPieces(),
+			SegmentSize:  segmentSize,
+		},
+		Chunks: make([]modules.FileKeysChunk, 0, snap.NumChunks()),
+	}
+	for chunkIndex := uint64(0); chunkIndex < snap.NumChunks(); chunkIndex++ {
+		pieceSets := snap.Pieces(chunkIndex)
+		chunk := modules.FileKeysChunk{
+			Index:  chunkIndex,
+			Pieces: make([]modules.FileKeysPiece, 0, len(pieceSets)),
+		}
+		for pieceIndex, pieceSet := range pieceSets {
+			piece := modules.FileKeysPiece{
+				Index:   uint64(pieceIndex),
+				Key:     mk.Derive(chunkIndex, uint64(pieceIndex)).Key(),
+				Sectors: make([]modules.FileKeysSector, 0, len(pieceSet)),
+			}
+			for _, p := range pieceSet {
+				piece.Sectors = append(piece.Sectors, modules.FileKeysSector{
+					HostPublicKey: p.HostPubKey,
+					MerkleRoot:    p.MerkleRoot,
+				})
+			}
+			chunk.Pieces = append(chunk.Pieces, piece)
+		}
+		fk.Chunks = append(fk.Chunks, chunk)
+	}
+	return fk, nil
+}
@@ -0,0 +1,91 @@
+package renter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"gitlab.com/NebulousLabs/writeaheadlog"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem/siafile"
+	"go.sia.tech/siad/types"
+)
+
+// TestExportFileKeys checks that the exported keys and layout of a siafile can
+// be used to decrypt its pieces.
+func TestExportFileKeys(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir("renter", t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	_, wal, err := writeaheadlog.New(filepath.Join(dir, "wal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := modules.NewRSSubCode(2, 1, crypto.SegmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := crypto.GenerateSiaKey(crypto.TypeThreefish)
+	fileSize := 3 * 2 * modules.SectorSize / 2
+	sf, err := siafile.New(filepath.Join(dir, "file"+modules.SiaFileExtension), "", wal, rc, sk, fileSize, 0600, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hpk := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: fastrand.Bytes(32)}
+	root := crypto.Hash{1}
+	if err := sf.AddPiece(hpk, 1, 2, root); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := sf.Snapshot(modules.RandomSiaPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fk, err := exportFileKeys(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fk.Version != modules.FileKeysVersion || fk.FileSize != fileSize || fk.ChunkSize != snap.ChunkSize() || fk.PieceSize != modules.SectorSize {
+		t.Fatalf("wrong file layout %+v", fk)
+	}
+	if fk.ErasureCode != (modules.FileKeysErasure{Type: "reedsolomon-subshards64", DataPieces: 2, ParityPieces: 1, SegmentSize: crypto.SegmentSize}) {
+		t.Fatal("wrong erasure code", fk.ErasureCode)
+	}
+	if fk.CipherType != crypto.TypeThreefish.String() || !bytes.Equal(fk.MasterKey, sk.Key()) {
+		t.Fatal("wrong master key")
+	}
+	if uint64(len(fk.Chunks)) != snap.NumChunks() || len(fk.Chunks[1].Pieces) != rc.NumPieces() {
+		t.Fatal("wrong number of chunks or pieces")
+	}
+	piece := fk.Chunks[1].Pieces[2]
+	if len(piece.Sectors) != 1 || piece.Sectors[0].MerkleRoot != root || !piece.Sectors[0].HostPublicKey.Equals(hpk) {
+		t.Fatal("wrong sectors", piece.Sectors)
+	}
+
+	// The exported piece key decrypts data encrypted by the renter.
+	var ct crypto.CipherType
+	if err := ct.FromString(fk.CipherType); err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.NewSiaKey(ct, piece.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := fastrand.Bytes(int(crypto.SegmentSize))
+	ciphertext := sk.Derive(1, 2).EncryptBytes(data)
+	plaintext, err := key.DecryptBytes(ciphertext)
+	if err != nil || !bytes.Equal(plaintext, data) {
+		t.Fatal("exported key can't decrypt piece", err)
+	}
+}
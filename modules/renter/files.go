@@ -5,10 +5,21 @@ import (
 
 	"go.sia.tech/siad/build"
 	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
 
 	"gitlab.com/NebulousLabs/errors"
 )
 
+var (
+	// errDeleteDirNotRecursive is returned if a batch deletion contains a
+	// directory but wasn't requested to be recursive.
+	errDeleteDirNotRecursive = errors.New("siapath is a directory, recursive deletion required")
+
+	// errDeleteRootDir is returned if a batch deletion contains the root
+	// directory.
+	errDeleteRootDir = errors.New("the root directory can't be deleted")
+)
+
 // DeleteFile removes a file entry from the renter and deletes its data from
 // the hosts it is stored on.
 func (r *Renter) DeleteFile(siaPath modules.SiaPath) error {
@@ -23,6 +34,9 @@ func (r *Renter) DeleteFile(siaPath modules.SiaPath) error {
 	if err != nil {
 		return errors.AddContext(err, "unable to delete siafile from filesystem")
 	}
+	if err := r.managedThawDeletedPaths(siaPath); err != nil {
+		r.log.Printf("Unable to thaw deleted siafile %v: %v", siaPath, err)
+	}
 
 	// Update the filesystem metadata.
 	//
@@ -43,6 +57,95 @@ func (r *Renter) DeleteFile(siaPath modules.SiaPath) error {
 	return nil
 }
 
+// DeleteFiles deletes multiple files with a single call. If recursive is set,
+// siapaths of directories delete the directory and all of its contents. A
+// result is returned for every file that was deleted and for every siapath
+// that couldn't be deleted. Every affected directory is only bubbled once
+// after all deletions are done.
+func (r *Renter) DeleteFiles(siaPaths []modules.SiaPath, recursive bool) ([]modules.FileDeletionResult, error) {
+	if err := r.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer r.tg.Done()
+
+	results := make([]modules.FileDeletionResult, 0, len(siaPaths))
+	bubbleDirs := make(map[modules.SiaPath]struct{})
+	for _, siaPath := range siaPaths {
+		results = append(results, r.managedBatchDelete(siaPath, recursive, bubbleDirs)...)
+	}
+	for dir := range bubbleDirs {
+		_ = r.staticBubbleScheduler.callQueueBubble(dir)
+	}
+	return results, nil
+}
+
+// managedBatchDelete deletes a single siapath of a batch deletion. The parent
+// directory of the deleted siapath is added to bubbleDirs.
+func (r *Renter) managedBatchDelete(siaPath modules.SiaPath, recursive bool, bubbleDirs map[modules.SiaPath]struct{}) []modules.FileDeletionResult {
+	result := func(siaPath modules.SiaPath, err error) modules.FileDeletionResult {
+		res := modules.FileDeletionResult{SiaPath: siaPath}
+		if err != nil {
+			res.Error = err.Error()
+		}
+		return res
+	}
+	deleted := func() {
+		if err := r.managedUnpinDeletedDirs(siaPath); err != nil {
+			r.log.Printf("Unable to unpin deleted siapath %v: %v", siaPath, err)
+		}
+		if err := r.managedThawDeletedPaths(siaPath); err != nil {
+			r.log.Printf("Unable to thaw deleted siapath %v: %v", siaPath, err)
+		}
+		dir, err := siaPath.Dir()
+		if err != nil {
+			r.log.Printf("Unable to fetch the parent of deleted siapath %v: %v", siaPath, err)
+			return
+		}
+		bubbleDirs[dir] = struct{}{}
+	}
+	if siaPath.IsRoot() {
+		return []modules.FileDeletionResult{result(siaPath, errDeleteRootDir)}
+	}
+
+	// Try deleting the siapath as a file first.
+	err := r.staticFileSystem.DeleteFile(siaPath)
+	if err == nil {
+		deleted()
+		return []modules.FileDeletionResult{result(siaPath, nil)}
+	}
+	if !errors.Contains(err, filesystem.ErrNotExist) {
+		return []modules.FileDeletionResult{result(siaPath, err)}
+	}
+	if isDir, dirErr := r.staticFileSystem.DirExists(siaPath); dirErr != nil || !isDir {
+		return []modules.FileDeletionResult{result(siaPath, err)}
+	}
+	if !recursive {
+		return []modules.FileDeletionResult{result(siaPath, errDeleteDirNotRecursive)}
+	}
+
+	// Delete the files within the directory one by one to report them before
+	// removing what is left of the directory.
+	files, _, err := r.managedDirDeletionFiles(siaPath)
+	if err != nil {
+		return []modules.FileDeletionResult{result(siaPath, err)}
+	}
+	results := make([]modules.FileDeletionResult, 0, len(files))
+	for _, fi := range files {
+		err := r.staticFileSystem.DeleteFile(fi.SiaPath)
+		if errors.Contains(err, filesystem.ErrNotExist) {
+			// The file was deleted in the meantime.
+			continue
+		}
+		results = append(results, result(fi.SiaPath, err))
+	}
+	err = r.staticFileSystem.DeleteDir(siaPath)
+	if err != nil {
+		results = append(results, result(siaPath, errors.AddContext(err, "failed to delete directory")))
+	}
+	deleted()
+	return results
+}
+
 // FileList loops over all the files within the directory specified by siaPath
 // and will then call the provided listing function on the file.
 func (r *Renter) FileList(siaPath modules.SiaPath, recursive, cached bool, flf modules.FileListFunc) error {
@@ -102,6 +205,9 @@ func (r *Renter) RenameFile(currentName, newName modules.SiaPath) error {
 	if err != nil {
 		return err
 	}
+	if err := r.managedRenameArchivedPaths(currentName, newName); err != nil {
+		r.log.Printf("Unable to move archived siafile %v to %v: %v", currentName, newName, err)
+	}
 
 	// Call callThreadedBubbleMetadata on the old and new directories to make
 	// sure the system metadata is updated to reflect the move.
@@ -143,6 +249,22 @@ func (r *Renter) SetFileStuck(siaPath modules.SiaPath, stuck bool) (err error) {
 	return entry.SetAllStuck(stuck)
 }
 
+// SetFileTags replaces the tags of a file.
+func (r *Renter) SetFileTags(siaPath modules.SiaPath, tags map[string]string) (err error) {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	entry, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Compose(err, entry.Close())
+	}()
+	return entry.SetTags(tags)
+}
+
 func (r *Renter) FileHosts(sp modules.SiaPath) (hosts []modules.HostDBEntry, _ error) {
 	// open the file
 	entry, err := r.staticFileSystem.OpenSiaFile(sp)
@@ -265,6 +265,140 @@ func TestRenterDeleteFileMissingParent(t *testing.T) {
 	}
 }
 
+// TestRenterDeleteFiles tests deleting multiple files and directories with a
+// single call.
+func TestRenterDeleteFiles(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	rt, err := newRenterTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Create two files in the root and a directory with two files.
+	paths := []string{"a", "b", "dir/c", "dir/sub/d"}
+	for _, path := range paths {
+		entry, err := rt.renter.createRenterTestFile(newSiaPath(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := entry.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Deleting a directory without recursive should fail without deleting
+	// anything but the other files should still be deleted.
+	results, err := rt.renter.DeleteFiles([]modules.SiaPath{newSiaPath("a"), newSiaPath("dir"), newSiaPath("dne")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatal("wrong number of results", results)
+	}
+	if results[0].Error != "" {
+		t.Fatal("file should have been deleted", results[0])
+	}
+	if !strings.Contains(results[1].Error, errDeleteDirNotRecursive.Error()) {
+		t.Fatal("expected errDeleteDirNotRecursive", results[1])
+	}
+	if !strings.Contains(results[2].Error, filesystem.ErrNotExist.Error()) {
+		t.Fatal("expected ErrNotExist", results[2])
+	}
+
+	// Delete the rest recursively. Every file should have a result.
+	results, err = rt.renter.DeleteFiles([]modules.SiaPath{newSiaPath("b"), newSiaPath("dir")}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted := make(map[string]bool)
+	for _, res := range results {
+		if res.Error != "" {
+			t.Fatal("deletion failed", res)
+		}
+		deleted[res.SiaPath.String()] = true
+	}
+	if len(deleted) != 3 || !deleted["b"] || !deleted["dir/c"] || !deleted["dir/sub/d"] {
+		t.Fatal("wrong results", results)
+	}
+	if exists, _ := rt.renter.staticFileSystem.DirExists(newSiaPath("dir")); exists {
+		t.Fatal("directory should have been deleted")
+	}
+	files, err := rt.renter.FileListCollect(modules.RootSiaPath(), true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatal("files weren't deleted", files)
+	}
+
+	// The root can't be deleted.
+	results, err = rt.renter.DeleteFiles([]modules.SiaPath{modules.RootSiaPath()}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Error, errDeleteRootDir.Error()) {
+		t.Fatal("expected errDeleteRootDir", results)
+	}
+}
+
+// TestRenterDeleteFilesUnpinsDirs checks that deleting a directory with
+// DeleteFiles unpins the directories within it.
+func TestRenterDeleteFilesUnpinsDirs(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	rt, err := newRenterTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Create a file in a nested directory and pin the directory as well as
+	// another directory which isn't deleted.
+	for _, path := range []string{"dir/sub/a", "other/b"} {
+		entry, err := rt.renter.createRenterTestFile(newSiaPath(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := entry.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{"dir/sub", "other"} {
+		if err := rt.renter.SetDirPinned(newSiaPath(path), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Delete the parent of the pinned directory.
+	results, err := rt.renter.DeleteFiles([]modules.SiaPath{newSiaPath("dir")}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range results {
+		if res.Error != "" {
+			t.Fatal("deletion failed", res)
+		}
+	}
+
+	// Only the pin of the directory which wasn't deleted should be left.
+	pinned := rt.renter.PinnedDirs()
+	if len(pinned) != 1 || !pinned[0].Equals(newSiaPath("other")) {
+		t.Fatal("wrong pinned dirs", pinned)
+	}
+}
+
 // TestRenterFileList probes the FileList method of the renter type.
 func TestRenterFileList(t *testing.T) {
 	if testing.Short() {
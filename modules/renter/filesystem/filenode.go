@@ -160,6 +160,7 @@ func (n *FileNode) managedFileInfo(siaPath modules.SiaPath, offline map[string]b
 		Stuck:            numStuckChunks > 0,
 		StuckHealth:      stuckHealth,
 		StuckBytes:       stuckBytes,
+		Tags:             n.Tags(),
 		UID:              n.staticUID,
 		UploadedBytes:    uploadedBytes,
 		UploadProgress:   uploadProgress,
@@ -244,6 +245,7 @@ func (n *FileNode) staticCachedInfo(siaPath modules.SiaPath) (modules.FileInfo,
 		Stuck:            md.NumStuckChunks > 0,
 		StuckBytes:       md.CachedStuckBytes,
 		StuckHealth:      md.CachedStuckHealth,
+		Tags:             md.Tags,
 		UID:              n.staticUID,
 		UploadedBytes:    md.CachedUploadedBytes,
 		UploadProgress:   md.CachedUploadProgress,
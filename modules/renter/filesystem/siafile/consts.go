@@ -6,6 +6,17 @@ import (
 	"go.sia.tech/siad/crypto"
 )
 
+const (
+	// MaxTags is the maximum number of tags a file can have.
+	MaxTags = 32
+
+	// MaxTagKeyLength is the maximum length of a tag key in bytes.
+	MaxTagKeyLength = 64
+
+	// MaxTagValueLength is the maximum length of a tag value in bytes.
+	MaxTagValueLength = 256
+)
+
 const (
 	// pageSize is the size of a physical page on disk.
 	pageSize = 4096
@@ -0,0 +1,191 @@
+package siafile
+
+import (
+	"io"
+	"io/ioutil"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/writeaheadlog"
+)
+
+// DefragResult describes the changes made to a SiaFile by Defragment.
+type DefragResult struct {
+	// PiecesRemoved is the number of dead pieces which were removed from the
+	// chunks of the file.
+	PiecesRemoved uint64
+
+	// HostsPruned is the number of unused hosts which were removed from the
+	// file's pubKeyTable.
+	HostsPruned uint64
+
+	// BytesFreed is the number of bytes by which the file on disk shrunk.
+	BytesFreed uint64
+}
+
+// Defragment compacts the metadata of the SiaFile. Pieces stored on hosts
+// which are no longer used by the file and duplicate entries of the same piece
+// on the same host are removed from the chunks, unused hosts are removed from
+// the pubKeyTable and header pages which are no longer needed are released.
+// Like UpdateUsedHosts, unused hosts are only removed if the file has more
+// used hosts than pieces per chunk, since the file might otherwise still be in
+// flux.
+func (sf *SiaFile) Defragment() (_ DefragResult, err error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	// Can't defragment a deleted file.
+	if sf.deleted {
+		return DefragResult{}, errors.AddContext(ErrDeleted, "can't call Defragment on deleted file")
+	}
+	// Backup the changed metadata before changing it. Revert the change on
+	// error.
+	oldPubKeyTable := append([]HostPublicKey{}, sf.pubKeyTable...)
+	defer func(backup Metadata) {
+		if err != nil {
+			sf.staticMetadata.restore(backup)
+			sf.pubKeyTable = oldPubKeyTable
+		}
+	}(sf.staticMetadata.backup())
+
+	// Figure out which hosts to keep and how their offsets change.
+	var usedHosts int
+	for _, hpk := range sf.pubKeyTable {
+		if hpk.Used {
+			usedHosts++
+		}
+	}
+	prune := usedHosts > sf.staticMetadata.staticErasureCode.NumPieces()
+	var prunedTable []HostPublicKey
+	offsetMap := make(map[uint32]uint32)
+	for i, hpk := range sf.pubKeyTable {
+		if hpk.Used || !prune {
+			prunedTable = append(prunedTable, hpk)
+			offsetMap[uint32(i)] = uint32(len(prunedTable) - 1)
+		}
+	}
+	var res DefragResult
+	res.HostsPruned = uint64(len(sf.pubKeyTable) - len(prunedTable))
+
+	// Remove the dead pieces from the chunks. The chunks need to be read
+	// before the header is changed since the chunks might move. Partial
+	// chunks are skipped since their pieces belong to the partials SiaFile.
+	var chunks []chunk
+	err = sf.iterateChunksReadonly(func(c chunk) error {
+		if _, ok := sf.isIncludedPartialChunk(uint64(c.Index)); ok || sf.isIncompletePartialChunk(uint64(c.Index)) {
+			return nil
+		}
+		modified := false
+		for pieceIndex, pieceSet := range c.Pieces {
+			var newPieceSet []piece
+			for _, p := range pieceSet {
+				newOffset, exists := offsetMap[p.HostTableOffset]
+				if !exists {
+					continue
+				}
+				duplicate := false
+				for _, np := range newPieceSet {
+					if np.HostTableOffset == newOffset && np.MerkleRoot == p.MerkleRoot {
+						duplicate = true
+						break
+					}
+				}
+				if duplicate {
+					continue
+				}
+				modified = modified || newOffset != p.HostTableOffset
+				p.HostTableOffset = newOffset
+				newPieceSet = append(newPieceSet, p)
+			}
+			res.PiecesRemoved += uint64(len(pieceSet) - len(newPieceSet))
+			modified = modified || len(newPieceSet) != len(pieceSet)
+			c.Pieces[pieceIndex] = newPieceSet
+		}
+		if modified {
+			chunks = append(chunks, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return DefragResult{}, errors.AddContext(err, "failed to defragment chunks")
+	}
+	sf.pubKeyTable = prunedTable
+
+	// Release the header pages which are no longer needed.
+	moveUpdates, freed, err := sf.releaseHeaderPagesUpdates()
+	if err != nil {
+		return DefragResult{}, errors.AddContext(err, "failed to release header pages")
+	}
+	res.BytesFreed = uint64(freed)
+	if res.PiecesRemoved == 0 && res.HostsPruned == 0 && res.BytesFreed == 0 {
+		return res, nil
+	}
+
+	// Save the header and the modified chunks.
+	headerUpdates, err := sf.saveHeaderUpdates()
+	if err != nil {
+		return DefragResult{}, err
+	}
+	updates := append(moveUpdates, headerUpdates...)
+	for _, c := range chunks {
+		updates = append(updates, sf.saveChunkUpdate(c))
+	}
+	err = sf.createAndApplyTransaction(updates...)
+	if err != nil {
+		return DefragResult{}, errors.AddContext(err, "failed to apply defragment updates")
+	}
+	return res, nil
+}
+
+// releaseHeaderPagesUpdates moves the chunks of the SiaFile to the front if the
+// metadata and pubKeyTable need fewer pages than are currently reserved for
+// them. It updates the ChunkOffset of the metadata and returns the updates to
+// move the chunks and truncate the file, together with the number of bytes
+// freed. The updates need to be applied before any other header or chunk
+// updates.
+func (sf *SiaFile) releaseHeaderPagesUpdates() (_ []writeaheadlog.Update, _ int64, err error) {
+	pubKeyTable, err := marshalPubKeyTable(sf.pubKeyTable)
+	if err != nil {
+		return nil, 0, errors.AddContext(err, "failed to marshal pubkey table")
+	}
+	// Find the smallest chunk offset for which the header still fits.
+	oldChunkOffset := sf.staticMetadata.ChunkOffset
+	chunkOffset := int64(defaultReservedMDPages * pageSize)
+	for ; chunkOffset < oldChunkOffset; chunkOffset += pageSize {
+		md := sf.staticMetadata
+		md.ChunkOffset = chunkOffset
+		md.PubKeyTableOffset = chunkOffset - int64(len(pubKeyTable))
+		metadata, err := marshalMetadata(md)
+		if err != nil {
+			return nil, 0, errors.AddContext(err, "failed to marshal metadata")
+		}
+		if int64(len(metadata))+int64(len(pubKeyTable)) <= chunkOffset {
+			break
+		}
+	}
+	if chunkOffset >= oldChunkOffset {
+		return nil, 0, nil
+	}
+	// Read all the chunk data.
+	f, err := sf.deps.Open(sf.siaFilePath)
+	if err != nil {
+		return nil, 0, errors.AddContext(err, "failed to open siafile")
+	}
+	defer func() {
+		err = errors.Compose(err, f.Close())
+	}()
+	if _, err = f.Seek(oldChunkOffset, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	chunkData, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Move the offset to the front.
+	sf.staticMetadata.ChunkOffset = chunkOffset
+
+	// Create the updates to move the chunks and cut off the end of the file.
+	updates := []writeaheadlog.Update{
+		sf.createInsertUpdate(chunkOffset, chunkData),
+		writeaheadlog.TruncateUpdate(sf.siaFilePath, chunkOffset+int64(len(chunkData))),
+	}
+	return updates, oldChunkOffset - chunkOffset, nil
+}
@@ -0,0 +1,136 @@
+package siafile
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestDefragment tests that defragmenting a SiaFile removes dead pieces and
+// unused hosts and shrinks the file on disk.
+func TestDefragment(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	// Create a siafile without partial chunk.
+	siaFilePath, _, source, rc, sk, fileSize, numChunks, fileMode := newTestFileParams(1, false)
+	sf, wal, _ := customTestFileAndWAL(siaFilePath, source, rc, sk, fileSize, numChunks, fileMode)
+
+	// Add enough hosts for the unused ones to be pruned.
+	sf.addRandomHostKeys(rc.NumPieces() + 2)
+	updates, err := sf.saveHeaderUpdates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sf.createAndApplyTransaction(updates...); err != nil {
+		t.Fatal(err)
+	}
+	hostKeys := sf.HostPublicKeys()
+
+	// Add a piece for the first two hosts to every pieceSet and add the piece
+	// of the second host twice.
+	var root crypto.Hash
+	for chunkIndex := 0; chunkIndex < sf.numChunks; chunkIndex++ {
+		for pieceIndex := 0; pieceIndex < rc.NumPieces(); pieceIndex++ {
+			for _, hk := range []types.SiaPublicKey{hostKeys[0], hostKeys[1], hostKeys[1]} {
+				if err := sf.AddPiece(hk, uint64(chunkIndex), uint64(pieceIndex), root); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	// Add lots of unused hosts to grow the header beyond a single page and
+	// mark the first host as unused. The hosts are added in batches since
+	// the header grows by at most a page at a time.
+	sf.mu.Lock()
+	for i := 0; i < 6; i++ {
+		sf.addRandomHostKeys(50)
+		updates, err = sf.saveHeaderUpdates()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sf.createAndApplyTransaction(updates...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range sf.pubKeyTable[rc.NumPieces()+2:] {
+		sf.pubKeyTable[rc.NumPieces()+2+i].Used = false
+	}
+	sf.pubKeyTable[0].Used = false
+	remainingKey := sf.pubKeyTable[1]
+	updates, err = sf.saveHeaderUpdates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sf.createAndApplyTransaction(updates...); err != nil {
+		t.Fatal(err)
+	}
+	sf.mu.Unlock()
+	if sf.staticMetadata.ChunkOffset == pageSize {
+		t.Fatal("header should span multiple pages")
+	}
+	fi, err := os.Stat(siaFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizeBefore := fi.Size()
+
+	// Defragment the file.
+	res, err := sf.Defragment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.HostsPruned != 301 {
+		t.Fatal("wrong number of pruned hosts", res.HostsPruned)
+	}
+	if expected := uint64(2 * sf.numChunks * rc.NumPieces()); res.PiecesRemoved != expected {
+		t.Fatalf("expected %v removed pieces but got %v", expected, res.PiecesRemoved)
+	}
+	if res.BytesFreed == 0 || sf.staticMetadata.ChunkOffset != pageSize {
+		t.Fatal("header pages weren't released", res.BytesFreed, sf.staticMetadata.ChunkOffset)
+	}
+	fi, err = os.Stat(siaFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != sizeBefore-int64(res.BytesFreed) {
+		t.Fatalf("file should have shrunk from %v to %v bytes but was %v", sizeBefore, sizeBefore-int64(res.BytesFreed), fi.Size())
+	}
+
+	// Reload the file and check that every pieceSet contains a single piece
+	// of the remaining host.
+	sf, err = loadSiaFile(siaFilePath, wal, modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sf.pubKeyTable) != rc.NumPieces()+1 || !reflect.DeepEqual(sf.pubKeyTable[0], remainingKey) {
+		t.Fatal("wrong pubKeyTable after reload", len(sf.pubKeyTable))
+	}
+	err = sf.iterateChunksReadonly(func(chunk chunk) error {
+		for _, pieceSet := range chunk.Pieces {
+			if len(pieceSet) != 1 || pieceSet[0].HostTableOffset != 0 {
+				t.Fatal("unexpected pieceSet", pieceSet)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Defragmenting the file again shouldn't change it.
+	res, err = sf.Defragment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (DefragResult{}) {
+		t.Fatal("file shouldn't have changed", res)
+	}
+}
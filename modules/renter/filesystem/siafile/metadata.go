@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gitlab.com/NebulousLabs/errors"
@@ -136,6 +137,12 @@ type (
 		UserID  int32       `json:"userid"`  // id of the user who owns the file
 		GroupID int32       `json:"groupid"` // id of the group that owns the file
 
+		// Tags are user defined key/value pairs which allow for organizing
+		// files independently of their siapath. The map is never modified in
+		// place, SetTags replaces it instead. That way the map can be shared
+		// by copies of the metadata.
+		Tags map[string]string `json:"tags,omitempty"`
+
 		// The following fields are the offsets for data that is written to disk
 		// after the pubKeyTable. We reserve a generous amount of space for the
 		// table and extra fields, but we need to remember those offsets in case we
@@ -337,6 +344,7 @@ func (md Metadata) backup() (b Metadata) {
 	b.GroupID = md.GroupID
 	b.ChunkOffset = md.ChunkOffset
 	b.PubKeyTableOffset = md.PubKeyTableOffset
+	b.Tags = copyTags(md.Tags)
 	// Special handling for slice since reflect.DeepEqual is false when
 	// comparing empty slice to nil.
 	if md.PartialChunks == nil {
@@ -389,6 +397,7 @@ func (md *Metadata) restore(b Metadata) {
 	md.GroupID = b.GroupID
 	md.ChunkOffset = b.ChunkOffset
 	md.PubKeyTableOffset = b.PubKeyTableOffset
+	md.Tags = b.Tags
 	// If the backup was successful it should match the backup.
 	if build.Release == "testing" && !md.equals(b) {
 		fmt.Println("md:\n", md)
@@ -530,6 +539,32 @@ func (sf *SiaFile) SetLocalPath(path string) (err error) {
 	return sf.createAndApplyTransaction(updates...)
 }
 
+// SetTags replaces the tags of the file.
+func (sf *SiaFile) SetTags(tags map[string]string) (err error) {
+	if err := ValidateTags(tags); err != nil {
+		return err
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	// backup the changed metadata before changing it. Revert the change on
+	// error.
+	defer func(backup Metadata) {
+		if err != nil {
+			sf.staticMetadata.restore(backup)
+		}
+	}(sf.staticMetadata.backup())
+
+	sf.staticMetadata.Tags = copyTags(tags)
+	sf.staticMetadata.ChangeTime = time.Now()
+
+	// Save changes to metadata to disk.
+	updates, err := sf.saveMetadataUpdates()
+	if err != nil {
+		return err
+	}
+	return sf.createAndApplyTransaction(updates...)
+}
+
 // Size returns the file's size.
 func (sf *SiaFile) Size() uint64 {
 	sf.mu.RLock()
@@ -537,6 +572,13 @@ func (sf *SiaFile) Size() uint64 {
 	return uint64(sf.staticMetadata.FileSize)
 }
 
+// Tags returns a copy of the file's tags.
+func (sf *SiaFile) Tags() map[string]string {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	return copyTags(sf.staticMetadata.Tags)
+}
+
 // UpdateUniqueID creates a new random uid for the SiaFile.
 func (sf *SiaFile) UpdateUniqueID() {
 	sf.staticMetadata.UniqueID = uniqueID()
@@ -600,3 +642,38 @@ func (sf *SiaFile) staticMasterKey() crypto.CipherKey {
 func uniqueID() SiafileUID {
 	return SiafileUID(persist.UID())
 }
+
+// ValidateTags checks that the tags don't exceed the limits of a siafile and
+// that the keys can be used to filter files.
+func ValidateTags(tags map[string]string) error {
+	if len(tags) > MaxTags {
+		return errors.AddContext(ErrInvalidTag, fmt.Sprintf("a file can't have more than %v tags", MaxTags))
+	}
+	for key, value := range tags {
+		if key == "" {
+			return errors.AddContext(ErrInvalidTag, "tag key can't be empty")
+		}
+		if strings.Contains(key, "=") {
+			return errors.AddContext(ErrInvalidTag, fmt.Sprintf("tag key '%v' can't contain '='", key))
+		}
+		if len(key) > MaxTagKeyLength {
+			return errors.AddContext(ErrInvalidTag, fmt.Sprintf("tag key '%v' is longer than %v bytes", key, MaxTagKeyLength))
+		}
+		if len(value) > MaxTagValueLength {
+			return errors.AddContext(ErrInvalidTag, fmt.Sprintf("value of tag '%v' is longer than %v bytes", key, MaxTagValueLength))
+		}
+	}
+	return nil
+}
+
+// copyTags returns a deep copy of the tags. An empty map is copied as nil.
+func copyTags(tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(tags))
+	for k, v := range tags {
+		c[k] = v
+	}
+	return c
+}
@@ -141,6 +141,7 @@ func TestBackupRestoreMetadata(t *testing.T) {
 		sf.staticMetadata.GroupID = int32(fastrand.Intn(100))
 		sf.staticMetadata.ChunkOffset = int64(fastrand.Uint64n(100))
 		sf.staticMetadata.PubKeyTableOffset = int64(fastrand.Uint64n(100))
+		sf.staticMetadata.Tags = map[string]string{"key": string(fastrand.Bytes(10))}
 
 		// Error occurred after changing the fields.
 		return errors.New("")
@@ -150,3 +151,66 @@ func TestBackupRestoreMetadata(t *testing.T) {
 		t.Fatalf("metadata wasn't restored successfully %v %v", mdBefore, sf.staticMetadata)
 	}
 }
+
+// TestSetTags tests setting, persisting and validating the tags of a SiaFile.
+func TestSetTags(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	sf := newBlankTestFile()
+
+	// Set some tags. Changing the passed map afterwards shouldn't affect the
+	// file.
+	tags := map[string]string{"project": "photos", "year": "2021"}
+	if err := sf.SetTags(tags); err != nil {
+		t.Fatal(err)
+	}
+	tags["project"] = "changed"
+	if got := sf.Tags(); len(got) != 2 || got["project"] != "photos" || got["year"] != "2021" {
+		t.Fatal("wrong tags", got)
+	}
+
+	// The tags should be persisted.
+	md, err := LoadSiaFileMetadata(sf.siaFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(md.Tags) != 2 || md.Tags["project"] != "photos" {
+		t.Fatal("tags weren't persisted", md.Tags)
+	}
+
+	// Invalid tags should be rejected without changing the file.
+	tooMany := make(map[string]string)
+	for i := 0; i <= MaxTags; i++ {
+		tooMany[fmt.Sprint(i)] = ""
+	}
+	invalid := []map[string]string{
+		{"": "empty key"},
+		{"a=b": "key with separator"},
+		{string(fastrand.Bytes(MaxTagKeyLength + 1)): "long key"},
+		{"key": string(fastrand.Bytes(MaxTagValueLength + 1))},
+		tooMany,
+	}
+	for _, tags := range invalid {
+		if err := sf.SetTags(tags); !errors.Contains(err, ErrInvalidTag) {
+			t.Fatal("expected ErrInvalidTag", err)
+		}
+	}
+	if got := sf.Tags(); len(got) != 2 {
+		t.Fatal("invalid tags changed the file", got)
+	}
+
+	// Clearing the tags removes them from the metadata.
+	if err := sf.SetTags(nil); err != nil {
+		t.Fatal(err)
+	}
+	md, err = LoadSiaFileMetadata(sf.siaFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.Tags != nil {
+		t.Fatal("tags weren't cleared", md.Tags)
+	}
+}
@@ -29,6 +29,9 @@ var (
 	// ErrDeleted is returned when an operation failed due to the siafile being
 	// deleted already.
 	ErrDeleted = errors.New("files was deleted")
+	// ErrInvalidTag is returned if the tags of a file exceed the limits of a
+	// siafile.
+	ErrInvalidTag = errors.New("invalid file tag")
 )
 
 type (
@@ -282,6 +285,56 @@ func (sf *SiaFile) GrowNumChunks(numChunks uint64) (err error) {
 	return sf.createAndApplyTransaction(updates...)
 }
 
+// ShrinkNumChunks decreases the number of chunks in the SiaFile to numChunks
+// by dropping the chunks at the end of the file. If the file contains <=
+// numChunks chunks then ShrinkNumChunks is a no-op. Since a persisted SiaFile
+// always contains at least one chunk, numChunks can't be 0.
+func (sf *SiaFile) ShrinkNumChunks(numChunks uint64) (err error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	if sf.deleted {
+		return errors.AddContext(ErrDeleted, "can't shrink number of chunks of deleted file")
+	}
+	if sf.staticMetadata.HasPartialChunk {
+		return errors.New("can't shrink a siafile with a partial chunk")
+	}
+	if numChunks == 0 {
+		return errors.New("can't shrink a siafile to 0 chunks")
+	}
+	if uint64(sf.numChunks) <= numChunks {
+		return nil
+	}
+	// Backup the changed metadata before changing it. Revert the change on
+	// error.
+	oldNumChunks := sf.numChunks
+	defer func(backup Metadata) {
+		if err != nil {
+			sf.staticMetadata.restore(backup)
+			sf.numChunks = oldNumChunks
+		}
+	}(sf.staticMetadata.backup())
+	// Update the number of stuck chunks.
+	for chunkIndex := int(numChunks); chunkIndex < sf.numChunks; chunkIndex++ {
+		chunk, err := sf.chunk(chunkIndex)
+		if err != nil {
+			return err
+		}
+		if chunk.Stuck {
+			sf.staticMetadata.NumStuckChunks--
+		}
+	}
+	// Update the chunks and the fileSize.
+	sf.numChunks = int(numChunks)
+	sf.staticMetadata.FileSize = int64(sf.staticChunkSize() * numChunks)
+	updates, err := sf.saveMetadataUpdates()
+	if err != nil {
+		return err
+	}
+	// Truncate the file on disk after the last remaining chunk.
+	updates = append(updates, writeaheadlog.TruncateUpdate(sf.siaFilePath, sf.chunkOffset(sf.numChunks)))
+	return sf.createAndApplyTransaction(updates...)
+}
+
 // RemoveLastChunk removes the last chunk of the SiaFile and truncates the file
 // accordingly.
 func (sf *SiaFile) RemoveLastChunk() error {
@@ -714,6 +714,69 @@ func TestGrowNumChunks(t *testing.T) {
 	}
 }
 
+// TestShrinkNumChunks is a unit test for the SiaFile's ShrinkNumChunks method.
+func TestShrinkNumChunks(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	// Create a blank file with 5 chunks and mark the last chunk as stuck.
+	siaFilePath, _, source, rc, sk, _, _, fileMode := newTestFileParams(1, false)
+	sf, wal, _ := customTestFileAndWAL(siaFilePath, source, rc, sk, 0, 0, fileMode)
+	if err := sf.GrowNumChunks(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := sf.SetStuck(4, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Declare a check method.
+	checkFile := func(sf *SiaFile, numChunks, numStuckChunks uint64) {
+		if numChunks != sf.NumChunks() {
+			t.Fatalf("Expected %v chunks but was %v", numChunks, sf.NumChunks())
+		}
+		if size := numChunks * sf.ChunkSize(); size != sf.Size() {
+			t.Fatalf("Expected size to be %v but was %v", size, sf.Size())
+		}
+		if numStuckChunks != sf.NumStuckChunks() {
+			t.Fatalf("Expected %v stuck chunks but was %v", numStuckChunks, sf.NumStuckChunks())
+		}
+	}
+
+	// Shrinking to a larger number of chunks is a no-op.
+	if err := sf.ShrinkNumChunks(6); err != nil {
+		t.Fatal(err)
+	}
+	checkFile(sf, 5, 1)
+
+	// Drop the last 3 chunks.
+	if err := sf.ShrinkNumChunks(2); err != nil {
+		t.Fatal(err)
+	}
+	checkFile(sf, 2, 0)
+	// Load the file from disk again to also check that persistence works.
+	sf, err := LoadSiaFile(sf.siaFilePath, wal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFile(sf, 2, 0)
+
+	// The file can grow again.
+	if err := sf.GrowNumChunks(3); err != nil {
+		t.Fatal(err)
+	}
+	checkFile(sf, 3, 0)
+	if err := ensureMetadataValid(sf.Metadata()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Files can't be shrunk to 0 chunks.
+	if err := sf.ShrinkNumChunks(0); err == nil {
+		t.Fatal("shouldn't be able to shrink the file to 0 chunks")
+	}
+}
+
 // TestPruneHosts is a unit test for the pruneHosts method.
 func TestPruneHosts(t *testing.T) {
 	if testing.Short() {
@@ -51,17 +51,26 @@ type fuseDirnode struct {
 //
 // NodeStatfser is necessary to provide information about the filesystem that
 // contains the directory.
+//
+// NodeCreater, NodeMkdirer, NodeRenamer, NodeRmdirer and NodeUnlinker are
+// necessary for modifying the directory if the filesystem isn't read-only.
 var _ = (fs.NodeAccesser)((*fuseDirnode)(nil))
+var _ = (fs.NodeCreater)((*fuseDirnode)(nil))
 var _ = (fs.NodeFlusher)((*fuseDirnode)(nil))
 var _ = (fs.NodeGetattrer)((*fuseDirnode)(nil))
 var _ = (fs.NodeLookuper)((*fuseDirnode)(nil))
+var _ = (fs.NodeMkdirer)((*fuseDirnode)(nil))
 var _ = (fs.NodeReaddirer)((*fuseDirnode)(nil))
+var _ = (fs.NodeRenamer)((*fuseDirnode)(nil))
+var _ = (fs.NodeRmdirer)((*fuseDirnode)(nil))
 var _ = (fs.NodeStatfser)((*fuseDirnode)(nil))
+var _ = (fs.NodeUnlinker)((*fuseDirnode)(nil))
 
 // fuseFilenode is a fuse node for the fs package that covers a siafile.
 //
 // Data is fetched using a download streamer. This download streamer needs to be
-// closed when the filehandle is released.
+// closed when the filehandle is released. Files that are opened for writing use
+// a fuseWriteHandle instead.
 type fuseFilenode struct {
 	atomicClosed uint32
 
@@ -88,12 +97,17 @@ type fuseFilenode struct {
 //
 // NodeStatfser is necessary to provide information about the filesystem that
 // contains the file.
+//
+// NodeFsyncer, NodeSetattrer and NodeWriter are necessary for writing files.
 var _ = (fs.NodeAccesser)((*fuseFilenode)(nil))
 var _ = (fs.NodeFlusher)((*fuseFilenode)(nil))
+var _ = (fs.NodeFsyncer)((*fuseFilenode)(nil))
 var _ = (fs.NodeGetattrer)((*fuseFilenode)(nil))
 var _ = (fs.NodeOpener)((*fuseFilenode)(nil))
 var _ = (fs.NodeReader)((*fuseFilenode)(nil))
+var _ = (fs.NodeSetattrer)((*fuseFilenode)(nil))
 var _ = (fs.NodeStatfser)((*fuseFilenode)(nil))
+var _ = (fs.NodeWriter)((*fuseFilenode)(nil))
 
 // fuseRoot is the root directory for a mounted fuse filesystem.
 type fuseFS struct {
@@ -108,8 +122,14 @@ type fuseFS struct {
 func errToStatus(err error) syscall.Errno {
 	if err == nil {
 		return syscall.F_OK
-	} else if errors.IsOSNotExist(err) {
+	} else if errors.IsOSNotExist(err) || errors.Contains(err, filesystem.ErrNotExist) {
 		return syscall.ENOENT
+	} else if errors.Contains(err, filesystem.ErrExists) {
+		return syscall.EEXIST
+	} else if errors.Contains(err, errFuseReadOnly) {
+		return syscall.EROFS
+	} else if errors.Contains(err, errFuseNotEmpty) {
+		return syscall.ENOTEMPTY
 	}
 	return syscall.EIO
 }
@@ -138,8 +158,16 @@ func (fdn *fuseDirnode) Flush(ctx context.Context, fh fs.FileHandle) syscall.Err
 	return errToStatus(err)
 }
 
-// Flush is called when a file is being closed.
+// Flush is called when a file is being closed. Files that were opened for
+// writing are uploaded.
 func (ffn *fuseFilenode) Flush(ctx context.Context, fh fs.FileHandle) syscall.Errno {
+	if fwh, ok := fh.(*fuseWriteHandle); ok {
+		err := fwh.managedUpload()
+		if err != nil {
+			ffn.staticFilesystem.renter.log.Printf("error when flushing fuse file %v: %v", fwh.staticSiaPath, err)
+		}
+		return errToStatus(err)
+	}
 	swapped := atomic.CompareAndSwapUint32(&ffn.atomicClosed, 0, 1)
 	if !swapped {
 		return errToStatus(nil)
@@ -251,17 +279,40 @@ func (ffn *fuseFilenode) Getattr(ctx context.Context, fh fs.FileHandle, out *fus
 	}
 
 	out.Size = fileInfo.Filesize
+	// Files that are open for writing report the size of their write buffer.
+	if fwh, ok := fh.(*fuseWriteHandle); ok {
+		size, err := fwh.callSize()
+		if err != nil {
+			ffn.staticFilesystem.renter.log.Printf("Unable to fetch size of fuse write buffer: %v", err)
+			return errToStatus(err)
+		}
+		out.Size = size
+	}
 	out.Mode = uint32(fileInfo.Mode()) | syscall.S_IFREG
 	out.Ino = fileInfo.UID
 	return errToStatus(nil)
 }
 
-// Open will open a streamer for the file.
+// Open will open a streamer for the file. If the file is opened for writing, a
+// write handle is returned instead.
 //
 // TODO: Currently 'Open' returns '0' for the fuseFlags. I was unable to figure
 // out from the documentation what the flags are supposed to represent. So far,
 // this has not seemed to cause problems.
 func (ffn *fuseFilenode) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR) != 0 {
+		if ffn.staticFilesystem.options.ReadOnly {
+			return nil, 0, errToStatus(errFuseReadOnly)
+		}
+		fwh, err := ffn.managedOpenWrite(flags&syscall.O_TRUNC != 0)
+		if err != nil {
+			siaPath := ffn.staticFilesystem.renter.staticFileSystem.FileSiaPath(ffn.staticFileNode)
+			ffn.staticFilesystem.renter.log.Printf("Unable to open file %v for writing: %v", siaPath, err)
+			return nil, 0, errToStatus(err)
+		}
+		return fwh, 0, errToStatus(nil)
+	}
+
 	ffn.mu.Lock()
 	defer ffn.mu.Unlock()
 
@@ -277,6 +328,16 @@ func (ffn *fuseFilenode) Open(ctx context.Context, flags uint32) (fs.FileHandle,
 
 // Read will read data from the file and place it in dest.
 func (ffn *fuseFilenode) Read(ctx context.Context, f fs.FileHandle, dest []byte, offset int64) (fuse.ReadResult, syscall.Errno) {
+	// Files that are open for writing are read from their write buffer.
+	if fwh, ok := f.(*fuseWriteHandle); ok {
+		data, err := fwh.callRead(dest, offset)
+		if err != nil {
+			ffn.staticFilesystem.renter.log.Printf("Error reading from offset %v in fuse write buffer of %v: %v", offset, fwh.staticSiaPath, err)
+			return nil, errToStatus(err)
+		}
+		return fuse.ReadResultData(data), errToStatus(nil)
+	}
+
 	// TODO: Right now only one call to Read from a file can be in effect at
 	// once, based on the way the streamer and the read call has been
 	// implemented. As the streamer gets updated to more readily support
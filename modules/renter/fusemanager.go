@@ -99,11 +99,6 @@ func (fm *fuseManager) Mount(mountPoint string, sp modules.SiaPath, opts modules
 		}
 	}()
 
-	// Enforce read-only requirements for now.
-	if !opts.ReadOnly {
-		return errors.New("fuse must be mounted as read-only")
-	}
-
 	// Get the mountpoint's root from the filesystem.
 	rootDirNode, err := fm.renter.staticFileSystem.OpenSiaDir(sp)
 	if err != nil {
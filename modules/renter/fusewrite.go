@@ -0,0 +1,477 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package renter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+	"go.sia.tech/siad/persist"
+)
+
+const (
+	// fuseWritesDir is the name of the directory within the renter's persist
+	// dir that buffers the data written to fuse files until it is uploaded.
+	fuseWritesDir = "fusewrites"
+)
+
+var (
+	// errFuseReadOnly is returned when trying to modify a fuse filesystem that
+	// was mounted read-only.
+	errFuseReadOnly = errors.New("fuse filesystem is mounted read-only")
+
+	// errFuseNotEmpty is returned when trying to remove a directory that still
+	// contains files or directories.
+	errFuseNotEmpty = errors.New("directory is not empty")
+)
+
+// fuseWriteHandle is the file handle of a fuse file that was opened for
+// writing. Writes are buffered in a local file and the buffer is uploaded when
+// the file is flushed or synced. The upload goes to a temporary siapath next
+// to the file, which replaces the file once the upload succeeded. That way a
+// failed upload never destroys the previous version of the file.
+type fuseWriteHandle struct {
+	// dirty indicates that the buffer contains data that wasn't uploaded yet.
+	dirty bool
+
+	staticBuffer      *os.File
+	staticErasureCode modules.ErasureCoder
+	staticFilenode    *fuseFilenode
+	staticSiaPath     modules.SiaPath
+	mu                sync.Mutex
+}
+
+// Ensure the write handle satisfies the required interfaces.
+//
+// FileReleaser is necessary for deleting the local buffer once the file is
+// closed. All other calls go through the fuseFilenode, which passes them on to
+// the write handle.
+var _ = (fs.FileReleaser)((*fuseWriteHandle)(nil))
+
+// newFuseWriteHandle creates a write handle for the file at the given siapath.
+// If src is not nil, the buffer is initialized with its contents.
+func (ffn *fuseFilenode) newFuseWriteHandle(siaPath modules.SiaPath, ec modules.ErasureCoder, src io.Reader) (_ *fuseWriteHandle, err error) {
+	r := ffn.staticFilesystem.renter
+	dir := filepath.Join(r.persistDir, fuseWritesDir)
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		return nil, errors.AddContext(err, "unable to create fuse write buffer dir")
+	}
+	buf, err := ioutil.TempFile(dir, "")
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to create fuse write buffer")
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Compose(err, buf.Close(), os.Remove(buf.Name()))
+		}
+	}()
+	if src != nil {
+		if _, err := io.Copy(buf, src); err != nil {
+			return nil, errors.AddContext(err, "unable to copy file into fuse write buffer")
+		}
+	}
+	return &fuseWriteHandle{
+		staticBuffer:      buf,
+		staticErasureCode: ec,
+		staticFilenode:    ffn,
+		staticSiaPath:     siaPath,
+	}, nil
+}
+
+// callRead reads from the write buffer.
+func (fwh *fuseWriteHandle) callRead(dest []byte, offset int64) ([]byte, error) {
+	fwh.mu.Lock()
+	defer fwh.mu.Unlock()
+	n, err := fwh.staticBuffer.ReadAt(dest, offset)
+	if err != nil && !errors.Contains(err, io.EOF) {
+		return nil, err
+	}
+	return dest[:n], nil
+}
+
+// callSize returns the size of the write buffer.
+func (fwh *fuseWriteHandle) callSize() (uint64, error) {
+	fwh.mu.Lock()
+	defer fwh.mu.Unlock()
+	stat, err := fwh.staticBuffer.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(stat.Size()), nil
+}
+
+// callTruncate changes the size of the write buffer.
+func (fwh *fuseWriteHandle) callTruncate(size uint64) error {
+	fwh.mu.Lock()
+	defer fwh.mu.Unlock()
+	if err := fwh.staticBuffer.Truncate(int64(size)); err != nil {
+		return err
+	}
+	fwh.dirty = true
+	return nil
+}
+
+// callWrite writes data to the write buffer.
+func (fwh *fuseWriteHandle) callWrite(data []byte, offset int64) (int, error) {
+	fwh.mu.Lock()
+	defer fwh.mu.Unlock()
+	n, err := fwh.staticBuffer.WriteAt(data, offset)
+	if n > 0 {
+		fwh.dirty = true
+	}
+	return n, err
+}
+
+// fuseTempSiaPath returns a hidden siapath next to siaPath which is used to
+// replace the file at siaPath.
+func fuseTempSiaPath(siaPath modules.SiaPath) (modules.SiaPath, error) {
+	dirSiaPath, err := siaPath.Dir()
+	if err != nil {
+		return modules.SiaPath{}, err
+	}
+	return dirSiaPath.Join(fmt.Sprintf(".%v.fuse-%v", siaPath.Name(), persist.RandomSuffix()))
+}
+
+// managedUpload uploads the write buffer if it contains data that wasn't
+// uploaded yet and replaces the file with the upload.
+func (fwh *fuseWriteHandle) managedUpload() error {
+	fwh.mu.Lock()
+	defer fwh.mu.Unlock()
+	if !fwh.dirty {
+		return nil
+	}
+	r := fwh.staticFilenode.staticFilesystem.renter
+	dirSiaPath, err := fwh.staticSiaPath.Dir()
+	if err != nil {
+		return err
+	}
+	tmpSiaPath, err := fuseTempSiaPath(fwh.staticSiaPath)
+	if err != nil {
+		return err
+	}
+
+	// Upload the buffer to the temporary siapath.
+	if _, err := fwh.staticBuffer.Seek(0, io.SeekStart); err != nil {
+		return errors.AddContext(err, "unable to seek to the start of the fuse write buffer")
+	}
+	fileNode, err := r.callUploadStreamFromReader(modules.FileUploadParams{
+		SiaPath:     tmpSiaPath,
+		ErasureCode: fwh.staticErasureCode,
+		CipherType:  crypto.TypeDefaultRenter,
+	}, fwh.staticBuffer)
+	if err != nil {
+		return errors.AddContext(err, "unable to upload fuse write buffer")
+	}
+	if err := fileNode.Close(); err != nil {
+		return errors.AddContext(err, "unable to close uploaded file")
+	}
+
+	// Replace the file with the upload.
+	err = r.staticFileSystem.DeleteFile(fwh.staticSiaPath)
+	if err != nil && !errors.Contains(err, filesystem.ErrNotExist) {
+		return errors.AddContext(err, "unable to delete previous version of the file")
+	}
+	if err := r.staticFileSystem.RenameFile(tmpSiaPath, fwh.staticSiaPath); err != nil {
+		return errors.AddContext(err, "unable to replace file with upload")
+	}
+	fwh.dirty = false
+
+	// The inode still refers to the previous version of the file. Drop the
+	// kernel's cached entry so that the next lookup picks up the new one.
+	if name, parent := fwh.staticFilenode.Parent(); parent != nil {
+		_ = parent.NotifyEntry(name)
+	}
+	_ = r.staticBubbleScheduler.callQueueBubble(dirSiaPath)
+	return nil
+}
+
+// Release uploads any data that wasn't uploaded yet and deletes the write
+// buffer.
+func (fwh *fuseWriteHandle) Release(ctx context.Context) syscall.Errno {
+	uploadErr := fwh.managedUpload()
+	fwh.mu.Lock()
+	closeErr := fwh.staticBuffer.Close()
+	removeErr := os.Remove(fwh.staticBuffer.Name())
+	fwh.mu.Unlock()
+	err := errors.Compose(uploadErr, closeErr, removeErr)
+	if err != nil {
+		fwh.staticFilenode.staticFilesystem.renter.log.Printf("error when releasing fuse write handle for %v: %v", fwh.staticSiaPath, err)
+	}
+	return errToStatus(err)
+}
+
+// childSiaPath returns the siapath of the child with the given name.
+func (fdn *fuseDirnode) childSiaPath(name string) (modules.SiaPath, error) {
+	return fdn.staticFilesystem.renter.staticFileSystem.DirSiaPath(fdn.staticDirNode).Join(name)
+}
+
+// Create creates a new file in the directory and opens it for writing.
+func (fdn *fuseDirnode) Create(ctx context.Context, name string, flags uint32, mode uint32, out *fuse.EntryOut) (*fs.Inode, fs.FileHandle, uint32, syscall.Errno) {
+	if fdn.staticFilesystem.options.ReadOnly {
+		return nil, nil, 0, errToStatus(errFuseReadOnly)
+	}
+	r := fdn.staticFilesystem.renter
+	siaPath, err := fdn.childSiaPath(name)
+	if err != nil {
+		return nil, nil, 0, errToStatus(err)
+	}
+
+	// Create an empty siafile so the file shows up right away. The data is
+	// uploaded once the file is flushed.
+	ec := modules.NewRSSubCodeDefault()
+	err = r.staticFileSystem.NewSiaFile(siaPath, "", ec, crypto.GenerateSiaKey(crypto.TypeDefaultRenter), 0, os.FileMode(mode)&os.ModePerm, false)
+	if err != nil {
+		r.log.Printf("Unable to create fuse file %v: %v", siaPath, err)
+		return nil, nil, 0, errToStatus(err)
+	}
+	fileNode, err := fdn.staticDirNode.File(name)
+	if err != nil {
+		return nil, nil, 0, errToStatus(err)
+	}
+	fileInfo, err := r.staticFileSystem.FileNodeInfo(fileNode)
+	if err != nil {
+		return nil, nil, 0, errToStatus(errors.Compose(err, fileNode.Close()))
+	}
+	filenode := &fuseFilenode{
+		staticFilesystem: fdn.staticFilesystem,
+		staticFileNode:   fileNode,
+	}
+	fwh, err := filenode.newFuseWriteHandle(siaPath, ec, nil)
+	if err != nil {
+		r.log.Printf("Unable to open fuse file %v for writing: %v", siaPath, err)
+		return nil, nil, 0, errToStatus(errors.Compose(err, fileNode.Close()))
+	}
+	out.Ino = fileInfo.UID
+	out.Mode = uint32(fileInfo.Mode()) | fuse.S_IFREG
+	inode := fdn.NewInode(ctx, filenode, fs.StableAttr{
+		Ino:  fileInfo.UID,
+		Mode: fuse.S_IFREG,
+	})
+	return inode, fwh, 0, errToStatus(nil)
+}
+
+// Mkdir creates a new directory.
+func (fdn *fuseDirnode) Mkdir(ctx context.Context, name string, mode uint32, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	if fdn.staticFilesystem.options.ReadOnly {
+		return nil, errToStatus(errFuseReadOnly)
+	}
+	siaPath, err := fdn.childSiaPath(name)
+	if err != nil {
+		return nil, errToStatus(err)
+	}
+	err = fdn.staticFilesystem.renter.staticFileSystem.NewSiaDir(siaPath, os.FileMode(mode)&os.ModePerm)
+	if err != nil {
+		fdn.staticFilesystem.renter.log.Printf("Unable to create fuse dir %v: %v", siaPath, err)
+		return nil, errToStatus(err)
+	}
+	return fdn.Lookup(ctx, name, out)
+}
+
+// Rename moves a file or directory. An existing file at the destination is
+// replaced.
+func (fdn *fuseDirnode) Rename(ctx context.Context, name string, newParent fs.InodeEmbedder, newName string, flags uint32) syscall.Errno {
+	if fdn.staticFilesystem.options.ReadOnly {
+		return errToStatus(errFuseReadOnly)
+	}
+	// Exchanging files and other special renames are not supported.
+	if flags != 0 {
+		return syscall.ENOTSUP
+	}
+	newDir, ok := newParent.(*fuseDirnode)
+	if !ok {
+		return syscall.EXDEV
+	}
+	r := fdn.staticFilesystem.renter
+	oldSiaPath, err := fdn.childSiaPath(name)
+	if err != nil {
+		return errToStatus(err)
+	}
+	newSiaPath, err := newDir.childSiaPath(newName)
+	if err != nil {
+		return errToStatus(err)
+	}
+
+	// Rename a directory.
+	_, err = r.staticFileSystem.CachedFileInfo(oldSiaPath)
+	if errors.Contains(err, filesystem.ErrNotExist) {
+		err = r.RenameDir(oldSiaPath, newSiaPath)
+		if err != nil {
+			r.log.Printf("Unable to rename fuse dir %v to %v: %v", oldSiaPath, newSiaPath, err)
+		}
+		return errToStatus(err)
+	} else if err != nil {
+		r.log.Printf("Unable to get info of fuse file %v: %v", oldSiaPath, err)
+		return errToStatus(err)
+	}
+
+	// Rename a file. An existing file at the destination is moved out of the
+	// way first and only deleted once the rename succeeded, so that a failed
+	// rename doesn't lose it.
+	tmpSiaPath, err := fuseTempSiaPath(newSiaPath)
+	if err != nil {
+		return errToStatus(err)
+	}
+	err = r.staticFileSystem.RenameFile(newSiaPath, tmpSiaPath)
+	replaced := err == nil
+	if err != nil && !errors.Contains(err, filesystem.ErrNotExist) {
+		r.log.Printf("Unable to replace fuse file %v: %v", newSiaPath, err)
+		return errToStatus(err)
+	}
+	err = r.RenameFile(oldSiaPath, newSiaPath)
+	if err != nil {
+		r.log.Printf("Unable to rename fuse file %v to %v: %v", oldSiaPath, newSiaPath, err)
+		if replaced {
+			if restoreErr := r.staticFileSystem.RenameFile(tmpSiaPath, newSiaPath); restoreErr != nil {
+				r.log.Printf("Unable to restore fuse file %v from %v: %v", newSiaPath, tmpSiaPath, restoreErr)
+			}
+		}
+		return errToStatus(err)
+	}
+	if replaced {
+		if err := r.staticFileSystem.DeleteFile(tmpSiaPath); err != nil {
+			r.log.Printf("Unable to delete replaced fuse file %v: %v", tmpSiaPath, err)
+		}
+	}
+	return errToStatus(nil)
+}
+
+// Rmdir removes an empty directory.
+func (fdn *fuseDirnode) Rmdir(ctx context.Context, name string) syscall.Errno {
+	if fdn.staticFilesystem.options.ReadOnly {
+		return errToStatus(errFuseReadOnly)
+	}
+	r := fdn.staticFilesystem.renter
+	siaPath, err := fdn.childSiaPath(name)
+	if err != nil {
+		return errToStatus(err)
+	}
+	var mu sync.Mutex
+	var entries int
+	err = r.staticFileSystem.CachedList(siaPath, false, func(modules.FileInfo) {
+		mu.Lock()
+		entries++
+		mu.Unlock()
+	}, func(modules.DirectoryInfo) {
+		mu.Lock()
+		entries++
+		mu.Unlock()
+	})
+	if err != nil {
+		return errToStatus(err)
+	}
+	// The directory itself is part of the list.
+	if entries > 1 {
+		return errToStatus(errFuseNotEmpty)
+	}
+	err = r.DeleteDir(siaPath)
+	if err != nil {
+		r.log.Printf("Unable to delete fuse dir %v: %v", siaPath, err)
+	}
+	return errToStatus(err)
+}
+
+// Unlink deletes a file.
+func (fdn *fuseDirnode) Unlink(ctx context.Context, name string) syscall.Errno {
+	if fdn.staticFilesystem.options.ReadOnly {
+		return errToStatus(errFuseReadOnly)
+	}
+	siaPath, err := fdn.childSiaPath(name)
+	if err != nil {
+		return errToStatus(err)
+	}
+	err = fdn.staticFilesystem.renter.DeleteFile(siaPath)
+	if err != nil {
+		fdn.staticFilesystem.renter.log.Printf("Unable to delete fuse file %v: %v", siaPath, err)
+	}
+	return errToStatus(err)
+}
+
+// managedOpenWrite opens the file for writing. Unless the file is truncated,
+// the buffer is initialized with the current contents of the file.
+func (ffn *fuseFilenode) managedOpenWrite(truncate bool) (*fuseWriteHandle, error) {
+	r := ffn.staticFilesystem.renter
+	siaPath := r.staticFileSystem.FileSiaPath(ffn.staticFileNode)
+	ec := ffn.staticFileNode.ErasureCode()
+	if truncate {
+		return ffn.newFuseWriteHandle(siaPath, ec, nil)
+	}
+	stream, err := r.StreamerByNode(ffn.staticFileNode, false)
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to get stream for file")
+	}
+	fwh, err := ffn.newFuseWriteHandle(siaPath, ec, stream)
+	return fwh, errors.Compose(err, stream.Close())
+}
+
+// Fsync uploads the data written to the file.
+func (ffn *fuseFilenode) Fsync(ctx context.Context, fh fs.FileHandle, flags uint32) syscall.Errno {
+	fwh, ok := fh.(*fuseWriteHandle)
+	if !ok {
+		return errToStatus(nil)
+	}
+	err := fwh.managedUpload()
+	if err != nil {
+		ffn.staticFilesystem.renter.log.Printf("Unable to sync fuse file %v: %v", fwh.staticSiaPath, err)
+	}
+	return errToStatus(err)
+}
+
+// Setattr changes the size of a file. Other attributes are ignored.
+func (ffn *fuseFilenode) Setattr(ctx context.Context, fh fs.FileHandle, in *fuse.SetAttrIn, out *fuse.AttrOut) syscall.Errno {
+	size, sizeChanged := in.GetSize()
+	if sizeChanged {
+		if ffn.staticFilesystem.options.ReadOnly {
+			return errToStatus(errFuseReadOnly)
+		}
+		err := ffn.managedTruncate(fh, size)
+		if err != nil {
+			siaPath := ffn.staticFilesystem.renter.staticFileSystem.FileSiaPath(ffn.staticFileNode)
+			ffn.staticFilesystem.renter.log.Printf("Unable to truncate fuse file %v: %v", siaPath, err)
+			return errToStatus(err)
+		}
+	}
+	return ffn.Getattr(ctx, fh, out)
+}
+
+// managedTruncate changes the size of the file. If the file isn't open for
+// writing, it is opened, truncated and uploaded right away.
+func (ffn *fuseFilenode) managedTruncate(fh fs.FileHandle, size uint64) error {
+	if fwh, ok := fh.(*fuseWriteHandle); ok {
+		return fwh.callTruncate(size)
+	}
+	fwh, err := ffn.managedOpenWrite(size == 0)
+	if err != nil {
+		return err
+	}
+	err = fwh.callTruncate(size)
+	if errno := fwh.Release(context.Background()); errno != syscall.F_OK {
+		err = errors.Compose(err, errno)
+	}
+	return err
+}
+
+// Write writes data to the file's write buffer.
+func (ffn *fuseFilenode) Write(ctx context.Context, fh fs.FileHandle, data []byte, offset int64) (uint32, syscall.Errno) {
+	fwh, ok := fh.(*fuseWriteHandle)
+	if !ok {
+		return 0, syscall.EBADF
+	}
+	n, err := fwh.callWrite(data, offset)
+	if err != nil {
+		ffn.staticFilesystem.renter.log.Printf("Error writing to offset %v in fuse file %v: %v", offset, fwh.staticSiaPath, err)
+	}
+	return uint32(n), errToStatus(err)
+}
@@ -0,0 +1,75 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package renter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+)
+
+// TestFuseWriteHandleBuffer is a unit test for the buffer operations of the
+// fuse write handle.
+func TestFuseWriteHandleBuffer(t *testing.T) {
+	t.Parallel()
+
+	dir := build.TempDir("renter", t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := ioutil.TempFile(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := buf.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	fwh := &fuseWriteHandle{staticBuffer: buf}
+
+	// Write some data at an offset. The gap is filled with zeros.
+	if n, err := fwh.callWrite([]byte("abc"), 2); err != nil || n != 3 {
+		t.Fatal(n, err)
+	}
+	if !fwh.dirty {
+		t.Fatal("handle should be dirty after a write")
+	}
+	if size, err := fwh.callSize(); err != nil || size != 5 {
+		t.Fatal(size, err)
+	}
+	data, err := fwh.callRead(make([]byte, 10), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0, 0, 'a', 'b', 'c'}) {
+		t.Fatal("wrong data", data)
+	}
+
+	// Reading past the end returns no data.
+	data, err = fwh.callRead(make([]byte, 10), 10)
+	if err != nil || len(data) != 0 {
+		t.Fatal(data, err)
+	}
+
+	// Truncate the buffer.
+	fwh.dirty = false
+	if err := fwh.callTruncate(3); err != nil {
+		t.Fatal(err)
+	}
+	if !fwh.dirty {
+		t.Fatal("handle should be dirty after a truncate")
+	}
+	data, err = fwh.callRead(make([]byte, 10), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0, 0, 'a'}) {
+		t.Fatal("wrong data", data)
+	}
+}
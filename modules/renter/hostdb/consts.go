@@ -59,6 +59,10 @@ const (
 	// minScansForSpeedup successful scans.
 	scanSpeedupMedianMultiplier = 5
 
+	// scanLatencyWeight is the weight of a new latency measurement in the
+	// moving average of a host's latency.
+	scanLatencyWeight = 0.2
+
 	// storageCompetitionFactor is the amount of competition we expect to have
 	// over a volume of storage on a host. If a host has 1 TB of storage
 	// remaining and we are actively uploading, it is unlikely that we will get
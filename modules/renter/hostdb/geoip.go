@@ -0,0 +1,95 @@
+package hostdb
+
+import (
+	"encoding/csv"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"gitlab.com/NebulousLabs/errors"
+)
+
+const (
+	// geoIPFilename is the name of the optional file in the hostdb's persist
+	// directory which maps subnets to regions. Every line of the file contains
+	// a subnet in CIDR notation followed by the name of its region, separated
+	// by a comma. Lines starting with '#' are ignored.
+	geoIPFilename = "geoip.csv"
+)
+
+type (
+	// geoIPEntry maps a subnet to a region.
+	geoIPEntry struct {
+		subnet *net.IPNet
+		region string
+	}
+
+	// geoIPDatabase is used to look up the regions of hosts.
+	geoIPDatabase struct {
+		entries []geoIPEntry
+	}
+)
+
+// loadGeoIPDatabase loads the GeoIP database at the provided path. If the file
+// doesn't exist, an empty database is returned.
+func loadGeoIPDatabase(path string) (*geoIPDatabase, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &geoIPDatabase{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readGeoIPDatabase(f)
+}
+
+// readGeoIPDatabase parses a GeoIP database.
+func readGeoIPDatabase(r io.Reader) (*geoIPDatabase, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+	db := &geoIPDatabase{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.AddContext(err, "failed to read GeoIP database")
+		}
+		_, subnet, err := net.ParseCIDR(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, errors.AddContext(err, "invalid subnet in GeoIP database")
+		}
+		db.entries = append(db.entries, geoIPEntry{
+			subnet: subnet,
+			region: strings.TrimSpace(record[1]),
+		})
+	}
+	return db, nil
+}
+
+// Region returns the region of a host, given the subnets it uses. If multiple
+// entries match, the most specific subnet wins. An empty string is returned
+// if the region is unknown.
+func (db *geoIPDatabase) Region(ipNets []string) string {
+	var region string
+	bestSize := -1
+	for _, ipNet := range ipNets {
+		ip, _, err := net.ParseCIDR(ipNet)
+		if err != nil {
+			continue
+		}
+		for _, e := range db.entries {
+			size, _ := e.subnet.Mask.Size()
+			if size > bestSize && e.subnet.Contains(ip) {
+				region = e.region
+				bestSize = size
+			}
+		}
+	}
+	return region
+}
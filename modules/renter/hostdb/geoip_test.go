@@ -0,0 +1,42 @@
+package hostdb
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGeoIPDatabase probes parsing and querying the GeoIP database.
+func TestGeoIPDatabase(t *testing.T) {
+	t.Parallel()
+
+	db, err := readGeoIPDatabase(strings.NewReader(`# subnet,region
+12.0.0.0/8, us
+12.34.0.0/16,eu
+2001:db8::/32,asia
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ipNets []string
+		region string
+	}{
+		{[]string{"12.1.2.0/24"}, "us"},
+		{[]string{"12.34.56.0/24"}, "eu"},
+		{[]string{"13.0.0.0/24", "2001:db8:1::/54"}, "asia"},
+		{[]string{"13.0.0.0/24"}, ""},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		if region := db.Region(test.ipNets); region != test.region {
+			t.Errorf("expected region '%v' for %v but got '%v'", test.region, test.ipNets, region)
+		}
+	}
+
+	// Invalid databases are rejected.
+	for _, s := range []string{"12.0.0.0/33,us", "12.0.0.0/8", "12.0.0.0/8,us,eu"} {
+		if _, err := readGeoIPDatabase(strings.NewReader(s)); err == nil {
+			t.Errorf("'%v' should be invalid", s)
+		}
+	}
+}
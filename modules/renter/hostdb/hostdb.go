@@ -200,6 +200,9 @@ type HostDB struct {
 	// filteredDomains tracks blocked domains for the hostdb.
 	filteredDomains *filteredDomains
 
+	// staticGeoIP is used to look up the regions of hosts.
+	staticGeoIP *geoIPDatabase
+
 	blockHeight types.BlockHeight
 	lastChange  modules.ConsensusChangeID
 }
@@ -361,6 +364,12 @@ func hostdbBlockingStartup(g modules.Gateway, cs modules.ConsensusSet, tpool mod
 		return nil, err
 	}
 
+	// Load the optional GeoIP database.
+	hdb.staticGeoIP, err = loadGeoIPDatabase(filepath.Join(persistDir, geoIPFilename))
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the logger.
 	logger, err := persist.NewFileLogger(filepath.Join(persistDir, "hostdb.log"))
 	if err != nil {
@@ -24,7 +24,9 @@ type HostAdjustments struct {
 	CollateralAdjustment       float64
 	DurationAdjustment         float64
 	InteractionAdjustment      float64
+	LatencyAdjustment          float64
 	PriceAdjustment            float64
+	RegionAdjustment           float64
 	StorageRemainingAdjustment float64
 	UptimeAdjustment           float64
 	VersionAdjustment          float64
@@ -76,7 +78,9 @@ func (h HostAdjustments) HostScoreBreakdown(totalScore types.Currency, ignoreAge
 		CollateralAdjustment:       h.CollateralAdjustment,
 		DurationAdjustment:         h.DurationAdjustment,
 		InteractionAdjustment:      h.InteractionAdjustment,
+		LatencyAdjustment:          h.LatencyAdjustment,
 		PriceAdjustment:            h.PriceAdjustment,
+		RegionAdjustment:           h.RegionAdjustment,
 		StorageRemainingAdjustment: h.StorageRemainingAdjustment,
 		UptimeAdjustment:           h.UptimeAdjustment,
 		VersionAdjustment:          h.VersionAdjustment,
@@ -94,7 +98,9 @@ func (h HostAdjustments) Score() types.Currency {
 		h.CollateralAdjustment *
 		h.DurationAdjustment *
 		h.InteractionAdjustment *
+		h.LatencyAdjustment *
 		h.PriceAdjustment *
+		h.RegionAdjustment *
 		h.StorageRemainingAdjustment *
 		h.UptimeAdjustment *
 		h.VersionAdjustment
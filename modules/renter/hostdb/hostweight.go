@@ -57,6 +57,11 @@ const (
 	// the bad points do not rack up very quickly.
 	interactionExponentiation = 10
 
+	// latencyExponentiation determines how heavily we penalize hosts for
+	// having a higher latency than the allowance's preferred latency. A host
+	// with twice the preferred latency gets a quarter of the score.
+	latencyExponentiation = 2
+
 	// priceExponentiationLarge is the number of times that the weight is
 	// divided by the price when the price is large relative to the allowance.
 	// The exponentiation is a lot higher because we care greatly about high
@@ -81,6 +86,10 @@ const (
 	// This is necessary to prevent exploits where a host gets an unreasonable
 	// score by putting it's price way too low.
 	priceFloor = 0.1
+
+	// regionPenalty is the adjustment of hosts which are not within one of the
+	// allowance's preferred regions.
+	regionPenalty = 0.1
 )
 
 // basePriceAdjustments will adjust the weight of the entry according to the prices
@@ -231,6 +240,17 @@ func (hdb *HostDB) interactionAdjustments(entry modules.HostDBEntry) float64 {
 	return math.Pow(ratio, interactionExponentiation)
 }
 
+// latencyAdjustments penalizes hosts with a higher measured latency than the
+// allowance's preferred latency. Hosts that haven't been measured yet are not
+// penalized.
+func latencyAdjustments(entry modules.HostDBEntry, allowance modules.Allowance) float64 {
+	if allowance.PreferredLatency == 0 || entry.Latency <= allowance.PreferredLatency {
+		return 1
+	}
+	ratio := float64(allowance.PreferredLatency) / float64(entry.Latency)
+	return math.Pow(ratio, latencyExponentiation)
+}
+
 // priceAdjustments will adjust the weight of the entry according to the prices
 // that it has set.
 //
@@ -346,6 +366,20 @@ func (hdb *HostDB) priceAdjustments(entry modules.HostDBEntry, allowance modules
 	return 1 / (smallWeight * largeWeight)
 }
 
+// regionAdjustments penalizes hosts which are not within one of the
+// allowance's preferred regions.
+func regionAdjustments(entry modules.HostDBEntry, allowance modules.Allowance) float64 {
+	if len(allowance.PreferredRegions) == 0 {
+		return 1
+	}
+	for _, region := range allowance.PreferredRegions {
+		if strings.EqualFold(region, entry.Region) {
+			return 1
+		}
+	}
+	return regionPenalty
+}
+
 // storageRemainingAdjustments adjusts the weight of the entry according to how
 // much storage it has remaining.
 func (hdb *HostDB) storageRemainingAdjustments(entry modules.HostDBEntry, allowance modules.Allowance) float64 {
@@ -577,7 +611,9 @@ func (hdb *HostDB) managedCalculateHostWeightFn(allowance modules.Allowance) hos
 			CollateralAdjustment:       hdb.collateralAdjustments(entry, allowance),
 			DurationAdjustment:         hdb.durationAdjustments(entry, allowance),
 			InteractionAdjustment:      hdb.interactionAdjustments(entry),
+			LatencyAdjustment:          latencyAdjustments(entry, allowance),
 			PriceAdjustment:            hdb.priceAdjustments(entry, allowance, txnFees),
+			RegionAdjustment:           regionAdjustments(entry, allowance),
 			StorageRemainingAdjustment: hdb.storageRemainingAdjustments(entry, allowance),
 			UptimeAdjustment:           hdb.uptimeAdjustments(entry),
 			VersionAdjustment:          versionAdjustments(entry),
@@ -746,3 +746,47 @@ func TestHostWeightAcceptContract(t *testing.T) {
 		t.Error("Entry2 should have smallest weight")
 	}
 }
+
+// TestHostWeightLatencyAndRegion checks that hosts with a higher latency than
+// the preferred latency and hosts outside of the preferred regions have a
+// lower score.
+func TestHostWeightLatencyAndRegion(t *testing.T) {
+	t.Parallel()
+	allowance := DefaultTestAllowance
+	entry := DefaultHostDBEntry
+
+	// Without preferences there are no adjustments.
+	entry.Latency = time.Second
+	entry.Region = "eu"
+	if latencyAdjustments(entry, allowance) != 1 || regionAdjustments(entry, allowance) != 1 {
+		t.Fatal("hosts shouldn't be adjusted without preferences")
+	}
+
+	// A host with twice the preferred latency gets a quarter of the score.
+	// Hosts within the preferred latency and unmeasured hosts aren't
+	// penalized.
+	allowance.PreferredLatency = 500 * time.Millisecond
+	if adj := latencyAdjustments(entry, allowance); adj != 0.25 {
+		t.Fatal("wrong latency adjustment", adj)
+	}
+	for _, latency := range []time.Duration{0, 100 * time.Millisecond, 500 * time.Millisecond} {
+		entry.Latency = latency
+		if adj := latencyAdjustments(entry, allowance); adj != 1 {
+			t.Fatal("wrong latency adjustment", latency, adj)
+		}
+	}
+
+	// Regions are matched case-insensitively.
+	allowance.PreferredRegions = []string{"us", "EU"}
+	if adj := regionAdjustments(entry, allowance); adj != 1 {
+		t.Fatal("wrong region adjustment", adj)
+	}
+	entry.Region = "asia"
+	if adj := regionAdjustments(entry, allowance); adj != regionPenalty {
+		t.Fatal("wrong region adjustment", adj)
+	}
+	entry.Region = ""
+	if adj := regionAdjustments(entry, allowance); adj != regionPenalty {
+		t.Fatal("wrong region adjustment", adj)
+	}
+}
@@ -229,6 +229,10 @@ func (hdb *HostDB) updateEntry(entry modules.HostDBEntry, netErr error) {
 		newEntry.HostExternalSettings = entry.HostExternalSettings
 		newEntry.IPNets = entry.IPNets
 		newEntry.LastIPNetChange = entry.LastIPNetChange
+		newEntry.Region = entry.Region
+		if entry.Latency != 0 {
+			newEntry.Latency = entry.Latency
+		}
 	} else {
 		newEntry = entry
 	}
@@ -388,6 +392,7 @@ func (hdb *HostDB) managedScanHost(entry modules.HostDBEntry) {
 	if err != nil {
 		hdb.staticLog.Debugln("mangedScanHost: failed to look up IP nets", err)
 	}
+	entry.Region = hdb.staticGeoIP.Region(entry.IPNets)
 
 	// Update historic interactions of entry if necessary
 	hdb.mu.Lock()
@@ -499,6 +504,13 @@ func (hdb *HostDB) managedScanHost(entry modules.HostDBEntry) {
 	if exists {
 		entry.NetAddress = oldEntry.NetAddress
 	}
+	// Update the host's latency. The latency is a moving average to smooth
+	// out the noise of individual measurements.
+	if success && exists && oldEntry.Latency != 0 {
+		entry.Latency = time.Duration(scanLatencyWeight*float64(latency) + (1-scanLatencyWeight)*float64(oldEntry.Latency))
+	} else if success {
+		entry.Latency = latency
+	}
 	// Update the host tree to have a new entry, including the new error. Then
 	// delete the entry from the scan map as the scan has been successful.
 	hdb.updateEntry(entry, err)
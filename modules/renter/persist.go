@@ -60,6 +60,37 @@ type (
 		MaxUploadSpeed   int64
 		UploadedBackups  []modules.UploadedBackup
 		SyncedContracts  []types.FileContractID
+
+		// PrunedBackups contains the UIDs of the backups that were pruned.
+		// They are ignored when synchronizing snapshots with hosts and are
+		// dropped from the hosts' snapshot tables.
+		PrunedBackups [][16]byte
+
+		// PinnedDirs contains the directories whose subtrees are repaired
+		// before any other directories.
+		PinnedDirs []modules.SiaPath
+
+		// ArchivedPaths contains the files and directories of the archive
+		// tier which are not repaired.
+		ArchivedPaths []modules.SiaPath
+
+		// ChunkCacheSize is the size limit of the on-disk cache of
+		// downloaded chunks. A limit of 0 disables the cache.
+		ChunkCacheSize uint64
+
+		// DownloadOverdrivePolicies contains the overdrive policies that were
+		// changed from their defaults.
+		DownloadOverdrivePolicies map[modules.DownloadRequestClass]modules.DownloadOverdrivePolicy
+
+		// AccountFundingPolicy is the renter-wide funding policy of the
+		// ephemeral accounts. HostAccountFundingPolicies contains the
+		// policies of individual hosts, keyed by the host's public key.
+		AccountFundingPolicy       modules.AccountFundingPolicy
+		HostAccountFundingPolicies map[string]modules.AccountFundingPolicy
+
+		// RepairSchedule restricts background repairs to a set of daily
+		// windows.
+		RepairSchedule modules.RepairSchedule
 	}
 )
 
@@ -108,6 +139,12 @@ func (r *Renter) managedLoadSettings() error {
 		return err
 	}
 
+	// Restore the pinned directories.
+	r.directoryHeap.managedSetPinnedDirs(r.persist.PinnedDirs)
+
+	// Restore the archived files and directories.
+	r.staticArchivedPaths.callSetPaths(r.persist.ArchivedPaths)
+
 	// Set the bandwidth limits on the contractor, which was already initialized
 	// without bandwidth limits.
 	return r.setBandwidthLimits(r.persist.MaxDownloadSpeed, r.persist.MaxUploadSpeed)
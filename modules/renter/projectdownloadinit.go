@@ -91,9 +91,9 @@ import (
 // worker set.
 const maxWaitUnresolvedWorkerUpdate = 10 * time.Millisecond
 
-// errNotEnoughWorkers is returned if the working set does not have enough
+// ErrNotEnoughWorkers is returned if the working set does not have enough
 // workers to successfully complete the download
-var errNotEnoughWorkers = errors.New("not enough workers to complete download")
+var ErrNotEnoughWorkers = errors.New("not enough workers to complete download")
 
 // pdcInitialWorker tracks information about a worker that is useful for
 // building the optimal set of launch workers.
@@ -465,7 +465,7 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 	}
 
 	if totalWorkers < ec.MinPieces() {
-		return nil, errors.AddContext(errNotEnoughWorkers, fmt.Sprintf("%v < %v", totalWorkers, ec.MinPieces()))
+		return nil, errors.AddContext(ErrNotEnoughWorkers, fmt.Sprintf("%v < %v", totalWorkers, ec.MinPieces()))
 	}
 
 	if isUnresolved {
@@ -291,7 +291,7 @@ func TestProjectDownloadChunk_createInitialWorkerSet(t *testing.T) {
 	// there's not enough workers, seeing as w1 and w2 return the same piece,
 	// rendering w1 unuseful.
 	iws, err := pdc.createInitialWorkerSet(wh)
-	if !errors.Contains(err, errNotEnoughWorkers) || iws != nil {
+	if !errors.Contains(err, ErrNotEnoughWorkers) || iws != nil {
 		t.Fatal("unexpected")
 	}
 
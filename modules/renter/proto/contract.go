@@ -1006,6 +1006,26 @@ func (cs *ContractSet) loadSafeContract(headerFileName, rootsFileName, refCountF
 			return errors.AddContext(err, "unable to commit the wal transactions during contractset recovery")
 		}
 	}
+
+	// Make sure the refcounter has a count for every sector of the contract.
+	// The two can disagree if the renter crashed while appending a sector, in
+	// which case the refcounter would fail with ErrInvalidSectorNumber later.
+	// Unapplied txns might still change the number of sectors, so the check
+	// is only performed once they are applied.
+	if rc != nil && len(sc.unappliedTxns) == 0 {
+		numRoots := uint64(sc.merkleRoots.len())
+		numCounts := rc.numSectors
+		if numCounts != numRoots {
+			if err := rc.callResize(numRoots); err != nil {
+				return errors.AddContext(err, "failed to resize refcounter to match the contract's sectors")
+			}
+			cs.refCounterRepairs = append(cs.refCounterRepairs, RefCounterRepair{
+				ID:         header.ID(),
+				OldSectors: numCounts,
+				NewSectors: numRoots,
+			})
+		}
+	}
 	if _, exists := cs.contracts[sc.header.ID()]; exists {
 		build.Critical("trying to overwrite existing contract")
 	}
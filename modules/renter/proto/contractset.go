@@ -29,6 +29,18 @@ type ContractSet struct {
 	mu         sync.Mutex
 	staticRL   *ratelimit.RateLimit
 	staticWal  *writeaheadlog.WAL
+
+	// refCounterRepairs are the refcounters which didn't match the number of
+	// sectors of their contract when the set was loaded.
+	refCounterRepairs []RefCounterRepair
+}
+
+// A RefCounterRepair describes a refcounter which was resized on startup to
+// match the number of sectors of its contract.
+type RefCounterRepair struct {
+	ID         types.FileContractID
+	OldSectors uint64
+	NewSectors uint64
 }
 
 // Acquire looks up the contract for the specified host key and locks it before
@@ -159,6 +171,14 @@ func (cs *ContractSet) PublicKey(id types.FileContractID) (crypto.PublicKey, boo
 	return safeContract.PublicKey(), true
 }
 
+// RefCounterRepairs returns the refcounters which were resized when the
+// contract set was loaded.
+func (cs *ContractSet) RefCounterRepairs() []RefCounterRepair {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return append([]RefCounterRepair(nil), cs.refCounterRepairs...)
+}
+
 // ViewAll returns the metadata of each contract in the set. The contracts are
 // not locked.
 func (cs *ContractSet) ViewAll() []modules.RenterContract {
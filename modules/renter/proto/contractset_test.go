@@ -350,6 +350,79 @@ func TestContractSetApplyInsertUpdateAtStartup(t *testing.T) {
 	}
 }
 
+// TestContractSetRefCounterValidation checks that refcounters which don't match
+// the number of sectors of their contract are resized on startup.
+func TestContractSetRefCounterValidation(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	testDir := build.TempDir(t.Name())
+	rl := ratelimit.NewRateLimit(0, 0, 0)
+	cs, err := NewContractSet(testDir, rl, modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := contractHeader{Transaction: types.Transaction{
+		FileContractRevisions: []types.FileContractRevision{{
+			ParentID:             types.FileContractID{1},
+			NewValidProofOutputs: []types.SiacoinOutput{{}, {}},
+			UnlockConditions: types.UnlockConditions{
+				PublicKeys: []types.SiaPublicKey{{}, {}},
+			},
+		}},
+	}}
+	roots := []crypto.Hash{{1}, {2}, {3}}
+	if _, err := cs.managedInsertContract(header, roots); err != nil {
+		t.Fatal(err)
+	}
+	if len(cs.RefCounterRepairs()) != 0 {
+		t.Fatal("new contract set shouldn't have repairs")
+	}
+	if err := cs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop the last count from the refcounter as if the renter crashed while
+	// appending a sector.
+	rcPath := filepath.Join(testDir, header.ID().String()+refCounterExtension)
+	if err := os.Truncate(rcPath, refCounterHeaderSize+2*2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload the set. The refcounter should be extended.
+	cs, err = NewContractSet(testDir, rl, modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repairs := cs.RefCounterRepairs()
+	if len(repairs) != 1 || repairs[0].ID != header.ID() || repairs[0].OldSectors != 2 || repairs[0].NewSectors != 3 {
+		t.Fatal("wrong repairs", repairs)
+	}
+	sc := cs.managedMustAcquire(t, header.ID())
+	count, err := sc.staticRC.callCount(2)
+	cs.Return(sc)
+	if err != nil || count != 1 {
+		t.Fatal("wrong count of the missing sector", count, err)
+	}
+	if err := cs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reloading again shouldn't require another repair.
+	cs, err = NewContractSet(testDir, rl, modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs.RefCounterRepairs()) != 0 {
+		t.Fatal("refcounter should have been repaired", cs.RefCounterRepairs())
+	}
+	if err := cs.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // TestInsertContractTotalCost tests that InsertContrct sets a good estimate for
 // TotalCost and TxnFee on recovered contracts.
 func TestInsertContractTotalCost(t *testing.T) {
@@ -352,6 +352,41 @@ func (rc *refCounter) callIncrement(secIdx uint64) (writeaheadlog.Update, error)
 	return createWriteAtUpdate(rc.filepath, secIdx, count), nil
 }
 
+// callResize changes the number of sector counts in the refcounter to numSec.
+// Missing counts are appended with a value of `1` and excess counts are
+// dropped from the end of the file.
+func (rc *refCounter) callResize(numSec uint64) (err error) {
+	if err := rc.callStartUpdate(); err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Compose(err, rc.callUpdateApplied())
+	}()
+	rc.mu.Lock()
+	numSectors := rc.numSectors
+	rc.mu.Unlock()
+
+	var updates []writeaheadlog.Update
+	if numSec < numSectors {
+		u, err := rc.callDropSectors(numSectors - numSec)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, u)
+	}
+	for secIdx := numSectors; secIdx < numSec; secIdx++ {
+		u, err := rc.callSetCount(secIdx, 1)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, u)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return rc.callCreateAndApplyTransaction(updates...)
+}
+
 // callSetCount sets the value of the reference counter of a given sector. The
 // sector is specified by its sequential number (secIdx).
 func (rc *refCounter) callSetCount(secIdx uint64, c uint16) (writeaheadlog.Update, error) {
@@ -554,6 +554,51 @@ func TestRefCounterLoadInvalidVersion(t *testing.T) {
 	}
 }
 
+// TestRefCounterResize tests that callResize extends and truncates the
+// refcounter.
+func TestRefCounterResize(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	rc := testPrepareRefCounter(2, t)
+
+	// Extending the refcounter should add counts of 1.
+	if err := rc.callResize(5); err != nil {
+		t.Fatal("Failed to extend the refcounter:", err)
+	}
+	if rc.numSectors != 5 {
+		t.Fatal("wrong number of sectors", rc.numSectors)
+	}
+	for secIdx := uint64(0); secIdx < 5; secIdx++ {
+		c, err := rc.callCount(secIdx)
+		if err != nil || c != 1 {
+			t.Fatal("wrong count", secIdx, c, err)
+		}
+	}
+
+	// Truncating the refcounter should drop the last counts.
+	if err := rc.callResize(3); err != nil {
+		t.Fatal("Failed to truncate the refcounter:", err)
+	}
+	if _, err := rc.callCount(3); !errors.Contains(err, ErrInvalidSectorNumber) {
+		t.Fatal("Expected ErrInvalidSectorNumber, got:", err)
+	}
+
+	// The changes should be on disk and the update session should be closed.
+	rcLoaded, err := loadRefCounter(rc.filepath, testWAL)
+	if err != nil {
+		t.Fatal("Failed to load the refcounter:", err)
+	}
+	if rcLoaded.numSectors != 3 {
+		t.Fatal("wrong number of sectors on disk", rcLoaded.numSectors)
+	}
+	if err := rc.callResize(3); err != nil {
+		t.Fatal("Failed to resize the refcounter to its current size:", err)
+	}
+}
+
 // TestRefCounterSetCount tests that the callSetCount method behaves correctly
 func TestRefCounterSetCount(t *testing.T) {
 	if testing.Short() {
@@ -0,0 +1,21 @@
+package renter
+
+import (
+	"gitlab.com/NebulousLabs/errors"
+)
+
+var (
+	// ErrReadOnly is returned if the user tries to upload data while the
+	// renter is in read-only mode.
+	ErrReadOnly = errors.New("renter is in read-only mode")
+)
+
+// managedCheckReadOnly returns ErrReadOnly if the renter is in read-only mode.
+// In read-only mode, no contracts are formed or renewed and no data is
+// uploaded, but files can still be downloaded and queried.
+func (r *Renter) managedCheckReadOnly() error {
+	if r.hostContractor.ReadOnly() {
+		return ErrReadOnly
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package renter
+
+import (
+	"bytes"
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/contractor"
+	"go.sia.tech/siad/types"
+)
+
+// TestRenterReadOnly checks that uploads and contract renewals are refused
+// while the renter is in read-only mode and accepted again once the mode is
+// disabled.
+func TestRenterReadOnly(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	rt, err := newRenterTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	r := rt.renter
+
+	source, err := rt.createZeroByteFileOnDisk()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upload := func() modules.FileUploadParams {
+		return modules.FileUploadParams{
+			Source:      source,
+			SiaPath:     modules.RandomSiaPath(),
+			ErasureCode: modules.NewRSSubCodeDefault(),
+			CipherType:  crypto.TypeDefaultRenter,
+		}
+	}
+	// Declare a helper to set the read-only mode through the settings.
+	setReadOnly := func(readOnly bool) {
+		settings, err := r.Settings()
+		if err != nil {
+			t.Fatal(err)
+		}
+		settings.ReadOnly = readOnly
+		if err := r.SetSettings(settings); err != nil {
+			t.Fatal(err)
+		}
+		settings, err = r.Settings()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if settings.ReadOnly != readOnly {
+			t.Fatal("read-only mode wasn't set", settings.ReadOnly, readOnly)
+		}
+	}
+	// Declare a helper to try every kind of upload.
+	uploadErrs := func() []error {
+		_, sessionErr := r.NewUploadSession(upload())
+		return []error{
+			r.Upload(upload()),
+			r.UploadStreamFromReader(upload(), bytes.NewReader(fastrand.Bytes(100))),
+			sessionErr,
+		}
+	}
+
+	// In read-only mode uploads and renewals are refused.
+	setReadOnly(true)
+	for i, err := range uploadErrs() {
+		if !errors.Contains(err, ErrReadOnly) {
+			t.Fatalf("upload %v: expected %v but got %v", i, ErrReadOnly, err)
+		}
+	}
+	_, _, err = r.hostContractor.RenewContract(nil, types.FileContractID{}, modules.ContractParams{}, nil, nil, nil, nil)
+	if !errors.Contains(err, contractor.ErrReadOnly) {
+		t.Fatalf("expected %v but got %v", contractor.ErrReadOnly, err)
+	}
+
+	// Once the mode is disabled, uploads are accepted. Uploads that need
+	// workers still fail since the renter doesn't have any contracts, but not
+	// because of the read-only mode.
+	setReadOnly(false)
+	for i, err := range uploadErrs() {
+		if errors.Contains(err, ErrReadOnly) {
+			t.Fatalf("upload %v: shouldn't be refused but got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package renter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+	"go.sia.tech/siad/persist"
+)
+
+const (
+	// maxFileReencodes is the number of completed file re-encodes the renter
+	// keeps track of.
+	maxFileReencodes = 100
+)
+
+var (
+	// errReencodeInProgress is returned if a file is re-encoded while a
+	// re-encode of the same file is still running.
+	errReencodeInProgress = errors.New("the file is already being re-encoded")
+
+	// errReencodeInterrupted is returned if the renter shuts down before a
+	// re-encode is done.
+	errReencodeInterrupted = errors.New("renter shut down before the file was re-encoded")
+
+	// errReencodeNoErasureCode is returned if a file is re-encoded without
+	// specifying the new erasure code.
+	errReencodeNoErasureCode = errors.New("no erasure code provided for re-encoding")
+
+	// errReencodeSameErasureCode is returned if a file is re-encoded to the
+	// erasure code it is already using.
+	errReencodeSameErasureCode = errors.New("the file already uses the provided erasure code")
+)
+
+type (
+	// fileReencodes keeps track of the renter's file re-encodes.
+	fileReencodes struct {
+		reencodes map[modules.FileReencodeID]*modules.FileReencodeStatus
+		mu        sync.Mutex
+	}
+
+	// reencodeReader wraps the streamer of the file that is re-encoded and
+	// updates the progress of the re-encode with every read.
+	reencodeReader struct {
+		staticChunkSize uint64
+		staticID        modules.FileReencodeID
+		staticReader    io.Reader
+		staticReencodes *fileReencodes
+	}
+)
+
+// newFileReencodes creates a new fileReencodes object.
+func newFileReencodes() *fileReencodes {
+	return &fileReencodes{
+		reencodes: make(map[modules.FileReencodeID]*modules.FileReencodeStatus),
+	}
+}
+
+// callAdd starts tracking a new re-encode. Completed re-encodes are pruned if
+// the renter tracks more than maxFileReencodes of them.
+func (fr *fileReencodes) callAdd(status modules.FileReencodeStatus) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	var completed []*modules.FileReencodeStatus
+	for _, re := range fr.reencodes {
+		if !re.Completed && re.SiaPath.Equals(status.SiaPath) {
+			return errReencodeInProgress
+		}
+		if re.Completed {
+			completed = append(completed, re)
+		}
+	}
+	if len(completed) >= maxFileReencodes {
+		sort.Slice(completed, func(i, j int) bool {
+			return completed[i].EndTime.Before(completed[j].EndTime)
+		})
+		for _, re := range completed[:len(completed)-maxFileReencodes+1] {
+			delete(fr.reencodes, re.ID)
+		}
+	}
+	fr.reencodes[status.ID] = &status
+	return nil
+}
+
+// callFinish marks a re-encode as completed.
+func (fr *fileReencodes) callFinish(id modules.FileReencodeID, err error) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	re, exists := fr.reencodes[id]
+	if !exists {
+		return
+	}
+	re.Completed = true
+	re.EndTime = time.Now()
+	if err != nil {
+		re.Error = err.Error()
+		return
+	}
+	re.BytesReencoded = re.Filesize
+	re.ChunksReencoded = re.NumChunks
+}
+
+// callProgress updates the progress of a re-encode after n bytes of the file
+// were downloaded and handed to the upload. A chunk counts as re-encoded once
+// all of its data was handed to the upload.
+func (fr *fileReencodes) callProgress(id modules.FileReencodeID, n, chunkSize uint64) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	re, exists := fr.reencodes[id]
+	if !exists {
+		return
+	}
+	re.BytesReencoded += n
+	re.ChunksReencoded = re.BytesReencoded / chunkSize
+}
+
+// callStatus returns the status of all tracked re-encodes, ordered from newest
+// to oldest.
+func (fr *fileReencodes) callStatus() []modules.FileReencodeStatus {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	statuses := make([]modules.FileReencodeStatus, 0, len(fr.reencodes))
+	for _, re := range fr.reencodes {
+		statuses = append(statuses, *re)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].StartTime.After(statuses[j].StartTime)
+	})
+	return statuses
+}
+
+// Read reads from the underlying streamer and records the progress.
+func (rr *reencodeReader) Read(b []byte) (int, error) {
+	n, err := rr.staticReader.Read(b)
+	if n > 0 {
+		rr.staticReencodes.callProgress(rr.staticID, uint64(n), rr.staticChunkSize)
+	}
+	return n, err
+}
+
+// FileReencodes returns the status of the renter's recent file re-encodes.
+func (r *Renter) FileReencodes() []modules.FileReencodeStatus {
+	return r.staticFileReencodes.callStatus()
+}
+
+// ReencodeFile starts converting a file to the provided erasure code in the
+// background. The file is streamed from the network and uploaded again with
+// the new erasure code to a temporary siapath. Once the upload is available,
+// the temporary file replaces the original one.
+func (r *Renter) ReencodeFile(siaPath modules.SiaPath, ec modules.ErasureCoder) (_ modules.FileReencodeStatus, err error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.FileReencodeStatus{}, err
+	}
+	defer r.tg.Done()
+	if err := r.managedCheckReadOnly(); err != nil {
+		return modules.FileReencodeStatus{}, err
+	}
+	if ec == nil {
+		return modules.FileReencodeStatus{}, errReencodeNoErasureCode
+	}
+
+	fileNode, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return modules.FileReencodeStatus{}, err
+	}
+	defer func() {
+		// The background thread closes the fileNode if the re-encode was
+		// started.
+		if err != nil {
+			err = errors.Compose(err, fileNode.Close())
+		}
+	}()
+	oldEC := fileNode.ErasureCode()
+	if oldEC.Identifier() == ec.Identifier() {
+		return modules.FileReencodeStatus{}, errReencodeSameErasureCode
+	}
+	if err := r.managedCheckUploadContracts(ec); err != nil {
+		return modules.FileReencodeStatus{}, err
+	}
+
+	// The re-encoded file uses the same cipher type as the original one.
+	chunkSize := (modules.SectorSize - fileNode.MasterKey().Type().Overhead()) * uint64(ec.MinPieces())
+	filesize := fileNode.Size()
+	status := modules.FileReencodeStatus{
+		ID:              modules.FileReencodeID(hex.EncodeToString(fastrand.Bytes(16))),
+		SiaPath:         siaPath,
+		OldDataPieces:   oldEC.MinPieces(),
+		OldParityPieces: oldEC.NumPieces() - oldEC.MinPieces(),
+		NewDataPieces:   ec.MinPieces(),
+		NewParityPieces: ec.NumPieces() - ec.MinPieces(),
+		Filesize:        filesize,
+		NumChunks:       (filesize + chunkSize - 1) / chunkSize,
+		StartTime:       time.Now(),
+	}
+	err = r.staticFileReencodes.callAdd(status)
+	if err != nil {
+		return modules.FileReencodeStatus{}, err
+	}
+	go r.threadedReencodeFile(status.ID, siaPath, fileNode, ec, chunkSize)
+	return status, nil
+}
+
+// managedReencodeFile uploads the data of the provided fileNode with the new
+// erasure code and replaces the file at siaPath with the upload.
+func (r *Renter) managedReencodeFile(id modules.FileReencodeID, siaPath modules.SiaPath, fileNode *filesystem.FileNode, ec modules.ErasureCoder, chunkSize uint64) (err error) {
+	dirSiaPath, err := siaPath.Dir()
+	if err != nil {
+		return err
+	}
+	tmpSiaPath, err := dirSiaPath.Join(fmt.Sprintf(".%v.reencode-%v", siaPath.Name(), persist.RandomSuffix()))
+	if err != nil {
+		return err
+	}
+
+	// Stream the file from the network into the upload.
+	streamer, err := r.StreamerByNode(fileNode, false)
+	if err != nil {
+		return errors.AddContext(err, "unable to create streamer for file")
+	}
+	defer func() {
+		err = errors.Compose(err, streamer.Close())
+	}()
+	rr := &reencodeReader{
+		staticChunkSize: chunkSize,
+		staticID:        id,
+		staticReader:    streamer,
+		staticReencodes: r.staticFileReencodes,
+	}
+	newFileNode, err := r.callUploadStreamFromReader(modules.FileUploadParams{
+		Source:      fileNode.LocalPath(),
+		SiaPath:     tmpSiaPath,
+		ErasureCode: ec,
+		CipherType:  fileNode.MasterKey().Type(),
+	}, rr)
+	if err != nil {
+		deleteErr := r.staticFileSystem.DeleteFile(tmpSiaPath)
+		if deleteErr != nil && !errors.Contains(deleteErr, filesystem.ErrNotExist) {
+			err = errors.Compose(err, deleteErr)
+		}
+		return errors.AddContext(err, "unable to upload re-encoded file")
+	}
+
+	// Carry over the metadata of the original file.
+	err = errors.Compose(newFileNode.SetMode(fileNode.Mode()), newFileNode.SetTags(fileNode.Tags()))
+	err = errors.Compose(err, newFileNode.Close())
+	if err != nil {
+		return errors.AddContext(err, "unable to update metadata of re-encoded file")
+	}
+
+	// Replace the file with the upload.
+	err = r.staticFileSystem.DeleteFile(siaPath)
+	if err != nil && !errors.Contains(err, filesystem.ErrNotExist) {
+		return errors.AddContext(err, "unable to delete previous version of the file")
+	}
+	if err := r.staticFileSystem.RenameFile(tmpSiaPath, siaPath); err != nil {
+		return errors.AddContext(err, "unable to replace file with re-encoded file")
+	}
+	_ = r.staticBubbleScheduler.callQueueBubble(dirSiaPath)
+	return nil
+}
+
+// threadedReencodeFile re-encodes a file in the background and records the
+// outcome of the re-encode.
+func (r *Renter) threadedReencodeFile(id modules.FileReencodeID, siaPath modules.SiaPath, fileNode *filesystem.FileNode, ec modules.ErasureCoder, chunkSize uint64) {
+	defer func() {
+		if err := fileNode.Close(); err != nil {
+			r.log.Printf("Unable to close re-encoded file %v: %v", siaPath, err)
+		}
+	}()
+	if err := r.tg.Add(); err != nil {
+		r.staticFileReencodes.callFinish(id, errReencodeInterrupted)
+		return
+	}
+	defer r.tg.Done()
+	err := r.managedReencodeFile(id, siaPath, fileNode, ec, chunkSize)
+	if err != nil {
+		r.log.Printf("WARN: re-encode of %v failed: %v", siaPath, err)
+	}
+	r.staticFileReencodes.callFinish(id, err)
+}
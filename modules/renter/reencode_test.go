@@ -0,0 +1,106 @@
+package renter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/modules"
+)
+
+// TestFileReencodes is a unit test for tracking file re-encodes.
+func TestFileReencodes(t *testing.T) {
+	t.Parallel()
+
+	fr := newFileReencodes()
+	siaPath, err := modules.NewSiaPath("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	// Add a re-encode of a file with 3 chunks of 10 bytes.
+	err = fr.callAdd(modules.FileReencodeStatus{ID: "1", SiaPath: siaPath, Filesize: 25, NumChunks: 3, StartTime: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the file through a reencodeReader and check that the progress is
+	// updated.
+	data := fastrand.Bytes(25)
+	rr := &reencodeReader{
+		staticChunkSize: 10,
+		staticID:        "1",
+		staticReader:    bytes.NewReader(data[:15]),
+		staticReencodes: fr,
+	}
+	read, err := ioutil.ReadAll(rr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(read, data[:15]) {
+		t.Fatal("reencodeReader returned wrong data")
+	}
+	s := fr.callStatus()[0]
+	if s.BytesReencoded != 15 || s.ChunksReencoded != 1 {
+		t.Fatal("wrong progress", s.BytesReencoded, s.ChunksReencoded)
+	}
+
+	// Re-encoding the same file again should fail while the first re-encode
+	// is in progress.
+	err = fr.callAdd(modules.FileReencodeStatus{ID: "2", SiaPath: siaPath})
+	if !errors.Contains(err, errReencodeInProgress) {
+		t.Fatal("expected errReencodeInProgress", err)
+	}
+
+	// Finishing successfully marks the partial last chunk as re-encoded.
+	fr.callFinish("1", nil)
+	s = fr.callStatus()[0]
+	if !s.Completed || s.EndTime.IsZero() || s.Error != "" {
+		t.Fatal("re-encode wasn't finished correctly", s)
+	}
+	if s.BytesReencoded != 25 || s.ChunksReencoded != 3 {
+		t.Fatal("wrong progress", s.BytesReencoded, s.ChunksReencoded)
+	}
+
+	// Once finished, the file can be re-encoded again. A failed re-encode
+	// keeps its progress.
+	err = fr.callAdd(modules.FileReencodeStatus{ID: "2", SiaPath: siaPath, Filesize: 25, NumChunks: 3, StartTime: now.Add(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr.callProgress("2", 5, 10)
+	fr.callFinish("2", errReencodeInterrupted)
+	statuses := fr.callStatus()
+	if len(statuses) != 2 || statuses[0].ID != "2" {
+		t.Fatal("statuses should be ordered from newest to oldest", statuses)
+	}
+	s = statuses[0]
+	if s.Error != errReencodeInterrupted.Error() || s.BytesReencoded != 5 || s.ChunksReencoded != 0 {
+		t.Fatal("wrong status of failed re-encode", s)
+	}
+
+	// Updating unknown re-encodes is a no-op.
+	fr.callProgress("unknown", 10, 10)
+	fr.callFinish("unknown", nil)
+
+	// Completed re-encodes are pruned.
+	for i := 0; i < maxFileReencodes; i++ {
+		sp, err := modules.NewSiaPath(fmt.Sprint("file", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := modules.FileReencodeID(fmt.Sprint("id", i))
+		if err := fr.callAdd(modules.FileReencodeStatus{ID: id, SiaPath: sp}); err != nil {
+			t.Fatal(err)
+		}
+		fr.callFinish(id, nil)
+	}
+	if n := len(fr.callStatus()); n != maxFileReencodes {
+		t.Fatal("wrong number of re-encodes after pruning", n)
+	}
+}
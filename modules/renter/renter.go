@@ -93,6 +93,23 @@ type hostContractor interface {
 	// ChurnStatus returns contract churn stats for the current period.
 	ChurnStatus() modules.ContractorChurnStatus
 
+	// MigrationPlan returns the contractor's migrations in the current
+	// period, including the ones that are deferred by the churn budget.
+	MigrationPlan() modules.ContractorMigrationPlan
+
+	// HostLists returns the contractor's host allowlist and denylist.
+	HostLists() modules.ContractorHostLists
+
+	// SetHostLists replaces the contractor's host allowlist and denylist.
+	SetHostLists(modules.ContractorHostLists) error
+
+	// ReadOnly returns whether the contractor is in read-only mode.
+	ReadOnly() bool
+
+	// SetReadOnly enables or disables the read-only mode in which no
+	// contracts are formed or renewed.
+	SetReadOnly(bool) error
+
 	// ContractUtility returns the utility field for a given contract, along
 	// with a bool indicating if it exists.
 	ContractUtility(types.SiaPublicKey) (modules.ContractUtility, bool)
@@ -255,9 +272,22 @@ type Renter struct {
 	repairLog                          *persist.Logger
 	staticAccountManager               *accountManager
 	staticAlerter                      *modules.GenericAlerter
+	staticArchivedPaths                *archivedPaths
+	staticChunkCache                   *chunkCache
+	staticEvents                       *renterEvents
+	staticSpendingHistory              *spendingHistory
+	staticBackupSchedule               *backupSchedule
+	staticDirDeletions                 *dirDeletions
+	staticFileDefrag                   *fileDefrag
+	staticFileReencodes                *fileReencodes
 	staticFileSystem                   *filesystem.FileSystem
 	staticFuseManager                  renterFuseManager
+	staticOverdriveStats               *downloadOverdriveStats
+	staticRepairSchedule               *repairSchedule
+	staticRestoreDrills                *restoreDrills
 	staticStreamBufferSet              *streamBufferSet
+	staticUploadSessions               *uploadSessions
+	staticWorkerJobHistory             *workerJobHistory
 	tg                                 threadgroup.ThreadGroup
 	tpool                              modules.TransactionPool
 	wal                                *writeaheadlog.WAL
@@ -604,12 +634,31 @@ func (r *Renter) SetSettings(s modules.RenterSettings) error {
 	if s.MaxDownloadSpeed < 0 || s.MaxUploadSpeed < 0 {
 		return errors.New("bandwidth limits cannot be negative")
 	}
+	if err := validateAccountFundingPolicy(s.AccountFunding); err != nil {
+		return err
+	}
+	if err := s.RepairSchedule.Validate(); err != nil {
+		return err
+	}
+
+	// Set the read-only mode before the allowance so that no contracts are
+	// formed for the new allowance.
+	if s.ReadOnly {
+		if err := r.hostContractor.SetReadOnly(true); err != nil {
+			return err
+		}
+	}
 
 	// Set allowance.
 	err := r.hostContractor.SetAllowance(s.Allowance)
 	if err != nil {
 		return err
 	}
+	if !s.ReadOnly {
+		if err := r.hostContractor.SetReadOnly(false); err != nil {
+			return err
+		}
+	}
 
 	// Set IPViolationsCheck
 	r.hostDB.SetIPViolationCheck(s.IPViolationCheck)
@@ -620,15 +669,26 @@ func (r *Renter) SetSettings(s modules.RenterSettings) error {
 		return err
 	}
 
+	// Set the size of the chunk cache. The new limit applies even if evicted
+	// chunks couldn't be removed from disk.
+	err = r.staticChunkCache.callSetMaxSize(s.ChunkCacheSize)
+	if err != nil {
+		r.log.Println("WARN: failed to remove evicted chunks from chunk cache:", err)
+	}
+
 	// Save the changes.
 	id := r.mu.Lock()
+	r.persist.AccountFundingPolicy = s.AccountFunding
+	r.persist.ChunkCacheSize = s.ChunkCacheSize
 	r.persist.MaxDownloadSpeed = s.MaxDownloadSpeed
 	r.persist.MaxUploadSpeed = s.MaxUploadSpeed
+	r.persist.RepairSchedule = s.RepairSchedule
 	err = r.saveSync()
 	r.mu.Unlock(id)
 	if err != nil {
 		return err
 	}
+	r.staticRepairSchedule.callSetSchedule(s.RepairSchedule)
 
 	// Update the worker pool so that the changes are immediately apparent to
 	// users.
@@ -772,6 +832,27 @@ func (r *Renter) ContractorChurnStatus() modules.ContractorChurnStatus {
 	return r.hostContractor.ChurnStatus()
 }
 
+// ContractorMigrationPlan returns the contractor's migrations in the current
+// period, including the ones that are deferred by the churn budget.
+func (r *Renter) ContractorMigrationPlan() modules.ContractorMigrationPlan {
+	return r.hostContractor.MigrationPlan()
+}
+
+// ContractorHostLists returns the contractor's host allowlist and denylist.
+func (r *Renter) ContractorHostLists() modules.ContractorHostLists {
+	return r.hostContractor.HostLists()
+}
+
+// SetContractorHostLists replaces the contractor's host allowlist and
+// denylist.
+func (r *Renter) SetContractorHostLists(lists modules.ContractorHostLists) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	return r.hostContractor.SetHostLists(lists)
+}
+
 // InitRecoveryScan starts scanning the whole blockchain for recoverable
 // contracts within a separate thread.
 func (r *Renter) InitRecoveryScan() error {
@@ -817,11 +898,19 @@ func (r *Renter) Settings() (modules.RenterSettings, error) {
 		return modules.RenterSettings{}, errors.AddContext(err, "error getting IPViolationsCheck:")
 	}
 	paused, endTime := r.uploadHeap.managedPauseStatus()
+	id := r.mu.RLock()
+	accountFunding := r.persist.AccountFundingPolicy
+	repairSchedule := r.persist.RepairSchedule
+	r.mu.RUnlock(id)
 	return modules.RenterSettings{
+		AccountFunding:   accountFunding,
 		Allowance:        r.hostContractor.Allowance(),
+		ChunkCacheSize:   r.staticChunkCache.callStats().MaxSize,
 		IPViolationCheck: enabled,
 		MaxDownloadSpeed: download,
 		MaxUploadSpeed:   upload,
+		ReadOnly:         r.hostContractor.ReadOnly(),
+		RepairSchedule:   repairSchedule,
 		UploadsStatus: modules.UploadsStatus{
 			Paused:       paused,
 			PauseEndTime: endTime,
@@ -836,6 +925,9 @@ func (r *Renter) ProcessConsensusChange(cc modules.ConsensusChange) {
 	r.mu.Unlock(id)
 	if cc.Synced {
 		_ = r.tg.Launch(r.staticWorkerPool.callUpdate)
+		_ = r.tg.Launch(func() {
+			r.managedRecordSpending(cc.BlockHeight)
+		})
 	}
 }
 
@@ -908,10 +1000,19 @@ func renterBlockingStartup(g modules.Gateway, cs modules.ConsensusSet, tpool mod
 		},
 		directoryHeap: directoryHeap{
 			heapDirectories: make(map[modules.SiaPath]*directory),
+			pinnedDirs:      make(map[modules.SiaPath]struct{}),
 		},
 
 		downloadHistory: make(map[modules.DownloadID]*download),
 
+		staticArchivedPaths:  newArchivedPaths(),
+		staticEvents:         newRenterEvents(),
+		staticDirDeletions:   newDirDeletions(),
+		staticFileDefrag:     &fileDefrag{},
+		staticFileReencodes:  newFileReencodes(),
+		staticOverdriveStats: newDownloadOverdriveStats(),
+		staticRepairSchedule: &repairSchedule{},
+
 		cs:             cs,
 		deps:           deps,
 		g:              g,
@@ -979,6 +1080,33 @@ func renterBlockingStartup(g modules.Gateway, cs modules.ConsensusSet, tpool mod
 		return nil, err
 	}
 
+	r.staticBackupSchedule, err = newBackupSchedule(r.persistDir)
+	if err != nil {
+		return nil, err
+	}
+	r.staticRestoreDrills, err = newRestoreDrills(r.persistDir)
+	if err != nil {
+		return nil, err
+	}
+	r.staticWorkerJobHistory, err = newWorkerJobHistory(r.persistDir)
+	if err != nil {
+		return nil, err
+	}
+	go r.threadedSaveWorkerJobHistory()
+	r.staticUploadSessions, err = newUploadSessions(r.persistDir)
+	if err != nil {
+		return nil, err
+	}
+	r.staticChunkCache, err = newChunkCache(filepath.Join(r.persistDir, chunkCacheDir), r.persist.ChunkCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	r.staticRepairSchedule.callSetSchedule(r.persist.RepairSchedule)
+	r.staticSpendingHistory, err = newSpendingHistory(r.persistDir)
+	if err != nil {
+		return nil, err
+	}
+
 	// After persist is initialized, create the worker pool.
 	r.staticWorkerPool = r.newWorkerPool()
 
@@ -1039,6 +1167,14 @@ func renterAsyncStartup(r *Renter, cs modules.ConsensusSet) error {
 		go r.threadedUploadAndRepair()
 		go r.threadedStuckFileLoop()
 	}
+	// Spin up the restore drills.
+	go r.threadedRestoreDrillLoop()
+	// Spin up the siafile defragmentation.
+	go r.threadedFileDefragLoop()
+	// Spin up the repair schedule.
+	go r.threadedRepairScheduleLoop()
+	// Spin up the backup schedule.
+	go r.threadedBackupScheduleLoop()
 	// Spin up the snapshot synchronization thread.
 	if !r.deps.Disrupt("DisableSnapshotSync") {
 		go r.threadedSynchronizeSnapshots()
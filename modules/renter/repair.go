@@ -53,6 +53,11 @@ func (r *Renter) managedAddRandomStuckChunks(hosts map[string]struct{}) ([]modul
 			return dirSiaPaths, errors.AddContext(err, "unable to get random stuck file in dir "+dirSiaPath.String())
 		}
 
+		// Archived files are not repaired.
+		if r.staticArchivedPaths.callIsArchived(siaPath) {
+			continue
+		}
+
 		// Add stuck chunk to upload heap and signal repair needed
 		err = r.managedBuildAndPushRandomChunk(siaPath, hosts, targetStuckChunks, r.repairMemoryManager)
 		if err != nil {
@@ -92,7 +97,7 @@ func (r *Renter) managedAddStuckChunksFromStuckStack(hosts map[string]struct{})
 
 		// Add stuck chunks to uploadHeap
 		err := r.managedAddStuckChunksToHeap(siaPath, hosts, offline, goodForRenew)
-		if err != nil && !errors.Contains(err, errNoStuckChunks) {
+		if err != nil && !errors.Contains(err, errNoStuckChunks) && !errors.Contains(err, errFileArchived) {
 			return dirSiaPaths, errors.AddContext(err, "unable to add stuck chunks to heap")
 		}
 
@@ -114,6 +119,11 @@ func (r *Renter) managedAddStuckChunksFromStuckStack(hosts map[string]struct{})
 // managedAddStuckChunksToHeap tries to add as many stuck chunks from a siafile
 // to the upload heap as possible
 func (r *Renter) managedAddStuckChunksToHeap(siaPath modules.SiaPath, hosts map[string]struct{}, offline, goodForRenew map[string]bool) (err error) {
+	// Archived files are not repaired.
+	if r.staticArchivedPaths.callIsArchived(siaPath) {
+		return errFileArchived
+	}
+
 	// Open File
 	sf, err := r.staticFileSystem.OpenSiaFile(siaPath)
 	if err != nil {
@@ -470,6 +480,11 @@ func (r *Renter) threadedStuckFileLoop() {
 			return
 		}
 
+		// Wait until the repair schedule allows repairs.
+		if !r.managedBlockUntilRepairWindow() {
+			return
+		}
+
 		// As we add stuck chunks to the upload heap we want to remember the
 		// directories they came from so we can call bubble to update the
 		// filesystem
@@ -784,7 +799,8 @@ func (r *Renter) managedUpdateFileMetadata(sf *filesystem.FileNode, offlineMap,
 		return errors.AddContext(err, "WARN: Could not update cached redundancy")
 	}
 	// Update cached health values.
-	_, _, _, _, _, _, _ = sf.Health(offlineMap, goodForRenew)
+	md := sf.Metadata()
+	health, _, _, _, _, _, _ := sf.Health(offlineMap, goodForRenew)
 	// Set the LastHealthCheckTime
 	sf.SetLastHealthCheckTime()
 	// Update the cached expiration of the siafile.
@@ -794,5 +810,14 @@ func (r *Renter) managedUpdateFileMetadata(sf *filesystem.FileNode, offlineMap,
 	if err != nil {
 		return err
 	}
+	// Report health changes of fully uploaded files to the event stream.
+	if md.CachedUploadProgress >= 100 && health != md.CachedHealth {
+		r.staticEvents.callAddFileEvent(modules.RenterEvent{
+			Type:           modules.RenterEventFileHealthChanged,
+			SiaPath:        r.staticFileSystem.FileSiaPath(sf),
+			Health:         health,
+			PreviousHealth: md.CachedHealth,
+		}, sf.UID())
+	}
 	return nil
 }
@@ -0,0 +1,148 @@
+package renter
+
+import (
+	"sync"
+	"time"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+)
+
+var (
+	// repairScheduleCheckInterval is the interval at which the renter checks
+	// whether a repair window has opened.
+	repairScheduleCheckInterval = build.Select(build.Var{
+		Dev:      10 * time.Second,
+		Standard: time.Minute,
+		Testnet:  time.Minute,
+		Testing:  time.Second,
+	}).(time.Duration)
+)
+
+// repairSchedule keeps track of the renter's repair windows and of the
+// bandwidth used by repairs during the current window.
+type repairSchedule struct {
+	schedule modules.RepairSchedule
+
+	// windowStart is the start of the window in which bandwidthUsed was
+	// recorded. The bandwidth is reset when a new window starts.
+	windowStart   time.Time
+	bandwidthUsed uint64
+
+	mu sync.Mutex
+}
+
+// repairScheduleStatus returns the status of a schedule at time t, given the
+// bandwidth that was used since windowStart.
+func repairScheduleStatus(schedule modules.RepairSchedule, t, windowStart time.Time, bandwidthUsed uint64) modules.RepairScheduleStatus {
+	if len(schedule.Windows) == 0 {
+		return modules.RepairScheduleStatus{
+			Active:        true,
+			BandwidthUsed: bandwidthUsed,
+		}
+	}
+	var status modules.RepairScheduleStatus
+	for _, rw := range schedule.Windows {
+		if start, end, ok := rw.Occurrence(t); ok && (status.WindowStart.IsZero() || start.Before(status.WindowStart)) {
+			status.WindowStart, status.WindowEnd = start, end
+		}
+		if next := rw.NextStart(t); status.NextWindowStart.IsZero() || next.Before(status.NextWindowStart) {
+			status.NextWindowStart = next
+		}
+	}
+	if !status.WindowStart.IsZero() && status.WindowStart.Equal(windowStart) {
+		status.BandwidthUsed = bandwidthUsed
+	}
+	withinBudget := schedule.BandwidthBudget == 0 || status.BandwidthUsed < schedule.BandwidthBudget
+	status.Active = !status.WindowStart.IsZero() && withinBudget
+	return status
+}
+
+// callSetSchedule replaces the schedule. The bandwidth used during the current
+// window is kept.
+func (rs *repairSchedule) callSetSchedule(schedule modules.RepairSchedule) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.schedule = schedule
+}
+
+// callStatus returns the current status of the schedule.
+func (rs *repairSchedule) callStatus() modules.RepairScheduleStatus {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return repairScheduleStatus(rs.schedule, time.Now(), rs.windowStart, rs.bandwidthUsed)
+}
+
+// callAddBandwidth adds the bandwidth used by a repair to the current window.
+func (rs *repairSchedule) callAddBandwidth(n uint64) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	status := repairScheduleStatus(rs.schedule, time.Now(), rs.windowStart, rs.bandwidthUsed)
+	if !status.WindowStart.Equal(rs.windowStart) {
+		rs.windowStart = status.WindowStart
+		rs.bandwidthUsed = 0
+	}
+	rs.bandwidthUsed += n
+}
+
+// callRepairAllowed returns whether repairs may currently run.
+func (rs *repairSchedule) callRepairAllowed() bool {
+	return rs.callStatus().Active
+}
+
+// isScheduledRepair returns whether the upload of a chunk is a repair which is
+// restricted by the repair schedule. Chunks of new uploads and streamed chunks
+// are never restricted. The chunk's mutex needs to be held.
+func (uc *unfinishedUploadChunk) isScheduledRepair() bool {
+	return uc.sourceReader == nil && (uc.staticRepair || uc.stuckRepair)
+}
+
+// managedBlockUntilRepairWindow blocks until the repair schedule allows
+// repairs. It returns false if the renter shut down before that.
+func (r *Renter) managedBlockUntilRepairWindow() bool {
+	for !r.staticRepairSchedule.callRepairAllowed() {
+		select {
+		case <-r.tg.StopChan():
+			return false
+		case <-time.After(repairScheduleCheckInterval):
+		}
+	}
+	return true
+}
+
+// threadedRepairScheduleLoop wakes up the repair loops whenever a repair
+// window opens, so that repairs which were skipped while the window was closed
+// start right away.
+func (r *Renter) threadedRepairScheduleLoop() {
+	if err := r.tg.Add(); err != nil {
+		return
+	}
+	defer r.tg.Done()
+	active := r.staticRepairSchedule.callRepairAllowed()
+	for {
+		select {
+		case <-r.tg.StopChan():
+			return
+		case <-time.After(repairScheduleCheckInterval):
+		}
+		wasActive := active
+		active = r.staticRepairSchedule.callRepairAllowed()
+		if wasActive || !active {
+			continue
+		}
+		select {
+		case r.uploadHeap.repairNeeded <- struct{}{}:
+		default:
+		}
+		select {
+		case r.uploadHeap.stuckChunkFound <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// RepairScheduleStatus returns whether the renter's repairs may currently run
+// according to its repair schedule.
+func (r *Renter) RepairScheduleStatus() modules.RepairScheduleStatus {
+	return r.staticRepairSchedule.callStatus()
+}
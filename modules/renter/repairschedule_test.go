@@ -0,0 +1,60 @@
+package renter
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/modules"
+)
+
+// TestRepairScheduleStatus tests computing the status of a repair schedule.
+func TestRepairScheduleStatus(t *testing.T) {
+	day := time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC)
+	rw1, err := modules.ParseRepairWindow("02:00-06:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw2, err := modules.ParseRepairWindow("22:00-01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without windows, repairs can always run.
+	status := repairScheduleStatus(modules.RepairSchedule{}, day, time.Time{}, 0)
+	if !status.Active || !status.NextWindowStart.IsZero() {
+		t.Fatal("repairs should always be allowed without windows", status)
+	}
+
+	// Outside of the windows, the next window is the closest one.
+	schedule := modules.RepairSchedule{Windows: []modules.RepairWindow{rw1, rw2}, BandwidthBudget: 100}
+	status = repairScheduleStatus(schedule, day.Add(12*time.Hour), time.Time{}, 0)
+	if status.Active || !status.WindowStart.IsZero() || !status.NextWindowStart.Equal(day.Add(22*time.Hour)) {
+		t.Fatal("wrong status outside of windows", status)
+	}
+
+	// Within a window, the bandwidth of the same window is reported.
+	windowStart := day.Add(2 * time.Hour)
+	status = repairScheduleStatus(schedule, day.Add(3*time.Hour), windowStart, 50)
+	if !status.Active || !status.WindowStart.Equal(windowStart) || !status.WindowEnd.Equal(day.Add(6*time.Hour)) {
+		t.Fatal("wrong status within window", status)
+	}
+	if status.BandwidthUsed != 50 || !status.NextWindowStart.Equal(day.Add(22*time.Hour)) {
+		t.Fatal("wrong bandwidth or next window", status)
+	}
+
+	// Once the budget is used up, repairs stop until the next window.
+	status = repairScheduleStatus(schedule, day.Add(3*time.Hour), windowStart, 100)
+	if status.Active {
+		t.Fatal("repairs shouldn't run after the budget is used up", status)
+	}
+	status = repairScheduleStatus(schedule, day.Add(23*time.Hour), windowStart, 100)
+	if !status.Active || status.BandwidthUsed != 0 {
+		t.Fatal("the budget should reset in a new window", status)
+	}
+
+	// The window spanning midnight is active after midnight.
+	status = repairScheduleStatus(schedule, day.Add(30*time.Minute), time.Time{}, 0)
+	if !status.Active || !status.WindowStart.Equal(day.Add(-2*time.Hour)) {
+		t.Fatal("wrong status after midnight", status)
+	}
+}
@@ -0,0 +1,409 @@
+package renter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+)
+
+const (
+	// maxRestoreDrillResults is the number of restore drill results the
+	// renter keeps track of.
+	maxRestoreDrillResults = 100
+
+	// restoreDrillsDir is the name of the scratch directory within the
+	// renter's persist dir that restore drills download files into.
+	restoreDrillsDir = "restoredrills"
+
+	// restoreDrillsFilename is the name of the file the renter persists the
+	// restore drill settings, checksums and results in.
+	restoreDrillsFilename = "restoredrills.json"
+)
+
+var (
+	// restoreDrillsMetadata is the metadata of the restore drills persist
+	// file.
+	restoreDrillsMetadata = persist.Metadata{
+		Header:  "Renter Restore Drills",
+		Version: "1.5.5",
+	}
+
+	// defaultRestoreDrillInterval is the interval between restore drills if
+	// the user doesn't specify one.
+	defaultRestoreDrillInterval = build.Select(build.Var{
+		Dev:      time.Minute * 10,
+		Standard: time.Hour * 24,
+		Testnet:  time.Hour * 24,
+		Testing:  time.Second * 5,
+	}).(time.Duration)
+
+	// minRestoreDrillInterval is the minimum interval between restore drills
+	// to avoid the renter spending most of its bandwidth on drills.
+	minRestoreDrillInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: time.Hour,
+		Testnet:  time.Hour,
+		Testing:  time.Second,
+	}).(time.Duration)
+
+	// restoreDrillCheckInterval is how often the restore drill loop checks
+	// whether a drill is due.
+	restoreDrillCheckInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: time.Minute * 10,
+		Testnet:  time.Minute * 10,
+		Testing:  time.Second,
+	}).(time.Duration)
+)
+
+var (
+	// errNoRestoreDrillFiles is returned if there are no files a restore drill
+	// could be run on.
+	errNoRestoreDrillFiles = errors.New("no recoverable files available for a restore drill")
+
+	// errRestoreDrillInProgress is returned if a restore drill is started while
+	// another one is still running.
+	errRestoreDrillInProgress = errors.New("a restore drill is already in progress")
+
+	// errRestoreDrillChecksumMismatch is returned if the restored data doesn't
+	// match the reference checksum.
+	errRestoreDrillChecksumMismatch = errors.New("checksum of restored data doesn't match the reference checksum")
+
+	// errRestoreDrillIntervalTooLow is returned if the user tries to set an
+	// interval below minRestoreDrillInterval.
+	errRestoreDrillIntervalTooLow = errors.New("restore drill interval is too low")
+)
+
+type (
+	// restoreDrills keeps track of the renter's restore drills.
+	restoreDrills struct {
+		inProgress bool
+		persist    restoreDrillsPersist
+
+		staticPersistPath string
+		mu                sync.Mutex
+	}
+
+	// restoreDrillsPersist contains the persisted restore drill data.
+	restoreDrillsPersist struct {
+		Settings  modules.RestoreDrillSettings
+		LastDrill time.Time
+		Results   []modules.RestoreDrillResult
+
+		// Checksums maps the UIDs of siafiles to the checksum of their
+		// contents.
+		Checksums map[string]crypto.Hash
+	}
+)
+
+// newRestoreDrills loads the restore drills from disk or initializes them
+// with default settings.
+func newRestoreDrills(persistDir string) (*restoreDrills, error) {
+	rd := &restoreDrills{
+		persist: restoreDrillsPersist{
+			Settings: modules.RestoreDrillSettings{
+				Interval: defaultRestoreDrillInterval,
+			},
+			Checksums: make(map[string]crypto.Hash),
+		},
+		staticPersistPath: filepath.Join(persistDir, restoreDrillsFilename),
+	}
+	err := persist.LoadJSON(restoreDrillsMetadata, &rd.persist, rd.staticPersistPath)
+	if os.IsNotExist(err) {
+		return rd, nil
+	}
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to load restore drills")
+	}
+	if rd.persist.Checksums == nil {
+		rd.persist.Checksums = make(map[string]crypto.Hash)
+	}
+	return rd, nil
+}
+
+// addResult adds a result to the restore drills, dropping the oldest one if
+// necessary.
+func (rd *restoreDrills) addResult(res modules.RestoreDrillResult) {
+	rd.persist.LastDrill = res.Timestamp
+	rd.persist.Results = append(rd.persist.Results, res)
+	if len(rd.persist.Results) > maxRestoreDrillResults {
+		rd.persist.Results = rd.persist.Results[len(rd.persist.Results)-maxRestoreDrillResults:]
+	}
+}
+
+// due returns whether a restore drill should be run at the given time.
+func (rd *restoreDrills) due(now time.Time) bool {
+	if !rd.persist.Settings.Enabled || rd.inProgress {
+		return false
+	}
+	return now.Sub(rd.persist.LastDrill) >= rd.persist.Settings.Interval
+}
+
+// save persists the restore drills.
+func (rd *restoreDrills) save() error {
+	return persist.SaveJSON(restoreDrillsMetadata, rd.persist, rd.staticPersistPath)
+}
+
+// hashFile computes the checksum of the file at the given path.
+func hashFile(path string) (_ crypto.Hash, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return crypto.Hash{}, err
+	}
+	defer func() {
+		err = errors.Compose(err, f.Close())
+	}()
+	h := crypto.NewHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return crypto.Hash{}, err
+	}
+	var checksum crypto.Hash
+	copy(checksum[:], h.Sum(nil))
+	return checksum, nil
+}
+
+// managedRandomRestoreDrillFile picks a random recoverable file from the
+// user's files.
+func (r *Renter) managedRandomRestoreDrillFile() (modules.FileInfo, error) {
+	var mu sync.Mutex
+	var files []modules.FileInfo
+	err := r.FileList(modules.UserFolder, true, true, func(fi modules.FileInfo) {
+		if !fi.Recoverable || fi.Filesize == 0 {
+			return
+		}
+		mu.Lock()
+		files = append(files, fi)
+		mu.Unlock()
+	})
+	if err != nil {
+		return modules.FileInfo{}, errors.AddContext(err, "failed to list files")
+	}
+	if len(files) == 0 {
+		return modules.FileInfo{}, errNoRestoreDrillFiles
+	}
+	return files[fastrand.Intn(len(files))], nil
+}
+
+// managedReferenceChecksum returns the checksum a restored file is verified
+// against. If the renter doesn't have a checksum on record yet, the local copy
+// of the file is used if it still exists and has the right size.
+func (r *Renter) managedReferenceChecksum(uid string, fi modules.FileInfo) (crypto.Hash, bool) {
+	rd := r.staticRestoreDrills
+	rd.mu.Lock()
+	checksum, exists := rd.persist.Checksums[uid]
+	rd.mu.Unlock()
+	if exists {
+		return checksum, true
+	}
+	if fi.LocalPath == "" {
+		return crypto.Hash{}, false
+	}
+	stat, err := os.Stat(fi.LocalPath)
+	if err != nil || uint64(stat.Size()) != fi.Filesize {
+		return crypto.Hash{}, false
+	}
+	checksum, err = hashFile(fi.LocalPath)
+	if err != nil {
+		return crypto.Hash{}, false
+	}
+	return checksum, true
+}
+
+// managedRestoreDrill downloads the provided file into the scratch directory
+// and verifies it.
+func (r *Renter) managedRestoreDrill(fi modules.FileInfo) (res modules.RestoreDrillResult) {
+	res = modules.RestoreDrillResult{
+		Filesize:  fi.Filesize,
+		SiaPath:   fi.SiaPath,
+		Timestamp: time.Now(),
+	}
+	var err error
+	defer func() {
+		res.Duration = time.Since(res.Timestamp)
+		res.Success = err == nil
+		if err != nil {
+			res.Error = err.Error()
+		}
+	}()
+
+	// Get the siafile's UID which doesn't change when the file is renamed.
+	entry, err := r.staticFileSystem.OpenSiaFile(fi.SiaPath)
+	if err != nil {
+		err = errors.AddContext(err, "failed to open siafile")
+		return
+	}
+	uid := string(entry.UID())
+	err = entry.Close()
+	if err != nil {
+		err = errors.AddContext(err, "failed to close siafile")
+		return
+	}
+
+	// Download the file into the scratch directory. Local copies of the file
+	// are ignored since the drill is meant to test the network.
+	scratchDir := filepath.Join(r.persistDir, restoreDrillsDir)
+	err = os.MkdirAll(scratchDir, modules.DefaultDirPerm)
+	if err != nil {
+		err = errors.AddContext(err, "failed to create scratch directory")
+		return
+	}
+	dst := filepath.Join(scratchDir, uid)
+	defer func() {
+		err = errors.Compose(err, os.RemoveAll(dst))
+	}()
+	_, start, err := r.Download(modules.RenterDownloadParameters{
+		Destination:      dst,
+		DisableDiskFetch: true,
+		SiaPath:          fi.SiaPath,
+	})
+	if err != nil {
+		err = errors.AddContext(err, "failed to create download")
+		return
+	}
+	err = start()
+	if err != nil {
+		err = errors.AddContext(err, "download failed")
+		return
+	}
+
+	// Verify the restored data.
+	res.Checksum, err = hashFile(dst)
+	if err != nil {
+		err = errors.AddContext(err, "failed to hash restored file")
+		return
+	}
+	reference, exists := r.managedReferenceChecksum(uid, fi)
+	if exists && reference != res.Checksum {
+		err = errRestoreDrillChecksumMismatch
+		return
+	}
+	res.Baseline = !exists
+
+	rd := r.staticRestoreDrills
+	rd.mu.Lock()
+	rd.persist.Checksums[uid] = res.Checksum
+	rd.mu.Unlock()
+	return
+}
+
+// RestoreDrillStatus returns the settings and recent results of the renter's
+// restore drills.
+func (r *Renter) RestoreDrillStatus() (modules.RestoreDrillStatus, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.RestoreDrillStatus{}, err
+	}
+	defer r.tg.Done()
+	rd := r.staticRestoreDrills
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	results := make([]modules.RestoreDrillResult, len(rd.persist.Results))
+	copy(results, rd.persist.Results)
+	return modules.RestoreDrillStatus{
+		RestoreDrillSettings: rd.persist.Settings,
+		InProgress:           rd.inProgress,
+		LastDrill:            rd.persist.LastDrill,
+		Results:              results,
+	}, nil
+}
+
+// RunRestoreDrill downloads a random file and verifies its contents. It blocks
+// until the drill is complete. A failed drill is reported through the result
+// and an alert, not the returned error.
+func (r *Renter) RunRestoreDrill() (modules.RestoreDrillResult, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.RestoreDrillResult{}, err
+	}
+	defer r.tg.Done()
+	rd := r.staticRestoreDrills
+	rd.mu.Lock()
+	if rd.inProgress {
+		rd.mu.Unlock()
+		return modules.RestoreDrillResult{}, errRestoreDrillInProgress
+	}
+	rd.inProgress = true
+	rd.mu.Unlock()
+	defer func() {
+		rd.mu.Lock()
+		rd.inProgress = false
+		rd.mu.Unlock()
+	}()
+
+	fi, err := r.managedRandomRestoreDrillFile()
+	if err != nil {
+		return modules.RestoreDrillResult{}, err
+	}
+	res := r.managedRestoreDrill(fi)
+
+	// Report the result.
+	if res.Success {
+		r.staticAlerter.UnregisterAlert(modules.AlertIDRenterRestoreDrillFailed)
+		r.log.Printf("Restore drill of '%v' succeeded after %v", res.SiaPath, res.Duration)
+	} else {
+		r.staticAlerter.RegisterAlert(modules.AlertIDRenterRestoreDrillFailed, AlertMSGRestoreDrillFailed,
+			AlertCauseRestoreDrillFailed(res.SiaPath, res.Error), modules.SeverityError)
+		r.log.Printf("Restore drill of '%v' failed: %v", res.SiaPath, res.Error)
+	}
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.addResult(res)
+	return res, rd.save()
+}
+
+// SetRestoreDrillSettings updates the settings of the renter's restore drills.
+func (r *Renter) SetRestoreDrillSettings(settings modules.RestoreDrillSettings) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	if settings.Interval == 0 {
+		settings.Interval = defaultRestoreDrillInterval
+	}
+	if settings.Interval < minRestoreDrillInterval {
+		return errors.AddContext(errRestoreDrillIntervalTooLow, "interval must be at least "+minRestoreDrillInterval.String())
+	}
+	rd := r.staticRestoreDrills
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.persist.Settings = settings
+	return rd.save()
+}
+
+// threadedRestoreDrillLoop periodically runs restore drills if they are
+// enabled.
+func (r *Renter) threadedRestoreDrillLoop() {
+	if err := r.tg.Add(); err != nil {
+		return
+	}
+	defer r.tg.Done()
+	for {
+		select {
+		case <-r.tg.StopChan():
+			return
+		case <-time.After(restoreDrillCheckInterval):
+		}
+		rd := r.staticRestoreDrills
+		rd.mu.Lock()
+		due := rd.due(time.Now())
+		rd.mu.Unlock()
+		if !due {
+			continue
+		}
+		_, err := r.RunRestoreDrill()
+		if errors.Contains(err, errNoRestoreDrillFiles) {
+			continue
+		}
+		if err != nil {
+			r.log.Println("WARN: failed to run restore drill:", err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package renter
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+)
+
+// TestRestoreDrillsPersist is a unit test for loading and saving the restore
+// drills.
+func TestRestoreDrillsPersist(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir("renter", t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fresh restore drills should be disabled and use the default interval.
+	rd, err := newRestoreDrills(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rd.persist.Settings.Enabled || rd.persist.Settings.Interval != defaultRestoreDrillInterval {
+		t.Fatal("wrong default settings", rd.persist.Settings)
+	}
+
+	// Update the settings, add a result and a checksum.
+	rd.persist.Settings = modules.RestoreDrillSettings{Enabled: true, Interval: time.Hour}
+	var checksum crypto.Hash
+	fastrand.Read(checksum[:])
+	rd.persist.Checksums["uid"] = checksum
+	rd.addResult(modules.RestoreDrillResult{Success: true, Timestamp: time.Now()})
+	if err := rd.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload and compare.
+	rd2, err := newRestoreDrills(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rd2.persist.Settings != rd.persist.Settings {
+		t.Fatal("settings don't match", rd2.persist.Settings, rd.persist.Settings)
+	}
+	if rd2.persist.Checksums["uid"] != checksum {
+		t.Fatal("checksum wasn't persisted")
+	}
+	if len(rd2.persist.Results) != 1 || !rd2.persist.Results[0].Success {
+		t.Fatal("result wasn't persisted", rd2.persist.Results)
+	}
+	if !rd2.persist.LastDrill.Equal(rd.persist.LastDrill) {
+		t.Fatal("last drill doesn't match", rd2.persist.LastDrill, rd.persist.LastDrill)
+	}
+}
+
+// TestRestoreDrillsResultsAndDue is a unit test for tracking the restore drill
+// results and deciding when the next drill is due.
+func TestRestoreDrillsResultsAndDue(t *testing.T) {
+	t.Parallel()
+
+	rd := &restoreDrills{}
+	now := time.Now()
+
+	// Disabled drills are never due.
+	if rd.due(now) {
+		t.Fatal("disabled drills shouldn't be due")
+	}
+	rd.persist.Settings = modules.RestoreDrillSettings{Enabled: true, Interval: time.Hour}
+	if !rd.due(now) {
+		t.Fatal("drill should be due if it never ran")
+	}
+
+	// Drills in progress are not due.
+	rd.inProgress = true
+	if rd.due(now) {
+		t.Fatal("drill shouldn't be due while in progress")
+	}
+	rd.inProgress = false
+
+	// After a drill the next one is due after the interval.
+	rd.addResult(modules.RestoreDrillResult{Timestamp: now})
+	if rd.due(now.Add(time.Hour - time.Second)) {
+		t.Fatal("drill shouldn't be due before the interval passed")
+	}
+	if !rd.due(now.Add(time.Hour)) {
+		t.Fatal("drill should be due after the interval passed")
+	}
+
+	// Add more results than are kept.
+	for i := 1; i <= maxRestoreDrillResults; i++ {
+		rd.addResult(modules.RestoreDrillResult{Timestamp: now.Add(time.Duration(i))})
+	}
+	if len(rd.persist.Results) != maxRestoreDrillResults {
+		t.Fatal("wrong number of results", len(rd.persist.Results))
+	}
+	if !rd.persist.Results[0].Timestamp.Equal(now.Add(1)) {
+		t.Fatal("oldest result wasn't dropped")
+	}
+	if !rd.persist.LastDrill.Equal(now.Add(maxRestoreDrillResults)) {
+		t.Fatal("wrong last drill", rd.persist.LastDrill)
+	}
+}
@@ -0,0 +1,61 @@
+package renter
+
+import (
+	"sync"
+
+	"go.sia.tech/siad/modules"
+)
+
+// staticSectorBufferPool is the pool of sector buffers shared by all upload
+// chunks of the renter.
+var staticSectorBufferPool = newSectorBufferPool()
+
+// sectorBufferPool is a pool of sector sized buffers. The pieces of upload
+// chunks are read, erasure coded, padded and encrypted within these buffers
+// and handed to the RPC writer without being copied. Once a piece was
+// uploaded, its buffer is returned to the pool to be reused for the next
+// chunk instead of leaving multiple MiB of garbage behind for every piece.
+type sectorBufferPool struct {
+	staticPool sync.Pool
+}
+
+// newSectorBufferPool creates a new sectorBufferPool.
+func newSectorBufferPool() *sectorBufferPool {
+	return &sectorBufferPool{
+		staticPool: sync.Pool{
+			New: func() interface{} {
+				b := make([]byte, modules.SectorSize)
+				return &b
+			},
+		},
+	}
+}
+
+// callGet returns a buffer of the given length with a capacity of a full
+// sector. The contents of the buffer are undefined.
+func (sbp *sectorBufferPool) callGet(length uint64) []byte {
+	if length > modules.SectorSize {
+		return make([]byte, length)
+	}
+	b := sbp.staticPool.Get().(*[]byte)
+	return (*b)[:length]
+}
+
+// callPut returns a buffer to the pool. The caller needs to make sure that the
+// buffer was obtained through callGet and isn't used anymore. Buffers without
+// the capacity of a full sector are ignored.
+func (sbp *sectorBufferPool) callPut(b []byte) {
+	if cap(b) != int(modules.SectorSize) {
+		return
+	}
+	b = b[:cap(b)]
+	sbp.staticPool.Put(&b)
+}
+
+// callPutPieces returns all pieces to the pool. Pieces which weren't obtained
+// through callGet, e.g. nil pieces, are ignored.
+func (sbp *sectorBufferPool) callPutPieces(pieces [][]byte) {
+	for _, piece := range pieces {
+		sbp.callPut(piece)
+	}
+}
@@ -0,0 +1,89 @@
+package renter
+
+import (
+	"bytes"
+	"testing"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/modules"
+)
+
+// TestSectorBufferPool is a unit test for the sectorBufferPool.
+func TestSectorBufferPool(t *testing.T) {
+	t.Parallel()
+
+	sbp := newSectorBufferPool()
+
+	// Buffers should have the requested length and the capacity of a sector.
+	b := sbp.callGet(100)
+	if len(b) != 100 || cap(b) != int(modules.SectorSize) {
+		t.Fatal("wrong buffer dimensions", len(b), cap(b))
+	}
+	sbp.callPut(b)
+
+	// Buffers larger than a sector are allocated separately.
+	b = sbp.callGet(modules.SectorSize + 1)
+	if len(b) != int(modules.SectorSize+1) {
+		t.Fatal("wrong buffer length", len(b))
+	}
+
+	// Putting buffers with the wrong capacity is a no-op.
+	sbp.callPut(b)
+	sbp.callPut(make([]byte, 10))
+	sbp.callPut(nil)
+
+	// Putting multiple pieces ignores pieces which weren't allocated.
+	sbp.callPutPieces([][]byte{sbp.callGet(10), nil, make([]byte, 10)})
+}
+
+// TestReadDataPiecesReusedBuffers makes sure that readDataPieces doesn't leak
+// stale data from reused buffers into the padding of the pieces.
+func TestReadDataPiecesReusedBuffers(t *testing.T) {
+	t.Parallel()
+
+	rs, err := modules.NewRSCode(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieceSize := modules.SectorSize
+
+	// Fill the pool with dirty buffers.
+	for i := 0; i < rs.NumPieces(); i++ {
+		staticSectorBufferPool.callPut(fastrand.Bytes(int(modules.SectorSize)))
+	}
+
+	// Read less data than fits into the pieces.
+	data := fastrand.Bytes(int(pieceSize + pieceSize/2))
+	pieces, n, err := readDataPieces(bytes.NewReader(data), rs, pieceSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != uint64(len(data)) {
+		t.Fatal("wrong number of bytes read", n, len(data))
+	}
+	if !bytes.Equal(pieces[0], data[:pieceSize]) {
+		t.Fatal("first piece doesn't match data")
+	}
+	if !bytes.Equal(pieces[1][:pieceSize/2], data[pieceSize:]) {
+		t.Fatal("second piece doesn't match data")
+	}
+	if !bytes.Equal(pieces[1][pieceSize/2:], make([]byte, pieceSize/2)) {
+		t.Fatal("remainder of second piece wasn't zeroed")
+	}
+
+	// Encode the pieces and check that the parity matches the one computed
+	// from freshly allocated pieces.
+	shards := encodeShards(rs, pieces)
+	expected, err := rs.EncodeShards([][]byte{append([]byte{}, pieces[0]...), append([]byte{}, pieces[1]...)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shards) != len(expected) {
+		t.Fatal("wrong number of shards", len(shards), len(expected))
+	}
+	for i := range shards {
+		if !bytes.Equal(shards[i], expected[i]) {
+			t.Fatal("shard mismatch", i)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package renter
+
+import (
+	"reflect"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// ValidateSettings checks proposed renter settings for internal consistency
+// and estimates whether the allowance can fund the expected usage, without
+// applying the settings.
+func (r *Renter) ValidateSettings(s modules.RenterSettings) (modules.SettingsValidation, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.SettingsValidation{}, err
+	}
+	defer r.tg.Done()
+	sv := modules.SettingsValidation{Issues: []modules.SettingsIssue{}}
+
+	// Check the bandwidth limits.
+	limits := []struct {
+		field string
+		limit int64
+	}{
+		{"maxdownloadspeed", s.MaxDownloadSpeed},
+		{"maxuploadspeed", s.MaxUploadSpeed},
+	}
+	for _, l := range limits {
+		if l.limit < 0 {
+			sv.AddError(l.field, "bandwidth limits cannot be negative")
+		} else if l.limit > 0 && l.limit < modules.SettingsMinRecommendedBandwidth {
+			sv.AddWarning(l.field, "limits below %v per second may cause transfers to time out", modules.FilesizeUnits(modules.SettingsMinRecommendedBandwidth))
+		}
+	}
+
+	// Check the account funding policy.
+	if err := validateAccountFundingPolicy(s.AccountFunding); err != nil {
+		sv.AddError("accountrefillthreshold", err.Error())
+	}
+
+	// Check the repair windows.
+	if err := s.RepairSchedule.Validate(); err != nil {
+		sv.AddError("repairwindows", err.Error())
+	}
+
+	// An empty allowance cancels the current one.
+	a := s.Allowance
+	if reflect.DeepEqual(a, modules.Allowance{}) {
+		return sv, nil
+	}
+	r.managedValidateAllowance(a, &sv)
+	return sv, nil
+}
+
+// managedValidateAllowance adds the issues of the allowance to the
+// validation.
+func (r *Renter) managedValidateAllowance(a modules.Allowance, sv *modules.SettingsValidation) {
+	// Check the fields the contractor requires.
+	required := []struct {
+		field string
+		zero  bool
+	}{
+		{"funds", a.Funds.IsZero()},
+		{"hosts", a.Hosts == 0},
+		{"period", a.Period == 0},
+		{"renewwindow", a.RenewWindow == 0},
+		{"expectedstorage", a.ExpectedStorage == 0},
+		{"expectedupload", a.ExpectedUpload == 0},
+		{"expecteddownload", a.ExpectedDownload == 0},
+		{"expectedredundancy", a.ExpectedRedundancy == 0},
+		{"maxperiodchurn", a.MaxPeriodChurn == 0},
+	}
+	var missing bool
+	for _, field := range required {
+		if field.zero {
+			sv.AddError(field.field, "must be non-zero")
+			missing = true
+		}
+	}
+	if missing {
+		return
+	}
+
+	// Check the fields for consistency.
+	if a.RenewWindow > a.Period {
+		sv.AddWarning("renewwindow", "renew window of %v blocks is longer than the period of %v blocks, contracts will be renewed right after forming them", a.RenewWindow, a.Period)
+	}
+	if minHosts := uint64(modules.RenterDefaultDataPieces + modules.RenterDefaultParityPieces); a.Hosts < minHosts {
+		sv.AddWarning("hosts", "files uploaded with the default erasure coding need %v hosts to reach full redundancy", minHosts)
+	}
+	if active, err := r.hostDB.ActiveHosts(); err == nil && uint64(len(active)) < a.Hosts {
+		sv.AddWarning("hosts", "only %v hosts are active, the allowance requests %v", len(active), a.Hosts)
+	}
+
+	// Estimate the costs of the allowance.
+	estimate, _, err := r.PriceEstimation(a)
+	if err != nil {
+		sv.AddWarning("funds", "unable to estimate the costs of the allowance: %v", err)
+		return
+	}
+	if estimate.FormContracts.Cmp(a.Funds) > 0 {
+		sv.AddWarning("funds", "funds of %v cannot fund contracts with %v hosts, which is estimated to cost %v", a.Funds.HumanString(), a.Hosts, estimate.FormContracts.HumanString())
+		return
+	}
+	period := uint64(a.Period)
+	storage := estimate.StorageTerabyteMonth.Mul64(a.ExpectedStorage).Mul64(period).Div(modules.BytesPerTerabyte).Div64(uint64(types.BlocksPerMonth))
+	upload := estimate.UploadTerabyte.Mul64(a.ExpectedUpload).Mul64(period).Div(modules.BytesPerTerabyte)
+	download := estimate.DownloadTerabyte.Mul64(a.ExpectedDownload).Mul64(period).Div(modules.BytesPerTerabyte)
+	total := estimate.FormContracts.Add(storage).Add(upload).Add(download)
+	if total.Cmp(a.Funds) > 0 {
+		sv.AddWarning("funds", "funds of %v are less than the %v the expected storage, upload and download are estimated to cost per period", a.Funds.HumanString(), total.HumanString())
+	}
+}
@@ -0,0 +1,187 @@
+package renter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+)
+
+const (
+	// sharedFileVersion is the version of the shared file format.
+	sharedFileVersion = "1.0"
+
+	// sharedFileSaltSize is the size of the salt that is used together with
+	// the password to derive the key of a shared file.
+	sharedFileSaltSize = 32
+)
+
+var (
+	// encryptionTwofishGCM is the encryption of shared files. Unlike the
+	// twofish-ctr encryption of backups it is authenticated.
+	encryptionTwofishGCM = "twofish-gcm"
+)
+
+var (
+	// errNoSharedFilePassword is returned if a file is shared or loaded
+	// without a password.
+	errNoSharedFilePassword = errors.New("a password is required to share a file")
+
+	// errSharedFileDecrypt is returned if a shared file can't be decrypted,
+	// which usually means that the password is wrong.
+	errSharedFileDecrypt = errors.New("failed to decrypt, the password is wrong or the shared file is corrupted")
+
+	// errSharedFilePartialChunk is returned when sharing a file whose last
+	// chunk is combined with the chunks of other files.
+	errSharedFilePartialChunk = errors.New("sharing files with partial chunks is not supported")
+)
+
+// sharedFileHeader is the plaintext JSON header of a shared file. It is
+// followed by a newline and the encrypted siafile.
+type sharedFileHeader struct {
+	Version    string `json:"version"`
+	Encryption string `json:"encryption"`
+	Salt       []byte `json:"salt"`
+}
+
+// encryptSharedFile encrypts a siafile with the password and returns the
+// shared file.
+func encryptSharedFile(siaFile []byte, password string) ([]byte, error) {
+	sh := sharedFileHeader{
+		Version:    sharedFileVersion,
+		Encryption: encryptionTwofishGCM,
+		Salt:       fastrand.Bytes(sharedFileSaltSize),
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(sh); err != nil {
+		return nil, err
+	}
+	buf.Write(modules.PassphraseKey(password, sh.Salt).EncryptBytes(siaFile))
+	return buf.Bytes(), nil
+}
+
+// decryptSharedFile decrypts a shared file with the password and returns the
+// siafile.
+func decryptSharedFile(sharedFile []byte, password string) ([]byte, error) {
+	i := bytes.IndexByte(sharedFile, '\n')
+	if i == -1 {
+		return nil, errors.New("shared file is missing its header")
+	}
+	var sh sharedFileHeader
+	if err := json.Unmarshal(sharedFile[:i], &sh); err != nil {
+		return nil, errors.AddContext(err, "failed to decode header of shared file")
+	}
+	if sh.Version != sharedFileVersion {
+		return nil, errors.New("unknown version")
+	}
+	if sh.Encryption != encryptionTwofishGCM {
+		return nil, errors.New("unknown encryption")
+	}
+	if len(sh.Salt) != sharedFileSaltSize {
+		return nil, errors.New("invalid salt")
+	}
+	siaFile, err := modules.PassphraseKey(password, sh.Salt).DecryptBytes(crypto.Ciphertext(sharedFile[i+1:]))
+	if err != nil {
+		return nil, errors.Compose(errSharedFileDecrypt, err)
+	}
+	return siaFile, nil
+}
+
+// ShareFile writes the siafile at siaPath to dst, encrypted with the
+// password. The shared file contains the keys of the file and the hosts that
+// store its pieces, which allows anyone who knows the password to load and
+// download the file without access to the renter's seed.
+func (r *Renter) ShareFile(siaPath modules.SiaPath, dst, password string) (err error) {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	if password == "" {
+		return errNoSharedFilePassword
+	}
+
+	// Read the siafile.
+	node, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Compose(err, node.Close())
+	}()
+	if node.HasPartialChunk() {
+		return errSharedFilePartialChunk
+	}
+	sr, err := node.SnapshotReader()
+	if err != nil {
+		return err
+	}
+	siaFile, err := ioutil.ReadAll(sr)
+	if err := errors.Compose(err, sr.Close()); err != nil {
+		return errors.AddContext(err, "failed to read siafile")
+	}
+
+	// Encrypt it and write it to dst.
+	sharedFile, err := encryptSharedFile(siaFile, password)
+	if err != nil {
+		return errors.AddContext(err, "failed to encrypt siafile")
+	}
+	return ioutil.WriteFile(dst, sharedFile, 0600)
+}
+
+// LoadSharedFile loads a file that was shared with ShareFile into the renter
+// at siaPath. The local path of the file is cleared since it refers to the
+// disk of the renter that shared the file.
+func (r *Renter) LoadSharedFile(src string, siaPath modules.SiaPath, password string) (err error) {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	if password == "" {
+		return errNoSharedFilePassword
+	}
+
+	// Read and decrypt the shared file.
+	sharedFile, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	siaFile, err := decryptSharedFile(sharedFile, password)
+	if err != nil {
+		return err
+	}
+
+	// Add the siafile to the filesystem. AddSiaFileFromReader would pick a
+	// different path if a file exists at siaPath already.
+	exists, err := r.staticFileSystem.FileExists(siaPath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.AddContext(filesystem.ErrExists, siaPath.String())
+	}
+	if err := r.staticFileSystem.AddSiaFileFromReader(bytes.NewReader(siaFile), siaPath); err != nil {
+		return errors.AddContext(err, "failed to add siafile")
+	}
+	node, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return err
+	}
+	err = node.SetLocalPath("")
+	if err := errors.Compose(err, node.Close()); err != nil {
+		return errors.AddContext(err, "failed to clear local path")
+	}
+
+	// Update the metadata of the file's directory.
+	dirSiaPath, err := siaPath.Dir()
+	if err != nil {
+		return err
+	}
+	_ = r.staticBubbleScheduler.callQueueBubble(dirSiaPath)
+	return nil
+}
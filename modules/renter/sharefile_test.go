@@ -0,0 +1,128 @@
+package renter
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+)
+
+// TestSharedFileEncryption tests encrypting and decrypting shared files.
+func TestSharedFileEncryption(t *testing.T) {
+	siaFile := fastrand.Bytes(1000)
+	sharedFile, err := encryptSharedFile(siaFile, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sharedFile, siaFile[:100]) {
+		t.Fatal("shared file contains plaintext")
+	}
+	if h := crypto.HashBytes(siaFile); bytes.Contains(sharedFile, h[:]) || bytes.Contains(sharedFile, []byte(h.String())) {
+		t.Fatal("shared file contains hash of plaintext")
+	}
+
+	// Decrypt it with the right password.
+	decrypted, err := decryptSharedFile(sharedFile, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, siaFile) {
+		t.Fatal("decrypted siafile doesn't match")
+	}
+
+	// Decrypt it with the wrong password.
+	if _, err := decryptSharedFile(sharedFile, "wrong"); !errors.Contains(err, errSharedFileDecrypt) {
+		t.Fatal("expected errSharedFileDecrypt but got", err)
+	}
+
+	// Sharing the same file twice shouldn't result in the same ciphertext.
+	sharedFile2, err := encryptSharedFile(siaFile, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sharedFile, sharedFile2) {
+		t.Fatal("shared files should differ")
+	}
+
+	// Corrupt the ciphertext.
+	sharedFile[len(sharedFile)-1]++
+	if _, err := decryptSharedFile(sharedFile, "password"); !errors.Contains(err, errSharedFileDecrypt) {
+		t.Fatal("expected errSharedFileDecrypt but got", err)
+	}
+}
+
+// TestShareFile tests sharing a file and loading it again.
+func TestShareFile(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	rt, err := newRenterTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Create a file with a local path.
+	siaPath, rsc := testingFileParams()
+	sf, err := rt.renter.createRenterTestFileWithParams(siaPath, rsc, crypto.RandomCipherType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sf.SetLocalPath("/some/local/path"); err != nil {
+		t.Fatal(err)
+	}
+	masterKey := sf.MasterKey()
+	if err := sf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Share it without a password.
+	dst := filepath.Join(rt.dir, "shared.sia")
+	if err := rt.renter.ShareFile(siaPath, dst, ""); !errors.Contains(err, errNoSharedFilePassword) {
+		t.Fatal("expected errNoSharedFilePassword but got", err)
+	}
+	if err := rt.renter.ShareFile(siaPath, dst, "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loading it at the same path or with the wrong password should fail.
+	if err := rt.renter.LoadSharedFile(dst, siaPath, "password"); !errors.Contains(err, filesystem.ErrExists) {
+		t.Fatal("expected ErrExists but got", err)
+	}
+	newSiaPath := modules.RandomSiaPath()
+	if err := rt.renter.LoadSharedFile(dst, newSiaPath, "wrong"); !errors.Contains(err, errSharedFileDecrypt) {
+		t.Fatal("expected errSharedFileDecrypt but got", err)
+	}
+
+	// Load it at a new path.
+	if err := rt.renter.LoadSharedFile(dst, newSiaPath, "password"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := rt.renter.staticFileSystem.OpenSiaFile(newSiaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := loaded.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if !bytes.Equal(loaded.MasterKey().Key(), masterKey.Key()) {
+		t.Fatal("master key doesn't match")
+	}
+	if loaded.LocalPath() != "" {
+		t.Fatal("local path should be cleared", loaded.LocalPath())
+	}
+}
@@ -120,6 +120,9 @@ func (r *Renter) UploadBackup(src, name string) error {
 // managedUploadBackup creates a backup of the renter which is uploaded to the
 // sia network as a snapshot and can be retrieved using only the seed.
 func (r *Renter) managedUploadBackup(src, name string) error {
+	if err := r.managedCheckReadOnly(); err != nil {
+		return err
+	}
 	if len(name) > 96 {
 		return errors.New("name is too long")
 	}
@@ -271,6 +274,12 @@ func (r *Renter) managedSnapshotExists(name string) bool {
 func (r *Renter) managedSaveSnapshot(meta modules.UploadedBackup) error {
 	id := r.mu.Lock()
 	defer r.mu.Unlock(id)
+	// Don't bring back snapshots that were pruned.
+	for _, uid := range r.persist.PrunedBackups {
+		if uid == meta.UID {
+			return nil
+		}
+	}
 	// Check whether we've already saved this snapshot.
 	for i, ub := range r.persist.UploadedBackups {
 		if ub.UID == meta.UID {
@@ -542,13 +551,14 @@ func (r *Renter) threadedSynchronizeSnapshots() {
 	// calcOverlap takes a host's entry table and the set of known snapshots,
 	// and calculates which snapshots the host is missing and which snapshots it
 	// has that we don't.
-	calcOverlap := func(entryTable []snapshotEntry, known map[[16]byte]struct{}) (unknown []modules.UploadedBackup, missing [][16]byte) {
+	calcOverlap := func(entryTable []snapshotEntry, known, pruned map[[16]byte]struct{}) (unknown []modules.UploadedBackup, missing [][16]byte) {
 		missingMap := make(map[[16]byte]struct{}, len(known))
 		for uid := range known {
 			missingMap[uid] = struct{}{}
 		}
 		for _, e := range entryTable {
-			if _, ok := known[e.UID]; !ok {
+			_, isPruned := pruned[e.UID]
+			if _, ok := known[e.UID]; !ok && !isPruned {
 				unknown = append(unknown, modules.UploadedBackup{
 					Name:           string(bytes.TrimRight(e.Name[:], types.RuneToString(0))),
 					UID:            e.UID,
@@ -574,8 +584,9 @@ func (r *Renter) threadedSynchronizeSnapshots() {
 	r.mu.RUnlock(id)
 
 	for {
-		// Can't do anything if the wallet is locked.
-		if unlocked, _ := r.w.Unlocked(); !unlocked {
+		// Can't do anything if the wallet is locked. In read-only mode, the
+		// snapshots aren't synced since that requires uploading to the hosts.
+		if unlocked, _ := r.w.Unlocked(); !unlocked || r.hostContractor.ReadOnly() {
 			select {
 			case <-time.After(snapshotSyncSleepDuration):
 			case <-r.tg.StopChan():
@@ -750,7 +761,7 @@ func (r *Renter) threadedSynchronizeSnapshots() {
 
 			// Calculate which snapshots the host doesn't have, and which
 			// snapshots it does have that we haven't seen before.
-			unknown, missing := calcOverlap(entryTable, known)
+			unknown, missing := calcOverlap(entryTable, known, r.managedPrunedBackups())
 
 			// If *any* snapshots are new, mark all other hosts as not
 			// synchronized.
@@ -0,0 +1,262 @@
+package renter
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// spendingHistoryFilename is the name of the file the renter persists the
+	// spending history in.
+	spendingHistoryFilename = "spendinghistory.json"
+)
+
+var (
+	// spendingHistoryMetadata is the metadata of the spending history persist
+	// file.
+	spendingHistoryMetadata = persist.Metadata{
+		Header:  "Renter Spending History",
+		Version: "1.5.5",
+	}
+
+	// spendingHistoryInterval is the minimum number of blocks between two
+	// snapshots of a contract's spending.
+	spendingHistoryInterval = build.Select(build.Var{
+		Dev:      types.BlockHeight(10),
+		Standard: types.BlockHeight(144), // 1 day
+		Testnet:  types.BlockHeight(144),
+		Testing:  types.BlockHeight(1),
+	}).(types.BlockHeight)
+
+	// spendingHistoryMaxAge is the number of blocks after which snapshots are
+	// dropped from the spending history.
+	spendingHistoryMaxAge = build.Select(build.Var{
+		Dev:      types.BlockHeight(1000),
+		Standard: types.BlockHeight(52560), // 1 year
+		Testnet:  types.BlockHeight(52560),
+		Testing:  types.BlockHeight(100),
+	}).(types.BlockHeight)
+)
+
+var (
+	// errInvalidSpendingRange is returned if the end of a spending range isn't
+	// after its start.
+	errInvalidSpendingRange = errors.New("the end of the spending range must be after its start")
+)
+
+// persistedContractSpendingHistory is the on-disk representation of the
+// spending history of a contract.
+type persistedContractSpendingHistory struct {
+	ContractID types.FileContractID               `json:"contractid"`
+	Snapshots  []modules.ContractSpendingSnapshot `json:"snapshots"`
+}
+
+// spendingHistory contains snapshots of the cumulative spending of the
+// renter's contracts. A snapshot is only taken if the spending of the
+// contract changed since the previous snapshot, so the spending of a contract
+// at any height is the spending of its most recent snapshot at or before that
+// height.
+type spendingHistory struct {
+	// snapshots are ordered from oldest to newest.
+	snapshots map[types.FileContractID][]modules.ContractSpendingSnapshot
+
+	staticPersistPath string
+	mu                sync.Mutex
+}
+
+// newSpendingHistory loads the spending history from disk or initializes an
+// empty one.
+func newSpendingHistory(persistDir string) (*spendingHistory, error) {
+	sh := &spendingHistory{
+		snapshots:         make(map[types.FileContractID][]modules.ContractSpendingSnapshot),
+		staticPersistPath: filepath.Join(persistDir, spendingHistoryFilename),
+	}
+	var persisted []persistedContractSpendingHistory
+	err := persist.LoadJSON(spendingHistoryMetadata, &persisted, sh.staticPersistPath)
+	if os.IsNotExist(err) {
+		return sh, nil
+	}
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to load spending history")
+	}
+	for _, h := range persisted {
+		sh.snapshots[h.ContractID] = h.Snapshots
+	}
+	return sh, nil
+}
+
+// callRecord takes a snapshot of the spending of every contract whose spending
+// changed since its last snapshot, unless the last snapshot is more recent
+// than the spending history interval. Snapshots older than the maximum age are
+// dropped. The history is saved if it changed.
+func (sh *spendingHistory) callRecord(height types.BlockHeight, contracts []modules.RenterContract) error {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	var changed bool
+	for _, c := range contracts {
+		spending := modules.NewSpendingCategories(c)
+		snapshots := sh.snapshots[c.ID]
+		if len(snapshots) > 0 {
+			// Spending only grows, so an unchanged total means that none of
+			// the categories changed.
+			last := snapshots[len(snapshots)-1]
+			if last.Total().Equals(spending.Total()) || last.BlockHeight+spendingHistoryInterval > height {
+				continue
+			}
+		}
+		sh.snapshots[c.ID] = append(snapshots, modules.ContractSpendingSnapshot{
+			BlockHeight:        height,
+			SpendingCategories: spending,
+		})
+		changed = true
+	}
+	if height > spendingHistoryMaxAge {
+		cutoff := height - spendingHistoryMaxAge
+		for id, snapshots := range sh.snapshots {
+			i := sort.Search(len(snapshots), func(i int) bool {
+				return snapshots[i].BlockHeight >= cutoff
+			})
+			if i == 0 {
+				continue
+			} else if i == len(snapshots) {
+				delete(sh.snapshots, id)
+			} else {
+				sh.snapshots[id] = append([]modules.ContractSpendingSnapshot{}, snapshots[i:]...)
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	persisted := make([]persistedContractSpendingHistory, 0, len(sh.snapshots))
+	for id, snapshots := range sh.snapshots {
+		persisted = append(persisted, persistedContractSpendingHistory{
+			ContractID: id,
+			Snapshots:  snapshots,
+		})
+	}
+	return persist.SaveJSON(spendingHistoryMetadata, persisted, sh.staticPersistPath)
+}
+
+// callSnapshots returns a copy of the spending history.
+func (sh *spendingHistory) callSnapshots() map[types.FileContractID][]modules.ContractSpendingSnapshot {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	snapshots := make(map[types.FileContractID][]modules.ContractSpendingSnapshot, len(sh.snapshots))
+	for id, s := range sh.snapshots {
+		snapshots[id] = append([]modules.ContractSpendingSnapshot{}, s...)
+	}
+	return snapshots
+}
+
+// callSpendingAt returns the cumulative spending of a contract at the
+// provided height. The spending of a contract before its start height or its
+// first snapshot is 0, so spending of contracts that were formed before the
+// history was started is attributed to the range of their first snapshot.
+func (sh *spendingHistory) callSpendingAt(c modules.RenterContract, height types.BlockHeight) modules.SpendingCategories {
+	if height <= c.StartHeight {
+		return modules.SpendingCategories{}
+	}
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	snapshots := sh.snapshots[c.ID]
+	i := sort.Search(len(snapshots), func(i int) bool {
+		return snapshots[i].BlockHeight > height
+	})
+	if i == 0 {
+		// There is no snapshot at or before the height.
+		return modules.SpendingCategories{}
+	}
+	return snapshots[i-1].SpendingCategories
+}
+
+// callContractSpending returns the spending of a contract between the start and
+// end heights. Contract fees are attributed to the range that contains the
+// contract's start height. If the range extends to the current height, the
+// current spending of the contract is used instead of the last snapshot.
+func (sh *spendingHistory) callContractSpending(c modules.RenterContract, start, end, currentHeight types.BlockHeight) modules.ContractSpending {
+	endSpending := modules.NewSpendingCategories(c)
+	if end <= currentHeight {
+		endSpending = sh.callSpendingAt(c, end)
+	}
+	spending := endSpending.Sub(sh.callSpendingAt(c, start))
+	spending.Fees = types.ZeroCurrency
+	if start <= c.StartHeight && c.StartHeight < end {
+		spending.Fees = modules.NewSpendingCategories(c).Fees
+	}
+	return modules.ContractSpending{
+		ContractID:         c.ID,
+		HostPublicKey:      c.HostPublicKey,
+		StartHeight:        c.StartHeight,
+		EndHeight:          c.EndHeight,
+		TotalSpending:      spending.Total(),
+		SpendingCategories: spending,
+	}
+}
+
+// managedRecordSpending records the spending of the renter's contracts in the
+// spending history.
+func (r *Renter) managedRecordSpending(height types.BlockHeight) {
+	contracts := append(r.hostContractor.Contracts(), r.hostContractor.OldContracts()...)
+	err := r.staticSpendingHistory.callRecord(height, contracts)
+	if err != nil {
+		r.log.Println("Unable to save spending history:", err)
+	}
+}
+
+// Spending returns the renter's spending between the start and end heights by
+// contract, most expensive contract first. Contracts without spending in the
+// range are omitted.
+func (r *Renter) Spending(start, end types.BlockHeight) (modules.RenterSpending, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.RenterSpending{}, err
+	}
+	defer r.tg.Done()
+	if end <= start {
+		return modules.RenterSpending{}, errInvalidSpendingRange
+	}
+
+	currentHeight := r.cs.Height()
+	rs := modules.RenterSpending{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Contracts:   []modules.ContractSpending{},
+	}
+	contracts := append(r.hostContractor.Contracts(), r.hostContractor.OldContracts()...)
+	seen := make(map[types.FileContractID]struct{}, len(contracts))
+	for _, c := range contracts {
+		// Refreshed contracts might be returned as both current and old
+		// contracts.
+		if _, exists := seen[c.ID]; exists || c.StartHeight >= end {
+			continue
+		}
+		seen[c.ID] = struct{}{}
+		cs := r.staticSpendingHistory.callContractSpending(c, start, end, currentHeight)
+		if cs.TotalSpending.IsZero() {
+			continue
+		}
+		rs.Contracts = append(rs.Contracts, cs)
+		rs.SpendingCategories = rs.SpendingCategories.Add(cs.SpendingCategories)
+	}
+	rs.TotalSpending = rs.SpendingCategories.Total()
+	sort.Slice(rs.Contracts, func(i, j int) bool {
+		return rs.Contracts[i].TotalSpending.Cmp(rs.Contracts[j].TotalSpending) > 0
+	})
+	return rs, nil
+}
+
+// SpendingHistory returns the recorded cumulative spending of the renter's
+// contracts, oldest snapshot first.
+func (r *Renter) SpendingHistory() map[types.FileContractID][]modules.ContractSpendingSnapshot {
+	return r.staticSpendingHistory.callSnapshots()
+}
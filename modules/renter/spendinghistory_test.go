@@ -0,0 +1,105 @@
+package renter
+
+import (
+	"os"
+	"testing"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestSpendingHistory is a unit test for the spendingHistory.
+func TestSpendingHistory(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir("renter", t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	sh, err := newSpendingHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Record the spending of a contract at heights 10, 20 and 30.
+	c := modules.RenterContract{
+		ID:          types.FileContractID{1},
+		StartHeight: 5,
+		ContractFee: types.NewCurrency64(7),
+	}
+	for _, height := range []types.BlockHeight{10, 20, 30} {
+		c.UploadSpending = c.UploadSpending.Add(types.NewCurrency64(100))
+		if err := sh.callRecord(height, []modules.RenterContract{c}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Unchanged spending doesn't create a snapshot.
+	if err := sh.callRecord(40, []modules.RenterContract{c}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sh.callSnapshots()[c.ID]) != 3 {
+		t.Fatal("wrong number of snapshots", sh.callSnapshots()[c.ID])
+	}
+
+	// The spending between two heights is the difference of the snapshots.
+	// Fees are only counted in the range that contains the start height.
+	cs := sh.callContractSpending(c, 15, 30, 100)
+	if !cs.UploadSpending.Equals64(200) || !cs.Fees.IsZero() || !cs.TotalSpending.Equals64(200) {
+		t.Fatal("wrong spending", cs)
+	}
+	cs = sh.callContractSpending(c, 0, 15, 100)
+	if !cs.UploadSpending.Equals64(100) || !cs.Fees.Equals64(7) || !cs.TotalSpending.Equals64(107) {
+		t.Fatal("wrong spending", cs)
+	}
+
+	// Ranges that end in the future use the current spending of the contract.
+	c.UploadSpending = c.UploadSpending.Add(types.NewCurrency64(50))
+	cs = sh.callContractSpending(c, 25, 150, 100)
+	if !cs.UploadSpending.Equals64(150) {
+		t.Fatal("wrong spending", cs)
+	}
+
+	// The history is persisted.
+	sh, err = newSpendingHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sh.callSnapshots()[c.ID]) != 3 {
+		t.Fatal("history wasn't persisted", sh.callSnapshots())
+	}
+
+	// Old snapshots are pruned.
+	if err := sh.callRecord(30+spendingHistoryMaxAge, nil); err != nil {
+		t.Fatal(err)
+	}
+	if snapshots := sh.callSnapshots()[c.ID]; len(snapshots) != 1 || snapshots[0].BlockHeight != 30 {
+		t.Fatal("old snapshots weren't pruned", snapshots)
+	}
+}
+
+// TestSpendingCategoriesSub probes the saturating subtraction of
+// SpendingCategories.
+func TestSpendingCategoriesSub(t *testing.T) {
+	t.Parallel()
+
+	x := modules.SpendingCategories{
+		DownloadSpending: types.NewCurrency64(10),
+		UploadSpending:   types.NewCurrency64(5),
+	}
+	y := modules.SpendingCategories{
+		DownloadSpending: types.NewCurrency64(4),
+		UploadSpending:   types.NewCurrency64(6),
+	}
+	diff := x.Sub(y)
+	if !diff.DownloadSpending.Equals64(6) || !diff.UploadSpending.IsZero() || !diff.Total().Equals64(6) {
+		t.Fatal("wrong difference", diff)
+	}
+	if sum := diff.Add(y); !sum.Total().Equals64(16) {
+		t.Fatal("wrong sum", sum)
+	}
+}
@@ -11,6 +11,7 @@ package renter
 import (
 	"fmt"
 	"os"
+	"reflect"
 
 	"gitlab.com/NebulousLabs/errors"
 
@@ -21,10 +22,34 @@ import (
 )
 
 var (
+	// ErrAllowanceEmpty is returned if the user tries to upload a file
+	// without having set an allowance.
+	ErrAllowanceEmpty = errors.New("renter has no allowance set")
+
+	// ErrNotEnoughContracts is returned if the renter doesn't have enough
+	// contracts to upload a file with the requested redundancy.
+	ErrNotEnoughContracts = errors.New("not enough contracts to upload file")
+
 	// ErrUploadDirectory is returned if the user tries to upload a directory.
 	ErrUploadDirectory = errors.New("cannot upload directory")
 )
 
+// managedCheckUploadContracts checks that we have contracts to upload to. We
+// need at least data + parity/2 contracts. NumPieces is equal to data+parity,
+// and min pieces is equal to parity. Therefore (NumPieces+MinPieces)/2 =
+// (data+data+parity)/2 = data+parity/2.
+func (r *Renter) managedCheckUploadContracts(ec modules.ErasureCoder) error {
+	numContracts := len(r.hostContractor.Contracts())
+	requiredContracts := (ec.NumPieces() + ec.MinPieces()) / 2
+	if numContracts >= requiredContracts || build.Release == "testing" {
+		return nil
+	}
+	if reflect.DeepEqual(r.hostContractor.Allowance(), modules.Allowance{}) {
+		return ErrAllowanceEmpty
+	}
+	return errors.AddContext(ErrNotEnoughContracts, fmt.Sprintf("got %v, needed %v", numContracts, requiredContracts))
+}
+
 // Upload instructs the renter to start tracking a file. The renter will
 // automatically upload and repair tracked files using a background loop.
 func (r *Renter) Upload(up modules.FileUploadParams) error {
@@ -32,6 +57,9 @@ func (r *Renter) Upload(up modules.FileUploadParams) error {
 		return err
 	}
 	defer r.tg.Done()
+	if err := r.managedCheckReadOnly(); err != nil {
+		return err
+	}
 
 	// Check if the file is a directory.
 	sourceInfo, err := os.Stat(up.Source)
@@ -65,14 +93,9 @@ func (r *Renter) Upload(up modules.FileUploadParams) error {
 		up.ErasureCode = modules.NewRSSubCodeDefault()
 	}
 
-	// Check that we have contracts to upload to. We need at least data +
-	// parity/2 contracts. NumPieces is equal to data+parity, and min pieces is
-	// equal to parity. Therefore (NumPieces+MinPieces)/2 = (data+data+parity)/2
-	// = data+parity/2.
-	numContracts := len(r.hostContractor.Contracts())
-	requiredContracts := (up.ErasureCode.NumPieces() + up.ErasureCode.MinPieces()) / 2
-	if numContracts < requiredContracts && build.Release != "testing" {
-		return fmt.Errorf("not enough contracts to upload file: got %v, needed %v", numContracts, (up.ErasureCode.NumPieces()+up.ErasureCode.MinPieces())/2)
+	// Check that we have contracts to upload to.
+	if err := r.managedCheckUploadContracts(up.ErasureCode); err != nil {
+		return err
 	}
 
 	// Create the directory path on disk. Renter directory is already present so
@@ -41,6 +41,7 @@ type unfinishedUploadChunk struct {
 	staticPiecesNeeded     int    // number of pieces to achieve a 100% complete upload
 	stuck                  bool   // indicates if the chunk was marked as stuck during last repair
 	stuckRepair            bool   // indicates if the chunk was identified for repair by the stuck loop
+	staticRepair           bool   // indicates if the chunk already had uploaded pieces when it was built
 
 	staticMemoryManager *memoryManager
 
@@ -55,6 +56,11 @@ type unfinishedUploadChunk struct {
 	logicalChunkData  [][]byte
 	physicalChunkData [][]byte
 
+	// piecesPooled indicates that the pieces of the chunk were allocated
+	// from the sector buffer pool and can be returned to it once they are no
+	// longer needed.
+	piecesPooled bool
+
 	// staticExpectedPieceRoots is a list of piece roots that are known for the
 	// chunk. If the roots are blank, it means there is no expectation for the
 	// root. This field is used to prevent file corruption when repairing from
@@ -217,22 +223,52 @@ func (uc *unfinishedUploadChunk) chunkComplete() bool {
 	return false
 }
 
+// returnPooledPieces returns the given pieces of the chunk to the sector buffer
+// pool if they were allocated from it. The caller needs to make sure that no
+// worker is still using the pieces.
+func (uc *unfinishedUploadChunk) returnPooledPieces(pieces [][]byte) {
+	if uc.piecesPooled {
+		staticSectorBufferPool.callPutPieces(pieces)
+	}
+}
+
 // readDataPieces reads dataPieces from a io.Reader and stores them in a
-// [][]byte ready to be encoded using an ErasureCoder.
+// [][]byte ready to be encoded using an ErasureCoder. The pieces are allocated
+// from the sector buffer pool.
 func readDataPieces(r io.Reader, ec modules.ErasureCoder, pieceSize uint64) ([][]byte, uint64, error) {
-	dataPieces := make([][]byte, ec.MinPieces())
+	dataPieces := make([][]byte, ec.MinPieces(), ec.NumPieces())
 	var total uint64
 	for i := range dataPieces {
-		dataPieces[i] = make([]byte, pieceSize)
+		dataPieces[i] = staticSectorBufferPool.callGet(pieceSize)
 		n, err := io.ReadFull(r, dataPieces[i])
 		total += uint64(n)
 		if err != nil && !errors.Contains(err, io.EOF) && err != io.ErrUnexpectedEOF {
+			staticSectorBufferPool.callPutPieces(dataPieces)
 			return nil, 0, errors.AddContext(err, "failed to read chunk from source reader")
 		}
+		// Pooled buffers are not zeroed. Clear whatever the reader didn't
+		// overwrite.
+		for j := n; j < len(dataPieces[i]); j++ {
+			dataPieces[i][j] = 0
+		}
 	}
 	return dataPieces, total, nil
 }
 
+// encodeShards erasure codes the data pieces returned by readDataPieces. If
+// the erasure coder supports it, the parity pieces are allocated from the
+// sector buffer pool as well.
+func encodeShards(ec modules.ErasureCoder, dataPieces [][]byte) [][]byte {
+	if ec.Type() == modules.ECReedSolomon && len(dataPieces) > 0 {
+		pieceSize := uint64(len(dataPieces[0]))
+		for len(dataPieces) < ec.NumPieces() {
+			dataPieces = append(dataPieces, staticSectorBufferPool.callGet(pieceSize))
+		}
+	}
+	shards, _ := ec.EncodeShards(dataPieces)
+	return shards
+}
+
 // padAndEncryptPiece will add padding to a unfinishedUploadChunk's piece at
 // index i and then encrypt it.
 func (uc *unfinishedUploadChunk) padAndEncryptPiece(i int) {
@@ -253,10 +289,9 @@ func padAndEncryptPiece(chunkIndex, pieceIndex uint64, logicalChunkData [][]byte
 		// compiler to eliminate unneeded allocations starting go 1.11.
 		logicalChunkData[pieceIndex] = append(logicalChunkData[pieceIndex], make([]byte, short)...)
 	}
-	// Encrypt the piece.
+	// Encrypt the piece in place.
 	key := masterKey.Derive(chunkIndex, pieceIndex)
-	// TODO: Switch this to perform in-place encryption.
-	logicalChunkData[pieceIndex] = key.EncryptBytes(logicalChunkData[pieceIndex])
+	logicalChunkData[pieceIndex] = key.EncryptBytesInPlace(logicalChunkData[pieceIndex])
 }
 
 // managedDownloadLogicalChunkData will fetch the logical chunk data by sending a
@@ -294,9 +329,10 @@ func (r *Renter) managedDownloadLogicalChunkData(chunk *unfinishedUploadChunk) e
 		length:        downloadLength,
 		needsMemory:   false, // We already requested memory, the download memory fits inside of that.
 		offset:        uint64(chunk.offset),
-		overdrive:     0, // No need to rush the latency on repair downloads.
 		priority:      0, // Repair downloads are completely de-prioritized.
 
+		overdrivePolicy: modules.DownloadOverdrivePolicy{}, // No need to rush the latency on repair downloads.
+
 		staticMemoryManager:    chunk.staticMemoryManager, // Same memory manager as upload chunk
 		staticSpendingCategory: categoryRepairDownload,
 	})
@@ -325,6 +361,7 @@ func (r *Renter) managedDownloadLogicalChunkData(chunk *unfinishedUploadChunk) e
 		return d.Err()
 	}
 	chunk.logicalChunkData = buf.pieces
+	chunk.piecesPooled = false
 
 	// Reconstruct the pieces.
 	//
@@ -390,6 +427,7 @@ func (r *Renter) threadedFetchAndRepairChunk(chunk *unfinishedUploadChunk) {
 		chunk.workersRemaining = 0
 		// Set the logical chunk data to nil for faster GC. The physical chunk
 		// data is nil'd by managedCleanUpUploadChunk later.
+		chunk.returnPooledPieces(chunk.logicalChunkData)
 		chunk.logicalChunkData = nil
 		// Set the error to indicate the failure happened when fetching the
 		// data.
@@ -456,6 +494,9 @@ func (uc *unfinishedUploadChunk) staticEncryptAndCheckIntegrity() error {
 		}
 		// Skip if this piece is not needed.
 		if uc.pieceUsage[i] {
+			if uc.piecesPooled {
+				staticSectorBufferPool.callPut(uc.logicalChunkData[i])
+			}
 			uc.logicalChunkData[i] = nil
 			continue
 		}
@@ -500,7 +541,8 @@ func (uc *unfinishedUploadChunk) staticReadLogicalData(r io.Reader) (uint64, err
 	// Encode the data pieces, forming the chunk's logical data.
 	//
 	// TODO: Ideally there is a way to only encode the shards that we need.
-	uc.logicalChunkData, _ = uc.fileEntry.ErasureCode().EncodeShards(dataPieces)
+	uc.logicalChunkData = encodeShards(uc.fileEntry.ErasureCode(), dataPieces)
+	uc.piecesPooled = true
 	return total, nil
 }
 
@@ -525,10 +567,14 @@ func (r *Renter) staticFetchLogicalDataFromReader(uc *unfinishedUploadChunk) (er
 
 	// Adjust the filesize. Since we don't know the length of the stream
 	// beforehand we simply assume that a whole chunk will be added to the
-	// file. That's why we subtract the difference between the size of a
-	// chunk and n here.
-	adjustedSize := uc.fileEntry.Size() - uc.length + n
-	if errSize := uc.fileEntry.SetFileSize(adjustedSize); errSize != nil {
+	// file. If the chunk turns out to be the partial last chunk of the stream,
+	// the file ends within the chunk. The size is derived from the chunk's
+	// offset rather than the current size, so that fetching the same chunk
+	// again doesn't adjust the size twice.
+	if n >= uc.length {
+		return nil
+	}
+	if errSize := uc.fileEntry.SetFileSize(uint64(uc.offset) + n); errSize != nil {
 		return errors.AddContext(errSize, "failed to adjust FileSize")
 	}
 	return nil
@@ -579,7 +625,8 @@ func (r *Renter) managedFetchLogicalChunkData(uc *unfinishedUploadChunk) error {
 		if err != nil {
 			return errors.AddContext(err, "unable to read the data from the local file")
 		}
-		uc.logicalChunkData, _ = uc.fileEntry.ErasureCode().EncodeShards(dataPieces)
+		uc.logicalChunkData = encodeShards(uc.fileEntry.ErasureCode(), dataPieces)
+		uc.piecesPooled = true
 		err = uc.staticEncryptAndCheckIntegrity()
 		if err != nil {
 			return errors.AddContext(err, "local file failed the integrity check")
@@ -587,6 +634,8 @@ func (r *Renter) managedFetchLogicalChunkData(uc *unfinishedUploadChunk) error {
 		return nil
 	}()
 	if err != nil {
+		uc.returnPooledPieces(uc.logicalChunkData)
+		uc.logicalChunkData = nil
 		r.log.Printf("falling back to remote download for repair: fetch from local file %v failed: %v", uc.fileEntry.LocalPath(), err)
 		return r.managedDownloadLogicalChunkData(uc)
 	}
@@ -614,6 +663,9 @@ func (r *Renter) managedCleanUpUploadChunk(uc *unfinishedUploadChunk) {
 		// complexity for erasure coding.
 		if piecesAvailable >= uc.workersRemaining {
 			memoryReleased += modules.SectorSize
+			if uc.piecesPooled {
+				staticSectorBufferPool.callPut(uc.physicalChunkData[i])
+			}
 			uc.physicalChunkData[i] = nil
 			// Mark this piece as taken so that we don't double release memory.
 			uc.pieceUsage[i] = true
@@ -642,6 +694,7 @@ func (r *Renter) managedCleanUpUploadChunk(uc *unfinishedUploadChunk) {
 	// yet been released.
 	released := uc.released
 	canceled := uc.canceled
+	fullyUploaded := uc.piecesCompleted >= uc.staticPiecesNeeded
 	if chunkComplete && !released {
 		if uc.piecesCompleted >= uc.staticPiecesNeeded {
 			r.repairLog.Printf("Completed repair for chunk %v of %s, %v pieces were completed out of %v", uc.staticIndex, uc.staticSiaPath, uc.piecesCompleted, uc.staticPiecesNeeded)
@@ -697,6 +750,7 @@ func (r *Renter) managedCleanUpUploadChunk(uc *unfinishedUploadChunk) {
 		if err != nil {
 			r.log.Print("managedCleanUpUploadChunk: failed to update file metadata", err)
 		}
+		r.managedEmitUploadChunkEvents(uc, fullyUploaded)
 
 		// Close the file entry for the completed chunk unless disrupted.
 		if !r.deps.Disrupt("disableCloseUploadEntry") {
@@ -707,7 +761,11 @@ func (r *Renter) managedCleanUpUploadChunk(uc *unfinishedUploadChunk) {
 		}
 		// Remove the chunk from the repairingChunks map
 		r.uploadHeap.managedMarkRepairDone(uc)
-		// Signal garbage collector to free memory before returning it to the manager.
+		// Signal garbage collector to free memory before returning it to the
+		// manager. No pieces are registered anymore, so the pooled buffers can
+		// be reused.
+		uc.returnPooledPieces(uc.logicalChunkData)
+		uc.returnPooledPieces(uc.physicalChunkData)
 		uc.logicalChunkData = nil
 		uc.physicalChunkData = nil
 	}
@@ -608,6 +608,7 @@ func (r *Renter) managedBuildUnfinishedChunk(entry *filesystem.FileNode, chunkIn
 		// a local (and therefore potentially altered or corrupt) file.
 		if len(pieceSet) > 0 {
 			uuc.staticExpectedPieceRoots[pieceIndex] = pieceSet[0].MerkleRoot
+			uuc.staticRepair = true
 		}
 	}
 	// Now that we have calculated the completed pieces for the chunk we can
@@ -815,6 +816,11 @@ func (r *Renter) managedAddChunksToHeap(hosts map[string]struct{}) (*uniqueRefre
 			return siaPaths, nil
 		}
 
+		// Archived directories are not repaired.
+		if r.staticArchivedPaths.callIsArchived(dir.staticSiaPath) {
+			continue
+		}
+
 		// Add chunks from the directory to the uploadHeap.
 		r.managedBuildChunkHeap(dir.staticSiaPath, hosts, targetUnstuckChunks, offline, goodForRenew)
 
@@ -1155,6 +1161,10 @@ func (r *Renter) managedBuildChunkHeap(dirSiaPath modules.SiaPath, hosts map[str
 			r.log.Println("WARN: could not create siaPath:", err)
 			continue
 		}
+		// Archived files are not repaired. Backups can't be archived.
+		if target != targetBackupChunks && r.staticArchivedPaths.callIsArchived(siaPath) {
+			continue
+		}
 		file, err := r.staticFileSystem.OpenSiaFile(siaPath)
 		if err != nil {
 			r.log.Println("WARN: could not open siafile:", err)
@@ -1270,6 +1280,20 @@ func (r *Renter) managedPushChunkForRepair(uuc *unfinishedUploadChunk, ct chunkT
 		return false, err
 	}
 
+	// Local chunks aren't uploaded in read-only mode. Streamed chunks are only
+	// pushed by uploads which were started before the mode was enabled.
+	if ct == chunkTypeLocalChunk && r.hostContractor.ReadOnly() {
+		return false, nil
+	}
+
+	// Repairs are only added to the heap within the repair windows.
+	uuc.mu.Lock()
+	scheduledRepair := uuc.isScheduledRepair()
+	uuc.mu.Unlock()
+	if scheduledRepair && !r.staticRepairSchedule.callRepairAllowed() {
+		return false, nil
+	}
+
 	// Try and update any existing chunk in the heap
 	err := r.uploadHeap.managedTryUpdate(uuc, ct)
 	if err != nil {
@@ -1394,6 +1418,17 @@ func (r *Renter) managedRepairLoop() error {
 			return nil
 		}
 		chunkPath := nextChunk.staticSiaPath
+
+		// Skip repairs if the repair window closed or its bandwidth budget was
+		// used up since the chunk was added to the heap.
+		nextChunk.mu.Lock()
+		scheduledRepair := nextChunk.isScheduledRepair()
+		nextChunk.mu.Unlock()
+		if scheduledRepair && !r.staticRepairSchedule.callRepairAllowed() {
+			nextChunk.fileEntry.Close()
+			r.uploadHeap.managedMarkRepairDone(nextChunk)
+			continue
+		}
 		r.repairLog.Printf("Repairing chunk %v of %s, currently have %v out of %v pieces", nextChunk.staticIndex, chunkPath, nextChunk.piecesCompleted, nextChunk.staticPiecesNeeded)
 
 		// Make sure we have enough workers for this chunk to reach minimum
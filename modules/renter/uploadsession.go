@@ -0,0 +1,363 @@
+package renter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+	"go.sia.tech/siad/persist"
+)
+
+const (
+	// uploadSessionsFilename is the name of the file the renter persists its
+	// upload sessions in.
+	uploadSessionsFilename = "uploadsessions.json"
+)
+
+var (
+	// uploadSessionsMetadata is the metadata of the upload sessions persist
+	// file.
+	uploadSessionsMetadata = persist.Metadata{
+		Header:  "Renter Upload Sessions",
+		Version: "1.5.5",
+	}
+
+	// errUnknownUploadSession is returned if an upload session doesn't exist.
+	errUnknownUploadSession = errors.New("upload session doesn't exist")
+
+	// errUploadSessionActive is returned if data is uploaded to a session
+	// which is already receiving data.
+	errUploadSessionActive = errors.New("upload session is already receiving data")
+
+	// errUploadSessionPartialChunk is returned if a non-final upload to a
+	// session doesn't end at a chunk boundary.
+	errUploadSessionPartialChunk = errors.New("only the final upload of a session can end with a partial chunk")
+)
+
+// uploadSessions are the renter's unfinished upload sessions.
+type uploadSessions struct {
+	sessions map[modules.UploadSessionID]*modules.UploadSession
+
+	// active contains the sessions that are currently receiving data. It is
+	// not persisted since uploads are interrupted by a restart.
+	active map[modules.UploadSessionID]struct{}
+
+	staticPersistPath string
+	mu                sync.Mutex
+}
+
+// chunkAlignedReader wraps the data of a non-final upload and turns an EOF
+// within a chunk into an error.
+type chunkAlignedReader struct {
+	r         io.Reader
+	chunkSize uint64
+	n         uint64
+}
+
+// Read implements the io.Reader interface.
+func (car *chunkAlignedReader) Read(b []byte) (int, error) {
+	n, err := car.r.Read(b)
+	car.n += uint64(n)
+	if err == io.EOF && car.n%car.chunkSize != 0 {
+		err = errUploadSessionPartialChunk
+	}
+	return n, err
+}
+
+// countingReader counts the bytes read from the wrapped reader.
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+// Read implements the io.Reader interface.
+func (cr *countingReader) Read(b []byte) (int, error) {
+	n, err := cr.r.Read(b)
+	cr.n += uint64(n)
+	return n, err
+}
+
+// newUploadSessions loads the upload sessions from disk or initializes an
+// empty set.
+func newUploadSessions(persistDir string) (*uploadSessions, error) {
+	us := &uploadSessions{
+		sessions:          make(map[modules.UploadSessionID]*modules.UploadSession),
+		active:            make(map[modules.UploadSessionID]struct{}),
+		staticPersistPath: filepath.Join(persistDir, uploadSessionsFilename),
+	}
+	err := persist.LoadJSON(uploadSessionsMetadata, &us.sessions, us.staticPersistPath)
+	if os.IsNotExist(err) {
+		return us, nil
+	}
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to load upload sessions")
+	}
+	return us, nil
+}
+
+// save persists the upload sessions.
+func (us *uploadSessions) save() error {
+	return persist.SaveJSON(uploadSessionsMetadata, us.sessions, us.staticPersistPath)
+}
+
+// callAdd adds a new session.
+func (us *uploadSessions) callAdd(s modules.UploadSession) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	us.sessions[s.ID] = &s
+	return us.save()
+}
+
+// callSession returns the session with the given id.
+func (us *uploadSessions) callSession(id modules.UploadSessionID) (modules.UploadSession, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	s, exists := us.sessions[id]
+	if !exists {
+		return modules.UploadSession{}, errUnknownUploadSession
+	}
+	return *s, nil
+}
+
+// callSessions returns all sessions, oldest first.
+func (us *uploadSessions) callSessions() []modules.UploadSession {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	sessions := make([]modules.UploadSession, 0, len(us.sessions))
+	for _, s := range us.sessions {
+		sessions = append(sessions, *s)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreateTime.Before(sessions[j].CreateTime)
+	})
+	return sessions
+}
+
+// callStart marks a session as receiving data at the given offset.
+func (us *uploadSessions) callStart(id modules.UploadSessionID, offset uint64) (modules.UploadSession, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	s, exists := us.sessions[id]
+	if !exists {
+		return modules.UploadSession{}, errUnknownUploadSession
+	}
+	if _, active := us.active[id]; active {
+		return modules.UploadSession{}, errUploadSessionActive
+	}
+	if offset != s.Offset {
+		return modules.UploadSession{}, fmt.Errorf("upload needs to resume at offset %v but got %v", s.Offset, offset)
+	}
+	us.active[id] = struct{}{}
+	return *s, nil
+}
+
+// callStop marks a session as no longer receiving data.
+func (us *uploadSessions) callStop(id modules.UploadSessionID) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	delete(us.active, id)
+}
+
+// callCheckpoint records that all chunks of the session up to the given
+// number of chunks are available on the network.
+func (us *uploadSessions) callCheckpoint(id modules.UploadSessionID, completedChunks uint64) (modules.UploadSession, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	s, exists := us.sessions[id]
+	if !exists {
+		return modules.UploadSession{}, errUnknownUploadSession
+	}
+	if completedChunks <= s.CompletedChunks {
+		return *s, nil
+	}
+	s.CompletedChunks = completedChunks
+	s.Offset = completedChunks * s.ChunkSize
+	s.LastCheckpoint = time.Now()
+	return *s, us.save()
+}
+
+// callRemove removes a session.
+func (us *uploadSessions) callRemove(id modules.UploadSessionID) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	if _, exists := us.sessions[id]; !exists {
+		return errUnknownUploadSession
+	}
+	if _, active := us.active[id]; active {
+		return errUploadSessionActive
+	}
+	delete(us.sessions, id)
+	return us.save()
+}
+
+// setUploadSessionFileSize drops the chunks of the file beyond the given
+// size and sets the file's size.
+func setUploadSessionFileSize(fileNode *filesystem.FileNode, size uint64) error {
+	// An empty file keeps its initial size.
+	if size == 0 {
+		return nil
+	}
+	numChunks := size / fileNode.ChunkSize()
+	if size%fileNode.ChunkSize() != 0 {
+		numChunks++
+	}
+	if err := fileNode.ShrinkNumChunks(numChunks); err != nil {
+		return err
+	}
+	return fileNode.SetFileSize(size)
+}
+
+// NewUploadSession creates an empty siafile and starts a resumable upload
+// session for it.
+func (r *Renter) NewUploadSession(up modules.FileUploadParams) (modules.UploadSession, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.UploadSession{}, err
+	}
+	defer r.tg.Done()
+	if err := r.managedCheckReadOnly(); err != nil {
+		return modules.UploadSession{}, err
+	}
+	if up.Repair {
+		return modules.UploadSession{}, errors.New("upload sessions can't repair existing files")
+	}
+	// The file of a session grows chunk by chunk, which isn't possible once
+	// it contains a partial chunk.
+	up.DisablePartialChunk = true
+	fileNode, err := r.managedInitUploadStream(up)
+	if err != nil {
+		return modules.UploadSession{}, errors.AddContext(err, "unable to create file for upload session")
+	}
+	s := modules.UploadSession{
+		ID:         modules.UploadSessionID(hex.EncodeToString(fastrand.Bytes(16))),
+		SiaPath:    up.SiaPath,
+		ChunkSize:  fileNode.ChunkSize(),
+		CreateTime: time.Now(),
+	}
+	if err := fileNode.Close(); err != nil {
+		return modules.UploadSession{}, err
+	}
+	return s, r.staticUploadSessions.callAdd(s)
+}
+
+// UploadSession returns the upload session with the given id.
+func (r *Renter) UploadSession(id modules.UploadSessionID) (modules.UploadSession, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.UploadSession{}, err
+	}
+	defer r.tg.Done()
+	return r.staticUploadSessions.callSession(id)
+}
+
+// UploadSessions returns all unfinished upload sessions.
+func (r *Renter) UploadSessions() ([]modules.UploadSession, error) {
+	if err := r.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer r.tg.Done()
+	return r.staticUploadSessions.callSessions(), nil
+}
+
+// AbortUploadSession removes an upload session. The data that was already
+// uploaded is kept.
+func (r *Renter) AbortUploadSession(id modules.UploadSessionID) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	return r.staticUploadSessions.callRemove(id)
+}
+
+// UploadSessionData uploads the data of the reader to the session, starting
+// at the given offset which needs to match the session's offset. Every chunk
+// that becomes available on the network is checkpointed. A final upload
+// finishes the session once all of its chunks are available.
+func (r *Renter) UploadSessionData(id modules.UploadSessionID, offset uint64, reader io.Reader, final bool) (_ modules.UploadSession, err error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.UploadSession{}, err
+	}
+	defer r.tg.Done()
+	if err := r.managedCheckReadOnly(); err != nil {
+		return modules.UploadSession{}, err
+	}
+	s, err := r.staticUploadSessions.callStart(id, offset)
+	if err != nil {
+		return modules.UploadSession{}, err
+	}
+	defer r.staticUploadSessions.callStop(id)
+
+	fileNode, err := r.staticFileSystem.OpenSiaFile(s.SiaPath)
+	if err != nil {
+		return modules.UploadSession{}, errors.AddContext(err, "unable to open file of upload session")
+	}
+	defer func() {
+		err = errors.Compose(err, fileNode.Close())
+	}()
+
+	// Drop the chunks after the checkpoint. They were grown by an earlier
+	// upload that failed and the data uploaded now might end before them. A
+	// file always keeps its first chunk.
+	keepChunks := s.CompletedChunks
+	if keepChunks == 0 {
+		keepChunks = 1
+	}
+	if err := fileNode.ShrinkNumChunks(keepChunks); err != nil {
+		return modules.UploadSession{}, errors.AddContext(err, "unable to drop incomplete chunks of upload session")
+	}
+
+	// Push the chunks and checkpoint them in order as they become available.
+	cr := &countingReader{r: reader}
+	reader = cr
+	if !final {
+		reader = &chunkAlignedReader{r: reader, chunkSize: s.ChunkSize}
+	}
+	chunks, nextChunk, err := r.managedPushStreamChunks(fileNode, reader, s.CompletedChunks)
+	if err != nil {
+		return modules.UploadSession{}, errors.AddContext(err, "unable to upload session data")
+	}
+	for _, chunk := range chunks {
+		select {
+		case <-r.tg.StopChan():
+			return modules.UploadSession{}, errors.New("upload interrupted by shutdown")
+		case <-chunk.staticAvailableChan:
+		}
+		chunk.mu.Lock()
+		chunkErr := chunk.err
+		chunk.mu.Unlock()
+		if chunkErr != nil {
+			return modules.UploadSession{}, errors.AddContext(chunkErr, "chunk of upload session failed")
+		}
+		s, err = r.staticUploadSessions.callCheckpoint(id, chunk.staticIndex+1)
+		if err != nil {
+			return modules.UploadSession{}, errors.AddContext(err, "unable to checkpoint upload session")
+		}
+	}
+
+	// Chunks that didn't need any work aren't returned but are complete as
+	// well.
+	s, err = r.staticUploadSessions.callCheckpoint(id, nextChunk)
+	if err != nil {
+		return modules.UploadSession{}, errors.AddContext(err, "unable to checkpoint upload session")
+	}
+	if !final {
+		return s, nil
+	}
+
+	// Set the size of the finished file explicitly. The chunks only adjust it
+	// while they are uploaded, which doesn't account for chunks or partial
+	// chunks left behind by a failed earlier upload.
+	if err := setUploadSessionFileSize(fileNode, offset+cr.n); err != nil {
+		return modules.UploadSession{}, errors.AddContext(err, "unable to set size of upload session file")
+	}
+	r.staticUploadSessions.callStop(id)
+	return s, r.staticUploadSessions.callRemove(id)
+}
@@ -0,0 +1,220 @@
+package renter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+	"gitlab.com/NebulousLabs/writeaheadlog"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem"
+	"go.sia.tech/siad/persist"
+)
+
+// TestUploadSessions is a unit test for the offset checks, checkpoints and
+// persistence of the upload sessions.
+func TestUploadSessions(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir("renter", t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	us, err := newUploadSessions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := modules.UploadSessionID("session")
+	err = us.callAdd(modules.UploadSession{
+		ID:         id,
+		SiaPath:    modules.RandomSiaPath(),
+		ChunkSize:  10,
+		CreateTime: time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Uploads need to start at the session's offset and can't run
+	// concurrently.
+	if _, err := us.callStart("unknown", 0); !errors.Contains(err, errUnknownUploadSession) {
+		t.Fatal("expected unknown session error", err)
+	}
+	if _, err := us.callStart(id, 10); err == nil {
+		t.Fatal("upload at wrong offset should fail")
+	}
+	if _, err := us.callStart(id, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := us.callStart(id, 0); !errors.Contains(err, errUploadSessionActive) {
+		t.Fatal("expected active session error", err)
+	}
+	if err := us.callRemove(id); !errors.Contains(err, errUploadSessionActive) {
+		t.Fatal("active session shouldn't be removable", err)
+	}
+
+	// Checkpoints only move forward.
+	s, err := us.callCheckpoint(id, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.CompletedChunks != 3 || s.Offset != 30 {
+		t.Fatal("wrong checkpoint", s.CompletedChunks, s.Offset)
+	}
+	if s, err = us.callCheckpoint(id, 2); err != nil || s.CompletedChunks != 3 {
+		t.Fatal("checkpoint went backwards", s.CompletedChunks, err)
+	}
+	us.callStop(id)
+
+	// Reloading the sessions keeps the checkpoint but not the active uploads.
+	us2, err := newUploadSessions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := us2.callStart(id, 30); err != nil {
+		t.Fatal("upload should resume at the checkpoint", err)
+	}
+	us2.callStop(id)
+	if err := us2.callRemove(id); err != nil {
+		t.Fatal(err)
+	}
+	if sessions := us2.callSessions(); len(sessions) != 0 {
+		t.Fatal("session wasn't removed", sessions)
+	}
+}
+
+// TestChunkAlignedReader checks that a chunkAlignedReader only allows the
+// data to end at a chunk boundary.
+func TestChunkAlignedReader(t *testing.T) {
+	t.Parallel()
+
+	data := fastrand.Bytes(30)
+	read, err := ioutil.ReadAll(&chunkAlignedReader{r: bytes.NewReader(data), chunkSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Fatal("data doesn't match")
+	}
+	_, err = ioutil.ReadAll(&chunkAlignedReader{r: bytes.NewReader(data[:25]), chunkSize: 10})
+	if !errors.Contains(err, errUploadSessionPartialChunk) {
+		t.Fatal("expected partial chunk error", err)
+	}
+}
+
+// TestUploadSessionRetryFinalChunk checks the size of a session's file when a
+// final upload is retried after its partial last chunk failed.
+func TestUploadSessionRetryFinalChunk(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	// Create an empty file the way a session does.
+	dir := build.TempDir("renter", t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	_, wal, err := writeaheadlog.New(filepath.Join(dir, "wal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := filesystem.New(filepath.Join(dir, modules.FileSystemRoot), log, wal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := modules.NewRSCode(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	siaPath := modules.RandomSiaPath()
+	err = fs.NewSiaFile(siaPath, "", rc, crypto.GenerateSiaKey(crypto.TypeDefaultRenter), 0, persist.DefaultDiskPermissionsTest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileNode, err := fs.OpenSiaFile(siaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := fileNode.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	chunkSize := fileNode.ChunkSize()
+	r := &Renter{}
+
+	// Declare helpers to grow the file and fetch the data of a chunk like a
+	// streamed upload does.
+	grow := func(numChunks uint64) {
+		if err := fileNode.GrowNumChunks(numChunks); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fetch := func(chunkIndex, length uint64) {
+		uc := &unfinishedUploadChunk{
+			fileEntry:                fileNode,
+			length:                   chunkSize,
+			offset:                   int64(chunkIndex * chunkSize),
+			staticIndex:              chunkIndex,
+			pieceUsage:               make([]bool, rc.NumPieces()),
+			staticExpectedPieceRoots: make([]crypto.Hash, rc.NumPieces()),
+			sourceReader:             ioutil.NopCloser(bytes.NewReader(fastrand.Bytes(int(length)))),
+		}
+		if err := r.staticFetchLogicalDataFromReader(uc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkFile := func(numChunks, size uint64) {
+		if fileNode.NumChunks() != numChunks || fileNode.Size() != size {
+			t.Fatalf("expected %v chunks and size %v but got %v and %v", numChunks, size, fileNode.NumChunks(), fileNode.Size())
+		}
+	}
+
+	// The first final upload has 3.5 chunks of data. Chunk 2 fails, so only
+	// the first 2 chunks are checkpointed, but the partial last chunk was
+	// fetched already.
+	for i := uint64(0); i < 3; i++ {
+		grow(i + 1)
+		fetch(i, chunkSize)
+	}
+	grow(4)
+	fetch(3, chunkSize/2)
+	checkFile(4, 3*chunkSize+chunkSize/2)
+
+	// Fetching the partial last chunk again doesn't adjust the size twice.
+	fetch(3, chunkSize/2)
+	checkFile(4, 3*chunkSize+chunkSize/2)
+
+	// The retry resumes at the checkpoint with less data than before. The
+	// chunks of the earlier upload after the checkpoint are dropped.
+	if err := fileNode.ShrinkNumChunks(2); err != nil {
+		t.Fatal(err)
+	}
+	grow(3)
+	fetch(2, chunkSize/4)
+	checkFile(3, 2*chunkSize+chunkSize/4)
+
+	// Finishing the session sets the size from the offset and the data
+	// received, even if the file was grown further in the meantime.
+	grow(4)
+	if err := setUploadSessionFileSize(fileNode, 2*chunkSize+chunkSize/4); err != nil {
+		t.Fatal(err)
+	}
+	checkFile(3, 2*chunkSize+chunkSize/4)
+}
@@ -124,6 +124,9 @@ func (r *Renter) UploadStreamFromReader(up modules.FileUploadParams, reader io.R
 		return err
 	}
 	defer r.tg.Done()
+	if err := r.managedCheckReadOnly(); err != nil {
+		return err
+	}
 
 	// Perform the upload, close the filenode, and return.
 	fileNode, err := r.callUploadStreamFromReader(up, reader)
@@ -166,14 +169,9 @@ func (r *Renter) managedInitUploadStream(up modules.FileUploadParams) (*filesyst
 		}
 		return entry, nil
 	}
-	// Check that we have contracts to upload to. We need at least data +
-	// parity/2 contracts. NumPieces is equal to data+parity, and min pieces is
-	// equal to parity. Therefore (NumPieces+MinPieces)/2 = (data+data+parity)/2
-	// = data+parity/2.
-	numContracts := len(r.hostContractor.Contracts())
-	requiredContracts := (ec.NumPieces() + ec.MinPieces()) / 2
-	if numContracts < requiredContracts && build.Release != "testing" {
-		return nil, fmt.Errorf("not enough contracts to upload file: got %v, needed %v", numContracts, (ec.NumPieces()+ec.MinPieces())/2)
+	// Check that we have contracts to upload to.
+	if err := r.managedCheckUploadContracts(ec); err != nil {
+		return nil, err
 	}
 
 	// If there's a cipherKey defined already use that, otherwise generate a new
@@ -218,13 +216,48 @@ func (r *Renter) callUploadStreamFromReader(up modules.FileUploadParams, reader
 		}
 	}()
 
+	// Push the chunks of the stream to the upload heap.
+	chunks, _, err := r.managedPushStreamChunks(fileNode, reader, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for all chunks to become available.
+	for _, chunk := range chunks {
+		select {
+		case <-r.tg.StopChan():
+			err = errors.New("upload timed out, renter has shutdown")
+		case <-chunk.staticAvailableChan:
+			chunk.mu.Lock()
+			err = chunk.err
+			chunk.mu.Unlock()
+		}
+		if err != nil {
+			return nil, errors.AddContext(err, "upload streamer failed to get all data available")
+		}
+	}
+
+	// Disrupt to force an error and ensure the fileNode is being closed
+	// correctly.
+	if r.deps.Disrupt("failUploadStreamFromReader") {
+		return nil, errors.New("disrupted by failUploadStreamFromReader")
+	}
+	return fileNode, nil
+}
+
+// managedPushStreamChunks reads the chunks of the stream one by one, starting
+// at firstChunk, and pushes them to the upload heap. It returns once the
+// stream was read entirely. The returned chunks are the chunks that needed
+// work, they might not be available on the network yet. nextChunk is the
+// index of the chunk following the stream's last chunk.
+func (r *Renter) managedPushStreamChunks(fileNode *filesystem.FileNode, reader io.Reader, firstChunk uint64) (chunks []*unfinishedUploadChunk, nextChunk uint64, err error) {
 	// Check if stream has at least one byte. No need to upload empty data.
 	peek := []byte{0}
 	_, err = io.ReadFull(reader, peek)
 	if errors.Contains(err, io.EOF) || errors.Contains(err, io.ErrUnexpectedEOF) {
-		return fileNode, nil
+		return nil, firstChunk, nil
 	} else if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Build a map of host public keys.
@@ -242,14 +275,14 @@ func (r *Renter) callUploadStreamFromReader(up modules.FileUploadParams, reader
 	availableWorkers := len(r.staticWorkerPool.workers)
 	r.staticWorkerPool.mu.RUnlock()
 	if availableWorkers < minWorkers {
-		return nil, fmt.Errorf("Need at least %v workers for upload but got only %v", minWorkers, availableWorkers)
+		return nil, 0, fmt.Errorf("Need at least %v workers for upload but got only %v", minWorkers, availableWorkers)
 	}
 
 	// Read the chunks we want to upload one by one from the input stream using
 	// shards. A shard will signal completion after reading the input but
 	// before the upload is done.
-	var chunks []*unfinishedUploadChunk
-	for chunkIndex := uint64(0); ; chunkIndex++ {
+	var chunkIndex uint64
+	for chunkIndex = firstChunk; ; chunkIndex++ {
 		// Disrupt the upload by closing the reader and simulating losing
 		// connectivity during the upload.
 		if r.deps.Disrupt("DisruptUploadStream") {
@@ -261,14 +294,14 @@ func (r *Renter) callUploadStreamFromReader(up modules.FileUploadParams, reader
 		// Grow the SiaFile to the right size. Otherwise buildUnfinishedChunk
 		// won't realize that there are pieces which haven't been repaired yet.
 		if err := fileNode.SiaFile.GrowNumChunks(chunkIndex + 1); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		// Start the chunk upload.
 		offline, goodForRenew, _ := r.managedContractUtilityMaps()
 		uuc, err := r.managedBuildUnfinishedChunk(fileNode, chunkIndex, hosts, pks, memoryPriorityHigh, offline, goodForRenew, r.userUploadMemoryManager)
 		if err != nil {
-			return nil, errors.AddContext(err, "unable to fetch chunk for stream")
+			return nil, 0, errors.AddContext(err, "unable to fetch chunk for stream")
 		}
 
 		// Create a new shard set it to be the source reader of the chunk.
@@ -280,14 +313,14 @@ func (r *Renter) callUploadStreamFromReader(up modules.FileUploadParams, reader
 			// Add the chunk to the upload heap's repair map.
 			pushed, err := r.managedPushChunkForRepair(uuc, chunkTypeStreamChunk)
 			if err != nil {
-				return nil, errors.AddContext(err, "unable to push chunk")
+				return nil, 0, errors.AddContext(err, "unable to push chunk")
 			}
 			if !pushed {
 				// The chunk wasn't added to the repair map meaning it must have
 				// already been in the repair map
 				_, _ = io.ReadFull(ss, make([]byte, fileNode.ChunkSize()))
 				if err := ss.Close(); err != nil {
-					return nil, err
+					return nil, 0, err
 				}
 			}
 			chunks = append(chunks, uuc)
@@ -298,13 +331,13 @@ func (r *Renter) callUploadStreamFromReader(up modules.FileUploadParams, reader
 			// since we check that anyway at the end of the loop.
 			_, _ = io.ReadFull(ss, make([]byte, fileNode.ChunkSize()))
 			if err := ss.Close(); err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 		}
 		// Wait for the shard to be read.
 		select {
 		case <-r.tg.StopChan():
-			return nil, errors.New("interrupted by shutdown")
+			return nil, 0, errors.New("interrupted by shutdown")
 		case <-ss.signalChan:
 		}
 
@@ -314,7 +347,7 @@ func (r *Renter) callUploadStreamFromReader(up modules.FileUploadParams, reader
 			// All chunks successfully submitted.
 			break
 		} else if ss.err != nil {
-			return nil, ss.err
+			return nil, 0, ss.err
 		}
 
 		// Call Peek to make sure that there's more data for another shard.
@@ -322,29 +355,9 @@ func (r *Renter) callUploadStreamFromReader(up modules.FileUploadParams, reader
 		if errors.Contains(err, io.EOF) || errors.Contains(err, io.ErrUnexpectedEOF) {
 			break
 		} else if err != nil {
-			return nil, ss.err
-		}
-	}
-
-	// Wait for all chunks to become available.
-	for _, chunk := range chunks {
-		select {
-		case <-r.tg.StopChan():
-			err = errors.New("upload timed out, renter has shutdown")
-		case <-chunk.staticAvailableChan:
-			chunk.mu.Lock()
-			err = chunk.err
-			chunk.mu.Unlock()
-		}
-		if err != nil {
-			return nil, errors.AddContext(err, "upload streamer failed to get all data available")
+			return nil, 0, ss.err
 		}
 	}
 
-	// Disrupt to force an error and ensure the fileNode is being closed
-	// correctly.
-	if r.deps.Disrupt("failUploadStreamFromReader") {
-		return nil, errors.New("disrupted by failUploadStreamFromReader")
-	}
-	return fileNode, nil
+	return chunks, chunkIndex + 1, nil
 }
@@ -0,0 +1,194 @@
+package renter
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter/filesystem/siafile"
+)
+
+var (
+	// verifyPieceTimeout is the maximum amount of time the renter waits for a
+	// host to return a piece that is verified.
+	verifyPieceTimeout = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: 5 * time.Minute,
+		Testnet:  5 * time.Minute,
+		Testing:  10 * time.Second,
+	}).(time.Duration)
+)
+
+var (
+	// errVerifyMerkleRootMismatch is returned if the data of a piece doesn't
+	// match the Merkle root in the file's metadata.
+	errVerifyMerkleRootMismatch = errors.New("piece data doesn't match the Merkle root of the file's metadata")
+
+	// errVerifyNoPieces is returned if a file without uploaded pieces is
+	// verified.
+	errVerifyNoPieces = errors.New("the file has no uploaded pieces to verify")
+)
+
+// verifyPiece is a piece of a file that is verified.
+type verifyPiece struct {
+	chunkIndex uint64
+	pieceIndex uint64
+	piece      siafile.Piece
+}
+
+// samplePieces returns n random pieces of the provided pieces. If n is 0 or
+// not smaller than the number of pieces, all pieces are returned.
+func samplePieces(pieces []verifyPiece, n uint64) []verifyPiece {
+	if n == 0 || n >= uint64(len(pieces)) {
+		return pieces
+	}
+	sample := make([]verifyPiece, 0, n)
+	for _, i := range fastrand.Perm(len(pieces))[:n] {
+		sample = append(sample, pieces[i])
+	}
+	return sample
+}
+
+// verifyPieceData checks the outcome of downloading a piece. A piece is bad if
+// the host returned data that doesn't match the piece's Merkle root. Any other
+// error means that the piece is unavailable.
+func verifyPieceData(data []byte, root crypto.Hash, err error) (bad bool, _ error) {
+	if errors.Contains(err, errReadSectorProofInvalid) {
+		return true, err
+	}
+	if err != nil {
+		return false, err
+	}
+	if uint64(len(data)) != modules.SectorSize || crypto.MerkleRoot(data) != root {
+		return true, errVerifyMerkleRootMismatch
+	}
+	return false, nil
+}
+
+// managedVerifyHostPieces verifies the pieces that are stored on a single
+// host. The hostdb is informed about every verified and every bad piece.
+func (r *Renter) managedVerifyHostPieces(hv *modules.HostVerification, pieces []verifyPiece) {
+	w, err := r.staticWorkerPool.callWorker(hv.HostPublicKey)
+	if err != nil {
+		hv.PiecesChecked = uint64(len(pieces))
+		hv.PiecesUnavailable = uint64(len(pieces))
+		hv.Error = err.Error()
+		return
+	}
+	for _, vp := range pieces {
+		ctx, cancel := context.WithTimeout(r.tg.StopCtx(), verifyPieceTimeout)
+		data, err := w.ReadSector(ctx, categoryDownload, vp.piece.MerkleRoot, 0, modules.SectorSize)
+		cancel()
+
+		hv.PiecesChecked++
+		bad, err := verifyPieceData(data, vp.piece.MerkleRoot, err)
+		var hdbErr error
+		switch {
+		case bad:
+			hv.PiecesBad++
+			hdbErr = r.hostDB.IncrementFailedInteractions(hv.HostPublicKey)
+			r.log.Printf("WARN: host %v returned bad data for piece %v of chunk %v: %v", hv.HostPublicKey, vp.pieceIndex, vp.chunkIndex, err)
+		case err != nil:
+			hv.PiecesUnavailable++
+		default:
+			hv.PiecesVerified++
+			hdbErr = r.hostDB.IncrementSuccessfulInteractions(hv.HostPublicKey)
+		}
+		if err != nil {
+			hv.Error = err.Error()
+		}
+		if hdbErr != nil {
+			r.log.Printf("Unable to update interactions of host %v: %v", hv.HostPublicKey, hdbErr)
+		}
+	}
+}
+
+// VerifyFile downloads a random sample of a file's pieces, or all of them if
+// sample is 0, and verifies them against the Merkle roots in the file's
+// metadata. The pieces of different hosts are verified in parallel. Hosts that
+// return bad data are penalized in the hostdb.
+func (r *Renter) VerifyFile(siaPath modules.SiaPath, sample uint64) (modules.FileVerification, error) {
+	if err := r.tg.Add(); err != nil {
+		return modules.FileVerification{}, err
+	}
+	defer r.tg.Done()
+	start := time.Now()
+
+	fileNode, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return modules.FileVerification{}, err
+	}
+	snap, err := fileNode.Snapshot(siaPath)
+	err = errors.Compose(err, fileNode.Close())
+	if err != nil {
+		return modules.FileVerification{}, errors.AddContext(err, "unable to create snapshot of file")
+	}
+
+	// Gather the pieces of the file and group the sample by host.
+	var pieces []verifyPiece
+	for chunkIndex := uint64(0); chunkIndex < snap.NumChunks(); chunkIndex++ {
+		for pieceIndex, pieceSet := range snap.Pieces(chunkIndex) {
+			for _, piece := range pieceSet {
+				pieces = append(pieces, verifyPiece{
+					chunkIndex: chunkIndex,
+					pieceIndex: uint64(pieceIndex),
+					piece:      piece,
+				})
+			}
+		}
+	}
+	if len(pieces) == 0 {
+		return modules.FileVerification{}, errVerifyNoPieces
+	}
+	hostPieces := make(map[string][]verifyPiece)
+	for _, vp := range samplePieces(pieces, sample) {
+		hostKey := vp.piece.HostPubKey.String()
+		hostPieces[hostKey] = append(hostPieces[hostKey], vp)
+	}
+
+	// Verify the pieces.
+	hvs := make([]modules.HostVerification, 0, len(hostPieces))
+	for _, pieces := range hostPieces {
+		hvs = append(hvs, modules.HostVerification{HostPublicKey: pieces[0].piece.HostPubKey})
+	}
+	var wg sync.WaitGroup
+	for i := range hvs {
+		wg.Add(1)
+		go func(hv *modules.HostVerification) {
+			defer wg.Done()
+			r.managedVerifyHostPieces(hv, hostPieces[hv.HostPublicKey.String()])
+		}(&hvs[i])
+	}
+	wg.Wait()
+
+	// Hosts with bad data are listed first.
+	sort.Slice(hvs, func(i, j int) bool {
+		if hvs[i].PiecesBad != hvs[j].PiecesBad {
+			return hvs[i].PiecesBad > hvs[j].PiecesBad
+		}
+		if hvs[i].PiecesUnavailable != hvs[j].PiecesUnavailable {
+			return hvs[i].PiecesUnavailable > hvs[j].PiecesUnavailable
+		}
+		return bytes.Compare(hvs[i].HostPublicKey.Key, hvs[j].HostPublicKey.Key) < 0
+	})
+	fv := modules.FileVerification{
+		SiaPath:  siaPath,
+		Hosts:    hvs,
+		Duration: time.Since(start),
+	}
+	for _, hv := range hvs {
+		fv.PiecesChecked += hv.PiecesChecked
+		fv.PiecesVerified += hv.PiecesVerified
+		fv.PiecesBad += hv.PiecesBad
+		fv.PiecesUnavailable += hv.PiecesUnavailable
+	}
+	return fv, nil
+}
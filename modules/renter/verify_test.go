@@ -0,0 +1,72 @@
+package renter
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+)
+
+// TestSamplePieces is a unit test for samplePieces.
+func TestSamplePieces(t *testing.T) {
+	t.Parallel()
+
+	pieces := make([]verifyPiece, 10)
+	for i := range pieces {
+		pieces[i].pieceIndex = uint64(i)
+	}
+
+	// A sample of 0 or more than the available pieces returns all pieces.
+	if len(samplePieces(pieces, 0)) != len(pieces) {
+		t.Fatal("sample of 0 should return all pieces")
+	}
+	if len(samplePieces(pieces, 20)) != len(pieces) {
+		t.Fatal("sample larger than the number of pieces should return all pieces")
+	}
+
+	// A smaller sample returns distinct pieces.
+	sample := samplePieces(pieces, 4)
+	if len(sample) != 4 {
+		t.Fatal("wrong sample size", len(sample))
+	}
+	seen := make(map[uint64]struct{})
+	for _, vp := range sample {
+		if _, exists := seen[vp.pieceIndex]; exists {
+			t.Fatal("piece sampled twice", vp.pieceIndex)
+		}
+		seen[vp.pieceIndex] = struct{}{}
+	}
+}
+
+// TestVerifyPieceData is a unit test for verifyPieceData.
+func TestVerifyPieceData(t *testing.T) {
+	t.Parallel()
+
+	data := fastrand.Bytes(int(modules.SectorSize))
+	root := crypto.MerkleRoot(data)
+
+	// Correct data.
+	if bad, err := verifyPieceData(data, root, nil); bad || err != nil {
+		t.Fatal("correct data should verify", bad, err)
+	}
+	// Data that doesn't match the root.
+	if bad, err := verifyPieceData(data[:crypto.SegmentSize], root, nil); !bad || !errors.Contains(err, errVerifyMerkleRootMismatch) {
+		t.Fatal("truncated data should be bad", bad, err)
+	}
+	corrupted := append([]byte{}, data...)
+	corrupted[0]++
+	if bad, err := verifyPieceData(corrupted, root, nil); !bad || !errors.Contains(err, errVerifyMerkleRootMismatch) {
+		t.Fatal("corrupted data should be bad", bad, err)
+	}
+	// An invalid proof means that the host returned bad data.
+	proofErr := errors.AddContext(errReadSectorProofInvalid, "jobReadSector: failed to execute managedRead")
+	if bad, err := verifyPieceData(nil, root, proofErr); !bad || err == nil {
+		t.Fatal("invalid proof should be bad", bad, err)
+	}
+	// Any other error means that the piece is unavailable.
+	if bad, err := verifyPieceData(nil, root, errors.New("worker unavailable")); bad || err == nil {
+		t.Fatal("failed download should be unavailable", bad, err)
+	}
+}
@@ -220,11 +220,9 @@ func (r *Renter) newWorker(hostPubKey types.SiaPublicKey) (*worker, error) {
 		return nil, errors.AddContext(err, "could not open account")
 	}
 
-	// set the balance target to 1SC
-	//
-	// TODO: check that the balance target  makes sense in function of the
-	// amount of MDM programs it can run with that amount of money
-	balanceTarget := types.SiacoinPrecision
+	// set the default balance target, the account funding policy might
+	// override it
+	balanceTarget := defaultAccountBalanceTarget
 	if r.deps.Disrupt("DisableFunding") {
 		balanceTarget = types.ZeroCurrency
 	}
@@ -116,9 +116,14 @@ type (
 		// track of this drift as in the future we might add code that acts upon
 		// it and penalizes the host if we find they are cheating us, or
 		// behaving sub-optimally.
+		//
+		// balanceLost is the part of the negative drift that the renter
+		// accepted because the host's balance fell short by more than the
+		// tolerated drift, which indicates that the host reset the account.
 		balance              types.Currency
 		balanceDriftPositive types.Currency
 		balanceDriftNegative types.Currency
+		balanceLost          types.Currency
 		pendingDeposits      types.Currency
 		pendingWithdrawals   types.Currency
 		negativeBalance      types.Currency
@@ -389,8 +394,10 @@ func (a *account) managedNeedsToRefill(target types.Currency) bool {
 // managedSyncBalance updates the account's balance related fields to "sync"
 // with the given balance, which was returned by the host. If the given balance
 // is higher or lower than the account's available balance, we update the drift
-// fields in the positive or negative direction.
-func (a *account) managedSyncBalance(balance types.Currency) {
+// fields in the positive or negative direction. If the given balance is lower
+// by more than maxDrift, the host is assumed to have reset the account. The
+// given balance is then accepted and the amount that was lost is returned.
+func (a *account) managedSyncBalance(balance, maxDrift types.Currency) (lost types.Currency) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -420,10 +427,17 @@ func (a *account) managedSyncBalance(balance types.Currency) {
 		a.balanceDriftPositive = a.balanceDriftPositive.Add(delta)
 	}
 
-	// If it's higher we only track the amount we drifted.
+	// If it's higher we track the amount we drifted. If we drifted by more
+	// than the tolerated amount, we accept the host's balance and track the
+	// difference as lost.
 	if currBalance.Cmp(balance) > 0 {
 		delta := currBalance.Sub(balance)
 		a.balanceDriftNegative = a.balanceDriftNegative.Add(delta)
+		if delta.Cmp(maxDrift) > 0 {
+			a.resetBalance(balance)
+			a.balanceLost = a.balanceLost.Add(delta)
+			lost = delta
+		}
 	}
 
 	// Persist the account
@@ -431,6 +445,7 @@ func (a *account) managedSyncBalance(balance types.Currency) {
 	if err != nil {
 		a.staticRenter.log.Printf("could not persist account, err: %v\n", err)
 	}
+	return lost
 }
 
 // managedStatus returns the status of the account
@@ -447,6 +462,8 @@ func (a *account) managedStatus() modules.WorkerAccountStatus {
 		AvailableBalance: a.availableBalance(),
 		NegativeBalance:  a.negativeBalance,
 
+		BalanceLost: a.balanceLost,
+
 		RecentErr:         recentErrStr,
 		RecentErrTime:     a.recentErrTime,
 		RecentSuccessTime: a.recentSuccessTime,
@@ -586,7 +603,11 @@ func (w *worker) externSyncAccountBalanceToHost() {
 	// Sync the account with the host's version of our balance. This will update
 	// our balance in case the host tells us we actually have more money, and it
 	// will keep track of drift in both directions.
-	w.staticAccount.managedSyncBalance(balance)
+	policy := w.staticAccountFundingPolicy()
+	lost := w.staticAccount.managedSyncBalance(balance, policy.MaxDriftTolerance)
+	if !lost.IsZero() {
+		w.renter.log.Printf("WARN: host %v reset the ephemeral account, %v were lost", w.staticHostPubKeyStr, lost.HumanString())
+	}
 
 	// TODO perform a thorough balance comparison to decide whether the drift in
 	// the account balance is warranted. If not the host needs to be penalized
@@ -607,7 +628,7 @@ func (w *worker) managedNeedsToRefillAccount() bool {
 		return false
 	}
 
-	return w.staticAccount.managedNeedsToRefill(w.staticBalanceTarget.Div64(2))
+	return w.staticAccount.managedNeedsToRefill(w.staticAccountFundingPolicy().RefillThreshold)
 }
 
 // managedNeedsToSyncAccountBalanceToHost returns true if the renter needs to
@@ -631,11 +652,15 @@ func (w *worker) managedRefillAccount() {
 	if w.renter.deps.Disrupt("DisableFunding") {
 		return // don't refill account
 	}
-	// The account balance dropped to below half the balance target, refill. Use
+	// The account balance dropped to below the refill threshold, refill. Use
 	// the max expected balance when refilling to avoid exceeding any host
-	// maximums.
+	// maximums. The balance might exceed the target if the target was lowered.
+	policy := w.staticAccountFundingPolicy()
 	balance := w.staticAccount.managedMaxExpectedBalance()
-	amount := w.staticBalanceTarget.Sub(balance)
+	if balance.Cmp(policy.BalanceTarget) >= 0 {
+		return
+	}
+	amount := policy.BalanceTarget.Sub(balance)
 	pt := w.staticPriceTable().staticPriceTable
 
 	// If the target amount is larger than the remaining money, adjust the
@@ -695,7 +720,7 @@ func (w *worker) managedRefillAccount() {
 	}()
 
 	// check the current price table for gouging errors
-	err = checkFundAccountGouging(w.staticPriceTable().staticPriceTable, w.staticCache().staticRenterAllowance, w.staticBalanceTarget)
+	err = checkFundAccountGouging(w.staticPriceTable().staticPriceTable, w.staticCache().staticRenterAllowance, policy.BalanceTarget)
 	if err != nil {
 		return
 	}
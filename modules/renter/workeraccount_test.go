@@ -224,7 +224,7 @@ func testAccountSyncBalance(t *testing.T) {
 	a.negativeBalance = oneCurrency
 	a.pendingDeposits = oneCurrency
 	a.pendingWithdrawals = oneCurrency
-	a.managedSyncBalance(oneCurrency)
+	a.managedSyncBalance(oneCurrency, oneCurrency)
 
 	if !a.balance.Equals(oneCurrency) {
 		t.Fatal("unexpected balance after reset", a.balance)
@@ -245,11 +245,25 @@ func testAccountSyncBalance(t *testing.T) {
 		t.Fatal("unexpected sync at")
 	}
 
-	// verify negative drift gets updated properly as well
-	a.managedSyncBalance(types.ZeroCurrency)
+	// verify negative drift gets updated properly as well, a drift within the
+	// tolerance doesn't change the balance
+	lost := a.managedSyncBalance(types.ZeroCurrency, oneCurrency)
 	if !a.balanceDriftPositive.Equals(oneCurrency) || !a.balanceDriftNegative.Equals(oneCurrency) {
 		t.Fatal("unexpected drift")
 	}
+	if !lost.IsZero() || !a.balanceLost.IsZero() || !a.balance.Equals(oneCurrency) {
+		t.Fatal("drift within tolerance shouldn't be lost", lost, a.balanceLost, a.balance)
+	}
+
+	// verify a drift beyond the tolerance resets the balance and is tracked as
+	// lost
+	lost = a.managedSyncBalance(types.ZeroCurrency, types.ZeroCurrency)
+	if !a.balanceDriftNegative.Equals(oneCurrency.Mul64(2)) {
+		t.Fatal("unexpected drift", a.balanceDriftNegative)
+	}
+	if !lost.Equals(oneCurrency) || !a.balanceLost.Equals(oneCurrency) || !a.balance.IsZero() {
+		t.Fatal("drift beyond tolerance should be lost", lost, a.balanceLost, a.balance)
+	}
 }
 
 // testAccountTrackSpending is a small unit test that verifies the functionality
@@ -103,6 +103,11 @@ type (
 		SpendingSnapshotUploads   types.Currency
 		SpendingSubscriptions     types.Currency
 		SpendingUploads           types.Currency
+
+		// BalanceLost is the money that was lost to the host resetting the
+		// account. It was added after the spending details, accounts that
+		// were persisted before decode it as zero.
+		BalanceLost types.Currency
 	}
 
 	// accountPersistenceV150 is how the account persistence struct looked
@@ -150,6 +155,7 @@ func (a *account) persist() error {
 		Balance:              a.minExpectedBalance(),
 		BalanceDriftPositive: a.balanceDriftPositive,
 		BalanceDriftNegative: a.balanceDriftNegative,
+		BalanceLost:          a.balanceLost,
 
 		// spending details
 		SpendingDownloads:         a.spending.downloads,
@@ -555,6 +561,7 @@ func (am *accountManager) readAccountAt(offset int64) (*account, error) {
 		balance:              accountData.Balance,
 		balanceDriftPositive: accountData.BalanceDriftPositive,
 		balanceDriftNegative: accountData.BalanceDriftNegative,
+		balanceLost:          accountData.BalanceLost,
 
 		// spending details
 		spending: spendingDetails{
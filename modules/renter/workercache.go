@@ -34,6 +34,8 @@ type (
 		staticHostMuxAddress  string
 		staticSynced          bool
 
+		staticAccountFundingPolicy modules.AccountFundingPolicy
+
 		staticLastUpdate time.Time
 	}
 )
@@ -80,6 +82,8 @@ func (w *worker) managedUpdateCache() {
 		staticRenterAllowance: w.renter.hostContractor.Allowance(),
 		staticSynced:          w.renter.cs.Synced(),
 
+		staticAccountFundingPolicy: w.renter.managedHostAccountFundingPolicy(w.staticHostPubKey),
+
 		staticLastUpdate: time.Now(),
 	}
 
@@ -220,6 +220,7 @@ func (w *worker) managedProcessDownloadChunk(udc *unfinishedDownloadChunk) *unfi
 	pieceData, workerHasPiece := udc.staticChunkMap[w.staticHostPubKey.String()]
 	pieceCompleted := udc.completedPieces[pieceData.index]
 	if chunkComplete || chunkFailed || onCooldown || !workerHasPiece || pieceCompleted {
+		delete(udc.preferredPending, w.staticHostPubKey.String())
 		udc.mu.Unlock()
 		udc.managedRemoveWorker()
 
@@ -272,13 +273,27 @@ func (w *worker) managedProcessDownloadChunk(udc *unfinishedDownloadChunk) *unfi
 	// finished.
 	pieceTaken := udc.pieceUsage[pieceData.index]
 	piecesInProgress := udc.piecesRegistered + udc.piecesCompleted
-	desiredPiecesInProgress := udc.erasureCode.MinPieces() + udc.staticOverdrive
+	desiredPiecesInProgress := udc.erasureCode.MinPieces() + udc.overdrive()
 	workersDesired := piecesInProgress < desiredPiecesInProgress && !pieceTaken
 
+	// If the download prefers a subset of the hosts, e.g. because they are
+	// cheaper, workers of other hosts are put on standby as long as the
+	// preferred workers that didn't process the chunk yet are enough to reach
+	// the desired number of pieces.
+	if udc.staticPreferredHosts != nil {
+		hostKey := w.staticHostPubKey.String()
+		if _, preferred := udc.staticPreferredHosts[hostKey]; preferred {
+			delete(udc.preferredPending, hostKey)
+		} else if piecesInProgress+len(udc.preferredPending) >= desiredPiecesInProgress {
+			meetsExtraCriteria = false
+		}
+	}
+
 	if workersDesired && meetsExtraCriteria {
 		// Worker can be useful. Register the worker and return the chunk for
 		// downloading.
 		udc.piecesRegistered++
+		udc.piecesLaunched++
 		udc.pieceUsage[pieceData.index] = true
 		return udc
 	}
@@ -8,6 +8,7 @@ import (
 
 	"gitlab.com/NebulousLabs/errors"
 	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
 )
 
 var (
@@ -19,6 +20,12 @@ var (
 	ErrJobDiscarded = errors.New("job is being discarded")
 )
 
+const (
+	// jobRecentTimesLen is the number of recent job times a job queue keeps
+	// track of for reporting them in the worker status.
+	jobRecentTimesLen = 10
+)
+
 type (
 	// jobGeneric implements the basic functionality for a job.
 	jobGeneric struct {
@@ -51,6 +58,11 @@ type (
 		recentErr           error
 		recentErrTime       time.Time
 
+		// recentJobTimes contains the durations of the most recent successful
+		// jobs, oldest first.
+		recentJobTimes []time.Duration
+
+		staticJobType   modules.WorkerJobType
 		staticWorkerObj *worker // name conflict with staticWorker method
 		mu              sync.Mutex
 	}
@@ -105,6 +117,7 @@ type (
 		consecutiveFailures uint64
 		recentErr           error
 		recentErrTime       time.Time
+		recentJobTimes      []time.Duration
 	}
 )
 
@@ -130,9 +143,10 @@ func newJobGeneric(ctx context.Context, queue workerJobQueue, metadata interface
 }
 
 // newJobGenericQueue will return an initialized generic job queue.
-func newJobGenericQueue(w *worker) *jobGenericQueue {
+func newJobGenericQueue(w *worker, jobType modules.WorkerJobType) *jobGenericQueue {
 	return &jobGenericQueue{
 		jobs:            list.New(),
+		staticJobType:   jobType,
 		staticWorkerObj: w,
 	}
 }
@@ -233,12 +247,32 @@ func (jq *jobGenericQueue) callReportFailure(err error) {
 	jq.mu.Lock()
 	defer jq.mu.Unlock()
 
+	// Remember the failure in the renter's job history before adding context
+	// which is the same for every failure.
+	failure := modules.WorkerJobFailure{
+		JobType:   jq.staticJobType,
+		Error:     err.Error(),
+		ErrorCode: workerJobErrorCode(err),
+		Time:      time.Now(),
+	}
+
 	err = errors.AddContext(err, "discarding all jobs in this queue and going on cooldown")
 	jq.discardAll(err)
 	jq.cooldownUntil = cooldownUntil(jq.consecutiveFailures)
 	jq.consecutiveFailures++
 	jq.recentErr = err
-	jq.recentErrTime = time.Now()
+	jq.recentErrTime = failure.Time
+
+	failure.ConsecutiveFailures = jq.consecutiveFailures
+	failure.CooldownUntil = jq.cooldownUntil
+	jq.staticWorkerObj.callRecordJobFailure(failure)
+}
+
+// callRecordJobTime records the duration of a successful job.
+func (jq *jobGenericQueue) callRecordJobTime(jobTime time.Duration) {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+	jq.recordJobTime(jobTime)
 }
 
 // callReportSuccess lets the job queue know that there was a successsful job.
@@ -262,6 +296,7 @@ func (jq *jobGenericQueue) callStatus() workerJobQueueStatus {
 		consecutiveFailures: jq.consecutiveFailures,
 		recentErr:           jq.recentErr,
 		recentErrTime:       jq.recentErrTime,
+		recentJobTimes:      append([]time.Duration{}, jq.recentJobTimes...),
 	}
 }
 
@@ -274,6 +309,15 @@ func (jq *jobGenericQueue) discardAll(err error) {
 	jq.jobs = list.New()
 }
 
+// recordJobTime adds the duration of a successful job to the recent job times
+// of the queue, dropping the oldest one if necessary.
+func (jq *jobGenericQueue) recordJobTime(jobTime time.Duration) {
+	if len(jq.recentJobTimes) >= jobRecentTimesLen {
+		jq.recentJobTimes = jq.recentJobTimes[len(jq.recentJobTimes)-jobRecentTimesLen+1:]
+	}
+	jq.recentJobTimes = append(jq.recentJobTimes, jobTime)
+}
+
 // staticWorker will return the worker that is associated with this job queue.
 func (jq *jobGenericQueue) staticWorker() *worker {
 	return jq.staticWorkerObj
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
 	"golang.org/x/net/context"
 
 	"gitlab.com/NebulousLabs/errors"
@@ -129,7 +130,7 @@ func TestWorkerJobGeneric(t *testing.T) {
 	// Create a job queue.
 	w := new(worker)
 	w.renter = new(Renter)
-	jq := newJobGenericQueue(w)
+	jq := newJobGenericQueue(w, modules.WorkerJobRead)
 	cancelCtx, cancel := context.WithCancel(context.Background())
 
 	// Create a job, add the job to the queue, and then ensure that the
@@ -488,7 +489,7 @@ func TestQueueMemoryLeak(t *testing.T) {
 	// Create queue.
 	w := new(worker)
 	w.renter = new(Renter)
-	jq := newJobGenericQueue(w)
+	jq := newJobGenericQueue(w, modules.WorkerJobRead)
 
 	// Prepare a job.
 	cancelCtx, cancel := context.WithCancel(context.Background())
@@ -529,3 +530,49 @@ func TestQueueMemoryLeak(t *testing.T) {
 	runtime.ReadMemStats(&ms)
 	t.Log("after gc", ms.HeapObjects, ms.HeapAlloc)
 }
+
+// TestJobGenericRecentJobTimes tests that a job queue keeps track of the most
+// recent job times.
+func TestJobGenericRecentJobTimes(t *testing.T) {
+	t.Parallel()
+
+	w := new(worker)
+	w.renter = new(Renter)
+	jq := newJobGenericQueue(w, modules.WorkerJobRead)
+	if times := jq.callStatus().recentJobTimes; len(times) != 0 {
+		t.Fatal("new queue shouldn't have recent job times", times)
+	}
+
+	// Record fewer times than the queue keeps track of.
+	jq.callRecordJobTime(time.Second)
+	jq.callRecordJobTime(2 * time.Second)
+	if times := jq.callStatus().recentJobTimes; !reflect.DeepEqual(times, []time.Duration{time.Second, 2 * time.Second}) {
+		t.Fatal("wrong recent job times", times)
+	}
+
+	// Record more times than the queue keeps track of. Only the most recent
+	// ones should be kept, oldest first.
+	for i := 3; i <= jobRecentTimesLen+5; i++ {
+		jq.callRecordJobTime(time.Duration(i) * time.Second)
+	}
+	times := jq.callStatus().recentJobTimes
+	if len(times) != jobRecentTimesLen {
+		t.Fatal("wrong number of recent job times", len(times))
+	}
+	for i, jt := range times {
+		if expected := time.Duration(i+6) * time.Second; jt != expected {
+			t.Fatalf("expected job time %v at index %v but got %v", expected, i, jt)
+		}
+	}
+
+	// The returned times shouldn't be affected by recording new ones.
+	jq.callRecordJobTime(time.Hour)
+	if times[len(times)-1] != time.Duration(jobRecentTimesLen+5)*time.Second {
+		t.Fatal("status was modified by recording a job time")
+	}
+
+	// The times are reported in milliseconds.
+	if ms := recentJobTimesInMs(times[:2]); !reflect.DeepEqual(ms, []uint64{6000, 7000}) {
+		t.Fatal("wrong job times in ms", ms)
+	}
+}
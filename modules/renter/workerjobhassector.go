@@ -119,6 +119,7 @@ func (j *jobHasSector) callExecute() {
 	// Job was a success, update the performance stats on the queue.
 	jq := j.staticQueue.(*jobHasSectorQueue)
 	jq.callUpdateJobTimeMetrics(jobTime)
+	jq.callRecordJobTime(jobTime)
 }
 
 // callExpectedBandwidth returns the bandwidth that is expected to be consumed
@@ -217,7 +218,7 @@ func (w *worker) initJobHasSectorQueue() {
 	}
 
 	w.staticJobHasSectorQueue = &jobHasSectorQueue{
-		jobGenericQueue: newJobGenericQueue(w),
+		jobGenericQueue: newJobGenericQueue(w, modules.WorkerJobHasSector),
 	}
 }
 
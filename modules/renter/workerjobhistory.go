@@ -0,0 +1,214 @@
+package renter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/siamux/mux"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+)
+
+const (
+	// maxWorkerJobFailures is the number of failed worker jobs the renter
+	// keeps track of. Once the history is full, the oldest failures are
+	// dropped.
+	maxWorkerJobFailures = 1000
+
+	// workerJobHistoryFilename is the name of the file the renter persists the
+	// worker job history in.
+	workerJobHistoryFilename = "workerjobhistory.json"
+)
+
+var (
+	// workerJobHistoryMetadata is the metadata of the worker job history
+	// persist file.
+	workerJobHistoryMetadata = persist.Metadata{
+		Header:  "Renter Worker Job History",
+		Version: "1.5.5",
+	}
+
+	// workerJobHistorySaveInterval is how often the worker job history is
+	// saved to disk if new failures were recorded. Failures are batched since
+	// an unreachable host can cause many failures in a short period of time.
+	workerJobHistorySaveInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: time.Minute * 5,
+		Testnet:  time.Minute * 5,
+		Testing:  time.Second,
+	}).(time.Duration)
+)
+
+// workerJobHistory is a bounded history of the renter's failed worker jobs.
+type workerJobHistory struct {
+	// failures are ordered from oldest to newest.
+	failures []modules.WorkerJobFailure
+	dirty    bool
+
+	staticPersistPath string
+	mu                sync.Mutex
+}
+
+// newWorkerJobHistory loads the worker job history from disk or initializes an
+// empty one.
+func newWorkerJobHistory(persistDir string) (*workerJobHistory, error) {
+	wjh := &workerJobHistory{
+		staticPersistPath: filepath.Join(persistDir, workerJobHistoryFilename),
+	}
+	err := persist.LoadJSON(workerJobHistoryMetadata, &wjh.failures, wjh.staticPersistPath)
+	if os.IsNotExist(err) {
+		return wjh, nil
+	}
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to load worker job history")
+	}
+	return wjh, nil
+}
+
+// callAdd adds a failure to the history, dropping the oldest one if necessary.
+func (wjh *workerJobHistory) callAdd(failure modules.WorkerJobFailure) {
+	wjh.mu.Lock()
+	defer wjh.mu.Unlock()
+	wjh.failures = append(wjh.failures, failure)
+	if len(wjh.failures) > maxWorkerJobFailures {
+		wjh.failures = wjh.failures[len(wjh.failures)-maxWorkerJobFailures:]
+	}
+	wjh.dirty = true
+}
+
+// callFailures returns the failures matching the filter, newest first.
+func (wjh *workerJobHistory) callFailures(filter modules.WorkerJobFailureFilter) []modules.WorkerJobFailure {
+	wjh.mu.Lock()
+	defer wjh.mu.Unlock()
+	failures := make([]modules.WorkerJobFailure, 0)
+	for i := len(wjh.failures) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(failures) >= filter.Limit {
+			break
+		}
+		f := wjh.failures[i]
+		if len(filter.HostPubKey.Key) != 0 && !f.HostPubKey.Equals(filter.HostPubKey) {
+			continue
+		}
+		if filter.JobType != "" && f.JobType != filter.JobType {
+			continue
+		}
+		if filter.ErrorCode != "" && f.ErrorCode != filter.ErrorCode {
+			continue
+		}
+		if !filter.Since.IsZero() && f.Time.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && f.Time.After(filter.Until) {
+			continue
+		}
+		failures = append(failures, f)
+	}
+	return failures
+}
+
+// callSave persists the history if it changed since it was last saved.
+func (wjh *workerJobHistory) callSave() error {
+	wjh.mu.Lock()
+	defer wjh.mu.Unlock()
+	if !wjh.dirty {
+		return nil
+	}
+	err := persist.SaveJSON(workerJobHistoryMetadata, wjh.failures, wjh.staticPersistPath)
+	if err != nil {
+		return err
+	}
+	wjh.dirty = false
+	return nil
+}
+
+// errCausedByTimeout returns true if the error is caused by a timeout while
+// communicating with a host.
+func errCausedByTimeout(err error) bool {
+	if errors.Contains(err, mux.ErrStreamTimedOut) {
+		return true
+	}
+	switch e := err.(type) {
+	case errors.Error:
+		for _, err := range e.ErrSet {
+			if errCausedByTimeout(err) {
+				return true
+			}
+		}
+	case interface{ Timeout() bool }:
+		return e.Timeout()
+	}
+	return false
+}
+
+// errCausedByInsufficientBalance returns true if the error is caused by the
+// host rejecting a payment from an ephemeral account with an insufficient
+// balance. Errors returned by the host only retain their message, so the
+// message of the host's error is matched.
+func errCausedByInsufficientBalance(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "ephemeral account balance was insufficient")
+}
+
+// workerJobErrorCode classifies the error a worker job failed with.
+func workerJobErrorCode(err error) modules.WorkerJobErrorCode {
+	switch {
+	case errCausedByTimeout(err):
+		return modules.WorkerJobErrorTimeout
+	case errCausedByInsufficientBalance(err):
+		return modules.WorkerJobErrorInsufficientBalance
+	case errCausedByRevisionMismatch(err):
+		return modules.WorkerJobErrorRevisionMismatch
+	case errors.Contains(err, errReadSectorProofInvalid),
+		errors.Contains(err, errReadOffsetProofInvalid),
+		errors.Contains(err, errReadRegistrySignatureInvalid),
+		errors.Contains(err, errHostOutdatedProof),
+		errors.Contains(err, errHostLowerRevisionThanCache):
+		return modules.WorkerJobErrorInvalidResponse
+	default:
+		return modules.WorkerJobErrorOther
+	}
+}
+
+// callRecordJobFailure adds a failed job of the worker to the renter's worker
+// job history.
+func (w *worker) callRecordJobFailure(failure modules.WorkerJobFailure) {
+	// Workers created by unit tests might not have a renter or history.
+	if w == nil || w.renter == nil || w.renter.staticWorkerJobHistory == nil {
+		return
+	}
+	failure.HostPubKey = w.staticHostPubKey
+	w.renter.staticWorkerJobHistory.callAdd(failure)
+}
+
+// WorkerJobHistory returns the failed worker jobs matching the filter, newest
+// first.
+func (r *Renter) WorkerJobHistory(filter modules.WorkerJobFailureFilter) []modules.WorkerJobFailure {
+	return r.staticWorkerJobHistory.callFailures(filter)
+}
+
+// threadedSaveWorkerJobHistory periodically saves the worker job history. The
+// history is saved one last time when the renter shuts down.
+func (r *Renter) threadedSaveWorkerJobHistory() {
+	if err := r.tg.Add(); err != nil {
+		return
+	}
+	defer r.tg.Done()
+	for {
+		select {
+		case <-r.tg.StopChan():
+			if err := r.staticWorkerJobHistory.callSave(); err != nil {
+				r.log.Println("Unable to save worker job history:", err)
+			}
+			return
+		case <-time.After(workerJobHistorySaveInterval):
+		}
+		if err := r.staticWorkerJobHistory.callSave(); err != nil {
+			r.log.Println("Unable to save worker job history:", err)
+		}
+	}
+}
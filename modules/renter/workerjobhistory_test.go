@@ -0,0 +1,128 @@
+package renter
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/siamux/mux"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestWorkerJobHistory is a unit test for filtering, bounding and persisting
+// the worker job history.
+func TestWorkerJobHistory(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir("renter", t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	wjh, err := newWorkerJobHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add failures for two hosts and job types, one second apart.
+	host1 := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{1}}
+	host2 := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{2}}
+	start := time.Unix(1000, 0)
+	for i := 0; i < 4; i++ {
+		f := modules.WorkerJobFailure{
+			HostPubKey: host1,
+			JobType:    modules.WorkerJobRead,
+			Time:       start.Add(time.Duration(i) * time.Second),
+		}
+		if i%2 == 1 {
+			f.HostPubKey = host2
+			f.JobType = modules.WorkerJobHasSector
+		}
+		if i < 2 {
+			f.ErrorCode = modules.WorkerJobErrorTimeout
+		}
+		wjh.callAdd(f)
+	}
+
+	tests := []struct {
+		filter   modules.WorkerJobFailureFilter
+		expected []int64
+	}{
+		{modules.WorkerJobFailureFilter{}, []int64{1003, 1002, 1001, 1000}},
+		{modules.WorkerJobFailureFilter{HostPubKey: host1}, []int64{1002, 1000}},
+		{modules.WorkerJobFailureFilter{JobType: modules.WorkerJobHasSector}, []int64{1003, 1001}},
+		{modules.WorkerJobFailureFilter{ErrorCode: modules.WorkerJobErrorTimeout}, []int64{1001, 1000}},
+		{modules.WorkerJobFailureFilter{ErrorCode: modules.WorkerJobErrorOther}, []int64{}},
+		{modules.WorkerJobFailureFilter{Since: start.Add(time.Second), Until: start.Add(2 * time.Second)}, []int64{1002, 1001}},
+		{modules.WorkerJobFailureFilter{Limit: 1}, []int64{1003}},
+		{modules.WorkerJobFailureFilter{HostPubKey: host1, JobType: modules.WorkerJobHasSector}, []int64{}},
+	}
+	for _, test := range tests {
+		failures := wjh.callFailures(test.filter)
+		if len(failures) != len(test.expected) {
+			t.Fatalf("%+v: expected %v failures but got %v", test.filter, len(test.expected), len(failures))
+		}
+		for i, f := range failures {
+			if f.Time.Unix() != test.expected[i] {
+				t.Fatalf("%+v: expected failure at %v but got %v", test.filter, test.expected[i], f.Time.Unix())
+			}
+		}
+	}
+
+	// Save and reload the history.
+	if err := wjh.callSave(); err != nil {
+		t.Fatal(err)
+	}
+	if wjh.dirty {
+		t.Fatal("history should be clean after saving")
+	}
+	wjh2, err := newWorkerJobHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures := wjh2.callFailures(modules.WorkerJobFailureFilter{})
+	if len(failures) != 4 || !failures[0].HostPubKey.Equals(host2) || failures[0].JobType != modules.WorkerJobHasSector {
+		t.Fatal("history wasn't persisted", failures)
+	}
+
+	// The history is bounded.
+	for i := 0; i < maxWorkerJobFailures; i++ {
+		wjh2.callAdd(modules.WorkerJobFailure{Time: start.Add(time.Hour)})
+	}
+	if len(wjh2.failures) != maxWorkerJobFailures {
+		t.Fatal("history exceeds its bound", len(wjh2.failures))
+	}
+	if failures := wjh2.callFailures(modules.WorkerJobFailureFilter{HostPubKey: host1}); len(failures) != 0 {
+		t.Fatal("oldest failures should have been dropped", failures)
+	}
+}
+
+// TestWorkerJobErrorCode is a unit test for workerJobErrorCode.
+func TestWorkerJobErrorCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err      error
+		expected modules.WorkerJobErrorCode
+	}{
+		{errors.AddContext(mux.ErrStreamTimedOut, "could not read response"), modules.WorkerJobErrorTimeout},
+		{errors.Compose(errors.New("program failed"), context.DeadlineExceeded), modules.WorkerJobErrorTimeout},
+		{errors.AddContext(errors.New("ephemeral account balance was insufficient"), "could not withdraw"), modules.WorkerJobErrorInsufficientBalance},
+		{errors.New("bad revision number"), modules.WorkerJobErrorRevisionMismatch},
+		{errors.AddContext(errReadSectorProofInvalid, "read failed"), modules.WorkerJobErrorInvalidResponse},
+		{errHostLowerRevisionThanCache, modules.WorkerJobErrorInvalidResponse},
+		{errors.New("host refused the request"), modules.WorkerJobErrorOther},
+	}
+	for _, test := range tests {
+		if code := workerJobErrorCode(test.err); code != test.expected {
+			t.Errorf("%q: expected %v but got %v", test.err, test.expected, code)
+		}
+	}
+}
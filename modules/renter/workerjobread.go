@@ -144,6 +144,7 @@ func (j *jobRead) managedFinishExecute(readData []byte, readErr error, readJobTi
 	// failures stat can be reset.
 	jq := j.staticQueue.(*jobReadQueue)
 	jq.callUpdateJobTimeMetrics(j.staticLength, readJobTime)
+	jq.callRecordJobTime(readJobTime)
 }
 
 // callExpectedBandwidth returns the bandwidth that gets consumed by a
@@ -270,7 +271,7 @@ func (w *worker) initJobReadQueue() {
 		w.renter.log.Critical("incorret call on initJobReadQueue")
 	}
 	w.staticJobReadQueue = &jobReadQueue{
-		jobGenericQueue: newJobGenericQueue(w),
+		jobGenericQueue: newJobGenericQueue(w, modules.WorkerJobRead),
 	}
 }
 
@@ -282,6 +283,6 @@ func (w *worker) initJobLowPrioReadQueue() {
 		w.renter.log.Critical("incorret call on initJobReadQueue")
 	}
 	w.staticJobLowPrioReadQueue = &jobReadQueue{
-		jobGenericQueue: newJobGenericQueue(w),
+		jobGenericQueue: newJobGenericQueue(w, modules.WorkerJobLowPrioRead),
 	}
 }
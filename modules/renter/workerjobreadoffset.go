@@ -10,6 +10,12 @@ import (
 	"go.sia.tech/siad/modules"
 )
 
+var (
+	// errReadOffsetProofInvalid is returned if the data returned by a host
+	// doesn't match the proof for the requested range of the contract.
+	errReadOffsetProofInvalid = errors.New("verifying proof failed")
+)
+
 type (
 	// jobReadOffset contains information about a ReadOffset job.
 	jobReadOffset struct {
@@ -92,7 +98,7 @@ func (j *jobReadOffset) managedReadOffset() ([]byte, error) {
 	proofEnd := int(j.staticOffset+j.staticLength) / crypto.SegmentSize
 	ok = crypto.VerifyMixedRangeProof(downloadResponse.Output, downloadResponse.Proof, rev.NewFileMerkleRoot, proofStart, proofEnd)
 	if !ok {
-		return nil, errors.New("verifying proof failed")
+		return nil, errReadOffsetProofInvalid
 	}
 	return downloadResponse.Output, nil
 }
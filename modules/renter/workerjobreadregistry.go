@@ -26,6 +26,12 @@ const (
 	jobReadRegistryPerformanceDecay = 0.9
 )
 
+var (
+	// errReadRegistrySignatureInvalid is returned if the signature of a
+	// registry value returned by a host is invalid.
+	errReadRegistrySignatureInvalid = errors.New("failed to verify returned registry value's signature")
+)
+
 type (
 	// jobReadRegistry contains information about a ReadRegistry query.
 	jobReadRegistry struct {
@@ -140,7 +146,7 @@ func lookupRegistry(w *worker, spk types.SiaPublicKey, tweak crypto.Hash) (*modu
 
 	// Verify signature.
 	if rv.Verify(spk.ToPublicKey()) != nil {
-		return nil, errors.New("failed to verify returned registry value's signature")
+		return nil, errReadRegistrySignatureInvalid
 	}
 	return &rv, nil
 }
@@ -234,6 +240,7 @@ func (j *jobReadRegistry) callExecute() {
 	jq := j.staticQueue.(*jobReadRegistryQueue)
 	jq.mu.Lock()
 	jq.weightedJobTime = expMovingAvg(jq.weightedJobTime, float64(jobTime), jobReadRegistryPerformanceDecay)
+	jq.recordJobTime(jobTime)
 	jq.mu.Unlock()
 }
 
@@ -252,7 +259,7 @@ func (w *worker) initJobReadRegistryQueue() {
 	}
 
 	w.staticJobReadRegistryQueue = &jobReadRegistryQueue{
-		jobGenericQueue: newJobGenericQueue(w),
+		jobGenericQueue: newJobGenericQueue(w, modules.WorkerJobReadRegistry),
 	}
 }
 
@@ -9,6 +9,12 @@ import (
 	"go.sia.tech/siad/modules"
 )
 
+var (
+	// errReadSectorProofInvalid is returned if the data returned by a host
+	// doesn't match the Merkle root of the requested sector.
+	errReadSectorProofInvalid = errors.New("proof verification failed")
+)
+
 type (
 	// jobReadSector contains information about a readSector query.
 	jobReadSector struct {
@@ -56,7 +62,7 @@ func (j *jobReadSector) managedReadSector() ([]byte, error) {
 	proofStart := int(j.staticOffset) / crypto.SegmentSize
 	proofEnd := int(j.staticOffset+j.staticLength) / crypto.SegmentSize
 	if !crypto.VerifyRangeProof(data, proof, proofStart, proofEnd, j.staticSector) {
-		return nil, errors.New("proof verification failed")
+		return nil, errReadSectorProofInvalid
 	}
 	return data, nil
 }
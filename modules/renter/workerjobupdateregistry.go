@@ -167,6 +167,7 @@ func (j *jobUpdateRegistry) callExecute() {
 	jq := j.staticQueue.(*jobUpdateRegistryQueue)
 	jq.mu.Lock()
 	jq.weightedJobTime = expMovingAvg(jq.weightedJobTime, float64(jobTime), jobUpdateRegistryPerformanceDecay)
+	jq.recordJobTime(jobTime)
 	jq.mu.Unlock()
 }
 
@@ -247,7 +248,7 @@ func (w *worker) initJobUpdateRegistryQueue() {
 	}
 
 	w.staticJobUpdateRegistryQueue = &jobUpdateRegistryQueue{
-		jobGenericQueue: newJobGenericQueue(w),
+		jobGenericQueue: newJobGenericQueue(w, modules.WorkerJobUpdateRegistry),
 	}
 }
 
@@ -205,7 +205,7 @@ func (w *worker) initJobUploadSnapshotQueue() {
 	}
 
 	w.staticJobUploadSnapshotQueue = &jobUploadSnapshotQueue{
-		jobGenericQueue: newJobGenericQueue(w),
+		jobGenericQueue: newJobGenericQueue(w, modules.WorkerJobUploadSnapshot),
 	}
 }
 
@@ -265,7 +265,16 @@ func (r *Renter) managedUploadSnapshotHost(meta modules.UploadedBackup, dotSia [
 	}
 
 	shouldOverwrite := len(entryTable) != 0 // only overwrite if the sector already contained an entryTable
-	entryTable = append(entryTable, entry)
+
+	// drop the entries of pruned snapshots from the table
+	pruned := r.managedPrunedBackups()
+	filtered := entryTable[:0]
+	for _, e := range entryTable {
+		if _, isPruned := pruned[e.UID]; !isPruned {
+			filtered = append(filtered, e)
+		}
+	}
+	entryTable = append(filtered, entry)
 
 	// if entryTable is too large to fit in a sector, repeatedly remove the
 	// oldest entry until it fits
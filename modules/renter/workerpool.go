@@ -71,6 +71,7 @@ func (wp *workerPool) callStatus() modules.WorkerPoolStatus {
 // necessary.
 func (wp *workerPool) callUpdate() {
 	contractSlice := wp.renter.hostContractor.Contracts()
+	wp.renter.staticEvents.callUpdateContracts(contractSlice)
 	contractMap := make(map[string]modules.RenterContract, len(contractSlice))
 	for _, contract := range contractSlice {
 		if contract.Utility.BadContract {
@@ -69,14 +69,17 @@ func (w *worker) callStatus() modules.WorkerStatus {
 		MaintenanceCoolDownTime:  maintenanceCoolDownTime,
 
 		// Account Information
-		AccountBalanceTarget: w.staticBalanceTarget,
+		AccountBalanceTarget: w.staticAccountFundingPolicy().BalanceTarget,
 		AccountStatus:        w.staticAccount.managedStatus(),
 
 		// Price Table Information
 		PriceTableStatus: w.staticPriceTableStatus(),
 
 		// Read Job Information
-		ReadJobsStatus: w.callReadJobStatus(),
+		ReadJobsStatus: callReadJobStatus(w.staticJobReadQueue),
+
+		// Low Priority Read Job Information
+		LowPrioReadJobsStatus: callReadJobStatus(w.staticJobLowPrioReadQueue),
 
 		// HasSector Job Information
 		HasSectorJobsStatus: w.callHasSectorJobStatus(),
@@ -109,9 +112,8 @@ func (w *worker) staticPriceTableStatus() modules.WorkerPriceTableStatus {
 	}
 }
 
-// callReadJobStatus returns the status of the read job queue
-func (w *worker) callReadJobStatus() modules.WorkerReadJobsStatus {
-	jrq := w.staticJobReadQueue
+// callReadJobStatus returns the status of a read job queue
+func callReadJobStatus(jrq *jobReadQueue) modules.WorkerReadJobsStatus {
 	status := jrq.callStatus()
 
 	var recentErrString string
@@ -134,6 +136,7 @@ func (w *worker) callReadJobStatus() modules.WorkerReadJobsStatus {
 		JobQueueSize:        status.size,
 		RecentErr:           recentErrString,
 		RecentErrTime:       status.recentErrTime,
+		RecentJobTimes:      recentJobTimesInMs(status.recentJobTimes),
 	}
 }
 
@@ -155,6 +158,7 @@ func (w *worker) callHasSectorJobStatus() modules.WorkerHasSectorJobsStatus {
 		JobQueueSize:        status.size,
 		RecentErr:           recentErrStr,
 		RecentErrTime:       status.recentErrTime,
+		RecentJobTimes:      recentJobTimesInMs(status.recentJobTimes),
 	}
 }
 
@@ -174,7 +178,18 @@ func callGenericWorkerJobStatus(queue *jobGenericQueue) modules.WorkerGenericJob
 		OnCooldownUntil:     status.cooldownUntil,
 		RecentErr:           recentErrStr,
 		RecentErrTime:       status.recentErrTime,
+		RecentJobTimes:      recentJobTimesInMs(status.recentJobTimes),
+	}
+}
+
+// recentJobTimesInMs converts the recent job times of a queue to
+// milliseconds.
+func recentJobTimesInMs(jobTimes []time.Duration) []uint64 {
+	times := make([]uint64, 0, len(jobTimes))
+	for _, jt := range jobTimes {
+		times = append(times, uint64(jt.Milliseconds()))
 	}
+	return times
 }
 
 // callUpdateRegistryJobsStatus returns the status for the ReadRegistry queue.
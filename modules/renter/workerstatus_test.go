@@ -158,7 +158,7 @@ func TestWorkerPriceTableStatus(t *testing.T) {
 }
 
 // TestWorkerReadJobStatus is a small unit test that verifies the output of the
-// `callReadJobStatus` method on the worker.
+// `callReadJobStatus` function for the read queue of the worker.
 func TestWorkerReadJobStatus(t *testing.T) {
 	if testing.Short() {
 		t.SkipNow()
@@ -196,7 +196,7 @@ func TestWorkerReadJobStatus(t *testing.T) {
 	}
 
 	// fetch the worker's read jobs status and verify its output
-	status := w.callReadJobStatus()
+	status := callReadJobStatus(w.staticJobReadQueue)
 	if !(status.ConsecutiveFailures == 0 &&
 		status.JobQueueSize == 0 &&
 		status.RecentErr == "" &&
@@ -238,7 +238,7 @@ func TestWorkerReadJobStatus(t *testing.T) {
 	// verify the status in a build.Retry to allow the worker some time to
 	// process the job
 	if err := build.Retry(100, 100*time.Millisecond, func() error {
-		status = w.callReadJobStatus()
+		status = callReadJobStatus(w.staticJobReadQueue)
 		if !(status.ConsecutiveFailures == 1 &&
 			status.RecentErr != "" &&
 			status.RecentErrTime != time.Time{}) {
@@ -234,11 +234,18 @@ func (w *worker) managedPerformUploadChunkJob() {
 	releaseSize := len(uc.physicalChunkData[pieceIndex])
 	uc.piecesRegistered--
 	uc.piecesCompleted++
+	if uc.piecesPooled {
+		staticSectorBufferPool.callPut(uc.physicalChunkData[pieceIndex])
+	}
 	uc.physicalChunkData[pieceIndex] = nil
 	uc.memoryReleased += uint64(releaseSize)
 	uc.chunkSuccessProcessTimes = append(uc.chunkSuccessProcessTimes, time.Now())
+	scheduledRepair := uc.isScheduledRepair()
 	uc.mu.Unlock()
 	uc.staticMemoryManager.Return(uint64(releaseSize))
+	if scheduledRepair {
+		w.renter.staticRepairSchedule.callAddBandwidth(uint64(releaseSize))
+	}
 	w.renter.managedCleanUpUploadChunk(uc)
 }
 
@@ -0,0 +1,129 @@
+package modules
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+)
+
+type (
+	// RepairWindow is a daily time window during which the renter repairs
+	// files and uploads stuck chunks. Start and End are the offsets from
+	// midnight in the renter's local time. A window which ends before it
+	// starts spans midnight.
+	RepairWindow struct {
+		Start time.Duration `json:"start"`
+		End   time.Duration `json:"end"`
+	}
+
+	// RepairSchedule restricts the renter's background repairs and the
+	// uploads of stuck chunks to a set of daily time windows. New uploads are
+	// not restricted. Without any windows, repairs can run at any time.
+	RepairSchedule struct {
+		Windows []RepairWindow `json:"windows"`
+
+		// BandwidthBudget is the number of bytes that repairs may upload
+		// during a single window. A budget of 0 is unlimited.
+		BandwidthBudget uint64 `json:"bandwidthbudget"`
+	}
+
+	// RepairScheduleStatus describes whether the renter's repairs may
+	// currently run according to its RepairSchedule.
+	RepairScheduleStatus struct {
+		// Active indicates whether repairs may currently run.
+		Active bool `json:"active"`
+
+		// WindowStart and WindowEnd are the bounds of the current window. They
+		// are zero if the renter is outside of a window or if no windows are
+		// set.
+		WindowStart time.Time `json:"windowstart"`
+		WindowEnd   time.Time `json:"windowend"`
+
+		// NextWindowStart is the start of the next window after the current
+		// time. It is zero if no windows are set.
+		NextWindowStart time.Time `json:"nextwindowstart"`
+
+		// BandwidthUsed is the number of bytes repairs uploaded during the
+		// current window.
+		BandwidthUsed uint64 `json:"bandwidthused"`
+	}
+)
+
+// ParseRepairWindow parses a repair window in the format "HH:MM-HH:MM", e.g.
+// "02:00-06:00".
+func ParseRepairWindow(s string) (RepairWindow, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return RepairWindow{}, fmt.Errorf("repair window '%v' should have the format HH:MM-HH:MM", s)
+	}
+	var offsets [2]time.Duration
+	for i, part := range parts {
+		var hours, minutes int
+		if _, err := fmt.Sscanf(strings.TrimSpace(part), "%d:%d", &hours, &minutes); err != nil {
+			return RepairWindow{}, errors.AddContext(err, fmt.Sprintf("unable to parse time '%v' of repair window", part))
+		}
+		offsets[i] = time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	}
+	rw := RepairWindow{Start: offsets[0], End: offsets[1]}
+	return rw, rw.Validate()
+}
+
+// String returns the repair window in the format "HH:MM-HH:MM".
+func (rw RepairWindow) String() string {
+	format := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return format(rw.Start) + "-" + format(rw.End)
+}
+
+// Validate checks that the window starts and ends within a day and that it
+// isn't empty.
+func (rw RepairWindow) Validate() error {
+	day := 24 * time.Hour
+	if rw.Start < 0 || rw.Start >= day || rw.End < 0 || rw.End >= day {
+		return fmt.Errorf("repair window %v has to start and end between 00:00 and 23:59", rw)
+	}
+	if rw.Start == rw.End {
+		return fmt.Errorf("repair window %v is empty", rw)
+	}
+	return nil
+}
+
+// Occurrence returns the occurrence of the window which contains t. If t is
+// outside of the window, false is returned.
+func (rw RepairWindow) Occurrence(t time.Time) (start, end time.Time, ok bool) {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for _, day := range []int{-1, 0} {
+		start = midnight.AddDate(0, 0, day).Add(rw.Start)
+		end = midnight.AddDate(0, 0, day).Add(rw.End)
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !t.Before(start) && t.Before(end) {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// NextStart returns the start of the next occurrence of the window after t.
+func (rw RepairWindow) NextStart(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	start := midnight.Add(rw.Start)
+	if !start.After(t) {
+		start = midnight.AddDate(0, 0, 1).Add(rw.Start)
+	}
+	return start
+}
+
+// Validate checks that all of the schedule's windows are valid.
+func (rs RepairSchedule) Validate() error {
+	for _, rw := range rs.Windows {
+		if err := rw.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
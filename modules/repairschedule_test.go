@@ -0,0 +1,60 @@
+package modules
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRepairWindow tests parsing repair windows and finding their
+// occurrences.
+func TestRepairWindow(t *testing.T) {
+	// Parse some valid and invalid windows.
+	rw, err := ParseRepairWindow("02:00-06:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rw.Start != 2*time.Hour || rw.End != 6*time.Hour+30*time.Minute || rw.String() != "02:00-06:30" {
+		t.Fatal("wrong window", rw)
+	}
+	for _, s := range []string{"", "02:00", "02:00-02:00", "24:00-01:00", "aa:00-01:00", "01:00-02:00-03:00"} {
+		if _, err := ParseRepairWindow(s); err == nil {
+			t.Fatalf("parsing '%v' should fail", s)
+		}
+	}
+
+	// Check the occurrences of a window within a day.
+	day := time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC)
+	if _, _, ok := rw.Occurrence(day.Add(time.Hour)); ok {
+		t.Fatal("01:00 shouldn't be within the window")
+	}
+	start, end, ok := rw.Occurrence(day.Add(3 * time.Hour))
+	if !ok || !start.Equal(day.Add(2*time.Hour)) || !end.Equal(day.Add(6*time.Hour+30*time.Minute)) {
+		t.Fatal("wrong occurrence", start, end, ok)
+	}
+	if _, _, ok := rw.Occurrence(day.Add(6*time.Hour + 30*time.Minute)); ok {
+		t.Fatal("the end of the window should be excluded")
+	}
+	if next := rw.NextStart(day.Add(time.Hour)); !next.Equal(day.Add(2 * time.Hour)) {
+		t.Fatal("wrong next start", next)
+	}
+	if next := rw.NextStart(day.Add(2 * time.Hour)); !next.Equal(day.Add(26 * time.Hour)) {
+		t.Fatal("wrong next start", next)
+	}
+
+	// Check a window which spans midnight.
+	rw, err = ParseRepairWindow("22:00-03:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, end, ok = rw.Occurrence(day.Add(time.Hour))
+	if !ok || !start.Equal(day.Add(-2*time.Hour)) || !end.Equal(day.Add(3*time.Hour)) {
+		t.Fatal("wrong occurrence after midnight", start, end, ok)
+	}
+	start, end, ok = rw.Occurrence(day.Add(23 * time.Hour))
+	if !ok || !start.Equal(day.Add(22*time.Hour)) || !end.Equal(day.Add(27*time.Hour)) {
+		t.Fatal("wrong occurrence before midnight", start, end, ok)
+	}
+	if _, _, ok := rw.Occurrence(day.Add(12 * time.Hour)); ok {
+		t.Fatal("noon shouldn't be within the window")
+	}
+}
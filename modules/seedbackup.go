@@ -0,0 +1,129 @@
+package modules
+
+import (
+	"encoding/base32"
+	"strings"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+	"golang.org/x/crypto/argon2"
+
+	"go.sia.tech/siad/crypto"
+)
+
+// SeedBackup is a passphrase encrypted backup of a wallet seed. It allows
+// wallet UIs to back up and restore seeds without handling the plaintext seed
+// phrase.
+//
+// The backup is laid out as follows:
+//
+//	version (1 byte) | salt (16 bytes) | encrypted seed
+//
+// The encryption key is derived from the passphrase and the salt using
+// argon2id and the seed is encrypted with the default wallet cipher, which
+// is authenticated. Decrypting a backup with the wrong passphrase will
+// therefore fail with ErrBadEncryptionKey.
+type SeedBackup []byte
+
+const (
+	// SeedBackupQRPrefix is the prefix of the QR payload of a SeedBackup.
+	SeedBackupQRPrefix = "SIASEED:"
+
+	// seedBackupVersion is the current version of the SeedBackup format.
+	seedBackupVersion = 1
+
+	// seedBackupSaltSize is the size of the salt used to derive the
+	// encryption key of a SeedBackup.
+	seedBackupSaltSize = 16
+
+	// Argon2id parameters used by PassphraseKey to derive encryption keys
+	// from passphrases. Changing them requires a new seedBackupVersion and a
+	// new version of every other format that uses PassphraseKey.
+	seedBackupKDFTime    = 1
+	seedBackupKDFMemory  = 64 * 1024
+	seedBackupKDFThreads = 4
+)
+
+var (
+	// ErrEmptySeedBackupPassphrase is returned when a seed backup is created
+	// or opened without a passphrase.
+	ErrEmptySeedBackupPassphrase = errors.New("seed backup passphrase can't be empty")
+
+	// errInvalidSeedBackup is returned when a seed backup can't be decoded.
+	errInvalidSeedBackup = errors.New("invalid seed backup")
+
+	// seedBackupEncoding is the encoding of the QR payload. The base32
+	// alphabet without padding only uses characters from the alphanumeric
+	// mode of QR codes which produces considerably smaller codes than the
+	// byte mode.
+	seedBackupEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// PassphraseKey derives an encryption key from a passphrase and a salt using
+// argon2id. The key uses the default wallet cipher, which is authenticated,
+// so decrypting with a key derived from the wrong passphrase fails.
+func PassphraseKey(passphrase string, salt []byte) crypto.CipherKey {
+	var entropy crypto.Hash
+	copy(entropy[:], argon2.IDKey([]byte(passphrase), salt, seedBackupKDFTime, seedBackupKDFMemory, seedBackupKDFThreads, uint32(len(entropy))))
+	return crypto.NewWalletKey(entropy)
+}
+
+// NewSeedBackup encrypts the seed with the provided passphrase.
+func NewSeedBackup(seed Seed, passphrase string) (SeedBackup, error) {
+	if passphrase == "" {
+		return nil, ErrEmptySeedBackupPassphrase
+	}
+	salt := fastrand.Bytes(seedBackupSaltSize)
+	ct := PassphraseKey(passphrase, salt).EncryptBytes(seed[:])
+	backup := make(SeedBackup, 0, 1+len(salt)+len(ct))
+	backup = append(backup, seedBackupVersion)
+	backup = append(backup, salt...)
+	backup = append(backup, ct...)
+	return backup, nil
+}
+
+// ParseSeedBackupQR decodes the QR payload of a seed backup.
+func ParseSeedBackupQR(payload string) (SeedBackup, error) {
+	payload = strings.TrimSpace(payload)
+	if !strings.HasPrefix(payload, SeedBackupQRPrefix) {
+		return nil, errors.AddContext(errInvalidSeedBackup, "missing prefix")
+	}
+	b, err := seedBackupEncoding.DecodeString(strings.TrimPrefix(payload, SeedBackupQRPrefix))
+	if err != nil {
+		return nil, errors.Compose(errInvalidSeedBackup, err)
+	}
+	return SeedBackup(b), nil
+}
+
+// Open decrypts the seed backup using the provided passphrase.
+func (sb SeedBackup) Open(passphrase string) (Seed, error) {
+	if passphrase == "" {
+		return Seed{}, ErrEmptySeedBackupPassphrase
+	}
+	if len(sb) < 1+seedBackupSaltSize {
+		return Seed{}, errors.AddContext(errInvalidSeedBackup, "backup is too short")
+	}
+	if sb[0] != seedBackupVersion {
+		return Seed{}, errors.AddContext(errInvalidSeedBackup, "unknown version")
+	}
+	salt := sb[1 : 1+seedBackupSaltSize]
+	ct := crypto.Ciphertext(sb[1+seedBackupSaltSize:])
+	plaintext, err := PassphraseKey(passphrase, salt).DecryptBytes(ct)
+	if errors.Contains(err, crypto.ErrInsufficientLen) {
+		return Seed{}, errors.AddContext(errInvalidSeedBackup, "backup is too short")
+	} else if err != nil {
+		return Seed{}, ErrBadEncryptionKey
+	}
+	var seed Seed
+	if len(plaintext) != len(seed) {
+		return Seed{}, errors.AddContext(errInvalidSeedBackup, "wrong seed length")
+	}
+	copy(seed[:], plaintext)
+	return seed, nil
+}
+
+// QRPayload returns the seed backup encoded as a string which can be turned
+// into a QR code.
+func (sb SeedBackup) QRPayload() string {
+	return SeedBackupQRPrefix + seedBackupEncoding.EncodeToString(sb)
+}
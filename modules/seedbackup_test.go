@@ -0,0 +1,77 @@
+package modules
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+)
+
+// TestSeedBackup tests creating and opening seed backups.
+func TestSeedBackup(t *testing.T) {
+	var seed Seed
+	fastrand.Read(seed[:])
+
+	// Empty passphrases are not allowed.
+	if _, err := NewSeedBackup(seed, ""); !errors.Contains(err, ErrEmptySeedBackupPassphrase) {
+		t.Fatal("expected ErrEmptySeedBackupPassphrase", err)
+	}
+
+	backup, err := NewSeedBackup(seed, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The backup should open with the right passphrase only.
+	opened, err := backup.Open("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened != seed {
+		t.Fatal("opened seed doesn't match")
+	}
+	if _, err := backup.Open("wrong"); !errors.Contains(err, ErrBadEncryptionKey) {
+		t.Fatal("expected ErrBadEncryptionKey", err)
+	}
+
+	// Backups of the same seed should use different salts.
+	backup2, err := NewSeedBackup(seed, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) == string(backup2) {
+		t.Fatal("backups should differ")
+	}
+
+	// The QR payload should round-trip and only use characters of the
+	// alphanumeric QR mode.
+	payload := backup.QRPayload()
+	for _, c := range payload {
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c == ':') {
+			t.Fatalf("payload contains invalid character %q", c)
+		}
+	}
+	parsed, err := ParseSeedBackupQR(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened, err := parsed.Open("passphrase"); err != nil || opened != seed {
+		t.Fatal("parsed backup doesn't open", err)
+	}
+
+	// Invalid backups should be rejected.
+	if _, err := ParseSeedBackupQR("foo"); !errors.Contains(err, errInvalidSeedBackup) {
+		t.Fatal("expected errInvalidSeedBackup", err)
+	}
+	if _, err := SeedBackup(backup[:10]).Open("passphrase"); !errors.Contains(err, errInvalidSeedBackup) {
+		t.Fatal("expected errInvalidSeedBackup", err)
+	}
+	if _, err := SeedBackup(backup[:20]).Open("passphrase"); !errors.Contains(err, errInvalidSeedBackup) {
+		t.Fatal("expected errInvalidSeedBackup", err)
+	}
+	badVersion := append(SeedBackup{}, backup...)
+	badVersion[0]++
+	if _, err := badVersion.Open("passphrase"); !errors.Contains(err, errInvalidSeedBackup) {
+		t.Fatal("expected errInvalidSeedBackup", err)
+	}
+}
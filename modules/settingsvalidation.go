@@ -0,0 +1,63 @@
+package modules
+
+import (
+	"fmt"
+
+	"gitlab.com/NebulousLabs/errors"
+)
+
+const (
+	// SettingsMinRecommendedBandwidth is the lowest non-zero bandwidth limit
+	// in bytes per second that doesn't cause sector transfers to run into
+	// timeouts.
+	SettingsMinRecommendedBandwidth = 1 << 20 // 1 MiB/s
+)
+
+type (
+	// SettingsIssue is a problem found while validating a proposed change of
+	// a module's settings. Issues with a severity of SeverityError or higher
+	// prevent the settings from being applied, warnings describe projected
+	// consequences of the change.
+	SettingsIssue struct {
+		Field    string        `json:"field"`
+		Severity AlertSeverity `json:"severity"`
+		Message  string        `json:"message"`
+	}
+
+	// SettingsValidation is the result of validating a proposed change of a
+	// module's settings.
+	SettingsValidation struct {
+		Issues []SettingsIssue `json:"issues"`
+	}
+)
+
+// AddError adds an issue that prevents the settings from being applied.
+func (sv *SettingsValidation) AddError(field, format string, args ...interface{}) {
+	sv.Issues = append(sv.Issues, SettingsIssue{
+		Field:    field,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// AddWarning adds an issue that doesn't prevent the settings from being
+// applied.
+func (sv *SettingsValidation) AddWarning(field, format string, args ...interface{}) {
+	sv.Issues = append(sv.Issues, SettingsIssue{
+		Field:    field,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Err returns an error containing all issues that prevent the settings from
+// being applied, or nil if there are none.
+func (sv SettingsValidation) Err() error {
+	var errs []error
+	for _, issue := range sv.Issues {
+		if issue.Severity >= SeverityError {
+			errs = append(errs, fmt.Errorf("%v: %v", issue.Field, issue.Message))
+		}
+	}
+	return errors.Compose(errs...)
+}
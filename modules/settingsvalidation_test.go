@@ -0,0 +1,39 @@
+package modules
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestSettingsValidation checks that only errors prevent settings from being
+// applied and that issues survive a JSON roundtrip.
+func TestSettingsValidation(t *testing.T) {
+	t.Parallel()
+
+	var sv SettingsValidation
+	if err := sv.Err(); err != nil {
+		t.Fatal("empty validation shouldn't have an error", err)
+	}
+	sv.AddWarning("funds", "funds are low")
+	if err := sv.Err(); err != nil {
+		t.Fatal("warnings shouldn't cause an error", err)
+	}
+	sv.AddError("hosts", "must be at least %v", 1)
+	err := sv.Err()
+	if err == nil || !strings.Contains(err.Error(), "hosts: must be at least 1") || strings.Contains(err.Error(), "funds") {
+		t.Fatal("unexpected error", err)
+	}
+
+	b, err := json.Marshal(sv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sv2 SettingsValidation
+	if err := json.Unmarshal(b, &sv2); err != nil {
+		t.Fatal(err)
+	}
+	if len(sv2.Issues) != 2 || sv2.Issues[0].Severity != SeverityWarning || sv2.Issues[1] != sv.Issues[1] {
+		t.Fatal("issues weren't preserved", sv2.Issues)
+	}
+}
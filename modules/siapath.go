@@ -140,6 +140,12 @@ func (sp SiaPath) Dir() (SiaPath, error) {
 	return newSiaPath(dir)
 }
 
+// Contains returns true if the siapath is equal to the provided siapath or is
+// one of its ancestors. The root contains every siapath.
+func (sp SiaPath) Contains(siaPath SiaPath) bool {
+	return sp.IsRoot() || sp.Equals(siaPath) || strings.HasPrefix(siaPath.Path, sp.Path+"/")
+}
+
 // Equals compares two SiaPath types for equality
 func (sp SiaPath) Equals(siaPath SiaPath) bool {
 	return sp.Path == siaPath.Path
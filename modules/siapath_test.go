@@ -253,6 +253,30 @@ func TestSiapathRebase(t *testing.T) {
 	}
 }
 
+// TestSiapathContains probes the Contains function for SiaPaths.
+func TestSiapathContains(t *testing.T) {
+	var containstests = []struct {
+		path     string
+		other    string
+		contains bool
+	}{
+		{"", "a/b", true},
+		{"", "", true},
+		{"a", "a", true},
+		{"a", "a/b/c", true},
+		{"a/b", "a", false},
+		{"a", "ab", false},
+		{"a/b", "a/bc/d", false},
+		{"a", "", false},
+	}
+	for _, test := range containstests {
+		sp, other := SiaPath{Path: test.path}, SiaPath{Path: test.other}
+		if sp.Contains(other) != test.contains {
+			t.Errorf("'%v' contains '%v' should be %v", test.path, test.other, test.contains)
+		}
+	}
+}
+
 // TestSiapathDir probes the Dir function for SiaPaths.
 func TestSiapathDir(t *testing.T) {
 	var pathtests = []struct {
@@ -1,6 +1,8 @@
 package modules
 
 import (
+	"time"
+
 	"go.sia.tech/siad/crypto"
 )
 
@@ -23,6 +25,11 @@ type (
 		Index             uint16 `json:"index"`
 		Path              string `json:"path"`
 
+		// Draining indicates that the storage folder was marked for removal.
+		// Its sectors are migrated to other storage folders in the background
+		// and the folder is removed once it is empty.
+		Draining bool `json:"draining"`
+
 		// Below are statistics about the filesystem. FailedReads and
 		// FailedWrites are only incremented if the filesystem is returning
 		// errors when operations are being performed. A large number of
@@ -40,6 +47,60 @@ type (
 		// folder. Progress is always reported in bytes.
 		ProgressNumerator   uint64
 		ProgressDenominator uint64
+
+		// Health describes the health of the storage folder's disk.
+		Health StorageFolderHealth `json:"health"`
+	}
+
+	// StorageFolderHealth describes the health of the disk of a storage
+	// folder as probed periodically by the storage manager. A storage folder
+	// is degraded if any warnings were raised during the most recent probe.
+	StorageFolderHealth struct {
+		// LastProbe is the time of the most recent probe. It is zero if the
+		// storage folder wasn't probed yet.
+		LastProbe time.Time `json:"lastprobe"`
+
+		// FilesystemSize and FilesystemFree are the size and the free space
+		// of the filesystem that contains the storage folder. FreeSpaceTrend
+		// is the change of the free space in bytes per day over the recent
+		// probes. A negative trend means that the filesystem is filling up.
+		FilesystemSize uint64 `json:"filesystemsize"`
+		FilesystemFree uint64 `json:"filesystemfree"`
+		FreeSpaceTrend int64  `json:"freespacetrend"`
+
+		// RecentFailedReads and RecentFailedWrites are the number of failed
+		// reads and writes since the previous probe.
+		RecentFailedReads  uint64 `json:"recentfailedreads"`
+		RecentFailedWrites uint64 `json:"recentfailedwrites"`
+
+		// SMARTStatus is the overall health reported by the disk's SMART
+		// self-assessment. It is either "passed", "failed" or "unknown" if
+		// the disk couldn't be queried, and empty if SMART querying is
+		// disabled.
+		SMARTStatus string `json:"smartstatus"`
+
+		Degraded bool     `json:"degraded"`
+		Warnings []string `json:"warnings"`
+	}
+
+	// StorageScrubStatus describes the progress of the storage manager's
+	// integrity scrubber, which periodically re-reads all sectors and
+	// quarantines those whose data no longer matches their Merkle root.
+	StorageScrubStatus struct {
+		// PassStart is the time at which the current or most recent pass
+		// over all sectors started, LastPassEnd the time at which the most
+		// recent pass finished.
+		PassStart   time.Time `json:"passstart"`
+		LastPassEnd time.Time `json:"lastpassend"`
+
+		// SectorsScrubbed is the number of sectors that were verified during
+		// the current or most recent pass.
+		SectorsScrubbed uint64 `json:"sectorsscrubbed"`
+
+		// CorruptSectors is the number of quarantined sectors. Quarantined
+		// sectors are no longer served to renters and storage proofs for
+		// them will fail.
+		CorruptSectors uint64 `json:"corruptsectors"`
 	}
 
 	// A StorageManager is responsible for managing storage folders and
@@ -78,6 +139,13 @@ type (
 		// The storage manager needs to be able to shut down.
 		Close() error
 
+		// DrainStorageFolder marks a storage folder for removal, or unmarks
+		// it if drain is false. Instead of blocking like RemoveStorageFolder,
+		// the sectors of a draining storage folder are migrated to other
+		// storage folders in the background and the folder is removed once it
+		// is empty.
+		DrainStorageFolder(index uint16, drain bool) error
+
 		// DeleteSector deletes a sector, meaning that the manager will be
 		// unable to upload that sector and be unable to provide a storage
 		// proof on that sector. DeleteSector is for removing the data
@@ -90,6 +158,17 @@ type (
 		// bytes that match the input sector root.
 		ReadSector(sectorRoot crypto.Hash) ([]byte, error)
 
+		// SetSMARTQuerying enables or disables querying the SMART health of
+		// the storage folders' disks during the periodic health probes.
+		SetSMARTQuerying(enabled bool)
+
+		// SectorCorrupt indicates whether the sector with the given root was
+		// quarantined because its data failed the integrity check.
+		SectorCorrupt(sectorRoot crypto.Hash) bool
+
+		// ScrubStatus returns the progress of the integrity scrubber.
+		ScrubStatus() StorageScrubStatus
+
 		// ReadPartialSector will read a sector from the storage manager,
 		// returning the bytes that match the input sector root.
 		ReadPartialSector(sectorRoot crypto.Hash, offset, length uint64) ([]byte, error)
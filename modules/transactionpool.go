@@ -44,6 +44,11 @@ var (
 	// IsStandard rules of the transaction pool.
 	ErrLargeTransactionSet = errors.New("transaction set is too large for this transaction pool")
 
+	// ErrTransactionPoolFull is the error that gets returned if a transaction
+	// set doesn't fit into the transaction pool and doesn't pay a higher fee
+	// rate than enough of the sets in the pool to evict them.
+	ErrTransactionPoolFull = errors.New("transaction pool is full and the transaction set doesn't pay enough fees to evict other sets")
+
 	// PrefixNonSia defines the prefix that should be appended to any
 	// transactions that use the arbitrary data for reasons outside of the
 	// standard Sia protocol. This will prevent these transactions from being
@@ -75,6 +80,54 @@ type (
 		RevertedTransactions []TransactionSetID
 	}
 
+	// FeePercentiles are the fee rates per byte which are paid by at least 75%,
+	// 50% and 10% of the bytes of a group of transactions.
+	FeePercentiles struct {
+		P25 types.Currency `json:"p25"` // hastings / byte
+		P50 types.Currency `json:"p50"` // hastings / byte
+		P90 types.Currency `json:"p90"` // hastings / byte
+	}
+
+	// FeeEstimate is an estimation of the fee per byte a transaction needs to
+	// pay to be confirmed within a number of blocks. It is derived from the
+	// fees paid in recent blocks and the fees paid by the transactions in the
+	// transaction pool.
+	FeeEstimate struct {
+		// Economy targets a confirmation within ~10 blocks, Standard within ~3
+		// blocks and Priority within the next block.
+		Economy  types.Currency `json:"economy"`
+		Standard types.Currency `json:"standard"`
+		Priority types.Currency `json:"priority"`
+
+		// BlockFees are the median fee percentiles of the recent blocks. The
+		// unused space of a block counts as space paying no fees.
+		BlockFees FeePercentiles `json:"blockfees"`
+		NumBlocks int            `json:"numblocks"`
+
+		// PoolFees are the fee percentiles of the transaction pool.
+		PoolFees FeePercentiles `json:"poolfees"`
+		PoolSize uint64         `json:"poolsize"`
+	}
+
+	// TransactionPoolSet is a transaction set of the transaction pool
+	// together with its size and fees.
+	TransactionPoolSet struct {
+		ID           TransactionSetID    `json:"id"`
+		Transactions []types.Transaction `json:"transactions"`
+		Size         uint64              `json:"size"`
+		Fees         types.Currency      `json:"fees"`
+		FeeRate      types.Currency      `json:"feerate"` // hastings / byte
+	}
+
+	// TransactionPoolContents is a page of the transaction sets of the
+	// transaction pool, which are sorted by fee rate in descending order.
+	TransactionPoolContents struct {
+		Sets      []TransactionPoolSet `json:"sets"`
+		TotalSets uint64               `json:"totalsets"`
+		Size      uint64               `json:"size"`
+		MaxSize   uint64               `json:"maxsize"`
+	}
+
 	// UnconfirmedTransactionSet defines a new unconfirmed transaction that has
 	// been added to the transaction pool. ID is the ID of the set, IDs contains
 	// an ID for each transaction, eliminating the need to recompute it (because
@@ -105,7 +158,8 @@ type (
 		Alerter
 
 		// AcceptTransactionSet accepts a set of potentially interdependent
-		// transactions.
+		// transactions. A set which double spends transactions of the pool
+		// replaces them if it pays higher fees.
 		AcceptTransactionSet([]types.Transaction) error
 
 		// Broadcast broadcasts a transaction set to all of the transaction pool's
@@ -115,6 +169,11 @@ type (
 		// Close is necessary for clean shutdown (e.g. during testing).
 		Close() error
 
+		// Contents returns the transaction sets of the pool sorted by fee
+		// rate in descending order. At most limit sets are returned, starting
+		// with the set at offset.
+		Contents(offset, limit uint64) (TransactionPoolContents, error)
+
 		// FeeEstimation returns an estimation for how high the transaction fee
 		// needs to be per byte. The minimum recommended targets getting accepted
 		// in ~3 blocks, and the maximum recommended targets getting accepted
@@ -123,6 +182,19 @@ type (
 		// within 10 blocks.
 		FeeEstimation() (minimumRecommended, maximumRecommended types.Currency)
 
+		// FeeEstimate returns the fee estimation per byte for different
+		// confirmation targets together with the statistics it is based on.
+		// FeeEstimation returns the Standard and Priority estimations.
+		FeeEstimate() FeeEstimate
+
+		// MaxSize returns the maximum size of the transaction pool in bytes.
+		MaxSize() uint64
+
+		// SetMaxSize sets the maximum size of the transaction pool in bytes.
+		// If the pool is larger, the sets with the lowest fee rates are
+		// evicted.
+		SetMaxSize(size uint64) error
+
 		// PurgeTransactionPool is a temporary function available to the miner. In
 		// the event that a miner mines an unacceptable block, the transaction pool
 		// will be purged to clear out the transaction pool and get rid of the
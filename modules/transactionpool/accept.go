@@ -202,6 +202,11 @@ func (tp *TransactionPool) handleConflicts(ts []types.Transaction, conflicts []m
 		return nil, modules.NewConsensusConflict("provided transaction set has prereqs, but is still invalid: " + err.Error())
 	}
 
+	// Make room for the superset, which replaces the conflicts.
+	if err := tp.makeRoom(superset, supersetMap); err != nil {
+		return nil, err
+	}
+
 	// Remove the conflicts from the transaction pool.
 	for conflict := range supersetMap {
 		conflictSet := tp.transactionSets[conflict]
@@ -288,6 +293,13 @@ func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction, txnFn fu
 		return nil, errLowMinerFees
 	}
 
+	// Check for transactions of the pool which spend the same outputs. The
+	// sets containing them are replaced if the transaction set pays enough
+	// fees.
+	if replaced := tp.doubleSpentSets(ts); len(replaced) > 0 {
+		return tp.replaceTransactionSets(ts, replaced, txnFn)
+	}
+
 	// Check for conflicts with other transactions, which would indicate a
 	// double-spend. Legal children of a transaction set will also trigger the
 	// conflict-detector.
@@ -307,6 +319,11 @@ func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction, txnFn fu
 		return nil, modules.NewConsensusConflict("provided transaction set is invalid: " + err.Error())
 	}
 
+	// Evict sets with lower fee rates if the pool is full.
+	if err := tp.makeRoom(ts, nil); err != nil {
+		return nil, err
+	}
+
 	// Add the transaction set to the pool.
 	setID := modules.TransactionSetID(crypto.HashObject(ts))
 	tp.transactionSets[setID] = ts
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/persist"
 	"go.sia.tech/siad/types"
 )
@@ -37,6 +38,10 @@ const (
 	// TransactionPoolSizeTarget defines the target size of the pool when the
 	// transactions are paying 1 SC / kb in fees.
 	TransactionPoolSizeTarget = 3e6
+
+	// minMaxSize is the smallest maximum size of the transaction pool, which
+	// still fits the largest transaction set.
+	minMaxSize = modules.TransactionSetSizeLimit
 )
 
 // Constants related to fee estimation.
@@ -46,10 +51,19 @@ const (
 	// to add to transactions.
 	blockFeeEstimationDepth = 6
 
-	// maxMultiplier defines the general gap between the maximum recommended fee
-	// and the minimum recommended fee.
+	// maxMultiplier defines the minimum gap between the priority estimation and
+	// the standard estimation. It keeps the fees of renters and hosts
+	// acceptable to each other even if their transaction pools differ
+	// slightly.
 	maxMultiplier = 3
 
+	// The confirmation targets in blocks of the fee estimations. The
+	// transaction pool based estimation for a target is the fee rate needed to
+	// be among the transactions that fill that many blocks.
+	feeTargetPriority = 1
+	feeTargetStandard = 3
+	feeTargetEconomy  = 10
+
 	// feeEstimationConstantPadding is the constant amount of padding added to
 	// the current tpool size when estimating a good fee rate for new
 	// transactions.
@@ -74,6 +88,15 @@ var (
 	// minEstimation defines a sane minimum fee per byte for transactions.  This
 	// will typically be only suggested as a fee in the absence of congestion.
 	minEstimation = types.SiacoinPrecision.Div64(100).Div64(1e3)
+
+	// defaultMaxSize is the default maximum size of the transaction pool in
+	// bytes.
+	defaultMaxSize = build.Select(build.Var{
+		Standard: uint64(20e6),
+		Testnet:  uint64(20e6),
+		Dev:      uint64(10e6),
+		Testing:  uint64(10e6),
+	}).(uint64)
 )
 
 // Variables related to propagating transactions through the network.
@@ -74,8 +74,11 @@ type (
 	// medianPersist is the json object that gets stored in the database so that
 	// the transaction pool can persist its block based fee estimations.
 	medianPersist struct {
-		RecentMedians   []types.Currency
-		RecentMedianFee types.Currency
+		RecentBlockFees []modules.FeePercentiles
+
+		// RecentMedians are the fee medians of the recent blocks which were
+		// persisted by older versions.
+		RecentMedians []types.Currency `json:",omitempty"`
 	}
 )
 
@@ -0,0 +1,189 @@
+package transactionpool
+
+import (
+	"bytes"
+	"sort"
+
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// errSmallMaxSize is returned if the maximum size of the transaction pool
+	// is set below minMaxSize.
+	errSmallMaxSize = errors.New("maximum size of the transaction pool is too small to fit the largest transaction set")
+)
+
+// removedSet is a transaction set which was removed from the pool, together
+// with the state which is needed to restore it.
+type removedSet struct {
+	id      modules.TransactionSetID
+	set     []types.Transaction
+	diff    *modules.ConsensusChange
+	objects []ObjectID
+	heights map[types.TransactionID]types.BlockHeight
+}
+
+// poolSet returns the size and fees of a transaction set. The size is the size
+// which is counted by transactionListSize.
+func poolSet(id modules.TransactionSetID, set []types.Transaction) modules.TransactionPoolSet {
+	size := uint64(len(encoding.Marshal(set)))
+	var fees types.Currency
+	for _, txn := range set {
+		for _, fee := range txn.MinerFees {
+			fees = fees.Add(fee)
+		}
+	}
+	return modules.TransactionPoolSet{
+		ID:           id,
+		Transactions: set,
+		Size:         size,
+		Fees:         fees,
+		FeeRate:      fees.Div64(size),
+	}
+}
+
+// setsByFeeRate returns the sets of the pool sorted by fee rate in ascending
+// order. Sets with the same fee rate are sorted by ID.
+func (tp *TransactionPool) setsByFeeRate() []modules.TransactionPoolSet {
+	sets := make([]modules.TransactionPoolSet, 0, len(tp.transactionSets))
+	for id, set := range tp.transactionSets {
+		sets = append(sets, poolSet(id, set))
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		if cmp := sets[i].FeeRate.Cmp(sets[j].FeeRate); cmp != 0 {
+			return cmp < 0
+		}
+		return bytes.Compare(sets[i].ID[:], sets[j].ID[:]) < 0
+	})
+	return sets
+}
+
+// removeTransactionSet removes a set from the pool and returns it, so that it
+// can be restored with restoreTransactionSet.
+func (tp *TransactionPool) removeTransactionSet(id modules.TransactionSetID) removedSet {
+	set := tp.transactionSets[id]
+	rs := removedSet{
+		id:      id,
+		set:     set,
+		diff:    tp.transactionSetDiffs[id],
+		heights: make(map[types.TransactionID]types.BlockHeight),
+	}
+	for _, oid := range relatedObjectIDs(set) {
+		if tp.knownObjects[oid] == id {
+			rs.objects = append(rs.objects, oid)
+			delete(tp.knownObjects, oid)
+		}
+	}
+	for _, txn := range set {
+		txid := txn.ID()
+		if height, exists := tp.transactionHeights[txid]; exists {
+			rs.heights[txid] = height
+			delete(tp.transactionHeights, txid)
+		}
+	}
+	tp.transactionListSize -= len(encoding.Marshal(set))
+	delete(tp.transactionSets, id)
+	delete(tp.transactionSetDiffs, id)
+	return rs
+}
+
+// restoreTransactionSet adds a removed set back to the pool.
+func (tp *TransactionPool) restoreTransactionSet(rs removedSet) {
+	tp.transactionSets[rs.id] = rs.set
+	tp.transactionSetDiffs[rs.id] = rs.diff
+	for _, oid := range rs.objects {
+		tp.knownObjects[oid] = rs.id
+	}
+	for txid, height := range rs.heights {
+		tp.transactionHeights[txid] = height
+	}
+	tp.transactionListSize += len(encoding.Marshal(rs.set))
+}
+
+// setsToEvict returns the sets with the lowest fee rates which need to be
+// evicted so that the set fits into the pool. The excluded sets are about to
+// be replaced by the set, so they are neither counted nor evicted. Only sets
+// with a lower fee rate than the set are evicted, if that isn't enough
+// ErrTransactionPoolFull is returned.
+func (tp *TransactionPool) setsToEvict(ps modules.TransactionPoolSet, exclude map[modules.TransactionSetID]struct{}) ([]modules.TransactionSetID, error) {
+	size := uint64(tp.transactionListSize) + ps.Size
+	for id := range exclude {
+		size -= uint64(len(encoding.Marshal(tp.transactionSets[id])))
+	}
+	if size <= tp.maxSize {
+		return nil, nil
+	}
+
+	var evict []modules.TransactionSetID
+	for _, candidate := range tp.setsByFeeRate() {
+		if size <= tp.maxSize {
+			break
+		}
+		if _, excluded := exclude[candidate.ID]; excluded {
+			continue
+		}
+		if candidate.FeeRate.Cmp(ps.FeeRate) >= 0 {
+			break
+		}
+		evict = append(evict, candidate.ID)
+		size -= candidate.Size
+	}
+	if size > tp.maxSize {
+		return nil, modules.ErrTransactionPoolFull
+	}
+	return evict, nil
+}
+
+// makeRoom evicts the sets with the lowest fee rates until the set fits into
+// the pool. The excluded sets are about to be replaced by the set. If the set
+// doesn't fit, nothing is evicted.
+func (tp *TransactionPool) makeRoom(ts []types.Transaction, exclude map[modules.TransactionSetID]struct{}) error {
+	evict, err := tp.setsToEvict(poolSet(modules.TransactionSetID{}, ts), exclude)
+	if err != nil {
+		return err
+	}
+	for _, id := range evict {
+		tp.removeTransactionSet(id)
+		tp.log.Debugln("Evicted transaction set", id, "to make room for a set with a higher fee rate")
+	}
+	return nil
+}
+
+// MaxSize returns the maximum size of the transaction pool in bytes.
+func (tp *TransactionPool) MaxSize() uint64 {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+	return tp.maxSize
+}
+
+// SetMaxSize sets the maximum size of the transaction pool in bytes. If the
+// pool is larger, the sets with the lowest fee rates are evicted.
+func (tp *TransactionPool) SetMaxSize(size uint64) error {
+	if err := tp.tg.Add(); err != nil {
+		return err
+	}
+	defer tp.tg.Done()
+	if size < minMaxSize {
+		return errSmallMaxSize
+	}
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.maxSize = size
+	if uint64(tp.transactionListSize) <= size {
+		return nil
+	}
+	for _, ps := range tp.setsByFeeRate() {
+		if uint64(tp.transactionListSize) <= size {
+			break
+		}
+		tp.removeTransactionSet(ps.ID)
+		tp.log.Debugln("Evicted transaction set", ps.ID, "after lowering the maximum size of the pool")
+	}
+	tp.updateSubscribersTransactions()
+	return nil
+}
@@ -0,0 +1,196 @@
+package transactionpool
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// newBareTpool returns a transaction pool without any dependencies, which is
+// enough to test the bookkeeping of its sets.
+func newBareTpool() *TransactionPool {
+	return &TransactionPool{
+		knownObjects:        make(map[ObjectID]modules.TransactionSetID),
+		transactionHeights:  make(map[types.TransactionID]types.BlockHeight),
+		transactionSets:     make(map[modules.TransactionSetID][]types.Transaction),
+		transactionSetDiffs: make(map[modules.TransactionSetID]*modules.ConsensusChange),
+		maxSize:             defaultMaxSize,
+	}
+}
+
+// addBareSet adds a set to the pool the same way acceptTransactionSet does,
+// without validating it.
+func (tp *TransactionPool) addBareSet(ts []types.Transaction) modules.TransactionSetID {
+	setID := modules.TransactionSetID(crypto.HashObject(ts))
+	tp.transactionSets[setID] = ts
+	for _, oid := range relatedObjectIDs(ts) {
+		tp.knownObjects[oid] = setID
+	}
+	for _, txn := range ts {
+		tp.transactionHeights[txn.ID()] = tp.blockHeight
+	}
+	tp.transactionSetDiffs[setID] = &modules.ConsensusChange{}
+	tp.transactionListSize += len(encoding.Marshal(ts))
+	return setID
+}
+
+// spendingSet returns a single transaction set spending the given output and
+// paying the given fee.
+func spendingSet(parent types.SiacoinOutputID, fee types.Currency) []types.Transaction {
+	return []types.Transaction{{
+		SiacoinInputs: []types.SiacoinInput{{ParentID: parent}},
+		SiacoinOutputs: []types.SiacoinOutput{{
+			Value:      types.SiacoinPrecision,
+			UnlockHash: types.UnlockHash(crypto.Hash{}),
+		}},
+		MinerFees: []types.Currency{fee},
+	}}
+}
+
+// randomOutputID returns a random siacoin output id.
+func randomOutputID() (id types.SiacoinOutputID) {
+	fastrand.Read(id[:])
+	return
+}
+
+// TestRemoveRestoreTransactionSet checks that a removed set is restored
+// completely.
+func TestRemoveRestoreTransactionSet(t *testing.T) {
+	t.Parallel()
+	tp := newBareTpool()
+	other := tp.addBareSet(spendingSet(randomOutputID(), types.SiacoinPrecision))
+	ts := spendingSet(randomOutputID(), types.SiacoinPrecision)
+	id := tp.addBareSet(ts)
+	objects, size := len(tp.knownObjects), tp.transactionListSize
+
+	rs := tp.removeTransactionSet(id)
+	if _, exists := tp.transactionSets[id]; exists {
+		t.Fatal("set wasn't removed")
+	}
+	if _, exists := tp.transactionSets[other]; !exists {
+		t.Fatal("other set was removed")
+	}
+	if _, exists := tp.transactionHeights[ts[0].ID()]; exists {
+		t.Fatal("height wasn't removed")
+	}
+	for _, oid := range relatedObjectIDs(ts) {
+		if _, exists := tp.knownObjects[oid]; exists {
+			t.Fatal("object wasn't removed")
+		}
+	}
+	if tp.transactionListSize != size-len(encoding.Marshal(ts)) {
+		t.Fatal("wrong size after removal", tp.transactionListSize)
+	}
+
+	tp.restoreTransactionSet(rs)
+	if _, exists := tp.transactionSets[id]; !exists {
+		t.Fatal("set wasn't restored")
+	}
+	if _, exists := tp.transactionHeights[ts[0].ID()]; !exists {
+		t.Fatal("height wasn't restored")
+	}
+	if len(tp.knownObjects) != objects || tp.transactionListSize != size {
+		t.Fatal("wrong state after restoring", len(tp.knownObjects), tp.transactionListSize)
+	}
+}
+
+// TestSetsToEvict checks that only the sets with the lowest fee rates are
+// evicted, and only if they pay less than the new set.
+func TestSetsToEvict(t *testing.T) {
+	t.Parallel()
+	tp := newBareTpool()
+	low := tp.addBareSet(spendingSet(randomOutputID(), types.NewCurrency64(1000)))
+	mid := tp.addBareSet(spendingSet(randomOutputID(), types.NewCurrency64(2000)))
+	high := tp.addBareSet(spendingSet(randomOutputID(), types.NewCurrency64(4000)))
+	setSize := uint64(len(encoding.Marshal(tp.transactionSets[low])))
+
+	// Nothing is evicted while the pool has room.
+	ps := poolSet(modules.TransactionSetID{}, spendingSet(randomOutputID(), types.NewCurrency64(3000)))
+	if evict, err := tp.setsToEvict(ps, nil); err != nil || len(evict) != 0 {
+		t.Fatal("unexpected eviction", evict, err)
+	}
+
+	// Once the pool is full, the set with the lowest fee rate is evicted.
+	tp.maxSize = uint64(tp.transactionListSize)
+	evict, err := tp.setsToEvict(ps, nil)
+	if err != nil || len(evict) != 1 || evict[0] != low {
+		t.Fatal("wrong eviction", evict, err)
+	}
+
+	// If there is room for one set, two sets need to be evicted.
+	tp.maxSize = 2 * setSize
+	evict, err = tp.setsToEvict(ps, nil)
+	if err != nil || len(evict) != 2 || evict[0] != low || evict[1] != mid {
+		t.Fatal("wrong eviction", evict, err)
+	}
+
+	// The set with the highest fee rate isn't evicted for a set paying less.
+	tp.maxSize = setSize
+	if _, err := tp.setsToEvict(ps, nil); err != modules.ErrTransactionPoolFull {
+		t.Fatal("expected ErrTransactionPoolFull but got", err)
+	}
+
+	// Excluded sets aren't counted.
+	exclude := map[modules.TransactionSetID]struct{}{high: {}}
+	tp.maxSize = 3 * setSize
+	if evict, err := tp.setsToEvict(ps, exclude); err != nil || len(evict) != 0 {
+		t.Fatal("unexpected eviction", evict, err)
+	}
+}
+
+// TestContents checks that Contents returns the sets sorted by fee rate.
+func TestContents(t *testing.T) {
+	t.Parallel()
+	tp := newBareTpool()
+	var ids []modules.TransactionSetID
+	for i := uint64(1); i <= 5; i++ {
+		ids = append(ids, tp.addBareSet(spendingSet(randomOutputID(), types.NewCurrency64(1000*i))))
+	}
+
+	contents, err := tp.Contents(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contents.TotalSets != 5 || len(contents.Sets) != 5 {
+		t.Fatal("wrong number of sets", contents.TotalSets, len(contents.Sets))
+	}
+	if contents.Size != uint64(tp.transactionListSize) || contents.MaxSize != defaultMaxSize {
+		t.Fatal("wrong size", contents.Size, contents.MaxSize)
+	}
+	for i, ps := range contents.Sets {
+		if ps.ID != ids[len(ids)-1-i] {
+			t.Fatal("sets aren't sorted by fee rate")
+		}
+		if !ps.FeeRate.Equals(ps.Fees.Div64(ps.Size)) {
+			t.Fatal("wrong fee rate", ps.FeeRate)
+		}
+	}
+
+	// Paginate.
+	contents, err = tp.Contents(3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contents.Sets) != 2 || contents.Sets[0].ID != ids[1] || contents.Sets[1].ID != ids[0] {
+		t.Fatal("wrong page", len(contents.Sets))
+	}
+	contents, err = tp.Contents(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contents.Sets) != 2 || contents.Sets[0].ID != ids[3] || contents.Sets[1].ID != ids[2] {
+		t.Fatal("wrong page", len(contents.Sets))
+	}
+	contents, err = tp.Contents(5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contents.Sets == nil || len(contents.Sets) != 0 {
+		t.Fatal("expected an empty page", contents.Sets)
+	}
+}
@@ -0,0 +1,212 @@
+package transactionpool
+
+import (
+	"bytes"
+	"sort"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// feeRate is the fee per byte paid by a transaction set of the given size.
+type feeRate struct {
+	fee  types.Currency
+	size uint64
+}
+
+// setFeeRate returns the fee rate of a transaction set.
+func setFeeRate(set []types.Transaction) feeRate {
+	var feeSum types.Currency
+	var size uint64
+	b := new(bytes.Buffer)
+	for _, txn := range set {
+		txn.MarshalSia(b)
+		size += uint64(b.Len())
+		b.Reset()
+		for _, fee := range txn.MinerFees {
+			feeSum = feeSum.Add(fee)
+		}
+	}
+	if size == 0 {
+		return feeRate{}
+	}
+	return feeRate{fee: feeSum.Div64(size), size: size}
+}
+
+// sortFeeRates sorts the fee rates by fee in ascending order.
+func sortFeeRates(rates []feeRate) {
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].fee.Cmp(rates[j].fee) < 0
+	})
+}
+
+// feePercentile returns the fee rate at which more than the given percentage
+// of the bytes is covered, counting from the lowest fee rate. The rates need
+// to be sorted in ascending order.
+func feePercentile(rates []feeRate, percentile uint64) types.Currency {
+	var total uint64
+	for _, r := range rates {
+		total += r.size
+	}
+	var progress uint64
+	for _, r := range rates {
+		progress += r.size
+		if progress*100 > total*percentile {
+			return r.fee
+		}
+	}
+	return types.ZeroCurrency
+}
+
+// feePercentiles returns the percentiles of the fee rates. The rates need to be
+// sorted in ascending order.
+func feePercentiles(rates []feeRate) modules.FeePercentiles {
+	return modules.FeePercentiles{
+		P25: feePercentile(rates, 25),
+		P50: feePercentile(rates, 50),
+		P90: feePercentile(rates, 90),
+	}
+}
+
+// targetFeeRate returns the fee rate a transaction needs to pay to be among
+// the transactions with the highest fee rates that fill the given number of
+// blocks. If all transactions fit into the blocks, it returns zero. The rates
+// need to be sorted in ascending order.
+func targetFeeRate(rates []feeRate, blocks uint64) types.Currency {
+	var progress uint64
+	for i := len(rates) - 1; i >= 0; i-- {
+		progress += rates[i].size
+		if progress > blocks*types.BlockSizeLimit {
+			return rates[i].fee
+		}
+	}
+	return types.ZeroCurrency
+}
+
+// blockFeePercentiles returns the fee percentiles of a block. The unused space
+// of the block is counted as space paying no fees.
+func blockFeePercentiles(b types.Block) modules.FeePercentiles {
+	var rates []feeRate
+	var totalSize uint64
+	for _, set := range findSets(b.Transactions) {
+		r := setFeeRate(set)
+		rates = append(rates, r)
+		totalSize += r.size
+	}
+	if totalSize < types.BlockSizeLimit {
+		rates = append(rates, feeRate{size: types.BlockSizeLimit - totalSize})
+	}
+	sortFeeRates(rates)
+	return feePercentiles(rates)
+}
+
+// medianFeePercentiles returns the median of each of the fee percentiles.
+func medianFeePercentiles(fps []modules.FeePercentiles) modules.FeePercentiles {
+	if len(fps) == 0 {
+		return modules.FeePercentiles{}
+	}
+	median := func(get func(modules.FeePercentiles) types.Currency) types.Currency {
+		fees := make([]types.Currency, 0, len(fps))
+		for _, fp := range fps {
+			fees = append(fees, get(fp))
+		}
+		sort.Slice(fees, func(i, j int) bool {
+			return fees[i].Cmp(fees[j]) < 0
+		})
+		return fees[len(fees)/2]
+	}
+	return modules.FeePercentiles{
+		P25: median(func(fp modules.FeePercentiles) types.Currency { return fp.P25 }),
+		P50: median(func(fp modules.FeePercentiles) types.Currency { return fp.P50 }),
+		P90: median(func(fp modules.FeePercentiles) types.Currency { return fp.P90 }),
+	}
+}
+
+// maxCurrency returns the largest of the provided currencies.
+func maxCurrency(cs ...types.Currency) types.Currency {
+	var max types.Currency
+	for _, c := range cs {
+		if c.Cmp(max) > 0 {
+			max = c
+		}
+	}
+	return max
+}
+
+// computeFeeEstimate computes the fee estimate from the fee percentiles of the
+// recent blocks, the fee rates of the transaction pool's sets and the fee
+// which is required to get into the transaction pool. The pool rates need to
+// be sorted in ascending order.
+func computeFeeEstimate(recentBlocks []modules.FeePercentiles, poolRates []feeRate, poolFee types.Currency) modules.FeeEstimate {
+	est := modules.FeeEstimate{
+		BlockFees: medianFeePercentiles(recentBlocks),
+		NumBlocks: len(recentBlocks),
+		PoolFees:  feePercentiles(poolRates),
+	}
+	for _, r := range poolRates {
+		est.PoolSize += r.size
+	}
+
+	// Every estimation needs to get the transaction into the pool and be above
+	// the absolute minimum. The percentiles of the recent blocks and the
+	// ranking within the pool decide how much more is needed for the target.
+	floor := maxCurrency(poolFee, minEstimation)
+	est.Economy = maxCurrency(floor, est.BlockFees.P25, targetFeeRate(poolRates, feeTargetEconomy))
+	est.Standard = maxCurrency(floor, est.BlockFees.P50, targetFeeRate(poolRates, feeTargetStandard))
+	est.Priority = maxCurrency(est.Standard.Mul64(maxMultiplier), est.BlockFees.P90, targetFeeRate(poolRates, feeTargetPriority))
+	return est
+}
+
+// poolFeeRates returns the fee rates of the transaction pool's sets sorted in
+// ascending order.
+func (tp *TransactionPool) poolFeeRates() []feeRate {
+	rates := make([]feeRate, 0, len(tp.transactionSets))
+	for id, set := range tp.transactionSets {
+		// Use the sizes which were computed for the subscribers if possible.
+		ut, exists := tp.subscriberSets[id]
+		if !exists {
+			rates = append(rates, setFeeRate(set))
+			continue
+		}
+		var r feeRate
+		var feeSum types.Currency
+		for i, txn := range ut.Transactions {
+			r.size += ut.Sizes[i]
+			for _, fee := range txn.MinerFees {
+				feeSum = feeSum.Add(fee)
+			}
+		}
+		if r.size > 0 {
+			r.fee = feeSum.Div64(r.size)
+		}
+		rates = append(rates, r)
+	}
+	sortFeeRates(rates)
+	return rates
+}
+
+// paddedPoolFee returns the fee required to extend the transaction pool by a
+// padding. The fixed padding handles cases where the pool is really small and
+// a low number of transactions can move the fee substantially. The
+// proportional padding is for when the pool is large and there is a lot of
+// activity which is adding to the pool. The larger of the two is used.
+func (tp *TransactionPool) paddedPoolFee() types.Currency {
+	sizeAfterConstantPadding := tp.transactionListSize + feeEstimationConstantPadding
+	sizeAfterProportionalPadding := int(float64(tp.transactionListSize) * float64(feeEstimationProportionalPadding))
+	if sizeAfterConstantPadding > sizeAfterProportionalPadding {
+		return requiredFeesToExtendTpoolAtSize(sizeAfterConstantPadding)
+	}
+	return requiredFeesToExtendTpoolAtSize(sizeAfterProportionalPadding)
+}
+
+// FeeEstimate returns the fee estimation per byte for different confirmation
+// targets together with the statistics it is based on.
+func (tp *TransactionPool) FeeEstimate() modules.FeeEstimate {
+	if err := tp.tg.Add(); err != nil {
+		return modules.FeeEstimate{}
+	}
+	defer tp.tg.Done()
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return computeFeeEstimate(tp.recentBlockFees, tp.poolFeeRates(), tp.paddedPoolFee())
+}
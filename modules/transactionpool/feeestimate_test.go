@@ -0,0 +1,95 @@
+package transactionpool
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestFeePercentiles is a unit test for feePercentiles and targetFeeRate.
+func TestFeePercentiles(t *testing.T) {
+	t.Parallel()
+
+	rates := []feeRate{
+		{fee: types.NewCurrency64(30), size: 10},
+		{fee: types.NewCurrency64(10), size: 20},
+		{fee: types.ZeroCurrency, size: 60},
+		{fee: types.NewCurrency64(20), size: 10},
+	}
+	sortFeeRates(rates)
+	fp := feePercentiles(rates)
+	if !fp.P25.IsZero() || !fp.P50.IsZero() || !fp.P90.Equals64(30) {
+		t.Fatal("wrong percentiles", fp)
+	}
+	if fp := feePercentiles(nil); !fp.P25.IsZero() || !fp.P50.IsZero() || !fp.P90.IsZero() {
+		t.Fatal("percentiles of no rates should be zero", fp)
+	}
+
+	// All of the rates fit into a block.
+	if fee := targetFeeRate(rates, 1); !fee.IsZero() {
+		t.Fatal("expected zero fee but got", fee)
+	}
+	// Only the rates with the highest fees fit into a block.
+	full := []feeRate{
+		{fee: types.NewCurrency64(1), size: types.BlockSizeLimit},
+		{fee: types.NewCurrency64(2), size: types.BlockSizeLimit / 2},
+		{fee: types.NewCurrency64(3), size: types.BlockSizeLimit / 2},
+	}
+	if fee := targetFeeRate(full, 1); !fee.Equals64(1) {
+		t.Fatal("expected a fee of 1 but got", fee)
+	}
+	if fee := targetFeeRate(full, 2); !fee.IsZero() {
+		t.Fatal("expected zero fee but got", fee)
+	}
+}
+
+// TestComputeFeeEstimate is a unit test for computeFeeEstimate.
+func TestComputeFeeEstimate(t *testing.T) {
+	t.Parallel()
+
+	// Without any congestion the estimations are at the minimum.
+	est := computeFeeEstimate(nil, nil, types.ZeroCurrency)
+	if !est.Economy.Equals(minEstimation) || !est.Standard.Equals(minEstimation) {
+		t.Fatal("estimations should be at the minimum", est)
+	}
+	if !est.Priority.Equals(minEstimation.Mul64(maxMultiplier)) {
+		t.Fatal("wrong priority estimation", est.Priority)
+	}
+
+	// The block fees raise the estimations according to their percentiles.
+	fee := minEstimation.Mul64(10)
+	blocks := []modules.FeePercentiles{
+		{P25: fee, P50: fee.Mul64(2), P90: fee.Mul64(100)},
+		{P25: fee, P50: fee.Mul64(3), P90: fee.Mul64(100)},
+		{},
+	}
+	est = computeFeeEstimate(blocks, nil, types.ZeroCurrency)
+	if est.NumBlocks != 3 || !est.BlockFees.P50.Equals(fee.Mul64(2)) {
+		t.Fatal("wrong block fees", est.BlockFees)
+	}
+	if !est.Economy.Equals(fee) || !est.Standard.Equals(fee.Mul64(2)) || !est.Priority.Equals(fee.Mul64(100)) {
+		t.Fatal("wrong estimations", est)
+	}
+
+	// A full transaction pool raises the estimations to the fees needed to
+	// get into the next blocks.
+	pool := []feeRate{
+		{fee: fee.Mul64(200), size: 2 * types.BlockSizeLimit},
+		{fee: fee.Mul64(300), size: types.BlockSizeLimit},
+		{fee: fee.Mul64(400), size: types.BlockSizeLimit},
+	}
+	est = computeFeeEstimate(blocks, pool, types.ZeroCurrency)
+	if est.PoolSize != 4*types.BlockSizeLimit {
+		t.Fatal("wrong pool size", est.PoolSize)
+	}
+	if !est.Economy.Equals(fee) || !est.Standard.Equals(fee.Mul64(200)) || !est.Priority.Equals(fee.Mul64(600)) {
+		t.Fatal("wrong estimations", est)
+	}
+
+	// The fee required to get into the pool is the floor.
+	est = computeFeeEstimate(nil, nil, fee)
+	if !est.Economy.Equals(fee) || !est.Standard.Equals(fee) {
+		t.Fatal("estimations should be at the pool fee", est)
+	}
+}
@@ -174,8 +174,17 @@ func (tp *TransactionPool) initPersist() error {
 	// Just leave the fields empty if no fee median was found. They will be
 	// filled out.
 	if !errors.Contains(err, errNilFeeMedian) {
-		tp.recentMedians = mp.RecentMedians
-		tp.recentMedianFee = mp.RecentMedianFee
+		tp.recentBlockFees = mp.RecentBlockFees
+		// Older versions only persisted a single fee per block.
+		if len(tp.recentBlockFees) == 0 {
+			for _, median := range mp.RecentMedians {
+				tp.recentBlockFees = append(tp.recentBlockFees, modules.FeePercentiles{
+					P25: median,
+					P50: median,
+					P90: median,
+				})
+			}
+		}
 	}
 
 	// Subscribe to the consensus set using the most recent consensus change.
@@ -0,0 +1,104 @@
+package transactionpool
+
+import (
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// errLowReplacementFees is returned if a transaction set double spends
+	// sets of the pool but doesn't pay enough fees to replace them.
+	errLowReplacementFees = errors.New("transaction set double spends transactions of the pool and doesn't pay enough fees to replace them")
+)
+
+// spentObjectIDs returns the ids of the siacoin and siafund outputs spent by a
+// transaction. Spending one of them in another transaction is a double spend.
+// File contract revisions are not included, since multiple revisions of a
+// contract can be confirmed together.
+func spentObjectIDs(t types.Transaction) []ObjectID {
+	oids := make([]ObjectID, 0, len(t.SiacoinInputs)+len(t.SiafundInputs))
+	for _, sci := range t.SiacoinInputs {
+		oids = append(oids, ObjectID(sci.ParentID))
+	}
+	for _, sfi := range t.SiafundInputs {
+		oids = append(oids, ObjectID(sfi.ParentID))
+	}
+	return oids
+}
+
+// doubleSpentSets returns the sets of the pool which contain a transaction
+// that spends an output which is spent by a different transaction of ts.
+func (tp *TransactionPool) doubleSpentSets(ts []types.Transaction) map[modules.TransactionSetID]struct{} {
+	spenders := make(map[ObjectID]types.TransactionID)
+	for _, t := range ts {
+		txid := t.ID()
+		for _, oid := range spentObjectIDs(t) {
+			spenders[oid] = txid
+		}
+	}
+
+	replaced := make(map[modules.TransactionSetID]struct{})
+	for oid, txid := range spenders {
+		setID, exists := tp.knownObjects[oid]
+		if !exists {
+			continue
+		}
+		if _, exists := replaced[setID]; exists {
+			continue
+		}
+		for _, t := range tp.transactionSets[setID] {
+			for _, spent := range spentObjectIDs(t) {
+				if spent == oid && t.ID() != txid {
+					replaced[setID] = struct{}{}
+				}
+			}
+		}
+	}
+	return replaced
+}
+
+// checkReplacement checks that a transaction set pays enough fees to replace
+// the sets it double spends. It needs to pay a higher fee rate than each of
+// them, and its fees need to exceed their combined fees by at least the
+// minimum fee rate for its own size, so that repeated replacements can't be
+// used to flood the network for free.
+func (tp *TransactionPool) checkReplacement(ts []types.Transaction, replaced map[modules.TransactionSetID]struct{}) error {
+	ps := poolSet(modules.TransactionSetID{}, ts)
+	var replacedFees types.Currency
+	for id := range replaced {
+		rps := poolSet(id, tp.transactionSets[id])
+		if ps.FeeRate.Cmp(rps.FeeRate) <= 0 {
+			return errLowReplacementFees
+		}
+		replacedFees = replacedFees.Add(rps.Fees)
+	}
+	if ps.Fees.Cmp(replacedFees.Add(minEstimation.Mul64(ps.Size))) < 0 {
+		return errLowReplacementFees
+	}
+	return nil
+}
+
+// replaceTransactionSets replaces the sets which are double spent by ts with
+// ts. If ts isn't accepted, the replaced sets are restored.
+func (tp *TransactionPool) replaceTransactionSets(ts []types.Transaction, replaced map[modules.TransactionSetID]struct{}, txnFn func([]types.Transaction) (modules.ConsensusChange, error)) ([]types.Transaction, error) {
+	if err := tp.checkReplacement(ts, replaced); err != nil {
+		return nil, err
+	}
+	removed := make([]removedSet, 0, len(replaced))
+	for id := range replaced {
+		removed = append(removed, tp.removeTransactionSet(id))
+	}
+	superset, err := tp.acceptTransactionSet(ts, txnFn)
+	if err != nil {
+		for _, rs := range removed {
+			tp.restoreTransactionSet(rs)
+		}
+		return nil, err
+	}
+	for _, rs := range removed {
+		tp.log.Debugln("Replaced transaction set", rs.id, "with a set paying higher fees")
+	}
+	return superset, nil
+}
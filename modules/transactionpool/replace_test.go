@@ -0,0 +1,60 @@
+package transactionpool
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestDoubleSpentSets checks that only sets which spend the same outputs with
+// a different transaction are replaced.
+func TestDoubleSpentSets(t *testing.T) {
+	t.Parallel()
+	tp := newBareTpool()
+	parent := randomOutputID()
+	original := spendingSet(parent, types.SiacoinPrecision)
+	id := tp.addBareSet(original)
+	tp.addBareSet(spendingSet(randomOutputID(), types.SiacoinPrecision))
+
+	// Resubmitting the same transaction isn't a double spend.
+	if replaced := tp.doubleSpentSets(original); len(replaced) != 0 {
+		t.Fatal("resubmitted set is a double spend", replaced)
+	}
+	// Spending another output isn't a double spend either.
+	if replaced := tp.doubleSpentSets(spendingSet(randomOutputID(), types.SiacoinPrecision)); len(replaced) != 0 {
+		t.Fatal("unrelated set is a double spend", replaced)
+	}
+	// Spending the same output in another transaction is.
+	replaced := tp.doubleSpentSets(spendingSet(parent, types.SiacoinPrecision.Mul64(2)))
+	if _, exists := replaced[id]; !exists || len(replaced) != 1 {
+		t.Fatal("wrong replaced sets", replaced)
+	}
+}
+
+// TestCheckReplacement checks that replacements need to pay a higher fee rate
+// and cover the fees of the replaced sets.
+func TestCheckReplacement(t *testing.T) {
+	t.Parallel()
+	tp := newBareTpool()
+	parent := randomOutputID()
+	fee := types.SiacoinPrecision
+	id := tp.addBareSet(spendingSet(parent, fee))
+	replaced := map[modules.TransactionSetID]struct{}{id: {}}
+
+	if err := tp.checkReplacement(spendingSet(parent, fee), replaced); err != errLowReplacementFees {
+		t.Fatal("expected errLowReplacementFees but got", err)
+	}
+	if err := tp.checkReplacement(spendingSet(parent, fee.Sub64(1)), replaced); err != errLowReplacementFees {
+		t.Fatal("expected errLowReplacementFees but got", err)
+	}
+	// A higher fee rate isn't enough if it doesn't pay for its own size.
+	if err := tp.checkReplacement(spendingSet(parent, fee.Add64(1)), replaced); err != errLowReplacementFees {
+		t.Fatal("expected errLowReplacementFees but got", err)
+	}
+	ts := spendingSet(parent, fee)
+	ts[0].MinerFees[0] = fee.Add(minEstimation.Mul64(poolSet(id, ts).Size))
+	if err := tp.checkReplacement(ts, replaced); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -55,10 +55,14 @@ type (
 		transactionSetDiffs map[modules.TransactionSetID]*modules.ConsensusChange
 		transactionListSize int
 
+		// maxSize is the maximum size of the transaction pool in bytes. Once
+		// it is reached, the sets with the lowest fee rates are evicted to
+		// make room for sets paying higher fee rates.
+		maxSize uint64
+
 		// Variables related to the blockchain.
 		blockHeight     types.BlockHeight
-		recentMedians   []types.Currency
-		recentMedianFee types.Currency // SC per byte
+		recentBlockFees []modules.FeePercentiles
 
 		// The consensus change index tracks how many consensus changes have
 		// been sent to the transaction pool. When a new subscriber joins the
@@ -106,6 +110,8 @@ func NewCustomTPool(cs modules.ConsensusSet, g modules.Gateway, persistDir strin
 		transactionSets:     make(map[modules.TransactionSetID][]types.Transaction),
 		transactionSetDiffs: make(map[modules.TransactionSetID]*modules.ConsensusChange),
 
+		maxSize: defaultMaxSize,
+
 		deps:       deps,
 		persistDir: persistDir,
 	}
@@ -138,60 +144,10 @@ func (tp *TransactionPool) Close() error {
 
 // FeeEstimation returns an estimation for what fee should be applied to
 // transactions. It returns a minimum and maximum estimated fee per transaction
-// byte.
+// byte, which are the standard and priority estimations of FeeEstimate.
 func (tp *TransactionPool) FeeEstimation() (min, max types.Currency) {
-	err := tp.tg.Add()
-	if err != nil {
-		return
-	}
-	defer tp.tg.Done()
-	tp.mu.Lock()
-	defer tp.mu.Unlock()
-
-	// Use three methods to determine an acceptable fee. The first method looks
-	// at what fee is required to get into a block on the blockchain based on
-	// the actual fees of transactions confirmed in recent blocks. The second
-	// method looks at the current tpool and performs fee estimation based on
-	// the other transactions in the tpool. The third method is an absolute
-	// minimum.
-
-	// First method: use the median fees calculated while looking at
-	// transactions that have been confirmed in the recent blocks.
-	feeByBlockchain := tp.recentMedianFee
-
-	// Second method: use the median fees calculated while looking at the
-	// current size of the transaction pool. For the min fee, use a size that's
-	// a fixed size larger than the current pool, and then also add some
-	// proportional padding. The fixed size handles cases where the tpool is
-	// really small, and a low number of transactions can move the fee
-	// substantially. The proportional padding is for when the tpool is large
-	// and there is a lot of activity which is adding to the tpool.
-	//
-	// The sizes for proportional and constant are computed independently, and
-	// then the max is taken of the two.
-	sizeAfterConstantPadding := tp.transactionListSize + feeEstimationConstantPadding
-	sizeAfterProportionalPadding := int(float64(tp.transactionListSize) * float64(feeEstimationProportionalPadding))
-	var feeByCurrentTpoolSize types.Currency
-	if sizeAfterConstantPadding > sizeAfterProportionalPadding {
-		feeByCurrentTpoolSize = requiredFeesToExtendTpoolAtSize(sizeAfterConstantPadding)
-	} else {
-		feeByCurrentTpoolSize = requiredFeesToExtendTpoolAtSize(sizeAfterProportionalPadding)
-	}
-
-	// Pick the larger of the first two methods to be compared with the third
-	// method.
-	if feeByBlockchain.Cmp(feeByCurrentTpoolSize) > 0 {
-		min = feeByBlockchain
-	} else {
-		min = feeByCurrentTpoolSize
-	}
-
-	// Third method: ensure the fee is above an absolute minimum.
-	if min.Cmp(minEstimation) < 0 {
-		min = minEstimation
-	}
-	max = min.Mul64(maxMultiplier)
-	return
+	est := tp.FeeEstimate()
+	return est.Standard, est.Priority
 }
 
 // TransactionList returns a list of all transactions in the transaction pool.
@@ -292,6 +248,30 @@ func (tp *TransactionPool) Transactions() []types.Transaction {
 	return txns
 }
 
+// Contents returns the transaction sets of the pool sorted by fee rate in
+// descending order. At most limit sets are returned, starting with the set at
+// offset.
+func (tp *TransactionPool) Contents(offset, limit uint64) (modules.TransactionPoolContents, error) {
+	if err := tp.tg.Add(); err != nil {
+		return modules.TransactionPoolContents{}, err
+	}
+	defer tp.tg.Done()
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+
+	sets := tp.setsByFeeRate()
+	contents := modules.TransactionPoolContents{
+		Sets:      []modules.TransactionPoolSet{},
+		TotalSets: uint64(len(sets)),
+		Size:      uint64(tp.transactionListSize),
+		MaxSize:   tp.maxSize,
+	}
+	for i := offset; i < contents.TotalSets && uint64(len(contents.Sets)) < limit; i++ {
+		contents.Sets = append(contents.Sets, sets[contents.TotalSets-1-i])
+	}
+	return contents, nil
+}
+
 // TransactionSet returns the transaction set the provided object appears in.
 func (tp *TransactionPool) TransactionSet(oid crypto.Hash) []types.Transaction {
 	tp.mu.RLock()
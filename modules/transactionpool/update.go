@@ -1,9 +1,7 @@
 package transactionpool
 
 import (
-	"bytes"
 	"fmt"
-	"sort"
 	"time"
 
 	"gitlab.com/NebulousLabs/errors"
@@ -216,9 +214,9 @@ func (tp *TransactionPool) ProcessConsensusChange(cc modules.ConsensusChange) {
 		// over 10 blocks, it is extremely likely that there will be more
 		// applied blocks than reverted blocks, and if there aren't (a height
 		// decreasing reorg), there will be more than 10 applied blocks.
-		if len(tp.recentMedians) > 0 {
+		if len(tp.recentBlockFees) > 0 {
 			// Strip out all of the transactions in this block.
-			tp.recentMedians = tp.recentMedians[:len(tp.recentMedians)-1]
+			tp.recentBlockFees = tp.recentBlockFees[:len(tp.recentBlockFees)-1]
 		}
 	}
 
@@ -237,70 +235,15 @@ func (tp *TransactionPool) ProcessConsensusChange(cc modules.ConsensusChange) {
 			}
 		}
 
-		// Find the median transaction fee for this block.
-		type feeSummary struct {
-			fee  types.Currency
-			size int
-		}
-		var fees []feeSummary
-		var totalSize int
-		txnSets := findSets(block.Transactions)
-		for _, set := range txnSets {
-			// Compile the fees for this set.
-			var feeSum types.Currency
-			var sizeSum int
-			b := new(bytes.Buffer)
-			for _, txn := range set {
-				txn.MarshalSia(b)
-				sizeSum += b.Len()
-				b.Reset()
-				for _, fee := range txn.MinerFees {
-					feeSum = feeSum.Add(fee)
-				}
-			}
-			feeAvg := feeSum.Div64(uint64(sizeSum))
-			fees = append(fees, feeSummary{
-				fee:  feeAvg,
-				size: sizeSum,
-			})
-			totalSize += sizeSum
-		}
-		// Add an extra zero-fee tranasction for any unused block space.
-		remaining := int(types.BlockSizeLimit) - totalSize
-		fees = append(fees, feeSummary{
-			fee:  types.ZeroCurrency,
-			size: remaining, // fine if remaining is zero.
-		})
-		// Sort the fees by value and then scroll until the median.
-		sort.Slice(fees, func(i, j int) bool {
-			return fees[i].fee.Cmp(fees[j].fee) < 0
-		})
-		var progress int
-		for i := range fees {
-			progress += fees[i].size
-			// Instead of grabbing the full median, look at the 75%-ile. It's
-			// going to be cheaper than the 50%-ile, but it still got into a
-			// block.
-			if uint64(progress) > types.BlockSizeLimit/4 {
-				tp.recentMedians = append(tp.recentMedians, fees[i].fee)
-				break
-			}
-		}
+		// Record the fee percentiles of this block.
+		tp.recentBlockFees = append(tp.recentBlockFees, blockFeePercentiles(block))
 
 		// If there are more than 10 blocks recorded in the txnsPerBlock, strip
 		// off the oldest blocks.
-		for len(tp.recentMedians) > blockFeeEstimationDepth {
-			tp.recentMedians = tp.recentMedians[1:]
+		for len(tp.recentBlockFees) > blockFeeEstimationDepth {
+			tp.recentBlockFees = tp.recentBlockFees[1:]
 		}
 	}
-	// Grab the median of the recent medians. Copy to a new slice so the sorting
-	// doesn't screw up the slice.
-	safeMedians := make([]types.Currency, len(tp.recentMedians))
-	copy(safeMedians, tp.recentMedians)
-	sort.Slice(safeMedians, func(i, j int) bool {
-		return safeMedians[i].Cmp(safeMedians[j]) < 0
-	})
-	tp.recentMedianFee = safeMedians[len(safeMedians)/2]
 
 	// Update all the on-disk structures.
 	tp.blockHeight = cc.BlockHeight
@@ -317,8 +260,7 @@ func (tp *TransactionPool) ProcessConsensusChange(cc modules.ConsensusChange) {
 		tp.log.Println("ERROR: could not update the block height:", err)
 	}
 	err = tp.putFeeMedian(tp.dbTx, medianPersist{
-		RecentMedians:   tp.recentMedians,
-		RecentMedianFee: tp.recentMedianFee,
+		RecentBlockFees: tp.recentBlockFees,
 	})
 	if err != nil {
 		tp.log.Println("ERROR: could not update the transaction pool median fee information:", err)
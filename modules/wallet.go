@@ -2,10 +2,12 @@ package modules
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 
 	mnemonics "gitlab.com/NebulousLabs/entropy-mnemonics"
@@ -15,6 +17,10 @@ import (
 )
 
 const (
+	// MaxTransactionLabelLength is the maximum length in characters of the
+	// label of a wallet transaction.
+	MaxTransactionLabelLength = 1024
+
 	// PublicKeysPerSeed define the number of public keys that get pregenerated
 	// for a seed at startup when searching for balances in the blockchain.
 	PublicKeysPerSeed = 2500
@@ -27,6 +33,57 @@ const (
 	WalletDir = "wallet"
 )
 
+const (
+	// SelectMinimizeInputs funds a transaction with the largest outputs of
+	// the wallet, which results in the fewest inputs. It is the default
+	// selection strategy.
+	SelectMinimizeInputs SelectionStrategy = "minimizeinputs"
+
+	// SelectMinimizeChange funds a transaction with the outputs that exceed
+	// the required amount by the least, which results in the smallest change
+	// output.
+	SelectMinimizeChange SelectionStrategy = "minimizechange"
+
+	// SelectConsolidate funds a transaction with the smallest outputs of the
+	// wallet, including dust, which reduces the number of outputs the wallet
+	// has to track.
+	SelectConsolidate SelectionStrategy = "consolidate"
+)
+
+const (
+	// BroadcastStatusPending is the status of a broadcast transaction set
+	// which is in the transaction pool and waits to be confirmed.
+	BroadcastStatusPending BroadcastStatus = "pending"
+
+	// BroadcastStatusEvicted is the status of a broadcast transaction set
+	// which was dropped by the transaction pool and can't be added again,
+	// usually because its fees are too low.
+	BroadcastStatusEvicted BroadcastStatus = "evicted"
+
+	// BroadcastStatusDoubleSpent is the status of a broadcast transaction set
+	// which spends outputs that were spent by another confirmed transaction.
+	// It can never be confirmed.
+	BroadcastStatusDoubleSpent BroadcastStatus = "doublespent"
+)
+
+const (
+	// WalletEventBalance is emitted when the confirmed or unconfirmed balance
+	// of the wallet changes.
+	WalletEventBalance WalletEventType = "balance"
+
+	// WalletEventTransactionConfirmed is emitted when a transaction related
+	// to the wallet is confirmed in a block.
+	WalletEventTransactionConfirmed WalletEventType = "transactionconfirmed"
+
+	// WalletEventTransactionReverted is emitted when a confirmed transaction
+	// related to the wallet is reverted by a reorg.
+	WalletEventTransactionReverted WalletEventType = "transactionreverted"
+
+	// WalletEventTransactionUnconfirmed is emitted when a transaction related
+	// to the wallet enters the transaction pool.
+	WalletEventTransactionUnconfirmed WalletEventType = "transactionunconfirmed"
+)
+
 var (
 	// ErrBadEncryptionKey is returned if the incorrect encryption key to a
 	// file is provided.
@@ -49,6 +106,10 @@ var (
 	// ErrWalletShutdown is returned when a method can't continue execution due
 	// to the wallet shutting down.
 	ErrWalletShutdown = errors.New("wallet is shutting down")
+
+	// ErrUnknownSelectionStrategy is returned when parsing a selection
+	// strategy which the wallet doesn't support.
+	ErrUnknownSelectionStrategy = errors.New("unknown output selection strategy")
 )
 
 type (
@@ -59,6 +120,14 @@ type (
 	// WalletTransactionID is a unique identifier for a wallet transaction.
 	WalletTransactionID crypto.Hash
 
+	// BroadcastStatus is the status of a transaction set which was broadcast
+	// by the wallet.
+	BroadcastStatus string
+
+	// SelectionStrategy determines which of the wallet's outputs are used to
+	// fund a transaction.
+	SelectionStrategy string
+
 	// A ProcessedInput represents funding to a transaction. The input is
 	// coming from an address and going to the outputs. The fund types are
 	// 'SiacoinInput', 'SiafundInput'.
@@ -126,6 +195,87 @@ type (
 		Value              types.Currency    `json:"value"`
 		ConfirmationHeight types.BlockHeight `json:"confirmationheight"`
 		IsWatchOnly        bool              `json:"iswatchonly"`
+
+		// DoNotSpend indicates that the output was marked by the user and is
+		// never used to fund a transaction.
+		DoNotSpend bool `json:"donotspend"`
+	}
+
+	// PendingSend is a send of siacoins which exceeded the approval threshold
+	// of the wallet's spending limits. It is held until it is approved or
+	// rejected. Multi is set for sends to multiple outputs, and FeeIncluded
+	// only applies to sends which aren't.
+	PendingSend struct {
+		ID          string                `json:"id"`
+		Outputs     []types.SiacoinOutput `json:"outputs"`
+		Multi       bool                  `json:"multi"`
+		FeeIncluded bool                  `json:"feeincluded"`
+		Strategy    SelectionStrategy     `json:"strategy"`
+		Created     types.Timestamp       `json:"created"`
+	}
+
+	// BroadcastTransactionSet is a transaction set which was broadcast by the
+	// wallet. The wallet rebroadcasts it until it is confirmed. Its ID is the
+	// ID of the last transaction of the set, Height is the height at which it
+	// was broadcast first.
+	BroadcastTransactionSet struct {
+		ID            types.TransactionID `json:"id"`
+		Transactions  []types.Transaction `json:"transactions"`
+		Status        BroadcastStatus     `json:"status"`
+		Height        types.BlockHeight   `json:"height"`
+		Broadcasts    uint64              `json:"broadcasts"`
+		LastBroadcast types.Timestamp     `json:"lastbroadcast"`
+	}
+
+	// PendingSendError is returned by the wallet's send methods if a send
+	// exceeds the approval threshold and was queued for approval.
+	PendingSendError struct {
+		ID string
+	}
+
+	// WalletSpendingLimits restrict the siacoins the wallet sends. A zero
+	// daily limit or approval threshold disables the limit. Sends above the
+	// approval threshold are held until they are approved, and the approval
+	// webhook, if set, is notified about them.
+	WalletSpendingLimits struct {
+		DailyLimit        types.Currency `json:"dailylimit"`
+		ApprovalThreshold types.Currency `json:"approvalthreshold"`
+		ApprovalWebhook   string         `json:"approvalwebhook"`
+	}
+
+	// AddressBookEntry is a named address of the wallet's address book.
+	AddressBookEntry struct {
+		Name    string           `json:"name"`
+		Address types.UnlockHash `json:"address"`
+	}
+
+	// ColdTransaction is an unsigned transaction which spends the outputs of
+	// watch-only addresses. It is created by a watch-only wallet, signed on an
+	// air-gapped machine which only knows the seed of the addresses and then
+	// imported again for broadcast.
+	ColdTransaction struct {
+		Transaction types.Transaction `json:"transaction"`
+		ToSign      []crypto.Hash     `json:"tosign"`
+		Height      types.BlockHeight `json:"height"`
+
+		// Funds is the value of the spent outputs, which allows the signer to
+		// check the fee.
+		Funds types.Currency `json:"funds"`
+	}
+
+	// WatchAddressBalance is the balance of an address that the wallet watches
+	// without holding its keys.
+	WatchAddressBalance struct {
+		Address types.UnlockHash `json:"address"`
+
+		ConfirmedSiacoinBalance     types.Currency `json:"confirmedsiacoinbalance"`
+		UnconfirmedOutgoingSiacoins types.Currency `json:"unconfirmedoutgoingsiacoins"`
+		UnconfirmedIncomingSiacoins types.Currency `json:"unconfirmedincomingsiacoins"`
+		SiafundBalance              types.Currency `json:"siafundbalance"`
+
+		// Transactions is the number of confirmed transactions related to the
+		// address.
+		Transactions uint64 `json:"transactions"`
 	}
 
 	// TransactionBuilder is used to construct custom transactions. A transaction
@@ -159,6 +309,11 @@ type (
 		// failed.
 		FundSiafunds(amount types.Currency) error
 
+		// SetSelectionStrategy sets the strategy which is used to select the
+		// outputs that fund the transaction in subsequent calls to
+		// 'FundSiacoins'.
+		SetSelectionStrategy(strategy SelectionStrategy)
+
 		// AddParents adds a set of parents to the transaction.
 		AddParents([]types.Transaction)
 
@@ -438,16 +593,22 @@ type (
 
 		// ConfirmedBalance returns the confirmed balance of the wallet, minus
 		// any outgoing transactions. ConfirmedBalance will include unconfirmed
-		// refund transactions.
+		// refund transactions. Watch-only addresses are not included.
 		ConfirmedBalance() (siacoinBalance types.Currency, siafundBalance types.Currency, siacoinClaimBalance types.Currency, err error)
 
 		// UnconfirmedBalance returns the unconfirmed balance of the wallet.
 		// Outgoing funds and incoming funds are reported separately. Refund
 		// outputs are included, meaning that sending a single coin to
-		// someone could result in 'outgoing: 12, incoming: 11'. Siafunds are
-		// not considered in the unconfirmed balance.
+		// someone could result in 'outgoing: 12, incoming: 11'. Siafunds and
+		// watch-only addresses are not considered in the unconfirmed balance.
 		UnconfirmedBalance() (outgoingSiacoins types.Currency, incomingSiacoins types.Currency, err error)
 
+		// Events returns the events of the wallet's event stream that
+		// occurred after the event with the provided ID. If there are no such
+		// events, the call blocks until an event occurs or the context is
+		// done.
+		Events(ctx context.Context, since uint64) ([]WalletEvent, error)
+
 		// Height returns the wallet's internal processed consensus height
 		Height() (types.BlockHeight, error)
 
@@ -484,6 +645,16 @@ type (
 		// rebuild its transaction history.
 		RemoveWatchAddresses(addrs []types.UnlockHash, unused bool) error
 
+		// Rescan rescans the blockchain for transactions of the wallet's
+		// addresses in the background. A non-zero gap limit sets the minimum
+		// number of unused addresses of the primary seed which the wallet
+		// looks for.
+		Rescan(gapLimit uint64) error
+
+		// RescanProgress reports the progress of the current or last rescan
+		// of the blockchain.
+		RescanProgress() (WalletRescanProgress, error)
+
 		// Rescanning reports whether the wallet is currently rescanning the
 		// blockchain.
 		Rescanning() (bool, error)
@@ -494,6 +665,23 @@ type (
 		// SetSettings sets the Wallet's settings.
 		SetSettings(WalletSettings) error
 
+		// SpendingLimits returns the wallet's spending limits and the
+		// siacoins it sent within the last day.
+		SpendingLimits() (WalletSpendingLimits, types.Currency, error)
+
+		// SetSpendingLimits sets the wallet's spending limits.
+		SetSpendingLimits(WalletSpendingLimits) error
+
+		// PendingSends returns the sends which are waiting for approval.
+		PendingSends() ([]PendingSend, error)
+
+		// ApprovePendingSend sends the siacoins of a pending send. The send
+		// still needs to be within the daily spending limit.
+		ApprovePendingSend(id string) ([]types.Transaction, error)
+
+		// RejectPendingSend removes a send from the pending sends.
+		RejectPendingSend(id string) error
+
 		// StartTransaction is a convenience method that calls
 		// RegisterTransaction(types.Transaction{}, nil)
 		StartTransaction() (TransactionBuilder, error)
@@ -507,14 +695,41 @@ type (
 		// SendSiacoinsFeeIncluded sends siacoins with fees included.
 		SendSiacoinsFeeIncluded(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error)
 
+		// SendSiacoinsWithStrategy sends siacoins like SendSiacoins, or like
+		// SendSiacoinsFeeIncluded if feeIncluded is set, but funds the
+		// transaction with the provided selection strategy.
+		SendSiacoinsWithStrategy(amount types.Currency, dest types.UnlockHash, feeIncluded bool, strategy SelectionStrategy) ([]types.Transaction, error)
+
 		SiacoinSenderMulti
 
+		// SendSiacoinsMultiWithStrategy sends siacoins like
+		// SendSiacoinsMulti but funds the transaction with the provided
+		// selection strategy.
+		SendSiacoinsMultiWithStrategy(outputs []types.SiacoinOutput, strategy SelectionStrategy) ([]types.Transaction, error)
+
 		// SendSiafunds is a tool for sending siafunds from the wallet to an
 		// address. Sending money usually results in multiple transactions. The
 		// transactions are automatically given to the transaction pool, and
 		// are also returned to the caller.
 		SendSiafunds(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error)
 
+		// BumpFee replaces the unconfirmed transaction set of one of the
+		// wallet's transactions with a set paying the given fee per byte. The
+		// higher fee is paid from a change output of the set, so the ids of
+		// the replacements differ from the original transactions.
+		BumpFee(txid types.TransactionID, feePerByte types.Currency) ([]types.Transaction, error)
+
+		// StuckTransactions returns the transaction sets broadcast by the
+		// wallet which weren't confirmed within a few blocks, were evicted
+		// from the transaction pool or were double spent.
+		StuckTransactions() ([]BroadcastTransactionSet, error)
+
+		// ChildPaysForParent speeds up the confirmation of a transaction set
+		// broadcast by the wallet. A child transaction spending a change
+		// output of the set is added, which raises the fee of the whole set
+		// to the given fee per byte. The extended set is returned.
+		ChildPaysForParent(txid types.TransactionID, feePerByte types.Currency) ([]types.Transaction, error)
+
 		// DustThreshold returns the quantity per byte below which a Currency is
 		// considered to be Dust.
 		DustThreshold() (types.Currency, error)
@@ -522,29 +737,140 @@ type (
 		// UnspentOutputs returns the unspent outputs tracked by the wallet.
 		UnspentOutputs() ([]UnspentOutput, error)
 
+		// MarkOutputsDoNotSpend marks or unmarks the provided siacoin and
+		// siafund outputs as do-not-spend. Marked outputs are never used to
+		// fund a transaction.
+		MarkOutputsDoNotSpend(ids []types.OutputID, doNotSpend bool) error
+
 		// UnlockConditions returns the UnlockConditions for the specified
 		// address, if they are known to the wallet.
 		UnlockConditions(addr types.UnlockHash) (types.UnlockConditions, error)
 
+		// CreateMultisigAddress stores the unlock conditions of an address
+		// which can be spent with the signatures of 'required' of the public
+		// keys and starts watching the address.
+		CreateMultisigAddress(required uint64, pks []types.SiaPublicKey, unused bool) (types.UnlockConditions, error)
+
+		// MultisigTransaction creates an unsigned transaction which sends the
+		// outputs from a multisig address.
+		MultisigTransaction(addr types.UnlockHash, outputs []types.SiacoinOutput) (types.Transaction, error)
+
+		// SignMultisigTransaction adds the signatures of the wallet's keys to
+		// the inputs of a multisig transaction.
+		SignMultisigTransaction(txn types.Transaction) (types.Transaction, error)
+
+		// CreateColdTransaction creates an unsigned transaction which sends
+		// the outputs from the watch-only addresses of the wallet. The change
+		// is sent to the change address.
+		CreateColdTransaction(outputs []types.SiacoinOutput, change types.UnlockHash) (ColdTransaction, error)
+
+		// BroadcastColdTransaction broadcasts a cold transaction which was
+		// signed offline.
+		BroadcastColdTransaction(txn types.Transaction) error
+
+		// AddressBook returns the named addresses of the wallet's address
+		// book.
+		AddressBook() ([]AddressBookEntry, error)
+
+		// SetAddressBookEntry adds a named address to the address book or
+		// changes the address of an existing name.
+		SetAddressBookEntry(entry AddressBookEntry) error
+
+		// RemoveAddressBookEntry removes a named address from the address
+		// book.
+		RemoveAddressBookEntry(name string) error
+
+		// SetTransactionLabel sets the label of a transaction. An empty label
+		// removes the label.
+		SetTransactionLabel(txid types.TransactionID, label string) error
+
+		// TransactionLabels returns the labels of the wallet's transactions.
+		TransactionLabels() (map[types.TransactionID]string, error)
+
 		// WatchAddresses returns the set of addresses that the wallet is
 		// currently watching.
 		WatchAddresses() ([]types.UnlockHash, error)
+
+		// WatchBalances returns the balances of the watch-only addresses. The
+		// watch-only balances are not included in the wallet's balance.
+		WatchBalances() ([]WatchAddressBalance, error)
+
+		// WatchTransactions returns the confirmed and unconfirmed transactions
+		// related to the watch-only addresses.
+		WatchTransactions() (confirmed, unconfirmed []ProcessedTransaction, err error)
 	}
 
 	// WalletSettings control the behavior of the Wallet.
 	WalletSettings struct {
 		NoDefrag bool `json:"nodefrag"`
 	}
+
+	// WalletRescanProgress reports the progress of a rescan of the
+	// blockchain. The gap limit is zero if the wallet uses the default
+	// lookahead. Lookahead is the number of addresses of the primary seed
+	// which are searched for beyond the addresses in use.
+	WalletRescanProgress struct {
+		Rescanning    bool              `json:"rescanning"`
+		ScannedHeight types.BlockHeight `json:"scannedheight"`
+		TargetHeight  types.BlockHeight `json:"targetheight"`
+		GapLimit      uint64            `json:"gaplimit"`
+		Lookahead     uint64            `json:"lookahead"`
+		Error         string            `json:"error,omitempty"`
+	}
+
+	// WalletEventType is the type of an event in the wallet's event stream.
+	WalletEventType string
+
+	// WalletEvent is an event in the wallet's event stream. Events are
+	// numbered in the order they occur, starting at 1 every time the wallet
+	// starts. Only the fields related to the event's type are set.
+	WalletEvent struct {
+		ID   uint64          `json:"id"`
+		Type WalletEventType `json:"type"`
+		Time time.Time       `json:"time"`
+
+		// Balance events.
+		ConfirmedSiacoinBalance     types.Currency `json:"confirmedsiacoinbalance"`
+		UnconfirmedOutgoingSiacoins types.Currency `json:"unconfirmedoutgoingsiacoins"`
+		UnconfirmedIncomingSiacoins types.Currency `json:"unconfirmedincomingsiacoins"`
+		SiafundBalance              types.Currency `json:"siafundbalance"`
+		SiacoinClaimBalance         types.Currency `json:"siacoinclaimbalance"`
+
+		// Transaction events.
+		Transaction *ProcessedTransaction `json:"transaction,omitempty"`
+	}
 )
 
+// Error implements the error interface.
+func (e PendingSendError) Error() string {
+	return fmt.Sprintf("send exceeds the approval threshold and is pending approval with id %v", e.ID)
+}
+
+// ParseSelectionStrategy parses a selection strategy. An empty string results
+// in the default strategy.
+func ParseSelectionStrategy(s string) (SelectionStrategy, error) {
+	switch strategy := SelectionStrategy(s); strategy {
+	case "":
+		return SelectMinimizeInputs, nil
+	case SelectMinimizeInputs, SelectMinimizeChange, SelectConsolidate:
+		return strategy, nil
+	default:
+		return "", ErrUnknownSelectionStrategy
+	}
+}
+
 // CalculateWalletTransactionID is a helper function for determining the id of
 // a wallet transaction.
 func CalculateWalletTransactionID(tid types.TransactionID, oid types.OutputID) WalletTransactionID {
 	return WalletTransactionID(crypto.HashAll(tid, oid))
 }
 
-// SeedToString converts a wallet seed to a human friendly string.
+// SeedToString converts a wallet seed to a human friendly string. The
+// BIP39Dictionary encodes the seed as a BIP39 mnemonic.
 func SeedToString(seed Seed, did mnemonics.DictionaryID) (string, error) {
+	if did == BIP39Dictionary {
+		return SeedToBIP39(seed), nil
+	}
 	fullChecksum := crypto.HashObject(seed)
 	checksumSeed := append(seed[:], fullChecksum[:SeedChecksumSize]...)
 	phrase, err := mnemonics.ToPhrase(checksumSeed, did)
@@ -554,7 +880,8 @@ func SeedToString(seed Seed, did mnemonics.DictionaryID) (string, error) {
 	return phrase.String(), nil
 }
 
-// StringToSeed converts a string to a wallet seed.
+// StringToSeed converts a string to a wallet seed. The BIP39Dictionary
+// decodes the seed from a BIP39 mnemonic.
 func StringToSeed(str string, did mnemonics.DictionaryID) (Seed, error) {
 	// Ensure the string is all lowercase letters and spaces
 	for _, char := range str {
@@ -566,6 +893,10 @@ func StringToSeed(str string, did mnemonics.DictionaryID) (Seed, error) {
 		}
 	}
 
+	if did == BIP39Dictionary {
+		return BIP39ToSeed(str)
+	}
+
 	// Decode the string into the checksummed byte slice.
 	checksumSeedBytes, err := mnemonics.FromString(str, did)
 	if err != nil {
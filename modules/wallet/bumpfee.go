@@ -0,0 +1,250 @@
+package wallet
+
+import (
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// errBumpFeeForeignInputs is returned if a transaction set spends outputs
+	// which the wallet can't sign, so it can't be replaced.
+	errBumpFeeForeignInputs = errors.New("transaction set spends outputs which the wallet can't sign")
+
+	// errBumpFeeLowFee is returned if the new fee isn't higher than the
+	// current fee of a transaction set.
+	errBumpFeeLowFee = errors.New("fee must be higher than the current fee of the transaction set")
+
+	// errBumpFeeNoChange is returned if a transaction set has no change
+	// output which is large enough to pay the higher fee.
+	errBumpFeeNoChange = errors.New("transaction set has no change output which can pay the higher fee")
+
+	// errBumpFeeNotInPool is returned if the transaction of the wallet isn't
+	// in the transaction pool.
+	errBumpFeeNotInPool = errors.New("transaction is not in the transaction pool")
+
+	// errBumpFeeNotFound is returned if the wallet has no unconfirmed
+	// transaction with the given id.
+	errBumpFeeNotFound = errors.New("transaction is not an unconfirmed transaction of the wallet")
+
+	// errBumpFeeUnsupported is returned if a transaction set contains file
+	// contracts, revisions or storage proofs, which can't be replaced.
+	errBumpFeeUnsupported = errors.New("only transaction sets sending siacoins and siafunds can be replaced")
+)
+
+// BumpFee replaces the unconfirmed transaction set of one of the wallet's
+// transactions with a set paying the given fee per byte, so that it confirms
+// faster. The higher fee is paid from the largest change output of the set,
+// which means that the ids of the transactions change. The transaction pool
+// replaces the original set with the new one, which is returned.
+func (w *Wallet) BumpFee(txid types.TransactionID, feePerByte types.Currency) ([]types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.spendMu.Lock()
+	defer w.spendMu.Unlock()
+
+	// Find the transaction set in the transaction pool.
+	var txn types.Transaction
+	var found bool
+	w.mu.RLock()
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		if upt.TransactionID == txid {
+			txn, found = upt.Transaction, true
+			break
+		}
+	}
+	w.mu.RUnlock()
+	if !found {
+		return nil, errBumpFeeNotFound
+	}
+	var oid crypto.Hash
+	if len(txn.SiacoinInputs) > 0 {
+		oid = crypto.Hash(txn.SiacoinInputs[0].ParentID)
+	} else {
+		oid = crypto.Hash(txn.SiacoinOutputID(0))
+	}
+	set := w.tpool.TransactionSet(oid)
+	found = false
+	for _, t := range set {
+		found = found || t.ID() == txid
+	}
+	if !found {
+		return nil, errBumpFeeNotInPool
+	}
+
+	w.mu.Lock()
+	newSet, err := w.bumpFee(set, feePerByte)
+	w.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	// The transaction pool notifies the wallet, so the lock can't be held.
+	if err := w.tpool.AcceptTransactionSet(newSet); err != nil {
+		w.log.Println("Attempt to bump the fee has failed - transaction pool rejected the replacement:", err)
+		return nil, errors.AddContext(err, "unable to get replacement transaction set accepted")
+	}
+	w.managedTrackBroadcast(newSet)
+
+	// Move the labels to the replacements.
+	w.mu.Lock()
+	for i := range set {
+		label, err := dbGetTransactionLabel(w.dbTx, set[i].ID())
+		if err != nil {
+			continue
+		}
+		if err := dbPutTransactionLabel(w.dbTx, newSet[i].ID(), label); err != nil {
+			w.log.Println("WARN: failed to label replacement transaction:", err)
+		}
+	}
+	w.mu.Unlock()
+
+	w.log.Println("Replaced the transaction set of", txid, "with a set paying", feePerByte.HumanString(), "per byte, IDs:")
+	for _, t := range newSet {
+		w.log.Println("\t", t.ID())
+	}
+	return newSet, nil
+}
+
+// bumpFee creates a replacement for the transaction set which pays the given
+// fee per byte. The caller must hold the lock.
+func (w *Wallet) bumpFee(set []types.Transaction, feePerByte types.Currency) ([]types.Transaction, error) {
+	if !w.unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every transaction needs to be a transfer which the wallet can sign.
+	spent := make(map[types.SiacoinOutputID]struct{})
+	var fees types.Currency
+	for _, txn := range set {
+		if len(txn.FileContracts) > 0 || len(txn.FileContractRevisions) > 0 || len(txn.StorageProofs) > 0 {
+			return nil, errBumpFeeUnsupported
+		}
+		for _, sci := range txn.SiacoinInputs {
+			if _, exists := w.keys[sci.UnlockConditions.UnlockHash()]; !exists {
+				return nil, errBumpFeeForeignInputs
+			}
+			spent[sci.ParentID] = struct{}{}
+		}
+		for _, sfi := range txn.SiafundInputs {
+			if _, exists := w.keys[sfi.UnlockConditions.UnlockHash()]; !exists {
+				return nil, errBumpFeeForeignInputs
+			}
+		}
+		for _, fee := range txn.MinerFees {
+			fees = fees.Add(fee)
+		}
+	}
+
+	// Use the largest output of the wallet which isn't spent within the set
+	// as the change output.
+	changeTxn, changeOutput := -1, -1
+	for i, txn := range set {
+		for j, sco := range txn.SiacoinOutputs {
+			if _, exists := w.keys[sco.UnlockHash]; !exists {
+				continue
+			}
+			if _, exists := spent[txn.SiacoinOutputID(uint64(j))]; exists {
+				continue
+			}
+			if changeTxn == -1 || sco.Value.Cmp(set[changeTxn].SiacoinOutputs[changeOutput].Value) > 0 {
+				changeTxn, changeOutput = i, j
+			}
+		}
+	}
+	if changeTxn == -1 {
+		return nil, errBumpFeeNoChange
+	}
+
+	required := feePerByte.Mul64(uint64(len(encoding.Marshal(set))))
+	if required.Cmp(fees) <= 0 {
+		return nil, errBumpFeeLowFee
+	}
+	// The size of the set can grow slightly with the fee, so the fee is
+	// increased until it covers the size of the replacement.
+	for {
+		extra := required.Sub(fees)
+		if set[changeTxn].SiacoinOutputs[changeOutput].Value.Cmp(extra) <= 0 {
+			return nil, errBumpFeeNoChange
+		}
+		newSet, err := w.replaceChange(set, changeTxn, changeOutput, extra, height)
+		if err != nil {
+			return nil, err
+		}
+		required = feePerByte.Mul64(uint64(len(encoding.Marshal(newSet))))
+		if fees.Add(extra).Cmp(required) >= 0 {
+			return newSet, nil
+		}
+	}
+}
+
+// replaceChange returns a copy of the transaction set which moves the extra
+// fee from the change output to the miner fees. The ids of the changed
+// transactions are updated in the inputs of their children, and all changed
+// transactions are signed again. The caller must hold the lock.
+func (w *Wallet) replaceChange(set []types.Transaction, changeTxn, changeOutput int, extra types.Currency, height types.BlockHeight) ([]types.Transaction, error) {
+	newSet := make([]types.Transaction, len(set))
+	newIDs := make(map[crypto.Hash]crypto.Hash)
+	for i, old := range set {
+		txn := old
+		txn.SiacoinInputs = append([]types.SiacoinInput(nil), old.SiacoinInputs...)
+		txn.SiacoinOutputs = append([]types.SiacoinOutput(nil), old.SiacoinOutputs...)
+		txn.SiafundInputs = append([]types.SiafundInput(nil), old.SiafundInputs...)
+		txn.MinerFees = append([]types.Currency(nil), old.MinerFees...)
+		txn.TransactionSignatures = append([]types.TransactionSignature(nil), old.TransactionSignatures...)
+
+		changed := i == changeTxn
+		if changed {
+			txn.SiacoinOutputs[changeOutput].Value = txn.SiacoinOutputs[changeOutput].Value.Sub(extra)
+			if len(txn.MinerFees) > 0 {
+				txn.MinerFees[0] = txn.MinerFees[0].Add(extra)
+			} else {
+				txn.MinerFees = append(txn.MinerFees, extra)
+			}
+		}
+		for j, sci := range txn.SiacoinInputs {
+			if id, exists := newIDs[crypto.Hash(sci.ParentID)]; exists {
+				txn.SiacoinInputs[j].ParentID = types.SiacoinOutputID(id)
+				changed = true
+			}
+		}
+		for j, sfi := range txn.SiafundInputs {
+			if id, exists := newIDs[crypto.Hash(sfi.ParentID)]; exists {
+				txn.SiafundInputs[j].ParentID = types.SiafundOutputID(id)
+				changed = true
+			}
+		}
+		if !changed {
+			newSet[i] = txn
+			continue
+		}
+
+		// Sign the transaction again and record the new ids of its outputs.
+		var toSign []crypto.Hash
+		for j, sig := range txn.TransactionSignatures {
+			if id, exists := newIDs[sig.ParentID]; exists {
+				txn.TransactionSignatures[j].ParentID = id
+			}
+			toSign = append(toSign, txn.TransactionSignatures[j].ParentID)
+		}
+		if err := signTransaction(&txn, w.keys, toSign, height); err != nil {
+			return nil, errors.AddContext(err, "unable to sign replacement transaction")
+		}
+		for j := range old.SiacoinOutputs {
+			newIDs[crypto.Hash(old.SiacoinOutputID(uint64(j)))] = crypto.Hash(txn.SiacoinOutputID(uint64(j)))
+		}
+		for j := range old.SiafundOutputs {
+			newIDs[crypto.Hash(old.SiafundOutputID(uint64(j)))] = crypto.Hash(txn.SiafundOutputID(uint64(j)))
+		}
+		newSet[i] = txn
+	}
+	return newSet, nil
+}
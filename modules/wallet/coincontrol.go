@@ -0,0 +1,133 @@
+package wallet
+
+import (
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// errDoNotSpend indicates an output was marked as do-not-spend by the
+	// user.
+	errDoNotSpend = errors.New("output is marked as do-not-spend")
+
+	// errUnknownOutput is returned when marking an output which isn't tracked
+	// by the wallet.
+	errUnknownOutput = errors.New("output is not tracked by the wallet")
+
+	// errUnspendableOutput indicates the wallet doesn't hold the keys of an
+	// output, e.g. because it belongs to a watch-only address.
+	errUnspendableOutput = errors.New("wallet doesn't hold the keys of the output")
+)
+
+// selectLargestFirst selects the largest outputs until they cover the amount.
+func selectLargestFirst(so sortedOutputs, amount types.Currency) (selected []int, fund types.Currency) {
+	for i := 0; i < len(so.ids) && fund.Cmp(amount) < 0; i++ {
+		selected = append(selected, i)
+		fund = fund.Add(so.outputs[i].Value)
+	}
+	return selected, fund
+}
+
+// selectSmallestFirst selects the smallest outputs until they cover the
+// amount.
+func selectSmallestFirst(so sortedOutputs, amount types.Currency) (selected []int, fund types.Currency) {
+	for i := len(so.ids) - 1; i >= 0 && fund.Cmp(amount) < 0; i-- {
+		selected = append(selected, i)
+		fund = fund.Add(so.outputs[i].Value)
+	}
+	return selected, fund
+}
+
+// selectMinimizeChange selects the smallest output which covers the remaining
+// amount. If no output covers it, the largest output is selected and the
+// search is repeated for the new remaining amount.
+func selectMinimizeChange(so sortedOutputs, amount types.Currency) (selected []int, fund types.Currency) {
+	used := make([]bool, len(so.ids))
+	for fund.Cmp(amount) < 0 {
+		remaining := amount.Sub(fund)
+		next := -1
+		for i := len(so.ids) - 1; i >= 0; i-- {
+			if !used[i] && so.outputs[i].Value.Cmp(remaining) >= 0 {
+				next = i
+				break
+			}
+		}
+		for i := 0; i < len(so.ids) && next == -1; i++ {
+			if !used[i] {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+		used[next] = true
+		selected = append(selected, next)
+		fund = fund.Add(so.outputs[next].Value)
+	}
+	return selected, fund
+}
+
+// selectOutputs selects the outputs which fund 'amount' according to the
+// strategy. The outputs need to be sorted from largest to smallest. It returns
+// the indices of the selected outputs and their total value, which is less
+// than the amount if the outputs are insufficient.
+func selectOutputs(so sortedOutputs, amount types.Currency, strategy modules.SelectionStrategy) ([]int, types.Currency) {
+	switch strategy {
+	case modules.SelectMinimizeChange:
+		return selectMinimizeChange(so, amount)
+	case modules.SelectConsolidate:
+		selected, fund := selectSmallestFirst(so, amount)
+		if fund.Cmp(amount) >= 0 && len(selected) <= maxConsolidateInputs {
+			return selected, fund
+		}
+	}
+	return selectLargestFirst(so, amount)
+}
+
+// MarkOutputsDoNotSpend marks or unmarks the provided siacoin and siafund
+// outputs as do-not-spend. Marked outputs are never used to fund a
+// transaction, which includes defragging the wallet.
+func (w *Wallet) MarkOutputsDoNotSpend(ids []types.OutputID, doNotSpend bool) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !doNotSpend {
+		for _, id := range ids {
+			if err := dbDeleteDoNotSpend(w.dbTx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Only outputs tracked by the wallet can be marked.
+	unconfirmed := make(map[types.OutputID]struct{})
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, output := range upt.Outputs {
+			if output.WalletAddress {
+				unconfirmed[output.ID] = struct{}{}
+			}
+		}
+	}
+	for _, id := range ids {
+		_, pending := unconfirmed[id]
+		key := encoding.Marshal(id)
+		confirmed := w.dbTx.Bucket(bucketSiacoinOutputs).Get(key) != nil || w.dbTx.Bucket(bucketSiafundOutputs).Get(key) != nil
+		if !pending && !confirmed {
+			return errors.AddContext(errUnknownOutput, id.String())
+		}
+	}
+	for _, id := range ids {
+		if err := dbPutDoNotSpend(w.dbTx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package wallet
+
+import (
+	"reflect"
+	"testing"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestSelectOutputs is a unit test for the output selection strategies.
+func TestSelectOutputs(t *testing.T) {
+	t.Parallel()
+
+	// The outputs are sorted from largest to smallest.
+	var so sortedOutputs
+	for _, value := range []uint64{100, 50, 30, 20, 5, 1} {
+		so.ids = append(so.ids, types.SiacoinOutputID{byte(value)})
+		so.outputs = append(so.outputs, types.SiacoinOutput{Value: types.NewCurrency64(value)})
+	}
+
+	tests := []struct {
+		strategy modules.SelectionStrategy
+		amount   uint64
+		selected []int
+		fund     uint64
+	}{
+		// The default strategy spends the largest outputs.
+		{modules.SelectMinimizeInputs, 25, []int{0}, 100},
+		{modules.SelectMinimizeInputs, 120, []int{0, 1}, 150},
+		// Minimizing change spends the smallest output covering the
+		// remaining amount.
+		{modules.SelectMinimizeChange, 25, []int{2}, 30},
+		{modules.SelectMinimizeChange, 20, []int{3}, 20},
+		{modules.SelectMinimizeChange, 120, []int{0, 3}, 120},
+		{modules.SelectMinimizeChange, 180, []int{0, 1, 2}, 180},
+		// Consolidating spends the smallest outputs.
+		{modules.SelectConsolidate, 25, []int{5, 4, 3}, 26},
+		// Insufficient outputs are all selected.
+		{modules.SelectMinimizeInputs, 1000, []int{0, 1, 2, 3, 4, 5}, 206},
+		{modules.SelectMinimizeChange, 1000, []int{0, 1, 2, 3, 4, 5}, 206},
+	}
+	for _, test := range tests {
+		selected, fund := selectOutputs(so, types.NewCurrency64(test.amount), test.strategy)
+		if !reflect.DeepEqual(selected, test.selected) || !fund.Equals64(test.fund) {
+			t.Errorf("%v of %v: expected %v (%v) but got %v (%v)", test.strategy, test.amount, test.selected, test.fund, selected, fund)
+		}
+	}
+
+	// Consolidating falls back to the largest outputs if too many small
+	// outputs would be needed.
+	so = sortedOutputs{}
+	for i := 0; i < maxConsolidateInputs+10; i++ {
+		so.ids = append(so.ids, types.SiacoinOutputID{byte(i)})
+		so.outputs = append(so.outputs, types.SiacoinOutput{Value: types.NewCurrency64(1)})
+	}
+	selected, _ := selectOutputs(so, types.NewCurrency64(maxConsolidateInputs+1), modules.SelectConsolidate)
+	if len(selected) != maxConsolidateInputs+1 || selected[0] != 0 {
+		t.Fatal("consolidating didn't fall back to the largest outputs", selected)
+	}
+}
+
+// TestMarkOutputsDoNotSpend tests that outputs marked as do-not-spend aren't
+// used to fund transactions.
+func TestMarkOutputsDoNotSpend(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Unknown outputs can't be marked.
+	err = wt.wallet.MarkOutputsDoNotSpend([]types.OutputID{{1}}, true)
+	if err == nil {
+		t.Fatal("unknown output was marked")
+	}
+
+	// Mark all outputs of the wallet.
+	outputs, err := wt.wallet.UnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []types.OutputID
+	for _, o := range outputs {
+		if o.FundType == types.SpecifierSiacoinOutput {
+			ids = append(ids, o.ID)
+		}
+	}
+	if err := wt.wallet.MarkOutputsDoNotSpend(ids, true); err != nil {
+		t.Fatal(err)
+	}
+	outputs, err = wt.wallet.UnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range outputs {
+		if o.FundType == types.SpecifierSiacoinOutput && !o.DoNotSpend {
+			t.Fatal("output wasn't marked", o.ID)
+		}
+	}
+
+	// The wallet can't send coins anymore.
+	_, err = wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{})
+	if err == nil {
+		t.Fatal("wallet spent outputs marked as do-not-spend")
+	}
+
+	// After removing the marks the coins can be sent with any strategy.
+	if err := wt.wallet.MarkOutputsDoNotSpend(ids, false); err != nil {
+		t.Fatal(err)
+	}
+	_, err = wt.wallet.SendSiacoinsWithStrategy(types.SiacoinPrecision, types.UnlockHash{}, false, modules.SelectMinimizeChange)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,195 @@
+package wallet
+
+import (
+	"fmt"
+	"sort"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// errNoColdOutputs is returned if the wallet doesn't watch any outputs
+	// which can be spent by a cold transaction.
+	errNoColdOutputs = errors.New("wallet doesn't watch any spendable outputs of addresses with known unlock conditions")
+
+	// errNotColdTransaction is returned when broadcasting a transaction which
+	// spends outputs of addresses that aren't watched by the wallet.
+	errNotColdTransaction = errors.New("transaction spends outputs of addresses which aren't watched by the wallet")
+)
+
+// isColdUnlockConditions returns true if the unlock conditions belong to a
+// standard address of a seed which can be spent at the given height.
+func isColdUnlockConditions(uc types.UnlockConditions, height types.BlockHeight) bool {
+	return len(uc.PublicKeys) == 1 && uc.SignaturesRequired == 1 &&
+		uc.PublicKeys[0].Algorithm == types.SignatureEd25519 && uc.Timelock <= height
+}
+
+// CreateColdTransaction creates an unsigned transaction which sends the
+// outputs from the watch-only addresses of the wallet. Only the outputs of
+// addresses with known standard unlock conditions are spent. The change is
+// sent to the change address, or to the address of the largest spent output
+// if no change address is provided.
+func (w *Wallet) CreateColdTransaction(outputs []types.SiacoinOutput, change types.UnlockHash) (modules.ColdTransaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.ColdTransaction{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if len(outputs) == 0 {
+		return modules.ColdTransaction{}, errors.New("no outputs provided")
+	}
+	var total types.Currency
+	for _, sco := range outputs {
+		if sco.Value.IsZero() {
+			return modules.ColdTransaction{}, errors.New("cannot send zero siacoins")
+		}
+		total = total.Add(sco.Value)
+	}
+	_, feePerByte := w.tpool.FeeEstimation()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return modules.ColdTransaction{}, modules.ErrLockedWallet
+	}
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return modules.ColdTransaction{}, err
+	}
+
+	// Collect the watch-only outputs which aren't spent by unconfirmed
+	// transactions or marked as do-not-spend.
+	pending := make(map[types.SiacoinOutputID]struct{})
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, input := range upt.Inputs {
+			pending[types.SiacoinOutputID(input.ParentID)] = struct{}{}
+		}
+	}
+	ucs := make(map[types.UnlockHash]types.UnlockConditions)
+	var so sortedOutputs
+	dbForEachSiacoinOutput(w.dbTx, func(id types.SiacoinOutputID, sco types.SiacoinOutput) {
+		if _, spent := pending[id]; spent || !w.isWatchOnlyAddress(sco.UnlockHash) || dbGetDoNotSpend(w.dbTx, types.OutputID(id)) {
+			return
+		}
+		if _, known := ucs[sco.UnlockHash]; !known {
+			uc, err := dbGetUnlockConditions(w.dbTx, sco.UnlockHash)
+			if err != nil || !isColdUnlockConditions(uc, height) {
+				return
+			}
+			ucs[sco.UnlockHash] = uc
+		}
+		so.ids = append(so.ids, id)
+		so.outputs = append(so.outputs, sco)
+	})
+	if len(so.ids) == 0 {
+		return modules.ColdTransaction{}, errNoColdOutputs
+	}
+	sort.Sort(sort.Reverse(so))
+
+	// Add the largest outputs until they cover the outputs and the fee, which
+	// grows with the number of inputs.
+	ct := modules.ColdTransaction{
+		Transaction: types.Transaction{
+			SiacoinOutputs: append([]types.SiacoinOutput(nil), outputs...),
+		},
+		Height: height,
+	}
+	txn := &ct.Transaction
+	var fee types.Currency
+	for i := range so.ids {
+		parentID := crypto.Hash(so.ids[i])
+		txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         so.ids[i],
+			UnlockConditions: ucs[so.outputs[i].UnlockHash],
+		})
+		txn.TransactionSignatures = append(txn.TransactionSignatures, types.TransactionSignature{
+			ParentID:       parentID,
+			PublicKeyIndex: 0,
+			CoveredFields:  types.FullCoveredFields,
+		})
+		ct.ToSign = append(ct.ToSign, parentID)
+		ct.Funds = ct.Funds.Add(so.outputs[i].Value)
+		fee = feePerByte.Mul64(estimatedTransactionSize + uint64(len(txn.SiacoinInputs))*estimatedSignatureSize)
+		if ct.Funds.Cmp(total.Add(fee)) >= 0 {
+			break
+		}
+	}
+	if ct.Funds.Cmp(total.Add(fee)) < 0 {
+		return modules.ColdTransaction{}, modules.ErrLowBalance
+	}
+	txn.MinerFees = []types.Currency{fee}
+	if remaining := ct.Funds.Sub(total).Sub(fee); !remaining.IsZero() {
+		if change == (types.UnlockHash{}) {
+			change = so.outputs[0].UnlockHash
+		}
+		txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+			Value:      remaining,
+			UnlockHash: change,
+		})
+	}
+	return ct, nil
+}
+
+// BroadcastColdTransaction broadcasts a cold transaction which was signed
+// offline. The transaction needs to be fully signed and may only spend the
+// outputs of watched addresses.
+func (w *Wallet) BroadcastColdTransaction(txn types.Transaction) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.RLock()
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		w.mu.RUnlock()
+		return err
+	}
+	for _, sci := range txn.SiacoinInputs {
+		if _, watched := w.watchedAddrs[sci.UnlockConditions.UnlockHash()]; !watched {
+			w.mu.RUnlock()
+			return errNotColdTransaction
+		}
+	}
+	w.mu.RUnlock()
+
+	if missing := modules.MissingSignatures(txn); missing > 0 {
+		return fmt.Errorf("transaction still needs %v signatures", missing)
+	}
+	if err := txn.StandaloneValid(height); err != nil {
+		return errors.AddContext(err, "invalid transaction")
+	}
+	// The transaction pool informs the wallet about the transaction, so the
+	// wallet's lock must not be held.
+	if err := w.tpool.AcceptTransactionSet([]types.Transaction{txn}); err != nil {
+		return err
+	}
+	w.managedTrackBroadcast([]types.Transaction{txn})
+	return nil
+}
+
+// SeedUnlockConditions returns the unlock conditions of the first n addresses
+// of a seed. Watching their public keys allows a watch-only wallet to create
+// cold transactions for the addresses.
+func SeedUnlockConditions(seed modules.Seed, n uint64) []types.UnlockConditions {
+	ucs := make([]types.UnlockConditions, 0, n)
+	for _, sk := range generateKeys(seed, 0, n) {
+		ucs = append(ucs, sk.UnlockConditions)
+	}
+	return ucs
+}
+
+// SignColdTransaction signs a cold transaction using secret keys derived from
+// seed. It doesn't need a wallet, so it can be used on an air-gapped machine.
+func SignColdTransaction(ct modules.ColdTransaction, seed modules.Seed) (types.Transaction, error) {
+	txn := ct.Transaction
+	// Don't modify the caller's signatures.
+	txn.TransactionSignatures = append([]types.TransactionSignature(nil), txn.TransactionSignatures...)
+	if err := SignTransaction(&txn, seed, ct.ToSign, ct.Height); err != nil {
+		return types.Transaction{}, errors.AddContext(err, "unable to sign cold transaction")
+	}
+	return txn, nil
+}
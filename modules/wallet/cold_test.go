@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestSignColdTransaction checks that a cold transaction can be signed with
+// the seed of its addresses alone.
+func TestSignColdTransaction(t *testing.T) {
+	t.Parallel()
+
+	seed := modules.Seed{1, 2, 3}
+	ucs := SeedUnlockConditions(seed, 5)
+	if len(ucs) != 5 || ucs[3].UnlockHash() != generateSpendableKey(seed, 3).UnlockConditions.UnlockHash() {
+		t.Fatal("wrong unlock conditions")
+	}
+	height := types.BlockHeight(100)
+	if !isColdUnlockConditions(ucs[3], height) {
+		t.Fatal("seed address isn't a cold address")
+	}
+
+	parentID := crypto.Hash{1}
+	ct := modules.ColdTransaction{
+		Transaction: types.Transaction{
+			SiacoinInputs:  []types.SiacoinInput{{ParentID: types.SiacoinOutputID(parentID), UnlockConditions: ucs[3]}},
+			SiacoinOutputs: []types.SiacoinOutput{{Value: types.SiacoinPrecision}},
+			TransactionSignatures: []types.TransactionSignature{{
+				ParentID:      parentID,
+				CoveredFields: types.FullCoveredFields,
+			}},
+		},
+		ToSign: []crypto.Hash{parentID},
+		Height: height,
+	}
+	txn, err := SignColdTransaction(ct, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.StandaloneValid(height); err != nil {
+		t.Fatal("signed transaction is invalid", err)
+	}
+	if len(ct.Transaction.TransactionSignatures[0].Signature) != 0 {
+		t.Fatal("the cold transaction was modified")
+	}
+}
+
+// TestCreateColdTransaction tests that a watch-only wallet creates a cold
+// transaction which can be broadcast after it was signed offline.
+func TestCreateColdTransaction(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Send coins to an address of a cold seed and watch its public key.
+	seed := modules.Seed{7}
+	uc := SeedUnlockConditions(seed, 1)[0]
+	amount := types.SiacoinPrecision.Mul64(100)
+	if _, err := wt.wallet.SendSiacoins(amount, uc.UnlockHash()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.wallet.AddUnlockConditions(uc); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.wallet.AddWatchAddresses([]types.UnlockHash{uc.UnlockHash()}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Spending more than the cold address holds fails.
+	dest := types.UnlockHash{1}
+	_, err = wt.wallet.CreateColdTransaction([]types.SiacoinOutput{{Value: amount, UnlockHash: dest}}, types.UnlockHash{})
+	if err != modules.ErrLowBalance {
+		t.Fatal("expected ErrLowBalance but got", err)
+	}
+	ct, err := wt.wallet.CreateColdTransaction([]types.SiacoinOutput{{Value: amount.Div64(2), UnlockHash: dest}}, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ct.Funds.Equals(amount) || len(ct.ToSign) != 1 {
+		t.Fatal("wrong cold transaction", ct)
+	}
+	if change := ct.Transaction.SiacoinOutputs[1]; change.UnlockHash != uc.UnlockHash() {
+		t.Fatal("change wasn't returned to the cold address")
+	}
+
+	// The unsigned transaction can't be broadcast.
+	if err := wt.wallet.BroadcastColdTransaction(ct.Transaction); err == nil {
+		t.Fatal("unsigned transaction was broadcast")
+	}
+	txn, err := SignColdTransaction(ct, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.wallet.BroadcastColdTransaction(txn); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	wbs, err := wt.wallet.WatchBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wbs) != 1 || wbs[0].ConfirmedSiacoinBalance.Cmp(amount.Div64(2)) >= 0 {
+		t.Fatal("cold address wasn't spent from", wbs)
+	}
+}
@@ -15,6 +15,11 @@ const (
 	// defragThreshold is the number of outputs a wallet is allowed before it is
 	// defragmented.
 	defragThreshold = 50
+
+	// maxConsolidateInputs is the maximum number of outputs the consolidate
+	// selection strategy spends to fund a transaction. If more outputs would
+	// be needed, the largest outputs are used instead.
+	maxConsolidateInputs = defragBatchSize
 )
 
 var (
@@ -35,6 +40,15 @@ var (
 		Testnet:  uint64(1000),
 		Testing:  uint64(10),
 	}).(uint64)
+
+	// maxGapLimit is the largest gap limit that can be set for a rescan. Every
+	// address of the lookahead is kept in memory.
+	maxGapLimit = build.Select(build.Var{
+		Dev:      uint64(100e3),
+		Standard: uint64(1e6),
+		Testnet:  uint64(1e6),
+		Testing:  uint64(1e3),
+	}).(uint64)
 )
 
 func init() {
@@ -46,7 +60,12 @@ func init() {
 }
 
 // maxLookahead returns the size of the lookahead for a given seed progress
-// which usually is the current primarySeedProgress
-func maxLookahead(start uint64) uint64 {
-	return start + lookaheadRescanThreshold + lookaheadBuffer + start/10
+// which usually is the current primarySeedProgress. A gapLimit larger than
+// the default size extends the lookahead to gapLimit keys.
+func maxLookahead(start, gapLimit uint64) uint64 {
+	lookahead := start + lookaheadRescanThreshold + lookaheadBuffer + start/10
+	if gapLimit > lookahead {
+		return gapLimit
+	}
+	return lookahead
 }
@@ -43,6 +43,22 @@ var (
 	// is used to track UnlockConditions manually stored by the user,
 	// typically with an offline wallet.
 	bucketUnlockConditions = []byte("bucketUnlockConditions")
+	// bucketDoNotSpend maps an OutputID to a bool indicating that the user
+	// marked the output as do-not-spend. The wallet doesn't use these outputs
+	// to fund transactions.
+	bucketDoNotSpend = []byte("bucketDoNotSpend")
+	// bucketAddressBook maps the name of an address book entry to its
+	// UnlockHash.
+	bucketAddressBook = []byte("bucketAddressBook")
+	// bucketTransactionLabels maps a TransactionID to the label the user
+	// gave the transaction.
+	bucketTransactionLabels = []byte("bucketTransactionLabels")
+	// bucketPendingSends maps the ID of a send which exceeded the approval
+	// threshold to the pending send.
+	bucketPendingSends = []byte("bucketPendingSends")
+	// bucketBroadcasts maps the ID of a transaction set broadcast by the
+	// wallet to the set, which is rebroadcast until it is confirmed.
+	bucketBroadcasts = []byte("bucketBroadcasts")
 	// bucketWallet contains various fields needed by the wallet, such as its
 	// UID, EncryptionVerification, and PrimarySeedFile.
 	bucketWallet = []byte("bucketWallet")
@@ -55,6 +71,11 @@ var (
 		bucketSiafundOutputs,
 		bucketSpentOutputs,
 		bucketUnlockConditions,
+		bucketDoNotSpend,
+		bucketAddressBook,
+		bucketTransactionLabels,
+		bucketPendingSends,
+		bucketBroadcasts,
 		bucketWallet,
 	}
 
@@ -65,9 +86,12 @@ var (
 	keyConsensusChange        = []byte("keyConsensusChange")
 	keyConsensusHeight        = []byte("keyConsensusHeight")
 	keyEncryptionVerification = []byte("keyEncryptionVerification")
+	keyGapLimit               = []byte("keyGapLimit")
 	keyPrimarySeedFile        = []byte("keyPrimarySeedFile")
 	keyPrimarySeedProgress    = []byte("keyPrimarySeedProgress")
 	keySiafundPool            = []byte("keySiafundPool")
+	keySpendingHistory        = []byte("keySpendingHistory")
+	keySpendingLimits         = []byte("keySpendingLimits")
 	keySpendableKeyFiles      = []byte("keySpendableKeyFiles")
 	keySalt                   = []byte("keyUID")
 	keyWalletPassword         = []byte("keyWalletPassword")
@@ -232,6 +256,69 @@ func dbDeleteSpentOutput(tx *bolt.Tx, id types.OutputID) error {
 	return dbDelete(tx.Bucket(bucketSpentOutputs), id)
 }
 
+func dbPutDoNotSpend(tx *bolt.Tx, id types.OutputID) error {
+	return dbPut(tx.Bucket(bucketDoNotSpend), id, true)
+}
+func dbGetDoNotSpend(tx *bolt.Tx, id types.OutputID) bool {
+	var doNotSpend bool
+	return dbGet(tx.Bucket(bucketDoNotSpend), id, &doNotSpend) == nil && doNotSpend
+}
+func dbDeleteDoNotSpend(tx *bolt.Tx, id types.OutputID) error {
+	return dbDelete(tx.Bucket(bucketDoNotSpend), id)
+}
+
+func dbPutAddressBookEntry(tx *bolt.Tx, name string, addr types.UnlockHash) error {
+	return dbPut(tx.Bucket(bucketAddressBook), name, addr)
+}
+func dbDeleteAddressBookEntry(tx *bolt.Tx, name string) error {
+	return dbDelete(tx.Bucket(bucketAddressBook), name)
+}
+func dbForEachAddressBookEntry(tx *bolt.Tx, fn func(string, types.UnlockHash)) error {
+	return dbForEach(tx.Bucket(bucketAddressBook), fn)
+}
+
+func dbPutTransactionLabel(tx *bolt.Tx, txid types.TransactionID, label string) error {
+	return dbPut(tx.Bucket(bucketTransactionLabels), txid, label)
+}
+func dbGetTransactionLabel(tx *bolt.Tx, txid types.TransactionID) (label string, err error) {
+	err = dbGet(tx.Bucket(bucketTransactionLabels), txid, &label)
+	return
+}
+func dbDeleteTransactionLabel(tx *bolt.Tx, txid types.TransactionID) error {
+	return dbDelete(tx.Bucket(bucketTransactionLabels), txid)
+}
+func dbForEachTransactionLabel(tx *bolt.Tx, fn func(types.TransactionID, string)) error {
+	return dbForEach(tx.Bucket(bucketTransactionLabels), fn)
+}
+
+func dbPutPendingSend(tx *bolt.Tx, ps modules.PendingSend) error {
+	return dbPut(tx.Bucket(bucketPendingSends), ps.ID, ps)
+}
+func dbGetPendingSend(tx *bolt.Tx, id string) (ps modules.PendingSend, err error) {
+	err = dbGet(tx.Bucket(bucketPendingSends), id, &ps)
+	return
+}
+func dbDeletePendingSend(tx *bolt.Tx, id string) error {
+	return dbDelete(tx.Bucket(bucketPendingSends), id)
+}
+func dbForEachPendingSend(tx *bolt.Tx, fn func(string, modules.PendingSend)) error {
+	return dbForEach(tx.Bucket(bucketPendingSends), fn)
+}
+
+func dbPutBroadcast(tx *bolt.Tx, bts modules.BroadcastTransactionSet) error {
+	return dbPut(tx.Bucket(bucketBroadcasts), bts.ID, bts)
+}
+func dbGetBroadcast(tx *bolt.Tx, id types.TransactionID) (bts modules.BroadcastTransactionSet, err error) {
+	err = dbGet(tx.Bucket(bucketBroadcasts), id, &bts)
+	return
+}
+func dbDeleteBroadcast(tx *bolt.Tx, id types.TransactionID) error {
+	return dbDelete(tx.Bucket(bucketBroadcasts), id)
+}
+func dbForEachBroadcast(tx *bolt.Tx, fn func(types.TransactionID, modules.BroadcastTransactionSet)) error {
+	return dbForEach(tx.Bucket(bucketBroadcasts), fn)
+}
+
 func dbPutAddrTransactions(tx *bolt.Tx, addr types.UnlockHash, txns []uint64) error {
 	return dbPut(tx.Bucket(bucketAddrTransactions), addr, txns)
 }
@@ -474,6 +561,48 @@ func dbPutConsensusHeight(tx *bolt.Tx, height types.BlockHeight) error {
 	return tx.Bucket(bucketWallet).Put(keyConsensusHeight, encoding.Marshal(height))
 }
 
+// dbGetGapLimit returns the gap limit of the primary seed's lookahead. Wallets
+// which never set a gap limit use the default lookahead, indicated by zero.
+func dbGetGapLimit(tx *bolt.Tx) (gapLimit uint64, err error) {
+	if b := tx.Bucket(bucketWallet).Get(keyGapLimit); b != nil {
+		err = encoding.Unmarshal(b, &gapLimit)
+	}
+	return
+}
+
+// dbPutGapLimit stores the gap limit of the primary seed's lookahead.
+func dbPutGapLimit(tx *bolt.Tx, gapLimit uint64) error {
+	return tx.Bucket(bucketWallet).Put(keyGapLimit, encoding.Marshal(gapLimit))
+}
+
+// dbGetSpendingLimits returns the wallet's spending limits.
+func dbGetSpendingLimits(tx *bolt.Tx) (limits modules.WalletSpendingLimits, err error) {
+	if b := tx.Bucket(bucketWallet).Get(keySpendingLimits); b != nil {
+		err = encoding.Unmarshal(b, &limits)
+	}
+	return
+}
+
+// dbPutSpendingLimits stores the wallet's spending limits.
+func dbPutSpendingLimits(tx *bolt.Tx, limits modules.WalletSpendingLimits) error {
+	return tx.Bucket(bucketWallet).Put(keySpendingLimits, encoding.Marshal(limits))
+}
+
+// dbGetSpendingHistory returns the records of the siacoins sent within the
+// spending window.
+func dbGetSpendingHistory(tx *bolt.Tx) (history []spendingRecord, err error) {
+	if b := tx.Bucket(bucketWallet).Get(keySpendingHistory); b != nil {
+		err = encoding.Unmarshal(b, &history)
+	}
+	return
+}
+
+// dbPutSpendingHistory stores the records of the siacoins sent within the
+// spending window.
+func dbPutSpendingHistory(tx *bolt.Tx, history []spendingRecord) error {
+	return tx.Bucket(bucketWallet).Put(keySpendingHistory, encoding.Marshal(history))
+}
+
 // dbGetSiafundPool returns the value of the siafund pool.
 func dbGetSiafundPool(tx *bolt.Tx) (pool types.Currency, err error) {
 	err = encoding.Unmarshal(tx.Bucket(bucketWallet).Get(keySiafundPool), &pool)
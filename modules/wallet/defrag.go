@@ -196,6 +196,7 @@ func (w *Wallet) threadedDefragWallet() {
 		w.log.Println("WARN: defrag transaction was rejected:", err)
 		return
 	}
+	w.managedTrackBroadcast(txnSet)
 	w.log.Println("Submitting a transaction set to defragment the wallet's outputs, IDs:")
 	for _, txn := range txnSet {
 		w.log.Println("Wallet defrag: \t", txn.ID())
@@ -195,6 +195,7 @@ func (w *Wallet) managedBlockingUnlock(masterKey crypto.CipherKey) (modules.Cons
 	var auxiliarySeedFiles []seedFile
 	var unseededKeyFiles []spendableKeyFile
 	var watchedAddrs []types.UnlockHash
+	var gapLimit uint64
 	err := func() error {
 		w.mu.Lock()
 		defer w.mu.Unlock()
@@ -237,6 +238,12 @@ func (w *Wallet) managedBlockingUnlock(masterKey crypto.CipherKey) (modules.Cons
 			return err
 		}
 
+		// gapLimit
+		gapLimit, err = dbGetGapLimit(w.dbTx)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	}()
 	if err != nil {
@@ -255,6 +262,7 @@ func (w *Wallet) managedBlockingUnlock(masterKey crypto.CipherKey) (modules.Cons
 		}
 		w.integrateSeed(primarySeed, primarySeedProgress)
 		w.primarySeed = primarySeed
+		w.gapLimit = gapLimit
 		w.regenerateLookahead(primarySeedProgress)
 
 		// auxiliarySeedFiles
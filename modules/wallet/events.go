@@ -0,0 +1,223 @@
+package wallet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/threadgroup"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+)
+
+const (
+	// maxWalletEvents is the number of events the wallet keeps for clients of
+	// the event stream. Older events are dropped.
+	maxWalletEvents = 1000
+
+	// walletEventIDReservation is the number of event IDs which are reserved
+	// at once. The end of the reservation is persisted before any of its IDs
+	// are used, so that IDs are never reused after a restart or crash
+	// without persisting every event.
+	walletEventIDReservation = 1000
+
+	// walletEventsFile is the name of the file the end of the current event
+	// ID reservation is persisted in.
+	walletEventsFile = "events.json"
+)
+
+var (
+	// walletEventsMetadata is the metadata of the wallet events persist file.
+	walletEventsMetadata = persist.Metadata{
+		Header:  "Wallet Events",
+		Version: "1.5.5",
+	}
+)
+
+type (
+	// walletEvents is the wallet's event stream. Clients long-poll for events
+	// that occurred after the last event they have seen.
+	walletEvents struct {
+		events []modules.WalletEvent
+		nextID uint64
+
+		// reservedID is the end of the persisted reservation of event IDs.
+		// IDs below it can be used without persisting anything.
+		reservedID uint64
+
+		// lastBalance is the most recent balance event. A balance event is
+		// only added if the balance differs from it.
+		lastBalance *modules.WalletEvent
+
+		// newEvents is closed and replaced whenever an event is added.
+		newEvents chan struct{}
+
+		// balanceMu serializes the balance updates, so that the balance
+		// events are added in the order the balances were computed.
+		balanceMu sync.Mutex
+		mu        sync.Mutex
+
+		staticLog         *persist.Logger
+		staticPersistPath string
+	}
+
+	// walletEventsPersist is the persisted state of the event stream.
+	walletEventsPersist struct {
+		// ReservedID is the end of the last reservation of event IDs. The
+		// first event after a restart gets this ID.
+		ReservedID uint64 `json:"reservedid"`
+	}
+)
+
+// newWalletEvents creates a new, empty event stream. Its IDs continue after
+// the IDs reserved before the last shutdown.
+func newWalletEvents(persistDir string, log *persist.Logger) (*walletEvents, error) {
+	we := &walletEvents{
+		nextID:    1,
+		newEvents: make(chan struct{}),
+
+		staticLog:         log,
+		staticPersistPath: filepath.Join(persistDir, walletEventsFile),
+	}
+	var wep walletEventsPersist
+	err := persist.LoadJSON(walletEventsMetadata, &wep, we.staticPersistPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.AddContext(err, "failed to load wallet events")
+	}
+	if wep.ReservedID > we.nextID {
+		we.nextID = wep.ReservedID
+	}
+	we.reservedID = we.nextID
+	return we, nil
+}
+
+// add adds an event to the stream and wakes up the waiting clients.
+func (we *walletEvents) add(e modules.WalletEvent) {
+	if we.nextID >= we.reservedID {
+		reservedID := we.nextID + walletEventIDReservation
+		err := persist.SaveJSON(walletEventsMetadata, walletEventsPersist{ReservedID: reservedID}, we.staticPersistPath)
+		if err != nil {
+			// The event is added anyway since clients are waiting for it.
+			// The reservation is retried with the next event.
+			we.staticLog.Println("WARN: failed to reserve wallet event IDs:", err)
+		} else {
+			we.reservedID = reservedID
+		}
+	}
+	e.ID = we.nextID
+	e.Time = time.Now()
+	we.nextID++
+	we.events = append(we.events, e)
+	if len(we.events) > maxWalletEvents {
+		we.events = append([]modules.WalletEvent{}, we.events[len(we.events)-maxWalletEvents:]...)
+	}
+	close(we.newEvents)
+	we.newEvents = make(chan struct{})
+}
+
+// callAddTransaction adds a transaction event to the stream.
+func (we *walletEvents) callAddTransaction(t modules.WalletEventType, pt modules.ProcessedTransaction) {
+	we.mu.Lock()
+	defer we.mu.Unlock()
+	we.add(modules.WalletEvent{
+		Type:        t,
+		Transaction: &pt,
+	})
+}
+
+// callUpdateBalance adds a balance event to the stream if the balance changed
+// since the last balance event.
+func (we *walletEvents) callUpdateBalance(balance modules.WalletEvent) {
+	we.mu.Lock()
+	defer we.mu.Unlock()
+	balance.Type = modules.WalletEventBalance
+	if last := we.lastBalance; last != nil &&
+		last.ConfirmedSiacoinBalance.Equals(balance.ConfirmedSiacoinBalance) &&
+		last.UnconfirmedOutgoingSiacoins.Equals(balance.UnconfirmedOutgoingSiacoins) &&
+		last.UnconfirmedIncomingSiacoins.Equals(balance.UnconfirmedIncomingSiacoins) &&
+		last.SiafundBalance.Equals(balance.SiafundBalance) &&
+		last.SiacoinClaimBalance.Equals(balance.SiacoinClaimBalance) {
+		return
+	}
+	we.lastBalance = &balance
+	we.add(balance)
+}
+
+// callEvents returns the events that occurred after the event with the
+// provided ID. IDs aren't reused across restarts, so an ID from before the
+// last restart returns all events. An ID that was never used, e.g. because it
+// belongs to a different wallet, also returns all events. The returned
+// channel is closed when the next event is added.
+func (we *walletEvents) callEvents(since uint64) ([]modules.WalletEvent, <-chan struct{}) {
+	we.mu.Lock()
+	defer we.mu.Unlock()
+	if since >= we.nextID {
+		since = 0
+	}
+	var events []modules.WalletEvent
+	for _, e := range we.events {
+		if e.ID > since {
+			events = append(events, e)
+		}
+	}
+	return events, we.newEvents
+}
+
+// threadedUpdateBalanceEvent adds a balance event to the event stream if the
+// wallet's balance changed. The balance can't be computed while holding the
+// wallet's lock, because the dust threshold is fetched from the transaction
+// pool.
+func (w *Wallet) threadedUpdateBalanceEvent() {
+	if err := w.tg.Add(); err != nil {
+		return
+	}
+	defer w.tg.Done()
+
+	w.staticEvents.balanceMu.Lock()
+	defer w.staticEvents.balanceMu.Unlock()
+	siacoins, siafunds, claims, err := w.ConfirmedBalance()
+	if err != nil {
+		w.log.Println("WARN: failed to get confirmed balance for the event stream:", err)
+		return
+	}
+	outgoing, incoming, err := w.UnconfirmedBalance()
+	if err != nil {
+		w.log.Println("WARN: failed to get unconfirmed balance for the event stream:", err)
+		return
+	}
+	w.staticEvents.callUpdateBalance(modules.WalletEvent{
+		ConfirmedSiacoinBalance:     siacoins,
+		UnconfirmedOutgoingSiacoins: outgoing,
+		UnconfirmedIncomingSiacoins: incoming,
+		SiafundBalance:              siafunds,
+		SiacoinClaimBalance:         claims,
+	})
+}
+
+// Events returns the events of the wallet's event stream that occurred after
+// the event with the provided ID. If there are no such events yet, Events
+// blocks until the next event occurs or the context is done, in which case no
+// events are returned.
+func (w *Wallet) Events(ctx context.Context, since uint64) ([]modules.WalletEvent, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+	for {
+		events, newEvents := w.staticEvents.callEvents(since)
+		if len(events) > 0 {
+			return events, nil
+		}
+		select {
+		case <-newEvents:
+		case <-ctx.Done():
+			return nil, nil
+		case <-w.tg.StopChan():
+			return nil, threadgroup.ErrStopped
+		}
+	}
+}
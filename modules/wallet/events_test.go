@@ -0,0 +1,152 @@
+package wallet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+// TestWalletEvents is a unit test for the walletEvents object.
+func TestWalletEvents(t *testing.T) {
+	t.Parallel()
+
+	dir := build.TempDir(modules.WalletDir, t.Name())
+	if err := os.MkdirAll(dir, modules.DefaultDirPerm); err != nil {
+		t.Fatal(err)
+	}
+	log, err := persist.NewFileLogger(filepath.Join(dir, logFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	we, err := newWalletEvents(dir, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, newEvents := we.callEvents(0)
+	if len(events) != 0 {
+		t.Fatal("stream should be empty")
+	}
+
+	// Adding an event wakes up waiting clients.
+	pt := modules.ProcessedTransaction{TransactionID: types.TransactionID{1}}
+	we.callAddTransaction(modules.WalletEventTransactionUnconfirmed, pt)
+	select {
+	case <-newEvents:
+	default:
+		t.Fatal("waiting clients weren't notified")
+	}
+	events, _ = we.callEvents(0)
+	if len(events) != 1 || events[0].ID != 1 || events[0].Time.IsZero() || events[0].Transaction.TransactionID != pt.TransactionID {
+		t.Fatal("wrong events", events)
+	}
+
+	// A balance event is only added if the balance changed.
+	balance := modules.WalletEvent{ConfirmedSiacoinBalance: types.SiacoinPrecision}
+	we.callUpdateBalance(balance)
+	we.callUpdateBalance(balance)
+	balance.UnconfirmedIncomingSiacoins = types.SiacoinPrecision
+	we.callUpdateBalance(balance)
+	events, _ = we.callEvents(1)
+	if len(events) != 2 || events[0].Type != modules.WalletEventBalance || !events[1].UnconfirmedIncomingSiacoins.Equals(types.SiacoinPrecision) {
+		t.Fatal("wrong balance events", events)
+	}
+
+	// IDs that were never used return all events.
+	events, _ = we.callEvents(100)
+	if len(events) != 3 {
+		t.Fatal("wrong number of events", len(events))
+	}
+
+	// Old events are dropped.
+	for i := 0; i < maxWalletEvents; i++ {
+		we.callAddTransaction(modules.WalletEventTransactionConfirmed, pt)
+	}
+	events, _ = we.callEvents(0)
+	if len(events) != maxWalletEvents || events[0].ID != 4 {
+		t.Fatal("old events weren't dropped", len(events), events[0].ID)
+	}
+	lastID := events[len(events)-1].ID
+
+	// After a restart, IDs continue after the ones used before, so the ID of
+	// the last event a client saw before the restart returns all new events.
+	we, err = newWalletEvents(dir, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	we.callAddTransaction(modules.WalletEventTransactionConfirmed, pt)
+	for _, since := range []uint64{0, 1, lastID} {
+		events, _ = we.callEvents(since)
+		if len(events) != 1 || events[0].ID <= lastID {
+			t.Fatal("wrong events after restart", since, events)
+		}
+	}
+}
+
+// TestWalletEventsTransactions tests that sending siacoins adds transaction
+// and balance events to the wallet's event stream.
+func TestWalletEventsTransactions(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Skip the events of the wallet tester's setup.
+	events, _ := wt.wallet.staticEvents.callEvents(0)
+	var since uint64
+	if len(events) > 0 {
+		since = events[len(events)-1].ID
+	}
+
+	// waitForEvent waits for an event of the provided type.
+	waitForEvent := func(typ modules.WalletEventType) modules.WalletEvent {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for {
+			events, err := wt.wallet.Events(ctx, since)
+			if err != nil {
+				t.Fatal(err)
+			} else if len(events) == 0 {
+				t.Fatal("no event of type", typ)
+			}
+			for _, e := range events {
+				since = e.ID
+				if e.Type == typ {
+					return e
+				}
+			}
+		}
+	}
+
+	if _, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{}); err != nil {
+		t.Fatal(err)
+	}
+	if e := waitForEvent(modules.WalletEventTransactionUnconfirmed); e.Transaction == nil {
+		t.Fatal("unconfirmed transaction wasn't reported")
+	}
+	if e := waitForEvent(modules.WalletEventBalance); e.UnconfirmedOutgoingSiacoins.IsZero() {
+		t.Fatal("outgoing siacoins weren't reported")
+	}
+
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if e := waitForEvent(modules.WalletEventTransactionConfirmed); e.Transaction == nil {
+		t.Fatal("confirmed transaction wasn't reported")
+	}
+}
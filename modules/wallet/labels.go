@@ -0,0 +1,152 @@
+package wallet
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// maxAddressBookNameLength is the maximum length in characters of the
+	// name of an address book entry.
+	maxAddressBookNameLength = 64
+)
+
+var (
+	// errAddressBookNameAddress is returned if the name of an address book
+	// entry is an address, which would make it ambiguous as a destination.
+	errAddressBookNameAddress = errors.New("name of an address book entry can't be an address")
+
+	// errAddressBookNameInvalid is returned if the name of an address book
+	// entry is empty or contains whitespace.
+	errAddressBookNameInvalid = errors.New("name of an address book entry must be non-empty and can't contain whitespace")
+
+	// errAddressBookNameLength is returned if the name of an address book
+	// entry is too long.
+	errAddressBookNameLength = errors.New("name of an address book entry is too long")
+
+	// errTransactionLabelLength is returned if the label of a transaction is
+	// too long.
+	errTransactionLabelLength = errors.New("transaction label is too long")
+
+	// errUnknownAddressBookEntry is returned when removing a name which isn't
+	// in the address book.
+	errUnknownAddressBookEntry = errors.New("no address book entry with that name")
+)
+
+// validateAddressBookName checks that a name can be used for an address book
+// entry.
+func validateAddressBookName(name string) error {
+	if name == "" || strings.IndexFunc(name, unicode.IsSpace) != -1 {
+		return errAddressBookNameInvalid
+	}
+	var uh types.UnlockHash
+	if uh.LoadString(name) == nil {
+		return errAddressBookNameAddress
+	}
+	if utf8.RuneCountInString(name) > maxAddressBookNameLength {
+		return errAddressBookNameLength
+	}
+	return nil
+}
+
+// AddressBook returns the named addresses of the wallet's address book sorted
+// by name.
+func (w *Wallet) AddressBook() ([]modules.AddressBookEntry, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries := []modules.AddressBookEntry{}
+	err := dbForEachAddressBookEntry(w.dbTx, func(name string, addr types.UnlockHash) {
+		entries = append(entries, modules.AddressBookEntry{
+			Name:    name,
+			Address: addr,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// SetAddressBookEntry adds a named address to the address book or changes the
+// address of an existing name.
+func (w *Wallet) SetAddressBookEntry(entry modules.AddressBookEntry) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if err := validateAddressBookName(entry.Name); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return dbPutAddressBookEntry(w.dbTx, entry.Name, entry.Address)
+}
+
+// RemoveAddressBookEntry removes a named address from the address book.
+func (w *Wallet) RemoveAddressBookEntry(name string) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var addr types.UnlockHash
+	if err := dbGet(w.dbTx.Bucket(bucketAddressBook), name, &addr); err != nil {
+		return errUnknownAddressBookEntry
+	}
+	return dbDeleteAddressBookEntry(w.dbTx, name)
+}
+
+// SetTransactionLabel sets the label of a transaction. An empty label removes
+// the label. Transactions can be labeled before the wallet knows about them,
+// e.g. to add a memo to a transaction which is about to be broadcast.
+func (w *Wallet) SetTransactionLabel(txid types.TransactionID, label string) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if utf8.RuneCountInString(label) > modules.MaxTransactionLabelLength {
+		return errTransactionLabelLength
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if label == "" {
+		return dbDeleteTransactionLabel(w.dbTx, txid)
+	}
+	return dbPutTransactionLabel(w.dbTx, txid, label)
+}
+
+// TransactionLabels returns the labels of the wallet's transactions.
+func (w *Wallet) TransactionLabels() (map[types.TransactionID]string, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	labels := make(map[types.TransactionID]string)
+	err := dbForEachTransactionLabel(w.dbTx, func(txid types.TransactionID, label string) {
+		labels[txid] = label
+	})
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
@@ -0,0 +1,106 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestValidateAddressBookName is a unit test for validateAddressBookName.
+func TestValidateAddressBookName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"alice", nil},
+		{"exchange-deposit", nil},
+		{"", errAddressBookNameInvalid},
+		{"cold storage", errAddressBookNameInvalid},
+		{"bob\t", errAddressBookNameInvalid},
+		{strings.Repeat("a", maxAddressBookNameLength), nil},
+		{strings.Repeat("a", maxAddressBookNameLength+1), errAddressBookNameLength},
+		{types.UnlockHash{}.String(), errAddressBookNameAddress},
+	}
+	for _, test := range tests {
+		err := validateAddressBookName(test.name)
+		if (err == nil) != (test.err == nil) || (err != nil && !errors.Contains(err, test.err)) {
+			t.Errorf("expected %v for %q but got %v", test.err, test.name, err)
+		}
+	}
+}
+
+// TestAddressBookAndLabels tests that the address book entries and the
+// transaction labels are persisted by the wallet.
+func TestAddressBookAndLabels(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Add two entries and change the address of the first one.
+	alice := modules.AddressBookEntry{Name: "alice", Address: types.UnlockHash{1}}
+	bob := modules.AddressBookEntry{Name: "bob", Address: types.UnlockHash{2}}
+	for _, entry := range []modules.AddressBookEntry{bob, alice} {
+		if err := wt.wallet.SetAddressBookEntry(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alice.Address = types.UnlockHash{3}
+	if err := wt.wallet.SetAddressBookEntry(alice); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := wt.wallet.AddressBook()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0] != alice || entries[1] != bob {
+		t.Fatal("wrong address book", entries)
+	}
+	if err := wt.wallet.RemoveAddressBookEntry("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.wallet.RemoveAddressBookEntry("bob"); !errors.Contains(err, errUnknownAddressBookEntry) {
+		t.Fatal("expected errUnknownAddressBookEntry but got", err)
+	}
+
+	// Label a sent transaction.
+	txns, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txid := txns[len(txns)-1].ID()
+	if err := wt.wallet.SetTransactionLabel(txid, "rent"); err != nil {
+		t.Fatal(err)
+	}
+	labels, err := wt.wallet.TransactionLabels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 1 || labels[txid] != "rent" {
+		t.Fatal("wrong labels", labels)
+	}
+	if err := wt.wallet.SetTransactionLabel(txid, ""); err != nil {
+		t.Fatal(err)
+	}
+	labels, err = wt.wallet.TransactionLabels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 0 {
+		t.Fatal("label wasn't removed", labels)
+	}
+}
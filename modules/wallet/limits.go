@@ -0,0 +1,304 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// spendingWindow is the period over which the siacoins sent by the wallet
+	// are summed up to enforce the daily spending limit.
+	spendingWindow = 24 * time.Hour
+
+	// approvalWebhookTimeout is the timeout for notifying the approval webhook
+	// about a pending send.
+	approvalWebhookTimeout = 30 * time.Second
+)
+
+var (
+	// errDailyLimitExceeded is returned if a send would exceed the daily
+	// spending limit of the wallet.
+	errDailyLimitExceeded = errors.New("send exceeds the wallet's daily spending limit")
+
+	// errInvalidApprovalWebhook is returned if the approval webhook isn't an
+	// http or https URL.
+	errInvalidApprovalWebhook = errors.New("approval webhook must be an http or https URL")
+
+	// errUnknownPendingSend is returned when approving or rejecting a pending
+	// send which doesn't exist.
+	errUnknownPendingSend = errors.New("no pending send with that id")
+)
+
+// spendingRecord records the siacoins sent by a send which was subject to the
+// spending limits.
+type spendingRecord struct {
+	Timestamp types.Timestamp
+	Amount    types.Currency
+}
+
+// recentSpending returns the records which are within the spending window
+// ending at now, together with the sum of their amounts.
+func recentSpending(history []spendingRecord, now types.Timestamp) ([]spendingRecord, types.Currency) {
+	windowStart := now - types.Timestamp(spendingWindow.Seconds())
+	var recent []spendingRecord
+	var spent types.Currency
+	for _, r := range history {
+		if r.Timestamp > windowStart {
+			recent = append(recent, r)
+			spent = spent.Add(r.Amount)
+		}
+	}
+	return recent, spent
+}
+
+// sendTotal returns the siacoins sent to the outputs of a send.
+func sendTotal(ps modules.PendingSend) types.Currency {
+	var total types.Currency
+	for _, sco := range ps.Outputs {
+		total = total.Add(sco.Value)
+	}
+	return total
+}
+
+// managedCheckSpendingLimits checks a send against the wallet's spending
+// limits. A send above the approval threshold which wasn't approved yet is
+// added to the pending sends and a modules.PendingSendError is returned.
+func (w *Wallet) managedCheckSpendingLimits(ps modules.PendingSend, approved bool) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	limits, err := dbGetSpendingLimits(w.dbTx)
+	if err != nil {
+		return err
+	}
+	history, err := dbGetSpendingHistory(w.dbTx)
+	if err != nil {
+		return err
+	}
+	now := types.CurrentTimestamp()
+	_, spent := recentSpending(history, now)
+	total := sendTotal(ps)
+	if !limits.DailyLimit.IsZero() && spent.Add(total).Cmp(limits.DailyLimit) > 0 {
+		return errDailyLimitExceeded
+	}
+	if approved || limits.ApprovalThreshold.IsZero() || total.Cmp(limits.ApprovalThreshold) <= 0 {
+		return nil
+	}
+
+	// Hold the send until it is approved.
+	ps.ID = hex.EncodeToString(fastrand.Bytes(16))
+	ps.Created = now
+	if err := dbPutPendingSend(w.dbTx, ps); err != nil {
+		return err
+	}
+	if err := w.syncDB(); err != nil {
+		return err
+	}
+	w.log.Println("Send of", total.HumanString(), "exceeds the approval threshold and is pending approval with id", ps.ID)
+	if limits.ApprovalWebhook != "" {
+		go w.threadedNotifyApprovalWebhook(limits.ApprovalWebhook, ps)
+	}
+	return modules.PendingSendError{ID: ps.ID}
+}
+
+// managedRecordSpending adds the siacoins of a send to the spending history.
+func (w *Wallet) managedRecordSpending(amount types.Currency) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	history, err := dbGetSpendingHistory(w.dbTx)
+	if err != nil {
+		return err
+	}
+	now := types.CurrentTimestamp()
+	history, _ = recentSpending(history, now)
+	history = append(history, spendingRecord{
+		Timestamp: now,
+		Amount:    amount,
+	})
+	if err := dbPutSpendingHistory(w.dbTx, history); err != nil {
+		return err
+	}
+	return w.syncDB()
+}
+
+// managedSendWithLimits sends the siacoins of a send if it is within the
+// spending limits. The caller must hold the spendMu.
+func (w *Wallet) managedSendWithLimits(ps modules.PendingSend, approved bool) (txns []types.Transaction, err error) {
+	if err := w.managedCheckSpendingLimits(ps, approved); err != nil {
+		return nil, err
+	}
+	if ps.Multi {
+		txns, err = w.managedSendSiacoinsMulti(ps.Outputs, ps.Strategy)
+	} else if len(ps.Outputs) == 1 {
+		txns, err = w.managedSendSiacoinsWithStrategy(ps.Outputs[0].Value, ps.Outputs[0].UnlockHash, ps.FeeIncluded, ps.Strategy)
+	} else {
+		return nil, errors.New("send needs to have exactly one output")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The coins were sent, so failing to record them is only logged.
+	if err := w.managedRecordSpending(sendTotal(ps)); err != nil {
+		w.log.Println("ERROR: failed to record spending:", err)
+	}
+	return txns, nil
+}
+
+// threadedNotifyApprovalWebhook posts a pending send to the approval webhook.
+func (w *Wallet) threadedNotifyApprovalWebhook(webhook string, ps modules.PendingSend) {
+	if err := w.tg.Add(); err != nil {
+		return
+	}
+	defer w.tg.Done()
+
+	b, err := json.Marshal(ps)
+	if err != nil {
+		w.log.Println("ERROR: failed to encode pending send:", err)
+		return
+	}
+	client := http.Client{Timeout: approvalWebhookTimeout}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(b))
+	if err != nil {
+		w.log.Println("WARN: failed to notify approval webhook:", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		w.log.Println("WARN: approval webhook responded with status", resp.Status)
+	}
+}
+
+// SpendingLimits returns the wallet's spending limits and the siacoins sent
+// within the last day.
+func (w *Wallet) SpendingLimits() (modules.WalletSpendingLimits, types.Currency, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletSpendingLimits{}, types.Currency{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	limits, err := dbGetSpendingLimits(w.dbTx)
+	if err != nil {
+		return modules.WalletSpendingLimits{}, types.Currency{}, err
+	}
+	history, err := dbGetSpendingHistory(w.dbTx)
+	if err != nil {
+		return modules.WalletSpendingLimits{}, types.Currency{}, err
+	}
+	_, spent := recentSpending(history, types.CurrentTimestamp())
+	return limits, spent, nil
+}
+
+// SetSpendingLimits sets the wallet's spending limits. The approval webhook
+// needs to be empty or an http or https URL.
+func (w *Wallet) SetSpendingLimits(limits modules.WalletSpendingLimits) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if limits.ApprovalWebhook != "" {
+		u, err := url.Parse(limits.ApprovalWebhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errInvalidApprovalWebhook
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := dbPutSpendingLimits(w.dbTx, limits); err != nil {
+		return err
+	}
+	return w.syncDB()
+}
+
+// PendingSends returns the sends which are waiting for approval, sorted by the
+// time they were created.
+func (w *Wallet) PendingSends() ([]modules.PendingSend, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sends := []modules.PendingSend{}
+	err := dbForEachPendingSend(w.dbTx, func(_ string, ps modules.PendingSend) {
+		sends = append(sends, ps)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sends, func(i, j int) bool {
+		return sends[i].Created < sends[j].Created
+	})
+	return sends, nil
+}
+
+// ApprovePendingSend sends the siacoins of a pending send and removes it from
+// the pending sends. The send still needs to be within the daily spending
+// limit.
+func (w *Wallet) ApprovePendingSend(id string) ([]types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.spendMu.Lock()
+	defer w.spendMu.Unlock()
+	w.mu.Lock()
+	ps, err := dbGetPendingSend(w.dbTx, id)
+	w.mu.Unlock()
+	if errors.Contains(err, errNoKey) {
+		return nil, errUnknownPendingSend
+	} else if err != nil {
+		return nil, err
+	}
+
+	txns, err := w.managedSendWithLimits(ps, true)
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to send approved siacoins")
+	}
+	w.log.Println("Pending send", id, "was approved")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := dbDeletePendingSend(w.dbTx, id); err != nil {
+		return nil, err
+	}
+	return txns, w.syncDB()
+}
+
+// RejectPendingSend removes a send from the pending sends without sending its
+// siacoins.
+func (w *Wallet) RejectPendingSend(id string) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.spendMu.Lock()
+	defer w.spendMu.Unlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := dbGetPendingSend(w.dbTx, id); errors.Contains(err, errNoKey) {
+		return errUnknownPendingSend
+	} else if err != nil {
+		return err
+	}
+	if err := dbDeletePendingSend(w.dbTx, id); err != nil {
+		return err
+	}
+	w.log.Println("Pending send", id, "was rejected")
+	return w.syncDB()
+}
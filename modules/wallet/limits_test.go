@@ -0,0 +1,118 @@
+package wallet
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestRecentSpending is a unit test for recentSpending.
+func TestRecentSpending(t *testing.T) {
+	t.Parallel()
+
+	now := types.Timestamp(1e9)
+	day := types.Timestamp(spendingWindow.Seconds())
+	history := []spendingRecord{
+		{Timestamp: now - day - 1, Amount: types.NewCurrency64(1)},
+		{Timestamp: now - day, Amount: types.NewCurrency64(2)},
+		{Timestamp: now - day + 1, Amount: types.NewCurrency64(4)},
+		{Timestamp: now, Amount: types.NewCurrency64(8)},
+	}
+	recent, spent := recentSpending(history, now)
+	if len(recent) != 2 || !spent.Equals64(12) {
+		t.Fatal("wrong recent spending", recent, spent)
+	}
+	if recent, spent := recentSpending(nil, now); len(recent) != 0 || !spent.IsZero() {
+		t.Fatal("expected no spending", recent, spent)
+	}
+}
+
+// TestSpendingLimits tests that sends are held for approval above the
+// approval threshold and rejected above the daily limit.
+func TestSpendingLimits(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// An invalid webhook is rejected.
+	if err := wt.wallet.SetSpendingLimits(modules.WalletSpendingLimits{ApprovalWebhook: "ftp://foo"}); err != errInvalidApprovalWebhook {
+		t.Fatal("expected errInvalidApprovalWebhook but got", err)
+	}
+	limits := modules.WalletSpendingLimits{
+		DailyLimit:        types.SiacoinPrecision.Mul64(100),
+		ApprovalThreshold: types.SiacoinPrecision.Mul64(10),
+	}
+	if err := wt.wallet.SetSpendingLimits(limits); err != nil {
+		t.Fatal(err)
+	}
+
+	// A send below the threshold is sent right away.
+	if _, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{}); err != nil {
+		t.Fatal(err)
+	}
+	_, spent, err := wt.wallet.SpendingLimits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !spent.Equals(types.SiacoinPrecision) {
+		t.Fatal("wrong amount spent", spent)
+	}
+
+	// A send above the threshold is held.
+	_, err = wt.wallet.SendSiacoins(types.SiacoinPrecision.Mul64(50), types.UnlockHash{})
+	pse, ok := err.(modules.PendingSendError)
+	if !ok {
+		t.Fatal("expected PendingSendError but got", err)
+	}
+	sends, err := wt.wallet.PendingSends()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sends) != 1 || sends[0].ID != pse.ID {
+		t.Fatal("wrong pending sends", sends)
+	}
+
+	// A send above the daily limit is rejected.
+	_, err = wt.wallet.SendSiacoins(types.SiacoinPrecision.Mul64(100), types.UnlockHash{})
+	if err != errDailyLimitExceeded {
+		t.Fatal("expected errDailyLimitExceeded but got", err)
+	}
+
+	// Approving the pending send sends the coins.
+	if _, err := wt.wallet.ApprovePendingSend(pse.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.wallet.ApprovePendingSend(pse.ID); err != errUnknownPendingSend {
+		t.Fatal("expected errUnknownPendingSend but got", err)
+	}
+	_, spent, err = wt.wallet.SpendingLimits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !spent.Equals(types.SiacoinPrecision.Mul64(51)) {
+		t.Fatal("wrong amount spent", spent)
+	}
+
+	// A rejected send is removed without sending the coins.
+	_, err = wt.wallet.SendSiacoinsMulti([]types.SiacoinOutput{{Value: types.SiacoinPrecision.Mul64(20)}})
+	pse, ok = err.(modules.PendingSendError)
+	if !ok {
+		t.Fatal("expected PendingSendError but got", err)
+	}
+	if err := wt.wallet.RejectPendingSend(pse.ID); err != nil {
+		t.Fatal(err)
+	}
+	if sends, err := wt.wallet.PendingSends(); err != nil || len(sends) != 0 {
+		t.Fatal("expected no pending sends", sends, err)
+	}
+}
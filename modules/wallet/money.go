@@ -53,7 +53,7 @@ func (w *Wallet) ConfirmedBalance() (siacoinBalance types.Currency, siafundBalan
 	}
 
 	dbForEachSiacoinOutput(w.dbTx, func(_ types.SiacoinOutputID, sco types.SiacoinOutput) {
-		if sco.Value.Cmp(dustThreshold) > 0 {
+		if sco.Value.Cmp(dustThreshold) > 0 && !w.isWatchOnlyAddress(sco.UnlockHash) {
 			siacoinBalance = siacoinBalance.Add(sco.Value)
 		}
 	})
@@ -63,6 +63,9 @@ func (w *Wallet) ConfirmedBalance() (siacoinBalance types.Currency, siafundBalan
 		return
 	}
 	dbForEachSiafundOutput(w.dbTx, func(_ types.SiafundOutputID, sfo types.SiafundOutput) {
+		if w.isWatchOnlyAddress(sfo.UnlockHash) {
+			return
+		}
 		siafundBalance = siafundBalance.Add(sfo.Value)
 		if sfo.ClaimStart.Cmp(siafundPool) > 0 {
 			// Skip claims larger than the siafund pool. This should only
@@ -95,12 +98,12 @@ func (w *Wallet) UnconfirmedBalance() (outgoingSiacoins types.Currency, incoming
 
 	for _, upt := range w.unconfirmedProcessedTransactions {
 		for _, input := range upt.Inputs {
-			if input.FundType == types.SpecifierSiacoinInput && input.WalletAddress {
+			if input.FundType == types.SpecifierSiacoinInput && input.WalletAddress && !w.isWatchOnlyAddress(input.RelatedAddress) {
 				outgoingSiacoins = outgoingSiacoins.Add(input.Value)
 			}
 		}
 		for _, output := range upt.Outputs {
-			if output.FundType == types.SpecifierSiacoinOutput && output.WalletAddress && output.Value.Cmp(dustThreshold) > 0 {
+			if output.FundType == types.SpecifierSiacoinOutput && output.WalletAddress && output.Value.Cmp(dustThreshold) > 0 && !w.isWatchOnlyAddress(output.RelatedAddress) {
 				incomingSiacoins = incomingSiacoins.Add(output.Value)
 			}
 		}
@@ -112,41 +115,57 @@ func (w *Wallet) UnconfirmedBalance() (outgoingSiacoins types.Currency, incoming
 // transaction is submitted to the transaction pool and is also returned. Fees
 // are added to the amount sent.
 func (w *Wallet) SendSiacoins(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error) {
-	if err := w.tg.Add(); err != nil {
-		err = modules.ErrWalletShutdown
-		return nil, err
-	}
-	defer w.tg.Done()
-
-	_, fee := w.tpool.FeeEstimation()
-	fee = fee.Mul64(estimatedTransactionSize)
-	return w.managedSendSiacoins(amount, fee, dest)
+	return w.SendSiacoinsWithStrategy(amount, dest, false, modules.SelectMinimizeInputs)
 }
 
 // SendSiacoinsFeeIncluded creates a transaction sending 'amount' to 'dest'. The
 // transaction is submitted to the transaction pool and is also returned. Fees
 // are subtracted from the amount sent.
 func (w *Wallet) SendSiacoinsFeeIncluded(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error) {
+	return w.SendSiacoinsWithStrategy(amount, dest, true, modules.SelectMinimizeInputs)
+}
+
+// SendSiacoinsWithStrategy creates a transaction sending 'amount' to 'dest'
+// which is funded with the outputs selected by the strategy. The transaction
+// is submitted to the transaction pool and is also returned. Fees are
+// subtracted from the amount sent if feeIncluded is set and added to it
+// otherwise.
+func (w *Wallet) SendSiacoinsWithStrategy(amount types.Currency, dest types.UnlockHash, feeIncluded bool, strategy modules.SelectionStrategy) ([]types.Transaction, error) {
 	if err := w.tg.Add(); err != nil {
 		err = modules.ErrWalletShutdown
 		return nil, err
 	}
 	defer w.tg.Done()
 
+	w.spendMu.Lock()
+	defer w.spendMu.Unlock()
+	return w.managedSendWithLimits(modules.PendingSend{
+		Outputs:     []types.SiacoinOutput{{Value: amount, UnlockHash: dest}},
+		FeeIncluded: feeIncluded,
+		Strategy:    strategy,
+	}, false)
+}
+
+// managedSendSiacoinsWithStrategy creates a transaction sending 'amount' to
+// 'dest' without checking the spending limits.
+func (w *Wallet) managedSendSiacoinsWithStrategy(amount types.Currency, dest types.UnlockHash, feeIncluded bool, strategy modules.SelectionStrategy) ([]types.Transaction, error) {
 	_, fee := w.tpool.FeeEstimation()
 	fee = fee.Mul64(estimatedTransactionSize)
+	if !feeIncluded {
+		return w.managedSendSiacoins(amount, fee, dest, strategy)
+	}
 	// Don't allow sending an amount equal to the fee, as zero spending is not
 	// allowed and would error out later.
 	if amount.Cmp(fee) <= 0 {
 		w.log.Println("Attempt to send coins has failed - not enough to cover fee")
 		return nil, errors.AddContext(modules.ErrLowBalance, "not enough coins to cover fee")
 	}
-	return w.managedSendSiacoins(amount.Sub(fee), fee, dest)
+	return w.managedSendSiacoins(amount.Sub(fee), fee, dest, strategy)
 }
 
 // managedSendSiacoins creates a transaction sending 'amount' to 'dest'. The
 // transaction is submitted to the transaction pool and is also returned.
-func (w *Wallet) managedSendSiacoins(amount, fee types.Currency, dest types.UnlockHash) (txns []types.Transaction, err error) {
+func (w *Wallet) managedSendSiacoins(amount, fee types.Currency, dest types.UnlockHash, strategy modules.SelectionStrategy) (txns []types.Transaction, err error) {
 	// Check if consensus is synced
 	if !w.cs.Synced() || w.deps.Disrupt("UnsyncedConsensus") {
 		return nil, errors.New("cannot send siacoin until fully synced")
@@ -174,6 +193,7 @@ func (w *Wallet) managedSendSiacoins(amount, fee types.Currency, dest types.Unlo
 			txnBuilder.Drop()
 		}
 	}()
+	txnBuilder.SetSelectionStrategy(strategy)
 	err = txnBuilder.FundSiacoins(amount.Add(fee))
 	if err != nil {
 		w.log.Println("Attempt to send coins has failed - failed to fund transaction:", err)
@@ -194,6 +214,7 @@ func (w *Wallet) managedSendSiacoins(amount, fee types.Currency, dest types.Unlo
 		w.log.Println("Attempt to send coins has failed - transaction pool rejected transaction:", err)
 		return nil, build.ExtendErr("unable to get transaction accepted", err)
 	}
+	w.managedTrackBroadcast(txnSet)
 	w.log.Println("Submitted a siacoin transfer transaction set for value", amount.HumanString(), "with fees", fee.HumanString(), "IDs:")
 	for _, txn := range txnSet {
 		w.log.Println("\t", txn.ID())
@@ -204,12 +225,32 @@ func (w *Wallet) managedSendSiacoins(amount, fee types.Currency, dest types.Unlo
 // SendSiacoinsMulti creates a transaction that includes the specified
 // outputs. The transaction is submitted to the transaction pool and is also
 // returned.
-func (w *Wallet) SendSiacoinsMulti(outputs []types.SiacoinOutput) (txns []types.Transaction, err error) {
+func (w *Wallet) SendSiacoinsMulti(outputs []types.SiacoinOutput) ([]types.Transaction, error) {
+	return w.SendSiacoinsMultiWithStrategy(outputs, modules.SelectMinimizeInputs)
+}
+
+// SendSiacoinsMultiWithStrategy creates a transaction that includes the
+// specified outputs and is funded with the outputs selected by the strategy.
+// The transaction is submitted to the transaction pool and is also returned.
+func (w *Wallet) SendSiacoinsMultiWithStrategy(outputs []types.SiacoinOutput, strategy modules.SelectionStrategy) (txns []types.Transaction, err error) {
 	if err := w.tg.Add(); err != nil {
 		err = modules.ErrWalletShutdown
 		return nil, err
 	}
 	defer w.tg.Done()
+
+	w.spendMu.Lock()
+	defer w.spendMu.Unlock()
+	return w.managedSendWithLimits(modules.PendingSend{
+		Outputs:  outputs,
+		Multi:    true,
+		Strategy: strategy,
+	}, false)
+}
+
+// managedSendSiacoinsMulti creates a transaction that includes the specified
+// outputs without checking the spending limits.
+func (w *Wallet) managedSendSiacoinsMulti(outputs []types.SiacoinOutput, strategy modules.SelectionStrategy) (txns []types.Transaction, err error) {
 	w.log.Println("Beginning call to SendSiacoinsMulti")
 
 	// Check if consensus is synced
@@ -250,6 +291,7 @@ func (w *Wallet) SendSiacoinsMulti(outputs []types.SiacoinOutput) (txns []types.
 	for _, sco := range outputs {
 		totalCost = totalCost.Add(sco.Value)
 	}
+	txnBuilder.SetSelectionStrategy(strategy)
 	err = txnBuilder.FundSiacoins(totalCost)
 	if err != nil {
 		return nil, build.ExtendErr("unable to fund transaction", err)
@@ -273,6 +315,7 @@ func (w *Wallet) SendSiacoinsMulti(outputs []types.SiacoinOutput) (txns []types.
 		w.log.Println("Attempt to send coins has failed - transaction pool rejected transaction:", err)
 		return nil, build.ExtendErr("unable to get transaction accepted", err)
 	}
+	w.managedTrackBroadcast(txnSet)
 
 	// Log the success.
 	var outputList string
@@ -339,6 +382,7 @@ func (w *Wallet) SendSiafunds(amount types.Currency, dest types.UnlockHash) (txn
 	if err != nil {
 		return nil, err
 	}
+	w.managedTrackBroadcast(txnSet)
 	w.log.Println("Submitted a siafund transfer transaction set for value", amount.HumanString(), "with fees", tpoolFee.HumanString(), "IDs:")
 	for _, txn := range txnSet {
 		w.log.Println("\t", txn.ID())
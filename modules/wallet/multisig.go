@@ -0,0 +1,234 @@
+package wallet
+
+import (
+	"sort"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// estimatedSignatureSize is the estimated size of a transaction signature
+	// which is added to the size of a multisig transaction for every required
+	// signature of an input.
+	estimatedSignatureSize = 150
+)
+
+var (
+	// errNoMultisigKeys is returned if the wallet can't add a signature to a
+	// multisig transaction.
+	errNoMultisigKeys = errors.New("wallet doesn't hold any keys that can add a signature to the transaction")
+
+	// errUnknownMultisigAddress is returned if the unlock conditions of a
+	// multisig address are unknown to the wallet.
+	errUnknownMultisigAddress = errors.New("no record of the unlock conditions of the address")
+)
+
+// signMultisigInputs adds the signatures of the provided keys to the siacoin
+// and siafund inputs of the transaction until each input has the required
+// number of signatures. The keys are indexed by the string representation of
+// their public keys. It returns the number of added signatures.
+func signMultisigInputs(txn *types.Transaction, keys map[string]crypto.SecretKey, height types.BlockHeight) int {
+	type input struct {
+		parentID crypto.Hash
+		uc       types.UnlockConditions
+	}
+	var inputs []input
+	for _, sci := range txn.SiacoinInputs {
+		inputs = append(inputs, input{crypto.Hash(sci.ParentID), sci.UnlockConditions})
+	}
+	for _, sfi := range txn.SiafundInputs {
+		inputs = append(inputs, input{crypto.Hash(sfi.ParentID), sfi.UnlockConditions})
+	}
+
+	var added int
+	for _, in := range inputs {
+		used := make(map[uint64]struct{})
+		for _, sig := range txn.TransactionSignatures {
+			if sig.ParentID == in.parentID {
+				used[sig.PublicKeyIndex] = struct{}{}
+			}
+		}
+		for i, pk := range in.uc.PublicKeys {
+			if uint64(len(used)) >= in.uc.SignaturesRequired {
+				break
+			}
+			sk, exists := keys[pk.String()]
+			if _, signed := used[uint64(i)]; signed || !exists {
+				continue
+			}
+			txn.TransactionSignatures = append(txn.TransactionSignatures, types.TransactionSignature{
+				ParentID:       in.parentID,
+				PublicKeyIndex: uint64(i),
+				CoveredFields:  types.FullCoveredFields,
+			})
+			sigIndex := len(txn.TransactionSignatures) - 1
+			encodedSig := crypto.SignHash(txn.SigHash(sigIndex, height), sk)
+			txn.TransactionSignatures[sigIndex].Signature = encodedSig[:]
+			used[uint64(i)] = struct{}{}
+			added++
+		}
+	}
+	return added
+}
+
+// CreateMultisigAddress stores the unlock conditions of an address which can
+// be spent with the signatures of 'required' of the provided public keys and
+// starts watching the address. If the address has never appeared in the
+// blockchain, the unused flag may be set to true to skip the rescan.
+func (w *Wallet) CreateMultisigAddress(required uint64, pks []types.SiaPublicKey, unused bool) (types.UnlockConditions, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.UnlockConditions{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	uc, err := modules.MultisigUnlockConditions(required, pks)
+	if err != nil {
+		return types.UnlockConditions{}, err
+	}
+	if err := w.AddUnlockConditions(uc); err != nil {
+		return types.UnlockConditions{}, errors.AddContext(err, "unable to store unlock conditions")
+	}
+	if err := w.AddWatchAddresses([]types.UnlockHash{uc.UnlockHash()}, unused); err != nil {
+		return types.UnlockConditions{}, errors.AddContext(err, "unable to watch address")
+	}
+	return uc, nil
+}
+
+// MultisigTransaction creates an unsigned transaction which sends the outputs
+// from a multisig address. The change is returned to the multisig address.
+// The transaction needs to be signed by the required number of parties before
+// it can be broadcast.
+func (w *Wallet) MultisigTransaction(addr types.UnlockHash, outputs []types.SiacoinOutput) (types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.Transaction{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if len(outputs) == 0 {
+		return types.Transaction{}, errors.New("no outputs provided")
+	}
+	var total types.Currency
+	for _, sco := range outputs {
+		if sco.Value.IsZero() {
+			return types.Transaction{}, errors.New("cannot send zero siacoins")
+		}
+		total = total.Add(sco.Value)
+	}
+	_, feePerByte := w.tpool.FeeEstimation()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return types.Transaction{}, modules.ErrLockedWallet
+	}
+	uc, err := dbGetUnlockConditions(w.dbTx, addr)
+	if err != nil {
+		return types.Transaction{}, errUnknownMultisigAddress
+	}
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	if height < uc.Timelock {
+		return types.Transaction{}, errOutputTimelock
+	}
+
+	// Collect the outputs of the address which aren't spent by unconfirmed
+	// transactions.
+	pending := make(map[types.SiacoinOutputID]struct{})
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, input := range upt.Inputs {
+			pending[types.SiacoinOutputID(input.ParentID)] = struct{}{}
+		}
+	}
+	var so sortedOutputs
+	dbForEachSiacoinOutput(w.dbTx, func(id types.SiacoinOutputID, sco types.SiacoinOutput) {
+		if _, spent := pending[id]; !spent && sco.UnlockHash == addr {
+			so.ids = append(so.ids, id)
+			so.outputs = append(so.outputs, sco)
+		}
+	})
+	sort.Sort(sort.Reverse(so))
+
+	// Add the largest outputs until they cover the outputs and the fee, which
+	// grows with the number of inputs.
+	txn := types.Transaction{
+		SiacoinOutputs: append([]types.SiacoinOutput(nil), outputs...),
+	}
+	var funds, fee types.Currency
+	for i := range so.ids {
+		txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         so.ids[i],
+			UnlockConditions: uc,
+		})
+		funds = funds.Add(so.outputs[i].Value)
+		size := estimatedTransactionSize + uint64(len(txn.SiacoinInputs))*uc.SignaturesRequired*estimatedSignatureSize
+		fee = feePerByte.Mul64(size)
+		if funds.Cmp(total.Add(fee)) >= 0 {
+			break
+		}
+	}
+	if funds.Cmp(total.Add(fee)) < 0 {
+		return types.Transaction{}, modules.ErrLowBalance
+	}
+	txn.MinerFees = []types.Currency{fee}
+	if change := funds.Sub(total).Sub(fee); !change.IsZero() {
+		txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+			Value:      change,
+			UnlockHash: addr,
+		})
+	}
+	return txn, nil
+}
+
+// SignMultisigTransaction adds the signatures of the wallet's keys to the
+// inputs of a multisig transaction which still need signatures. The signatures
+// of other parties can be merged with modules.MergeTransactionSignatures.
+func (w *Wallet) SignMultisigTransaction(txn types.Transaction) (types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.Transaction{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return types.Transaction{}, modules.ErrLockedWallet
+	}
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+
+	// Find the wallet's secret keys for the public keys of the inputs.
+	needed := make(map[string]struct{})
+	for _, sci := range txn.SiacoinInputs {
+		for _, pk := range sci.UnlockConditions.PublicKeys {
+			needed[pk.String()] = struct{}{}
+		}
+	}
+	for _, sfi := range txn.SiafundInputs {
+		for _, pk := range sfi.UnlockConditions.PublicKeys {
+			needed[pk.String()] = struct{}{}
+		}
+	}
+	keys := make(map[string]crypto.SecretKey)
+	for _, sk := range w.keys {
+		for _, key := range sk.SecretKeys {
+			pk := types.Ed25519PublicKey(key.PublicKey()).String()
+			if _, exists := needed[pk]; exists {
+				keys[pk] = key
+			}
+		}
+	}
+
+	// Don't modify the caller's signatures.
+	txn.TransactionSignatures = append([]types.TransactionSignature(nil), txn.TransactionSignatures...)
+	if signMultisigInputs(&txn, keys, height) == 0 {
+		return types.Transaction{}, errNoMultisigKeys
+	}
+	return txn, nil
+}
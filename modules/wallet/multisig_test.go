@@ -0,0 +1,59 @@
+package wallet
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestSignMultisigInputs checks that the parties of a multisig address can
+// sign a transaction independently and that the merged transaction is valid.
+func TestSignMultisigInputs(t *testing.T) {
+	t.Parallel()
+
+	var pks []types.SiaPublicKey
+	keys := make([]map[string]crypto.SecretKey, 3)
+	for i := range keys {
+		sk, pk := crypto.GenerateKeyPair()
+		pks = append(pks, types.Ed25519PublicKey(pk))
+		keys[i] = map[string]crypto.SecretKey{pks[i].String(): sk}
+	}
+	uc, err := modules.MultisigUnlockConditions(2, pks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn := types.Transaction{
+		SiacoinInputs:  []types.SiacoinInput{{ParentID: types.SiacoinOutputID{1}, UnlockConditions: uc}},
+		SiacoinOutputs: []types.SiacoinOutput{{Value: types.SiacoinPrecision}},
+	}
+	height := types.BlockHeight(100)
+
+	// A key that isn't part of the address can't sign.
+	sk, _ := crypto.GenerateKeyPair()
+	if signMultisigInputs(&txn, map[string]crypto.SecretKey{"other": sk}, height) != 0 {
+		t.Fatal("unrelated key signed the transaction")
+	}
+
+	// The first and the third party sign their own copies.
+	first, third := txn, txn
+	if signMultisigInputs(&first, keys[0], height) != 1 || signMultisigInputs(&third, keys[2], height) != 1 {
+		t.Fatal("parties couldn't sign")
+	}
+	if err := first.StandaloneValid(height); err == nil {
+		t.Fatal("transaction with a single signature is valid")
+	}
+	merged, err := modules.MergeTransactionSignatures([]types.Transaction{first, third})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := merged.StandaloneValid(height); err != nil {
+		t.Fatal("merged transaction is invalid", err)
+	}
+
+	// Once the transaction is fully signed, no more signatures are added.
+	if signMultisigInputs(&merged, keys[1], height) != 0 {
+		t.Fatal("signature was added to a fully signed transaction")
+	}
+}
@@ -97,10 +97,11 @@ outer:
 		}
 	}
 
-	// mark the watch-only outputs
+	// mark the watch-only and do-not-spend outputs
 	for i, o := range outputs {
 		_, ok := w.watchedAddrs[o.UnlockHash]
 		outputs[i].IsWatchOnly = ok
+		outputs[i].DoNotSpend = dbGetDoNotSpend(w.dbTx, o.ID)
 	}
 
 	return outputs, nil
@@ -118,6 +118,12 @@ func (w *Wallet) initPersist() error {
 		return w.log.Close()
 	})
 
+	// Load the event stream.
+	w.staticEvents, err = newWalletEvents(w.persistDir, w.log)
+	if err != nil {
+		return err
+	}
+
 	// Open the database.
 	dbFilename := filepath.Join(w.persistDir, dbFile)
 	compatFilename := filepath.Join(w.persistDir, compatFile)
@@ -178,6 +184,9 @@ func (w *Wallet) initPersist() error {
 
 	// spawn a goroutine to commit the db transaction at regular intervals
 	go w.threadedDBUpdate()
+
+	// spawn a goroutine to rebroadcast the wallet's unconfirmed transactions
+	go w.threadedRebroadcast()
 	return nil
 }
 
@@ -0,0 +1,440 @@
+package wallet
+
+import (
+	"sort"
+	"time"
+
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// maxBroadcastAge is the number of blocks after which the wallet stops
+	// rebroadcasting a transaction set. It matches the RespendTimeout, after
+	// which the wallet may spend the inputs of the set again.
+	maxBroadcastAge = RespendTimeout
+
+	// stuckBroadcastAge is the number of blocks after which an unconfirmed
+	// transaction set of the wallet is considered stuck.
+	stuckBroadcastAge = 6
+)
+
+var (
+	// rebroadcastInterval is the interval at which the wallet rebroadcasts
+	// its unconfirmed transaction sets.
+	rebroadcastInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: 10 * time.Minute,
+		Testnet:  10 * time.Minute,
+		Testing:  5 * time.Second,
+	}).(time.Duration)
+)
+
+var (
+	// errDoubleSpentBroadcast is returned when bumping the fee of a
+	// transaction set which was double spent.
+	errDoubleSpentBroadcast = errors.New("transaction set was double spent and can't be confirmed")
+
+	// errUnknownBroadcast is returned if the wallet doesn't track an
+	// unconfirmed transaction set containing the transaction.
+	errUnknownBroadcast = errors.New("transaction is not part of an unconfirmed transaction set broadcast by the wallet")
+)
+
+// externalInputs returns the ids of the outputs spent by a transaction set
+// which aren't created within the set.
+func externalInputs(set []types.Transaction) (scoids []types.SiacoinOutputID, sfoids []types.SiafundOutputID) {
+	created := make(map[types.OutputID]struct{})
+	for _, txn := range set {
+		for i := range txn.SiacoinOutputs {
+			created[types.OutputID(txn.SiacoinOutputID(uint64(i)))] = struct{}{}
+		}
+		for i := range txn.SiafundOutputs {
+			created[types.OutputID(txn.SiafundOutputID(uint64(i)))] = struct{}{}
+		}
+	}
+	for _, txn := range set {
+		for _, sci := range txn.SiacoinInputs {
+			if _, exists := created[types.OutputID(sci.ParentID)]; !exists {
+				scoids = append(scoids, sci.ParentID)
+			}
+		}
+		for _, sfi := range txn.SiafundInputs {
+			if _, exists := created[types.OutputID(sfi.ParentID)]; !exists {
+				sfoids = append(sfoids, sfi.ParentID)
+			}
+		}
+	}
+	return
+}
+
+// spendsSameOutputs returns true if two transaction sets spend any of the
+// same outputs.
+func spendsSameOutputs(a, b []types.Transaction) bool {
+	spent := make(map[types.OutputID]struct{})
+	scoids, sfoids := externalInputs(a)
+	for _, id := range scoids {
+		spent[types.OutputID(id)] = struct{}{}
+	}
+	for _, id := range sfoids {
+		spent[types.OutputID(id)] = struct{}{}
+	}
+	scoids, sfoids = externalInputs(b)
+	for _, id := range scoids {
+		if _, exists := spent[types.OutputID(id)]; exists {
+			return true
+		}
+	}
+	for _, id := range sfoids {
+		if _, exists := spent[types.OutputID(id)]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// isStuck returns true if a broadcast transaction set is considered stuck at
+// the given height.
+func isStuck(bts modules.BroadcastTransactionSet, height types.BlockHeight) bool {
+	return bts.Status != modules.BroadcastStatusPending || bts.Height+stuckBroadcastAge <= height
+}
+
+// managedTrackBroadcast starts tracking a transaction set which the wallet
+// submitted to the transaction pool, so that it is rebroadcast until it is
+// confirmed. Tracked sets which spend the same outputs, such as the sets
+// replaced by a fee bump, are no longer tracked.
+func (w *Wallet) managedTrackBroadcast(set []types.Transaction) {
+	if len(set) == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.trackBroadcast(set); err != nil {
+		w.log.Println("WARN: failed to track broadcast transaction set:", err)
+	}
+}
+
+// trackBroadcast starts tracking a transaction set. The caller must hold the
+// lock.
+func (w *Wallet) trackBroadcast(set []types.Transaction) error {
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return err
+	}
+	var replaced []types.TransactionID
+	err = dbForEachBroadcast(w.dbTx, func(id types.TransactionID, bts modules.BroadcastTransactionSet) {
+		if spendsSameOutputs(bts.Transactions, set) {
+			replaced = append(replaced, id)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range replaced {
+		if err := dbDeleteBroadcast(w.dbTx, id); err != nil {
+			return err
+		}
+	}
+	return dbPutBroadcast(w.dbTx, modules.BroadcastTransactionSet{
+		ID:            set[len(set)-1].ID(),
+		Transactions:  set,
+		Status:        modules.BroadcastStatusPending,
+		Height:        height,
+		Broadcasts:    1,
+		LastBroadcast: types.CurrentTimestamp(),
+	})
+}
+
+// threadedRebroadcast periodically rebroadcasts the unconfirmed transaction
+// sets of the wallet.
+func (w *Wallet) threadedRebroadcast() {
+	if err := w.tg.Add(); err != nil {
+		return
+	}
+	defer w.tg.Done()
+
+	for {
+		select {
+		case <-time.After(rebroadcastInterval):
+		case <-w.tg.StopChan():
+			return
+		}
+		if !w.cs.Synced() {
+			continue
+		}
+		w.managedRebroadcast()
+	}
+}
+
+// managedRebroadcast rebroadcasts the tracked transaction sets which are in
+// the transaction pool and tries to add the ones which aren't back to the
+// pool. Sets which can't be added are either evicted or double spent.
+// Confirmed sets and sets older than maxBroadcastAge are no longer tracked.
+func (w *Wallet) managedRebroadcast() {
+	// Load the tracked sets.
+	w.mu.Lock()
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		w.mu.Unlock()
+		w.log.Println("WARN: failed to get consensus height for rebroadcast:", err)
+		return
+	}
+	var sets []modules.BroadcastTransactionSet
+	var done []types.TransactionID
+	err = dbForEachBroadcast(w.dbTx, func(id types.TransactionID, bts modules.BroadcastTransactionSet) {
+		if _, err := dbGetTransactionIndex(w.dbTx, id); err == nil {
+			done = append(done, id)
+		} else if bts.Height+maxBroadcastAge <= height {
+			w.log.Println("Giving up on rebroadcasting transaction set", id, "which wasn't confirmed within", maxBroadcastAge, "blocks")
+			done = append(done, id)
+		} else {
+			sets = append(sets, bts)
+		}
+	})
+	for _, id := range done {
+		err = errors.Compose(err, dbDeleteBroadcast(w.dbTx, id))
+	}
+	w.mu.Unlock()
+	if err != nil {
+		w.log.Println("WARN: failed to load broadcast transaction sets:", err)
+		return
+	}
+
+	// The transaction pool notifies the wallet about accepted sets, so the
+	// lock can't be held while rebroadcasting.
+	now := types.CurrentTimestamp()
+	for i := range sets {
+		bts := &sets[i]
+		status := modules.BroadcastStatusPending
+		if _, _, inPool := w.tpool.Transaction(bts.ID); inPool {
+			w.tpool.Broadcast(bts.Transactions)
+		} else if err := w.tpool.AcceptTransactionSet(bts.Transactions); err == nil || errors.Contains(err, modules.ErrDuplicateTransactionSet) {
+			w.log.Println("Transaction set", bts.ID, "was added to the transaction pool again")
+		} else if w.managedDoubleSpent(bts.Transactions) {
+			status = modules.BroadcastStatusDoubleSpent
+		} else {
+			status = modules.BroadcastStatusEvicted
+		}
+		if status != bts.Status {
+			w.log.Printf("Status of broadcast transaction set %v changed from %v to %v", bts.ID, bts.Status, status)
+		}
+		bts.Status = status
+		if status == modules.BroadcastStatusPending {
+			bts.Broadcasts++
+			bts.LastBroadcast = now
+		}
+	}
+
+	// Update the sets which are still tracked.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, bts := range sets {
+		if _, err := dbGetBroadcast(w.dbTx, bts.ID); err != nil {
+			continue
+		}
+		if err := dbPutBroadcast(w.dbTx, bts); err != nil {
+			w.log.Println("WARN: failed to update broadcast transaction set:", err)
+		}
+	}
+}
+
+// managedDoubleSpent returns true if an output of the wallet which is spent by
+// an unconfirmed transaction set was spent by another confirmed transaction.
+func (w *Wallet) managedDoubleSpent(set []types.Transaction) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	spender := make(map[types.OutputID]types.UnlockHash)
+	for _, txn := range set {
+		for _, sci := range txn.SiacoinInputs {
+			spender[types.OutputID(sci.ParentID)] = sci.UnlockConditions.UnlockHash()
+		}
+		for _, sfi := range txn.SiafundInputs {
+			spender[types.OutputID(sfi.ParentID)] = sfi.UnlockConditions.UnlockHash()
+		}
+	}
+	scoids, sfoids := externalInputs(set)
+	for _, id := range scoids {
+		if w.isWalletAddress(spender[types.OutputID(id)]) && w.dbTx.Bucket(bucketSiacoinOutputs).Get(encoding.Marshal(id)) == nil {
+			return true
+		}
+	}
+	for _, id := range sfoids {
+		if w.isWalletAddress(spender[types.OutputID(id)]) && w.dbTx.Bucket(bucketSiafundOutputs).Get(encoding.Marshal(id)) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// StuckTransactions returns the transaction sets broadcast by the wallet which
+// weren't confirmed within stuckBroadcastAge blocks, were evicted from the
+// transaction pool or were double spent. The oldest sets are returned first.
+func (w *Wallet) StuckTransactions() ([]modules.BroadcastTransactionSet, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return nil, err
+	}
+	stuck := []modules.BroadcastTransactionSet{}
+	err = dbForEachBroadcast(w.dbTx, func(id types.TransactionID, bts modules.BroadcastTransactionSet) {
+		if _, err := dbGetTransactionIndex(w.dbTx, id); err == nil {
+			return
+		}
+		if isStuck(bts, height) {
+			stuck = append(stuck, bts)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stuck, func(i, j int) bool {
+		return stuck[i].Height < stuck[j].Height
+	})
+	return stuck, nil
+}
+
+// ChildPaysForParent speeds up the confirmation of a transaction set broadcast
+// by the wallet. A child transaction is added which spends the largest change
+// output of the set and pays enough fees to raise the fee of the whole set to
+// the given fee per byte. Unlike BumpFee, the ids of the transactions in the
+// set don't change. The extended set is submitted to the transaction pool,
+// which also adds it again if the original set was evicted.
+func (w *Wallet) ChildPaysForParent(txid types.TransactionID, feePerByte types.Currency) ([]types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	w.spendMu.Lock()
+	defer w.spendMu.Unlock()
+
+	w.mu.Lock()
+	set, err := w.childPaysForParent(txid, feePerByte)
+	w.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	// The transaction pool notifies the wallet, so the lock can't be held.
+	if err := w.tpool.AcceptTransactionSet(set); err != nil {
+		w.log.Println("Attempt to bump the fee with a child transaction has failed - transaction pool rejected the set:", err)
+		return nil, errors.AddContext(err, "unable to get transaction set with child transaction accepted")
+	}
+	w.managedTrackBroadcast(set)
+
+	w.log.Println("Added child transaction", set[len(set)-1].ID(), "to the transaction set of", txid, "to pay", feePerByte.HumanString(), "per byte")
+	return set, nil
+}
+
+// childPaysForParent returns the tracked transaction set containing the
+// transaction extended by a child transaction which pays the given fee per
+// byte for the set. The caller must hold the lock.
+func (w *Wallet) childPaysForParent(txid types.TransactionID, feePerByte types.Currency) ([]types.Transaction, error) {
+	if !w.unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the tracked set.
+	var bts modules.BroadcastTransactionSet
+	var found bool
+	err = dbForEachBroadcast(w.dbTx, func(_ types.TransactionID, candidate modules.BroadcastTransactionSet) {
+		for _, txn := range candidate.Transactions {
+			if txn.ID() == txid {
+				bts, found = candidate, true
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errUnknownBroadcast
+	}
+	if _, err := dbGetTransactionIndex(w.dbTx, bts.ID); err == nil {
+		return nil, errUnknownBroadcast
+	}
+	if bts.Status == modules.BroadcastStatusDoubleSpent {
+		return nil, errDoubleSpentBroadcast
+	}
+	set := bts.Transactions
+
+	// Spend the largest output of the wallet which isn't spent within the
+	// set.
+	spent := make(map[types.SiacoinOutputID]struct{})
+	var fees types.Currency
+	for _, txn := range set {
+		for _, sci := range txn.SiacoinInputs {
+			spent[sci.ParentID] = struct{}{}
+		}
+		for _, fee := range txn.MinerFees {
+			fees = fees.Add(fee)
+		}
+	}
+	var changeID types.SiacoinOutputID
+	var change types.SiacoinOutput
+	var hasChange bool
+	for _, txn := range set {
+		for i, sco := range txn.SiacoinOutputs {
+			id := txn.SiacoinOutputID(uint64(i))
+			if _, exists := w.keys[sco.UnlockHash]; !exists {
+				continue
+			}
+			if _, exists := spent[id]; exists {
+				continue
+			}
+			if !hasChange || sco.Value.Cmp(change.Value) > 0 {
+				changeID, change, hasChange = id, sco, true
+			}
+		}
+	}
+	if !hasChange {
+		return nil, errBumpFeeNoChange
+	}
+
+	required := feePerByte.Mul64(uint64(len(encoding.Marshal(set))))
+	if required.Cmp(fees) <= 0 {
+		return nil, errBumpFeeLowFee
+	}
+	uc, err := w.nextPrimarySeedAddress(w.dbTx)
+	if err != nil {
+		return nil, err
+	}
+	key := w.keys[change.UnlockHash]
+	// The fee of the child depends on its size, so it is increased until it
+	// covers the size of the extended set.
+	for {
+		fee := required.Sub(fees)
+		if change.Value.Cmp(fee) <= 0 {
+			return nil, errBumpFeeNoChange
+		}
+		child := types.Transaction{
+			SiacoinInputs: []types.SiacoinInput{{
+				ParentID:         changeID,
+				UnlockConditions: key.UnlockConditions,
+			}},
+			SiacoinOutputs: []types.SiacoinOutput{{
+				Value:      change.Value.Sub(fee),
+				UnlockHash: uc.UnlockHash(),
+			}},
+			MinerFees: []types.Currency{fee},
+		}
+		addSignatures(&child, types.FullCoveredFields, key.UnlockConditions, crypto.Hash(changeID), key, height)
+		newSet := append(append([]types.Transaction(nil), set...), child)
+		required = feePerByte.Mul64(uint64(len(encoding.Marshal(newSet))))
+		if fees.Add(fee).Cmp(required) >= 0 {
+			return newSet, nil
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package wallet
+
+import (
+	"testing"
+
+	"gitlab.com/NebulousLabs/encoding"
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// randomSpend returns a transaction spending a random output and paying the
+// given value to the address.
+func randomSpend(value types.Currency, addr types.UnlockHash) types.Transaction {
+	var parent types.SiacoinOutputID
+	fastrand.Read(parent[:])
+	return types.Transaction{
+		SiacoinInputs:  []types.SiacoinInput{{ParentID: parent}},
+		SiacoinOutputs: []types.SiacoinOutput{{Value: value, UnlockHash: addr}},
+		MinerFees:      []types.Currency{types.SiacoinPrecision.Div64(10)},
+	}
+}
+
+// TestExternalInputs is a unit test for externalInputs and spendsSameOutputs.
+func TestExternalInputs(t *testing.T) {
+	t.Parallel()
+	parent := randomSpend(types.SiacoinPrecision, types.UnlockHash{})
+	child := randomSpend(types.SiacoinPrecision, types.UnlockHash{})
+	child.SiacoinInputs = append(child.SiacoinInputs, types.SiacoinInput{ParentID: parent.SiacoinOutputID(0)})
+	child.SiafundInputs = []types.SiafundInput{{ParentID: types.SiafundOutputID{1}}}
+
+	scoids, sfoids := externalInputs([]types.Transaction{parent, child})
+	if len(scoids) != 2 || scoids[0] != parent.SiacoinInputs[0].ParentID || scoids[1] != child.SiacoinInputs[0].ParentID {
+		t.Fatal("wrong siacoin inputs", scoids)
+	}
+	if len(sfoids) != 1 || sfoids[0] != (types.SiafundOutputID{1}) {
+		t.Fatal("wrong siafund inputs", sfoids)
+	}
+
+	// A replacement spends the same outputs, an unrelated set doesn't.
+	replacement := parent
+	replacement.MinerFees = []types.Currency{types.SiacoinPrecision}
+	if !spendsSameOutputs([]types.Transaction{parent, child}, []types.Transaction{replacement}) {
+		t.Fatal("replacement should spend the same outputs")
+	}
+	unrelated := randomSpend(types.SiacoinPrecision, types.UnlockHash{})
+	if spendsSameOutputs([]types.Transaction{parent, child}, []types.Transaction{unrelated}) {
+		t.Fatal("unrelated set shouldn't spend the same outputs")
+	}
+}
+
+// TestIsStuck is a unit test for isStuck.
+func TestIsStuck(t *testing.T) {
+	t.Parallel()
+	bts := modules.BroadcastTransactionSet{
+		Status: modules.BroadcastStatusPending,
+		Height: 10,
+	}
+	if isStuck(bts, 10+stuckBroadcastAge-1) {
+		t.Fatal("recent pending set shouldn't be stuck")
+	}
+	if !isStuck(bts, 10+stuckBroadcastAge) {
+		t.Fatal("old pending set should be stuck")
+	}
+	for _, status := range []modules.BroadcastStatus{modules.BroadcastStatusEvicted, modules.BroadcastStatusDoubleSpent} {
+		bts.Status = status
+		if !isStuck(bts, 10) {
+			t.Fatalf("%v set should be stuck", status)
+		}
+	}
+}
+
+// TestTrackBroadcast checks that the wallet tracks its broadcast sets, marks
+// the ones which can't be added to the transaction pool and replaces sets
+// which spend the same outputs.
+func TestTrackBroadcast(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createBlankWalletTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	w := wt.wallet
+
+	txn := randomSpend(types.SiacoinPrecision, types.UnlockHash{})
+	w.managedTrackBroadcast([]types.Transaction{txn})
+	stuck, err := w.StuckTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stuck) != 0 {
+		t.Fatal("new set shouldn't be stuck", stuck)
+	}
+
+	// The set spends an output which doesn't exist, so it can't be added to
+	// the transaction pool. It doesn't spend an output of the wallet, so it
+	// isn't double spent.
+	w.managedRebroadcast()
+	stuck, err = w.StuckTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stuck) != 1 || stuck[0].ID != txn.ID() || stuck[0].Status != modules.BroadcastStatusEvicted {
+		t.Fatal("set should be evicted", stuck)
+	}
+	if stuck[0].Broadcasts != 1 {
+		t.Fatal("evicted set shouldn't be broadcast", stuck[0].Broadcasts)
+	}
+
+	// A replacement is tracked instead of the original set.
+	replacement := txn
+	replacement.MinerFees = []types.Currency{types.SiacoinPrecision}
+	w.managedTrackBroadcast([]types.Transaction{replacement})
+	w.mu.Lock()
+	var ids []types.TransactionID
+	err = dbForEachBroadcast(w.dbTx, func(id types.TransactionID, _ modules.BroadcastTransactionSet) {
+		ids = append(ids, id)
+	})
+	w.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != replacement.ID() {
+		t.Fatal("replacement isn't tracked", ids)
+	}
+}
+
+// TestChildPaysForParent checks that the child transaction spends the change
+// output of a broadcast set and pays the requested fee for the whole set.
+func TestChildPaysForParent(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	wt, err := createBlankWalletTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	w := wt.wallet
+	key := crypto.NewWalletKey(crypto.HashObject([]byte{}))
+	if _, err := w.Encrypt(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Unlock(key); err != nil {
+		t.Fatal(err)
+	}
+	uc, err := w.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only broadcast sets can be bumped.
+	txn := randomSpend(types.SiacoinPrecision.Mul64(10), uc.UnlockHash())
+	feePerByte := types.SiacoinPrecision.Div64(1e3)
+	w.mu.Lock()
+	_, err = w.childPaysForParent(txn.ID(), feePerByte)
+	w.mu.Unlock()
+	if !errors.Contains(err, errUnknownBroadcast) {
+		t.Fatal("expected errUnknownBroadcast but got", err)
+	}
+	w.managedTrackBroadcast([]types.Transaction{txn})
+
+	// The fee needs to be higher than the current fee of the set.
+	w.mu.Lock()
+	_, err = w.childPaysForParent(txn.ID(), types.NewCurrency64(1))
+	w.mu.Unlock()
+	if !errors.Contains(err, errBumpFeeLowFee) {
+		t.Fatal("expected errBumpFeeLowFee but got", err)
+	}
+
+	w.mu.Lock()
+	set, err := w.childPaysForParent(txn.ID(), feePerByte)
+	w.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 2 || set[0].ID() != txn.ID() {
+		t.Fatal("parent should be unchanged", len(set))
+	}
+	child := set[1]
+	if len(child.SiacoinInputs) != 1 || child.SiacoinInputs[0].ParentID != txn.SiacoinOutputID(0) {
+		t.Fatal("child doesn't spend the change output")
+	}
+	if err := child.StandaloneValid(0); err != nil {
+		t.Fatal("child isn't valid:", err)
+	}
+	var fees types.Currency
+	for _, txn := range set {
+		for _, fee := range txn.MinerFees {
+			fees = fees.Add(fee)
+		}
+	}
+	if fees.Cmp(feePerByte.Mul64(uint64(len(encoding.Marshal(set))))) < 0 {
+		t.Fatal("set doesn't pay the requested fee", fees)
+	}
+	if !child.SiacoinOutputs[0].Value.Add(child.MinerFees[0]).Equals(txn.SiacoinOutputs[0].Value) {
+		t.Fatal("child doesn't spend the whole change output")
+	}
+}
@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"fmt"
+
+	"go.sia.tech/siad/modules"
+)
+
+// Rescan rescans the blockchain for transactions of the wallet's addresses.
+// If gapLimit is not zero, the lookahead of the primary seed is extended to at
+// least gapLimit addresses past the last used address, so that addresses which
+// were generated elsewhere are found. The gap limit is persisted. The rescan
+// runs in the background and its progress is reported by RescanProgress.
+func (w *Wallet) Rescan(gapLimit uint64) error {
+	if err := w.tg.Add(); err != nil {
+		return modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+	if gapLimit > maxGapLimit {
+		return fmt.Errorf("gap limit can't be larger than %v", maxGapLimit)
+	}
+	if !w.scanLock.TryLock() {
+		return errScanInProgress
+	}
+
+	err := func() error {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if !w.unlocked {
+			return modules.ErrLockedWallet
+		}
+
+		if gapLimit != 0 {
+			if err := dbPutGapLimit(w.dbTx, gapLimit); err != nil {
+				return err
+			}
+			progress, err := dbGetPrimarySeedProgress(w.dbTx)
+			if err != nil {
+				return err
+			}
+			w.gapLimit = gapLimit
+			w.regenerateLookahead(progress)
+		}
+
+		// prepare to rescan
+		if err := w.dbTx.DeleteBucket(bucketProcessedTransactions); err != nil {
+			return err
+		}
+		if _, err := w.dbTx.CreateBucket(bucketProcessedTransactions); err != nil {
+			return err
+		}
+		w.unconfirmedProcessedTransactions = nil
+		if err := dbPutConsensusChangeID(w.dbTx, modules.ConsensusChangeBeginning); err != nil {
+			return err
+		}
+		if err := dbPutConsensusHeight(w.dbTx, 0); err != nil {
+			return err
+		}
+		w.rescanErr = nil
+		return w.syncDB()
+	}()
+	if err != nil {
+		w.scanLock.Unlock()
+		return err
+	}
+
+	go func() {
+		defer w.scanLock.Unlock()
+		if err := w.tg.Add(); err != nil {
+			return
+		}
+		defer w.tg.Done()
+		w.managedResubscribe()
+	}()
+	return nil
+}
+
+// RescanProgress reports the progress of the current or last rescan of the
+// blockchain.
+func (w *Wallet) RescanProgress() (modules.WalletRescanProgress, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletRescanProgress{}, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	rescanning := !w.scanLock.TryLock()
+	if !rescanning {
+		w.scanLock.Unlock()
+	}
+	// The consensus set may hold its lock while waiting for the wallet's lock,
+	// so its height needs to be fetched first.
+	targetHeight := w.cs.Height()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	height, err := dbGetConsensusHeight(w.dbTx)
+	if err != nil {
+		return modules.WalletRescanProgress{}, err
+	}
+	progress := modules.WalletRescanProgress{
+		Rescanning:    rescanning,
+		ScannedHeight: height,
+		TargetHeight:  targetHeight,
+		GapLimit:      w.gapLimit,
+		Lookahead:     uint64(len(w.lookahead)),
+	}
+	if w.rescanErr != nil {
+		progress.Error = w.rescanErr.Error()
+	}
+	return progress, nil
+}
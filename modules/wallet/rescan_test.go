@@ -0,0 +1,102 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"go.sia.tech/siad/build"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestMaxLookahead is a unit test for maxLookahead.
+func TestMaxLookahead(t *testing.T) {
+	t.Parallel()
+
+	def := maxLookahead(100, 0)
+	if def != 100+lookaheadRescanThreshold+lookaheadBuffer+10 {
+		t.Fatal("wrong default lookahead", def)
+	}
+	if l := maxLookahead(100, def-1); l != def {
+		t.Fatal("a small gap limit shouldn't change the lookahead", l)
+	}
+	if l := maxLookahead(100, def+1); l != def+1 {
+		t.Fatal("a large gap limit should extend the lookahead", l)
+	}
+}
+
+// TestRescanGapLimit tests that a rescan with a large enough gap limit finds
+// the outputs of addresses beyond the default lookahead.
+func TestRescanGapLimit(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Send coins to an address of the primary seed outside of the lookahead.
+	wt.wallet.mu.RLock()
+	progress, err := dbGetPrimarySeedProgress(wt.wallet.dbTx)
+	wt.wallet.mu.RUnlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := maxLookahead(progress, 0) * 2
+	farAddr := generateSpendableKey(wt.wallet.primarySeed, progress+index).UnlockConditions.UnlockHash()
+	if _, err := wt.wallet.SendSiacoins(types.SiacoinPrecision, farAddr); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.addBlockNoPayout(); err != nil {
+		t.Fatal(err)
+	}
+	before, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A gap limit which is too large is rejected.
+	if err := wt.wallet.Rescan(maxGapLimit + 1); err == nil {
+		t.Fatal("expected an error for a gap limit above the maximum")
+	}
+
+	// Rescan with a gap limit that covers the address.
+	if err := wt.wallet.Rescan(index + 1); err != nil {
+		t.Fatal(err)
+	}
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		rp, err := wt.wallet.RescanProgress()
+		if err != nil {
+			return err
+		}
+		if rp.Rescanning || rp.ScannedHeight != rp.TargetHeight {
+			return errScanInProgress
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rp, err := wt.wallet.RescanProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp.GapLimit != index+1 || rp.Error != "" {
+		t.Fatal("wrong rescan progress", rp)
+	}
+
+	// The coins sent to the address are part of the balance again.
+	after, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.Equals(before.Add(types.SiacoinPrecision)) {
+		t.Fatal("rescan didn't find the output of the distant address", before, after)
+	}
+}
@@ -97,7 +97,7 @@ func decryptSeedFile(masterKey crypto.CipherKey, sf seedFile) (seed modules.Seed
 // regenerateLookahead creates future keys up to a maximum of maxKeys keys
 func (w *Wallet) regenerateLookahead(start uint64) {
 	// Check how many keys need to be generated
-	maxKeys := maxLookahead(start)
+	maxKeys := maxLookahead(start, w.gapLimit)
 	existingKeys := uint64(len(w.lookahead))
 
 	for i, k := range generateKeys(w.primarySeed, start+existingKeys, maxKeys-existingKeys) {
@@ -605,6 +605,7 @@ func (w *Wallet) SweepSeed(seed modules.Seed) (coins, funds types.Currency, err
 		if err != nil {
 			return types.ZeroCurrency, types.ZeroCurrency, err
 		}
+		w.managedTrackBroadcast(txnSet)
 
 		w.log.Println("Creating a transaction set to sweep a seed, IDs:")
 		for _, txn := range txnSet {
@@ -48,6 +48,9 @@ type transactionBuilder struct {
 	siafundInputs         []int
 	transactionSignatures []int
 
+	// strategy determines which outputs are used by 'FundSiacoins'.
+	strategy modules.SelectionStrategy
+
 	wallet *Wallet
 }
 
@@ -108,8 +111,15 @@ func (w *Wallet) checkOutput(tx *bolt.Tx, currentHeight types.BlockHeight, id ty
 			return errSpendHeightTooHigh
 		}
 	}
-	outputUnlockConditions := w.keys[output.UnlockHash].UnlockConditions
-	if currentHeight < outputUnlockConditions.Timelock {
+	// Check that the wallet can and may spend the output.
+	spendKey, spendable := w.keys[output.UnlockHash]
+	if !spendable {
+		return errUnspendableOutput
+	}
+	if dbGetDoNotSpend(tx, types.OutputID(id)) {
+		return errDoNotSpend
+	}
+	if currentHeight < spendKey.UnlockConditions.Timelock {
 		return errOutputTimelock
 	}
 
@@ -136,6 +146,7 @@ func (tb *transactionBuilder) Copy() modules.TransactionBuilder {
 	copy(copyBuilder.transactionSignatures, tb.transactionSignatures)
 
 	copyBuilder.signed = tb.signed
+	copyBuilder.strategy = tb.strategy
 	return copyBuilder
 }
 
@@ -231,48 +242,49 @@ func (tb *transactionBuilder) FundSiacoins(amount types.Currency) (err error) {
 	}
 	sort.Sort(sort.Reverse(so))
 
-	// Create and fund a parent transaction that will add the correct amount of
-	// siacoins to the transaction.
-	var fund types.Currency
+	// The consolidate strategy spends dust outputs as well.
+	if tb.strategy == modules.SelectConsolidate {
+		dustThreshold = types.ZeroCurrency
+	}
+
+	// Collect the outputs that can be spent.
+	var usable sortedOutputs
 	// potentialFund tracks the balance of the wallet including outputs that
 	// have been spent in other unconfirmed transactions recently. This is to
 	// provide the user with a more useful error message in the event that they
 	// are overspending.
 	var potentialFund types.Currency
-	parentTxn := types.Transaction{}
-	var spentScoids []types.SiacoinOutputID
 	for i := range so.ids {
-		scoid := so.ids[i]
-		sco := so.outputs[i]
-		// Check that the output can be spent.
-		if err := tb.wallet.checkOutput(tb.wallet.dbTx, consensusHeight, scoid, sco, dustThreshold); err != nil {
+		if err := tb.wallet.checkOutput(tb.wallet.dbTx, consensusHeight, so.ids[i], so.outputs[i], dustThreshold); err != nil {
 			if errors.Contains(err, errSpendHeightTooHigh) {
-				potentialFund = potentialFund.Add(sco.Value)
+				potentialFund = potentialFund.Add(so.outputs[i].Value)
 			}
 			continue
 		}
-
-		// Add a siacoin input for this output.
-		sci := types.SiacoinInput{
-			ParentID:         scoid,
-			UnlockConditions: tb.wallet.keys[sco.UnlockHash].UnlockConditions,
-		}
-		parentTxn.SiacoinInputs = append(parentTxn.SiacoinInputs, sci)
-		spentScoids = append(spentScoids, scoid)
-
-		// Add the output to the total fund
-		fund = fund.Add(sco.Value)
-		potentialFund = potentialFund.Add(sco.Value)
-		if fund.Cmp(amount) >= 0 {
-			break
-		}
+		usable.ids = append(usable.ids, so.ids[i])
+		usable.outputs = append(usable.outputs, so.outputs[i])
+		potentialFund = potentialFund.Add(so.outputs[i].Value)
 	}
+
+	// Create and fund a parent transaction that will add the correct amount of
+	// siacoins to the transaction.
+	selected, fund := selectOutputs(usable, amount, tb.strategy)
 	if potentialFund.Cmp(amount) >= 0 && fund.Cmp(amount) < 0 {
 		return modules.ErrIncompleteTransactions
 	}
 	if fund.Cmp(amount) < 0 {
 		return modules.ErrLowBalance
 	}
+	parentTxn := types.Transaction{}
+	var spentScoids []types.SiacoinOutputID
+	for _, i := range selected {
+		sci := types.SiacoinInput{
+			ParentID:         usable.ids[i],
+			UnlockConditions: tb.wallet.keys[usable.outputs[i].UnlockHash].UnlockConditions,
+		}
+		parentTxn.SiacoinInputs = append(parentTxn.SiacoinInputs, sci)
+		spentScoids = append(spentScoids, usable.ids[i])
+	}
 
 	// Create and add the output that will be used to fund the standard
 	// transaction.
@@ -389,7 +401,11 @@ func (tb *transactionBuilder) FundSiafunds(amount types.Currency) (err error) {
 			potentialFund = potentialFund.Add(sfo.Value)
 			continue
 		}
-		outputUnlockConditions := tb.wallet.keys[sfo.UnlockHash].UnlockConditions
+		spendKey, spendable := tb.wallet.keys[sfo.UnlockHash]
+		if !spendable || dbGetDoNotSpend(tb.wallet.dbTx, types.OutputID(sfoid)) {
+			continue
+		}
+		outputUnlockConditions := spendKey.UnlockConditions
 		if consensusHeight < outputUnlockConditions.Timelock {
 			continue
 		}
@@ -533,6 +549,12 @@ func (tb *transactionBuilder) UnconfirmedParents() (parents []types.Transaction,
 	return
 }
 
+// SetSelectionStrategy sets the strategy which is used to select the outputs
+// that fund the transaction in subsequent calls to 'FundSiacoins'.
+func (tb *transactionBuilder) SetSelectionStrategy(strategy modules.SelectionStrategy) {
+	tb.strategy = strategy
+}
+
 // AddParents adds a set of parents to the transaction.
 func (tb *transactionBuilder) AddParents(newParents []types.Transaction) {
 	tb.parents = append(tb.parents, newParents...)
@@ -782,6 +804,7 @@ func (w *Wallet) registerTransaction(t types.Transaction, parents []types.Transa
 	return &transactionBuilder{
 		parents:     pCopy,
 		transaction: tCopy,
+		strategy:    modules.SelectMinimizeInputs,
 
 		wallet: w,
 	}
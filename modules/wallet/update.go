@@ -23,11 +23,21 @@ func (w *Wallet) threadedResetSubscriptions() {
 		return
 	}
 	defer w.scanLock.Unlock()
+	w.managedResubscribe()
+}
 
+// managedResubscribe unsubscribes the wallet from the consensus set and
+// transaction pool and subscribes again from the beginning of the blockchain.
+// The error of the rescan is reported by RescanProgress. The caller must hold
+// the scanLock.
+func (w *Wallet) managedResubscribe() {
 	w.cs.Unsubscribe(w)
 	w.tpool.Unsubscribe(w)
 
 	err := w.cs.ConsensusSetSubscribe(w, modules.ConsensusChangeBeginning, w.tg.StopChan())
+	w.mu.Lock()
+	w.rescanErr = err
+	w.mu.Unlock()
 	if err != nil {
 		w.log.Print("failed to subscribe wallet to consensus", err)
 		return
@@ -76,6 +86,14 @@ func (w *Wallet) isWalletAddress(uh types.UnlockHash) bool {
 	return spendable || watchonly
 }
 
+// isWatchOnlyAddress is a helper function that checks if an UnlockHash is
+// being explicitly watched without the wallet holding its keys.
+func (w *Wallet) isWatchOnlyAddress(uh types.UnlockHash) bool {
+	_, spendable := w.keys[uh]
+	_, watchonly := w.watchedAddrs[uh]
+	return watchonly && !spendable
+}
+
 // updateLookahead uses a consensus change to update the seed progress if one of the outputs
 // contains an unlock hash of the lookahead set. Returns true if a blockchain rescan is required
 func (w *Wallet) updateLookahead(tx *bolt.Tx, cc modules.ConsensusChange) (bool, error) {
@@ -176,6 +194,7 @@ func (w *Wallet) revertHistory(tx *bolt.Tx, reverted []types.Block) error {
 					w.log.Severe("Could not revert transaction:", err)
 					return err
 				}
+				w.staticEvents.callAddTransaction(modules.WalletEventTransactionReverted, pt)
 			}
 		}
 
@@ -193,6 +212,7 @@ func (w *Wallet) revertHistory(tx *bolt.Tx, reverted []types.Block) error {
 					w.log.Severe("Could not revert transaction:", err)
 					return err
 				}
+				w.staticEvents.callAddTransaction(modules.WalletEventTransactionReverted, pt)
 				break // there will only ever be one miner transaction
 			}
 		}
@@ -488,6 +508,11 @@ func (w *Wallet) applyHistory(tx *bolt.Tx, cc modules.ConsensusChange) error {
 			if err != nil {
 				return errors.AddContext(err, "could not put processed transaction")
 			}
+			// Don't flood the event stream with the history while syncing
+			// or rescanning.
+			if cc.Synced {
+				w.staticEvents.callAddTransaction(modules.WalletEventTransactionConfirmed, pt)
+			}
 		}
 	}
 
@@ -534,6 +559,7 @@ func (w *Wallet) ProcessConsensusChange(cc modules.ConsensusChange) {
 
 	if cc.Synced {
 		go w.threadedDefragWallet()
+		go w.threadedUpdateBalanceEvent()
 	}
 }
 
@@ -548,6 +574,10 @@ func (w *Wallet) ReceiveUpdatedUnconfirmedTransactions(diff *modules.Transaction
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	// changed indicates whether the wallet's unconfirmed transactions changed,
+	// which might change its balance.
+	changed := false
+
 	// Do the pruning first. If there are any pruned transactions, we will need
 	// to re-allocate the whole processed transactions array.
 	droppedTransactions := make(map[types.TransactionID]struct{})
@@ -578,6 +608,7 @@ func (w *Wallet) ReceiveUpdatedUnconfirmedTransactions(diff *modules.Transaction
 		}
 
 		// Set the unconfirmed preocessed transactions to the pruned set.
+		changed = len(newUPT) != len(w.unconfirmedProcessedTransactions)
 		w.unconfirmedProcessedTransactions = newUPT
 	}
 
@@ -647,6 +678,11 @@ func (w *Wallet) ReceiveUpdatedUnconfirmedTransactions(diff *modules.Transaction
 				})
 			}
 			w.unconfirmedProcessedTransactions = append(w.unconfirmedProcessedTransactions, pt)
+			w.staticEvents.callAddTransaction(modules.WalletEventTransactionUnconfirmed, pt)
+			changed = true
 		}
 	}
+	if changed {
+		go w.threadedUpdateBalanceEvent()
+	}
 }
@@ -80,6 +80,11 @@ type Wallet struct {
 	lookahead    map[types.UnlockHash]uint64
 	watchedAddrs map[types.UnlockHash]struct{}
 
+	// gapLimit is the minimum number of addresses past the primary seed
+	// progress which are kept in the lookahead. It is zero if the default
+	// lookahead is used.
+	gapLimit uint64
+
 	// unconfirmedProcessedTransactions tracks unconfirmed transactions.
 	//
 	// TODO: Replace this field with a linked list. Currently when a new
@@ -109,6 +114,18 @@ type Wallet struct {
 	// initialization.
 	scanLock siasync.TryMutex
 
+	// spendMu serializes the sends which are subject to the spending limits,
+	// so that the daily limit can't be exceeded by concurrent sends.
+	spendMu sync.Mutex
+
+	// rescanErr is the error of the last rescan of the blockchain, if it
+	// failed.
+	rescanErr error
+
+	// staticEvents is the wallet's event stream of balance changes and
+	// transactions.
+	staticEvents *walletEvents
+
 	// The wallet's ThreadGroup tells tracked functions to shut down and
 	// blocks until they have all exited before returning from Close.
 	tg threadgroup.ThreadGroup
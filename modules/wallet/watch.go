@@ -0,0 +1,130 @@
+package wallet
+
+import (
+	"sort"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// WatchBalances returns the balances of the watch-only addresses. Addresses
+// which the wallet holds the keys for are reported by ConfirmedBalance
+// instead.
+func (w *Wallet) WatchBalances() ([]modules.WatchAddressBalance, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+	// ensure durability of reported balance
+	if err := w.syncDB(); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[types.UnlockHash]*modules.WatchAddressBalance)
+	for addr := range w.watchedAddrs {
+		if w.isWatchOnlyAddress(addr) {
+			balances[addr] = &modules.WatchAddressBalance{Address: addr}
+		}
+	}
+	dbForEachSiacoinOutput(w.dbTx, func(_ types.SiacoinOutputID, sco types.SiacoinOutput) {
+		if wb, ok := balances[sco.UnlockHash]; ok {
+			wb.ConfirmedSiacoinBalance = wb.ConfirmedSiacoinBalance.Add(sco.Value)
+		}
+	})
+	dbForEachSiafundOutput(w.dbTx, func(_ types.SiafundOutputID, sfo types.SiafundOutput) {
+		if wb, ok := balances[sfo.UnlockHash]; ok {
+			wb.SiafundBalance = wb.SiafundBalance.Add(sfo.Value)
+		}
+	})
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, input := range upt.Inputs {
+			if wb, ok := balances[input.RelatedAddress]; ok && input.FundType == types.SpecifierSiacoinInput {
+				wb.UnconfirmedOutgoingSiacoins = wb.UnconfirmedOutgoingSiacoins.Add(input.Value)
+			}
+		}
+		for _, output := range upt.Outputs {
+			if wb, ok := balances[output.RelatedAddress]; ok && output.FundType == types.SpecifierSiacoinOutput {
+				wb.UnconfirmedIncomingSiacoins = wb.UnconfirmedIncomingSiacoins.Add(output.Value)
+			}
+		}
+	}
+
+	wbs := make([]modules.WatchAddressBalance, 0, len(balances))
+	for addr, wb := range balances {
+		txnIndices, _ := dbGetAddrTransactions(w.dbTx, addr)
+		wb.Transactions = uint64(len(txnIndices))
+		wbs = append(wbs, *wb)
+	}
+	sort.Slice(wbs, func(i, j int) bool {
+		return wbs[i].Address.String() < wbs[j].Address.String()
+	})
+	return wbs, nil
+}
+
+// WatchTransactions returns the confirmed and unconfirmed transactions related
+// to the watch-only addresses. Confirmed transactions are ordered by their
+// confirmation.
+func (w *Wallet) WatchTransactions() (confirmed, unconfirmed []modules.ProcessedTransaction, err error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, nil, modules.ErrWalletShutdown
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return nil, nil, modules.ErrLockedWallet
+	}
+	// ensure durability of reported transactions
+	if err := w.syncDB(); err != nil {
+		return nil, nil, err
+	}
+
+	// A transaction may be related to multiple watched addresses.
+	indexSet := make(map[uint64]struct{})
+	for addr := range w.watchedAddrs {
+		if !w.isWatchOnlyAddress(addr) {
+			continue
+		}
+		txnIndices, _ := dbGetAddrTransactions(w.dbTx, addr)
+		for _, i := range txnIndices {
+			indexSet[i] = struct{}{}
+		}
+	}
+	indices := make([]uint64, 0, len(indexSet))
+	for i := range indexSet {
+		indices = append(indices, i)
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
+	})
+	confirmed = make([]modules.ProcessedTransaction, 0, len(indices))
+	for _, i := range indices {
+		pt, err := dbGetProcessedTransaction(w.dbTx, i)
+		if err != nil {
+			continue
+		}
+		confirmed = append(confirmed, pt)
+	}
+
+	unconfirmed = []modules.ProcessedTransaction{}
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		relevant := false
+		for _, input := range upt.Inputs {
+			relevant = relevant || w.isWatchOnlyAddress(input.RelatedAddress)
+		}
+		for _, output := range upt.Outputs {
+			relevant = relevant || w.isWatchOnlyAddress(output.RelatedAddress)
+		}
+		if relevant {
+			unconfirmed = append(unconfirmed, upt)
+		}
+	}
+	return confirmed, unconfirmed, nil
+}
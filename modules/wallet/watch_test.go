@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestWatchBalances tests that the balances and transactions of watch-only
+// addresses are reported separately from the wallet's balance.
+func TestWatchBalances(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester(t.Name(), modules.ProdDependencies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := wt.closeWt(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// send coins to an address the wallet doesn't have the keys for
+	sk := generateSpendableKey(modules.Seed{}, 1234)
+	addr := sk.UnlockConditions.UnlockHash()
+	amount := types.SiacoinPrecision.Mul64(77)
+	_, err = wt.wallet.SendSiacoins(amount, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	balance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// watch the address; the wallet's balance shouldn't change
+	err = wt.wallet.AddWatchAddresses([]types.UnlockHash{addr}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newBalance, _, _, err := wt.wallet.ConfirmedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !newBalance.Equals(balance) {
+		t.Fatalf("watch-only balance was added to the wallet's balance: %v != %v", newBalance, balance)
+	}
+
+	// the watch-only balance should contain the coins
+	wbs, err := wt.wallet.WatchBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wbs) != 1 || wbs[0].Address != addr {
+		t.Fatal("expected a balance for the watched address", wbs)
+	}
+	if !wbs[0].ConfirmedSiacoinBalance.Equals(amount) || wbs[0].Transactions != 1 {
+		t.Fatal("wrong watch-only balance", wbs[0])
+	}
+	confirmed, unconfirmed, err := wt.wallet.WatchTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(confirmed) != 1 || len(unconfirmed) != 0 {
+		t.Fatal("wrong number of watch-only transactions", len(confirmed), len(unconfirmed))
+	}
+}
@@ -35,6 +35,12 @@ type Error struct {
 	// `err.Error()`. This field is required.
 	Message string `json:"message"`
 
+	// Code is a stable, machine readable identifier of the error. Unlike the
+	// Message it doesn't change between releases, which allows clients to
+	// branch on it. If it is not set, WriteError will derive it from the
+	// Message and the status code of the response.
+	Code ErrorCode `json:"code,omitempty"`
+
 	// TODO: add a Param field with the (omitempty option in the json tag)
 	// to indicate that the error was caused by an invalid, missing, or
 	// incorrect parameter. This is not trivial as the API does not
@@ -233,15 +239,18 @@ func (api *API) UnrecognizedCallHandler(w http.ResponseWriter, _ *http.Request)
 	var errStr string
 	if api.modulesSet {
 		errStr = fmt.Sprintf("%d Module disabled - Refer to API.md", StatusModuleDisabled)
-		WriteError(w, Error{errStr}, StatusModuleDisabled)
+		WriteError(w, Error{Message: errStr}, StatusModuleDisabled)
 	} else {
 		errStr = fmt.Sprintf("%d Module not loaded - Refer to API.md", StatusModuleNotLoaded)
-		WriteError(w, Error{errStr}, StatusModuleNotLoaded)
+		WriteError(w, Error{Message: errStr}, StatusModuleNotLoaded)
 	}
 }
 
 // WriteError an error to the API caller.
 func WriteError(w http.ResponseWriter, err Error, code int) {
+	if err.Code == "" {
+		err.Code = statusErrorCode(code)
+	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(code)
 	encodingErr := json.NewEncoder(w).Encode(err)
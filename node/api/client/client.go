@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"go.sia.tech/siad/node/api"
 
 	"gitlab.com/NebulousLabs/errors"
+	"golang.org/x/net/websocket"
 )
 
 type (
@@ -126,6 +128,28 @@ func (c *Client) NewRequest(method, resource string, body io.Reader) (*http.Requ
 	return req, nil
 }
 
+// dialWebSocket opens a WebSocket connection to the siad HTTP API, setting the
+// correct User-Agent and Basic Auth. The resource path must begin with /.
+func (c *Client) dialWebSocket(resource string) (*websocket.Conn, error) {
+	config, err := websocket.NewConfig("ws://"+c.Address+resource, "http://"+c.Address)
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to create websocket config")
+	}
+	agent := c.UserAgent
+	if agent == "" {
+		agent = "Sia-Agent"
+	}
+	config.Header.Set("User-Agent", agent)
+	if c.Password != "" {
+		config.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+c.Password)))
+	}
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to connect to "+resource)
+	}
+	return conn, nil
+}
+
 // drainAndClose reads rc until EOF and then closes it. drainAndClose should
 // always be called on HTTP response bodies, because if the body is not fully
 // read, the underlying connection can't be reused.
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"gitlab.com/NebulousLabs/encoding"
 	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/node/api"
 	"go.sia.tech/siad/types"
+	"golang.org/x/net/websocket"
 )
 
 // ConsensusGet requests the /consensus api resource
@@ -32,6 +34,46 @@ func (c *Client) ConsensusBlocksHeightGet(height types.BlockHeight) (cbg api.Con
 	return
 }
 
+// ConsensusHashrateGet requests the /consensus/hashrate api resource
+func (c *Client) ConsensusHashrateGet(window types.BlockHeight, samples uint64) (chg api.ConsensusHashrateGET, err error) {
+	err = c.get(fmt.Sprintf("/consensus/hashrate?window=%v&samples=%v", window, samples), &chg)
+	return
+}
+
+// ConsensusReorgGet requests the /consensus/reorg endpoint.
+func (c *Client) ConsensusReorgGet() (crg api.ConsensusReorgGET, err error) {
+	err = c.get("/consensus/reorg", &crg)
+	return
+}
+
+// ConsensusReorgPost uses the /consensus/reorg endpoint to set the reorg
+// settings.
+func (c *Client) ConsensusReorgPost(settings modules.ReorgSettings) (err error) {
+	values := url.Values{}
+	values.Set("alertdepth", fmt.Sprint(settings.AlertDepth))
+	values.Set("maxdepth", fmt.Sprint(settings.MaxDepth))
+	err = c.post("/consensus/reorg", values.Encode(), nil)
+	return
+}
+
+// ConsensusReorgConfirmPost uses the /consensus/reorg/confirm endpoint to
+// switch to the fork of a refused reorg.
+func (c *Client) ConsensusReorgConfirmPost(tip types.BlockID) (err error) {
+	values := url.Values{}
+	values.Set("id", tip.String())
+	err = c.post("/consensus/reorg/confirm", values.Encode(), nil)
+	return
+}
+
+// ConsensusVerifyPost uses the /consensus/verify endpoint to check the
+// consensus database for inconsistencies, optionally repairing them.
+func (c *Client) ConsensusVerifyPost(repair bool) (cvp api.ConsensusVerifyPOST, err error) {
+	values := url.Values{}
+	values.Set("repair", fmt.Sprint(repair))
+	err = c.post("/consensus/verify", values.Encode(), &cvp)
+	return
+}
+
 // ConsensusSubscribeSingle streams consensus changes from the
 // /consensus/subscribe endpoint to the provided subscriber. Multiple calls may
 // be required before the subscriber is fully caught up. It returns the latest
@@ -72,6 +114,31 @@ func (c *Client) ConsensusSubscribeSingle(subscriber modules.ConsensusSetSubscri
 	}
 }
 
+// ConsensusSubscribeWS connects to the /consensus/subscribe/:id/ws WebSocket
+// and calls fn for every consensus change after the change with the provided
+// ID, including the changes which occur after the client caught up. It
+// returns when fn returns an error, the subscription fails or the connection
+// is closed.
+func (c *Client) ConsensusSubscribeWS(ccid modules.ConsensusChangeID, fn func(api.ConsensusChangeEvent) error) error {
+	conn, err := c.dialWebSocket(fmt.Sprintf("/consensus/subscribe/%s/ws", ccid))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for {
+		var e api.ConsensusChangeEvent
+		if err := websocket.JSON.Receive(conn, &e); err != nil {
+			return fmt.Errorf("failed to receive consensus change: %w", err)
+		}
+		if e.Error != "" {
+			return errors.New(e.Error)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
+
 // ConsensusSetSubscribe polls the /consensus/subscribe endpoint, streaming
 // consensus changes to the subscriber indefinitely. First, it will stream
 // changes until the subscriber is fully caught up. It will send any error
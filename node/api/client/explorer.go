@@ -0,0 +1,14 @@
+package client
+
+import (
+	"fmt"
+
+	"go.sia.tech/siad/node/api"
+	"go.sia.tech/siad/types"
+)
+
+// ExplorerAddressGet requests the /explorer/address/:addr api resource
+func (c *Client) ExplorerAddressGet(addr types.UnlockHash, offset, limit uint64) (eag api.ExplorerAddressGET, err error) {
+	err = c.get(fmt.Sprintf("/explorer/address/%v?offset=%v&limit=%v", addr, offset, limit), &eag)
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/url"
 	"strconv"
+	"time"
 
 	"gitlab.com/NebulousLabs/errors"
 
@@ -41,12 +42,27 @@ func (c *Client) GatewayDisconnectPost(address modules.NetAddress) (err error) {
 	return
 }
 
+// GatewayConnectivityGet requests the /gateway/connectivity api resource. If
+// probe is true, the reachability of the node is probed first.
+func (c *Client) GatewayConnectivityGet(probe bool) (gcg api.GatewayConnectivityGET, err error) {
+	values := url.Values{}
+	values.Set("probe", strconv.FormatBool(probe))
+	err = c.get("/gateway/connectivity?"+values.Encode(), &gcg)
+	return
+}
+
 // GatewayGet requests the /gateway api resource
 func (c *Client) GatewayGet() (gwg api.GatewayGET, err error) {
 	err = c.get("/gateway", &gwg)
 	return
 }
 
+// GatewayHealthGet requests the /gateway/health api resource
+func (c *Client) GatewayHealthGet() (ghg api.GatewayHealthGET, err error) {
+	err = c.get("/gateway/health", &ghg)
+	return
+}
+
 // GatewayRateLimitPost uses the /gateway endpoint to change the gateway's
 // bandwidth rate limit. downloadSpeed and uploadSpeed are interpreted as
 // bytes/second.
@@ -58,6 +74,17 @@ func (c *Client) GatewayRateLimitPost(downloadSpeed, uploadSpeed int64) (err err
 	return
 }
 
+// GatewayConnectionLimitsPost uses the /gateway endpoint to change the limits
+// of inbound peers per subnet and autonomous system.
+func (c *Client) GatewayConnectionLimitsPost(limits modules.GatewayConnectionLimits) (err error) {
+	values := url.Values{}
+	values.Set("maxinboundpersubnet16", strconv.FormatUint(limits.MaxInboundPerSubnet16, 10))
+	values.Set("maxinboundpersubnet24", strconv.FormatUint(limits.MaxInboundPerSubnet24, 10))
+	values.Set("maxinboundperasn", strconv.FormatUint(limits.MaxInboundPerASN, 10))
+	err = c.post("/gateway", values.Encode(), nil)
+	return
+}
+
 // GatewayBlocklistGet uses the /gateway/blocklist endpoint to request the
 // Gateway's blocklist
 func (c *Client) GatewayBlocklistGet() (gbg api.GatewayBlocklistGET, err error) {
@@ -109,3 +136,42 @@ func (c *Client) GatewaySetBlocklistPost(addresses []string) (err error) {
 	err = c.post("/gateway/blocklist", string(data), nil)
 	return
 }
+
+// GatewayBansGet uses the /gateway/bans endpoint to request the Gateway's
+// bans and peer scores
+func (c *Client) GatewayBansGet() (gbg api.GatewayBansGET, err error) {
+	err = c.get("/gateway/bans", &gbg)
+	return
+}
+
+// GatewayBanPost uses the /gateway/bans endpoint to ban addresses for the
+// given duration. A duration of 0 adds the addresses to the blocklist.
+func (c *Client) GatewayBanPost(addresses []string, duration time.Duration, reason string) (err error) {
+	gbp := api.GatewayBansPOST{
+		Action:    "ban",
+		Addresses: addresses,
+		Duration:  uint64(duration.Seconds()),
+		Reason:    reason,
+	}
+	data, err := json.Marshal(gbp)
+	if err != nil {
+		return err
+	}
+	err = c.post("/gateway/bans", string(data), nil)
+	return
+}
+
+// GatewayUnbanPost uses the /gateway/bans endpoint to remove addresses from
+// the blocklist and the temporary bans
+func (c *Client) GatewayUnbanPost(addresses []string) (err error) {
+	gbp := api.GatewayBansPOST{
+		Action:    "unban",
+		Addresses: addresses,
+	}
+	data, err := json.Marshal(gbp)
+	if err != nil {
+		return err
+	}
+	err = c.post("/gateway/bans", string(data), nil)
+	return
+}
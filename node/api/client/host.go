@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/modules"
@@ -68,6 +69,81 @@ const (
 	// HostParamCustomRegistryPath is the locataion of the host's registry on
 	// disk.
 	HostParamCustomRegistryPath = HostParam("customregistrypath")
+	// HostParamAccessLogging indicates if the host logs the sector accesses
+	// of its contracts.
+	HostParamAccessLogging = HostParam("accesslogging")
+	// HostParamMaintenanceMode indicates if the host stops forming contracts
+	// and accepting new data to prepare for maintenance.
+	HostParamMaintenanceMode = HostParam("maintenancemode")
+	// HostParamDiskHealthSMART indicates if the host queries the SMART health
+	// of its storage folders' disks.
+	HostParamDiskHealthSMART = HostParam("diskhealthsmart")
+	// HostParamMinRenterFunds is the minimum amount of funds in hastings a
+	// renter needs to put into a contract.
+	HostParamMinRenterFunds = HostParam("minrenterfunds")
+	// HostParamMinCompletedContracts is the number of contracts a renter needs
+	// to have completed with the host before forming new contracts.
+	HostParamMinCompletedContracts = HostParam("mincompletedcontracts")
+	// HostParamAcceptanceWindows is a comma separated list of HH:MM-HH:MM time
+	// of day windows in UTC during which the host accepts contracts.
+	HostParamAcceptanceWindows = HostParam("acceptancewindows")
+	// HostParamMaxRenterBandwidth is the default number of bytes per second a
+	// single renter can read and write respectively.
+	HostParamMaxRenterBandwidth = HostParam("maxrenterbandwidth")
+	// HostParamMaxRenterRPCRate is the default number of RPCs per second a
+	// single renter can perform.
+	HostParamMaxRenterRPCRate = HostParam("maxrenterrpcrate")
+	// HostParamMaxRenterSessions is the default number of sessions a single
+	// renter can have open at the same time.
+	HostParamMaxRenterSessions = HostParam("maxrentersessions")
+	// HostParamMaxTrafficBandwidth is the number of bytes per second that all
+	// of the host's RPC traffic can read and write respectively.
+	HostParamMaxTrafficBandwidth = HostParam("maxtrafficbandwidth")
+	// HostParamTrafficPriorityBurst is the number of bytes latency-critical
+	// traffic can transfer in excess of the max traffic bandwidth.
+	HostParamTrafficPriorityBurst = HostParam("trafficpriorityburst")
+	// HostParamBandwidthPeriod is the length of the accounting period of the
+	// host's bandwidth caps in seconds.
+	HostParamBandwidthPeriod = HostParam("bandwidthperiod")
+	// HostParamMaxPeriodUpload is the number of bytes the host can send
+	// within an accounting period.
+	HostParamMaxPeriodUpload = HostParam("maxperiodupload")
+	// HostParamMaxPeriodDownload is the number of bytes the host can receive
+	// within an accounting period.
+	HostParamMaxPeriodDownload = HostParam("maxperioddownload")
+	// HostParamMaxRenterPeriodUpload is the default number of bytes the host
+	// can send to a single renter within an accounting period.
+	HostParamMaxRenterPeriodUpload = HostParam("maxrenterperiodupload")
+	// HostParamMaxRenterPeriodDownload is the default number of bytes the
+	// host can receive from a single renter within an accounting period.
+	HostParamMaxRenterPeriodDownload = HostParam("maxrenterperioddownload")
+	// HostParamMaxRegistryUpdateRate is the number of registry updates per
+	// second the host accepts.
+	HostParamMaxRegistryUpdateRate = HostParam("maxregistryupdaterate")
+	// HostParamDynamicPricing enables the host's pricing engine.
+	HostParamDynamicPricing = HostParam("dynamicpricing")
+	// HostParamDynamicMinStoragePrice is the lower bound of the storage price
+	// set by the pricing engine.
+	HostParamDynamicMinStoragePrice = HostParam("dynamicminstorageprice")
+	// HostParamDynamicMaxStoragePrice is the upper bound of the storage price
+	// set by the pricing engine.
+	HostParamDynamicMaxStoragePrice = HostParam("dynamicmaxstorageprice")
+	// HostParamDynamicMinDownloadBandwidthPrice is the lower bound of the
+	// download bandwidth price set by the pricing engine.
+	HostParamDynamicMinDownloadBandwidthPrice = HostParam("dynamicmindownloadbandwidthprice")
+	// HostParamDynamicMaxDownloadBandwidthPrice is the upper bound of the
+	// download bandwidth price set by the pricing engine.
+	HostParamDynamicMaxDownloadBandwidthPrice = HostParam("dynamicmaxdownloadbandwidthprice")
+	// HostParamDynamicMinUploadBandwidthPrice is the lower bound of the
+	// upload bandwidth price set by the pricing engine.
+	HostParamDynamicMinUploadBandwidthPrice = HostParam("dynamicminuploadbandwidthprice")
+	// HostParamDynamicMaxUploadBandwidthPrice is the upper bound of the
+	// upload bandwidth price set by the pricing engine.
+	HostParamDynamicMaxUploadBandwidthPrice = HostParam("dynamicmaxuploadbandwidthprice")
+	// HostParamDynamicReferenceBandwidth is the bandwidth in bytes per second
+	// at which the pricing engine charges the upper bounds of the bandwidth
+	// prices.
+	HostParamDynamicReferenceBandwidth = HostParam("dynamicreferencebandwidth")
 )
 
 // HostAnnouncePost uses the /host/announce endpoint to announce the host to
@@ -91,6 +167,54 @@ func (c *Client) HostContractInfoGet() (cg api.ContractInfoGET, err error) {
 	return
 }
 
+// HostAccessGet uses the /host/access endpoint to get the host's sector access
+// log since the given time.
+func (c *Client) HostAccessGet(since time.Time) (hag api.HostAccessGET, err error) {
+	values := url.Values{}
+	values.Set("since", strconv.FormatInt(since.Unix(), 10))
+	err = c.get("/host/access?"+values.Encode(), &hag)
+	return
+}
+
+// HostAccessContractGet uses the /host/access endpoint to get the sector
+// access log of a single contract since the given time.
+func (c *Client) HostAccessContractGet(contractID types.FileContractID, since time.Time) (hag api.HostAccessGET, err error) {
+	values := url.Values{}
+	values.Set("contractid", contractID.String())
+	values.Set("since", strconv.FormatInt(since.Unix(), 10))
+	err = c.get("/host/access?"+values.Encode(), &hag)
+	return
+}
+
+// HostContractDecisionsGet uses the /host/contractdecisions endpoint to get
+// the host's most recent decisions about incoming contract requests.
+func (c *Client) HostContractDecisionsGet(rejectedOnly bool) (hcdg api.HostContractDecisionsGET, err error) {
+	err = c.get(fmt.Sprintf("/host/contractdecisions?rejected=%v", rejectedOnly), &hcdg)
+	return
+}
+
+// HostRenterLimitPost uses the /host/renterlimits endpoint to override the
+// default limits for the renter with the given key.
+func (c *Client) HostRenterLimitPost(renterKey types.SiaPublicKey, limit modules.HostRenterLimit) (err error) {
+	values := url.Values{}
+	values.Set("renterkey", renterKey.String())
+	values.Set("maxbandwidth", fmt.Sprint(limit.MaxBandwidth))
+	values.Set("maxrpcrate", fmt.Sprint(limit.MaxRPCRate))
+	values.Set("maxsessions", fmt.Sprint(limit.MaxSessions))
+	err = c.post("/host/renterlimits", values.Encode(), nil)
+	return
+}
+
+// HostRenterLimitRemovePost uses the /host/renterlimits endpoint to remove the
+// limit override of the renter with the given key.
+func (c *Client) HostRenterLimitRemovePost(renterKey types.SiaPublicKey) (err error) {
+	values := url.Values{}
+	values.Set("renterkey", renterKey.String())
+	values.Set("remove", "true")
+	err = c.post("/host/renterlimits", values.Encode(), nil)
+	return
+}
+
 // HostContractGet uses the /host/contracts/:id endpoint to get information
 // about a contract on the host.
 func (c *Client) HostContractGet(obligationID types.FileContractID) (cg api.HostContractGET, err error) {
@@ -117,12 +241,53 @@ func (c *Client) HostModifySettingPost(param HostParam, value interface{}) (err
 	return
 }
 
+// HostModifySettingDryRunPost uses the /host endpoint to validate a change of
+// a host setting without applying it.
+func (c *Client) HostModifySettingDryRunPost(param HostParam, value interface{}) (sv modules.SettingsValidation, err error) {
+	values := url.Values{}
+	values.Set(string(param), fmt.Sprint(value))
+	values.Set("dryrun", "true")
+	err = c.post("/host", values.Encode(), &sv)
+	return
+}
+
 // HostBandwidthGet requests the /host/bandwidth api resource
-func (c *Client) HostBandwidthGet() (gbg api.GatewayBandwidthGET, err error) {
-	err = c.get("/host/bandwidth", &gbg)
+func (c *Client) HostBandwidthGet() (hbg api.HostBandwidthGET, err error) {
+	err = c.get("/host/bandwidth", &hbg)
+	return
+}
+
+// HostTrafficGet uses the /host/traffic endpoint to get the state of the
+// host's traffic prioritization.
+func (c *Client) HostTrafficGet() (hts modules.HostTrafficStats, err error) {
+	err = c.get("/host/traffic", &hts)
 	return
 }
 
+// HostRegistryGet uses the /host/registry endpoint to get the usage of the
+// host's registry.
+func (c *Client) HostRegistryGet() (hrs modules.HostRegistryStats, err error) {
+	err = c.get("/host/registry", &hrs)
+	return
+}
+
+// HostRevenueGet uses the /host/revenue endpoint to get the revenue of the
+// host's contracts per contract and per calendar month.
+func (c *Client) HostRevenueGet() (hrr modules.HostRevenueReport, err error) {
+	err = c.get("/host/revenue", &hrr)
+	return
+}
+
+// HostRevenueCSVGet uses the /host/revenue endpoint to export the revenue of
+// the host's contracts as CSV. exportType is either "contracts" or "periods".
+func (c *Client) HostRevenueCSVGet(exportType string) ([]byte, error) {
+	values := url.Values{}
+	values.Set("format", "csv")
+	values.Set("type", exportType)
+	_, csv, err := c.getRawResponse("/host/revenue?" + values.Encode())
+	return csv, err
+}
+
 // HostStorageFoldersAddPost uses the /host/storage/folders/add api endpoint to
 // add a storage folder to a host
 func (c *Client) HostStorageFoldersAddPost(path string, size uint64) (err error) {
@@ -133,6 +298,16 @@ func (c *Client) HostStorageFoldersAddPost(path string, size uint64) (err error)
 	return
 }
 
+// HostStorageFoldersDrainPost uses the /host/storage/folders/drain api
+// endpoint to mark a storage folder of a host for removal.
+func (c *Client) HostStorageFoldersDrainPost(path string, drain bool) (err error) {
+	values := url.Values{}
+	values.Set("path", path)
+	values.Set("drain", strconv.FormatBool(drain))
+	err = c.post("/host/storage/folders/drain", values.Encode(), nil)
+	return
+}
+
 // HostStorageFoldersRemovePost uses the /host/storage/folders/remove api
 // endpoint to remove a storage folder from a host.
 func (c *Client) HostStorageFoldersRemovePost(path string, force bool) (err error) {
@@ -159,6 +334,12 @@ func (c *Client) HostStorageGet() (sg api.StorageGET, err error) {
 	return
 }
 
+// HostStorageScrubGet requests the /host/storage/scrub endpoint.
+func (c *Client) HostStorageScrubGet() (hsr modules.HostScrubReport, err error) {
+	err = c.get("/host/storage/scrub", &hsr)
+	return
+}
+
 // HostStorageSectorsDeletePost uses the /host/storage/sectors/delete endpoint
 // to delete a sector from the host.
 func (c *Client) HostStorageSectorsDeletePost(root crypto.Hash) (err error) {
@@ -1,7 +1,11 @@
 package client
 
 import (
+	"net/url"
+	"strings"
+
 	"gitlab.com/NebulousLabs/encoding"
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/node/api"
 	"go.sia.tech/siad/types"
 )
@@ -46,3 +50,39 @@ func (c *Client) MinerStopGet() (err error) {
 	err = c.get("/miner/stop", nil)
 	return
 }
+
+// MinerPayoutsGet uses the /miner/payouts endpoint to get the addresses the
+// block reward is split across.
+func (c *Client) MinerPayoutsGet() (mpg api.MinerPayoutsGET, err error) {
+	err = c.get("/miner/payouts", &mpg)
+	return
+}
+
+// MinerPayoutsPost uses the /miner/payouts endpoint to set the addresses the
+// block reward is split across.
+func (c *Client) MinerPayoutsPost(splits []modules.MinerPayoutSplit) (err error) {
+	strs := make([]string, len(splits))
+	for i, split := range splits {
+		strs[i] = split.String()
+	}
+	values := url.Values{}
+	values.Set("splits", strings.Join(strs, ","))
+	err = c.post("/miner/payouts", values.Encode(), nil)
+	return
+}
+
+// MinerStratumGet uses the /miner/stratum endpoint to get statistics about the
+// stratum server.
+func (c *Client) MinerStratumGet() (msg api.MinerStratumGET, err error) {
+	err = c.get("/miner/stratum", &msg)
+	return
+}
+
+// MinerStratumPost uses the /miner/stratum endpoint to start the stratum
+// server on the given address.
+func (c *Client) MinerStratumPost(addr string) (err error) {
+	values := url.Values{}
+	values.Set("addr", addr)
+	err = c.post("/miner/stratum", values.Encode(), nil)
+	return
+}
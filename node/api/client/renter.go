@@ -1,6 +1,8 @@
 package client
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
@@ -12,6 +14,7 @@ import (
 
 	"gitlab.com/NebulousLabs/errors"
 
+	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/node/api"
 	"go.sia.tech/siad/types"
@@ -87,6 +90,24 @@ func (a *AllowanceRequestPost) WithMaxPeriodChurn(maxPeriodChurn uint64) *Allowa
 	return a
 }
 
+// WithPreferredLatency adds the preferred latency field to the request.
+func (a *AllowanceRequestPost) WithPreferredLatency(latency time.Duration) *AllowanceRequestPost {
+	a.values.Set("preferredlatency", fmt.Sprint(latency.Milliseconds()))
+	return a
+}
+
+// WithPreferredRegions adds the preferred regions field to the request.
+func (a *AllowanceRequestPost) WithPreferredRegions(regions []string) *AllowanceRequestPost {
+	a.values.Set("preferredregions", strings.Join(regions, ","))
+	return a
+}
+
+// WithMinSubnets adds the minimum subnets field to the request.
+func (a *AllowanceRequestPost) WithMinSubnets(minSubnets uint64) *AllowanceRequestPost {
+	a.values.Set("minsubnets", fmt.Sprint(minSubnets))
+	return a
+}
+
 // WithMaxRPCPrice adds the maxrpcprice field to the request.
 func (a *AllowanceRequestPost) WithMaxRPCPrice(price types.Currency) *AllowanceRequestPost {
 	a.values.Set("maxrpcprice", price.String())
@@ -133,6 +154,18 @@ func (a *AllowanceRequestPost) Send() (err error) {
 	return
 }
 
+// DryRun validates the allowance without setting it and returns the
+// analysis.
+func (a *AllowanceRequestPost) DryRun() (sv modules.SettingsValidation, err error) {
+	values := url.Values{}
+	for k, v := range a.values {
+		values[k] = v
+	}
+	values.Set("dryrun", "true")
+	err = a.c.post("/renter", values.Encode(), &sv)
+	return
+}
+
 // escapeSiaPath escapes the siapath to make it safe to use within a URL. This
 // should only be used on SiaPaths which are used as part of the URL path.
 // Paths within the query have to be escaped with url.PathEscape.
@@ -161,6 +194,31 @@ func (c *Client) RenterContractorChurnStatus() (churnStatus modules.ContractorCh
 	return
 }
 
+// RenterContractorMigrationsGet requests the /renter/contractormigrations
+// resource.
+func (c *Client) RenterContractorMigrationsGet() (plan modules.ContractorMigrationPlan, err error) {
+	err = c.get("/renter/contractormigrations", &plan)
+	return
+}
+
+// RenterContractorHostListsGet requests the /renter/contractorhostlists
+// resource.
+func (c *Client) RenterContractorHostListsGet() (lists modules.ContractorHostLists, err error) {
+	err = c.get("/renter/contractorhostlists", &lists)
+	return
+}
+
+// RenterContractorHostListsPost uses the /renter/contractorhostlists endpoint
+// to replace the contractor's host allowlist and denylist.
+func (c *Client) RenterContractorHostListsPost(lists modules.ContractorHostLists) (err error) {
+	data, err := json.Marshal(lists)
+	if err != nil {
+		return err
+	}
+	err = c.post("/renter/contractorhostlists", string(data), nil)
+	return
+}
+
 // RenterContractCancelPost uses the /renter/contract/cancel endpoint to cancel
 // a contract
 func (c *Client) RenterContractCancelPost(id types.FileContractID) (err error) {
@@ -188,6 +246,17 @@ func (c *Client) RenterContractsGet() (rc api.RenterContracts, err error) {
 	return
 }
 
+// RenterContractsSpendingHistoryGet requests the /renter/contracts resource
+// with all options and the spending history of the contracts.
+func (c *Client) RenterContractsSpendingHistoryGet() (rc api.RenterContracts, err error) {
+	values := url.Values{}
+	values.Set("disabled", fmt.Sprint(true))
+	values.Set("expired", fmt.Sprint(true))
+	values.Set("spendinghistory", fmt.Sprint(true))
+	err = c.get("/renter/contracts?"+values.Encode(), &rc)
+	return
+}
+
 // RenterContractStatus requests the /watchdog/contractstatus resource and returns
 // the status of a contract.
 func (c *Client) RenterContractStatus(fcID types.FileContractID) (status modules.ContractWatchStatus, err error) {
@@ -271,6 +340,19 @@ func (c *Client) RenterFileDeletePost(siaPath modules.SiaPath) (err error) {
 	return
 }
 
+// RenterDeletePost uses the /renter/delete endpoint to delete multiple files at
+// once. If recursive is set, directories and their contents are deleted too.
+func (c *Client) RenterDeletePost(siaPaths []modules.SiaPath, recursive, root bool) (rdp api.RenterDeletePOST, err error) {
+	values := url.Values{}
+	for _, siaPath := range siaPaths {
+		values.Add("siapath", siaPath.String())
+	}
+	values.Set("recursive", strconv.FormatBool(recursive))
+	values.Set("root", strconv.FormatBool(root))
+	err = c.post("/renter/delete", values.Encode(), &rdp)
+	return
+}
+
 // RenterDownloadGet uses the /renter/download endpoint to download a file to a
 // destination on disk.
 func (c *Client) RenterDownloadGet(siaPath modules.SiaPath, destination string, offset, length uint64, async bool, disableLocalFetch bool, root bool) (modules.DownloadID, error) {
@@ -289,6 +371,38 @@ func (c *Client) RenterDownloadGet(siaPath modules.SiaPath, destination string,
 	return modules.DownloadID(h.Get("ID")), nil
 }
 
+// RenterDownloadWithCostWeightGet uses the /renter/download endpoint to
+// download a file to a destination on disk while weighing host prices against
+// their speed according to costWeight.
+func (c *Client) RenterDownloadWithCostWeightGet(siaPath modules.SiaPath, destination string, async bool, costWeight float64) (modules.DownloadID, error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	values.Set("destination", destination)
+	values.Set("async", fmt.Sprint(async))
+	values.Set("costweight", fmt.Sprint(costWeight))
+	h, _, err := c.getRawResponse(fmt.Sprintf("/renter/download/%s?%s", sp, values.Encode()))
+	if err != nil {
+		return "", err
+	}
+	return modules.DownloadID(h.Get("ID")), nil
+}
+
+// RenterDownloadWithMaxSpeedGet uses the /renter/download endpoint to
+// download a file to a destination on disk without fetching more than maxSpeed
+// bytes per second from hosts.
+func (c *Client) RenterDownloadWithMaxSpeedGet(siaPath modules.SiaPath, destination string, async bool, maxSpeed uint64) (modules.DownloadID, error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	values.Set("destination", destination)
+	values.Set("async", fmt.Sprint(async))
+	values.Set("maxspeed", fmt.Sprint(maxSpeed))
+	h, _, err := c.getRawResponse(fmt.Sprintf("/renter/download/%s?%s", sp, values.Encode()))
+	if err != nil {
+		return "", err
+	}
+	return modules.DownloadID(h.Get("ID")), nil
+}
+
 // RenterDownloadInfoGet uses the /renter/downloadinfo endpoint to fetch
 // information about a download from the history.
 func (c *Client) RenterDownloadInfoGet(uid modules.DownloadID) (di api.DownloadInfo, err error) {
@@ -328,6 +442,41 @@ func (c *Client) RenterRecoverBackupPost(name string) (err error) {
 	return
 }
 
+// RenterBackupsPrunePost uses the /renter/backups/prune endpoint to prune the
+// specified backup.
+func (c *Client) RenterBackupsPrunePost(name string) (err error) {
+	values := url.Values{}
+	values.Set("name", name)
+	err = c.post("/renter/backups/prune", values.Encode(), nil)
+	return
+}
+
+// RenterBackupScheduleGet uses the /renter/backups/schedule endpoint to get
+// the settings of the renter's scheduled backups and the scheduled backups
+// the renter knows about.
+func (c *Client) RenterBackupScheduleGet() (bsg api.RenterBackupScheduleGET, err error) {
+	err = c.get("/renter/backups/schedule", &bsg)
+	return
+}
+
+// RenterBackupSchedulePost uses the /renter/backups/schedule endpoint to
+// update the settings of the renter's scheduled backups.
+func (c *Client) RenterBackupSchedulePost(enabled bool, interval time.Duration, generations uint64) (err error) {
+	values := url.Values{}
+	values.Set("enabled", strconv.FormatBool(enabled))
+	values.Set("interval", fmt.Sprint(uint64(math.Round(interval.Seconds()))))
+	values.Set("generations", fmt.Sprint(generations))
+	err = c.post("/renter/backups/schedule", values.Encode(), nil)
+	return
+}
+
+// RenterBackupScheduleRunPost uses the /renter/backups/schedule/run endpoint
+// to create a scheduled backup right away.
+func (c *Client) RenterBackupScheduleRunPost() (ub api.RenterUploadedBackup, err error) {
+	err = c.post("/renter/backups/schedule/run", "", &ub)
+	return
+}
+
 // RenterCreateLocalBackupPost creates a local backup of the SiaFiles of the
 // renter.
 //
@@ -406,6 +555,18 @@ func (c *Client) RenterDownloadsGet() (rdq api.RenterDownloadQueue, err error) {
 	return
 }
 
+// RenterDownloadOverdrivePost uses the /renter/downloads/overdrive endpoint to
+// set the overdrive policy of a download class.
+func (c *Client) RenterDownloadOverdrivePost(class modules.DownloadRequestClass, policy modules.DownloadOverdrivePolicy) (err error) {
+	values := url.Values{}
+	values.Set("class", string(class))
+	values.Set("baseworkers", fmt.Sprint(policy.BaseWorkers))
+	values.Set("hedgedelay", fmt.Sprint(policy.HedgeDelay.Milliseconds()))
+	values.Set("maxextrapieces", fmt.Sprint(policy.MaxExtraPieces))
+	err = c.post("/renter/downloads/overdrive", values.Encode(), nil)
+	return
+}
+
 // RenterDownloadsRootGet requests the /renter/downloads resource with the root
 // flag set.
 func (c *Client) RenterDownloadsRootGet() (rdq api.RenterDownloadQueue, err error) {
@@ -438,6 +599,39 @@ func (c *Client) RenterFileRootGet(siaPath modules.SiaPath) (rf api.RenterFile,
 	return
 }
 
+// RenterFileKeysGet uses the /renter/filekeys/:siapath endpoint to export the
+// encryption keys and the erasure coding layout of a file.
+func (c *Client) RenterFileKeysGet(siaPath modules.SiaPath, root bool) (fk modules.FileKeys, err error) {
+	sp := escapeSiaPath(siaPath)
+	err = c.get(fmt.Sprintf("/renter/filekeys/%s?root=%v", sp, root), &fk)
+	return
+}
+
+// RenterShareFilePost uses the /renter/sharefile/:siapath endpoint to export a
+// file together with its keys into a shared file at dst, encrypted with the
+// password.
+func (c *Client) RenterShareFilePost(siaPath modules.SiaPath, dst, password string, root bool) (err error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	values.Set("destination", dst)
+	values.Set("password", password)
+	values.Set("root", fmt.Sprint(root))
+	err = c.post("/renter/sharefile/"+sp, values.Encode(), nil)
+	return
+}
+
+// RenterLoadSharedFilePost uses the /renter/loadsharedfile/:siapath endpoint
+// to load the shared file at src into the renter at siaPath.
+func (c *Client) RenterLoadSharedFilePost(src string, siaPath modules.SiaPath, password string, root bool) (err error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	values.Set("source", src)
+	values.Set("password", password)
+	values.Set("root", fmt.Sprint(root))
+	err = c.post("/renter/loadsharedfile/"+sp, values.Encode(), nil)
+	return
+}
+
 // RenterFileGet uses the /renter/file/:siapath endpoint to query a file.
 func (c *Client) RenterFileGet(siaPath modules.SiaPath) (rf api.RenterFile, err error) {
 	sp := escapeSiaPath(siaPath)
@@ -451,6 +645,19 @@ func (c *Client) RenterFilesGet(cached bool) (rf api.RenterFiles, err error) {
 	return
 }
 
+// RenterFilesTaggedGet requests the /renter/files resource and only returns
+// the files matching all of the tag filters. A filter is either a tag key or of
+// the form key=value.
+func (c *Client) RenterFilesTaggedGet(cached bool, tagFilters ...string) (rf api.RenterFiles, err error) {
+	values := url.Values{}
+	values.Set("cached", fmt.Sprint(cached))
+	for _, filter := range tagFilters {
+		values.Add("tag", filter)
+	}
+	err = c.get("/renter/files?"+values.Encode(), &rf)
+	return
+}
+
 // RenterGet requests the /renter resource.
 func (c *Client) RenterGet() (rg api.RenterGET, err error) {
 	err = c.get("/renter", &rg)
@@ -497,6 +704,39 @@ func (c *Client) RenterRateLimitPost(readBPS, writeBPS int64) (err error) {
 	return
 }
 
+// RenterReencodePost uses the /renter/reencode/:siapath endpoint to start
+// converting a file to new erasure code parameters in the background. If root
+// is set, the siapath is treated as an absolute path.
+func (c *Client) RenterReencodePost(siaPath modules.SiaPath, dataPieces, parityPieces uint64, root bool) (frs modules.FileReencodeStatus, err error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	values.Set("datapieces", fmt.Sprint(dataPieces))
+	values.Set("paritypieces", fmt.Sprint(parityPieces))
+	values.Set("root", fmt.Sprint(root))
+	err = c.post(fmt.Sprintf("/renter/reencode/%s", sp), values.Encode(), &frs)
+	return
+}
+
+// RenterReencodesGet uses the /renter/reencodes endpoint to query the status
+// of the renter's recent file re-encodes. If root is set, the siapaths are
+// returned relative to the root directory.
+func (c *Client) RenterReencodesGet(root bool) (rfr api.RenterFileReencodes, err error) {
+	err = c.get(fmt.Sprintf("/renter/reencodes?root=%v", root), &rfr)
+	return
+}
+
+// RenterVerifyPost uses the /renter/verify/:siapath endpoint to verify a random
+// sample of a file's pieces. If sample is 0, all pieces are verified. If root
+// is set, the siapath is treated as an absolute path.
+func (c *Client) RenterVerifyPost(siaPath modules.SiaPath, sample uint64, root bool) (fv modules.FileVerification, err error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	values.Set("sample", fmt.Sprint(sample))
+	values.Set("root", fmt.Sprint(root))
+	err = c.post(fmt.Sprintf("/renter/verify/%s", sp), values.Encode(), &fv)
+	return
+}
+
 // RenterRenamePost uses the /renter/rename/:siapath endpoint to rename a file.
 func (c *Client) RenterRenamePost(siaPathOld, siaPathNew modules.SiaPath, root bool) (err error) {
 	spo := escapeSiaPath(siaPathOld)
@@ -536,6 +776,19 @@ func (c *Client) RenterStreamGet(siaPath modules.SiaPath, disableLocalFetch, roo
 	return
 }
 
+// RenterDownloadsBatchGet uses the /renter/downloads/batch endpoint to download
+// multiple files and directories as a tar archive.
+func (c *Client) RenterDownloadsBatchGet(siaPaths []modules.SiaPath, disableLocalFetch, root bool) (resp []byte, err error) {
+	values := url.Values{}
+	for _, siaPath := range siaPaths {
+		values.Add("siapath", siaPath.String())
+	}
+	values.Set("disablelocalfetch", fmt.Sprint(disableLocalFetch))
+	values.Set("root", fmt.Sprint(root))
+	_, resp, err = c.getRawResponse("/renter/downloads/batch?" + values.Encode())
+	return
+}
+
 // RenterStreamPartialGet uses the /renter/stream endpoint to download a part
 // of data as a stream.
 func (c *Client) RenterStreamPartialGet(siaPath modules.SiaPath, start, end uint64, disableLocalFetch, root bool) (resp []byte, err error) {
@@ -547,6 +800,20 @@ func (c *Client) RenterStreamPartialGet(siaPath modules.SiaPath, start, end uint
 	return
 }
 
+// RenterStreamRangeGet uses the /renter/stream endpoint to stream length bytes
+// of a file starting at offset. Only the chunks covering the range are
+// downloaded.
+func (c *Client) RenterStreamRangeGet(siaPath modules.SiaPath, offset, length uint64, disableLocalFetch, root bool) (resp []byte, err error) {
+	values := url.Values{}
+	values.Set("disablelocalfetch", fmt.Sprint(disableLocalFetch))
+	values.Set("root", fmt.Sprint(root))
+	values.Set("offset", fmt.Sprint(offset))
+	values.Set("length", fmt.Sprint(length))
+	sp := escapeSiaPath(siaPath)
+	_, resp, err = c.getRawResponse(fmt.Sprintf("/renter/stream/%s?%s", sp, values.Encode()))
+	return
+}
+
 // RenterSetRepairPathPost uses the /renter/tracking endpoint to set the repair
 // path of a file to a new location. The file at newPath must exists.
 func (c *Client) RenterSetRepairPathPost(siaPath modules.SiaPath, newPath string) (err error) {
@@ -568,6 +835,22 @@ func (c *Client) RenterSetFileStuckPost(siaPath modules.SiaPath, root, stuck boo
 	return
 }
 
+// RenterSetFileTagsPost replaces the tags of the siafile at siaPath. Passing
+// no tags removes all of the file's tags.
+func (c *Client) RenterSetFileTagsPost(siaPath modules.SiaPath, root bool, tags map[string]string) (err error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	for key, value := range tags {
+		values.Add("tag", key+"="+value)
+	}
+	if len(tags) == 0 {
+		values.Set("cleartags", "true")
+	}
+	values.Set("root", fmt.Sprint(root))
+	err = c.post(fmt.Sprintf("/renter/file/%v", sp), values.Encode(), nil)
+	return
+}
+
 // RenterUploadPost uses the /renter/upload endpoint to upload a file
 func (c *Client) RenterUploadPost(path string, siaPath modules.SiaPath, dataPieces, parityPieces uint64) (err error) {
 	return c.RenterUploadForcePost(path, siaPath, dataPieces, parityPieces, false)
@@ -620,6 +903,51 @@ func (c *Client) RenterUploadStreamRepairPost(r io.Reader, siaPath modules.SiaPa
 	return err
 }
 
+// RenterUploadSessionsGet returns the renter's unfinished upload sessions.
+func (c *Client) RenterUploadSessionsGet() (rusg api.RenterUploadSessionsGET, err error) {
+	err = c.get("/renter/uploadsessions", &rusg)
+	return
+}
+
+// RenterUploadSessionsPost creates a new resumable upload session for the
+// given siapath.
+func (c *Client) RenterUploadSessionsPost(siaPath modules.SiaPath, dataPieces, parityPieces uint64, force bool) (session modules.UploadSession, err error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	values.Set("datapieces", strconv.FormatUint(dataPieces, 10))
+	values.Set("paritypieces", strconv.FormatUint(parityPieces, 10))
+	values.Set("force", strconv.FormatBool(force))
+	err = c.post(fmt.Sprintf("/renter/uploadsessions/%s", sp), values.Encode(), &session)
+	return
+}
+
+// RenterUploadSessionGet returns the upload session with the given id.
+func (c *Client) RenterUploadSessionGet(id modules.UploadSessionID) (session modules.UploadSession, err error) {
+	err = c.get(fmt.Sprintf("/renter/uploadsession/%s", id), &session)
+	return
+}
+
+// RenterUploadSessionDataPost uploads the data of r to an upload session,
+// starting at the given offset. The last upload of a session needs to be
+// final.
+func (c *Client) RenterUploadSessionDataPost(r io.Reader, id modules.UploadSessionID, offset uint64, final bool) (session modules.UploadSession, err error) {
+	values := url.Values{}
+	values.Set("offset", strconv.FormatUint(offset, 10))
+	values.Set("final", strconv.FormatBool(final))
+	_, resp, err := c.postRawResponse(fmt.Sprintf("/renter/uploadsession/%s?%s", id, values.Encode()), r)
+	if err != nil {
+		return modules.UploadSession{}, err
+	}
+	err = json.Unmarshal(resp, &session)
+	return
+}
+
+// RenterUploadSessionAbortPost aborts an upload session.
+func (c *Client) RenterUploadSessionAbortPost(id modules.UploadSessionID) (err error) {
+	err = c.post(fmt.Sprintf("/renter/uploadsession/%s/abort", id), "", nil)
+	return
+}
+
 // RenterDirCreatePost uses the /renter/dir/ endpoint to create a directory for the
 // renter
 func (c *Client) RenterDirCreatePost(siaPath modules.SiaPath) (err error) {
@@ -652,6 +980,32 @@ func (c *Client) RenterDirDeletePost(siaPath modules.SiaPath) (err error) {
 	return
 }
 
+// RenterDirDeleteAsyncPost uses the /renter/dir/ endpoint to delete a
+// directory for the renter in the background. If root is set, the siapath is
+// treated as an absolute path.
+func (c *Client) RenterDirDeleteAsyncPost(siaPath modules.SiaPath, root bool) (dds modules.DirDeletionStatus, err error) {
+	sp := escapeSiaPath(siaPath)
+	err = c.post(fmt.Sprintf("/renter/dir/%s?async=true&root=%v", sp, root), "action=delete", &dds)
+	return
+}
+
+// RenterDirDeleteDryRunPost uses the /renter/dir/ endpoint to find out how
+// many files and bytes deleting a directory would remove. If root is set, the
+// siapath is treated as an absolute path.
+func (c *Client) RenterDirDeleteDryRunPost(siaPath modules.SiaPath, root bool) (dds modules.DirDeletionStatus, err error) {
+	sp := escapeSiaPath(siaPath)
+	err = c.post(fmt.Sprintf("/renter/dir/%s?dryrun=true&root=%v", sp, root), "action=delete", &dds)
+	return
+}
+
+// RenterDirDeletionsGet uses the /renter/dirdeletions endpoint to query the
+// status of the renter's recent background directory deletions. If root is
+// set, the siapaths are returned relative to the root directory.
+func (c *Client) RenterDirDeletionsGet(root bool) (rdd api.RenterDirDeletions, err error) {
+	err = c.get(fmt.Sprintf("/renter/dirdeletions?root=%v", root), &rdd)
+	return
+}
+
 // RenterDirRenamePost uses the /renter/dir/ endpoint to rename a directory for the
 // renter
 func (c *Client) RenterDirRenamePost(siaPath, newSiaPath modules.SiaPath) (err error) {
@@ -676,6 +1030,167 @@ func (c *Client) RenterDirGet(siaPath modules.SiaPath) (rd api.RenterDirectory,
 	return
 }
 
+// RenterDirsHealthGet uses the /renter/dirs/health endpoint to query the
+// aggregated health of a directory's subtree and the subtrees of its
+// subdirectories.
+func (c *Client) RenterDirsHealthGet(siaPath modules.SiaPath, recursive, root bool) (rdh api.RenterDirsHealthGET, err error) {
+	sp := escapeSiaPath(siaPath)
+	err = c.get(fmt.Sprintf("/renter/dirs/health/%s?recursive=%v&root=%v", sp, recursive, root), &rdh)
+	return
+}
+
+// RenterEventsGet uses the /renter/events endpoint to wait for events of the
+// renter's event stream that occurred after the event with the provided ID.
+// The request returns without events once the timeout expires. If root is
+// set, the siapaths are relative to the root directory.
+func (c *Client) RenterEventsGet(since uint64, timeout time.Duration, root bool) (reg api.RenterEventsGET, err error) {
+	values := url.Values{}
+	values.Set("since", fmt.Sprint(since))
+	values.Set("timeout", fmt.Sprint(uint64(timeout.Seconds())))
+	values.Set("root", fmt.Sprint(root))
+	err = c.get("/renter/events?"+values.Encode(), &reg)
+	return
+}
+
+// RenterSpendingGet uses the /renter/spending endpoint to request the renter's
+// spending within the period that starts at the provided height.
+func (c *Client) RenterSpendingGet(period types.BlockHeight) (rs modules.RenterSpending, err error) {
+	values := url.Values{}
+	values.Set("period", fmt.Sprint(period))
+	err = c.get("/renter/spending?"+values.Encode(), &rs)
+	return
+}
+
+// RenterCurrentSpendingGet uses the /renter/spending endpoint to request the
+// renter's spending within the current period.
+func (c *Client) RenterCurrentSpendingGet() (rs modules.RenterSpending, err error) {
+	err = c.get("/renter/spending", &rs)
+	return
+}
+
+// RenterChunkCacheGet uses the /renter/chunkcache endpoint to query statistics
+// about the renter's on-disk cache of downloaded chunks.
+func (c *Client) RenterChunkCacheGet() (ccs modules.ChunkCacheStats, err error) {
+	err = c.get("/renter/chunkcache", &ccs)
+	return
+}
+
+// RenterChunkCacheSizePost uses the /renter endpoint to change the size limit
+// of the renter's chunk cache. A size of 0 disables the cache.
+func (c *Client) RenterChunkCacheSizePost(size uint64) (err error) {
+	values := url.Values{}
+	values.Set("chunkcachesize", strconv.FormatUint(size, 10))
+	err = c.post("/renter", values.Encode(), nil)
+	return
+}
+
+// RenterReadOnlyPost uses the /renter endpoint to enable or disable the
+// renter's read-only mode.
+func (c *Client) RenterReadOnlyPost(readOnly bool) (err error) {
+	values := url.Values{}
+	values.Set("readonly", strconv.FormatBool(readOnly))
+	err = c.post("/renter", values.Encode(), nil)
+	return
+}
+
+// RenterAccountsGet uses the /renter/accounts endpoint to query the account
+// funding policy and the money lost to hosts resetting the renter's ephemeral
+// accounts.
+func (c *Client) RenterAccountsGet() (afr modules.AccountFundingReport, err error) {
+	err = c.get("/renter/accounts", &afr)
+	return
+}
+
+// RenterAccountsPolicyPost uses the /renter/accounts/policy endpoint to set
+// the account funding policy of a host. A zero policy removes the host's
+// policy.
+func (c *Client) RenterAccountsPolicyPost(hostKey types.SiaPublicKey, policy modules.AccountFundingPolicy) (err error) {
+	values := url.Values{}
+	values.Set("hostkey", hostKey.String())
+	accountFundingPolicyValues(values, "", policy)
+	err = c.post("/renter/accounts/policy", values.Encode(), nil)
+	return
+}
+
+// RenterAccountFundingPost uses the /renter endpoint to set the renter-wide
+// account funding policy. Zero fields of the policy are left unchanged.
+func (c *Client) RenterAccountFundingPost(policy modules.AccountFundingPolicy) (err error) {
+	values := url.Values{}
+	accountFundingPolicyValues(values, "account", policy)
+	err = c.post("/renter", values.Encode(), nil)
+	return
+}
+
+// RenterRepairSchedulePost uses the /renter endpoint to set the renter's repair
+// windows and the bandwidth budget of a window.
+func (c *Client) RenterRepairSchedulePost(schedule modules.RepairSchedule) (err error) {
+	windows := make([]string, 0, len(schedule.Windows))
+	for _, rw := range schedule.Windows {
+		windows = append(windows, rw.String())
+	}
+	values := url.Values{}
+	values.Set("repairwindows", strings.Join(windows, ","))
+	values.Set("repairbandwidthbudget", fmt.Sprint(schedule.BandwidthBudget))
+	err = c.post("/renter", values.Encode(), nil)
+	return
+}
+
+// accountFundingPolicyValues adds the non-zero fields of an account funding
+// policy to the values. The names of the fields are prefixed with the given
+// prefix.
+func accountFundingPolicyValues(values url.Values, prefix string, policy modules.AccountFundingPolicy) {
+	if !policy.BalanceTarget.IsZero() {
+		values.Set(prefix+"balancetarget", policy.BalanceTarget.String())
+	}
+	if !policy.RefillThreshold.IsZero() {
+		values.Set(prefix+"refillthreshold", policy.RefillThreshold.String())
+	}
+	if !policy.MaxDriftTolerance.IsZero() {
+		values.Set(prefix+"maxdrifttolerance", policy.MaxDriftTolerance.String())
+	}
+}
+
+// RenterArchiveGet uses the /renter/archive endpoint to query the files and
+// directories of the archive tier.
+func (c *Client) RenterArchiveGet(root bool) (rag api.RenterArchiveGET, err error) {
+	err = c.get(fmt.Sprintf("/renter/archive?root=%v", root), &rag)
+	return
+}
+
+// RenterArchivePost uses the /renter/archive endpoint to move a file or
+// directory to the archive tier.
+func (c *Client) RenterArchivePost(siaPath modules.SiaPath, root bool) (err error) {
+	sp := escapeSiaPath(siaPath)
+	err = c.post(fmt.Sprintf("/renter/archive/%s", sp), fmt.Sprintf("root=%v", root), nil)
+	return
+}
+
+// RenterThawPost uses the /renter/thaw endpoint to move an archived file or
+// directory back to actively maintained status.
+func (c *Client) RenterThawPost(siaPath modules.SiaPath, root bool) (err error) {
+	sp := escapeSiaPath(siaPath)
+	err = c.post(fmt.Sprintf("/renter/thaw/%s", sp), fmt.Sprintf("root=%v", root), nil)
+	return
+}
+
+// RenterDirsPinnedGet uses the /renter/dirs/pinned endpoint to query the
+// pinned directories.
+func (c *Client) RenterDirsPinnedGet(root bool) (rdp api.RenterDirsPinnedGET, err error) {
+	err = c.get(fmt.Sprintf("/renter/dirs/pinned?root=%v", root), &rdp)
+	return
+}
+
+// RenterDirPinnedPost uses the /renter/dirs/pinned endpoint to pin or unpin a
+// directory.
+func (c *Client) RenterDirPinnedPost(siaPath modules.SiaPath, pinned, root bool) (err error) {
+	sp := escapeSiaPath(siaPath)
+	values := url.Values{}
+	values.Set("pinned", fmt.Sprint(pinned))
+	values.Set("root", fmt.Sprint(root))
+	err = c.post(fmt.Sprintf("/renter/dirs/pinned/%s", sp), values.Encode(), nil)
+	return
+}
+
 // RenterValidateSiaPathPost uses the /renter/validatesiapath endpoint to
 // validate a potential siapath
 //
@@ -742,6 +1257,75 @@ func (c *Client) RenterUploadsPausePost(duration time.Duration) (err error) {
 	return
 }
 
+// RenterRegistryGet uses the /renter/registry endpoint to read a value from the
+// hosts' registries.
+func (c *Client) RenterRegistryGet(spk types.SiaPublicKey, dataKey crypto.Hash, timeout time.Duration) (modules.SignedRegistryValue, error) {
+	values := url.Values{}
+	values.Set("publickey", spk.String())
+	values.Set("datakey", dataKey.String())
+	values.Set("timeout", fmt.Sprint(int(timeout.Seconds())))
+	var rrg api.RenterRegistryGET
+	err := c.get("/renter/registry?"+values.Encode(), &rrg)
+	if err != nil {
+		return modules.SignedRegistryValue{}, err
+	}
+	data, err := hex.DecodeString(rrg.Data)
+	if err != nil {
+		return modules.SignedRegistryValue{}, errors.AddContext(err, "failed to decode data")
+	}
+	sig, err := hex.DecodeString(rrg.Signature)
+	if err != nil {
+		return modules.SignedRegistryValue{}, errors.AddContext(err, "failed to decode signature")
+	}
+	var signature crypto.Signature
+	if len(sig) != len(signature) {
+		return modules.SignedRegistryValue{}, errors.New("signature has the wrong length")
+	}
+	copy(signature[:], sig)
+	return modules.NewSignedRegistryValue(dataKey, data, rrg.Revision, signature, rrg.Type), nil
+}
+
+// RenterRegistryPost uses the /renter/registry endpoint to update a value in
+// the hosts' registries.
+func (c *Client) RenterRegistryPost(spk types.SiaPublicKey, srv modules.SignedRegistryValue) error {
+	data, err := json.Marshal(api.RenterRegistryPOST{
+		PublicKey: spk,
+		DataKey:   srv.Tweak,
+		Revision:  srv.Revision,
+		Signature: srv.Signature,
+		Data:      srv.Data,
+		Type:      srv.Type,
+	})
+	if err != nil {
+		return err
+	}
+	return c.post("/renter/registry", string(data), nil)
+}
+
+// RenterRestoreDrillsGet uses the /renter/restoredrills endpoint to get the
+// settings and recent results of the renter's restore drills.
+func (c *Client) RenterRestoreDrillsGet() (rds modules.RestoreDrillStatus, err error) {
+	err = c.get("/renter/restoredrills", &rds)
+	return
+}
+
+// RenterRestoreDrillsPost uses the /renter/restoredrills endpoint to update the
+// settings of the renter's restore drills.
+func (c *Client) RenterRestoreDrillsPost(enabled bool, interval time.Duration) (err error) {
+	values := url.Values{}
+	values.Set("enabled", strconv.FormatBool(enabled))
+	values.Set("interval", fmt.Sprint(uint64(math.Round(interval.Seconds()))))
+	err = c.post("/renter/restoredrills", values.Encode(), nil)
+	return
+}
+
+// RenterRestoreDrillsRunPost uses the /renter/restoredrills/run endpoint to run
+// a restore drill right away.
+func (c *Client) RenterRestoreDrillsRunPost() (rdr modules.RestoreDrillResult, err error) {
+	err = c.post("/renter/restoredrills/run", "", &rdr)
+	return
+}
+
 // RenterUploadsResumePost uses the /renter/uploads/resume endpoint to resume
 // the renter's uploads and repairs
 func (c *Client) RenterUploadsResumePost() (err error) {
@@ -763,6 +1347,32 @@ func (c *Client) RenterWorkersGet() (wps modules.WorkerPoolStatus, err error) {
 	return
 }
 
+// RenterWorkerJobHistoryGet requests the /renter/workers/history resource to
+// get the renter's failed worker jobs matching the filter.
+func (c *Client) RenterWorkerJobHistoryGet(filter modules.WorkerJobFailureFilter) (wjh api.RenterWorkerJobHistoryGET, err error) {
+	values := url.Values{}
+	if len(filter.HostPubKey.Key) != 0 {
+		values.Set("host", filter.HostPubKey.String())
+	}
+	if filter.JobType != "" {
+		values.Set("jobtype", string(filter.JobType))
+	}
+	if filter.ErrorCode != "" {
+		values.Set("errorcode", string(filter.ErrorCode))
+	}
+	if !filter.Since.IsZero() {
+		values.Set("since", strconv.FormatInt(filter.Since.Unix(), 10))
+	}
+	if !filter.Until.IsZero() {
+		values.Set("until", strconv.FormatInt(filter.Until.Unix(), 10))
+	}
+	if filter.Limit > 0 {
+		values.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	err = c.get("/renter/workers/history?"+values.Encode(), &wjh)
+	return
+}
+
 // RenterBubblePost uses the /renter/bubble endpoint to manually trigger an
 // update to the directories metadata.
 func (c *Client) RenterBubblePost(siaPath modules.SiaPath, force, recursive bool) (err error) {
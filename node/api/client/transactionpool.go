@@ -2,6 +2,7 @@ package client
 
 import (
 	"encoding/base64"
+	"fmt"
 	"net/url"
 
 	"gitlab.com/NebulousLabs/encoding"
@@ -9,12 +10,36 @@ import (
 	"go.sia.tech/siad/types"
 )
 
+// TransactionPoolContentsGet uses the /tpool/contents endpoint to get a page
+// of the transaction sets of the tpool, sorted by fee rate in descending
+// order.
+func (c *Client) TransactionPoolContentsGet(offset, limit uint64) (tcg api.TpoolContentsGET, err error) {
+	err = c.get(fmt.Sprintf("/tpool/contents?offset=%v&limit=%v", offset, limit), &tcg)
+	return
+}
+
+// TransactionPoolMaxSizePost uses the /tpool/maxsize endpoint to set the
+// maximum size of the tpool in bytes.
+func (c *Client) TransactionPoolMaxSizePost(size uint64) (err error) {
+	values := url.Values{}
+	values.Set("size", fmt.Sprint(size))
+	err = c.post("/tpool/maxsize", values.Encode(), nil)
+	return
+}
+
 // TransactionPoolFeeGet uses the /tpool/fee endpoint to get a fee estimation.
 func (c *Client) TransactionPoolFeeGet() (tfg api.TpoolFeeGET, err error) {
 	err = c.get("/tpool/fee", &tfg)
 	return
 }
 
+// TransactionPoolFeeEstimateGet uses the /tpool/feeestimate endpoint to get the
+// fee estimations for different confirmation targets.
+func (c *Client) TransactionPoolFeeEstimateGet() (tfeg api.TpoolFeeEstimateGET, err error) {
+	err = c.get("/tpool/feeestimate", &tfeg)
+	return
+}
+
 // TransactionPoolRawPost uses the /tpool/raw endpoint to send a raw
 // transaction to the transaction pool.
 func (c *Client) TransactionPoolRawPost(txn types.Transaction, parents []types.Transaction) (err error) {
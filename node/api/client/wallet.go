@@ -12,8 +12,42 @@ import (
 	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/node/api"
 	"go.sia.tech/siad/types"
+	"golang.org/x/net/websocket"
 )
 
+// WalletAddressBookGet requests the /wallet/addressbook endpoint and returns
+// the named addresses of the wallet's address book.
+func (c *Client) WalletAddressBookGet() (wabg api.WalletAddressBookGET, err error) {
+	err = c.get("/wallet/addressbook", &wabg)
+	return
+}
+
+// WalletAddressBookAddPost uses the /wallet/addressbook endpoint to add a
+// named address to the address book or to change the address of a name.
+func (c *Client) WalletAddressBookAddPost(name string, addr types.UnlockHash) error {
+	json, err := json.Marshal(api.WalletAddressBookPOST{
+		Name:    name,
+		Address: addr,
+	})
+	if err != nil {
+		return err
+	}
+	return c.post("/wallet/addressbook", string(json), nil)
+}
+
+// WalletAddressBookRemovePost uses the /wallet/addressbook endpoint to remove
+// a named address from the address book.
+func (c *Client) WalletAddressBookRemovePost(name string) error {
+	json, err := json.Marshal(api.WalletAddressBookPOST{
+		Name:   name,
+		Remove: true,
+	})
+	if err != nil {
+		return err
+	}
+	return c.post("/wallet/addressbook", string(json), nil)
+}
+
 // WalletAddressGet requests a new address from the /wallet/address endpoint
 func (c *Client) WalletAddressGet() (wag api.WalletAddressGET, err error) {
 	err = c.get("/wallet/address", &wag)
@@ -47,6 +81,71 @@ func (c *Client) WalletChangePasswordWithSeedPost(seed modules.Seed, newPassword
 	return c.WalletChangePasswordPost(seedStr, newPassword)
 }
 
+// WalletLimitsGet uses the /wallet/limits endpoint to get the wallet's
+// spending limits.
+func (c *Client) WalletLimitsGet() (wlg api.WalletLimitsGET, err error) {
+	err = c.get("/wallet/limits", &wlg)
+	return
+}
+
+// WalletLimitsPost uses the /wallet/limits endpoint to set the wallet's
+// spending limits.
+func (c *Client) WalletLimitsPost(limits modules.WalletSpendingLimits) (err error) {
+	values := url.Values{}
+	values.Set("dailylimit", limits.DailyLimit.String())
+	values.Set("approvalthreshold", limits.ApprovalThreshold.String())
+	values.Set("approvalwebhook", limits.ApprovalWebhook)
+	err = c.post("/wallet/limits", values.Encode(), nil)
+	return
+}
+
+// WalletPendingGet uses the /wallet/pending endpoint to get the sends which
+// are waiting for approval.
+func (c *Client) WalletPendingGet() (wpg api.WalletPendingGET, err error) {
+	err = c.get("/wallet/pending", &wpg)
+	return
+}
+
+// WalletStuckGet requests the /wallet/stuck endpoint to get the transaction
+// sets broadcast by the wallet which are stuck.
+func (c *Client) WalletStuckGet() (wsg api.WalletStuckGET, err error) {
+	err = c.get("/wallet/stuck", &wsg)
+	return
+}
+
+// WalletPendingApprovePost uses the /wallet/pending/:id/approve endpoint to
+// approve a pending send. The label is given to the transaction sending the
+// coins if it isn't empty.
+func (c *Client) WalletPendingApprovePost(id, label string) (wsp api.WalletSiacoinsPOST, err error) {
+	values := url.Values{}
+	values.Set("label", label)
+	err = c.post(fmt.Sprintf("/wallet/pending/%s/approve", id), values.Encode(), &wsp)
+	return
+}
+
+// WalletPendingRejectPost uses the /wallet/pending/:id/reject endpoint to
+// reject a pending send.
+func (c *Client) WalletPendingRejectPost(id string) (err error) {
+	err = c.post(fmt.Sprintf("/wallet/pending/%s/reject", id), "", nil)
+	return
+}
+
+// WalletRescanGet uses the /wallet/rescan endpoint to get the progress of the
+// wallet's rescan of the blockchain.
+func (c *Client) WalletRescanGet() (wrg api.WalletRescanGET, err error) {
+	err = c.get("/wallet/rescan", &wrg)
+	return
+}
+
+// WalletRescanPost uses the /wallet/rescan endpoint to start a rescan of the
+// blockchain. A gap limit of zero keeps the wallet's current gap limit.
+func (c *Client) WalletRescanPost(gapLimit uint64) (err error) {
+	values := url.Values{}
+	values.Set("gaplimit", strconv.FormatUint(gapLimit, 10))
+	err = c.post("/wallet/rescan", values.Encode(), nil)
+	return
+}
+
 // WalletVerifyPasswordGet uses the /wallet/verifypassword endpoint to check
 // the wallet's password.
 func (c *Client) WalletVerifyPasswordGet(password string) (wvpg api.WalletVerifyPasswordGET, err error) {
@@ -70,6 +169,29 @@ func (c *Client) WalletVerifyPasswordSeedGet(seed modules.Seed) (wvpg api.Wallet
 	return
 }
 
+// WalletEventsSubscribe connects to the /wallet/events WebSocket and calls fn
+// for every event of the wallet's event stream that occurs after the event
+// with the provided ID. It returns when fn returns an error or the connection
+// is closed.
+func (c *Client) WalletEventsSubscribe(since uint64, fn func(modules.WalletEvent) error) error {
+	values := url.Values{}
+	values.Set("since", fmt.Sprint(since))
+	conn, err := c.dialWebSocket("/wallet/events?" + values.Encode())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for {
+		var e modules.WalletEvent
+		if err := websocket.JSON.Receive(conn, &e); err != nil {
+			return errors.AddContext(err, "failed to receive wallet event")
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
+
 // WalletInitPost uses the /wallet/init endpoint to initialize and encrypt a
 // wallet
 func (c *Client) WalletInitPost(password string, force bool) (wip api.WalletInitPOST, err error) {
@@ -80,6 +202,17 @@ func (c *Client) WalletInitPost(password string, force bool) (wip api.WalletInit
 	return
 }
 
+// WalletInitDictionaryPost uses the /wallet/init endpoint to initialize and
+// encrypt a wallet. The seed is returned encoded with the dictionary.
+func (c *Client) WalletInitDictionaryPost(password string, dictionary mnemonics.DictionaryID, force bool) (wip api.WalletInitPOST, err error) {
+	values := url.Values{}
+	values.Set("encryptionpassword", password)
+	values.Set("dictionary", string(dictionary))
+	values.Set("force", strconv.FormatBool(force))
+	err = c.post("/wallet/init", values.Encode(), &wip)
+	return
+}
+
 // WalletInitSeedPost uses the /wallet/init/seed endpoint to initialize and
 // encrypt a wallet using a given seed.
 func (c *Client) WalletInitSeedPost(seed, password string, force bool) (err error) {
@@ -91,6 +224,18 @@ func (c *Client) WalletInitSeedPost(seed, password string, force bool) (err erro
 	return
 }
 
+// WalletInitSeedBackupPost uses the /wallet/init/seed endpoint to initialize
+// the wallet from an encrypted seed backup.
+func (c *Client) WalletInitSeedBackupPost(backup modules.SeedBackup, passphrase, password string, force bool) (err error) {
+	values := url.Values{}
+	values.Set("seedbackup", backup.QRPayload())
+	values.Set("passphrase", passphrase)
+	values.Set("encryptionpassword", password)
+	values.Set("force", strconv.FormatBool(force))
+	err = c.post("/wallet/init/seed", values.Encode(), nil)
+	return
+}
+
 // WalletGet requests the /wallet api resource
 func (c *Client) WalletGet() (wg api.WalletGET, err error) {
 	err = c.get("/wallet", &wg)
@@ -121,6 +266,26 @@ func (c *Client) WalletSeedPost(seed, password string) (err error) {
 	return
 }
 
+// WalletSeedBackupPost uses the /wallet/seedbackup endpoint to create a
+// passphrase encrypted backup of the wallet's primary seed.
+func (c *Client) WalletSeedBackupPost(passphrase string) (wsbp api.WalletSeedBackupPOST, err error) {
+	values := url.Values{}
+	values.Set("passphrase", passphrase)
+	err = c.post("/wallet/seedbackup", values.Encode(), &wsbp)
+	return
+}
+
+// WalletSeedBackupLoadPost uses the /wallet/seed endpoint to add a seed from
+// an encrypted seed backup to the wallet's list of seeds.
+func (c *Client) WalletSeedBackupLoadPost(backup modules.SeedBackup, passphrase, password string) (err error) {
+	values := url.Values{}
+	values.Set("seedbackup", backup.QRPayload())
+	values.Set("passphrase", passphrase)
+	values.Set("encryptionpassword", password)
+	err = c.post("/wallet/seed", values.Encode(), nil)
+	return
+}
+
 // WalletSeedsGet uses the /wallet/seeds endpoint to return the wallet's
 // current seeds.
 func (c *Client) WalletSeedsGet() (wsg api.WalletSeedsGET, err error) {
@@ -128,6 +293,15 @@ func (c *Client) WalletSeedsGet() (wsg api.WalletSeedsGET, err error) {
 	return
 }
 
+// WalletSeedsDictionaryGet uses the /wallet/seeds endpoint to return the
+// wallet's current seeds encoded with the dictionary.
+func (c *Client) WalletSeedsDictionaryGet(dictionary mnemonics.DictionaryID) (wsg api.WalletSeedsGET, err error) {
+	values := url.Values{}
+	values.Set("dictionary", string(dictionary))
+	err = c.get(fmt.Sprintf("/wallet/seeds?%s", values.Encode()), &wsg)
+	return
+}
+
 // WalletSiacoinsMultiPost uses the /wallet/siacoin api endpoint to send money
 // to multiple addresses at once
 func (c *Client) WalletSiacoinsMultiPost(outputs []types.SiacoinOutput) (wsp api.WalletSiacoinsPOST, err error) {
@@ -152,6 +326,105 @@ func (c *Client) WalletSiacoinsPost(amount types.Currency, destination types.Unl
 	return
 }
 
+// WalletSiacoinsStrategyPost uses the /wallet/siacoins api endpoint to send
+// money to a single address, funding the transaction with the outputs selected
+// by the strategy.
+func (c *Client) WalletSiacoinsStrategyPost(amount types.Currency, destination types.UnlockHash, feeIncluded bool, strategy modules.SelectionStrategy) (wsp api.WalletSiacoinsPOST, err error) {
+	values := url.Values{}
+	values.Set("amount", amount.String())
+	values.Set("destination", destination.String())
+	values.Set("feeIncluded", strconv.FormatBool(feeIncluded))
+	values.Set("strategy", string(strategy))
+	err = c.post("/wallet/siacoins", values.Encode(), &wsp)
+	return
+}
+
+// WalletMultisigAddressPost uses the /wallet/multisig/address endpoint to
+// create a multisig address which requires 'required' signatures of the
+// public keys. The unused flag should be set to true if the address has never
+// appeared in the blockchain.
+func (c *Client) WalletMultisigAddressPost(required uint64, pks []types.SiaPublicKey, unused bool) (wmap api.WalletMultisigAddressPOSTResp, err error) {
+	json, err := json.Marshal(api.WalletMultisigAddressPOSTParams{
+		PublicKeys:         pks,
+		SignaturesRequired: required,
+		Unused:             unused,
+	})
+	if err != nil {
+		return
+	}
+	err = c.post("/wallet/multisig/address", string(json), &wmap)
+	return
+}
+
+// WalletMultisigTransactionPost uses the /wallet/multisig/transaction
+// endpoint to create an unsigned transaction sending the outputs from a
+// multisig address.
+func (c *Client) WalletMultisigTransactionPost(addr types.UnlockHash, outputs []types.SiacoinOutput) (wmp api.WalletMultisigPOSTResp, err error) {
+	json, err := json.Marshal(api.WalletMultisigTransactionPOSTParams{
+		Address: addr,
+		Outputs: outputs,
+	})
+	if err != nil {
+		return
+	}
+	err = c.post("/wallet/multisig/transaction", string(json), &wmp)
+	return
+}
+
+// WalletMultisigSignPost uses the /wallet/multisig/sign endpoint to add the
+// wallet's signatures to a multisig transaction.
+func (c *Client) WalletMultisigSignPost(txn types.Transaction) (wmp api.WalletMultisigPOSTResp, err error) {
+	json, err := json.Marshal(api.WalletMultisigSignPOSTParams{
+		Transaction: txn,
+	})
+	if err != nil {
+		return
+	}
+	err = c.post("/wallet/multisig/sign", string(json), &wmp)
+	return
+}
+
+// WalletMultisigMergePost uses the /wallet/multisig/merge endpoint to merge
+// the signatures of copies of a multisig transaction.
+func (c *Client) WalletMultisigMergePost(txns []types.Transaction) (wmp api.WalletMultisigPOSTResp, err error) {
+	json, err := json.Marshal(api.WalletMultisigMergePOSTParams{
+		Transactions: txns,
+	})
+	if err != nil {
+		return
+	}
+	err = c.post("/wallet/multisig/merge", string(json), &wmp)
+	return
+}
+
+// WalletColdTransactionPost uses the /wallet/cold/transaction endpoint to
+// create an unsigned transaction sending the outputs from the watch-only
+// addresses of the wallet.
+func (c *Client) WalletColdTransactionPost(outputs []types.SiacoinOutput, change types.UnlockHash) (wctp api.WalletColdTransactionPOSTResp, err error) {
+	json, err := json.Marshal(api.WalletColdTransactionPOSTParams{
+		Outputs:       outputs,
+		ChangeAddress: change,
+	})
+	if err != nil {
+		return
+	}
+	err = c.post("/wallet/cold/transaction", string(json), &wctp)
+	return
+}
+
+// WalletColdBroadcastPost uses the /wallet/cold/broadcast endpoint to
+// broadcast a cold transaction which was signed offline.
+func (c *Client) WalletColdBroadcastPost(txn types.Transaction) (err error) {
+	json, err := json.Marshal(api.WalletColdBroadcastPOSTParams{
+		Transaction: txn,
+	})
+	if err != nil {
+		return
+	}
+	err = c.post("/wallet/cold/broadcast", string(json), nil)
+	return
+}
+
 // WalletSignPost uses the /wallet/sign api endpoint to sign a transaction.
 func (c *Client) WalletSignPost(txn types.Transaction, toSign []crypto.Hash) (wspr api.WalletSignPOSTResp, err error) {
 	json, err := json.Marshal(api.WalletSignPOSTParams{
@@ -202,6 +475,34 @@ func (c *Client) WalletTransactionsGet(startHeight types.BlockHeight, endHeight
 	return
 }
 
+// WalletTransactionLabelPost uses the /wallet/transaction/:id/label endpoint
+// to set the label of a transaction. An empty label removes the label.
+func (c *Client) WalletTransactionLabelPost(id types.TransactionID, label string) error {
+	values := url.Values{}
+	values.Set("label", label)
+	return c.post("/wallet/transaction/"+id.String()+"/label", values.Encode(), nil)
+}
+
+// WalletTransactionBumpFeePost uses the /wallet/transaction/:id/bumpfee
+// endpoint to replace the transaction set of an unconfirmed transaction with a
+// set paying the given fee per byte.
+func (c *Client) WalletTransactionBumpFeePost(id types.TransactionID, feePerByte types.Currency) (wbfp api.WalletBumpFeePOST, err error) {
+	values := url.Values{}
+	values.Set("fee", feePerByte.String())
+	err = c.post("/wallet/transaction/"+id.String()+"/bumpfee", values.Encode(), &wbfp)
+	return
+}
+
+// WalletTransactionCPFPPost uses the /wallet/transaction/:id/cpfp endpoint to
+// add a child transaction to the transaction set of an unconfirmed transaction
+// which raises the fee of the set to the given fee per byte.
+func (c *Client) WalletTransactionCPFPPost(id types.TransactionID, feePerByte types.Currency) (wbfp api.WalletBumpFeePOST, err error) {
+	values := url.Values{}
+	values.Set("fee", feePerByte.String())
+	err = c.post("/wallet/transaction/"+id.String()+"/cpfp", values.Encode(), &wbfp)
+	return
+}
+
 // WalletTransactionGet requests the /wallet/transaction/:id api resource for a
 // certain TransactionID.
 func (c *Client) WalletTransactionGet(id types.TransactionID) (wtg api.WalletTransactionGETid, err error) {
@@ -232,6 +533,19 @@ func (c *Client) WalletUnspentGet() (wug api.WalletUnspentGET, err error) {
 	return
 }
 
+// WalletUnspentPost uses the /wallet/unspent endpoint to mark or unmark the
+// provided outputs as do-not-spend.
+func (c *Client) WalletUnspentPost(ids []types.OutputID, doNotSpend bool) error {
+	json, err := json.Marshal(api.WalletUnspentPOSTParams{
+		IDs:        ids,
+		DoNotSpend: doNotSpend,
+	})
+	if err != nil {
+		return err
+	}
+	return c.post("/wallet/unspent", string(json), nil)
+}
+
 // WalletWatchGet requests the /wallet/watch endpoint and returns the set of
 // currently watched addresses.
 func (c *Client) WalletWatchGet() (wwg api.WalletWatchGET, err error) {
@@ -269,6 +583,35 @@ func (c *Client) WalletWatchRemovePost(addrs []types.UnlockHash, unused bool) er
 	return c.post("/wallet/watch", string(json), nil)
 }
 
+// WalletWatchPublicKeysAddPost uses the /wallet/watch endpoint to watch the
+// standard addresses of a set of public keys. The unused flag should be set
+// to true if the addresses have never appeared in the blockchain.
+func (c *Client) WalletWatchPublicKeysAddPost(pks []types.SiaPublicKey, unused bool) error {
+	json, err := json.Marshal(api.WalletWatchPOST{
+		PublicKeys: pks,
+		Remove:     false,
+		Unused:     unused,
+	})
+	if err != nil {
+		return err
+	}
+	return c.post("/wallet/watch", string(json), nil)
+}
+
+// WalletWatchBalanceGet requests the /wallet/watch/balance endpoint and
+// returns the balances of the watch-only addresses.
+func (c *Client) WalletWatchBalanceGet() (wwbg api.WalletWatchBalanceGET, err error) {
+	err = c.get("/wallet/watch/balance", &wwbg)
+	return
+}
+
+// WalletWatchTransactionsGet requests the /wallet/watch/transactions endpoint
+// and returns the transactions related to the watch-only addresses.
+func (c *Client) WalletWatchTransactionsGet() (wwtg api.WalletWatchTransactionsGET, err error) {
+	err = c.get("/wallet/watch/transactions", &wwtg)
+	return
+}
+
 // Wallet033xPost uses the /wallet/033x endpoint to load a v0.3.3.x wallet into
 // the current wallet.
 func (c *Client) Wallet033xPost(path, password string) (err error) {
@@ -1,13 +1,19 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/big"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"golang.org/x/net/websocket"
 
 	"gitlab.com/NebulousLabs/encoding"
 	"go.sia.tech/siad/build"
@@ -16,6 +22,21 @@ import (
 	"go.sia.tech/siad/types"
 )
 
+const (
+	// defaultHashrateWindow is the default number of blocks the hashrate is
+	// estimated over by /consensus/hashrate.
+	defaultHashrateWindow = types.BlockHeight(144)
+
+	// maxHashrateBlocks is the maximum number of blocks a single call to
+	// /consensus/hashrate may process.
+	maxHashrateBlocks = 50e3
+
+	// maxConsensusChangeBacklog is the number of consensus changes which are
+	// buffered for a client of /consensus/subscribe/:id/ws. A client which
+	// falls further behind is disconnected.
+	maxConsensusChangeBacklog = 100
+)
+
 // ConsensusGET contains general information about the consensus set, with tags
 // to support idiomatic json encodings.
 type ConsensusGET struct {
@@ -26,6 +47,9 @@ type ConsensusGET struct {
 	Target       types.Target      `json:"target"`
 	Difficulty   types.Currency    `json:"difficulty"`
 
+	// LastReorgDepth is the depth of the most recent reorg since startup.
+	LastReorgDepth types.BlockHeight `json:"lastreorgdepth"`
+
 	// Foundation unlock hashes.
 	FoundationPrimaryUnlockHash  types.UnlockHash `json:"foundationprimaryunlockhash"`
 	FoundationFailsafeUnlockHash types.UnlockHash `json:"foundationfailsafeunlockhash"`
@@ -50,6 +74,61 @@ type ConsensusGET struct {
 	SiacoinPrecision types.Currency `json:"siacoinprecision"`
 }
 
+// ConsensusHashrateGET contains estimations of the network hashrate over
+// consecutive windows of blocks, ordered from newest to oldest.
+type ConsensusHashrateGET struct {
+	Difficulty types.Currency            `json:"difficulty"`
+	Hashrate   types.Currency            `json:"hashrate"`
+	Window     types.BlockHeight         `json:"window"`
+	Samples    []ConsensusHashrateSample `json:"samples"`
+}
+
+// ConsensusHashrateSample is the estimated hashrate within a window of blocks.
+// The hashrate is the total work of the blocks divided by the time between
+// the timestamps of the blocks at StartHeight and EndHeight.
+type ConsensusHashrateSample struct {
+	StartHeight types.BlockHeight `json:"startheight"`
+	EndHeight   types.BlockHeight `json:"endheight"`
+	StartTime   types.Timestamp   `json:"starttime"`
+	EndTime     types.Timestamp   `json:"endtime"`
+	Difficulty  types.Currency    `json:"difficulty"`
+	Hashrate    types.Currency    `json:"hashrate"`
+}
+
+// ConsensusReorgGET contains the reorg settings, the most recent reorg since
+// startup and the refused reorg which awaits confirmation.
+type ConsensusReorgGET struct {
+	modules.ReorgStatus
+}
+
+// ConsensusVerifyPOST is the result of checking the consensus database for
+// inconsistencies.
+type ConsensusVerifyPOST struct {
+	modules.ConsensusVerifyReport
+}
+
+// ConsensusChangeEvent is a consensus change sent by the
+// /consensus/subscribe/:id/ws endpoint. The reverted blocks were disconnected
+// from the chain, starting with the tip, before the applied blocks were
+// connected. If the subscription fails, a final event with only the error set
+// is sent.
+type ConsensusChangeEvent struct {
+	ID          modules.ConsensusChangeID   `json:"id"`
+	BlockHeight types.BlockHeight           `json:"blockheight"`
+	Synced      bool                        `json:"synced"`
+	Reverted    []ConsensusChangeEventBlock `json:"reverted"`
+	Applied     []ConsensusChangeEventBlock `json:"applied"`
+	Error       string                      `json:"error,omitempty"`
+}
+
+// ConsensusChangeEventBlock is a block of a consensus change together with
+// the diffs it applied or reverted.
+type ConsensusChangeEventBlock struct {
+	ID    types.BlockID                `json:"id"`
+	Block types.Block                  `json:"block"`
+	Diffs modules.ConsensusChangeDiffs `json:"diffs"`
+}
+
 // ConsensusHeadersGET contains information from a blocks header.
 type ConsensusHeadersGET struct {
 	BlockID types.BlockID `json:"blockid"`
@@ -117,19 +196,37 @@ type ConsensusBlocksGetSiafundOutput struct {
 }
 
 // RegisterRoutesConsensus is a helper function to register all consensus routes.
-func RegisterRoutesConsensus(router *httprouter.Router, cs modules.ConsensusSet) {
+func RegisterRoutesConsensus(router *httprouter.Router, cs modules.ConsensusSet, requiredPassword string) {
 	router.GET("/consensus", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		consensusHandler(cs, w, req, ps)
 	})
 	router.GET("/consensus/blocks", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		consensusBlocksHandler(cs, w, req, ps)
 	})
+	router.GET("/consensus/hashrate", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		consensusHashrateHandler(cs, w, req, ps)
+	})
 	router.GET("/consensus/subscribe/:id", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		consensusSubscribeHandler(cs, w, req, ps)
 	})
+	router.GET("/consensus/subscribe/:id/ws", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		consensusSubscribeWSHandler(cs, w, req, ps)
+	})
 	router.POST("/consensus/validate/transactionset", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		consensusValidateTransactionsetHandler(cs, w, req, ps)
 	})
+	router.GET("/consensus/reorg", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		consensusReorgHandlerGET(cs, w, req, ps)
+	})
+	router.POST("/consensus/reorg", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		consensusReorgHandlerPOST(cs, w, req, ps)
+	}, requiredPassword))
+	router.POST("/consensus/reorg/confirm", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		consensusReorgConfirmHandlerPOST(cs, w, req, ps)
+	}, requiredPassword))
+	router.POST("/consensus/verify", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		consensusVerifyHandlerPOST(cs, w, req, ps)
+	}, requiredPassword))
 }
 
 // ConsensusBlocksGetFromBlock is a helper method that uses a types.Block, types.BlockHeight and
@@ -222,13 +319,17 @@ func consensusHandler(cs modules.ConsensusSet, w http.ResponseWriter, _ *http.Re
 	b, found := cs.BlockAtHeight(height)
 	if !found {
 		err := "Failed to fetch block for current height"
-		WriteError(w, Error{err}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: err}, http.StatusInternalServerError)
 		build.Critical(err)
 		return
 	}
 	cbid := b.ID()
 	currentTarget, _ := cs.ChildTarget(cbid)
 	primary, failsafe := cs.FoundationUnlockHashes()
+	var lastReorgDepth types.BlockHeight
+	if rs, err := cs.ReorgStatus(); err == nil && rs.LastReorg != nil {
+		lastReorgDepth = rs.LastReorg.Depth
+	}
 	WriteJSON(w, ConsensusGET{
 		Synced:       cs.Synced(),
 		Height:       height,
@@ -236,6 +337,8 @@ func consensusHandler(cs modules.ConsensusSet, w http.ResponseWriter, _ *http.Re
 		Target:       currentTarget,
 		Difficulty:   currentTarget.Difficulty(),
 
+		LastReorgDepth: lastReorgDepth,
+
 		FoundationPrimaryUnlockHash:  primary,
 		FoundationFailsafeUnlockHash: failsafe,
 
@@ -265,11 +368,11 @@ func consensusBlocksHandler(cs modules.ConsensusSet, w http.ResponseWriter, req
 	// Get query params and check them.
 	id, height := req.FormValue("id"), req.FormValue("height")
 	if id != "" && height != "" {
-		WriteError(w, Error{"can't specify both id and height"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "can't specify both id and height"}, http.StatusBadRequest)
 		return
 	}
 	if id == "" && height == "" {
-		WriteError(w, Error{"either id or height has to be provided"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "either id or height has to be provided"}, http.StatusBadRequest)
 		return
 	}
 
@@ -281,7 +384,7 @@ func consensusBlocksHandler(cs modules.ConsensusSet, w http.ResponseWriter, req
 	if id != "" {
 		var bid types.BlockID
 		if err := bid.LoadString(id); err != nil {
-			WriteError(w, Error{"failed to unmarshal blockid"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "failed to unmarshal blockid"}, http.StatusBadRequest)
 			return
 		}
 		b, h, exists = cs.BlockByID(bid)
@@ -289,14 +392,14 @@ func consensusBlocksHandler(cs modules.ConsensusSet, w http.ResponseWriter, req
 	// Handle request by height
 	if height != "" {
 		if _, err := fmt.Sscan(height, &h); err != nil {
-			WriteError(w, Error{"failed to parse block height"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "failed to parse block height"}, http.StatusBadRequest)
 			return
 		}
 		b, exists = cs.BlockAtHeight(h)
 	}
 	// Check if block was found
 	if !exists {
-		WriteError(w, Error{"block doesn't exist"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "block doesn't exist"}, http.StatusBadRequest)
 		return
 	}
 
@@ -307,29 +410,188 @@ func consensusBlocksHandler(cs modules.ConsensusSet, w http.ResponseWriter, req
 	WriteJSON(w, consensusBlocksGetFromBlock(b, h, d))
 }
 
+// consensusHashrateSample estimates the hashrate of the network within the
+// window of blocks following start and ending at end.
+func consensusHashrateSample(cs modules.ConsensusSet, start, end types.BlockHeight) (ConsensusHashrateSample, error) {
+	prev, exists := cs.BlockAtHeight(start)
+	if !exists {
+		return ConsensusHashrateSample{}, fmt.Errorf("block at height %v doesn't exist", start)
+	}
+	sample := ConsensusHashrateSample{
+		StartHeight: start,
+		EndHeight:   end,
+		StartTime:   prev.Timestamp,
+	}
+	var work types.Currency
+	for height := start + 1; height <= end; height++ {
+		b, exists := cs.BlockAtHeight(height)
+		if !exists {
+			return ConsensusHashrateSample{}, fmt.Errorf("block at height %v doesn't exist", height)
+		}
+		target, exists := cs.ChildTarget(prev.ID())
+		if !exists {
+			return ConsensusHashrateSample{}, fmt.Errorf("target of block at height %v doesn't exist", height)
+		}
+		sample.Difficulty = target.Difficulty()
+		work = work.Add(sample.Difficulty)
+		prev = b
+	}
+	sample.EndTime = prev.Timestamp
+	// Timestamps of blocks are not strictly increasing. If the end of the
+	// window isn't after its start, the hashrate can't be estimated.
+	if sample.EndTime > sample.StartTime {
+		sample.Hashrate = work.Div64(uint64(sample.EndTime - sample.StartTime))
+	}
+	return sample, nil
+}
+
+// consensusHashrateHandler handles the API calls to /consensus/hashrate.
+func consensusHashrateHandler(cs modules.ConsensusSet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	window := defaultHashrateWindow
+	if ws := req.FormValue("window"); ws != "" {
+		if _, err := fmt.Sscan(ws, &window); err != nil {
+			WriteError(w, Error{Message: "failed to parse window: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	samples := uint64(1)
+	if ss := req.FormValue("samples"); ss != "" {
+		if _, err := fmt.Sscan(ss, &samples); err != nil {
+			WriteError(w, Error{Message: "failed to parse samples: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if window == 0 || samples == 0 {
+		WriteError(w, Error{Message: "window and samples must be greater than 0"}, http.StatusBadRequest)
+		return
+	}
+	if samples > maxHashrateBlocks/uint64(window) {
+		WriteError(w, Error{Message: fmt.Sprintf("window * samples can't exceed %v blocks", maxHashrateBlocks)}, http.StatusBadRequest)
+		return
+	}
+
+	// Get the current difficulty.
+	height := cs.Height()
+	target, _ := cs.ChildTarget(cs.CurrentBlock().ID())
+	chg := ConsensusHashrateGET{
+		Difficulty: target.Difficulty(),
+		Window:     window,
+	}
+
+	// Estimate the hashrate of the requested windows, starting at the tip.
+	end := height
+	for i := uint64(0); i < samples && end > 0; i++ {
+		start := types.BlockHeight(0)
+		if end > window {
+			start = end - window
+		}
+		sample, err := consensusHashrateSample(cs, start, end)
+		if err != nil {
+			WriteError(w, Error{Message: "failed to estimate hashrate: " + err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		chg.Samples = append(chg.Samples, sample)
+		end = start
+	}
+	if len(chg.Samples) > 0 {
+		chg.Hashrate = chg.Samples[0].Hashrate
+	}
+	WriteJSON(w, chg)
+}
+
 // consensusValidateTransactionsetHandler handles the API calls to
 // /consensus/validate/transactionset.
 func consensusValidateTransactionsetHandler(cs modules.ConsensusSet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var txnset []types.Transaction
 	err := json.NewDecoder(req.Body).Decode(&txnset)
 	if err != nil {
-		WriteError(w, Error{"could not decode transaction set: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "could not decode transaction set: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	_, err = cs.TryTransactionSet(txnset)
 	if err != nil {
-		WriteError(w, Error{"transaction set validation failed: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "transaction set validation failed: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// consensusReorgHandlerGET handles the API calls to /consensus/reorg.
+func consensusReorgHandlerGET(cs modules.ConsensusSet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	rs, err := cs.ReorgStatus()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get reorg status: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, ConsensusReorgGET{rs})
+}
+
+// consensusReorgHandlerPOST handles the API calls to set the reorg settings.
+// Settings which are not provided keep their current value.
+func consensusReorgHandlerPOST(cs modules.ConsensusSet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	rs, err := cs.ReorgStatus()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get reorg settings: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	settings := rs.Settings
+	if ad := req.FormValue("alertdepth"); ad != "" {
+		if _, err := fmt.Sscan(ad, &settings.AlertDepth); err != nil {
+			WriteError(w, Error{Message: "failed to parse alertdepth: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if md := req.FormValue("maxdepth"); md != "" {
+		if _, err := fmt.Sscan(md, &settings.MaxDepth); err != nil {
+			WriteError(w, Error{Message: "failed to parse maxdepth: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if err := cs.SetReorgSettings(settings); err != nil {
+		WriteError(w, Error{Message: "failed to set reorg settings: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// consensusReorgConfirmHandlerPOST handles the API calls to
+// /consensus/reorg/confirm.
+func consensusReorgConfirmHandlerPOST(cs modules.ConsensusSet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var tip types.BlockID
+	if err := tip.LoadString(req.FormValue("id")); err != nil {
+		WriteError(w, Error{Message: "failed to parse id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := cs.ConfirmReorg(tip); err != nil {
+		WriteError(w, Error{Message: "failed to confirm reorg: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
 }
 
+// consensusVerifyHandlerPOST handles the API calls to /consensus/verify.
+func consensusVerifyHandlerPOST(cs modules.ConsensusSet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var repair bool
+	if r := req.FormValue("repair"); r != "" {
+		if _, err := fmt.Sscan(r, &repair); err != nil {
+			WriteError(w, Error{Message: "failed to parse repair: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	report, err := cs.Verify(repair)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to verify consensus database: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, ConsensusVerifyPOST{report})
+}
+
 // consensusSubscribeHandler handles the API calls to the /consensus/subscribe
 // endpoint.
 func consensusSubscribeHandler(cs modules.ConsensusSet, w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	var ccid modules.ConsensusChangeID
 	if err := (*crypto.Hash)(&ccid).LoadString(ps.ByName("id")); err != nil {
-		WriteError(w, Error{"could not decode ID: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "could not decode ID: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -360,3 +622,139 @@ func newConsensusChangeStreamer(w io.Writer) consensusChangeStreamer {
 		e: encoding.NewEncoder(w),
 	}
 }
+
+// consensusChangeEventFromChange converts a consensus change into the event
+// sent by /consensus/subscribe/:id/ws.
+func consensusChangeEventFromChange(cc modules.ConsensusChange) ConsensusChangeEvent {
+	e := ConsensusChangeEvent{
+		ID:          cc.ID,
+		BlockHeight: cc.BlockHeight,
+		Synced:      cc.Synced,
+		Reverted:    make([]ConsensusChangeEventBlock, 0, len(cc.RevertedBlocks)),
+		Applied:     make([]ConsensusChangeEventBlock, 0, len(cc.AppliedBlocks)),
+	}
+	for i, b := range cc.RevertedBlocks {
+		e.Reverted = append(e.Reverted, ConsensusChangeEventBlock{
+			ID:    b.ID(),
+			Block: b,
+			Diffs: cc.RevertedDiffs[i],
+		})
+	}
+	for i, b := range cc.AppliedBlocks {
+		e.Applied = append(e.Applied, ConsensusChangeEventBlock{
+			ID:    b.ID(),
+			Block: b,
+			Diffs: cc.AppliedDiffs[i],
+		})
+	}
+	return e
+}
+
+// consensusChangeWSSubscriber buffers the consensus changes of a
+// /consensus/subscribe/:id/ws connection.
+type consensusChangeWSSubscriber struct {
+	changes chan modules.ConsensusChange
+
+	// live is set once the subscriber caught up with the consensus set.
+	live uint32
+
+	// overflow is closed if a live change doesn't fit into the buffer.
+	overflow     chan struct{}
+	overflowOnce sync.Once
+
+	// stop is closed when the connection is closed.
+	stop <-chan struct{}
+}
+
+// newConsensusChangeWSSubscriber creates a new subscriber which stops
+// blocking once stop is closed.
+func newConsensusChangeWSSubscriber(stop <-chan struct{}) *consensusChangeWSSubscriber {
+	return &consensusChangeWSSubscriber{
+		changes:  make(chan modules.ConsensusChange, maxConsensusChangeBacklog),
+		overflow: make(chan struct{}),
+		stop:     stop,
+	}
+}
+
+// ProcessConsensusChange implements modules.ConsensusSetSubscriber.
+func (s *consensusChangeWSSubscriber) ProcessConsensusChange(cc modules.ConsensusChange) {
+	if atomic.LoadUint32(&s.live) == 0 {
+		// While catching up, the consensus set waits for the client.
+		select {
+		case s.changes <- cc:
+		case <-s.stop:
+		}
+		return
+	}
+	// Live changes are sent while holding the consensus set's lock, so a
+	// client which falls behind is disconnected instead of blocking the
+	// consensus set. It can resubscribe from the last change it received.
+	select {
+	case s.changes <- cc:
+	default:
+		s.overflowOnce.Do(func() { close(s.overflow) })
+	}
+}
+
+// consensusSubscribeWSHandler handles the API calls to the
+// /consensus/subscribe/:id/ws endpoint, which streams the consensus changes
+// after the change with the provided ID to the client over a WebSocket.
+// Unlike /consensus/subscribe/:id, the connection stays open after the client
+// caught up and new changes are sent as they occur.
+func consensusSubscribeWSHandler(cs modules.ConsensusSet, w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	var ccid modules.ConsensusChangeID
+	if err := (*crypto.Hash)(&ccid).LoadString(ps.ByName("id")); err != nil {
+		WriteError(w, Error{Message: "could not decode ID: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+		// The connection outlives the read timeout of the API server.
+		if err := conn.SetDeadline(time.Time{}); err != nil {
+			return
+		}
+		ctx, cancel := context.WithCancel(req.Context())
+
+		// Clients don't send any messages, so reading only detects when the
+		// connection was closed.
+		go func() {
+			io.Copy(ioutil.Discard, conn)
+			cancel()
+		}()
+
+		sub := newConsensusChangeWSSubscriber(ctx.Done())
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- cs.ConsensusSetSubscribe(sub, ccid, ctx.Done())
+		}()
+		defer func() {
+			// Wait for the subscription to finish before unsubscribing, so
+			// that the subscriber isn't added after it was removed.
+			cancel()
+			if errCh != nil {
+				<-errCh
+			}
+			cs.Unsubscribe(sub)
+		}()
+		for {
+			select {
+			case cc := <-sub.changes:
+				if err := websocket.JSON.Send(conn, consensusChangeEventFromChange(cc)); err != nil {
+					return
+				}
+			case err := <-errCh:
+				errCh = nil
+				if err != nil {
+					websocket.JSON.Send(conn, ConsensusChangeEvent{Error: err.Error()})
+					return
+				}
+				atomic.StoreUint32(&sub.live, 1)
+			case <-sub.overflow:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}}
+	server.ServeHTTP(w, req)
+}
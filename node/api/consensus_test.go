@@ -4,11 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"gitlab.com/NebulousLabs/encoding"
 	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/types"
+	"golang.org/x/net/websocket"
 )
 
 // TestConsensusGet probes the GET call to /consensus.
@@ -168,3 +173,218 @@ func TestIntegrationConsensusSubscribe(t *testing.T) {
 		}
 	}
 }
+
+// hashrateConsensusSet is a minimal consensus set to test the hashrate
+// estimation with.
+type hashrateConsensusSet struct {
+	modules.ConsensusSet
+	blocks []types.Block
+	target types.Target
+}
+
+// BlockAtHeight returns the block at the given height.
+func (cs hashrateConsensusSet) BlockAtHeight(height types.BlockHeight) (types.Block, bool) {
+	if int(height) >= len(cs.blocks) {
+		return types.Block{}, false
+	}
+	return cs.blocks[height], true
+}
+
+// ChildTarget returns the same target for every block.
+func (cs hashrateConsensusSet) ChildTarget(types.BlockID) (types.Target, bool) {
+	return cs.target, true
+}
+
+// TestConsensusHashrateSample is a unit test for consensusHashrateSample.
+func TestConsensusHashrateSample(t *testing.T) {
+	t.Parallel()
+
+	// Create a chain of blocks which are 10 minutes apart.
+	cs := hashrateConsensusSet{target: types.Target{0, 0, 1}}
+	for i := 0; i < 21; i++ {
+		cs.blocks = append(cs.blocks, types.Block{
+			Nonce:     types.BlockNonce{byte(i)},
+			Timestamp: types.Timestamp(1e6 + 600*i),
+		})
+	}
+	difficulty := cs.target.Difficulty()
+
+	sample, err := consensusHashrateSample(cs, 10, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sample.StartHeight != 10 || sample.EndHeight != 20 {
+		t.Fatal("wrong heights", sample.StartHeight, sample.EndHeight)
+	}
+	if sample.StartTime != cs.blocks[10].Timestamp || sample.EndTime != cs.blocks[20].Timestamp {
+		t.Fatal("wrong timestamps", sample.StartTime, sample.EndTime)
+	}
+	if !sample.Difficulty.Equals(difficulty) {
+		t.Fatal("wrong difficulty", sample.Difficulty, difficulty)
+	}
+	// 10 blocks with the same difficulty within 6000 seconds.
+	if expected := difficulty.Mul64(10).Div64(6000); !sample.Hashrate.Equals(expected) {
+		t.Fatal("wrong hashrate", sample.Hashrate, expected)
+	}
+
+	// Windows that end before they start have no hashrate.
+	cs.blocks[20].Timestamp = cs.blocks[10].Timestamp
+	sample, err = consensusHashrateSample(cs, 10, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sample.Hashrate.IsZero() {
+		t.Fatal("expected no hashrate", sample.Hashrate)
+	}
+
+	// Missing blocks result in an error.
+	if _, err := consensusHashrateSample(cs, 10, 21); err == nil {
+		t.Fatal("expected error for missing block")
+	}
+}
+
+// TestConsensusHashrateHandlerLimits probes the validation of the window and
+// samples of /consensus/hashrate.
+func TestConsensusHashrateHandlerLimits(t *testing.T) {
+	t.Parallel()
+
+	// All of the requests are rejected before the consensus set is used.
+	tests := []struct {
+		query string
+		code  int
+	}{
+		{"window=0", http.StatusBadRequest},
+		{"samples=0", http.StatusBadRequest},
+		{"window=1001&samples=1000", http.StatusBadRequest},
+		// window * samples overflows to 0.
+		{"window=8589934592&samples=2147483648", http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/consensus/hashrate?"+test.query, nil)
+		w := httptest.NewRecorder()
+		consensusHashrateHandler(hashrateConsensusSet{}, w, req, nil)
+		if w.Code != test.code {
+			t.Errorf("%v: expected status %v but got %v", test.query, test.code, w.Code)
+		}
+	}
+}
+
+// TestConsensusChangeWSSubscriber probes the buffering of the
+// consensusChangeWSSubscriber.
+func TestConsensusChangeWSSubscriber(t *testing.T) {
+	t.Parallel()
+
+	// While catching up, changes block until the subscriber is stopped.
+	stop := make(chan struct{})
+	sub := newConsensusChangeWSSubscriber(stop)
+	for i := 0; i < maxConsensusChangeBacklog; i++ {
+		sub.ProcessConsensusChange(modules.ConsensusChange{})
+	}
+	done := make(chan struct{})
+	go func() {
+		sub.ProcessConsensusChange(modules.ConsensusChange{})
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("change didn't block while catching up")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(stop)
+	<-done
+
+	// Live changes which don't fit into the buffer overflow.
+	sub = newConsensusChangeWSSubscriber(make(chan struct{}))
+	atomic.StoreUint32(&sub.live, 1)
+	for i := 0; i < maxConsensusChangeBacklog; i++ {
+		sub.ProcessConsensusChange(modules.ConsensusChange{})
+	}
+	select {
+	case <-sub.overflow:
+		t.Fatal("subscriber overflowed early")
+	default:
+	}
+	sub.ProcessConsensusChange(modules.ConsensusChange{})
+	sub.ProcessConsensusChange(modules.ConsensusChange{})
+	select {
+	case <-sub.overflow:
+	default:
+		t.Fatal("subscriber didn't overflow")
+	}
+}
+
+// TestIntegrationConsensusSubscribeWS probes the /consensus/subscribe/:id/ws
+// endpoint.
+func TestIntegrationConsensusSubscribeWS(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	st, err := createServerTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.panicClose()
+
+	addr := st.server.listener.Addr().String()
+	dial := func(ccid modules.ConsensusChangeID) *websocket.Conn {
+		config, err := websocket.NewConfig("ws://"+addr+"/consensus/subscribe/"+ccid.String()+"/ws", "http://"+addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config.Header.Set("User-Agent", "Sia-Agent")
+		conn, err := websocket.DialConfig(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conn
+	}
+
+	// The client receives the existing changes starting with the genesis
+	// block.
+	conn := dial(modules.ConsensusChangeBeginning)
+	defer conn.Close()
+	var e ConsensusChangeEvent
+	var lastID modules.ConsensusChangeID
+	for height := types.BlockHeight(0); height <= st.cs.Height(); height++ {
+		if err := websocket.JSON.Receive(conn, &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.BlockHeight != height || len(e.Applied) != 1 || e.Applied[0].ID != e.Applied[0].Block.ID() {
+			t.Fatal("wrong consensus change", e.BlockHeight, height)
+		}
+		lastID = e.ID
+	}
+
+	// New blocks are sent after the client caught up.
+	b, err := st.miner.AddBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := websocket.JSON.Receive(conn, &e); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Applied) != 1 || e.Applied[0].ID != b.ID() {
+		t.Fatal("new block wasn't sent")
+	}
+
+	// Resubscribing starts after the provided change.
+	conn2 := dial(lastID)
+	defer conn2.Close()
+	if err := websocket.JSON.Receive(conn2, &e); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Applied) != 1 || e.Applied[0].ID != b.ID() {
+		t.Fatal("wrong first change after resubscribing")
+	}
+
+	// Unknown change IDs return an error.
+	conn3 := dial(modules.ConsensusChangeID{1})
+	defer conn3.Close()
+	if err := websocket.JSON.Receive(conn3, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Error == "" {
+		t.Fatal("expected an error for an unknown change ID")
+	}
+}
@@ -438,7 +438,7 @@ func (api *API) daemonStackHandlerGET(w http.ResponseWriter, _ *http.Request, _
 	stack := make([]byte, modules.StackSize)
 	n := runtime.Stack(stack, true)
 	if n == 0 {
-		WriteError(w, Error{"no stack trace pulled"}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "no stack trace pulled"}, http.StatusInternalServerError)
 		return
 	}
 
@@ -453,12 +453,12 @@ func (api *API) daemonStartProfileHandlerPOST(w http.ResponseWriter, req *http.R
 	// Parse profile string
 	profileStr := req.FormValue("profileFlags")
 	if profileStr == "" {
-		WriteError(w, Error{"profile flags cannot be blank"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "profile flags cannot be blank"}, http.StatusBadRequest)
 		return
 	}
 	profileStr, err := profile.ProcessProfileFlags(profileStr)
 	if err != nil {
-		WriteError(w, Error{"unable to process profile flags:" + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to process profile flags:" + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	profileCPU := strings.Contains(profileStr, "c")
@@ -472,7 +472,7 @@ func (api *API) daemonStartProfileHandlerPOST(w http.ResponseWriter, req *http.R
 	}
 	err = os.MkdirAll(profileDir, modules.DefaultDirPerm)
 	if err != nil {
-		WriteError(w, Error{"unable to create directory for profiles:" + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to create directory for profiles:" + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -525,7 +525,7 @@ func (api *API) daemonSettingsHandlerPOST(w http.ResponseWriter, req *http.Reque
 	if d := req.FormValue("maxdownloadspeed"); d != "" {
 		var downloadSpeed int64
 		if _, err := fmt.Sscan(d, &downloadSpeed); err != nil {
-			WriteError(w, Error{"unable to parse downloadspeed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse downloadspeed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		maxDownloadSpeed = downloadSpeed
@@ -534,14 +534,14 @@ func (api *API) daemonSettingsHandlerPOST(w http.ResponseWriter, req *http.Reque
 	if u := req.FormValue("maxuploadspeed"); u != "" {
 		var uploadSpeed int64
 		if _, err := fmt.Sscan(u, &uploadSpeed); err != nil {
-			WriteError(w, Error{"unable to parse uploadspeed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse uploadspeed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		maxUploadSpeed = uploadSpeed
 	}
 	// Set the limit.
 	if err := api.siadConfig.SetRatelimit(maxDownloadSpeed, maxUploadSpeed); err != nil {
-		WriteError(w, Error{"unable to set limits: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to set limits: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+
+	"gitlab.com/NebulousLabs/errors"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/modules/renter"
+	"go.sia.tech/siad/modules/renter/filesystem"
+	"go.sia.tech/siad/modules/renter/filesystem/siafile"
+)
+
+// ErrorCode is a stable identifier of an API error. Clients should branch on
+// the code of an Error rather than its Message since the latter might change
+// between releases. Codes are never renamed or reused.
+type ErrorCode string
+
+const (
+	// ErrCodeAllowanceEmpty is returned when a request requires the renter to
+	// have an allowance but no allowance is set.
+	ErrCodeAllowanceEmpty ErrorCode = "allowance_empty"
+
+	// ErrCodeInsufficientHosts is returned when there are not enough hosts,
+	// contracts or workers to complete a request.
+	ErrCodeInsufficientHosts ErrorCode = "insufficient_hosts"
+
+	// ErrCodeInternal is returned for errors which don't have a more specific
+	// code and were caused by the server.
+	ErrCodeInternal ErrorCode = "internal_error"
+
+	// ErrCodeInvalidRequest is returned for errors which don't have a more
+	// specific code and were caused by the request.
+	ErrCodeInvalidRequest ErrorCode = "invalid_request"
+
+	// ErrCodeModuleNotLoaded is returned when a request is made to a module
+	// which isn't loaded or has been disabled.
+	ErrCodeModuleNotLoaded ErrorCode = "module_not_loaded"
+
+	// ErrCodeNotFound is returned when the requested resource doesn't exist.
+	ErrCodeNotFound ErrorCode = "not_found"
+
+	// ErrCodePathExists is returned when a file or folder already exists at
+	// the requested siapath.
+	ErrCodePathExists ErrorCode = "path_exists"
+
+	// ErrCodePathNotFound is returned when there is no file or folder at the
+	// requested siapath.
+	ErrCodePathNotFound ErrorCode = "path_not_found"
+
+	// ErrCodeUnauthorized is returned when the API password is missing or
+	// wrong.
+	ErrCodeUnauthorized ErrorCode = "unauthorized"
+)
+
+// renterErrorCodes maps errors returned by the renter to their codes. Errors
+// which are more specific than others need to come first.
+var renterErrorCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{renter.ErrAllowanceEmpty, ErrCodeAllowanceEmpty},
+	{renter.ErrNotEnoughContracts, ErrCodeInsufficientHosts},
+	{renter.ErrNotEnoughWorkers, ErrCodeInsufficientHosts},
+	{modules.ErrNotEnoughWorkersInWorkerPool, ErrCodeInsufficientHosts},
+	{filesystem.ErrExists, ErrCodePathExists},
+	{siafile.ErrPathOverload, ErrCodePathExists},
+	{filesystem.ErrNotExist, ErrCodePathNotFound},
+	{siafile.ErrUnknownPath, ErrCodePathNotFound},
+}
+
+// renterErrorCode returns the code of an error returned by the renter. If the
+// error doesn't have a specific code, an empty code is returned and
+// WriteError falls back to the code of the HTTP status.
+func renterErrorCode(err error) ErrorCode {
+	for _, ec := range renterErrorCodes {
+		if errors.Contains(err, ec.err) {
+			return ec.code
+		}
+	}
+	return ""
+}
+
+// statusErrorCode returns the code of an error which is returned with the
+// given HTTP status code and doesn't have a more specific code.
+func statusErrorCode(status int) ErrorCode {
+	switch {
+	case status == http.StatusUnauthorized:
+		return ErrCodeUnauthorized
+	case status == http.StatusNotFound:
+		return ErrCodeNotFound
+	case status == StatusModuleNotLoaded || status == StatusModuleDisabled:
+		return ErrCodeModuleNotLoaded
+	case status >= 500:
+		return ErrCodeInternal
+	default:
+		return ErrCodeInvalidRequest
+	}
+}
+
+// ErrorCodeOf returns the code of the first Error contained in err. If err
+// doesn't contain an Error, an empty code is returned.
+func ErrorCodeOf(err error) ErrorCode {
+	switch e := err.(type) {
+	case Error:
+		return e.Code
+	case errors.Error:
+		for _, err := range e.ErrSet {
+			if code := ErrorCodeOf(err); code != "" {
+				return code
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/modules/renter"
+	"go.sia.tech/siad/modules/renter/filesystem"
+)
+
+// TestRenterErrorCode is a unit test for renterErrorCode.
+func TestRenterErrorCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err      error
+		expected ErrorCode
+	}{
+		{errors.AddContext(filesystem.ErrNotExist, "failed to get file"), ErrCodePathNotFound},
+		{errors.Compose(errors.New("upload failed"), filesystem.ErrExists), ErrCodePathExists},
+		{errors.AddContext(renter.ErrNotEnoughContracts, "got 1, needed 20"), ErrCodeInsufficientHosts},
+		{renter.ErrAllowanceEmpty, ErrCodeAllowanceEmpty},
+		// Errors are matched by value, not by their message.
+		{errors.New("upload failed: " + filesystem.ErrNotExist.Error()), ""},
+		{errors.New("something broke"), ""},
+	}
+	for _, test := range tests {
+		if code := renterErrorCode(test.err); code != test.expected {
+			t.Errorf("%q: expected %q but got %q", test.err, test.expected, code)
+		}
+	}
+}
+
+// TestStatusErrorCode is a unit test for statusErrorCode.
+func TestStatusErrorCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status   int
+		expected ErrorCode
+	}{
+		{http.StatusUnauthorized, ErrCodeUnauthorized},
+		{http.StatusNotFound, ErrCodeNotFound},
+		{StatusModuleNotLoaded, ErrCodeModuleNotLoaded},
+		{StatusModuleDisabled, ErrCodeModuleNotLoaded},
+		{http.StatusBadRequest, ErrCodeInvalidRequest},
+		{http.StatusInternalServerError, ErrCodeInternal},
+	}
+	for _, test := range tests {
+		if code := statusErrorCode(test.status); code != test.expected {
+			t.Errorf("%v: expected %v but got %v", test.status, test.expected, code)
+		}
+	}
+}
+
+// TestWriteErrorCode checks that WriteError sets the code of an error and
+// that it can be retrieved from a wrapped error using ErrorCodeOf.
+func TestWriteErrorCode(t *testing.T) {
+	t.Parallel()
+
+	decode := func(rec *httptest.ResponseRecorder) Error {
+		var apiErr Error
+		if err := json.NewDecoder(rec.Body).Decode(&apiErr); err != nil {
+			t.Fatal(err)
+		}
+		return apiErr
+	}
+
+	// A missing code should be derived from the status, not the message.
+	rec := httptest.NewRecorder()
+	WriteError(rec, Error{Message: filesystem.ErrNotExist.Error()}, http.StatusBadRequest)
+	apiErr := decode(rec)
+	if apiErr.Code != ErrCodeInvalidRequest {
+		t.Fatal("wrong code", apiErr.Code)
+	}
+
+	// An explicit code should be kept.
+	rec = httptest.NewRecorder()
+	WriteError(rec, Error{Message: "too few hosts", Code: ErrCodeInsufficientHosts}, http.StatusBadRequest)
+	apiErr = decode(rec)
+	if apiErr.Code != ErrCodeInsufficientHosts {
+		t.Fatal("wrong code", apiErr.Code)
+	}
+
+	// ErrorCodeOf should find the code of wrapped errors.
+	err := errors.AddContext(apiErr, "GET request error")
+	if code := ErrorCodeOf(err); code != ErrCodeInsufficientHosts {
+		t.Fatal("wrong code", code)
+	}
+	if code := ErrorCodeOf(errors.New("not an api error")); code != "" {
+		t.Fatal("expected no code", code)
+	}
+}
+
+// TestRenterErrorCodes checks that renter handlers return the codes of the
+// renter's errors.
+func TestRenterErrorCodes(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.panicClose()
+
+	// A missing file should return path_not_found.
+	err = st.getAPI("/renter/file/doesnotexist", &RenterFile{})
+	if code := ErrorCodeOf(err); code != ErrCodePathNotFound {
+		t.Fatalf("expected %v but got %v: %v", ErrCodePathNotFound, code, err)
+	}
+
+	// A missing directory should return path_not_found.
+	err = st.stdPostAPI("/renter/dir/doesnotexist", url.Values{"action": {"delete"}})
+	if code := ErrorCodeOf(err); code != ErrCodePathNotFound {
+		t.Fatalf("expected %v but got %v: %v", ErrCodePathNotFound, code, err)
+	}
+}
@@ -12,6 +12,16 @@ import (
 	"go.sia.tech/siad/types"
 )
 
+const (
+	// defaultExplorerAddressLimit is the number of address events returned by
+	// /explorer/address if no limit is provided.
+	defaultExplorerAddressLimit = 100
+
+	// maxExplorerAddressLimit is the maximum number of address events
+	// returned by a single request to /explorer/address.
+	maxExplorerAddressLimit = 1000
+)
+
 type (
 	// ExplorerBlock is a block with some extra information such as the id and
 	// height. This information is provided for programs that may not be
@@ -59,6 +69,12 @@ type (
 		Block ExplorerBlock `json:"block"`
 	}
 
+	// ExplorerAddressGET is the object returned as a response to a GET
+	// request to /explorer/address/:addr.
+	ExplorerAddressGET struct {
+		modules.AddressHistory
+	}
+
 	// ExplorerHashGET is the object returned as a response to a GET request to
 	// /explorer/hash. The HashType will indicate whether the hash corresponds
 	// to a block id, a transaction id, a siacoin output id, a file contract
@@ -87,6 +103,9 @@ func RegisterRoutesExplorer(router *httprouter.Router, e modules.Explorer, cs mo
 	router.GET("/explorer/hashes/:hash", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		explorerHashHandler(e, w, req, ps)
 	})
+	router.GET("/explorer/address/:addr", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		explorerAddressHandler(e, w, req, ps)
+	})
 }
 
 // buildExplorerTransaction takes a transaction and the height + id of the
@@ -216,14 +235,14 @@ func explorerBlocksHandler(e modules.Explorer, cs modules.ConsensusSet, w http.R
 	var height types.BlockHeight
 	_, err := fmt.Sscan(ps.ByName("height"), &height)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Fetch and return the explorer block.
 	block, exists := cs.BlockAtHeight(height)
 	if !exists {
-		WriteError(w, Error{"no block found at input height in call to /explorer/block"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "no block found at input height in call to /explorer/block"}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, ExplorerBlockGET{
@@ -266,7 +285,7 @@ func explorerHashHandler(explorer modules.Explorer, w http.ResponseWriter, _ *ht
 	if err != nil {
 		addr, err := scanAddress(ps.ByName("hash"))
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		hash = crypto.Hash(addr)
@@ -275,7 +294,7 @@ func explorerHashHandler(explorer modules.Explorer, w http.ResponseWriter, _ *ht
 	// TODO: lookups on the zero hash are too expensive to allow. Need a
 	// better way to handle this case.
 	if hash == (crypto.Hash{}) {
-		WriteError(w, Error{"can't lookup the empty unlock hash"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "can't lookup the empty unlock hash"}, http.StatusBadRequest)
 		return
 	}
 
@@ -361,7 +380,41 @@ func explorerHashHandler(explorer modules.Explorer, w http.ResponseWriter, _ *ht
 	}
 
 	// Hash not found, return an error.
-	WriteError(w, Error{"unrecognized hash used as input to /explorer/hash"}, http.StatusBadRequest)
+	WriteError(w, Error{Message: "unrecognized hash used as input to /explorer/hash"}, http.StatusBadRequest)
+}
+
+// explorerAddressHandler handles GET requests to /explorer/address/:addr.
+func explorerAddressHandler(explorer modules.Explorer, w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	addr, err := scanAddress(ps.ByName("addr"))
+	if err != nil {
+		WriteError(w, Error{Message: "failed to parse address: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var offset uint64
+	if o := req.FormValue("offset"); o != "" {
+		if _, err := fmt.Sscan(o, &offset); err != nil {
+			WriteError(w, Error{Message: "failed to parse offset: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	limit := uint64(defaultExplorerAddressLimit)
+	if l := req.FormValue("limit"); l != "" {
+		if _, err := fmt.Sscan(l, &limit); err != nil {
+			WriteError(w, Error{Message: "failed to parse limit: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if limit > maxExplorerAddressLimit {
+		WriteError(w, Error{Message: fmt.Sprintf("limit can't be greater than %v", maxExplorerAddressLimit)}, http.StatusBadRequest)
+		return
+	}
+
+	history, exists := explorer.AddressHistory(addr, offset, limit)
+	if !exists {
+		WriteError(w, Error{Message: "address does not appear in the blockchain"}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, ExplorerAddressGET{history})
 }
 
 // explorerHandler handles API calls to /explorer
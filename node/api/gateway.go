@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -20,6 +21,10 @@ type (
 
 		MaxDownloadSpeed int64 `json:"maxdownloadspeed"`
 		MaxUploadSpeed   int64 `json:"maxuploadspeed"`
+
+		// GatewayConnectionLimits are the limits of inbound peers per subnet
+		// and autonomous system.
+		modules.GatewayConnectionLimits
 	}
 
 	// GatewayBandwidthGET contains the bandwidth usage of the gateway
@@ -27,6 +32,21 @@ type (
 		Download  uint64    `json:"download"`
 		Upload    uint64    `json:"upload"`
 		StartTime time.Time `json:"starttime"`
+
+		// Protocols contains the bandwidth usage split by protocol.
+		Protocols []modules.ProtocolBandwidth `json:"protocols,omitempty"`
+	}
+
+	// GatewayConnectivityGET contains the port mappings of the gateway and
+	// whether the node is reachable from the outside.
+	GatewayConnectivityGET struct {
+		modules.GatewayConnectivity
+	}
+
+	// GatewayHealthGET contains the node's view of the health of the network.
+	GatewayHealthGET struct {
+		modules.NetworkHealth
+		Online bool `json:"online"`
 	}
 
 	// GatewayBlocklistPOST contains the information needed to set the Blocklist
@@ -41,6 +61,23 @@ type (
 		Blacklist []string `json:"blacklist"` // deprecated, kept for backwards compatibility
 		Blocklist []string `json:"blocklist"`
 	}
+
+	// GatewayBansGET contains the bans of the gateway and the scores of the
+	// peers it has scored.
+	GatewayBansGET struct {
+		Bans   []modules.PeerBan   `json:"bans"`
+		Scores []modules.PeerScore `json:"scores"`
+	}
+
+	// GatewayBansPOST contains the information needed to ban or unban
+	// addresses. Duration is in seconds, 0 bans addresses permanently by
+	// adding them to the blocklist.
+	GatewayBansPOST struct {
+		Action    string   `json:"action"`
+		Addresses []string `json:"addresses"`
+		Duration  uint64   `json:"duration"`
+		Reason    string   `json:"reason"`
+	}
 )
 
 // RegisterRoutesGateway is a helper function to register all gateway routes.
@@ -60,12 +97,21 @@ func RegisterRoutesGateway(router *httprouter.Router, g modules.Gateway, require
 	router.POST("/gateway/disconnect/:netaddress", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		gatewayDisconnectHandler(g, w, req, ps)
 	}, requiredPassword))
+	router.GET("/gateway/connectivity", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		gatewayConnectivityHandlerGET(g, w, req, ps)
+	})
 	router.GET("/gateway/blocklist", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		gatewayBlocklistHandlerGET(g, w, req, ps)
 	})
 	router.POST("/gateway/blocklist", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		gatewayBlocklistHandlerPOST(g, w, req, ps)
 	}, requiredPassword))
+	router.GET("/gateway/bans", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		gatewayBansHandlerGET(g, w, req, ps)
+	})
+	router.POST("/gateway/bans", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		gatewayBansHandlerPOST(g, w, req, ps)
+	}, requiredPassword))
 
 	// Deprecated fields
 	router.GET("/gateway/blacklist", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
@@ -86,7 +132,58 @@ func gatewayHandlerGET(gateway modules.Gateway, w http.ResponseWriter, _ *http.R
 	if peers == nil {
 		peers = make([]modules.Peer, 0)
 	}
-	WriteJSON(w, GatewayGET{gateway.Address(), peers, gateway.Online(), mds, mus})
+	WriteJSON(w, GatewayGET{
+		NetAddress:              gateway.Address(),
+		Peers:                   peers,
+		Online:                  gateway.Online(),
+		MaxDownloadSpeed:        mds,
+		MaxUploadSpeed:          mus,
+		GatewayConnectionLimits: gateway.ConnectionLimits(),
+	})
+}
+
+// gatewayConnectivityHandlerGET handles the API call asking whether the node
+// is reachable from the outside. If 'probe' is set, the reachability is
+// probed before returning.
+func gatewayConnectivityHandlerGET(gateway modules.Gateway, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	probe := false
+	if p := req.FormValue("probe"); p != "" {
+		var err error
+		probe, err = strconv.ParseBool(p)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'probe' parameter: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	var connectivity modules.GatewayConnectivity
+	var err error
+	if probe {
+		connectivity, err = gateway.ProbeConnectivity()
+	} else {
+		connectivity, err = gateway.Connectivity()
+	}
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get connectivity: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, GatewayConnectivityGET{connectivity})
+}
+
+// gatewayHealthHandlerGET handles the API call asking for the node's view of
+// the health of the network.
+func gatewayHealthHandlerGET(gateway modules.Gateway, cs modules.ConsensusSet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	health, err := cs.NetworkHealth()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get network health: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	if health.PeerReports == nil {
+		health.PeerReports = make([]modules.PeerHeightReport, 0)
+	}
+	WriteJSON(w, GatewayHealthGET{
+		NetworkHealth: health,
+		Online:        gateway.Online(),
+	})
 }
 
 // gatewayHandlerPOST handles the API call changing gateway specific settings.
@@ -96,7 +193,7 @@ func gatewayHandlerPOST(gateway modules.Gateway, w http.ResponseWriter, req *htt
 	if d := req.FormValue("maxdownloadspeed"); d != "" {
 		var downloadSpeed int64
 		if _, err := fmt.Sscan(d, &downloadSpeed); err != nil {
-			WriteError(w, Error{"unable to parse downloadspeed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse downloadspeed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		maxDownloadSpeed = downloadSpeed
@@ -105,17 +202,44 @@ func gatewayHandlerPOST(gateway modules.Gateway, w http.ResponseWriter, req *htt
 	if u := req.FormValue("maxuploadspeed"); u != "" {
 		var uploadSpeed int64
 		if _, err := fmt.Sscan(u, &uploadSpeed); err != nil {
-			WriteError(w, Error{"unable to parse uploadspeed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse uploadspeed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		maxUploadSpeed = uploadSpeed
 	}
+	// Scan the connection limits. (optional parameters)
+	limits := gateway.ConnectionLimits()
+	limitsChanged := false
+	for _, param := range []struct {
+		name  string
+		limit *uint64
+	}{
+		{"maxinboundpersubnet16", &limits.MaxInboundPerSubnet16},
+		{"maxinboundpersubnet24", &limits.MaxInboundPerSubnet24},
+		{"maxinboundperasn", &limits.MaxInboundPerASN},
+	} {
+		if l := req.FormValue(param.name); l != "" {
+			limit, err := strconv.ParseUint(l, 10, 64)
+			if err != nil {
+				WriteError(w, Error{Message: "unable to parse " + param.name + ": " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			*param.limit = limit
+			limitsChanged = true
+		}
+	}
 	// Try to set the limits.
 	err := gateway.SetRateLimits(maxDownloadSpeed, maxUploadSpeed)
 	if err != nil {
-		WriteError(w, Error{"failed to set new rate limit: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to set new rate limit: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
+	if limitsChanged {
+		if err := gateway.SetConnectionLimits(limits); err != nil {
+			WriteError(w, Error{Message: "failed to set new connection limits: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
 	WriteSuccess(w)
 }
 
@@ -124,13 +248,19 @@ func gatewayHandlerPOST(gateway modules.Gateway, w http.ResponseWriter, req *htt
 func gatewayBandwidthHandlerGET(gateway modules.Gateway, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	upload, download, startTime, err := gateway.BandwidthCounters()
 	if err != nil {
-		WriteError(w, Error{"failed to get gateway's bandwidth usage: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to get gateway's bandwidth usage: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	protocols, err := gateway.ProtocolBandwidth()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get gateway's bandwidth usage by protocol: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, GatewayBandwidthGET{
 		Download:  download,
 		Upload:    upload,
 		StartTime: startTime,
+		Protocols: protocols,
 	})
 }
 
@@ -139,7 +269,7 @@ func gatewayConnectHandler(gateway modules.Gateway, w http.ResponseWriter, _ *ht
 	addr := modules.NetAddress(ps.ByName("netaddress"))
 	err := gateway.ConnectManual(addr)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -151,7 +281,7 @@ func gatewayDisconnectHandler(gateway modules.Gateway, w http.ResponseWriter, _
 	addr := modules.NetAddress(ps.ByName("netaddress"))
 	err := gateway.DisconnectManual(addr)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -164,7 +294,7 @@ func gatewayBlocklistHandlerGET(gateway modules.Gateway, w http.ResponseWriter,
 	// Get Blocklist
 	blocklist, err := gateway.Blocklist()
 	if err != nil {
-		WriteError(w, Error{"unable to get blocklist mode: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to get blocklist mode: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, GatewayBlocklistGET{
@@ -183,7 +313,7 @@ func gatewayBlocklistHandlerPOST(gateway modules.Gateway, w http.ResponseWriter,
 	var params GatewayBlocklistPOST
 	err := json.NewDecoder(req.Body).Decode(&params)
 	if err != nil {
-		WriteError(w, Error{"invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -191,35 +321,89 @@ func gatewayBlocklistHandlerPOST(gateway modules.Gateway, w http.ResponseWriter,
 	case "append":
 		// Check that addresses where submitted
 		if len(params.Addresses) == 0 {
-			WriteError(w, Error{"no addresses submitted to append or remove"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "no addresses submitted to append or remove"}, http.StatusBadRequest)
 			return
 		}
 		// Add addresses to Blocklist
 		if err := gateway.AddToBlocklist(params.Addresses); err != nil {
-			WriteError(w, Error{"failed to add addresses to the blocklist: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "failed to add addresses to the blocklist: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	case "remove":
 		// Check that addresses where submitted
 		if len(params.Addresses) == 0 {
-			WriteError(w, Error{"no addresses submitted to append or remove"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "no addresses submitted to append or remove"}, http.StatusBadRequest)
 			return
 		}
 		// Remove addresses from the Blocklist
 		if err := gateway.RemoveFromBlocklist(params.Addresses); err != nil {
-			WriteError(w, Error{"failed to remove addresses from the blocklist: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "failed to remove addresses from the blocklist: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	case "set":
 		// Set Blocklist
 		if err := gateway.SetBlocklist(params.Addresses); err != nil {
-			WriteError(w, Error{"failed to set the blocklist: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "failed to set the blocklist: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	default:
-		WriteError(w, Error{"invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	WriteSuccess(w)
+}
+
+// gatewayBansHandlerGET handles the API call to get the gateway's bans and
+// peer scores.
+func gatewayBansHandlerGET(gateway modules.Gateway, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	bans, err := gateway.Bans()
+	if err != nil {
+		WriteError(w, Error{Message: "unable to get bans: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	scores, err := gateway.PeerScores()
+	if err != nil {
+		WriteError(w, Error{Message: "unable to get peer scores: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	if bans == nil {
+		bans = make([]modules.PeerBan, 0)
+	}
+	WriteJSON(w, GatewayBansGET{
+		Bans:   bans,
+		Scores: scores,
+	})
+}
+
+// gatewayBansHandlerPOST handles the API call to ban and unban addresses.
+func gatewayBansHandlerPOST(gateway modules.Gateway, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var params GatewayBansPOST
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if len(params.Addresses) == 0 {
+		WriteError(w, Error{Message: "no addresses submitted to ban or unban"}, http.StatusBadRequest)
 		return
 	}
 
+	switch params.Action {
+	case "ban":
+		duration := time.Duration(params.Duration) * time.Second
+		if err := gateway.BanPeers(params.Addresses, duration, params.Reason); err != nil {
+			WriteError(w, Error{Message: "failed to ban addresses: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	case "unban":
+		if err := gateway.UnbanPeers(params.Addresses); err != nil {
+			WriteError(w, Error{Message: "failed to unban addresses: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	default:
+		WriteError(w, Error{Message: "invalid action: " + params.Action}, http.StatusBadRequest)
+		return
+	}
 	WriteSuccess(w)
 }
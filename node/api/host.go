@@ -1,10 +1,12 @@
 package api
 
 import (
+	"encoding/csv"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -16,7 +18,7 @@ import (
 var (
 	// errNoPath is returned when a call fails to provide a nonempty string
 	// for the path parameter.
-	errNoPath = Error{"path parameter is required"}
+	errNoPath = Error{Message: "path parameter is required"}
 
 	// errStorageFolderNotFound is returned if a call is made looking for a
 	// storage folder which does not appear to exist within the storage
@@ -36,9 +38,43 @@ var (
 
 type (
 	// ContractInfoGET contains the information that is returned after a GET request
-	// to /host/contracts - information for the host about stored obligations.
+	// to /host/contracts - information for the host about stored obligations
+	// and the resources used by each renter.
 	ContractInfoGET struct {
 		Contracts []modules.StorageObligation `json:"contracts"`
+		Renters   []modules.HostRenterStats   `json:"renters"`
+	}
+
+	// HostAccessGET contains the host's sector access log returned by a GET
+	// request to /host/access. Contracts contains the totals of the entries
+	// per contract, ordered by the number of bytes read and written.
+	HostAccessGET struct {
+		Contracts []HostContractAccess       `json:"contracts"`
+		Entries   []modules.HostSectorAccess `json:"entries"`
+	}
+
+	// HostContractAccess contains the total sector accesses of a contract.
+	HostContractAccess struct {
+		ContractID   types.FileContractID `json:"contractid"`
+		SectorReads  uint64               `json:"sectorreads"`
+		SectorWrites uint64               `json:"sectorwrites"`
+		BytesRead    uint64               `json:"bytesread"`
+		BytesWritten uint64               `json:"byteswritten"`
+	}
+
+	// HostBandwidthGET contains the host's total bandwidth usage since it was
+	// started and its RPC traffic within the current accounting period.
+	HostBandwidthGET struct {
+		GatewayBandwidthGET
+		Caps   modules.HostBandwidthCaps   `json:"caps"`
+		Period modules.HostBandwidthPeriod `json:"period"`
+	}
+
+	// HostContractDecisionsGET contains the host's most recent decisions about
+	// incoming contract requests returned by a GET request to
+	// /host/contractdecisions.
+	HostContractDecisionsGET struct {
+		Decisions []modules.HostContractDecision `json:"decisions"`
 	}
 
 	// HostContractGET contains information about the storage contract returned
@@ -58,6 +94,10 @@ type (
 		PriceTable           modules.RPCPriceTable            `json:"pricetable"`
 		PublicKey            types.SiaPublicKey               `json:"publickey"`
 		WorkingStatus        modules.HostWorkingStatus        `json:"workingstatus"`
+
+		// DiskHealthWarnings contains the warnings of the disk health probes
+		// of the storage folders, prefixed by the storage folders' paths.
+		DiskHealthWarnings []string `json:"diskhealthwarnings"`
 	}
 
 	// HostEstimateScoreGET contains the information that is returned from a
@@ -93,9 +133,27 @@ func RegisterRoutesHost(router *httprouter.Router, h modules.Host, deps modules.
 	router.GET("/host/contracts/:contractID", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		hostContractGetHandler(h, w, req, ps)
 	})
+	router.GET("/host/access", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		hostAccessHandlerGET(h, w, req, ps)
+	})
+	router.GET("/host/contractdecisions", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		hostContractDecisionsHandlerGET(h, w, req, ps)
+	})
+	router.POST("/host/renterlimits", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		hostRenterLimitsHandlerPOST(h, w, req, ps)
+	}, requiredPassword))
 	router.GET("/host/bandwidth", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		hostBandwidthHandlerGET(h, w, req, ps)
 	})
+	router.GET("/host/traffic", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		hostTrafficHandlerGET(h, w, req, ps)
+	})
+	router.GET("/host/registry", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		hostRegistryHandlerGET(h, w, req, ps)
+	})
+	router.GET("/host/revenue", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		hostRevenueHandlerGET(h, w, req, ps)
+	})
 
 	// Calls pertaining to the storage manager that the host uses.
 	router.GET("/host/storage", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
@@ -104,12 +162,18 @@ func RegisterRoutesHost(router *httprouter.Router, h modules.Host, deps modules.
 	router.POST("/host/storage/folders/add", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		storageFoldersAddHandler(h, w, req, ps)
 	}, requiredPassword))
+	router.POST("/host/storage/folders/drain", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		storageFoldersDrainHandler(h, w, req, ps)
+	}, requiredPassword))
 	router.POST("/host/storage/folders/remove", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		storageFoldersRemoveHandler(h, w, req, ps)
 	}, requiredPassword))
 	router.POST("/host/storage/folders/resize", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		storageFoldersResizeHandler(h, w, req, ps)
 	}, requiredPassword))
+	router.GET("/host/storage/scrub", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		storageScrubHandler(h, w, req, ps)
+	})
 	router.POST("/host/storage/sectors/delete/:merkleroot", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		storageSectorsDeleteHandler(h, w, req, ps)
 	}, requiredPassword))
@@ -133,7 +197,7 @@ func hostContractGetHandler(host modules.Host, w http.ResponseWriter, _ *http.Re
 
 	buf, err := hex.DecodeString(contractIDStr)
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("error parsing storage contract id: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("error parsing storage contract id: %v", err)}, http.StatusBadRequest)
 		return
 	}
 
@@ -141,7 +205,7 @@ func hostContractGetHandler(host modules.Host, w http.ResponseWriter, _ *http.Re
 
 	contract, err := host.StorageObligation(obligationID)
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("error get storage contract: %v", err)}, http.StatusNotFound)
+		WriteError(w, Error{Message: fmt.Sprintf("error get storage contract: %v", err)}, http.StatusNotFound)
 		return
 	}
 
@@ -155,10 +219,84 @@ func hostContractGetHandler(host modules.Host, w http.ResponseWriter, _ *http.Re
 func hostContractInfoHandler(host modules.Host, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	cg := ContractInfoGET{
 		Contracts: host.StorageObligations(),
+		Renters:   host.RenterStats(),
 	}
 	WriteJSON(w, cg)
 }
 
+// hostContractDecisionsHandlerGET handles the API call to get the host's most
+// recent decisions about incoming contract requests.
+func hostContractDecisionsHandlerGET(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	decisions := host.ContractDecisions()
+
+	// Filter out accepted contracts if requested.
+	if req.FormValue("rejected") == "true" {
+		var rejected []modules.HostContractDecision
+		for _, d := range decisions {
+			if !d.Accepted {
+				rejected = append(rejected, d)
+			}
+		}
+		decisions = rejected
+	}
+	WriteJSON(w, HostContractDecisionsGET{
+		Decisions: decisions,
+	})
+}
+
+// hostAccessHandlerGET handles GET requests to the /host/access API endpoint.
+func hostAccessHandlerGET(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var filterContract bool
+	var contractID types.FileContractID
+	if id := req.FormValue("contractid"); id != "" {
+		if err := contractID.LoadString(id); err != nil {
+			WriteError(w, Error{Message: "unable to parse contractid: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		filterContract = true
+	}
+	var since int64
+	if s := req.FormValue("since"); s != "" {
+		if _, err := fmt.Sscan(s, &since); err != nil {
+			WriteError(w, Error{Message: "unable to parse since: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Filter the entries and sum them up per contract.
+	entries := []modules.HostSectorAccess{}
+	totals := make(map[types.FileContractID]*HostContractAccess)
+	for _, entry := range host.SectorAccessLog() {
+		if filterContract && entry.ContractID != contractID {
+			continue
+		}
+		if entry.Hour.Unix() < since {
+			continue
+		}
+		entries = append(entries, entry)
+		total, exists := totals[entry.ContractID]
+		if !exists {
+			total = &HostContractAccess{ContractID: entry.ContractID}
+			totals[entry.ContractID] = total
+		}
+		total.SectorReads += entry.SectorReads
+		total.SectorWrites += entry.SectorWrites
+		total.BytesRead += entry.BytesRead
+		total.BytesWritten += entry.BytesWritten
+	}
+	contracts := make([]HostContractAccess, 0, len(totals))
+	for _, total := range totals {
+		contracts = append(contracts, *total)
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].BytesRead+contracts[i].BytesWritten > contracts[j].BytesRead+contracts[j].BytesWritten
+	})
+	WriteJSON(w, HostAccessGET{
+		Contracts: contracts,
+		Entries:   entries,
+	})
+}
+
 // hostHandlerGET handles GET requests to the /host API endpoint, returning key
 // information about the host.
 func hostHandlerGET(host modules.Host, w http.ResponseWriter, deps modules.Dependencies, _ *http.Request, _ httprouter.Params) {
@@ -179,6 +317,12 @@ func hostHandlerGET(host modules.Host, w http.ResponseWriter, deps modules.Depen
 		PriceTable:           pt,
 		PublicKey:            pk,
 		WorkingStatus:        ws,
+		DiskHealthWarnings:   []string{},
+	}
+	for _, sf := range host.StorageFolders() {
+		for _, warning := range sf.Health.Warnings {
+			hg.DiskHealthWarnings = append(hg.DiskHealthWarnings, sf.Path+": "+warning)
+		}
 	}
 
 	if deps.Disrupt("TimeoutOnHostGET") {
@@ -193,16 +337,116 @@ func hostHandlerGET(host modules.Host, w http.ResponseWriter, deps modules.Depen
 func hostBandwidthHandlerGET(host modules.Host, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	sent, receive, startTime, err := host.BandwidthCounters()
 	if err != nil {
-		WriteError(w, Error{"failed to get hosts's bandwidth usage: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to get hosts's bandwidth usage: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
-	WriteJSON(w, GatewayBandwidthGET{
-		Download:  receive,
-		Upload:    sent,
-		StartTime: startTime,
+	WriteJSON(w, HostBandwidthGET{
+		GatewayBandwidthGET: GatewayBandwidthGET{
+			Download:  receive,
+			Upload:    sent,
+			StartTime: startTime,
+		},
+		Caps:   host.InternalSettings().BandwidthCaps,
+		Period: host.BandwidthPeriod(),
 	})
 }
 
+// hostTrafficHandlerGET handles the API call to get the state of the host's
+// traffic prioritization.
+func hostTrafficHandlerGET(host modules.Host, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, host.TrafficStats())
+}
+
+// hostRegistryHandlerGET handles GET requests to the /host/registry API
+// endpoint.
+func hostRegistryHandlerGET(host modules.Host, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, host.RegistryStats())
+}
+
+// revenueCSVTime formats a timestamp of the revenue report for CSV exports.
+// Unknown timestamps are left empty.
+func revenueCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// revenueCSV converts the contracts or the periods of a revenue report into
+// CSV records. Amounts are in hastings.
+func revenueCSV(report modules.HostRevenueReport, exportType string) ([][]string, error) {
+	switch exportType {
+	case "", "contracts":
+		records := [][]string{{"contractid", "renterpublickey", "status", "negotiationheight", "proofdeadline", "negotiationtime", "resolutiontime", "contractcompensation", "storagerevenue", "downloadrevenue", "uploadrevenue", "accountfunding", "transactionfees", "lockedcollateral", "riskedcollateral", "burnedcollateral"}}
+		for _, cr := range report.Contracts {
+			records = append(records, []string{
+				cr.ContractID.String(),
+				cr.RenterPublicKey.String(),
+				cr.Status,
+				fmt.Sprint(cr.NegotiationHeight),
+				fmt.Sprint(cr.ProofDeadline),
+				revenueCSVTime(cr.NegotiationTime),
+				revenueCSVTime(cr.ResolutionTime),
+				cr.ContractCompensation.String(),
+				cr.StorageRevenue.String(),
+				cr.DownloadRevenue.String(),
+				cr.UploadRevenue.String(),
+				cr.AccountFunding.String(),
+				cr.TransactionFees.String(),
+				cr.LockedCollateral.String(),
+				cr.RiskedCollateral.String(),
+				cr.BurnedCollateral.String(),
+			})
+		}
+		return records, nil
+	case "periods":
+		records := [][]string{{"period", "contracts", "contractcompensation", "storagerevenue", "downloadrevenue", "uploadrevenue", "accountfunding", "transactionfees", "lostrevenue", "burnedcollateral"}}
+		for _, rp := range report.Periods {
+			records = append(records, []string{
+				rp.Period,
+				fmt.Sprint(rp.Contracts),
+				rp.ContractCompensation.String(),
+				rp.StorageRevenue.String(),
+				rp.DownloadRevenue.String(),
+				rp.UploadRevenue.String(),
+				rp.AccountFunding.String(),
+				rp.TransactionFees.String(),
+				rp.LostRevenue.String(),
+				rp.BurnedCollateral.String(),
+			})
+		}
+		return records, nil
+	default:
+		return nil, fmt.Errorf("unknown export type %q, must be 'contracts' or 'periods'", exportType)
+	}
+}
+
+// hostRevenueHandlerGET handles GET requests to the /host/revenue API
+// endpoint. The report is returned as JSON or, for accounting software, as
+// CSV.
+func hostRevenueHandlerGET(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	switch format := req.FormValue("format"); format {
+	case "", "json":
+		WriteJSON(w, host.RevenueReport())
+	case "csv":
+		exportType := req.FormValue("type")
+		records, err := revenueCSV(host.RevenueReport(), exportType)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		if exportType == "" {
+			exportType = "contracts"
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="host-revenue-%v.csv"`, exportType))
+		cw := csv.NewWriter(w)
+		_ = cw.WriteAll(records)
+	default:
+		WriteError(w, Error{Message: fmt.Sprintf("unknown format %q, must be 'json' or 'csv'", format)}, http.StatusBadRequest)
+	}
+}
+
 // parseHostSettings a request's query strings and returns a
 // modules.HostInternalSettings configured with the request's query string
 // parameters.
@@ -366,6 +610,171 @@ func parseHostSettings(host modules.Host, req *http.Request) (modules.HostIntern
 	if req.FormValue("customregistrypath") != "" {
 		settings.CustomRegistryPath = req.FormValue("customregistrypath")
 	}
+	if req.FormValue("maxregistryupdaterate") != "" {
+		var x float64
+		_, err := fmt.Sscan(req.FormValue("maxregistryupdaterate"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.MaxRegistryUpdateRate = x
+	}
+	if req.FormValue("accesslogging") != "" {
+		var x bool
+		_, err := fmt.Sscan(req.FormValue("accesslogging"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.AccessLogging = x
+	}
+	if req.FormValue("maintenancemode") != "" {
+		var x bool
+		_, err := fmt.Sscan(req.FormValue("maintenancemode"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.MaintenanceMode = x
+	}
+	if req.FormValue("diskhealthsmart") != "" {
+		var x bool
+		_, err := fmt.Sscan(req.FormValue("diskhealthsmart"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DiskHealthSMART = x
+	}
+	if req.FormValue("minrenterfunds") != "" {
+		var x types.Currency
+		_, err := fmt.Sscan(req.FormValue("minrenterfunds"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.ContractPolicy.MinRenterFunds = x
+	}
+	if req.FormValue("mincompletedcontracts") != "" {
+		var x uint64
+		_, err := fmt.Sscan(req.FormValue("mincompletedcontracts"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.ContractPolicy.MinCompletedContracts = x
+	}
+	if _, ok := req.Form["acceptancewindows"]; ok {
+		windows, err := modules.ParseHostAcceptanceWindows(req.FormValue("acceptancewindows"))
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.ContractPolicy.AcceptanceWindows = windows
+	}
+	limit, err := parseHostRenterLimit(settings.RenterLimits.Default, req, "maxrenter")
+	if err != nil {
+		return modules.HostInternalSettings{}, err
+	}
+	settings.RenterLimits.Default = limit
+	if req.FormValue("maxtrafficbandwidth") != "" {
+		var x int64
+		_, err := fmt.Sscan(req.FormValue("maxtrafficbandwidth"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.Traffic.MaxBandwidth = x
+	}
+	if req.FormValue("trafficpriorityburst") != "" {
+		var x int64
+		_, err := fmt.Sscan(req.FormValue("trafficpriorityburst"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.Traffic.PriorityBurst = x
+	}
+	if req.FormValue("bandwidthperiod") != "" {
+		var x uint64
+		_, err := fmt.Sscan(req.FormValue("bandwidthperiod"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.BandwidthCaps.Period = time.Duration(x) * time.Second
+	}
+	if req.FormValue("maxperiodupload") != "" {
+		var x uint64
+		_, err := fmt.Sscan(req.FormValue("maxperiodupload"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.BandwidthCaps.MaxUpload = x
+	}
+	if req.FormValue("maxperioddownload") != "" {
+		var x uint64
+		_, err := fmt.Sscan(req.FormValue("maxperioddownload"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.BandwidthCaps.MaxDownload = x
+	}
+
+	if req.FormValue("dynamicpricing") != "" {
+		var x bool
+		_, err := fmt.Sscan(req.FormValue("dynamicpricing"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DynamicPricing.Enabled = x
+	}
+	if req.FormValue("dynamicminstorageprice") != "" {
+		var x types.Currency
+		_, err := fmt.Sscan(req.FormValue("dynamicminstorageprice"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DynamicPricing.MinStoragePrice = x
+	}
+	if req.FormValue("dynamicmaxstorageprice") != "" {
+		var x types.Currency
+		_, err := fmt.Sscan(req.FormValue("dynamicmaxstorageprice"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DynamicPricing.MaxStoragePrice = x
+	}
+	if req.FormValue("dynamicmindownloadbandwidthprice") != "" {
+		var x types.Currency
+		_, err := fmt.Sscan(req.FormValue("dynamicmindownloadbandwidthprice"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DynamicPricing.MinDownloadBandwidthPrice = x
+	}
+	if req.FormValue("dynamicmaxdownloadbandwidthprice") != "" {
+		var x types.Currency
+		_, err := fmt.Sscan(req.FormValue("dynamicmaxdownloadbandwidthprice"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DynamicPricing.MaxDownloadBandwidthPrice = x
+	}
+	if req.FormValue("dynamicminuploadbandwidthprice") != "" {
+		var x types.Currency
+		_, err := fmt.Sscan(req.FormValue("dynamicminuploadbandwidthprice"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DynamicPricing.MinUploadBandwidthPrice = x
+	}
+	if req.FormValue("dynamicmaxuploadbandwidthprice") != "" {
+		var x types.Currency
+		_, err := fmt.Sscan(req.FormValue("dynamicmaxuploadbandwidthprice"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DynamicPricing.MaxUploadBandwidthPrice = x
+	}
+	if req.FormValue("dynamicreferencebandwidth") != "" {
+		var x int64
+		_, err := fmt.Sscan(req.FormValue("dynamicreferencebandwidth"), &x)
+		if err != nil {
+			return modules.HostInternalSettings{}, err
+		}
+		settings.DynamicPricing.ReferenceBandwidth = x
+	}
 
 	// Validate the RPC, Sector Access, and Download Prices
 	minBaseRPCPrice := settings.MinBaseRPCPrice
@@ -379,21 +788,118 @@ func parseHostSettings(host modules.Host, req *http.Request) (modules.HostIntern
 		return modules.HostInternalSettings{}, ErrInvalidSectorAccessDownloadRatio
 	}
 
+	// The prices also need to be valid at the lower bound of the dynamic
+	// download bandwidth price.
+	if dp := settings.DynamicPricing; dp.Enabled && !dp.MaxDownloadBandwidthPrice.IsZero() {
+		if minBaseRPCPrice.Cmp(dp.MinDownloadBandwidthPrice.Mul64(modules.MaxBaseRPCPriceVsBandwidth)) > 0 {
+			return modules.HostInternalSettings{}, ErrInvalidRPCDownloadRatio
+		}
+		if minSectorAccessPrice.Cmp(dp.MinDownloadBandwidthPrice.Mul64(modules.MaxSectorAccessPriceVsBandwidth)) > 0 {
+			return modules.HostInternalSettings{}, ErrInvalidSectorAccessDownloadRatio
+		}
+	}
+
 	return settings, nil
 }
 
+// parseHostRenterLimit updates the limit with the limits specified in the
+// request. The names of the parameters are the lowercase names of the limit's
+// fields with the given prefix.
+func parseHostRenterLimit(limit modules.HostRenterLimit, req *http.Request, prefix string) (modules.HostRenterLimit, error) {
+	if req.FormValue(prefix+"bandwidth") != "" {
+		_, err := fmt.Sscan(req.FormValue(prefix+"bandwidth"), &limit.MaxBandwidth)
+		if err != nil {
+			return modules.HostRenterLimit{}, fmt.Errorf("unable to parse %v: %v", prefix+"bandwidth", err)
+		}
+	}
+	if req.FormValue(prefix+"rpcrate") != "" {
+		_, err := fmt.Sscan(req.FormValue(prefix+"rpcrate"), &limit.MaxRPCRate)
+		if err != nil {
+			return modules.HostRenterLimit{}, fmt.Errorf("unable to parse %v: %v", prefix+"rpcrate", err)
+		}
+	}
+	if req.FormValue(prefix+"sessions") != "" {
+		_, err := fmt.Sscan(req.FormValue(prefix+"sessions"), &limit.MaxSessions)
+		if err != nil {
+			return modules.HostRenterLimit{}, fmt.Errorf("unable to parse %v: %v", prefix+"sessions", err)
+		}
+	}
+	if req.FormValue(prefix+"periodupload") != "" {
+		_, err := fmt.Sscan(req.FormValue(prefix+"periodupload"), &limit.MaxPeriodUpload)
+		if err != nil {
+			return modules.HostRenterLimit{}, fmt.Errorf("unable to parse %v: %v", prefix+"periodupload", err)
+		}
+	}
+	if req.FormValue(prefix+"perioddownload") != "" {
+		_, err := fmt.Sscan(req.FormValue(prefix+"perioddownload"), &limit.MaxPeriodDownload)
+		if err != nil {
+			return modules.HostRenterLimit{}, fmt.Errorf("unable to parse %v: %v", prefix+"perioddownload", err)
+		}
+	}
+	return limit, nil
+}
+
+// hostRenterLimitsHandlerPOST handles the API call to set or remove the limit
+// override of a single renter.
+func hostRenterLimitsHandlerPOST(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var renterKey types.SiaPublicKey
+	if err := renterKey.LoadString(req.FormValue("renterkey")); err != nil {
+		WriteError(w, Error{Message: "unable to parse renterkey: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var remove bool
+	if req.FormValue("remove") != "" {
+		var err error
+		remove, err = scanBool(req.FormValue("remove"))
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse remove: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Copy the overrides to not modify the map of the host's settings.
+	settings := host.InternalSettings()
+	overrides := make(map[string]modules.HostRenterLimit, len(settings.RenterLimits.Overrides)+1)
+	for key, limit := range settings.RenterLimits.Overrides {
+		overrides[key] = limit
+	}
+	if remove {
+		delete(overrides, renterKey.String())
+	} else {
+		// New overrides start out with the default limit.
+		limit, exists := overrides[renterKey.String()]
+		if !exists {
+			limit = settings.RenterLimits.Default
+		}
+		limit, err := parseHostRenterLimit(limit, req, "max")
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		overrides[renterKey.String()] = limit
+	}
+	settings.RenterLimits.Overrides = overrides
+
+	err := host.SetInternalSettings(settings)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // hostEstimateScoreGET handles the POST request to /host/estimatescore and
 // computes an estimated HostDB score for the provided settings.
 func hostEstimateScoreGET(host modules.Host, renter modules.Renter, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// This call requires a renter, check that it is present.
 	if renter == nil {
-		WriteError(w, Error{"cannot call /host/estimatescore without the renter module"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "cannot call /host/estimatescore without the renter module"}, http.StatusBadRequest)
 		return
 	}
 
 	settings, err := parseHostSettings(host, req)
 	if err != nil {
-		WriteError(w, Error{"error parsing host settings: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error parsing host settings: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	var totalStorage, remainingStorage uint64
@@ -431,7 +937,7 @@ func hostEstimateScoreGET(host modules.Host, renter modules.Renter, w http.Respo
 	// allowance the renters may use to attempt to access this host.
 	estimatedScoreBreakdown, err := renter.EstimateHostScore(entry, modules.DefaultAllowance)
 	if err != nil {
-		WriteError(w, Error{"error estimating host score: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "error estimating host score: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
 	e := HostEstimateScoreGET{
@@ -444,15 +950,39 @@ func hostEstimateScoreGET(host modules.Host, renter modules.Renter, w http.Respo
 // hostHandlerPOST handles POST request to the /host API endpoint, which sets
 // the internal settings of the host.
 func hostHandlerPOST(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var dryRun bool
+	if dr := req.FormValue("dryrun"); dr != "" {
+		var err error
+		dryRun, err = scanBool(dr)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse dryrun: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
 	settings, err := parseHostSettings(host, req)
 	if err != nil {
-		WriteError(w, Error{"error parsing host settings: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error parsing host settings: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	// Validate the settings. A dry run only returns the analysis.
+	validation, err := host.ValidateInternalSettings(settings)
+	if err != nil {
+		WriteError(w, Error{Message: "unable to validate host settings: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	if dryRun {
+		WriteJSON(w, validation)
+		return
+	}
+	if err := validation.Err(); err != nil {
+		WriteError(w, Error{Message: "invalid host settings: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	err = host.SetInternalSettings(settings)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -468,7 +998,7 @@ func hostAnnounceHandler(host modules.Host, w http.ResponseWriter, req *http.Req
 		err = host.Announce()
 	}
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -482,18 +1012,29 @@ func storageHandler(host modules.Host, w http.ResponseWriter, _ *http.Request, _
 	})
 }
 
+// storageScrubHandler returns the progress of the storage manager's integrity
+// scrubber and the contracts at risk because of quarantined sectors.
+func storageScrubHandler(host modules.Host, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	report, err := host.ScrubReport()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get scrub report: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, report)
+}
+
 // storageFoldersAddHandler adds a storage folder to the storage manager.
 func storageFoldersAddHandler(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	folderPath := req.FormValue("path")
 	var folderSize uint64
 	_, err := fmt.Sscan(req.FormValue("size"), &folderSize)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err = host.AddStorageFolder(folderPath, folderSize)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -503,26 +1044,58 @@ func storageFoldersAddHandler(host modules.Host, w http.ResponseWriter, req *htt
 func storageFoldersResizeHandler(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	folderPath := req.FormValue("path")
 	if folderPath == "" {
-		WriteError(w, Error{"path parameter is required"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "path parameter is required"}, http.StatusBadRequest)
 		return
 	}
 
 	storageFolders := host.StorageFolders()
 	folderIndex, err := folderIndex(folderPath, storageFolders)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	var newSize uint64
 	_, err = fmt.Sscan(req.FormValue("newsize"), &newSize)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err = host.ResizeStorageFolder(uint16(folderIndex), newSize, false)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// storageFoldersDrainHandler marks a storage folder for removal. The storage
+// folder's sectors are migrated to other storage folders in the background.
+func storageFoldersDrainHandler(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	folderPath := req.FormValue("path")
+	if folderPath == "" {
+		WriteError(w, Error{Message: "path parameter is required"}, http.StatusBadRequest)
+		return
+	}
+
+	storageFolders := host.StorageFolders()
+	folderIndex, err := folderIndex(folderPath, storageFolders)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	drain := true
+	if d := req.FormValue("drain"); d != "" {
+		drain, err = scanBool(d)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse drain: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	err = host.DrainStorageFolder(uint16(folderIndex), drain)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -533,21 +1106,21 @@ func storageFoldersResizeHandler(host modules.Host, w http.ResponseWriter, req *
 func storageFoldersRemoveHandler(host modules.Host, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	folderPath := req.FormValue("path")
 	if folderPath == "" {
-		WriteError(w, Error{"path parameter is required"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "path parameter is required"}, http.StatusBadRequest)
 		return
 	}
 
 	storageFolders := host.StorageFolders()
 	folderIndex, err := folderIndex(folderPath, storageFolders)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	force := req.FormValue("force") == "true"
 	err = host.RemoveStorageFolder(uint16(folderIndex), force)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -558,12 +1131,12 @@ func storageFoldersRemoveHandler(host modules.Host, w http.ResponseWriter, req *
 func storageSectorsDeleteHandler(host modules.Host, w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
 	sectorRoot, err := scanHash(ps.ByName("merkleroot"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err = host.DeleteSector(sectorRoot)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
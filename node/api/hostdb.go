@@ -64,7 +64,7 @@ type (
 func (api *API) hostdbHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	isc, err := api.renter.InitialScanComplete()
 	if err != nil {
-		WriteError(w, Error{"Failed to get initial scan status: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "Failed to get initial scan status: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
 	WriteJSON(w, HostdbGet{
@@ -78,7 +78,7 @@ func (api *API) hostdbActiveHandler(w http.ResponseWriter, req *http.Request, _
 	var numHosts uint64
 	hosts, err := api.renter.ActiveHosts()
 	if err != nil {
-		WriteError(w, Error{"unable to get active hosts: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to get active hosts: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -89,7 +89,7 @@ func (api *API) hostdbActiveHandler(w http.ResponseWriter, req *http.Request, _
 		// Parse the value for 'numhosts'.
 		_, err := fmt.Sscan(req.FormValue("numhosts"), &numHosts)
 		if err != nil {
-			WriteError(w, Error{"unable to parse numhosts: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse numhosts: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 
@@ -118,7 +118,7 @@ func (api *API) hostdbAllHandler(w http.ResponseWriter, _ *http.Request, _ httpr
 	// Get the set of all hosts and convert them into extended hosts.
 	hosts, err := api.renter.AllHosts()
 	if err != nil {
-		WriteError(w, Error{"unable to get all hosts: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to get all hosts: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	var extendedHosts []ExtendedHostDBEntry
@@ -142,16 +142,16 @@ func (api *API) hostdbHostsHandler(w http.ResponseWriter, _ *http.Request, ps ht
 
 	entry, exists, err := api.renter.Host(pk)
 	if err != nil {
-		WriteError(w, Error{"unable to get host: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to get host: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	if !exists {
-		WriteError(w, Error{"requested host does not exist"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "requested host does not exist"}, http.StatusBadRequest)
 		return
 	}
 	breakdown, err := api.renter.ScoreBreakdown(entry)
 	if err != nil {
-		WriteError(w, Error{"error calculating score breakdown: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "error calculating score breakdown: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
 
@@ -172,7 +172,7 @@ func (api *API) hostdbFilterModeHandlerGET(w http.ResponseWriter, _ *http.Reques
 	// Get FilterMode
 	fm, hostMap, netAddresses, err := api.renter.Filter()
 	if err != nil {
-		WriteError(w, Error{"unable to get filter mode: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to get filter mode: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	// Build Slice of PubKeys
@@ -194,19 +194,19 @@ func (api *API) hostdbFilterModeHandlerPOST(w http.ResponseWriter, req *http.Req
 	var params HostdbFilterModePOST
 	err := json.NewDecoder(req.Body).Decode(&params)
 	if err != nil {
-		WriteError(w, Error{"invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	var fm modules.FilterMode
 	if err = fm.FromString(params.FilterMode); err != nil {
-		WriteError(w, Error{"unable to load filter mode from string: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to load filter mode from string: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Set list mode
 	if err := api.renter.SetFilterMode(fm, params.Hosts, params.NetAddresses); err != nil {
-		WriteError(w, Error{"failed to set the list mode: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to set the list mode: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
 
@@ -19,6 +20,18 @@ type (
 		CPUMining        bool `json:"cpumining"`
 		StaleBlocksMined int  `json:"staleblocksmined"`
 	}
+
+	// MinerPayoutsGET contains the addresses the block reward is split
+	// across.
+	MinerPayoutsGET struct {
+		Splits []modules.MinerPayoutSplit `json:"splits"`
+	}
+
+	// MinerStratumGET contains statistics about the stratum server of the
+	// miner.
+	MinerStratumGET struct {
+		modules.StratumStats
+	}
 )
 
 // RegisterRoutesMiner is a helper function to register all miner routes.
@@ -35,9 +48,21 @@ func RegisterRoutesMiner(router *httprouter.Router, m modules.Miner, requiredPas
 	router.POST("/miner/header", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		minerHeaderHandlerPOST(m, w, req, ps)
 	}, requiredPassword))
+	router.GET("/miner/payouts", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		minerPayoutsHandlerGET(m, w, req, ps)
+	})
+	router.POST("/miner/payouts", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		minerPayoutsHandlerPOST(m, w, req, ps)
+	}, requiredPassword))
 	router.GET("/miner/start", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		minerStartHandler(m, w, req, ps)
 	}, requiredPassword))
+	router.GET("/miner/stratum", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		minerStratumHandlerGET(m, w, req, ps)
+	})
+	router.POST("/miner/stratum", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		minerStratumHandlerPOST(m, w, req, ps)
+	}, requiredPassword))
 	router.GET("/miner/stop", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		minerStopHandler(m, w, req, ps)
 	}, requiredPassword))
@@ -67,12 +92,73 @@ func minerStopHandler(miner modules.Miner, w http.ResponseWriter, _ *http.Reques
 	WriteSuccess(w)
 }
 
+// minerPayoutsHandlerGET handles the API call that queries the addresses the
+// block reward is split across.
+func minerPayoutsHandlerGET(miner modules.Miner, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	splits := miner.PayoutSplits()
+	if splits == nil {
+		splits = []modules.MinerPayoutSplit{}
+	}
+	WriteJSON(w, MinerPayoutsGET{Splits: splits})
+}
+
+// minerPayoutsHandlerPOST handles the API call that sets the addresses the
+// block reward is split across. An empty value pays the whole reward to the
+// wallet.
+func minerPayoutsHandlerPOST(miner modules.Miner, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if err := req.ParseForm(); err != nil {
+		WriteError(w, Error{Message: "unable to parse form: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if _, exists := req.Form["splits"]; !exists {
+		WriteError(w, Error{Message: "splits must be provided"}, http.StatusBadRequest)
+		return
+	}
+	var splits []modules.MinerPayoutSplit
+	for _, str := range strings.Split(req.FormValue("splits"), ",") {
+		if str = strings.TrimSpace(str); str == "" {
+			continue
+		}
+		split, err := modules.ParseMinerPayoutSplit(str)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse splits: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		splits = append(splits, split)
+	}
+	if err := miner.SetPayoutSplits(splits); err != nil {
+		WriteError(w, Error{Message: "failed to set payout splits: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// minerStratumHandlerGET handles the API call that queries the statistics of
+// the stratum server.
+func minerStratumHandlerGET(miner modules.Miner, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, MinerStratumGET{miner.StratumStats()})
+}
+
+// minerStratumHandlerPOST handles the API call that starts the stratum server.
+func minerStratumHandlerPOST(miner modules.Miner, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	addr := req.FormValue("addr")
+	if addr == "" {
+		WriteError(w, Error{Message: "addr must be provided"}, http.StatusBadRequest)
+		return
+	}
+	if err := miner.StartStratum(addr); err != nil {
+		WriteError(w, Error{Message: "failed to start stratum server: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // minerHeaderHandlerGET handles the API call that retrieves a block header
 // for work.
 func minerHeaderHandlerGET(miner modules.Miner, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	bhfw, target, err := miner.HeaderForWork()
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	w.Write(encoding.MarshalAll(target, bhfw))
@@ -84,12 +170,12 @@ func minerHeaderHandlerPOST(miner modules.Miner, w http.ResponseWriter, req *htt
 	var bh types.BlockHeader
 	err := encoding.NewDecoder(req.Body, encoding.DefaultAllocLimit).Decode(&bh)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err = miner.SubmitHeader(bh)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -101,12 +187,12 @@ func minerBlockHandlerPOST(miner modules.Miner, w http.ResponseWriter, req *http
 	var b types.Block
 	err := encoding.NewDecoder(req.Body, encoding.DefaultAllocLimit).Decode(&b)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err = miner.SubmitBlock(b)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -68,6 +71,14 @@ var (
 		Testing:  types.BlockHeight(1),
 	}).(types.BlockHeight)
 
+	// defaultRenterEventsTimeout is the default amount of time a request to
+	// /renter/events waits for new events.
+	defaultRenterEventsTimeout = 30 * time.Second
+
+	// maxRenterEventsTimeout is the maximum amount of time a request to
+	// /renter/events waits for new events.
+	maxRenterEventsTimeout = 5 * time.Minute
+
 	// errNeedBothDataAndParityPieces is the error returned when only one of the
 	// erasure coding parameters is set
 	errNeedBothDataAndParityPieces = errors.New("must provide both the datapieces parameter and the paritypieces parameter if specifying erasure coding parameters")
@@ -89,7 +100,8 @@ type (
 		CurrentPeriod    types.BlockHeight          `json:"currentperiod"`
 		NextPeriod       types.BlockHeight          `json:"nextperiod"`
 
-		MemoryStatus modules.MemoryStatus `json:"memorystatus"`
+		MemoryStatus   modules.MemoryStatus         `json:"memorystatus"`
+		RepairSchedule modules.RepairScheduleStatus `json:"repairschedule"`
 	}
 
 	// RenterContract represents a contract formed by the renter.
@@ -143,6 +155,9 @@ type (
 		GoodForRenew bool `json:"goodforrenew"`
 		// Signals if a contract has been marked as bad
 		BadContract bool `json:"badcontract"`
+		// The recorded cumulative spending of the contract over time. Only
+		// set if requested.
+		SpendingHistory []modules.ContractSpendingSnapshot `json:"spendinghistory,omitempty"`
 	}
 
 	// RenterContracts contains the renter's contracts.
@@ -161,6 +176,18 @@ type (
 		RecoverableContracts      []modules.RecoverableContract `json:"recoverablecontracts"`
 	}
 
+	// RenterDirDeletions contains the status of the renter's recent background
+	// directory deletions.
+	RenterDirDeletions struct {
+		Deletions []modules.DirDeletionStatus `json:"deletions"`
+	}
+
+	// RenterFileReencodes contains the status of the renter's recent file
+	// re-encodes.
+	RenterFileReencodes struct {
+		Reencodes []modules.FileReencodeStatus `json:"reencodes"`
+	}
+
 	// RenterDirectory lists the files and directories contained in the queried
 	// directory
 	RenterDirectory struct {
@@ -171,6 +198,10 @@ type (
 	// RenterDownloadQueue contains the renter's download queue.
 	RenterDownloadQueue struct {
 		Downloads []DownloadInfo `json:"downloads"`
+
+		// Overdrive contains the overdrive policy and recent chunk latencies
+		// of every download class.
+		Overdrive map[modules.DownloadRequestClass]modules.DownloadOverdriveStatus `json:"overdrive"`
 	}
 
 	// RenterFile lists the file queried.
@@ -180,7 +211,8 @@ type (
 
 	// RenterFiles lists the files known to the renter.
 	RenterFiles struct {
-		Files []modules.FileInfo `json:"files"`
+		Files  []modules.FileInfo       `json:"files"`
+		Defrag modules.FileDefragStatus `json:"defrag"`
 	}
 
 	// RenterFuseInfo contains information about mounted fuse filesystems.
@@ -226,6 +258,42 @@ type (
 		UnsyncedHosts []types.SiaPublicKey   `json:"unsyncedhosts"`
 	}
 
+	// RenterDirsHealthGET contains the aggregated health of the subtrees of a
+	// directory and its subdirectories, worst health first.
+	RenterDirsHealthGET struct {
+		Directories []modules.DirectoryHealth `json:"directories"`
+	}
+
+	// RenterEventsGET contains the events of the renter's event stream.
+	// LastID is the ID to pass to the next request to continue the stream.
+	RenterEventsGET struct {
+		Events []modules.RenterEvent `json:"events"`
+		LastID uint64                `json:"lastid"`
+	}
+
+	// RenterArchiveGET contains the files and directories of the archive
+	// tier.
+	RenterArchiveGET struct {
+		ArchivedPaths []modules.SiaPath `json:"archivedpaths"`
+	}
+
+	// RenterDirsPinnedGET contains the directories whose subtrees are repaired
+	// before any other directories.
+	RenterDirsPinnedGET struct {
+		PinnedDirs []modules.SiaPath `json:"pinneddirs"`
+	}
+
+	// RenterBackupScheduleGET contains the settings of the renter's scheduled
+	// backups and the scheduled backups the renter knows about, ordered from
+	// newest to oldest.
+	RenterBackupScheduleGET struct {
+		modules.BackupScheduleSettings
+		InProgress bool                   `json:"inprogress"`
+		LastBackup time.Time              `json:"lastbackup"`
+		LastError  string                 `json:"lasterror"`
+		Backups    []RenterUploadedBackup `json:"backups"`
+	}
+
 	// RenterUploadReadyGet lists the upload ready status of the renter
 	RenterUploadReadyGet struct {
 		// Ready indicates whether of not the renter is ready to successfully
@@ -242,6 +310,41 @@ type (
 		ParityPieces int `json:"paritypieces"`
 	}
 
+	// RenterDeletePOST contains the results of a batch deletion.
+	RenterDeletePOST struct {
+		Results []modules.FileDeletionResult `json:"results"`
+	}
+
+	// RenterWorkerJobHistoryGET contains the renter's failed worker jobs.
+	RenterWorkerJobHistoryGET struct {
+		Failures []modules.WorkerJobFailure `json:"failures"`
+	}
+
+	// RenterUploadSessionsGET contains the renter's unfinished upload
+	// sessions.
+	RenterUploadSessionsGET struct {
+		Sessions []modules.UploadSession `json:"sessions"`
+	}
+
+	// RenterRegistryGET contains a registry value which was read from the
+	// hosts' registries.
+	RenterRegistryGET struct {
+		Data      string                    `json:"data"`
+		Revision  uint64                    `json:"revision"`
+		Signature string                    `json:"signature"`
+		Type      modules.RegistryEntryType `json:"type"`
+	}
+
+	// RenterRegistryPOST is the request body of /renter/registry [POST].
+	RenterRegistryPOST struct {
+		PublicKey types.SiaPublicKey        `json:"publickey"`
+		DataKey   crypto.Hash               `json:"datakey"`
+		Revision  uint64                    `json:"revision"`
+		Signature crypto.Signature          `json:"signature"`
+		Data      []byte                    `json:"data"`
+		Type      modules.RegistryEntryType `json:"type"`
+	}
+
 	// DownloadInfo contains all client-facing information of a file.
 	DownloadInfo struct {
 		Destination     string          `json:"destination"`     // The destination of the download.
@@ -258,6 +361,13 @@ type (
 		StartTime            time.Time `json:"starttime"`            // The time when the download was started.
 		StartTimeUnix        int64     `json:"starttimeunix"`        // The time when the download was started in unix format.
 		TotalDataTransferred uint64    `json:"totaldatatransferred"` // The total amount of data transferred, including negotiation, overdrive etc.
+
+		CostWeight    float64        `json:"costweight"`    // How much host prices were weighed against host speed.
+		EstimatedCost types.Currency `json:"estimatedcost"` // The estimated cost of fetching the pieces from the preferred hosts.
+		MaxSpeed      uint64         `json:"maxspeed"`      // The per-download bandwidth cap in bytes per second, 0 if uncapped.
+
+		RequestClass    modules.DownloadRequestClass `json:"requestclass"`    // The class of the download which determines its overdrive policy.
+		OverdrivePieces uint64                       `json:"overdrivepieces"` // The number of pieces fetched beyond the minimum required to recover the chunks.
 	}
 )
 
@@ -324,6 +434,141 @@ func trimDownloadInfo(dis ...modules.DownloadInfo) (_ []modules.DownloadInfo, er
 	return dis, nil
 }
 
+// trimRestoreDrillResults is a helper method to trim /home/siafiles off of the
+// siapaths of the restore drill results since the user expects a path relative
+// to /home/siafiles and not relative to root.
+func trimRestoreDrillResults(rdrs ...modules.RestoreDrillResult) (_ []modules.RestoreDrillResult, err error) {
+	for i := range rdrs {
+		rdrs[i].SiaPath, err = rdrs[i].SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rdrs, nil
+}
+
+// trimUploadSessions is a helper method to trim /home/siafiles off of the
+// siapaths of the upload sessions since the user expects a path relative to
+// /home/siafiles and not relative to root.
+func trimUploadSessions(uss ...modules.UploadSession) (_ []modules.UploadSession, err error) {
+	for i := range uss {
+		uss[i].SiaPath, err = uss[i].SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uss, nil
+}
+
+// trimDirDeletions is a helper method to trim /home/siafiles off of the
+// siapaths of the directory deletions since the user expects a path relative
+// to /home/siafiles and not relative to root. Deletions of directories outside
+// of /home/siafiles are omitted.
+func trimDirDeletions(dds ...modules.DirDeletionStatus) []modules.DirDeletionStatus {
+	trimmed := make([]modules.DirDeletionStatus, 0, len(dds))
+	for _, dd := range dds {
+		sp, err := dd.SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			continue
+		}
+		dd.SiaPath = sp
+		trimmed = append(trimmed, dd)
+	}
+	return trimmed
+}
+
+// trimFileReencodes is a helper method to trim /home/siafiles off of the
+// siapaths of the file re-encodes since the user expects a path relative to
+// /home/siafiles and not relative to root. Re-encodes of files outside of
+// /home/siafiles are omitted.
+func trimFileReencodes(frs ...modules.FileReencodeStatus) []modules.FileReencodeStatus {
+	trimmed := make([]modules.FileReencodeStatus, 0, len(frs))
+	for _, fr := range frs {
+		sp, err := fr.SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			continue
+		}
+		fr.SiaPath = sp
+		trimmed = append(trimmed, fr)
+	}
+	return trimmed
+}
+
+// trimRenterEvents is a helper method to trim /home/siafiles off of the
+// siapaths of file events. Events of files outside of /home/siafiles are
+// dropped.
+func trimRenterEvents(events ...modules.RenterEvent) []modules.RenterEvent {
+	trimmed := make([]modules.RenterEvent, 0, len(events))
+	for _, e := range events {
+		if !e.SiaPath.IsRoot() {
+			sp, err := e.SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+			if err != nil {
+				continue
+			}
+			e.SiaPath = sp
+		}
+		trimmed = append(trimmed, e)
+	}
+	return trimmed
+}
+
+// trimDirectoryHealths is a helper method to trim /home/siafiles off of the
+// siapaths of the directory healths since the user expects a path relative to
+// /home/siafiles and not relative to root.
+func trimDirectoryHealths(dhs ...modules.DirectoryHealth) (_ []modules.DirectoryHealth, err error) {
+	for i := range dhs {
+		dhs[i].SiaPath, err = dhs[i].SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dhs, nil
+}
+
+// trimSiaPaths is a helper method to trim /home/siafiles off of siapaths since
+// the user expects a path relative to /home/siafiles and not relative to root.
+// Siapaths outside of /home/siafiles are omitted.
+func trimSiaPaths(siaPaths ...modules.SiaPath) []modules.SiaPath {
+	trimmed := make([]modules.SiaPath, 0, len(siaPaths))
+	for _, siaPath := range siaPaths {
+		if !modules.UserFolder.Contains(siaPath) {
+			continue
+		}
+		sp, err := siaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			continue
+		}
+		trimmed = append(trimmed, sp)
+	}
+	return trimmed
+}
+
+// parseDirSiaPath parses the siapath of a directory from the request's params.
+// Unless the request sets the root flag, the siapath is relative to the user's
+// home directory.
+func parseDirSiaPath(req *http.Request, ps httprouter.Params) (siaPath modules.SiaPath, root bool, err error) {
+	root, err = isCalledWithRootFlag(req)
+	if err != nil {
+		return modules.SiaPath{}, false, err
+	}
+	str := ps.ByName("siapath")
+	if str == "" || str == "/" {
+		siaPath = modules.RootSiaPath()
+	} else {
+		siaPath, err = modules.NewSiaPath(str)
+		if err != nil {
+			return modules.SiaPath{}, false, err
+		}
+	}
+	if !root {
+		siaPath, err = rebaseInputSiaPath(siaPath)
+		if err != nil {
+			return modules.SiaPath{}, false, err
+		}
+	}
+	return siaPath, root, nil
+}
+
 // renterBubbleHandlerPOST handles the API calls to /renter/bubble.
 func (api *API) renterBubbleHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// Parse the 'rootsiapath' parameter
@@ -332,7 +577,7 @@ func (api *API) renterBubbleHandlerPOST(w http.ResponseWriter, req *http.Request
 	if r := req.FormValue("rootsiapath"); r != "" {
 		rootSiaPath, err = scanBool(r)
 		if err != nil {
-			WriteError(w, Error{"unable to parse 'rootsiapath' parameter: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse 'rootsiapath' parameter: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -341,11 +586,11 @@ func (api *API) renterBubbleHandlerPOST(w http.ResponseWriter, req *http.Request
 	var siaPath modules.SiaPath
 	s := req.FormValue("siapath")
 	if rootSiaPath && s != "" {
-		WriteError(w, Error{"rootsiapath and non empty siapath cannot both be used"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "rootsiapath and non empty siapath cannot both be used"}, http.StatusBadRequest)
 		return
 	}
 	if !rootSiaPath && s == "" {
-		WriteError(w, Error{"rootsiapath should be true if no siapath is provided"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "rootsiapath should be true if no siapath is provided"}, http.StatusBadRequest)
 		return
 	}
 	if rootSiaPath {
@@ -353,7 +598,7 @@ func (api *API) renterBubbleHandlerPOST(w http.ResponseWriter, req *http.Request
 	} else {
 		err = siaPath.LoadString(s)
 		if err != nil {
-			WriteError(w, Error{"unable to parse siapath: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse siapath: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -363,7 +608,7 @@ func (api *API) renterBubbleHandlerPOST(w http.ResponseWriter, req *http.Request
 	if f := req.FormValue("force"); f != "" {
 		force, err = scanBool(f)
 		if err != nil {
-			WriteError(w, Error{"unable to parse 'force' parameter: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse 'force' parameter: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -372,7 +617,7 @@ func (api *API) renterBubbleHandlerPOST(w http.ResponseWriter, req *http.Request
 	if r := req.FormValue("recursive"); r != "" {
 		recursive, err = strconv.ParseBool(r)
 		if err != nil {
-			WriteError(w, Error{"unable to parse 'recursive' parameter: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse 'recursive' parameter: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -380,7 +625,7 @@ func (api *API) renterBubbleHandlerPOST(w http.ResponseWriter, req *http.Request
 	// Call bubble
 	err = api.renter.BubbleMetadata(siaPath, force, recursive)
 	if err != nil {
-		WriteError(w, Error{"unable to bubble directory: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "unable to bubble directory: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 		return
 	}
 	WriteSuccess(w)
@@ -391,7 +636,7 @@ func (api *API) renterBubbleHandlerPOST(w http.ResponseWriter, req *http.Request
 func (api *API) renterBackupsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	backups, syncedHosts, err := api.renter.UploadedBackups()
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	var unsyncedHosts []types.SiaPublicKey
@@ -410,24 +655,19 @@ outer:
 		var hostKey types.SiaPublicKey
 		hostKey.LoadString(req.FormValue("host"))
 		if hostKey.Key == nil {
-			WriteError(w, Error{"invalid host public key"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "invalid host public key"}, http.StatusBadRequest)
 			return
 		}
 		backups, err = api.renter.BackupsOnHost(hostKey)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 			return
 		}
 	}
 
 	rups := make([]RenterUploadedBackup, len(backups))
 	for i, b := range backups {
-		rups[i] = RenterUploadedBackup{
-			Name:           b.Name,
-			CreationDate:   b.CreationDate,
-			Size:           b.Size,
-			UploadProgress: b.UploadProgress,
-		}
+		rups[i] = renterUploadedBackup(b)
 	}
 	WriteJSON(w, RenterBackupsGET{
 		Backups:       rups,
@@ -441,14 +681,14 @@ func (api *API) renterBackupsCreateHandlerPOST(w http.ResponseWriter, req *http.
 	// Check that a name was specified.
 	name := req.FormValue("name")
 	if name == "" {
-		WriteError(w, Error{"name not specified"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "name not specified"}, http.StatusBadRequest)
 		return
 	}
 
 	// Write the backup to a temporary file and delete it after uploading.
 	tmpDir, err := ioutil.TempDir("", "sia-backup")
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	randomSuffix := persist.RandomSuffix()
@@ -462,7 +702,7 @@ func (api *API) renterBackupsCreateHandlerPOST(w http.ResponseWriter, req *http.
 	// Get the wallet seed.
 	ws, _, err := api.wallet.PrimarySeed()
 	if err != nil {
-		WriteError(w, Error{"failed to get wallet's primary seed"}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "failed to get wallet's primary seed"}, http.StatusInternalServerError)
 		return
 	}
 	// Derive the renter seed and wipe the memory once we are done using it.
@@ -473,12 +713,12 @@ func (api *API) renterBackupsCreateHandlerPOST(w http.ResponseWriter, req *http.
 	defer fastrand.Read(secret[:])
 	// Create the backup.
 	if err := api.renter.CreateBackup(backupPath, secret[:32]); err != nil {
-		WriteError(w, Error{"failed to create backup: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to create backup: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	// Upload the backup.
 	if err := api.renter.UploadBackup(backupPath, name); err != nil {
-		WriteError(w, Error{"failed to upload backup: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to upload backup: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -489,13 +729,13 @@ func (api *API) renterBackupsRestoreHandlerGET(w http.ResponseWriter, req *http.
 	// Check that a name was specified.
 	name := req.FormValue("name")
 	if name == "" {
-		WriteError(w, Error{"name not specified"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "name not specified"}, http.StatusBadRequest)
 		return
 	}
 	// Write the backup to a temporary file and delete it after loading.
 	tmpDir, err := ioutil.TempDir("", "sia-backup")
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	defer func() {
@@ -503,13 +743,13 @@ func (api *API) renterBackupsRestoreHandlerGET(w http.ResponseWriter, req *http.
 	}()
 	backupPath := filepath.Join(tmpDir, name)
 	if err := api.renter.DownloadBackup(backupPath, name); err != nil {
-		WriteError(w, Error{"failed to download backup: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to download backup: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	// Get the wallet seed.
 	ws, _, err := api.wallet.PrimarySeed()
 	if err != nil {
-		WriteError(w, Error{"failed to get wallet's primary seed"}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "failed to get wallet's primary seed"}, http.StatusInternalServerError)
 		return
 	}
 	// Derive the renter seed and wipe the memory once we are done using it.
@@ -520,29 +760,125 @@ func (api *API) renterBackupsRestoreHandlerGET(w http.ResponseWriter, req *http.
 	defer fastrand.Read(secret[:])
 	// Load the backup.
 	if err := api.renter.LoadBackup(backupPath, secret[:32]); err != nil {
-		WriteError(w, Error{"failed to load backup: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to load backup: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterUploadedBackup converts an uploaded backup into its API
+// representation.
+func renterUploadedBackup(b modules.UploadedBackup) RenterUploadedBackup {
+	return RenterUploadedBackup{
+		Name:           b.Name,
+		CreationDate:   b.CreationDate,
+		Size:           b.Size,
+		UploadProgress: b.UploadProgress,
+	}
+}
+
+// renterBackupsPruneHandlerPOST handles the API calls to /renter/backups/prune
+func (api *API) renterBackupsPruneHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Check that a name was specified.
+	name := req.FormValue("name")
+	if name == "" {
+		WriteError(w, Error{Message: "name not specified"}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.PruneBackup(name); err != nil {
+		WriteError(w, Error{Message: "failed to prune backup: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterBackupsScheduleHandlerGET handles the API calls to
+// /renter/backups/schedule
+func (api *API) renterBackupsScheduleHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	status, err := api.renter.BackupScheduleStatus()
+	if err != nil {
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	backups := make([]RenterUploadedBackup, 0, len(status.Backups))
+	for _, b := range status.Backups {
+		backups = append(backups, renterUploadedBackup(b))
+	}
+	WriteJSON(w, RenterBackupScheduleGET{
+		BackupScheduleSettings: status.BackupScheduleSettings,
+		InProgress:             status.InProgress,
+		LastBackup:             status.LastBackup,
+		LastError:              status.LastError,
+		Backups:                backups,
+	})
+}
+
+// renterBackupsScheduleHandlerPOST handles the API call to update the settings
+// of the renter's scheduled backups.
+func (api *API) renterBackupsScheduleHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	status, err := api.renter.BackupScheduleStatus()
+	if err != nil {
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	settings := status.BackupScheduleSettings
+	if e := req.FormValue("enabled"); e != "" {
+		settings.Enabled, err = scanBool(e)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'enabled': " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if i := req.FormValue("interval"); i != "" {
+		var seconds uint64
+		if _, err := fmt.Sscan(i, &seconds); err != nil {
+			WriteError(w, Error{Message: "unable to parse 'interval': " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Interval = time.Duration(seconds) * time.Second
+	}
+	if g := req.FormValue("generations"); g != "" {
+		if _, err := fmt.Sscan(g, &settings.Generations); err != nil {
+			WriteError(w, Error{Message: "unable to parse 'generations': " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	err = api.renter.SetBackupScheduleSettings(settings)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to update backup schedule settings: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
 }
 
+// renterBackupsScheduleRunHandlerPOST handles the API call to create a
+// scheduled backup right away.
+func (api *API) renterBackupsScheduleRunHandlerPOST(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	ub, err := api.renter.RunScheduledBackup()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to run scheduled backup: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, renterUploadedBackup(ub))
+}
+
 // renterBackupHandlerPOST handles the API calls to /renter/backup
 func (api *API) renterBackupHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// Check that destination was specified.
 	dst := req.FormValue("destination")
 	if dst == "" {
-		WriteError(w, Error{"destination not specified"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "destination not specified"}, http.StatusBadRequest)
 		return
 	}
 	// The destination needs to be an absolute path.
 	if !filepath.IsAbs(dst) {
-		WriteError(w, Error{"destination must be an absolute path"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "destination must be an absolute path"}, http.StatusBadRequest)
 		return
 	}
 	// Get the wallet seed.
 	ws, _, err := api.wallet.PrimarySeed()
 	if err != nil {
-		WriteError(w, Error{"failed to get wallet's primary seed"}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "failed to get wallet's primary seed"}, http.StatusInternalServerError)
 		return
 	}
 	// Derive the renter seed and wipe the memory once we are done using it.
@@ -553,7 +889,7 @@ func (api *API) renterBackupHandlerPOST(w http.ResponseWriter, req *http.Request
 	defer fastrand.Read(secret[:])
 	// Create the backup.
 	if err := api.renter.CreateBackup(dst, secret[:32]); err != nil {
-		WriteError(w, Error{"failed to create backup: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to create backup: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -564,18 +900,18 @@ func (api *API) renterLoadBackupHandlerPOST(w http.ResponseWriter, req *http.Req
 	// Check that source was specified.
 	src := req.FormValue("source")
 	if src == "" {
-		WriteError(w, Error{"source not specified"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "source not specified"}, http.StatusBadRequest)
 		return
 	}
 	// The source needs to be an absolute path.
 	if !filepath.IsAbs(src) {
-		WriteError(w, Error{"source must be an absolute path"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "source must be an absolute path"}, http.StatusBadRequest)
 		return
 	}
 	// Get the wallet seed.
 	ws, _, err := api.wallet.PrimarySeed()
 	if err != nil {
-		WriteError(w, Error{"failed to get wallet's primary seed"}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "failed to get wallet's primary seed"}, http.StatusInternalServerError)
 		return
 	}
 	// Derive the renter seed and wipe the memory once we are done using it.
@@ -586,7 +922,7 @@ func (api *API) renterLoadBackupHandlerPOST(w http.ResponseWriter, req *http.Req
 	defer fastrand.Read(secret[:])
 	// Load the backup.
 	if err := api.renter.LoadBackup(src, secret[:32]); err != nil {
-		WriteError(w, Error{"failed to load backup: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to load backup: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -660,19 +996,19 @@ func ParseDataAndParityPieces(strDataPieces, strParityPieces string) (dataPieces
 func (api *API) renterHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	settings, err := api.renter.Settings()
 	if err != nil {
-		WriteError(w, Error{"unable able to get renter settings: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable able to get renter settings: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	spending, err := api.renter.PeriodSpending()
 	if err != nil {
-		WriteError(w, Error{"unable to get Period Spending: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to get Period Spending: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	currentPeriod := api.renter.CurrentPeriod()
 	nextPeriod := currentPeriod + settings.Allowance.Period
 	memoryStatus, err := api.renter.MemoryStatus()
 	if err != nil {
-		WriteError(w, Error{"unable to get renter memory information: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to get renter memory information: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, RenterGET{
@@ -681,7 +1017,8 @@ func (api *API) renterHandlerGET(w http.ResponseWriter, _ *http.Request, _ httpr
 		CurrentPeriod:    currentPeriod,
 		NextPeriod:       nextPeriod,
 
-		MemoryStatus: memoryStatus,
+		MemoryStatus:   memoryStatus,
+		RepairSchedule: api.renter.RepairScheduleStatus(),
 	})
 }
 
@@ -693,17 +1030,27 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	// Get the existing settings
 	settings, err := api.renter.Settings()
 	if err != nil {
-		WriteError(w, Error{"unable able to get renter settings: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable able to get renter settings: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 
+	// Check whether the settings should only be validated.
+	var dryRun bool
+	if dr := req.FormValue("dryrun"); dr != "" {
+		dryRun, err = scanBool(dr)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse dryrun: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Scan for all allowance fields
 	var hostsSet, renewWindowSet, expectedStorageSet,
 		expectedUploadSet, expectedDownloadSet, expectedRedundancySet, maxPeriodChurnSet bool
 	if f := req.FormValue("funds"); f != "" {
 		funds, ok := scanAmount(f)
 		if !ok {
-			WriteError(w, Error{"unable to parse funds"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse funds"}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.Funds = funds
@@ -711,10 +1058,10 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if h := req.FormValue("hosts"); h != "" {
 		var hosts uint64
 		if _, err := fmt.Sscan(h, &hosts); err != nil {
-			WriteError(w, Error{"unable to parse hosts: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse hosts: " + err.Error()}, http.StatusBadRequest)
 			return
 		} else if hosts != 0 && hosts < requiredHosts {
-			WriteError(w, Error{fmt.Sprintf("insufficient number of hosts, need at least %v but have %v", requiredHosts, hosts)}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprintf("insufficient number of hosts, need at least %v but have %v", requiredHosts, hosts), Code: ErrCodeInsufficientHosts}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.Hosts = hosts
@@ -723,7 +1070,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if p := req.FormValue("period"); p != "" {
 		var period types.BlockHeight
 		if _, err := fmt.Sscan(p, &period); err != nil {
-			WriteError(w, Error{"unable to parse period: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse period: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.Period = types.BlockHeight(period)
@@ -731,10 +1078,10 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if rw := req.FormValue("renewwindow"); rw != "" {
 		var renewWindow types.BlockHeight
 		if _, err := fmt.Sscan(rw, &renewWindow); err != nil {
-			WriteError(w, Error{"unable to parse renewwindow: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse renewwindow: " + err.Error()}, http.StatusBadRequest)
 			return
 		} else if renewWindow != 0 && types.BlockHeight(renewWindow) < requiredRenewWindow {
-			WriteError(w, Error{fmt.Sprintf("renew window is too small, must be at least %v blocks but have %v blocks", requiredRenewWindow, renewWindow)}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprintf("renew window is too small, must be at least %v blocks but have %v blocks", requiredRenewWindow, renewWindow)}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.RenewWindow = types.BlockHeight(renewWindow)
@@ -743,7 +1090,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if es := req.FormValue("expectedstorage"); es != "" {
 		var expectedStorage uint64
 		if _, err := fmt.Sscan(es, &expectedStorage); err != nil {
-			WriteError(w, Error{"unable to parse expectedStorage: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse expectedStorage: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.ExpectedStorage = expectedStorage
@@ -752,7 +1099,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if euf := req.FormValue("expectedupload"); euf != "" {
 		var expectedUpload uint64
 		if _, err := fmt.Sscan(euf, &expectedUpload); err != nil {
-			WriteError(w, Error{"unable to parse expectedUpload: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse expectedUpload: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.ExpectedUpload = expectedUpload
@@ -761,7 +1108,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if edf := req.FormValue("expecteddownload"); edf != "" {
 		var expectedDownload uint64
 		if _, err := fmt.Sscan(edf, &expectedDownload); err != nil {
-			WriteError(w, Error{"unable to parse expectedDownload: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse expectedDownload: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.ExpectedDownload = expectedDownload
@@ -770,7 +1117,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if er := req.FormValue("expectedredundancy"); er != "" {
 		var expectedRedundancy float64
 		if _, err := fmt.Sscan(er, &expectedRedundancy); err != nil {
-			WriteError(w, Error{"unable to parse expectedRedundancy: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse expectedRedundancy: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.ExpectedRedundancy = expectedRedundancy
@@ -779,16 +1126,42 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if mpc := req.FormValue("maxperiodchurn"); mpc != "" {
 		var maxPeriodChurn uint64
 		if _, err := fmt.Sscan(mpc, &maxPeriodChurn); err != nil {
-			WriteError(w, Error{"unable to parse new max churn per period: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse new max churn per period: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.MaxPeriodChurn = maxPeriodChurn
 		maxPeriodChurnSet = true
 	}
+	if pl := req.FormValue("preferredlatency"); pl != "" {
+		var preferredLatency uint64
+		if _, err := fmt.Sscan(pl, &preferredLatency); err != nil {
+			WriteError(w, Error{Message: "unable to parse preferredlatency: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.PreferredLatency = time.Duration(preferredLatency) * time.Millisecond
+	}
+	if _, exists := req.Form["preferredregions"]; exists {
+		// An empty value clears the preferred regions.
+		var regions []string
+		for _, region := range strings.Split(req.FormValue("preferredregions"), ",") {
+			if region = strings.TrimSpace(region); region != "" {
+				regions = append(regions, region)
+			}
+		}
+		settings.Allowance.PreferredRegions = regions
+	}
+	if ms := req.FormValue("minsubnets"); ms != "" {
+		var minSubnets uint64
+		if _, err := fmt.Sscan(ms, &minSubnets); err != nil {
+			WriteError(w, Error{Message: "unable to parse minsubnets: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.MinSubnets = minSubnets
+	}
 	if str := req.FormValue("maxrpcprice"); str != "" {
 		price, ok := scanAmount(str)
 		if !ok {
-			WriteError(w, Error{"unable to parse maxrpcprice"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse maxrpcprice"}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.MaxRPCPrice = price
@@ -796,7 +1169,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if str := req.FormValue("maxcontractprice"); str != "" {
 		price, ok := scanAmount(str)
 		if !ok {
-			WriteError(w, Error{"unable to parse maxcontractprice"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse maxcontractprice"}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.MaxContractPrice = price
@@ -804,7 +1177,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if str := req.FormValue("maxdownloadbandwidthprice"); str != "" {
 		price, ok := scanAmount(str)
 		if !ok {
-			WriteError(w, Error{"unable to parse maxdownloadbandwidthprice"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse maxdownloadbandwidthprice"}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.MaxDownloadBandwidthPrice = price
@@ -812,7 +1185,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if str := req.FormValue("maxsectoraccessprice"); str != "" {
 		price, ok := scanAmount(str)
 		if !ok {
-			WriteError(w, Error{"unable to parse maxsectoraccessprice"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse maxsectoraccessprice"}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.MaxSectorAccessPrice = price
@@ -820,7 +1193,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if str := req.FormValue("maxstorageprice"); str != "" {
 		price, ok := scanAmount(str)
 		if !ok {
-			WriteError(w, Error{"unable to parse maxstorageprice"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse maxstorageprice"}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.MaxStoragePrice = price
@@ -828,7 +1201,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if str := req.FormValue("maxuploadbandwidthprice"); str != "" {
 		price, ok := scanAmount(str)
 		if !ok {
-			WriteError(w, Error{"unable to parse maxuploadbandwidthprice"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse maxuploadbandwidthprice"}, http.StatusBadRequest)
 			return
 		}
 		settings.Allowance.MaxUploadBandwidthPrice = price
@@ -849,21 +1222,21 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 		// If Funds is still 0 return an error since we need the user to set the
 		// period initially
 		if zeroFunds {
-			WriteError(w, Error{ErrFundsNeedToBeSet.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: ErrFundsNeedToBeSet.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		// If Period is still 0 return an error since we need the user to set
 		// the period initially
 		if zeroPeriod {
-			WriteError(w, Error{ErrPeriodNeedToBeSet.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: ErrPeriodNeedToBeSet.Error()}, http.StatusBadRequest)
 			return
 		}
 
 		// If the user set Hosts to 0 return an error, otherwise if Hosts was
 		// not set by the user then set it to the sane default
 		if settings.Allowance.Hosts == 0 && hostsSet {
-			WriteError(w, Error{contractor.ErrAllowanceNoHosts.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: contractor.ErrAllowanceNoHosts.Error()}, http.StatusBadRequest)
 			return
 		} else if settings.Allowance.Hosts == 0 {
 			settings.Allowance.Hosts = modules.DefaultAllowance.Hosts
@@ -873,7 +1246,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 		// the Renew Window was not set by the user then set it to the sane
 		// default
 		if settings.Allowance.RenewWindow == 0 && renewWindowSet {
-			WriteError(w, Error{contractor.ErrAllowanceZeroWindow.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: contractor.ErrAllowanceZeroWindow.Error()}, http.StatusBadRequest)
 			return
 		} else if settings.Allowance.RenewWindow == 0 {
 			settings.Allowance.RenewWindow = settings.Allowance.Period / 2
@@ -883,7 +1256,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 		// ExpectedStorage was not set by the user then set it to the sane
 		// default
 		if settings.Allowance.ExpectedStorage == 0 && expectedStorageSet {
-			WriteError(w, Error{contractor.ErrAllowanceZeroExpectedStorage.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: contractor.ErrAllowanceZeroExpectedStorage.Error()}, http.StatusBadRequest)
 			return
 		} else if settings.Allowance.ExpectedStorage == 0 {
 			settings.Allowance.ExpectedStorage = modules.DefaultAllowance.ExpectedStorage
@@ -893,7 +1266,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 		// ExpectedUpload was not set by the user then set it to the sane
 		// default
 		if settings.Allowance.ExpectedUpload == 0 && expectedUploadSet {
-			WriteError(w, Error{contractor.ErrAllowanceZeroExpectedUpload.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: contractor.ErrAllowanceZeroExpectedUpload.Error()}, http.StatusBadRequest)
 			return
 		} else if settings.Allowance.ExpectedUpload == 0 {
 			settings.Allowance.ExpectedUpload = modules.DefaultAllowance.ExpectedUpload
@@ -903,7 +1276,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 		// ExpectedDownload was not set by the user then set it to the sane
 		// default
 		if settings.Allowance.ExpectedDownload == 0 && expectedDownloadSet {
-			WriteError(w, Error{contractor.ErrAllowanceZeroExpectedDownload.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: contractor.ErrAllowanceZeroExpectedDownload.Error()}, http.StatusBadRequest)
 			return
 		} else if settings.Allowance.ExpectedDownload == 0 {
 			settings.Allowance.ExpectedDownload = modules.DefaultAllowance.ExpectedDownload
@@ -913,7 +1286,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 		// ExpectedRedundancy was not set by the user then set it to the sane
 		// default
 		if settings.Allowance.ExpectedRedundancy == 0 && expectedRedundancySet {
-			WriteError(w, Error{contractor.ErrAllowanceZeroExpectedRedundancy.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: contractor.ErrAllowanceZeroExpectedRedundancy.Error()}, http.StatusBadRequest)
 			return
 		} else if settings.Allowance.ExpectedRedundancy == 0 {
 			settings.Allowance.ExpectedRedundancy = modules.DefaultAllowance.ExpectedRedundancy
@@ -923,7 +1296,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 		// MaxPeriodChurn was not set by the user then set it to the sane
 		// default
 		if settings.Allowance.MaxPeriodChurn == 0 && maxPeriodChurnSet {
-			WriteError(w, Error{contractor.ErrAllowanceZeroMaxPeriodChurn.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: contractor.ErrAllowanceZeroMaxPeriodChurn.Error()}, http.StatusBadRequest)
 			return
 		} else if settings.Allowance.MaxPeriodChurn == 0 {
 			settings.Allowance.MaxPeriodChurn = modules.DefaultAllowance.MaxPeriodChurn
@@ -934,7 +1307,7 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if d := req.FormValue("maxdownloadspeed"); d != "" {
 		var downloadSpeed int64
 		if _, err := fmt.Sscan(d, &downloadSpeed); err != nil {
-			WriteError(w, Error{"unable to parse downloadspeed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse downloadspeed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.MaxDownloadSpeed = downloadSpeed
@@ -943,26 +1316,92 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 	if u := req.FormValue("maxuploadspeed"); u != "" {
 		var uploadSpeed int64
 		if _, err := fmt.Sscan(u, &uploadSpeed); err != nil {
-			WriteError(w, Error{"unable to parse uploadspeed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse uploadspeed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.MaxUploadSpeed = uploadSpeed
 	}
 
+	// Scan the chunk cache size. (optional parameter)
+	if c := req.FormValue("chunkcachesize"); c != "" {
+		var chunkCacheSize uint64
+		if _, err := fmt.Sscan(c, &chunkCacheSize); err != nil {
+			WriteError(w, Error{Message: "unable to parse chunkcachesize: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.ChunkCacheSize = chunkCacheSize
+	}
+
+	// Scan the account funding policy. (optional parameters)
+	if err := scanAccountFundingPolicy(req, "account", &settings.AccountFunding); err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	// Scan the read-only mode.
+	if ro := req.FormValue("readonly"); ro != "" {
+		readOnly, err := scanBool(ro)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse readonly: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.ReadOnly = readOnly
+	}
+
+	// Scan the repair windows. An empty value removes all windows.
+	if _, exists := req.Form["repairwindows"]; exists {
+		var windows []modules.RepairWindow
+		for _, str := range strings.Split(req.FormValue("repairwindows"), ",") {
+			if str = strings.TrimSpace(str); str == "" {
+				continue
+			}
+			rw, err := modules.ParseRepairWindow(str)
+			if err != nil {
+				WriteError(w, Error{Message: "unable to parse repairwindows: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			windows = append(windows, rw)
+		}
+		settings.RepairSchedule.Windows = windows
+	}
+	if b := req.FormValue("repairbandwidthbudget"); b != "" {
+		var budget uint64
+		if _, err := fmt.Sscan(b, &budget); err != nil {
+			WriteError(w, Error{Message: "unable to parse repairbandwidthbudget: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.RepairSchedule.BandwidthBudget = budget
+	}
+
 	// Scan the checkforipviolation flag.
 	if ipc := req.FormValue("checkforipviolation"); ipc != "" {
 		var ipviolationcheck bool
 		if _, err := fmt.Sscan(ipc, &ipviolationcheck); err != nil {
-			WriteError(w, Error{"unable to parse ipviolationcheck: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse ipviolationcheck: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		settings.IPViolationCheck = ipviolationcheck
 	}
 
+	// Validate the settings. A dry run only returns the analysis.
+	validation, err := api.renter.ValidateSettings(settings)
+	if err != nil {
+		WriteError(w, Error{Message: "unable to validate renter settings: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	if dryRun {
+		WriteJSON(w, validation)
+		return
+	}
+	if err := validation.Err(); err != nil {
+		WriteError(w, Error{Message: "invalid renter settings: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
 	// Set the settings in the renter.
 	err = api.renter.SetSettings(settings)
 	if err != nil {
-		WriteError(w, Error{"unable to set renter settings: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to set renter settings: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -974,7 +1413,7 @@ func (api *API) renterAllowanceCancelHandlerPOST(w http.ResponseWriter, _ *http.
 	// Get the existing settings
 	settings, err := api.renter.Settings()
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 
@@ -984,7 +1423,7 @@ func (api *API) renterAllowanceCancelHandlerPOST(w http.ResponseWriter, _ *http.
 	// Set the settings in the renter.
 	err = api.renter.SetSettings(settings)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -1002,7 +1441,7 @@ func (api *API) renterCleanHandlerPOST(w http.ResponseWriter, _ *http.Request, _
 	err := api.renter.FileList(modules.RootSiaPath(), true, false, cleanFunc)
 	err = errors.Compose(err, deleteErrs)
 	if err != nil {
-		WriteError(w, Error{"unable to clear lost files: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to clear lost files: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 
@@ -1013,12 +1452,12 @@ func (api *API) renterCleanHandlerPOST(w http.ResponseWriter, _ *http.Request, _
 func (api *API) renterContractCancelHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var fcid types.FileContractID
 	if err := fcid.LoadString(req.FormValue("id")); err != nil {
-		WriteError(w, Error{"unable to parse id: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to parse id: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err := api.renter.CancelContract(fcid)
 	if err != nil {
-		WriteError(w, Error{"unable to cancel contract: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to cancel contract: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -1057,33 +1496,40 @@ func (api *API) renterContractCancelHandler(w http.ResponseWriter, req *http.Req
 // blockchain by using the renter's seed.
 func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// Parse flags
-	var disabled, inactive, expired, recoverable bool
+	var disabled, inactive, expired, recoverable, spendingHistory bool
 	var err error
 	if s := req.FormValue("disabled"); s != "" {
 		disabled, err = scanBool(s)
 		if err != nil {
-			WriteError(w, Error{"unable to parse disabled: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse disabled: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	if s := req.FormValue("inactive"); s != "" {
 		inactive, err = scanBool(s)
 		if err != nil {
-			WriteError(w, Error{"unable to parse inactive: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse inactive: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	if s := req.FormValue("expired"); s != "" {
 		expired, err = scanBool(s)
 		if err != nil {
-			WriteError(w, Error{"unable to parse expired: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse expired: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	if s := req.FormValue("recoverable"); s != "" {
 		recoverable, err = scanBool(s)
 		if err != nil {
-			WriteError(w, Error{"unable to parse recoverable: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse recoverable: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if s := req.FormValue("spendinghistory"); s != "" {
+		spendingHistory, err = scanBool(s)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse spendinghistory: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -1098,6 +1544,25 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 	}
 	contracts.RecoverableContracts = recoverableContracts
 
+	// Add the spending history to the contracts
+	if spendingHistory {
+		history := api.renter.SpendingHistory()
+		for _, cs := range [][]RenterContract{
+			contracts.Contracts,
+			contracts.InactiveContracts,
+			contracts.ActiveContracts,
+			contracts.PassiveContracts,
+			contracts.RefreshedContracts,
+			contracts.DisabledContracts,
+			contracts.ExpiredContracts,
+			contracts.ExpiredRefreshedContracts,
+		} {
+			for i := range cs {
+				cs[i].SpendingHistory = history[cs[i].ID]
+			}
+		}
+	}
+
 	WriteJSON(w, contracts)
 }
 
@@ -1240,7 +1705,7 @@ func (api *API) renterClearDownloadsHandler(w http.ResponseWriter, req *http.Req
 	if beforeStr != "" {
 		beforeInt, err := strconv.ParseInt(beforeStr, 10, 64)
 		if err != nil {
-			WriteError(w, Error{"parsing integer value for parameter `before` failed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "parsing integer value for parameter `before` failed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		beforeTime = time.Unix(0, beforeInt)
@@ -1248,7 +1713,7 @@ func (api *API) renterClearDownloadsHandler(w http.ResponseWriter, req *http.Req
 	if afterStr != "" {
 		afterInt, err := strconv.ParseInt(afterStr, 10, 64)
 		if err != nil {
-			WriteError(w, Error{"parsing integer value for parameter `after` failed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "parsing integer value for parameter `after` failed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		afterTime = time.Unix(0, afterInt)
@@ -1256,7 +1721,7 @@ func (api *API) renterClearDownloadsHandler(w http.ResponseWriter, req *http.Req
 
 	err := api.renter.ClearDownloadHistory(afterTime, beforeTime)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -1268,6 +1733,185 @@ func (api *API) renterContractorChurnStatus(w http.ResponseWriter, _ *http.Reque
 	WriteJSON(w, api.renter.ContractorChurnStatus())
 }
 
+// renterRegistryHandlerGET handles the API call to read a value from the
+// hosts' registries.
+func (api *API) renterRegistryHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var spk types.SiaPublicKey
+	if err := spk.LoadString(req.FormValue("publickey")); err != nil {
+		WriteError(w, Error{Message: "unable to parse publickey: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var dataKey crypto.Hash
+	if err := dataKey.LoadString(req.FormValue("datakey")); err != nil {
+		WriteError(w, Error{Message: "unable to parse datakey: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	timeout := renter.MaxRegistryReadTimeout
+	if t := req.FormValue("timeout"); t != "" {
+		var seconds uint64
+		if _, err := fmt.Sscan(t, &seconds); err != nil {
+			WriteError(w, Error{Message: "unable to parse timeout: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout == 0 || timeout > renter.MaxRegistryReadTimeout {
+		WriteError(w, Error{Message: fmt.Sprintf("timeout must be between 1 and %v seconds", renter.MaxRegistryReadTimeout.Seconds())}, http.StatusBadRequest)
+		return
+	}
+
+	srv, err := api.renter.ReadRegistry(spk, dataKey, timeout)
+	if errors.Contains(err, renter.ErrRegistryEntryNotFound) || errors.Contains(err, renter.ErrRegistryLookupTimeout) {
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		WriteError(w, Error{Message: "failed to read registry: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, RenterRegistryGET{
+		Data:      hex.EncodeToString(srv.Data),
+		Revision:  srv.Revision,
+		Signature: hex.EncodeToString(srv.Signature[:]),
+		Type:      srv.Type,
+	})
+}
+
+// renterRegistryHandlerPOST handles the API call to update a value in the
+// hosts' registries.
+func (api *API) renterRegistryHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var rrp RenterRegistryPOST
+	if err := json.NewDecoder(req.Body).Decode(&rrp); err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if rrp.PublicKey.Algorithm != types.SignatureEd25519 || len(rrp.PublicKey.Key) != crypto.PublicKeySize {
+		WriteError(w, Error{Message: "publickey must be an ed25519 public key"}, http.StatusBadRequest)
+		return
+	}
+	if len(rrp.Data) > modules.RegistryDataSize {
+		WriteError(w, Error{Message: fmt.Sprintf("data can't be larger than %v bytes", modules.RegistryDataSize)}, http.StatusBadRequest)
+		return
+	}
+	// Values without a type are treated as values without a host's pubkey.
+	if rrp.Type == modules.RegistryTypeInvalid {
+		rrp.Type = modules.RegistryTypeWithoutPubkey
+	}
+
+	// Verify the signature before sending the value to the hosts.
+	srv := modules.NewSignedRegistryValue(rrp.DataKey, rrp.Data, rrp.Revision, rrp.Signature, rrp.Type)
+	var pk crypto.PublicKey
+	copy(pk[:], rrp.PublicKey.Key)
+	if err := srv.Verify(pk); err != nil {
+		WriteError(w, Error{Message: "invalid registry value: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err := api.renter.UpdateRegistry(rrp.PublicKey, srv, renter.DefaultRegistryUpdateTimeout)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to update registry: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterContractorMigrationsHandlerGET handles the API call to request the
+// contractor's migration plan.
+func (api *API) renterContractorMigrationsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, api.renter.ContractorMigrationPlan())
+}
+
+// renterContractorHostListsHandlerGET handles the API call to request the
+// contractor's host allowlist and denylist.
+func (api *API) renterContractorHostListsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, api.renter.ContractorHostLists())
+}
+
+// renterContractorHostListsHandlerPOST handles the API call to replace the
+// contractor's host allowlist and denylist.
+func (api *API) renterContractorHostListsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var lists modules.ContractorHostLists
+	err := json.NewDecoder(req.Body).Decode(&lists)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = api.renter.SetContractorHostLists(lists)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to set the host lists: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// batchDownloadWriter sets the headers of a batch download's response once the
+// renter starts writing the archive. Errors which occur before that can still
+// be returned as regular error responses.
+type batchDownloadWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+// Write implements io.Writer.
+func (bw *batchDownloadWriter) Write(b []byte) (int, error) {
+	if !bw.written {
+		bw.w.Header().Set("Content-Type", "application/x-tar")
+		bw.w.Header().Set("Content-Disposition", `attachment; filename="download.tar"`)
+		bw.written = true
+	}
+	return bw.w.Write(b)
+}
+
+// renterDownloadsBatchHandler handles the API call to download multiple files
+// as a tar archive.
+func (api *API) renterDownloadsBatchHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	root, err := scanBool(req.FormValue("root"))
+	if err != nil {
+		WriteError(w, Error{Message: "error parsing the root flag: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var disableLocalFetch bool
+	if dlf := req.FormValue("disablelocalfetch"); dlf != "" {
+		disableLocalFetch, err = scanBool(dlf)
+		if err != nil {
+			WriteError(w, Error{Message: "error parsing the disablelocalfetch flag: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	base := modules.UserFolder
+	if root {
+		base = modules.RootSiaPath()
+	}
+	var siaPaths []modules.SiaPath
+	for _, sp := range req.Form["siapath"] {
+		siaPath, err := modules.NewSiaPath(sp)
+		if err != nil {
+			WriteError(w, Error{Message: fmt.Sprintf("invalid siapath '%v': %v", sp, err)}, http.StatusBadRequest)
+			return
+		}
+		if !root {
+			siaPath, err = rebaseInputSiaPath(siaPath)
+			if err != nil {
+				WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+		}
+		siaPaths = append(siaPaths, siaPath)
+	}
+	if len(siaPaths) == 0 {
+		WriteError(w, Error{Message: "at least one siapath is required"}, http.StatusBadRequest)
+		return
+	}
+
+	// If the renter already started writing the archive, the error can't be
+	// reported anymore. The client will notice that the archive is
+	// incomplete when reading it.
+	bw := &batchDownloadWriter{w: w}
+	err = api.renter.DownloadBatch(siaPaths, base, disableLocalFetch, bw)
+	if err != nil && !bw.written {
+		WriteError(w, Error{Message: "failed to download files: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+	}
+}
+
 // renterDownloadsHandler handles the API call to request the download queue.
 func (api *API) renterDownloadsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var downloads []DownloadInfo
@@ -1275,13 +1919,13 @@ func (api *API) renterDownloadsHandler(w http.ResponseWriter, req *http.Request,
 	dis := api.renter.DownloadHistory()
 	root, err := scanBool(req.FormValue("root"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	if !root {
 		dis, err = trimDownloadInfo(dis...)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusInternalServerError)
+			WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
 			return
 		}
 	}
@@ -1301,24 +1945,85 @@ func (api *API) renterDownloadsHandler(w http.ResponseWriter, req *http.Request,
 			StartTime:            di.StartTime,
 			StartTimeUnix:        di.StartTimeUnix,
 			TotalDataTransferred: di.TotalDataTransferred,
+
+			CostWeight:    di.CostWeight,
+			EstimatedCost: di.EstimatedCost,
+			MaxSpeed:      di.MaxSpeed,
+
+			RequestClass:    di.RequestClass,
+			OverdrivePieces: di.OverdrivePieces,
 		})
 	}
+	overdrive, err := api.renter.DownloadOverdrive()
+	if err != nil {
+		WriteError(w, Error{Message: "unable to get download overdrive: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
 	WriteJSON(w, RenterDownloadQueue{
 		Downloads: downloads,
+		Overdrive: overdrive,
 	})
 }
 
+// renterDownloadOverdriveHandlerPOST handles the API call to update the
+// overdrive policy of a download class. Parameters that aren't provided keep
+// their current value.
+func (api *API) renterDownloadOverdriveHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	class := modules.DownloadRequestClass(req.FormValue("class"))
+	if class == "" {
+		WriteError(w, Error{Message: "class must be specified"}, http.StatusBadRequest)
+		return
+	}
+	overdrive, err := api.renter.DownloadOverdrive()
+	if err != nil {
+		WriteError(w, Error{Message: "unable to get download overdrive: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	status, exists := overdrive[class]
+	if !exists {
+		WriteError(w, Error{Message: fmt.Sprintf("unknown download class '%v'", class)}, http.StatusBadRequest)
+		return
+	}
+	policy := status.Policy
+	if bw := req.FormValue("baseworkers"); bw != "" {
+		if _, err := fmt.Sscan(bw, &policy.BaseWorkers); err != nil {
+			WriteError(w, Error{Message: "unable to parse baseworkers: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if hd := req.FormValue("hedgedelay"); hd != "" {
+		var ms uint64
+		if _, err := fmt.Sscan(hd, &ms); err != nil {
+			WriteError(w, Error{Message: "unable to parse hedgedelay: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		policy.HedgeDelay = time.Duration(ms) * time.Millisecond
+	}
+	if mep := req.FormValue("maxextrapieces"); mep != "" {
+		if _, err := fmt.Sscan(mep, &policy.MaxExtraPieces); err != nil {
+			WriteError(w, Error{Message: "unable to parse maxextrapieces: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	err = api.renter.SetDownloadOverdrivePolicy(class, policy)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to set overdrive policy: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // renterDownloadByUIDHandlerGET handles the API call to /renter/downloadinfo.
 func (api *API) renterDownloadByUIDHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
 	uid := strings.TrimPrefix(ps.ByName("uid"), "/")
 	di, exists := api.renter.DownloadByUID(modules.DownloadID(uid))
 	if !exists {
-		WriteError(w, Error{fmt.Sprintf("Download with id '%v' doesn't exist", string(uid))}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Download with id '%v' doesn't exist", string(uid))}, http.StatusBadRequest)
 		return
 	}
 	dis, err := trimDownloadInfo(di)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
 		return
 	}
 	di = dis[0]
@@ -1337,6 +2042,10 @@ func (api *API) renterDownloadByUIDHandlerGET(w http.ResponseWriter, _ *http.Req
 		StartTime:            di.StartTime,
 		StartTimeUnix:        di.StartTimeUnix,
 		TotalDataTransferred: di.TotalDataTransferred,
+
+		CostWeight:    di.CostWeight,
+		EstimatedCost: di.EstimatedCost,
+		MaxSpeed:      di.MaxSpeed,
 	})
 }
 
@@ -1348,7 +2057,7 @@ func (api *API) renterFuseHandlerGET(w http.ResponseWriter, _ *http.Request, _ h
 	for i := 0; i < len(rfi.MountPoints); i++ {
 		rebased, err := rfi.MountPoints[i].SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		rfi.MountPoints[i].SiaPath = rebased
@@ -1367,13 +2076,13 @@ func (api *API) renterFuseMountHandlerPOST(w http.ResponseWriter, req *http.Requ
 	} else {
 		siaPath, err = modules.NewSiaPath(spfv)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	siaPath, err = rebaseInputSiaPath(siaPath)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -1382,7 +2091,7 @@ func (api *API) renterFuseMountHandlerPOST(w http.ResponseWriter, req *http.Requ
 	if req.FormValue("readonly") != "" {
 		readOnly, err := scanBool(req.FormValue("readonly"))
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		opts.ReadOnly = readOnly
@@ -1390,13 +2099,13 @@ func (api *API) renterFuseMountHandlerPOST(w http.ResponseWriter, req *http.Requ
 	if req.FormValue("allowother") != "" {
 		allowOther, err := scanBool(req.FormValue("allowother"))
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		opts.AllowOther = allowOther
 	}
 	if err := api.renter.Mount(mount, siaPath, opts); err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -1406,7 +2115,7 @@ func (api *API) renterFuseMountHandlerPOST(w http.ResponseWriter, req *http.Requ
 func (api *API) renterFuseUnmountHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	err := api.renter.Unmount(req.FormValue("mount"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -1415,7 +2124,7 @@ func (api *API) renterFuseUnmountHandlerPOST(w http.ResponseWriter, req *http.Re
 // renterRecoveryScanHandlerPOST handles the API call to /renter/recoveryscan.
 func (api *API) renterRecoveryScanHandlerPOST(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	if err := api.renter.InitRecoveryScan(); err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -1430,68 +2139,236 @@ func (api *API) renterRecoveryScanHandlerGET(w http.ResponseWriter, _ *http.Requ
 	})
 }
 
+// renterRestoreDrillsHandlerGET handles the API call to /renter/restoredrills.
+func (api *API) renterRestoreDrillsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	status, err := api.renter.RestoreDrillStatus()
+	if err != nil {
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	status.Results, err = trimRestoreDrillResults(status.Results...)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, status)
+}
+
+// renterRestoreDrillsHandlerPOST handles the API call to update the settings
+// of the renter's restore drills.
+func (api *API) renterRestoreDrillsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	status, err := api.renter.RestoreDrillStatus()
+	if err != nil {
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	settings := status.RestoreDrillSettings
+	if e := req.FormValue("enabled"); e != "" {
+		settings.Enabled, err = scanBool(e)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'enabled': " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if i := req.FormValue("interval"); i != "" {
+		var seconds uint64
+		if _, err := fmt.Sscan(i, &seconds); err != nil {
+			WriteError(w, Error{Message: "unable to parse 'interval': " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Interval = time.Duration(seconds) * time.Second
+	}
+	err = api.renter.SetRestoreDrillSettings(settings)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to update restore drill settings: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterRestoreDrillsRunHandlerPOST handles the API call to run a restore
+// drill right away.
+func (api *API) renterRestoreDrillsRunHandlerPOST(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	res, err := api.renter.RunRestoreDrill()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to run restore drill: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	results, err := trimRestoreDrillResults(res)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, results[0])
+}
+
 // renterRenameHandler handles the API call to rename a file entry in the
 // renter.
 func (api *API) renterRenameHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	// Parse the siaPath and the newSiaPath
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	newSiaPath, err := modules.NewSiaPath(req.FormValue("newsiapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Determine whether the user is requesting a user siapath, or a root siapath.
 	root, err := isCalledWithRootFlag(req)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	// Rebase the user's input to the user folder if the user is requesting a user siapath.
 	if !root {
 		siaPath, err = rebaseInputSiaPath(siaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		newSiaPath, err = rebaseInputSiaPath(newSiaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	err = api.renter.RenameFile(siaPath, newSiaPath)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterSharedFileSiaPath parses the siapath of the shared file endpoints and
+// rebases it onto the user folder unless the root flag is set.
+func renterSharedFileSiaPath(req *http.Request, ps httprouter.Params) (modules.SiaPath, error) {
+	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
+	if err != nil {
+		return modules.SiaPath{}, errors.AddContext(err, "unable to parse siapath")
+	}
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		return modules.SiaPath{}, err
+	}
+	if !root {
+		siaPath, err = rebaseInputSiaPath(siaPath)
+		if err != nil {
+			return modules.SiaPath{}, err
+		}
+	}
+	return siaPath, nil
+}
+
+// renterShareFileHandlerPOST handles the API call to export a file together
+// with its keys into an encrypted shared file.
+func (api *API) renterShareFileHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	siaPath, err := renterSharedFileSiaPath(req, ps)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	// The destination needs to be an absolute path.
+	dst := req.FormValue("destination")
+	if dst == "" {
+		WriteError(w, Error{Message: "destination not specified"}, http.StatusBadRequest)
+		return
+	}
+	if !filepath.IsAbs(dst) {
+		WriteError(w, Error{Message: "destination must be an absolute path"}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.ShareFile(siaPath, dst, req.FormValue("password")); err != nil {
+		WriteError(w, Error{Message: "failed to share file: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterLoadSharedFileHandlerPOST handles the API call to load a shared file
+// into the renter.
+func (api *API) renterLoadSharedFileHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	siaPath, err := renterSharedFileSiaPath(req, ps)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	// The source needs to be an absolute path.
+	src := req.FormValue("source")
+	if src == "" {
+		WriteError(w, Error{Message: "source not specified"}, http.StatusBadRequest)
+		return
+	}
+	if !filepath.IsAbs(src) {
+		WriteError(w, Error{Message: "source must be an absolute path"}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.LoadSharedFile(src, siaPath, req.FormValue("password")); err != nil {
+		WriteError(w, Error{Message: "failed to load shared file: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
 }
 
+// renterFileKeysHandlerGET handles the API call to export the encryption keys
+// and the erasure coding layout of a file.
+func (api *API) renterFileKeysHandlerGET(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if !root {
+		siaPath, err = rebaseInputSiaPath(siaPath)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	fk, err := api.renter.FileKeys(siaPath)
+	if err != nil {
+		WriteError(w, Error{Message: "unable to export file keys: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	if !root {
+		fk.SiaPath, err = fk.SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+	}
+	WriteJSON(w, fk)
+}
+
 // renterFileHandler handles GET requests to the /renter/file/:siapath API endpoint.
 func (api *API) renterFileHandlerGET(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	// Determine the siapath that the user wants to get the file from.
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Determine whether the user is requesting a user siapath, or a root siapath.
 	root, err := isCalledWithRootFlag(req)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	// Rebase the user's input to the user folder if the user is requesting a user siapath.
 	if !root {
 		siaPath, err = rebaseInputSiaPath(siaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -1499,7 +2376,7 @@ func (api *API) renterFileHandlerGET(w http.ResponseWriter, req *http.Request, p
 	// Fetch the file.
 	file, err := api.renter.File(siaPath)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 
@@ -1508,7 +2385,7 @@ func (api *API) renterFileHandlerGET(w http.ResponseWriter, req *http.Request, p
 	if !root {
 		files, err := trimSiaDirFolderOnFiles(file)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		file = files[0]
@@ -1525,25 +2402,50 @@ func (api *API) renterFileHandlerPOST(w http.ResponseWriter, req *http.Request,
 	stuck := req.FormValue("stuck")
 	root, err := scanBool(req.FormValue("root"))
 	if err != nil {
-		WriteError(w, Error{"unable to parse root flag: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to parse root flag: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{"unable to parse siapath: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to parse siapath: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	if !root {
 		siaPath, err = rebaseInputSiaPath(siaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	// Handle changing the tracking path of a file.
 	if newTrackingPath != "" {
 		if err := api.renter.SetFileTrackingPath(siaPath, newTrackingPath); err != nil {
-			WriteError(w, Error{fmt.Sprintf("unable set tracking path: %v", err)}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprintf("unable set tracking path: %v", err), Code: renterErrorCode(err)}, http.StatusBadRequest)
+			return
+		}
+	}
+	// Handle replacing the tags of a file.
+	clearTags, err := scanBool(req.FormValue("cleartags"))
+	if err != nil {
+		WriteError(w, Error{Message: "unable to parse 'cleartags' arg: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if tagStrs := req.Form["tag"]; len(tagStrs) > 0 || clearTags {
+		if len(tagStrs) > 0 && clearTags {
+			WriteError(w, Error{Message: "'tag' and 'cleartags' can't be combined"}, http.StatusBadRequest)
+			return
+		}
+		tags := make(map[string]string)
+		for _, str := range tagStrs {
+			key, value, hasValue := parseFileTag(str)
+			if !hasValue {
+				WriteError(w, Error{Message: fmt.Sprintf("tag '%v' needs to be of the form key=value", str)}, http.StatusBadRequest)
+				return
+			}
+			tags[key] = value
+		}
+		if err := api.renter.SetFileTags(siaPath, tags); err != nil {
+			WriteError(w, Error{Message: "failed to set file tags: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -1551,17 +2453,122 @@ func (api *API) renterFileHandlerPOST(w http.ResponseWriter, req *http.Request,
 	if stuck != "" {
 		s, err := strconv.ParseBool(stuck)
 		if err != nil {
-			WriteError(w, Error{"unable to parse 'stuck' arg"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse 'stuck' arg"}, http.StatusBadRequest)
 			return
 		}
 		if err := api.renter.SetFileStuck(siaPath, s); err != nil {
-			WriteError(w, Error{"failed to change file 'stuck' status: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "failed to change file 'stuck' status: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 			return
 		}
 	}
 	WriteSuccess(w)
 }
 
+// renterReencodeHandlerPOST handles the API call to /renter/reencode which
+// starts converting a file to new erasure code parameters in the background.
+func (api *API) renterReencodeHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
+	if err != nil {
+		WriteError(w, Error{Message: "unable to parse siapath: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if !root {
+		siaPath, err = rebaseInputSiaPath(siaPath)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	ec, err := parseErasureCodingParameters(req.FormValue("datapieces"), req.FormValue("paritypieces"))
+	if err != nil {
+		WriteError(w, Error{Message: "unable to parse erasure code settings: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if ec == nil {
+		WriteError(w, Error{Message: "the datapieces and paritypieces parameters are required"}, http.StatusBadRequest)
+		return
+	}
+	status, err := api.renter.ReencodeFile(siaPath, ec)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to re-encode file: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	if !root {
+		status.SiaPath, err = status.SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+	}
+	WriteJSON(w, status)
+}
+
+// renterReencodesHandlerGET handles the API call to /renter/reencodes which
+// returns the status of the renter's recent file re-encodes.
+func (api *API) renterReencodesHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	reencodes := api.renter.FileReencodes()
+	if !root {
+		reencodes = trimFileReencodes(reencodes...)
+	}
+	WriteJSON(w, RenterFileReencodes{
+		Reencodes: reencodes,
+	})
+}
+
+// renterVerifyHandlerPOST handles the API call to /renter/verify which
+// downloads a sample of a file's pieces and verifies them against the file's
+// metadata.
+func (api *API) renterVerifyHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
+	if err != nil {
+		WriteError(w, Error{Message: "unable to parse siapath: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if !root {
+		siaPath, err = rebaseInputSiaPath(siaPath)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	var sample uint64
+	if s := req.FormValue("sample"); s != "" {
+		_, err = fmt.Sscan(s, &sample)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'sample' arg: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	fv, err := api.renter.VerifyFile(siaPath, sample)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to verify file: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	if !root {
+		fv.SiaPath, err = fv.SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+	}
+	WriteJSON(w, fv)
+}
+
 // renterFilesHandler handles the API call to list all of the files.
 func (api *API) renterFilesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var c bool
@@ -1569,19 +2576,23 @@ func (api *API) renterFilesHandler(w http.ResponseWriter, req *http.Request, _ h
 	if cached := req.FormValue("cached"); cached != "" {
 		c, err = strconv.ParseBool(cached)
 		if err != nil {
-			WriteError(w, Error{"unable to parse 'cached' arg"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse 'cached' arg"}, http.StatusBadRequest)
 			return
 		}
 	}
+	tagFilters := req.Form["tag"]
 	var files []modules.FileInfo
 	var mu sync.Mutex
 	err = api.renter.FileList(modules.UserFolder, true, c, func(fi modules.FileInfo) {
+		if !fileMatchesTags(fi, tagFilters) {
+			return
+		}
 		mu.Lock()
 		files = append(files, fi)
 		mu.Unlock()
 	})
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	// Sort slices by SiaPath.
@@ -1590,14 +2601,39 @@ func (api *API) renterFilesHandler(w http.ResponseWriter, req *http.Request, _ h
 	})
 	files, err = trimSiaDirFolderOnFiles(files...)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
 		return
 	}
 	WriteJSON(w, RenterFiles{
-		Files: files,
+		Files:  files,
+		Defrag: api.renter.FileDefragStatus(),
 	})
 }
 
+// parseFileTag splits a tag of the form key=value. If the tag doesn't contain
+// a value, only the key is returned.
+func parseFileTag(str string) (key, value string, hasValue bool) {
+	i := strings.Index(str, "=")
+	if i == -1 {
+		return str, "", false
+	}
+	return str[:i], str[i+1:], true
+}
+
+// fileMatchesTags returns whether the file matches all of the tag filters. A
+// filter of the form key matches files with that tag and a filter of the form
+// key=value matches files with that tag set to the value.
+func fileMatchesTags(fi modules.FileInfo, filters []string) bool {
+	for _, filter := range filters {
+		key, value, hasValue := parseFileTag(filter)
+		v, exists := fi.Tags[key]
+		if !exists || (hasValue && v != value) {
+			return false
+		}
+	}
+	return true
+}
+
 // renterPricesHandler reports the expected costs of various actions given the
 // renter settings and the set of available hosts.
 func (api *API) renterPricesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
@@ -1606,7 +2642,7 @@ func (api *API) renterPricesHandler(w http.ResponseWriter, req *http.Request, _
 	if f := req.FormValue("funds"); f != "" {
 		funds, ok := scanAmount(f)
 		if !ok {
-			WriteError(w, Error{"unable to parse funds"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse funds"}, http.StatusBadRequest)
 			return
 		}
 		allowance.Funds = funds
@@ -1615,10 +2651,10 @@ func (api *API) renterPricesHandler(w http.ResponseWriter, req *http.Request, _
 	if h := req.FormValue("hosts"); h != "" {
 		var hosts uint64
 		if _, err := fmt.Sscan(h, &hosts); err != nil {
-			WriteError(w, Error{"unable to parse hosts: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse hosts: " + err.Error()}, http.StatusBadRequest)
 			return
 		} else if hosts != 0 && hosts < requiredHosts {
-			WriteError(w, Error{fmt.Sprintf("insufficient number of hosts, need at least %v but have %v", modules.DefaultAllowance.Hosts, hosts)}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprintf("insufficient number of hosts, need at least %v but have %v", modules.DefaultAllowance.Hosts, hosts), Code: ErrCodeInsufficientHosts}, http.StatusBadRequest)
 			return
 		} else {
 			allowance.Hosts = hosts
@@ -1628,7 +2664,7 @@ func (api *API) renterPricesHandler(w http.ResponseWriter, req *http.Request, _
 	if p := req.FormValue("period"); p != "" {
 		var period types.BlockHeight
 		if _, err := fmt.Sscan(p, &period); err != nil {
-			WriteError(w, Error{"unable to parse period: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse period: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		allowance.Period = types.BlockHeight(period)
@@ -1637,10 +2673,10 @@ func (api *API) renterPricesHandler(w http.ResponseWriter, req *http.Request, _
 	if rw := req.FormValue("renewwindow"); rw != "" {
 		var renewWindow types.BlockHeight
 		if _, err := fmt.Sscan(rw, &renewWindow); err != nil {
-			WriteError(w, Error{"unable to parse renewwindow: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse renewwindow: " + err.Error()}, http.StatusBadRequest)
 			return
 		} else if renewWindow != 0 && types.BlockHeight(renewWindow) < requiredRenewWindow {
-			WriteError(w, Error{fmt.Sprintf("renew window is too small, must be at least %v blocks but have %v blocks", requiredRenewWindow, renewWindow)}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprintf("renew window is too small, must be at least %v blocks but have %v blocks", requiredRenewWindow, renewWindow)}, http.StatusBadRequest)
 			return
 		} else {
 			allowance.RenewWindow = types.BlockHeight(renewWindow)
@@ -1652,26 +2688,26 @@ func (api *API) renterPricesHandler(w http.ResponseWriter, req *http.Request, _
 	// above so that an empty allowance can still be submitted
 	if !reflect.DeepEqual(allowance, modules.Allowance{}) {
 		if allowance.Funds.Cmp(types.ZeroCurrency) == 0 {
-			WriteError(w, Error{fmt.Sprint("Allowance not set correctly, `funds` parameter left empty")}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprint("Allowance not set correctly, `funds` parameter left empty")}, http.StatusBadRequest)
 			return
 		}
 		if allowance.Period == 0 {
-			WriteError(w, Error{fmt.Sprint("Allowance not set correctly, `period` parameter left empty")}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprint("Allowance not set correctly, `period` parameter left empty")}, http.StatusBadRequest)
 			return
 		}
 		if allowance.Hosts == 0 {
-			WriteError(w, Error{fmt.Sprint("Allowance not set correctly, `hosts` parameter left empty")}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprint("Allowance not set correctly, `hosts` parameter left empty")}, http.StatusBadRequest)
 			return
 		}
 		if allowance.RenewWindow == 0 {
-			WriteError(w, Error{fmt.Sprint("Allowance not set correctly, `renewwindow` parameter left empty")}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprint("Allowance not set correctly, `renewwindow` parameter left empty")}, http.StatusBadRequest)
 			return
 		}
 	}
 
 	estimate, a, err := api.renter.PriceEstimation(allowance)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, RenterPricesGET{
@@ -1685,40 +2721,97 @@ func (api *API) renterPricesHandler(w http.ResponseWriter, req *http.Request, _
 func (api *API) renterDeleteHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Determine whether the user is requesting a user siapath, or a root siapath.
 	root, err := isCalledWithRootFlag(req)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	// Rebase the user's input to the user folder if the user is requesting a user siapath.
 	if !root {
 		siaPath, err = rebaseInputSiaPath(siaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 
 	err = api.renter.DeleteFile(siaPath)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 
 	WriteSuccess(w)
 }
 
+// renterBatchDeleteHandler handles the API call to delete multiple files or
+// directories at once.
+func (api *API) renterBatchDeleteHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Determine whether the user is requesting user siapaths, or root
+	// siapaths.
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var recursive bool
+	if r := req.FormValue("recursive"); r != "" {
+		recursive, err = scanBool(r)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'recursive' parameter: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Parse the siapaths.
+	var siaPaths []modules.SiaPath
+	for _, str := range req.Form["siapath"] {
+		siaPath, err := modules.NewSiaPath(str)
+		if err != nil {
+			WriteError(w, Error{Message: fmt.Sprintf("unable to parse siapath '%v': %v", str, err)}, http.StatusBadRequest)
+			return
+		}
+		if !root {
+			siaPath, err = rebaseInputSiaPath(siaPath)
+			if err != nil {
+				WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+				return
+			}
+		}
+		siaPaths = append(siaPaths, siaPath)
+	}
+	if len(siaPaths) == 0 {
+		WriteError(w, Error{Message: "at least one siapath is required"}, http.StatusBadRequest)
+		return
+	}
+
+	results, err := api.renter.DeleteFiles(siaPaths, recursive)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	// Return user siapaths relative to the user folder.
+	if !root {
+		for i := range results {
+			if sp, err := results[i].SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath()); err == nil {
+				results[i].SiaPath = sp
+			}
+		}
+	}
+	WriteJSON(w, RenterDeletePOST{Results: results})
+}
+
 // renterCancelDownloadHandler handles the API call to cancel a download.
 func (api *API) renterCancelDownloadHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// Get the id.
 	id := modules.DownloadID(req.FormValue("id"))
 	if id == "" {
-		WriteError(w, Error{"id not specified"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "id not specified"}, http.StatusBadRequest)
 		return
 	}
 	// Get the download from the map and delete it.
@@ -1727,7 +2820,7 @@ func (api *API) renterCancelDownloadHandler(w http.ResponseWriter, req *http.Req
 	delete(api.downloads, id)
 	api.downloadMu.Unlock()
 	if !ok {
-		WriteError(w, Error{"download for id not found"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "download for id not found"}, http.StatusBadRequest)
 		return
 	}
 	// Cancel download and delete it from the map.
@@ -1739,7 +2832,7 @@ func (api *API) renterCancelDownloadHandler(w http.ResponseWriter, req *http.Req
 func (api *API) renterDownloadHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	params, err := parseDownloadParameters(w, req, ps)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	var id modules.DownloadID
@@ -1762,14 +2855,14 @@ func (api *API) renterDownloadHandler(w http.ResponseWriter, req *http.Request,
 		id, start, err = api.renter.Download(params)
 	}
 	if err != nil {
-		WriteError(w, Error{"download creation failed: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "download creation failed: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 		return
 	}
 	// Set ID before starting download.
 	w.Header().Set("ID", string(id))
 	// Start download.
 	if err := start(); err != nil {
-		WriteError(w, Error{"download failed: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "download failed: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 		return
 	}
 	if params.Httpwriter == nil {
@@ -1813,6 +2906,13 @@ func parseDownloadParameters(w http.ResponseWriter, req *http.Request, ps httpro
 	// disk if available.
 	disablelocalfetchparam := req.FormValue("disablelocalfetch")
 
+	// costweightparam determines how much host prices are weighed against
+	// host speed when choosing hosts to download from.
+	costweightparam := req.FormValue("costweight")
+
+	// maxspeedparam caps the download's bandwidth in bytes per second.
+	maxspeedparam := req.FormValue("maxspeed")
+
 	// Parse the offset and length parameters.
 	var offset, length uint64
 	if len(offsetparam) > 0 {
@@ -1867,11 +2967,29 @@ func parseDownloadParameters(w http.ResponseWriter, req *http.Request, ps httpro
 		}
 	}
 
+	var costWeight float64
+	if costweightparam != "" {
+		_, err = fmt.Sscan(costweightparam, &costWeight)
+		if err != nil {
+			return modules.RenterDownloadParameters{}, errors.AddContext(err, "could not decode the costweight as float64")
+		}
+	}
+
+	var maxSpeed uint64
+	if maxspeedparam != "" {
+		_, err = fmt.Sscan(maxspeedparam, &maxSpeed)
+		if err != nil {
+			return modules.RenterDownloadParameters{}, errors.AddContext(err, "could not decode the maxspeed as uint64")
+		}
+	}
+
 	dp := modules.RenterDownloadParameters{
+		CostWeight:       costWeight,
 		Destination:      destination,
 		DisableDiskFetch: disableLocalFetch,
 		Async:            async,
 		Length:           length,
+		MaxSpeed:         maxSpeed,
 		Offset:           offset,
 		SiaPath:          siaPath,
 	}
@@ -1886,19 +3004,19 @@ func parseDownloadParameters(w http.ResponseWriter, req *http.Request, ps httpro
 func (api *API) renterStreamHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	root, err := scanBool(req.FormValue("root"))
 	if err != nil {
 		err = errors.AddContext(err, "error parsing the root flag")
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	if !root {
 		siaPath, err = rebaseInputSiaPath(siaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -1908,14 +3026,36 @@ func (api *API) renterStreamHandler(w http.ResponseWriter, req *http.Request, ps
 		disableLocalFetch, err = scanBool(disablelocalfetchparam)
 		if err != nil {
 			err = errors.AddContext(err, "error parsing the disablelocalfetch flag")
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
-	fileName, streamer, err := api.renter.Streamer(siaPath, disableLocalFetch)
-	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("failed to create download streamer: %v", err)},
-			http.StatusInternalServerError)
+
+	// If a range is requested, only the chunks covering the range are
+	// fetched. Range headers are relative to the start of the range.
+	var offset, length uint64
+	if offsetparam := req.FormValue("offset"); offsetparam != "" {
+		if _, err := fmt.Sscan(offsetparam, &offset); err != nil {
+			WriteError(w, Error{Message: "could not decode the offset as uint64: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if lengthparam := req.FormValue("length"); lengthparam != "" {
+		if _, err := fmt.Sscan(lengthparam, &length); err != nil {
+			WriteError(w, Error{Message: "could not decode the length as uint64: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	var fileName string
+	var streamer modules.Streamer
+	if offset == 0 && length == 0 {
+		fileName, streamer, err = api.renter.Streamer(siaPath, disableLocalFetch)
+	} else {
+		fileName, streamer, err = api.renter.StreamerRange(siaPath, offset, length, disableLocalFetch)
+	}
+	if err != nil {
+		WriteError(w, Error{Message: fmt.Sprintf("failed to create download streamer: %v", err), Code: renterErrorCode(err)},
+			http.StatusInternalServerError)
 		return
 	}
 	defer func() {
@@ -1930,7 +3070,7 @@ func (api *API) renterUploadHandler(w http.ResponseWriter, req *http.Request, ps
 	source := req.FormValue("source")
 	// Source must be absolute path.
 	if !filepath.IsAbs(source) {
-		WriteError(w, Error{"source must be an absolute path"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "source must be an absolute path"}, http.StatusBadRequest)
 		return
 	}
 	// Check whether existing file should be overwritten
@@ -1939,26 +3079,26 @@ func (api *API) renterUploadHandler(w http.ResponseWriter, req *http.Request, ps
 	if f := req.FormValue("force"); f != "" {
 		force, err = strconv.ParseBool(f)
 		if err != nil {
-			WriteError(w, Error{"unable to parse 'force' parameter: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse 'force' parameter: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	// Parse the erasure coder.
 	ec, err := parseErasureCodingParameters(req.FormValue("datapieces"), req.FormValue("paritypieces"))
 	if err != nil {
-		WriteError(w, Error{"unable to parse erasure code settings: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to parse erasure code settings: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Call the renter to upload the file.
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	siaPath, err = rebaseInputSiaPath(siaPath)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err = api.renter.Upload(modules.FileUploadParams{
@@ -1972,7 +3112,7 @@ func (api *API) renterUploadHandler(w http.ResponseWriter, req *http.Request, ps
 		CipherType: crypto.TypeDefaultRenter,
 	})
 	if err != nil {
-		WriteError(w, Error{"upload failed: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "upload failed: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 		return
 	}
 	WriteSuccess(w)
@@ -1988,7 +3128,7 @@ func (api *API) renterUploadReadyHandler(w http.ResponseWriter, req *http.Reques
 	// Check params
 	dataPieces, parityPieces, err := ParseDataAndParityPieces(dataPiecesStr, parityPiecesStr)
 	if err != nil {
-		WriteError(w, Error{"failed to parse query params: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to parse query params: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	// Check if we need to set to defaults
@@ -2018,7 +3158,7 @@ func (api *API) renterUploadsPauseHandler(w http.ResponseWriter, req *http.Reque
 	if durationStr != "" {
 		durationInt, err := strconv.ParseUint(durationStr, 10, 64)
 		if err != nil {
-			WriteError(w, Error{"failed to parse duration: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "failed to parse duration: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		duration = time.Second * time.Duration(durationInt)
@@ -2026,7 +3166,7 @@ func (api *API) renterUploadsPauseHandler(w http.ResponseWriter, req *http.Reque
 
 	err = api.renter.PauseRepairsAndUploads(duration)
 	if err != nil {
-		WriteError(w, Error{"failed to pause uploads: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to pause uploads: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -2037,7 +3177,7 @@ func (api *API) renterUploadsPauseHandler(w http.ResponseWriter, req *http.Reque
 func (api *API) renterUploadsResumeHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	err := api.renter.ResumeRepairsAndUploads()
 	if err != nil {
-		WriteError(w, Error{"failed to resume uploads: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to resume uploads: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -2049,7 +3189,7 @@ func (api *API) renterUploadStreamHandler(w http.ResponseWriter, req *http.Reque
 	// Parse the query params.
 	queryForm, err := url.ParseQuery(req.URL.RawQuery)
 	if err != nil {
-		WriteError(w, Error{"failed to parse query params"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to parse query params"}, http.StatusBadRequest)
 		return
 	}
 	// Check whether existing file should be overwritten
@@ -2057,7 +3197,7 @@ func (api *API) renterUploadStreamHandler(w http.ResponseWriter, req *http.Reque
 	if f := queryForm.Get("force"); f != "" {
 		force, err = strconv.ParseBool(f)
 		if err != nil {
-			WriteError(w, Error{"unable to parse 'force' parameter: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse 'force' parameter: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -2066,30 +3206,30 @@ func (api *API) renterUploadStreamHandler(w http.ResponseWriter, req *http.Reque
 	if r := queryForm.Get("repair"); r != "" {
 		repair, err = strconv.ParseBool(r)
 		if err != nil {
-			WriteError(w, Error{"unable to parse 'repair' parameter: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "unable to parse 'repair' parameter: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	// Parse the erasure coder.
 	ec, err := parseErasureCodingParameters(queryForm.Get("datapieces"), queryForm.Get("paritypieces"))
 	if err != nil && !repair {
-		WriteError(w, Error{"unable to parse erasure code settings: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to parse erasure code settings: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	if repair && ec != nil {
-		WriteError(w, Error{"can't provide erasure code settings when doing a repair"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "can't provide erasure code settings when doing a repair"}, http.StatusBadRequest)
 		return
 	}
 
 	// Call the renter to upload the file.
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	siaPath, err = rebaseInputSiaPath(siaPath)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	up := modules.FileUploadParams{
@@ -2103,7 +3243,134 @@ func (api *API) renterUploadStreamHandler(w http.ResponseWriter, req *http.Reque
 	}
 	err = api.renter.UploadStreamFromReader(up, req.Body)
 	if err != nil {
-		WriteError(w, Error{"upload failed: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "upload failed: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterUploadSessionsHandlerGET handles the API call to get the renter's
+// unfinished upload sessions.
+func (api *API) renterUploadSessionsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	sessions, err := api.renter.UploadSessions()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get upload sessions: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	sessions, err = trimUploadSessions(sessions...)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, RenterUploadSessionsGET{
+		Sessions: sessions,
+	})
+}
+
+// renterUploadSessionsHandlerPOST handles the API call to create a new
+// resumable upload session.
+func (api *API) renterUploadSessionsHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	// Check whether existing file should be overwritten
+	force := false
+	if f := req.FormValue("force"); f != "" {
+		var err error
+		force, err = strconv.ParseBool(f)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'force' parameter: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	// Parse the erasure coder.
+	ec, err := parseErasureCodingParameters(req.FormValue("datapieces"), req.FormValue("paritypieces"))
+	if err != nil {
+		WriteError(w, Error{Message: "unable to parse erasure code settings: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	siaPath, err = rebaseInputSiaPath(siaPath)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	session, err := api.renter.NewUploadSession(modules.FileUploadParams{
+		SiaPath:     siaPath,
+		ErasureCode: ec,
+		Force:       force,
+		CipherType:  crypto.TypeDefaultRenter,
+	})
+	if err != nil {
+		WriteError(w, Error{Message: "failed to create upload session: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	sessions, err := trimUploadSessions(session)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, sessions[0])
+}
+
+// renterUploadSessionHandlerGET handles the API call to get a single upload
+// session.
+func (api *API) renterUploadSessionHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	session, err := api.renter.UploadSession(modules.UploadSessionID(ps.ByName("id")))
+	if err != nil {
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	sessions, err := trimUploadSessions(session)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, sessions[0])
+}
+
+// renterUploadSessionHandlerPOST handles the API call to upload the request
+// body to an upload session.
+func (api *API) renterUploadSessionHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	// Parse the query params.
+	queryForm, err := url.ParseQuery(req.URL.RawQuery)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to parse query params"}, http.StatusBadRequest)
+		return
+	}
+	var offset uint64
+	if _, err := fmt.Sscan(queryForm.Get("offset"), &offset); err != nil {
+		WriteError(w, Error{Message: "unable to parse 'offset' parameter: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	final := false
+	if f := queryForm.Get("final"); f != "" {
+		final, err = strconv.ParseBool(f)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'final' parameter: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	session, err := api.renter.UploadSessionData(modules.UploadSessionID(ps.ByName("id")), offset, req.Body, final)
+	if err != nil {
+		WriteError(w, Error{Message: "upload failed: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	sessions, err := trimUploadSessions(session)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, sessions[0])
+}
+
+// renterUploadSessionAbortHandlerPOST handles the API call to abort an upload
+// session.
+func (api *API) renterUploadSessionAbortHandlerPOST(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	err := api.renter.AbortUploadSession(modules.UploadSessionID(ps.ByName("id")))
+	if err != nil {
+		WriteError(w, Error{Message: "failed to abort upload session: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -2114,7 +3381,7 @@ func (api *API) renterValidateSiaPathHandler(w http.ResponseWriter, _ *http.Requ
 	// Try and create a new siapath, this will validate the potential siapath
 	_, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -2128,7 +3395,7 @@ func (api *API) renterDirHandlerGET(w http.ResponseWriter, req *http.Request, ps
 	// Check whether the user is requesting the directory from the root path.
 	root, err := isCalledWithRootFlag(req)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -2139,28 +3406,28 @@ func (api *API) renterDirHandlerGET(w http.ResponseWriter, req *http.Request, ps
 		siaPath, err = modules.NewSiaPath(str)
 	}
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	if !root {
 		siaPath, err = rebaseInputSiaPath(siaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 
 	directories, err := api.renter.DirList(siaPath)
 	if err != nil {
-		WriteError(w, Error{"failed to get directory contents: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "failed to get directory contents: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 		return
 	}
 
 	if !root {
 		directories, err = trimSiaDirFolder(directories...)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -2173,14 +3440,14 @@ func (api *API) renterDirHandlerGET(w http.ResponseWriter, req *http.Request, ps
 		mu.Unlock()
 	})
 	if err != nil {
-		WriteError(w, Error{"failed to get file infos: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "failed to get file infos: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 		return
 	}
 
 	if !root {
 		files, err = trimSiaDirFolderOnFiles(files...)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -2192,13 +3459,299 @@ func (api *API) renterDirHandlerGET(w http.ResponseWriter, req *http.Request, ps
 	return
 }
 
+// renterDirDelete handles the 'delete' action of renterDirHandlerPOST. By
+// default the directory is deleted synchronously. The 'dryrun' parameter only
+// reports what would be deleted and the 'async' parameter deletes the
+// directory in the background. The progress of background deletions can be
+// polled using /renter/dirdeletions.
+func (api *API) renterDirDelete(w http.ResponseWriter, req *http.Request, siaPath modules.SiaPath, root bool) {
+	// Parse the 'dryrun' parameter
+	var dryRun bool
+	var err error
+	if d := req.FormValue("dryrun"); d != "" {
+		dryRun, err = scanBool(d)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'dryrun' parameter: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	// Parse the 'async' parameter
+	var async bool
+	if a := req.FormValue("async"); a != "" {
+		async, err = scanBool(a)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'async' parameter: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if dryRun && async {
+		WriteError(w, Error{Message: "'dryrun' and 'async' can't be combined"}, http.StatusBadRequest)
+		return
+	}
+
+	if !dryRun && !async {
+		err = api.renter.DeleteDir(siaPath)
+		if err != nil {
+			WriteError(w, Error{Message: "failed to delete directory: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+			return
+		}
+		WriteSuccess(w)
+		return
+	}
+
+	var status modules.DirDeletionStatus
+	if dryRun {
+		status, err = api.renter.DeleteDirDryRun(siaPath)
+	} else {
+		status, err = api.renter.DeleteDirAsync(siaPath)
+	}
+	if err != nil {
+		WriteError(w, Error{Message: "failed to delete directory: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	if !root {
+		status.SiaPath, err = status.SiaPath.Rebase(modules.UserFolder, modules.RootSiaPath())
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+	}
+	WriteJSON(w, status)
+}
+
+// renterDirDeletionsHandlerGET handles the API call to /renter/dirdeletions
+// which returns the status of the renter's recent background directory
+// deletions.
+func (api *API) renterDirDeletionsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	deletions := api.renter.DirDeletions()
+	if !root {
+		deletions = trimDirDeletions(deletions...)
+	}
+	WriteJSON(w, RenterDirDeletions{
+		Deletions: deletions,
+	})
+}
+
+// renterDirsHealthHandlerGET handles the API call to /renter/dirs/health which
+// returns the aggregated health of the subtrees of a directory and its
+// subdirectories.
+func (api *API) renterDirsHealthHandlerGET(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	siaPath, root, err := parseDirSiaPath(req, ps)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var recursive bool
+	if r := req.FormValue("recursive"); r != "" {
+		recursive, err = scanBool(r)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'recursive' arg: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	healths, err := api.renter.DirsHealth(siaPath, recursive)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get directory health: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	if !root {
+		healths, err = trimDirectoryHealths(healths...)
+		if err != nil {
+			WriteError(w, Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+	}
+	WriteJSON(w, RenterDirsHealthGET{
+		Directories: healths,
+	})
+}
+
+// renterDirsPinnedHandlerGET handles the API call to /renter/dirs/pinned which
+// returns the pinned directories.
+func (api *API) renterDirsPinnedHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	pinnedDirs := api.renter.PinnedDirs()
+	if !root {
+		pinnedDirs = trimSiaPaths(pinnedDirs...)
+	}
+	WriteJSON(w, RenterDirsPinnedGET{
+		PinnedDirs: pinnedDirs,
+	})
+}
+
+// renterDirsPinnedHandlerPOST handles the API call to /renter/dirs/pinned
+// which pins or unpins a directory.
+func (api *API) renterDirsPinnedHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	siaPath, _, err := parseDirSiaPath(req, ps)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	pinned := true
+	if p := req.FormValue("pinned"); p != "" {
+		pinned, err = scanBool(p)
+		if err != nil {
+			WriteError(w, Error{Message: "unable to parse 'pinned' arg: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	err = api.renter.SetDirPinned(siaPath, pinned)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to update pinned directories: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterEventsHandlerGET handles the API call to /renter/events which
+// long-polls the renter's event stream.
+func (api *API) renterEventsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var since uint64
+	if s := req.FormValue("since"); s != "" {
+		if _, err := fmt.Sscan(s, &since); err != nil {
+			WriteError(w, Error{Message: "unable to parse since: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	timeout := defaultRenterEventsTimeout
+	if t := req.FormValue("timeout"); t != "" {
+		var seconds uint64
+		if _, err := fmt.Sscan(t, &seconds); err != nil {
+			WriteError(w, Error{Message: "unable to parse timeout: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout > maxRenterEventsTimeout {
+		WriteError(w, Error{Message: fmt.Sprintf("timeout can't be longer than %v", maxRenterEventsTimeout)}, http.StatusBadRequest)
+		return
+	}
+
+	// Wait for events until the timeout expires or the client disconnects.
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+	events, err := api.renter.Events(ctx, since)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get events: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	lastID := since
+	if len(events) > 0 {
+		lastID = events[len(events)-1].ID
+	}
+	if !root {
+		events = trimRenterEvents(events...)
+	}
+	WriteJSON(w, RenterEventsGET{
+		Events: events,
+		LastID: lastID,
+	})
+}
+
+// renterSpendingHandlerGET handles the API call to /renter/spending which
+// returns the renter's spending within a period by contract.
+func (api *API) renterSpendingHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	settings, err := api.renter.Settings()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get renter settings: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	start := api.renter.CurrentPeriod()
+	if p := req.FormValue("period"); p != "" {
+		if _, err := fmt.Sscan(p, &start); err != nil {
+			WriteError(w, Error{Message: "unable to parse period: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	period := settings.Allowance.Period
+	if period == 0 {
+		WriteError(w, Error{Message: "the renter's allowance period is not set"}, http.StatusBadRequest)
+		return
+	}
+	spending, err := api.renter.Spending(start, start+period)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get spending: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, spending)
+}
+
+// renterChunkCacheHandlerGET handles the API call to /renter/chunkcache which
+// returns statistics about the renter's on-disk cache of downloaded chunks.
+func (api *API) renterChunkCacheHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, api.renter.ChunkCacheStats())
+}
+
+// renterArchiveHandlerGET handles the API call to /renter/archive which
+// returns the files and directories of the archive tier.
+func (api *API) renterArchiveHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	root, err := isCalledWithRootFlag(req)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	archivedPaths := api.renter.ArchivedPaths()
+	if !root {
+		archivedPaths = trimSiaPaths(archivedPaths...)
+	}
+	WriteJSON(w, RenterArchiveGET{
+		ArchivedPaths: archivedPaths,
+	})
+}
+
+// renterArchiveHandlerPOST handles the API call to /renter/archive which moves
+// a file or directory to the archive tier.
+func (api *API) renterArchiveHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	siaPath, _, err := parseDirSiaPath(req, ps)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = api.renter.ArchivePath(siaPath)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to archive siapath: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterThawHandlerPOST handles the API call to /renter/thaw which moves an
+// archived file or directory back to actively maintained status.
+func (api *API) renterThawHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	siaPath, _, err := parseDirSiaPath(req, ps)
+	if err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = api.renter.ThawPath(siaPath)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to thaw siapath: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // renterDirHandlerPOST handles POST requests to /renter/dir/:siapath?action=<>
 // in order to create, delete, and rename a directory
 func (api *API) renterDirHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	// Parse action
 	action := req.FormValue("action")
 	if action == "" {
-		WriteError(w, Error{"you must set the action you wish to execute"}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "you must set the action you wish to execute"}, http.StatusInternalServerError)
 		return
 	}
 	// Parse mode
@@ -2206,28 +3759,28 @@ func (api *API) renterDirHandlerPOST(w http.ResponseWriter, req *http.Request, p
 	if m := req.FormValue("mode"); m != "" {
 		mode64, err := strconv.ParseUint(m, 10, 32)
 		if err != nil {
-			WriteError(w, Error{fmt.Sprintf("failed to parse provided mode '%v'", m)}, http.StatusBadRequest)
+			WriteError(w, Error{Message: fmt.Sprintf("failed to parse provided mode '%v'", m)}, http.StatusBadRequest)
 			return
 		}
 		mode = os.FileMode(mode64)
 	}
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Determine whether the user is requesting a user siapath, or a root siapath.
 	root, err := isCalledWithRootFlag(req)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	// Rebase the user's input to the user folder if the user is requesting a user siapath.
 	if !root {
 		siaPath, err = rebaseInputSiaPath(siaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -2236,35 +3789,30 @@ func (api *API) renterDirHandlerPOST(w http.ResponseWriter, req *http.Request, p
 		// Call the renter to create directory
 		err := api.renter.CreateDir(siaPath, mode)
 		if err != nil {
-			WriteError(w, Error{"failed to create directory: " + err.Error()}, http.StatusInternalServerError)
+			WriteError(w, Error{Message: "failed to create directory: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 			return
 		}
 		WriteSuccess(w)
 		return
 	}
 	if action == "delete" {
-		err := api.renter.DeleteDir(siaPath)
-		if err != nil {
-			WriteError(w, Error{"failed to delete directory: " + err.Error()}, http.StatusInternalServerError)
-			return
-		}
-		WriteSuccess(w)
+		api.renterDirDelete(w, req, siaPath, root)
 		return
 	}
 	if action == "rename" {
 		newSiaPath, err := modules.NewSiaPath(req.FormValue("newsiapath"))
 		if err != nil {
-			WriteError(w, Error{"failed to parse newsiapath: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "failed to parse newsiapath: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		newSiaPath, err = rebaseInputSiaPath(newSiaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 		err = api.renter.RenameDir(siaPath, newSiaPath)
 		if err != nil {
-			WriteError(w, Error{"failed to rename directory: " + err.Error()}, http.StatusInternalServerError)
+			WriteError(w, Error{Message: "failed to rename directory: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 			return
 		}
 		WriteSuccess(w)
@@ -2272,7 +3820,7 @@ func (api *API) renterDirHandlerPOST(w http.ResponseWriter, req *http.Request, p
 	}
 
 	// Report that no calls were made
-	WriteError(w, Error{"no calls were made, please check your submission and try again"}, http.StatusInternalServerError)
+	WriteError(w, Error{Message: "no calls were made, please check your submission and try again"}, http.StatusInternalServerError)
 	return
 }
 
@@ -2281,57 +3829,159 @@ func (api *API) renterDirHandlerPOST(w http.ResponseWriter, req *http.Request, p
 func (api *API) renterContractStatusHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var fcID types.FileContractID
 	if err := fcID.LoadString(req.FormValue("id")); err != nil {
-		WriteError(w, Error{"unable to parse id: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "unable to parse id: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	contractStatus, monitoringContract := api.renter.ContractStatus(fcID)
 	if !monitoringContract {
-		WriteError(w, Error{"renter unaware of contract"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "renter unaware of contract"}, http.StatusBadRequest)
 		return
 	}
 
 	WriteJSON(w, contractStatus)
 }
 
+// scanAccountFundingPolicy scans the optional fields of an account funding
+// policy from the request. The names of the fields are prefixed with the given
+// prefix.
+func scanAccountFundingPolicy(req *http.Request, prefix string, policy *modules.AccountFundingPolicy) error {
+	fields := []struct {
+		name  string
+		value *types.Currency
+	}{
+		{"balancetarget", &policy.BalanceTarget},
+		{"refillthreshold", &policy.RefillThreshold},
+		{"maxdrifttolerance", &policy.MaxDriftTolerance},
+	}
+	for _, f := range fields {
+		str := req.FormValue(prefix + f.name)
+		if str == "" {
+			continue
+		}
+		amount, ok := scanAmount(str)
+		if !ok {
+			return fmt.Errorf("unable to parse %v", prefix+f.name)
+		}
+		*f.value = amount
+	}
+	return nil
+}
+
+// renterAccountsHandlerGET handles the API call to /renter/accounts which
+// returns the account funding policy and the money lost to hosts resetting
+// the renter's ephemeral accounts.
+func (api *API) renterAccountsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	report, err := api.renter.AccountFunding()
+	if err != nil {
+		WriteError(w, Error{Message: "unable to get account funding: " + err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, report)
+}
+
+// renterAccountsPolicyHandlerPOST handles the API call to
+// /renter/accounts/policy which sets the account funding policy of a host.
+// Fields that are not provided fall back to the renter-wide policy.
+func (api *API) renterAccountsPolicyHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var hostKey types.SiaPublicKey
+	if err := hostKey.LoadString(req.FormValue("hostkey")); err != nil {
+		WriteError(w, Error{Message: "unable to parse hostkey: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var policy modules.AccountFundingPolicy
+	if err := scanAccountFundingPolicy(req, "", &policy); err != nil {
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := api.renter.SetHostAccountFundingPolicy(hostKey, policy); err != nil {
+		WriteError(w, Error{Message: "failed to set account funding policy: " + err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // renterWorkersHandler handles the API call to check the status of the renter's
 // workers
 func (api *API) renterWorkersHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	workerPoolStatus, err := api.renter.WorkerPoolStatus()
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusBadRequest)
 		return
 	}
 
 	WriteJSON(w, workerPoolStatus)
 }
 
+// renterWorkerJobHistoryHandler handles the API call to get the renter's
+// failed worker jobs.
+func (api *API) renterWorkerJobHistoryHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var filter modules.WorkerJobFailureFilter
+	if host := req.FormValue("host"); host != "" {
+		filter.HostPubKey.LoadString(host)
+		if filter.HostPubKey.Key == nil {
+			WriteError(w, Error{Message: "invalid host public key"}, http.StatusBadRequest)
+			return
+		}
+	}
+	filter.JobType = modules.WorkerJobType(req.FormValue("jobtype"))
+	filter.ErrorCode = modules.WorkerJobErrorCode(req.FormValue("errorcode"))
+	for _, param := range []struct {
+		name string
+		t    *time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		str := req.FormValue(param.name)
+		if str == "" {
+			continue
+		}
+		var unix int64
+		if _, err := fmt.Sscan(str, &unix); err != nil {
+			WriteError(w, Error{Message: fmt.Sprintf("unable to parse '%v' as unix timestamp: %v", param.name, err)}, http.StatusBadRequest)
+			return
+		}
+		*param.t = time.Unix(unix, 0)
+	}
+	if limit := req.FormValue("limit"); limit != "" {
+		_, err := fmt.Sscan(limit, &filter.Limit)
+		if err != nil || filter.Limit < 0 {
+			WriteError(w, Error{Message: "unable to parse 'limit' as non-negative integer"}, http.StatusBadRequest)
+			return
+		}
+	}
+	WriteJSON(w, RenterWorkerJobHistoryGET{
+		Failures: api.renter.WorkerJobHistory(filter),
+	})
+}
+
 func (api *API) renterFileHostsHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	// Determine the siapath that the user wants to get the file from.
 	siaPath, err := modules.NewSiaPath(ps.ByName("siapath"))
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Determine whether the user is requesting a user siapath, or a root siapath.
 	root, err := isCalledWithRootFlag(req)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	// Rebase the user's input to the user folder if the user is requesting a user siapath.
 	if !root {
 		siaPath, err = rebaseInputSiaPath(siaPath)
 		if err != nil {
-			WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 
 	hosts, err := api.renter.FileHosts(siaPath)
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: err.Error(), Code: renterErrorCode(err)}, http.StatusInternalServerError)
 		return
 	}
 
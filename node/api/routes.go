@@ -48,7 +48,7 @@ func (api *API) buildHTTPRoutes() {
 
 	// Consensus API Calls
 	if api.cs != nil {
-		RegisterRoutesConsensus(router, api.cs)
+		RegisterRoutesConsensus(router, api.cs, requiredPassword)
 	}
 
 	// Explorer API Calls
@@ -59,6 +59,13 @@ func (api *API) buildHTTPRoutes() {
 	// Gateway API Calls
 	if api.gateway != nil {
 		RegisterRoutesGateway(router, api.gateway, requiredPassword)
+
+		// Register health separately since it depends on the consensus set.
+		if api.cs != nil {
+			router.GET("/gateway/health", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+				gatewayHealthHandlerGET(api.gateway, api.cs, w, req, ps)
+			})
+		}
 	}
 
 	// Host API Calls
@@ -80,46 +87,85 @@ func (api *API) buildHTTPRoutes() {
 	if api.renter != nil {
 		router.GET("/renter", api.renterHandlerGET)
 		router.POST("/renter", RequirePassword(api.renterHandlerPOST, requiredPassword))
+		router.GET("/renter/accounts", api.renterAccountsHandlerGET)
+		router.POST("/renter/accounts/policy", RequirePassword(api.renterAccountsPolicyHandlerPOST, requiredPassword))
 		router.POST("/renter/allowance/cancel", RequirePassword(api.renterAllowanceCancelHandlerPOST, requiredPassword))
+		router.GET("/renter/archive", api.renterArchiveHandlerGET)
+		router.POST("/renter/archive/*siapath", RequirePassword(api.renterArchiveHandlerPOST, requiredPassword))
 		router.POST("/renter/bubble", api.renterBubbleHandlerPOST)
 		router.GET("/renter/backups", RequirePassword(api.renterBackupsHandlerGET, requiredPassword))
 		router.POST("/renter/backups/create", RequirePassword(api.renterBackupsCreateHandlerPOST, requiredPassword))
+		router.POST("/renter/backups/prune", RequirePassword(api.renterBackupsPruneHandlerPOST, requiredPassword))
 		router.POST("/renter/backups/restore", RequirePassword(api.renterBackupsRestoreHandlerGET, requiredPassword))
+		router.GET("/renter/backups/schedule", RequirePassword(api.renterBackupsScheduleHandlerGET, requiredPassword))
+		router.POST("/renter/backups/schedule", RequirePassword(api.renterBackupsScheduleHandlerPOST, requiredPassword))
+		router.POST("/renter/backups/schedule/run", RequirePassword(api.renterBackupsScheduleRunHandlerPOST, requiredPassword))
+		router.GET("/renter/chunkcache", api.renterChunkCacheHandlerGET)
 		router.POST("/renter/clean", RequirePassword(api.renterCleanHandlerPOST, requiredPassword))
 		router.POST("/renter/contract/cancel", RequirePassword(api.renterContractCancelHandler, requiredPassword))
 		router.GET("/renter/contracts", api.renterContractsHandler)
 		router.GET("/renter/contractorchurnstatus", api.renterContractorChurnStatus)
+		router.GET("/renter/contractorhostlists", api.renterContractorHostListsHandlerGET)
+		router.POST("/renter/contractorhostlists", RequirePassword(api.renterContractorHostListsHandlerPOST, requiredPassword))
+		router.GET("/renter/contractormigrations", api.renterContractorMigrationsHandlerGET)
+		router.GET("/renter/dirdeletions", api.renterDirDeletionsHandlerGET)
 		router.GET("/renter/downloadinfo/*uid", api.renterDownloadByUIDHandlerGET)
 		router.GET("/renter/downloads", api.renterDownloadsHandler)
+		router.GET("/renter/downloads/batch", api.renterDownloadsBatchHandler)
 		router.POST("/renter/downloads/clear", RequirePassword(api.renterClearDownloadsHandler, requiredPassword))
+		router.POST("/renter/downloads/overdrive", RequirePassword(api.renterDownloadOverdriveHandlerPOST, requiredPassword))
+		router.GET("/renter/events", api.renterEventsHandlerGET)
 		router.GET("/renter/files", api.renterFilesHandler)
 		router.GET("/renter/file/*siapath", api.renterFileHandlerGET)
 		router.POST("/renter/file/*siapath", RequirePassword(api.renterFileHandlerPOST, requiredPassword))
+		router.GET("/renter/filekeys/*siapath", RequirePassword(api.renterFileKeysHandlerGET, requiredPassword))
+		router.POST("/renter/loadsharedfile/*siapath", RequirePassword(api.renterLoadSharedFileHandlerPOST, requiredPassword))
+		router.POST("/renter/sharefile/*siapath", RequirePassword(api.renterShareFileHandlerPOST, requiredPassword))
 		router.GET("/renter/prices", api.renterPricesHandler)
 		router.POST("/renter/recoveryscan", RequirePassword(api.renterRecoveryScanHandlerPOST, requiredPassword))
 		router.GET("/renter/recoveryscan", api.renterRecoveryScanHandlerGET)
+		router.GET("/renter/registry", api.renterRegistryHandlerGET)
+		router.POST("/renter/registry", RequirePassword(api.renterRegistryHandlerPOST, requiredPassword))
+		router.GET("/renter/restoredrills", api.renterRestoreDrillsHandlerGET)
+		router.POST("/renter/restoredrills", RequirePassword(api.renterRestoreDrillsHandlerPOST, requiredPassword))
+		router.POST("/renter/restoredrills/run", RequirePassword(api.renterRestoreDrillsRunHandlerPOST, requiredPassword))
 		router.GET("/renter/fuse", api.renterFuseHandlerGET)
 		router.POST("/renter/fuse/mount", RequirePassword(api.renterFuseMountHandlerPOST, requiredPassword))
 		router.POST("/renter/fuse/unmount", RequirePassword(api.renterFuseUnmountHandlerPOST, requiredPassword))
 
+		router.POST("/renter/delete", RequirePassword(api.renterBatchDeleteHandler, requiredPassword))
 		router.POST("/renter/delete/*siapath", RequirePassword(api.renterDeleteHandler, requiredPassword))
 		router.GET("/renter/download/*siapath", RequirePassword(api.renterDownloadHandler, requiredPassword))
 		router.POST("/renter/download/cancel", RequirePassword(api.renterCancelDownloadHandler, requiredPassword))
 		router.GET("/renter/downloadasync/*siapath", RequirePassword(api.renterDownloadAsyncHandler, requiredPassword))
+		router.POST("/renter/reencode/*siapath", RequirePassword(api.renterReencodeHandlerPOST, requiredPassword))
+		router.GET("/renter/reencodes", api.renterReencodesHandlerGET)
 		router.POST("/renter/rename/*siapath", RequirePassword(api.renterRenameHandler, requiredPassword))
+		router.GET("/renter/spending", api.renterSpendingHandlerGET)
 		router.GET("/renter/stream/*siapath", api.renterStreamHandler)
 		router.POST("/renter/upload/*siapath", RequirePassword(api.renterUploadHandler, requiredPassword))
+		router.POST("/renter/thaw/*siapath", RequirePassword(api.renterThawHandlerPOST, requiredPassword))
 		router.GET("/renter/uploadready", api.renterUploadReadyHandler)
 		router.POST("/renter/uploads/pause", RequirePassword(api.renterUploadsPauseHandler, requiredPassword))
 		router.POST("/renter/uploads/resume", RequirePassword(api.renterUploadsResumeHandler, requiredPassword))
 		router.POST("/renter/uploadstream/*siapath", RequirePassword(api.renterUploadStreamHandler, requiredPassword))
+		router.GET("/renter/uploadsessions", api.renterUploadSessionsHandlerGET)
+		router.POST("/renter/uploadsessions/*siapath", RequirePassword(api.renterUploadSessionsHandlerPOST, requiredPassword))
+		router.GET("/renter/uploadsession/:id", api.renterUploadSessionHandlerGET)
+		router.POST("/renter/uploadsession/:id", RequirePassword(api.renterUploadSessionHandlerPOST, requiredPassword))
+		router.POST("/renter/uploadsession/:id/abort", RequirePassword(api.renterUploadSessionAbortHandlerPOST, requiredPassword))
+		router.POST("/renter/verify/*siapath", RequirePassword(api.renterVerifyHandlerPOST, requiredPassword))
 		router.POST("/renter/validatesiapath/*siapath", RequirePassword(api.renterValidateSiaPathHandler, requiredPassword))
 		router.GET("/renter/workers", api.renterWorkersHandler)
+		router.GET("/renter/workers/history", api.renterWorkerJobHistoryHandler)
 		router.GET("/renter/hosts/*siapath", api.renterFileHostsHandler)
 
 		// Directory endpoints
 		router.POST("/renter/dir/*siapath", RequirePassword(api.renterDirHandlerPOST, requiredPassword))
 		router.GET("/renter/dir/*siapath", api.renterDirHandlerGET)
+		router.GET("/renter/dirs/health/*siapath", api.renterDirsHealthHandlerGET)
+		router.GET("/renter/dirs/pinned", api.renterDirsPinnedHandlerGET)
+		router.POST("/renter/dirs/pinned/*siapath", RequirePassword(api.renterDirsPinnedHandlerPOST, requiredPassword))
 
 		// HostDB endpoints.
 		router.GET("/hostdb", api.hostdbHandler)
@@ -139,7 +185,7 @@ func (api *API) buildHTTPRoutes() {
 
 	// Transaction pool API Calls
 	if api.tpool != nil {
-		RegisterRoutesTransactionPool(router, api.tpool)
+		RegisterRoutesTransactionPool(router, api.tpool, requiredPassword)
 	}
 
 	// Wallet API Calls
@@ -172,7 +218,7 @@ func timeoutHandler(h http.Handler, timeout time.Duration) http.Handler {
 func RequireUserAgent(h http.Handler, ua string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		if !strings.Contains(req.UserAgent(), ua) && !isUnrestricted(req) {
-			WriteError(w, Error{"Browser access disabled due to security vulnerability. Use Sia-UI or siac."}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "Browser access disabled due to security vulnerability. Use Sia-UI or siac."}, http.StatusBadRequest)
 			return
 		}
 		h.ServeHTTP(w, req)
@@ -191,7 +237,7 @@ func RequirePassword(h httprouter.Handle, password string) httprouter.Handle {
 		_, pass, ok := req.BasicAuth()
 		if !ok || pass != password {
 			w.Header().Set("WWW-Authenticate", "Basic realm=\"SiaAPI\"")
-			WriteError(w, Error{"API authentication failed."}, http.StatusUnauthorized)
+			WriteError(w, Error{Message: "API authentication failed."}, http.StatusUnauthorized)
 			return
 		}
 		h(w, req, ps)
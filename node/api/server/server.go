@@ -133,7 +133,7 @@ func (srv *Server) Unlock(password string) error {
 		return errors.New("server doesn't have a wallet")
 	}
 	var validKeys []crypto.CipherKey
-	dicts := []mnemonics.DictionaryID{"english", "german", "japanese"}
+	dicts := []mnemonics.DictionaryID{"english", "german", "japanese", modules.BIP39Dictionary}
 	for _, dict := range dicts {
 		seed, err := modules.StringToSeed(password, dict)
 		if err != nil {
@@ -177,7 +177,7 @@ func assembleServerTesterWithDeps(key crypto.CipherKey, testdir string, gDeps, c
 	}
 
 	// Create the modules.
-	g, err := gateway.NewCustomGateway("localhost:0", false, false, filepath.Join(testdir, modules.GatewayDir), gDeps)
+	g, err := gateway.NewCustomGateway("localhost:0", false, false, gateway.ProxySettings{}, filepath.Join(testdir, modules.GatewayDir), gDeps)
 	if err != nil {
 		return nil, err
 	}
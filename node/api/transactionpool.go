@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
@@ -14,13 +15,35 @@ import (
 	"go.sia.tech/siad/types"
 )
 
+const (
+	// defaultTpoolContentsLimit is the number of transaction sets returned by
+	// /tpool/contents if no limit is provided.
+	defaultTpoolContentsLimit = 100
+
+	// maxTpoolContentsLimit is the maximum number of transaction sets
+	// returned by /tpool/contents.
+	maxTpoolContentsLimit = 1000
+)
+
 type (
+	// TpoolContentsGET contains a page of the transaction sets of the
+	// transaction pool, sorted by fee rate in descending order.
+	TpoolContentsGET struct {
+		modules.TransactionPoolContents
+	}
+
 	// TpoolFeeGET contains the current estimated fee
 	TpoolFeeGET struct {
 		Minimum types.Currency `json:"minimum"`
 		Maximum types.Currency `json:"maximum"`
 	}
 
+	// TpoolFeeEstimateGET contains the fee estimations for different
+	// confirmation targets and the statistics they are based on.
+	TpoolFeeEstimateGET struct {
+		modules.FeeEstimate
+	}
+
 	// TpoolRawGET contains the requested transaction encoded to the raw
 	// format, along with the id of that transaction.
 	TpoolRawGET struct {
@@ -43,10 +66,19 @@ type (
 
 // RegisterRoutesTransactionPool is a helper function to register all
 // transaction pool routes.
-func RegisterRoutesTransactionPool(router *httprouter.Router, tpool modules.TransactionPool) {
+func RegisterRoutesTransactionPool(router *httprouter.Router, tpool modules.TransactionPool, requiredPassword string) {
+	router.GET("/tpool/contents", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		tpoolContentsHandlerGET(tpool, w, req, ps)
+	})
 	router.GET("/tpool/fee", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		tpoolFeeHandlerGET(tpool, w, req, ps)
 	})
+	router.GET("/tpool/feeestimate", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		tpoolFeeEstimateHandlerGET(tpool, w, req, ps)
+	})
+	router.POST("/tpool/maxsize", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		tpoolMaxSizeHandlerPOST(tpool, w, req, ps)
+	}, requiredPassword))
 	router.GET("/tpool/raw/:id", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		tpoolRawHandlerGET(tpool, w, req, ps)
 	})
@@ -71,6 +103,49 @@ func decodeTransactionID(txidStr string) (types.TransactionID, error) {
 	return types.TransactionID(*txid), nil
 }
 
+// tpoolContentsHandlerGET returns a page of the transaction sets of the
+// transaction pool, sorted by fee rate in descending order.
+func tpoolContentsHandlerGET(tpool modules.TransactionPool, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var offset uint64
+	if o := req.FormValue("offset"); o != "" {
+		if _, err := fmt.Sscan(o, &offset); err != nil {
+			WriteError(w, Error{Message: "failed to parse offset: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	limit := uint64(defaultTpoolContentsLimit)
+	if l := req.FormValue("limit"); l != "" {
+		if _, err := fmt.Sscan(l, &limit); err != nil {
+			WriteError(w, Error{Message: "failed to parse limit: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if limit > maxTpoolContentsLimit {
+		WriteError(w, Error{Message: fmt.Sprintf("limit can't be greater than %v", maxTpoolContentsLimit)}, http.StatusBadRequest)
+		return
+	}
+	contents, err := tpool.Contents(offset, limit)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get transaction pool contents: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, TpoolContentsGET{contents})
+}
+
+// tpoolMaxSizeHandlerPOST sets the maximum size of the transaction pool.
+func tpoolMaxSizeHandlerPOST(tpool modules.TransactionPool, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var size uint64
+	if _, err := fmt.Sscan(req.FormValue("size"), &size); err != nil {
+		WriteError(w, Error{Message: "failed to parse size: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := tpool.SetMaxSize(size); err != nil {
+		WriteError(w, Error{Message: "failed to set maximum size: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // tpoolFeeHandlerGET returns the current estimated fee. Transactions with
 // fees are lower than the estimated fee may take longer to confirm.
 func tpoolFeeHandlerGET(tpool modules.TransactionPool, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
@@ -81,17 +156,24 @@ func tpoolFeeHandlerGET(tpool modules.TransactionPool, w http.ResponseWriter, _
 	})
 }
 
+// tpoolFeeEstimateHandlerGET returns the fee estimations for different
+// confirmation targets, which are based on the fees of recent blocks and the
+// transactions in the transaction pool.
+func tpoolFeeEstimateHandlerGET(tpool modules.TransactionPool, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	WriteJSON(w, TpoolFeeEstimateGET{tpool.FeeEstimate()})
+}
+
 // tpoolRawHandlerGET will provide the raw byte representation of a
 // transaction that matches the input id.
 func tpoolRawHandlerGET(tpool modules.TransactionPool, w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
 	txid, err := decodeTransactionID(ps.ByName("id"))
 	if err != nil {
-		WriteError(w, Error{"error decoding transaction id: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error decoding transaction id: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	txn, parents, exists := tpool.Transaction(txid)
 	if !exists {
-		WriteError(w, Error{"transaction not found in transaction pool"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "transaction not found in transaction pool"}, http.StatusBadRequest)
 		return
 	}
 
@@ -116,7 +198,7 @@ func tpoolRawHandlerPOST(tpool modules.TransactionPool, w http.ResponseWriter, r
 			rawParents = []byte(req.FormValue("parents"))
 		}
 		if err := encoding.Unmarshal(rawParents, &parents); err != nil {
-			WriteError(w, Error{"error decoding parents: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error decoding parents: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -126,7 +208,7 @@ func tpoolRawHandlerPOST(tpool modules.TransactionPool, w http.ResponseWriter, r
 			rawTransaction = []byte(req.FormValue("transaction"))
 		}
 		if err := encoding.Unmarshal(rawTransaction, &txn); err != nil {
-			WriteError(w, Error{"error decoding transaction: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error decoding transaction: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -136,7 +218,7 @@ func tpoolRawHandlerPOST(tpool modules.TransactionPool, w http.ResponseWriter, r
 	tpool.Broadcast(txnSet)
 	err := tpool.AcceptTransactionSet(txnSet)
 	if err != nil && !errors.Contains(err, modules.ErrDuplicateTransactionSet) {
-		WriteError(w, Error{"error accepting transaction set: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error accepting transaction set: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -147,12 +229,12 @@ func tpoolRawHandlerPOST(tpool modules.TransactionPool, w http.ResponseWriter, r
 func tpoolConfirmedGET(tpool modules.TransactionPool, w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
 	txid, err := decodeTransactionID(ps.ByName("id"))
 	if err != nil {
-		WriteError(w, Error{"error decoding transaction id: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error decoding transaction id: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	confirmed, err := tpool.TransactionConfirmed(txid)
 	if err != nil {
-		WriteError(w, Error{"error fetching transaction status: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error fetching transaction status: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, TpoolConfirmedGET{
@@ -205,6 +205,32 @@ func TestTransactionPoolFee(t *testing.T) {
 	}
 }
 
+// TestTransactionPoolFeeEstimate tests the /tpool/feeestimate endpoint.
+func TestTransactionPoolFeeEstimate(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	st, err := createServerTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var est TpoolFeeEstimateGET
+	err = st.getAPI("/tpool/feeestimate", &est)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	min, max := st.tpool.FeeEstimation()
+	if !min.Equals(est.Standard) || !max.Equals(est.Priority) {
+		t.Fatal("fee mismatch")
+	}
+	if est.Economy.Cmp(est.Standard) > 0 || est.Standard.Cmp(est.Priority) > 0 {
+		t.Fatal("estimations aren't ordered by their targets", est)
+	}
+}
+
 // TestTransactionPoolConfirmed tests the /tpool/confirmed endpoint.
 func TestTransactionPoolConfirmed(t *testing.T) {
 	if testing.Short() {
@@ -1,17 +1,24 @@
 package api
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math"
 	"net/http"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/julienschmidt/httprouter"
 	mnemonics "gitlab.com/NebulousLabs/entropy-mnemonics"
 	"gitlab.com/NebulousLabs/errors"
+	"golang.org/x/net/websocket"
 
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/modules"
@@ -48,6 +55,20 @@ type (
 		Addresses []types.UnlockHash `json:"addresses"`
 	}
 
+	// WalletAddressBookGET contains the named addresses of the wallet's
+	// address book.
+	WalletAddressBookGET struct {
+		Entries []modules.AddressBookEntry `json:"entries"`
+	}
+
+	// WalletAddressBookPOST contains the address book entry to add, change or
+	// remove.
+	WalletAddressBookPOST struct {
+		Name    string           `json:"name"`
+		Address types.UnlockHash `json:"address"`
+		Remove  bool             `json:"remove"`
+	}
+
 	// WalletInitPOST contains the primary seed that gets generated during a
 	// POST call to /wallet/init.
 	WalletInitPOST struct {
@@ -59,6 +80,33 @@ type (
 	WalletSiacoinsPOST struct {
 		Transactions   []types.Transaction   `json:"transactions"`
 		TransactionIDs []types.TransactionID `json:"transactionids"`
+		PendingID      string                `json:"pendingid,omitempty"`
+	}
+
+	// WalletBumpFeePOST contains the transaction set created in the POST
+	// calls to /wallet/transaction/:id/bumpfee and
+	// /wallet/transaction/:id/cpfp.
+	WalletBumpFeePOST struct {
+		Transactions   []types.Transaction   `json:"transactions"`
+		TransactionIDs []types.TransactionID `json:"transactionids"`
+	}
+
+	// WalletLimitsGET contains the wallet's spending limits and the siacoins
+	// it sent within the last day.
+	WalletLimitsGET struct {
+		modules.WalletSpendingLimits
+		Spent types.Currency `json:"spent"`
+	}
+
+	// WalletPendingGET contains the sends which are waiting for approval.
+	WalletPendingGET struct {
+		Sends []modules.PendingSend `json:"sends"`
+	}
+
+	// WalletStuckGET contains the transaction sets broadcast by the wallet
+	// which are stuck.
+	WalletStuckGET struct {
+		Sets []modules.BroadcastTransactionSet `json:"sets"`
 	}
 
 	// WalletSiafundsPOST contains the transaction sent in the POST call to
@@ -68,6 +116,70 @@ type (
 		TransactionIDs []types.TransactionID `json:"transactionids"`
 	}
 
+	// WalletMultisigAddressPOSTParams contains the public keys and the number
+	// of required signatures of a multisig address.
+	WalletMultisigAddressPOSTParams struct {
+		PublicKeys         []types.SiaPublicKey `json:"publickeys"`
+		SignaturesRequired uint64               `json:"signaturesrequired"`
+		Unused             bool                 `json:"unused"`
+	}
+
+	// WalletMultisigAddressPOSTResp contains a multisig address and its
+	// unlock conditions.
+	WalletMultisigAddressPOSTResp struct {
+		Address          types.UnlockHash       `json:"address"`
+		UnlockConditions types.UnlockConditions `json:"unlockconditions"`
+	}
+
+	// WalletMultisigTransactionPOSTParams contains the multisig address to
+	// send from and the outputs to create.
+	WalletMultisigTransactionPOSTParams struct {
+		Address types.UnlockHash      `json:"address"`
+		Outputs []types.SiacoinOutput `json:"outputs"`
+	}
+
+	// WalletMultisigSignPOSTParams contains a multisig transaction to sign.
+	WalletMultisigSignPOSTParams struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+
+	// WalletMultisigMergePOSTParams contains copies of a multisig transaction
+	// signed by different parties.
+	WalletMultisigMergePOSTParams struct {
+		Transactions []types.Transaction `json:"transactions"`
+	}
+
+	// WalletMultisigPOSTResp contains a multisig transaction and the number
+	// of signatures it still needs before it can be broadcast.
+	WalletMultisigPOSTResp struct {
+		Transaction       types.Transaction `json:"transaction"`
+		MissingSignatures uint64            `json:"missingsignatures"`
+	}
+
+	// WalletColdTransactionPOSTParams contains the outputs of a cold
+	// transaction and the address which receives the change.
+	WalletColdTransactionPOSTParams struct {
+		Outputs       []types.SiacoinOutput `json:"outputs"`
+		ChangeAddress types.UnlockHash      `json:"changeaddress"`
+	}
+
+	// WalletColdTransactionPOSTResp contains an unsigned cold transaction.
+	WalletColdTransactionPOSTResp struct {
+		modules.ColdTransaction
+	}
+
+	// WalletColdBroadcastPOSTParams contains a cold transaction which was
+	// signed offline.
+	WalletColdBroadcastPOSTParams struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+
+	// WalletRescanGET contains the progress of the wallet's rescan of the
+	// blockchain.
+	WalletRescanGET struct {
+		modules.WalletRescanProgress
+	}
+
 	// WalletSignPOSTParams contains the unsigned transaction and a set of
 	// inputs to sign.
 	WalletSignPOSTParams struct {
@@ -80,6 +192,13 @@ type (
 		Transaction types.Transaction `json:"transaction"`
 	}
 
+	// WalletSeedBackupPOST contains a passphrase encrypted backup of the
+	// wallet's primary seed.
+	WalletSeedBackupPOST struct {
+		Backup    modules.SeedBackup `json:"backup"`
+		QRPayload string             `json:"qrpayload"`
+	}
+
 	// WalletSeedsGET contains the seeds used by the wallet.
 	WalletSeedsGET struct {
 		PrimarySeed        string   `json:"primaryseed"`
@@ -98,6 +217,7 @@ type (
 	// /wallet/transaction/:id
 	WalletTransactionGETid struct {
 		Transaction modules.ProcessedTransaction `json:"transaction"`
+		Label       string                       `json:"label"`
 	}
 
 	// WalletTransactionsGET contains the specified set of confirmed and
@@ -105,6 +225,9 @@ type (
 	WalletTransactionsGET struct {
 		ConfirmedTransactions   []modules.ProcessedTransaction `json:"confirmedtransactions"`
 		UnconfirmedTransactions []modules.ProcessedTransaction `json:"unconfirmedtransactions"`
+
+		// Labels maps the ids of the labeled transactions to their labels.
+		Labels map[string]string `json:"labels"`
 	}
 
 	// WalletTransactionsGETaddr contains the set of wallet transactions
@@ -113,6 +236,9 @@ type (
 	WalletTransactionsGETaddr struct {
 		ConfirmedTransactions   []modules.ProcessedTransaction `json:"confirmedtransactions"`
 		UnconfirmedTransactions []modules.ProcessedTransaction `json:"unconfirmedtransactions"`
+
+		// Labels maps the ids of the labeled transactions to their labels.
+		Labels map[string]string `json:"labels"`
 	}
 
 	// WalletUnlockConditionsGET contains a set of unlock conditions.
@@ -132,6 +258,13 @@ type (
 		Outputs []modules.UnspentOutput `json:"outputs"`
 	}
 
+	// WalletUnspentPOSTParams contains the outputs to mark or unmark as
+	// do-not-spend.
+	WalletUnspentPOSTParams struct {
+		IDs        []types.OutputID `json:"ids"`
+		DoNotSpend bool             `json:"donotspend"`
+	}
+
 	// WalletVerifyAddressGET contains a bool indicating if the address passed to
 	// /wallet/verify/address/:addr is a valid address.
 	WalletVerifyAddressGET struct {
@@ -146,11 +279,12 @@ type (
 	}
 
 	// WalletWatchPOST contains the set of addresses to add or remove from the
-	// watch set.
+	// watch set. Public keys are watched through their standard addresses.
 	WalletWatchPOST struct {
-		Addresses []types.UnlockHash `json:"addresses"`
-		Remove    bool               `json:"remove"`
-		Unused    bool               `json:"unused"`
+		Addresses  []types.UnlockHash   `json:"addresses"`
+		PublicKeys []types.SiaPublicKey `json:"publickeys"`
+		Remove     bool                 `json:"remove"`
+		Unused     bool                 `json:"unused"`
 	}
 
 	// WalletWatchGET contains the set of addresses that the wallet is
@@ -158,6 +292,24 @@ type (
 	WalletWatchGET struct {
 		Addresses []types.UnlockHash `json:"addresses"`
 	}
+
+	// WalletWatchBalanceGET contains the balances of the watch-only
+	// addresses and their totals.
+	WalletWatchBalanceGET struct {
+		ConfirmedSiacoinBalance     types.Currency `json:"confirmedsiacoinbalance"`
+		UnconfirmedOutgoingSiacoins types.Currency `json:"unconfirmedoutgoingsiacoins"`
+		UnconfirmedIncomingSiacoins types.Currency `json:"unconfirmedincomingsiacoins"`
+		SiafundBalance              types.Currency `json:"siafundbalance"`
+
+		Addresses []modules.WatchAddressBalance `json:"addresses"`
+	}
+
+	// WalletWatchTransactionsGET contains the transactions related to the
+	// watch-only addresses.
+	WalletWatchTransactionsGET struct {
+		ConfirmedTransactions   []modules.ProcessedTransaction `json:"confirmedtransactions"`
+		UnconfirmedTransactions []modules.ProcessedTransaction `json:"unconfirmedtransactions"`
+	}
 )
 
 // RegisterRoutesWallet is a helper function to register all wallet routes.
@@ -174,24 +326,57 @@ func RegisterRoutesWallet(router *httprouter.Router, wallet modules.Wallet, requ
 	router.GET("/wallet/addresses", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletAddressesHandler(wallet, w, req, ps)
 	})
+	router.GET("/wallet/addressbook", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletAddressBookHandlerGET(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/addressbook", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletAddressBookHandlerPOST(wallet, w, req, ps)
+	}, requiredPassword))
 	router.GET("/wallet/seedaddrs", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletSeedAddressesHandler(wallet, w, req, ps)
 	})
 	router.GET("/wallet/backup", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletBackupHandler(wallet, w, req, ps)
 	}, requiredPassword))
+	router.GET("/wallet/events", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletEventsHandler(wallet, w, req, ps)
+	}, requiredPassword))
 	router.POST("/wallet/init", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletInitHandler(wallet, w, req, ps)
 	}, requiredPassword))
 	router.POST("/wallet/init/seed", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletInitSeedHandler(wallet, w, req, ps)
 	}, requiredPassword))
+	router.GET("/wallet/limits", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletLimitsHandlerGET(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/limits", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletLimitsHandlerPOST(wallet, w, req, ps)
+	}, requiredPassword))
 	router.POST("/wallet/lock", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletLockHandler(wallet, w, req, ps)
 	}, requiredPassword))
+	router.GET("/wallet/pending", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletPendingHandlerGET(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/pending/:id/approve", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletPendingApproveHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/pending/:id/reject", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletPendingRejectHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.GET("/wallet/rescan", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletRescanHandlerGET(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/rescan", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletRescanHandlerPOST(wallet, w, req, ps)
+	}, requiredPassword))
 	router.POST("/wallet/seed", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletSeedHandler(wallet, w, req, ps)
 	}, requiredPassword))
+	router.POST("/wallet/seedbackup", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletSeedBackupHandler(wallet, w, req, ps)
+	}, requiredPassword))
 	router.GET("/wallet/seeds", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletSeedsHandler(wallet, w, req, ps)
 	}, requiredPassword))
@@ -204,12 +389,24 @@ func RegisterRoutesWallet(router *httprouter.Router, wallet modules.Wallet, requ
 	router.POST("/wallet/siagkey", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletSiagkeyHandler(wallet, w, req, ps)
 	}, requiredPassword))
+	router.GET("/wallet/stuck", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletStuckHandlerGET(wallet, w, req, ps)
+	}, requiredPassword))
 	router.POST("/wallet/sweep/seed", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletSweepSeedHandler(wallet, w, req, ps)
 	}, requiredPassword))
 	router.GET("/wallet/transaction/:id", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletTransactionHandler(wallet, w, req, ps)
 	})
+	router.POST("/wallet/transaction/:id/bumpfee", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletTransactionBumpFeeHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/transaction/:id/cpfp", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletTransactionCPFPHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/transaction/:id/label", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletTransactionLabelHandler(wallet, w, req, ps)
+	}, requiredPassword))
 	router.GET("/wallet/transactions", func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletTransactionsHandler(wallet, w, req, ps)
 	})
@@ -237,21 +434,48 @@ func RegisterRoutesWallet(router *httprouter.Router, wallet modules.Wallet, requ
 	router.GET("/wallet/unspent", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletUnspentHandler(wallet, w, req, ps)
 	}, requiredPassword))
+	router.POST("/wallet/unspent", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletUnspentHandlerPOST(wallet, w, req, ps)
+	}, requiredPassword))
 	router.POST("/wallet/sign", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletSignHandler(wallet, w, req, ps)
 	}, requiredPassword))
+	router.POST("/wallet/multisig/address", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletMultisigAddressHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/multisig/transaction", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletMultisigTransactionHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/multisig/sign", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletMultisigSignHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/multisig/merge", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletMultisigMergeHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/cold/transaction", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletColdTransactionHandler(wallet, w, req, ps)
+	}, requiredPassword))
+	router.POST("/wallet/cold/broadcast", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletColdBroadcastHandler(wallet, w, req, ps)
+	}, requiredPassword))
 	router.GET("/wallet/watch", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletWatchHandlerGET(wallet, w, req, ps)
 	}, requiredPassword))
 	router.POST("/wallet/watch", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		walletWatchHandlerPOST(wallet, w, req, ps)
 	}, requiredPassword))
+	router.GET("/wallet/watch/balance", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletWatchBalanceHandlerGET(wallet, w, req, ps)
+	}, requiredPassword))
+	router.GET("/wallet/watch/transactions", RequirePassword(func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		walletWatchTransactionsHandlerGET(wallet, w, req, ps)
+	}, requiredPassword))
 }
 
 // encryptionKeys enumerates the possible encryption keys that can be derived
 // from an input string.
 func encryptionKeys(seedStr string) (validKeys []crypto.CipherKey, seeds []modules.Seed) {
-	dicts := []mnemonics.DictionaryID{"english", "german", "japanese"}
+	dicts := []mnemonics.DictionaryID{"english", "german", "japanese", modules.BIP39Dictionary}
 	for _, dict := range dicts {
 		seed, err := modules.StringToSeed(seedStr, dict)
 		if err != nil {
@@ -268,37 +492,37 @@ func encryptionKeys(seedStr string) (validKeys []crypto.CipherKey, seeds []modul
 func walletHandler(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	siacoinBal, siafundBal, siaclaimBal, err := wallet.ConfirmedBalance()
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	siacoinsOut, siacoinsIn, err := wallet.UnconfirmedBalance()
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	dustThreshold, err := wallet.DustThreshold()
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	encrypted, err := wallet.Encrypted()
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	unlocked, err := wallet.Unlocked()
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	rescanning, err := wallet.Rescanning()
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	height, err := wallet.Height()
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, WalletGET{
@@ -323,7 +547,7 @@ func wallet033xHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.R
 	source := req.FormValue("source")
 	// Check that source is an absolute paths.
 	if !filepath.IsAbs(source) {
-		WriteError(w, Error{"error when calling /wallet/033x: source must be an absolute path"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/033x: source must be an absolute path"}, http.StatusBadRequest)
 		return
 	}
 	potentialKeys, _ := encryptionKeys(req.FormValue("encryptionpassword"))
@@ -334,18 +558,18 @@ func wallet033xHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.R
 			return
 		}
 		if !errors.Contains(err, modules.ErrBadEncryptionKey) {
-			WriteError(w, Error{"error when calling /wallet/033x: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/033x: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
-	WriteError(w, Error{modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
+	WriteError(w, Error{Message: modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 }
 
 // walletAddressHandler handles API calls to /wallet/address.
 func walletAddressHandler(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	unlockConditions, err := wallet.NextAddress()
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/addresses: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/addresses: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, WalletAddressGET{
@@ -353,6 +577,32 @@ func walletAddressHandler(wallet modules.Wallet, w http.ResponseWriter, _ *http.
 	})
 }
 
+// walletRescanHandlerGET handles GET calls to /wallet/rescan.
+func walletRescanHandlerGET(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	progress, err := wallet.RescanProgress()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get rescan progress: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletRescanGET{progress})
+}
+
+// walletRescanHandlerPOST handles POST calls to /wallet/rescan.
+func walletRescanHandlerPOST(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var gapLimit uint64
+	if gl := req.FormValue("gaplimit"); gl != "" {
+		if _, err := fmt.Sscan(gl, &gapLimit); err != nil {
+			WriteError(w, Error{Message: "failed to parse gaplimit: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if err := wallet.Rescan(gapLimit); err != nil {
+		WriteError(w, Error{Message: "failed to start rescan: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // walletSeedAddressesHandler handles the requests to /wallet/seedaddrs.
 func walletSeedAddressesHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// Parse the count argument. If it isn't specified we return as many
@@ -362,14 +612,14 @@ func walletSeedAddressesHandler(wallet modules.Wallet, w http.ResponseWriter, re
 	if c != "" {
 		_, err := fmt.Sscan(c, &count)
 		if err != nil {
-			WriteError(w, Error{"Failed to parse count: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "Failed to parse count: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	// Get the last count addresses.
 	addresses, err := wallet.LastAddresses(count)
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet/addresses: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet/addresses: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	// Send the response.
@@ -382,7 +632,7 @@ func walletSeedAddressesHandler(wallet modules.Wallet, w http.ResponseWriter, re
 func walletAddressesHandler(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	addresses, err := wallet.AllAddresses()
 	if err != nil {
-		WriteError(w, Error{fmt.Sprintf("Error when calling /wallet/addresses: %v", err)}, http.StatusBadRequest)
+		WriteError(w, Error{Message: fmt.Sprintf("Error when calling /wallet/addresses: %v", err)}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, WalletAddressesGET{
@@ -390,22 +640,100 @@ func walletAddressesHandler(wallet modules.Wallet, w http.ResponseWriter, _ *htt
 	})
 }
 
+// walletAddressBookHandlerGET handles GET calls to /wallet/addressbook.
+func walletAddressBookHandlerGET(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	entries, err := wallet.AddressBook()
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/addressbook: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	WriteJSON(w, WalletAddressBookGET{
+		Entries: entries,
+	})
+}
+
+// walletAddressBookHandlerPOST handles POST calls to /wallet/addressbook.
+func walletAddressBookHandlerPOST(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var params WalletAddressBookPOST
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if params.Remove {
+		err = wallet.RemoveAddressBookEntry(params.Name)
+	} else {
+		err = wallet.SetAddressBookEntry(modules.AddressBookEntry{
+			Name:    params.Name,
+			Address: params.Address,
+		})
+	}
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/addressbook: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // walletBackupHandler handles API calls to /wallet/backup.
 func walletBackupHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	destination := req.FormValue("destination")
 	// Check that the destination is absolute.
 	if !filepath.IsAbs(destination) {
-		WriteError(w, Error{"error when calling /wallet/backup: destination must be an absolute path"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/backup: destination must be an absolute path"}, http.StatusBadRequest)
 		return
 	}
 	err := wallet.CreateBackup(destination)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/backup: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/backup: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
 }
 
+// walletEventsHandler handles API calls to /wallet/events, which streams the
+// wallet's events to the client over a WebSocket.
+func walletEventsHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var since uint64
+	if s := req.FormValue("since"); s != "" {
+		if _, err := fmt.Sscan(s, &since); err != nil {
+			WriteError(w, Error{Message: "unable to parse since: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	// The API is protected by the user agent and password, so the origin of
+	// the connection isn't checked.
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+		// The connection outlives the read timeout of the API server.
+		if err := conn.SetDeadline(time.Time{}); err != nil {
+			return
+		}
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+
+		// Clients don't send any messages, so reading only detects when the
+		// connection was closed.
+		go func() {
+			io.Copy(ioutil.Discard, conn)
+			cancel()
+		}()
+		for {
+			events, err := wallet.Events(ctx, since)
+			if err != nil || ctx.Err() != nil {
+				return
+			}
+			for _, e := range events {
+				if err := websocket.JSON.Send(conn, e); err != nil {
+					return
+				}
+				since = e.ID
+			}
+		}
+	}}
+	server.ServeHTTP(w, req)
+}
+
 // walletInitHandler handles API calls to /wallet/init.
 func walletInitHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var encryptionKey crypto.CipherKey
@@ -416,13 +744,13 @@ func walletInitHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.R
 	if req.FormValue("force") == "true" {
 		err := wallet.Reset()
 		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 	seed, err := wallet.Encrypt(encryptionKey)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -432,7 +760,7 @@ func walletInitHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.R
 	}
 	seedStr, err := modules.SeedToString(seed, dictID)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/init: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, WalletInitPOST{
@@ -446,42 +774,90 @@ func walletInitSeedHandler(wallet modules.Wallet, w http.ResponseWriter, req *ht
 	if req.FormValue("encryptionpassword") != "" {
 		encryptionKey = crypto.NewWalletKey(crypto.HashObject(req.FormValue("encryptionpassword")))
 	}
-	dictID := mnemonics.DictionaryID(req.FormValue("dictionary"))
-	if dictID == "" {
-		dictID = "english"
-	}
-	seed, err := modules.StringToSeed(req.FormValue("seed"), dictID)
+	seed, err := seedFromRequest(req)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/init/seed: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/init/seed: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	if req.FormValue("force") == "true" {
 		err = wallet.Reset()
 		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/init/seed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/init/seed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
 
 	err = wallet.InitFromSeed(encryptionKey, seed)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/init/seed: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/init/seed: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
 }
 
+// seedDictionary returns the dictionary of the seed phrase provided to a
+// request. If no dictionary is provided, BIP39 mnemonics are detected by their
+// number of words and other phrases default to english.
+func seedDictionary(req *http.Request) mnemonics.DictionaryID {
+	if dictID := req.FormValue("dictionary"); dictID != "" {
+		return mnemonics.DictionaryID(dictID)
+	}
+	if modules.IsBIP39Phrase(req.FormValue("seed")) {
+		return modules.BIP39Dictionary
+	}
+	return mnemonics.English
+}
+
+// seedFromRequest returns the seed provided to a request. The seed is either
+// provided as a phrase using 'seed' and 'dictionary' or as an encrypted seed
+// backup using 'seedbackup' and 'passphrase'. The backup can either be the
+// base64 encoded backup or its QR payload.
+func seedFromRequest(req *http.Request) (modules.Seed, error) {
+	backupStr := req.FormValue("seedbackup")
+	if backupStr == "" {
+		// Get the seed using the dictionary + phrase
+		return modules.StringToSeed(req.FormValue("seed"), seedDictionary(req))
+	}
+	if req.FormValue("seed") != "" {
+		return modules.Seed{}, errors.New("cannot supply both 'seed' and 'seedbackup'")
+	}
+	var backup modules.SeedBackup
+	var err error
+	if strings.HasPrefix(backupStr, modules.SeedBackupQRPrefix) {
+		backup, err = modules.ParseSeedBackupQR(backupStr)
+	} else {
+		backup, err = base64.StdEncoding.DecodeString(backupStr)
+	}
+	if err != nil {
+		return modules.Seed{}, errors.AddContext(err, "unable to decode seed backup")
+	}
+	return backup.Open(req.FormValue("passphrase"))
+}
+
+// walletSeedBackupHandler handles API calls to /wallet/seedbackup.
+func walletSeedBackupHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	primarySeed, _, err := wallet.PrimarySeed()
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/seedbackup: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	backup, err := modules.NewSeedBackup(primarySeed, req.FormValue("passphrase"))
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/seedbackup: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletSeedBackupPOST{
+		Backup:    backup,
+		QRPayload: backup.QRPayload(),
+	})
+}
+
 // walletSeedHandler handles API calls to /wallet/seed.
 func walletSeedHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	// Get the seed using the dictionary + phrase
-	dictID := mnemonics.DictionaryID(req.FormValue("dictionary"))
-	if dictID == "" {
-		dictID = "english"
-	}
-	seed, err := modules.StringToSeed(req.FormValue("seed"), dictID)
+	seed, err := seedFromRequest(req)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/seed: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/seed: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
@@ -493,11 +869,11 @@ func walletSeedHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.R
 			return
 		}
 		if !errors.Contains(err, modules.ErrBadEncryptionKey) {
-			WriteError(w, Error{"error when calling /wallet/seed: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/seed: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
-	WriteError(w, Error{"error when calling /wallet/seed: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
+	WriteError(w, Error{Message: "error when calling /wallet/seed: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 }
 
 // walletSiagkeyHandler handles API calls to /wallet/siagkey.
@@ -509,7 +885,7 @@ func walletSiagkeyHandler(wallet modules.Wallet, w http.ResponseWriter, req *htt
 	for _, keypath := range keyfiles {
 		// Check that all key paths are absolute paths.
 		if !filepath.IsAbs(keypath) {
-			WriteError(w, Error{"error when calling /wallet/siagkey: keyfiles contains a non-absolute path"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/siagkey: keyfiles contains a non-absolute path"}, http.StatusBadRequest)
 			return
 		}
 	}
@@ -521,18 +897,18 @@ func walletSiagkeyHandler(wallet modules.Wallet, w http.ResponseWriter, req *htt
 			return
 		}
 		if !errors.Contains(err, modules.ErrBadEncryptionKey) {
-			WriteError(w, Error{"error when calling /wallet/siagkey: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/siagkey: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 	}
-	WriteError(w, Error{"error when calling /wallet/siagkey: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
+	WriteError(w, Error{Message: "error when calling /wallet/siagkey: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 }
 
 // walletLockHandler handles API calls to /wallet/lock.
 func walletLockHandler(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	err := wallet.Lock()
 	if err != nil {
-		WriteError(w, Error{err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -548,26 +924,26 @@ func walletSeedsHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.
 	// Get the primary seed information.
 	primarySeed, addrsRemaining, err := wallet.PrimarySeed()
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	primarySeedStr, err := modules.SeedToString(primarySeed, dictionary)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	// Get the list of seeds known to the wallet.
 	allSeeds, err := wallet.AllSeeds()
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	var allSeedsStrs []string
 	for _, seed := range allSeeds {
 		str, err := modules.SeedToString(seed, dictionary)
 		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "error when calling /wallet/seeds: " + err.Error()}, http.StatusBadRequest)
 			return
 		}
 		allSeedsStrs = append(allSeedsStrs, str)
@@ -581,50 +957,72 @@ func walletSeedsHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.
 
 // walletSiacoinsHandler handles API calls to /wallet/siacoins.
 func walletSiacoinsHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	strategy, err := modules.ParseSelectionStrategy(req.FormValue("strategy"))
+	if err != nil {
+		WriteError(w, Error{Message: "could not read strategy from POST call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	label := req.FormValue("label")
+	if utf8.RuneCountInString(label) > modules.MaxTransactionLabelLength {
+		WriteError(w, Error{Message: fmt.Sprintf("label can't be longer than %v characters", modules.MaxTransactionLabelLength)}, http.StatusBadRequest)
+		return
+	}
 	var txns []types.Transaction
 	if req.FormValue("outputs") != "" {
 		// multiple amounts + destinations
 		if req.FormValue("amount") != "" || req.FormValue("destination") != "" || req.FormValue("feeIncluded") != "" {
-			WriteError(w, Error{"cannot supply both 'outputs' and single amount+destination pair and/or feeIncluded parameter"}, http.StatusInternalServerError)
+			WriteError(w, Error{Message: "cannot supply both 'outputs' and single amount+destination pair and/or feeIncluded parameter"}, http.StatusInternalServerError)
 			return
 		}
 
 		var outputs []types.SiacoinOutput
 		err := json.Unmarshal([]byte(req.FormValue("outputs")), &outputs)
 		if err != nil {
-			WriteError(w, Error{"could not decode outputs: " + err.Error()}, http.StatusInternalServerError)
+			WriteError(w, Error{Message: "could not decode outputs: " + err.Error()}, http.StatusInternalServerError)
 			return
 		}
-		txns, err = wallet.SendSiacoinsMulti(outputs)
-		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
+		txns, err = wallet.SendSiacoinsMultiWithStrategy(outputs, strategy)
+		if pse, ok := err.(modules.PendingSendError); ok {
+			WriteJSON(w, WalletSiacoinsPOST{PendingID: pse.ID})
+			return
+		} else if err != nil {
+			WriteError(w, Error{Message: "error when calling /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
 			return
 		}
 	} else {
 		// single amount + destination
 		amount, ok := scanAmount(req.FormValue("amount"))
 		if !ok {
-			WriteError(w, Error{"could not read amount from POST call to /wallet/siacoins"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "could not read amount from POST call to /wallet/siacoins"}, http.StatusBadRequest)
 			return
 		}
 		dest, err := scanAddress(req.FormValue("destination"))
 		if err != nil {
-			WriteError(w, Error{"could not read address from POST call to /wallet/siacoins"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "could not read address from POST call to /wallet/siacoins"}, http.StatusBadRequest)
 			return
 		}
 		feeIncluded, err := scanBool(req.FormValue("feeIncluded"))
 		if err != nil {
-			WriteError(w, Error{"could not read feeIncluded from POST call to /wallet/siacoins"}, http.StatusBadRequest)
+			WriteError(w, Error{Message: "could not read feeIncluded from POST call to /wallet/siacoins"}, http.StatusBadRequest)
 			return
 		}
 
-		if feeIncluded {
-			txns, err = wallet.SendSiacoinsFeeIncluded(amount, dest)
-		} else {
-			txns, err = wallet.SendSiacoins(amount, dest)
+		txns, err = wallet.SendSiacoinsWithStrategy(amount, dest, feeIncluded, strategy)
+		if pse, ok := err.(modules.PendingSendError); ok {
+			WriteJSON(w, WalletSiacoinsPOST{PendingID: pse.ID})
+			return
+		} else if err != nil {
+			WriteError(w, Error{Message: "error when calling /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
+			return
 		}
+	}
+
+	// The label is given to the last transaction of the set, which sends the
+	// coins to the destination.
+	if label != "" && len(txns) > 0 {
+		err = wallet.SetTransactionLabel(txns[len(txns)-1].ID(), label)
 		if err != nil {
-			WriteError(w, Error{"error when calling /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
+			WriteError(w, Error{Message: "coins were sent but the transaction couldn't be labeled: " + err.Error()}, http.StatusInternalServerError)
 			return
 		}
 	}
@@ -639,22 +1037,147 @@ func walletSiacoinsHandler(wallet modules.Wallet, w http.ResponseWriter, req *ht
 	})
 }
 
+// walletLimitsHandlerGET handles GET calls to /wallet/limits.
+func walletLimitsHandlerGET(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	limits, spent, err := wallet.SpendingLimits()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get spending limits: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletLimitsGET{
+		WalletSpendingLimits: limits,
+		Spent:                spent,
+	})
+}
+
+// walletLimitsHandlerPOST handles POST calls to /wallet/limits. Limits which
+// aren't provided keep their current value.
+func walletLimitsHandlerPOST(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	limits, _, err := wallet.SpendingLimits()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get spending limits: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if req.FormValue("dailylimit") != "" {
+		limit, ok := scanAmount(req.FormValue("dailylimit"))
+		if !ok {
+			WriteError(w, Error{Message: "could not read dailylimit"}, http.StatusBadRequest)
+			return
+		}
+		limits.DailyLimit = limit
+	}
+	if req.FormValue("approvalthreshold") != "" {
+		threshold, ok := scanAmount(req.FormValue("approvalthreshold"))
+		if !ok {
+			WriteError(w, Error{Message: "could not read approvalthreshold"}, http.StatusBadRequest)
+			return
+		}
+		limits.ApprovalThreshold = threshold
+	}
+	if webhook, ok := req.Form["approvalwebhook"]; ok && len(webhook) > 0 {
+		limits.ApprovalWebhook = webhook[0]
+	}
+	if err := wallet.SetSpendingLimits(limits); err != nil {
+		WriteError(w, Error{Message: "failed to set spending limits: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletSpendingLimitsSet writes an error and returns true if spending limits
+// are set on the wallet. Routes which spend the wallet's outputs without being
+// checked against the limits are disabled while limits are set.
+func walletSpendingLimitsSet(wallet modules.Wallet, w http.ResponseWriter, route string) bool {
+	limits, _, err := wallet.SpendingLimits()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get spending limits: " + err.Error()}, http.StatusInternalServerError)
+		return true
+	}
+	if limits.DailyLimit.IsZero() && limits.ApprovalThreshold.IsZero() {
+		return false
+	}
+	WriteError(w, Error{Message: route + " is disabled while wallet spending limits are set"}, http.StatusBadRequest)
+	return true
+}
+
+// walletPendingHandlerGET handles GET calls to /wallet/pending.
+func walletPendingHandlerGET(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	sends, err := wallet.PendingSends()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get pending sends: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletPendingGET{Sends: sends})
+}
+
+// walletStuckHandlerGET handles GET calls to /wallet/stuck.
+func walletStuckHandlerGET(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	sets, err := wallet.StuckTransactions()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get stuck transactions: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletStuckGET{Sets: sets})
+}
+
+// walletPendingApproveHandler handles API calls to
+// /wallet/pending/:id/approve.
+func walletPendingApproveHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	label := req.FormValue("label")
+	if utf8.RuneCountInString(label) > modules.MaxTransactionLabelLength {
+		WriteError(w, Error{Message: fmt.Sprintf("label can't be longer than %v characters", modules.MaxTransactionLabelLength)}, http.StatusBadRequest)
+		return
+	}
+	txns, err := wallet.ApprovePendingSend(ps.ByName("id"))
+	if err != nil {
+		WriteError(w, Error{Message: "failed to approve pending send: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if label != "" && len(txns) > 0 {
+		err = wallet.SetTransactionLabel(txns[len(txns)-1].ID(), label)
+		if err != nil {
+			WriteError(w, Error{Message: "coins were sent but the transaction couldn't be labeled: " + err.Error()}, http.StatusInternalServerError)
+			return
+		}
+	}
+	var txids []types.TransactionID
+	for _, txn := range txns {
+		txids = append(txids, txn.ID())
+	}
+	WriteJSON(w, WalletSiacoinsPOST{
+		Transactions:   txns,
+		TransactionIDs: txids,
+	})
+}
+
+// walletPendingRejectHandler handles API calls to /wallet/pending/:id/reject.
+func walletPendingRejectHandler(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	if err := wallet.RejectPendingSend(ps.ByName("id")); err != nil {
+		WriteError(w, Error{Message: "failed to reject pending send: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // walletSiafundsHandler handles API calls to /wallet/siafunds.
 func walletSiafundsHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if walletSpendingLimitsSet(wallet, w, "/wallet/siafunds") {
+		return
+	}
 	amount, ok := scanAmount(req.FormValue("amount"))
 	if !ok {
-		WriteError(w, Error{"could not read 'amount' from POST call to /wallet/siafunds"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "could not read 'amount' from POST call to /wallet/siafunds"}, http.StatusBadRequest)
 		return
 	}
 	dest, err := scanAddress(req.FormValue("destination"))
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/siafunds: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/siafunds: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	txns, err := wallet.SendSiafunds(amount, dest)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/siafunds: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "error when calling /wallet/siafunds: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
 	var txids []types.TransactionID
@@ -670,19 +1193,15 @@ func walletSiafundsHandler(wallet modules.Wallet, w http.ResponseWriter, req *ht
 // walletSweepSeedHandler handles API calls to /wallet/sweep/seed.
 func walletSweepSeedHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// Get the seed using the dictionary + phrase
-	dictID := mnemonics.DictionaryID(req.FormValue("dictionary"))
-	if dictID == "" {
-		dictID = "english"
-	}
-	seed, err := modules.StringToSeed(req.FormValue("seed"), dictID)
+	seed, err := modules.StringToSeed(req.FormValue("seed"), seedDictionary(req))
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/sweep/seed: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/sweep/seed: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	coins, funds, err := wallet.SweepSeed(seed)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/sweep/seed: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/sweep/seed: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, WalletSweepPOST{
@@ -698,35 +1217,143 @@ func walletTransactionHandler(wallet modules.Wallet, w http.ResponseWriter, _ *h
 	jsonID := "\"" + ps.ByName("id") + "\""
 	err := id.UnmarshalJSON([]byte(jsonID))
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/transaction/id: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	txn, ok, err := wallet.Transaction(id)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/transaction/id: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	if !ok {
-		WriteError(w, Error{"error when calling /wallet/transaction/id  :  transaction not found"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id  :  transaction not found"}, http.StatusBadRequest)
+		return
+	}
+	labels, err := wallet.TransactionLabels()
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
 	WriteJSON(w, WalletTransactionGETid{
 		Transaction: txn,
+		Label:       labels[id],
 	})
 }
 
+// walletTransactionLabelHandler handles API calls to
+// /wallet/transaction/:id/label.
+func walletTransactionLabelHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	var id types.TransactionID
+	jsonID := "\"" + ps.ByName("id") + "\""
+	err := id.UnmarshalJSON([]byte(jsonID))
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id/label: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = wallet.SetTransactionLabel(id, req.FormValue("label"))
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id/label: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// walletTransactionBumpFeeHandler handles API calls to
+// /wallet/transaction/:id/bumpfee.
+func walletTransactionBumpFeeHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	if walletSpendingLimitsSet(wallet, w, "/wallet/transaction/id/bumpfee") {
+		return
+	}
+	var id types.TransactionID
+	jsonID := "\"" + ps.ByName("id") + "\""
+	err := id.UnmarshalJSON([]byte(jsonID))
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id/bumpfee: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	fee, ok := scanAmount(req.FormValue("fee"))
+	if !ok {
+		WriteError(w, Error{Message: "could not read fee from POST call to /wallet/transaction/id/bumpfee"}, http.StatusBadRequest)
+		return
+	}
+	txns, err := wallet.BumpFee(id, fee)
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id/bumpfee: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var txids []types.TransactionID
+	for _, txn := range txns {
+		txids = append(txids, txn.ID())
+	}
+	WriteJSON(w, WalletBumpFeePOST{
+		Transactions:   txns,
+		TransactionIDs: txids,
+	})
+}
+
+// walletTransactionCPFPHandler handles API calls to
+// /wallet/transaction/:id/cpfp.
+func walletTransactionCPFPHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	if walletSpendingLimitsSet(wallet, w, "/wallet/transaction/id/cpfp") {
+		return
+	}
+	var id types.TransactionID
+	jsonID := "\"" + ps.ByName("id") + "\""
+	err := id.UnmarshalJSON([]byte(jsonID))
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id/cpfp: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	fee, ok := scanAmount(req.FormValue("fee"))
+	if !ok {
+		WriteError(w, Error{Message: "could not read fee from POST call to /wallet/transaction/id/cpfp"}, http.StatusBadRequest)
+		return
+	}
+	txns, err := wallet.ChildPaysForParent(id, fee)
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transaction/id/cpfp: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var txids []types.TransactionID
+	for _, txn := range txns {
+		txids = append(txids, txn.ID())
+	}
+	WriteJSON(w, WalletBumpFeePOST{
+		Transactions:   txns,
+		TransactionIDs: txids,
+	})
+}
+
+// transactionLabels returns the labels of the provided transactions by their
+// ids.
+func transactionLabels(wallet modules.Wallet, txnSets ...[]modules.ProcessedTransaction) (map[string]string, error) {
+	all, err := wallet.TransactionLabels()
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string)
+	for _, txns := range txnSets {
+		for _, txn := range txns {
+			if label, exists := all[txn.TransactionID]; exists {
+				labels[txn.TransactionID.String()] = label
+			}
+		}
+	}
+	return labels, nil
+}
+
 // walletTransactionsHandler handles API calls to /wallet/transactions.
 func walletTransactionsHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	startheightStr, endheightStr := req.FormValue("startheight"), req.FormValue("endheight")
 	if startheightStr == "" || endheightStr == "" {
-		WriteError(w, Error{"startheight and endheight must be provided to a /wallet/transactions call."}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "startheight and endheight must be provided to a /wallet/transactions call."}, http.StatusBadRequest)
 		return
 	}
 	// Get the start and end blocks.
 	start, err := strconv.ParseUint(startheightStr, 10, 64)
 	if err != nil {
-		WriteError(w, Error{"parsing integer value for parameter `startheight` failed: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "parsing integer value for parameter `startheight` failed: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	// Check if endheightStr is set to -1. If it is, we use MaxUint64 as the
@@ -738,23 +1365,30 @@ func walletTransactionsHandler(wallet modules.Wallet, w http.ResponseWriter, req
 		end, err = strconv.ParseUint(endheightStr, 10, 64)
 	}
 	if err != nil {
-		WriteError(w, Error{"parsing integer value for parameter `endheight` failed: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "parsing integer value for parameter `endheight` failed: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	confirmedTxns, err := wallet.Transactions(types.BlockHeight(start), types.BlockHeight(end))
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	unconfirmedTxns, err := wallet.UnconfirmedTransactions()
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	labels, err := transactionLabels(wallet, confirmedTxns, unconfirmedTxns)
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transactions: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
 
 	WriteJSON(w, WalletTransactionsGET{
 		ConfirmedTransactions:   confirmedTxns,
 		UnconfirmedTransactions: unconfirmedTxns,
+		Labels:                  labels,
 	})
 }
 
@@ -766,23 +1400,29 @@ func walletTransactionsAddrHandler(wallet modules.Wallet, w http.ResponseWriter,
 	var addr types.UnlockHash
 	err := addr.UnmarshalJSON([]byte(jsonAddr))
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 
 	confirmedATs, err := wallet.AddressTransactions(addr)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	unconfirmedATs, err := wallet.AddressUnconfirmedTransactions(addr)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/transactions: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	labels, err := transactionLabels(wallet, confirmedATs, unconfirmedATs)
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/transactions: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
 	WriteJSON(w, WalletTransactionsGETaddr{
 		ConfirmedTransactions:   confirmedATs,
 		UnconfirmedTransactions: unconfirmedATs,
+		Labels:                  labels,
 	})
 }
 
@@ -803,7 +1443,7 @@ func walletUnlockHandler(wallet modules.Wallet, w http.ResponseWriter, req *http
 		}
 		err = errors.Compose(err, unlockErr)
 	}
-	WriteError(w, Error{"error when calling /wallet/unlock: " + err.Error()}, http.StatusBadRequest)
+	WriteError(w, Error{Message: "error when calling /wallet/unlock: " + err.Error()}, http.StatusBadRequest)
 }
 
 // walletChangePasswordHandler handles API calls to /wallet/changepassword
@@ -811,7 +1451,7 @@ func walletChangePasswordHandler(wallet modules.Wallet, w http.ResponseWriter, r
 	var newKey crypto.CipherKey
 	newPassword := req.FormValue("newpassword")
 	if newPassword == "" {
-		WriteError(w, Error{"a password must be provided to newpassword"}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "a password must be provided to newpassword"}, http.StatusBadRequest)
 		return
 	}
 	newKey = crypto.NewWalletKey(crypto.HashObject(newPassword))
@@ -834,7 +1474,7 @@ func walletChangePasswordHandler(wallet modules.Wallet, w http.ResponseWriter, r
 		}
 		err = errors.Compose(err, seedErr)
 	}
-	WriteError(w, Error{"error when calling /wallet/changepassword: " + err.Error()}, http.StatusBadRequest)
+	WriteError(w, Error{Message: "error when calling /wallet/changepassword: " + err.Error()}, http.StatusBadRequest)
 	return
 }
 
@@ -852,7 +1492,7 @@ func walletVerifyPasswordHandler(wallet modules.Wallet, w http.ResponseWriter, r
 		}
 		err = errors.Compose(err, keyErr)
 	}
-	WriteError(w, Error{"error when calling /wallet/verifypassword: " + err.Error()}, http.StatusBadRequest)
+	WriteError(w, Error{Message: "error when calling /wallet/verifypassword: " + err.Error()}, http.StatusBadRequest)
 }
 
 // walletVerifyAddressHandler handles API calls to /wallet/verify/address/:addr.
@@ -868,12 +1508,12 @@ func walletUnlockConditionsHandlerGET(wallet modules.Wallet, w http.ResponseWrit
 	var addr types.UnlockHash
 	err := addr.LoadString(ps.ByName("addr"))
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/unlockconditions: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/unlockconditions: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	uc, err := wallet.UnlockConditions(addr)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/unlockconditions: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/unlockconditions: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, WalletUnlockConditionsGET{
@@ -886,12 +1526,12 @@ func walletUnlockConditionsHandlerPOST(wallet modules.Wallet, w http.ResponseWri
 	var params WalletUnlockConditionsPOSTParams
 	err := json.NewDecoder(req.Body).Decode(&params)
 	if err != nil {
-		WriteError(w, Error{"invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err = wallet.AddUnlockConditions(params.UnlockConditions)
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/unlockconditions: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "error when calling /wallet/unlockconditions: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
@@ -901,7 +1541,7 @@ func walletUnlockConditionsHandlerPOST(wallet modules.Wallet, w http.ResponseWri
 func walletUnspentHandler(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	outputs, err := wallet.UnspentOutputs()
 	if err != nil {
-		WriteError(w, Error{"error when calling /wallet/unspent: " + err.Error()}, http.StatusInternalServerError)
+		WriteError(w, Error{Message: "error when calling /wallet/unspent: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
 	WriteJSON(w, WalletUnspentGET{
@@ -909,17 +1549,40 @@ func walletUnspentHandler(wallet modules.Wallet, w http.ResponseWriter, _ *http.
 	})
 }
 
+// walletUnspentHandlerPOST handles POST calls to /wallet/unspent.
+func walletUnspentHandlerPOST(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var params WalletUnspentPOSTParams
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if len(params.IDs) == 0 {
+		WriteError(w, Error{Message: "no outputs provided"}, http.StatusBadRequest)
+		return
+	}
+	err = wallet.MarkOutputsDoNotSpend(params.IDs, params.DoNotSpend)
+	if err != nil {
+		WriteError(w, Error{Message: "error when calling /wallet/unspent: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // walletSignHandler handles API calls to /wallet/sign.
 func walletSignHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if walletSpendingLimitsSet(wallet, w, "/wallet/sign") {
+		return
+	}
 	var params WalletSignPOSTParams
 	err := json.NewDecoder(req.Body).Decode(&params)
 	if err != nil {
-		WriteError(w, Error{"invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	err = wallet.SignTransaction(&params.Transaction, params.ToSign)
 	if err != nil {
-		WriteError(w, Error{"failed to sign transaction: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to sign transaction: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, WalletSignPOSTResp{
@@ -927,11 +1590,128 @@ func walletSignHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.R
 	})
 }
 
+// walletMultisigAddressHandler handles API calls to /wallet/multisig/address.
+func walletMultisigAddressHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var params WalletMultisigAddressPOSTParams
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	uc, err := wallet.CreateMultisigAddress(params.SignaturesRequired, params.PublicKeys, params.Unused)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to create multisig address: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletMultisigAddressPOSTResp{
+		Address:          uc.UnlockHash(),
+		UnlockConditions: uc,
+	})
+}
+
+// walletMultisigTransactionHandler handles API calls to
+// /wallet/multisig/transaction.
+func walletMultisigTransactionHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if walletSpendingLimitsSet(wallet, w, "/wallet/multisig/transaction") {
+		return
+	}
+	var params WalletMultisigTransactionPOSTParams
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	txn, err := wallet.MultisigTransaction(params.Address, params.Outputs)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to create multisig transaction: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletMultisigPOSTResp{
+		Transaction:       txn,
+		MissingSignatures: modules.MissingSignatures(txn),
+	})
+}
+
+// walletMultisigSignHandler handles API calls to /wallet/multisig/sign.
+func walletMultisigSignHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if walletSpendingLimitsSet(wallet, w, "/wallet/multisig/sign") {
+		return
+	}
+	var params WalletMultisigSignPOSTParams
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	txn, err := wallet.SignMultisigTransaction(params.Transaction)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to sign multisig transaction: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletMultisigPOSTResp{
+		Transaction:       txn,
+		MissingSignatures: modules.MissingSignatures(txn),
+	})
+}
+
+// walletMultisigMergeHandler handles API calls to /wallet/multisig/merge.
+func walletMultisigMergeHandler(_ modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var params WalletMultisigMergePOSTParams
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	txn, err := modules.MergeTransactionSignatures(params.Transactions)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to merge signatures: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletMultisigPOSTResp{
+		Transaction:       txn,
+		MissingSignatures: modules.MissingSignatures(txn),
+	})
+}
+
+// walletColdTransactionHandler handles API calls to /wallet/cold/transaction.
+func walletColdTransactionHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var params WalletColdTransactionPOSTParams
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	ct, err := wallet.CreateColdTransaction(params.Outputs, params.ChangeAddress)
+	if err != nil {
+		WriteError(w, Error{Message: "failed to create cold transaction: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletColdTransactionPOSTResp{ct})
+}
+
+// walletColdBroadcastHandler handles API calls to /wallet/cold/broadcast.
+func walletColdBroadcastHandler(wallet modules.Wallet, w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if walletSpendingLimitsSet(wallet, w, "/wallet/cold/broadcast") {
+		return
+	}
+	var params WalletColdBroadcastPOSTParams
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := wallet.BroadcastColdTransaction(params.Transaction); err != nil {
+		WriteError(w, Error{Message: "failed to broadcast cold transaction: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // walletWatchHandlerGET handles GET calls to /wallet/watch.
 func walletWatchHandlerGET(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	addrs, err := wallet.WatchAddresses()
 	if err != nil {
-		WriteError(w, Error{"failed to get watch addresses: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to get watch addresses: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteJSON(w, WalletWatchGET{
@@ -944,17 +1724,68 @@ func walletWatchHandlerPOST(wallet modules.Wallet, w http.ResponseWriter, req *h
 	var wwpp WalletWatchPOST
 	err := json.NewDecoder(req.Body).Decode(&wwpp)
 	if err != nil {
-		WriteError(w, Error{"invalid parameters: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "invalid parameters: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
+	// Public keys are watched through the address of their standard unlock
+	// conditions. The unlock conditions are added to the wallet so that
+	// transactions spending from the address can be built for an offline
+	// signer.
+	addrs := wwpp.Addresses
+	for _, pk := range wwpp.PublicKeys {
+		uc := types.UnlockConditions{
+			PublicKeys:         []types.SiaPublicKey{pk},
+			SignaturesRequired: 1,
+		}
+		if !wwpp.Remove {
+			if err := wallet.AddUnlockConditions(uc); err != nil {
+				WriteError(w, Error{Message: "failed to add unlock conditions: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+		}
+		addrs = append(addrs, uc.UnlockHash())
+	}
 	if wwpp.Remove {
-		err = wallet.RemoveWatchAddresses(wwpp.Addresses, wwpp.Unused)
+		err = wallet.RemoveWatchAddresses(addrs, wwpp.Unused)
 	} else {
-		err = wallet.AddWatchAddresses(wwpp.Addresses, wwpp.Unused)
+		err = wallet.AddWatchAddresses(addrs, wwpp.Unused)
 	}
 	if err != nil {
-		WriteError(w, Error{"failed to update watch set: " + err.Error()}, http.StatusBadRequest)
+		WriteError(w, Error{Message: "failed to update watch set: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
 	WriteSuccess(w)
 }
+
+// walletWatchBalanceHandlerGET handles GET calls to /wallet/watch/balance.
+func walletWatchBalanceHandlerGET(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	wbs, err := wallet.WatchBalances()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get watch-only balances: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	wwbg := WalletWatchBalanceGET{
+		Addresses: wbs,
+	}
+	for _, wb := range wbs {
+		wwbg.ConfirmedSiacoinBalance = wwbg.ConfirmedSiacoinBalance.Add(wb.ConfirmedSiacoinBalance)
+		wwbg.UnconfirmedOutgoingSiacoins = wwbg.UnconfirmedOutgoingSiacoins.Add(wb.UnconfirmedOutgoingSiacoins)
+		wwbg.UnconfirmedIncomingSiacoins = wwbg.UnconfirmedIncomingSiacoins.Add(wb.UnconfirmedIncomingSiacoins)
+		wwbg.SiafundBalance = wwbg.SiafundBalance.Add(wb.SiafundBalance)
+	}
+	WriteJSON(w, wwbg)
+}
+
+// walletWatchTransactionsHandlerGET handles GET calls to
+// /wallet/watch/transactions.
+func walletWatchTransactionsHandlerGET(wallet modules.Wallet, w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	confirmed, unconfirmed, err := wallet.WatchTransactions()
+	if err != nil {
+		WriteError(w, Error{Message: "failed to get watch-only transactions: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, WalletWatchTransactionsGET{
+		ConfirmedTransactions:   confirmed,
+		UnconfirmedTransactions: unconfirmed,
+	})
+}
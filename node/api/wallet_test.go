@@ -1810,3 +1810,88 @@ func TestWalletTransactionsGetAddr(t *testing.T) {
 		t.Errorf("There should be exactly 0 unconfirmed and 1 confirmed related txns")
 	}
 }
+
+// TestWalletSpendingLimits probes the spending limits through the API. A send
+// above the approval threshold is held until it is approved, and the routes
+// which aren't checked against the limits are rejected while they are set.
+func TestWalletSpendingLimits(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	st, err := createServerTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.panicClose()
+
+	limitValues := url.Values{}
+	limitValues.Set("dailylimit", types.SiacoinPrecision.Mul64(100).String())
+	limitValues.Set("approvalthreshold", types.SiacoinPrecision.Mul64(10).String())
+	if err := st.stdPostAPI("/wallet/limits", limitValues); err != nil {
+		t.Fatal(err)
+	}
+
+	// A send above the approval threshold is held.
+	amount := types.SiacoinPrecision.Mul64(50)
+	sendValues := url.Values{}
+	sendValues.Set("amount", amount.String())
+	sendValues.Set("destination", types.UnlockHash{}.String())
+	var wsp WalletSiacoinsPOST
+	if err := st.postAPI("/wallet/siacoins", sendValues, &wsp); err != nil {
+		t.Fatal(err)
+	}
+	if wsp.PendingID == "" || len(wsp.Transactions) != 0 {
+		t.Fatal("send wasn't held", wsp)
+	}
+	var wpg WalletPendingGET
+	if err := st.getAPI("/wallet/pending", &wpg); err != nil {
+		t.Fatal(err)
+	}
+	if len(wpg.Sends) != 1 || wpg.Sends[0].ID != wsp.PendingID {
+		t.Fatal("wrong pending sends", wpg.Sends)
+	}
+
+	// Siafunds can't be sent while limits are set.
+	sfValues := url.Values{}
+	sfValues.Set("amount", "1")
+	sfValues.Set("destination", types.UnlockHash{}.String())
+	err = st.stdPostAPI("/wallet/siafunds", sfValues)
+	if err == nil || !strings.Contains(err.Error(), "disabled while wallet spending limits are set") {
+		t.Fatal("expected /wallet/siafunds to be rejected but got", err)
+	}
+
+	// Approving the send sends the coins.
+	wsp = WalletSiacoinsPOST{}
+	if err := st.postAPI("/wallet/pending/"+wpg.Sends[0].ID+"/approve", url.Values{}, &wsp); err != nil {
+		t.Fatal(err)
+	}
+	if len(wsp.Transactions) == 0 || wsp.PendingID != "" {
+		t.Fatal("approved send wasn't sent", wsp)
+	}
+	if err := st.getAPI("/wallet/pending", &wpg); err != nil {
+		t.Fatal(err)
+	}
+	if len(wpg.Sends) != 0 {
+		t.Fatal("expected no pending sends", wpg.Sends)
+	}
+	var wlg WalletLimitsGET
+	if err := st.getAPI("/wallet/limits", &wlg); err != nil {
+		t.Fatal(err)
+	}
+	if !wlg.Spent.Equals(amount) {
+		t.Fatal("wrong amount spent", wlg.Spent)
+	}
+
+	// Without limits siafunds can be sent again. The wallet doesn't have any,
+	// so the send still fails.
+	limitValues.Set("dailylimit", "0")
+	limitValues.Set("approvalthreshold", "0")
+	if err := st.stdPostAPI("/wallet/limits", limitValues); err != nil {
+		t.Fatal(err)
+	}
+	err = st.stdPostAPI("/wallet/siafunds", sfValues)
+	if err == nil || strings.Contains(err.Error(), "disabled while wallet spending limits are set") {
+		t.Fatal("expected /wallet/siafunds to fail for lack of siafunds but got", err)
+	}
+}
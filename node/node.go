@@ -33,6 +33,7 @@ import (
 	"go.sia.tech/siad/modules/transactionpool"
 	"go.sia.tech/siad/modules/wallet"
 	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
 )
 
 // NodeParams contains a bunch of parameters for creating a new test node. As
@@ -104,13 +105,23 @@ type NodeParams struct {
 	SiaMuxWSAddress  string
 
 	// Custom settings for modules
-	Allowance      modules.Allowance
-	Bootstrap      bool
-	UseUPNP        bool
-	HostAddress    string
-	HostStorage    uint64
-	RPCAddress     string
-	WalletPassword string
+	Allowance           modules.Allowance
+	Bootstrap           bool
+	BootstrapMirrors    []string
+	BootstrapCheckpoint consensus.BootstrapCheckpoint
+	ReorgAlertDepth     types.BlockHeight
+	MaxReorgDepth       types.BlockHeight
+	TPoolMaxSize        uint64
+	UseUPNP             bool
+	Proxy               string
+	ProxyOnion          bool
+	TorControl          string
+	TorPassword         string
+	HostAddress         string
+	HostStorage         uint64
+	RPCAddress          string
+	StratumAddress      string
+	WalletPassword      string
 
 	MainChainPort string
 
@@ -197,6 +208,27 @@ func printfRelease(format string, a ...interface{}) {
 	}
 }
 
+// bootstrapConsensus downloads a consensus snapshot into an empty consensus
+// directory. The snapshot is verified against the provided checkpoint, or the
+// most recent checkpoint embedded in the binary if none was provided.
+func bootstrapConsensus(dir string, mirrors []string, cp consensus.BootstrapCheckpoint) error {
+	if cp == (consensus.BootstrapCheckpoint{}) {
+		var err error
+		cp, err = consensus.LatestBootstrapCheckpoint()
+		if err != nil {
+			return err
+		}
+	}
+	printfRelease("Downloading consensus snapshot at height %v...\n", cp.Height)
+	ok, err := consensus.BootstrapFromSnapshot(dir, mirrors, cp)
+	if err != nil {
+		return err
+	} else if ok {
+		printfRelease("Consensus snapshot verified\n")
+	}
+	return nil
+}
+
 // Close will call close on every module within the node, combining and
 // returning the errors.
 func (n *Node) Close() (err error) {
@@ -249,7 +281,7 @@ func tryUnlockWallet(w modules.Wallet, password string) error {
 		return errors.New("server doesn't have a wallet")
 	}
 	var validKeys []crypto.CipherKey
-	dicts := []mnemonics.DictionaryID{"english", "german", "japanese"}
+	dicts := []mnemonics.DictionaryID{"english", "german", "japanese", modules.BIP39Dictionary}
 	for _, dict := range dicts {
 		seed, err := modules.StringToSeed(password, dict)
 		if err != nil {
@@ -311,7 +343,13 @@ func New(params NodeParams, loadStartTime time.Time) (*Node, <-chan error) {
 		}
 		i++
 		printfRelease("(%d/%d) Loading gateway...\n", i, numModules)
-		return gateway.NewCustomGateway(params.RPCAddress, params.Bootstrap, params.UseUPNP, filepath.Join(dir, modules.GatewayDir), gatewayDeps)
+		proxy := gateway.ProxySettings{
+			Address:            params.Proxy,
+			Onion:              params.ProxyOnion,
+			TorControl:         params.TorControl,
+			TorControlPassword: params.TorPassword,
+		}
+		return gateway.NewCustomGateway(params.RPCAddress, params.Bootstrap, params.UseUPNP, proxy, filepath.Join(dir, modules.GatewayDir), gatewayDeps)
 	}()
 	if err != nil {
 		errChan <- errors.Extend(err, errors.New("unable to create gateway"))
@@ -338,12 +376,34 @@ func New(params NodeParams, loadStartTime time.Time) (*Node, <-chan error) {
 		if consensusSetDeps == nil {
 			consensusSetDeps = modules.ProdDependencies
 		}
-		return consensus.NewCustomConsensusSet(g, params.Bootstrap, filepath.Join(dir, modules.ConsensusDir), consensusSetDeps)
+		consensusDir := filepath.Join(dir, modules.ConsensusDir)
+		if len(params.BootstrapMirrors) > 0 {
+			_, err := os.Stat(filepath.Join(consensusDir, consensus.DatabaseFilename))
+			if os.IsNotExist(err) {
+				err = bootstrapConsensus(consensusDir, params.BootstrapMirrors, params.BootstrapCheckpoint)
+			}
+			if err != nil {
+				// Failing to bootstrap from a snapshot isn't fatal, the node
+				// syncs the full blockchain instead.
+				printfRelease("Unable to bootstrap from a consensus snapshot, syncing from peers instead: %v\n", err)
+			}
+		}
+		return consensus.NewCustomConsensusSet(g, params.Bootstrap, consensusDir, consensusSetDeps)
 	}()
 	if err := modules.PeekErr(errChanCS); err != nil {
 		errChan <- errors.Extend(err, errors.New("unable to create consensus set"))
 		return nil, errChan
 	}
+	if cs != nil && (params.ReorgAlertDepth != 0 || params.MaxReorgDepth != 0) {
+		err := cs.SetReorgSettings(modules.ReorgSettings{
+			AlertDepth: params.ReorgAlertDepth,
+			MaxDepth:   params.MaxReorgDepth,
+		})
+		if err != nil {
+			errChan <- errors.Extend(err, errors.New("unable to set reorg settings"))
+			return nil, errChan
+		}
+	}
 
 	// Explorer.
 	e, err := func() (modules.Explorer, error) {
@@ -392,6 +452,12 @@ func New(params NodeParams, loadStartTime time.Time) (*Node, <-chan error) {
 		errChan <- errors.Extend(err, errors.New("unable to create transaction pool"))
 		return nil, errChan
 	}
+	if tp != nil && params.TPoolMaxSize != 0 {
+		if err := tp.SetMaxSize(params.TPoolMaxSize); err != nil {
+			errChan <- errors.Extend(err, errors.New("unable to set transaction pool size"))
+			return nil, errChan
+		}
+	}
 
 	// Wallet.
 	w, err := func() (modules.Wallet, error) {
@@ -454,6 +520,12 @@ func New(params NodeParams, loadStartTime time.Time) (*Node, <-chan error) {
 		errChan <- errors.Extend(err, errors.New("unable to create miner"))
 		return nil, errChan
 	}
+	if m != nil && params.StratumAddress != "" {
+		if err := m.StartStratum(params.StratumAddress); err != nil {
+			errChan <- errors.Extend(err, errors.New("unable to start stratum server"))
+			return nil, errChan
+		}
+	}
 
 	// Host.
 	h, err := func() (modules.Host, error) {
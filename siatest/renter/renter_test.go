@@ -246,6 +246,7 @@ func TestRenterThree(t *testing.T) {
 		{Name: "TestAllowanceDefaultSet", Test: testAllowanceDefaultSet},
 		{Name: "TestSetFileStuck", Test: testSetFileStuck},
 		{Name: "TestCancelAsyncDownload", Test: testCancelAsyncDownload},
+		{Name: "TestRenterRegistry", Test: testRenterRegistry},
 		{Name: "TestUploadDownload", Test: testUploadDownload}, // Needs to be last as it impacts hosts
 	}
 
@@ -310,6 +311,49 @@ func testAllowanceDefaultSet(t *testing.T, tg *siatest.TestGroup) {
 	}
 }
 
+// testRenterRegistry tests reading and updating registry values through the
+// renter's API.
+func testRenterRegistry(t *testing.T, tg *siatest.TestGroup) {
+	r := tg.Renters()[0]
+
+	// Create a signed registry value.
+	sk, pk := crypto.GenerateKeyPair()
+	spk := types.Ed25519PublicKey(pk)
+	var dataKey crypto.Hash
+	fastrand.Read(dataKey[:])
+	srv := modules.NewRegistryValue(dataKey, fastrand.Bytes(modules.RegistryDataSize), 1, modules.RegistryTypeWithoutPubkey).Sign(sk)
+
+	// The value doesn't exist yet.
+	_, err := r.RenterRegistryGet(spk, dataKey, time.Second)
+	if err == nil || !strings.Contains(err.Error(), renter.ErrRegistryEntryNotFound.Error()) {
+		t.Fatal("expected entry not to be found", err)
+	}
+
+	// A value with an invalid signature is rejected.
+	invalid := srv
+	invalid.Revision++
+	if err := r.RenterRegistryPost(spk, invalid); err == nil {
+		t.Fatal("value with invalid signature shouldn't be accepted")
+	}
+
+	// Update the registry. The workers might not be ready yet.
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		return r.RenterRegistryPost(spk, srv)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read the value back.
+	readSRV, err := r.RenterRegistryGet(spk, dataKey, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(readSRV, srv) {
+		t.Fatal("registry values don't match", readSRV, srv)
+	}
+}
+
 // testReceivedFieldEqualsFileSize tests that the bug that caused finished
 // downloads to stall in the UI and siac is gone.
 func testReceivedFieldEqualsFileSize(t *testing.T, tg *siatest.TestGroup) {
@@ -1,8 +1,10 @@
 package renter
 
 import (
+	"archive/tar"
 	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"path/filepath"
 	"testing"
@@ -178,6 +180,7 @@ func TestRenterStream(t *testing.T) {
 
 	// Specify subtests to run
 	subTests := []siatest.SubTest{
+		{Name: "TestDownloadBatch", Test: testDownloadBatch},
 		{Name: "TestStreamLargeFile", Test: testStreamLargeFile},
 		{Name: "TestStreamRepair", Test: testStreamRepair},
 		{Name: "TestUploadStreaming", Test: testUploadStreaming},
@@ -190,6 +193,62 @@ func TestRenterStream(t *testing.T) {
 	}
 }
 
+// testDownloadBatch tests downloading multiple files as a tar archive.
+func testDownloadBatch(t *testing.T, tg *siatest.TestGroup) {
+	// Grab the first of the group's renters
+	renter := tg.Renters()[0]
+	// Upload a few files of different sizes.
+	dataPieces := uint64(2)
+	parityPieces := uint64(len(tg.Hosts())) - dataPieces
+	ct := crypto.TypeDefaultRenter
+	expected := make(map[string]*siatest.LocalFile)
+	var siaPaths []modules.SiaPath
+	for _, fileSize := range []int{100, int(siatest.ChunkSize(dataPieces, ct)) + 1, 0} {
+		localFile, remoteFile, err := renter.UploadNewFileBlocking(fileSize, dataPieces, parityPieces, false)
+		if err != nil {
+			t.Fatal("Failed to upload a file for testing: ", err)
+		}
+		expected[remoteFile.SiaPath().String()] = localFile
+		siaPaths = append(siaPaths, remoteFile.SiaPath())
+	}
+
+	// Download the files and pass the first one twice. It should only be
+	// included once.
+	archive, err := renter.RenterDownloadsBatchGet(append(siaPaths, siaPaths[0]), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for i := 0; ; i++ {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			if i != len(siaPaths) {
+				t.Fatalf("expected %v files but got %v", len(siaPaths), i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Name != siaPaths[i].String() {
+			t.Fatalf("expected file %v but got %v", siaPaths[i], hdr.Name)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := expected[hdr.Name].Equal(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Downloading a file that doesn't exist should fail.
+	_, err = renter.RenterDownloadsBatchGet([]modules.SiaPath{modules.RandomSiaPath()}, false, false)
+	if err == nil {
+		t.Fatal("downloading a file that doesn't exist should fail")
+	}
+}
+
 // testStreamLargeFile tests that using the streaming endpoint to download
 // multiple chunks works.
 func testStreamLargeFile(t *testing.T, tg *siatest.TestGroup) {